@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
@@ -31,6 +32,11 @@ func formatCobraError(err error) string {
 
 func main() {
 	if err := cli.Execute(); err != nil {
+		var pluginExit cli.PluginExitError
+		if errors.As(err, &pluginExit) {
+			os.Exit(pluginExit.Code)
+		}
+		code := cli.ErrorCode(err)
 		// Print error if not already printed by command handler
 		if !cli.IsPrintedError(err) {
 			msg := formatCobraError(err)
@@ -38,12 +44,13 @@ func main() {
 				resp := map[string]any{
 					"ok":    false,
 					"error": msg,
+					"code":  code,
 				}
 				_ = json.NewEncoder(os.Stderr).Encode(resp)
 			} else {
 				fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
 			}
 		}
-		os.Exit(1)
+		os.Exit(cli.ExitCode(code))
 	}
 }
@@ -0,0 +1,112 @@
+// Package banners loads the rules 'webctl dismiss-banners' uses to accept or
+// hide cookie-consent and newsletter overlays: a maintained built-in list and
+// the user's own rules in $XDG_CONFIG_HOME/webctl/banners.yaml.
+package banners
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/config"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"go.yaml.in/yaml/v3"
+)
+
+// UserFile is the name of the user's rules file, beside config.yaml.
+const UserFile = "banners.yaml"
+
+//go:embed rules.yaml
+var builtinRules []byte
+
+// Builtin returns the built-in rules.
+func Builtin() []ipc.BannerRule {
+	rules, err := parse(builtinRules)
+	if err != nil {
+		panic(fmt.Sprintf("banners: built-in rules: %v", err))
+	}
+	return rules
+}
+
+// UserPath returns the user's rules file, beside the user config file.
+func UserPath() string {
+	path := config.UserPath()
+	if path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), UserFile)
+}
+
+// LoadFile reads and validates a rules file. A missing file yields no rules
+// and no error.
+func LoadFile(path string) ([]ipc.BannerRule, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rules, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Merge returns base with each rule in over added, replacing the base rule of
+// the same name, so a user rule can fix or disable a built-in one.
+func Merge(base, over []ipc.BannerRule) []ipc.BannerRule {
+	merged := make([]ipc.BannerRule, 0, len(base)+len(over))
+	names := make(map[string]int, len(base))
+	for _, r := range base {
+		names[r.Name] = len(merged)
+		merged = append(merged, r)
+	}
+	for _, r := range over {
+		if i, ok := names[r.Name]; ok {
+			merged[i] = r
+			continue
+		}
+		names[r.Name] = len(merged)
+		merged = append(merged, r)
+	}
+	// A replacement without click or hide selectors disables the rule
+	rules := merged[:0]
+	for _, r := range merged {
+		if len(r.Click) > 0 || len(r.Hide) > 0 {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// parse decodes a YAML list of rules, rejecting unknown keys so a misspelt
+// key is not silently ignored.
+func parse(data []byte) ([]ipc.BannerRule, error) {
+	var rules []ipc.BannerRule
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	for i, r := range rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i+1)
+		}
+		for _, h := range r.Hosts {
+			if h == "" || strings.ContainsAny(h, "/:*") {
+				return nil, fmt.Errorf("rule %q: invalid host %q (use a hostname like example.com)", r.Name, h)
+			}
+		}
+	}
+	return rules, nil
+}
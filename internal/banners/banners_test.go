@@ -0,0 +1,81 @@
+package banners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestBuiltin(t *testing.T) {
+	rules := Builtin()
+	if len(rules) == 0 {
+		t.Fatal("no built-in rules")
+	}
+	seen := map[string]bool{}
+	for _, r := range rules {
+		if seen[r.Name] {
+			t.Errorf("duplicate rule %q", r.Name)
+		}
+		seen[r.Name] = true
+		if len(r.Click) == 0 && len(r.Hide) == 0 {
+			t.Errorf("rule %q has no click or hide selectors", r.Name)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	rules, err := LoadFile(filepath.Join(dir, "missing.yaml"))
+	if err != nil || rules != nil {
+		t.Errorf("missing file: %v, %v", rules, err)
+	}
+
+	path := filepath.Join(dir, "banners.yaml")
+	if err := os.WriteFile(path, []byte(`
+- name: shop-newsletter
+  hosts: [shop.test]
+  click: [".newsletter .close"]
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0].Name != "shop-newsletter" || rules[0].Hosts[0] != "shop.test" || rules[0].Click[0] != ".newsletter .close" {
+		t.Errorf("rules = %+v", rules)
+	}
+
+	for name, content := range map[string]string{
+		"unknown key": "- name: x\n  clik: [a]\n",
+		"no name":     "- click: [a]\n",
+		"url host":    "- name: x\n  hosts: [https://shop.test]\n  click: [a]\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := []ipc.BannerRule{
+		{Name: "a", Click: []string{"#a"}},
+		{Name: "b", Hide: []string{"#b"}},
+	}
+	over := []ipc.BannerRule{
+		{Name: "a", Click: []string{"#a2"}},
+		{Name: "b"},
+		{Name: "c", Hide: []string{"#c"}},
+	}
+	got := Merge(base, over)
+	if len(got) != 2 || got[0].Name != "a" || got[0].Click[0] != "#a2" || got[1].Name != "c" {
+		t.Errorf("Merge() = %+v", got)
+	}
+}
@@ -12,10 +12,12 @@ import (
 
 // Browser represents a running Chrome instance with CDP enabled.
 type Browser struct {
-	cmd      *exec.Cmd
+	cmd      *exec.Cmd   // nil for a reused browser, which is not our child
+	proc     *os.Process // the browser process, launched or reused
 	port     int
 	dataDir  string
 	ownsData bool // true if we created the temp data dir
+	adopted  bool // true for a browser someone else launched; never closed
 
 	// systemProfile is true when launched against the user's real Chrome
 	// profile (UserDataDirDefault). Used to give a targeted error if the
@@ -26,7 +28,8 @@ type Browser struct {
 	// done is closed by a single watcher goroutine after cmd.Wait() returns.
 	// waitErr holds that result. Wait is single-call, so this watcher is the
 	// sole reaper: both the startup fail-fast path and Close() observe the exit
-	// through done rather than calling cmd.Wait() themselves.
+	// through done rather than calling cmd.Wait() themselves. A reused browser
+	// cannot be waited for; its watcher polls the process instead.
 	done    chan struct{}
 	waitErr error
 }
@@ -43,6 +46,10 @@ var ErrStartTimeout = errors.New("browser start timeout")
 // ErrPortInUse is returned when the requested port is already in use.
 var ErrPortInUse = errors.New("port is already in use")
 
+// ErrBrowserOnPort is returned when the requested port is taken by a browser
+// already serving CDP, which can be adopted instead of launching another.
+var ErrBrowserOnPort = errors.New("a browser is already listening on the debug port")
+
 // ErrSystemProfileInUse is returned when --system-profile is selected but the
 // launched browser exits before CDP comes up, which typically means another
 // Chrome instance already holds the default profile and the new launch
@@ -101,7 +108,7 @@ func StartWithBinary(binPath string, opts LaunchOptions) (*Browser, error) {
 		// Port explicitly specified - use it or fail
 		port = opts.Port
 		if !isPortAvailable(port) {
-			return nil, fmt.Errorf("%w: %d", ErrPortInUse, port)
+			return nil, portInUseError(port)
 		}
 	}
 
@@ -115,9 +122,10 @@ func StartWithBinary(binPath string, opts LaunchOptions) (*Browser, error) {
 
 	b := &Browser{
 		cmd:           cmd,
+		proc:          cmd.Process,
 		port:          port,
 		dataDir:       dataDir,
-		ownsData:      opts.UserDataDir == "", // we created temp dir if UserDataDir was empty
+		ownsData:      opts.UserDataDir == "" || opts.TempProfile, // we created temp dir if UserDataDir was empty
 		systemProfile: opts.UserDataDir == UserDataDirDefault,
 		done:          make(chan struct{}),
 	}
@@ -130,7 +138,11 @@ func StartWithBinary(binPath string, opts LaunchOptions) (*Browser, error) {
 	}()
 
 	// Wait for CDP endpoint to become available
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	timeout := StartTimeout
+	if opts.CI {
+		timeout = CIStartTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	if err := b.waitForCDP(ctx); err != nil {
@@ -141,6 +153,17 @@ func StartWithBinary(binPath string, opts LaunchOptions) (*Browser, error) {
 	return b, nil
 }
 
+// portInUseError describes a taken port, telling apart a browser serving CDP
+// on it from any other process.
+func portInUseError(port int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if v, err := FetchVersion(ctx, "127.0.0.1", port); err == nil {
+		return fmt.Errorf("%w: %d (%s)", ErrBrowserOnPort, port, v.Browser)
+	}
+	return fmt.Errorf("%w: %d", ErrPortInUse, port)
+}
+
 // waitForCDP polls the CDP endpoint until it responds or context is cancelled.
 func (b *Browser) waitForCDP(ctx context.Context) error {
 	ticker := time.NewTicker(100 * time.Millisecond)
@@ -182,10 +205,50 @@ func (b *Browser) Port() int {
 
 // PID returns the browser process ID.
 func (b *Browser) PID() int {
-	if b.cmd == nil || b.cmd.Process == nil {
+	if b.proc == nil {
 		return 0
 	}
-	return b.cmd.Process.Pid
+	return b.proc.Pid
+}
+
+// DataDir returns the browser's profile directory.
+func (b *Browser) DataDir() string {
+	return b.dataDir
+}
+
+// TempProfile reports whether the profile directory is a throwaway one that
+// Close deletes.
+func (b *Browser) TempProfile() bool {
+	return b.ownsData
+}
+
+// KeepProfile stops Close from deleting a temporary profile directory, so it
+// can be handed to another browser with LaunchOptions.TempProfile.
+func (b *Browser) KeepProfile() {
+	b.ownsData = false
+}
+
+// Adopted reports whether the browser was adopted rather than launched or
+// reused. Close leaves an adopted browser running.
+func (b *Browser) Adopted() bool {
+	return b.adopted
+}
+
+// Exited returns a channel that is closed when the browser process exits, for
+// whatever reason.
+func (b *Browser) Exited() <-chan struct{} {
+	return b.done
+}
+
+// ExitErr returns the error the browser process exited with, nil for a clean
+// exit. It is only meaningful once Exited is closed.
+func (b *Browser) ExitErr() error {
+	select {
+	case <-b.done:
+		return b.waitErr
+	default:
+		return nil
+	}
 }
 
 // Targets fetches the list of available CDP targets.
@@ -213,31 +276,32 @@ func (b *Browser) Version(ctx context.Context) (*VersionInfo, error) {
 	return FetchVersion(ctx, "127.0.0.1", b.port)
 }
 
-// Close terminates the browser process and cleans up resources.
+// Close terminates the browser process and cleans up resources. An adopted
+// browser is left running.
 func (b *Browser) Close() error {
-	if b.cmd == nil || b.cmd.Process == nil {
+	if b.adopted || b.proc == nil {
 		return nil
 	}
 
 	// Send SIGTERM for graceful shutdown
-	if err := b.cmd.Process.Signal(os.Interrupt); err != nil {
+	if err := b.proc.Signal(os.Interrupt); err != nil {
 		// Process may have already exited
 		if !errors.Is(err, os.ErrProcessDone) {
 			// Force kill
-			_ = b.cmd.Process.Kill()
+			_ = b.proc.Kill()
 		}
 	}
 
 	// Wait for the process to exit with a timeout. The watcher goroutine started
-	// in StartWithBinary owns the single cmd.Wait() call and closes b.done; we
-	// only observe it here.
+	// in StartWithBinary or Reuse owns the wait and closes b.done; we only
+	// observe it here.
 	select {
 	case <-b.done:
 		// Process exited cleanly
 	case <-time.After(5 * time.Second):
 		// Force kill after timeout. SIGKILL cannot be caught or ignored on POSIX,
 		// so the process will terminate and cmd.Wait() will return.
-		_ = b.cmd.Process.Kill()
+		_ = b.proc.Kill()
 		<-b.done
 	}
 
@@ -246,7 +310,7 @@ func (b *Browser) Close() error {
 		_ = os.RemoveAll(b.dataDir)
 	}
 
-	b.cmd = nil
+	b.proc = nil
 	return nil
 }
 
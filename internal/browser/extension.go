@@ -0,0 +1,70 @@
+package browser
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Extension is an unpacked extension loaded with LaunchOptions.Extensions.
+type Extension struct {
+	ID      string
+	Name    string
+	Version string
+	Path    string
+}
+
+// ReadExtension reads the manifest of the unpacked extension in dir and works
+// out the ID Chrome gives it.
+func ReadExtension(dir string) (Extension, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Extension{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(abs, "manifest.json"))
+	if err != nil {
+		return Extension{}, fmt.Errorf("not an unpacked extension: %w", err)
+	}
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Key     string `json:"key"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Extension{}, fmt.Errorf("invalid manifest.json in %s: %w", abs, err)
+	}
+	id, err := extensionID(abs, manifest.Key)
+	if err != nil {
+		return Extension{}, fmt.Errorf("invalid key in %s/manifest.json: %w", abs, err)
+	}
+	return Extension{ID: id, Name: manifest.Name, Version: manifest.Version, Path: abs}, nil
+}
+
+// extensionID derives an extension ID the way Chrome does: from the public key
+// in the manifest if it has one, otherwise from the absolute path of an
+// unpacked extension. The first 128 bits of the SHA-256 hash are written with
+// the letters a-p in place of the hex digits 0-f.
+func extensionID(path, key string) (string, error) {
+	input := []byte(path)
+	if key != "" {
+		der, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return "", err
+		}
+		input = der
+	}
+	sum := sha256.Sum256(input)
+	id := []byte(hex.EncodeToString(sum[:16]))
+	for i, c := range id {
+		v := c - '0'
+		if c >= 'a' {
+			v = c - 'a' + 10
+		}
+		id[i] = 'a' + v
+	}
+	return string(id), nil
+}
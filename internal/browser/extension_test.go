@@ -0,0 +1,53 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtensionID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name, path, key, want string
+	}{
+		{"unpacked path", "/home/user/my-extension", "", "nilnepjhjgfacmijbmgplkmpgnbgekgi"},
+		{"manifest key", "/home/user/my-extension", "dGVzdC1rZXk=", "gckpihaehgepkpiokicpmgbmojmemdja"},
+	}
+	for _, tt := range tests {
+		got, err := extensionID(tt.path, tt.key)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := extensionID("/x", "not base64!"); err == nil {
+		t.Error("expected an error for an invalid key")
+	}
+}
+
+func TestReadExtension(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if _, err := ReadExtension(dir); err == nil {
+		t.Error("expected an error for a directory without manifest.json")
+	}
+
+	manifest := `{"manifest_version": 3, "name": "Dev Tool", "version": "1.2.0"}`
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ext, err := ReadExtension(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantID, _ := extensionID(dir, "")
+	if ext.ID != wantID || ext.Name != "Dev Tool" || ext.Version != "1.2.0" || ext.Path != dir {
+		t.Errorf("got %+v", ext)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
+	"time"
 )
 
 // LaunchOptions configures browser launch behavior.
@@ -21,11 +23,50 @@ type LaunchOptions struct {
 	//   - "default": use the user's default Chrome profile
 	//   - Any path: use that directory
 	UserDataDir string
+
+	// TempProfile marks UserDataDir as a throwaway profile carried over from
+	// an earlier launch, deleted when the browser is closed as if it had been
+	// created for it.
+	TempProfile bool
+
+	// Lang sets the browser's UI and Accept-Language locale, e.g. "en-GB".
+	Lang string
+
+	// WindowSize is the initial window size as "WIDTH,HEIGHT", and
+	// WindowPosition its position as "X,Y", both in screen pixels.
+	WindowSize     string
+	WindowPosition string
+
+	// Incognito opens the first window in incognito mode, which keeps
+	// cookies and storage out of the profile.
+	Incognito bool
+
+	// Extensions are directories of unpacked extensions to load.
+	Extensions []string
+
+	// DevTools opens a DevTools window for every tab. It has no effect when
+	// headless.
+	DevTools bool
+
+	// ExtraFlags are appended to the command line as given.
+	ExtraFlags []string
+
+	// CI adds the flags Chrome needs in containers and CI runners, which
+	// often run as root without a GPU, and gives up on a browser that has not
+	// served CDP within CIStartTimeout instead of StartTimeout.
+	CI bool
 }
 
 // DefaultPort is the default CDP debugging port.
 const DefaultPort = 9222
 
+// StartTimeout is how long Start waits for the browser to serve CDP.
+const StartTimeout = 30 * time.Second
+
+// CIStartTimeout replaces StartTimeout with LaunchOptions.CI, so a browser that
+// cannot start in a CI job fails the step quickly.
+const CIStartTimeout = 15 * time.Second
+
 // UserDataDirDefault is the special value that means "use the user's Chrome profile".
 const UserDataDirDefault = "default"
 
@@ -72,6 +113,9 @@ func buildArgs(opts LaunchOptions) []string {
 	// Allow popups in headed mode for debugging; block in headless (invisible anyway)
 	if !opts.Headless {
 		args = append(args, "--disable-popup-blocking")
+		if opts.DevTools {
+			args = append(args, "--auto-open-devtools-for-tabs")
+		}
 	}
 
 	// Platform-specific flags to avoid system dialogs
@@ -86,6 +130,12 @@ func buildArgs(opts LaunchOptions) []string {
 		args = append(args, "--headless")
 	}
 
+	// Container/CI runners: the sandbox cannot start as root or without user
+	// namespaces, and there is rarely a GPU to use
+	if opts.CI {
+		args = append(args, "--no-sandbox", "--disable-gpu")
+	}
+
 	// Handle user data directory:
 	// - Empty or "default": no flag (use user's Chrome profile)
 	// - Any path: use that directory
@@ -104,6 +154,27 @@ func buildArgs(opts LaunchOptions) []string {
 		args = append(args, "--hide-crash-restore-bubble")
 	}
 
+	if opts.Lang != "" {
+		args = append(args, "--lang="+opts.Lang)
+	}
+	if opts.WindowSize != "" {
+		args = append(args, "--window-size="+opts.WindowSize)
+	}
+	if opts.WindowPosition != "" {
+		args = append(args, "--window-position="+opts.WindowPosition)
+	}
+	if opts.Incognito {
+		args = append(args, "--incognito")
+	}
+	if len(opts.Extensions) > 0 {
+		// Branded Chrome ignores --load-extension unless this switch is
+		// turned back on
+		args = append(args,
+			"--load-extension="+strings.Join(opts.Extensions, ","),
+			"--disable-features=DisableLoadExtensionCommandLineSwitch")
+	}
+	args = append(args, opts.ExtraFlags...)
+
 	// Open about:blank to avoid any default page loading
 	args = append(args, "about:blank")
 
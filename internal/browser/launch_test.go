@@ -135,6 +135,91 @@ func TestBuildArgs_UserDataDirDefault(t *testing.T) {
 	}
 }
 
+func TestBuildArgs_Customization(t *testing.T) {
+	t.Parallel()
+
+	opts := LaunchOptions{
+		Lang:           "en-GB",
+		WindowSize:     "1280,800",
+		WindowPosition: "0,0",
+		Incognito:      true,
+		ExtraFlags:     []string{"--disable-gpu", "--enable-features=Foo"},
+	}
+	args := buildArgs(opts)
+
+	for _, want := range []string{"--lang=en-GB", "--window-size=1280,800", "--window-position=0,0", "--incognito", "--disable-gpu", "--enable-features=Foo"} {
+		if !containsArg(args, want) {
+			t.Errorf("expected %s in args: %v", want, args)
+		}
+	}
+	if args[len(args)-1] != "about:blank" {
+		t.Errorf("expected about:blank last, args: %v", args)
+	}
+}
+
+func TestBuildArgs_Extensions(t *testing.T) {
+	t.Parallel()
+
+	args := buildArgs(LaunchOptions{Extensions: []string{"/ext/one", "/ext/two"}})
+	if !containsArg(args, "--load-extension=/ext/one,/ext/two") {
+		t.Errorf("expected --load-extension with both directories, args: %v", args)
+	}
+	if !containsArg(args, "--disable-features=DisableLoadExtensionCommandLineSwitch") {
+		t.Errorf("expected the load-extension switch to be re-enabled, args: %v", args)
+	}
+}
+
+func TestBuildArgs_DevTools(t *testing.T) {
+	t.Parallel()
+
+	if args := buildArgs(LaunchOptions{DevTools: true}); !containsArg(args, "--auto-open-devtools-for-tabs") {
+		t.Errorf("expected --auto-open-devtools-for-tabs, args: %v", args)
+	}
+	if args := buildArgs(LaunchOptions{DevTools: true, Headless: true}); containsArg(args, "--auto-open-devtools-for-tabs") {
+		t.Errorf("unexpected --auto-open-devtools-for-tabs when headless, args: %v", args)
+	}
+}
+
+func TestBuildArgs_NoCustomization(t *testing.T) {
+	t.Parallel()
+
+	args := buildArgs(LaunchOptions{})
+	for _, arg := range args {
+		for _, prefix := range []string{"--lang", "--window-size", "--window-position", "--incognito", "--load-extension"} {
+			if strings.HasPrefix(arg, prefix) {
+				t.Errorf("unexpected %s in args: %v", arg, args)
+			}
+		}
+	}
+}
+
+func TestBuildArgs_CI(t *testing.T) {
+	t.Parallel()
+
+	has := func(args []string, flag string) bool {
+		for _, arg := range args {
+			if arg == flag {
+				return true
+			}
+		}
+		return false
+	}
+
+	args := buildArgs(LaunchOptions{CI: true})
+	for _, flag := range []string{"--no-sandbox", "--disable-gpu", "--disable-dev-shm-usage"} {
+		if !has(args, flag) {
+			t.Errorf("expected %s in CI args: %v", flag, args)
+		}
+	}
+
+	args = buildArgs(LaunchOptions{})
+	for _, flag := range []string{"--no-sandbox", "--disable-gpu"} {
+		if has(args, flag) {
+			t.Errorf("unexpected %s without CI: %v", flag, args)
+		}
+	}
+}
+
 func TestBuildArgs_RequiredFlags(t *testing.T) {
 	t.Parallel()
 
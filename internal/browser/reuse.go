@@ -0,0 +1,69 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ErrNotReusable is returned by Reuse when the recorded browser is no longer
+// running or no longer serving CDP on its port.
+var ErrNotReusable = errors.New("no running browser to reuse")
+
+// reusePollInterval is how often a reused browser's process is checked for
+// exit. It is not our child, so it cannot be waited for.
+const reusePollInterval = 500 * time.Millisecond
+
+// Reuse takes over a browser launched by an earlier daemon that is still
+// running, identified by its process ID and CDP port. The returned Browser
+// owns it as if it had been launched here: Close terminates it and, when
+// tempProfile is set, deletes dataDir.
+func Reuse(ctx context.Context, pid, port int, dataDir string, tempProfile bool) (*Browser, error) {
+	if pid <= 0 || port <= 0 {
+		return nil, ErrNotReusable
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil || proc.Signal(syscall.Signal(0)) != nil {
+		return nil, fmt.Errorf("%w: browser process %d has exited", ErrNotReusable, pid)
+	}
+	if _, err := FetchVersion(ctx, "127.0.0.1", port); err != nil {
+		return nil, fmt.Errorf("%w: no CDP endpoint on port %d", ErrNotReusable, port)
+	}
+
+	b := &Browser{
+		proc:     proc,
+		port:     port,
+		dataDir:  dataDir,
+		ownsData: tempProfile,
+		done:     make(chan struct{}),
+	}
+
+	// The process is not our child, so its exit status is unknown; it is
+	// reported as a clean exit.
+	go func() {
+		ticker := time.NewTicker(reusePollInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if proc.Signal(syscall.Signal(0)) != nil {
+				close(b.done)
+				return
+			}
+		}
+	}()
+
+	return b, nil
+}
+
+// Adopt connects to a browser that is already serving CDP on port but was not
+// launched by webctl, such as the user's own Chrome started with
+// --remote-debugging-port. The returned Browser never closes it, and since its
+// process is unknown, Exited never fires; loss is seen on the CDP connection.
+func Adopt(ctx context.Context, port int) (*Browser, error) {
+	if _, err := FetchVersion(ctx, "127.0.0.1", port); err != nil {
+		return nil, fmt.Errorf("no browser serving CDP on port %d to adopt: %w", port, err)
+	}
+	return &Browser{port: port, adopted: true, done: make(chan struct{})}, nil
+}
@@ -0,0 +1,116 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// versionServer serves /json/version and returns its port.
+func versionServer(t *testing.T) int {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(VersionInfo{WebSocketURL: "ws://127.0.0.1/devtools/browser/abc"})
+	}))
+	t.Cleanup(server.Close)
+	port, _ := strconv.Atoi(server.URL[strings.LastIndex(server.URL, ":")+1:])
+	return port
+}
+
+func TestReuse_NotReusable(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := Reuse(ctx, 0, 9222, "", false); !errors.Is(err, ErrNotReusable) {
+		t.Errorf("missing PID: expected ErrNotReusable, got %v", err)
+	}
+
+	// A live process with nothing serving CDP on the port.
+	if _, err := Reuse(ctx, os.Getpid(), findTestPort(t), "", false); !errors.Is(err, ErrNotReusable) {
+		t.Errorf("no endpoint: expected ErrNotReusable, got %v", err)
+	}
+}
+
+func TestReuse_TakesOwnership(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no sleep on windows")
+	}
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// Reap the child so the reused browser's poller sees it go.
+	go func() { _ = cmd.Wait() }()
+
+	dataDir := filepath.Join(t.TempDir(), "profile")
+	if err := os.Mkdir(dataDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Reuse(context.Background(), cmd.Process.Pid, versionServer(t), dataDir, true)
+	if err != nil {
+		t.Fatalf("Reuse: %v", err)
+	}
+	if b.PID() != cmd.Process.Pid || b.DataDir() != dataDir || !b.TempProfile() {
+		t.Errorf("unexpected browser: pid=%d dataDir=%q temp=%v", b.PID(), b.DataDir(), b.TempProfile())
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-b.Exited():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Exited not closed after Close")
+	}
+	if _, err := os.Stat(dataDir); !os.IsNotExist(err) {
+		t.Errorf("temp profile should be removed on Close, stat err = %v", err)
+	}
+}
+
+func TestAdopt(t *testing.T) {
+	port := versionServer(t)
+
+	b, err := Adopt(context.Background(), port)
+	if err != nil {
+		t.Fatalf("Adopt: %v", err)
+	}
+	if !b.Adopted() || b.Port() != port || b.PID() != 0 {
+		t.Errorf("unexpected browser: adopted=%v port=%d pid=%d", b.Adopted(), b.Port(), b.PID())
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("Close on an adopted browser should be a no-op, got %v", err)
+	}
+
+	if _, err := Adopt(context.Background(), findTestPort(t)); err == nil {
+		t.Error("expected an error with nothing on the port")
+	}
+}
+
+func TestStart_PortTakenByBrowser(t *testing.T) {
+	port := versionServer(t)
+	_, err := StartWithBinary("/nonexistent", LaunchOptions{Port: port})
+	if !errors.Is(err, ErrBrowserOnPort) {
+		t.Errorf("expected ErrBrowserOnPort, got %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+	_, err = StartWithBinary("/nonexistent", LaunchOptions{Port: ln.Addr().(*net.TCPAddr).Port})
+	if !errors.Is(err, ErrPortInUse) {
+		t.Errorf("expected ErrPortInUse for a non-CDP listener, got %v", err)
+	}
+}
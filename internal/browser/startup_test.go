@@ -2,6 +2,7 @@ package browser
 
 import (
 	"errors"
+	"os/exec"
 	"runtime"
 	"testing"
 	"time"
@@ -64,3 +65,28 @@ func findTestPort(t *testing.T) int {
 	}
 	return port
 }
+
+func TestExited(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no /bin/false on windows")
+	}
+	cmd := exec.Command("/bin/false")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	b := &Browser{cmd: cmd, done: make(chan struct{})}
+	go func() {
+		b.waitErr = b.cmd.Wait()
+		close(b.done)
+	}()
+
+	select {
+	case <-b.Exited():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Exited not closed after the process exited")
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(b.ExitErr(), &exitErr) {
+		t.Errorf("expected an exit error, got %v", b.ExitErr())
+	}
+}
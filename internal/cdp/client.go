@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -37,8 +38,21 @@ type Client struct {
 
 	// done signals that the read loop has exited
 	done chan struct{}
+
+	// log receives command and connection records; nil discards them.
+	log atomic.Pointer[slog.Logger]
+
+	// observer, if set, is told the outcome and round-trip time of each command.
+	observer atomic.Pointer[CommandObserver]
 }
 
+// CommandObserver is called after each command completes (or fails) with its
+// method, round-trip time, and error.
+type CommandObserver func(method string, d time.Duration, err error)
+
+// discardLogger is used until SetLogger is called.
+var discardLogger = slog.New(slog.DiscardHandler)
+
 // NewClient creates a new CDP client with the given connection.
 func NewClient(conn Conn) *Client {
 	c := &Client{
@@ -66,6 +80,30 @@ func Dial(ctx context.Context, wsURL string) (*Client, error) {
 	return NewClient(conn), nil
 }
 
+// SetLogger directs the client's log records (commands at debug level,
+// malformed messages and connection loss at warn) to l. Safe to call at any time.
+func (c *Client) SetLogger(l *slog.Logger) {
+	c.log.Store(l)
+}
+
+// SetObserver registers fn to be called after every command, for metrics.
+// Safe to call at any time; nil removes the observer.
+func (c *Client) SetObserver(fn CommandObserver) {
+	if fn == nil {
+		c.observer.Store(nil)
+		return
+	}
+	c.observer.Store(&fn)
+}
+
+// logger returns the current logger, or one that discards.
+func (c *Client) logger() *slog.Logger {
+	if l := c.log.Load(); l != nil {
+		return l
+	}
+	return discardLogger
+}
+
 // Send sends a CDP command and waits for the response.
 // Uses the default timeout.
 func (c *Client) Send(method string, params interface{}) (json.RawMessage, error) {
@@ -113,17 +151,36 @@ func (c *Client) SendToSession(ctx context.Context, sessionID string, method str
 	}
 
 	// Wait for response
+	start := time.Now()
+	var result json.RawMessage
 	select {
 	case resp := <-respCh:
 		if resp.Error != nil {
-			return nil, resp.Error
+			err = resp.Error
+		} else {
+			result = resp.Result
 		}
-		return resp.Result, nil
 	case <-ctx.Done():
-		return nil, fmt.Errorf("request timed out: %w", ctx.Err())
+		err = fmt.Errorf("request timed out: %w", ctx.Err())
 	case <-c.closedCh:
-		return nil, errors.New("client closed while waiting for response")
+		err = errors.New("client closed while waiting for response")
+	}
+
+	elapsed := time.Since(start)
+	if obs := c.observer.Load(); obs != nil {
+		(*obs)(method, elapsed, err)
+	}
+	if log := c.logger(); log.Enabled(context.Background(), slog.LevelDebug) {
+		attrs := []any{"method", method, "session", sessionID, "id", id, "duration", elapsed}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		}
+		log.Debug("cdp command", attrs...)
+	}
+	if err != nil {
+		return nil, err
 	}
+	return result, nil
 }
 
 // Subscribe registers a handler for CDP events matching the given method.
@@ -175,13 +232,18 @@ func (c *Client) readLoop() {
 	for {
 		_, data, err := c.conn.Read(ctx)
 		if err != nil {
+			// A read error after Close is the expected shutdown path.
+			if !c.closed.Load() {
+				c.logger().Warn("cdp connection lost", "error", err)
+			}
 			c.markClosed(err)
 			return
 		}
 
 		resp, evt, err := parseMessage(data)
 		if err != nil {
-			continue // Skip malformed messages
+			c.logger().Warn("cdp: skipping malformed message", "error", err)
+			continue
 		}
 
 		if resp != nil {
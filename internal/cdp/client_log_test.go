@@ -0,0 +1,77 @@
+package cdp
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer is a bytes.Buffer safe for the read loop and callers to share.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestClient_SetLogger_RecordsCommands(t *testing.T) {
+	t.Parallel()
+
+	var out syncBuffer
+	client := NewClient(newEchoMockConnWithResult(`{}`))
+	client.SetLogger(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	if _, err := client.Send("Page.navigate", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = client.Close()
+
+	got := out.String()
+	for _, want := range []string{"msg=\"cdp command\"", "method=Page.navigate", "id=1", "duration="} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "connection lost") {
+		t.Errorf("Close should not log a lost connection:\n%s", got)
+	}
+}
+
+func TestClient_SetLogger_RecordsCommandErrors(t *testing.T) {
+	t.Parallel()
+
+	var out syncBuffer
+	client := NewClient(newEchoMockConnWithError(-32000, "Cannot navigate"))
+	defer func() { _ = client.Close() }()
+	client.SetLogger(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	if _, err := client.Send("Page.navigate", nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := out.String(); !strings.Contains(got, "Cannot navigate") {
+		t.Errorf("log missing command error:\n%s", got)
+	}
+}
+
+func TestClient_NoLogger_Discards(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(newEchoMockConnWithResult(`{}`))
+	defer func() { _ = client.Close() }()
+
+	if _, err := client.Send("Page.navigate", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/executor"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// saveFailureArtifacts captures a screenshot and the page HTML into dir after
+// a failed wait or assertion, so a CI failure comes with evidence of what the
+// page showed. The files are named after the time and the command. It returns
+// the paths saved; a capture that fails is left out rather than hiding the
+// original failure.
+func saveFailureArtifacts(exec executor.Executor, dir, name string) []string {
+	base := filepath.Join(dir, time.Now().Format("06-01-02-150405")+"-"+name)
+	var saved []string
+
+	params, _ := json.Marshal(ipc.ScreenshotParams{})
+	if resp, err := writeScreenshot(exec, params, base+".png"); err == nil && resp.OK {
+		saved = append(saved, base+".png")
+	} else {
+		debugParam("artifact screenshot failed: err=%v resp=%q", err, resp.Error)
+	}
+
+	debugRequest("html", "artifacts")
+	resp, err := executeStreamData(exec, ipc.Request{Cmd: "html", Params: json.RawMessage(`{}`)})
+	if err == nil && resp.OK {
+		var data ipc.HTMLData
+		if err = json.Unmarshal(resp.Data, &data); err == nil {
+			err = os.WriteFile(base+".html", []byte(data.HTML), 0644)
+		}
+		if err == nil {
+			saved = append(saved, base+".html")
+		}
+	}
+	if err != nil || !resp.OK {
+		debugParam("artifact html failed: err=%v resp=%q", err, resp.Error)
+	}
+
+	return saved
+}
+
+// outputArtifactsError reports a failed daemon response like
+// outputResponseError, adding the failure artifacts: an "artifacts" field in
+// JSON mode, a line after the error in text mode.
+func outputArtifactsError(resp ipc.Response, artifacts []string) error {
+	if len(artifacts) == 0 {
+		return outputResponseError(resp)
+	}
+	code := resp.Code
+	if code == "" {
+		code = ipc.ErrorCode(resp.Error)
+	}
+	if JSONOutput {
+		_ = outputJSON(os.Stderr, map[string]any{
+			"ok":        false,
+			"error":     resp.Error,
+			"code":      code,
+			"artifacts": artifacts,
+		})
+		return printedError{err: fmt.Errorf("%s", resp.Error), code: code}
+	}
+	err := outputCodedError(code, resp.Error)
+	fmt.Fprintf(os.Stderr, "Artifacts: %s\n", strings.Join(artifacts, ", "))
+	return err
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// artifactsExecutor fails the wait or count with failure, and serves a
+// screenshot and the page HTML for the artifacts.
+func artifactsExecutor(t *testing.T, failure ipc.Response) *mockExecutor {
+	t.Helper()
+	png := testPNG(t, 4, 4)
+	return &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		switch req.Cmd {
+		case "screenshot":
+			return ipc.SuccessResponse(ipc.ScreenshotData{Data: png}), nil
+		case "html":
+			return ipc.SuccessResponse(ipc.HTMLData{HTML: "<html><body>Loading...</body></html>"}), nil
+		}
+		return failure, nil
+	}}
+}
+
+func TestRunReady_Artifacts(t *testing.T) {
+	enableJSONOutput(t)
+	exec := artifactsExecutor(t, ipc.ErrorResponse("timeout waiting for: #dashboard"))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	dir := filepath.Join(t.TempDir(), "artifacts")
+	cmd := &cobra.Command{}
+	cmd.Flags().Duration("timeout", time.Second, "")
+	cmd.Flags().Bool("network-idle", false, "")
+	cmd.Flags().String("eval", "", "")
+	cmd.Flags().String("artifacts", "", "")
+	_ = cmd.Flags().Set("artifacts", dir)
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runReady(cmd, []string{"#dashboard"})
+	})
+	if err == nil {
+		t.Fatal("expected error on timeout")
+	}
+	var result struct {
+		Error     string   `json:"error"`
+		Artifacts []string `json:"artifacts"`
+	}
+	if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+		t.Fatalf("invalid JSON: %v\n%s", jerr, out)
+	}
+	if result.Error != "timeout waiting for: #dashboard" || len(result.Artifacts) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	if !strings.HasSuffix(result.Artifacts[0], "-ready.png") || !strings.HasSuffix(result.Artifacts[1], "-ready.html") {
+		t.Errorf("artifacts = %v", result.Artifacts)
+	}
+	html, rerr := os.ReadFile(result.Artifacts[1])
+	if rerr != nil || !strings.Contains(string(html), "Loading...") {
+		t.Errorf("html artifact = %q, %v", html, rerr)
+	}
+	if _, serr := os.Stat(result.Artifacts[0]); serr != nil {
+		t.Errorf("screenshot artifact: %v", serr)
+	}
+}
+
+func TestRunCount_Artifacts(t *testing.T) {
+	raw, _ := json.Marshal(ipc.CountData{Count: 2})
+	exec := artifactsExecutor(t, ipc.Response{OK: true, Data: raw})
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	dir := t.TempDir()
+	setCountFlag(t, "max", "0")
+	setCountFlag(t, "artifacts", dir)
+
+	var err error
+	stderr := captureStream(t, &os.Stderr, func() {
+		captureStream(t, &os.Stdout, func() {
+			err = runCount(countCmd, []string{".error"})
+		})
+	})
+	if err == nil {
+		t.Fatal("expected threshold failure")
+	}
+	if !strings.Contains(stderr, "Artifacts: "+dir) {
+		t.Errorf("stderr = %q", stderr)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*-count.*"))
+	if len(files) != 2 {
+		t.Errorf("files = %v", files)
+	}
+}
+
+func TestRunCount_NoArtifactsOnPass(t *testing.T) {
+	raw, _ := json.Marshal(ipc.CountData{Count: 0})
+	exec := artifactsExecutor(t, ipc.Response{OK: true, Data: raw})
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	dir := filepath.Join(t.TempDir(), "artifacts")
+	setCountFlag(t, "max", "0")
+	setCountFlag(t, "artifacts", dir)
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		err = runCount(countCmd, []string{".error"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, serr := os.Stat(dir); !os.IsNotExist(serr) {
+		t.Errorf("artifacts directory created on pass: %v", serr)
+	}
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var assertCmd = &cobra.Command{
+	Use:   "assert",
+	Short: "Check captured traffic against expectations, failing on mismatch",
+	Long: `Checks what the browser has captured against an expectation and exits 1 when
+it does not hold, as a CI guard.
+
+Subcommands:
+  header <url-pattern> <Header-Name> <regex>    Check a response header`,
+}
+
+var assertHeaderCmd = &cobra.Command{
+	Use:   "header <url-pattern> <Header-Name> <regex>",
+	Short: "Check that matching responses carry a header with a matching value",
+	Long: `Checks every captured response in the active tab whose URL matches
+url-pattern for the header Header-Name, and that its value matches regex.
+Exits 1 if any response lacks the header or has a value that does not
+match, or if no captured response matches url-pattern.
+
+url-pattern and regex are Go regular expressions, as for network --url, and
+match anywhere unless anchored with ^ and $. Header names are matched
+without regard to case. A header sent more than once is checked with its
+values joined by newlines. Requests that failed without a response are not
+checked.
+
+Only traffic already in the network buffer counts: load the pages first.
+
+Examples:
+  assert header '^https://app\.test/$' Cache-Control 'no-store'
+  assert header '\.js$' Cache-Control 'max-age=\d{5,}'
+  assert header . Content-Security-Policy "default-src 'self'"
+  assert header /api/ Content-Type '^application/json'
+
+Response:
+  PASS  200 GET https://app.test/  cache-control: no-store
+  FAIL  200 GET https://app.test/app.js  cache-control: no-cache
+  FAIL  200 GET https://app.test/logo.svg  cache-control missing
+  2 of 3 responses failed
+
+Error cases:
+  - "no captured responses match ..." - load the page first, or check the pattern
+  - "invalid url pattern ..." / "invalid regex ..." - not a Go regular expression
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.ExactArgs(3),
+	RunE: runAssertHeader,
+}
+
+func init() {
+	assertCmd.AddCommand(assertHeaderCmd)
+	rootCmd.AddCommand(assertCmd)
+}
+
+// headerCheck is the result of checking one response for a header.
+type headerCheck struct {
+	Seq    uint64 `json:"seq"`
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Status int    `json:"status"`
+	// Present reports whether the response had the header at all.
+	Present bool   `json:"present"`
+	Value   string `json:"value,omitempty"`
+	OK      bool   `json:"ok"`
+}
+
+func runAssertHeader(cmd *cobra.Command, args []string) error {
+	t := startTimer("assert header")
+	defer t.log()
+
+	urlRe, err := regexp.Compile(args[0])
+	if err != nil {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid url pattern %q: %v", args[0], err))
+	}
+	name := args[1]
+	valueRe, err := regexp.Compile(args[2])
+	if err != nil {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid regex %q: %v", args[2], err))
+	}
+	debugParam("url=%q header=%q regex=%q", args[0], name, args[2])
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+	entries, err := fetchNetworkEntries()
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	checks := checkResponseHeader(entries, urlRe, name, valueRe)
+	if len(checks) == 0 {
+		return outputCodedError(ipc.CodeNotFound, fmt.Sprintf("no captured responses match %q", args[0]))
+	}
+	failed := 0
+	for _, c := range checks {
+		if !c.OK {
+			failed++
+		}
+	}
+
+	if JSONOutput {
+		if err := outputJSON(os.Stdout, map[string]any{
+			"ok":      failed == 0,
+			"header":  name,
+			"regex":   args[2],
+			"checked": len(checks),
+			"failed":  failed,
+			"results": checks,
+		}); err != nil {
+			return err
+		}
+	} else if err := writeHeaderChecks(os.Stdout, checks, strings.ToLower(name), failed); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return printedError{err: errors.New("header assertion failed")}
+	}
+	return nil
+}
+
+// checkResponseHeader checks the header of every response whose URL matches
+// urlRe, in buffer order.
+func checkResponseHeader(entries []ipc.NetworkEntry, urlRe *regexp.Regexp, name string, valueRe *regexp.Regexp) []headerCheck {
+	var checks []headerCheck
+	for _, e := range entries {
+		if e.Status == 0 || !urlRe.MatchString(e.URL) {
+			continue
+		}
+		c := headerCheck{Seq: e.Seq, Method: e.Method, URL: e.URL, Status: e.Status}
+		for k, v := range e.ResponseHeaders {
+			if strings.EqualFold(k, name) {
+				c.Present, c.Value = true, v
+				break
+			}
+		}
+		c.OK = c.Present && valueRe.MatchString(c.Value)
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// writeHeaderChecks prints one line per checked response and a summary.
+func writeHeaderChecks(w io.Writer, checks []headerCheck, name string, failed int) error {
+	for _, c := range checks {
+		result := "PASS"
+		if !c.OK {
+			result = "FAIL"
+		}
+		detail := name + ": " + strings.ReplaceAll(c.Value, "\n", ", ")
+		if !c.Present {
+			detail = name + " missing"
+		}
+		if _, err := fmt.Fprintf(w, "%s  %d %s %s  %s\n", result, c.Status, c.Method, c.URL, detail); err != nil {
+			return err
+		}
+	}
+	responses := "responses"
+	if len(checks) == 1 {
+		responses = "response"
+	}
+	var err error
+	if failed > 0 {
+		_, err = fmt.Fprintf(w, "%d of %d %s failed\n", failed, len(checks), responses)
+	} else {
+		_, err = fmt.Fprintf(w, "%d %s passed\n", len(checks), responses)
+	}
+	return err
+}
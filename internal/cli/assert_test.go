@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunAssertHeader(t *testing.T) {
+	exec := networkDiffExecutor(
+		ipc.NetworkEntry{Seq: 1, Method: "GET", URL: "https://app.test/", Status: 200, ResponseHeaders: map[string]string{"cache-control": "no-store"}},
+		ipc.NetworkEntry{Seq: 2, Method: "GET", URL: "https://app.test/app.js", Status: 200, ResponseHeaders: map[string]string{"Cache-Control": "no-cache"}},
+		ipc.NetworkEntry{Seq: 3, Method: "GET", URL: "https://app.test/logo.svg", Status: 200},
+		ipc.NetworkEntry{Seq: 4, Method: "GET", URL: "https://app.test/down", Failed: true, Error: "net::ERR_FAILED"},
+		ipc.NetworkEntry{Seq: 5, Method: "GET", URL: "https://cdn.test/lib.js", Status: 200},
+	)
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runAssertHeader(assertHeaderCmd, []string{`app\.test`, "Cache-Control", "no-store"})
+	})
+	if err == nil || !IsPrintedError(err) {
+		t.Fatalf("err = %v, want a printed failure", err)
+	}
+	want := "PASS  200 GET https://app.test/  cache-control: no-store\n" +
+		"FAIL  200 GET https://app.test/app.js  cache-control: no-cache\n" +
+		"FAIL  200 GET https://app.test/logo.svg  cache-control missing\n" +
+		"2 of 3 responses failed\n"
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+
+	out = captureStream(t, &os.Stdout, func() {
+		err = runAssertHeader(assertHeaderCmd, []string{`app\.test/$`, "cache-control", "^no-"})
+	})
+	if err != nil || out != "PASS  200 GET https://app.test/  cache-control: no-store\n1 response passed\n" {
+		t.Errorf("err = %v, output = %q", err, out)
+	}
+}
+
+func TestRunAssertHeader_Errors(t *testing.T) {
+	exec := networkDiffExecutor(ipc.NetworkEntry{Seq: 1, Method: "GET", URL: "https://app.test/", Status: 200})
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	tests := []struct {
+		args []string
+		code string
+		want string
+	}{
+		{[]string{"(", "X", "y"}, ipc.CodeInvalidArgs, "invalid url pattern"},
+		{[]string{".", "X", "["}, ipc.CodeInvalidArgs, "invalid regex"},
+		{[]string{"other\\.test", "X", "y"}, ipc.CodeNotFound, "no captured responses match"},
+	}
+	for _, tt := range tests {
+		var err error
+		_ = captureStream(t, &os.Stderr, func() {
+			err = runAssertHeader(assertHeaderCmd, tt.args)
+		})
+		if err == nil || ErrorCode(err) != tt.code || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: err = %v, want %s %q", tt.args, err, tt.code, tt.want)
+		}
+	}
+}
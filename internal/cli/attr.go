@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var attrCmd = &cobra.Command{
+	Use:   "attr",
+	Short: "Read and set element attributes",
+	Long: `Reads and sets DOM attributes on elements without writing eval expressions.
+
+Subcommands:
+  get <sel> <name>          Get one attribute from the first matching element
+  set <sel> <name> <value>  Set an attribute on all matching elements
+  list <sel>                List all attributes of all matching elements
+
+Examples:
+  attr get "a.download" href
+  attr set "#email" value "test@example.com"
+  attr set "details" open ""
+  attr list "form input"
+
+Error cases:
+  - "selector '.missing' matched no elements" - nothing matches selector
+  - "attribute not found" - element does not have the attribute (get)
+  - "daemon not running" - start daemon first with: webctl start`,
+}
+
+var attrGetCmd = &cobra.Command{
+	Use:   "get <selector> <name>",
+	Short: "Get an attribute value to stdout",
+	Long: `Gets a single attribute value from the first element matching the selector
+and outputs it to stdout. Boolean attributes that are present print an empty line.
+
+Examples:
+  attr get "a.download" href
+  attr get "#submit" disabled
+  attr get "img.logo" src --json
+
+Output:
+  /files/report.pdf
+
+Common patterns:
+  # Follow a link's target
+  webctl navigate "$(webctl attr get 'a.next' href)"
+
+Error cases:
+  - "attribute not found" - element does not have the attribute
+  - "selector '.missing' matched no elements" - nothing matches selector`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAttrGet,
+}
+
+var attrSetCmd = &cobra.Command{
+	Use:   "set <selector> <name> <value>",
+	Short: "Set an attribute on all matching elements",
+	Long: `Sets an attribute on every element matching the selector. Use an empty value
+to add a boolean attribute such as disabled or open.
+
+Setting the value attribute changes the default value of form fields, not what
+the user has typed. Use the type command to enter text.
+
+Examples:
+  attr set "#submit" disabled ""
+  attr set "details" open ""
+  attr set "img" loading eager
+
+Output:
+  OK (text) or {"ok": true, "count": 3} (JSON)
+
+Error cases:
+  - "selector '.missing' matched no elements" - nothing matches selector`,
+	Args: cobra.ExactArgs(3),
+	RunE: runAttrSet,
+}
+
+var attrListCmd = &cobra.Command{
+	Use:   "list <selector>",
+	Short: "List all attributes of matching elements",
+	Long: `Lists every attribute of every element matching the selector. Attribute names
+are sorted. Multiple elements are separated by -- markers.
+
+Examples:
+  attr list "#email"
+  attr list "form input"
+  attr list "a" --json | jq '.elements[].attributes.href'
+
+Output:
+  #email
+  id=email
+  name=email
+  required=
+  type=email
+
+Error cases:
+  - "selector '.missing' matched no elements" - nothing matches selector`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAttrList,
+}
+
+func init() {
+	attrCmd.AddCommand(attrGetCmd, attrSetCmd, attrListCmd)
+	rootCmd.AddCommand(attrCmd)
+}
+
+// executeAttr sends an attr request and returns the daemon response.
+func executeAttr(params ipc.AttrParams) (ipc.Response, error) {
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return ipc.Response{}, err
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return ipc.Response{}, err
+	}
+
+	debugRequest("attr", fmt.Sprintf("action=%s selector=%q name=%q", params.Action, params.Selector, params.Name))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "attr",
+		Params: raw,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	return resp, err
+}
+
+func runAttrGet(cmd *cobra.Command, args []string) error {
+	t := startTimer("attr get")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	resp, err := executeAttr(ipc.AttrParams{
+		Action:   "get",
+		Selector: args[0],
+		Name:     args[1],
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		if isNoElementsError(resp.Error) {
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		if resp.Error == "attribute not found" {
+			return outputCodedNotice(ipc.CodeNotFound, "Attribute not found")
+		}
+		return outputResponseError(resp)
+	}
+
+	var data ipc.AttrData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	// JSON mode: output JSON
+	if JSONOutput {
+		result := map[string]any{
+			"ok":    true,
+			"value": data.Value,
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	// Text mode: just output the value
+	_, err = fmt.Fprintln(os.Stdout, data.Value)
+	return err
+}
+
+func runAttrSet(cmd *cobra.Command, args []string) error {
+	t := startTimer("attr set")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	resp, err := executeAttr(ipc.AttrParams{
+		Action:   "set",
+		Selector: args[0],
+		Name:     args[1],
+		Value:    args[2],
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		if isNoElementsError(resp.Error) {
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputResponseError(resp)
+	}
+
+	var data ipc.AttrData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	// JSON mode: include how many elements were changed
+	if JSONOutput {
+		result := map[string]any{
+			"ok":    true,
+			"count": data.Count,
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	return outputSuccess(nil)
+}
+
+func runAttrList(cmd *cobra.Command, args []string) error {
+	t := startTimer("attr list")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	resp, err := executeAttr(ipc.AttrParams{
+		Action:   "list",
+		Selector: args[0],
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		if isNoElementsError(resp.Error) {
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputResponseError(resp)
+	}
+
+	var data ipc.AttrData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	// JSON mode: elements with their attribute maps
+	if JSONOutput {
+		result := map[string]any{
+			"ok":       true,
+			"elements": data.Elements,
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	// Text mode: name=value lines per element with -- separators
+	return format.Attributes(os.Stdout, data.Elements)
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunAttrGet_Text(t *testing.T) {
+	raw, _ := json.Marshal(ipc.AttrData{Value: "/files/report.pdf"})
+	exec := expectParamsExecutor(t, "attr",
+		ipc.AttrParams{Action: "get", Selector: "a.download", Name: "href"},
+		ipc.Response{OK: true, Data: raw})
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runAttrGet(attrGetCmd, []string{"a.download", "href"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "/files/report.pdf\n" {
+		t.Errorf("output = %q, want %q", out, "/files/report.pdf\n")
+	}
+}
+
+func TestRunAttrGet_NotFound(t *testing.T) {
+	exec := expectParamsExecutor(t, "attr",
+		ipc.AttrParams{Action: "get", Selector: "#submit", Name: "disabled"},
+		ipc.Response{OK: false, Error: "attribute not found"})
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runAttrGet(attrGetCmd, []string{"#submit", "disabled"})
+	})
+	if err == nil {
+		t.Error("expected error for missing attribute")
+	}
+	if !strings.Contains(out, "Attribute not found") {
+		t.Errorf("expected notice, got %q", out)
+	}
+}
+
+func TestRunAttrSet_JSON(t *testing.T) {
+	enableJSONOutput(t)
+	raw, _ := json.Marshal(ipc.AttrData{Count: 3})
+	exec := expectParamsExecutor(t, "attr",
+		ipc.AttrParams{Action: "set", Selector: "img", Name: "loading", Value: "eager"},
+		ipc.Response{OK: true, Data: raw})
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runAttrSet(attrSetCmd, []string{"img", "loading", "eager"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if result["ok"] != true || result["count"] != float64(3) {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestRunAttrList_ElementNotFound(t *testing.T) {
+	exec := expectParamsExecutor(t, "attr",
+		ipc.AttrParams{Action: "list", Selector: ".missing"},
+		ipc.Response{OK: false, Error: "selector '.missing' matched no elements"})
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runAttrList(attrListCmd, []string{".missing"})
+	})
+	if err == nil {
+		t.Error("expected error when no elements match")
+	}
+	if !strings.Contains(out, "No elements found") {
+		t.Errorf("expected notice, got %q", out)
+	}
+}
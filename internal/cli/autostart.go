@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// AutoStart launches a headless daemon when a command needs one and none is
+// running. Also enabled by setting WEBCTL_AUTO_START to a true value.
+var AutoStart bool
+
+// autoStartEnv is the environment variable that enables auto-start for every
+// command, so scripts can opt in once instead of passing --auto-start each time.
+const autoStartEnv = "WEBCTL_AUTO_START"
+
+// autoStartTimeout bounds how long a command waits for an auto-started daemon
+// to begin serving IPC. Browser launch dominates this, so it is generous.
+const autoStartTimeout = 30 * time.Second
+
+// autoStartPollInterval is how often the socket is probed while waiting.
+const autoStartPollInterval = 100 * time.Millisecond
+
+// noAutoStartAnnotation marks commands that must observe the real daemon
+// state rather than start one: start itself, status, and serve (which starts
+// its own daemon when none is running).
+const noAutoStartAnnotation = "webctl:no-auto-start"
+
+// autoStartSuppressed is set per invocation from the executing command's
+// annotations.
+var autoStartSuppressed bool
+
+// spawnDaemon launches the background daemon process. Replaceable for testing.
+var spawnDaemon = spawnDetachedDaemon
+
+// daemonRunning reports whether the daemon socket is live. Replaceable for testing.
+var daemonRunning = ipc.IsDaemonRunning
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&AutoStart, "auto-start", false, "Start a headless daemon if none is running (or set "+autoStartEnv+"=1)")
+}
+
+// autoStartEnabled reports whether the flag or environment opts in to
+// auto-starting the daemon for this command.
+func autoStartEnabled() bool {
+	if autoStartSuppressed {
+		return false
+	}
+	if AutoStart {
+		return true
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv(autoStartEnv))
+	return enabled
+}
+
+// ensureDaemon reports whether the daemon is running, starting a headless one
+// first when auto-start is enabled. A failed auto-start is reported on stderr
+// so the caller's "daemon not running" error is not the only clue.
+func ensureDaemon() bool {
+	if daemonRunning() {
+		return true
+	}
+	if !autoStartEnabled() {
+		return false
+	}
+
+	timeout := autoStartTimeout
+	if ciMode {
+		timeout = ciAutoStartTimeout
+	}
+	start := time.Now()
+	err := autoStartDaemon(timeout)
+	debugTiming("auto-start", time.Since(start))
+	if err != nil {
+		if !JSONOutput {
+			fmt.Fprintf(os.Stderr, "Warning: auto-start failed: %v\n", err)
+		}
+		debugf("AUTOSTART", "failed: %v", err)
+		return false
+	}
+	return true
+}
+
+// autoStartDaemon launches a headless daemon and waits until it serves IPC.
+// It fails early if the daemon process exits before becoming ready.
+func autoStartDaemon(timeout time.Duration) error {
+	debugf("AUTOSTART", "daemon not running, launching headless daemon")
+
+	exited, err := spawnDaemon()
+	if err != nil {
+		return fmt.Errorf("failed to launch daemon: %w", err)
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(autoStartPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if daemonRunning() {
+			return nil
+		}
+		select {
+		case err := <-exited:
+			// The daemon may have lost a race with another auto-start; that
+			// one's daemon is as good as ours.
+			if daemonRunning() {
+				return nil
+			}
+			if err == nil {
+				err = errors.New("exited before becoming ready")
+			}
+			return fmt.Errorf("daemon exited: %v (run 'webctl start --headless' to see why)", err)
+		case <-deadline.C:
+			return fmt.Errorf("daemon not ready after %v", timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// spawnDetachedDaemon runs "webctl start --headless" in its own session so it
+// outlives this command and is not interrupted by Ctrl-C in the caller's
+// terminal. Stdin is not a TTY, so the daemon runs without a REPL. The
+// returned channel receives the process exit status if it stops.
+func spawnDetachedDaemon() (<-chan error, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(self, "start", "--headless")
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	return exited, nil
+}
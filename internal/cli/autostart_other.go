@@ -0,0 +1,11 @@
+//go:build !unix
+
+package cli
+
+import "syscall"
+
+// detachedProcAttr has no session to detach from on this platform; the
+// daemon starts with default attributes.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return nil
+}
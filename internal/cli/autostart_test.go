@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubAutoStart replaces the daemon probe and spawner for the duration of the
+// test. running is consulted on every probe; spawned counts launches.
+func stubAutoStart(t *testing.T, running func() bool, spawn func() (<-chan error, error)) {
+	oldRunning, oldSpawn := daemonRunning, spawnDaemon
+	oldAuto, oldSuppressed := AutoStart, autoStartSuppressed
+	daemonRunning = running
+	spawnDaemon = spawn
+	t.Cleanup(func() {
+		daemonRunning, spawnDaemon = oldRunning, oldSpawn
+		AutoStart, autoStartSuppressed = oldAuto, oldSuppressed
+	})
+}
+
+func TestAutoStartEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		flag       bool
+		env        string
+		suppressed bool
+		want       bool
+	}{
+		{"default off", false, "", false, false},
+		{"flag", true, "", false, true},
+		{"env true", false, "1", false, true},
+		{"env false", false, "0", false, false},
+		{"env invalid", false, "yes please", false, false},
+		{"suppressed flag", true, "", true, false},
+		{"suppressed env", false, "true", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubAutoStart(t, nil, nil)
+			t.Setenv(autoStartEnv, tt.env)
+			AutoStart = tt.flag
+			autoStartSuppressed = tt.suppressed
+			if got := autoStartEnabled(); got != tt.want {
+				t.Errorf("autoStartEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureDaemon_AlreadyRunning(t *testing.T) {
+	spawned := 0
+	stubAutoStart(t, func() bool { return true }, func() (<-chan error, error) {
+		spawned++
+		return nil, nil
+	})
+	AutoStart = true
+
+	if !ensureDaemon() {
+		t.Fatal("ensureDaemon() = false, want true")
+	}
+	if spawned != 0 {
+		t.Errorf("spawned %d daemons, want 0", spawned)
+	}
+}
+
+func TestEnsureDaemon_Disabled(t *testing.T) {
+	spawned := 0
+	stubAutoStart(t, func() bool { return false }, func() (<-chan error, error) {
+		spawned++
+		return nil, nil
+	})
+	t.Setenv(autoStartEnv, "")
+	AutoStart = false
+
+	if ensureDaemon() {
+		t.Fatal("ensureDaemon() = true, want false")
+	}
+	if spawned != 0 {
+		t.Errorf("spawned %d daemons, want 0", spawned)
+	}
+}
+
+func TestEnsureDaemon_StartsAndWaits(t *testing.T) {
+	probes := 0
+	spawned := 0
+	stubAutoStart(t,
+		func() bool {
+			probes++
+			// Not running before the spawn and for the first poll after it
+			return spawned > 0 && probes > 3
+		},
+		func() (<-chan error, error) {
+			spawned++
+			return make(chan error), nil
+		})
+	AutoStart = true
+
+	if !ensureDaemon() {
+		t.Fatal("ensureDaemon() = false, want true")
+	}
+	if spawned != 1 {
+		t.Errorf("spawned %d daemons, want 1", spawned)
+	}
+}
+
+func TestAutoStartDaemon_ProcessExits(t *testing.T) {
+	stubAutoStart(t, func() bool { return false }, func() (<-chan error, error) {
+		exited := make(chan error, 1)
+		exited <- errors.New("exit status 1")
+		return exited, nil
+	})
+
+	err := autoStartDaemon(time.Second)
+	if err == nil || !strings.Contains(err.Error(), "daemon exited: exit status 1") {
+		t.Errorf("autoStartDaemon() error = %v, want daemon exited", err)
+	}
+}
+
+func TestAutoStartDaemon_SpawnFails(t *testing.T) {
+	stubAutoStart(t, func() bool { return false }, func() (<-chan error, error) {
+		return nil, errors.New("no such file")
+	})
+
+	err := autoStartDaemon(time.Second)
+	if err == nil || !strings.Contains(err.Error(), "failed to launch daemon") {
+		t.Errorf("autoStartDaemon() error = %v, want launch failure", err)
+	}
+}
+
+func TestAutoStartDaemon_Timeout(t *testing.T) {
+	stubAutoStart(t, func() bool { return false }, func() (<-chan error, error) {
+		return make(chan error), nil
+	})
+
+	err := autoStartDaemon(50 * time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "not ready after") {
+		t.Errorf("autoStartDaemon() error = %v, want timeout", err)
+	}
+}
+
+func TestAutoStartSuppressedCommands(t *testing.T) {
+	for _, name := range []string{"start", "status", "serve"} {
+		cmd, _, err := rootCmd.Find([]string{name})
+		if err != nil {
+			t.Fatalf("find %s: %v", name, err)
+		}
+		if _, ok := cmd.Annotations[noAutoStartAnnotation]; !ok {
+			t.Errorf("%s should not auto-start the daemon", name)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build unix
+
+package cli
+
+import "syscall"
+
+// detachedProcAttr starts the daemon in a new session, away from the
+// caller's terminal and its signals.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -29,7 +29,7 @@ func runBack(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Read flags
@@ -68,9 +68,9 @@ func runBack(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoHistoryError(resp.Error) {
-			return outputNotice("No previous page")
+			return outputCodedNotice(ipc.CodeNotFound, "No previous page")
 		}
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: include URL and title
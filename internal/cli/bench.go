@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/executor"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark page loads",
+	Long: `Benchmarks page loads in the active tab.
+
+Subcommands:
+  compare <urlA> <urlB>   Load two URLs alternately and compare their metrics
+
+Examples:
+  bench compare https://example.com https://staging.example.com
+  bench compare https://example.com/ "https://example.com/?lazy=1" --runs 10`,
+	Args: cobra.NoArgs,
+}
+
+var benchCompareCmd = &cobra.Command{
+	Use:   "compare <urlA> <urlB>",
+	Short: "Load two URLs alternately and compare their metrics",
+	Long: `Loads two URLs in the active tab --runs times each and prints the median of
+each metric side by side, with B's change from A as a percentage.
+
+Both URLs are measured under the same conditions: the loads alternate (A B,
+then B A, ...) so drift in the machine or network affects both alike, and the
+browser's HTTP cache is cleared before every load unless --warm is set. Each
+load waits for the load event, then --settle, so late paints and layout
+shifts are counted.
+
+Metrics:
+  TTFB            Time to the first byte of the document
+  FCP             First contentful paint
+  LCP             Largest contentful paint
+  DCL             DOMContentLoaded finished
+  Load            Load event
+  CLS             Cumulative layout shift (largest session window)
+  Requests        Document plus every resource it loaded
+  Transfer        Bytes over the network, as the page can see them
+  JS heap         Used JavaScript heap after the load
+
+Times are milliseconds since the navigation started. The tab is left on B.
+A CPU slowdown set with 'throttle cpu' applies to every load and is noted in
+the report.
+
+Flags:
+  --runs <n>        Loads of each URL (default 5)
+  --settle <d>      Wait after the load event before measuring (default 1s)
+  --timeout <d>     Time each load may take (default 30s)
+  --warm            Keep the HTTP cache between loads
+
+Examples:
+  bench compare https://example.com https://staging.example.com
+  bench compare http://localhost:3000/ http://localhost:3001/ --runs 9
+  bench compare https://example.com https://example.com --warm
+  bench compare https://a.test https://b.test --json | jq '.delta.lcp'
+
+Text output:
+  A  https://example.com/
+  B  https://staging.example.com/
+  median of 5 cold loads each
+
+  metric            A          B      delta
+  TTFB          120ms       95ms     -20.8%
+  FCP           310ms      280ms      -9.7%
+  LCP           640ms      710ms     +10.9%
+  CLS           0.052      0.000    -100.0%
+  Requests         42         38      -9.5%
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start
+  - "timeout waiting for page load" - a load took longer than --timeout`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBenchCompare,
+}
+
+func init() {
+	benchCompareCmd.Flags().Int("runs", 5, "Loads of each URL")
+	benchCompareCmd.Flags().Duration("settle", time.Second, "Wait after the load event before measuring")
+	benchCompareCmd.Flags().Duration("timeout", 30*time.Second, "Time each load may take")
+	benchCompareCmd.Flags().Bool("warm", false, "Keep the HTTP cache between loads")
+	benchCmd.AddCommand(benchCompareCmd)
+	rootCmd.AddCommand(benchCmd)
+}
+
+// benchSide is one URL's results in 'bench compare --json'.
+type benchSide struct {
+	URL    string             `json:"url"`
+	Median ipc.BenchMetrics   `json:"median"`
+	Runs   []ipc.BenchMetrics `json:"runs"`
+}
+
+func runBenchCompare(cmd *cobra.Command, args []string) error {
+	t := startTimer("bench compare")
+	defer t.log()
+
+	runs, _ := cmd.Flags().GetInt("runs")
+	settle, _ := cmd.Flags().GetDuration("settle")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	warm, _ := cmd.Flags().GetBool("warm")
+	switch {
+	case runs < 1:
+		return outputCodedError(ipc.CodeInvalidArgs, "--runs must be at least 1")
+	case settle < 0:
+		return outputCodedError(ipc.CodeInvalidArgs, "--settle must not be negative")
+	case timeout < time.Second:
+		return outputCodedError(ipc.CodeInvalidArgs, "--timeout must be at least 1s")
+	}
+
+	urls := [2]string{normalizeURL(args[0]), normalizeURL(args[1])}
+	debugParam("a=%q b=%q runs=%d settle=%v timeout=%v warm=%v", urls[0], urls[1], runs, settle, timeout, warm)
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	sides := [2]benchSide{{URL: urls[0]}, {URL: urls[1]}}
+	var cpu float64
+	for i := 0; i < runs; i++ {
+		// Alternate which URL loads first, so neither always follows the other
+		order := [2]int{0, 1}
+		if i%2 == 1 {
+			order = [2]int{1, 0}
+		}
+		for _, side := range order {
+			data, resp, err := benchRequest(exec, ipc.BenchParams{
+				URL:       urls[side],
+				KeepCache: warm,
+				Settle:    int(settle.Milliseconds()),
+				Timeout:   int(math.Ceil(timeout.Seconds())),
+			})
+			if err != nil {
+				return outputError(err.Error())
+			}
+			if !resp.OK {
+				return outputResponseError(resp)
+			}
+			sides[side].Runs = append(sides[side].Runs, data.Metrics)
+			cpu = data.CPUThrottle
+		}
+	}
+	for i := range sides {
+		sides[i].Median = benchMedian(sides[i].Runs)
+	}
+
+	if JSONOutput {
+		result := map[string]any{
+			"ok":    true,
+			"runs":  runs,
+			"warm":  warm,
+			"a":     sides[0],
+			"b":     sides[1],
+			"delta": benchDeltas(sides[0].Median, sides[1].Median),
+		}
+		if cpu > 0 {
+			result["cpuThrottle"] = cpu
+		}
+		return outputJSON(os.Stdout, result)
+	}
+	return format.BenchCompare(os.Stdout, urls[0], urls[1], sides[0].Median, sides[1].Median, runs, warm, cpu)
+}
+
+// benchRequest runs one measured load in the daemon.
+func benchRequest(exec executor.Executor, params ipc.BenchParams) (ipc.BenchData, ipc.Response, error) {
+	var data ipc.BenchData
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, ipc.Response{}, err
+	}
+
+	debugRequest("bench", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "bench", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil || !resp.OK {
+		return data, resp, err
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, resp, fmt.Errorf("failed to parse bench result: %v", err)
+	}
+	return data, resp, nil
+}
+
+// benchMedian returns the median of each metric across runs, taken
+// separately, so one slow run does not skew any of them.
+func benchMedian(runs []ipc.BenchMetrics) ipc.BenchMetrics {
+	pick := func(f func(ipc.BenchMetrics) float64) float64 {
+		vals := make([]float64, len(runs))
+		for i, r := range runs {
+			vals[i] = f(r)
+		}
+		return median(vals)
+	}
+	return ipc.BenchMetrics{
+		TTFB:             pick(func(m ipc.BenchMetrics) float64 { return m.TTFB }),
+		FCP:              pick(func(m ipc.BenchMetrics) float64 { return m.FCP }),
+		LCP:              pick(func(m ipc.BenchMetrics) float64 { return m.LCP }),
+		DOMContentLoaded: pick(func(m ipc.BenchMetrics) float64 { return m.DOMContentLoaded }),
+		Load:             pick(func(m ipc.BenchMetrics) float64 { return m.Load }),
+		CLS:              pick(func(m ipc.BenchMetrics) float64 { return m.CLS }),
+		Requests:         int(math.Round(pick(func(m ipc.BenchMetrics) float64 { return float64(m.Requests) }))),
+		TransferSize:     int64(math.Round(pick(func(m ipc.BenchMetrics) float64 { return float64(m.TransferSize) }))),
+		JSHeapUsed:       int64(math.Round(pick(func(m ipc.BenchMetrics) float64 { return float64(m.JSHeapUsed) }))),
+	}
+}
+
+// median returns the middle value of vals, or the mean of the two middle
+// values for an even count. vals is sorted in place.
+func median(vals []float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	sort.Float64s(vals)
+	mid := len(vals) / 2
+	if len(vals)%2 == 0 {
+		return (vals[mid-1] + vals[mid]) / 2
+	}
+	return vals[mid]
+}
+
+// benchDeltas returns B's change from A for each metric as a percentage,
+// keyed by the metric's JSON name. Metrics that are zero for A have none.
+func benchDeltas(a, b ipc.BenchMetrics) map[string]float64 {
+	pairs := map[string][2]float64{
+		"ttfb":             {a.TTFB, b.TTFB},
+		"fcp":              {a.FCP, b.FCP},
+		"lcp":              {a.LCP, b.LCP},
+		"domContentLoaded": {a.DOMContentLoaded, b.DOMContentLoaded},
+		"load":             {a.Load, b.Load},
+		"cls":              {a.CLS, b.CLS},
+		"requests":         {float64(a.Requests), float64(b.Requests)},
+		"transferSize":     {float64(a.TransferSize), float64(b.TransferSize)},
+		"jsHeapUsed":       {float64(a.JSHeapUsed), float64(b.JSHeapUsed)},
+	}
+	deltas := make(map[string]float64, len(pairs))
+	for name, p := range pairs {
+		if d, ok := format.PercentChange(p[0], p[1]); ok {
+			deltas[name] = d
+		}
+	}
+	return deltas
+}
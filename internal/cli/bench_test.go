@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunBenchCompare(t *testing.T) {
+	resetFlags(t, benchCompareCmd.Flags(), "runs", "settle", "timeout", "warm")
+	if err := benchCompareCmd.Flags().Set("runs", "3"); err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	ttfb := map[string][]float64{"https://a.test": {100, 300, 200}, "https://b.test": {50, 70, 60}}
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		if req.Cmd != "bench" {
+			t.Errorf("expected cmd=bench, got %s", req.Cmd)
+		}
+		var params ipc.BenchParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			t.Fatal(err)
+		}
+		if params.KeepCache || params.Settle != 1000 || params.Timeout != 30 {
+			t.Errorf("params = %+v", params)
+		}
+		order = append(order, params.URL)
+		next := ttfb[params.URL][0]
+		ttfb[params.URL] = ttfb[params.URL][1:]
+		return ipc.SuccessResponse(ipc.BenchData{URL: params.URL, Metrics: ipc.BenchMetrics{TTFB: next, Requests: 10}, CPUThrottle: 4}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runBenchCompare(benchCompareCmd, []string{"a.test", "b.test"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantOrder := "https://a.test https://b.test https://b.test https://a.test https://a.test https://b.test"
+	if got := strings.Join(order, " "); got != wantOrder {
+		t.Errorf("load order = %s, want %s", got, wantOrder)
+	}
+	for _, want := range []string{"median of 3 cold loads each, CPU throttled 4x\n", "TTFB          200ms       60ms     -70.0%", "Requests         10         10      +0.0%"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunBenchCompare_InvalidRuns(t *testing.T) {
+	resetFlags(t, benchCompareCmd.Flags(), "runs")
+	if err := benchCompareCmd.Flags().Set("runs", "0"); err != nil {
+		t.Fatal(err)
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})()
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runBenchCompare(benchCompareCmd, []string{"a.test", "b.test"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "--runs must be at least 1") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		vals []float64
+		want float64
+	}{
+		{nil, 0},
+		{[]float64{3}, 3},
+		{[]float64{9, 1, 5}, 5},
+		{[]float64{4, 1, 3, 2}, 2.5},
+	}
+	for _, tt := range tests {
+		if got := median(tt.vals); got != tt.want {
+			t.Errorf("median(%v) = %v, want %v", tt.vals, got, tt.want)
+		}
+	}
+}
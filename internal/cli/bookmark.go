@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/grantcarthew/webctl/internal/config"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var bookmarkCmd = &cobra.Command{
+	Use:   "bookmark",
+	Short: "Name URLs to open with 'webctl open <name>'",
+	Long: `Saves named URLs, such as local dev pages and staging dashboards, so they are
+one short command away: 'webctl open admin' instead of the full URL. Without
+a subcommand, lists bookmarks.
+
+Bookmarks are kept under bookmarks: in the config file, the user file by
+default or the project .webctl.yaml with --project, so a project can share
+its own. A project bookmark overrides a user one of the same name.
+
+Subcommands:
+  add <name> [url]    Save a URL, or the active tab's URL, under a name
+  list                List bookmarks
+  remove <name>       Remove a bookmark
+
+Examples:
+  bookmark add admin localhost:3000/admin
+  bookmark add dash                     # the page in the active tab
+  bookmark add --project api localhost:8080/docs
+  bookmark list
+  open admin
+
+Response:
+  admin  http://localhost:3000/admin
+  dash   https://staging.example.com/dashboard`,
+	Args: cobra.NoArgs,
+	RunE: runBookmarkList,
+}
+
+var bookmarkAddCmd = &cobra.Command{
+	Use:   "add <name> [url]",
+	Short: "Save a URL under a name",
+	Long: `Saves a URL under a name, replacing any bookmark of that name. Without a URL,
+saves the active tab's URL. URLs without a scheme get one as with navigate:
+http:// for localhost, https:// otherwise.
+
+Names are single words, such as admin or staging-dash.
+
+Flags:
+  --project    Save in the project .webctl.yaml instead of the user config
+
+Examples:
+  bookmark add admin localhost:3000/admin
+  bookmark add dash
+
+Error cases:
+  - "invalid bookmark name ..." - the name has dots, slashes, colons, or spaces
+  - "daemon not running" - no URL was given and there is no tab to take it from`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runBookmarkAdd,
+}
+
+var bookmarkListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List bookmarks",
+	Args:  cobra.NoArgs,
+	RunE:  runBookmarkList,
+}
+
+var bookmarkRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a bookmark",
+	Long: `Removes a bookmark from the user config, or with --project from the project
+.webctl.yaml.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBookmarkRemove,
+}
+
+var openCmd = &cobra.Command{
+	Use:   "open <name>",
+	Short: "Navigate to a bookmark",
+	Long: `Navigates the active tab to the URL saved under a name with 'bookmark add'.
+It takes navigate's flags.
+
+Flags:
+  --wait              Wait for page load completion
+  --until <strategy>  What "loaded" means for --wait (implies --wait); see navigate
+  --timeout <seconds> Timeout in seconds when using --wait (default 60)
+
+Examples:
+  open admin
+  open dash --wait && screenshot
+
+Response:
+  OK
+
+Error cases:
+  - "no bookmark named ..." - add it first with: webctl bookmark add <name> <url>
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOpen,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{bookmarkCmd, bookmarkAddCmd, bookmarkListCmd, bookmarkRemoveCmd} {
+		c.Annotations = map[string]string{noAutoStartAnnotation: ""}
+	}
+	bookmarkAddCmd.Flags().Bool("project", false, "Save in the project .webctl.yaml instead of the user config")
+	bookmarkRemoveCmd.Flags().Bool("project", false, "Edit the project .webctl.yaml instead of the user config")
+	bookmarkCmd.AddCommand(bookmarkAddCmd, bookmarkListCmd, bookmarkRemoveCmd)
+	rootCmd.AddCommand(bookmarkCmd)
+
+	openCmd.Flags().Bool("wait", false, "Wait for page load completion")
+	openCmd.Flags().String("until", "", "Wait strategy: load, domcontentloaded, networkidle, selector:<css>, js:<expr> (implies --wait)")
+	openCmd.Flags().Int("timeout", 60, "Timeout in seconds (used with --wait)")
+	rootCmd.AddCommand(openCmd)
+}
+
+func runBookmarkAdd(cmd *cobra.Command, args []string) error {
+	t := startTimer("bookmark add")
+	defer t.log()
+
+	name := args[0]
+	if err := config.ValidateBookmarkName(name); err != nil {
+		return outputCodedError(ipc.CodeInvalidArgs, err.Error())
+	}
+	var url string
+	if len(args) == 2 {
+		url = normalizeURL(args[1])
+	} else {
+		active, err := activeTabURL()
+		if err != nil {
+			return err
+		}
+		url = active
+	}
+	debugParam("name=%q url=%q", name, url)
+
+	return editConfig(cmd, func(c *config.Config) error {
+		return c.Set("bookmarks."+name, url)
+	})
+}
+
+func runBookmarkRemove(cmd *cobra.Command, args []string) error {
+	t := startTimer("bookmark remove")
+	defer t.log()
+
+	name := args[0]
+	return editConfig(cmd, func(c *config.Config) error {
+		if _, ok := c.Bookmarks[name]; !ok {
+			return fmt.Errorf("no bookmark named %q in this file", name)
+		}
+		return c.Unset("bookmarks." + name)
+	})
+}
+
+func runBookmarkList(cmd *cobra.Command, args []string) error {
+	t := startTimer("bookmark list")
+	defer t.log()
+
+	c, err := loadConfig()
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		bookmarks := c.Bookmarks
+		if bookmarks == nil {
+			bookmarks = map[string]string{}
+		}
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":        true,
+			"bookmarks": bookmarks,
+		})
+	}
+
+	names := c.BookmarkNames()
+	if len(names) == 0 {
+		_, err := fmt.Fprintln(os.Stdout, "No bookmarks")
+		return err
+	}
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for _, name := range names {
+		if _, err := fmt.Fprintf(os.Stdout, "%-*s  %s\n", width, name, c.Bookmarks[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	c, err := loadConfig()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	url, ok := c.Bookmarks[args[0]]
+	if !ok {
+		return outputCodedError(ipc.CodeNotFound, fmt.Sprintf("no bookmark named %q; add it with: webctl bookmark add %s <url>", args[0], args[0]))
+	}
+	debugParam("bookmark=%q url=%q", args[0], url)
+	return runNavigate(cmd, []string{url})
+}
+
+// activeTabURL returns the URL of the page in the active tab.
+func activeTabURL() (string, error) {
+	if !execFactory.IsDaemonRunning() {
+		return "", outputCodedError(ipc.CodeDaemonDown, "daemon not running; give the URL, or start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return "", outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "status"})
+	if err != nil {
+		return "", outputError(err.Error())
+	}
+	if !resp.OK {
+		return "", outputResponseError(resp)
+	}
+
+	var status ipc.StatusData
+	if err := json.Unmarshal(resp.Data, &status); err != nil {
+		return "", outputError(err.Error())
+	}
+	if status.ActiveSession == nil || status.ActiveSession.URL == "" {
+		return "", outputCodedError(ipc.CodeNoSession, "no active tab; give the URL")
+	}
+	return status.ActiveSession.URL, nil
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/config"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunBookmark_AddListRemove(t *testing.T) {
+	userPath, _ := useConfigDirs(t)
+	resetFlags(t, bookmarkAddCmd.Flags(), "project")
+	resetFlags(t, bookmarkRemoveCmd.Flags(), "project")
+
+	var err error
+	_ = captureStream(t, &os.Stdout, func() {
+		err = runBookmarkAdd(bookmarkAddCmd, []string{"admin", "localhost:3000/admin"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a URL, the active tab's is saved
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		if req.Cmd != "status" {
+			t.Errorf("expected cmd=status, got %s", req.Cmd)
+		}
+		return ipc.SuccessResponse(ipc.StatusData{Running: true, ActiveSession: &ipc.PageSession{URL: "https://staging.example.com/dash"}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+	_ = captureStream(t, &os.Stdout, func() {
+		err = runBookmarkAdd(bookmarkAddCmd, []string{"dash"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := config.LoadFile(userPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"admin": "http://localhost:3000/admin", "dash": "https://staging.example.com/dash"}
+	for name, url := range want {
+		if c.Bookmarks[name] != url {
+			t.Errorf("bookmark %s = %q, want %q", name, c.Bookmarks[name], url)
+		}
+	}
+
+	out := captureStream(t, &os.Stdout, func() {
+		err = runBookmarkList(bookmarkListCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "admin  http://localhost:3000/admin\ndash   https://staging.example.com/dash\n" {
+		t.Errorf("list output:\n%s", out)
+	}
+
+	_ = captureStream(t, &os.Stdout, func() {
+		err = runBookmarkRemove(bookmarkRemoveCmd, []string{"admin"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runBookmarkRemove(bookmarkRemoveCmd, []string{"admin"})
+	})
+	if err == nil || !strings.Contains(err.Error(), `no bookmark named "admin"`) {
+		t.Errorf("removing a missing bookmark: err = %v", err)
+	}
+}
+
+func TestRunBookmarkAdd_InvalidName(t *testing.T) {
+	useConfigDirs(t)
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runBookmarkAdd(bookmarkAddCmd, []string{"a.b", "example.com"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid bookmark name") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRunOpen(t *testing.T) {
+	userPath, _ := useConfigDirs(t)
+	writeConfigFile(t, userPath, "bookmarks:\n  admin: http://localhost:3000/admin\n")
+	resetFlags(t, openCmd.Flags(), "wait", "until", "timeout")
+	if err := openCmd.Flags().Set("wait", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		var params ipc.NavigateParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			t.Fatal(err)
+		}
+		if req.Cmd != "navigate" || params.URL != "http://localhost:3000/admin" || !params.Wait {
+			t.Errorf("request = %s %+v", req.Cmd, params)
+		}
+		return ipc.SuccessResponse(ipc.NavigateData{URL: params.URL}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	_ = captureStream(t, &os.Stdout, func() {
+		err = runOpen(openCmd, []string{"admin"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runOpen(openCmd, []string{"missing"})
+	})
+	if err == nil || ErrorCode(err) != ipc.CodeNotFound {
+		t.Errorf("unknown bookmark: err = %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var boxCmd = &cobra.Command{
+	Use:   "box <selector>",
+	Short: "Get element position, size, and visibility",
+	Long: `Gets the bounding box, visibility, and viewport position of every element
+matching the CSS selector. Useful for coordinate-based interactions and layout
+assertions.
+
+All values are CSS pixels. x/y/width/height come from getBoundingClientRect and
+are relative to the viewport; pageX/pageY add the window scroll offset to give
+document coordinates.
+
+Fields per element:
+  x, y, width, height   Viewport-relative bounding box
+  pageX, pageY          Document-relative position
+  visible               Rendered with a non-zero box (not display:none,
+                        visibility:hidden, or opacity:0)
+  inViewport            Any part of the box is on screen
+  fullyInViewport       The whole box is on screen
+  scrollTop, scrollLeft The element's own scroll offsets (scroll containers)
+
+The response also carries the window scrollX/scrollY and viewport size.
+
+Examples:
+  box "#submit"
+  box ".card"                           # All matching elements
+  box "#submit" --json | jq '.elements[0].inViewport'
+
+Text output:
+  #submit
+  box: 120,340 200x40
+  page: 120,940
+  visible: yes
+  viewport: full
+
+Error cases:
+  - "selector '.missing' matched no elements" - nothing matches selector
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBox,
+}
+
+func init() {
+	rootCmd.AddCommand(boxCmd)
+}
+
+func runBox(cmd *cobra.Command, args []string) error {
+	t := startTimer("box")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	selector := args[0]
+	debugParam("selector=%q", selector)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.BoxParams{
+		Selector: selector,
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("box", fmt.Sprintf("selector=%q", selector))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "box",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		if isNoElementsError(resp.Error) {
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputResponseError(resp)
+	}
+
+	var data ipc.BoxData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	// JSON mode: elements plus window scroll and viewport size
+	if JSONOutput {
+		result := map[string]any{
+			"ok":             true,
+			"elements":       data.Elements,
+			"scrollX":        data.ScrollX,
+			"scrollY":        data.ScrollY,
+			"viewportWidth":  data.ViewportWidth,
+			"viewportHeight": data.ViewportHeight,
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	// Text mode: one block per element with -- separators
+	return format.Boxes(os.Stdout, data.Elements)
+}
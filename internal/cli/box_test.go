@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunBox_JSON(t *testing.T) {
+	enableJSONOutput(t)
+	boxData := ipc.BoxData{
+		Elements: []ipc.ElementBox{{
+			ElementMeta: ipc.ElementMeta{Tag: "button", ID: "submit"},
+			X:           10, Y: 20, Width: 100, Height: 30,
+			Visible: true, InViewport: true, FullyInViewport: true,
+		}},
+		ScrollY:        600,
+		ViewportWidth:  1280,
+		ViewportHeight: 720,
+	}
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "box" {
+				t.Errorf("expected cmd=box, got %s", req.Cmd)
+			}
+			var params ipc.BoxParams
+			_ = json.Unmarshal(req.Params, &params)
+			if params.Selector != "#submit" {
+				t.Errorf("expected selector=#submit, got %q", params.Selector)
+			}
+			raw, _ := json.Marshal(boxData)
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runBox(boxCmd, []string{"#submit"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		OK             bool             `json:"ok"`
+		Elements       []ipc.ElementBox `json:"elements"`
+		ScrollY        float64          `json:"scrollY"`
+		ViewportHeight float64          `json:"viewportHeight"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !result.OK || len(result.Elements) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if !result.Elements[0].FullyInViewport || result.Elements[0].Width != 100 {
+		t.Errorf("unexpected element: %+v", result.Elements[0])
+	}
+	if result.ScrollY != 600 || result.ViewportHeight != 720 {
+		t.Errorf("unexpected window state: %+v", result)
+	}
+}
+
+func TestRunBox_Text(t *testing.T) {
+	boxData := ipc.BoxData{
+		Elements: []ipc.ElementBox{{
+			ElementMeta: ipc.ElementMeta{Tag: "button", ID: "submit"},
+			Width:       100, Height: 30, Visible: true,
+		}},
+	}
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			raw, _ := json.Marshal(boxData)
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runBox(boxCmd, []string{"#submit"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "#submit\nbox: 0,0 100x30\n") {
+		t.Errorf("unexpected text output: %q", out)
+	}
+	if !strings.Contains(out, "viewport: outside") {
+		t.Errorf("expected viewport line, got %q", out)
+	}
+}
+
+func TestRunBox_ElementNotFound(t *testing.T) {
+	enableJSONOutput(t)
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			return ipc.Response{OK: false, Error: "selector '.missing' matched no elements"}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runBox(boxCmd, []string{".missing"})
+	})
+	if err == nil {
+		t.Error("expected error for missing element")
+	}
+	if !strings.Contains(out, "No elements found") {
+		t.Errorf("expected notice, got %q", out)
+	}
+}
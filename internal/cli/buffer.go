@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var bufferCmd = &cobra.Command{
+	Use:   "buffer",
+	Short: "Show or change the console and network buffers",
+	Long: `Shows how full the daemon's console and network buffers are, and changes
+their size or body capture without restarting the daemon.
+
+Without a subcommand, same as 'buffer status'.
+
+Subcommands:
+  status                      Show entries, capacity, and drops per buffer
+  set console|network <size>  Resize a buffer (entries)
+  set bodies on|off           Turn network body capture on or off
+
+A full buffer drops its oldest entry for each new one; the drop count says
+how many were lost since the daemon started or the buffer was cleared. If
+entries are being dropped, grow the buffer. Shrinking keeps the newest
+entries. Sizes last until the daemon stops; 'start --buffer-size' sets both.
+
+With body capture off, network entries are still recorded but response
+bodies, and request bodies too large to arrive with the request, are not
+fetched, which saves memory and CDP traffic on media-heavy pages.
+
+Examples:
+  webctl buffer                     # Fill level of each buffer
+  webctl buffer set console 50000   # Keep more console history
+  webctl buffer set bodies off      # Stop fetching network bodies`,
+	Args: cobra.NoArgs,
+	RunE: runBufferStatus,
+}
+
+var bufferStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show buffer fill levels and drop counts",
+	Args:  cobra.NoArgs,
+	RunE:  runBufferStatus,
+}
+
+var bufferSetCmd = &cobra.Command{
+	Use:   "set <console|network|bodies> <value>",
+	Short: "Resize a buffer or toggle body capture",
+	Long: `Resizes the console or network buffer to <value> entries, or turns network
+body capture on or off with 'set bodies on|off'.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBufferSet,
+}
+
+func init() {
+	bufferCmd.AddCommand(bufferStatusCmd, bufferSetCmd)
+	rootCmd.AddCommand(bufferCmd)
+}
+
+func runBufferStatus(cmd *cobra.Command, args []string) error {
+	t := startTimer("buffer status")
+	defer t.log()
+
+	return executeBuffer(ipc.BufferParams{Action: "status"})
+}
+
+func runBufferSet(cmd *cobra.Command, args []string) error {
+	t := startTimer("buffer set")
+	defer t.log()
+
+	params := ipc.BufferParams{Action: "set"}
+	switch args[0] {
+	case "console", "network":
+		size, err := strconv.Atoi(args[1])
+		if err != nil || size <= 0 {
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid buffer size %q (must be a positive number of entries)", args[1]))
+		}
+		params.Buffer, params.Size = args[0], size
+	case "bodies":
+		var capture bool
+		switch args[1] {
+		case "on", "true":
+			capture = true
+		case "off", "false":
+		default:
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid value %q for bodies (use on or off)", args[1]))
+		}
+		params.CaptureBodies = &capture
+	default:
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("unknown buffer setting %q (use console, network, or bodies)", args[0]))
+	}
+	debugParam("buffer=%q size=%d bodies=%s", params.Buffer, params.Size, args[1])
+
+	return executeBuffer(params)
+}
+
+// executeBuffer sends a "buffer" request and writes the buffers it reports.
+func executeBuffer(params ipc.BufferParams) error {
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("buffer", "action="+params.Action)
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "buffer", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.BufferData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":            true,
+			"buffers":       data.Buffers,
+			"captureBodies": data.CaptureBodies,
+		})
+	}
+	return format.Buffers(os.Stdout, data, format.NewOutputOptions(JSONOutput, NoColor))
+}
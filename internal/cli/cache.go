@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var cacheDisableCmd = &cobra.Command{
+	Use:   "cache-disable [on|off]",
+	Short: "Turn the browser's HTTP cache off or back on",
+	Long: `Turns the HTTP cache off in every tab, so every request goes to the server as
+with DevTools' "Disable cache", or back on. Tabs opened later follow the
+setting, which lasts until changed or the daemon stops. Without an argument,
+prints whether the cache is disabled.
+
+Examples:
+  cache-disable on && reload
+  cache-disable off
+  cache-disable
+
+Response:
+  Cache disabled
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCacheDisable,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "cache-clear",
+	Short: "Clear the browser's HTTP cache, and optionally cookies and storage",
+	Long: `Clears the browser's HTTP cache, so the next load of any page starts cold.
+The cache is shared by every tab.
+
+--cookies and --storage also clear the cookies and the site storage of the
+active page's origin (scheme and host): localStorage, sessionStorage,
+IndexedDB, Cache Storage, and service workers. Other sites keep theirs.
+
+Flags:
+  --cookies    Also clear the active page origin's cookies
+  --storage    Also clear the active page origin's storage
+
+Examples:
+  cache-clear && reload
+  cache-clear --cookies --storage && navigate app.test
+
+Response:
+  Cleared cache, cookies, storage for https://app.test
+
+Error cases:
+  - "the active tab has no web page ..." - --cookies or --storage on a tab
+    showing about:blank or a file
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.NoArgs,
+	RunE: runCacheClear,
+}
+
+func init() {
+	cacheClearCmd.Flags().Bool("cookies", false, "Also clear the active page origin's cookies")
+	cacheClearCmd.Flags().Bool("storage", false, "Also clear the active page origin's storage")
+	rootCmd.AddCommand(cacheDisableCmd, cacheClearCmd)
+}
+
+func runCacheDisable(cmd *cobra.Command, args []string) error {
+	t := startTimer("cache-disable")
+	defer t.log()
+
+	params := ipc.CacheParams{Action: "status"}
+	if len(args) == 1 {
+		switch strings.ToLower(args[0]) {
+		case "on":
+			params = ipc.CacheParams{Action: "disable", Disabled: true}
+		case "off":
+			params = ipc.CacheParams{Action: "disable"}
+		default:
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid argument %q (use on or off)", args[0]))
+		}
+	}
+	debugParam("action=%s disabled=%t", params.Action, params.Disabled)
+
+	data, err := cacheRequest(params)
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":       true,
+			"disabled": data.Disabled,
+		})
+	}
+	state := "enabled"
+	if data.Disabled {
+		state = "disabled"
+	}
+	_, err = fmt.Fprintf(os.Stdout, "Cache %s\n", state)
+	return err
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	t := startTimer("cache-clear")
+	defer t.log()
+
+	cookies, _ := cmd.Flags().GetBool("cookies")
+	storage, _ := cmd.Flags().GetBool("storage")
+	debugParam("cookies=%t storage=%t", cookies, storage)
+
+	data, err := cacheRequest(ipc.CacheParams{Action: "clear", Cookies: cookies, Storage: storage})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		result := map[string]any{
+			"ok":      true,
+			"cleared": data.Cleared,
+		}
+		if data.Origin != "" {
+			result["origin"] = data.Origin
+		}
+		return outputJSON(os.Stdout, result)
+	}
+	line := "Cleared " + strings.Join(data.Cleared, ", ")
+	if data.Origin != "" {
+		line += " for " + data.Origin
+	}
+	_, err = fmt.Fprintln(os.Stdout, line)
+	return err
+}
+
+// cacheRequest sends a cache action to the daemon.
+func cacheRequest(params ipc.CacheParams) (ipc.CacheData, error) {
+	var data ipc.CacheData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("cache", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "cache", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
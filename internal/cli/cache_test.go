@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunCacheDisable(t *testing.T) {
+	var got ipc.CacheParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		got = ipc.CacheParams{}
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.CacheData{Disabled: got.Disabled}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	tests := []struct {
+		args   []string
+		action string
+		want   string
+	}{
+		{[]string{"on"}, "disable", "Cache disabled\n"},
+		{[]string{"OFF"}, "disable", "Cache enabled\n"},
+		{nil, "status", "Cache enabled\n"},
+	}
+	for _, tt := range tests {
+		var err error
+		out := captureStream(t, &os.Stdout, func() {
+			err = runCacheDisable(cacheDisableCmd, tt.args)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Action != tt.action || out != tt.want {
+			t.Errorf("%v: action = %s, output = %q", tt.args, got.Action, out)
+		}
+	}
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runCacheDisable(cacheDisableCmd, []string{"maybe"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "use on or off") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRunCacheClear(t *testing.T) {
+	resetFlags(t, cacheClearCmd.Flags(), "cookies", "storage")
+	var got ipc.CacheParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.CacheData{Cleared: []string{"cache", "cookies"}, Origin: "https://app.test"}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	_ = cacheClearCmd.Flags().Set("cookies", "true")
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCacheClear(cacheClearCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != "clear" || !got.Cookies || got.Storage {
+		t.Errorf("params = %+v", got)
+	}
+	if out != "Cleared cache, cookies for https://app.test\n" {
+		t.Errorf("output = %q", out)
+	}
+}
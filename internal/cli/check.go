@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check <selector>",
+	Short: "Check a checkbox or radio button",
+	Long: `Checks a checkbox or radio button matching the CSS selector.
+
+Unlike click, check is idempotent: an element that is already checked is
+left alone, so a script can state the form it wants without knowing the
+current state. Otherwise the element is clicked, which fires the click,
+input, and change events the page listens for, and the new state is
+verified. A selector matching a <label> acts on the label's control.
+
+Examples:
+  check "#agree"                        # Accept the terms
+  check "input[name=plan][value=pro]"   # Pick a radio option
+  check "label[for=newsletter]"         # Via the label
+
+Output:
+  OK (text) or {"ok": true, "checked": true, "changed": false} (JSON)
+
+Error cases:
+  - "element not found" - selector doesn't match any element
+  - "element is not a checkbox or radio button" - wrong element type
+  - "element is disabled" - the element cannot be changed
+  - "did not change" - a click handler prevented the change`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCheck,
+}
+
+var uncheckCmd = &cobra.Command{
+	Use:   "uncheck <selector>",
+	Short: "Uncheck a checkbox",
+	Long: `Unchecks a checkbox matching the CSS selector, leaving it alone if it is
+already unchecked. See 'check' for how the state is changed.
+
+A checked radio button cannot be unchecked on its own; check another option
+in its group instead.
+
+Examples:
+  uncheck "#newsletter"
+  uncheck "input[type=checkbox]#remember-me"
+
+Output:
+  OK (text) or {"ok": true, "checked": false, "changed": true} (JSON)
+
+Error cases:
+  - "element not found" - selector doesn't match any element
+  - "element is not a checkbox or radio button" - wrong element type
+  - "cannot uncheck radio button" - the radio button is checked`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd, uncheckCmd)
+}
+
+// runCheck serves check and uncheck, telling them apart by command name.
+func runCheck(cmd *cobra.Command, args []string) error {
+	t := startTimer(cmd.Name())
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	selector := args[0]
+	checked := cmd.Name() != "uncheck"
+	debugParam("selector=%q checked=%t", selector, checked)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.CheckParams{
+		Selector: selector,
+		Checked:  checked,
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("check", fmt.Sprintf("selector=%q checked=%t", selector, checked))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "check",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		if isNoElementsError(resp.Error) {
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputResponseError(resp)
+	}
+
+	var data ipc.CheckData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":      true,
+			"checked": data.Checked,
+			"changed": data.Changed,
+		})
+	}
+
+	return outputSuccess(nil)
+}
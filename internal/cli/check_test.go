@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunCheck(t *testing.T) {
+	enableJSONOutput(t)
+	var got ipc.CheckParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		if req.Cmd != "check" {
+			t.Errorf("expected cmd=check, got %s", req.Cmd)
+		}
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.CheckData{Checked: got.Checked, Changed: false}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCheck(checkCmd, []string{"#agree"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Selector != "#agree" || !got.Checked {
+		t.Errorf("params = %+v", got)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result["checked"] != true || result["changed"] != false {
+		t.Errorf("result = %v", result)
+	}
+}
+
+func TestRunUncheck(t *testing.T) {
+	var got ipc.CheckParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.CheckData{Changed: true}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCheck(uncheckCmd, []string{"#newsletter"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Selector != "#newsletter" || got.Checked {
+		t.Errorf("params = %+v", got)
+	}
+	if out != "OK\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunCheck_WrongType(t *testing.T) {
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.ErrorResponse("element is not a checkbox or radio button: #email is email"), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runCheck(checkCmd, []string{"#email"})
+	})
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// CI enables CI mode: container-safe Chrome flags, no color, a headless
+// browser on a throwaway profile, and shorter startup timeouts. Also enabled
+// by WEBCTL_CI, or by the CI variable that CI services set.
+var CI bool
+
+// ciEnv is the environment variable that turns CI mode on or off. It also
+// carries --ci to auto-started daemons.
+const ciEnv = "WEBCTL_CI"
+
+// ciAutoStartTimeout replaces autoStartTimeout in CI mode. It outlasts the
+// browser's own CI start timeout so that failure is the one reported.
+const ciAutoStartTimeout = 20 * time.Second
+
+// ciMode is resolved per invocation by applyCI.
+var ciMode bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&CI, "ci", false, "CI mode: container-safe browser, no color, faster failures (or set "+ciEnv+"=1; on when CI=true)")
+}
+
+// ciEnabled reports whether CI mode is on. An explicit --ci or --ci=false
+// wins, then WEBCTL_CI, then CI, which GitHub Actions, GitLab CI, CircleCI,
+// and most other CI services set to true.
+func ciEnabled(flagSet bool) bool {
+	if flagSet {
+		return CI
+	}
+	for _, env := range []string{ciEnv, "CI"} {
+		if v := os.Getenv(env); v != "" {
+			enabled, _ := strconv.ParseBool(v)
+			return enabled
+		}
+	}
+	return false
+}
+
+// applyCI resolves CI mode for cmd and applies its defaults. It runs before
+// applyConfig, so its defaults for start take precedence over the config
+// files, while flags given on the command line still win.
+func applyCI(cmd *cobra.Command) error {
+	ciMode = ciEnabled(cmd.Flags().Changed("ci"))
+	if !ciMode {
+		return nil
+	}
+	debugf("CI", "CI mode")
+
+	NoColor = true
+	// Auto-started daemons are separate processes; pass the mode on.
+	_ = setCommandEnv(ciEnv, "1")
+	// GitHub Actions provides a per-job temp directory that is cleaned up
+	// after the job; keep the browser's temp files and profile there.
+	if os.Getenv("TMPDIR") == "" {
+		if dir := os.Getenv("RUNNER_TEMP"); dir != "" {
+			_ = setCommandEnv("TMPDIR", dir)
+		}
+	}
+
+	if cmd == startCmd {
+		// No display in a CI job, and nothing worth keeping in a profile
+		flags := cmd.Flags()
+		if !flags.Changed("headless") {
+			if err := flags.Set("headless", "true"); err != nil {
+				return err
+			}
+		}
+		if !flags.Changed("temp-profile") && !flags.Changed("user-data-dir") && !flags.Changed("system-profile") {
+			if err := flags.Set("temp-profile", "true"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCIEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    bool
+		flagSet bool
+		webctl  string
+		ci      string
+		want    bool
+	}{
+		{"default off", false, false, "", "", false},
+		{"flag", true, true, "", "", true},
+		{"flag false beats CI", false, true, "", "true", false},
+		{"CI true", false, false, "", "true", true},
+		{"CI false", false, false, "", "false", false},
+		{"CI invalid", false, false, "", "yes please", false},
+		{"WEBCTL_CI on", false, false, "1", "", true},
+		{"WEBCTL_CI off beats CI", false, false, "0", "true", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := CI
+			t.Cleanup(func() { CI = old })
+			t.Setenv(ciEnv, tt.webctl)
+			t.Setenv("CI", tt.ci)
+			CI = tt.flag
+			if got := ciEnabled(tt.flagSet); got != tt.want {
+				t.Errorf("ciEnabled(%v) = %v, want %v", tt.flagSet, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCI_Start(t *testing.T) {
+	flags := startCmd.Flags()
+	resetFlags(t, flags, "headless", "temp-profile", "user-data-dir")
+	oldNoColor := NoColor
+	t.Cleanup(func() { NoColor, ciMode = oldNoColor, false })
+	t.Setenv(ciEnv, "1")
+	t.Setenv("TMPDIR", "")
+	t.Setenv("RUNNER_TEMP", "/runner/temp")
+
+	if err := applyCI(startCmd); err != nil {
+		t.Fatalf("applyCI() error = %v", err)
+	}
+	if !ciMode || !NoColor {
+		t.Errorf("ciMode = %v, NoColor = %v, want both true", ciMode, NoColor)
+	}
+	if headless, _ := flags.GetBool("headless"); !headless {
+		t.Error("CI mode should default start to --headless")
+	}
+	if temp, _ := flags.GetBool("temp-profile"); !temp {
+		t.Error("CI mode should default start to --temp-profile")
+	}
+	if got := os.Getenv("TMPDIR"); got != "/runner/temp" {
+		t.Errorf("TMPDIR = %q, want RUNNER_TEMP", got)
+	}
+
+	// An explicit profile is kept rather than made to conflict
+	resetFlags(t, flags, "headless", "temp-profile", "user-data-dir")
+	_ = flags.Set("user-data-dir", "/tmp/profile")
+	if err := applyCI(startCmd); err != nil {
+		t.Fatalf("applyCI() error = %v", err)
+	}
+	if temp, _ := flags.GetBool("temp-profile"); temp {
+		t.Error("--user-data-dir should not be overridden by CI mode")
+	}
+}
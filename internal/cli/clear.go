@@ -1,23 +1,49 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/grantcarthew/webctl/internal/ipc"
 	"github.com/spf13/cobra"
 )
 
-// Note: ipc import kept for ipc.Request type
+// clearTargets are the buffers 'clear' accepts.
+var clearTargets = []string{"console", "network", "events", "all"}
 
 var clearCmd = &cobra.Command{
-	Use:   "clear [console|network]",
+	Use:   "clear [console|network|events|all]",
 	Short: "Clear event buffers",
-	Long:  "Clears the console and/or network event buffers. Specify 'console' or 'network' to clear only that buffer, or omit to clear all.",
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runClear,
+	Long: `Clears the daemon's console, network, and event ('webctl events') buffers.
+Name one to clear only that buffer, or omit the target (or use 'all') to clear
+them all.
+
+With --session, only the entries of the tab matching the query (a session ID
+prefix or title substring, as for 'tab switch') are removed, so one tab's
+noise can be purged without losing what another tab captured. Events are
+scoped by the tab they concern; events not about a tab are kept.
+
+Flags:
+  --session <query>   Only clear entries from this tab
+
+Examples:
+  clear                       # Every buffer
+  clear network
+  clear console --session Dashboard
+  clear --session 4F2A        # Everything from one tab
+
+Error cases:
+  - "no tab matches query" - --session matched no tab
+  - "ambiguous query" - --session matched several tabs`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runClear,
 }
 
 func init() {
+	clearCmd.Flags().String("session", "", "Only clear entries from the tab matching this query")
 	rootCmd.AddCommand(clearCmd)
 }
 
@@ -25,29 +51,34 @@ func runClear(cmd *cobra.Command, args []string) error {
 	t := startTimer("clear")
 	defer t.log()
 
+	target := ""
+	if len(args) > 0 {
+		target = args[0]
+		if !slices.Contains(clearTargets, target) {
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid target: must be one of %s", strings.Join(clearTargets, ", ")))
+		}
+	}
+	session, _ := cmd.Flags().GetString("session")
+
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
 		return outputError(err.Error())
 	}
 	defer func() { _ = exec.Close() }()
 
-	target := ""
-	if len(args) > 0 {
-		target = args[0]
-		// Validate target
-		if target != "console" && target != "network" {
-			return outputError("invalid target: must be 'console' or 'network'")
+	req := ipc.Request{Cmd: "clear", Target: target}
+	if session != "" {
+		req.Params, err = json.Marshal(ipc.ClearParams{Session: session})
+		if err != nil {
+			return outputError(err.Error())
 		}
 	}
 
-	debugParam("target=%q", target)
+	debugParam("target=%q session=%q", target, session)
 	debugRequest("clear", target)
 	ipcStart := time.Now()
 
-	resp, err := exec.Execute(ipc.Request{
-		Cmd:    "clear",
-		Target: target,
-	})
+	resp, err := exec.Execute(req)
 
 	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
 
@@ -56,18 +87,25 @@ func runClear(cmd *cobra.Command, args []string) error {
 	}
 
 	if !resp.OK {
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: include message
 	if JSONOutput {
 		msg := "all buffers cleared"
-		if target != "" {
+		if target != "" && target != "all" {
 			msg = target + " buffer cleared"
 		}
-		return outputSuccess(map[string]string{
-			"message": msg,
-		})
+		result := map[string]string{"message": msg}
+		if session != "" {
+			var data ipc.ClearData
+			if err := json.Unmarshal(resp.Data, &data); err != nil {
+				return outputError(err.Error())
+			}
+			result["message"] = msg + " for tab " + data.Session
+			result["session"] = data.Session
+		}
+		return outputSuccess(result)
 	}
 
 	// Text mode: just output OK
@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/grantcarthew/webctl/internal/executor"
 	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
 )
 
 func init() {
@@ -43,6 +46,39 @@ func (m *mockExecutor) Close() error {
 	return nil
 }
 
+// expectExecutor returns a mockExecutor that answers with resp, failing the
+// test if a request is not for cmd.
+func expectExecutor(t *testing.T, cmd string, resp ipc.Response) *mockExecutor {
+	t.Helper()
+	return &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != cmd {
+				t.Errorf("expected cmd=%s, got %s", cmd, req.Cmd)
+			}
+			return resp, nil
+		},
+	}
+}
+
+// expectParamsExecutor is expectExecutor that also fails the test if a
+// request's params do not decode to want.
+func expectParamsExecutor[P comparable](t *testing.T, cmd string, want P, resp ipc.Response) *mockExecutor {
+	t.Helper()
+	return &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != cmd {
+				t.Errorf("expected cmd=%s, got %s", cmd, req.Cmd)
+			}
+			var params P
+			_ = json.Unmarshal(req.Params, &params)
+			if params != want {
+				t.Errorf("params = %+v, want %+v", params, want)
+			}
+			return resp, nil
+		},
+	}
+}
+
 // mockFactory implements ExecutorFactory for testing.
 type mockFactory struct {
 	executor      executor.Executor
@@ -178,6 +214,47 @@ func TestOutputError(t *testing.T) {
 	if result["error"] != "something went wrong" {
 		t.Errorf("expected error='something went wrong', got %v", result["error"])
 	}
+
+	if result["code"] != ipc.CodeFailed {
+		t.Errorf("expected code=%s, got %v", ipc.CodeFailed, result["code"])
+	}
+}
+
+func TestOutputResponseError_KeepsDaemonCode(t *testing.T) {
+	enableJSONOutput(t)
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = outputResponseError(ipc.CodedErrorResponse(ipc.CodeNotFound, "attribute not found"))
+	})
+	if !strings.Contains(out, `"code":"E_NOT_FOUND"`) {
+		t.Errorf("expected daemon code in output, got %s", out)
+	}
+	if code := ErrorCode(err); code != ipc.CodeNotFound || ExitCode(code) != 7 {
+		t.Errorf("got code %q, exit %d", code, ExitCode(code))
+	}
+}
+
+func TestErrorCode_Unprinted(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+		exit int
+	}{
+		{errors.New("accepts 1 arg(s), received 0"), ipc.CodeInvalidArgs, 2},
+		{errors.New("unknown flag: --bogus"), ipc.CodeInvalidArgs, 2},
+		{errors.New(`invalid argument "soon" for "--timeout" flag`), ipc.CodeInvalidArgs, 2},
+		{errors.New("config: invalid output \"xml\""), ipc.CodeInvalidArgs, 2},
+		{errors.New("daemon not running"), ipc.CodeDaemonDown, 3},
+		{printedError{err: errors.New("No matches found")}, "", 1},
+		{printedError{err: errors.New("No elements found"), code: ipc.CodeElementNotFound}, ipc.CodeElementNotFound, 5},
+	}
+	for _, tt := range tests {
+		code := ErrorCode(tt.err)
+		if code != tt.want || ExitCode(code) != tt.exit {
+			t.Errorf("%v: got %q exit %d, want %q exit %d", tt.err, code, ExitCode(code), tt.want, tt.exit)
+		}
+	}
 }
 
 func TestRunStatus_DaemonNotRunning(t *testing.T) {
@@ -195,7 +272,7 @@ func TestRunStatus_DaemonNotRunning(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := runStatus(nil, nil)
+	err := runStatus(newStatusTestCmd(), nil)
 
 	_ = w.Close()
 	os.Stdout = old
@@ -259,7 +336,7 @@ func TestRunStatus_DaemonRunning(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := runStatus(nil, nil)
+	err := runStatus(newStatusTestCmd(), nil)
 
 	_ = w.Close()
 	os.Stdout = old
@@ -297,6 +374,47 @@ func TestRunStatus_DaemonRunning(t *testing.T) {
 	}
 }
 
+func TestRunStatus_Verbose(t *testing.T) {
+	enableJSONOutput(t)
+
+	statusJSON, _ := json.Marshal(ipc.StatusData{
+		Running: true,
+		Detail:  &ipc.StatusDetail{UptimeMs: 5000, Goroutines: 12},
+	})
+	var got ipc.StatusParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if err := json.Unmarshal(req.Params, &got); err != nil {
+				t.Fatalf("failed to parse params: %v", err)
+			}
+			return ipc.Response{OK: true, Data: statusJSON}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	cmd := newStatusTestCmd()
+	_ = cmd.Flags().Set("verbose", "true")
+	out := captureStream(t, &os.Stdout, func() {
+		if err := runStatus(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !got.Verbose {
+		t.Error("expected verbose=true in params")
+	}
+	if !strings.Contains(out, `"goroutines":12`) && !strings.Contains(out, `"goroutines": 12`) {
+		t.Errorf("expected detail in output, got %s", out)
+	}
+}
+
+func newStatusTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "status", RunE: runStatus}
+	cmd.Flags().Bool("verbose", false, "")
+	return cmd
+}
+
 func TestRunStop_Success(t *testing.T) {
 	enableJSONOutput(t)
 
@@ -407,7 +525,7 @@ func TestRunClear_AllBuffers(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := runClear(nil, []string{})
+	err := runClear(newClearTestCmd(), []string{})
 
 	_ = w.Close()
 	os.Stdout = old
@@ -453,7 +571,7 @@ func TestRunClear_ConsoleOnly(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := runClear(nil, []string{"console"})
+	err := runClear(newClearTestCmd(), []string{"console"})
 
 	_ = w.Close()
 	os.Stdout = old
@@ -492,7 +610,7 @@ func TestRunClear_InvalidTarget(t *testing.T) {
 	_, w, _ := os.Pipe()
 	os.Stderr = w
 
-	err := runClear(nil, []string{"invalid"})
+	err := runClear(newClearTestCmd(), []string{"invalid"})
 
 	_ = w.Close()
 	os.Stderr = old
@@ -501,11 +619,61 @@ func TestRunClear_InvalidTarget(t *testing.T) {
 		t.Fatal("expected error for invalid target")
 	}
 
-	if err.Error() != "invalid target: must be 'console' or 'network'" {
+	if err.Error() != "invalid target: must be one of console, network, events, all" {
 		t.Errorf("unexpected error message: %v", err)
 	}
 }
 
+func TestRunClear_Session(t *testing.T) {
+	enableJSONOutput(t)
+
+	var got ipc.ClearParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Target != "network" {
+				t.Errorf("expected target=network, got %q", req.Target)
+			}
+			if err := json.Unmarshal(req.Params, &got); err != nil {
+				t.Fatalf("failed to parse params: %v", err)
+			}
+			raw, _ := json.Marshal(ipc.ClearData{Session: "4F2A90"})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	cmd := newClearTestCmd()
+	_ = cmd.Flags().Set("session", "Dashboard")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runClear(cmd, []string{"network"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Session != "Dashboard" {
+		t.Errorf("session param = %q, want Dashboard", got.Session)
+	}
+
+	var result struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse output %q: %v", out, err)
+	}
+	if result.Data["session"] != "4F2A90" || result.Data["message"] != "network buffer cleared for tab 4F2A90" {
+		t.Errorf("data = %v", result.Data)
+	}
+}
+
+func newClearTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "clear", RunE: runClear}
+	cmd.Flags().String("session", "", "")
+	return cmd
+}
+
 func TestRunStart_DaemonAlreadyRunning(t *testing.T) {
 	enableJSONOutput(t)
 
@@ -792,7 +960,7 @@ func TestFilterConsoleByText(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			filtered := filterConsoleByText(entries, tt.search)
+			filtered := filterConsoleByText(entries, substringMatcher(tt.search))
 			if len(filtered) != tt.expected {
 				t.Errorf("expected %d entries, got %d", tt.expected, len(filtered))
 			}
@@ -1308,6 +1476,36 @@ func TestExecuteArgs_emptyArgs(t *testing.T) {
 	}
 }
 
+func TestExecuteStreamData(t *testing.T) {
+	// Streamed when the daemon streams the command, inline otherwise
+	for _, stream := range []bool{true, false} {
+		exec := executor.NewDirectExecutor(func(req ipc.Request) ipc.Response {
+			if stream && req.Stream {
+				return ipc.StreamResponse(func(w io.Writer) error {
+					_, err := io.WriteString(w, `{"html":"<p>streamed</p>"}`)
+					return err
+				})
+			}
+			return ipc.SuccessResponse(ipc.HTMLData{HTML: "<p>inline</p>"})
+		})
+		resp, err := executeStreamData(exec, ipc.Request{Cmd: "html"})
+		if err != nil || !resp.OK {
+			t.Fatalf("executeStreamData() = %+v, %v", resp, err)
+		}
+		var data ipc.HTMLData
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+		want := "<p>inline</p>"
+		if stream {
+			want = "<p>streamed</p>"
+		}
+		if data.HTML != want {
+			t.Errorf("stream=%v: html = %q, want %q", stream, data.HTML, want)
+		}
+	}
+}
+
 func TestDirectExecutorFactory(t *testing.T) {
 	handlerCalled := false
 	receivedCmd := ""
@@ -1352,6 +1550,33 @@ func TestDirectExecutorFactory(t *testing.T) {
 	}
 }
 
+func TestExecuteArgs_restoresEnv(t *testing.T) {
+	t.Setenv(ipc.SocketEnv, "/tmp/original.sock")
+	t.Setenv(ciEnv, "")
+	_ = os.Unsetenv(ciEnv)
+
+	exec := expectExecutor(t, "status", ipc.SuccessResponse(ipc.StatusData{Running: true}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		_, err = ExecuteArgs([]string{"status", "--socket", "/tmp/other.sock", "--ci"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := os.Getenv(ipc.SocketEnv); got != "/tmp/original.sock" {
+		t.Errorf("%s = %q after ExecuteArgs, want it restored", ipc.SocketEnv, got)
+	}
+	if _, set := os.LookupEnv(ciEnv); set {
+		t.Errorf("%s still set after ExecuteArgs", ciEnv)
+	}
+	if ciMode {
+		t.Error("ciMode still on after ExecuteArgs")
+	}
+}
+
 func TestExecuteArgs_resetsFlagsBetweenCalls(t *testing.T) {
 	// TODO: This test is flaky when run with the full test suite due to global state sharing
 	// in Cobra commands. It passes when run in isolation. Need to refactor to use isolated
@@ -3431,6 +3656,67 @@ func TestRunNavigate_WithWaitFlag(t *testing.T) {
 	}
 }
 
+func TestRunNavigate_UntilImpliesWait(t *testing.T) {
+	enableJSONOutput(t)
+	navJSON, _ := json.Marshal(ipc.NavigateData{URL: "https://example.com"})
+
+	var capturedParams ipc.NavigateParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			_ = json.Unmarshal(req.Params, &capturedParams)
+			return ipc.Response{OK: true, Data: navJSON}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	_ = navigateCmd.Flags().Set("until", "selector:#app")
+	defer func() {
+		_ = navigateCmd.Flags().Set("until", "")
+		navigateCmd.Flags().Lookup("until").Changed = false
+	}()
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		err = runNavigate(navigateCmd, []string{"example.com"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !capturedParams.Wait || capturedParams.Until != "selector:#app" {
+		t.Errorf("expected Wait=true Until=selector:#app, got %+v", capturedParams)
+	}
+}
+
+func TestRunNavigate_InvalidUntil(t *testing.T) {
+	enableJSONOutput(t)
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			t.Error("daemon should not be called with an invalid --until")
+			return ipc.Response{}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	_ = navigateCmd.Flags().Set("until", "idle")
+	defer func() {
+		_ = navigateCmd.Flags().Set("until", "")
+		navigateCmd.Flags().Lookup("until").Changed = false
+	}()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runNavigate(navigateCmd, []string{"example.com"})
+	})
+	if err == nil {
+		t.Error("expected error for invalid --until")
+	}
+	if !strings.Contains(out, "invalid --until") {
+		t.Errorf("unexpected error output: %q", out)
+	}
+}
+
 func TestRunNavigate_LocalhostUsesHTTP(t *testing.T) {
 	enableJSONOutput(t)
 	navData := ipc.NavigateData{URL: "http://localhost:3000", Title: ""}
@@ -3591,6 +3877,32 @@ func TestRunReload_Success(t *testing.T) {
 	}
 }
 
+func TestRunReload_PreserveScroll(t *testing.T) {
+	enableJSONOutput(t)
+	resetFlags(t, reloadCmd.Flags(), "preserve-scroll")
+	if err := reloadCmd.Flags().Set("preserve-scroll", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := ipc.ReloadParams{IgnoreCache: true, Timeout: 60, PreserveScroll: true}
+	exec := expectParamsExecutor(t, "reload", want, ipc.SuccessResponse(ipc.NavigateData{
+		URL:    "https://example.com",
+		Scroll: &ipc.ScrollPosition{Y: 2400},
+	}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runReload(reloadCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"scroll":{"x":0,"y":2400}`) {
+		t.Errorf("output missing the restored scroll position: %s", out)
+	}
+}
+
 func TestRunReload_WithWaitFlag(t *testing.T) {
 	enableJSONOutput(t)
 	navData := ipc.NavigateData{URL: "https://example.com", Title: "Example Domain"}
@@ -4443,6 +4755,83 @@ func TestRunType_WithClearFlag(t *testing.T) {
 	}
 }
 
+func TestRunType_WithDelayFlag(t *testing.T) {
+	enableJSONOutput(t)
+	var capturedParams ipc.TypeParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			_ = json.Unmarshal(req.Params, &capturedParams)
+			return ipc.Response{OK: true}, nil
+		},
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	_ = typeCmd.Flags().Set("delay", "50ms-120ms")
+	defer func() { _ = typeCmd.Flags().Set("delay", "") }()
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		err = runType(typeCmd, []string{"#city", "Melbourne"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capturedParams.DelayMin != 50 || capturedParams.DelayMax != 120 {
+		t.Errorf("delay = %d-%d, want 50-120", capturedParams.DelayMin, capturedParams.DelayMax)
+	}
+}
+
+func TestRunType_WithIMEFlag(t *testing.T) {
+	enableJSONOutput(t)
+	var capturedParams ipc.TypeParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			_ = json.Unmarshal(req.Params, &capturedParams)
+			return ipc.Response{OK: true}, nil
+		},
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	_ = typeCmd.Flags().Set("ime", "true")
+	defer func() { _ = typeCmd.Flags().Set("ime", "false") }()
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		err = runType(typeCmd, []string{"#chat", "こんにちは"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !capturedParams.IME || capturedParams.Text != "こんにちは" {
+		t.Errorf("params = %+v, want IME text", capturedParams)
+	}
+}
+
+func TestParseTypeDelay(t *testing.T) {
+	tests := []struct {
+		in      string
+		lo, hi  time.Duration
+		wantErr bool
+	}{
+		{"", 0, 0, false},
+		{"80ms", 80 * time.Millisecond, 80 * time.Millisecond, false},
+		{"50ms-120ms", 50 * time.Millisecond, 120 * time.Millisecond, false},
+		{"50ms - 1s", 50 * time.Millisecond, time.Second, false},
+		{"120ms-50ms", 0, 0, true},
+		{"fast", 0, 0, true},
+		{"-5ms", 0, 0, true},
+		{"50ms-", 0, 0, true},
+		{"11s", 0, 0, true},
+		{"100us", 0, 0, true},
+	}
+	for _, tt := range tests {
+		lo, hi, err := parseTypeDelay(tt.in)
+		if (err != nil) != tt.wantErr || lo != tt.lo || hi != tt.hi {
+			t.Errorf("parseTypeDelay(%q) = %v, %v, %v", tt.in, lo, hi, err)
+		}
+	}
+}
+
 func TestRunType_AllFlags(t *testing.T) {
 	enableJSONOutput(t)
 	var capturedParams ipc.TypeParams
@@ -4696,6 +5085,77 @@ func TestRunKey_AllModifiers(t *testing.T) {
 	}
 }
 
+func TestRunKey_Sequence(t *testing.T) {
+	enableJSONOutput(t)
+	var capturedParams ipc.KeyParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			_ = json.Unmarshal(req.Params, &capturedParams)
+			return ipc.Response{OK: true}, nil
+		},
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	_ = keyCmd.Flags().Set("repeat", "2")
+	_ = keyCmd.Flags().Set("delay", "100ms")
+	defer func() {
+		_ = keyCmd.Flags().Set("repeat", "1")
+		_ = keyCmd.Flags().Set("delay", "0s")
+	}()
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		err = runKey(keyCmd, []string{"ctrl+k p Enter"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ipc.KeyChord{{Key: "k", Ctrl: true}, {Key: "p"}, {Key: "Enter"}}
+	if capturedParams.Key != "" || len(capturedParams.Sequence) != len(want) {
+		t.Fatalf("params = %+v", capturedParams)
+	}
+	for i, c := range want {
+		if capturedParams.Sequence[i] != c {
+			t.Errorf("sequence[%d] = %+v, want %+v", i, capturedParams.Sequence[i], c)
+		}
+	}
+	if capturedParams.Repeat != 2 || capturedParams.Delay != 100 {
+		t.Errorf("repeat = %d, delay = %d", capturedParams.Repeat, capturedParams.Delay)
+	}
+}
+
+func TestParseKeySequence(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []ipc.KeyChord
+		wantErr bool
+	}{
+		{"g i", []ipc.KeyChord{{Key: "g"}, {Key: "i"}}, false},
+		{"ctrl+shift+p", []ipc.KeyChord{{Key: "p", Ctrl: true, Shift: true}}, false},
+		{"Cmd+Enter  Escape", []ipc.KeyChord{{Key: "Enter", Meta: true}, {Key: "Escape"}}, false},
+		{"option+ArrowLeft", []ipc.KeyChord{{Key: "ArrowLeft", Alt: true}}, false},
+		{"ctrl++", []ipc.KeyChord{{Key: "+", Ctrl: true}}, false},
+		{"hyper+k", nil, true},
+		{"   ", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseKeySequence(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseKeySequence(%q) error = %v", tt.in, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("parseKeySequence(%q) = %+v, want %+v", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("parseKeySequence(%q)[%d] = %+v, want %+v", tt.in, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
 // Select command tests
 
 func TestRunSelect_DaemonNotRunning(t *testing.T) {
@@ -4781,6 +5241,59 @@ func TestRunSelect_Success(t *testing.T) {
 	}
 }
 
+func TestRunSelect_LabelsAndValues(t *testing.T) {
+	var got ipc.SelectParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			_ = json.Unmarshal(req.Params, &got)
+			return ipc.SuccessResponse(ipc.SelectData{Selected: []ipc.SelectedOption{
+				{Index: 0, Value: "t-1f3a", Label: "Cheese"},
+				{Index: 2, Value: "t-44d0", Label: "Basil"},
+			}}), nil
+		},
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("label", nil, "")
+	_ = cmd.Flags().Set("label", "Cheese")
+	_ = cmd.Flags().Set("label", "Basil")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runSelect(cmd, []string{"#toppings", "t-9c2e", "t-0000"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Value != "" || strings.Join(got.Values, ",") != "t-9c2e,t-0000" || strings.Join(got.Labels, ",") != "Cheese,Basil" {
+		t.Errorf("params = %+v", got)
+	}
+	if out != "Cheese (t-1f3a)\nBasil (t-44d0)\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunSelect_NoValueOrLabel(t *testing.T) {
+	called := false
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		called = true
+		return ipc.SuccessResponse(nil), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("label", nil, "")
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runSelect(cmd, []string{"#country"})
+	})
+	if err == nil || called {
+		t.Errorf("err = %v, called = %v; want an error before any request", err, called)
+	}
+}
+
 func TestRunSelect_ElementNotFound(t *testing.T) {
 	enableJSONOutput(t)
 	exec := &mockExecutor{
@@ -5152,7 +5665,7 @@ func TestRunScroll_NoModeSpecified(t *testing.T) {
 	if resp["ok"] != false {
 		t.Error("expected ok=false")
 	}
-	if resp["error"] != "provide a selector, --to x,y, or --by x,y" {
+	if resp["error"] != "provide a selector, --to x,y, --by x,y, --top, --bottom, --page-down, or --page-up" {
 		t.Errorf("unexpected error: %v", resp["error"])
 	}
 }
@@ -5200,6 +5713,88 @@ func TestRunScroll_ElementNotFound(t *testing.T) {
 	}
 }
 
+func newScrollTestCmd(flags ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("to", "", "")
+	cmd.Flags().String("by", "", "")
+	for _, name := range []string{"top", "bottom", "page-down", "page-up", "smooth", "until-idle"} {
+		cmd.Flags().Bool(name, false, "")
+	}
+	cmd.Flags().Duration("idle", time.Second, "")
+	cmd.Flags().Duration("timeout", 30*time.Second, "")
+	for i := 0; i+1 < len(flags); i += 2 {
+		_ = cmd.Flags().Set(flags[i], flags[i+1])
+	}
+	return cmd
+}
+
+func TestRunScroll_PageUpSmooth(t *testing.T) {
+	enableJSONOutput(t)
+	var capturedParams ipc.ScrollParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			_ = json.Unmarshal(req.Params, &capturedParams)
+			return ipc.Response{OK: true}, nil
+		},
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		err = runScroll(newScrollTestCmd("page-up", "true", "smooth", "true"), nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capturedParams.Mode != "page" || capturedParams.Pages != -1 || !capturedParams.Smooth {
+		t.Errorf("params = %+v", capturedParams)
+	}
+}
+
+func TestRunScroll_UntilIdle(t *testing.T) {
+	var capturedParams ipc.ScrollParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			_ = json.Unmarshal(req.Params, &capturedParams)
+			return ipc.SuccessResponse(ipc.ScrollData{Scrolls: 6, Height: 14820}), nil
+		},
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runScroll(newScrollTestCmd("until-idle", "true", "idle", "3s"), nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capturedParams.Mode != "bottom" || !capturedParams.UntilIdle || capturedParams.Idle != 3000 || capturedParams.Timeout != 30000 {
+		t.Errorf("params = %+v", capturedParams)
+	}
+	if out != "Scrolled 6 times until idle (height 14820px)\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunScroll_ConflictingModes(t *testing.T) {
+	called := false
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			called = true
+			return ipc.Response{OK: true}, nil
+		},
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runScroll(newScrollTestCmd("top", "true", "until-idle", "true"), nil)
+	})
+	if err == nil || called {
+		t.Errorf("err = %v, called = %v; want an error before any request", err, called)
+	}
+}
+
 // Ready command tests
 
 func TestRunReady_DaemonNotRunning(t *testing.T) {
@@ -5464,3 +6059,100 @@ func TestIsWriterTTY(t *testing.T) {
 		t.Error("isWriterTTY(pipe write end) = true, want false")
 	}
 }
+
+func TestResolveOutput(t *testing.T) {
+	oldOutput, oldJSON := Output, JSONOutput
+	t.Cleanup(func() { Output, JSONOutput = oldOutput, oldJSON })
+	resetFlags(t, rootCmd.PersistentFlags(), "json")
+
+	tests := []struct {
+		output  string
+		cmd     *cobra.Command
+		json    bool
+		wantErr string
+	}{
+		{output: "", cmd: consoleCmd},
+		{output: "yaml", cmd: navigateCmd, json: true},
+		{output: "jsonl", cmd: networkCmd, json: true},
+		{output: "csv", cmd: cookiesCmd, json: true},
+		{output: "table", cmd: navigateCmd, wantErr: "only supported by console, network, and cookies"},
+		{output: "xml", cmd: consoleCmd, wantErr: "invalid --output"},
+	}
+	for _, tt := range tests {
+		Output, JSONOutput = tt.output, false
+		err := resolveOutput(tt.cmd)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("--output %s on %s: expected error %q, got %v", tt.output, tt.cmd.Name(), tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("--output %s on %s: %v", tt.output, tt.cmd.Name(), err)
+		}
+		if JSONOutput != tt.json {
+			t.Errorf("--output %s: JSONOutput = %v, want %v", tt.output, JSONOutput, tt.json)
+		}
+	}
+}
+
+func TestOutputJSON_YAML(t *testing.T) {
+	old := Output
+	Output = "yaml"
+	t.Cleanup(func() { Output = old })
+
+	var buf bytes.Buffer
+	if err := outputJSON(&buf, map[string]any{"ok": true, "url": "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "ok: true\nurl: https://example.com\n" {
+		t.Errorf("unexpected YAML:\n%s", buf.String())
+	}
+}
+
+func TestExecuteArgs_UsageErrorsAreTyped(t *testing.T) {
+	defer setMockFactory(&mockFactory{daemonRunning: true})()
+
+	tests := [][]string{
+		{"navigate"},
+		{"status", "--bogus"},
+		{"network", "--head", "1", "--tail", "1"},
+		{"status", "--output", "xml"},
+	}
+	for _, args := range tests {
+		var err error
+		captureStream(t, &os.Stderr, func() {
+			_, err = ExecuteArgs(args)
+		})
+		var ue usageError
+		if !errors.As(err, &ue) {
+			t.Errorf("%v: got %v, want a usageError", args, err)
+			continue
+		}
+		if code := ErrorCode(err); code != ipc.CodeInvalidArgs {
+			t.Errorf("%v: got code %q, want %q", args, code, ipc.CodeInvalidArgs)
+		}
+	}
+}
+
+func TestExecuteArgs_ErrorCodesFromSource(t *testing.T) {
+	tests := []struct {
+		args    []string
+		running bool
+		want    string
+	}{
+		{[]string{"describe", "--limit", "0"}, true, ipc.CodeInvalidArgs},
+		{[]string{"describe"}, false, ipc.CodeDaemonDown},
+	}
+	for _, tt := range tests {
+		restore := setMockFactory(&mockFactory{daemonRunning: tt.running})
+		var err error
+		captureStream(t, &os.Stderr, func() {
+			_, err = ExecuteArgs(tt.args)
+		})
+		restore()
+		if code := ErrorCode(err); code != tt.want {
+			t.Errorf("%v: got code %q (%v), want %q", tt.args, code, err, tt.want)
+		}
+	}
+}
@@ -52,9 +52,8 @@ Common patterns:
   click "nav a[href='/dashboard']"
   ready                                 # Wait for new page
 
-  # Toggle/checkbox
+  # Toggle (for checkboxes, check and uncheck leave the right state alone)
   click "#dark-mode-toggle"
-  click "input[type=checkbox]#agree"
 
   # Modal interaction
   click "#open-modal"
@@ -88,7 +87,7 @@ func runClick(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	selector := args[0]
@@ -123,9 +122,9 @@ func runClick(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoElementsError(resp.Error) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 		}
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: include any warnings from response data
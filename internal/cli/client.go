@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"bytes"
+	"io"
+
 	"github.com/grantcarthew/webctl/internal/executor"
 	"github.com/grantcarthew/webctl/internal/ipc"
 )
@@ -18,8 +21,10 @@ func (f defaultFactory) NewExecutor() (executor.Executor, error) {
 	return executor.NewIPCExecutorWithDebug(Debug)
 }
 
+// IsDaemonRunning reports whether the daemon is running, auto-starting it
+// when enabled (see ensureDaemon).
 func (f defaultFactory) IsDaemonRunning() bool {
-	return ipc.IsDaemonRunning()
+	return ensureDaemon()
 }
 
 // DirectExecutorFactory creates direct executors for REPL use.
@@ -41,6 +46,28 @@ func (f *DirectExecutorFactory) IsDaemonRunning() bool {
 	return true // Always true for direct execution (REPL runs inside daemon)
 }
 
+// executeStream sends req asking for its body to be streamed to w, with
+// executors that can stream. With others, and from a daemon that does not
+// stream the command, the body comes back in the response data instead.
+func executeStream(exec executor.Executor, req ipc.Request, w io.Writer) (ipc.Response, error) {
+	if se, ok := exec.(executor.StreamExecutor); ok {
+		return se.ExecuteStream(req, w)
+	}
+	return exec.Execute(req)
+}
+
+// executeStreamData is executeStream for commands whose streamed body is the
+// JSON their response data would otherwise hold, such as a full page of HTML
+// or the network buffer. The data is returned in resp.Data either way.
+func executeStreamData(exec executor.Executor, req ipc.Request) (ipc.Response, error) {
+	var buf bytes.Buffer
+	resp, err := executeStream(exec, req, &buf)
+	if err == nil && resp.OK && buf.Len() > 0 {
+		resp.Data = buf.Bytes()
+	}
+	return resp, err
+}
+
 // execFactory is the package-level factory, replaceable for testing.
 var execFactory ExecutorFactory = defaultFactory{}
 
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// Shell completion beyond static flags: the generated bash, zsh, and fish
+// scripts call back into webctl, and these functions answer from the running
+// daemon. They never start one, and complete nothing when it is down.
+
+func init() {
+	tabSwitchCmd.ValidArgsFunction = completeTabQuery
+	tabCloseCmd.ValidArgsFunction = completeTabQuery
+	cookiesDeleteCmd.ValidArgsFunction = completeCookieName
+	networkCmd.ValidArgsFunction = completeNetworkSeq
+	networkTLSCmd.ValidArgsFunction = completeNetworkSeq
+	networkDiffCmd.ValidArgsFunction = completeNetworkSeq
+}
+
+// completionRequest sends one request to the daemon for a completion and
+// decodes its data into out. It reports false when the daemon is not running
+// or the request fails, so the shell offers no candidates.
+func completionRequest(cmd string, params any, out any) bool {
+	// PersistentPreRunE does not run for completions, so apply --socket and
+	// keep auto-start off here: pressing Tab must not launch a browser.
+	if err := applySocket(); err != nil {
+		return false
+	}
+	autoStartSuppressed = true
+	if !execFactory.IsDaemonRunning() {
+		return false
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return false
+	}
+	defer func() { _ = exec.Close() }()
+
+	req := ipc.Request{Cmd: cmd}
+	if params != nil {
+		if req.Params, err = json.Marshal(params); err != nil {
+			return false
+		}
+	}
+	resp, err := exec.Execute(req)
+	if err != nil || !resp.OK {
+		debugf("COMPLETE", "%s request failed", cmd)
+		return false
+	}
+	return json.Unmarshal(resp.Data, out) == nil
+}
+
+// completion formats a candidate with its description, which zsh and fish
+// show beside it.
+func completion(value, description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if description == "" {
+		return value
+	}
+	return value + "\t" + description
+}
+
+// completeTabQuery completes the session query of tab switch and tab close
+// with the open tabs' session IDs, described by their titles.
+func completeTabQuery(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var data ipc.TabData
+	if !completionRequest("tab", ipc.TabParams{Action: "list"}, &data) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var out []string
+	for _, s := range data.Sessions {
+		if !strings.HasPrefix(s.ID, toComplete) {
+			continue
+		}
+		title := s.Title
+		if title == "" {
+			title = s.URL
+		}
+		out = append(out, completion(s.ID, title))
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCookieName completes cookies delete with the names of the page's
+// cookies, described by their domains. --domain narrows the list.
+func completeCookieName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var data ipc.CookiesData
+	if !completionRequest("cookies", ipc.CookiesParams{Action: "list"}, &data) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	domain, _ := cmd.Flags().GetString("domain")
+	cookies := data.Cookies
+	if domain != "" {
+		cookies = filterCookiesByDomain(cookies, domain)
+	}
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, c := range cookies {
+		if seen[c.Name] || !strings.HasPrefix(c.Name, toComplete) {
+			continue
+		}
+		seen[c.Name] = true
+		out = append(out, completion(c.Name, c.Domain))
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeNetworkSeq completes the network drill-down address, and the
+// entries of network tls and network diff, with the buffered requests'
+// sequence numbers, described by method and URL.
+func completeNetworkSeq(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	limit := 1
+	if cmd == networkDiffCmd {
+		limit = 2
+	}
+	if len(args) >= limit {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var data ipc.NetworkData
+	if !completionRequest("network", nil, &data) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var out []string
+	for _, e := range data.Entries {
+		seq := strconv.FormatUint(e.Seq, 10)
+		if !strings.HasPrefix(seq, toComplete) {
+			continue
+		}
+		out = append(out, completion(seq, fmt.Sprintf("%s %s", e.Method, e.URL)))
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// completionDaemon mocks a running daemon that answers tab, cookies, and
+// network list requests.
+func completionDaemon(t *testing.T) func() {
+	t.Helper()
+	return setMockFactory(&mockFactory{
+		daemonRunning: true,
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			var data any
+			switch req.Cmd {
+			case "tab":
+				data = ipc.TabData{Sessions: []ipc.PageSession{
+					{ID: "9A3E01", Title: "Example  Domain"},
+					{ID: "B7C402", URL: "https://blank.example"},
+				}}
+			case "cookies":
+				data = ipc.CookiesData{Cookies: []ipc.Cookie{
+					{Name: "session", Domain: "example.com"},
+					{Name: "session", Domain: "api.example.com"},
+					{Name: "theme", Domain: "other.org"},
+				}}
+			case "network":
+				data = ipc.NetworkData{Entries: []ipc.NetworkEntry{
+					{Seq: 3, Method: "GET", URL: "https://example.com/"},
+					{Seq: 12, Method: "POST", URL: "https://example.com/api"},
+				}}
+			default:
+				t.Errorf("unexpected %q request", req.Cmd)
+			}
+			raw, _ := json.Marshal(data)
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	})
+}
+
+func assertCompletions(t *testing.T, got []string, dir cobra.ShellCompDirective, want ...string) {
+	t.Helper()
+	if dir&cobra.ShellCompDirectiveNoFileComp == 0 {
+		t.Errorf("directive %v should disable file completion", dir)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("completions = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("completions = %q, want %q", got, want)
+			break
+		}
+	}
+}
+
+func TestCompleteTabQuery(t *testing.T) {
+	defer completionDaemon(t)()
+
+	got, dir := completeTabQuery(tabSwitchCmd, nil, "")
+	assertCompletions(t, got, dir, "9A3E01\tExample Domain", "B7C402\thttps://blank.example")
+
+	got, dir = completeTabQuery(tabSwitchCmd, nil, "B7")
+	assertCompletions(t, got, dir, "B7C402\thttps://blank.example")
+
+	got, dir = completeTabQuery(tabSwitchCmd, []string{"9A3E01"}, "")
+	assertCompletions(t, got, dir)
+}
+
+func TestCompleteCookieName(t *testing.T) {
+	defer completionDaemon(t)()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("domain", "", "")
+
+	got, dir := completeCookieName(cmd, nil, "")
+	assertCompletions(t, got, dir, "session\texample.com", "theme\tother.org")
+
+	_ = cmd.Flags().Set("domain", "other.org")
+	got, dir = completeCookieName(cmd, nil, "")
+	assertCompletions(t, got, dir, "theme\tother.org")
+}
+
+func TestCompleteNetworkSeq(t *testing.T) {
+	defer completionDaemon(t)()
+
+	got, dir := completeNetworkSeq(networkCmd, nil, "1")
+	assertCompletions(t, got, dir, "12\tPOST https://example.com/api")
+	if dir&cobra.ShellCompDirectiveKeepOrder == 0 {
+		t.Error("sequence numbers should keep buffer order")
+	}
+}
+
+func TestCompletion_DaemonNotRunning(t *testing.T) {
+	defer setMockFactory(&mockFactory{
+		daemonRunning: false,
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			t.Errorf("unexpected %q request with no daemon", req.Cmd)
+			return ipc.Response{}, nil
+		},
+	})()
+
+	got, dir := completeTabQuery(tabSwitchCmd, nil, "")
+	assertCompletions(t, got, dir)
+	if !autoStartSuppressed {
+		t.Error("completion should not auto-start the daemon")
+	}
+	autoStartSuppressed = false
+}
@@ -0,0 +1,433 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show or edit configuration",
+	Long: `Shows or edits webctl's configuration files.
+
+Two YAML files are read on every command, the project file overriding the user
+file, and command-line flags and environment variables overriding both:
+  $XDG_CONFIG_HOME/webctl/config.yaml   User config (falls back to
+                                        ~/.config/webctl/config.yaml)
+  .webctl.yaml                          Project config, found in the working
+                                        directory or its nearest parent
+
+Keys:
+  headless               Default for 'start --headless' (true or false)
+  browser                Chrome or Chromium binary (used when WEBCTL_CHROME is unset)
+  output                 Default --output format: text, json, jsonl, yaml, csv,
+                         or table (csv/table apply to console, network, cookies)
+  buffer-size            Daemon console and network buffer capacity ('start --buffer-size')
+  timeout                Default for every command's --timeout (e.g. 30s); commands
+                         that take seconds get the value rounded up
+  viewport               Default for 'start --viewport' (e.g. 390x844)
+  user-agent             Default for 'start --user-agent'
+  throttle               Default for 'start --throttle' (fast-4g, slow-4g, 3g, offline)
+  defaults.<cmd>.<flag>  Default for one command's flag, e.g. defaults.navigate.wait
+                         (subcommands use spaces: "defaults.history go.wait")
+  preset                 Preset applied when --preset and $WEBCTL_PRESET are unset
+  presets.<name>.<key>   A key inside a named preset
+  bookmarks.<name>       A named URL for 'webctl open <name>' (see 'webctl bookmark')
+
+Presets:
+  A preset bundles any of the keys above under a name. Select one with
+  --preset <name> (any command) or WEBCTL_PRESET; its values override the rest
+  of the config, and flags still override the preset.
+
+Subcommands:
+  list                   Show the effective configuration and where each value comes from
+  get <key>              Print one effective value
+  set <key> <value>      Set a value in the user config (--project for .webctl.yaml)
+  unset <key>            Remove a value from the user config (--project for .webctl.yaml)
+
+Example file:
+  headless: true
+  timeout: 30s
+  defaults:
+    navigate:
+      wait: true
+  presets:
+    mobile-ci:
+      viewport: 390x844
+      user-agent: Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) ...
+      throttle: slow-4g
+      timeout: 90s
+
+Examples:
+  config list
+  config set headless true
+  config set --project defaults.navigate.wait true
+  config set presets.mobile-ci.viewport 390x844
+  config get timeout
+  config unset browser
+  start --preset mobile-ci`,
+	Args: cobra.NoArgs,
+	RunE: runConfigList,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the effective configuration",
+	Long: `Shows every configured value after merging the user and project files, with
+the file each value comes from.
+
+Text output:
+  headless              true    /home/me/.config/webctl/config.yaml
+  defaults.navigate.wait true   /home/me/project/.webctl.yaml
+
+JSON output:
+  {"ok": true, "files": {"user": "...", "project": "..."}, "values": {"headless": "true"}}`,
+	Args: cobra.NoArgs,
+	RunE: runConfigList,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print one configuration value",
+	Long: `Prints the effective value of a key. Exits non-zero if the key is not set.
+
+Examples:
+  config get timeout
+  config get defaults.navigate.wait`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration value",
+	Long: `Sets a key in the user config file, or with --project in the nearest
+.webctl.yaml (created in the working directory if there is none).
+
+Examples:
+  config set headless true
+  config set timeout 30s
+  config set --project defaults.navigate.wait true
+  config set "defaults.history go.wait" true`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration value",
+	Long: `Removes a key from the user config file, or with --project from the nearest
+.webctl.yaml.
+
+Examples:
+  config unset browser
+  config unset --project defaults.navigate.wait`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigUnset,
+}
+
+// presetEnv is the environment variable that selects a config preset when
+// --preset is not given. It also carries --preset to auto-started daemons.
+const presetEnv = "WEBCTL_PRESET"
+
+// skipConfigAnnotation marks commands that must run even when a config file is
+// invalid, so 'webctl config' can repair it.
+const skipConfigAnnotation = "webctl:skip-config"
+
+func init() {
+	for _, c := range []*cobra.Command{configCmd, configListCmd, configGetCmd, configSetCmd, configUnsetCmd} {
+		c.Annotations = map[string]string{noAutoStartAnnotation: "", skipConfigAnnotation: ""}
+	}
+	configSetCmd.Flags().Bool("project", false, "Write to the project .webctl.yaml instead of the user config")
+	configUnsetCmd.Flags().Bool("project", false, "Edit the project .webctl.yaml instead of the user config")
+	configCmd.AddCommand(configListCmd, configGetCmd, configSetCmd, configUnsetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configFiles returns the user config path and the nearest project config
+// path ("" if none).
+func configFiles() (user, project string) {
+	if wd, err := os.Getwd(); err == nil {
+		project = config.FindProject(wd)
+	}
+	return config.UserPath(), project
+}
+
+// loadConfig reads and merges the user and project config files.
+func loadConfig() (config.Config, error) {
+	userPath, projectPath := configFiles()
+	user, err := config.LoadFile(userPath)
+	if err != nil {
+		return config.Config{}, err
+	}
+	project, err := config.LoadFile(projectPath)
+	if err != nil {
+		return config.Config{}, err
+	}
+	return user.Merge(project), nil
+}
+
+// applyConfig fills in flags the user did not pass on the command line from
+// the merged config. It runs before every command except 'config' itself.
+func applyConfig(cmd *cobra.Command) error {
+	if _, skip := cmd.Annotations[skipConfigAnnotation]; skip {
+		return nil
+	}
+	c, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	preset := c.Preset
+	if env := os.Getenv(presetEnv); env != "" {
+		preset = env
+	}
+	if Preset != "" {
+		preset = Preset
+		// Auto-started daemons are separate processes; pass the choice on.
+		_ = setCommandEnv(presetEnv, Preset)
+	}
+	if c, err = c.WithPreset(preset); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	if preset != "" {
+		debugf("CONFIG", "preset %s", preset)
+	}
+
+	if c.Browser != "" && os.Getenv("WEBCTL_CHROME") == "" {
+		// The environment carries the path to the daemon and to auto-started
+		// daemons, which find Chrome through WEBCTL_CHROME.
+		_ = setCommandEnv("WEBCTL_CHROME", c.Browser)
+	}
+
+	defaults := map[string]string{}
+	switch c.Output {
+	case "", config.OutputText:
+	case config.OutputJSON:
+		defaults["json"] = "true"
+	default:
+		_, records := cmd.Annotations[recordsAnnotation]
+		tabular := c.Output == config.OutputCSV || c.Output == config.OutputTable
+		// An explicit --json wins, and commands without records keep their
+		// text output rather than failing on csv or table.
+		if !cmd.Flags().Changed("json") && (records || !tabular) {
+			defaults["output"] = c.Output
+		}
+	}
+	if c.Headless != nil {
+		defaults["headless"] = strconv.FormatBool(*c.Headless)
+	}
+	if c.BufferSize > 0 {
+		defaults["buffer-size"] = strconv.Itoa(c.BufferSize)
+	}
+	if c.Viewport != "" {
+		defaults["viewport"] = c.Viewport
+	}
+	if c.UserAgent != "" {
+		defaults["user-agent"] = c.UserAgent
+	}
+	if c.Throttle != "" {
+		defaults["throttle"] = c.Throttle
+	}
+	if c.Timeout > 0 {
+		if f := cmd.Flags().Lookup("timeout"); f != nil && f.DefValue != "0s" {
+			// A zero default means "no limit" (watch-dom); leave those alone.
+			defaults["timeout"] = c.Timeout.String()
+			if f.Value.Type() == "int" {
+				defaults["timeout"] = strconv.Itoa(int((c.Timeout + time.Second - 1) / time.Second))
+			}
+		}
+	}
+
+	path := configCommandPath(cmd)
+	for flag, value := range c.Defaults[path] {
+		if cmd.Flags().Lookup(flag) == nil {
+			return fmt.Errorf("config: defaults.%s.%s: %q has no --%s flag", path, flag, path, flag)
+		}
+		defaults[flag] = value
+	}
+
+	for flag, value := range defaults {
+		f := cmd.Flags().Lookup(flag)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			return fmt.Errorf("config: invalid value %q for %s --%s: %w", value, path, flag, err)
+		}
+		debugf("CONFIG", "%s --%s=%s", path, flag, value)
+	}
+	return nil
+}
+
+// configCommandPath returns cmd's path without the program name, as used in
+// defaults.<cmd>.<flag> keys (e.g. "navigate", "history go").
+func configCommandPath(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	t := startTimer("config list")
+	defer t.log()
+
+	userPath, projectPath := configFiles()
+	user, err := config.LoadFile(userPath)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	project, err := config.LoadFile(projectPath)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	merged := user.Merge(project)
+
+	keys := merged.List()
+	values := make(map[string]string, len(keys))
+	for _, k := range keys {
+		values[k], _, _ = merged.Get(k)
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":     true,
+			"files":  map[string]string{"user": userPath, "project": projectPath},
+			"values": values,
+		})
+	}
+
+	if len(keys) == 0 {
+		_, _ = fmt.Fprintf(os.Stdout, "No configuration set (user config: %s)\n", userPath)
+		return nil
+	}
+	keyWidth, valueWidth := 0, 0
+	for _, k := range keys {
+		keyWidth = max(keyWidth, len(k))
+		valueWidth = max(valueWidth, len(values[k]))
+	}
+	for _, k := range keys {
+		source := userPath
+		if _, ok, _ := project.Get(k); ok {
+			source = projectPath
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "%-*s  %-*s  %s\n", keyWidth, k, valueWidth, values[k], source)
+	}
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	t := startTimer("config get")
+	defer t.log()
+
+	c, err := loadConfig()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	key := args[0]
+	value, ok, err := c.Get(key)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !ok {
+		return outputNotice(fmt.Sprintf("%s is not set", key))
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":    true,
+			"key":   key,
+			"value": value,
+		})
+	}
+	_, _ = fmt.Fprintln(os.Stdout, value)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	t := startTimer("config set")
+	defer t.log()
+
+	key, value := args[0], args[1]
+	if err := validateDefaultsKey(key); err != nil {
+		return outputError(err.Error())
+	}
+	return editConfig(cmd, func(c *config.Config) error {
+		return c.Set(key, value)
+	})
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	t := startTimer("config unset")
+	defer t.log()
+
+	return editConfig(cmd, func(c *config.Config) error {
+		return c.Unset(args[0])
+	})
+}
+
+// editConfig applies edit to the user config, or the project config with
+// --project, and saves it.
+func editConfig(cmd *cobra.Command, edit func(*config.Config) error) error {
+	project, _ := cmd.Flags().GetBool("project")
+	userPath, projectPath := configFiles()
+	path := userPath
+	if project {
+		path = projectPath
+		if path == "" {
+			path = config.ProjectFile
+		}
+	}
+	if path == "" {
+		return outputError("cannot locate the user config directory; set XDG_CONFIG_HOME")
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	debugParam("path=%q", path)
+
+	c, err := config.LoadFile(path)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if err := edit(&c); err != nil {
+		return outputError(err.Error())
+	}
+	if err := c.Save(path); err != nil {
+		return outputError(err.Error())
+	}
+	debugFile("wrote", path, 0)
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":   true,
+			"path": path,
+		})
+	}
+	return outputSuccess(nil)
+}
+
+// validateDefaultsKey checks that a defaults.<cmd>.<flag> key names a real
+// command and flag, so typos fail at set time rather than on every command.
+func validateDefaultsKey(key string) error {
+	if !strings.HasPrefix(key, "defaults.") {
+		return nil
+	}
+	cmdPath, flag, err := config.SplitDefaultsKey(key)
+	if err != nil {
+		return err
+	}
+
+	target, remaining, findErr := rootCmd.Find(strings.Fields(cmdPath))
+	if findErr != nil || target == rootCmd || len(remaining) > 0 || configCommandPath(target) != cmdPath {
+		return fmt.Errorf("unknown command %q in %s", cmdPath, key)
+	}
+	if target.Flags().Lookup(flag) == nil && target.InheritedFlags().Lookup(flag) == nil {
+		return fmt.Errorf("%q has no --%s flag", cmdPath, flag)
+	}
+	return nil
+}
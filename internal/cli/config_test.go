@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// useConfigDirs points the user config at a temp dir and changes into a temp
+// working directory, returning the user config path and the working dir.
+func useConfigDirs(t *testing.T) (userPath, workDir string) {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	workDir = filepath.Join(root, "project")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(workDir)
+	return filepath.Join(root, "xdg", "webctl", "config.yaml"), workDir
+}
+
+func writeConfigFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// resetFlags restores the named flags to their defaults now and when t ends,
+// so state left by other tests cannot mask config defaults.
+func resetFlags(t *testing.T, flags *pflag.FlagSet, names ...string) {
+	t.Helper()
+	reset := func() {
+		for _, name := range names {
+			f := flags.Lookup(name)
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		}
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestApplyConfig_DefaultsAndPrecedence(t *testing.T) {
+	userPath, workDir := useConfigDirs(t)
+	writeConfigFile(t, userPath, "timeout: 1500ms\ndefaults:\n  navigate:\n    wait: true\n")
+	writeConfigFile(t, filepath.Join(workDir, ".webctl.yaml"), "defaults:\n  navigate:\n    wait: false\n")
+
+	flags := navigateCmd.Flags()
+	resetFlags(t, flags, "wait", "timeout")
+
+	if err := applyConfig(navigateCmd); err != nil {
+		t.Fatal(err)
+	}
+	if wait, _ := flags.GetBool("wait"); wait {
+		t.Error("project config should override the user's wait=true")
+	}
+	if timeout, _ := flags.GetInt("timeout"); timeout != 2 {
+		t.Errorf("expected 1500ms rounded up to 2 seconds, got %d", timeout)
+	}
+
+	// Flags given on the command line win.
+	if err := flags.Set("timeout", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfig(navigateCmd); err != nil {
+		t.Fatal(err)
+	}
+	if timeout, _ := flags.GetInt("timeout"); timeout != 7 {
+		t.Errorf("explicit --timeout overridden by config: %d", timeout)
+	}
+}
+
+func TestApplyConfig_DurationTimeoutAndNoLimit(t *testing.T) {
+	userPath, _ := useConfigDirs(t)
+	writeConfigFile(t, userPath, "timeout: 20s\n")
+
+	resetFlags(t, readyCmd.Flags(), "timeout")
+
+	if err := applyConfig(readyCmd); err != nil {
+		t.Fatal(err)
+	}
+	if got := readyCmd.Flags().Lookup("timeout").Value.String(); got != "20s" {
+		t.Errorf("ready --timeout: got %s, want 20s", got)
+	}
+
+	resetFlags(t, watchDOMCmd.Flags(), "timeout")
+	if err := applyConfig(watchDOMCmd); err != nil {
+		t.Fatal(err)
+	}
+	if watchDOMCmd.Flags().Lookup("timeout").Changed {
+		t.Error("watch-dom's no-limit timeout should not take the config default")
+	}
+}
+
+func TestApplyConfig_UnknownFlag(t *testing.T) {
+	userPath, _ := useConfigDirs(t)
+	writeConfigFile(t, userPath, "defaults:\n  navigate:\n    bogus: 1\n")
+
+	err := applyConfig(navigateCmd)
+	if err == nil || !strings.Contains(err.Error(), "--bogus") {
+		t.Errorf("expected unknown flag error, got %v", err)
+	}
+}
+
+func TestApplyConfig_SkippedForConfigCommand(t *testing.T) {
+	userPath, _ := useConfigDirs(t)
+	writeConfigFile(t, userPath, "output: xml\n")
+
+	if err := applyConfig(configSetCmd); err != nil {
+		t.Errorf("config set must run with an invalid config: %v", err)
+	}
+	if err := applyConfig(navigateCmd); err == nil {
+		t.Error("expected invalid config error for navigate")
+	}
+}
+
+func TestRunConfigSet_WritesProjectFile(t *testing.T) {
+	_, workDir := useConfigDirs(t)
+	resetFlags(t, configSetCmd.Flags(), "project")
+	if err := configSetCmd.Flags().Set("project", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	_ = captureStream(t, &os.Stdout, func() {
+		err = runConfigSet(configSetCmd, []string{"defaults.history go.wait", "true"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, readErr := os.ReadFile(filepath.Join(workDir, ".webctl.yaml"))
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	if !strings.Contains(string(data), "history go:") {
+		t.Errorf("unexpected project file:\n%s", data)
+	}
+
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runConfigSet(configSetCmd, []string{"defaults.history go.bogus", "1"})
+	})
+	if err == nil {
+		t.Error("expected error for unknown flag")
+	}
+}
+
+func TestRunConfigGet_NotSet(t *testing.T) {
+	useConfigDirs(t)
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runConfigGet(configGetCmd, []string{"timeout"})
+	})
+	if err == nil || !IsPrintedError(err) {
+		t.Errorf("expected printed error for unset key, got %v", err)
+	}
+}
+
+func TestApplyConfig_Preset(t *testing.T) {
+	userPath, _ := useConfigDirs(t)
+	writeConfigFile(t, userPath, `headless: false
+preset: local-dev
+presets:
+  local-dev:
+    timeout: 5s
+  mobile-ci:
+    headless: true
+    viewport: 390x844
+    throttle: slow-4g
+`)
+	flags := startCmd.Flags()
+	resetFlags(t, flags, "headless", "viewport", "throttle")
+	resetFlags(t, readyCmd.Flags(), "timeout")
+	t.Setenv(presetEnv, "")
+	t.Cleanup(func() { Preset = "" })
+
+	// The config's default preset applies when none is selected.
+	if err := applyConfig(readyCmd); err != nil {
+		t.Fatal(err)
+	}
+	if got := readyCmd.Flags().Lookup("timeout").Value.String(); got != "5s" {
+		t.Errorf("default preset timeout: got %s", got)
+	}
+
+	Preset = "mobile-ci"
+	if err := applyConfig(startCmd); err != nil {
+		t.Fatal(err)
+	}
+	if headless, _ := flags.GetBool("headless"); !headless {
+		t.Error("preset headless=true should override the top-level false")
+	}
+	if v, _ := flags.GetString("viewport"); v != "390x844" {
+		t.Errorf("viewport: got %q", v)
+	}
+	if os.Getenv(presetEnv) != "mobile-ci" {
+		t.Error("--preset should be passed on to auto-started daemons via the environment")
+	}
+
+	Preset = "missing"
+	if err := applyConfig(startCmd); err == nil || !strings.Contains(err.Error(), "local-dev, mobile-ci") {
+		t.Errorf("expected unknown preset error, got %v", err)
+	}
+}
+
+func TestApplyConfig_Output(t *testing.T) {
+	userPath, _ := useConfigDirs(t)
+	writeConfigFile(t, userPath, "output: table\n")
+	// Merge the persistent flags into each command's set, as parsing does.
+	_ = consoleCmd.InheritedFlags()
+	_ = navigateCmd.InheritedFlags()
+	resetFlags(t, rootCmd.PersistentFlags(), "output", "json")
+	t.Cleanup(func() { Output = "" })
+
+	if err := applyConfig(consoleCmd); err != nil {
+		t.Fatal(err)
+	}
+	if Output != "table" {
+		t.Errorf("console should take output: table, got %q", Output)
+	}
+
+	Output = ""
+	if err := applyConfig(navigateCmd); err != nil {
+		t.Fatal(err)
+	}
+	if Output != "" {
+		t.Errorf("navigate has no records and should keep text output, got %q", Output)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/grantcarthew/webctl/internal/cli/format"
@@ -28,7 +27,10 @@ Subcommands:
   save [path]       Save console logs to file (temp dir if no path given)
 
 Universal flags:
-  --find, -f        Search for text within log messages (narrows the list)
+  --find, -f        Search for text within log messages and their source URLs
+                    (narrows the list; case-insensitive)
+  --regex           Treat --find as a regular expression (Go regexp syntax)
+  --invert          Keep entries that do NOT match --find
   --json            Output in JSON format, always full fidelity (global flag)
 
 Console-specific filter flags (list and save; ignored by drill-down):
@@ -36,6 +38,17 @@ Console-specific filter flags (list and save; ignored by drill-down):
   --head N          Return first N entries (count over the seq-ordered list)
   --tail N          Return last N entries (count over the seq-ordered list)
   --range START-END Keep entries whose seq is in [START, END] inclusive
+  --since DURATION  Keep entries logged within DURATION of now (30s, 5m)
+  --after TIME      Keep entries logged at or after TIME
+  --before TIME     Keep entries logged at or before TIME
+                    TIME is a local clock time (15:04:05, as the list prints),
+                    an RFC 3339 date and time, or Unix milliseconds
+
+Text formatting (list and drill-down):
+  --timestamps MODE absolute (default): wall-clock time, [15:04:05]
+                    relative: time since the page's latest navigation, [+0.234s]
+                    elapsed: time since the previous entry listed, [+0.012s]
+  --no-source       Omit the source location from each line
 
 Drill-down:
   console <n>       Show the single entry with seq n, rendered in full: the
@@ -48,8 +61,14 @@ Default mode (stdout):
   console                                  # Indexed list, one line per entry
   console --type error                     # Only errors to stdout
   console --find "undefined"               # Search and show matches
+  console --type error --find vendor.js --invert
+                                           # Errors not from vendor.js
+  console --find "timeout|refused" --regex # Either word
   console --tail 20                        # Last 20 entries
   console --range 318-425                  # Entries with seq in [318, 425]
+  console --timestamps relative            # +0.234s since navigation
+  console --since 2m --type error          # Errors from the last two minutes
+  console --after 14:30:05                 # Everything since 14:30:05 today
 
 Drill-down mode (stdout):
   console 42                               # Entry 42, rendered in full
@@ -59,11 +78,14 @@ Save mode (file):
   console save ./logs/debug.json           # Save to custom file
   console save ./output/                   # Save to dir (auto-filename)
   console save --type error --tail 50
+  console save --since 5m
 
 Response formats:
   List:     03 [15:04:05] ERROR app.js:42:10 TypeError: undefined (to stdout)
   Drill:    the single entry with its full stack and arguments
   Save:     /tmp/webctl-console/25-12-28-143052-123-console.json
+  Records:  --output jsonl|yaml|csv|table renders the list one entry per
+            record (csv/table columns: seq, time, type, source, text, location)
 
 Error cases:
   - "No matches found" - find text not in logs
@@ -72,8 +94,9 @@ Error cases:
 	// At most one positional argument: the bare-integer drill-down address. A
 	// stray extra token is a usage error rather than a silently discarded arg.
 	// `save` dispatches as a subcommand before this constraint applies.
-	Args: cobra.MaximumNArgs(1),
-	RunE: runConsoleDefault,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runConsoleDefault,
+	Annotations: map[string]string{recordsAnnotation: ""},
 }
 
 var consoleSaveCmd = &cobra.Command{
@@ -97,16 +120,25 @@ Examples:
 
 func init() {
 	// Universal flags on root command (inherited by default/save subcommands)
-	consoleCmd.PersistentFlags().StringP("find", "f", "", "Search for text within log messages")
+	consoleCmd.PersistentFlags().StringP("find", "f", "", "Search for text within log messages and source URLs")
+	consoleCmd.PersistentFlags().Bool("regex", false, "Treat --find as a regular expression")
+	consoleCmd.PersistentFlags().Bool("invert", false, "Keep entries that do not match --find")
 
 	// Console-specific filter flags
 	consoleCmd.PersistentFlags().StringSlice("type", nil, "Filter by entry type (repeatable, CSV-supported)")
 	consoleCmd.PersistentFlags().Int("head", 0, "Return first N entries (count over the seq-ordered list)")
 	consoleCmd.PersistentFlags().Int("tail", 0, "Return last N entries (count over the seq-ordered list)")
 	consoleCmd.PersistentFlags().String("range", "", "Keep entries whose seq is in [START, END] inclusive (format: START-END)")
+	consoleCmd.PersistentFlags().Duration("since", 0, "Keep entries logged within this long before now (e.g. 30s, 5m)")
+	consoleCmd.PersistentFlags().String("after", "", "Keep entries logged at or after this time (15:04:05, RFC 3339, or Unix ms)")
+	consoleCmd.PersistentFlags().String("before", "", "Keep entries logged at or before this time (15:04:05, RFC 3339, or Unix ms)")
 	// Note: MarkFlagsMutuallyExclusive doesn't work with PersistentFlags,
 	// so we validate manually in getConsoleFromDaemon
 
+	// Text formatting flags (list and drill-down)
+	consoleCmd.Flags().String("timestamps", "absolute", "Timestamp style: absolute, relative (since navigation), or elapsed (since previous entry)")
+	consoleCmd.Flags().Bool("no-source", false, "Omit the source location from each line")
+
 	// Add all subcommands
 	consoleCmd.AddCommand(consoleSaveCmd)
 
@@ -129,22 +161,29 @@ func runConsoleDefault(cmd *cobra.Command, args []string) error {
 	if len(args) > 0 {
 		n, err := strconv.Atoi(args[0])
 		if err != nil {
-			return outputError(fmt.Sprintf("unknown command %q for \"webctl console\"", args[0]))
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("unknown command %q for \"webctl console\"", args[0]))
 		}
 		drillSeq = n
 		hasDrill = true
 	}
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	// Validate the formatting flags before fetching, so a malformed value is a
+	// usage error in every mode, JSON included.
+	opts, err := consoleOutputOptions(cmd)
+	if err != nil {
+		return outputError(err.Error())
 	}
 
 	if hasDrill {
-		return runConsoleDrilldown(drillSeq)
+		return runConsoleDrilldown(drillSeq, opts)
 	}
 
 	// List mode. Fetch, filter, and limit the active session's entries.
-	entries, err := getConsoleFromDaemon(cmd)
+	entries, navigations, err := getConsoleFromDaemon(cmd)
 	if err != nil {
 		if errors.Is(err, ErrNoMatches) {
 			return outputNotice("No matches found")
@@ -152,33 +191,57 @@ func runConsoleDefault(cmd *cobra.Command, args []string) error {
 		return outputError(err.Error())
 	}
 
+	if recordOutput() {
+		return format.Records(os.Stdout, Output, entries, format.ConsoleColumns)
+	}
 	if JSONOutput {
 		return outputConsoleJSON(entries)
 	}
 
-	return format.Console(os.Stdout, entries, format.NewOutputOptions(JSONOutput, NoColor))
+	opts.Navigations = navigations
+	return format.Console(os.Stdout, entries, opts)
+}
+
+// consoleOutputOptions resolves the text formatting flags.
+func consoleOutputOptions(cmd *cobra.Command) (format.OutputOptions, error) {
+	opts := format.NewOutputOptions(JSONOutput, NoColor)
+	opts.NoSource, _ = cmd.Flags().GetBool("no-source")
+
+	timestamps, _ := cmd.Flags().GetString("timestamps")
+	switch timestamps {
+	case "absolute", "":
+		opts.Timestamps = format.TimestampsAbsolute
+	case "relative":
+		opts.Timestamps = format.TimestampsRelative
+	case "elapsed":
+		opts.Timestamps = format.TimestampsElapsed
+	default:
+		return opts, fmt.Errorf("invalid --timestamps %q: use absolute, relative, or elapsed", timestamps)
+	}
+	return opts, nil
 }
 
 // runConsoleDrilldown resolves a single entry by exact seq membership over the
 // active session's full unfiltered set and renders it. It ignores the filter and
 // head/tail/range flags so a live entry is never hidden by a narrowing flag, and
 // derives its miss-error bounds from that same set.
-func runConsoleDrilldown(n int) error {
-	entries, err := fetchConsoleEntries()
+func runConsoleDrilldown(n int, opts format.OutputOptions) error {
+	data, err := fetchConsoleData()
 	if err != nil {
 		return outputError(err.Error())
 	}
 
-	entry, found := findConsoleEntryBySeq(entries, n)
+	entry, found := findConsoleEntryBySeq(data.Entries, n)
 	if !found {
-		return outputError(consoleDrilldownMissMessage(n, entries))
+		return outputError(consoleDrilldownMissMessage(n, data.Entries))
 	}
 
 	if JSONOutput {
 		return outputConsoleJSON([]ipc.ConsoleEntry{*entry})
 	}
 
-	return format.ConsoleDetail(os.Stdout, *entry, format.NewOutputOptions(JSONOutput, NoColor))
+	opts.Navigations = data.Navigations
+	return format.ConsoleDetail(os.Stdout, *entry, opts)
 }
 
 // consoleEntriesOrEmpty returns entries, or a non-nil empty slice when entries
@@ -262,7 +325,7 @@ func runConsoleSave(cmd *cobra.Command, args []string) error {
 // consoleSaveContent produces the console save-file payload: the JSON envelope
 // written to disk, identical in shape to the console JSON output.
 func consoleSaveContent(cmd *cobra.Command) (string, error) {
-	entries, err := getConsoleFromDaemon(cmd)
+	entries, _, err := getConsoleFromDaemon(cmd)
 	if err != nil {
 		return "", err
 	}
@@ -274,14 +337,14 @@ func consoleSaveContent(cmd *cobra.Command) (string, error) {
 	})
 }
 
-// fetchConsoleEntries returns the active session's full unfiltered entry set from
-// the daemon, in buffer order. Both the filtered list path and the unfiltered
-// drill-down path build on it, so drill-down addresses the same scope the list
-// derives its bounds from.
-func fetchConsoleEntries() ([]ipc.ConsoleEntry, error) {
+// fetchConsoleData returns the active session's full unfiltered entry set from
+// the daemon, in buffer order, with its navigation times. Both the filtered list
+// path and the unfiltered drill-down path build on it, so drill-down addresses
+// the same scope the list derives its bounds from.
+func fetchConsoleData() (ipc.ConsoleData, error) {
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
-		return nil, err
+		return ipc.ConsoleData{}, err
 	}
 	defer func() { _ = exec.Close() }()
 
@@ -293,29 +356,27 @@ func fetchConsoleEntries() ([]ipc.ConsoleEntry, error) {
 	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
 
 	if err != nil {
-		return nil, err
+		return ipc.ConsoleData{}, err
 	}
 	if !resp.OK {
-		return nil, fmt.Errorf("%s", resp.Error)
+		return ipc.ConsoleData{}, fmt.Errorf("%s", resp.Error)
 	}
 
 	var data ipc.ConsoleData
 	if err := json.Unmarshal(resp.Data, &data); err != nil {
-		return nil, err
+		return ipc.ConsoleData{}, err
 	}
-	return data.Entries, nil
+	return data, nil
 }
 
-// getConsoleFromDaemon fetches console logs from daemon, applying filters
-func getConsoleFromDaemon(cmd *cobra.Command) ([]ipc.ConsoleEntry, error) {
+// getConsoleFromDaemon fetches console logs from daemon, applying filters. It
+// also returns the active tab's navigation times for relative timestamps.
+func getConsoleFromDaemon(cmd *cobra.Command) ([]ipc.ConsoleEntry, []int64, error) {
 	// Try to get flags from command's merged flags, then persistent flags,
 	// then parent's persistent flags (for subcommands)
-	find, _ := cmd.Flags().GetString("find")
-	if find == "" {
-		find, _ = cmd.PersistentFlags().GetString("find")
-	}
-	if find == "" && cmd.Parent() != nil {
-		find, _ = cmd.Parent().PersistentFlags().GetString("find")
+	finder, find, err := findFromFlags(cmd)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	types, _ := cmd.Flags().GetStringSlice("type")
@@ -362,14 +423,27 @@ func getConsoleFromDaemon(cmd *cobra.Command) ([]ipc.ConsoleEntry, error) {
 		limitFlags++
 	}
 	if limitFlags > 1 {
-		return nil, fmt.Errorf("--head, --tail, and --range are mutually exclusive")
+		return nil, nil, fmt.Errorf("--head, --tail, and --range are mutually exclusive")
+	}
+
+	window, err := timeWindowFromFlags(cmd)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	debugParam("find=%q types=%v head=%d tail=%d range=%q", find, types, head, tail, rangeStr)
+	debugParam("find=%q regex=%v invert=%v types=%v head=%d tail=%d range=%q window=%s", finder.pattern, finder.re != nil, finder.invert, types, head, tail, rangeStr, window)
 
-	entries, err := fetchConsoleEntries()
+	data, err := fetchConsoleData()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	entries := data.Entries
+
+	// Apply time window
+	if window.active() {
+		beforeCount := len(entries)
+		entries = filterConsoleByTime(entries, window)
+		debugFilter(fmt.Sprintf("time window %s", window), beforeCount, len(entries))
 	}
 
 	// Apply type filter
@@ -380,12 +454,12 @@ func getConsoleFromDaemon(cmd *cobra.Command) ([]ipc.ConsoleEntry, error) {
 	}
 
 	// Apply --find filter if specified
-	if find != "" {
+	if find {
 		beforeCount := len(entries)
-		entries = filterConsoleByText(entries, find)
-		debugFilter(fmt.Sprintf("--find %q", find), beforeCount, len(entries))
+		entries = filterConsoleByText(entries, finder)
+		debugFilter(finder.String(), beforeCount, len(entries))
 		if len(entries) == 0 {
-			return nil, ErrNoMatches
+			return nil, nil, ErrNoMatches
 		}
 	}
 
@@ -393,10 +467,21 @@ func getConsoleFromDaemon(cmd *cobra.Command) ([]ipc.ConsoleEntry, error) {
 	// not an error: it returns an empty list with exit 0, matching network.
 	entries, err = applyConsoleLimiting(entries, head, tail, rangeStr)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return entries, nil
+	return entries, data.Navigations, nil
+}
+
+// filterConsoleByTime filters entries to those logged inside the window.
+func filterConsoleByTime(entries []ipc.ConsoleEntry, window timeWindow) []ipc.ConsoleEntry {
+	var filtered []ipc.ConsoleEntry
+	for _, e := range entries {
+		if window.contains(e.Timestamp) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
 }
 
 // filterConsoleByType filters entries to only include those with matching types.
@@ -415,13 +500,13 @@ func filterConsoleByType(entries []ipc.ConsoleEntry, types []string) []ipc.Conso
 	return filtered
 }
 
-// filterConsoleByText filters entries to only include those containing the search text
-func filterConsoleByText(entries []ipc.ConsoleEntry, searchText string) []ipc.ConsoleEntry {
+// filterConsoleByText filters entries to those whose text or source URL passes
+// the --find matcher.
+func filterConsoleByText(entries []ipc.ConsoleEntry, m textMatcher) []ipc.ConsoleEntry {
 	var matchedEntries []ipc.ConsoleEntry
-	searchLower := strings.ToLower(searchText)
 
 	for _, entry := range entries {
-		if strings.Contains(strings.ToLower(entry.Text), searchLower) {
+		if m.matchAny(entry.Text, entry.URL) {
 			matchedEntries = append(matchedEntries, entry)
 		}
 	}
@@ -272,6 +272,30 @@ func TestRunConsole_NonIntegerArgIsUnknownCommand(t *testing.T) {
 	}
 }
 
+func TestRunConsole_InvalidTimestampsRejectedBeforeFetch(t *testing.T) {
+	_ = consoleCmd.Flags().Set("timestamps", "epoch")
+	t.Cleanup(func() { _ = consoleCmd.Flags().Set("timestamps", "absolute") })
+	restore := setMockFactory(&mockFactory{
+		daemonRunning: true,
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			t.Errorf("unexpected %q request for an invalid --timestamps", req.Cmd)
+			return ipc.Response{OK: true}, nil
+		},
+	})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runConsoleDefault(consoleCmd, []string{})
+	})
+	if err == nil {
+		t.Fatal("expected an error for an invalid --timestamps value")
+	}
+	if !strings.Contains(out, `invalid --timestamps "epoch"`) {
+		t.Errorf("error should reject the --timestamps value:\n%s", out)
+	}
+}
+
 func TestConsoleCmd_AcceptsAtMostOneArg(t *testing.T) {
 	// The drill-down address is a single positional token. Zero or one arg is
 	// valid; a stray second token is a usage error rather than a silently
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Create, list, or close isolated browser contexts",
+	Long: `Manage browser contexts: separate cookie jars and storage in one browser,
+like incognito windows. Tabs in different contexts do not share logins, so two
+users can be signed in to the same site side by side.
+
+Without a subcommand, lists the contexts and their tabs.
+
+Subcommands:
+  new [url]     Open a tab in a new context and make it active
+  list          List contexts and their tabs
+  close <id>    Close a context and its tabs
+
+A context made with 'context new' lives in memory: its cookies and storage
+never reach the profile, and are lost when it is closed or the browser is
+relaunched. With --incognito it is closed with its last tab, as closing the
+last incognito window ends that session; otherwise it stays until
+'context close' or 'webctl stop'.
+
+'webctl tab new' opens its tab in the active tab's context, so further tabs
+for the same user are opened by switching to one of theirs first.
+
+Examples:
+  webctl context new example.com/login              # Log in as user A here
+  webctl context new example.com/login --incognito  # ...and as user B here
+  webctl context                                    # List contexts and tabs
+  webctl context close 5F1A                         # Close by ID prefix`,
+	Args: cobra.NoArgs,
+	RunE: runContextList,
+}
+
+var contextNewCmd = &cobra.Command{
+	Use:   "new [url]",
+	Short: "Open a tab in a new browser context",
+	Long: `Creates a browser context with its own cookies and storage, opens a tab in
+it (about:blank if no URL is given), and makes that tab active. URLs get the
+same protocol auto-detection as 'navigate'.
+
+With --incognito, the context is closed when its last tab closes.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runContextNew,
+}
+
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List browser contexts and their tabs",
+	Args:  cobra.NoArgs,
+	RunE:  runContextList,
+}
+
+var contextCloseCmd = &cobra.Command{
+	Use:   "close <id>",
+	Short: "Close a browser context and its tabs",
+	Long: `Closes the context whose ID starts with <id>, with all its tabs, discarding its
+cookies and storage. The default context cannot be closed, nor a context
+holding the only open tabs.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContextClose,
+}
+
+var contextIncognito bool
+
+func init() {
+	contextNewCmd.Flags().BoolVar(&contextIncognito, "incognito", false, "Close the context when its last tab closes")
+	contextCmd.AddCommand(contextNewCmd, contextListCmd, contextCloseCmd)
+	rootCmd.AddCommand(contextCmd)
+}
+
+// executeContext sends a "context" request and returns its response data,
+// or a printed error.
+func executeContext(params ipc.ContextParams) (json.RawMessage, error) {
+	if !execFactory.IsDaemonRunning() {
+		return nil, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return nil, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, outputError(err.Error())
+	}
+
+	debugRequest("context", fmt.Sprintf("action=%s id=%q url=%q incognito=%v", params.Action, params.ID, params.URL, params.Incognito))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "context", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return nil, outputError(err.Error())
+	}
+	if !resp.OK {
+		return nil, outputResponseError(resp)
+	}
+	return resp.Data, nil
+}
+
+func runContextList(cmd *cobra.Command, args []string) error {
+	t := startTimer("context list")
+	defer t.log()
+
+	raw, err := executeContext(ipc.ContextParams{Action: "list"})
+	if err != nil {
+		return err
+	}
+	return outputContexts(raw)
+}
+
+func runContextNew(cmd *cobra.Command, args []string) error {
+	t := startTimer("context new")
+	defer t.log()
+
+	url := ""
+	if len(args) == 1 {
+		url = normalizeURL(args[0])
+	}
+	debugParam("url=%q incognito=%v", url, contextIncognito)
+
+	raw, err := executeContext(ipc.ContextParams{Action: "new", URL: url, Incognito: contextIncognito})
+	if err != nil {
+		return err
+	}
+
+	var data ipc.ContextInfo
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":        true,
+			"id":        data.ID,
+			"incognito": data.Incognito,
+			"tabs":      data.Tabs,
+		})
+	}
+	_, err = fmt.Fprintln(os.Stdout, data.ID)
+	return err
+}
+
+func runContextClose(cmd *cobra.Command, args []string) error {
+	t := startTimer("context close")
+	defer t.log()
+
+	raw, err := executeContext(ipc.ContextParams{Action: "close", ID: args[0]})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputContexts(raw)
+	}
+	return outputSuccess(nil)
+}
+
+// outputContexts writes a "context" list response.
+func outputContexts(raw json.RawMessage) error {
+	var data ipc.ContextData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return outputError(err.Error())
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":       true,
+			"contexts": data.Contexts,
+		})
+	}
+	return format.Contexts(os.Stdout, data.Contexts, format.NewOutputOptions(JSONOutput, NoColor))
+}
@@ -23,6 +23,7 @@ Default behavior (no subcommand):
 
 Subcommands:
   save [path]       Save cookies to file (temp dir if no path given)
+  diff <saved.json> Compare cookies against a saved capture
   set <name> <value>  Set a cookie (mutation)
   delete <name>     Delete a cookie (mutation)
 
@@ -55,11 +56,15 @@ Mutation subcommands:
 Response formats:
   Default:  session | abc123 | .example.com | / | Session | Secure, HttpOnly
   Save:     /tmp/webctl-cookies/25-12-28-143052-123-cookies.json
+  Records:  --output jsonl|yaml|csv|table renders one cookie per record
+            (csv/table columns: name, value, domain, path, expires, httpOnly,
+            secure, sameSite)
 
 Error cases:
   - "No matches found" - find text not in cookies
   - "daemon not running" - start daemon first with: webctl start`,
-	RunE: runCookiesDefault,
+	RunE:        runCookiesDefault,
+	Annotations: map[string]string{recordsAnnotation: ""},
 }
 
 var cookiesSaveCmd = &cobra.Command{
@@ -203,7 +208,7 @@ func init() {
 	cookiesDeleteCmd.Flags().String("domain", "", "Cookie domain (required if ambiguous)")
 
 	// Add all subcommands
-	cookiesCmd.AddCommand(cookiesSaveCmd, cookiesSetCmd, cookiesDeleteCmd)
+	cookiesCmd.AddCommand(cookiesSaveCmd, cookiesSetCmd, cookiesDeleteCmd, cookiesDiffCmd)
 
 	rootCmd.AddCommand(cookiesCmd)
 }
@@ -215,11 +220,11 @@ func runCookiesDefault(cmd *cobra.Command, args []string) error {
 
 	// Validate that no arguments were provided (catches unknown subcommands)
 	if len(args) > 0 {
-		return outputError(fmt.Sprintf("unknown command %q for \"webctl cookies\"", args[0]))
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("unknown command %q for \"webctl cookies\"", args[0]))
 	}
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Get cookies from daemon
@@ -231,6 +236,10 @@ func runCookiesDefault(cmd *cobra.Command, args []string) error {
 		return outputError(err.Error())
 	}
 
+	if recordOutput() {
+		return format.Records(os.Stdout, Output, cookies, format.CookieColumns)
+	}
+
 	// JSON mode: output JSON
 	if JSONOutput {
 		result := map[string]any{
@@ -429,7 +438,7 @@ func runCookiesSet(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -470,7 +479,7 @@ func runCookiesSet(cmd *cobra.Command, args []string) error {
 	}
 
 	if !resp.OK {
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: output JSON
@@ -487,7 +496,7 @@ func runCookiesDelete(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -525,17 +534,18 @@ func runCookiesDelete(cmd *cobra.Command, args []string) error {
 					result := map[string]any{
 						"ok":      false,
 						"error":   resp.Error,
+						"code":    resp.Code,
 						"matches": data.Matches,
 					}
 					_ = outputJSON(os.Stdout, result)
 				}
-				return outputError(resp.Error)
+				return outputResponseError(resp)
 			}
 		}
 		if isNoCookieError(resp.Error) {
-			return outputNotice("No cookie found")
+			return outputCodedNotice(ipc.CodeNotFound, "No cookie found")
 		}
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: output JSON
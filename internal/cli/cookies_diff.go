@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/grantcarthew/webctl/internal/jsondiff"
+	"github.com/spf13/cobra"
+)
+
+var cookiesDiffCmd = &cobra.Command{
+	Use:   "diff <saved.json>",
+	Short: "Compare cookies against a saved capture",
+	Long: `Compares the browser's cookies against a capture made earlier with
+'cookies save' (or any JSON array of cookies), and lists the cookies added,
+removed, or changed since. Use it to check that a login, logout, or consent
+banner really set or cleared what it claims.
+
+Cookies are matched by name, domain, and path. A cookie is changed when its
+value, expiry, secure, httpOnly, or sameSite attribute differs.
+
+The --domain, --name, and --find filters apply to both sides. Like diff(1),
+the exit code is 0 when nothing changed and 1 when something did.
+
+Flags:
+  --ignore-expires   Do not report cookies whose only change is their expiry
+
+Examples:
+  cookies save ./before.json && webctl click "#accept" && webctl cookies diff ./before.json
+  cookies diff ./logged-out.json --domain example.com
+  cookies diff ./before.json --json | jq '.changes[] | select(.op == "added") | .name'
+
+Text output:
+  + session=abc123; domain=app.test; path=/
+  - consent_pending=1; domain=app.test; path=/
+  ~ theme; domain=app.test; path=/: value "dark" → "light"
+
+JSON output:
+  {"ok": false, "identical": false, "changes": [{"op": "added", "name": "session",
+   "domain": "app.test", "path": "/", "cookie": {...}}]}
+
+Error cases:
+  - "no cookies in <file>" - the file is not a cookies capture`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCookiesDiff,
+}
+
+func init() {
+	cookiesDiffCmd.Flags().Bool("ignore-expires", false, "Do not report cookies whose only change is their expiry")
+}
+
+// cookieChange is one difference found by 'cookies diff'.
+type cookieChange struct {
+	Op     jsondiff.Op `json:"op"`
+	Name   string      `json:"name"`
+	Domain string      `json:"domain"`
+	Path   string      `json:"path"`
+	// Fields lists the attributes that differ, for a changed cookie.
+	Fields []cookieFieldChange `json:"fields,omitempty"`
+	// Cookie is the current cookie, or the saved one if it was removed.
+	Cookie ipc.Cookie `json:"cookie"`
+}
+
+// cookieFieldChange is one attribute of a changed cookie.
+type cookieFieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+func runCookiesDiff(cmd *cobra.Command, args []string) error {
+	t := startTimer("cookies diff")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	ignoreExpires, _ := cmd.Flags().GetBool("ignore-expires")
+
+	saved, err := readSavedCookies(args[0])
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	current, err := getCookiesFromDaemon(cmd)
+	if err != nil && !errors.Is(err, ErrNoMatches) {
+		return outputError(err.Error())
+	}
+	saved = filterSavedCookies(cmd, saved)
+
+	changes := diffCookies(saved, current, ignoreExpires)
+
+	if JSONOutput {
+		if changes == nil {
+			changes = []cookieChange{}
+		}
+		if err := outputJSON(os.Stdout, map[string]any{
+			"ok":        len(changes) == 0,
+			"identical": len(changes) == 0,
+			"changes":   changes,
+		}); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range changes {
+			switch c.Op {
+			case jsondiff.Added:
+				fmt.Printf("+ %s=%s; %s\n", c.Name, c.Cookie.Value, cookieScope(c.Cookie))
+			case jsondiff.Removed:
+				fmt.Printf("- %s=%s; %s\n", c.Name, c.Cookie.Value, cookieScope(c.Cookie))
+			default:
+				fields := make([]string, len(c.Fields))
+				for i, f := range c.Fields {
+					fields[i] = fmt.Sprintf("%s %s → %s", f.Field, f.From, f.To)
+				}
+				fmt.Printf("~ %s; %s: %s\n", c.Name, cookieScope(c.Cookie), strings.Join(fields, "; "))
+			}
+		}
+	}
+
+	if len(changes) > 0 {
+		return printedError{err: errors.New("cookies differ")}
+	}
+	return nil
+}
+
+// readSavedCookies loads a cookies capture: the envelope written by
+// 'cookies save' and 'cookies --json', or a bare array of cookies.
+func readSavedCookies(path string) ([]ipc.Cookie, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Cookies []ipc.Cookie `json:"cookies"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err == nil && envelope.Cookies != nil {
+		return envelope.Cookies, nil
+	}
+	var cookies []ipc.Cookie
+	if err := json.Unmarshal(raw, &cookies); err == nil && cookies != nil {
+		return cookies, nil
+	}
+	return nil, fmt.Errorf("no cookies in %s (expected the output of 'cookies save')", path)
+}
+
+// filterSavedCookies applies the --domain, --name, and --find filters to the
+// saved side, as getCookiesFromDaemon does to the current one.
+func filterSavedCookies(cmd *cobra.Command, cookies []ipc.Cookie) []ipc.Cookie {
+	flag := func(name string) string {
+		v, _ := cmd.Flags().GetString(name)
+		if v == "" && cmd.Parent() != nil {
+			v, _ = cmd.Parent().PersistentFlags().GetString(name)
+		}
+		return v
+	}
+	if domain := flag("domain"); domain != "" {
+		cookies = filterCookiesByDomain(cookies, domain)
+	}
+	if name := flag("name"); name != "" {
+		cookies = filterCookiesByName(cookies, name)
+	}
+	if find := flag("find"); find != "" {
+		cookies = filterCookiesByText(cookies, find)
+	}
+	return cookies
+}
+
+// diffCookies compares two cookie lists by name, domain, and path. Changes
+// are sorted by domain, name, and path.
+func diffCookies(saved, current []ipc.Cookie, ignoreExpires bool) []cookieChange {
+	key := func(c ipc.Cookie) string { return c.Name + "\x00" + c.Domain + "\x00" + c.Path }
+	before := make(map[string]ipc.Cookie, len(saved))
+	for _, c := range saved {
+		before[key(c)] = c
+	}
+
+	var changes []cookieChange
+	seen := make(map[string]bool, len(current))
+	for _, c := range current {
+		k := key(c)
+		seen[k] = true
+		old, ok := before[k]
+		if !ok {
+			changes = append(changes, cookieChange{Op: jsondiff.Added, Name: c.Name, Domain: c.Domain, Path: c.Path, Cookie: c})
+			continue
+		}
+		if fields := cookieFieldChanges(old, c, ignoreExpires); len(fields) > 0 {
+			changes = append(changes, cookieChange{Op: jsondiff.Changed, Name: c.Name, Domain: c.Domain, Path: c.Path, Fields: fields, Cookie: c})
+		}
+	}
+	for _, c := range saved {
+		if !seen[key(c)] {
+			changes = append(changes, cookieChange{Op: jsondiff.Removed, Name: c.Name, Domain: c.Domain, Path: c.Path, Cookie: c})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Path < b.Path
+	})
+	return changes
+}
+
+// cookieFieldChanges lists the attributes that differ between two versions
+// of a cookie.
+func cookieFieldChanges(a, b ipc.Cookie, ignoreExpires bool) []cookieFieldChange {
+	var fields []cookieFieldChange
+	add := func(field, from, to string) {
+		if from != to {
+			fields = append(fields, cookieFieldChange{Field: field, From: from, To: to})
+		}
+	}
+	add("value", strconv.Quote(a.Value), strconv.Quote(b.Value))
+	if !ignoreExpires {
+		add("expires", cookieExpiry(a), cookieExpiry(b))
+	}
+	add("secure", strconv.FormatBool(a.Secure), strconv.FormatBool(b.Secure))
+	add("httpOnly", strconv.FormatBool(a.HTTPOnly), strconv.FormatBool(b.HTTPOnly))
+	add("sameSite", cookieSameSite(a), cookieSameSite(b))
+	return fields
+}
+
+// cookieExpiry formats a cookie's expiry to the second, or "session".
+func cookieExpiry(c ipc.Cookie) string {
+	if c.Session || c.Expires <= 0 {
+		return "session"
+	}
+	return time.Unix(int64(c.Expires), 0).UTC().Format(time.RFC3339)
+}
+
+// cookieSameSite returns a cookie's SameSite policy, or "unset".
+func cookieSameSite(c ipc.Cookie) string {
+	if c.SameSite == "" {
+		return "unset"
+	}
+	return c.SameSite
+}
+
+// cookieScope formats a cookie's domain and path for 'cookies diff'.
+func cookieScope(c ipc.Cookie) string {
+	return fmt.Sprintf("domain=%s; path=%s", c.Domain, c.Path)
+}
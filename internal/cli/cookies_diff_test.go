@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func cookiesDiffExecutor(cookies ...ipc.Cookie) *mockExecutor {
+	return &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.SuccessResponse(ipc.CookiesData{Cookies: cookies}), nil
+	}}
+}
+
+func writeSavedCookies(t *testing.T, v any) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	raw, _ := json.Marshal(v)
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newCookiesDiffTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "diff", RunE: runCookiesDiff}
+	cmd.Flags().Bool("ignore-expires", false, "")
+	cmd.Flags().String("find", "", "")
+	cmd.Flags().String("domain", "", "")
+	cmd.Flags().String("name", "", "")
+	return cmd
+}
+
+func TestRunCookiesDiff(t *testing.T) {
+	saved := writeSavedCookies(t, map[string]any{"ok": true, "count": 3, "cookies": []ipc.Cookie{
+		{Name: "consent_pending", Value: "1", Domain: "app.test", Path: "/", Session: true},
+		{Name: "theme", Value: "dark", Domain: "app.test", Path: "/", Session: true},
+		{Name: "id", Value: "x", Domain: "other.test", Path: "/", Session: true},
+	}})
+	exec := cookiesDiffExecutor(
+		ipc.Cookie{Name: "theme", Value: "light", Domain: "app.test", Path: "/", Session: true, SameSite: "Lax"},
+		ipc.Cookie{Name: "session", Value: "abc123", Domain: "app.test", Path: "/", Session: true},
+		ipc.Cookie{Name: "id", Value: "x", Domain: "other.test", Path: "/", Session: true},
+	)
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCookiesDiff(newCookiesDiffTestCmd(), []string{saved})
+	})
+	if err == nil || !IsPrintedError(err) {
+		t.Fatalf("err = %v, want a printed difference", err)
+	}
+	want := `- consent_pending=1; domain=app.test; path=/
++ session=abc123; domain=app.test; path=/
+~ theme; domain=app.test; path=/: value "dark" → "light"; sameSite unset → Lax
+`
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestRunCookiesDiff_IgnoreExpiresAndFilter(t *testing.T) {
+	enableJSONOutput(t)
+	saved := writeSavedCookies(t, []ipc.Cookie{
+		{Name: "_ga", Value: "GA1", Domain: ".app.test", Path: "/", Expires: 1700000000},
+		{Name: "id", Value: "x", Domain: "other.test", Path: "/", Session: true},
+	})
+	exec := cookiesDiffExecutor(
+		ipc.Cookie{Name: "_ga", Value: "GA1", Domain: ".app.test", Path: "/", Expires: 1800000000},
+	)
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := newCookiesDiffTestCmd()
+	_ = cmd.Flags().Set("ignore-expires", "true")
+	_ = cmd.Flags().Set("domain", "app.test")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCookiesDiff(cmd, []string{saved})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	var result struct {
+		Identical bool           `json:"identical"`
+		Changes   []cookieChange `json:"changes"`
+	}
+	if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+		t.Fatalf("invalid JSON: %v\n%s", jerr, out)
+	}
+	if !result.Identical || len(result.Changes) != 0 {
+		t.Errorf("result = %+v, want identical", result)
+	}
+}
+
+func TestRunCookiesDiff_NotACapture(t *testing.T) {
+	saved := writeSavedCookies(t, map[string]any{"ok": true, "entries": []any{}})
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: cookiesDiffExecutor()})()
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runCookiesDiff(newCookiesDiffTestCmd(), []string{saved})
+	})
+	if err == nil {
+		t.Error("expected an error for a file without cookies")
+	}
+}
@@ -817,3 +817,34 @@ func TestResolveSavePath_CookiesTemp(t *testing.T) {
 		t.Errorf("expected path to end with -cookies.json, got %s", path)
 	}
 }
+
+func TestRunCookiesDefault_CSVOutput(t *testing.T) {
+	enableJSONOutput(t)
+	old := Output
+	Output = "csv"
+	t.Cleanup(func() { Output = old })
+
+	cookiesJSON, _ := json.Marshal(ipc.CookiesData{
+		Cookies: []ipc.Cookie{{Name: "theme", Value: "dark", Domain: "example.com", Path: "/", Session: true}},
+		Count:   1,
+	})
+	restore := setMockFactory(&mockFactory{
+		daemonRunning: true,
+		executor: &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			return ipc.Response{OK: true, Data: cookiesJSON}, nil
+		}},
+	})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCookiesDefault(cookiesCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "name,value,domain,path,expires,httpOnly,secure,sameSite\ntheme,dark,example.com,/,session,false,false,\n"
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
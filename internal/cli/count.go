@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var countCmd = &cobra.Command{
+	Use:   "count <selector>",
+	Short: "Count elements matching a selector",
+	Long: `Counts the elements matching the CSS selector and prints the number.
+
+Zero matches is a normal result and exits 0 unless a threshold says otherwise.
+With --min and/or --max the exit code reports whether the count is in range,
+which makes count usable directly in shell conditionals and CI assertions.
+
+Flags:
+  --min <n>         Fail (exit 1) if fewer than n elements match
+  --max <n>         Fail (exit 1) if more than n elements match
+  --artifacts <dir> When a threshold fails, save a screenshot and the page
+                    HTML to dir as <time>-count.png and <time>-count.html
+
+Examples:
+  count "li.result"                     # Print the number of matches
+  count "#banner" --min 1               # Assert the element exists
+  count ".error" --max 0                # Assert no errors are shown
+  count "tr" --min 10 --max 50          # Assert a range
+  count ".error" --max 0 --artifacts ./artifacts   # Keep evidence in CI
+
+Common patterns:
+  # Branch on existence
+  if webctl count ".cookie-banner" --min 1 >/dev/null; then
+    webctl click ".cookie-banner .accept"
+  fi
+
+Output:
+  3 (text) or {"ok": true, "count": 3} (JSON)
+
+When a threshold fails the count is still printed to stdout, a message goes to
+stderr, and the exit code is 1. In JSON mode the single stdout object has
+"ok": false and a "message", and "artifacts" lists any files saved.
+
+Error cases:
+  - "count 0 is below --min 1" - too few matches
+  - "count 4 is above --max 0" - too many matches
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCount,
+}
+
+func init() {
+	countCmd.Flags().Int("min", 0, "Fail if fewer elements match")
+	countCmd.Flags().Int("max", 0, "Fail if more elements match")
+	countCmd.Flags().String("artifacts", "", "Directory to save a screenshot and HTML to when a threshold fails")
+	rootCmd.AddCommand(countCmd)
+}
+
+func runCount(cmd *cobra.Command, args []string) error {
+	t := startTimer("count")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	selector := args[0]
+	minSet := cmd.Flags().Changed("min")
+	maxSet := cmd.Flags().Changed("max")
+	minCount, _ := cmd.Flags().GetInt("min")
+	maxCount, _ := cmd.Flags().GetInt("max")
+	artifacts, _ := cmd.Flags().GetString("artifacts")
+	debugParam("selector=%q min=%d(set=%v) max=%d(set=%v)", selector, minCount, minSet, maxCount, maxSet)
+
+	if minSet && maxSet && minCount > maxCount {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("--min %d is greater than --max %d", minCount, maxCount))
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.CountParams{
+		Selector: selector,
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("count", fmt.Sprintf("selector=%q", selector))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "count",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.CountData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	var failure string
+	switch {
+	case minSet && data.Count < minCount:
+		failure = fmt.Sprintf("count %d is below --min %d", data.Count, minCount)
+	case maxSet && data.Count > maxCount:
+		failure = fmt.Sprintf("count %d is above --max %d", data.Count, maxCount)
+	}
+
+	var saved []string
+	if failure != "" && artifacts != "" {
+		saved = saveFailureArtifacts(exec, artifacts, "count")
+	}
+
+	// JSON mode: a single object on stdout, ok reflects the threshold check
+	if JSONOutput {
+		result := map[string]any{
+			"ok":    failure == "",
+			"count": data.Count,
+		}
+		if failure != "" {
+			result["message"] = failure
+		}
+		if len(saved) > 0 {
+			result["artifacts"] = saved
+		}
+		if err := outputJSON(os.Stdout, result); err != nil {
+			return err
+		}
+		if failure != "" {
+			return printedError{err: fmt.Errorf("%s", failure)}
+		}
+		return nil
+	}
+
+	// Text mode: the count on stdout, threshold failures on stderr
+	fmt.Println(data.Count)
+	if failure != "" {
+		fmt.Fprintln(os.Stderr, failure)
+		if len(saved) > 0 {
+			fmt.Fprintf(os.Stderr, "Artifacts: %s\n", strings.Join(saved, ", "))
+		}
+		return printedError{err: fmt.Errorf("%s", failure)}
+	}
+	return nil
+}
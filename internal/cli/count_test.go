@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// setCountFlag sets a count threshold flag and restores it after the test.
+func setCountFlag(t *testing.T, name, value string) {
+	t.Helper()
+	if err := countCmd.Flags().Set(name, value); err != nil {
+		t.Fatalf("set %s=%s: %v", name, value, err)
+	}
+	t.Cleanup(func() {
+		f := countCmd.Flags().Lookup(name)
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestRunCount_Text(t *testing.T) {
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: expectExecutor(t, "count", ipc.SuccessResponse(ipc.CountData{Count: 0}))})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCount(countCmd, []string{".missing"})
+	})
+	if err != nil {
+		t.Fatalf("zero matches without thresholds should succeed: %v", err)
+	}
+	if out != "0\n" {
+		t.Errorf("output = %q, want %q", out, "0\n")
+	}
+}
+
+func TestRunCount_Thresholds(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		flags   map[string]string
+		wantErr string
+	}{
+		{name: "min satisfied", count: 2, flags: map[string]string{"min": "1"}},
+		{name: "min zero explicit", count: 0, flags: map[string]string{"min": "0"}},
+		{name: "below min", count: 0, flags: map[string]string{"min": "1"}, wantErr: "count 0 is below --min 1"},
+		{name: "above max", count: 4, flags: map[string]string{"max": "0"}, wantErr: "count 4 is above --max 0"},
+		{name: "in range", count: 10, flags: map[string]string{"min": "10", "max": "50"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enableJSONOutput(t)
+			for name, value := range tt.flags {
+				setCountFlag(t, name, value)
+			}
+			restore := setMockFactory(&mockFactory{daemonRunning: true, executor: expectExecutor(t, "count", ipc.SuccessResponse(ipc.CountData{Count: tt.count}))})
+			defer restore()
+
+			var err error
+			out := captureStream(t, &os.Stdout, func() {
+				err = runCount(countCmd, []string{"li"})
+			})
+
+			var result map[string]any
+			if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+				t.Fatalf("failed to parse response: %v", jerr)
+			}
+			if result["count"] != float64(tt.count) {
+				t.Errorf("count = %v, want %d", result["count"], tt.count)
+			}
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if result["ok"] != true {
+					t.Errorf("expected ok=true, got %v", result["ok"])
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+			if result["ok"] != false || result["message"] != tt.wantErr {
+				t.Errorf("unexpected result: %v", result)
+			}
+		})
+	}
+}
+
+func TestRunCount_MinGreaterThanMax(t *testing.T) {
+	enableJSONOutput(t)
+	setCountFlag(t, "min", "5")
+	setCountFlag(t, "max", "1")
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			t.Error("daemon should not be called with invalid thresholds")
+			return ipc.Response{}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runCount(countCmd, []string{"li"})
+	})
+	if err == nil {
+		t.Error("expected error when --min exceeds --max")
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -28,6 +31,9 @@ Subcommands:
   get <sel> <prop>  Get single CSS property value
   inline <sel>      Get inline style attributes
   matched <sel>     Get matched CSS rules from stylesheets
+  list              List stylesheets with URL, size, and rule count
+  dump <sheet>      Dump one stylesheet's source (--all for every sheet)
+  unused            Report style rules that match nothing on the page
 
 Universal flags (work with default/save modes):
   --select, -s      Filter CSS rules by selector pattern
@@ -54,6 +60,13 @@ Element-specific operations:
   css inline "[style]"                 # Inline style attributes
   css matched "#main"                  # Matched CSS rules for element
 
+Stylesheet operations:
+  css list                             # Stylesheets with size and rule count
+  css dump 1                           # Source of stylesheet #1 to stdout
+  css dump app.css ./app.css           # Stylesheet by URL substring to file
+  css dump --all ./css-archive/        # Every stylesheet, one file each
+  css unused                           # Unused rules per stylesheet
+
 Response formats:
   Default:  body { margin: 0; ... } (to stdout)
   Save:     /tmp/webctl-css/25-12-28-143052-123-example.css
@@ -61,6 +74,9 @@ Response formats:
   Get:      rgb(0,0,0) (to stdout)
   Inline:   style attribute content (multiple with -- separators)
   Matched:  /* selector */ property: value; (with -- separators)
+  List:     1  48.2KB  612 rules  https://example.com/app.css
+  Dump:     stylesheet source (stdout) or saved file paths
+  Unused:   per-stylesheet unused bytes/rules and selectors (with -- separators)
 
 Error cases:
   - "selector matched no elements" - nothing matches selector
@@ -240,6 +256,105 @@ Common patterns:
 	RunE: runCSSMatched,
 }
 
+var cssListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stylesheets with URL, size, and rule count",
+	Long: `Lists every stylesheet attached to the page using the CDP CSS domain, including
+inline <style> elements and constructed stylesheets. User-agent stylesheets are
+excluded.
+
+Size is the byte length of the stylesheet source. Rules counts top-level rules;
+an @media block counts as one rule. The index is the handle for css dump and is
+stable until the page adds or removes stylesheets.
+
+Examples:
+  css list
+  css list --json | jq '.styleSheets[] | select(.size > 100000) | .url'
+
+Output:
+  1  48.2KB  612 rules  https://example.com/css/app.css
+  2  312B    4 rules    <inline>
+  3  1.1KB   20 rules   <constructed>`,
+	Args: cobra.NoArgs,
+	RunE: runCSSList,
+}
+
+var cssDumpCmd = &cobra.Command{
+	Use:   "dump <stylesheet|--all> [path]",
+	Short: "Dump stylesheet source to stdout or file",
+	Long: `Dumps the source text of a stylesheet as the browser parsed it, for archiving
+and diffing the page's effective CSS across deployments.
+
+The stylesheet is picked by its index from css list, its CDP stylesheet ID, or
+a substring matching exactly one stylesheet URL. With --all, every stylesheet
+is written to its own file named NN-<name>.css.
+
+Single stylesheet:
+  No path           Source to stdout
+  path/             Save to directory with NN-<name>.css filename
+  path              Save to exact file
+
+All stylesheets (--all):
+  No path           Save to /tmp/webctl-css/<timestamp>/
+  path              Save into this directory
+
+Source text is written as-is (not formatted).
+
+Examples:
+  css dump 1                           # First stylesheet to stdout
+  css dump app.css                     # Stylesheet whose URL contains app.css
+  css dump 2 ./inline.css              # Save to file
+  css dump --all                       # All to temp dir
+  css dump --all ./css-v2/             # All to directory
+
+Common patterns:
+  # Diff effective CSS between deployments
+  webctl navigate https://staging.example.com && webctl css dump --all ./staging/
+  webctl navigate https://example.com && webctl css dump --all ./prod/
+  diff -r ./staging ./prod
+
+Error cases:
+  - "no stylesheet matches 'x'" - no URL contains the text
+  - "'x' matches 3 stylesheets, use the index from css list" - ambiguous
+  - "page has no stylesheets" - nothing to dump`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: runCSSDump,
+}
+
+var cssUnusedCmd = &cobra.Command{
+	Use:   "unused",
+	Short: "Report unused CSS rules per stylesheet",
+	Long: `Reports style rules that do not match anything in the current page state,
+per stylesheet, with byte counts. Uses CDP CSS rule usage tracking.
+
+A rule counts as used if it applies to the page as it is right now. Rules for
+states that are not currently present (:hover, open menus, other routes, other
+breakpoints) are reported as unused, so put the page into the state you care
+about first, and treat the report as a starting point rather than a delete list.
+
+Sizes are bytes of rule source (selector and declarations). Only style rules
+are tracked; @font-face, @keyframes and similar at-rules are not counted.
+
+Flags:
+  --summary         Only print per-stylesheet totals, not selectors
+
+Examples:
+  css unused
+  css unused --summary
+  css unused --json | jq '.unused[] | {url, unusedBytes}'
+
+Output:
+  https://example.com/css/app.css
+  unused: 18.2KB of 48.2KB (37.8%), 210 of 612 rules
+  .legacy-banner  120B
+  .modal.open .close  64B
+  --
+  <inline>
+  unused: 0B of 312B (0.0%), 0 of 4 rules`,
+	Args: cobra.NoArgs,
+	RunE: runCSSUnused,
+}
+
 func init() {
 	// Universal flags on root command (inherited by default/save subcommands)
 	cssCmd.PersistentFlags().StringP("select", "s", "", "Filter CSS rules by selector pattern")
@@ -249,8 +364,11 @@ func init() {
 	cssCmd.PersistentFlags().IntP("context", "C", 0, "Show N lines before and after each match (requires --find)")
 	cssCmd.PersistentFlags().Bool("raw", false, "Skip CSS formatting")
 
+	cssDumpCmd.Flags().Bool("all", false, "Dump every stylesheet to its own file")
+	cssUnusedCmd.Flags().Bool("summary", false, "Only print per-stylesheet totals")
+
 	// Add all subcommands
-	cssCmd.AddCommand(cssSaveCmd, cssComputedCmd, cssGetCmd, cssInlineCmd, cssMatchedCmd)
+	cssCmd.AddCommand(cssSaveCmd, cssComputedCmd, cssGetCmd, cssInlineCmd, cssMatchedCmd, cssListCmd, cssDumpCmd, cssUnusedCmd)
 
 	rootCmd.AddCommand(cssCmd)
 }
@@ -262,11 +380,11 @@ func runCSSDefault(cmd *cobra.Command, args []string) error {
 
 	// Validate that no arguments were provided (catches unknown subcommands)
 	if len(args) > 0 {
-		return outputError(fmt.Sprintf("unknown command %q for \"webctl css\"", args[0]))
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("unknown command %q for \"webctl css\"", args[0]))
 	}
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Get CSS from daemon
@@ -276,7 +394,7 @@ func runCSSDefault(cmd *cobra.Command, args []string) error {
 			return outputNotice("No matches found")
 		}
 		if errors.Is(err, ErrNoElements) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 		}
 		if errors.Is(err, ErrNoRules) {
 			return outputNotice("No rules found")
@@ -314,7 +432,7 @@ func runCSSComputed(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -341,9 +459,9 @@ func runCSSComputed(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoElementsError(resp.Error) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 		}
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// Parse CSS data
@@ -367,7 +485,7 @@ func runCSSComputed(cmd *cobra.Command, args []string) error {
 
 func runCSSGet(cmd *cobra.Command, args []string) error {
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -395,15 +513,15 @@ func runCSSGet(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoElementsError(resp.Error) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 		}
 		if resp.Error == "property not found" {
-			return outputNotice("Property not found")
+			return outputCodedNotice(ipc.CodeNotFound, "Property not found")
 		}
 		if resp.Error == "no value" {
 			return outputNotice("No value")
 		}
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// Parse CSS data
@@ -427,7 +545,7 @@ func runCSSGet(cmd *cobra.Command, args []string) error {
 
 func runCSSInline(cmd *cobra.Command, args []string) error {
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -454,9 +572,9 @@ func runCSSInline(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoElementsError(resp.Error) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 		}
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// Parse CSS data
@@ -494,7 +612,7 @@ func runCSSInline(cmd *cobra.Command, args []string) error {
 
 func runCSSMatched(cmd *cobra.Command, args []string) error {
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -521,9 +639,9 @@ func runCSSMatched(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoElementsError(resp.Error) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 		}
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// Parse CSS data
@@ -550,6 +668,246 @@ func runCSSMatched(cmd *cobra.Command, args []string) error {
 	return format.MatchedRules(os.Stdout, data.Matched)
 }
 
+// executeCSSStyleSheets sends a stylesheet list or dump request and returns the
+// stylesheets from the response.
+func executeCSSStyleSheets(params ipc.CSSParams) ([]ipc.CSSStyleSheet, error) {
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	debugRequest("css", fmt.Sprintf("action=%s sheet=%q", params.Action, params.Sheet))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "css",
+		Params: raw,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return nil, err
+	}
+	if !resp.OK {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	var data ipc.CSSData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, err
+	}
+	return data.StyleSheets, nil
+}
+
+func runCSSList(cmd *cobra.Command, args []string) error {
+	t := startTimer("css list")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	sheets, err := executeCSSStyleSheets(ipc.CSSParams{Action: "list"})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	// JSON mode: output JSON
+	if JSONOutput {
+		if sheets == nil {
+			sheets = []ipc.CSSStyleSheet{}
+		}
+		result := map[string]any{
+			"ok":          true,
+			"styleSheets": sheets,
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	if len(sheets) == 0 {
+		return outputNotice("No stylesheets found")
+	}
+
+	// Text mode: one line per stylesheet
+	return format.StyleSheets(os.Stdout, sheets)
+}
+
+func runCSSDump(cmd *cobra.Command, args []string) error {
+	t := startTimer("css dump")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	all, _ := cmd.Flags().GetBool("all")
+
+	// With --all the only argument is the optional directory
+	var sheet, outPath string
+	switch {
+	case all && len(args) > 1:
+		return outputCodedError(ipc.CodeInvalidArgs, "--all takes at most one argument: the output directory")
+	case all:
+		if len(args) == 1 {
+			outPath = args[0]
+		}
+	case len(args) == 0:
+		return outputCodedError(ipc.CodeInvalidArgs, "stylesheet required (index, ID, or URL substring), or use --all")
+	default:
+		sheet = args[0]
+		if len(args) == 2 {
+			outPath = args[1]
+		}
+	}
+	debugParam("sheet=%q all=%v path=%q", sheet, all, outPath)
+
+	sheets, err := executeCSSStyleSheets(ipc.CSSParams{Action: "dump", Sheet: sheet})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	// Single stylesheet without a path: source to stdout
+	if !all && outPath == "" {
+		if JSONOutput {
+			return outputJSON(os.Stdout, map[string]any{
+				"ok":         true,
+				"styleSheet": sheets[0],
+			})
+		}
+		fmt.Print(sheets[0].Text)
+		if !strings.HasSuffix(sheets[0].Text, "\n") {
+			fmt.Println()
+		}
+		return nil
+	}
+
+	var paths []string
+	if all {
+		dir := outPath
+		if dir == "" {
+			now := time.Now()
+			dir = filepath.Join("/tmp/webctl-css", fmt.Sprintf("%s-%03d", now.Format("06-01-02-150405"), now.Nanosecond()/int(time.Millisecond)))
+		}
+		for _, s := range sheets {
+			p := filepath.Join(dir, styleSheetFilename(s))
+			if err := writeSaveFile(p, s.Text); err != nil {
+				return outputError(err.Error())
+			}
+			paths = append(paths, p)
+		}
+	} else {
+		p := outPath
+		if strings.HasSuffix(p, string(os.PathSeparator)) || strings.HasSuffix(p, "/") {
+			p = filepath.Join(p, styleSheetFilename(sheets[0]))
+		}
+		if err := writeSaveFile(p, sheets[0].Text); err != nil {
+			return outputError(err.Error())
+		}
+		paths = append(paths, p)
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":    true,
+			"paths": paths,
+		})
+	}
+
+	for _, p := range paths {
+		if err := format.FilePath(os.Stdout, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runCSSUnused(cmd *cobra.Command, args []string) error {
+	t := startTimer("css unused")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	summary, _ := cmd.Flags().GetBool("summary")
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.CSSParams{Action: "unused"})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("css", "action=unused")
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "css",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.CSSData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	// JSON mode: output JSON
+	if JSONOutput {
+		unused := data.Unused
+		if unused == nil {
+			unused = []ipc.CSSUnusedSheet{}
+		}
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":     true,
+			"unused": unused,
+		})
+	}
+
+	if len(data.Unused) == 0 {
+		return outputNotice("No stylesheets found")
+	}
+
+	// Text mode: one block per stylesheet with -- separators
+	return format.UnusedCSS(os.Stdout, data.Unused, summary)
+}
+
+// styleSheetFilename names a dumped stylesheet NN-<name>.css, where name is the
+// normalized URL basename, or "inline"/"constructed" for sheets without a URL.
+func styleSheetFilename(s ipc.CSSStyleSheet) string {
+	name := "constructed"
+	switch {
+	case s.URL != "":
+		base := s.URL
+		if u, err := url.Parse(s.URL); err == nil && u.Path != "" {
+			base = path.Base(u.Path)
+		}
+		name = normalizeTitle(strings.TrimSuffix(base, ".css"))
+	case s.Inline:
+		name = "inline"
+	}
+	return fmt.Sprintf("%02d-%s.css", s.Index, name)
+}
+
 // getCSSFromDaemon fetches CSS from daemon, applying filters and formatting
 func getCSSFromDaemon(cmd *cobra.Command) (string, error) {
 	// Try to get flags from command, falling back to parent for persistent flags
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestStyleSheetFilename(t *testing.T) {
+	tests := []struct {
+		sheet ipc.CSSStyleSheet
+		want  string
+	}{
+		{ipc.CSSStyleSheet{Index: 1, URL: "https://example.com/static/App.Main.css?v=3"}, "01-app-main.css"},
+		{ipc.CSSStyleSheet{Index: 2, Inline: true}, "02-inline.css"},
+		{ipc.CSSStyleSheet{Index: 12}, "12-constructed.css"},
+	}
+	for _, tt := range tests {
+		if got := styleSheetFilename(tt.sheet); got != tt.want {
+			t.Errorf("styleSheetFilename(%+v) = %q, want %q", tt.sheet, got, tt.want)
+		}
+	}
+}
+
+func TestRunCSSDump_Stdout(t *testing.T) {
+	exec := expectParamsExecutor(t, "css", ipc.CSSParams{Action: "dump", Sheet: "app.css"}, ipc.SuccessResponse(ipc.CSSData{StyleSheets: []ipc.CSSStyleSheet{
+		{Index: 1, URL: "https://example.com/app.css", Text: "body { margin: 0; }"},
+	}}))
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCSSDump(cssDumpCmd, []string{"app.css"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "body { margin: 0; }\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunCSSDump_All(t *testing.T) {
+	if err := cssDumpCmd.Flags().Set("all", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = cssDumpCmd.Flags().Set("all", "false")
+		cssDumpCmd.Flags().Lookup("all").Changed = false
+	})
+
+	exec := expectParamsExecutor(t, "css", ipc.CSSParams{Action: "dump"}, ipc.SuccessResponse(ipc.CSSData{StyleSheets: []ipc.CSSStyleSheet{
+		{Index: 1, URL: "https://example.com/app.css", Text: "a { color: red; }"},
+		{Index: 2, Inline: true, Text: "p { margin: 0; }"},
+	}}))
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	dir := t.TempDir()
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCSSDump(cssDumpCmd, []string{dir})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"01-app.css":    "a { color: red; }",
+		"02-inline.css": "p { margin: 0; }",
+	} {
+		got, readErr := os.ReadFile(filepath.Join(dir, name))
+		if readErr != nil {
+			t.Fatalf("expected %s to be written: %v", name, readErr)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+		if !strings.Contains(out, filepath.Join(dir, name)) {
+			t.Errorf("output should list %s, got %q", name, out)
+		}
+	}
+}
+
+func TestRunCSSDump_RequiresStylesheet(t *testing.T) {
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runCSSDump(cssDumpCmd, nil)
+	})
+	if err == nil {
+		t.Error("expected error without a stylesheet or --all")
+	}
+	if !strings.Contains(out, "stylesheet required") {
+		t.Errorf("unexpected error output: %q", out)
+	}
+}
+
+func TestRunCSSUnused_Summary(t *testing.T) {
+	if err := cssUnusedCmd.Flags().Set("summary", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = cssUnusedCmd.Flags().Set("summary", "false")
+		cssUnusedCmd.Flags().Lookup("summary").Changed = false
+	})
+
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			var params ipc.CSSParams
+			_ = json.Unmarshal(req.Params, &params)
+			if params.Action != "unused" {
+				t.Errorf("expected action=unused, got %q", params.Action)
+			}
+			raw, _ := json.Marshal(ipc.CSSData{Unused: []ipc.CSSUnusedSheet{{
+				CSSStyleSheet: ipc.CSSStyleSheet{URL: "https://example.com/app.css", Size: 1000},
+				StyleRules:    4,
+				UnusedRules:   1,
+				UnusedBytes:   100,
+				Selectors:     []ipc.CSSUnusedRule{{Selector: ".old", Bytes: 100}},
+			}}})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runCSSUnused(cssUnusedCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "https://example.com/app.css\nunused: 100B of 1000B (10.0%), 1 of 4 rules\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
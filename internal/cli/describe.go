@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe",
+	Short: "Summarize the page compactly for use as agent context",
+	Long: `Prints a compact summary of the active page, meant as context for an agent
+instead of raw HTML: the title and URL, the landmark structure, the visible
+headings, the links and buttons, and the form fields, each with a selector
+that matches exactly that element and can be passed to click, type, and the
+other commands.
+
+Only visible elements are listed. Names come from aria-labelledby, aria-label,
+<label>, alt, title, or placeholder, then the element's text, and are cut to
+80 characters. Password values are never shown.
+
+Flags:
+  --limit <n>   Maximum controls to list (default 50)
+
+Examples:
+  describe
+  describe --limit 200
+  describe --json | jq -r '.controls[] | select(.role == "button") | .selector'
+
+Text output:
+  Checkout - Shop
+  https://shop.test/checkout
+
+  Landmarks:
+    navigation "Main"  nav.primary
+    main  #content
+  Headings:
+    h1 Checkout  #title
+      h2 Shipping  #content > h2
+  Controls:
+    link "Home" -> /  a.logo
+    button "Place order"  #place-order
+  Forms:
+    form #checkout -> /api/order
+      email "Email"  #email (required)
+      select "Country"  #country = "AU" [Australia, New Zealand]
+
+JSON output:
+  {"ok": true, "url": "...", "title": "...", "landmarks": [...], "headings": [...],
+   "controls": [{"role": "button", "name": "Place order", "selector": "#place-order"}],
+   "forms": [{"selector": "#checkout", "fields": [...]}]}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start
+  - "no active session" - no browser tab is open`,
+	Args: cobra.NoArgs,
+	RunE: runDescribe,
+}
+
+func init() {
+	describeCmd.Flags().Int("limit", 50, "Maximum controls to list")
+	rootCmd.AddCommand(describeCmd)
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	t := startTimer("describe")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit <= 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--limit must be positive")
+	}
+	debugParam("limit=%d", limit)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(ipc.DescribeParams{Limit: limit})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("describe", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "describe", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.DescribeData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":           true,
+			"url":          data.URL,
+			"title":        data.Title,
+			"landmarks":    data.Landmarks,
+			"headings":     data.Headings,
+			"controls":     data.Controls,
+			"moreControls": data.MoreControls,
+			"forms":        data.Forms,
+		})
+	}
+	return format.Describe(os.Stdout, data)
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func TestRunDescribe(t *testing.T) {
+	var got ipc.DescribeParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "describe" {
+				t.Errorf("expected cmd=describe, got %s", req.Cmd)
+			}
+			if err := json.Unmarshal(req.Params, &got); err != nil {
+				t.Fatalf("failed to parse params: %v", err)
+			}
+			raw, _ := json.Marshal(ipc.DescribeData{
+				URL:      "https://example.com/",
+				Title:    "Example",
+				Controls: []ipc.DescribeControl{{Role: "button", Name: "Go", Selector: "#go"}},
+			})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	cmd := newDescribeTestCmd()
+	_ = cmd.Flags().Set("limit", "10")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runDescribe(cmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Limit != 10 {
+		t.Errorf("limit = %d, want 10", got.Limit)
+	}
+	if !strings.Contains(out, "Example\nhttps://example.com/\n") || !strings.Contains(out, `button "Go"  #go`) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestRunDescribe_JSON(t *testing.T) {
+	enableJSONOutput(t)
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			raw, _ := json.Marshal(ipc.DescribeData{
+				URL:   "https://example.com/",
+				Title: "Example",
+				Forms: []ipc.DescribeForm{{Selector: "#f", Fields: []ipc.DescribeField{{Type: "text", Name: "Name", Selector: "#name"}}}},
+			})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runDescribe(newDescribeTestCmd(), nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		OK    bool               `json:"ok"`
+		Title string             `json:"title"`
+		Forms []ipc.DescribeForm `json:"forms"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !result.OK || result.Title != "Example" || len(result.Forms) != 1 || result.Forms[0].Fields[0].Selector != "#name" {
+		t.Errorf("unexpected response: %s", out)
+	}
+}
+
+func TestRunDescribe_InvalidLimit(t *testing.T) {
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})
+	defer restore()
+
+	cmd := newDescribeTestCmd()
+	_ = cmd.Flags().Set("limit", "0")
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runDescribe(cmd, nil)
+	})
+	if err == nil {
+		t.Error("expected an error for --limit 0")
+	}
+}
+
+func newDescribeTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "describe", RunE: runDescribe}
+	cmd.Flags().Int("limit", 50, "")
+	return cmd
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var devtoolsCmd = &cobra.Command{
+	Use:   "devtools",
+	Short: "Open DevTools for the active tab",
+	Long: `Opens a DevTools window inspecting the active tab, to take over debugging by
+hand from where automation left off. The browser must have a window: switch
+a headless one with 'webctl head' first. 'webctl start --devtools' opens
+DevTools for every tab as it opens instead.
+
+Needs Chrome 129 or later; with an older browser the error names the
+inspector URL to open by hand.
+
+Examples:
+  devtools
+  head && devtools
+  tab switch admin && devtools
+
+Response:
+  DevTools opened for https://example.com/
+
+Error cases:
+  - "the browser is headless ..." - switch with: webctl head
+  - "failed to open DevTools ..." - open the inspector URL it names instead
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.NoArgs,
+	RunE: runDevTools,
+}
+
+func init() {
+	rootCmd.AddCommand(devtoolsCmd)
+}
+
+func runDevTools(cmd *cobra.Command, args []string) error {
+	t := startTimer("devtools")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	debugRequest("devtools", "")
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "devtools"})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.DevToolsData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":       true,
+			"targetId": data.TargetID,
+			"url":      data.URL,
+		})
+	}
+	_, err = fmt.Fprintf(os.Stdout, "DevTools opened for %s\n", data.URL)
+	return err
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/banners"
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var dismissBannersCmd = &cobra.Command{
+	Use:   "dismiss-banners",
+	Short: "Accept or hide cookie-consent and newsletter overlays",
+	Long: `Gets cookie-consent and newsletter overlays out of the way before screenshots
+and scraping. Each rule names the buttons that accept or close an overlay,
+of which the first visible one is clicked, and the overlay elements, which
+are hidden. Scrolling the overlay locked is unlocked again.
+
+webctl has built-in rules for common consent platforms (OneTrust,
+Cookiebot, Quantcast, Didomi, TrustArc, Usercentrics, and more). Add your
+own in ~/.config/webctl/banners.yaml, or a file given with --rules:
+
+  - name: shop-newsletter
+    hosts: [shop.example.com]     # optional: these sites and subdomains
+    click: [".newsletter .close"] # buttons; the first visible is clicked
+    hide: [".newsletter-backdrop"] # elements to hide
+
+A rule with the name of a built-in one replaces it; one with no click or
+hide selectors turns it off.
+
+--auto dismisses banners on every page load in every tab from now on,
+including tabs opened later, watching for 10s after the page is parsed for
+overlays that appear late. --auto=false turns that off. It lasts until the
+daemon stops.
+
+Flags:
+  --auto            Dismiss on every page load (--auto=false to stop)
+  --rules <path>    Rules file (default ~/.config/webctl/banners.yaml)
+
+Examples:
+  dismiss-banners
+  dismiss-banners && screenshot
+  dismiss-banners --auto
+  dismiss-banners --rules ./banners.yaml
+  dismiss-banners --auto=false
+
+Response:
+  clicked  onetrust  #onetrust-accept-btn-handler
+  hidden   onetrust  #onetrust-consent-sdk
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start
+  - "banners.yaml: ..." - the rules file is invalid`,
+	Args: cobra.NoArgs,
+	RunE: runDismissBanners,
+}
+
+func init() {
+	dismissBannersCmd.Flags().Bool("auto", false, "Dismiss on every page load (--auto=false to stop)")
+	dismissBannersCmd.Flags().String("rules", "", "Rules file (default ~/.config/webctl/banners.yaml)")
+	rootCmd.AddCommand(dismissBannersCmd)
+}
+
+func runDismissBanners(cmd *cobra.Command, args []string) error {
+	t := startTimer("dismiss-banners")
+	defer t.log()
+
+	rulesPath, _ := cmd.Flags().GetString("rules")
+	if rulesPath == "" {
+		rulesPath = banners.UserPath()
+	} else if _, err := os.Stat(rulesPath); err != nil {
+		return outputError(fmt.Sprintf("failed to read rules: %v", err))
+	}
+	userRules, err := banners.LoadFile(rulesPath)
+	if err != nil {
+		return outputCodedError(ipc.CodeInvalidArgs, err.Error())
+	}
+	params := ipc.DismissBannersParams{Rules: banners.Merge(banners.Builtin(), userRules)}
+	if cmd.Flags().Changed("auto") {
+		auto, _ := cmd.Flags().GetBool("auto")
+		params.Auto = &auto
+	}
+	debugParam("rules=%d (user %d from %q) auto=%v", len(params.Rules), len(userRules), rulesPath, params.Auto != nil && *params.Auto)
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("dismiss-banners", fmt.Sprintf("rules=%d", len(params.Rules)))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "dismiss-banners", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.DismissBannersData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":        true,
+			"dismissed": data.Dismissed,
+			"auto":      data.Auto,
+		})
+	}
+	if params.Auto != nil && !*params.Auto {
+		_, err := fmt.Fprintln(os.Stdout, "Automatic banner dismissal off")
+		return err
+	}
+	if err := format.BannerHits(os.Stdout, data.Dismissed); err != nil {
+		return err
+	}
+	if params.Auto != nil {
+		_, err := fmt.Fprintln(os.Stdout, "Dismissing banners on every page load")
+		return err
+	}
+	return nil
+}
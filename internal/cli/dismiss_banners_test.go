@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/banners"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunDismissBanners(t *testing.T) {
+	resetFlags(t, dismissBannersCmd.Flags(), "auto", "rules")
+	rules := filepath.Join(t.TempDir(), "banners.yaml")
+	if err := os.WriteFile(rules, []byte("- name: shop\n  click: [\".close\"]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range map[string]string{"auto": "true", "rules": rules} {
+		if err := dismissBannersCmd.Flags().Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		if req.Cmd != "dismiss-banners" {
+			t.Errorf("expected cmd=dismiss-banners, got %s", req.Cmd)
+		}
+		var params ipc.DismissBannersParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			t.Fatal(err)
+		}
+		if params.Auto == nil || !*params.Auto {
+			t.Errorf("auto = %v, want true", params.Auto)
+		}
+		if n := len(banners.Builtin()) + 1; len(params.Rules) != n || params.Rules[n-1].Name != "shop" {
+			t.Errorf("rules = %+v, want the built-in rules and shop", params.Rules)
+		}
+		return ipc.SuccessResponse(ipc.DismissBannersData{
+			Dismissed: []ipc.BannerHit{{Rule: "shop", Action: "clicked", Selector: ".close"}},
+			Auto:      true,
+		}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runDismissBanners(dismissBannersCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"clicked  shop  .close\n", "Dismissing banners on every page load\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunDismissBanners_InvalidRules(t *testing.T) {
+	resetFlags(t, dismissBannersCmd.Flags(), "rules")
+	rules := filepath.Join(t.TempDir(), "banners.yaml")
+	if err := os.WriteFile(rules, []byte("- clik: [a]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := dismissBannersCmd.Flags().Set("rules", rules); err != nil {
+		t.Fatal(err)
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})()
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runDismissBanners(dismissBannersCmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), rules) {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,451 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/grantcarthew/webctl/internal/browser"
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose common setup problems",
+	Long: `Checks the environment webctl needs and prints a fix for anything wrong.
+
+Checks:
+  chrome     A Chrome or Chromium binary is found ($WEBCTL_CHROME or known paths)
+  display    A display server is available for headed mode (Linux)
+  sandbox    Chrome's sandbox can run (not root, user namespaces allowed; Linux)
+  socket     The socket directory is private to you, or a sticky shared one
+  pid        No stale PID file or socket is left by a dead daemon
+  port       The default CDP port (9222) is free, or in use by webctl's browser
+  launch     A headless probe browser starts (skipped with --skip-launch)
+  cdp        The probe browser completes a CDP handshake
+
+Each check reports OK, WARN, FAIL, or SKIP. The command exits non-zero if
+any check fails. The probe browser uses a throwaway profile and a free port,
+and does not disturb a running daemon.
+
+Flags:
+  --skip-launch     Skip the probe browser launch and CDP handshake
+
+Examples:
+  doctor
+  doctor --skip-launch
+  doctor --json | jq '.checks[] | select(.status != "ok")'
+
+Text output:
+  OK    chrome   /usr/bin/chromium (Chromium 131.0.6778.85)
+  WARN  display  DISPLAY and WAYLAND_DISPLAY are unset
+        fix: use 'webctl start --headless', or run under a display server (e.g. xvfb-run)
+  OK    launch   headless probe started on port 9223
+  OK    cdp      handshake complete (protocol 1.3)`,
+	Args:        cobra.NoArgs,
+	RunE:        runDoctor,
+	Annotations: map[string]string{noAutoStartAnnotation: ""},
+}
+
+func init() {
+	doctorCmd.Flags().Bool("skip-launch", false, "Skip the probe browser launch and CDP handshake")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// Doctor check outcomes.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// doctorCheck is the result of one diagnostic.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// doctorEnv is the environment the checks inspect, replaceable for testing.
+type doctorEnv struct {
+	goos        string
+	getenv      func(string) string
+	geteuid     func() int
+	readFile    func(string) ([]byte, error)
+	findChrome  func() (string, error)
+	socketPath  string
+	pidPath     string
+	processLive func(pid int) bool
+	portFree    func(port int) bool
+	probe       func(chromePath string) (port int, protocol string, err error)
+}
+
+// newDoctorEnv returns the real environment.
+var newDoctorEnv = func() doctorEnv {
+	return doctorEnv{
+		goos:        runtime.GOOS,
+		getenv:      os.Getenv,
+		geteuid:     os.Geteuid,
+		readFile:    os.ReadFile,
+		findChrome:  browser.FindChrome,
+		socketPath:  ipc.DefaultSocketPath(),
+		pidPath:     ipc.DefaultPIDPath(),
+		processLive: processLive,
+		portFree:    portFree,
+		probe:       probeBrowser,
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	t := startTimer("doctor")
+	defer t.log()
+
+	skipLaunch, _ := cmd.Flags().GetBool("skip-launch")
+	debugParam("skip-launch=%v", skipLaunch)
+
+	checks := runDoctorChecks(newDoctorEnv(), skipLaunch)
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == checkFail {
+			failed++
+		}
+	}
+
+	if JSONOutput {
+		if err := outputJSON(os.Stdout, map[string]any{
+			"ok":     failed == 0,
+			"checks": checks,
+		}); err != nil {
+			return err
+		}
+	} else {
+		writeDoctorReport(os.Stdout, checks, format.NewOutputOptions(JSONOutput, NoColor).UseColor)
+	}
+
+	if failed > 0 {
+		return printedError{err: fmt.Errorf("%d doctor check(s) failed", failed)}
+	}
+	return nil
+}
+
+// runDoctorChecks runs every check in order. The launch and CDP checks depend
+// on a Chrome binary being found.
+func runDoctorChecks(env doctorEnv, skipLaunch bool) []doctorCheck {
+	chrome, chromePath := checkChrome(env)
+	checks := []doctorCheck{
+		chrome,
+		checkDisplay(env),
+		checkSandbox(env),
+		checkSocketDir(env),
+		checkStaleFiles(env),
+		checkPort(env),
+	}
+
+	switch {
+	case skipLaunch:
+		checks = append(checks,
+			doctorCheck{Name: "launch", Status: checkSkip, Detail: "skipped (--skip-launch)"},
+			doctorCheck{Name: "cdp", Status: checkSkip, Detail: "skipped (--skip-launch)"})
+	case chromePath == "":
+		checks = append(checks,
+			doctorCheck{Name: "launch", Status: checkSkip, Detail: "no Chrome binary to launch"},
+			doctorCheck{Name: "cdp", Status: checkSkip, Detail: "no Chrome binary to launch"})
+	default:
+		checks = append(checks, checkLaunch(env, chromePath)...)
+	}
+	return checks
+}
+
+// checkChrome locates the browser binary and returns its path when found.
+func checkChrome(env doctorEnv) (doctorCheck, string) {
+	path, err := env.findChrome()
+	if err != nil {
+		fix := "install Google Chrome or Chromium, or set WEBCTL_CHROME to its path"
+		if env.getenv("WEBCTL_CHROME") != "" {
+			fix = "point WEBCTL_CHROME at an existing Chrome or Chromium binary, or unset it"
+		}
+		return doctorCheck{Name: "chrome", Status: checkFail, Detail: err.Error(), Fix: fix}, ""
+	}
+
+	detail := path
+	if out, err := exec.Command(path, "--version").Output(); err == nil {
+		if v := strings.TrimSpace(string(out)); v != "" {
+			detail = fmt.Sprintf("%s (%s)", path, v)
+		}
+	}
+	return doctorCheck{Name: "chrome", Status: checkOK, Detail: detail}, path
+}
+
+// checkDisplay warns when headed mode cannot open a window on Linux.
+func checkDisplay(env doctorEnv) doctorCheck {
+	if env.goos != "linux" {
+		return doctorCheck{Name: "display", Status: checkSkip, Detail: "not needed on " + env.goos}
+	}
+	if d := env.getenv("WAYLAND_DISPLAY"); d != "" {
+		return doctorCheck{Name: "display", Status: checkOK, Detail: "WAYLAND_DISPLAY=" + d}
+	}
+	if d := env.getenv("DISPLAY"); d != "" {
+		return doctorCheck{Name: "display", Status: checkOK, Detail: "DISPLAY=" + d}
+	}
+	return doctorCheck{
+		Name:   "display",
+		Status: checkWarn,
+		Detail: "DISPLAY and WAYLAND_DISPLAY are unset",
+		Fix:    "use 'webctl start --headless', or run under a display server (e.g. xvfb-run)",
+	}
+}
+
+// checkSandbox warns about conditions under which Chrome's Linux sandbox
+// cannot start: running as root, or unprivileged user namespaces disabled.
+func checkSandbox(env doctorEnv) doctorCheck {
+	if env.goos != "linux" {
+		return doctorCheck{Name: "sandbox", Status: checkSkip, Detail: "not checked on " + env.goos}
+	}
+	if env.geteuid() == 0 {
+		return doctorCheck{
+			Name:   "sandbox",
+			Status: checkWarn,
+			Detail: "running as root; Chrome refuses to start its sandbox as root",
+			Fix:    "run webctl as a regular user (in containers, add a non-root USER, or use 'webctl start --ci')",
+		}
+	}
+	if data, err := env.readFile("/proc/sys/kernel/unprivileged_userns_clone"); err == nil && strings.TrimSpace(string(data)) == "0" {
+		return doctorCheck{
+			Name:   "sandbox",
+			Status: checkWarn,
+			Detail: "unprivileged user namespaces are disabled",
+			Fix:    "sudo sysctl -w kernel.unprivileged_userns_clone=1",
+		}
+	}
+	if data, err := env.readFile("/proc/sys/kernel/apparmor_restrict_unprivileged_userns"); err == nil && strings.TrimSpace(string(data)) == "1" {
+		return doctorCheck{
+			Name:   "sandbox",
+			Status: checkWarn,
+			Detail: "AppArmor restricts unprivileged user namespaces",
+			Fix:    "use a distribution Chrome package with an AppArmor profile, or sudo sysctl -w kernel.apparmor_restrict_unprivileged_userns=0",
+		}
+	}
+	return doctorCheck{Name: "sandbox", Status: checkOK, Detail: "user namespaces available"}
+}
+
+// checkSocketDir verifies the socket directory is usable and private.
+func checkSocketDir(env doctorEnv) doctorCheck {
+	dir := filepath.Dir(env.socketPath)
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		parent := filepath.Dir(dir)
+		if !dirWritable(parent) {
+			return doctorCheck{
+				Name:   "socket",
+				Status: checkFail,
+				Detail: fmt.Sprintf("%s does not exist and %s is not writable", dir, parent),
+				Fix:    "set XDG_RUNTIME_DIR to a writable directory",
+			}
+		}
+		return doctorCheck{Name: "socket", Status: checkOK, Detail: dir + " will be created on start"}
+	}
+	if err != nil {
+		return doctorCheck{Name: "socket", Status: checkFail, Detail: err.Error(), Fix: "set XDG_RUNTIME_DIR to a writable directory"}
+	}
+	if !info.IsDir() {
+		return doctorCheck{Name: "socket", Status: checkFail, Detail: dir + " is not a directory", Fix: "rm " + dir}
+	}
+	// A sticky directory such as /tmp is shared, but only the socket's owner
+	// can remove or replace it there, and the socket itself is owner-only
+	sticky := info.Mode()&os.ModeSticky != 0
+	if uid, ok := fileOwner(info); ok && uid != env.geteuid() && !(uid == 0 && sticky) {
+		return doctorCheck{
+			Name:   "socket",
+			Status: checkFail,
+			Detail: fmt.Sprintf("%s is owned by uid %d, not you", dir, uid),
+			Fix:    "remove it (sudo rm -r " + dir + ") or set XDG_RUNTIME_DIR",
+		}
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 && !sticky {
+		return doctorCheck{
+			Name:   "socket",
+			Status: checkWarn,
+			Detail: fmt.Sprintf("%s has mode %04o; other users can reach the daemon", dir, perm),
+			Fix:    "chmod 700 " + dir,
+		}
+	}
+	if !dirWritable(dir) {
+		return doctorCheck{Name: "socket", Status: checkFail, Detail: dir + " is not writable", Fix: "chmod 700 " + dir}
+	}
+	if sticky {
+		return doctorCheck{Name: "socket", Status: checkOK, Detail: dir + " (shared sticky directory)"}
+	}
+	return doctorCheck{Name: "socket", Status: checkOK, Detail: dir}
+}
+
+// checkStaleFiles reports a PID file or socket left behind by a daemon that is
+// no longer running.
+func checkStaleFiles(env doctorEnv) doctorCheck {
+	const fix = "webctl stop --force"
+
+	if pid, err := readPIDFile(env.pidPath); err == nil {
+		if !env.processLive(pid) {
+			return doctorCheck{Name: "pid", Status: checkWarn, Detail: fmt.Sprintf("stale PID file (PID %d is not running)", pid), Fix: fix}
+		}
+		return doctorCheck{Name: "pid", Status: checkOK, Detail: fmt.Sprintf("daemon running (PID %d)", pid)}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return doctorCheck{Name: "pid", Status: checkWarn, Detail: fmt.Sprintf("unreadable PID file: %v", err), Fix: fix}
+	}
+
+	if _, err := os.Stat(env.socketPath); err == nil {
+		return doctorCheck{Name: "pid", Status: checkWarn, Detail: "socket file exists but no daemon PID file", Fix: fix}
+	}
+	return doctorCheck{Name: "pid", Status: checkOK, Detail: "no daemon running, nothing stale"}
+}
+
+// checkPort reports whether 'webctl start' can bind its default CDP port.
+func checkPort(env doctorEnv) doctorCheck {
+	if env.portFree(browser.DefaultPort) {
+		return doctorCheck{Name: "port", Status: checkOK, Detail: fmt.Sprintf("%d is free", browser.DefaultPort)}
+	}
+	if _, err := os.Stat(env.pidPath); err == nil {
+		return doctorCheck{Name: "port", Status: checkOK, Detail: fmt.Sprintf("%d is in use by the running daemon's browser", browser.DefaultPort)}
+	}
+	return doctorCheck{
+		Name:   "port",
+		Status: checkWarn,
+		Detail: fmt.Sprintf("%d is in use by another process", browser.DefaultPort),
+		Fix:    "use 'webctl start --port <n>', or 'webctl stop --force' if it is an orphaned browser",
+	}
+}
+
+// checkLaunch starts a headless probe browser and completes a CDP handshake.
+func checkLaunch(env doctorEnv, chromePath string) []doctorCheck {
+	port, protocol, err := env.probe(chromePath)
+	var launchErr *probeLaunchError
+	switch {
+	case errors.As(err, &launchErr):
+		fix := "run 'webctl start --headless --debug' for details"
+		if env.goos == "linux" && env.geteuid() == 0 {
+			fix = "run webctl as a regular user; Chrome's sandbox cannot start as root"
+		}
+		return []doctorCheck{
+			{Name: "launch", Status: checkFail, Detail: launchErr.Error(), Fix: fix},
+			{Name: "cdp", Status: checkSkip, Detail: "probe browser did not start"},
+		}
+	case err != nil:
+		return []doctorCheck{
+			{Name: "launch", Status: checkOK, Detail: fmt.Sprintf("headless probe started on port %d", port)},
+			{Name: "cdp", Status: checkFail, Detail: err.Error(), Fix: "check that nothing (proxy, firewall) intercepts localhost connections"},
+		}
+	}
+	return []doctorCheck{
+		{Name: "launch", Status: checkOK, Detail: fmt.Sprintf("headless probe started on port %d", port)},
+		{Name: "cdp", Status: checkOK, Detail: fmt.Sprintf("handshake complete (protocol %s)", protocol)},
+	}
+}
+
+// probeLaunchError marks a probe failure before CDP was reachable.
+type probeLaunchError struct {
+	err error
+}
+
+func (e *probeLaunchError) Error() string { return e.err.Error() }
+func (e *probeLaunchError) Unwrap() error { return e.err }
+
+// probeBrowser launches a throwaway headless browser on a free port, dials its
+// browser-level CDP endpoint, and round-trips Browser.getVersion.
+func probeBrowser(chromePath string) (int, string, error) {
+	// Pick our own free port rather than letting Start scan from 9222, which
+	// would print a port-in-use notice when a daemon is already running.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, "", &probeLaunchError{err: err}
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	_ = l.Close()
+
+	b, err := browser.StartWithBinary(chromePath, browser.LaunchOptions{Headless: true, Port: port})
+	if err != nil {
+		return 0, "", &probeLaunchError{err: err}
+	}
+	defer func() { _ = b.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	version, err := b.Version(ctx)
+	if err != nil {
+		return b.Port(), "", fmt.Errorf("failed to read /json/version: %w", err)
+	}
+	client, err := cdp.Dial(ctx, version.WebSocketURL)
+	if err != nil {
+		return b.Port(), "", err
+	}
+	defer func() { _ = client.Close() }()
+
+	if _, err := client.SendContext(ctx, "Browser.getVersion", nil); err != nil {
+		return b.Port(), "", fmt.Errorf("Browser.getVersion failed: %w", err)
+	}
+	return b.Port(), version.ProtocolVer, nil
+}
+
+// dirWritable reports whether a file can be created in dir.
+func dirWritable(dir string) bool {
+	f, err := os.CreateTemp(dir, ".webctl-doctor-*")
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+	return true
+}
+
+// portFree reports whether a TCP port can be bound on localhost.
+func portFree(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	_ = l.Close()
+	return true
+}
+
+// writeDoctorReport renders the checks as aligned status lines, each failing
+// or warning check followed by its fix.
+func writeDoctorReport(w io.Writer, checks []doctorCheck, useColor bool) {
+	width := 0
+	for _, c := range checks {
+		width = max(width, len(c.Name))
+	}
+
+	for _, c := range checks {
+		status := fmt.Sprintf("%-5s", strings.ToUpper(c.Status))
+		if useColor {
+			attr := color.FgGreen
+			switch c.Status {
+			case checkWarn:
+				attr = color.FgYellow
+			case checkFail:
+				attr = color.FgRed
+			case checkSkip:
+				attr = color.Faint
+			}
+			status = color.New(attr).Sprint(status)
+		}
+		_, _ = fmt.Fprintf(w, "%s %-*s  %s\n", status, width, c.Name, c.Detail)
+		if c.Fix != "" {
+			_, _ = fmt.Fprintf(w, "      fix: %s\n", c.Fix)
+		}
+	}
+}
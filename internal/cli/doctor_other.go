@@ -0,0 +1,21 @@
+//go:build !unix
+
+package cli
+
+import "os"
+
+// fileOwner is not available on this platform; ownership is not checked.
+func fileOwner(info os.FileInfo) (int, bool) {
+	return 0, false
+}
+
+// processLive reports whether pid names a running process. FindProcess
+// opens the process on this platform, so it fails for a dead PID.
+func processLive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
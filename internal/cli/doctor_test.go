@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/browser"
+)
+
+// fakeDoctorEnv returns a healthy Linux environment rooted in a temp dir.
+func fakeDoctorEnv(t *testing.T) doctorEnv {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "webctl")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"DISPLAY": ":0"}
+	return doctorEnv{
+		goos:        "linux",
+		getenv:      func(k string) string { return env[k] },
+		geteuid:     os.Geteuid,
+		readFile:    func(string) ([]byte, error) { return nil, os.ErrNotExist },
+		findChrome:  func() (string, error) { return "/nonexistent/chrome", nil },
+		socketPath:  filepath.Join(dir, "webctl.sock"),
+		pidPath:     filepath.Join(dir, "webctl.pid"),
+		processLive: func(int) bool { return true },
+		portFree:    func(int) bool { return true },
+		probe: func(string) (int, string, error) {
+			return 9333, "1.3", nil
+		},
+	}
+}
+
+func findCheck(t *testing.T, checks []doctorCheck, name string) doctorCheck {
+	t.Helper()
+	for _, c := range checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no %q check in %+v", name, checks)
+	return doctorCheck{}
+}
+
+func TestRunDoctorChecks_Healthy(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("sandbox check warns when run as root")
+	}
+	for _, c := range runDoctorChecks(fakeDoctorEnv(t), false) {
+		if c.Status != checkOK {
+			t.Errorf("%s: expected ok, got %s (%s)", c.Name, c.Status, c.Detail)
+		}
+	}
+}
+
+func TestRunDoctorChecks_ChromeMissingSkipsLaunch(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	env.findChrome = func() (string, error) { return "", browser.ErrChromeNotFound }
+	env.probe = func(string) (int, string, error) {
+		t.Fatal("probe should not run without a Chrome binary")
+		return 0, "", nil
+	}
+
+	checks := runDoctorChecks(env, false)
+	if c := findCheck(t, checks, "chrome"); c.Status != checkFail || !strings.Contains(c.Fix, "WEBCTL_CHROME") {
+		t.Errorf("chrome: got %+v", c)
+	}
+	if c := findCheck(t, checks, "launch"); c.Status != checkSkip {
+		t.Errorf("launch: expected skip, got %+v", c)
+	}
+}
+
+func TestCheckDisplay(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	env.getenv = func(string) string { return "" }
+	if c := checkDisplay(env); c.Status != checkWarn || !strings.Contains(c.Fix, "--headless") {
+		t.Errorf("expected warn with --headless fix, got %+v", c)
+	}
+
+	env.goos = "darwin"
+	if c := checkDisplay(env); c.Status != checkSkip {
+		t.Errorf("expected skip on darwin, got %+v", c)
+	}
+}
+
+func TestCheckSandbox(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	env.geteuid = func() int { return 0 }
+	if c := checkSandbox(env); c.Status != checkWarn || !strings.Contains(c.Detail, "root") {
+		t.Errorf("expected root warning, got %+v", c)
+	}
+
+	env.geteuid = func() int { return 1000 }
+	env.readFile = func(path string) ([]byte, error) {
+		if strings.HasSuffix(path, "unprivileged_userns_clone") {
+			return []byte("0\n"), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	if c := checkSandbox(env); c.Status != checkWarn || !strings.Contains(c.Fix, "sysctl") {
+		t.Errorf("expected userns warning, got %+v", c)
+	}
+}
+
+func TestCheckSocketDir_Permissions(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	dir := filepath.Dir(env.socketPath)
+	if err := os.Chmod(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	c := checkSocketDir(env)
+	if c.Status != checkWarn || c.Fix != "chmod 700 "+dir {
+		t.Errorf("expected mode warning, got %+v", c)
+	}
+}
+
+func TestCheckSocketDir_StickyShared(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	dir := filepath.Dir(env.socketPath)
+	if err := os.Chmod(dir, os.ModeSticky|0777); err != nil {
+		t.Fatal(err)
+	}
+
+	c := checkSocketDir(env)
+	if c.Status != checkOK || !strings.Contains(c.Detail, "shared sticky directory") {
+		t.Errorf("expected ok for a sticky shared directory, got %+v", c)
+	}
+}
+
+func TestCheckSocketDir_Missing(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	env.socketPath = filepath.Join(t.TempDir(), "absent", "webctl.sock")
+	if c := checkSocketDir(env); c.Status != checkOK || !strings.Contains(c.Detail, "will be created") {
+		t.Errorf("expected ok, got %+v", c)
+	}
+}
+
+func TestCheckStaleFiles(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	if err := os.WriteFile(env.pidPath, []byte(strconv.Itoa(424242)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	env.processLive = func(int) bool { return false }
+
+	c := checkStaleFiles(env)
+	if c.Status != checkWarn || !strings.Contains(c.Detail, "424242") || c.Fix != "webctl stop --force" {
+		t.Errorf("expected stale PID warning, got %+v", c)
+	}
+
+	if err := os.Remove(env.pidPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(env.socketPath, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if c := checkStaleFiles(env); c.Status != checkWarn || !strings.Contains(c.Detail, "socket") {
+		t.Errorf("expected stale socket warning, got %+v", c)
+	}
+}
+
+func TestCheckLaunch_Failures(t *testing.T) {
+	env := fakeDoctorEnv(t)
+	env.probe = func(string) (int, string, error) {
+		return 0, "", &probeLaunchError{err: errors.New("chrome exited")}
+	}
+	checks := checkLaunch(env, "/bin/chrome")
+	if checks[0].Status != checkFail || checks[1].Status != checkSkip {
+		t.Errorf("launch failure: got %+v", checks)
+	}
+
+	env.probe = func(string) (int, string, error) {
+		return 9333, "", errors.New("handshake refused")
+	}
+	checks = checkLaunch(env, "/bin/chrome")
+	if checks[0].Status != checkOK || checks[1].Status != checkFail {
+		t.Errorf("cdp failure: got %+v", checks)
+	}
+}
+
+func TestRunDoctor_JSONFailure(t *testing.T) {
+	enableJSONOutput(t)
+	old := newDoctorEnv
+	t.Cleanup(func() { newDoctorEnv = old })
+	newDoctorEnv = func() doctorEnv {
+		env := fakeDoctorEnv(t)
+		env.findChrome = func() (string, error) { return "", browser.ErrChromeNotFound }
+		return env
+	}
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runDoctor(doctorCmd, nil)
+	})
+	var pe printedError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected printedError, got %v", err)
+	}
+
+	var resp struct {
+		OK     bool          `json:"ok"`
+		Checks []doctorCheck `json:"checks"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if resp.OK || findCheck(t, resp.Checks, "chrome").Status != checkFail {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestWriteDoctorReport(t *testing.T) {
+	var sb strings.Builder
+	writeDoctorReport(&sb, []doctorCheck{
+		{Name: "chrome", Status: checkOK, Detail: "/usr/bin/chromium"},
+		{Name: "display", Status: checkWarn, Detail: "unset", Fix: "use --headless"},
+	}, false)
+
+	want := "OK    chrome   /usr/bin/chromium\n" +
+		"WARN  display  unset\n" +
+		"      fix: use --headless\n"
+	if sb.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
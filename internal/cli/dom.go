@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var domCmd = &cobra.Command{
+	Use:   "dom",
+	Short: "Capture the page's DOM as structured data",
+	Long: `Captures the rendered DOM of the active tab as data rather than markup, for
+diffing page structure between builds or feeding layout to other tools.
+
+Subcommands:
+  snapshot    Capture every node with its layout box and computed styles`,
+}
+
+var domSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Capture the DOM with layout boxes and computed styles",
+	Long: `Captures the active tab's document in one pass, through Chrome's DOMSnapshot
+domain: every element and text node with its attributes, its layout box, and
+the computed styles named by --computed-styles.
+
+Nodes are listed in document order, each with its index, its parent's index,
+and its depth, so the tree can be rebuilt from the flat list. Boxes are in CSS
+pixels relative to the document; nodes that are not rendered (display: none,
+<head> and its children) have no box and no styles. Comments, whitespace-only
+text, and the contents of <script> and <style> are left out. Iframe documents
+are not included.
+
+Flags:
+  --computed-styles <props>   Comma-separated CSS properties to capture
+
+Examples:
+  dom snapshot
+  dom snapshot --computed-styles display,color,font-size
+  dom snapshot --json > before.json
+  dom snapshot --json | jq '.nodes[] | select(.name == "img") | .box'
+
+Text output (indented tree; box as x,y WxH):
+  html  0,0 1280x720
+    head
+      title
+        "Checkout"
+    body  0,0 1280x720  display=block
+      h1.title  8,21 1264x37  display=block
+        "Welcome back"
+
+JSON output:
+  {"ok": true, "url": "...", "title": "...", "nodes": [{"index": 4, "parent": 1,
+   "depth": 2, "type": "element", "name": "body", "attributes": {...},
+   "box": {"x": 0, "y": 0, "width": 1280, "height": 720}, "styles": {...}}]}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start
+  - "no active session" - no browser tab is open`,
+	Args: cobra.NoArgs,
+	RunE: runDOMSnapshot,
+}
+
+func init() {
+	domSnapshotCmd.Flags().StringSlice("computed-styles", nil, "Comma-separated CSS properties to capture for each rendered node")
+
+	domCmd.AddCommand(domSnapshotCmd)
+	rootCmd.AddCommand(domCmd)
+}
+
+func runDOMSnapshot(cmd *cobra.Command, args []string) error {
+	t := startTimer("dom snapshot")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	styles, _ := cmd.Flags().GetStringSlice("computed-styles")
+	params := ipc.DOMParams{Action: "snapshot"}
+	for _, s := range styles {
+		if s = strings.TrimSpace(s); s != "" {
+			params.ComputedStyles = append(params.ComputedStyles, s)
+		}
+	}
+	debugParam("computedStyles=%v", params.ComputedStyles)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("dom", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "dom", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.DOMSnapshotData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":    true,
+			"url":   data.URL,
+			"title": data.Title,
+			"nodes": data.Nodes,
+		})
+	}
+	return format.DOMSnapshot(os.Stdout, data.Nodes)
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func TestRunDOMSnapshot(t *testing.T) {
+	var got ipc.DOMParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "dom" {
+				t.Errorf("expected cmd=dom, got %s", req.Cmd)
+			}
+			if err := json.Unmarshal(req.Params, &got); err != nil {
+				t.Fatalf("failed to parse params: %v", err)
+			}
+			raw, _ := json.Marshal(ipc.DOMSnapshotData{URL: "https://example.com/", Nodes: []ipc.DOMNode{
+				{Index: 0, Parent: -1, Type: "document", Name: "#document"},
+				{Index: 1, Parent: 0, Depth: 1, Type: "element", Name: "html", Box: &ipc.DOMBox{Width: 800, Height: 600}},
+				{Index: 2, Parent: 1, Depth: 2, Type: "text", Name: "#text", Value: "Hi"},
+			}})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	cmd := newDOMSnapshotTestCmd()
+	_ = cmd.Flags().Set("computed-styles", "display, color")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runDOMSnapshot(cmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := ipc.DOMParams{Action: "snapshot", ComputedStyles: []string{"display", "color"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("params = %+v, want %+v", got, want)
+	}
+	if expected := "html  0,0 800x600\n  \"Hi\"\n"; out != expected {
+		t.Errorf("output = %q, want %q", out, expected)
+	}
+}
+
+func TestRunDOMSnapshot_JSON(t *testing.T) {
+	enableJSONOutput(t)
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			raw, _ := json.Marshal(ipc.DOMSnapshotData{URL: "https://example.com/", Title: "Example", Nodes: []ipc.DOMNode{
+				{Index: 0, Parent: -1, Type: "document", Name: "#document"},
+			}})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runDOMSnapshot(newDOMSnapshotTestCmd(), nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		OK    bool          `json:"ok"`
+		URL   string        `json:"url"`
+		Title string        `json:"title"`
+		Nodes []ipc.DOMNode `json:"nodes"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !result.OK || result.Title != "Example" || len(result.Nodes) != 1 || result.Nodes[0].Parent != -1 {
+		t.Errorf("unexpected response: %s", out)
+	}
+}
+
+func newDOMSnapshotTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "snapshot", RunE: runDOMSnapshot}
+	cmd.Flags().StringSlice("computed-styles", nil, "")
+	return cmd
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// elementTypes are the values accepted by 'elements --type'.
+var elementTypes = []string{"link", "button", "input", "checkbox", "radio", "select", "tab", "menuitem", "other"}
+
+var elementsCmd = &cobra.Command{
+	Use:   "elements",
+	Short: "List clickable and focusable elements with selectors",
+	Long: `Lists the active page's clickable and focusable elements in document order,
+each with a selector that matches exactly that element, its accessible name,
+and whether it is disabled, hidden, or scrolled out of view. Use it to write
+automation against a page without opening DevTools.
+
+Selectors prefer a unique id, then a test or accessibility attribute
+(data-testid, name, aria-label, ...), then the shortest unique path.
+
+Types:
+  link       a[href], role=link
+  button     button, submit/button/reset inputs, summary, role=button
+  input      text-like inputs, textarea, contenteditable, role=textbox
+  checkbox   checkbox inputs, role=checkbox or switch
+  radio      radio inputs, role=radio
+  select     select, role=combobox or listbox
+  tab        role=tab
+  menuitem   role=menuitem or option
+  other      onclick handlers and tabindex >= 0
+An explicit role wins over the tag: <div role="button"> is a button.
+
+Flags:
+  --type <types>   Comma-separated types to list (default all)
+
+Examples:
+  elements
+  elements --type button,link
+  elements --type input --json | jq -r '.elements[] | select(.visible) | .selector'
+
+Text output (hidden: not rendered; offscreen: rendered but out of view):
+  link     "Home" -> /  a.logo
+  button   "Place order"  #place-order (disabled)
+  input    "Email"  #email
+  button   "Open menu"  #menu (hidden)
+
+JSON output:
+  {"ok": true, "elements": [{"type": "button", "tag": "button", "name": "Place order",
+   "selector": "#place-order", "disabled": true, "visible": true, "inViewport": true}]}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start
+  - "unknown element type" - --type names a type not listed above`,
+	Args: cobra.NoArgs,
+	RunE: runElements,
+}
+
+func init() {
+	elementsCmd.Flags().StringSlice("type", nil, "Comma-separated types to list: "+strings.Join(elementTypes, ", "))
+	rootCmd.AddCommand(elementsCmd)
+}
+
+func runElements(cmd *cobra.Command, args []string) error {
+	t := startTimer("elements")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	types, _ := cmd.Flags().GetStringSlice("type")
+	var params ipc.ElementsParams
+	for _, typ := range types {
+		typ = strings.ToLower(strings.TrimSpace(typ))
+		if typ == "" {
+			continue
+		}
+		if !slices.Contains(elementTypes, typ) {
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("unknown element type %q (use %s)", typ, strings.Join(elementTypes, ", ")))
+		}
+		params.Types = append(params.Types, typ)
+	}
+	debugParam("types=%v", params.Types)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("elements", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "elements", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.ElementsData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":       true,
+			"elements": data.Elements,
+		})
+	}
+	return format.Elements(os.Stdout, data.Elements)
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func TestRunElements(t *testing.T) {
+	var got ipc.ElementsParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "elements" {
+				t.Errorf("expected cmd=elements, got %s", req.Cmd)
+			}
+			if err := json.Unmarshal(req.Params, &got); err != nil {
+				t.Fatalf("failed to parse params: %v", err)
+			}
+			raw, _ := json.Marshal(ipc.ElementsData{Elements: []ipc.InteractiveElement{
+				{Type: "button", Tag: "button", Name: "Save", Selector: "#save", Visible: true, InViewport: true},
+			}})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	cmd := newElementsTestCmd()
+	_ = cmd.Flags().Set("type", "Button, link")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runElements(cmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"button", "link"}; !reflect.DeepEqual(got.Types, want) {
+		t.Errorf("types = %v, want %v", got.Types, want)
+	}
+	if expected := "button   \"Save\"  #save\n"; out != expected {
+		t.Errorf("output = %q, want %q", out, expected)
+	}
+}
+
+func TestRunElements_UnknownType(t *testing.T) {
+	called := false
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			called = true
+			return ipc.Response{OK: true}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	cmd := newElementsTestCmd()
+	_ = cmd.Flags().Set("type", "buton")
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runElements(cmd, nil)
+	})
+	if err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+	if called {
+		t.Error("daemon should not be called for an unknown type")
+	}
+}
+
+func newElementsTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "elements", RunE: runElements}
+	cmd.Flags().StringSlice("type", nil, "")
+	return cmd
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var emulateCmd = &cobra.Command{
+	Use:   "emulate",
+	Short: "Emulate device state in the active tab",
+	Long: `Overrides device state the active tab's page can see, so power-aware and
+idle-detection features can be exercised.
+
+Subcommands:
+  idle       Emulate the user being idle or the screen being locked
+  battery    Emulate the battery level and charging state`,
+}
+
+var emulateIdleCmd = &cobra.Command{
+	Use:   "idle",
+	Short: "Emulate the user being idle or the screen being locked",
+	Long: `Sets the user and screen state the Idle Detection API (IdleDetector) reports
+in the active tab, and grants the page's origin idle detection so it can use
+the API without a prompt. The user is active and the screen unlocked unless
+a flag says otherwise. The override lasts until cleared or the tab closes;
+an IdleDetector already running sees the change at once.
+
+Flags:
+  --user-idle        Report the user as idle
+  --screen-locked    Report the screen as locked
+  --clear            Remove the override, reporting the real state
+
+Examples:
+  emulate idle --user-idle
+  emulate idle --user-idle --screen-locked
+  emulate idle
+  emulate idle --clear
+
+Response:
+  Idle: user idle, screen locked
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.NoArgs,
+	RunE: runEmulateIdle,
+}
+
+var emulateBatteryCmd = &cobra.Command{
+	Use:   "battery",
+	Short: "Emulate the battery level and charging state",
+	Long: `Replaces navigator.getBattery() in the active tab with a battery at --level
+percent, charging with --charging. The page sees it at once and on every
+later load in the tab, until cleared. Running it again on a loaded page
+updates the battery it already has, firing levelchange and chargingchange as
+a real battery would.
+
+Flags:
+  --level <percent>   Battery level, 0 to 100 (default 100)
+  --charging          Report the battery as charging
+  --clear             Restore the browser's own battery
+
+Examples:
+  emulate battery --level 15
+  emulate battery --level 100 --charging
+  emulate battery --clear
+
+Response:
+  Battery: 15%, discharging
+
+Error cases:
+  - "--level must be from 0 to 100" - the level is out of range
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.NoArgs,
+	RunE: runEmulateBattery,
+}
+
+func init() {
+	emulateIdleCmd.Flags().Bool("user-idle", false, "Report the user as idle")
+	emulateIdleCmd.Flags().Bool("screen-locked", false, "Report the screen as locked")
+	emulateIdleCmd.Flags().Bool("clear", false, "Remove the override, reporting the real state")
+	emulateBatteryCmd.Flags().Float64("level", 100, "Battery level, 0 to 100")
+	emulateBatteryCmd.Flags().Bool("charging", false, "Report the battery as charging")
+	emulateBatteryCmd.Flags().Bool("clear", false, "Restore the browser's own battery")
+	emulateCmd.AddCommand(emulateIdleCmd, emulateBatteryCmd)
+	rootCmd.AddCommand(emulateCmd)
+}
+
+func runEmulateIdle(cmd *cobra.Command, args []string) error {
+	t := startTimer("emulate idle")
+	defer t.log()
+
+	userIdle, _ := cmd.Flags().GetBool("user-idle")
+	screenLocked, _ := cmd.Flags().GetBool("screen-locked")
+	clear, _ := cmd.Flags().GetBool("clear")
+	if clear && (userIdle || screenLocked) {
+		return outputCodedError(ipc.CodeInvalidArgs, "--clear cannot be combined with --user-idle or --screen-locked")
+	}
+	debugParam("user-idle=%t screen-locked=%t clear=%t", userIdle, screenLocked, clear)
+
+	params := ipc.EmulateParams{Action: "idle"}
+	if !clear {
+		params.Idle = &ipc.IdleState{UserIdle: userIdle, ScreenLocked: screenLocked}
+	}
+	data, err := emulateRequest(params)
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":   true,
+			"idle": data.Idle,
+		})
+	}
+	if data.Idle == nil {
+		_, err = fmt.Fprintln(os.Stdout, "Idle override cleared")
+		return err
+	}
+	user, screen := "active", "unlocked"
+	if data.Idle.UserIdle {
+		user = "idle"
+	}
+	if data.Idle.ScreenLocked {
+		screen = "locked"
+	}
+	_, err = fmt.Fprintf(os.Stdout, "Idle: user %s, screen %s\n", user, screen)
+	return err
+}
+
+func runEmulateBattery(cmd *cobra.Command, args []string) error {
+	t := startTimer("emulate battery")
+	defer t.log()
+
+	level, _ := cmd.Flags().GetFloat64("level")
+	charging, _ := cmd.Flags().GetBool("charging")
+	clear, _ := cmd.Flags().GetBool("clear")
+	if clear && (cmd.Flags().Changed("level") || charging) {
+		return outputCodedError(ipc.CodeInvalidArgs, "--clear cannot be combined with --level or --charging")
+	}
+	if level < 0 || level > 100 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--level must be from 0 to 100")
+	}
+	debugParam("level=%g charging=%t clear=%t", level, charging, clear)
+
+	params := ipc.EmulateParams{Action: "battery"}
+	if !clear {
+		params.Battery = &ipc.BatteryState{Level: level / 100, Charging: charging}
+	}
+	data, err := emulateRequest(params)
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":      true,
+			"battery": data.Battery,
+		})
+	}
+	if data.Battery == nil {
+		_, err = fmt.Fprintln(os.Stdout, "Battery override cleared")
+		return err
+	}
+	state := "discharging"
+	if data.Battery.Charging {
+		state = "charging"
+	}
+	_, err = fmt.Fprintf(os.Stdout, "Battery: %g%%, %s\n", data.Battery.Level*100, state)
+	return err
+}
+
+// emulateRequest sends an emulate action to the daemon.
+func emulateRequest(params ipc.EmulateParams) (ipc.EmulateData, error) {
+	var data ipc.EmulateData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("emulate", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "emulate", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
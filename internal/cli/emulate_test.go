@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// emulateExecutor echoes each "emulate" request's state back and records
+// the last request.
+func emulateExecutor(t *testing.T, got *ipc.EmulateParams) *mockExecutor {
+	return &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		if req.Cmd != "emulate" {
+			t.Errorf("expected cmd=emulate, got %s", req.Cmd)
+		}
+		*got = ipc.EmulateParams{}
+		_ = json.Unmarshal(req.Params, got)
+		return ipc.SuccessResponse(ipc.EmulateData{Idle: got.Idle, Battery: got.Battery}), nil
+	}}
+}
+
+func TestRunEmulateIdle(t *testing.T) {
+	var got ipc.EmulateParams
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: emulateExecutor(t, &got)})()
+
+	tests := []struct {
+		flags map[string]string
+		want  string
+	}{
+		{map[string]string{"user-idle": "true", "screen-locked": "true"}, "Idle: user idle, screen locked\n"},
+		{map[string]string{}, "Idle: user active, screen unlocked\n"},
+		{map[string]string{"clear": "true"}, "Idle override cleared\n"},
+	}
+	for _, tt := range tests {
+		resetFlags(t, emulateIdleCmd.Flags(), "user-idle", "screen-locked", "clear")
+		for name, value := range tt.flags {
+			if err := emulateIdleCmd.Flags().Set(name, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var err error
+		out := captureStream(t, &os.Stdout, func() {
+			err = runEmulateIdle(emulateIdleCmd, nil)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Action != "idle" || out != tt.want {
+			t.Errorf("%v: params = %+v, output = %q", tt.flags, got, out)
+		}
+	}
+
+	resetFlags(t, emulateIdleCmd.Flags(), "user-idle", "clear")
+	_ = emulateIdleCmd.Flags().Set("user-idle", "true")
+	_ = emulateIdleCmd.Flags().Set("clear", "true")
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runEmulateIdle(emulateIdleCmd, nil)
+	})
+	if err == nil || ErrorCode(err) != ipc.CodeInvalidArgs {
+		t.Errorf("--clear with --user-idle: err = %v", err)
+	}
+}
+
+func TestRunEmulateBattery(t *testing.T) {
+	var got ipc.EmulateParams
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: emulateExecutor(t, &got)})()
+
+	resetFlags(t, emulateBatteryCmd.Flags(), "level", "charging", "clear")
+	_ = emulateBatteryCmd.Flags().Set("level", "15")
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runEmulateBattery(emulateBatteryCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Battery == nil || got.Battery.Level != 0.15 || got.Battery.Charging {
+		t.Errorf("battery = %+v", got.Battery)
+	}
+	if out != "Battery: 15%, discharging\n" {
+		t.Errorf("output = %q", out)
+	}
+
+	resetFlags(t, emulateBatteryCmd.Flags(), "level", "charging", "clear")
+	_ = emulateBatteryCmd.Flags().Set("clear", "true")
+	out = captureStream(t, &os.Stdout, func() {
+		err = runEmulateBattery(emulateBatteryCmd, nil)
+	})
+	if err != nil || got.Battery != nil || out != "Battery override cleared\n" {
+		t.Errorf("--clear: err = %v, battery = %+v, output = %q", err, got.Battery, out)
+	}
+
+	resetFlags(t, emulateBatteryCmd.Flags(), "level", "charging", "clear")
+	_ = emulateBatteryCmd.Flags().Set("level", "150")
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runEmulateBattery(emulateBatteryCmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "--level must be from 0 to 100") {
+		t.Errorf("--level 150: err = %v", err)
+	}
+}
@@ -92,7 +92,7 @@ func runEval(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Read flags from command
@@ -132,7 +132,7 @@ func runEval(cmd *cobra.Command, args []string) error {
 	}
 
 	if !resp.OK {
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// Parse the response data
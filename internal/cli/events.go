@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// eventsFollowInterval is how often 'events follow' asks the daemon for new
+// events.
+const eventsFollowInterval = 500 * time.Millisecond
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Show the daemon's operational events",
+	Long: `Shows what the daemon itself has been doing, kept apart from the page's
+console output: tabs attached and detached, the browser lost, restarted, or
+switched between headed and headless, CDP connections, and commands that
+failed. Use it to find out why a command failed or a tab disappeared.
+
+Without a subcommand, same as 'events show'.
+
+The daemon keeps its last 1000 events in memory; they are lost when it stops.
+For the full history, including debug detail, see 'webctl logs'.
+
+Subcommands:
+  show     Print the buffered events, oldest first
+  follow   Print new events as they happen, until interrupted (Ctrl-C)
+
+Event types:
+  target_attached, target_detached     a tab opened or closed
+  browser_lost, browser_restarted      the browser crashed or disconnected
+  browser_relaunch_failed,
+  browser_restart_failed               a relaunch attempt, or all of them, failed
+  browser_mode_switched,
+  browser_mode_switch_failed           'webctl head' or 'webctl headless'
+  cdp_connected                        a CDP connection to a new browser
+  command_failed                       a command returned an error
+
+Flags:
+  --type <types>   Only show these event types (comma-separated)
+  --tail <n>       Only show the last n events (show only)
+
+Examples:
+  events
+  events show --type browser_lost,browser_restarted
+  events follow
+  events --json | jq '.events[] | select(.level == "error")'
+
+Text output:
+  14:03:21.118 INFO  cdp_connected CDP connected browser=Chrome/131.0.6778.85 port=9222
+  14:05:02.904 WARN  browser_lost browser crashed error="exit status 1"
+  14:05:03.611 WARN  browser_restarted browser restarted pid=48211 restarts=1
+
+JSON output (follow writes one event object per line):
+  {"ok": true, "events": [{"seq": 1, "timestamp": 1700000000123, "level": "info",
+   "type": "cdp_connected", "message": "CDP connected", "attrs": {"port": 9222}}],
+   "dropped": 0}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.NoArgs,
+	RunE: runEventsShow,
+}
+
+var eventsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the buffered daemon events",
+	Args:  cobra.NoArgs,
+	RunE:  runEventsShow,
+}
+
+var eventsFollowCmd = &cobra.Command{
+	Use:   "follow",
+	Short: "Print new daemon events until interrupted",
+	Args:  cobra.NoArgs,
+	RunE:  runEventsFollow,
+}
+
+func init() {
+	eventsCmd.PersistentFlags().StringSlice("type", nil, "Only show these event types (comma-separated)")
+	eventsCmd.Flags().Int("tail", 0, "Only show the last n events")
+	eventsShowCmd.Flags().Int("tail", 0, "Only show the last n events")
+	eventsCmd.AddCommand(eventsShowCmd, eventsFollowCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsShow(cmd *cobra.Command, args []string) error {
+	t := startTimer("events show")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	types := eventTypes(cmd)
+	tail, _ := cmd.Flags().GetInt("tail")
+	if tail < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid --tail %d (must not be negative)", tail))
+	}
+	debugParam("types=%v tail=%d", types, tail)
+
+	data, err := fetchEvents(0)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	events := filterEvents(data.Events, types)
+	if tail > 0 && len(events) > tail {
+		events = events[len(events)-tail:]
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":      true,
+			"events":  events,
+			"dropped": data.Dropped,
+		})
+	}
+	opts := format.NewOutputOptions(JSONOutput, NoColor)
+	for _, e := range events {
+		if err := format.DaemonEvent(os.Stdout, e, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runEventsFollow(cmd *cobra.Command, args []string) error {
+	t := startTimer("events follow")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	types := eventTypes(cmd)
+	debugParam("types=%v", types)
+
+	// Only events from now on: what came before is for 'events show'
+	data, err := fetchEvents(0)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	var after uint64
+	if n := len(data.Events); n > 0 {
+		after = data.Events[n-1].Seq
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(eventsFollowInterval)
+	defer ticker.Stop()
+
+	opts := format.NewOutputOptions(JSONOutput, NoColor)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		data, err := fetchEvents(after)
+		if err != nil {
+			return outputError(err.Error())
+		}
+		for _, e := range data.Events {
+			after = e.Seq
+			if len(filterEvents([]ipc.DaemonEvent{e}, types)) == 0 {
+				continue
+			}
+			if JSONOutput {
+				line, err := json.Marshal(e)
+				if err != nil {
+					return outputError(err.Error())
+				}
+				if _, err := os.Stdout.Write(append(line, '\n')); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := format.DaemonEvent(os.Stdout, e, opts); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// eventTypes returns the lowercased --type values, which may be given on
+// 'events' or on its subcommand.
+func eventTypes(cmd *cobra.Command) []string {
+	raw, _ := cmd.Flags().GetStringSlice("type")
+	var types []string
+	for _, typ := range raw {
+		if typ = strings.ToLower(strings.TrimSpace(typ)); typ != "" {
+			types = append(types, typ)
+		}
+	}
+	return types
+}
+
+// filterEvents keeps the events whose type is in types, or all of them when
+// types is empty.
+func filterEvents(events []ipc.DaemonEvent, types []string) []ipc.DaemonEvent {
+	if len(types) == 0 {
+		return events
+	}
+	kept := []ipc.DaemonEvent{}
+	for _, e := range events {
+		if slices.Contains(types, e.Type) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// fetchEvents asks the daemon for its events after seq after.
+func fetchEvents(after uint64) (ipc.EventsData, error) {
+	var data ipc.EventsData
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, err
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(ipc.EventsParams{After: after})
+	if err != nil {
+		return data, err
+	}
+
+	debugRequest("events", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "events", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, err
+	}
+	if !resp.OK {
+		return data, fmt.Errorf("%s", resp.Error)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, err
+	}
+	return data, nil
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func TestRunEventsShow(t *testing.T) {
+	enableJSONOutput(t)
+
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "events" {
+				t.Errorf("expected cmd=events, got %s", req.Cmd)
+			}
+			raw, _ := json.Marshal(ipc.EventsData{Events: []ipc.DaemonEvent{
+				{Seq: 1, Level: "info", Type: "target_attached", Message: "tab attached"},
+				{Seq: 2, Level: "warn", Type: "browser_lost", Message: "browser crashed"},
+				{Seq: 3, Level: "info", Type: "target_attached", Message: "tab attached"},
+				{Seq: 4, Level: "info", Type: "target_detached", Message: "tab detached"},
+			}, Dropped: 5})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	cmd := newEventsTestCmd()
+	_ = cmd.Flags().Set("type", "Target_Attached,browser_lost")
+	_ = cmd.Flags().Set("tail", "2")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runEventsShow(cmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		OK      bool              `json:"ok"`
+		Events  []ipc.DaemonEvent `json:"events"`
+		Dropped uint64            `json:"dropped"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse output %q: %v", out, err)
+	}
+	if !result.OK || result.Dropped != 5 {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.Events) != 2 || result.Events[0].Seq != 2 || result.Events[1].Seq != 3 {
+		t.Errorf("events = %+v, want seqs 2 and 3", result.Events)
+	}
+}
+
+func TestRunEventsShow_DaemonNotRunning(t *testing.T) {
+	restore := setMockFactory(&mockFactory{daemonRunning: false})
+	defer restore()
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runEventsShow(newEventsTestCmd(), nil)
+	})
+	if err == nil {
+		t.Error("expected an error when the daemon is not running")
+	}
+}
+
+func newEventsTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "events", RunE: runEventsShow}
+	cmd.Flags().StringSlice("type", nil, "")
+	cmd.Flags().Int("tail", 0, "")
+	return cmd
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var exceptionsCmd = &cobra.Command{
+	Use:   "exceptions",
+	Short: "Capture exceptions with their stack and local variables",
+	Long: `Pauses the page on exceptions through the debugger, captures the exception,
+its call stack, and the local variables of the frame that threw, and resumes
+straight away. This gives far more context than the console's error line:
+the values that led to the failure, not just the message.
+
+Capture is off until 'exceptions pause' turns it on. It applies to every tab,
+including tabs opened later, until turned off or the daemon stops. The
+daemon keeps the last 200 captured exceptions.
+
+Without a subcommand, same as 'exceptions show'.
+
+Subcommands:
+  pause on|uncaught|off   Capture every exception, only uncaught ones, or none
+  show                    Print the captured exceptions, oldest first
+
+Examples:
+  exceptions pause uncaught
+  navigate https://example.com/checkout
+  exceptions show
+  exceptions --json | jq '.exceptions[-1].locals'
+  exceptions pause off`,
+	Args: cobra.NoArgs,
+	RunE: runExceptionsShow,
+}
+
+var exceptionsPauseCmd = &cobra.Command{
+	Use:   "pause on|uncaught|off",
+	Short: "Set which exceptions are captured",
+	Long: `Sets which exceptions pause the page to be captured:
+  on         every exception, including ones the page catches
+  uncaught   exceptions nothing catches, and unhandled promise rejections
+  off        none; the debugger is released unless logpoints need it
+
+Each capture pauses the page only while its locals are read. With 'on',
+libraries that throw and catch as part of normal operation are captured too.
+
+Response:
+  Pausing on uncaught exceptions`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"on", "uncaught", "off"},
+	RunE:      runExceptionsPause,
+}
+
+var exceptionsShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the captured exceptions",
+	Long: `Prints the captured exceptions, oldest first, each with its call stack and
+the local variables of the frame that threw.
+
+Response:
+  3 14:05:02.904 Uncaught TypeError: Cannot read properties of undefined (reading 'id')
+         stack:
+           loadUser https://example.com/app.js:119:14
+           <anonymous> https://example.com/app.js:140:3
+         locals:
+           user = undefined
+           id = 42`,
+	Args: cobra.NoArgs,
+	RunE: runExceptionsShow,
+}
+
+func init() {
+	exceptionsCmd.AddCommand(exceptionsPauseCmd, exceptionsShowCmd)
+	rootCmd.AddCommand(exceptionsCmd)
+}
+
+func runExceptionsPause(cmd *cobra.Command, args []string) error {
+	t := startTimer("exceptions pause")
+	defer t.log()
+
+	mode := args[0]
+	if mode != "on" && mode != "uncaught" && mode != "off" {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid mode %q (use on, uncaught, or off)", mode))
+	}
+	debugParam("mode=%s", mode)
+
+	data, err := exceptionsRequest(ipc.ExceptionsParams{Action: "pause", Mode: mode})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":   true,
+			"mode": data.Mode,
+		})
+	}
+	switch data.Mode {
+	case "on":
+		fmt.Fprintln(os.Stdout, "Pausing on all exceptions")
+	case "uncaught":
+		fmt.Fprintln(os.Stdout, "Pausing on uncaught exceptions")
+	default:
+		fmt.Fprintln(os.Stdout, "Not pausing on exceptions")
+	}
+	return nil
+}
+
+func runExceptionsShow(cmd *cobra.Command, args []string) error {
+	t := startTimer("exceptions show")
+	defer t.log()
+
+	data, err := exceptionsRequest(ipc.ExceptionsParams{Action: "show"})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		exceptions := data.Exceptions
+		if exceptions == nil {
+			exceptions = []ipc.PausedException{}
+		}
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":         true,
+			"mode":       data.Mode,
+			"exceptions": exceptions,
+			"dropped":    data.Dropped,
+		})
+	}
+	if len(data.Exceptions) == 0 && data.Mode == "off" {
+		outputHint("capture is off; turn it on with: webctl exceptions pause on")
+	}
+	return format.PausedExceptions(os.Stdout, data.Exceptions)
+}
+
+// exceptionsRequest sends an exceptions action to the daemon.
+func exceptionsRequest(params ipc.ExceptionsParams) (ipc.ExceptionsData, error) {
+	var data ipc.ExceptionsData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("exceptions", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "exceptions", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
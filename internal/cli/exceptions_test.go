@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func TestRunExceptionsPause(t *testing.T) {
+	exec := expectParamsExecutor(t, "exceptions", ipc.ExceptionsParams{Action: "pause", Mode: "uncaught"}, ipc.SuccessResponse(ipc.ExceptionsData{Mode: "uncaught"}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runExceptionsPause(&cobra.Command{}, []string{"uncaught"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "Pausing on uncaught exceptions\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunExceptionsShow(t *testing.T) {
+	exec := expectParamsExecutor(t, "exceptions", ipc.ExceptionsParams{Action: "show"}, ipc.SuccessResponse(ipc.ExceptionsData{
+		Mode: "on",
+		Exceptions: []ipc.PausedException{{
+			Seq:      3,
+			Uncaught: true,
+			Message:  "TypeError: boom",
+			Stack:    []ipc.ConsoleFrame{{Function: "loadUser", URL: "https://example.com/app.js", Line: 119, Column: 14}},
+			Locals:   []ipc.ConsolePreviewProp{{Name: "id", Type: "number", Value: "42"}, {Name: "name", Type: "string", Value: "ann"}},
+		}},
+	}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runExceptionsShow(&cobra.Command{}, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Uncaught TypeError: boom", "loadUser https://example.com/app.js:119:14", "id = 42", `name = "ann"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunExceptionsShow_OffHint(t *testing.T) {
+	exec := expectParamsExecutor(t, "exceptions", ipc.ExceptionsParams{Action: "show"}, ipc.SuccessResponse(ipc.ExceptionsData{Mode: "off"}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var stderr string
+	out := captureStream(t, &os.Stdout, func() {
+		stderr = captureStream(t, &os.Stderr, func() {
+			_ = runExceptionsShow(&cobra.Command{}, nil)
+		})
+	})
+	if out != "No exceptions captured\n" || !strings.Contains(stderr, "exceptions pause on") {
+		t.Errorf("output = %q, stderr = %q", out, stderr)
+	}
+}
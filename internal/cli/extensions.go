@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var extensionsCmd = &cobra.Command{
+	Use:   "extensions",
+	Short: "List browser extensions",
+	Long: `Lists the browser's extensions.
+
+Without a subcommand, same as 'extensions list'.
+
+Subcommands:
+  list   List extensions with their IDs
+
+Extensions are loaded for development with 'webctl start --load-extension DIR'.
+Each is listed with its ID, which is what chrome-extension:// URLs use, e.g.
+'webctl navigate chrome-extension://<id>/popup.html' to test a popup as a page.
+Extensions installed in the profile are listed while they have a service
+worker or page open.`,
+	Args: cobra.NoArgs,
+	RunE: runExtensionsList,
+}
+
+var extensionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List extensions with their IDs",
+	Long: `Lists each extension loaded with --load-extension, by ID, name, version, and
+directory, followed by any other extension that has a service worker or page
+open. An extension with nothing open is marked idle: Chrome stops an idle
+extension's service worker until an event wakes it.`,
+	Args: cobra.NoArgs,
+	RunE: runExtensionsList,
+}
+
+func init() {
+	extensionsCmd.AddCommand(extensionsListCmd)
+	rootCmd.AddCommand(extensionsCmd)
+}
+
+func runExtensionsList(cmd *cobra.Command, args []string) error {
+	t := startTimer("extensions list")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.ExtensionsParams{Action: "list"})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("extensions", "action=list")
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "extensions", Params: params})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.ExtensionsData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":         true,
+			"extensions": data.Extensions,
+		})
+	}
+	return format.Extensions(os.Stdout, data.Extensions, format.NewOutputOptions(JSONOutput, NoColor))
+}
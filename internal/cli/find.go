@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// textMatcher is the --find test shared by console and network: a
+// case-insensitive substring or, with --regex, a regular expression, either
+// of which --invert turns into an exclusion.
+type textMatcher struct {
+	pattern string
+	lower   string
+	re      *regexp.Regexp
+	invert  bool
+}
+
+// newTextMatcher builds the matcher for --find pattern. A regex is
+// case-insensitive like the substring search unless it sets its own flags.
+func newTextMatcher(pattern string, regex, invert bool) (textMatcher, error) {
+	m := textMatcher{pattern: pattern, invert: invert}
+	if !regex {
+		m.lower = strings.ToLower(pattern)
+		return m, nil
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return m, fmt.Errorf("invalid --find regex: %v", err)
+	}
+	m.re = re
+	return m, nil
+}
+
+// substringMatcher is the plain --find matcher.
+func substringMatcher(text string) textMatcher {
+	m, _ := newTextMatcher(text, false, false)
+	return m
+}
+
+// matchAny reports whether an entry with these fields passes the filter: any
+// field matches, or with --invert, none does.
+func (m textMatcher) matchAny(fields ...string) bool {
+	for _, f := range fields {
+		if m.match(f) {
+			return !m.invert
+		}
+	}
+	return m.invert
+}
+
+func (m textMatcher) match(s string) bool {
+	if m.re != nil {
+		return m.re.MatchString(s)
+	}
+	return strings.Contains(strings.ToLower(s), m.lower)
+}
+
+// String describes the filter for debug output.
+func (m textMatcher) String() string {
+	s := fmt.Sprintf("--find %q", m.pattern)
+	if m.re != nil {
+		s += " --regex"
+	}
+	if m.invert {
+		s += " --invert"
+	}
+	return s
+}
+
+// findFromFlags reads --find, --regex, and --invert from cmd, its persistent
+// flags, or its parent's persistent flags. It returns ok false when --find is
+// not set.
+func findFromFlags(cmd *cobra.Command) (m textMatcher, ok bool, err error) {
+	find, _ := cmd.Flags().GetString("find")
+	if find == "" {
+		find, _ = cmd.PersistentFlags().GetString("find")
+	}
+	if find == "" && cmd.Parent() != nil {
+		find, _ = cmd.Parent().PersistentFlags().GetString("find")
+	}
+
+	regex, _ := cmd.Flags().GetBool("regex")
+	if !regex {
+		regex, _ = cmd.PersistentFlags().GetBool("regex")
+	}
+	if !regex && cmd.Parent() != nil {
+		regex, _ = cmd.Parent().PersistentFlags().GetBool("regex")
+	}
+
+	invert, _ := cmd.Flags().GetBool("invert")
+	if !invert {
+		invert, _ = cmd.PersistentFlags().GetBool("invert")
+	}
+	if !invert && cmd.Parent() != nil {
+		invert, _ = cmd.Parent().PersistentFlags().GetBool("invert")
+	}
+
+	if find == "" {
+		if regex || invert {
+			return m, false, fmt.Errorf("--regex and --invert require --find")
+		}
+		return m, false, nil
+	}
+	m, err = newTextMatcher(find, regex, invert)
+	return m, err == nil, err
+}
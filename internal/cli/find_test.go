@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestTextMatcher(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		regex, invert bool
+		fields        []string
+		want          bool
+	}{
+		{"substring", "error", false, false, []string{"TypeError: x"}, true},
+		{"substring is literal", "a.c", false, false, []string{"abc"}, false},
+		{"substring any field", "vendor", false, false, []string{"boom", "https://x/vendor.js"}, true},
+		{"regex", `timeout|refused`, true, false, []string{"connection refused"}, true},
+		{"regex case-insensitive", `^typeerror`, true, false, []string{"TypeError: x"}, true},
+		{"regex own flags", `(?-i)^typeerror`, true, false, []string{"TypeError: x"}, false},
+		{"regex no match", `^\d+$`, true, false, []string{"12a"}, false},
+		{"invert excludes match", "vendor.js", false, true, []string{"boom", "https://x/vendor.js"}, false},
+		{"invert keeps non-match", "vendor.js", false, true, []string{"boom", "https://x/app.js"}, true},
+		{"invert regex", `vendor\.js$`, true, true, []string{"boom", "https://x/app.js"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newTextMatcher(tt.pattern, tt.regex, tt.invert)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := m.matchAny(tt.fields...); got != tt.want {
+				t.Errorf("matchAny(%q) = %v, want %v", tt.fields, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTextMatcher_InvalidRegex(t *testing.T) {
+	_, err := newTextMatcher("(unclosed", true, false)
+	if err == nil || !strings.Contains(err.Error(), "invalid --find regex") {
+		t.Errorf("error = %v, want invalid --find regex", err)
+	}
+	if _, err := newTextMatcher("(unclosed", false, false); err != nil {
+		t.Errorf("substring pattern should not be compiled: %v", err)
+	}
+}
+
+func TestFilterByText_Invert(t *testing.T) {
+	m, _ := newTextMatcher("vendor.js", false, true)
+
+	console := filterConsoleByText([]ipc.ConsoleEntry{
+		{Seq: 1, Type: "error", Text: "boom", URL: "https://x/vendor.js"},
+		{Seq: 2, Type: "error", Text: "bang", URL: "https://x/app.js"},
+	}, m)
+	if len(console) != 1 || console[0].Seq != 2 {
+		t.Errorf("console kept %+v, want seq 2", console)
+	}
+
+	network := filterNetworkByText([]ipc.NetworkEntry{
+		{Seq: 1, URL: "https://x/vendor.js"},
+		{Seq: 2, URL: "https://x/app.js"},
+	}, m)
+	if len(network) != 1 || network[0].Seq != 2 {
+		t.Errorf("network kept %+v, want seq 2", network)
+	}
+}
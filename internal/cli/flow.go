@@ -0,0 +1,443 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/executor"
+	"github.com/grantcarthew/webctl/internal/flow"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var flowCmd = &cobra.Command{
+	Use:   "flow",
+	Short: "Run scripted browser flows from a steps file",
+	Long: `Runs flows: YAML files listing browser steps to take in order, with
+variables, retries, and a pass/fail report. A middle ground between a shell
+script of webctl commands and a test framework.
+
+Subcommands:
+  run <file>    Run a flow and report each step`,
+}
+
+var flowRunCmd = &cobra.Command{
+	Use:   "run <file>",
+	Short: "Run a flow file and report each step",
+	Long: `Runs the steps of a flow file against the active tab, stopping at the first
+step that fails, and reports each step as pass, fail, or skip.
+
+Flow file:
+  name: login                      # Defaults to the file name
+  vars:
+    base: http://localhost:3000
+  timeout: 30s                     # Per-step default (30s)
+  steps:
+    - navigate: ${base}/login      # Waits for the page to load
+    - fill: {selector: "#email", text: "${USER}@example.com"}
+    - fill: {selector: "#password", text: "${PASSWORD}", secret: true}
+    - key: Enter                   # Press a key in the focused element
+    - click: "button[type=submit]"
+      name: submit login           # Report label (default: action and target)
+    - wait: .dashboard             # Selector, or {network-idle: true}, {eval: ...}
+    - assert: {selector: h1, text: Welcome}
+      retries: 5                   # Attempts after the first failure
+      delay: 1s                    # Between attempts (500ms)
+    - assert: {url: /dashboard}    # Or {title: ...}, {eval: ...}, or an expression
+    - screenshot: dashboard.png    # Or {path: ..., full-page: true}
+
+Each step has exactly one action. ${name} is replaced from --var, then vars,
+then the environment; an undefined name is an error before anything runs.
+
+Artifacts:
+  Each step that produces files gets a directory under the artifacts directory
+  (--artifacts, default /tmp/webctl-flows/YY-MM-DD-HHMMSS-{name}/), named after
+  its number and title. Screenshots are saved there, a failing step adds
+  failure.png, and report.json holds the --json report.
+
+Flags:
+  --var key=value      Set a variable, overriding the file (repeatable)
+  --artifacts <dir>    Directory for screenshots and the report
+
+Examples:
+  webctl flow run login.yaml
+  webctl flow run login.yaml --var base=https://staging.example.com
+  webctl flow run smoke.yaml --artifacts ./artifacts --json
+
+Exit status is 0 when every step passes and 1 otherwise.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFlowRun,
+}
+
+func init() {
+	flowRunCmd.Flags().StringArray("var", nil, "Set a flow variable as key=value (repeatable)")
+	flowRunCmd.Flags().String("artifacts", "", "Directory for step artifacts and report.json (default /tmp/webctl-flows/...)")
+	flowCmd.AddCommand(flowRunCmd)
+	rootCmd.AddCommand(flowCmd)
+}
+
+// Flow step outcomes.
+const (
+	flowPass = "pass"
+	flowFail = "fail"
+	flowSkip = "skip"
+)
+
+// flowStepResult is one step's outcome in the report.
+type flowStepResult struct {
+	Index      int      `json:"index"`
+	Name       string   `json:"name"`
+	Action     string   `json:"action"`
+	Status     string   `json:"status"` // pass, fail, or skip
+	Attempts   int      `json:"attempts,omitempty"`
+	DurationMs int64    `json:"durationMs"`
+	Error      string   `json:"error,omitempty"`
+	Artifacts  []string `json:"artifacts,omitempty"`
+}
+
+// flowReport is the outcome of a flow run.
+type flowReport struct {
+	Flow       string           `json:"flow"`
+	Passed     int              `json:"passed"`
+	Failed     int              `json:"failed"`
+	Skipped    int              `json:"skipped"`
+	DurationMs int64            `json:"durationMs"`
+	Artifacts  string           `json:"artifacts"`
+	Steps      []flowStepResult `json:"steps"`
+}
+
+func runFlowRun(cmd *cobra.Command, args []string) error {
+	t := startTimer("flow run")
+	defer t.log()
+
+	vars, err := parseFlowVars(cmd)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	f, err := flow.Load(args[0])
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if f, err = f.Expand(vars); err != nil {
+		return outputError(err.Error())
+	}
+	artifacts, _ := cmd.Flags().GetString("artifacts")
+	if artifacts == "" {
+		artifacts = filepath.Join("/tmp/webctl-flows", time.Now().Format("06-01-02-150405")+"-"+normalizeTitle(f.Name))
+	}
+	debugParam("flow=%q steps=%d artifacts=%q vars=%d", f.Name, len(f.Steps), artifacts, len(vars))
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	var progress io.Writer = os.Stdout
+	if JSONOutput {
+		progress = io.Discard
+	}
+	report := runFlow(exec, f, artifacts, progress, format.NewOutputOptions(JSONOutput, NoColor).UseColor)
+
+	// The report is kept with the artifacts, so a CI job that uploads the
+	// directory has the outcome alongside the screenshots
+	if data, err := json.MarshalIndent(report, "", "  "); err == nil {
+		if err := os.MkdirAll(artifacts, 0755); err == nil {
+			_ = os.WriteFile(filepath.Join(artifacts, "report.json"), append(data, '\n'), 0644)
+		}
+	}
+
+	if JSONOutput {
+		if err := outputJSON(os.Stdout, struct {
+			OK bool `json:"ok"`
+			flowReport
+		}{report.Failed == 0, report}); err != nil {
+			return err
+		}
+	} else {
+		writeFlowSummary(os.Stdout, report)
+	}
+
+	if report.Failed > 0 {
+		return printedError{err: fmt.Errorf("flow %q failed", report.Flow)}
+	}
+	return nil
+}
+
+// parseFlowVars reads the --var key=value flags.
+func parseFlowVars(cmd *cobra.Command) (map[string]string, error) {
+	pairs, _ := cmd.Flags().GetStringArray("var")
+	vars := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		key, value, ok := strings.Cut(p, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q: expected key=value", p)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// runFlow runs the steps in order, writing a line per step to w as it
+// finishes. After a step fails, the rest are skipped.
+func runFlow(exec executor.Executor, f *flow.Flow, artifacts string, w io.Writer, useColor bool) flowReport {
+	start := time.Now()
+	report := flowReport{Flow: f.Name, Artifacts: artifacts}
+
+	timeout := f.Timeout
+	if timeout == 0 {
+		timeout = flow.DefaultTimeout
+	}
+
+	failed := false
+	for i, s := range f.Steps {
+		res := flowStepResult{Index: i + 1, Name: s.Title(), Action: s.Action()}
+		if failed {
+			res.Status = flowSkip
+			report.Skipped++
+			report.Steps = append(report.Steps, res)
+			writeFlowStep(w, res, useColor)
+			continue
+		}
+
+		stepTimeout := timeout
+		if s.Timeout > 0 {
+			stepTimeout = s.Timeout
+		}
+		delay := s.Delay
+		if delay == 0 {
+			delay = flow.DefaultRetryDelay
+		}
+		dir := filepath.Join(artifacts, fmt.Sprintf("%02d-%s", i+1, normalizeTitle(res.Name)))
+
+		stepStart := time.Now()
+		var err error
+		for res.Attempts = 1; ; res.Attempts++ {
+			var files []string
+			files, err = runFlowStep(exec, s, stepTimeout, dir)
+			res.Artifacts = append(res.Artifacts, files...)
+			if err == nil || res.Attempts > s.Retries {
+				break
+			}
+			debugf("FLOW", "step %d attempt %d failed: %v", i+1, res.Attempts, err)
+			time.Sleep(delay)
+		}
+		res.DurationMs = time.Since(stepStart).Milliseconds()
+
+		if err != nil {
+			failed = true
+			res.Status = flowFail
+			res.Error = err.Error()
+			report.Failed++
+			if path, err := flowFailureScreenshot(exec, dir); err == nil {
+				res.Artifacts = append(res.Artifacts, path)
+			}
+		} else {
+			res.Status = flowPass
+			report.Passed++
+		}
+		report.Steps = append(report.Steps, res)
+		writeFlowStep(w, res, useColor)
+	}
+
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report
+}
+
+// runFlowStep performs one attempt of a step, returning the files it wrote.
+func runFlowStep(exec executor.Executor, s flow.Step, timeout time.Duration, dir string) ([]string, error) {
+	secs := int(math.Ceil(timeout.Seconds()))
+	switch s.Action() {
+	case "navigate":
+		return nil, flowRequest(exec, "navigate", ipc.NavigateParams{URL: normalizeURL(s.Navigate), Wait: true, Timeout: secs}, nil)
+	case "wait":
+		return nil, flowRequest(exec, "ready", ipc.ReadyParams{Timeout: secs, Selector: s.Wait.Selector, NetworkIdle: s.Wait.NetworkIdle, Eval: s.Wait.Eval}, nil)
+	case "click":
+		return nil, flowRequest(exec, "click", ipc.ClickParams{Selector: s.Click}, nil)
+	case "fill":
+		params := ipc.TypeParams{Selector: s.Fill.Selector, Text: s.Fill.Text, Clear: true}
+		if s.Fill.Secret {
+			return nil, flowSecretFill(exec, params)
+		}
+		return nil, flowRequest(exec, "type", params, nil)
+	case "key":
+		return nil, flowRequest(exec, "key", ipc.KeyParams{Key: s.Key}, nil)
+	case "assert":
+		var data struct {
+			Value struct {
+				OK      bool   `json:"ok"`
+				Message string `json:"message"`
+			} `json:"value"`
+		}
+		if err := flowRequest(exec, "eval", ipc.EvalParams{Expression: flowAssertJS(*s.Assert), Timeout: secs}, &data); err != nil {
+			return nil, err
+		}
+		if !data.Value.OK {
+			return nil, errors.New(data.Value.Message)
+		}
+		return nil, nil
+	case "screenshot":
+		path := filepath.Join(dir, s.Screenshot.Path)
+		if err := flowScreenshot(exec, path, s.Screenshot.FullPage); err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+	return nil, fmt.Errorf("unknown step action")
+}
+
+// flowRequest sends a step's request, decoding the response data into out
+// when it is not nil. A failed response is returned as an error.
+func flowRequest(exec executor.Executor, cmd string, params any, out any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return flowSend(exec, cmd, raw, string(raw), out)
+}
+
+// flowSecretFill sends a secret fill step's request, logging the selector
+// but not the text.
+func flowSecretFill(exec executor.Executor, params ipc.TypeParams) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return flowSend(exec, "type", raw, fmt.Sprintf("selector=%q text=<secret>", params.Selector), nil)
+}
+
+// flowSend sends a request with raw params, logging them as logged.
+func flowSend(exec executor.Executor, cmd string, raw json.RawMessage, logged string, out any) error {
+	debugRequest(cmd, logged)
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: cmd, Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+	if out != nil {
+		return json.Unmarshal(resp.Data, out)
+	}
+	return nil
+}
+
+// flowScreenshot saves a screenshot of the page at path.
+func flowScreenshot(exec executor.Executor, path string, fullPage bool) error {
+	params, err := json.Marshal(ipc.ScreenshotParams{FullPage: fullPage})
+	if err != nil {
+		return err
+	}
+	resp, err := writeScreenshot(exec, params, path)
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// flowFailureScreenshot saves failure.png in a failed step's directory, to
+// show the page as the step left it.
+func flowFailureScreenshot(exec executor.Executor, dir string) (string, error) {
+	path := filepath.Join(dir, "failure.png")
+	return path, flowScreenshot(exec, path, false)
+}
+
+// flowAssertJS builds the expression for an assert step. It evaluates to
+// {ok, message}, the message saying what was found instead.
+func flowAssertJS(a flow.Assert) string {
+	q := func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b)
+	}
+	switch {
+	case a.Selector != "":
+		return fmt.Sprintf(`(() => {
+  const el = document.querySelector(%[1]s);
+  if (!el) return {ok: false, message: 'no element matches ' + %[1]s};
+  const text = (el.innerText ?? el.textContent ?? '').trim();
+  if (!text.includes(%[2]s)) return {ok: false, message: 'element text ' + JSON.stringify(text.slice(0, 200)) + ' does not contain ' + JSON.stringify(%[2]s)};
+  return {ok: true};
+})()`, q(a.Selector), q(a.Text))
+	case a.URL != "":
+		return fmt.Sprintf(`(() => location.href.includes(%[1]s)
+  ? {ok: true}
+  : {ok: false, message: 'url ' + JSON.stringify(location.href) + ' does not contain ' + JSON.stringify(%[1]s)})()`, q(a.URL))
+	case a.Title != "":
+		return fmt.Sprintf(`(() => document.title.includes(%[1]s)
+  ? {ok: true}
+  : {ok: false, message: 'title ' + JSON.stringify(document.title) + ' does not contain ' + JSON.stringify(%[1]s)})()`, q(a.Title))
+	}
+	return fmt.Sprintf(`(async () => {
+  const value = await (%s
+  );
+  return value ? {ok: true} : {ok: false, message: 'expression is ' + String(value)};
+})()`, a.Eval)
+}
+
+// writeFlowStep writes one step's line of the text report, with its error
+// and artifacts on indented lines.
+func writeFlowStep(w io.Writer, r flowStepResult, useColor bool) {
+	status := fmt.Sprintf("%-4s", strings.ToUpper(r.Status))
+	if useColor {
+		attr := color.FgGreen
+		switch r.Status {
+		case flowFail:
+			attr = color.FgRed
+		case flowSkip:
+			attr = color.Faint
+		}
+		status = color.New(attr).Sprint(status)
+	}
+
+	detail := ""
+	if r.Status != flowSkip {
+		detail = fmt.Sprintf(" (%s", formatFlowDuration(r.DurationMs))
+		if r.Attempts > 1 {
+			detail += fmt.Sprintf(", %d attempts", r.Attempts)
+		}
+		detail += ")"
+	}
+	_, _ = fmt.Fprintf(w, "%s %2d %s%s\n", status, r.Index, r.Name, detail)
+	if r.Error != "" {
+		_, _ = fmt.Fprintf(w, "        %s\n", r.Error)
+	}
+	for _, a := range r.Artifacts {
+		_, _ = fmt.Fprintf(w, "        artifact: %s\n", a)
+	}
+}
+
+// writeFlowSummary writes the closing lines of the text report.
+func writeFlowSummary(w io.Writer, r flowReport) {
+	_, _ = fmt.Fprintf(w, "Flow %q: %d passed, %d failed, %d skipped (%s)\n",
+		r.Flow, r.Passed, r.Failed, r.Skipped, formatFlowDuration(r.DurationMs))
+	_, _ = fmt.Fprintf(w, "Artifacts: %s\n", r.Artifacts)
+}
+
+// formatFlowDuration shows milliseconds under a second, else seconds.
+func formatFlowDuration(ms int64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", ms)
+	}
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
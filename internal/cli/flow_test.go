@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/flow"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// flowDaemon answers flow requests: eval asserts return assertOK, clicks on
+// "#missing" fail, and screenshots return a fake PNG. It records each
+// request's command.
+func flowDaemon(assertOK func() bool) (*mockExecutor, *[]string) {
+	var cmds []string
+	return &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		cmds = append(cmds, req.Cmd)
+		switch req.Cmd {
+		case "click":
+			var p ipc.ClickParams
+			_ = json.Unmarshal(req.Params, &p)
+			if p.Selector == "#missing" {
+				return ipc.ErrorResponse("element not found: #missing"), nil
+			}
+		case "eval":
+			if assertOK() {
+				return ipc.SuccessResponse(ipc.EvalData{Value: map[string]any{"ok": true}, HasValue: true}), nil
+			}
+			return ipc.SuccessResponse(ipc.EvalData{Value: map[string]any{"ok": false, "message": "title \"Home\" does not contain \"Dash\""}, HasValue: true}), nil
+		case "screenshot":
+			return ipc.SuccessResponse(ipc.ScreenshotData{Data: []byte("\x89PNG")}), nil
+		}
+		return ipc.SuccessResponse(nil), nil
+	}}, &cmds
+}
+
+func parseFlow(t *testing.T, src string) *flow.Flow {
+	t.Helper()
+	f, err := flow.Parse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Name = "test"
+	return f
+}
+
+func TestRunFlow_Pass(t *testing.T) {
+	exec, cmds := flowDaemon(func() bool { return true })
+	dir := t.TempDir()
+	f := parseFlow(t, `
+steps:
+  - navigate: example.com
+  - fill: {selector: "#q", text: hello}
+  - click: "#go"
+  - wait: .results
+  - assert: {title: Results}
+  - screenshot: results.png
+`)
+
+	var out bytes.Buffer
+	report := runFlow(exec, f, dir, &out, false)
+
+	if report.Passed != 6 || report.Failed != 0 || report.Skipped != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	want := []string{"navigate", "type", "click", "ready", "eval", "screenshot"}
+	if strings.Join(*cmds, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %q, want %q", *cmds, want)
+	}
+	shot := filepath.Join(dir, "06-screenshot-results-png", "results.png")
+	if got := report.Steps[5].Artifacts; len(got) != 1 || got[0] != shot {
+		t.Errorf("screenshot artifacts = %q, want %s", got, shot)
+	}
+	if _, err := os.Stat(shot); err != nil {
+		t.Errorf("screenshot not written: %v", err)
+	}
+	if !strings.Contains(out.String(), "PASS  1 navigate example.com") {
+		t.Errorf("text report:\n%s", out.String())
+	}
+}
+
+func TestRunFlow_FailSkipsRest(t *testing.T) {
+	exec, cmds := flowDaemon(func() bool { return true })
+	dir := t.TempDir()
+	f := parseFlow(t, `
+steps:
+  - click: "#missing"
+    retries: 2
+    delay: 1ms
+  - navigate: example.com
+`)
+
+	var out bytes.Buffer
+	report := runFlow(exec, f, dir, &out, false)
+
+	if report.Failed != 1 || report.Skipped != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	failed := report.Steps[0]
+	if failed.Attempts != 3 || failed.Error != "element not found: #missing" {
+		t.Errorf("failed step = %+v", failed)
+	}
+	if len(failed.Artifacts) != 1 || filepath.Base(failed.Artifacts[0]) != "failure.png" {
+		t.Errorf("failed step artifacts = %q, want failure.png", failed.Artifacts)
+	}
+	if report.Steps[1].Status != flowSkip {
+		t.Errorf("step after failure = %+v, want skip", report.Steps[1])
+	}
+	for _, c := range *cmds {
+		if c == "navigate" {
+			t.Error("a skipped step should not run")
+		}
+	}
+	if !strings.Contains(out.String(), "3 attempts") || !strings.Contains(out.String(), "SKIP  2") {
+		t.Errorf("text report:\n%s", out.String())
+	}
+}
+
+func TestRunFlow_RetryPasses(t *testing.T) {
+	calls := 0
+	exec, _ := flowDaemon(func() bool { calls++; return calls == 2 })
+	f := parseFlow(t, "steps:\n  - assert: {title: Dash}\n    retries: 3\n    delay: 1ms\n")
+
+	report := runFlow(exec, f, t.TempDir(), &bytes.Buffer{}, false)
+	if report.Passed != 1 || report.Steps[0].Attempts != 2 {
+		t.Errorf("report = %+v, want a pass on the second attempt", report)
+	}
+}
+
+func TestRunFlowRun_JSONReport(t *testing.T) {
+	exec, _ := flowDaemon(func() bool { return false })
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+	defer func(j bool) { JSONOutput = j }(JSONOutput)
+	JSONOutput = true
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "check.yaml")
+	if err := os.WriteFile(file, []byte("steps:\n  - navigate: ${site}\n  - assert: {title: Dash}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	artifacts := filepath.Join(dir, "artifacts")
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("var", nil, "")
+	cmd.Flags().String("artifacts", "", "")
+	_ = cmd.Flags().Set("var", "site=example.com")
+	_ = cmd.Flags().Set("artifacts", artifacts)
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runFlowRun(cmd, []string{file})
+	})
+	if err == nil || !IsPrintedError(err) {
+		t.Fatalf("error = %v, want a printed failure", err)
+	}
+
+	var report struct {
+		OK bool `json:"ok"`
+		flowReport
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if report.OK || report.Flow != "check" || report.Passed != 1 || report.Failed != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.Steps[0].Name != "navigate example.com" {
+		t.Errorf("variable not expanded: %q", report.Steps[0].Name)
+	}
+	if _, err := os.Stat(filepath.Join(artifacts, "report.json")); err != nil {
+		t.Errorf("report.json not written: %v", err)
+	}
+}
+
+func TestParseFlowVars(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("var", nil, "")
+	_ = cmd.Flags().Set("var", "a=1=2")
+	vars, err := parseFlowVars(cmd)
+	if err != nil || vars["a"] != "1=2" {
+		t.Errorf("vars = %v, %v", vars, err)
+	}
+
+	_ = cmd.Flags().Set("var", "novalue")
+	if _, err := parseFlowVars(cmd); err == nil {
+		t.Error("--var without = should fail")
+	}
+}
@@ -27,7 +27,7 @@ func runFocus(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	selector := args[0]
@@ -62,9 +62,9 @@ func runFocus(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoElementsError(resp.Error) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 		}
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: output JSON
@@ -130,3 +130,105 @@ func MatchedRules(w io.Writer, rules []ipc.CSSMatchedRule) error {
 	}
 	return nil
 }
+
+// StyleDiff outputs computed properties that differ between two elements or
+// two states. The header names both sides; each property line shows the A value
+// then the B value. Missing values are shown as (none).
+//
+// Format:
+//
+//	--- #primary
+//	+++ .card .btn
+//	color: rgb(0, 0, 0) -> rgb(255, 0, 0)
+//	padding-top: 8px -> 12px
+func StyleDiff(w io.Writer, labelA, labelB string, diffs []ipc.CSSPropertyDiff) error {
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", labelA, labelB); err != nil {
+		return err
+	}
+	for _, d := range diffs {
+		if _, err := fmt.Fprintf(w, "%s: %s -> %s\n", d.Property, noneIfEmpty(d.A), noneIfEmpty(d.B)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// noneIfEmpty renders an empty value as (none).
+func noneIfEmpty(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// StyleSheets outputs one line per stylesheet: index, size, rule count, and
+// source. Inline <style> elements show as <inline>; sheets with neither a URL
+// nor an owning element (constructed stylesheets) show as <constructed>.
+//
+// Format:
+//
+//	1  48.2KB  612 rules  https://example.com/css/app.css
+//	2  312B    4 rules    <inline>
+func StyleSheets(w io.Writer, sheets []ipc.CSSStyleSheet) error {
+	for _, s := range sheets {
+		rules := fmt.Sprintf("%d rules", s.Rules)
+		if s.Rules == 1 {
+			rules = "1 rule"
+		}
+		if _, err := fmt.Fprintf(w, "%d  %-6s  %-9s  %s\n", s.Index, formatBytes(int64(s.Size)), rules, StyleSheetSource(s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StyleSheetSource returns the URL of a stylesheet, or a placeholder for
+// sheets without one.
+func StyleSheetSource(s ipc.CSSStyleSheet) string {
+	switch {
+	case s.URL != "":
+		return s.URL
+	case s.Inline:
+		return "<inline>"
+	default:
+		return "<constructed>"
+	}
+}
+
+// UnusedCSS outputs the unused rule report per stylesheet, separated by "--".
+// Each block names the stylesheet, summarises unused bytes and rules, then
+// lists unused selectors with their rule size unless summary is set.
+//
+// Format:
+//
+//	https://example.com/css/app.css
+//	unused: 18.2KB of 48.2KB (37.8%), 210 of 612 rules
+//	.legacy-banner  120B
+//	.modal.open .close  64B
+func UnusedCSS(w io.Writer, sheets []ipc.CSSUnusedSheet, summary bool) error {
+	for i, s := range sheets {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w, ipc.MultiElementSeparator); err != nil {
+				return err
+			}
+		}
+		pct := 0.0
+		if s.Size > 0 {
+			pct = float64(s.UnusedBytes) / float64(s.Size) * 100
+		}
+		if _, err := fmt.Fprintf(w, "%s\nunused: %s of %s (%.1f%%), %d of %d rules\n",
+			StyleSheetSource(s.CSSStyleSheet), formatBytes(int64(s.UnusedBytes)), formatBytes(int64(s.Size)),
+			pct, s.UnusedRules, s.StyleRules); err != nil {
+			return err
+		}
+		if summary {
+			continue
+		}
+		for _, r := range s.Selectors {
+			if _, err := fmt.Fprintf(w, "%s  %s\n", r.Selector, formatBytes(int64(r.Bytes))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
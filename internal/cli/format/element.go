@@ -0,0 +1,221 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// Boxes outputs element geometry with element identifiers and -- separators.
+// Coordinates are CSS pixels; x,y are viewport-relative and page x,y are
+// document-relative.
+//
+// Example output:
+//
+//	#submit
+//	box: 120,340 200x40
+//	page: 120,940
+//	visible: yes
+//	viewport: partial
+func Boxes(w io.Writer, elements []ipc.ElementBox) error {
+	for i, elem := range elements {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w, ipc.MultiElementSeparator); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, FormatElementIdentifier(elem.ElementMeta, i)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "box: %s,%s %sx%s\n",
+			formatPx(elem.X), formatPx(elem.Y), formatPx(elem.Width), formatPx(elem.Height)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "page: %s,%s\n", formatPx(elem.PageX), formatPx(elem.PageY)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "visible: %s\n", yesNo(elem.Visible)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "viewport: %s\n", viewportState(elem)); err != nil {
+			return err
+		}
+		if elem.ScrollTop != 0 || elem.ScrollLeft != 0 {
+			if _, err := fmt.Fprintf(w, "scroll: %s,%s\n", formatPx(elem.ScrollLeft), formatPx(elem.ScrollTop)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// viewportState summarises how much of an element's box is on screen.
+func viewportState(elem ipc.ElementBox) string {
+	switch {
+	case elem.FullyInViewport:
+		return "full"
+	case elem.InViewport:
+		return "partial"
+	default:
+		return "outside"
+	}
+}
+
+// formatPx renders a CSS pixel value without a trailing .00 for whole numbers.
+func formatPx(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// yesNo renders a boolean as yes or no.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// Attributes outputs element attributes with element identifiers and --
+// separators. Attribute names are sorted for stable output.
+//
+// Example output:
+//
+//	#email
+//	name=email
+//	required=
+//	type=email
+func Attributes(w io.Writer, elements []ipc.ElementWithAttributes) error {
+	for i, elem := range elements {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w, ipc.MultiElementSeparator); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w, FormatElementIdentifier(elem.ElementMeta, i)); err != nil {
+			return err
+		}
+		names := make([]string, 0, len(elem.Attributes))
+		for name := range elem.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, err := fmt.Fprintf(w, "%s=%s\n", name, elem.Attributes[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DOMMutations outputs one line per DOM change with a local timestamp and a
+// marker: + added, - removed, ~ attribute or text changed. Absent attribute
+// values (attribute added or removed) print as (none).
+//
+// Example output:
+//
+//	14:03:21.118 + li.item  in ul#results
+//	14:03:21.118 - div.spinner  from main
+//	14:03:21.402 ~ button#save [disabled] "" -> (none)
+//	14:03:22.010 ~ span.count text "3" -> "4"
+func DOMMutations(w io.Writer, mutations []ipc.DOMMutation) error {
+	for _, m := range mutations {
+		ts := time.UnixMilli(m.Time).Local().Format("15:04:05.000")
+		var line string
+		switch m.Type {
+		case "added":
+			line = fmt.Sprintf("%s + %s  in %s", ts, m.Node, m.Target)
+		case "removed":
+			line = fmt.Sprintf("%s - %s  from %s", ts, m.Node, m.Target)
+		case "attribute":
+			line = fmt.Sprintf("%s ~ %s [%s] %s -> %s", ts, m.Target, m.Attribute,
+				mutationValue(m.OldValue), mutationValue(m.Value))
+		case "text":
+			line = fmt.Sprintf("%s ~ %s text %s -> %s", ts, m.Target,
+				mutationValue(m.OldValue), mutationValue(m.Value))
+		default:
+			line = fmt.Sprintf("%s ? %s", ts, m.Target)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LayoutShifts outputs layout shifts, one header line per shift with the
+// elements that moved beneath it, each with its box before and after.
+//
+// Example output:
+//
+//	14:03:21.118  0.0834  at 812ms
+//	       div.banner  0,0 1280x90 -> 0,210 1280x90
+//	14:03:24.502  0.0012  at 4196ms (input)
+func LayoutShifts(w io.Writer, shifts []ipc.LayoutShift) error {
+	for _, s := range shifts {
+		ts := time.UnixMilli(s.Time).Local().Format("15:04:05.000")
+		line := fmt.Sprintf("%s  %.4f  at %.0fms", ts, s.Score, s.StartTime)
+		if s.HadRecentInput {
+			line += " (input)"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+
+		width := 0
+		for _, src := range s.Sources {
+			width = max(width, len(shiftNode(src.Node)))
+		}
+		for _, src := range s.Sources {
+			if _, err := fmt.Fprintf(w, "%s%-*s  %s -> %s\n", netIndent, width, shiftNode(src.Node),
+				shiftRect(src.Previous), shiftRect(src.Current)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LayoutShiftTotal outputs the summed layout shift score, or a note that the
+// page has not shifted.
+func LayoutShiftTotal(w io.Writer, total float64, count int) error {
+	if count == 0 {
+		_, err := fmt.Fprintln(w, "No layout shifts")
+		return err
+	}
+	noun := "shifts"
+	if count == 1 {
+		noun = "shift"
+	}
+	_, err := fmt.Fprintf(w, "total %.4f (%d %s)\n", total, count, noun)
+	return err
+}
+
+// shiftNode names a layout shift source, which is empty once the element has
+// been removed from the page.
+func shiftNode(node string) string {
+	if node == "" {
+		return "(removed)"
+	}
+	return node
+}
+
+// shiftRect formats a layout shift box as x,y WxH.
+func shiftRect(r ipc.LayoutShiftRect) string {
+	return fmt.Sprintf("%s,%s %sx%s", formatPx(r.X), formatPx(r.Y), formatPx(r.Width), formatPx(r.Height))
+}
+
+// mutationValue quotes an attribute or text value, or returns (none) when the
+// value is absent.
+func mutationValue(v *string) string {
+	if v == nil {
+		return "(none)"
+	}
+	return strconv.Quote(*v)
+}
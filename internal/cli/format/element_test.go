@@ -0,0 +1,148 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestBoxes(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []ipc.ElementBox
+		expected string
+	}{
+		{
+			name: "fully visible element",
+			elements: []ipc.ElementBox{{
+				ElementMeta:     ipc.ElementMeta{Tag: "button", ID: "submit"},
+				X:               120,
+				Y:               340,
+				Width:           200,
+				Height:          40,
+				PageX:           120,
+				PageY:           940,
+				Visible:         true,
+				InViewport:      true,
+				FullyInViewport: true,
+			}},
+			expected: "#submit\nbox: 120,340 200x40\npage: 120,940\nvisible: yes\nviewport: full\n",
+		},
+		{
+			name: "fractional values and partial viewport",
+			elements: []ipc.ElementBox{{
+				ElementMeta: ipc.ElementMeta{Tag: "div", Class: "card"},
+				X:           10.5,
+				Y:           -20,
+				Width:       99.25,
+				Height:      50,
+				PageX:       10.5,
+				PageY:       480,
+				Visible:     true,
+				InViewport:  true,
+			}},
+			expected: ".card:1\nbox: 10.50,-20 99.25x50\npage: 10.50,480\nvisible: yes\nviewport: partial\n",
+		},
+		{
+			name: "hidden scroll container with separator",
+			elements: []ipc.ElementBox{
+				{ElementMeta: ipc.ElementMeta{Tag: "div"}},
+				{ElementMeta: ipc.ElementMeta{Tag: "div"}, ScrollTop: 300},
+			},
+			expected: "div:1\nbox: 0,0 0x0\npage: 0,0\nvisible: no\nviewport: outside\n" +
+				"--\n" +
+				"div:2\nbox: 0,0 0x0\npage: 0,0\nvisible: no\nviewport: outside\nscroll: 0,300\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Boxes(&buf, tt.elements); err != nil {
+				t.Fatalf("Boxes() error = %v", err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("Boxes() =\n%q\nwant\n%q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	elements := []ipc.ElementWithAttributes{
+		{
+			ElementMeta: ipc.ElementMeta{Tag: "input", ID: "email"},
+			Attributes:  map[string]string{"type": "email", "id": "email", "required": ""},
+		},
+		{
+			ElementMeta: ipc.ElementMeta{Tag: "input"},
+			Attributes:  map[string]string{},
+		},
+	}
+	expected := "#email\nid=email\nrequired=\ntype=email\n--\ninput:2\n"
+
+	var buf bytes.Buffer
+	if err := Attributes(&buf, elements); err != nil {
+		t.Fatalf("Attributes() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("Attributes() =\n%q\nwant\n%q", got, expected)
+	}
+}
+
+func TestDOMMutations(t *testing.T) {
+	old, val := "", "4"
+	const ms = 1700000000123
+	mutations := []ipc.DOMMutation{
+		{Time: ms, Type: "added", Target: "ul#results", Node: "li.item"},
+		{Time: ms, Type: "removed", Target: "main", Node: "div.spinner"},
+		{Time: ms, Type: "attribute", Target: "button#save", Attribute: "disabled", OldValue: &old},
+		{Time: ms, Type: "text", Target: "span.count", OldValue: &old, Value: &val},
+	}
+	ts := time.UnixMilli(ms).Local().Format("15:04:05.000")
+	expected := ts + " + li.item  in ul#results\n" +
+		ts + " - div.spinner  from main\n" +
+		ts + " ~ button#save [disabled] \"\" -> (none)\n" +
+		ts + " ~ span.count text \"\" -> \"4\"\n"
+
+	var buf bytes.Buffer
+	if err := DOMMutations(&buf, mutations); err != nil {
+		t.Fatalf("DOMMutations() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("DOMMutations() =\n%q\nwant\n%q", got, expected)
+	}
+}
+
+func TestLayoutShifts(t *testing.T) {
+	const ms = 1700000000123
+	shifts := []ipc.LayoutShift{
+		{Time: ms, StartTime: 812.4, Score: 0.08341, Sources: []ipc.LayoutShiftSource{
+			{Node: "div.banner", Previous: ipc.LayoutShiftRect{Width: 1280, Height: 90}, Current: ipc.LayoutShiftRect{Y: 210, Width: 1280, Height: 90}},
+			{Node: "", Previous: ipc.LayoutShiftRect{Y: 90, Width: 1280, Height: 400.5}, Current: ipc.LayoutShiftRect{Y: 300, Width: 1280, Height: 400.5}},
+		}},
+		{Time: ms, StartTime: 4196, Score: 0.0012, HadRecentInput: true},
+	}
+	ts := time.UnixMilli(ms).Local().Format("15:04:05.000")
+	expected := ts + "  0.0834  at 812ms\n" +
+		"       div.banner  0,0 1280x90 -> 0,210 1280x90\n" +
+		"       (removed)   0,90 1280x400.50 -> 0,300 1280x400.50\n" +
+		ts + "  0.0012  at 4196ms (input)\n"
+
+	var buf bytes.Buffer
+	if err := LayoutShifts(&buf, shifts); err != nil {
+		t.Fatalf("LayoutShifts() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("LayoutShifts() =\n%q\nwant\n%q", got, expected)
+	}
+
+	buf.Reset()
+	_ = LayoutShiftTotal(&buf, 0.0834, 2)
+	_ = LayoutShiftTotal(&buf, 0, 0)
+	if got := buf.String(); got != "total 0.0834 (2 shifts)\nNo layout shifts\n" {
+		t.Errorf("LayoutShiftTotal() = %q", got)
+	}
+}
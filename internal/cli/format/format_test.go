@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/grantcarthew/webctl/internal/ipc"
@@ -107,6 +108,11 @@ func TestStatus(t *testing.T) {
 			data:     ipc.StatusData{Running: true, PID: 1234, Sessions: []ipc.PageSession{}},
 			expected: "No browser\npid: 1234\n",
 		},
+		{
+			name:     "browser restarting",
+			data:     ipc.StatusData{Running: true, PID: 1234, Restarting: true},
+			expected: "Browser restarting\npid: 1234\n",
+		},
 		{
 			name: "running with active session",
 			data: ipc.StatusData{
@@ -122,6 +128,96 @@ func TestStatus(t *testing.T) {
 			},
 			expected: "OK\npid: 1234\nsessions:\n  * https://example.com\n",
 		},
+		{
+			name: "browser restarted",
+			data: ipc.StatusData{
+				Running:         true,
+				PID:             1234,
+				ActiveSession:   &ipc.PageSession{ID: "session1", URL: "https://example.com"},
+				Sessions:        []ipc.PageSession{{ID: "session1", URL: "https://example.com", Active: true}},
+				BrowserRestarts: 2,
+			},
+			expected: "OK\npid: 1234\nbrowser restarts: 2\nsessions:\n  * https://example.com\n",
+		},
+		{
+			name: "adopted browser",
+			data: ipc.StatusData{
+				Running:       true,
+				PID:           1234,
+				ActiveSession: &ipc.PageSession{ID: "session1", URL: "https://example.com"},
+				Sessions:      []ipc.PageSession{{ID: "session1", URL: "https://example.com", Active: true}},
+				Adopted:       true,
+			},
+			expected: "OK\npid: 1234\nbrowser: adopted (left running on stop)\nsessions:\n  * https://example.com\n",
+		},
+		{
+			name: "launch settings",
+			data: ipc.StatusData{
+				Running:       true,
+				PID:           1234,
+				Socket:        "/run/user/1000/webctl/webctl.sock",
+				ActiveSession: &ipc.PageSession{ID: "session1", URL: "https://example.com"},
+				Sessions:      []ipc.PageSession{{ID: "session1", URL: "https://example.com", Active: true}},
+				Browser: &ipc.BrowserInfo{
+					Headless:    true,
+					Port:        9222,
+					UserDataDir: "/tmp/webctl-chrome-1",
+					TempProfile: true,
+					LaunchFlags: ipc.LaunchFlags{
+						Lang:           "en-GB",
+						WindowSize:     "1280,800",
+						WindowPosition: "0,0",
+						Incognito:      true,
+						DevTools:       true,
+						ChromeFlags:    []string{"--disable-gpu", "--mute-audio"},
+						CI:             true,
+					},
+				},
+			},
+			expected: "OK\npid: 1234\nsocket: /run/user/1000/webctl/webctl.sock\nbrowser: headless, port 9222\nprofile: /tmp/webctl-chrome-1 (temporary)\n" +
+				"lang: en-GB\nwindow size: 1280x800\nwindow position: 0,0\nincognito: yes\ndevtools: yes\n" +
+				"chrome flags: --disable-gpu --mute-audio\nci: yes\nsessions:\n  * https://example.com\n",
+		},
+		{
+			name: "verbose detail",
+			data: ipc.StatusData{
+				Running:       true,
+				PID:           1234,
+				ActiveSession: &ipc.PageSession{ID: "session1", URL: "https://example.com"},
+				Sessions:      []ipc.PageSession{{ID: "session1", URL: "https://example.com", Active: true}},
+				Detail: &ipc.StatusDetail{
+					StartedAt:  1609459200000,
+					UptimeMs:   3723400,
+					HeapBytes:  4 << 20,
+					SysBytes:   12 << 20,
+					Goroutines: 31,
+					Emulation:  &ipc.EmulationInfo{Viewport: "390x844", Throttle: "slow-4g", Stealth: true, CPUThrottle: 4},
+					Workers:    []ipc.WorkerInfo{{Type: "service_worker", URL: "https://example.com/sw.js"}},
+					Sessions: []ipc.SessionDetail{{
+						ID:                "session1",
+						ConsoleEntries:    3,
+						NetworkEntries:    20,
+						InFlight:          2,
+						NetworkEnabled:    true,
+						LastNavigation:    1609459260000,
+						NavigationPending: true,
+						Origins: []ipc.OriginSummary{
+							{Origin: "https://example.com", Requests: 15, Errors: 1, Bytes: 2048},
+							{Origin: "https://cdn.example.com", Requests: 5, Bytes: 512},
+						},
+					}},
+				},
+			},
+			expected: "OK\npid: 1234\nsessions:\n  * https://example.com\n" +
+				"uptime: 1h2m3s (since " + time.UnixMilli(1609459200000).Format("2006-01-02 15:04:05") + ")\n" +
+				"memory: 4.0MB heap, 12.0MB sys, 31 goroutines\n" +
+				"emulation: viewport 390x844, throttle slow-4g, cpu 4x, stealth\n" +
+				"workers:\n  service_worker https://example.com/sw.js\n" +
+				"tabs:\n  https://example.com\n    buffers: 3 console, 20 network (2 in flight)\n" +
+				"    last navigation: " + time.UnixMilli(1609459260000).Format("15:04:05") + ", pending\n" +
+				"    https://example.com  15 requests, 1 failed, 2.0KB\n" +
+				"    https://cdn.example.com  5 requests, 512B\n",
+		},
 	}
 
 	opts := OutputOptions{UseColor: false}
@@ -164,6 +260,59 @@ func TestConsole(t *testing.T) {
 	}
 }
 
+func TestConsole_Timestamps(t *testing.T) {
+	entries := []ipc.ConsoleEntry{
+		{Seq: 1, Type: "log", Text: "before", Timestamp: 10_000},
+		{Seq: 2, Type: "log", Text: "load", Timestamp: 20_234},
+		{Seq: 3, Type: "log", Text: "click", Timestamp: 21_500},
+	}
+	tests := []struct {
+		name string
+		opts OutputOptions
+		want []string
+	}{
+		{"relative", OutputOptions{Timestamps: TimestampsRelative, Navigations: []int64{5_000, 20_000}}, []string{
+			"01 [+5.000s] LOG before", "02 [+0.234s] LOG load", "03 [+1.500s] LOG click",
+		}},
+		{"relative without navigations", OutputOptions{Timestamps: TimestampsRelative}, []string{
+			"01 [+0.000s] LOG before", "02 [+10.234s] LOG load", "03 [+11.500s] LOG click",
+		}},
+		{"elapsed", OutputOptions{Timestamps: TimestampsElapsed}, []string{
+			"01 [+0.000s] LOG before", "02 [+10.234s] LOG load", "03 [+1.266s] LOG click",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Console(&buf, entries, tt.opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(tt.want), buf.String())
+			}
+			for i, want := range tt.want {
+				if lines[i] != want {
+					t.Errorf("line %d = %q, want %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestConsole_NoSource(t *testing.T) {
+	entries := []ipc.ConsoleEntry{
+		{Seq: 1, Type: "error", Text: "boom", Timestamp: 1609459200000, URL: "app.js", Line: 3, Column: 1},
+	}
+	var buf bytes.Buffer
+	if err := Console(&buf, entries, OutputOptions{NoSource: true, Timestamps: TimestampsElapsed}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "01 [+0.000s] ERROR boom" {
+		t.Errorf("output = %q, want the line without its source", got)
+	}
+}
+
 func TestConsole_ColumnZeroRenders(t *testing.T) {
 	// CDP columns are 0-based; column 0 is the first column and must appear on
 	// the locator rather than being treated as "absent".
@@ -644,6 +793,21 @@ func TestNetwork_RemoteLineOmitsPortWhenAbsent(t *testing.T) {
 	}
 }
 
+func TestNetwork_RemoteLinePriority(t *testing.T) {
+	entries := []ipc.NetworkEntry{
+		{Method: "GET", URL: "https://example.com/", Status: 200, RemoteIPAddress: "93.184.216.34", RemotePort: 443, Protocol: "h3", Priority: "VeryHigh"},
+	}
+
+	var buf bytes.Buffer
+	if err := Network(&buf, entries, netStd()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if out := buf.String(); !strings.Contains(out, "       remote: 93.184.216.34:443 h3 priority:VeryHigh\n") {
+		t.Errorf("remote line should end with the priority:\n%s", out)
+	}
+}
+
 func TestNetwork_CacheOriginToken(t *testing.T) {
 	// Each cache origin renders as a single self-describing main-line token; an
 	// uncached response carries none.
@@ -655,6 +819,7 @@ func TestNetwork_CacheOriginToken(t *testing.T) {
 		{"disk", ipc.NetworkEntry{Method: "GET", URL: "https://example.com/a", Status: 200, FromDiskCache: true}, "(disk)"},
 		{"service worker", ipc.NetworkEntry{Method: "GET", URL: "https://example.com/b", Status: 200, FromServiceWorker: true}, "(service-worker)"},
 		{"prefetch", ipc.NetworkEntry{Method: "GET", URL: "https://example.com/c", Status: 200, FromPrefetchCache: true}, "(prefetch)"},
+		{"memory", ipc.NetworkEntry{Method: "GET", URL: "https://example.com/d", Status: 200, FromMemoryCache: true}, "(memory)"},
 	}
 
 	for _, tc := range cases {
@@ -1830,3 +1995,484 @@ func TestElementIdentificationEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestStyleDiff(t *testing.T) {
+	diffs := []ipc.CSSPropertyDiff{
+		{Property: "color", A: "rgb(0, 0, 0)", B: "rgb(255, 0, 0)"},
+		{Property: "gap", A: "", B: "4px"},
+	}
+	var buf bytes.Buffer
+	if err := StyleDiff(&buf, "#primary", ".card .btn", diffs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "--- #primary\n+++ .card .btn\ncolor: rgb(0, 0, 0) -> rgb(255, 0, 0)\ngap: (none) -> 4px\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStyleSheets(t *testing.T) {
+	sheets := []ipc.CSSStyleSheet{
+		{Index: 1, URL: "https://example.com/app.css", Size: 49357, Rules: 612},
+		{Index: 2, Inline: true, Size: 312, Rules: 1},
+		{Index: 3, Size: 0, Rules: 0},
+	}
+	var buf bytes.Buffer
+	if err := StyleSheets(&buf, sheets); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "1  48.2KB  612 rules  https://example.com/app.css\n" +
+		"2  312B    1 rule     <inline>\n" +
+		"3  0B      0 rules    <constructed>\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnusedCSS(t *testing.T) {
+	sheets := []ipc.CSSUnusedSheet{
+		{
+			CSSStyleSheet: ipc.CSSStyleSheet{URL: "https://example.com/app.css", Size: 2000},
+			StyleRules:    10,
+			UnusedRules:   2,
+			UnusedBytes:   500,
+			Selectors: []ipc.CSSUnusedRule{
+				{Selector: ".legacy-banner", Bytes: 120},
+				{Selector: ".modal.open .close", Bytes: 380},
+			},
+		},
+		{CSSStyleSheet: ipc.CSSStyleSheet{Inline: true}, Selectors: []ipc.CSSUnusedRule{}},
+	}
+
+	var buf bytes.Buffer
+	if err := UnusedCSS(&buf, sheets, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "https://example.com/app.css\nunused: 500B of 2.0KB (25.0%), 2 of 10 rules\n" +
+		".legacy-banner  120B\n.modal.open .close  380B\n" +
+		"--\n<inline>\nunused: 0B of 0B (0.0%), 0 of 0 rules\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	_ = UnusedCSS(&buf, sheets[:1], true)
+	if strings.Contains(buf.String(), ".legacy-banner") {
+		t.Errorf("summary output should omit selectors, got %q", buf.String())
+	}
+}
+
+func TestFrames(t *testing.T) {
+	frames := []ipc.FrameInfo{
+		{ID: "MAIN", URL: "https://shop.example.com/checkout", Depth: 0},
+		{ID: "STRIPE", ParentID: "MAIN", URL: "https://js.stripe.com/v3/", Name: "__privateStripeFrame1", Depth: 1},
+		{ID: "BLANK", ParentID: "STRIPE", Depth: 2},
+	}
+	expected := "https://shop.example.com/checkout [MAIN]\n" +
+		"  https://js.stripe.com/v3/ (__privateStripeFrame1) [STRIPE]\n" +
+		"    about:blank [BLANK]\n"
+
+	var buf bytes.Buffer
+	if err := Frames(&buf, frames); err != nil {
+		t.Fatalf("Frames() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("Frames() =\n%q\nwant\n%q", got, expected)
+	}
+}
+
+func TestDOMSnapshot(t *testing.T) {
+	nodes := []ipc.DOMNode{
+		{Index: 0, Parent: -1, Depth: 0, Type: "document", Name: "#document"},
+		{Index: 1, Parent: 0, Depth: 1, Type: "element", Name: "html", Box: &ipc.DOMBox{Width: 1280, Height: 720}},
+		{Index: 2, Parent: 1, Depth: 2, Type: "element", Name: "head"},
+		{Index: 5, Parent: 1, Depth: 2, Type: "element", Name: "h1",
+			Attributes: map[string]string{"id": "hello", "class": "title big"},
+			Box:        &ipc.DOMBox{X: 8, Y: 21.5, Width: 1264, Height: 37},
+			Styles:     map[string]string{"font-size": "32px", "display": "block"}},
+		{Index: 6, Parent: 5, Depth: 3, Type: "text", Name: "#text", Value: "  Welcome\n  back  "},
+		{Index: 7, Parent: 1, Depth: 2, Type: "text", Name: "#text", Value: strings.Repeat("x", 70)},
+	}
+	expected := "html  0,0 1280x720\n" +
+		"  head\n" +
+		"  h1#hello.title.big  8,21.50 1264x37  display=block font-size=32px\n" +
+		"    \"Welcome back\"\n" +
+		"  \"" + strings.Repeat("x", 60) + "...\"\n"
+
+	var buf bytes.Buffer
+	if err := DOMSnapshot(&buf, nodes); err != nil {
+		t.Fatalf("DOMSnapshot() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("DOMSnapshot() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	data := ipc.DescribeData{
+		URL:   "https://shop.test/checkout",
+		Title: "Checkout - Shop",
+		Landmarks: []ipc.DescribeLandmark{
+			{Role: "navigation", Label: "Main", Selector: "nav.primary"},
+			{Role: "search", Selector: "#search", Depth: 1},
+			{Role: "main", Selector: "#content"},
+		},
+		Headings: []ipc.DescribeHeading{
+			{Level: 1, Text: "Checkout", Selector: "#title"},
+			{Level: 2, Text: "Shipping", Selector: "#content > h2"},
+		},
+		Controls: []ipc.DescribeControl{
+			{Role: "link", Name: "Home", Href: "/", Selector: "a.logo"},
+			{Role: "button", Name: "Place order", Selector: "#place-order", Disabled: true},
+		},
+		MoreControls: 3,
+		Forms: []ipc.DescribeForm{
+			{Selector: "#checkout", Action: "/api/order", Fields: []ipc.DescribeField{
+				{Type: "email", Name: "Email", Selector: "#email", Value: "a@b.c", Required: true},
+				{Type: "select", Name: "Country", Selector: "#country", Value: "AU", Options: []string{"Australia", "New Zealand"}},
+				{Type: "checkbox", Name: "Subscribe", Selector: "#sub"},
+			}},
+			{Fields: []ipc.DescribeField{{Type: "search", Selector: "#q"}}},
+		},
+	}
+	expected := "Checkout - Shop\nhttps://shop.test/checkout\n\n" +
+		"Landmarks:\n" +
+		"  navigation \"Main\"  nav.primary\n" +
+		"    search  #search\n" +
+		"  main  #content\n" +
+		"Headings:\n" +
+		"  h1 Checkout  #title\n" +
+		"    h2 Shipping  #content > h2\n" +
+		"Controls:\n" +
+		"  link \"Home\" -> /  a.logo\n" +
+		"  button \"Place order\"  #place-order (disabled)\n" +
+		"  ... 3 more (raise --limit)\n" +
+		"Forms:\n" +
+		"  form #checkout -> /api/order\n" +
+		"    email \"Email\"  #email = \"a@b.c\" (required)\n" +
+		"    select \"Country\"  #country = \"AU\" [Australia, New Zealand]\n" +
+		"    checkbox \"Subscribe\"  #sub [ ]\n" +
+		"  (no form)\n" +
+		"    search  #q\n"
+
+	var buf bytes.Buffer
+	if err := Describe(&buf, data); err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("Describe() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestElements(t *testing.T) {
+	elements := []ipc.InteractiveElement{
+		{Type: "link", Name: "Home", Href: "/", Selector: "a.logo", Visible: true, InViewport: true},
+		{Type: "button", Name: "Place order", Selector: "#place-order", Disabled: true, Visible: true, InViewport: true},
+		{Type: "checkbox", Selector: "#terms", Visible: true},
+		{Type: "button", Name: "Open menu", Selector: "#menu"},
+	}
+	expected := "link     \"Home\" -> /  a.logo\n" +
+		"button   \"Place order\"  #place-order (disabled)\n" +
+		"checkbox  #terms (offscreen)\n" +
+		"button   \"Open menu\"  #menu (hidden)\n"
+
+	var buf bytes.Buffer
+	if err := Elements(&buf, elements); err != nil {
+		t.Fatalf("Elements() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("Elements() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestSelectorCandidates(t *testing.T) {
+	data := ipc.SelectorData{
+		Candidates: []ipc.SelectorCandidate{
+			{Selector: "#save", Strategy: "id", Matches: 1, Unique: true},
+			{Selector: `[data-testid="save"]`, Strategy: "test-id", Matches: 1, Unique: true},
+			{Selector: "button.primary", Strategy: "class", Matches: 3},
+		},
+		XPath: `//*[@id="save"]`,
+	}
+	expected := "1. #save                 id\n" +
+		"2. [data-testid=\"save\"]  test-id\n" +
+		"3. button.primary        class, 3 matches\n" +
+		"xpath: //*[@id=\"save\"]\n"
+
+	var buf bytes.Buffer
+	if err := SelectorCandidates(&buf, data); err != nil {
+		t.Fatalf("SelectorCandidates() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("SelectorCandidates() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestMeta(t *testing.T) {
+	data := ipc.MetaData{
+		URL:         "https://shop.test/widgets/blue",
+		Title:       "Blue Widget - Shop",
+		Description: "A widget, in blue.",
+		Canonical:   "https://shop.test/widgets/blue",
+		Hreflang:    []ipc.MetaAlternate{{Lang: "de", URL: "https://shop.test/de/widgets/blue"}},
+		OpenGraph:   []ipc.MetaTag{{Property: "og:title", Content: "Blue Widget"}},
+		Twitter:     []ipc.MetaTag{{Property: "twitter:card", Content: "summary"}},
+		JSONLD: []json.RawMessage{
+			json.RawMessage(`{"@context":"https://schema.org","@type":"Product","name":"Blue Widget"}`),
+			json.RawMessage(`{"@graph":[{"@type":["Organization","Brand"],"name":"Shop"},{"@type":"Article","headline":"News"}]}`),
+		},
+		JSONLDErrors: []string{"block 3: Unexpected token"},
+		Microdata: []ipc.MetaItem{
+			{Type: []string{"https://schema.org/Product"}, Properties: map[string][]any{"name": {"Blue Widget"}}},
+			{Properties: map[string][]any{}},
+		},
+	}
+	expected := "title: Blue Widget - Shop\n" +
+		"description: A widget, in blue.\n" +
+		"canonical: https://shop.test/widgets/blue\n" +
+		"hreflang:\n" +
+		"  de  https://shop.test/de/widgets/blue\n" +
+		"openGraph:\n" +
+		"  og:title  Blue Widget\n" +
+		"twitter:\n" +
+		"  twitter:card  summary\n" +
+		"jsonLd:\n" +
+		"  Product \"Blue Widget\"\n" +
+		"  Organization, Brand \"Shop\"\n" +
+		"  Article \"News\"\n" +
+		"  error: block 3: Unexpected token\n" +
+		"microdata:\n" +
+		"  https://schema.org/Product \"Blue Widget\"\n" +
+		"  (untyped)\n"
+
+	var buf bytes.Buffer
+	if err := Meta(&buf, data); err != nil {
+		t.Fatalf("Meta() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("Meta() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	extensions := []ipc.ExtensionInfo{
+		{ID: "nilnepjhjgfacmijbmgplkmpgnbgekgi", Name: "Dev Tool", Version: "1.2.0", Path: "/home/user/my-extension", Active: true},
+		{ID: "gckpihaehgepkpiokicpmgbmojmemdja", Name: "Other", Path: "/home/user/other"},
+		{Path: "/home/user/broken", Error: "not an unpacked extension"},
+		{ID: "abcdefghijklmnopabcdefghijklmnop", Active: true},
+	}
+	expected := "nilnepjhjgfacmijbmgplkmpgnbgekgi  Dev Tool 1.2.0  /home/user/my-extension\n" +
+		"gckpihaehgepkpiokicpmgbmojmemdja  Other  /home/user/other (idle)\n" +
+		"error: /home/user/broken: not an unpacked extension\n" +
+		"abcdefghijklmnopabcdefghijklmnop\n"
+
+	var buf bytes.Buffer
+	if err := Extensions(&buf, extensions, OutputOptions{}); err != nil {
+		t.Fatalf("Extensions() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("Extensions() =\n%q\nwant\n%q", got, expected)
+	}
+
+	buf.Reset()
+	_ = Extensions(&buf, nil, OutputOptions{})
+	if got := buf.String(); got != "No extensions\n" {
+		t.Errorf("Extensions(nil) = %q", got)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	entries := []ipc.HistoryEntry{
+		{Index: 9, URL: "https://example.com/", Title: "Example Domain"},
+		{Index: 10, URL: "https://example.com/about", Title: "About", Current: true},
+		{Index: 11, URL: "about:blank", Title: "about:blank"},
+	}
+	expected := "   9  https://example.com/ - Example Domain\n" +
+		"* 10  https://example.com/about - About\n" +
+		"  11  about:blank\n"
+
+	var buf bytes.Buffer
+	if err := History(&buf, entries, OutputOptions{}); err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("History() =\n%q\nwant\n%q", got, expected)
+	}
+}
+
+func TestContexts(t *testing.T) {
+	contexts := []ipc.ContextInfo{
+		{Default: true, Tabs: []ipc.PageSession{
+			{ID: "9A3E8D71AB12CD34", URL: "https://example.com/", Title: "Example Domain"},
+		}},
+		{ID: "5F1A2B3C4D5E6F70", Incognito: true, Tabs: []ipc.PageSession{
+			{ID: "B2C4E6F8AB12CD34", URL: "https://example.com/login", Title: "Log in", Active: true, Context: "5F1A2B3C4D5E6F70"},
+		}},
+		{ID: "7E7E7E7E7E7E7E7E", Tabs: []ipc.PageSession{}},
+	}
+	expected := "default\n" +
+		"  https://example.com/ - Example Domain [9A3E8D71]\n" +
+		"5F1A2B3C (incognito)\n" +
+		"* https://example.com/login - Log in [B2C4E6F8]\n" +
+		"7E7E7E7E\n" +
+		"  (no tabs)\n"
+
+	var buf bytes.Buffer
+	if err := Contexts(&buf, contexts, OutputOptions{}); err != nil {
+		t.Fatalf("Contexts() error = %v", err)
+	}
+	if got := buf.String(); got != expected {
+		t.Errorf("Contexts() =\n%q\nwant\n%q", got, expected)
+	}
+}
+
+func TestBuffers(t *testing.T) {
+	data := ipc.BufferData{
+		Buffers: []ipc.BufferInfo{
+			{Name: "console", Entries: 120, Capacity: 10000},
+			{Name: "network", Entries: 500, Capacity: 500, Dropped: 37},
+		},
+		CaptureBodies: true,
+	}
+	var buf bytes.Buffer
+	if err := Buffers(&buf, data, OutputOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "console: 120/10000 entries\nnetwork: 500/500 entries, 37 dropped\nbodies: on\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
+
+func TestHeapLeakcheck(t *testing.T) {
+	data := ipc.HeapLeakcheckData{
+		Samples: []ipc.HeapSample{
+			{Cycle: 1, UsedSize: 10 << 20, TotalSize: 20 << 20},
+			{Cycle: 2, UsedSize: 11 << 20, TotalSize: 20 << 20},
+		},
+		Growth:    1 << 20,
+		Monotonic: true,
+		Constructors: []ipc.HeapConstructorGrowth{
+			{Name: "Detached HTMLDivElement", Count: 250, Size: 20000, CountDelta: 240, SizeDelta: 19200},
+		},
+	}
+	want := "cycle 1  used 10.0MB  total 20.0MB\n" +
+		"cycle 2  used 11.0MB  total 20.0MB\n" +
+		"grew +1.0MB over 1 cycle, every cycle: likely a leak\n" +
+		"growing constructors:\n" +
+		"       +240  +18.8KB  Detached HTMLDivElement (250 objects, 19.5KB)\n"
+
+	var buf bytes.Buffer
+	if err := HeapLeakcheck(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("HeapLeakcheck() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	data.Growth, data.Monotonic, data.Constructors = -2048, false, nil
+	_ = HeapLeakcheck(&buf, data)
+	if !strings.Contains(buf.String(), "grew -2.0KB over 1 cycle, no steady growth\nno constructors grew\n") {
+		t.Errorf("HeapLeakcheck() without growth =\n%s", buf.String())
+	}
+}
+
+func TestBenchCompare(t *testing.T) {
+	a := ipc.BenchMetrics{TTFB: 120, FCP: 310, LCP: 640, DOMContentLoaded: 500, Load: 900, CLS: 0.052, Requests: 40, TransferSize: 2 << 20, JSHeapUsed: 4 << 20}
+	b := ipc.BenchMetrics{TTFB: 90, FCP: 310, LCP: 704, DOMContentLoaded: 450, Load: 990, Requests: 38, TransferSize: 1 << 20, JSHeapUsed: 4 << 20}
+	want := "A  https://a.test/\n" +
+		"B  https://b.test/\n" +
+		"median of 5 cold loads each\n" +
+		"\n" +
+		"metric            A          B      delta\n" +
+		"TTFB          120ms       90ms     -25.0%\n" +
+		"FCP           310ms      310ms      +0.0%\n" +
+		"LCP           640ms      704ms     +10.0%\n" +
+		"DCL           500ms      450ms     -10.0%\n" +
+		"Load          900ms      990ms     +10.0%\n" +
+		"CLS           0.052      0.000    -100.0%\n" +
+		"Requests         40         38      -5.0%\n" +
+		"Transfer      2.0MB      1.0MB     -50.0%\n" +
+		"JS heap       4.0MB      4.0MB      +0.0%\n"
+
+	var buf bytes.Buffer
+	if err := BenchCompare(&buf, "https://a.test/", "https://b.test/", a, b, 5, false, 0); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("BenchCompare() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	_ = BenchCompare(&buf, "https://a.test/", "https://b.test/", ipc.BenchMetrics{}, b, 1, true, 2.5)
+	if !strings.Contains(buf.String(), "median of 1 warm load each, CPU throttled 2.5x\n") || !strings.Contains(buf.String(), "TTFB            0ms       90ms          -\n") {
+		t.Errorf("BenchCompare() without A metrics =\n%s", buf.String())
+	}
+}
+
+func TestBannerHits(t *testing.T) {
+	var buf bytes.Buffer
+	if err := BannerHits(&buf, []ipc.BannerHit{
+		{Rule: "onetrust", Action: "clicked", Selector: "#onetrust-accept-btn-handler"},
+		{Rule: "onetrust", Action: "hidden", Selector: "#onetrust-consent-sdk"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	want := "clicked  onetrust  #onetrust-accept-btn-handler\n" +
+		"hidden   onetrust  #onetrust-consent-sdk\n"
+	if buf.String() != want {
+		t.Errorf("BannerHits() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	_ = BannerHits(&buf, nil)
+	if buf.String() != "No banners found\n" {
+		t.Errorf("BannerHits(nil) = %q", buf.String())
+	}
+}
+
+func TestPoolResults(t *testing.T) {
+	results := []ipc.PoolMapResult{
+		{URL: "https://a.test/", OK: true, Value: json.RawMessage(`"Example\n"`), DurationMs: 1234},
+		{URL: "https://b.test/", OK: true, Value: json.RawMessage(`42`), DurationMs: 500},
+		{URL: "https://c.test/", Error: "navigation timeout", DurationMs: 30000},
+	}
+	want := "== https://a.test/  OK  1.2s\nExample\n" +
+		"== https://b.test/  OK  0.5s\n42\n" +
+		"== https://c.test/  FAIL  30.0s\nnavigation timeout\n" +
+		"3 URLs, 1 failed\n"
+
+	var buf bytes.Buffer
+	if err := PoolResults(&buf, results, OutputOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("PoolResults() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestAborts(t *testing.T) {
+	since := time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local).UnixMilli()
+	data := ipc.AbortData{
+		Rules: []ipc.AbortRule{
+			{ID: "a1", URL: "*/upload*", Delay: 2000, Times: 1},
+			{ID: "a2", URL: "*/poll*", Hold: true, Hits: 3},
+			{ID: "a3", URL: "*/api/*", Times: 5, Hits: 1},
+		},
+		Held: []ipc.HeldRequest{{RequestID: "1234.56", URL: "https://app.test/poll", By: "a2", Since: since}},
+	}
+	want := "a1  */upload*  abort the next request after 2s  0 hits\n" +
+		"a2  */poll*  hold every request  3 hits\n" +
+		"a3  */api/*  abort the next 5 requests  1 hits\n" +
+		"Held:\n" +
+		"  1234.56  https://app.test/poll  by a2 since 15:04:05\n"
+
+	var buf bytes.Buffer
+	if err := Aborts(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != want {
+		t.Errorf("Aborts() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
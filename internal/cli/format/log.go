@@ -0,0 +1,101 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/grantcarthew/webctl/internal/daemonlog"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// LogEntry renders one daemon log entry as "HH:MM:SS.mmm LEVEL msg key=value ...",
+// with attributes in key order.
+func LogEntry(w io.Writer, e daemonlog.Entry, opts OutputOptions) error {
+	ts := e.Time.Local().Format("15:04:05.000")
+	level := fmt.Sprintf("%-5s", e.Level.String())
+
+	if opts.UseColor {
+		colorFprint(w, color.Faint, ts)
+		_, _ = fmt.Fprint(w, " ")
+		printLogLevel(w, e.Level, level)
+	} else {
+		_, _ = fmt.Fprintf(w, "%s %s", ts, level)
+	}
+	_, _ = fmt.Fprintf(w, " %s", e.Msg)
+
+	keys := make([]string, 0, len(e.Attrs))
+	for k := range e.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(w, " %s=%s", k, logValue(e.Attrs[k]))
+	}
+	_, _ = fmt.Fprintln(w)
+	return nil
+}
+
+// DaemonEvent renders one daemon event as "HH:MM:SS.mmm LEVEL type message
+// key=value ...", in the layout of LogEntry.
+func DaemonEvent(w io.Writer, e ipc.DaemonEvent, opts OutputOptions) error {
+	ts := time.UnixMilli(e.Timestamp).Format("15:04:05.000")
+	l, err := daemonlog.ParseLevel(e.Level)
+	if err != nil {
+		l = slog.LevelInfo
+	}
+	level := fmt.Sprintf("%-5s", l.String())
+
+	if opts.UseColor {
+		colorFprint(w, color.Faint, ts)
+		_, _ = fmt.Fprint(w, " ")
+		printLogLevel(w, l, level)
+		_, _ = fmt.Fprint(w, " ")
+		colorFprint(w, color.Bold, e.Type)
+	} else {
+		_, _ = fmt.Fprintf(w, "%s %s %s", ts, level, e.Type)
+	}
+	_, _ = fmt.Fprintf(w, " %s", e.Message)
+
+	keys := make([]string, 0, len(e.Attrs))
+	for k := range e.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(w, " %s=%s", k, logValue(e.Attrs[k]))
+	}
+	_, _ = fmt.Fprintln(w)
+	return nil
+}
+
+// logValue formats an attribute value, quoting strings that would otherwise
+// run into the next attribute.
+func logValue(v any) string {
+	if s, ok := v.(string); ok {
+		if s == "" || strings.ContainsAny(s, " \t\"=") {
+			return strconv.Quote(s)
+		}
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// printLogLevel writes the log level, colourised by severity on a TTY.
+func printLogLevel(w io.Writer, l slog.Level, level string) {
+	switch {
+	case l >= slog.LevelError:
+		colorFprint(w, color.FgRed, level)
+	case l >= slog.LevelWarn:
+		colorFprint(w, color.FgYellow, level)
+	case l >= slog.LevelInfo:
+		colorFprint(w, color.FgCyan, level)
+	default:
+		colorFprint(w, color.Faint, level)
+	}
+}
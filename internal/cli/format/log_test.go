@@ -0,0 +1,52 @@
+package format
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/daemonlog"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestLogEntry(t *testing.T) {
+	e := daemonlog.Entry{
+		Time:  time.Date(2025, 1, 1, 14, 3, 21, 118e6, time.Local),
+		Level: slog.LevelWarn,
+		Msg:   "daemon stopping",
+		Attrs: map[string]any{"reason": "browser connection lost", "port": float64(9222)},
+	}
+
+	var buf bytes.Buffer
+	if err := LogEntry(&buf, e, OutputOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `14:03:21.118 WARN  daemon stopping port=9222 reason="browser connection lost"`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestDaemonEvent(t *testing.T) {
+	e := ipc.DaemonEvent{
+		Seq:       3,
+		Timestamp: time.Date(2025, 1, 1, 14, 5, 2, 904e6, time.Local).UnixMilli(),
+		Level:     "warn",
+		Type:      "browser_lost",
+		Message:   "browser crashed",
+		Attrs:     map[string]any{"error": "exit status 1", "pid": float64(48211)},
+	}
+
+	var buf bytes.Buffer
+	if err := DaemonEvent(&buf, e, OutputOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `14:05:02.904 WARN  browser_lost browser crashed error="exit status 1" pid=48211`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
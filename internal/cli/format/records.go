@@ -0,0 +1,194 @@
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"go.yaml.in/yaml/v3"
+)
+
+// Output formats selectable with --output. Text and JSON are the long-standing
+// modes; the rest render list commands as records for other tools.
+const (
+	OutputText  = "text"
+	OutputJSON  = "json"
+	OutputJSONL = "jsonl"
+	OutputYAML  = "yaml"
+	OutputCSV   = "csv"
+	OutputTable = "table"
+)
+
+// OutputFormats lists every --output value.
+var OutputFormats = []string{OutputText, OutputJSON, OutputJSONL, OutputYAML, OutputCSV, OutputTable}
+
+// Column is one CSV or table column: its header and how to render a cell.
+type Column[T any] struct {
+	Header string
+	Value  func(T) string
+}
+
+// Records writes items in a record format: one JSON object per line (jsonl), a
+// YAML sequence (yaml), or the columns as CSV with a header row (csv) or as an
+// aligned table (table).
+func Records[T any](w io.Writer, format string, items []T, cols []Column[T]) error {
+	switch format {
+	case OutputJSONL:
+		enc := json.NewEncoder(w)
+		for _, item := range items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OutputYAML:
+		if items == nil {
+			items = []T{}
+		}
+		return YAML(w, items)
+	case OutputCSV:
+		cw := csv.NewWriter(w)
+		header := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = c.Header
+		}
+		_ = cw.Write(header)
+		for _, item := range items {
+			row := make([]string, len(cols))
+			for i, c := range cols {
+				row[i] = c.Value(item)
+			}
+			_ = cw.Write(row)
+		}
+		cw.Flush()
+		return cw.Error()
+	case OutputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		header := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = strings.ToUpper(c.Header)
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for _, item := range items {
+			row := make([]string, len(cols))
+			for i, c := range cols {
+				row[i] = tableCell(c.Value(item))
+			}
+			_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unsupported record format %q", format)
+}
+
+// tableCell keeps a cell on one line and out of tabwriter's column logic.
+func tableCell(s string) string {
+	s = strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ").Replace(s)
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// YAML writes v as YAML using its JSON field names and order, so YAML output
+// has the same shape as --json.
+func YAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// JSON is YAML; decoding into a node keeps key order.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	clearStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// clearStyle drops the flow and quoting styles carried over from JSON so the
+// encoder emits block YAML, quoting only where needed.
+func clearStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearStyle(c)
+	}
+}
+
+// recordTime renders a Unix-millisecond timestamp for CSV and table cells.
+func recordTime(ms int64) string {
+	if ms == 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// ConsoleColumns are the CSV and table columns for console entries.
+var ConsoleColumns = []Column[ipc.ConsoleEntry]{
+	{"seq", func(e ipc.ConsoleEntry) string { return strconv.FormatUint(e.Seq, 10) }},
+	{"time", func(e ipc.ConsoleEntry) string { return recordTime(e.Timestamp) }},
+	{"type", func(e ipc.ConsoleEntry) string { return e.Type }},
+	{"source", func(e ipc.ConsoleEntry) string { return e.Source }},
+	{"text", func(e ipc.ConsoleEntry) string { return e.Text }},
+	{"location", func(e ipc.ConsoleEntry) string { return consoleLocation(e.URL, e.Line, e.Column) }},
+}
+
+// NetworkColumns are the CSV and table columns for network entries.
+var NetworkColumns = []Column[ipc.NetworkEntry]{
+	{"seq", func(e ipc.NetworkEntry) string { return strconv.FormatUint(e.Seq, 10) }},
+	{"time", func(e ipc.NetworkEntry) string { return recordTime(e.RequestTime) }},
+	{"method", func(e ipc.NetworkEntry) string { return e.Method }},
+	{"status", func(e ipc.NetworkEntry) string {
+		if e.Status == 0 {
+			return ""
+		}
+		return strconv.Itoa(e.Status)
+	}},
+	{"type", func(e ipc.NetworkEntry) string { return e.Type }},
+	{"url", func(e ipc.NetworkEntry) string { return e.URL }},
+	{"duration_ms", func(e ipc.NetworkEntry) string {
+		if e.Duration == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(e.Duration*1000, 'f', 0, 64)
+	}},
+	{"size", func(e ipc.NetworkEntry) string {
+		if e.Size == 0 {
+			return ""
+		}
+		return strconv.FormatInt(e.Size, 10)
+	}},
+	{"error", func(e ipc.NetworkEntry) string { return e.Error }},
+}
+
+// CookieColumns are the CSV and table columns for cookies.
+var CookieColumns = []Column[ipc.Cookie]{
+	{"name", func(c ipc.Cookie) string { return c.Name }},
+	{"value", func(c ipc.Cookie) string { return c.Value }},
+	{"domain", func(c ipc.Cookie) string { return c.Domain }},
+	{"path", func(c ipc.Cookie) string { return c.Path }},
+	{"expires", func(c ipc.Cookie) string {
+		if c.Session || c.Expires <= 0 {
+			return "session"
+		}
+		return time.Unix(int64(c.Expires), 0).UTC().Format(time.RFC3339)
+	}},
+	{"httpOnly", func(c ipc.Cookie) string { return strconv.FormatBool(c.HTTPOnly) }},
+	{"secure", func(c ipc.Cookie) string { return strconv.FormatBool(c.Secure) }},
+	{"sameSite", func(c ipc.Cookie) string { return c.SameSite }},
+}
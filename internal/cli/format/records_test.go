@@ -0,0 +1,90 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+var recordEntries = []ipc.ConsoleEntry{
+	{Seq: 1, Type: "log", Text: "hello, world", Timestamp: 1700000000000, URL: "https://example.com/app.js", Line: 12, Column: 4},
+	{Seq: 2, Type: "error", Text: "line one\nline two", Timestamp: 1700000001500},
+}
+
+func TestRecords_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Records(&buf, OutputCSV, recordEntries, ConsoleColumns); err != nil {
+		t.Fatal(err)
+	}
+	want := "seq,time,type,source,text,location\n" +
+		"1,2023-11-14T22:13:20.000Z,log,,\"hello, world\",https://example.com/app.js:12:4\n" +
+		"2,2023-11-14T22:13:21.500Z,error,,\"line one\nline two\",\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRecords_Table(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Records(&buf, OutputTable, recordEntries, ConsoleColumns); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two rows, got %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "SEQ  TIME") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if !strings.Contains(lines[2], "line one line two") || !strings.Contains(lines[2], " - ") {
+		t.Errorf("newlines and empty cells should be flattened: %q", lines[2])
+	}
+}
+
+func TestRecords_JSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Records(&buf, OutputJSONL, recordEntries, ConsoleColumns); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], `{"seq":1,`) {
+		t.Errorf("expected one object per line, got %q", buf.String())
+	}
+}
+
+func TestRecords_YAML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Records(&buf, OutputYAML, recordEntries[:1], ConsoleColumns); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "- seq: 1\n  type: log\n") {
+		t.Errorf("unexpected YAML:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := Records(&buf, OutputYAML, []ipc.ConsoleEntry(nil), ConsoleColumns); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("empty list should be an empty sequence, got %q", buf.String())
+	}
+}
+
+func TestRecords_Unsupported(t *testing.T) {
+	if err := Records(&bytes.Buffer{}, OutputText, recordEntries, ConsoleColumns); err == nil {
+		t.Error("expected error for text format")
+	}
+}
+
+func TestCookieColumns_Expires(t *testing.T) {
+	var buf bytes.Buffer
+	cookies := []ipc.Cookie{{Name: "a", Session: true}, {Name: "b", Expires: 1700000000}}
+	if err := Records(&buf, OutputCSV, cookies, CookieColumns); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "a,,,,session,") || !strings.Contains(buf.String(), "2023-11-14T22:13:20Z") {
+		t.Errorf("unexpected expires cells:\n%s", buf.String())
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,11 +38,30 @@ const (
 	DetailFull
 )
 
+// TimestampMode controls how console lines show when each entry was logged.
+type TimestampMode int
+
+const (
+	// TimestampsAbsolute shows the local wall-clock time, [15:04:05]. This is
+	// the default.
+	TimestampsAbsolute TimestampMode = iota
+	// TimestampsRelative shows the time since the page's latest main-frame
+	// navigation before the entry, [+0.234s]. Entries older than every known
+	// navigation count from the first entry rendered.
+	TimestampsRelative
+	// TimestampsElapsed shows the time since the previous entry rendered, with
+	// the first at +0.000s.
+	TimestampsElapsed
+)
+
 // OutputOptions controls text formatting behavior.
 type OutputOptions struct {
-	UseColor    bool        // Enable ANSI color codes
-	ShowHeaders bool        // Render request/response headers (network text mode)
-	Detail      DetailLevel // Network detail level (summary/standard/full)
+	UseColor    bool          // Enable ANSI color codes
+	ShowHeaders bool          // Render request/response headers (network text mode)
+	Detail      DetailLevel   // Network detail level (summary/standard/full)
+	Timestamps  TimestampMode // Console timestamp style (absolute/relative/elapsed)
+	Navigations []int64       // Main-frame navigation times in Unix ms, oldest first (relative timestamps)
+	NoSource    bool          // Omit the console source location from summary lines
 }
 
 // Network subordinate-line indentation. Detail lines read as children of their
@@ -124,6 +144,57 @@ func formatHTTPStatus(w io.Writer, status int, opts OutputOptions) {
 	}
 }
 
+// browserLaunch writes the browser's launch settings, one per line, omitting
+// those left at their defaults.
+func browserLaunch(w io.Writer, b ipc.BrowserInfo) {
+	mode := "headed"
+	if b.Headless {
+		mode = "headless"
+	}
+	_, _ = fmt.Fprintf(w, "browser: %s, port %d\n", mode, b.Port)
+	switch {
+	case b.UserDataDir == "":
+	case b.TempProfile:
+		_, _ = fmt.Fprintf(w, "profile: %s (temporary)\n", b.UserDataDir)
+	default:
+		_, _ = fmt.Fprintf(w, "profile: %s\n", b.UserDataDir)
+	}
+	if b.Lang != "" {
+		_, _ = fmt.Fprintf(w, "lang: %s\n", b.Lang)
+	}
+	if b.WindowSize != "" {
+		_, _ = fmt.Fprintf(w, "window size: %s\n", strings.Replace(b.WindowSize, ",", "x", 1))
+	}
+	if b.WindowPosition != "" {
+		_, _ = fmt.Fprintf(w, "window position: %s\n", b.WindowPosition)
+	}
+	if b.Incognito {
+		_, _ = fmt.Fprintln(w, "incognito: yes")
+	}
+	if b.DevTools {
+		_, _ = fmt.Fprintln(w, "devtools: yes")
+	}
+	for _, dir := range b.Extensions {
+		_, _ = fmt.Fprintf(w, "extension: %s\n", dir)
+	}
+	if len(b.ChromeFlags) > 0 {
+		_, _ = fmt.Fprintf(w, "chrome flags: %s\n", strings.Join(b.ChromeFlags, " "))
+	}
+	if b.CI {
+		_, _ = fmt.Fprintln(w, "ci: yes")
+	}
+}
+
+// daemonInfo writes the daemon's PID and socket lines of a status.
+func daemonInfo(w io.Writer, data ipc.StatusData) {
+	if data.PID > 0 {
+		_, _ = fmt.Fprintf(w, "pid: %d\n", data.PID)
+	}
+	if data.Socket != "" {
+		_, _ = fmt.Fprintf(w, "socket: %s\n", data.Socket)
+	}
+}
+
 // Status outputs daemon status in text format.
 func Status(w io.Writer, data ipc.StatusData, opts OutputOptions) error {
 	// Not running state
@@ -136,6 +207,17 @@ func Status(w io.Writer, data ipc.StatusData, opts OutputOptions) error {
 		return nil
 	}
 
+	// Running, with the browser being replaced
+	if data.Restarting {
+		if opts.UseColor {
+			colorFprint(w, color.FgYellow, "Browser restarting\n")
+		} else {
+			_, _ = fmt.Fprintln(w, "Browser restarting")
+		}
+		daemonInfo(w, data)
+		return nil
+	}
+
 	// Running but no browser
 	if data.ActiveSession == nil && len(data.Sessions) == 0 {
 		if opts.UseColor {
@@ -143,9 +225,8 @@ func Status(w io.Writer, data ipc.StatusData, opts OutputOptions) error {
 		} else {
 			_, _ = fmt.Fprintln(w, "No browser")
 		}
-		if data.PID > 0 {
-			_, _ = fmt.Fprintf(w, "pid: %d\n", data.PID)
-		}
+		daemonInfo(w, data)
+		statusDetail(w, data)
 		return nil
 	}
 
@@ -156,9 +237,8 @@ func Status(w io.Writer, data ipc.StatusData, opts OutputOptions) error {
 		} else {
 			_, _ = fmt.Fprintln(w, "No session")
 		}
-		if data.PID > 0 {
-			_, _ = fmt.Fprintf(w, "pid: %d\n", data.PID)
-		}
+		daemonInfo(w, data)
+		statusDetail(w, data)
 		return nil
 	}
 
@@ -168,8 +248,15 @@ func Status(w io.Writer, data ipc.StatusData, opts OutputOptions) error {
 	} else {
 		_, _ = fmt.Fprintln(w, "OK")
 	}
-	if data.PID > 0 {
-		_, _ = fmt.Fprintf(w, "pid: %d\n", data.PID)
+	daemonInfo(w, data)
+	if data.Adopted {
+		_, _ = fmt.Fprintln(w, "browser: adopted (left running on stop)")
+	}
+	if data.BrowserRestarts > 0 {
+		_, _ = fmt.Fprintf(w, "browser restarts: %d\n", data.BrowserRestarts)
+	}
+	if data.Browser != nil {
+		browserLaunch(w, *data.Browser)
 	}
 
 	// Show sessions
@@ -194,28 +281,154 @@ func Status(w io.Writer, data ipc.StatusData, opts OutputOptions) error {
 			_, _ = fmt.Fprintln(w)
 		}
 	}
+	statusDetail(w, data)
 
 	return nil
 }
 
+// statusOriginsShown is how many origins 'status --verbose' lists per tab.
+const statusOriginsShown = 5
+
+// statusDetail writes the diagnostics added by 'status --verbose': daemon
+// uptime and memory, emulation, workers, and each tab's buffers, navigation
+// state, and busiest origins. It writes nothing without data.Detail.
+func statusDetail(w io.Writer, data ipc.StatusData) {
+	d := data.Detail
+	if d == nil {
+		return
+	}
+	uptime := time.Duration(d.UptimeMs) * time.Millisecond
+	_, _ = fmt.Fprintf(w, "uptime: %s (since %s)\n", uptime.Round(time.Second), time.UnixMilli(d.StartedAt).Format("2006-01-02 15:04:05"))
+	_, _ = fmt.Fprintf(w, "memory: %s heap, %s sys, %d goroutines\n", formatBytes(int64(d.HeapBytes)), formatBytes(int64(d.SysBytes)), d.Goroutines)
+
+	if e := d.Emulation; e != nil {
+		var parts []string
+		if e.Viewport != "" {
+			parts = append(parts, "viewport "+e.Viewport)
+		}
+		if e.Throttle != "" {
+			parts = append(parts, "throttle "+e.Throttle)
+		}
+		if e.CPUThrottle > 1 {
+			parts = append(parts, "cpu "+CPUFactor(e.CPUThrottle))
+		}
+		if e.Stealth {
+			parts = append(parts, "stealth")
+		}
+		if e.UserAgent != "" {
+			parts = append(parts, fmt.Sprintf("user agent %q", e.UserAgent))
+		}
+		_, _ = fmt.Fprintf(w, "emulation: %s\n", strings.Join(parts, ", "))
+	}
+
+	if len(d.Workers) > 0 {
+		_, _ = fmt.Fprintln(w, "workers:")
+		for _, wk := range d.Workers {
+			attached := ""
+			if wk.Attached {
+				attached = " (attached)"
+			}
+			_, _ = fmt.Fprintf(w, "  %-14s %s%s\n", wk.Type, wk.URL, attached)
+		}
+	}
+
+	urls := make(map[string]string, len(data.Sessions))
+	for _, s := range data.Sessions {
+		urls[s.ID] = s.URL
+	}
+	if len(d.Sessions) > 0 {
+		_, _ = fmt.Fprintln(w, "tabs:")
+	}
+	for _, s := range d.Sessions {
+		name := urls[s.ID]
+		if name == "" {
+			name = s.ID
+		}
+		_, _ = fmt.Fprintf(w, "  %s\n", name)
+
+		network := fmt.Sprintf("%d network", s.NetworkEntries)
+		if !s.NetworkEnabled {
+			network += " (capture off)"
+		} else if s.InFlight > 0 {
+			network += fmt.Sprintf(" (%d in flight)", s.InFlight)
+		}
+		_, _ = fmt.Fprintf(w, "    buffers: %d console, %s\n", s.ConsoleEntries, network)
+
+		nav := "none"
+		if s.LastNavigation > 0 {
+			nav = time.UnixMilli(s.LastNavigation).Format("15:04:05")
+		}
+		if s.NavigationPending {
+			nav += ", pending"
+		}
+		_, _ = fmt.Fprintf(w, "    last navigation: %s\n", nav)
+
+		for i, o := range s.Origins {
+			if i == statusOriginsShown {
+				_, _ = fmt.Fprintf(w, "    ... %d more origins\n", len(s.Origins)-statusOriginsShown)
+				break
+			}
+			errs := ""
+			if o.Errors > 0 {
+				errs = fmt.Sprintf(", %d failed", o.Errors)
+			}
+			_, _ = fmt.Fprintf(w, "    %s  %d requests%s, %s\n", o.Origin, o.Requests, errs, formatBytes(o.Bytes))
+		}
+	}
+}
+
 // Console renders the indexed console list: one summary line per entry, prefixed
 // with the entry's seq (its drill-down address). The line carries the wall-clock
 // timestamp, the level, the top stack frame, and the first line of the message.
 // The enriched payload (full multi-line text, complete stack, all arguments, and
 // exception or Log-domain detail) is reserved for drill-down (ConsoleDetail).
 func Console(w io.Writer, entries []ipc.ConsoleEntry, opts OutputOptions) error {
-	for _, e := range entries {
-		writeConsoleSummaryLine(w, e, opts)
+	var first, prev int64
+	for i, e := range entries {
+		if i == 0 {
+			first, prev = e.Timestamp, e.Timestamp
+		}
+		writeConsoleSummaryLine(w, e, consoleTime(e.Timestamp, first, prev, opts), opts)
+		prev = e.Timestamp
 	}
 	return nil
 }
 
+// consoleTime renders an entry's timestamp for the summary line. first and
+// prev are the timestamps of the first and previous entries rendered.
+func consoleTime(ms, first, prev int64, opts OutputOptions) string {
+	switch opts.Timestamps {
+	case TimestampsRelative:
+		base := first
+		for _, nav := range opts.Navigations {
+			if nav > ms {
+				break
+			}
+			base = nav
+		}
+		return formatOffset(ms - base)
+	case TimestampsElapsed:
+		return formatOffset(ms - prev)
+	default:
+		return time.UnixMilli(ms).Local().Format("15:04:05")
+	}
+}
+
+// formatOffset renders a millisecond offset as signed seconds: +0.234s.
+func formatOffset(ms int64) string {
+	sign := "+"
+	if ms < 0 {
+		sign, ms = "-", -ms
+	}
+	return fmt.Sprintf("%s%d.%03ds", sign, ms/1000, ms%1000)
+}
+
 // ConsoleDetail renders a single console entry in full for drill-down: the
 // summary line, then the complete multi-line message, stack, arguments, and any
 // exception or Log-domain correlation on seven-space subordinate lines, matching
 // the network drill-down layout.
 func ConsoleDetail(w io.Writer, e ipc.ConsoleEntry, opts OutputOptions) error {
-	writeConsoleSummaryLine(w, e, opts)
+	writeConsoleSummaryLine(w, e, consoleTime(e.Timestamp, e.Timestamp, e.Timestamp, opts), opts)
 
 	// The summary line already carries the first line of Text; a multi-line
 	// message repeats in full here so nothing is lost off the index. Strip
@@ -257,13 +470,16 @@ func ConsoleDetail(w io.Writer, e ipc.ConsoleEntry, opts OutputOptions) error {
 }
 
 // writeConsoleSummaryLine writes the one-line index entry shared by the list and
-// the drill-down header: "SEQ [HH:MM:SS] LEVEL frame message", where frame is the
-// top stack locator and message is the first line of Text. Absent components are
-// omitted rather than padded.
-func writeConsoleSummaryLine(w io.Writer, e ipc.ConsoleEntry, opts OutputOptions) {
-	ts := time.UnixMilli(e.Timestamp).Local().Format("15:04:05")
+// the drill-down header: "SEQ [ts] LEVEL frame message", where ts is rendered by
+// consoleTime, frame is the top stack locator, and message is the first line of
+// Text. Absent components, and the frame under NoSource, are omitted rather
+// than padded.
+func writeConsoleSummaryLine(w io.Writer, e ipc.ConsoleEntry, ts string, opts OutputOptions) {
 	level := strings.ToUpper(e.Type)
-	frame := consoleTopFrame(e)
+	frame := ""
+	if !opts.NoSource {
+		frame = consoleTopFrame(e)
+	}
 	msg := firstLine(e.Text)
 
 	// Seq prefix, zero-padded to a minimum of two digits and growing naturally
@@ -432,6 +648,12 @@ func formatBytes(n int64) string {
 	return fmt.Sprintf("%.1f%cB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
+// Bytes renders a byte count as the network list does, for commands that
+// print sizes in their own layout.
+func Bytes(n int64) string {
+	return formatBytes(n)
+}
+
 // printNetworkMethod writes the HTTP method, colourised by method on a TTY.
 func printNetworkMethod(w io.Writer, method string, opts OutputOptions) {
 	if !opts.UseColor {
@@ -500,9 +722,12 @@ func printNetworkStatus(w io.Writer, status int, opts OutputOptions) {
 //   - Initiator: shown on a subordinate "initiator:" line as "type url:line"
 //     when a location was captured (parser and script initiators), naming what
 //     triggered the request. The bare "other" initiator is omitted as noise.
-//   - FromDiskCache/FromServiceWorker/FromPrefetchCache: shown as a single
-//     self-describing main-line token (disk, service-worker, prefetch) naming
-//     which cache served the response. The origins are mutually exclusive.
+//   - FromDiskCache/FromServiceWorker/FromPrefetchCache/FromMemoryCache: shown
+//     as a single self-describing main-line token (disk, service-worker,
+//     prefetch, memory) naming which cache served the response. The origins
+//     are mutually exclusive.
+//   - Priority: shown on the "remote:" line as priority:<level>, so a late
+//     critical resource can be checked against what the browser thought of it.
 func Network(w io.Writer, entries []ipc.NetworkEntry, opts OutputOptions) error {
 	for _, e := range entries {
 		// Format duration
@@ -609,13 +834,15 @@ func networkCacheToken(e ipc.NetworkEntry) string {
 		return "disk"
 	case e.FromPrefetchCache:
 		return "prefetch"
+	case e.FromMemoryCache:
+		return "memory"
 	default:
 		return ""
 	}
 }
 
 // printNetworkRemote renders the transport line for an entry: the remote
-// endpoint and the negotiated protocol. It prints only when the protocol or
+// endpoint, the negotiated protocol, and the loading priority. It prints only when the protocol or
 // address was captured, so request-only and failed entries (which carry no
 // response) stay quiet.
 func printNetworkRemote(w io.Writer, e ipc.NetworkEntry) {
@@ -636,6 +863,9 @@ func printNetworkRemote(w io.Writer, e ipc.NetworkEntry) {
 	if e.ConnectionID > 0 {
 		parts = append(parts, fmt.Sprintf("conn:%d", int64(e.ConnectionID)))
 	}
+	if e.Priority != "" {
+		parts = append(parts, "priority:"+e.Priority)
+	}
 	// Only surface a non-secure posture; "secure" is the norm on HTTPS and would
 	// be noise on nearly every row, so its absence here means the request is fine.
 	if e.SecurityState != "" && e.SecurityState != "secure" {
@@ -864,13 +1094,73 @@ func Tab(w io.Writer, data ipc.TabData, opts OutputOptions) error {
 			}
 			_, _ = fmt.Fprintf(w, "%s - %s [", session.URL, title)
 			colorFprint(w, color.FgCyan, displayID)
-			_, _ = fmt.Fprintln(w, "]")
+			_, _ = fmt.Fprintf(w, "]%s\n", tabContext(session))
 		} else {
 			prefix := "  "
 			if isActive {
 				prefix = "* "
 			}
-			_, _ = fmt.Fprintf(w, "%s%s - %s [%s]\n", prefix, session.URL, title, displayID)
+			_, _ = fmt.Fprintf(w, "%s%s - %s [%s]%s\n", prefix, session.URL, title, displayID, tabContext(session))
+		}
+	}
+	return nil
+}
+
+// tabContext marks a tab in a 'context new' browser context with the
+// context's short ID.
+func tabContext(s ipc.PageSession) string {
+	if s.Context == "" {
+		return ""
+	}
+	return " (context " + shortID(s.Context) + ")"
+}
+
+// shortID truncates an ID to 8 characters for display.
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// Contexts outputs browser contexts, each followed by its tabs, marking the
+// active tab with "*".
+//
+// Example output:
+//
+//	default
+//	  https://example.com/ - Example Domain [9A3E12F0]
+//	C1D2E3F4 (incognito)
+//	* https://example.com/login - Log in [7B2C4D5E]
+func Contexts(w io.Writer, contexts []ipc.ContextInfo, opts OutputOptions) error {
+	for _, c := range contexts {
+		header := "default"
+		if !c.Default {
+			header = shortID(c.ID)
+			if c.Incognito {
+				header += " (incognito)"
+			}
+		}
+		if opts.UseColor {
+			colorFprint(w, color.FgCyan, header+"\n")
+		} else {
+			_, _ = fmt.Fprintln(w, header)
+		}
+		if len(c.Tabs) == 0 {
+			_, _ = fmt.Fprintln(w, "  (no tabs)")
+		}
+		for _, t := range c.Tabs {
+			prefix := "  "
+			if t.Active {
+				prefix = "* "
+			}
+			title := strings.TrimSpace(t.Title)
+			if len(title) > 40 {
+				title = title[:37] + "..."
+			}
+			if _, err := fmt.Fprintf(w, "%s%s - %s [%s]\n", prefix, t.URL, title, shortID(t.ID)); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -911,3 +1201,963 @@ func TabError(w io.Writer, errorMsg string, sessions []ipc.PageSession, matches
 }
 
 // Find outputs find results in text format with colored highlighting.
+
+// Frames outputs the frame tree, one frame per line, indented two spaces per
+// nesting level. Named frames show the name in parentheses; full frame IDs are
+// kept so they can be matched against console and network frameId fields.
+//
+// Example output:
+//
+//	https://shop.example.com/checkout [8F3A...]
+//	  https://js.stripe.com/v3/ (__privateStripeFrame1) [A1B2...]
+func Frames(w io.Writer, frames []ipc.FrameInfo) error {
+	for _, f := range frames {
+		url := f.URL
+		if url == "" {
+			url = "about:blank"
+		}
+		line := strings.Repeat("  ", f.Depth) + url
+		if f.Name != "" {
+			line += " (" + f.Name + ")"
+		}
+		if _, err := fmt.Fprintf(w, "%s [%s]\n", line, f.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// domTextMax is the longest text node DOMSnapshot prints before truncating.
+const domTextMax = 60
+
+// DOMSnapshot outputs a DOM snapshot as a tree, one node per line, indented
+// two spaces per nesting level below the document. Elements show their tag,
+// id, and classes, then the layout box and requested styles when rendered;
+// text nodes are quoted.
+//
+// Example output:
+//
+//	html  0,0 1280x720
+//	  body  0,0 1280x720  display=block
+//	    h1.title  8,21 1264x37  display=block
+//	      "Welcome back"
+func DOMSnapshot(w io.Writer, nodes []ipc.DOMNode) error {
+	for _, n := range nodes {
+		if n.Type == "document" {
+			continue
+		}
+		indent := strings.Repeat("  ", max(n.Depth-1, 0))
+		if n.Type == "text" {
+			text := strings.Join(strings.Fields(n.Value), " ")
+			if r := []rune(text); len(r) > domTextMax {
+				text = string(r[:domTextMax]) + "..."
+			}
+			if _, err := fmt.Fprintf(w, "%s%q\n", indent, text); err != nil {
+				return err
+			}
+			continue
+		}
+
+		line := indent + n.Name
+		if id := n.Attributes["id"]; id != "" {
+			line += "#" + id
+		}
+		for _, c := range strings.Fields(n.Attributes["class"]) {
+			line += "." + c
+		}
+		if b := n.Box; b != nil {
+			line += fmt.Sprintf("  %s,%s %sx%s", formatPx(b.X), formatPx(b.Y), formatPx(b.Width), formatPx(b.Height))
+		}
+		if len(n.Styles) > 0 {
+			names := make([]string, 0, len(n.Styles))
+			for name := range n.Styles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			pairs := make([]string, len(names))
+			for i, name := range names {
+				pairs[i] = name + "=" + n.Styles[name]
+			}
+			line += "  " + strings.Join(pairs, " ")
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Describe outputs the "describe" page summary: title and URL, then the
+// landmarks, headings, controls, and forms, each with its selector. Sections
+// with nothing in them are left out.
+//
+// Example output:
+//
+//	Checkout - Shop
+//	https://shop.test/checkout
+//
+//	Landmarks:
+//	  navigation "Main"  nav.primary
+//	  main  #content
+//	Headings:
+//	  h1 Checkout  #title
+//	    h2 Shipping  #content > h2
+//	Controls:
+//	  link "Home" -> /  a.logo
+//	  button "Place order"  #place-order
+//	Forms:
+//	  form #checkout -> /api/order
+//	    email "Email"  #email (required)
+//	    select "Country"  #country = "AU" [Australia, New Zealand]
+func Describe(w io.Writer, data ipc.DescribeData) error {
+	_, _ = fmt.Fprintf(w, "%s\n%s\n", data.Title, data.URL)
+	if len(data.Landmarks)+len(data.Headings)+len(data.Controls)+len(data.Forms) > 0 {
+		_, _ = fmt.Fprintln(w)
+	}
+
+	if len(data.Landmarks) > 0 {
+		_, _ = fmt.Fprint(w, "Landmarks:\n")
+		for _, l := range data.Landmarks {
+			_, _ = fmt.Fprintf(w, "  %s%s%s  %s\n", strings.Repeat("  ", l.Depth), l.Role, describeName(l.Label), l.Selector)
+		}
+	}
+
+	if len(data.Headings) > 0 {
+		top := data.Headings[0].Level
+		for _, h := range data.Headings {
+			top = min(top, h.Level)
+		}
+		_, _ = fmt.Fprint(w, "Headings:\n")
+		for _, h := range data.Headings {
+			_, _ = fmt.Fprintf(w, "  %sh%d %s  %s\n", strings.Repeat("  ", h.Level-top), h.Level, h.Text, h.Selector)
+		}
+	}
+
+	if len(data.Controls) > 0 {
+		_, _ = fmt.Fprint(w, "Controls:\n")
+		for _, c := range data.Controls {
+			line := "  " + c.Role + describeName(c.Name)
+			if c.Href != "" {
+				line += " -> " + c.Href
+			}
+			line += "  " + c.Selector
+			if c.Disabled {
+				line += " (disabled)"
+			}
+			_, _ = fmt.Fprintln(w, line)
+		}
+		if data.MoreControls > 0 {
+			_, _ = fmt.Fprintf(w, "  ... %d more (raise --limit)\n", data.MoreControls)
+		}
+	}
+
+	if len(data.Forms) > 0 {
+		_, _ = fmt.Fprint(w, "Forms:\n")
+		for _, f := range data.Forms {
+			line := "  (no form)"
+			if f.Selector != "" {
+				line = "  form " + f.Selector + describeName(f.Name)
+				if f.Action != "" {
+					line += " -> " + f.Action
+				}
+			}
+			_, _ = fmt.Fprintln(w, line)
+			for _, field := range f.Fields {
+				line := "    " + field.Type + describeName(field.Name) + "  " + field.Selector
+				switch {
+				case field.Checked:
+					line += " [x]"
+				case field.Type == "checkbox" || field.Type == "radio":
+					line += " [ ]"
+				case field.Value != "":
+					line += fmt.Sprintf(" = %q", field.Value)
+				}
+				if len(field.Options) > 0 {
+					line += " [" + strings.Join(field.Options, ", ") + "]"
+				}
+				var flags []string
+				if field.Required {
+					flags = append(flags, "required")
+				}
+				if field.Disabled {
+					flags = append(flags, "disabled")
+				}
+				if len(flags) > 0 {
+					line += " (" + strings.Join(flags, ", ") + ")"
+				}
+				_, _ = fmt.Fprintln(w, line)
+			}
+		}
+	}
+	return nil
+}
+
+// Elements outputs the "elements" inventory, one element per line: type,
+// name, and selector, then the link target and any state worth flagging.
+// Elements that are rendered but scrolled out of view are "offscreen".
+//
+// Example output:
+//
+//	link     "Home" -> /  a.logo
+//	button   "Place order"  #place-order (disabled)
+//	input    "Email"  #email
+//	button   "Open menu"  #menu (hidden)
+func Elements(w io.Writer, elements []ipc.InteractiveElement) error {
+	for _, e := range elements {
+		line := fmt.Sprintf("%-8s", e.Type) + describeName(e.Name)
+		if e.Href != "" {
+			line += " -> " + e.Href
+		}
+		line += "  " + e.Selector
+		var flags []string
+		if e.Disabled {
+			flags = append(flags, "disabled")
+		}
+		switch {
+		case !e.Visible:
+			flags = append(flags, "hidden")
+		case !e.InViewport:
+			flags = append(flags, "offscreen")
+		}
+		if len(flags) > 0 {
+			line += " (" + strings.Join(flags, ", ") + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SelectorCandidates outputs the "selector" command's ranked selectors, one
+// numbered line each with the strategy that built it, then the element's
+// XPath. Selectors are padded to a column, and those that also match other
+// elements show the match count, e.g. "3. button.primary  class, 3 matches".
+func SelectorCandidates(w io.Writer, data ipc.SelectorData) error {
+	width := 0
+	for _, c := range data.Candidates {
+		width = max(width, len(c.Selector))
+	}
+	for i, c := range data.Candidates {
+		note := c.Strategy
+		if !c.Unique {
+			note += fmt.Sprintf(", %d matches", c.Matches)
+		}
+		_, _ = fmt.Fprintf(w, "%d. %-*s  %s\n", i+1, width, c.Selector, note)
+	}
+	_, err := fmt.Fprintf(w, "xpath: %s\n", data.XPath)
+	return err
+}
+
+// Meta outputs the "meta" command's page metadata: the basic fields, then
+// each section that has entries. JSON-LD objects and microdata items are
+// summarized as their type and name; use --json for the full values.
+//
+// Example output:
+//
+//	title: Blue Widget - Shop
+//	description: A widget, in blue.
+//	canonical: https://shop.test/widgets/blue
+//	hreflang:
+//	  de  https://shop.test/de/widgets/blue
+//	openGraph:
+//	  og:title  Blue Widget
+//	  og:image  https://shop.test/blue.jpg
+//	jsonLd:
+//	  Product "Blue Widget"
+//	microdata:
+//	  https://schema.org/Product "Blue Widget"
+func Meta(w io.Writer, data ipc.MetaData) error {
+	fields := []struct{ label, value string }{
+		{"title", data.Title},
+		{"description", data.Description},
+		{"canonical", data.Canonical},
+		{"robots", data.Robots},
+	}
+	for _, f := range fields {
+		if f.value != "" {
+			_, _ = fmt.Fprintf(w, "%s: %s\n", f.label, f.value)
+		}
+	}
+
+	if len(data.Hreflang) > 0 {
+		_, _ = fmt.Fprint(w, "hreflang:\n")
+		for _, a := range data.Hreflang {
+			_, _ = fmt.Fprintf(w, "  %s  %s\n", a.Lang, a.URL)
+		}
+	}
+	for _, section := range []struct {
+		label string
+		tags  []ipc.MetaTag
+	}{{"openGraph", data.OpenGraph}, {"twitter", data.Twitter}} {
+		if len(section.tags) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s:\n", section.label)
+		for _, t := range section.tags {
+			_, _ = fmt.Fprintf(w, "  %s  %s\n", t.Property, t.Content)
+		}
+	}
+
+	if len(data.JSONLD) > 0 || len(data.JSONLDErrors) > 0 {
+		_, _ = fmt.Fprint(w, "jsonLd:\n")
+		for _, raw := range data.JSONLD {
+			var v any
+			if err := json.Unmarshal(raw, &v); err != nil {
+				continue
+			}
+			for _, line := range jsonLDSummary(v) {
+				_, _ = fmt.Fprintf(w, "  %s\n", line)
+			}
+		}
+		for _, e := range data.JSONLDErrors {
+			_, _ = fmt.Fprintf(w, "  error: %s\n", e)
+		}
+	}
+
+	if len(data.Microdata) > 0 {
+		_, _ = fmt.Fprint(w, "microdata:\n")
+		for _, item := range data.Microdata {
+			line := "(untyped)"
+			if len(item.Type) > 0 {
+				line = strings.Join(item.Type, " ")
+			}
+			if names := item.Properties["name"]; len(names) > 0 {
+				if name, ok := names[0].(string); ok && name != "" {
+					line += fmt.Sprintf(" %q", name)
+				}
+			}
+			_, _ = fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+	return nil
+}
+
+// jsonLDSummary names the objects in a JSON-LD value by @type and name (or
+// headline), expanding top-level arrays and @graph.
+func jsonLDSummary(v any) []string {
+	switch v := v.(type) {
+	case []any:
+		var lines []string
+		for _, item := range v {
+			lines = append(lines, jsonLDSummary(item)...)
+		}
+		return lines
+	case map[string]any:
+		if graph, ok := v["@graph"]; ok {
+			return jsonLDSummary(graph)
+		}
+		line := "(untyped)"
+		switch t := v["@type"].(type) {
+		case string:
+			line = t
+		case []any:
+			var types []string
+			for _, s := range t {
+				if s, ok := s.(string); ok {
+					types = append(types, s)
+				}
+			}
+			line = strings.Join(types, ", ")
+		}
+		for _, key := range []string{"name", "headline"} {
+			if name, ok := v[key].(string); ok && name != "" {
+				line += fmt.Sprintf(" %q", name)
+				break
+			}
+		}
+		return []string{line}
+	}
+	return nil
+}
+
+// describeName quotes a non-empty accessible name after a space.
+func describeName(name string) string {
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf(" %q", name)
+}
+
+// Buffers formats the "buffer" command's report: each buffer's fill level and
+// drops, then whether bodies are captured.
+func Buffers(w io.Writer, data ipc.BufferData, opts OutputOptions) error {
+	for _, b := range data.Buffers {
+		_, _ = fmt.Fprintf(w, "%s: %d/%d entries", b.Name, b.Entries, b.Capacity)
+		if b.Dropped > 0 {
+			if opts.UseColor {
+				_, _ = fmt.Fprint(w, ", ")
+				colorFprintf(w, color.FgYellow, "%d dropped", b.Dropped)
+			} else {
+				_, _ = fmt.Fprintf(w, ", %d dropped", b.Dropped)
+			}
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+	bodies := "off"
+	if data.CaptureBodies {
+		bodies = "on"
+		if data.Capture != "" {
+			bodies += " (" + data.Capture + ")"
+		}
+	}
+	_, err := fmt.Fprintf(w, "bodies: %s\n", bodies)
+	return err
+}
+
+// Extensions outputs the browser's extensions, one per line: ID, name and
+// version, and the directory it was loaded from. Idle extensions are marked.
+//
+// Example output:
+//
+//	nilnepjhjgfacmijbmgplkmpgnbgekgi  Dev Tool 1.2.0  /home/user/my-extension
+//	abcdefghijklmnopabcdefghijklmnop  Password Manager
+func Extensions(w io.Writer, extensions []ipc.ExtensionInfo, opts OutputOptions) error {
+	if len(extensions) == 0 {
+		_, err := fmt.Fprintln(w, "No extensions")
+		return err
+	}
+	for _, e := range extensions {
+		if e.Error != "" {
+			if opts.UseColor {
+				colorFprintf(w, color.FgRed, "error: %s: %s\n", e.Path, e.Error)
+			} else {
+				_, _ = fmt.Fprintf(w, "error: %s: %s\n", e.Path, e.Error)
+			}
+			continue
+		}
+		line := e.ID
+		name := strings.TrimSpace(e.Name + " " + e.Version)
+		if name != "" {
+			line += "  " + name
+		}
+		if e.Path != "" {
+			line += "  " + e.Path
+		}
+		if !e.Active {
+			line += " (idle)"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// History outputs navigation history entries with their indices, marking the
+// current entry with "*".
+//
+// Example output:
+//
+//	  0  https://example.com/ - Example Domain
+//	* 1  https://example.com/about - About
+func History(w io.Writer, entries []ipc.HistoryEntry, opts OutputOptions) error {
+	// Right-align indices to the widest one (the last)
+	width := 1
+	if len(entries) > 0 {
+		width = len(fmt.Sprint(entries[len(entries)-1].Index))
+	}
+	for _, e := range entries {
+		title := strings.TrimSpace(e.Title)
+		line := e.URL
+		if title != "" && title != e.URL {
+			line += " - " + title
+		}
+		if e.Current {
+			if opts.UseColor {
+				colorFprint(w, color.FgCyan, "* ")
+			} else {
+				_, _ = fmt.Fprint(w, "* ")
+			}
+		} else {
+			_, _ = fmt.Fprint(w, "  ")
+		}
+		if _, err := fmt.Fprintf(w, "%*d  %s\n", width, e.Index, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Monitors formats 'monitor list' output, one monitor per line with its
+// latest status, interval, and counts:
+//
+//	m1  PASS  every 5m  12 checks, 1 failed  https://example.com/
+//	m2  FAIL  every 1m  3 checks, 3 failed  https://staging.example.com/
+//	      selector:#app: no element matches #app
+func Monitors(w io.Writer, monitors []ipc.MonitorInfo, opts OutputOptions) error {
+	if len(monitors) == 0 {
+		_, err := fmt.Fprintln(w, "No monitors")
+		return err
+	}
+	for _, m := range monitors {
+		_, _ = fmt.Fprintf(w, "%s  ", m.ID)
+		writeMonitorStatus(w, m.Last, opts)
+		if _, err := fmt.Fprintf(w, "  every %s  %d checks, %d failed  %s\n",
+			MonitorInterval(m.Every), m.Checks, m.Failures, m.URL); err != nil {
+			return err
+		}
+		if m.Last != nil && m.Last.Error != "" {
+			_, _ = fmt.Fprintf(w, "      %s\n", m.Last.Error)
+		}
+	}
+	return nil
+}
+
+// MonitorResults formats 'monitor results' output, one check per line:
+//
+//	2025-06-01 14:30:52  PASS  1.2s
+//	2025-06-01 14:35:52  FAIL  30.0s  selector:#app: no element matches #app
+//	      /tmp/webctl-monitors/m1-25-06-01-143052/25-06-01-143552.png
+func MonitorResults(w io.Writer, results []ipc.MonitorResult, opts OutputOptions) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(w, "No checks yet")
+		return err
+	}
+	for _, r := range results {
+		_, _ = fmt.Fprintf(w, "%s  ", time.UnixMilli(r.Time).Format("2006-01-02 15:04:05"))
+		res := r
+		writeMonitorStatus(w, &res, opts)
+		line := fmt.Sprintf("  %.1fs", float64(r.DurationMs)/1000)
+		if r.Error != "" {
+			line += "  " + r.Error
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		if r.Screenshot != "" {
+			_, _ = fmt.Fprintf(w, "      %s\n", r.Screenshot)
+		}
+	}
+	return nil
+}
+
+// writeMonitorStatus writes PASS, FAIL, or WAIT for a monitor's last check.
+func writeMonitorStatus(w io.Writer, last *ipc.MonitorResult, opts OutputOptions) {
+	status, c := "WAIT", color.FgYellow
+	if last != nil {
+		status, c = "PASS", color.FgGreen
+		if !last.OK {
+			status, c = "FAIL", color.FgRed
+		}
+	}
+	if opts.UseColor {
+		colorFprint(w, c, status)
+	} else {
+		_, _ = fmt.Fprint(w, status)
+	}
+}
+
+// PoolResults formats 'pool map' output: a header line per URL with its
+// status and duration, the value or error under it, and a summary:
+//
+//	== https://example.com/  OK  1.2s
+//	Example Domain
+//	== https://example.org/  FAIL  30.0s
+//	navigation timeout
+//	2 URLs, 1 failed
+//
+// String values are printed as they are; other values as JSON.
+func PoolResults(w io.Writer, results []ipc.PoolMapResult, opts OutputOptions) error {
+	failed := 0
+	for _, r := range results {
+		status, c := "OK", color.FgGreen
+		if !r.OK {
+			status, c = "FAIL", color.FgRed
+			failed++
+		}
+		_, _ = fmt.Fprintf(w, "== %s  ", r.URL)
+		if opts.UseColor {
+			colorFprint(w, c, status)
+		} else {
+			_, _ = fmt.Fprint(w, status)
+		}
+		_, _ = fmt.Fprintf(w, "  %.1fs\n", float64(r.DurationMs)/1000)
+
+		body := r.Error
+		if r.OK {
+			var s string
+			if err := json.Unmarshal(r.Value, &s); err == nil {
+				body = s
+			} else {
+				body = string(r.Value)
+			}
+		}
+		if body != "" {
+			if _, err := fmt.Fprintln(w, strings.TrimRight(body, "\n")); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d URLs, %d failed\n", len(results), failed)
+	return err
+}
+
+// MonitorInterval formats a monitor interval in milliseconds as a duration
+// without zero units: 5m, 1h30m, 45s.
+func MonitorInterval(ms int64) string {
+	s := (time.Duration(ms) * time.Millisecond).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Mocks formats 'mock list' output, one mock per line with what it does to
+// matching requests and how often it applied:
+//
+//	k1  */api/*  delay 2s, fail 30%  14 hits, 4 failed
+//	k2  */api/flags*  stub 200 application/json (120B)  3 hits, 0 failed
+func Mocks(w io.Writer, mocks []ipc.MockInfo) error {
+	if len(mocks) == 0 {
+		_, err := fmt.Fprintln(w, "No mocks")
+		return err
+	}
+	for _, m := range mocks {
+		if _, err := fmt.Fprintf(w, "%s  %s  %s  %d hits, %d failed\n",
+			m.ID, m.URL, MockEffect(m), m.Hits, m.Failures); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MockEffect describes what a mock does to the requests it matches:
+// "stub 200 application/json (120B), delay 2s, fail 30% with 503".
+func MockEffect(m ipc.MockInfo) string {
+	var parts []string
+	if m.Status != 0 {
+		parts = append(parts, fmt.Sprintf("stub %d %s (%s)", m.Status, m.ContentType, Bytes(int64(m.BodyBytes))))
+	}
+	if m.Delay > 0 {
+		parts = append(parts, "delay "+MonitorInterval(m.Delay))
+	}
+	if m.FailRate > 0 {
+		fail := fmt.Sprintf("fail %.4g%%", m.FailRate*100)
+		if m.FailStatus != 0 {
+			fail += fmt.Sprintf(" with %d", m.FailStatus)
+		}
+		parts = append(parts, fail)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Rewrites formats 'rewrite list' output, one rewrite per line with its edits
+// and how often it applied, and the latest failure indented below:
+//
+//	w1  */api/flags*  JSON patch (2 ops)  5 rewritten, 1 failed
+//	      op 0 (replace /beta): path not found
+//	w2  */api/me*  replace "free" → "pro"  3 rewritten, 0 failed
+func Rewrites(w io.Writer, rewrites []ipc.RewriteInfo) error {
+	if len(rewrites) == 0 {
+		_, err := fmt.Fprintln(w, "No rewrites")
+		return err
+	}
+	for _, r := range rewrites {
+		if _, err := fmt.Fprintf(w, "%s  %s  %s  %d rewritten, %d failed\n",
+			r.ID, r.URL, RewriteEdits(r), r.Hits, r.Errors); err != nil {
+			return err
+		}
+		if r.LastError != "" {
+			_, _ = fmt.Fprintf(w, "      %s\n", r.LastError)
+		}
+	}
+	return nil
+}
+
+// RewriteEdits describes a rewrite's edits: `JSON patch (2 ops), replace
+// "free" → "pro"`.
+func RewriteEdits(r ipc.RewriteInfo) string {
+	var parts []string
+	switch r.PatchOps {
+	case 0:
+	case 1:
+		parts = append(parts, "JSON patch (1 op)")
+	default:
+		parts = append(parts, fmt.Sprintf("JSON patch (%d ops)", r.PatchOps))
+	}
+	for _, rep := range r.Replace {
+		parts = append(parts, fmt.Sprintf("replace %q → %q", rep.From, rep.To))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Aborts formats 'network abort' output: one rule per line with what it does
+// and how many requests it matched, then the held requests that can be
+// aborted by ID and when each was paused:
+//
+//	a1  */upload*  abort the next request after 2s  0 hits
+//	a2  */poll*  hold every request  3 hits
+//	Held:
+//	  1234.56  https://app.test/poll?since=9  by a2 since 15:04:05
+func Aborts(w io.Writer, data ipc.AbortData) error {
+	if len(data.Rules) == 0 && len(data.Held) == 0 {
+		_, err := fmt.Fprintln(w, "No abort rules or held requests")
+		return err
+	}
+	for _, r := range data.Rules {
+		if _, err := fmt.Fprintf(w, "%s  %s  %s  %d hits\n", r.ID, r.URL, AbortEffect(r), r.Hits); err != nil {
+			return err
+		}
+	}
+	if len(data.Held) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "Held:"); err != nil {
+		return err
+	}
+	for _, h := range data.Held {
+		if _, err := fmt.Fprintf(w, "  %s  %s  by %s since %s\n",
+			h.RequestID, h.URL, h.By, time.UnixMilli(h.Since).Format("15:04:05")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AbortEffect describes what an abort rule does to the requests it matches:
+// "abort the next 3 requests after 2s" or "hold every request".
+func AbortEffect(r ipc.AbortRule) string {
+	var which string
+	switch r.Times {
+	case 0:
+		which = "every request"
+	case 1:
+		which = "the next request"
+	default:
+		which = fmt.Sprintf("the next %d requests", r.Times)
+	}
+	switch {
+	case r.Hold:
+		return "hold " + which
+	case r.Delay > 0:
+		return "abort " + which + " after " + MonitorInterval(r.Delay)
+	}
+	return "abort " + which
+}
+
+// Scripts outputs one line per loaded script: its scriptId, size, and URL,
+// marked "(map)" when it declares a source map.
+//
+// Format:
+//
+//	41  312.0KB  https://example.com/static/app.js (map)
+//	57  1.2KB    https://example.com/
+func Scripts(w io.Writer, scripts []ipc.ScriptSource) error {
+	for _, s := range scripts {
+		mapped := ""
+		if s.SourceMapURL != "" {
+			mapped = " (map)"
+		}
+		if _, err := fmt.Fprintf(w, "%s  %-7s  %s%s\n", s.ID, formatBytes(int64(s.Size)), s.URL, mapped); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Logpoints formats 'logpoint list' output, one logpoint per line:
+//
+//	l1  app.js:120  user.id, cart.items.length
+//	l2  vendor.min.js:1:4521  arguments
+func Logpoints(w io.Writer, logpoints []ipc.LogpointInfo) error {
+	if len(logpoints) == 0 {
+		_, err := fmt.Fprintln(w, "No logpoints")
+		return err
+	}
+	for _, lp := range logpoints {
+		if _, err := fmt.Fprintf(w, "%s  %s  %s\n", lp.ID, LogpointLocation(lp), lp.Expression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogpointLocation formats where a logpoint is set: url:line, or
+// url:line:column when it has a column.
+func LogpointLocation(lp ipc.LogpointInfo) string {
+	if lp.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d", lp.URL, lp.Line, lp.Column)
+	}
+	return fmt.Sprintf("%s:%d", lp.URL, lp.Line)
+}
+
+// PausedExceptions outputs captured exceptions, oldest first: a header line
+// with the sequence number, time, and message, then the stack and the
+// throwing frame's locals.
+//
+// Format:
+//
+//	3 14:05:02.904 Uncaught TypeError: Cannot read properties of undefined (reading 'id')
+//	       stack:
+//	         loadUser https://example.com/app.js:119:14
+//	       locals:
+//	         user = undefined
+//	         name = "Ada"
+func PausedExceptions(w io.Writer, exceptions []ipc.PausedException) error {
+	if len(exceptions) == 0 {
+		_, err := fmt.Fprintln(w, "No exceptions captured")
+		return err
+	}
+	for _, e := range exceptions {
+		msg := e.Message
+		switch {
+		case e.Reason == "promiseRejection":
+			msg = "Unhandled rejection: " + msg
+		case e.Uncaught:
+			msg = "Uncaught " + msg
+		}
+		ts := time.UnixMilli(e.Timestamp).Format("15:04:05.000")
+		if _, err := fmt.Fprintf(w, "%d %s %s\n", e.Seq, ts, msg); err != nil {
+			return err
+		}
+		printConsoleStack(w, e.Stack)
+		if len(e.Locals) > 0 {
+			_, _ = fmt.Fprintf(w, "%slocals:\n", netIndent)
+			for _, l := range e.Locals {
+				value := l.Value
+				if l.Type == "string" {
+					value = strconv.Quote(value)
+				}
+				_, _ = fmt.Fprintf(w, "%s%s = %s\n", netIndent2, l.Name, value)
+			}
+		}
+	}
+	return nil
+}
+
+// HeapLeakcheck outputs a leak check: the heap after each cycle, the growth
+// from the first cycle to the last, and the constructors that grew.
+//
+// Example output:
+//
+//	cycle 1  used 12.4MB  total 20.0MB
+//	cycle 2  used 13.1MB  total 20.0MB
+//	grew +720.0KB over 1 cycle, every cycle: likely a leak
+//	growing constructors:
+//	       +1200  +93.8KB  Detached HTMLDivElement (1250 objects, 97.7KB)
+func HeapLeakcheck(w io.Writer, data ipc.HeapLeakcheckData) error {
+	for _, s := range data.Samples {
+		if _, err := fmt.Fprintf(w, "cycle %d  used %s  total %s\n", s.Cycle, formatBytes(s.UsedSize), formatBytes(s.TotalSize)); err != nil {
+			return err
+		}
+	}
+
+	cycles := len(data.Samples) - 1
+	noun := "cycles"
+	if cycles == 1 {
+		noun = "cycle"
+	}
+	verdict := "no steady growth"
+	if data.Monotonic {
+		verdict = "every cycle: likely a leak"
+	}
+	if _, err := fmt.Fprintf(w, "grew %s over %d %s, %s\n", signedBytes(data.Growth), cycles, noun, verdict); err != nil {
+		return err
+	}
+
+	if len(data.Constructors) == 0 {
+		_, err := fmt.Fprintln(w, "no constructors grew")
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "growing constructors:"); err != nil {
+		return err
+	}
+	for _, c := range data.Constructors {
+		if _, err := fmt.Fprintf(w, "%s%+d  %s  %s (%d objects, %s)\n", netIndent, c.CountDelta, signedBytes(c.SizeDelta),
+			c.Name, c.Count, formatBytes(c.Size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// signedBytes renders a byte delta with its sign.
+func signedBytes(n int64) string {
+	if n < 0 {
+		return "-" + formatBytes(-n)
+	}
+	return "+" + formatBytes(n)
+}
+
+// BenchCompare renders the median metrics of two URLs side by side, with
+// B's change from A.
+func BenchCompare(w io.Writer, urlA, urlB string, a, b ipc.BenchMetrics, runs int, warm bool, cpu float64) error {
+	cache := "cold"
+	if warm {
+		cache = "warm"
+	}
+	noun := "loads"
+	if runs == 1 {
+		noun = "load"
+	}
+	conditions := ""
+	if cpu > 1 {
+		conditions = ", CPU throttled " + CPUFactor(cpu)
+	}
+	if _, err := fmt.Fprintf(w, "A  %s\nB  %s\nmedian of %d %s %s each%s\n\n", urlA, urlB, runs, cache, noun, conditions); err != nil {
+		return err
+	}
+
+	ms := func(v float64) string { return fmt.Sprintf("%.0fms", v) }
+	rows := []struct {
+		name string
+		a, b float64
+		text func(float64) string
+	}{
+		{"TTFB", a.TTFB, b.TTFB, ms},
+		{"FCP", a.FCP, b.FCP, ms},
+		{"LCP", a.LCP, b.LCP, ms},
+		{"DCL", a.DOMContentLoaded, b.DOMContentLoaded, ms},
+		{"Load", a.Load, b.Load, ms},
+		{"CLS", a.CLS, b.CLS, func(v float64) string { return fmt.Sprintf("%.3f", v) }},
+		{"Requests", float64(a.Requests), float64(b.Requests), func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+		{"Transfer", float64(a.TransferSize), float64(b.TransferSize), func(v float64) string { return formatBytes(int64(v)) }},
+		{"JS heap", float64(a.JSHeapUsed), float64(b.JSHeapUsed), func(v float64) string { return formatBytes(int64(v)) }},
+	}
+	if _, err := fmt.Fprintf(w, "%-8s %10s %10s %10s\n", "metric", "A", "B", "delta"); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		delta := "-"
+		if d, ok := PercentChange(r.a, r.b); ok {
+			delta = fmt.Sprintf("%+.1f%%", d)
+		}
+		if _, err := fmt.Fprintf(w, "%-8s %10s %10s %10s\n", r.name, r.text(r.a), r.text(r.b), delta); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CPUFactor formats a CPU slowdown factor as 4x or 2.5x.
+func CPUFactor(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64) + "x"
+}
+
+// PercentChange returns the change from a to b as a percentage of a. There
+// is none when a is zero.
+func PercentChange(a, b float64) (float64, bool) {
+	if a == 0 {
+		return 0, false
+	}
+	return (b - a) / a * 100, true
+}
+
+// BannerHits renders the overlay elements dismiss-banners acted on, one per
+// line: action, rule, selector.
+func BannerHits(w io.Writer, hits []ipc.BannerHit) error {
+	if len(hits) == 0 {
+		_, err := fmt.Fprintln(w, "No banners found")
+		return err
+	}
+	for _, h := range hits {
+		if _, err := fmt.Fprintf(w, "%-7s  %s  %s\n", h.Action, h.Rule, h.Selector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var framesCmd = &cobra.Command{
+	Use:   "frames",
+	Short: "Show the page's frame tree",
+	Long: `Prints the frame hierarchy of the current page (main frame first, iframes
+indented beneath their parents) with each frame's URL, name, and frame ID.
+
+Console and network entries carry a frameId field in JSON output that matches
+these IDs, so activity from embeds, payment iframes, and ads can be told apart
+from the main document:
+
+  webctl console --json | jq '.entries[] | select(.frameId == "A1B2...")'
+
+Examples:
+  frames                                # Tree of URLs and IDs
+  frames --json | jq -r '.frames[] | select(.depth > 0) | .url'
+
+Text output:
+  https://shop.example.com/checkout [8F3A...]
+    https://js.stripe.com/v3/ (__privateStripeFrame1) [A1B2...]
+    https://ads.example.net/ [C3D4...]
+
+JSON output:
+  {"ok": true, "frames": [{"id": "...", "parentId": "...", "url": "...", "name": "...", "depth": 1}]}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.NoArgs,
+	RunE: runFrames,
+}
+
+func init() {
+	rootCmd.AddCommand(framesCmd)
+}
+
+func runFrames(cmd *cobra.Command, args []string) error {
+	t := startTimer("frames")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	debugRequest("frames", "")
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "frames"})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.FramesData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":     true,
+			"frames": data.Frames,
+		})
+	}
+
+	return format.Frames(os.Stdout, data.Frames)
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunFrames_JSON(t *testing.T) {
+	enableJSONOutput(t)
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "frames" {
+				t.Errorf("expected cmd=frames, got %s", req.Cmd)
+			}
+			raw, _ := json.Marshal(ipc.FramesData{Frames: []ipc.FrameInfo{
+				{ID: "MAIN", URL: "https://example.com/"},
+				{ID: "CHILD", ParentID: "MAIN", URL: "https://embed.example.net/", Depth: 1},
+			}})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runFrames(framesCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		OK     bool            `json:"ok"`
+		Frames []ipc.FrameInfo `json:"frames"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !result.OK || len(result.Frames) != 2 || result.Frames[1].ParentID != "MAIN" {
+		t.Errorf("unexpected response: %s", out)
+	}
+}
@@ -0,0 +1,359 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// guardPollInterval is how often guard reads the console and network buffers.
+const guardPollInterval = time.Second
+
+// Violation kinds guard can fail on.
+const (
+	guardConsoleError   = "console-error"
+	guardConsoleWarning = "console-warning"
+	guardNetwork4xx     = "network-4xx"
+	guardNetwork5xx     = "network-5xx"
+	guardNetworkFailed  = "network-failed"
+)
+
+var guardKinds = []string{guardConsoleError, guardConsoleWarning, guardNetwork4xx, guardNetwork5xx, guardNetworkFailed}
+
+var guardCmd = &cobra.Command{
+	Use:   "guard",
+	Short: "Fail when console errors or failed requests occur while watching",
+	Long: `Watches the active tab's console and network buffers while another process
+drives the app, such as a test suite, and exits 1 if any violation occurred.
+This makes "no console errors" an enforceable CI gate.
+
+Only entries logged after guard starts count. Each violation is printed as it
+is seen, and a summary is printed when guard ends: after --duration, on
+Ctrl-C, or on SIGTERM, so 'kill' ends the watch and still reports.
+
+Violations (--fail-on, comma-separated or repeated):
+  console-error      A console error or uncaught exception
+  console-warning    A console warning
+  network-4xx        A response with a 4xx status
+  network-5xx        A response with a 5xx status
+  network-failed     A request that failed without a response (DNS, CORS, aborted)
+
+Flags:
+  --duration <d>     Stop watching after this long (default: until interrupted)
+  --fail-on <kinds>  Violations to fail on (default console-error,network-5xx)
+  --fail-fast        Stop at the first violation
+
+Examples:
+  guard --duration 60s --fail-on console-error,network-5xx
+  webctl guard & guard=$!; npm test; kill $guard; wait $guard
+  guard --fail-on console-error,network-4xx,network-5xx --fail-fast
+
+Text output:
+  14:03:21.118 console-error  Uncaught TypeError: x is undefined (https://app.test/main.js:42)
+  14:03:22.410 network-5xx  500 POST https://app.test/api/save
+  2 violations in 45s: 1 console-error, 1 network-5xx
+
+JSON output:
+  {"ok": false, "durationMs": 45012, "failOn": [...], "violations": [...]}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.NoArgs,
+	RunE: runGuard,
+}
+
+func init() {
+	guardCmd.Flags().Duration("duration", 0, "Stop watching after this duration (0 = until interrupted)")
+	guardCmd.Flags().StringSlice("fail-on", []string{guardConsoleError, guardNetwork5xx}, "Violations to fail on: "+strings.Join(guardKinds, ", "))
+	guardCmd.Flags().Bool("fail-fast", false, "Stop at the first violation")
+	rootCmd.AddCommand(guardCmd)
+}
+
+// guardViolation is a buffer entry that broke one of guard's rules.
+type guardViolation struct {
+	Kind string `json:"kind"`
+	Seq  uint64 `json:"seq"`  // buffer sequence number, for 'console <n>' or 'network <n>'
+	Time int64  `json:"time"` // Unix milliseconds
+	Text string `json:"text"`
+}
+
+// guard tracks which buffer entries have been checked across polls.
+type guard struct {
+	failOn map[string]bool
+	// consoleAfter is the highest console seq checked; console entries do not
+	// change once buffered.
+	consoleAfter uint64
+	// networkAfter is the highest network seq at start. Network entries are
+	// updated when their response arrives, so later ones are checked on every
+	// poll until reported.
+	networkAfter uint64
+	reported     map[uint64]bool
+	violations   []guardViolation
+}
+
+func runGuard(cmd *cobra.Command, args []string) error {
+	t := startTimer("guard")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	duration, _ := cmd.Flags().GetDuration("duration")
+	kinds, _ := cmd.Flags().GetStringSlice("fail-on")
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	if duration < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--duration must be zero or more")
+	}
+	failOn, err := parseGuardKinds(kinds)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	debugParam("duration=%v fail-on=%v fail-fast=%v", duration, kinds, failFast)
+
+	g := &guard{failOn: failOn, reported: map[uint64]bool{}}
+	if err := g.start(); err != nil {
+		return outputError(err.Error())
+	}
+
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	if duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	if !JSONOutput {
+		watching := "until interrupted"
+		if duration > 0 {
+			watching = "for " + duration.String()
+		}
+		fmt.Fprintf(os.Stderr, "Guarding %s, failing on %s\n", watching, strings.Join(sortedGuardKinds(failOn), ", "))
+	}
+
+	started := time.Now()
+	ticker := time.NewTicker(guardPollInterval)
+	defer ticker.Stop()
+
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			// One last read catches what was logged since the previous poll
+			done = true
+		case <-ticker.C:
+		}
+		found, err := g.poll()
+		if err != nil {
+			return outputError(err.Error())
+		}
+		if !JSONOutput {
+			for _, v := range found {
+				fmt.Fprintf(os.Stdout, "%s %s  %s\n", time.UnixMilli(v.Time).Format("15:04:05.000"), v.Kind, v.Text)
+			}
+		}
+		if failFast && len(g.violations) > 0 {
+			done = true
+		}
+	}
+	elapsed := time.Since(started)
+
+	if JSONOutput {
+		violations := g.violations
+		if violations == nil {
+			violations = []guardViolation{}
+		}
+		if err := outputJSON(os.Stdout, map[string]any{
+			"ok":         len(violations) == 0,
+			"durationMs": elapsed.Milliseconds(),
+			"failOn":     sortedGuardKinds(failOn),
+			"violations": violations,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(g.violations) == 0 {
+		if !JSONOutput {
+			fmt.Fprintf(os.Stdout, "No violations in %s\n", elapsed.Round(time.Second))
+		}
+		return nil
+	}
+	summary := guardSummary(g.violations, elapsed)
+	if !JSONOutput {
+		fmt.Fprintln(os.Stderr, summary)
+	}
+	return printedError{err: errors.New(summary)}
+}
+
+// parseGuardKinds validates --fail-on.
+func parseGuardKinds(kinds []string) (map[string]bool, error) {
+	failOn := map[string]bool{}
+	for _, k := range kinds {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		valid := false
+		for _, known := range guardKinds {
+			if k == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid --fail-on %q: must be one of %s", k, strings.Join(guardKinds, ", "))
+		}
+		failOn[k] = true
+	}
+	if len(failOn) == 0 {
+		return nil, errors.New("--fail-on requires at least one kind")
+	}
+	return failOn, nil
+}
+
+// sortedGuardKinds lists the kinds in failOn in the order of guardKinds.
+func sortedGuardKinds(failOn map[string]bool) []string {
+	var kinds []string
+	for _, k := range guardKinds {
+		if failOn[k] {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds
+}
+
+// start records the newest entries in both buffers so that only later ones
+// are checked. Reading the network buffer also enables network capture for
+// the active tab.
+func (g *guard) start() error {
+	data, err := fetchConsoleData()
+	if err != nil {
+		return err
+	}
+	for _, e := range data.Entries {
+		g.consoleAfter = max(g.consoleAfter, e.Seq)
+	}
+	entries, err := fetchNetworkEntries()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		g.networkAfter = max(g.networkAfter, e.Seq)
+	}
+	return nil
+}
+
+// poll reads both buffers and returns the violations not seen before.
+func (g *guard) poll() ([]guardViolation, error) {
+	data, err := fetchConsoleData()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fetchNetworkEntries()
+	if err != nil {
+		return nil, err
+	}
+	found := append(g.checkConsole(data.Entries), g.checkNetwork(entries)...)
+	g.violations = append(g.violations, found...)
+	return found, nil
+}
+
+// checkConsole returns the violations among console entries newer than the
+// last check.
+func (g *guard) checkConsole(entries []ipc.ConsoleEntry) []guardViolation {
+	var found []guardViolation
+	after := g.consoleAfter
+	for _, e := range entries {
+		if e.Seq <= after {
+			continue
+		}
+		g.consoleAfter = max(g.consoleAfter, e.Seq)
+
+		var kind string
+		switch e.Type {
+		case ipc.ConsoleTypeError:
+			kind = guardConsoleError
+		case ipc.ConsoleTypeWarning:
+			kind = guardConsoleWarning
+		}
+		if !g.failOn[kind] {
+			continue
+		}
+		text := e.Text
+		if e.URL != "" {
+			text += fmt.Sprintf(" (%s:%d)", e.URL, e.Line)
+		}
+		found = append(found, guardViolation{Kind: kind, Seq: e.Seq, Time: e.Timestamp, Text: text})
+	}
+	return found
+}
+
+// checkNetwork returns the violations among network entries logged since
+// start that have not been reported. Requests still in flight are checked
+// again on the next poll.
+func (g *guard) checkNetwork(entries []ipc.NetworkEntry) []guardViolation {
+	var found []guardViolation
+	for _, e := range entries {
+		if e.Seq <= g.networkAfter || g.reported[e.Seq] {
+			continue
+		}
+
+		var kind, text string
+		switch {
+		case e.Failed:
+			kind = guardNetworkFailed
+			text = fmt.Sprintf("%s %s: %s", e.Method, e.URL, e.Error)
+		case e.Status >= 500 && e.Status < 600:
+			kind = guardNetwork5xx
+		case e.Status >= 400 && e.Status < 500:
+			kind = guardNetwork4xx
+		}
+		if !g.failOn[kind] {
+			continue
+		}
+		if text == "" {
+			text = fmt.Sprintf("%d %s %s", e.Status, e.Method, e.URL)
+		}
+		g.reported[e.Seq] = true
+
+		at := e.ResponseTime
+		if at == 0 {
+			at = e.RequestTime
+		}
+		found = append(found, guardViolation{Kind: kind, Seq: e.Seq, Time: at, Text: text})
+	}
+	return found
+}
+
+// guardSummary counts violations by kind, e.g.
+// "3 violations in 45s: 2 console-error, 1 network-5xx".
+func guardSummary(violations []guardViolation, elapsed time.Duration) string {
+	counts := map[string]int{}
+	for _, v := range violations {
+		counts[v.Kind]++
+	}
+	kinds := make([]string, 0, len(counts))
+	for k := range counts {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, len(kinds))
+	for i, k := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", counts[k], k)
+	}
+	noun := "violations"
+	if len(violations) == 1 {
+		noun = "violation"
+	}
+	return fmt.Sprintf("%d %s in %s: %s", len(violations), noun, elapsed.Round(time.Second), strings.Join(parts, ", "))
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func newGuardTestCmd(failOn string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().Duration("duration", 10*time.Millisecond, "")
+	cmd.Flags().StringSlice("fail-on", nil, "")
+	cmd.Flags().Bool("fail-fast", false, "")
+	_ = cmd.Flags().Set("fail-on", failOn)
+	return cmd
+}
+
+// guardExecutor serves the baseline buffers on the first read of each and the
+// later buffers after.
+func guardExecutor(baseConsole, console []ipc.ConsoleEntry, baseNetwork, network []ipc.NetworkEntry) *mockExecutor {
+	reads := map[string]int{}
+	return &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		reads[req.Cmd]++
+		switch req.Cmd {
+		case "console":
+			entries := console
+			if reads[req.Cmd] == 1 {
+				entries = baseConsole
+			}
+			return ipc.SuccessResponse(ipc.ConsoleData{Entries: entries, Count: len(entries)}), nil
+		case "network":
+			entries := network
+			if reads[req.Cmd] == 1 {
+				entries = baseNetwork
+			}
+			return ipc.SuccessResponse(ipc.NetworkData{Entries: entries, Count: len(entries)}), nil
+		}
+		return ipc.ErrorResponse("unexpected command " + req.Cmd), nil
+	}}
+}
+
+func TestRunGuard_Violations(t *testing.T) {
+	enableJSONOutput(t)
+	old := ipc.ConsoleEntry{Seq: 1, Type: "error", Text: "before guard"}
+	exec := guardExecutor(
+		[]ipc.ConsoleEntry{old},
+		[]ipc.ConsoleEntry{old,
+			{Seq: 2, Type: "log", Text: "fine"},
+			{Seq: 3, Type: "error", Text: "Uncaught TypeError", URL: "https://app.test/main.js", Line: 42},
+			{Seq: 4, Type: "warning", Text: "deprecated"},
+		},
+		[]ipc.NetworkEntry{{Seq: 1, Status: 500, Method: "GET", URL: "https://app.test/old"}},
+		[]ipc.NetworkEntry{
+			{Seq: 1, Status: 500, Method: "GET", URL: "https://app.test/old"},
+			{Seq: 2, Status: 200, Method: "GET", URL: "https://app.test/"},
+			{Seq: 3, Status: 503, Method: "POST", URL: "https://app.test/api/save"},
+			{Seq: 4, Status: 404, Method: "GET", URL: "https://app.test/missing"},
+		},
+	)
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runGuard(newGuardTestCmd("console-error,network-5xx"), nil)
+	})
+	if err == nil || !IsPrintedError(err) {
+		t.Fatalf("error = %v, want a printed failure", err)
+	}
+	var result struct {
+		OK         bool             `json:"ok"`
+		Violations []guardViolation `json:"violations"`
+	}
+	if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+		t.Fatalf("invalid JSON: %v\n%s", jerr, out)
+	}
+	if result.OK || len(result.Violations) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	if v := result.Violations[0]; v.Kind != "console-error" || v.Text != "Uncaught TypeError (https://app.test/main.js:42)" {
+		t.Errorf("console violation = %+v", v)
+	}
+	if v := result.Violations[1]; v.Kind != "network-5xx" || v.Text != "503 POST https://app.test/api/save" {
+		t.Errorf("network violation = %+v", v)
+	}
+	if !strings.Contains(err.Error(), "2 violations in 0s: 1 console-error, 1 network-5xx") {
+		t.Errorf("summary = %q", err)
+	}
+}
+
+func TestRunGuard_Clean(t *testing.T) {
+	exec := guardExecutor(nil, []ipc.ConsoleEntry{{Seq: 1, Type: "warning", Text: "deprecated"}},
+		nil, []ipc.NetworkEntry{{Seq: 1, Status: 404, Method: "GET", URL: "https://app.test/favicon.ico"}})
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		captureStream(t, &os.Stderr, func() {
+			err = runGuard(newGuardTestCmd("console-error,network-5xx"), nil)
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "No violations in") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestGuardCheckNetwork_InFlight(t *testing.T) {
+	g := &guard{failOn: map[string]bool{guardNetworkFailed: true}, reported: map[uint64]bool{}}
+
+	pending := []ipc.NetworkEntry{{Seq: 1, Method: "GET", URL: "https://app.test/api"}}
+	if found := g.checkNetwork(pending); len(found) != 0 {
+		t.Fatalf("pending request reported: %+v", found)
+	}
+	failed := []ipc.NetworkEntry{{Seq: 1, Method: "GET", URL: "https://app.test/api", Failed: true, Error: "net::ERR_CONNECTION_REFUSED"}}
+	found := g.checkNetwork(failed)
+	if len(found) != 1 || found[0].Text != "GET https://app.test/api: net::ERR_CONNECTION_REFUSED" {
+		t.Fatalf("found = %+v", found)
+	}
+	if found := g.checkNetwork(failed); len(found) != 0 {
+		t.Errorf("failure reported twice: %+v", found)
+	}
+}
+
+func TestParseGuardKinds(t *testing.T) {
+	if _, err := parseGuardKinds([]string{"console-error", "network-500"}); err == nil {
+		t.Error("expected an unknown kind to fail")
+	}
+	if _, err := parseGuardKinds([]string{""}); err == nil {
+		t.Error("expected no kinds to fail")
+	}
+	got, err := parseGuardKinds([]string{"network-5xx", " console-warning"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kinds := sortedGuardKinds(got); strings.Join(kinds, ",") != "console-warning,network-5xx" {
+		t.Errorf("kinds = %v", kinds)
+	}
+}
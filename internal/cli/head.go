@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+const browserModeLong = `Relaunches the browser %s, on the same CDP port and profile, without
+stopping the daemon. Use it to watch or take over a headless session, or to
+hide a window once it is no longer needed.
+
+Carried over to the relaunched browser:
+  - cookies, including session cookies
+  - localStorage, IndexedDB, and anything else kept in the profile
+  - open tabs, reopened at their current URLs
+
+Lost in the relaunch: sessionStorage, in-page state such as form input and
+scroll position, and each tab's back/forward history. The console and network
+buffers are kept.
+
+Does nothing if the browser is already %s. A browser attached with
+'start --adopt' cannot be switched.`
+
+var headCmd = &cobra.Command{
+	Use:   "head",
+	Short: "Relaunch the browser with a visible window",
+	Long:  fmt.Sprintf(browserModeLong, "with a visible window", "headed"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBrowserMode("head", false)
+	},
+}
+
+var headlessCmd = &cobra.Command{
+	Use:   "headless",
+	Short: "Relaunch the browser without a window",
+	Long:  fmt.Sprintf(browserModeLong, "headless", "headless"),
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBrowserMode("headless", true)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(headCmd, headlessCmd)
+}
+
+func runBrowserMode(name string, headless bool) error {
+	t := startTimer(name)
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	debugParam("headless=%v", headless)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.BrowserModeParams{Headless: headless})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("browser-mode", fmt.Sprintf("headless=%v", headless))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "browser-mode",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.BrowserModeData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":       true,
+			"headless": data.Headless,
+			"changed":  data.Changed,
+			"tabs":     data.Tabs,
+			"cookies":  data.Cookies,
+		})
+	}
+
+	fmt.Println(formatBrowserMode(data))
+	return nil
+}
+
+// formatBrowserMode describes the result of a head or headless command.
+func formatBrowserMode(data ipc.BrowserModeData) string {
+	mode := "headed"
+	if data.Headless {
+		mode = "headless"
+	}
+	if !data.Changed {
+		return fmt.Sprintf("browser is already %s", mode)
+	}
+	return fmt.Sprintf("browser relaunched %s (tabs reopened: %d, cookies restored: %d)", mode, data.Tabs, data.Cookies)
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunBrowserMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		headless bool
+		data     ipc.BrowserModeData
+		want     string
+	}{
+		{"relaunched headless", true, ipc.BrowserModeData{Headless: true, Changed: true, Tabs: 2, Cookies: 14},
+			"browser relaunched headless (tabs reopened: 2, cookies restored: 14)"},
+		{"already headed", false, ipc.BrowserModeData{}, "browser is already headed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &mockExecutor{
+				executeFunc: func(req ipc.Request) (ipc.Response, error) {
+					var params ipc.BrowserModeParams
+					if req.Cmd != "browser-mode" || json.Unmarshal(req.Params, &params) != nil || params.Headless != tt.headless {
+						t.Errorf("unexpected request %s %s", req.Cmd, req.Params)
+					}
+					raw, _ := json.Marshal(tt.data)
+					return ipc.Response{OK: true, Data: raw}, nil
+				},
+			}
+			restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+			defer restore()
+
+			var err error
+			out := captureStream(t, &os.Stdout, func() {
+				err = runBrowserMode("test", tt.headless)
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.TrimSpace(out); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/executor"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunHeapLeakcheck(t *testing.T) {
+	resetFlags(t, heapLeakcheckCmd.Flags(), "cycles", "eval", "settle", "top")
+	for name, value := range map[string]string{"cycles": "3", "eval": "app.toggle()", "settle": "1s"} {
+		if err := heapLeakcheckCmd.Flags().Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := ipc.HeapParams{Action: "leakcheck", Cycles: 3, Expression: "app.toggle()", Settle: 1000, Top: 10}
+	exec := expectParamsExecutor(t, "heap", want, ipc.SuccessResponse(ipc.HeapLeakcheckData{
+		Action: "app.toggle()",
+		Samples: []ipc.HeapSample{
+			{Cycle: 1, UsedSize: 1000}, {Cycle: 2, UsedSize: 2000}, {Cycle: 3, UsedSize: 3000},
+		},
+		Growth:       2000,
+		Monotonic:    true,
+		Constructors: []ipc.HeapConstructorGrowth{{Name: "Listener", Count: 30, Size: 1200, CountDelta: 20, SizeDelta: 800}},
+	}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runHeapLeakcheck(heapLeakcheckCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"cycle 3  used 2.9KB", "over 2 cycles, every cycle: likely a leak", "+20  +800B  Listener"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunHeapLeakcheck_TooFewCycles(t *testing.T) {
+	resetFlags(t, heapLeakcheckCmd.Flags(), "cycles")
+	if err := heapLeakcheckCmd.Flags().Set("cycles", "1"); err != nil {
+		t.Fatal(err)
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})()
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runHeapLeakcheck(heapLeakcheckCmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid cycles 1") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestWriteHeapSnapshot(t *testing.T) {
+	// Streamed when the daemon streams the snapshot, inline otherwise
+	for _, stream := range []bool{true, false} {
+		exec := executor.NewDirectExecutor(func(req ipc.Request) ipc.Response {
+			if req.Cmd != "heap" {
+				t.Errorf("expected cmd=heap, got %s", req.Cmd)
+			}
+			if stream && req.Stream {
+				return ipc.StreamResponse(func(w io.Writer) error {
+					_, err := io.WriteString(w, `{"snapshot":{},"nodes":[1]}`)
+					return err
+				})
+			}
+			return ipc.SuccessResponse(ipc.HeapSnapshotData{Snapshot: json.RawMessage(`{"snapshot":{},"nodes":[2]}`)})
+		})
+
+		path := filepath.Join(t.TempDir(), "sub", "page.heapsnapshot")
+		resp, err := writeHeapSnapshot(exec, path)
+		if err != nil || !resp.OK {
+			t.Fatalf("stream=%v: writeHeapSnapshot() = %+v, %v", stream, resp, err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"snapshot":{},"nodes":[2]}`
+		if stream {
+			want = `{"snapshot":{},"nodes":[1]}`
+		}
+		if string(got) != want {
+			t.Errorf("stream=%v: file = %q, want %q", stream, got, want)
+		}
+	}
+}
+
+func TestWriteHeapSnapshot_FailureLeavesNoFile(t *testing.T) {
+	exec := executor.NewDirectExecutor(func(req ipc.Request) ipc.Response {
+		return ipc.StreamResponse(func(w io.Writer) error {
+			_, _ = io.WriteString(w, `{"snapshot":`)
+			return errors.New("target closed")
+		})
+	})
+	dir := t.TempDir()
+	resp, err := writeHeapSnapshot(exec, filepath.Join(dir, "page.heapsnapshot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.OK {
+		t.Fatal("expected a failed response")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("files left behind: %v", entries)
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var highlightCmd = &cobra.Command{
+	Use:   "highlight <selector>",
+	Short: "Highlight matching elements in the browser",
+	Long: `Draws DevTools-style overlay boxes around every element matching the CSS
+selector, so you can visually confirm what a selector matches before acting on it.
+
+The overlay uses the same colours as the Chrome element inspector: content
+(blue), padding (green), border (yellow), and margin (orange). It is removed
+after --duration; a duration of 0 keeps it until the next highlight.
+
+Only visible in a headful browser (webctl start without --headless).
+
+Flags:
+  --duration <d>    How long to show the overlay (default 3s, 0 = until replaced)
+
+Examples:
+  highlight "#submit"                   # Highlight for 3 seconds
+  highlight ".item" --duration 10s      # Highlight all matches for 10 seconds
+  highlight "nav a" --duration 0        # Keep until the next highlight
+
+Common patterns:
+  # Confirm a selector before clicking
+  highlight "form#login button"
+  click "form#login button"
+
+Response:
+  {"ok": true, "count": 3}
+
+Error cases:
+  - "selector '.missing' matched no elements" - nothing matches selector
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHighlight,
+}
+
+func init() {
+	highlightCmd.Flags().Duration("duration", 3*time.Second, "How long to show the overlay (0 = until replaced)")
+	rootCmd.AddCommand(highlightCmd)
+}
+
+func runHighlight(cmd *cobra.Command, args []string) error {
+	t := startTimer("highlight")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	selector := args[0]
+	duration, _ := cmd.Flags().GetDuration("duration")
+	if duration < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--duration must not be negative")
+	}
+	debugParam("selector=%q duration=%v", selector, duration)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.HighlightParams{
+		Selector: selector,
+		Duration: int(duration.Milliseconds()),
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("highlight", fmt.Sprintf("selector=%q duration=%v", selector, duration))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "highlight",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		if isNoElementsError(resp.Error) {
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputResponseError(resp)
+	}
+
+	var data ipc.HighlightData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	// JSON mode: include the match count
+	if JSONOutput {
+		result := map[string]any{
+			"ok":    true,
+			"count": data.Count,
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	// Text mode: just output OK
+	return outputSuccess(nil)
+}
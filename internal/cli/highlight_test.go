@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// setHighlightDuration sets --duration on highlightCmd and restores the
+// default after the test.
+func setHighlightDuration(t *testing.T, value string) {
+	t.Helper()
+	if err := highlightCmd.Flags().Set("duration", value); err != nil {
+		t.Fatalf("set duration=%s: %v", value, err)
+	}
+	t.Cleanup(func() {
+		_ = highlightCmd.Flags().Set("duration", (3 * time.Second).String())
+	})
+}
+
+func TestRunHighlight_DaemonNotRunning(t *testing.T) {
+	enableJSONOutput(t)
+	restore := setMockFactory(&mockFactory{daemonRunning: false})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runHighlight(highlightCmd, []string{"#submit"})
+	})
+	if err == nil {
+		t.Error("expected error when daemon not running")
+	}
+
+	var resp map[string]any
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("failed to parse error response: %v", err)
+	}
+	if resp["ok"] != false {
+		t.Error("expected ok=false in error response")
+	}
+}
+
+func TestRunHighlight_Success(t *testing.T) {
+	enableJSONOutput(t)
+	setHighlightDuration(t, "10s")
+
+	var captured ipc.HighlightParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "highlight" {
+				t.Errorf("expected cmd=highlight, got %s", req.Cmd)
+			}
+			_ = json.Unmarshal(req.Params, &captured)
+			data, _ := json.Marshal(ipc.HighlightData{Count: 3})
+			return ipc.Response{OK: true, Data: data}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runHighlight(highlightCmd, []string{".item"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if captured.Selector != ".item" {
+		t.Errorf("expected selector=.item, got %q", captured.Selector)
+	}
+	if captured.Duration != 10000 {
+		t.Errorf("expected duration=10000ms, got %d", captured.Duration)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if result["ok"] != true {
+		t.Error("expected ok=true")
+	}
+	if result["count"] != float64(3) {
+		t.Errorf("expected count=3, got %v", result["count"])
+	}
+}
+
+func TestRunHighlight_NegativeDuration(t *testing.T) {
+	enableJSONOutput(t)
+	setHighlightDuration(t, "-1s")
+
+	called := false
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			called = true
+			return ipc.Response{OK: true}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runHighlight(highlightCmd, []string{".item"})
+	})
+	if err == nil {
+		t.Error("expected error for negative duration")
+	}
+	if called {
+		t.Error("expected no IPC request for invalid duration")
+	}
+}
+
+func TestRunHighlight_ElementNotFound(t *testing.T) {
+	enableJSONOutput(t)
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			return ipc.Response{OK: false, Error: "selector '#missing' matched no elements"}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runHighlight(highlightCmd, []string{"#missing"})
+	})
+	if err == nil {
+		t.Error("expected error for missing element")
+	}
+
+	var resp map[string]any
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("failed to parse error response: %v", err)
+	}
+	if resp["message"] != "No elements found" {
+		t.Errorf("unexpected message: %v", resp["message"])
+	}
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List navigation history or jump to an entry",
+	Long: `Lists the active tab's back/forward navigation history with indices. The
+current entry is marked with "*".
+
+Subcommands:
+  go <index>        Navigate directly to a history entry
+
+Flags:
+  --limit <n>       Show only the n most recent entries (default: all)
+
+Examples:
+  history                               # All entries
+  history --limit 5                     # Last five entries
+  history go 0                          # Back to the first page
+  history go 3 --wait                   # Jump and wait for the page
+
+Text output:
+    0  https://example.com/ - Example Domain
+  * 1  https://example.com/about - About
+    2  https://example.com/contact - Contact
+
+JSON output:
+  {"ok": true, "currentIndex": 1, "entries": [{"index": 0, "url": "...", "title": "..."}]}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+var historyGoCmd = &cobra.Command{
+	Use:   "go <index>",
+	Short: "Navigate to a history entry by index",
+	Long: `Navigates directly to the history entry with the given index (as printed by
+webctl history), like pressing back or forward several times at once. Returns
+immediately unless --wait is specified.
+
+Examples:
+  history go 0
+  history go 2 --wait --timeout 30
+
+Error cases:
+  - "history index 9 out of range (0-4)" - no entry with that index
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistoryGo,
+}
+
+func init() {
+	historyCmd.Flags().Int("limit", 0, "Show only the N most recent entries (0 = all)")
+	historyGoCmd.Flags().Bool("wait", false, "Wait for page load completion")
+	historyGoCmd.Flags().Int("timeout", 60, "Timeout in seconds (used with --wait)")
+	historyCmd.AddCommand(historyGoCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	t := startTimer("history")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--limit must be zero or more")
+	}
+	debugParam("limit=%d", limit)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.HistoryParams{
+		Action: "list",
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("history", "action=list")
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "history",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.HistoryData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	// Limit keeps the most recent entries; indices stay as Chrome reports them
+	entries := data.Entries
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	if JSONOutput {
+		if entries == nil {
+			entries = []ipc.HistoryEntry{}
+		}
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":           true,
+			"currentIndex": data.CurrentIndex,
+			"entries":      entries,
+		})
+	}
+
+	return format.History(os.Stdout, entries, format.NewOutputOptions(JSONOutput, NoColor))
+}
+
+func runHistoryGo(cmd *cobra.Command, args []string) error {
+	t := startTimer("history go")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	index, err := strconv.Atoi(args[0])
+	if err != nil || index < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid history index %q: must be a non-negative integer", args[0]))
+	}
+	wait, _ := cmd.Flags().GetBool("wait")
+	timeout, _ := cmd.Flags().GetInt("timeout")
+	debugParam("index=%d wait=%v timeout=%d", index, wait, timeout)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.HistoryParams{
+		Action:  "go",
+		Index:   index,
+		Wait:    wait,
+		Timeout: timeout,
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("history", fmt.Sprintf("action=go index=%d wait=%v timeout=%d", index, wait, timeout))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "history",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	// JSON mode: include URL and title
+	if JSONOutput {
+		var data ipc.NavigateData
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			return outputError(err.Error())
+		}
+
+		result := map[string]any{
+			"ok":    true,
+			"url":   data.URL,
+			"title": data.Title,
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	// Text mode: just output OK
+	return outputSuccess(nil)
+}
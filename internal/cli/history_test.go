@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunHistory_Limit(t *testing.T) {
+	enableJSONOutput(t)
+	if err := historyCmd.Flags().Set("limit", "2"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = historyCmd.Flags().Set("limit", "0")
+		historyCmd.Flags().Lookup("limit").Changed = false
+	})
+
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			var params ipc.HistoryParams
+			_ = json.Unmarshal(req.Params, &params)
+			if req.Cmd != "history" || params.Action != "list" {
+				t.Errorf("expected history list, got %s %+v", req.Cmd, params)
+			}
+			raw, _ := json.Marshal(ipc.HistoryData{CurrentIndex: 1, Entries: []ipc.HistoryEntry{
+				{Index: 0, URL: "https://a.example/"},
+				{Index: 1, URL: "https://b.example/", Current: true},
+				{Index: 2, URL: "https://c.example/"},
+			}})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runHistory(historyCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		CurrentIndex int                `json:"currentIndex"`
+		Entries      []ipc.HistoryEntry `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if result.CurrentIndex != 1 || len(result.Entries) != 2 || result.Entries[0].Index != 1 {
+		t.Errorf("expected the last two entries with original indices, got %s", out)
+	}
+}
+
+func TestRunHistoryGo(t *testing.T) {
+	var captured ipc.HistoryParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			_ = json.Unmarshal(req.Params, &captured)
+			raw, _ := json.Marshal(ipc.NavigateData{URL: "https://a.example/"})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		err = runHistoryGo(historyGoCmd, []string{"3"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.Action != "go" || captured.Index != 3 {
+		t.Errorf("expected go to index 3, got %+v", captured)
+	}
+}
+
+func TestRunHistoryGo_InvalidIndex(t *testing.T) {
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runHistoryGo(historyGoCmd, []string{"-1"})
+	})
+	if err == nil {
+		t.Error("expected error for negative index")
+	}
+	if !strings.Contains(out, "invalid history index") {
+		t.Errorf("unexpected error output: %q", out)
+	}
+}
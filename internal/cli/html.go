@@ -4,14 +4,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/grantcarthew/webctl/internal/cli/format"
 	"github.com/grantcarthew/webctl/internal/htmlformat"
 	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/grantcarthew/webctl/internal/linediff"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +29,8 @@ Default behavior (no subcommand):
 
 Subcommands:
   save [path]       Save HTML to file (temp dir if no path given)
+  diff <file|url>   Diff current HTML against a reference capture
+  path <selector>   Show matched elements inside their ancestor chain
 
 Universal flags (work with all modes):
   --select, -s      Filter to element(s) matching CSS selector
@@ -32,18 +38,32 @@ Universal flags (work with all modes):
   --raw             Skip HTML formatting (return as-is from browser)
   --json            Output in JSON format (global flag)
 
+Formatting flags (ignored with --raw):
+  --indent <n|tab>      Spaces per level, or "tab" (default 2)
+  --max-line-width <n>  Wrap text and put attributes on their own lines
+                        past this width (default 0, no wrapping)
+  --compact-text        Keep text-only elements on one line: <p>Text</p>
+  --void-style <style>  Void tags: keep (as written), html (<br>), or
+                        xhtml (<br />) (default keep)
+
 Examples:
 
 Default mode (stdout):
   html                                  # Full page to stdout
   html --select "#main"                 # Element to stdout
   html --find "login"                   # Search and show matches
+  html --indent 4 --compact-text        # Match a prettier-style layout
 
 Save mode (file):
   html save                             # Save to temp with auto-filename
   html save ./page.html                 # Save to custom file
   html save ./output/                   # Save to dir (auto-filename)
   html save --select "form" --find "password"
+  html save --complete                  # Single-file MHTML with inlined resources
+
+Diff mode (golden files):
+  html diff ./golden/home.html          # Exit 1 if the page differs
+  html diff https://example.com/        # Against the server-rendered HTML
 
 Response formats:
   Default:  <html>...</html> (to stdout)
@@ -66,15 +86,110 @@ Path conventions:
   ./page.html       Save to exact file path
   ./output/         Save to directory with auto-generated filename (trailing slash required)
 
+Complete mode (--complete):
+  Captures the page with Page.captureSnapshot as a single MHTML file with
+  stylesheets, images, fonts, and frames inlined, so it renders offline in
+  Chrome or Edge. The default save is DOM-only and loads resources from the
+  network when opened. Auto-generated filenames use the .mhtml extension.
+  Cannot be combined with --select, --find, or --raw.
+
 Examples:
   html save                             # Save to temp dir
   html save ./page.html                 # Save to file
   html save ./output/                   # Save to dir (creates if needed)
-  html save --select "#app" --find "error"`,
+  html save --select "#app" --find "error"
+  html save --complete                  # /tmp/webctl-html/...-example.mhtml
+  html save --complete ./page.mhtml     # Self-contained snapshot to file`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runHTMLSave,
 }
 
+var htmlDiffCmd = &cobra.Command{
+	Use:   "diff <file|url>",
+	Short: "Diff current HTML against a reference capture",
+	Long: `Diffs the current page HTML against a reference and prints a unified diff.
+Both sides are formatted with the same HTML formatter first (unless --raw), so
+the diff shows structural and text changes rather than whitespace noise.
+
+The reference is a file (typically from html save) or an http(s) URL, which is
+fetched directly and so reflects the server-rendered HTML, not the live DOM.
+
+Like diff(1), the exit code is 0 when identical and 1 when they differ, which
+makes html diff usable for golden-file regression checks in CI.
+
+Flags:
+  --unified, -U <n> Lines of context around each change (default 3)
+  --select, -s      Diff only the matching element(s); compare with a
+                    reference saved by html save --select
+  --raw             Diff unformatted HTML
+
+Examples:
+  html save ./golden/home.html          # Record the golden file once
+  html diff ./golden/home.html          # Later: compare
+  html diff ./golden/nav.html --select "nav"
+  html diff https://staging.example.com/ -U 1
+
+Output:
+  --- ./golden/home.html
+  +++ (current page)
+  @@ -12,7 +12,7 @@
+       <h1>
+  -      Welcome
+  +      Welcome back
+       </h1>
+
+JSON output:
+  {"ok": false, "identical": false, "diff": "--- ...\n+++ ...\n@@ ..."}
+
+Error cases:
+  - "failed to read reference" - file missing or unreadable
+  - "failed to fetch reference" - URL unreachable or non-2xx status
+  - "--find cannot be used with diff" - filter the diff output instead`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHTMLDiff,
+}
+
+var htmlPathCmd = &cobra.Command{
+	Use:   "path <selector>",
+	Short: "Show matched elements inside their ancestor chain",
+	Long: `Outputs each element matching the selector wrapped in its ancestors, from
+<html> down, with every ancestor's other children collapsed into a comment
+counting them. This shows where an element sits in the document, and the
+ids and classes above it, without dumping the whole page: the context needed
+to write a tighter selector.
+
+The matched element itself is shown in full. Formatting flags and --find work
+as for html.
+
+Examples:
+  html path "li.active"
+  html path "button[type=submit]" --raw
+  html path ".price" --find "data-sku"
+
+Output:
+  <html lang="en">
+    <!-- 1 sibling -->
+    <body>
+      <main id="app">
+        <ul class="items">
+          <!-- 1 sibling -->
+          <li class="item active">
+            Beta
+          </li>
+          <!-- 3 siblings -->
+        </ul>
+      </main>
+      <!-- 1 sibling -->
+    </body>
+  </html>
+
+Error cases:
+  - "No elements found" - nothing matches the selector
+  - "--select cannot be used with path" - give the selector as the argument`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHTMLPath,
+}
+
 func init() {
 	// Universal flags on root command (inherited by subcommands)
 	htmlCmd.PersistentFlags().StringP("select", "s", "", "Filter to element(s) matching CSS selector")
@@ -83,9 +198,17 @@ func init() {
 	htmlCmd.PersistentFlags().IntP("after", "A", 0, "Show N lines after each match (requires --find)")
 	htmlCmd.PersistentFlags().IntP("context", "C", 0, "Show N lines before and after each match (requires --find)")
 	htmlCmd.PersistentFlags().Bool("raw", false, "Skip HTML formatting")
+	htmlCmd.PersistentFlags().String("indent", "2", `Indentation per level: number of spaces or "tab"`)
+	htmlCmd.PersistentFlags().Int("max-line-width", 0, "Wrap lines longer than this (0 = no wrapping)")
+	htmlCmd.PersistentFlags().Bool("compact-text", false, "Keep short text on the tag line")
+	htmlCmd.PersistentFlags().String("void-style", "keep", "Void element style: keep, html, or xhtml")
+
+	htmlSaveCmd.Flags().Bool("complete", false, "Save a single-file MHTML snapshot with inlined resources")
+
+	htmlDiffCmd.Flags().IntP("unified", "U", 3, "Lines of context around each change")
 
 	// Add subcommands
-	htmlCmd.AddCommand(htmlSaveCmd)
+	htmlCmd.AddCommand(htmlSaveCmd, htmlDiffCmd, htmlPathCmd)
 
 	rootCmd.AddCommand(htmlCmd)
 }
@@ -97,11 +220,11 @@ func runHTMLDefault(cmd *cobra.Command, args []string) error {
 
 	// Validate that no arguments were provided (catches unknown subcommands)
 	if len(args) > 0 {
-		return outputError(fmt.Sprintf("unknown command %q for \"webctl html\"", args[0]))
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("unknown command %q for \"webctl html\"", args[0]))
 	}
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// For JSON mode, get raw response data to access HTMLMulti
@@ -112,7 +235,7 @@ func runHTMLDefault(cmd *cobra.Command, args []string) error {
 				return outputNotice("No matches found")
 			}
 			if errors.Is(err, ErrNoElements) {
-				return outputNotice("No elements found")
+				return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 			}
 			return outputError(err.Error())
 		}
@@ -139,7 +262,7 @@ func runHTMLDefault(cmd *cobra.Command, args []string) error {
 			return outputNotice("No matches found")
 		}
 		if errors.Is(err, ErrNoElements) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 		}
 		return outputError(err.Error())
 	}
@@ -149,8 +272,59 @@ func runHTMLDefault(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runHTMLPath handles path subcommand: matches wrapped in their ancestors
+func runHTMLPath(cmd *cobra.Command, args []string) error {
+	t := startTimer("html path")
+	defer t.log()
+
+	if htmlFlagSet(cmd, "select") {
+		return outputCodedError(ipc.CodeInvalidArgs, "--select cannot be used with path: give the selector as the argument")
+	}
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	html, data, err := fetchHTMLData(cmd, ipc.HTMLParams{Selector: args[0], Path: true})
+	if err != nil {
+		if errors.Is(err, ErrNoMatches) {
+			return outputNotice("No matches found")
+		}
+		if errors.Is(err, ErrNoElements) {
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":       true,
+			"elements": data.HTMLMulti,
+		})
+	}
+	fmt.Println(html)
+	return nil
+}
+
 // runHTMLSave handles save subcommand: save to file
 func runHTMLSave(cmd *cobra.Command, args []string) error {
+	if complete, _ := cmd.Flags().GetBool("complete"); complete {
+		if saveSelectorFlag(cmd) != "" || htmlFlagSet(cmd, "find") || htmlFlagSet(cmd, "raw") {
+			return outputCodedError(ipc.CodeInvalidArgs, "--complete cannot be combined with --select, --find, or --raw")
+		}
+		for _, name := range []string{"indent", "max-line-width", "compact-text", "void-style"} {
+			if htmlFlagSet(cmd, name) {
+				return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("--complete cannot be combined with --%s", name))
+			}
+		}
+		return runSave(cmd, args, saveSpec{
+			timerLabel: "html save --complete",
+			tempDir:    "/tmp/webctl-html",
+			ext:        "mhtml",
+			produce:    getMHTMLFromDaemon,
+			identifier: selectorOrTitleIdentifier,
+		})
+	}
+
 	return runSave(cmd, args, saveSpec{
 		timerLabel: "html save",
 		tempDir:    "/tmp/webctl-html",
@@ -160,14 +334,230 @@ func runHTMLSave(cmd *cobra.Command, args []string) error {
 	})
 }
 
+// runHTMLDiff handles diff subcommand: compare current HTML with a reference
+func runHTMLDiff(cmd *cobra.Command, args []string) error {
+	t := startTimer("html diff")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	if htmlFlagSet(cmd, "find") {
+		return outputCodedError(ipc.CodeInvalidArgs, "--find cannot be used with diff")
+	}
+
+	contextLines, _ := cmd.Flags().GetInt("unified")
+	if contextLines < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--unified must be zero or more")
+	}
+
+	raw, _ := cmd.Flags().GetBool("raw")
+	if !raw && cmd.Parent() != nil {
+		raw, _ = cmd.Parent().PersistentFlags().GetBool("raw")
+	}
+
+	formatOpts, err := htmlFormatOptions(cmd)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	source := args[0]
+	reference, err := readHTMLReference(source)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !raw {
+		if formatted, err := htmlformat.FormatWithOptions(reference, formatOpts); err == nil {
+			reference = formatted
+		} else {
+			debugf("FORMAT", "reference HTML formatting failed: %v", err)
+		}
+	}
+
+	current, err := getHTMLFromDaemon(cmd)
+	if err != nil {
+		if errors.Is(err, ErrNoElements) {
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputError(err.Error())
+	}
+
+	diff := linediff.Unified(source, "(current page)",
+		splitHTMLLines(reference), splitHTMLLines(current), contextLines)
+
+	if JSONOutput {
+		if err := outputJSON(os.Stdout, map[string]any{
+			"ok":        diff == "",
+			"identical": diff == "",
+			"diff":      diff,
+		}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(diff)
+	}
+
+	if diff != "" {
+		return printedError{err: errors.New("html differs from reference")}
+	}
+	return nil
+}
+
+// readHTMLReference loads reference HTML from a file, or fetches it when the
+// source is an http(s) URL.
+func readHTMLReference(source string) (string, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return "", fmt.Errorf("failed to read reference: %v", err)
+		}
+		return string(content), nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(source)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch reference: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("failed to fetch reference: %s returned %s", source, resp.Status)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch reference: %v", err)
+	}
+	return string(content), nil
+}
+
+// splitHTMLLines splits HTML into lines for diffing, ignoring a trailing newline
+// so files saved with or without one compare equal.
+func splitHTMLLines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// htmlFlagSet reports whether a flag was given on the save subcommand or as the
+// parent's persistent flag.
+func htmlFlagSet(cmd *cobra.Command, name string) bool {
+	if cmd.Flags().Changed(name) {
+		return true
+	}
+	return cmd.Parent() != nil && cmd.Parent().PersistentFlags().Changed(name)
+}
+
+// htmlFormatOptions builds formatter options from the html formatting flags,
+// which may be set on the html command or inherited by a subcommand.
+func htmlFormatOptions(cmd *cobra.Command) (htmlformat.Options, error) {
+	opts := htmlformat.DefaultOptions()
+
+	lookup := func(name string) string {
+		for c := cmd; c != nil; c = c.Parent() {
+			if f := c.Flags().Lookup(name); f != nil && f.Changed {
+				return f.Value.String()
+			}
+			if f := c.PersistentFlags().Lookup(name); f != nil && f.Changed {
+				return f.Value.String()
+			}
+		}
+		return ""
+	}
+
+	if indent := lookup("indent"); indent != "" {
+		if indent == "tab" {
+			opts.Indent = "\t"
+		} else {
+			n, err := strconv.Atoi(indent)
+			if err != nil || n < 0 || n > 16 {
+				return opts, fmt.Errorf(`--indent must be a number from 0 to 16 or "tab", got %q`, indent)
+			}
+			opts.Indent = strings.Repeat(" ", n)
+		}
+	}
+
+	if width := lookup("max-line-width"); width != "" {
+		n, err := strconv.Atoi(width)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("--max-line-width must be zero or more, got %q", width)
+		}
+		opts.MaxLineWidth = n
+	}
+
+	opts.CompactText = lookup("compact-text") == "true"
+
+	switch style := lookup("void-style"); style {
+	case "", "keep":
+		opts.VoidStyle = htmlformat.VoidKeep
+	case "html":
+		opts.VoidStyle = htmlformat.VoidHTML
+	case "xhtml":
+		opts.VoidStyle = htmlformat.VoidXHTML
+	default:
+		return opts, fmt.Errorf("--void-style must be keep, html, or xhtml, got %q", style)
+	}
+
+	return opts, nil
+}
+
+// getMHTMLFromDaemon fetches a complete page snapshot in MHTML format.
+func getMHTMLFromDaemon(cmd *cobra.Command) (string, error) {
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.HTMLParams{
+		Complete: true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	debugRequest("html", "complete=true")
+	ipcStart := time.Now()
+
+	resp, err := executeStreamData(exec, ipc.Request{
+		Cmd:    "html",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return "", err
+	}
+	if !resp.OK {
+		return "", fmt.Errorf("%s", resp.Error)
+	}
+
+	var data ipc.HTMLData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return "", err
+	}
+	return data.MHTML, nil
+}
+
 // getHTMLDataFromDaemon fetches HTML from daemon and returns both formatted string and raw data
 func getHTMLDataFromDaemon(cmd *cobra.Command) (string, ipc.HTMLData, error) {
-	// Get flags
 	selector, _ := cmd.Flags().GetString("select")
 	if selector == "" && cmd.Parent() != nil {
 		selector, _ = cmd.Parent().PersistentFlags().GetString("select")
 	}
+	return fetchHTMLData(cmd, ipc.HTMLParams{Selector: selector})
+}
+
+// fetchHTMLData requests HTML with params and applies the formatting and
+// --find flags.
+func fetchHTMLData(cmd *cobra.Command, params ipc.HTMLParams) (string, ipc.HTMLData, error) {
+	selector := params.Selector
 
+	// Get flags
 	find, _ := cmd.Flags().GetString("find")
 	if find == "" && cmd.Parent() != nil {
 		find, _ = cmd.Parent().PersistentFlags().GetString("find")
@@ -201,27 +591,29 @@ func getHTMLDataFromDaemon(cmd *cobra.Command) (string, ipc.HTMLData, error) {
 
 	debugParam("selector=%q find=%q raw=%v before=%d after=%d", selector, find, raw, before, after)
 
+	formatOpts, err := htmlFormatOptions(cmd)
+	if err != nil {
+		return "", ipc.HTMLData{}, err
+	}
+
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
 		return "", ipc.HTMLData{}, err
 	}
 	defer func() { _ = exec.Close() }()
 
-	// Build request with selector
-	params, err := json.Marshal(ipc.HTMLParams{
-		Selector: selector,
-	})
+	reqParams, err := json.Marshal(params)
 	if err != nil {
 		return "", ipc.HTMLData{}, err
 	}
 
-	debugRequest("html", fmt.Sprintf("selector=%q", selector))
+	debugRequest("html", fmt.Sprintf("selector=%q path=%v", selector, params.Path))
 	ipcStart := time.Now()
 
 	// Execute HTML request
-	resp, err := exec.Execute(ipc.Request{
+	resp, err := executeStreamData(exec, ipc.Request{
 		Cmd:    "html",
-		Params: params,
+		Params: reqParams,
 	})
 
 	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
@@ -257,7 +649,7 @@ func getHTMLDataFromDaemon(cmd *cobra.Command) (string, ipc.HTMLData, error) {
 			// Add HTML content (formatted unless --raw)
 			elemHTML := elem.HTML
 			if !raw {
-				formatted, err := htmlformat.Format(elemHTML)
+				formatted, err := htmlformat.FormatWithOptions(elemHTML, formatOpts)
 				if err != nil {
 					debugf("FORMAT", "HTML formatting failed for element %d: %v", i, err)
 				} else {
@@ -272,7 +664,7 @@ func getHTMLDataFromDaemon(cmd *cobra.Command) (string, ipc.HTMLData, error) {
 		html = data.HTML
 		// Format HTML unless --raw flag is set
 		if !raw {
-			formatted, err := htmlformat.Format(html)
+			formatted, err := htmlformat.FormatWithOptions(html, formatOpts)
 			if err != nil {
 				// If formatting fails, fall back to raw HTML
 				debugf("FORMAT", "HTML formatting failed: %v", err)
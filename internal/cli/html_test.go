@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/htmlformat"
 	"github.com/grantcarthew/webctl/internal/ipc"
 )
 
@@ -205,3 +210,263 @@ func TestSanitizeSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestRunHTMLSave_Complete(t *testing.T) {
+	enableJSONOutput(t)
+	if err := htmlSaveCmd.Flags().Set("complete", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = htmlSaveCmd.Flags().Set("complete", "false")
+		htmlSaveCmd.Flags().Lookup("complete").Changed = false
+	})
+
+	const mhtml = "From: <Saved by Blink>\r\nContent-Type: multipart/related;\r\n"
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd == "status" {
+				return ipc.Response{OK: false}, nil
+			}
+			var params ipc.HTMLParams
+			_ = json.Unmarshal(req.Params, &params)
+			if req.Cmd != "html" || !params.Complete {
+				t.Errorf("expected html complete request, got %s %+v", req.Cmd, params)
+			}
+			raw, _ := json.Marshal(ipc.HTMLData{MHTML: mhtml})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	dir := t.TempDir() + "/"
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runHTMLSave(htmlSaveCmd, []string{dir})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !strings.HasSuffix(result.Path, ".mhtml") {
+		t.Errorf("expected .mhtml filename, got %q", result.Path)
+	}
+	got, err := os.ReadFile(result.Path)
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+	if string(got) != mhtml {
+		t.Errorf("snapshot content = %q, want %q", got, mhtml)
+	}
+}
+
+func htmlDiffExecutor(page string) *mockExecutor {
+	return &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			raw, _ := json.Marshal(ipc.HTMLData{HTML: page})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+}
+
+func TestRunHTMLDiff(t *testing.T) {
+	const page = `<html><head><title>Home</title></head><body><h1>Welcome back</h1><p>Hi</p></body></html>`
+
+	tests := []struct {
+		name      string
+		reference string
+		wantDiff  bool
+	}{
+		{
+			name:      "identical after formatting",
+			reference: "<html>\n<head><title>Home</title></head>\n<body>\n  <h1>Welcome back</h1>\n  <p>Hi</p>\n</body>\n</html>\n",
+		},
+		{
+			name:      "text change",
+			reference: `<html><head><title>Home</title></head><body><h1>Welcome</h1><p>Hi</p></body></html>`,
+			wantDiff:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enableJSONOutput(t)
+			refPath := filepath.Join(t.TempDir(), "golden.html")
+			if err := os.WriteFile(refPath, []byte(tt.reference), 0644); err != nil {
+				t.Fatal(err)
+			}
+			restore := setMockFactory(&mockFactory{daemonRunning: true, executor: htmlDiffExecutor(page)})
+			defer restore()
+
+			var err error
+			out := captureStream(t, &os.Stdout, func() {
+				err = runHTMLDiff(htmlDiffCmd, []string{refPath})
+			})
+
+			var result struct {
+				OK        bool   `json:"ok"`
+				Identical bool   `json:"identical"`
+				Diff      string `json:"diff"`
+			}
+			if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+				t.Fatalf("failed to parse response: %v", jerr)
+			}
+
+			if !tt.wantDiff {
+				if err != nil || !result.Identical {
+					t.Errorf("expected identical, err=%v diff=%q", err, result.Diff)
+				}
+				return
+			}
+			if err == nil {
+				t.Error("expected non-nil error (exit 1) when HTML differs")
+			}
+			if result.OK || result.Identical {
+				t.Errorf("expected ok=false identical=false, got %+v", result)
+			}
+			if !strings.Contains(result.Diff, "-      Welcome\n+      Welcome back\n") {
+				t.Errorf("unexpected diff:\n%s", result.Diff)
+			}
+		})
+	}
+}
+
+func TestRunHTMLDiff_MissingReference(t *testing.T) {
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: htmlDiffExecutor("<p>x</p>")})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runHTMLDiff(htmlDiffCmd, []string{filepath.Join(t.TempDir(), "missing.html")})
+	})
+	if err == nil || !strings.Contains(out, "failed to read reference") {
+		t.Errorf("expected read error, got err=%v out=%q", err, out)
+	}
+}
+
+func TestHTMLFormatOptions(t *testing.T) {
+	setHTMLFlag := func(t *testing.T, name, value string) {
+		t.Helper()
+		if err := htmlCmd.PersistentFlags().Set(name, value); err != nil {
+			t.Fatalf("set %s: %v", name, err)
+		}
+		t.Cleanup(func() {
+			f := htmlCmd.PersistentFlags().Lookup(name)
+			_ = f.Value.Set(f.DefValue)
+			f.Changed = false
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		opts, err := htmlFormatOptions(htmlSaveCmd)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if opts != htmlformat.DefaultOptions() {
+			t.Errorf("opts = %+v, want defaults", opts)
+		}
+	})
+
+	t.Run("inherited by subcommand", func(t *testing.T) {
+		setHTMLFlag(t, "indent", "tab")
+		setHTMLFlag(t, "max-line-width", "100")
+		setHTMLFlag(t, "compact-text", "true")
+		setHTMLFlag(t, "void-style", "xhtml")
+
+		opts, err := htmlFormatOptions(htmlSaveCmd)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := htmlformat.Options{Indent: "\t", MaxLineWidth: 100, CompactText: true, VoidStyle: htmlformat.VoidXHTML}
+		if opts != want {
+			t.Errorf("opts = %+v, want %+v", opts, want)
+		}
+	})
+
+	for _, tt := range []struct{ flag, value, wantErr string }{
+		{"indent", "wide", "--indent must be"},
+		{"void-style", "xml", "--void-style must be"},
+		{"max-line-width", "-1", "--max-line-width must be"},
+	} {
+		t.Run("invalid "+tt.flag, func(t *testing.T) {
+			setHTMLFlag(t, tt.flag, tt.value)
+			_, err := htmlFormatOptions(htmlCmd)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// resetHTMLSelectFlag clears --select, which other tests leave set on the
+// shared html command.
+func resetHTMLSelectFlag(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		_ = htmlCmd.PersistentFlags().Set("select", "")
+		htmlCmd.PersistentFlags().Lookup("select").Changed = false
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestRunHTMLPath(t *testing.T) {
+	resetHTMLSelectFlag(t)
+	const path = `<html lang="en"><!-- 1 sibling --><body><ul class="items"><!-- 1 sibling --><li class="active">Beta</li></ul></body></html>`
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			var params ipc.HTMLParams
+			_ = json.Unmarshal(req.Params, &params)
+			if req.Cmd != "html" || params.Selector != "li.active" || !params.Path {
+				t.Errorf("expected html path request, got %s %+v", req.Cmd, params)
+			}
+			raw, _ := json.Marshal(ipc.HTMLData{
+				HTML:      path,
+				HTMLMulti: []ipc.ElementWithHTML{{ElementMeta: ipc.ElementMeta{Tag: "li", Class: "active"}, HTML: path}},
+			})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runHTMLPath(htmlPathCmd, []string{"li.active"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`<html lang="en">`,
+		"\n  <!-- 1 sibling -->\n  <body>\n",
+		"\n    <ul class=\"items\">\n      <!-- 1 sibling -->\n      <li class=\"active\">\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunHTMLPath_RejectsSelectFlag(t *testing.T) {
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: htmlDiffExecutor("<p>x</p>")})
+	defer restore()
+	resetHTMLSelectFlag(t)
+	if err := htmlCmd.PersistentFlags().Set("select", "main"); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runHTMLPath(htmlPathCmd, []string{"li"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "--select cannot be used with path") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/grantcarthew/webctl/internal/ipc"
@@ -11,9 +12,9 @@ import (
 )
 
 var keyCmd = &cobra.Command{
-	Use:   "key <key>",
-	Short: "Send a keyboard key",
-	Long: `Sends a keyboard key to the focused element.
+	Use:   "key <key|sequence>",
+	Short: "Send a keyboard key or key sequence",
+	Long: `Sends a keyboard key, or a sequence of keys, to the focused element.
 
 Supported special keys:
   Navigation:    Enter, Tab, Escape, Space
@@ -22,6 +23,7 @@ Supported special keys:
   Page:          Home, End, PageUp, PageDown
 
 Single character keys (a-z, A-Z, 0-9, punctuation) can be used directly.
+Pressed without modifiers, a character is typed into the focused element.
 
 Modifier flags (can be combined):
   --ctrl   Hold Ctrl modifier (Linux)
@@ -29,6 +31,15 @@ Modifier flags (can be combined):
   --alt    Hold Alt/Option modifier
   --shift  Hold Shift modifier
 
+Sequences and chords:
+  Separate keys with spaces to press them in order. Join modifiers to a key
+  with + to press them together: ctrl, alt (or option), shift, and meta (or
+  cmd). Modifier flags apply to every key in the sequence.
+
+  --repeat <n>     Press the key or whole sequence n times (default 1)
+  --delay <d>      Pause between presses (default none), for UIs that
+                   animate or load between keys
+
 Examples:
   # Basic keys
   key Enter                    # Submit form / confirm
@@ -63,6 +74,13 @@ Examples:
   key PageDown                 # Scroll down one page
   key PageUp                   # Scroll up one page
 
+  # Sequences
+  key "ctrl+k p Enter"         # Open a command palette, type p, run it
+  key "g i"                    # Two-key shortcut (Gmail-style)
+  key "ctrl+shift+p"           # Chord with two modifiers
+  key ArrowDown --repeat 5     # Move down five items
+  key "Tab Tab Enter" --delay 100ms
+
   # Browser shortcuts
   key l --ctrl                 # Focus address bar (Linux)
   key l --meta                 # Focus address bar (macOS)
@@ -77,6 +95,8 @@ func init() {
 	keyCmd.Flags().Bool("alt", false, "Hold Alt modifier")
 	keyCmd.Flags().Bool("shift", false, "Hold Shift modifier")
 	keyCmd.Flags().Bool("meta", false, "Hold Meta/Command modifier")
+	keyCmd.Flags().Int("repeat", 1, "Press the key or sequence this many times")
+	keyCmd.Flags().Duration("delay", 0, "Pause between key presses")
 	rootCmd.AddCommand(keyCmd)
 }
 
@@ -85,7 +105,7 @@ func runKey(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Read flags from command
@@ -93,28 +113,57 @@ func runKey(cmd *cobra.Command, args []string) error {
 	alt, _ := cmd.Flags().GetBool("alt")
 	shift, _ := cmd.Flags().GetBool("shift")
 	meta, _ := cmd.Flags().GetBool("meta")
+	repeat, _ := cmd.Flags().GetInt("repeat")
+	delay, _ := cmd.Flags().GetDuration("delay")
 	key := args[0]
 
-	debugParam("key=%q ctrl=%v alt=%v shift=%v meta=%v", key, ctrl, alt, shift, meta)
-
-	exec, err := execFactory.NewExecutor()
-	if err != nil {
-		return outputError(err.Error())
+	if repeat < 1 || repeat > maxKeyRepeat {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("--repeat must be 1 to %d", maxKeyRepeat))
+	}
+	if delay < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--delay must not be negative")
 	}
-	defer func() { _ = exec.Close() }()
 
-	params, err := json.Marshal(ipc.KeyParams{
+	keyParams := ipc.KeyParams{
 		Key:   key,
 		Ctrl:  ctrl,
 		Alt:   alt,
 		Shift: shift,
 		Meta:  meta,
-	})
+		Delay: int(delay.Milliseconds()),
+	}
+	if repeat > 1 {
+		keyParams.Repeat = repeat
+	}
+	// Anything but a lone key name goes as a sequence
+	if strings.TrimSpace(key) != "" && strings.ContainsAny(key, " +") && key != "+" {
+		chords, err := parseKeySequence(key)
+		if err != nil {
+			return outputError(err.Error())
+		}
+		for i := range chords {
+			chords[i].Ctrl = chords[i].Ctrl || ctrl
+			chords[i].Alt = chords[i].Alt || alt
+			chords[i].Shift = chords[i].Shift || shift
+			chords[i].Meta = chords[i].Meta || meta
+		}
+		keyParams = ipc.KeyParams{Sequence: chords, Repeat: keyParams.Repeat, Delay: keyParams.Delay}
+	}
+
+	debugParam("key=%q ctrl=%v alt=%v shift=%v meta=%v repeat=%d delay=%v", key, ctrl, alt, shift, meta, repeat, delay)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(keyParams)
 	if err != nil {
 		return outputError(err.Error())
 	}
 
-	debugRequest("key", fmt.Sprintf("key=%q ctrl=%v alt=%v shift=%v meta=%v", key, ctrl, alt, shift, meta))
+	debugRequest("key", fmt.Sprintf("key=%q ctrl=%v alt=%v shift=%v meta=%v repeat=%d", key, ctrl, alt, shift, meta, repeat))
 	ipcStart := time.Now()
 
 	resp, err := exec.Execute(ipc.Request{
@@ -129,7 +178,7 @@ func runKey(cmd *cobra.Command, args []string) error {
 	}
 
 	if !resp.OK {
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: output JSON
@@ -143,3 +192,49 @@ func runKey(cmd *cobra.Command, args []string) error {
 	// Text mode: just output OK
 	return outputSuccess(nil)
 }
+
+// maxKeyRepeat caps --repeat.
+const maxKeyRepeat = 1000
+
+// keyModifiers maps the modifier names a chord accepts to the modifier they
+// set.
+var keyModifiers = map[string]func(*ipc.KeyChord){
+	"ctrl":    func(c *ipc.KeyChord) { c.Ctrl = true },
+	"control": func(c *ipc.KeyChord) { c.Ctrl = true },
+	"alt":     func(c *ipc.KeyChord) { c.Alt = true },
+	"option":  func(c *ipc.KeyChord) { c.Alt = true },
+	"shift":   func(c *ipc.KeyChord) { c.Shift = true },
+	"meta":    func(c *ipc.KeyChord) { c.Meta = true },
+	"cmd":     func(c *ipc.KeyChord) { c.Meta = true },
+	"command": func(c *ipc.KeyChord) { c.Meta = true },
+}
+
+// parseKeySequence parses space-separated chords such as "ctrl+k p Enter".
+// In a chord the last part is the key and the others are modifiers; a
+// trailing + is the + key itself, as in "ctrl++".
+func parseKeySequence(s string) ([]ipc.KeyChord, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid key sequence %q: no keys", s)
+	}
+	chords := make([]ipc.KeyChord, 0, len(fields))
+	for _, f := range fields {
+		var chord ipc.KeyChord
+		mods, key := "", f
+		if i := strings.LastIndex(f[:len(f)-1], "+"); i >= 0 {
+			mods, key = f[:i], f[i+1:]
+		}
+		if mods != "" {
+			for _, m := range strings.Split(mods, "+") {
+				set, ok := keyModifiers[strings.ToLower(m)]
+				if !ok {
+					return nil, fmt.Errorf("invalid key %q: unknown modifier %q", f, m)
+				}
+				set(&chord)
+			}
+		}
+		chord.Key = key
+		chords = append(chords, chord)
+	}
+	return chords, nil
+}
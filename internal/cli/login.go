@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/config"
+	"github.com/grantcarthew/webctl/internal/flow"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var loginCmd = &cobra.Command{
+	Use:   "login [name]",
+	Short: "Sign in with a login sequence from the config",
+	Long: `Signs in to a site in the active tab by running a login sequence named in the
+config: it opens the login page, fills each field, submits, and waits for the
+signed-in page. Secrets come from environment variables or a command such as
+a password manager's CLI, so passwords stay out of scripts and config files.
+Without a name, lists the configured logins.
+
+Logins live under logins: in ~/.config/webctl/config.yaml or the project
+.webctl.yaml:
+
+  logins:
+    staging:
+      url: https://staging.example.com/login
+      fields:
+        - selector: "#email"
+          value: admin@example.com
+        - selector: "#password"
+          env: STAGING_PASSWORD            # an environment variable
+        - selector: "#otp"
+          command: op item get staging --otp   # a command's output
+      submit: button[type=submit]          # default: press Enter
+      wait: "#dashboard"                   # default: wait for network idle
+      timeout: 30s                         # per step (default 30s)
+
+A command runs with sh -c (cmd /C on Windows) and can prompt on the terminal;
+its output, less the trailing newline, is the field's text. Text from env
+and command is kept out of --debug output.
+
+Each step is reported as it runs, as with 'flow run'. A failing step saves
+failure.png under /tmp/webctl-logins/.
+
+Examples:
+  login staging
+  login staging && navigate staging.example.com/admin
+  login                                  # List logins
+
+Response:
+  PASS  1 navigate https://staging.example.com/login (812ms)
+  PASS  2 fill #email (40ms)
+  PASS  3 fill #password (35ms)
+  PASS  4 click button[type=submit] (22ms)
+  PASS  5 wait #dashboard (1.1s)
+  Logged in with staging (2.0s)
+
+Error cases:
+  - "no login named ..." - add one under logins: in the config
+  - "field #password: STAGING_PASSWORD is not set" - export the variable
+  - "field #otp: command failed: ..." - the secret command exited non-zero
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLogin,
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+}
+
+func runLogin(cmd *cobra.Command, args []string) error {
+	t := startTimer("login")
+	defer t.log()
+
+	c, err := loadConfig()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if len(args) == 0 {
+		return writeLoginList(c)
+	}
+	name := args[0]
+	login, ok := c.Logins[name]
+	if !ok {
+		available := "none are configured"
+		if names := c.LoginNames(); len(names) > 0 {
+			available = "available: " + strings.Join(names, ", ")
+		}
+		return outputCodedError(ipc.CodeNotFound, fmt.Sprintf("no login named %q (%s)", name, available))
+	}
+	debugParam("login=%q url=%q fields=%d", name, login.URL, len(login.Fields))
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	// Secrets are fetched only once there is a browser to type them into
+	f, err := loginFlow(name, login)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	var progress io.Writer = os.Stdout
+	if JSONOutput {
+		progress = io.Discard
+	}
+	artifacts := filepath.Join("/tmp/webctl-logins", time.Now().Format("06-01-02-150405")+"-"+normalizeTitle(name))
+	report := runFlow(exec, f, artifacts, progress, format.NewOutputOptions(JSONOutput, NoColor).UseColor)
+
+	if JSONOutput {
+		if err := outputJSON(os.Stdout, map[string]any{
+			"ok":         report.Failed == 0,
+			"login":      name,
+			"durationMs": report.DurationMs,
+			"steps":      report.Steps,
+		}); err != nil {
+			return err
+		}
+	} else if report.Failed == 0 {
+		fmt.Fprintf(os.Stdout, "Logged in with %s (%s)\n", name, formatFlowDuration(report.DurationMs))
+	}
+
+	if report.Failed > 0 {
+		return printedError{err: fmt.Errorf("login %q failed", name)}
+	}
+	return nil
+}
+
+// writeLoginList prints the configured logins and their login pages.
+func writeLoginList(c config.Config) error {
+	names := c.LoginNames()
+	if JSONOutput {
+		logins := make(map[string]string, len(names))
+		for _, name := range names {
+			logins[name] = c.Logins[name].URL
+		}
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":     true,
+			"logins": logins,
+		})
+	}
+	if len(names) == 0 {
+		_, err := fmt.Fprintln(os.Stdout, "No logins configured (see webctl login --help)")
+		return err
+	}
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for _, name := range names {
+		if _, err := fmt.Fprintf(os.Stdout, "%-*s  %s\n", width, name, c.Logins[name].URL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loginFlow builds the flow a login runs, reading each field's text from its
+// source.
+func loginFlow(name string, login config.Login) (*flow.Flow, error) {
+	f := &flow.Flow{
+		Name:    "login " + name,
+		Timeout: login.Timeout,
+		Steps:   []flow.Step{{Navigate: login.URL}},
+	}
+	for _, field := range login.Fields {
+		text, err := loginFieldText(field)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Selector, err)
+		}
+		f.Steps = append(f.Steps, flow.Step{Fill: &flow.Fill{
+			Selector: field.Selector,
+			Text:     text,
+			Secret:   field.Env != "" || field.Command != "",
+		}})
+	}
+	switch {
+	case login.Submit != "":
+		f.Steps = append(f.Steps, flow.Step{Click: login.Submit})
+	case len(login.Fields) > 0:
+		f.Steps = append(f.Steps, flow.Step{Key: "Enter"})
+	}
+	if login.Wait != "" {
+		f.Steps = append(f.Steps, flow.Step{Wait: &flow.Wait{Selector: login.Wait}})
+	} else {
+		f.Steps = append(f.Steps, flow.Step{Wait: &flow.Wait{NetworkIdle: true}})
+	}
+	return f, nil
+}
+
+// loginFieldText returns a login field's text from its value, environment
+// variable, or command.
+func loginFieldText(field config.LoginField) (string, error) {
+	switch {
+	case field.Env != "":
+		v, ok := os.LookupEnv(field.Env)
+		if !ok {
+			return "", fmt.Errorf("%s is not set", field.Env)
+		}
+		return v, nil
+	case field.Command != "":
+		var out bytes.Buffer
+		cmd := shellCommand(field.Command)
+		// The terminal stays attached, so a password manager can prompt
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, &out, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("command failed: %v", err)
+		}
+		return strings.TrimRight(out.String(), "\r\n"), nil
+	}
+	return field.Value, nil
+}
+
+// shellCommand returns a command that runs command in the platform's shell.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunLogin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("secret command uses printf")
+	}
+	userPath, _ := useConfigDirs(t)
+	writeConfigFile(t, userPath, `logins:
+  staging:
+    url: staging.example.com/login
+    fields:
+      - selector: "#email"
+        value: admin@example.com
+      - selector: "#password"
+        env: TEST_LOGIN_PASSWORD
+      - selector: "#otp"
+        command: printf '123456\n'
+    submit: button[type=submit]
+    wait: "#dashboard"
+`)
+	t.Setenv("TEST_LOGIN_PASSWORD", "hunter2")
+
+	var got []string
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		switch req.Cmd {
+		case "navigate":
+			var p ipc.NavigateParams
+			_ = json.Unmarshal(req.Params, &p)
+			got = append(got, "navigate "+p.URL)
+		case "type":
+			var p ipc.TypeParams
+			_ = json.Unmarshal(req.Params, &p)
+			got = append(got, "type "+p.Selector+" "+p.Text)
+		case "click":
+			var p ipc.ClickParams
+			_ = json.Unmarshal(req.Params, &p)
+			got = append(got, "click "+p.Selector)
+		case "ready":
+			var p ipc.ReadyParams
+			_ = json.Unmarshal(req.Params, &p)
+			got = append(got, "ready "+p.Selector)
+		default:
+			t.Errorf("unexpected request %s", req.Cmd)
+		}
+		return ipc.SuccessResponse(nil), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runLogin(loginCmd, []string{"staging"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"navigate https://staging.example.com/login",
+		"type #email admin@example.com",
+		"type #password hunter2",
+		"type #otp 123456",
+		"click button[type=submit]",
+		"ready #dashboard",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests:\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(out, "Logged in with staging") || strings.Contains(out, "hunter2") {
+		t.Errorf("output:\n%s", out)
+	}
+}
+
+func TestRunLogin_MissingSecret(t *testing.T) {
+	userPath, _ := useConfigDirs(t)
+	writeConfigFile(t, userPath, `logins:
+  staging:
+    url: https://staging.example.com/login
+    fields:
+      - selector: "#password"
+        env: TEST_LOGIN_UNSET
+`)
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		t.Errorf("unexpected request %s", req.Cmd)
+		return ipc.SuccessResponse(nil), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runLogin(loginCmd, []string{"staging"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "field #password: TEST_LOGIN_UNSET is not set") {
+		t.Errorf("err = %v", err)
+	}
+
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runLogin(loginCmd, []string{"prod"})
+	})
+	if err == nil || ErrorCode(err) != ipc.CodeNotFound || !strings.Contains(err.Error(), "available: staging") {
+		t.Errorf("unknown login: err = %v", err)
+	}
+}
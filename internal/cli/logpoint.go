@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var logpointCmd = &cobra.Command{
+	Use:   "logpoint",
+	Short: "Log an expression when a script line runs, without editing the script",
+	Long: `Adds temporary logging to any script the page loads, including third-party and
+minified code, without editing files or pausing the page. Each time the line
+runs, the expression is logged with console.log, so the values appear in the
+console buffer. Without a subcommand, lists logpoints.
+
+Logpoints are Debugger breakpoints whose condition logs the expression and is
+always false. They apply to every tab, including tabs opened later and
+scripts loaded after they are added, and last until they are removed or the
+daemon stops.
+
+Subcommands:
+  add <url>:<line>[:<column>] <expr>   Add a logpoint
+  list                                 List logpoints
+  remove <id>                          Remove a logpoint
+  clear                                Remove every logpoint
+
+Examples:
+  logpoint add app.js:120 "user.id, cart.items.length"
+  logpoint add "https://cdn.example.com/*.min.js:1:4521" "e.target"
+  console --type log
+  logpoint remove l1`,
+	Args: cobra.NoArgs,
+	RunE: runLogpointList,
+}
+
+var logpointAddCmd = &cobra.Command{
+	Use:   "add <url>:<line>[:<column>] <expr>",
+	Short: "Add a logpoint",
+	Long: `Adds a logpoint at a line of every script whose URL matches. The URL may use
+* for any run of characters and ? for one character, and then matches the
+whole URL; a URL without either matches anywhere in the script URL. Lines
+and columns start at 1; give a column for minified code, where one line
+holds the whole script (the position a stack trace reports).
+
+The expression is passed to console.log as written, so several values can
+be separated with commas. It runs in the scope of the line, so local
+variables are available.
+
+Examples:
+  logpoint add app.js:120 "user.id, cart.items.length"
+  logpoint add vendor.min.js:1:4521 "arguments"
+  logpoint add "*/checkout/*.js:42" "'total', total"
+
+Response:
+  Logpoint l1 at app.js:120: user.id, cart.items.length
+
+Error cases:
+  - "invalid location" - not <url>:<line> or <url>:<line>:<column>`,
+	Args: cobra.ExactArgs(2),
+	RunE: runLogpointAdd,
+}
+
+var logpointListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List logpoints",
+	Long: `Lists logpoints in the order they were added.
+
+Response:
+  l1  app.js:120  user.id, cart.items.length
+  l2  vendor.min.js:1:4521  arguments`,
+	Args: cobra.NoArgs,
+	RunE: runLogpointList,
+}
+
+var logpointRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a logpoint",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runLogpointRemove,
+}
+
+var logpointClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every logpoint",
+	Args:  cobra.NoArgs,
+	RunE:  runLogpointClear,
+}
+
+func init() {
+	logpointCmd.AddCommand(logpointAddCmd, logpointListCmd, logpointRemoveCmd, logpointClearCmd)
+	rootCmd.AddCommand(logpointCmd)
+}
+
+func runLogpointAdd(cmd *cobra.Command, args []string) error {
+	t := startTimer("logpoint add")
+	defer t.log()
+
+	url, line, column, err := parseLogpointLocation(args[0])
+	if err != nil {
+		return outputCodedError(ipc.CodeInvalidArgs, err.Error())
+	}
+	if strings.TrimSpace(args[1]) == "" {
+		return outputCodedError(ipc.CodeInvalidArgs, "expression is required")
+	}
+	debugParam("url=%q line=%d column=%d", url, line, column)
+
+	data, err := logpointRequest(ipc.LogpointParams{
+		Action:     "add",
+		URL:        url,
+		Line:       line,
+		Column:     column,
+		Expression: args[1],
+	})
+	if err != nil {
+		return err
+	}
+	if len(data.Logpoints) == 0 {
+		return outputError("daemon did not return the logpoint")
+	}
+	lp := data.Logpoints[0]
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":       true,
+			"logpoint": lp,
+		})
+	}
+	fmt.Fprintf(os.Stdout, "Logpoint %s at %s: %s\n", lp.ID, format.LogpointLocation(lp), lp.Expression)
+	if lp.Locations == 0 {
+		outputHint("no loaded script matches yet; the logpoint binds when one loads")
+	}
+	return nil
+}
+
+func runLogpointList(cmd *cobra.Command, args []string) error {
+	t := startTimer("logpoint list")
+	defer t.log()
+
+	data, err := logpointRequest(ipc.LogpointParams{Action: "list"})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":        true,
+			"logpoints": data.Logpoints,
+		})
+	}
+	return format.Logpoints(os.Stdout, data.Logpoints)
+}
+
+func runLogpointRemove(cmd *cobra.Command, args []string) error {
+	t := startTimer("logpoint remove")
+	defer t.log()
+
+	if _, err := logpointRequest(ipc.LogpointParams{Action: "remove", ID: args[0]}); err != nil {
+		return err
+	}
+	return outputSuccess(nil)
+}
+
+func runLogpointClear(cmd *cobra.Command, args []string) error {
+	t := startTimer("logpoint clear")
+	defer t.log()
+
+	if _, err := logpointRequest(ipc.LogpointParams{Action: "clear"}); err != nil {
+		return err
+	}
+	return outputSuccess(nil)
+}
+
+// parseLogpointLocation splits <url>:<line>[:<column>]. The numbers are taken
+// from the end, so URLs with a scheme or port parse as expected.
+func parseLogpointLocation(loc string) (url string, line, column int, err error) {
+	invalid := fmt.Errorf("invalid location %q (use <url>:<line> or <url>:<line>:<column>)", loc)
+
+	rest, n, ok := cutLastNumber(loc)
+	if !ok {
+		return "", 0, 0, invalid
+	}
+	line = n
+	if head, m, ok := cutLastNumber(rest); ok {
+		rest, line, column = head, m, n
+		if column < 1 {
+			return "", 0, 0, invalid
+		}
+	}
+	if rest == "" || line < 1 {
+		return "", 0, 0, invalid
+	}
+	return rest, line, column, nil
+}
+
+// cutLastNumber splits s at its last colon when what follows is a number.
+func cutLastNumber(s string) (string, int, bool) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return s, 0, false
+	}
+	n, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return s, 0, false
+	}
+	return s[:i], n, true
+}
+
+// logpointRequest sends a logpoint action to the daemon.
+func logpointRequest(params ipc.LogpointParams) (ipc.LogpointData, error) {
+	var data ipc.LogpointData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("logpoint", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "logpoint", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func TestParseLogpointLocation(t *testing.T) {
+	tests := []struct {
+		loc          string
+		url          string
+		line, column int
+	}{
+		{"app.js:120", "app.js", 120, 0},
+		{"vendor.min.js:1:4521", "vendor.min.js", 1, 4521},
+		{"https://example.com/static/app.js:12", "https://example.com/static/app.js", 12, 0},
+		{"http://localhost:8080/app.js:3:9", "http://localhost:8080/app.js", 3, 9},
+	}
+	for _, tt := range tests {
+		url, line, column, err := parseLogpointLocation(tt.loc)
+		if err != nil {
+			t.Errorf("parseLogpointLocation(%q) error: %v", tt.loc, err)
+			continue
+		}
+		if url != tt.url || line != tt.line || column != tt.column {
+			t.Errorf("parseLogpointLocation(%q) = %q, %d, %d; want %q, %d, %d", tt.loc, url, line, column, tt.url, tt.line, tt.column)
+		}
+	}
+
+	for _, loc := range []string{"app.js", ":12", "app.js:0", "app.js:3:0", "app.js:x"} {
+		if _, _, _, err := parseLogpointLocation(loc); err == nil {
+			t.Errorf("parseLogpointLocation(%q) succeeded, want an error", loc)
+		}
+	}
+}
+
+func TestRunLogpointAdd(t *testing.T) {
+	want := ipc.LogpointParams{Action: "add", URL: "vendor.min.js", Line: 1, Column: 4521, Expression: "e.target"}
+	exec := expectParamsExecutor(t, "logpoint", want, ipc.SuccessResponse(ipc.LogpointData{Logpoints: []ipc.LogpointInfo{
+		{ID: "l1", URL: "vendor.min.js", Line: 1, Column: 4521, Expression: "e.target"},
+	}}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	var stderr string
+	out := captureStream(t, &os.Stdout, func() {
+		stderr = captureStream(t, &os.Stderr, func() {
+			err = runLogpointAdd(&cobra.Command{}, []string{"vendor.min.js:1:4521", "e.target"})
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "Logpoint l1 at vendor.min.js:1:4521: e.target\n" {
+		t.Errorf("output = %q", out)
+	}
+	if !strings.Contains(stderr, "no loaded script matches yet") {
+		t.Errorf("expected a hint for an unbound logpoint, got %q", stderr)
+	}
+}
+
+func TestRunLogpointList(t *testing.T) {
+	exec := expectParamsExecutor(t, "logpoint", ipc.LogpointParams{Action: "list"}, ipc.SuccessResponse(ipc.LogpointData{Logpoints: []ipc.LogpointInfo{
+		{ID: "l1", URL: "app.js", Line: 120, Expression: "user.id"},
+	}}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runLogpointList(&cobra.Command{}, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "l1  app.js:120  user.id\n" {
+		t.Errorf("output = %q", out)
+	}
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/daemonlog"
+	"github.com/spf13/cobra"
+)
+
+// logFileEnv is the environment variable that overrides the daemon log path
+// for both the daemon and "webctl logs", so one setting keeps them in step.
+const logFileEnv = "WEBCTL_LOG_FILE"
+
+// logsFollowInterval is how often --follow polls the log file for new lines.
+const logsFollowInterval = 250 * time.Millisecond
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the daemon log",
+	Long: `Prints the daemon's log file, oldest entry first, including rotated files.
+
+The daemon writes its log to $XDG_STATE_HOME/webctl/daemon.log (falls back to
+~/.local/state/webctl/daemon.log). Set ` + logFileEnv + ` or pass --log-file to
+'webctl start' to use another path; pass the same --log-file here to read it.
+The log rotates at 10 MB or after 7 days, keeping 3 old files.
+
+Entries are recorded at info level and above unless the daemon is started with
+--log-level (or --debug, which implies debug). Works whether or not the daemon
+is running.
+
+Flags:
+  --follow, -f      Keep printing new entries until interrupted (Ctrl-C)
+  --level <level>   Only show entries at this level or above
+                    (debug, info, warn, error)
+  --log-file <path> Read this log file instead of the default
+
+Examples:
+  logs                                 # Whole log
+  logs --level warn                    # Warnings and errors only
+  logs -f                              # Tail the log
+  logs --json | jq -c 'select(.msg == "daemon stopping")'
+
+Text output:
+  14:03:21.118 INFO  daemon ready port=9222 socket=/run/user/1000/webctl/webctl.sock
+  14:05:02.904 ERROR daemon stopping reason="browser connection lost"
+
+JSON output (the log lines as written, one object per line):
+  {"time":"2025-01-01T14:03:21.118+10:00","level":"INFO","msg":"daemon ready","port":9222}`,
+	Args:        cobra.NoArgs,
+	RunE:        runLogs,
+	Annotations: map[string]string{noAutoStartAnnotation: ""},
+}
+
+func init() {
+	logsCmd.Flags().BoolP("follow", "f", false, "Keep printing new entries until interrupted")
+	logsCmd.Flags().String("level", "", "Minimum level to show (debug, info, warn, error)")
+	logsCmd.Flags().String("log-file", "", "Log file to read (default $"+logFileEnv+" or the XDG state path)")
+	rootCmd.AddCommand(logsCmd)
+}
+
+// logFilePath resolves the daemon log path: an explicit flag value, then
+// WEBCTL_LOG_FILE, then the XDG default.
+func logFilePath(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if env := os.Getenv(logFileEnv); env != "" {
+		return env
+	}
+	return daemonlog.DefaultPath()
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	t := startTimer("logs")
+	defer t.log()
+
+	follow, _ := cmd.Flags().GetBool("follow")
+	levelName, _ := cmd.Flags().GetString("level")
+	logFile, _ := cmd.Flags().GetString("log-file")
+	path := logFilePath(logFile)
+	debugParam("path=%q follow=%v level=%q", path, follow, levelName)
+
+	minLevel := slog.LevelDebug
+	if levelName != "" {
+		level, err := daemonlog.ParseLevel(levelName)
+		if err != nil {
+			return outputError(err.Error())
+		}
+		minLevel = level
+	}
+
+	opts := format.NewOutputOptions(JSONOutput, NoColor)
+	printLine := func(line []byte) error {
+		e, err := daemonlog.ParseEntry(line)
+		if err != nil {
+			// Not one of ours (e.g. a hand-edited file); skip it
+			debugf("LOGS", "skipping unparseable line: %v", err)
+			return nil
+		}
+		if e.Level < minLevel {
+			return nil
+		}
+		if JSONOutput {
+			_, err := os.Stdout.Write(append(line, '\n'))
+			return err
+		}
+		return format.LogEntry(os.Stdout, e, opts)
+	}
+
+	offset, err := daemonlog.ReadAll(path, printLine)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !follow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := daemonlog.Follow(ctx, path, offset, logsFollowInterval, printLine); err != nil {
+		return outputError(err.Error())
+	}
+	return nil
+}
+
+// daemonLogging resolves --log-level, --log-format, and --debug into the
+// daemon's log file level and stderr handler. The log file is always written;
+// stderr only carries log records when one of the three flags asks for them.
+func daemonLogging() (slog.Level, slog.Handler, error) {
+	level := slog.LevelInfo
+	if Debug {
+		level = slog.LevelDebug
+	}
+	if LogLevel != "" {
+		l, err := daemonlog.ParseLevel(LogLevel)
+		if err != nil {
+			return 0, nil, err
+		}
+		level = l
+	}
+
+	stderr, err := daemonlog.NewHandler(os.Stderr, LogFormat, level)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !Debug && LogLevel == "" && LogFormat == "" {
+		stderr = nil
+	}
+	return level, stderr, nil
+}
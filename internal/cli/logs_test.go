@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunLogs_LevelFilterAndRotatedFiles(t *testing.T) {
+	enableJSONOutput(t)
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	t.Setenv(logFileEnv, path)
+	if err := os.WriteFile(path+".1", []byte(`{"time":"2025-01-01T10:00:00Z","level":"ERROR","msg":"old failure"}`+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(
+		`{"time":"2025-01-01T11:00:00Z","level":"INFO","msg":"daemon ready"}`+"\n"+
+			"garbage\n"+
+			`{"time":"2025-01-01T11:01:00Z","level":"WARN","msg":"slow"}`+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := logsCmd.Flags().Set("level", "warn"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = logsCmd.Flags().Set("level", "")
+		logsCmd.Flags().Lookup("level").Changed = false
+	})
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runLogs(logsCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "old failure") || !strings.Contains(lines[1], `"slow"`) {
+		t.Errorf("expected old failure then slow, got %q", out)
+	}
+}
+
+func TestRunLogs_InvalidLevel(t *testing.T) {
+	enableJSONOutput(t)
+	t.Setenv(logFileEnv, filepath.Join(t.TempDir(), "daemon.log"))
+	if err := logsCmd.Flags().Set("level", "loud"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = logsCmd.Flags().Set("level", "")
+		logsCmd.Flags().Lookup("level").Changed = false
+	})
+
+	var err error
+	_ = captureStream(t, &os.Stdout, func() {
+		err = runLogs(logsCmd, nil)
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+}
+
+func TestLogFilePath_Precedence(t *testing.T) {
+	t.Setenv(logFileEnv, "/env/daemon.log")
+	if got := logFilePath("/flag/daemon.log"); got != "/flag/daemon.log" {
+		t.Errorf("flag should win, got %q", got)
+	}
+	if got := logFilePath(""); got != "/env/daemon.log" {
+		t.Errorf("env should apply, got %q", got)
+	}
+}
+
+func TestDaemonLogging(t *testing.T) {
+	tests := []struct {
+		name       string
+		debug      bool
+		level      string
+		format     string
+		wantLevel  slog.Level
+		wantStderr bool
+		wantErr    bool
+	}{
+		{name: "defaults", wantLevel: slog.LevelInfo},
+		{name: "debug flag", debug: true, wantLevel: slog.LevelDebug, wantStderr: true},
+		{name: "explicit level wins over debug", debug: true, level: "warn", wantLevel: slog.LevelWarn, wantStderr: true},
+		{name: "json format alone", format: "json", wantLevel: slog.LevelInfo, wantStderr: true},
+		{name: "bad level", level: "loud", wantErr: true},
+		{name: "bad format", format: "xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldDebug, oldLevel, oldFormat := Debug, LogLevel, LogFormat
+			t.Cleanup(func() { Debug, LogLevel, LogFormat = oldDebug, oldLevel, oldFormat })
+			Debug, LogLevel, LogFormat = tt.debug, tt.level, tt.format
+
+			level, stderr, err := daemonLogging()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if level != tt.wantLevel {
+				t.Errorf("level = %v, want %v", level, tt.wantLevel)
+			}
+			if (stderr != nil) != tt.wantStderr {
+				t.Errorf("stderr handler = %v, want present=%v", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
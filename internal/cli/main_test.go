@@ -1,11 +1,25 @@
 package cli
 
 import (
+	"os"
 	"testing"
 
 	"go.uber.org/goleak"
 )
 
 func TestMain(m *testing.M) {
-	goleak.VerifyTestMain(m)
+	// Keep the developer's own config file out of commands run by tests.
+	dir, err := os.MkdirTemp("", "webctl-cli-test-config")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XDG_CONFIG_HOME", dir)
+	// Tests expect the default output, not CI mode, even when run in CI.
+	_ = os.Unsetenv("CI")
+	_ = os.Unsetenv(ciEnv)
+
+	goleak.VerifyTestMain(m, goleak.Cleanup(func(exitCode int) {
+		_ = os.RemoveAll(dir)
+		os.Exit(exitCode)
+	}))
 }
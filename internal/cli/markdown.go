@@ -93,11 +93,11 @@ func runMarkdownDefault(cmd *cobra.Command, args []string) error {
 
 	// Validate that no arguments were provided (catches unknown subcommands)
 	if len(args) > 0 {
-		return outputError(fmt.Sprintf("unknown command %q for \"webctl markdown\"", args[0]))
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("unknown command %q for \"webctl markdown\"", args[0]))
 	}
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	md, err := getMarkdownFromDaemon(cmd)
@@ -192,7 +192,7 @@ func getRawHTMLFromDaemon(cmd *cobra.Command) (string, error) {
 	debugRequest("html", fmt.Sprintf("selector=%q", selector))
 	ipcStart := time.Now()
 
-	resp, err := exec.Execute(ipc.Request{
+	resp, err := executeStreamData(exec, ipc.Request{
 		Cmd:    "html",
 		Params: params,
 	})
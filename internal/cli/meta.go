@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Extract OpenGraph, Twitter, JSON-LD, and microdata metadata",
+	Long: `Extracts the active page's metadata as structured data, for SEO pipelines
+and checking what search engines and link previews will see:
+  - description and robots <meta> tags
+  - the canonical link and hreflang alternates
+  - OpenGraph properties (og:, plus article:, product:, and the other
+    OpenGraph object namespaces) and Twitter card tags, in document order
+  - every JSON-LD block, parsed (blocks that are not valid JSON are reported)
+  - microdata items, with nested items as nested objects
+
+URLs in links and microdata are resolved against the page. Text output
+summarizes JSON-LD and microdata by type and name; use --json for everything.
+
+Examples:
+  meta
+  meta --json | jq '.jsonLd[] | select(."@type" == "Product")'
+  meta --json | jq -r '.openGraph[] | select(.property == "og:image") | .content'
+
+Text output:
+  title: Blue Widget - Shop
+  description: A widget, in blue.
+  canonical: https://shop.test/widgets/blue
+  hreflang:
+    de  https://shop.test/de/widgets/blue
+  openGraph:
+    og:title  Blue Widget
+    og:image  https://shop.test/blue.jpg
+  jsonLd:
+    Product "Blue Widget"
+
+JSON output:
+  {"ok": true, "url": "...", "title": "...", "canonical": "...", "hreflang": [...],
+   "openGraph": [{"property": "og:title", "content": "..."}], "twitter": [...],
+   "jsonLd": [{...}], "microdata": [{"type": [...], "properties": {...}}]}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start
+  - "no active session" - no browser tab is open`,
+	Args: cobra.NoArgs,
+	RunE: runMeta,
+}
+
+func init() {
+	rootCmd.AddCommand(metaCmd)
+}
+
+func runMeta(cmd *cobra.Command, args []string) error {
+	t := startTimer("meta")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	debugRequest("meta", "")
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "meta"})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.MetaData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		result := map[string]any{
+			"ok":        true,
+			"url":       data.URL,
+			"title":     data.Title,
+			"hreflang":  data.Hreflang,
+			"openGraph": data.OpenGraph,
+			"twitter":   data.Twitter,
+			"jsonLd":    data.JSONLD,
+			"microdata": data.Microdata,
+		}
+		if data.Description != "" {
+			result["description"] = data.Description
+		}
+		if data.Canonical != "" {
+			result["canonical"] = data.Canonical
+		}
+		if data.Robots != "" {
+			result["robots"] = data.Robots
+		}
+		if len(data.JSONLDErrors) > 0 {
+			result["jsonLdErrors"] = data.JSONLDErrors
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	return format.Meta(os.Stdout, data)
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunMeta_JSON(t *testing.T) {
+	enableJSONOutput(t)
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "meta" {
+				t.Errorf("expected cmd=meta, got %s", req.Cmd)
+			}
+			raw, _ := json.Marshal(ipc.MetaData{
+				URL:       "https://example.com/",
+				Title:     "Example",
+				Canonical: "https://example.com/",
+				OpenGraph: []ipc.MetaTag{{Property: "og:title", Content: "Example"}},
+				JSONLD:    []json.RawMessage{json.RawMessage(`{"@type":"WebSite","name":"Example"}`)},
+			})
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runMeta(metaCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		OK        bool             `json:"ok"`
+		Canonical string           `json:"canonical"`
+		Robots    *string          `json:"robots"`
+		OpenGraph []ipc.MetaTag    `json:"openGraph"`
+		JSONLD    []map[string]any `json:"jsonLd"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !result.OK || result.Canonical != "https://example.com/" || len(result.OpenGraph) != 1 {
+		t.Errorf("unexpected response: %s", out)
+	}
+	if len(result.JSONLD) != 1 || result.JSONLD[0]["@type"] != "WebSite" {
+		t.Errorf("jsonLd = %v, want the parsed block", result.JSONLD)
+	}
+	if result.Robots != nil {
+		t.Errorf("robots should be omitted when empty: %s", out)
+	}
+}
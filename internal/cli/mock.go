@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Slow down, fail, or stub requests matching a URL",
+	Long: `Intercepts requests whose URL matches a pattern and holds them, fails some of
+them, or answers them with a stub response, so slow and flaky backend
+behaviour can be simulated against a healthy backend. Use it to test loading
+states, retries, and error handling. Without a subcommand, lists mocks.
+
+Mocks apply to every tab, including tabs opened later, and last until they
+are removed or the daemon stops. When several match a request, the first
+added applies. Mocked requests still appear in the network buffer.
+
+Subcommands:
+  add --url <pattern>   Add a mock
+  list                  List mocks with how often each applied
+  remove <id>           Remove a mock
+  clear                 Remove every mock
+
+Examples:
+  mock add --url "*/api/*" --delay 2s --fail-rate 0.3
+  mock add --url /api/flags --status 200 --body '{"newCheckout": true}'
+  mock add --url "*/api/orders*" --fail-rate 1 --fail-status 503
+  mock
+  mock remove k1`,
+	Args: cobra.NoArgs,
+	RunE: runMockList,
+}
+
+var mockAddCmd = &cobra.Command{
+	Use:   "add --url <pattern>",
+	Short: "Add a mock",
+	Long: `Adds a mock for requests whose URL matches --url. A pattern may use * for
+any run of characters and ? for one character, and then matches the whole
+URL; a pattern without either matches anywhere in the URL.
+
+A mock does one or more of:
+  - holds each request for --delay before it proceeds
+  - fails a --fail-rate fraction of requests, as a network error or, with
+    --fail-status, as a response with that status
+  - answers with a stub response (--status, --body) instead of contacting
+    the server
+
+Flags:
+  --url <pattern>        URL pattern to match (required)
+  --delay <d>            Hold each request this long (e.g. 2s)
+  --fail-rate <f>        Fraction of requests to fail, 0 to 1
+  --fail-status <code>   Fail with this HTTP status instead of a network error
+  --status <code>        Answer with a stub response with this status
+  --body <text>          Stub response body
+  --body-file <path>     Read the stub response body from a file
+  --content-type <type>  Stub content type (default: JSON if the body parses, else text)
+
+Examples:
+  mock add --url "*/api/*" --delay 2s --fail-rate 0.3
+  mock add --url /api/user --status 200 --body-file ./fixtures/user.json
+  mock add --url "*.png" --fail-rate 1
+
+Response:
+  Mocking */api/* as k1: delay 2s, fail 30%
+
+Error cases:
+  - "--url is required" - no pattern given
+  - "mock does nothing" - none of --delay, --fail-rate, or --status set`,
+	Args: cobra.NoArgs,
+	RunE: runMockAdd,
+}
+
+var mockListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List mocks",
+	Long: `Lists mocks in the order they were added, with what each does, how many
+requests it matched, and how many of those it failed.
+
+Response:
+  k1  */api/*  delay 2s, fail 30%  14 hits, 4 failed
+  k2  */api/flags*  stub 200 application/json (21B)  3 hits, 0 failed`,
+	Args: cobra.NoArgs,
+	RunE: runMockList,
+}
+
+var mockRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a mock",
+	Long: `Removes a mock. Matching requests go to the server untouched again; a
+request already being held still completes as mocked.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMockRemove,
+}
+
+var mockClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every mock",
+	Args:  cobra.NoArgs,
+	RunE:  runMockClear,
+}
+
+func init() {
+	mockAddCmd.Flags().String("url", "", "URL pattern to match (* any run, ? one character)")
+	mockAddCmd.Flags().Duration("delay", 0, "Hold each matching request this long")
+	mockAddCmd.Flags().Float64("fail-rate", 0, "Fraction of matching requests to fail, 0 to 1")
+	mockAddCmd.Flags().Int("fail-status", 0, "Fail with this HTTP status instead of a network error")
+	mockAddCmd.Flags().Int("status", 0, "Answer with a stub response with this status")
+	mockAddCmd.Flags().String("body", "", "Stub response body")
+	mockAddCmd.Flags().String("body-file", "", "Read the stub response body from a file")
+	mockAddCmd.Flags().String("content-type", "", "Stub response content type")
+
+	mockCmd.AddCommand(mockAddCmd, mockListCmd, mockRemoveCmd, mockClearCmd)
+	rootCmd.AddCommand(mockCmd)
+}
+
+func runMockAdd(cmd *cobra.Command, args []string) error {
+	t := startTimer("mock add")
+	defer t.log()
+
+	url, _ := cmd.Flags().GetString("url")
+	delay, _ := cmd.Flags().GetDuration("delay")
+	failRate, _ := cmd.Flags().GetFloat64("fail-rate")
+	failStatus, _ := cmd.Flags().GetInt("fail-status")
+	status, _ := cmd.Flags().GetInt("status")
+	body, _ := cmd.Flags().GetString("body")
+	bodyFile, _ := cmd.Flags().GetString("body-file")
+	contentType, _ := cmd.Flags().GetString("content-type")
+
+	switch {
+	case url == "":
+		return outputCodedError(ipc.CodeInvalidArgs, "--url is required")
+	case delay < 0:
+		return outputCodedError(ipc.CodeInvalidArgs, "--delay must not be negative")
+	case failRate < 0 || failRate > 1:
+		return outputCodedError(ipc.CodeInvalidArgs, "--fail-rate must be between 0 and 1")
+	case failStatus != 0 && failRate == 0:
+		return outputCodedError(ipc.CodeInvalidArgs, "--fail-status needs --fail-rate")
+	case body != "" && bodyFile != "":
+		return outputError("use --body or --body-file, not both")
+	case (body != "" || bodyFile != "" || contentType != "") && status == 0:
+		return outputCodedError(ipc.CodeInvalidArgs, "--body, --body-file, and --content-type need --status")
+	}
+	if bodyFile != "" {
+		data, err := os.ReadFile(bodyFile)
+		if err != nil {
+			return outputError(err.Error())
+		}
+		body = string(data)
+	}
+
+	data, err := mockRequest(ipc.MockParams{
+		Action:      "add",
+		URL:         url,
+		Delay:       delay.Milliseconds(),
+		FailRate:    failRate,
+		FailStatus:  failStatus,
+		Status:      status,
+		Body:        body,
+		ContentType: contentType,
+	})
+	if err != nil {
+		return err
+	}
+	if len(data.Mocks) == 0 {
+		return outputError("daemon did not return the mock")
+	}
+	m := data.Mocks[0]
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":   true,
+			"mock": m,
+		})
+	}
+	fmt.Fprintf(os.Stdout, "Mocking %s as %s: %s\n", m.URL, m.ID, format.MockEffect(m))
+	return nil
+}
+
+func runMockList(cmd *cobra.Command, args []string) error {
+	t := startTimer("mock list")
+	defer t.log()
+
+	data, err := mockRequest(ipc.MockParams{Action: "list"})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":    true,
+			"mocks": data.Mocks,
+		})
+	}
+	return format.Mocks(os.Stdout, data.Mocks)
+}
+
+func runMockRemove(cmd *cobra.Command, args []string) error {
+	t := startTimer("mock remove")
+	defer t.log()
+
+	if _, err := mockRequest(ipc.MockParams{Action: "remove", ID: args[0]}); err != nil {
+		return err
+	}
+	return outputSuccess(nil)
+}
+
+func runMockClear(cmd *cobra.Command, args []string) error {
+	t := startTimer("mock clear")
+	defer t.log()
+
+	if _, err := mockRequest(ipc.MockParams{Action: "clear"}); err != nil {
+		return err
+	}
+	return outputSuccess(nil)
+}
+
+// mockRequest sends a mock action to the daemon.
+func mockRequest(params ipc.MockParams) (ipc.MockData, error) {
+	var data ipc.MockData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("mock", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "mock", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func newMockAddTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("url", "", "")
+	cmd.Flags().Duration("delay", 0, "")
+	cmd.Flags().Float64("fail-rate", 0, "")
+	cmd.Flags().Int("fail-status", 0, "")
+	cmd.Flags().Int("status", 0, "")
+	cmd.Flags().String("body", "", "")
+	cmd.Flags().String("body-file", "", "")
+	cmd.Flags().String("content-type", "", "")
+	return cmd
+}
+
+func TestRunMockAdd(t *testing.T) {
+	var got ipc.MockParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.MockData{Mocks: []ipc.MockInfo{{
+			ID: "k1", URL: got.URL, Delay: got.Delay, FailRate: got.FailRate,
+		}}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := newMockAddTestCmd()
+	_ = cmd.Flags().Set("url", "*/api/*")
+	_ = cmd.Flags().Set("delay", "2s")
+	_ = cmd.Flags().Set("fail-rate", "0.3")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runMockAdd(cmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != "add" || got.URL != "*/api/*" || got.Delay != 2000 || got.FailRate != 0.3 {
+		t.Errorf("params = %+v", got)
+	}
+	if out != "Mocking */api/* as k1: delay 2s, fail 30%\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunMockAdd_BodyFile(t *testing.T) {
+	var got ipc.MockParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.MockData{Mocks: []ipc.MockInfo{{ID: "k1"}}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	path := filepath.Join(t.TempDir(), "user.json")
+	if err := os.WriteFile(path, []byte(`{"name":"Ann"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := newMockAddTestCmd()
+	_ = cmd.Flags().Set("url", "/api/user")
+	_ = cmd.Flags().Set("status", "200")
+	_ = cmd.Flags().Set("body-file", path)
+
+	captureStream(t, &os.Stdout, func() {
+		if err := runMockAdd(cmd, nil); err != nil {
+			t.Error(err)
+		}
+	})
+	if got.Status != 200 || got.Body != `{"name":"Ann"}` {
+		t.Errorf("params = %+v", got)
+	}
+}
+
+func TestRunMockAdd_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  string
+	}{
+		{"no url", map[string]string{"delay": "1s"}, "--url is required"},
+		{"rate", map[string]string{"url": "/api", "fail-rate": "2"}, "between 0 and 1"},
+		{"fail status alone", map[string]string{"url": "/api", "fail-status": "503"}, "needs --fail-rate"},
+		{"body without status", map[string]string{"url": "/api", "body": "x"}, "need --status"},
+		{"both bodies", map[string]string{"url": "/api", "status": "200", "body": "x", "body-file": "y"}, "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+				called = true
+				return ipc.SuccessResponse(nil), nil
+			}}
+			defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+			cmd := newMockAddTestCmd()
+			for k, v := range tt.flags {
+				_ = cmd.Flags().Set(k, v)
+			}
+			var err error
+			captureStream(t, &os.Stderr, func() {
+				err = runMockAdd(cmd, nil)
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+			if called {
+				t.Error("daemon was called for an invalid mock")
+			}
+		})
+	}
+}
+
+func TestRunMockList(t *testing.T) {
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.SuccessResponse(ipc.MockData{Mocks: []ipc.MockInfo{
+			{ID: "k1", URL: "*/api/*", Delay: (2 * time.Second).Milliseconds(), FailRate: 0.3, Hits: 14, Failures: 4},
+			{ID: "k2", URL: "*/api/flags*", Status: 200, ContentType: "application/json", BodyBytes: 21, Hits: 3},
+		}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runMockList(mockListCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "k1  */api/*  delay 2s, fail 30%  14 hits, 4 failed\n" +
+		"k2  */api/flags*  stub 200 application/json (21B)  3 hits, 0 failed\n"
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+}
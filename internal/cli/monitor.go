@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var monitorCmd = &cobra.Command{
+	Use:   "monitor [url]",
+	Short: "Check a URL on a schedule and alert on failures",
+	Long: `Adds a monitor: the daemon loads the URL on a schedule, checks assertions
+against the page, and records each result, turning webctl into a lightweight
+synthetic-monitoring agent for internal apps. Without a URL, lists monitors.
+
+Each check opens the URL in a new background tab, waits for it to load, and
+checks every --assert in order, retrying one that fails until the check's
+timeout, so content rendered after load is seen. The tab is closed after.
+
+Monitors run inside the daemon and stop when it does.
+
+Assertions:
+  selector:<css>    An element matches the selector
+  text:<text>       The page text contains text
+  title:<text>      The page title contains text
+  url:<text>        The final URL contains text (catches redirects to login)
+  js:<expr>         The expression is truthy
+
+Flags:
+  --every <dur>      Time between checks (default 5m, at least 5s)
+  --timeout <dur>    Time each check may take (default 30s)
+  --assert <a>       Assertion the page must pass (repeatable)
+  --screenshot       Save a screenshot of every check
+  --webhook <url>    POST JSON when the monitor starts failing and recovers
+
+Results:
+  Every check is appended to results.jsonl in the monitor's directory,
+  /tmp/webctl-monitors/{id}-YY-MM-DD-HHMMSS/, beside its screenshots. The
+  daemon keeps the latest 500 for 'monitor results'.
+
+Webhook:
+  The body is {"event": "failure"|"recovery", "monitor": {...},
+  "result": {...}, "text": "..."}. The text field is a one-line summary that
+  Slack and similar incoming webhooks display as is.
+
+Subcommands:
+  list             List monitors with their latest status
+  results <id>     Show a monitor's recent checks
+  stop <id>        Stop and remove a monitor
+
+Examples:
+  monitor https://app.internal/ --every 5m --assert "selector:#app"
+  monitor app.internal/health --every 1m --assert "text:OK" --webhook https://hooks.slack.com/...
+  monitor https://app.internal/ --screenshot --assert "url:/dashboard"
+  monitor                          # List monitors
+  monitor results m1 --limit 10
+  monitor stop m1
+
+Response:
+  Monitoring https://app.internal/ every 5m as m1
+  Results: /tmp/webctl-monitors/m1-25-06-01-143052`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMonitor,
+}
+
+var monitorListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List monitors with their latest status",
+	Long: `Lists monitors in the order they were added, with the status of the latest
+check (PASS, FAIL, or WAIT before the first), the interval, and counts.
+
+Response:
+  m1  PASS  every 5m  12 checks, 1 failed  https://app.internal/
+  m2  FAIL  every 1m  3 checks, 3 failed  https://staging.internal/
+        selector:#app: no element matches #app`,
+	Args: cobra.NoArgs,
+	RunE: runMonitorList,
+}
+
+var monitorResultsCmd = &cobra.Command{
+	Use:   "results <id>",
+	Short: "Show a monitor's recent checks",
+	Long: `Shows a monitor's checks, oldest first, with their duration, the first
+failure, and screenshot path.
+
+Flags:
+  --limit <n>    Show only the latest n checks (default 20, 0 for all kept)
+
+Response:
+  2025-06-01 14:30:52  PASS  1.2s
+  2025-06-01 14:35:52  FAIL  30.0s  selector:#app: no element matches #app`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMonitorResults,
+}
+
+var monitorStopCmd = &cobra.Command{
+	Use:     "stop <id>",
+	Aliases: []string{"remove"},
+	Short:   "Stop and remove a monitor",
+	Long: `Stops a monitor's checks and removes it. Its results.jsonl and screenshots
+stay on disk.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMonitorStop,
+}
+
+func init() {
+	monitorCmd.Flags().Duration("every", 5*time.Minute, "Time between checks (at least 5s)")
+	monitorCmd.Flags().Duration("timeout", 30*time.Second, "Time each check may take")
+	monitorCmd.Flags().StringArray("assert", nil, "Assertion: selector:<css>, text:<text>, title:<text>, url:<text>, js:<expr> (repeatable)")
+	monitorCmd.Flags().Bool("screenshot", false, "Save a screenshot of every check")
+	monitorCmd.Flags().String("webhook", "", "URL to POST to when the monitor fails and recovers")
+	monitorResultsCmd.Flags().Int("limit", 20, "Show only the latest n checks (0 for all kept)")
+
+	monitorCmd.AddCommand(monitorListCmd, monitorResultsCmd, monitorStopCmd)
+	rootCmd.AddCommand(monitorCmd)
+}
+
+func runMonitor(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return runMonitorList(cmd, args)
+	}
+
+	t := startTimer("monitor")
+	defer t.log()
+
+	every, _ := cmd.Flags().GetDuration("every")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	asserts, _ := cmd.Flags().GetStringArray("assert")
+	screenshot, _ := cmd.Flags().GetBool("screenshot")
+	webhook, _ := cmd.Flags().GetString("webhook")
+
+	if timeout <= 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--timeout must be positive")
+	}
+	for _, a := range asserts {
+		if _, _, err := ipc.ParseMonitorAssert(a); err != nil {
+			return outputError(err.Error())
+		}
+	}
+
+	data, err := monitorRequest(ipc.MonitorParams{
+		Action:     "add",
+		URL:        normalizeURL(args[0]),
+		Every:      every.Milliseconds(),
+		Timeout:    timeout.Milliseconds(),
+		Screenshot: screenshot,
+		Assert:     asserts,
+		Webhook:    webhook,
+	})
+	if err != nil {
+		return err
+	}
+	if len(data.Monitors) == 0 {
+		return outputError("daemon did not return the monitor")
+	}
+	m := data.Monitors[0]
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":      true,
+			"monitor": m,
+		})
+	}
+	fmt.Fprintf(os.Stdout, "Monitoring %s every %s as %s\n", m.URL, format.MonitorInterval(m.Every), m.ID)
+	fmt.Fprintf(os.Stdout, "Results: %s\n", m.Dir)
+	return nil
+}
+
+func runMonitorList(cmd *cobra.Command, args []string) error {
+	t := startTimer("monitor list")
+	defer t.log()
+
+	data, err := monitorRequest(ipc.MonitorParams{Action: "list"})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":       true,
+			"monitors": data.Monitors,
+		})
+	}
+	return format.Monitors(os.Stdout, data.Monitors, format.NewOutputOptions(JSONOutput, NoColor))
+}
+
+func runMonitorResults(cmd *cobra.Command, args []string) error {
+	t := startTimer("monitor results")
+	defer t.log()
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--limit must be zero or more")
+	}
+
+	data, err := monitorRequest(ipc.MonitorParams{Action: "results", ID: args[0], Limit: limit})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":      true,
+			"results": data.Results,
+		})
+	}
+	return format.MonitorResults(os.Stdout, data.Results, format.NewOutputOptions(JSONOutput, NoColor))
+}
+
+func runMonitorStop(cmd *cobra.Command, args []string) error {
+	t := startTimer("monitor stop")
+	defer t.log()
+
+	if _, err := monitorRequest(ipc.MonitorParams{Action: "remove", ID: args[0]}); err != nil {
+		return err
+	}
+	return outputSuccess(nil)
+}
+
+// monitorRequest sends a monitor action to the daemon.
+func monitorRequest(params ipc.MonitorParams) (ipc.MonitorData, error) {
+	var data ipc.MonitorData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("monitor", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "monitor", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func newMonitorTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().Duration("every", 5*time.Minute, "")
+	cmd.Flags().Duration("timeout", 30*time.Second, "")
+	cmd.Flags().StringArray("assert", nil, "")
+	cmd.Flags().Bool("screenshot", false, "")
+	cmd.Flags().String("webhook", "", "")
+	return cmd
+}
+
+func TestRunMonitor_Add(t *testing.T) {
+	var got ipc.MonitorParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.MonitorData{Monitors: []ipc.MonitorInfo{{
+			ID: "m1", URL: got.URL, Every: got.Every, Dir: "/tmp/webctl-monitors/m1",
+		}}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := newMonitorTestCmd()
+	_ = cmd.Flags().Set("every", "1m")
+	_ = cmd.Flags().Set("assert", "selector:#app")
+	_ = cmd.Flags().Set("screenshot", "true")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runMonitor(cmd, []string{"app.internal"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != "add" || got.URL != "https://app.internal" || got.Every != 60000 || got.Timeout != 30000 ||
+		!got.Screenshot || len(got.Assert) != 1 {
+		t.Errorf("params = %+v", got)
+	}
+	if !strings.Contains(out, "Monitoring https://app.internal every 1m as m1") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunMonitor_InvalidAssert(t *testing.T) {
+	called := false
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		called = true
+		return ipc.SuccessResponse(nil), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := newMonitorTestCmd()
+	_ = cmd.Flags().Set("assert", "#app")
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runMonitor(cmd, []string{"https://app.internal"})
+	})
+	if err == nil || called {
+		t.Errorf("err = %v, called = %v; want an error before any request", err, called)
+	}
+}
+
+func TestRunMonitorList(t *testing.T) {
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.SuccessResponse(ipc.MonitorData{Monitors: []ipc.MonitorInfo{
+			{ID: "m1", URL: "https://a.internal/", Every: 300000, Checks: 12, Failures: 1, Last: &ipc.MonitorResult{OK: true}},
+			{ID: "m2", URL: "https://b.internal/", Every: 60000, Checks: 1, Failures: 1, Last: &ipc.MonitorResult{Error: "selector:#app: no element matches #app"}},
+			{ID: "m3", URL: "https://c.internal/", Every: 5400000},
+		}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runMonitorList(monitorListCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"m1  PASS  every 5m  12 checks, 1 failed  https://a.internal/\n",
+		"m2  FAIL  every 1m  1 checks, 1 failed  https://b.internal/\n      selector:#app: no element matches #app\n",
+		"m3  WAIT  every 1h30m  0 checks, 0 failed  https://c.internal/\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunMonitorResults(t *testing.T) {
+	var got ipc.MonitorParams
+	start := time.Date(2025, 6, 1, 14, 30, 52, 0, time.Local).UnixMilli()
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.MonitorData{Results: []ipc.MonitorResult{
+			{Time: start, OK: true, DurationMs: 1200},
+			{Time: start + 300000, DurationMs: 30000, Error: "timeout", Screenshot: "/tmp/x.png"},
+		}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Int("limit", 20, "")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runMonitorResults(cmd, []string{"m1"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != "results" || got.ID != "m1" || got.Limit != 20 {
+		t.Errorf("params = %+v", got)
+	}
+	want := "2025-06-01 14:30:52  PASS  1.2s\n2025-06-01 14:35:52  FAIL  30.0s  timeout\n      /tmp/x.png\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var mouseCmd = &cobra.Command{
+	Use:   "mouse",
+	Short: "Send low-level mouse input",
+	Long: `Moves the mouse, presses and releases buttons, and turns the wheel at exact
+viewport coordinates, for gestures the selector-based commands cannot
+express: drawing on a canvas, dragging a slider, panning a map.
+
+The daemon remembers each tab's pointer position and held buttons between
+commands, starting at 0,0 with nothing held. down, up, and wheel act where
+the pointer is; move while a button is held drags.
+
+Coordinates are CSS pixels from the top-left of the viewport. Use 'box' to
+find an element's position.
+
+Subcommands:
+  move <x,y>      Move the pointer
+  down            Press a button
+  up              Release a button
+  wheel <dx,dy>   Turn the wheel (positive dy scrolls down)
+  position        Print the pointer position and held buttons
+
+Examples:
+  # Drag a slider handle 200px to the right
+  mouse move 120,340
+  mouse down
+  mouse move 320,340 --steps 20
+  mouse up
+
+  # Draw a line on a canvas
+  mouse move 50,50 && mouse down && mouse move 250,150 --steps 30 && mouse up
+
+  # Pan a map, then zoom in with the wheel
+  mouse move 400,300 && mouse down --button middle
+  mouse move 200,300 --steps 10 && mouse up --button middle
+  mouse wheel 0,-300
+
+Error cases:
+  - "invalid button" - --button is not left, middle, or right
+  - "daemon not running" - start daemon first with: webctl start`,
+}
+
+var mouseMoveCmd = &cobra.Command{
+	Use:   "move <x,y>",
+	Short: "Move the pointer",
+	Long: `Moves the pointer to a viewport position. With --steps the path from the
+current position is split into that many moves, so handlers that track the
+pointer (drawing, drag and drop, sliders) see it travel. Held buttons stay
+held, making the move a drag.
+
+Examples:
+  mouse move 100,200
+  mouse move 400,200 --steps 25
+
+Output:
+  OK (text) or {"ok": true, "x": 400, "y": 200, "buttons": ["left"]} (JSON)`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMouseMove,
+}
+
+var mouseDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Press a mouse button",
+	Long: `Presses a mouse button at the pointer position and holds it until 'mouse up'.
+
+Examples:
+  mouse down
+  mouse down --button right`,
+	Args: cobra.NoArgs,
+	RunE: runMouseButton,
+}
+
+var mouseUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Release a mouse button",
+	Long: `Releases a mouse button at the pointer position.
+
+Examples:
+  mouse up
+  mouse up --button right`,
+	Args: cobra.NoArgs,
+	RunE: runMouseButton,
+}
+
+var mouseWheelCmd = &cobra.Command{
+	Use:   "wheel <dx,dy>",
+	Short: "Turn the mouse wheel",
+	Long: `Turns the wheel at the pointer position by dx,dy CSS pixels. Positive dy
+scrolls down and positive dx right; the element under the pointer receives
+the wheel event, so maps and canvases can zoom instead of scrolling.
+
+Examples:
+  mouse wheel 0,500      # Scroll down
+  mouse wheel 0,-300     # Scroll up, or zoom in on a map`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMouseWheel,
+}
+
+var mousePositionCmd = &cobra.Command{
+	Use:   "position",
+	Short: "Print the pointer position and held buttons",
+	Long: `Prints where the pointer is, as x,y, followed by any held buttons.
+
+Output:
+  320,340 left`,
+	Args: cobra.NoArgs,
+	RunE: runMousePosition,
+}
+
+func init() {
+	mouseMoveCmd.Flags().Int("steps", 1, "Number of moves to split the path into")
+	mouseDownCmd.Flags().String("button", "left", "Button to press: left, middle, or right")
+	mouseUpCmd.Flags().String("button", "left", "Button to release: left, middle, or right")
+
+	mouseCmd.AddCommand(mouseMoveCmd, mouseDownCmd, mouseUpCmd, mouseWheelCmd, mousePositionCmd)
+	rootCmd.AddCommand(mouseCmd)
+}
+
+func runMouseMove(cmd *cobra.Command, args []string) error {
+	x, y, err := parseCoords(args[0])
+	if err != nil {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid position: %v", err))
+	}
+	steps, _ := cmd.Flags().GetInt("steps")
+	if steps < 1 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--steps must be at least 1")
+	}
+	return runMouse(ipc.MouseParams{Action: "move", X: x, Y: y, Steps: steps})
+}
+
+func runMouseButton(cmd *cobra.Command, args []string) error {
+	button, _ := cmd.Flags().GetString("button")
+	return runMouse(ipc.MouseParams{Action: cmd.Name(), Button: button})
+}
+
+func runMouseWheel(cmd *cobra.Command, args []string) error {
+	dx, dy, err := parseCoords(args[0])
+	if err != nil {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid wheel delta: %v", err))
+	}
+	return runMouse(ipc.MouseParams{Action: "wheel", DeltaX: dx, DeltaY: dy})
+}
+
+func runMousePosition(cmd *cobra.Command, args []string) error {
+	return runMouse(ipc.MouseParams{Action: "position"})
+}
+
+// runMouse sends a mouse action and reports the resulting pointer state.
+func runMouse(params ipc.MouseParams) error {
+	t := startTimer("mouse " + params.Action)
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("mouse", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "mouse", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.MouseData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		buttons := data.Buttons
+		if buttons == nil {
+			buttons = []string{}
+		}
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":      true,
+			"x":       data.X,
+			"y":       data.Y,
+			"buttons": buttons,
+		})
+	}
+
+	if params.Action == "position" {
+		line := fmt.Sprintf("%d,%d", data.X, data.Y)
+		for _, b := range data.Buttons {
+			line += " " + b
+		}
+		fmt.Fprintln(os.Stdout, line)
+		return nil
+	}
+	return outputSuccess(nil)
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// mouseTestExecutor captures the mouse params and answers with data.
+func mouseTestExecutor(got *ipc.MouseParams, data ipc.MouseData) *mockExecutor {
+	return &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		if req.Cmd != "mouse" {
+			return ipc.ErrorResponse("unexpected command " + req.Cmd), nil
+		}
+		_ = json.Unmarshal(req.Params, got)
+		return ipc.SuccessResponse(data), nil
+	}}
+}
+
+func TestRunMouseMove(t *testing.T) {
+	enableJSONOutput(t)
+	var got ipc.MouseParams
+	exec := mouseTestExecutor(&got, ipc.MouseData{X: 320, Y: 340, Buttons: []string{"left"}})
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Int("steps", 1, "")
+	_ = cmd.Flags().Set("steps", "20")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runMouseMove(cmd, []string{"320, 340"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != "move" || got.X != 320 || got.Y != 340 || got.Steps != 20 {
+		t.Errorf("params = %+v", got)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if buttons, _ := result["buttons"].([]any); result["x"] != 320.0 || len(buttons) != 1 {
+		t.Errorf("result = %v", result)
+	}
+}
+
+func TestRunMouseButton(t *testing.T) {
+	var got ipc.MouseParams
+	exec := mouseTestExecutor(&got, ipc.MouseData{Buttons: []string{"right"}})
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := &cobra.Command{Use: "down"}
+	cmd.Flags().String("button", "left", "")
+	_ = cmd.Flags().Set("button", "right")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runMouseButton(cmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != "down" || got.Button != "right" {
+		t.Errorf("params = %+v", got)
+	}
+	if out != "OK\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunMousePosition(t *testing.T) {
+	var got ipc.MouseParams
+	exec := mouseTestExecutor(&got, ipc.MouseData{X: 12, Y: 34, Buttons: []string{"left", "middle"}})
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runMousePosition(mousePositionCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "12,34 left middle\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunMouseWheel_InvalidDelta(t *testing.T) {
+	called := false
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		called = true
+		return ipc.SuccessResponse(nil), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runMouseWheel(mouseWheelCmd, []string{"300"})
+	})
+	if err == nil || called {
+		t.Errorf("err = %v, called = %v; want an error before any request", err, called)
+	}
+}
@@ -26,6 +26,12 @@ URL protocol auto-detection:
 
 Flags:
   --wait              Wait for page load completion (load event fired)
+  --until <strategy>  What "loaded" means for --wait (implies --wait):
+                        load              Load event fired (default)
+                        domcontentloaded  DOMContentLoaded fired
+                        networkidle       No requests in flight for 500ms
+                        selector:<css>    An element matches the selector
+                        js:<expr>         The expression is truthy
   --timeout <seconds> Timeout in seconds when using --wait (default 60)
 
 Examples:
@@ -46,6 +52,12 @@ Examples:
   navigate example.com --wait
   navigate slow-site.com --wait --timeout 60
 
+  # Define "ready" per app
+  navigate example.com --until domcontentloaded
+  navigate app.example.com --until networkidle
+  navigate app.example.com --until "selector:#dashboard .chart"
+  navigate app.example.com --until "js:window.appReady === true"
+
   # Common workflow patterns
   navigate example.com && ready           # Equivalent to --wait
   navigate example.com && screenshot      # Capture after navigation
@@ -58,6 +70,8 @@ Error cases:
   - "net::ERR_NAME_NOT_RESOLVED" - domain does not exist
   - "net::ERR_CONNECTION_REFUSED" - server not responding
   - "timeout waiting for page load" - page didn't load within timeout (--wait)
+  - "timeout waiting for: #dashboard" - --until condition not met within timeout
+  - "invalid --until" - unknown wait strategy
   - "daemon not running" - start daemon first with: webctl start`,
 	Args: cobra.ExactArgs(1),
 	RunE: runNavigate,
@@ -65,6 +79,7 @@ Error cases:
 
 func init() {
 	navigateCmd.Flags().Bool("wait", false, "Wait for page load completion")
+	navigateCmd.Flags().String("until", "", "Wait strategy: load, domcontentloaded, networkidle, selector:<css>, js:<expr> (implies --wait)")
 	navigateCmd.Flags().Int("timeout", 60, "Timeout in seconds (used with --wait)")
 	rootCmd.AddCommand(navigateCmd)
 }
@@ -106,17 +121,26 @@ func runNavigate(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Read flags
 	wait, _ := cmd.Flags().GetBool("wait")
 	timeout, _ := cmd.Flags().GetInt("timeout")
+	until, _ := cmd.Flags().GetString("until")
+
+	// --until defines what to wait for, so it implies --wait
+	if until != "" {
+		if _, _, err := ipc.ParseWaitUntil(until); err != nil {
+			return outputError(err.Error())
+		}
+		wait = true
+	}
 
 	// Normalize URL (add protocol if missing)
 	url := normalizeURL(args[0])
 
-	debugParam("url=%q wait=%v timeout=%d", url, wait, timeout)
+	debugParam("url=%q wait=%v until=%q timeout=%d", url, wait, until, timeout)
 
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
@@ -129,12 +153,13 @@ func runNavigate(cmd *cobra.Command, args []string) error {
 		URL:     url,
 		Wait:    wait,
 		Timeout: timeout,
+		Until:   until,
 	})
 	if err != nil {
 		return outputError(err.Error())
 	}
 
-	debugRequest("navigate", fmt.Sprintf("url=%q wait=%v timeout=%d", url, wait, timeout))
+	debugRequest("navigate", fmt.Sprintf("url=%q wait=%v until=%q timeout=%d", url, wait, until, timeout))
 	ipcStart := time.Now()
 
 	resp, err := exec.Execute(ipc.Request{
@@ -149,7 +174,7 @@ func runNavigate(cmd *cobra.Command, args []string) error {
 	}
 
 	if !resp.OK {
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: include URL and title
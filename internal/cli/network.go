@@ -28,6 +28,10 @@ Default behavior (no subcommand):
 
 Subcommands:
   save [path]       Save network requests to file (temp dir if no path given)
+  tls <n>           Show the TLS connection and certificate behind entry n
+  dupes             Report identical requests sent repeatedly, and the bytes wasted
+  diff <a> <b>      Diff the response bodies of two entries (structural for JSON)
+  abort [requestId] Cancel in-flight requests by ID, or by URL with --url
 
 Drill-down:
   network <n>       Show the single entry with seq n, rendered with its bodies
@@ -46,6 +50,8 @@ Detail dial (text only; ignored in JSON and by --schema):
 Universal flags:
   --find, -f        Search for text within URLs and bodies (narrows the list;
                     the matched body is seen by drilling into the entry)
+  --regex           Treat --find as a regular expression (Go regexp syntax)
+  --invert          Keep entries that do NOT match --find
   --headers         Show request and response headers (standard and full levels)
   --json            Output in JSON format, always full fidelity (global flag)
 
@@ -59,9 +65,20 @@ Network-specific filter flags:
   --min-duration    Minimum request duration: 1s, 500ms, 100ms
   --min-size        Minimum response size in bytes
   --failed          Show only failed requests (network errors, CORS, etc.)
+  --cached          Show only responses served from a cache or service worker
+                    (memory, disk, prefetch), which never hit the network
+  --protocol        Negotiated protocol: h3, h2, http/1.1, data, blob
+  --insecure-only   Show only requests with a security warning: insecure
+                    state, mixed content, or a certificate expiring within
+                    30 days (see network tls)
   --head N          Return first N entries (count over the seq-ordered list)
   --tail N          Return last N entries (count over the seq-ordered list)
   --range START-END Keep entries whose seq is in [START, END] inclusive
+  --since DURATION  Keep requests sent within DURATION of now (30s, 5m)
+  --after TIME      Keep requests sent at or after TIME
+  --before TIME     Keep requests sent at or before TIME
+                    TIME is a local clock time (15:04:05), an RFC 3339 date
+                    and time, or Unix milliseconds
 
 All filters are AND-combined. StringSlice flags support CSV (--status 4xx,5xx)
 and repeatable (--status 4xx --status 5xx) syntax.
@@ -74,8 +91,14 @@ List mode (stdout):
   network --detail full                    # List with bodies
   network --status 4xx                     # Only 4xx
   network --find "api"                     # Narrow to entries matching "api"
+  network --find '/v[0-9]+/' --regex       # Versioned API paths
+  network --find analytics --invert        # Everything but analytics
   network --tail 20                        # Last 20 entries
   network --range 318-425                  # Entries with seq in [318, 425]
+  network --since 30s --type xhr,fetch     # API calls from the last 30 seconds
+  network --cached --type script          # Scripts that never hit the network
+  network --protocol h3                    # Requests that went over HTTP/3
+  network --insecure-only                  # Mixed content and expiring certs
 
 Drill-down mode (stdout):
   network 42                               # Entry 42 with its bodies
@@ -94,6 +117,9 @@ Response formats:
             transport block follows on indented lines
   Drill:    the single entry with its request and response bodies
   Save:     /tmp/webctl-network/25-12-28-143052-123-network.json
+  Records:  --output jsonl|yaml|csv|table renders one request per record
+            (csv/table columns: seq, time, method, status, type, url,
+            duration_ms, size, error)
 
 Error cases:
   - "No matches found" - find text not in requests
@@ -102,8 +128,9 @@ Error cases:
 	// At most one positional argument: the bare-integer drill-down address. A
 	// stray extra token is a usage error rather than a silently discarded arg.
 	// `save` dispatches as a subcommand before this constraint applies.
-	Args: cobra.MaximumNArgs(1),
-	RunE: runNetworkDefault,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runNetworkDefault,
+	Annotations: map[string]string{recordsAnnotation: ""},
 }
 
 var networkSaveCmd = &cobra.Command{
@@ -115,11 +142,24 @@ If no path is provided, saves to temp directory with auto-generated filename.
 If path is a directory, auto-generates filename.
 If path is a file, uses exact path.
 
+Credentials are redacted so a capture can be attached to a ticket: the values
+of Authorization, Proxy-Authorization, Cookie, and Set-Cookie headers are
+replaced with [REDACTED], keeping the auth scheme, cookie names, and cookie
+attributes. --redact adds header name patterns (case-insensitive globs such
+as x-*-token); set them for every save with the config key
+defaults."network save".redact. --no-redact keeps the raw values.
+
+Flags:
+  --redact <pattern>  Also redact headers matching the pattern (repeatable, CSV-supported)
+  --no-redact         Save header values unredacted
+
 Examples:
   network save                             # Save to temp dir
   network save ./logs/requests.json        # Save to file
   network save ./output/                   # Save to dir
-  network save --status 5xx --method POST`,
+  network save --status 5xx --method POST
+  network save --redact x-api-key,x-*-token
+  network save --no-redact`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runNetworkSave,
 }
@@ -127,6 +167,8 @@ Examples:
 func init() {
 	// Universal flags on root command (inherited by default/save subcommands)
 	networkCmd.PersistentFlags().StringP("find", "f", "", "Search for text within URLs and bodies")
+	networkCmd.PersistentFlags().Bool("regex", false, "Treat --find as a regular expression")
+	networkCmd.PersistentFlags().Bool("invert", false, "Keep entries that do not match --find")
 
 	// Network-specific filter flags
 	networkCmd.PersistentFlags().StringSlice("type", nil, "Filter by CDP resource type (repeatable, CSV-supported)")
@@ -137,6 +179,9 @@ func init() {
 	networkCmd.PersistentFlags().Duration("min-duration", 0, "Filter by minimum request duration")
 	networkCmd.PersistentFlags().Int64("min-size", 0, "Filter by minimum response size in bytes")
 	networkCmd.PersistentFlags().Bool("failed", false, "Show only failed requests")
+	networkCmd.PersistentFlags().Bool("cached", false, "Show only responses served from a cache or service worker")
+	networkCmd.PersistentFlags().StringSlice("protocol", nil, "Filter by negotiated protocol: h3, h2, http/1.1 (repeatable, CSV-supported)")
+	networkCmd.PersistentFlags().Bool("insecure-only", false, "Show only requests with mixed content, an insecure state, or an expiring certificate")
 	networkCmd.PersistentFlags().Bool("headers", false, "Show request and response headers (standard and full detail levels)")
 	// Registered default is 0 so pflag omits a misleading "(default N)": the real
 	// unset default is mode-dependent and resolved via Changed, not this value.
@@ -144,6 +189,9 @@ func init() {
 	networkCmd.PersistentFlags().Int("head", 0, "Return first N entries (count over the seq-ordered list)")
 	networkCmd.PersistentFlags().Int("tail", 0, "Return last N entries (count over the seq-ordered list)")
 	networkCmd.PersistentFlags().String("range", "", "Keep entries whose seq is in [START, END] inclusive (format: START-END)")
+	networkCmd.PersistentFlags().Duration("since", 0, "Keep requests sent within this long before now (e.g. 30s, 5m)")
+	networkCmd.PersistentFlags().String("after", "", "Keep requests sent at or after this time (15:04:05, RFC 3339, or Unix ms)")
+	networkCmd.PersistentFlags().String("before", "", "Keep requests sent at or before this time (15:04:05, RFC 3339, or Unix ms)")
 	networkCmd.MarkFlagsMutuallyExclusive("head", "tail", "range")
 
 	// Text-only flags for the default (list/drill-down) command. Local rather than
@@ -151,8 +199,11 @@ func init() {
 	networkCmd.Flags().String("detail", "standard", "Text detail level: summary, standard, or full")
 	networkCmd.Flags().Bool("schema", false, "Preview an entry's JSON response body as a key skeleton (requires an entry index)")
 
+	networkSaveCmd.Flags().StringSlice("redact", nil, "Also redact headers matching the pattern (repeatable, CSV-supported)")
+	networkSaveCmd.Flags().Bool("no-redact", false, "Save header values unredacted")
+
 	// Add all subcommands
-	networkCmd.AddCommand(networkSaveCmd)
+	networkCmd.AddCommand(networkSaveCmd, networkTLSCmd, networkDupesCmd, networkDiffCmd, networkAbortCmd)
 
 	rootCmd.AddCommand(networkCmd)
 }
@@ -175,7 +226,7 @@ func runNetworkDefault(cmd *cobra.Command, args []string) error {
 	if len(args) > 0 {
 		n, err := strconv.Atoi(args[0])
 		if err != nil {
-			return outputError(fmt.Sprintf("unknown command %q for \"webctl network\"", args[0]))
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("unknown command %q for \"webctl network\"", args[0]))
 		}
 		drillSeq = n
 		hasDrill = true
@@ -183,7 +234,7 @@ func runNetworkDefault(cmd *cobra.Command, args []string) error {
 
 	// --schema is a drill-down preview; it requires an entry index.
 	if schema && !hasDrill {
-		return outputError("network --schema requires an entry index (for example: network 42 --schema)")
+		return outputCodedError(ipc.CodeInvalidArgs, "network --schema requires an entry index (for example: network 42 --schema)")
 	}
 
 	// Validate --detail up front so a malformed value is a deterministic usage
@@ -196,7 +247,7 @@ func runNetworkDefault(cmd *cobra.Command, args []string) error {
 	}
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	if hasDrill {
@@ -212,6 +263,12 @@ func runNetworkDefault(cmd *cobra.Command, args []string) error {
 		return outputError(err.Error())
 	}
 
+	// Records carry the same full-fidelity entries as JSON.
+	if recordOutput() {
+		applyBodyTruncation(entries, resolveMaxBodySize(cmd, ipc.MaxBodySizeUnlimited))
+		return format.Records(os.Stdout, Output, entries, format.NetworkColumns)
+	}
+
 	// JSON is always full fidelity: unlimited bodies unless --max-body-size is set.
 	if JSONOutput {
 		return outputNetworkJSON(entries, resolveMaxBodySize(cmd, ipc.MaxBodySizeUnlimited))
@@ -339,12 +396,18 @@ func runNetworkSave(cmd *cobra.Command, args []string) error {
 }
 
 // networkSaveContent produces the network save-file payload: the JSON envelope
-// with per-entry body truncation applied, matching the network JSON output.
+// with per-entry body truncation applied, matching the network JSON output,
+// and credential headers redacted.
 func networkSaveContent(cmd *cobra.Command) (string, error) {
+	patterns, err := networkRedactPatterns(cmd)
+	if err != nil {
+		return "", err
+	}
 	entries, err := getNetworkFromDaemon(cmd)
 	if err != nil {
 		return "", err
 	}
+	debugParam("redacted %d header values", redactNetworkEntries(entries, patterns))
 
 	// A saved file is a full-fidelity archive, so bodies are unbounded unless the
 	// caller sets an explicit --max-body-size cap.
@@ -399,7 +462,7 @@ func fetchNetworkEntries() ([]ipc.NetworkEntry, error) {
 	debugRequest("network", "")
 	ipcStart := time.Now()
 
-	resp, err := exec.Execute(ipc.Request{Cmd: "network"})
+	resp, err := executeStreamData(exec, ipc.Request{Cmd: "network"})
 
 	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
 
@@ -420,9 +483,9 @@ func fetchNetworkEntries() ([]ipc.NetworkEntry, error) {
 // getNetworkFromDaemon fetches network entries from daemon, applying filters
 func getNetworkFromDaemon(cmd *cobra.Command) ([]ipc.NetworkEntry, error) {
 	// Try to get flags from command, falling back to parent for persistent flags
-	find, _ := cmd.Flags().GetString("find")
-	if find == "" && cmd.Parent() != nil {
-		find, _ = cmd.Parent().PersistentFlags().GetString("find")
+	finder, find, err := findFromFlags(cmd)
+	if err != nil {
+		return nil, err
 	}
 
 	types, _ := cmd.Flags().GetStringSlice("type")
@@ -465,6 +528,21 @@ func getNetworkFromDaemon(cmd *cobra.Command) ([]ipc.NetworkEntry, error) {
 		failed, _ = cmd.Parent().PersistentFlags().GetBool("failed")
 	}
 
+	cached, _ := cmd.Flags().GetBool("cached")
+	if !cached && cmd.Parent() != nil {
+		cached, _ = cmd.Parent().PersistentFlags().GetBool("cached")
+	}
+
+	protocols, _ := cmd.Flags().GetStringSlice("protocol")
+	if len(protocols) == 0 && cmd.Parent() != nil {
+		protocols, _ = cmd.Parent().PersistentFlags().GetStringSlice("protocol")
+	}
+
+	insecure, _ := cmd.Flags().GetBool("insecure-only")
+	if !insecure && cmd.Parent() != nil {
+		insecure, _ = cmd.Parent().PersistentFlags().GetBool("insecure-only")
+	}
+
 	head, _ := cmd.Flags().GetInt("head")
 	if head == 0 && cmd.Parent() != nil {
 		head, _ = cmd.Parent().PersistentFlags().GetInt("head")
@@ -496,7 +574,12 @@ func getNetworkFromDaemon(cmd *cobra.Command) ([]ipc.NetworkEntry, error) {
 		return nil, err
 	}
 
-	debugParam("find=%q types=%v methods=%v statuses=%v urlPattern=%q failed=%v", find, types, methods, statuses, urlPattern, failed)
+	window, err := timeWindowFromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	debugParam("find=%q regex=%v invert=%v types=%v methods=%v statuses=%v urlPattern=%q failed=%v cached=%v protocols=%v insecure=%v window=%s", finder.pattern, finder.re != nil, finder.invert, types, methods, statuses, urlPattern, failed, cached, protocols, insecure, window)
 
 	entries, err := fetchNetworkEntries()
 	if err != nil {
@@ -511,6 +594,10 @@ func getNetworkFromDaemon(cmd *cobra.Command) ([]ipc.NetworkEntry, error) {
 		minDuration: minDuration,
 		minSize:     minSize,
 		failed:      failed,
+		cached:      cached,
+		protocols:   protocols,
+		insecure:    insecure,
+		window:      window,
 	}
 
 	// Apply filters
@@ -521,10 +608,10 @@ func getNetworkFromDaemon(cmd *cobra.Command) ([]ipc.NetworkEntry, error) {
 	}
 
 	// Apply --find filter if specified
-	if find != "" {
+	if find {
 		beforeCount := len(entries)
-		entries = filterNetworkByText(entries, find)
-		debugFilter(fmt.Sprintf("--find %q", find), beforeCount, len(entries))
+		entries = filterNetworkByText(entries, finder)
+		debugFilter(finder.String(), beforeCount, len(entries))
 		if len(entries) == 0 {
 			return nil, ErrNoMatches
 		}
@@ -539,26 +626,14 @@ func getNetworkFromDaemon(cmd *cobra.Command) ([]ipc.NetworkEntry, error) {
 	return entries, nil
 }
 
-// filterNetworkByText filters entries to only include those containing the search text in URL or body
-func filterNetworkByText(entries []ipc.NetworkEntry, searchText string) []ipc.NetworkEntry {
+// filterNetworkByText filters entries to those whose URL or bodies pass the
+// --find matcher.
+func filterNetworkByText(entries []ipc.NetworkEntry, m textMatcher) []ipc.NetworkEntry {
 	var matchedEntries []ipc.NetworkEntry
-	searchLower := strings.ToLower(searchText)
 
 	for _, entry := range entries {
-		// Search in URL
-		if strings.Contains(strings.ToLower(entry.URL), searchLower) {
-			matchedEntries = append(matchedEntries, entry)
-			continue
-		}
-		// Search in request body
-		if strings.Contains(strings.ToLower(entry.RequestBody), searchLower) {
-			matchedEntries = append(matchedEntries, entry)
-			continue
-		}
-		// Search in response body
-		if strings.Contains(strings.ToLower(entry.ResponseBody), searchLower) {
+		if m.matchAny(entry.URL, entry.RequestBody, entry.ResponseBody) {
 			matchedEntries = append(matchedEntries, entry)
-			continue
 		}
 	}
 
@@ -648,13 +723,18 @@ type networkFilterOptions struct {
 	minDuration time.Duration
 	minSize     int64
 	failed      bool
+	cached      bool
+	protocols   []string
+	insecure    bool
+	window      timeWindow
 }
 
 // filterNetworkEntries applies all network filters.
 func filterNetworkEntries(entries []ipc.NetworkEntry, urlRegex *regexp.Regexp, statusMatchers []statusMatcher, opts networkFilterOptions) []ipc.NetworkEntry {
 	if len(opts.types) == 0 && len(opts.methods) == 0 && len(statusMatchers) == 0 &&
 		urlRegex == nil && len(opts.mimes) == 0 && opts.minDuration == 0 &&
-		opts.minSize == 0 && !opts.failed {
+		opts.minSize == 0 && !opts.failed && !opts.cached && len(opts.protocols) == 0 &&
+		!opts.insecure && !opts.window.active() {
 		return entries
 	}
 
@@ -719,6 +799,26 @@ func matchesNetworkFilters(e ipc.NetworkEntry, urlRegex *regexp.Regexp, statusMa
 		return false
 	}
 
+	// Cached filter: answered without going to the network
+	if opts.cached && !e.FromMemoryCache && !e.FromDiskCache && !e.FromPrefetchCache && !e.FromServiceWorker {
+		return false
+	}
+
+	// Protocol filter
+	if len(opts.protocols) > 0 && !matchesStringSlice(e.Protocol, opts.protocols) {
+		return false
+	}
+
+	// Insecure filter
+	if opts.insecure && networkInsecureReasons(e, time.Now()) == nil {
+		return false
+	}
+
+	// Time window filter
+	if !opts.window.contains(e.RequestTime) {
+		return false
+	}
+
 	return true
 }
 
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var networkAbortCmd = &cobra.Command{
+	Use:   "abort [requestId]",
+	Short: "Cancel in-flight requests by ID or URL",
+	Long: `Cancels requests so the page sees them fail as aborted (net::ERR_ABORTED), to
+test how an app copes when a long-poll, upload, or API call is cut off.
+
+With --url, adds a rule that intercepts requests whose URL matches and
+aborts them: the next one by default, the next n with --times n, or every
+one with --times 0. --delay keeps each request pending that long first, as
+a connection dropping mid-request would; --hold keeps it pending until it
+is aborted by ID. A pattern may use * for any run of characters and ? for
+one character, and then matches the whole URL; a pattern without either
+matches anywhere in the URL.
+
+With a request ID, aborts a request being held now, by an abort rule or by
+a 'mock add --delay' mock. Without arguments, lists abort rules and held
+requests with their IDs, which are the network buffer's request IDs.
+
+Rules apply to every tab, including tabs opened later. A rule is checked
+before the mocks, and removed once it has aborted its requests.
+
+Flags:
+  --url <pattern>   Abort requests whose URL matches
+  --times <n>       Requests to abort before the rule is removed (default 1,
+                    0 for every request)
+  --delay <d>       Keep each request pending this long before aborting it
+  --hold            Keep each request pending until aborted by ID
+  --clear           Remove every abort rule; held requests proceed
+
+Examples:
+  network abort --url /api/upload                # Fail the next upload
+  network abort --url "*/poll*" --delay 5s       # Drop the next long-poll after 5s
+  network abort --url "*/poll*" --hold --times 0 # Hold every long-poll
+  network abort                                  # List rules and held requests
+  network abort 1234.56                          # Abort a held request now
+  network abort --clear
+
+Response:
+  Intercepting */poll* as a1: abort the next request after 5s
+
+Error cases:
+  - "no held request ..." - only held requests can be aborted by ID; abort
+    others by URL with --url
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runNetworkAbort,
+}
+
+func init() {
+	// --url here is an intercept pattern, not the list's regex filter
+	networkAbortCmd.Flags().String("url", "", "Abort requests whose URL matches (* any run, ? one character)")
+	networkAbortCmd.Flags().Int("times", 1, "Requests to abort before the rule is removed (0 for every request)")
+	networkAbortCmd.Flags().Duration("delay", 0, "Keep each request pending this long before aborting it")
+	networkAbortCmd.Flags().Bool("hold", false, "Keep each request pending until aborted by ID")
+	networkAbortCmd.Flags().Bool("clear", false, "Remove every abort rule; held requests proceed")
+}
+
+func runNetworkAbort(cmd *cobra.Command, args []string) error {
+	t := startTimer("network abort")
+	defer t.log()
+
+	url, _ := cmd.Flags().GetString("url")
+	times, _ := cmd.Flags().GetInt("times")
+	delay, _ := cmd.Flags().GetDuration("delay")
+	hold, _ := cmd.Flags().GetBool("hold")
+	clearRules, _ := cmd.Flags().GetBool("clear")
+	ruleFlags := cmd.Flags().Changed("times") || cmd.Flags().Changed("delay") || hold
+
+	switch {
+	case len(args) == 1 && (url != "" || clearRules || ruleFlags):
+		return outputCodedError(ipc.CodeInvalidArgs, "give a request ID or --url, not both")
+	case clearRules && (url != "" || ruleFlags):
+		return outputCodedError(ipc.CodeInvalidArgs, "--clear takes no other flags")
+	case url == "" && ruleFlags:
+		return outputCodedError(ipc.CodeInvalidArgs, "--times, --delay, and --hold need --url")
+	case times < 0:
+		return outputCodedError(ipc.CodeInvalidArgs, "--times must not be negative")
+	case delay < 0:
+		return outputCodedError(ipc.CodeInvalidArgs, "--delay must not be negative")
+	case hold && delay > 0:
+		return outputCodedError(ipc.CodeInvalidArgs, "use --delay or --hold, not both")
+	}
+
+	switch {
+	case len(args) == 1:
+		debugParam("requestId=%q", args[0])
+		data, err := abortRequest(ipc.AbortParams{Action: "request", RequestID: args[0]})
+		if err != nil {
+			return err
+		}
+		if len(data.Held) == 0 {
+			return outputError("daemon did not return the request")
+		}
+		if JSONOutput {
+			return outputJSON(os.Stdout, map[string]any{
+				"ok":      true,
+				"aborted": data.Held[0],
+			})
+		}
+		fmt.Fprintf(os.Stdout, "Aborted %s %s\n", data.Held[0].RequestID, data.Held[0].URL)
+		return nil
+
+	case url != "":
+		debugParam("url=%q times=%d delay=%s hold=%t", url, times, delay, hold)
+		data, err := abortRequest(ipc.AbortParams{
+			Action: "add",
+			URL:    url,
+			Times:  times,
+			Delay:  delay.Milliseconds(),
+			Hold:   hold,
+		})
+		if err != nil {
+			return err
+		}
+		if len(data.Rules) == 0 {
+			return outputError("daemon did not return the rule")
+		}
+		r := data.Rules[0]
+		if JSONOutput {
+			return outputJSON(os.Stdout, map[string]any{
+				"ok":   true,
+				"rule": r,
+			})
+		}
+		fmt.Fprintf(os.Stdout, "Intercepting %s as %s: %s\n", r.URL, r.ID, format.AbortEffect(r))
+		return nil
+
+	case clearRules:
+		if _, err := abortRequest(ipc.AbortParams{Action: "clear"}); err != nil {
+			return err
+		}
+		return outputSuccess(nil)
+	}
+
+	data, err := abortRequest(ipc.AbortParams{Action: "list"})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":    true,
+			"rules": data.Rules,
+			"held":  data.Held,
+		})
+	}
+	return format.Aborts(os.Stdout, data)
+}
+
+// abortRequest sends an abort action to the daemon.
+func abortRequest(params ipc.AbortParams) (ipc.AbortData, error) {
+	var data ipc.AbortData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("abort", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "abort", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunNetworkAbort_AddRule(t *testing.T) {
+	resetFlags(t, networkAbortCmd.Flags(), "url", "times", "delay", "hold", "clear")
+	var got ipc.AbortParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.AbortData{Rules: []ipc.AbortRule{{
+			ID: "a1", URL: "*/poll*", Delay: got.Delay, Times: got.Times,
+		}}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	_ = networkAbortCmd.Flags().Set("url", "/poll")
+	_ = networkAbortCmd.Flags().Set("delay", "5s")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runNetworkAbort(networkAbortCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != "add" || got.URL != "/poll" || got.Delay != 5000 || got.Times != 1 || got.Hold {
+		t.Errorf("params = %+v", got)
+	}
+	if out != "Intercepting */poll* as a1: abort the next request after 5s\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunNetworkAbort_RequestID(t *testing.T) {
+	resetFlags(t, networkAbortCmd.Flags(), "url", "times", "delay", "hold", "clear")
+	var got ipc.AbortParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.AbortData{Held: []ipc.HeldRequest{{
+			RequestID: got.RequestID, URL: "https://app.test/poll", By: "a1",
+		}}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runNetworkAbort(networkAbortCmd, []string{"1234.56"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != "request" || got.RequestID != "1234.56" {
+		t.Errorf("params = %+v", got)
+	}
+	if out != "Aborted 1234.56 https://app.test/poll\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunNetworkAbort_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		flags map[string]string
+		want  string
+	}{
+		{"id and url", []string{"1.1"}, map[string]string{"url": "/poll"}, "not both"},
+		{"clear and url", nil, map[string]string{"clear": "true", "url": "/poll"}, "--clear takes no other flags"},
+		{"hold without url", nil, map[string]string{"hold": "true"}, "need --url"},
+		{"negative times", nil, map[string]string{"url": "/poll", "times": "-1"}, "--times must not be negative"},
+		{"delay and hold", nil, map[string]string{"url": "/poll", "delay": "1s", "hold": "true"}, "not both"},
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, networkAbortCmd.Flags(), "url", "times", "delay", "hold", "clear")
+			for name, value := range tt.flags {
+				if err := networkAbortCmd.Flags().Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var err error
+			_ = captureStream(t, &os.Stderr, func() {
+				err = runNetworkAbort(networkAbortCmd, tt.args)
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.want) || ErrorCode(err) != ipc.CodeInvalidArgs {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
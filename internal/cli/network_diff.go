@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/grantcarthew/webctl/internal/jsondiff"
+	"github.com/grantcarthew/webctl/internal/linediff"
+	"github.com/spf13/cobra"
+)
+
+var networkDiffCmd = &cobra.Command{
+	Use:   "diff <a> <b>",
+	Short: "Diff the response bodies of two captured requests",
+	Long: `Compares the response bodies of two captured requests, addressed by seq
+or request id, for when the same endpoint returns different data before and
+after a change.
+
+When both bodies are JSON the diff is structural: each added, removed, or
+changed value is listed by its path, so key order and formatting do not
+matter. Otherwise the bodies are compared line by line as a unified diff.
+
+Like diff(1), the exit code is 0 when the bodies are identical and 1 when
+they differ.
+
+Flags:
+  --unified, -U <n> Lines of context for a text diff (default 3)
+
+Examples:
+  network diff 12 31
+  network diff 12 31 --json
+
+Output (JSON bodies):
+  --- 12 GET https://app.test/api/user
+  +++ 31 GET https://app.test/api/user
+  ~ $.user.name: "Ann" → "Anne"
+  + $.user.roles[2]: "admin"
+  - $.meta.cached: true
+
+JSON output:
+  {"ok": false, "identical": false, "mode": "json", "changes": [{"op": "changed",
+   "path": "$.user.name", "from": "\"Ann\"", "to": "\"Anne\""}]}
+
+Error cases:
+  - "entry <n> not in buffer" - the seq or request id is not held
+  - "no captured response body" - binary, not yet loaded, or not captured`,
+	Args: cobra.ExactArgs(2),
+	RunE: runNetworkDiff,
+}
+
+func init() {
+	networkDiffCmd.Flags().IntP("unified", "U", 3, "Lines of context for a text diff")
+}
+
+func runNetworkDiff(cmd *cobra.Command, args []string) error {
+	t := startTimer("network diff")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	contextLines, _ := cmd.Flags().GetInt("unified")
+	if contextLines < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--unified must be 0 or more")
+	}
+
+	entries, err := fetchNetworkEntries()
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	var sides [2]*ipc.NetworkEntry
+	for i, addr := range args {
+		e, ok := findNetworkEntry(entries, addr)
+		if !ok {
+			return outputError(fmt.Sprintf("entry %s not in buffer (run network to list)", addr))
+		}
+		if e.ResponseBody == "" {
+			return outputError(fmt.Sprintf("no captured response body for entry %d %s", e.Seq, e.URL))
+		}
+		sides[i] = e
+	}
+	a, b := sides[0], sides[1]
+	nameA := fmt.Sprintf("%d %s %s", a.Seq, a.Method, a.URL)
+	nameB := fmt.Sprintf("%d %s %s", b.Seq, b.Method, b.URL)
+
+	docA, errA := jsondiff.Parse(a.ResponseBody)
+	docB, errB := jsondiff.Parse(b.ResponseBody)
+	if errA == nil && errB == nil {
+		changes := jsondiff.Compare(docA, docB)
+		if JSONOutput {
+			if changes == nil {
+				changes = []jsondiff.Change{}
+			}
+			if err := outputJSON(os.Stdout, map[string]any{
+				"ok":        len(changes) == 0,
+				"identical": len(changes) == 0,
+				"mode":      "json",
+				"changes":   changes,
+			}); err != nil {
+				return err
+			}
+		} else if len(changes) > 0 {
+			fmt.Printf("--- %s\n+++ %s\n", nameA, nameB)
+			for _, c := range changes {
+				switch c.Op {
+				case jsondiff.Added:
+					fmt.Printf("+ %s: %s\n", c.Path, c.To)
+				case jsondiff.Removed:
+					fmt.Printf("- %s: %s\n", c.Path, c.From)
+				default:
+					fmt.Printf("~ %s: %s → %s\n", c.Path, c.From, c.To)
+				}
+			}
+		}
+		if len(changes) > 0 {
+			return printedError{err: errors.New("response bodies differ")}
+		}
+		return nil
+	}
+
+	diff := linediff.Unified(nameA, nameB,
+		strings.Split(a.ResponseBody, "\n"), strings.Split(b.ResponseBody, "\n"), contextLines)
+
+	if JSONOutput {
+		if err := outputJSON(os.Stdout, map[string]any{
+			"ok":        diff == "",
+			"identical": diff == "",
+			"mode":      "text",
+			"diff":      diff,
+		}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(diff)
+	}
+
+	if diff != "" {
+		return printedError{err: errors.New("response bodies differ")}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func networkDiffExecutor(entries ...ipc.NetworkEntry) *mockExecutor {
+	return &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.SuccessResponse(ipc.NetworkData{Entries: entries, Count: len(entries)}), nil
+	}}
+}
+
+func TestRunNetworkDiff_JSONBodies(t *testing.T) {
+	exec := networkDiffExecutor(
+		ipc.NetworkEntry{Seq: 12, Method: "GET", URL: "https://app.test/api/user", ResponseBody: `{"name":"Ann","cached":true}`},
+		ipc.NetworkEntry{Seq: 31, Method: "GET", URL: "https://app.test/api/user", ResponseBody: "{\n  \"name\": \"Anne\",\n  \"roles\": [\"admin\"]\n}"},
+	)
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runNetworkDiff(networkDiffCmd, []string{"12", "31"})
+	})
+	if err == nil || !IsPrintedError(err) {
+		t.Fatalf("err = %v, want a printed difference", err)
+	}
+	want := `--- 12 GET https://app.test/api/user
++++ 31 GET https://app.test/api/user
+- $.cached: true
+~ $.name: "Ann" → "Anne"
++ $.roles: ["admin"]
+`
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestRunNetworkDiff_TextFallback(t *testing.T) {
+	enableJSONOutput(t)
+	exec := networkDiffExecutor(
+		ipc.NetworkEntry{Seq: 1, Method: "GET", URL: "https://app.test/", ResponseBody: "<p>one</p>\n<p>two</p>"},
+		ipc.NetworkEntry{Seq: 2, Method: "GET", URL: "https://app.test/", ResponseBody: "<p>one</p>\n<p>three</p>"},
+	)
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runNetworkDiff(networkDiffCmd, []string{"1", "2"})
+	})
+	if err == nil {
+		t.Fatal("expected differing bodies to fail")
+	}
+	var result struct {
+		Identical bool   `json:"identical"`
+		Mode      string `json:"mode"`
+		Diff      string `json:"diff"`
+	}
+	if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+		t.Fatalf("invalid JSON: %v\n%s", jerr, out)
+	}
+	if result.Identical || result.Mode != "text" || !strings.Contains(result.Diff, "-<p>two</p>\n+<p>three</p>") {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestRunNetworkDiff_Identical(t *testing.T) {
+	exec := networkDiffExecutor(
+		ipc.NetworkEntry{Seq: 1, ResponseBody: `{"a":1,"b":2}`},
+		ipc.NetworkEntry{Seq: 2, ResponseBody: `{"b":2,"a":1}`},
+	)
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runNetworkDiff(networkDiffCmd, []string{"1", "2"})
+	})
+	if err != nil || out != "" {
+		t.Errorf("out = %q, err = %v; want identical", out, err)
+	}
+}
+
+func TestRunNetworkDiff_NoBody(t *testing.T) {
+	exec := networkDiffExecutor(ipc.NetworkEntry{Seq: 1, ResponseBody: "x"}, ipc.NetworkEntry{Seq: 2, URL: "https://app.test/logo.png"})
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runNetworkDiff(networkDiffCmd, []string{"1", "2"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "no captured response body") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var networkDupesCmd = &cobra.Command{
+	Use:   "dupes",
+	Short: "Report requests the page sent more than once",
+	Long: `Groups identical requests, the same method, URL, and request body, that
+were sent close together, and reports how often each was repeated and the
+response bytes the repeats cost. It is the quickest way to find an effect
+loop or a missing cache hammering an API.
+
+Requests join a group while each one follows the previous within --window
+(default 5s); a longer pause starts a new group. The first request of a
+group is the one the page needed, so the rest count as wasted.
+
+The network filter flags apply first, so --type xhr,fetch narrows the report
+to API calls.
+
+Flags:
+  --window <d>      Longest gap between repeats in one group (default 5s)
+  --min <n>         Only report groups of at least n requests (default 2)
+
+Examples:
+  network dupes
+  network dupes --type xhr,fetch --window 1s
+  network dupes --min 5 --json
+
+Output:
+  12x GET https://app.test/api/user in 1.8s, 45.1KB wasted
+      seq: 14, 17, 21, 22, 25, 28, 31, 33, 36, 40, 41, 44
+  1 group, 11 repeated requests, 45.1KB wasted`,
+	Args: cobra.NoArgs,
+	RunE: runNetworkDupes,
+}
+
+func init() {
+	networkDupesCmd.Flags().Duration("window", 5*time.Second, "Longest gap between repeats in one group")
+	networkDupesCmd.Flags().Int("min", 2, "Only report groups of at least this many requests")
+}
+
+// networkDupeGroup is a burst of identical requests.
+type networkDupeGroup struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// BodyHash is the start of the request body's SHA-256, empty without a body.
+	BodyHash    string   `json:"bodyHash,omitempty"`
+	Count       int      `json:"count"`
+	WastedBytes int64    `json:"wastedBytes"`
+	SpanMs      int64    `json:"spanMs"`
+	Seqs        []uint64 `json:"seqs"`
+}
+
+func runNetworkDupes(cmd *cobra.Command, args []string) error {
+	t := startTimer("network dupes")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	window, _ := cmd.Flags().GetDuration("window")
+	minCount, _ := cmd.Flags().GetInt("min")
+	if window <= 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--window must be positive")
+	}
+	if minCount < 2 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--min must be at least 2")
+	}
+	debugParam("window=%v min=%d", window, minCount)
+
+	entries, err := getNetworkFromDaemon(cmd)
+	if err != nil && !errors.Is(err, ErrNoMatches) {
+		return outputError(err.Error())
+	}
+
+	groups := findNetworkDupes(entries, window, minCount)
+	var wasted int64
+	repeats := 0
+	for _, g := range groups {
+		wasted += g.WastedBytes
+		repeats += g.Count - 1
+	}
+
+	if JSONOutput {
+		if groups == nil {
+			groups = []networkDupeGroup{}
+		}
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":          true,
+			"groups":      groups,
+			"wastedBytes": wasted,
+		})
+	}
+
+	if len(groups) == 0 {
+		fmt.Fprintln(os.Stdout, "No duplicate requests")
+		return nil
+	}
+	for _, g := range groups {
+		fmt.Fprintf(os.Stdout, "%dx %s %s in %s, %s wasted\n",
+			g.Count, g.Method, g.URL, time.Duration(g.SpanMs)*time.Millisecond, format.Bytes(g.WastedBytes))
+		seqs := make([]string, len(g.Seqs))
+		for i, s := range g.Seqs {
+			seqs[i] = strconv.FormatUint(s, 10)
+		}
+		fmt.Fprintf(os.Stdout, "    seq: %s\n", strings.Join(seqs, ", "))
+	}
+	noun := "groups"
+	if len(groups) == 1 {
+		noun = "group"
+	}
+	fmt.Fprintf(os.Stdout, "%d %s, %d repeated requests, %s wasted\n", len(groups), noun, repeats, format.Bytes(wasted))
+	return nil
+}
+
+// findNetworkDupes groups entries by method, URL, and request body, splits
+// each group wherever the gap between consecutive requests exceeds window,
+// and returns the bursts of at least minCount requests, most repeated first.
+func findNetworkDupes(entries []ipc.NetworkEntry, window time.Duration, minCount int) []networkDupeGroup {
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b ipc.NetworkEntry) int {
+		return cmp.Compare(a.RequestTime, b.RequestTime)
+	})
+
+	type burst struct {
+		group networkDupeGroup
+		first int64
+		last  int64
+	}
+	open := map[string]*burst{}
+	var groups []networkDupeGroup
+	closeBurst := func(b *burst) {
+		if b.group.Count >= minCount {
+			b.group.SpanMs = b.last - b.first
+			groups = append(groups, b.group)
+		}
+	}
+
+	for _, e := range sorted {
+		var bodyHash string
+		if e.RequestBody != "" {
+			sum := sha256.Sum256([]byte(e.RequestBody))
+			bodyHash = hex.EncodeToString(sum[:6])
+		}
+		key := e.Method + " " + e.URL + " " + bodyHash
+
+		b := open[key]
+		if b != nil && time.Duration(e.RequestTime-b.last)*time.Millisecond > window {
+			closeBurst(b)
+			b = nil
+		}
+		if b == nil {
+			b = &burst{
+				group: networkDupeGroup{Method: e.Method, URL: e.URL, BodyHash: bodyHash},
+				first: e.RequestTime,
+			}
+			open[key] = b
+		} else {
+			b.group.WastedBytes += e.Size
+		}
+		b.group.Count++
+		b.group.Seqs = append(b.group.Seqs, e.Seq)
+		b.last = e.RequestTime
+	}
+	for _, b := range open {
+		closeBurst(b)
+	}
+
+	slices.SortFunc(groups, func(a, b networkDupeGroup) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		if a.WastedBytes != b.WastedBytes {
+			return cmp.Compare(b.WastedBytes, a.WastedBytes)
+		}
+		return cmp.Compare(a.Seqs[0], b.Seqs[0])
+	})
+	return groups
+}
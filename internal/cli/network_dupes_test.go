@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestFindNetworkDupes(t *testing.T) {
+	entries := []ipc.NetworkEntry{
+		{Seq: 1, Method: "GET", URL: "https://app.test/api/user", RequestTime: 1000, Size: 500},
+		{Seq: 2, Method: "GET", URL: "https://app.test/api/user", RequestTime: 1200, Size: 500},
+		{Seq: 3, Method: "POST", URL: "https://app.test/api/search", RequestBody: `{"q":"a"}`, RequestTime: 1300, Size: 100},
+		{Seq: 4, Method: "POST", URL: "https://app.test/api/search", RequestBody: `{"q":"b"}`, RequestTime: 1400, Size: 100},
+		{Seq: 5, Method: "GET", URL: "https://app.test/api/user", RequestTime: 1500, Size: 500},
+		{Seq: 6, Method: "POST", URL: "https://app.test/api/search", RequestBody: `{"q":"a"}`, RequestTime: 1600, Size: 100},
+		// After a pause longer than the window: a new burst of one
+		{Seq: 7, Method: "GET", URL: "https://app.test/api/user", RequestTime: 9000, Size: 500},
+		{Seq: 8, Method: "GET", URL: "https://app.test/logo.png", RequestTime: 1100, Size: 9000},
+	}
+
+	groups := findNetworkDupes(entries, 2*time.Second, 2)
+	if len(groups) != 2 {
+		t.Fatalf("groups = %+v, want 2", groups)
+	}
+	user := groups[0]
+	if user.URL != "https://app.test/api/user" || user.Count != 3 || user.WastedBytes != 1000 ||
+		user.SpanMs != 500 || !slices.Equal(user.Seqs, []uint64{1, 2, 5}) {
+		t.Errorf("user group = %+v", user)
+	}
+	search := groups[1]
+	if search.Method != "POST" || search.Count != 2 || search.BodyHash == "" || !slices.Equal(search.Seqs, []uint64{3, 6}) {
+		t.Errorf("search group = %+v; bodies that differ must not be grouped", search)
+	}
+
+	if groups := findNetworkDupes(entries, 2*time.Second, 3); len(groups) != 1 {
+		t.Errorf("--min 3 groups = %+v, want only the user group", groups)
+	}
+}
+
+func TestRunNetworkDupes_Text(t *testing.T) {
+	entries := []ipc.NetworkEntry{
+		{Seq: 4, Method: "GET", URL: "https://app.test/api/user", RequestTime: 1000, Size: 2048},
+		{Seq: 5, Method: "GET", URL: "https://app.test/api/user", RequestTime: 1800, Size: 2048},
+	}
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.SuccessResponse(ipc.NetworkData{Entries: entries, Count: len(entries)}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runNetworkDupes(networkDupesCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "2x GET https://app.test/api/user in 800ms, 2.0KB wasted\n    seq: 4, 5\n1 group, 1 repeated requests, 2.0KB wasted\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+
+	entries = entries[:1]
+	out = captureStream(t, &os.Stdout, func() {
+		err = runNetworkDupes(networkDupesCmd, nil)
+	})
+	if err != nil || !strings.HasPrefix(out, "No duplicate requests") {
+		t.Errorf("output = %q, err = %v", out, err)
+	}
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// redactedValue replaces a secret in a saved header.
+const redactedValue = "[REDACTED]"
+
+// defaultRedactHeaders are the headers network save always redacts unless
+// --no-redact is given: credentials a capture attached to a ticket must not
+// leak.
+var defaultRedactHeaders = []string{"authorization", "proxy-authorization", "cookie", "set-cookie"}
+
+// networkRedactPatterns returns the header name patterns network save
+// redacts: the defaults plus any --redact patterns, lower-cased, or nil with
+// --no-redact. Patterns are path.Match globs, checked here so a typo fails
+// before anything is written.
+func networkRedactPatterns(cmd *cobra.Command) ([]string, error) {
+	if noRedact, _ := cmd.Flags().GetBool("no-redact"); noRedact {
+		return nil, nil
+	}
+	extra, _ := cmd.Flags().GetStringSlice("redact")
+	patterns := append([]string{}, defaultRedactHeaders...)
+	for _, p := range extra {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid --redact pattern %q: %v", p, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// redactNetworkEntries masks, in place, the request and response headers of
+// entries whose name matches one of patterns. It returns the number of
+// header values masked.
+func redactNetworkEntries(entries []ipc.NetworkEntry, patterns []string) int {
+	if len(patterns) == 0 {
+		return 0
+	}
+	n := 0
+	for i := range entries {
+		n += redactHeaders(entries[i].RequestHeaders, patterns)
+		n += redactHeaders(entries[i].ResponseHeaders, patterns)
+	}
+	return n
+}
+
+func redactHeaders(headers map[string]string, patterns []string) int {
+	n := 0
+	for name, value := range headers {
+		lower := strings.ToLower(name)
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, lower); ok {
+				headers[name] = redactHeaderValue(lower, value)
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// redactHeaderValue masks one header value, keeping the parts that help
+// debugging without being secret: the Authorization scheme, cookie names,
+// and Set-Cookie attributes. Any other header is masked whole.
+func redactHeaderValue(name, value string) string {
+	switch name {
+	case "authorization", "proxy-authorization":
+		if scheme, _, ok := strings.Cut(value, " "); ok {
+			return scheme + " " + redactedValue
+		}
+	case "cookie":
+		pairs := strings.Split(value, ";")
+		for i, pair := range pairs {
+			pairs[i] = redactCookiePair(pair)
+		}
+		return strings.Join(pairs, ";")
+	case "set-cookie":
+		// CDP joins repeated Set-Cookie headers with newlines; only the leading
+		// name=value of each carries the secret.
+		lines := strings.Split(value, "\n")
+		for i, line := range lines {
+			pair, attrs, hasAttrs := strings.Cut(line, ";")
+			lines[i] = redactCookiePair(pair)
+			if hasAttrs {
+				lines[i] += ";" + attrs
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	return redactedValue
+}
+
+// redactCookiePair masks the value of a name=value cookie pair, keeping any
+// surrounding whitespace.
+func redactCookiePair(pair string) string {
+	name, _, ok := strings.Cut(pair, "=")
+	if !ok {
+		return redactedValue
+	}
+	return name + "=" + redactedValue
+}
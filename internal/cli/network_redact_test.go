@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/pflag"
+)
+
+func TestRedactNetworkEntries(t *testing.T) {
+	entries := []ipc.NetworkEntry{{
+		RequestHeaders: map[string]string{
+			"Authorization": "Bearer eyJhbGciOi.secret",
+			"Cookie":        "session=abc123; theme=dark",
+			"X-Api-Key":     "k-999",
+			"Accept":        "application/json",
+		},
+		ResponseHeaders: map[string]string{
+			"Set-Cookie":   "session=def456; Path=/; HttpOnly\nid=7; Secure",
+			"Content-Type": "application/json",
+		},
+	}}
+
+	n := redactNetworkEntries(entries, append(defaultRedactHeaders, "x-*-key"))
+	if n != 4 {
+		t.Errorf("redacted %d values, want 4", n)
+	}
+	want := map[string]string{
+		"Authorization": "Bearer [REDACTED]",
+		"Cookie":        "session=[REDACTED]; theme=[REDACTED]",
+		"X-Api-Key":     "[REDACTED]",
+		"Accept":        "application/json",
+	}
+	for name, v := range want {
+		if got := entries[0].RequestHeaders[name]; got != v {
+			t.Errorf("%s = %q, want %q", name, got, v)
+		}
+	}
+	if got := entries[0].ResponseHeaders["Set-Cookie"]; got != "session=[REDACTED]; Path=/; HttpOnly\nid=[REDACTED]; Secure" {
+		t.Errorf("Set-Cookie = %q", got)
+	}
+	if got := entries[0].ResponseHeaders["Content-Type"]; got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
+
+func TestNetworkSaveContent_Redaction(t *testing.T) {
+	flags := networkSaveCmd.Flags()
+	reset := func() {
+		_ = flags.Lookup("redact").Value.(pflag.SliceValue).Replace(nil)
+		_ = flags.Set("no-redact", "false")
+		flags.Lookup("redact").Changed = false
+		flags.Lookup("no-redact").Changed = false
+	}
+	reset()
+	t.Cleanup(reset)
+
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		entries := []ipc.NetworkEntry{{Seq: 1, URL: "https://app.test/api", RequestHeaders: map[string]string{
+			"Authorization": "Token s3cret",
+			"X-Csrf-Token":  "t0k",
+		}}}
+		return ipc.SuccessResponse(ipc.NetworkData{Entries: entries, Count: 1}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  []string
+		leak  []string
+	}{
+		{"defaults", nil, []string{"Token [REDACTED]", "t0k"}, []string{"s3cret"}},
+		{"custom pattern", map[string]string{"redact": "X-*-Token"}, []string{"Token [REDACTED]"}, []string{"s3cret", "t0k"}},
+		{"no redact", map[string]string{"no-redact": "true"}, []string{"Token s3cret", "t0k"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reset()
+			for k, v := range tt.flags {
+				_ = flags.Set(k, v)
+			}
+			content, err := networkSaveContent(networkSaveCmd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !json.Valid([]byte(content)) {
+				t.Fatalf("invalid JSON: %s", content)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(content, w) {
+					t.Errorf("missing %q in %s", w, content)
+				}
+			}
+			for _, l := range tt.leak {
+				if strings.Contains(content, l) {
+					t.Errorf("leaked %q in %s", l, content)
+				}
+			}
+		})
+	}
+
+	reset()
+	_ = flags.Set("redact", "x-[")
+	if _, err := networkSaveContent(networkSaveCmd); err == nil || !strings.Contains(err.Error(), "invalid --redact pattern") {
+		t.Errorf("err = %v, want invalid pattern", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -144,7 +145,7 @@ func TestFilterNetworkByText_MatchesRequestBody(t *testing.T) {
 		{URL: "https://api.example.com/other", ResponseBody: `{"result":"ok"}`},
 	}
 
-	matched := filterNetworkByText(entries, "grant")
+	matched := filterNetworkByText(entries, substringMatcher("grant"))
 	if len(matched) != 1 {
 		t.Fatalf("expected 1 match, got %d", len(matched))
 	}
@@ -159,7 +160,7 @@ func TestFilterNetworkByText_StillMatchesResponseBody(t *testing.T) {
 		{URL: "https://api.example.com/other", ResponseBody: `{"token":"abc123"}`},
 	}
 
-	matched := filterNetworkByText(entries, "abc123")
+	matched := filterNetworkByText(entries, substringMatcher("abc123"))
 	if len(matched) != 1 {
 		t.Fatalf("expected 1 match, got %d", len(matched))
 	}
@@ -386,13 +387,23 @@ func captureStream(t *testing.T, stream **os.File, fn func()) string {
 	}
 	*stream = w
 	// Restore via defer so a t.Fatalf inside fn (which unwinds via runtime.Goexit)
-	// cannot leave the process stream pointed at a closed pipe.
-	defer func() { *stream = old }()
+	// cannot leave the process stream pointed at a closed pipe. Closing the
+	// writer there also ends the reader below.
+	defer func() {
+		*stream = old
+		_ = w.Close()
+	}()
+
+	// Drain concurrently so output larger than the pipe buffer cannot block fn
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		_, _ = buf.ReadFrom(r)
+		close(done)
+	}()
 	fn()
 	_ = w.Close()
-
-	var buf bytes.Buffer
-	_, _ = buf.ReadFrom(r)
+	<-done
 	return buf.String()
 }
 
@@ -659,3 +670,30 @@ func TestRunNetwork_SchemaMissNamesBounds(t *testing.T) {
 		t.Errorf("a miss must not emit a schema envelope:\n%s", out)
 	}
 }
+
+func TestFilterNetworkEntries_CachedAndProtocol(t *testing.T) {
+	entries := []ipc.NetworkEntry{
+		{Seq: 1, Protocol: "h2"},
+		{Seq: 2, Protocol: "h3"},
+		{Seq: 3, Protocol: "h2", FromDiskCache: true},
+		{Seq: 4, FromMemoryCache: true},
+		{Seq: 5, Protocol: "HTTP/1.1", FromServiceWorker: true},
+	}
+	seqs := func(es []ipc.NetworkEntry) []uint64 {
+		var out []uint64
+		for _, e := range es {
+			out = append(out, e.Seq)
+		}
+		return out
+	}
+
+	if got := seqs(filterNetworkEntries(entries, nil, nil, networkFilterOptions{cached: true})); !slices.Equal(got, []uint64{3, 4, 5}) {
+		t.Errorf("--cached kept %v, want [3 4 5]", got)
+	}
+	if got := seqs(filterNetworkEntries(entries, nil, nil, networkFilterOptions{protocols: []string{"h3", "http/1.1"}})); !slices.Equal(got, []uint64{2, 5}) {
+		t.Errorf("--protocol h3,http/1.1 kept %v, want [2 5]", got)
+	}
+	if got := seqs(filterNetworkEntries(entries, nil, nil, networkFilterOptions{cached: true, protocols: []string{"h2"}})); !slices.Equal(got, []uint64{3}) {
+		t.Errorf("--cached --protocol h2 kept %v, want [3]", got)
+	}
+}
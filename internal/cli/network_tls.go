@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// certExpiryWarning is how close to expiry a certificate is flagged by
+// network tls and --insecure-only.
+const certExpiryWarning = 30 * 24 * time.Hour
+
+var networkTLSCmd = &cobra.Command{
+	Use:   "tls <n|requestId>",
+	Short: "Show the TLS connection and certificate behind a request",
+	Long: `Shows the TLS details of one captured request: protocol version, cipher,
+key exchange, and the certificate's subject, alternative names, issuer, and
+validity, followed by any security warnings.
+
+The request is addressed by its seq, as printed by network, or by its CDP
+request id. Like drill-down, it ignores the filter and range flags.
+
+Warnings:
+  - the page's security state is insecure
+  - mixed content: an HTTPS page loaded the resource over HTTP
+  - the certificate has expired, or expires within 30 days
+
+Use "network --insecure-only" to list every request with a warning.
+
+Examples:
+  network tls 12
+  network tls 1234.56 --json
+
+Output:
+  seq 12 GET https://example.com/
+  protocol:     TLS 1.3
+  cipher:       AES_128_GCM
+  key exchange: X25519
+  subject:      example.com
+  sans:         example.com, www.example.com
+  issuer:       R11
+  valid:        2026-08-01 to 2026-10-30 (expires in 14 days)
+  warning:      certificate expires in 14 days
+
+Error cases:
+  - "entry <n> not in buffer" - the seq or request id is not held
+  - "no TLS details" - plain HTTP, or a response served from a cache`,
+	Args: cobra.ExactArgs(1),
+	RunE: runNetworkTLS,
+}
+
+func runNetworkTLS(cmd *cobra.Command, args []string) error {
+	t := startTimer("network tls")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	entries, err := fetchNetworkEntries()
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	entry, ok := findNetworkEntry(entries, args[0])
+	if !ok {
+		if n, err := strconv.Atoi(args[0]); err == nil {
+			return outputError(networkDrilldownMissMessage(n, entries))
+		}
+		return outputError(fmt.Sprintf("request %s not in buffer (run network to list)", args[0]))
+	}
+	if entry.Security == nil {
+		return outputError(fmt.Sprintf("no TLS details for entry %d %s (plain HTTP, cached, or still loading)", entry.Seq, entry.URL))
+	}
+
+	warnings := networkInsecureReasons(*entry, time.Now())
+	if warnings == nil {
+		warnings = []string{}
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":            true,
+			"seq":           entry.Seq,
+			"requestId":     entry.RequestID,
+			"url":           entry.URL,
+			"securityState": entry.SecurityState,
+			"security":      entry.Security,
+			"warnings":      warnings,
+		})
+	}
+
+	s := entry.Security
+	fmt.Fprintf(os.Stdout, "seq %d %s %s\n", entry.Seq, entry.Method, entry.URL)
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(os.Stdout, "%-13s %s\n", label+":", value)
+		}
+	}
+	line("protocol", s.Protocol)
+	line("cipher", s.Cipher)
+	line("key exchange", s.KeyExchange)
+	line("subject", s.SubjectName)
+	line("sans", strings.Join(s.SANs, ", "))
+	line("issuer", s.Issuer)
+	line("valid", certValidity(s, time.Now()))
+	for _, w := range warnings {
+		line("warning", w)
+	}
+	return nil
+}
+
+// findNetworkEntry resolves a tls address: a seq, or else a CDP request id.
+// Redirect hops share a request id, so the newest hop, which carries the
+// final response, is the one returned.
+func findNetworkEntry(entries []ipc.NetworkEntry, addr string) (*ipc.NetworkEntry, bool) {
+	if n, err := strconv.Atoi(addr); err == nil {
+		if e, ok := findNetworkEntryBySeq(entries, n); ok {
+			return e, true
+		}
+	}
+	var found *ipc.NetworkEntry
+	for i := range entries {
+		if entries[i].RequestID == addr && (found == nil || entries[i].Seq > found.Seq) {
+			found = &entries[i]
+		}
+	}
+	return found, found != nil
+}
+
+// certValidity formats a certificate's validity window with the time left.
+func certValidity(s *ipc.NetworkSecurityDetails, now time.Time) string {
+	if s.ValidTo == 0 {
+		return ""
+	}
+	from := time.UnixMilli(s.ValidFrom).Format("2006-01-02")
+	to := time.UnixMilli(s.ValidTo)
+	left := to.Sub(now)
+	if left < 0 {
+		return fmt.Sprintf("%s to %s (expired %d days ago)", from, to.Format("2006-01-02"), int(-left.Hours()/24))
+	}
+	return fmt.Sprintf("%s to %s (expires in %d days)", from, to.Format("2006-01-02"), int(left.Hours()/24))
+}
+
+// networkInsecureReasons lists what is wrong with a request's transport
+// security at now: an insecure security state, mixed content, or a
+// certificate that has expired or is about to. It returns nil for a request
+// with nothing to flag.
+func networkInsecureReasons(e ipc.NetworkEntry, now time.Time) []string {
+	var reasons []string
+	if e.SecurityState == "insecure" {
+		reasons = append(reasons, "security state is insecure")
+	}
+	if e.MixedContentType != "" {
+		reasons = append(reasons, fmt.Sprintf("mixed content (%s): loaded over HTTP by an HTTPS page", e.MixedContentType))
+	}
+	if e.Security != nil && e.Security.ValidTo != 0 {
+		left := time.UnixMilli(e.Security.ValidTo).Sub(now)
+		switch {
+		case left < 0:
+			reasons = append(reasons, fmt.Sprintf("certificate expired %d days ago", int(-left.Hours()/24)))
+		case left < certExpiryWarning:
+			reasons = append(reasons, fmt.Sprintf("certificate expires in %d days", int(left.Hours()/24)))
+		}
+	}
+	return reasons
+}
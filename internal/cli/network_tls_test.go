@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestNetworkInsecureReasons(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	cert := func(validTo time.Time) *ipc.NetworkSecurityDetails {
+		return &ipc.NetworkSecurityDetails{Protocol: "TLS 1.3", ValidTo: validTo.UnixMilli()}
+	}
+
+	tests := []struct {
+		name  string
+		entry ipc.NetworkEntry
+		want  string
+	}{
+		{"secure", ipc.NetworkEntry{SecurityState: "secure", Security: cert(now.AddDate(1, 0, 0))}, ""},
+		{"plain http", ipc.NetworkEntry{SecurityState: "insecure"}, "security state is insecure"},
+		{"mixed content", ipc.NetworkEntry{MixedContentType: "blockable"}, "mixed content (blockable): loaded over HTTP by an HTTPS page"},
+		{"expiring", ipc.NetworkEntry{Security: cert(now.Add(14*24*time.Hour + time.Hour))}, "certificate expires in 14 days"},
+		{"expired", ipc.NetworkEntry{Security: cert(now.Add(-3*24*time.Hour - time.Hour))}, "certificate expired 3 days ago"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(networkInsecureReasons(tt.entry, now), "; "); got != tt.want {
+				t.Errorf("reasons = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindNetworkEntry(t *testing.T) {
+	entries := []ipc.NetworkEntry{
+		{Seq: 3, RequestID: "100.1", URL: "http://example.com/"},
+		{Seq: 4, RequestID: "100.1", URL: "https://example.com/"},
+		{Seq: 5, RequestID: "100.2", URL: "https://example.com/app.js"},
+	}
+	if e, ok := findNetworkEntry(entries, "5"); !ok || e.URL != "https://example.com/app.js" {
+		t.Errorf("by seq = %+v, %v", e, ok)
+	}
+	if e, ok := findNetworkEntry(entries, "100.1"); !ok || e.Seq != 4 {
+		t.Errorf("by request id = %+v, %v; want the newest redirect hop", e, ok)
+	}
+	if _, ok := findNetworkEntry(entries, "9"); ok {
+		t.Error("expected a missing seq to fail")
+	}
+}
+
+func TestRunNetworkTLS(t *testing.T) {
+	enableJSONOutput(t)
+	entry := ipc.NetworkEntry{
+		Seq: 7, RequestID: "100.7", Method: "GET", URL: "https://example.com/", SecurityState: "secure",
+		Security: &ipc.NetworkSecurityDetails{Protocol: "TLS 1.3", Cipher: "AES_128_GCM", Issuer: "R11",
+			ValidTo: time.Now().Add(10 * 24 * time.Hour).UnixMilli()},
+	}
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.SuccessResponse(ipc.NetworkData{Entries: []ipc.NetworkEntry{entry, {Seq: 8, URL: "http://example.com/x"}}, Count: 2}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runNetworkTLS(networkTLSCmd, []string{"7"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Security ipc.NetworkSecurityDetails `json:"security"`
+		Warnings []string                   `json:"warnings"`
+	}
+	if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+		t.Fatalf("invalid JSON: %v\n%s", jerr, out)
+	}
+	if result.Security.Protocol != "TLS 1.3" || len(result.Warnings) != 1 || !strings.HasPrefix(result.Warnings[0], "certificate expires in") {
+		t.Errorf("result = %+v", result)
+	}
+
+	captureStream(t, &os.Stderr, func() {
+		err = runNetworkTLS(networkTLSCmd, []string{"8"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "no TLS details") {
+		t.Errorf("plain HTTP entry error = %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// perfShiftsPollInterval is how often 'perf shifts --follow' drains layout
+// shifts from the page.
+const perfShiftsPollInterval = 250 * time.Millisecond
+
+var perfCmd = &cobra.Command{
+	Use:   "perf",
+	Short: "Measure page performance",
+	Long: `Measures page performance from inside the page.
+
+Subcommands:
+  shifts   Print layout shifts and the elements that moved
+
+Examples:
+  perf shifts
+  perf shifts --follow`,
+	Args: cobra.NoArgs,
+}
+
+var perfShiftsCmd = &cobra.Command{
+	Use:   "shifts",
+	Short: "Print layout shifts and the elements that moved",
+	Long: `Prints layout-shift performance entries, the events Cumulative Layout Shift
+(CLS) is made of, with the elements that moved and where from and to. Use it
+to find exactly which element shifts the page when content loads.
+
+Without --follow, prints the shifts the page has made since it loaded and
+exits. With --follow, prints those and then each new shift as it happens,
+until interrupted (Ctrl-C) or --timeout. The observer lives in the page, so
+after a navigation it is re-installed and the new page's shifts follow.
+
+The score is the shift's layout shift value. Shifts within 500ms of user
+input are marked (input) and do not count towards CLS. The total is the sum
+of the other scores; CLS proper takes the worst 5s window, so on long pages
+it can be lower. A CPU slowdown set with 'throttle cpu' is noted after the
+total.
+
+Flags:
+  --follow          Keep printing new shifts until interrupted
+  --timeout <d>     With --follow, stop after this long (default: until Ctrl-C)
+
+Examples:
+  perf shifts
+  navigate https://example.com && perf shifts --follow --timeout 10s
+  perf shifts --json | jq '.shifts[] | select(.score > 0.05) | .sources'
+  perf shifts --follow --json | jq -c '.sources[].node'
+
+Text output:
+  14:03:21.118  0.0834  at 812ms
+         div.banner  0,0 1280x90 -> 0,210 1280x90
+         img.hero    0,90 1280x400 -> 0,300 1280x400
+  14:03:24.502  0.0012  at 4196ms (input)
+         ul#results  0,500 600x300 -> 0,520 600x300
+  total 0.0834 (2 shifts)
+
+JSON output (follow writes one shift object per line):
+  {"ok":true,"total":0.0834,"shifts":[{"time":1700000000123,"startTime":812,"score":0.0834,"sources":[...]}]}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start
+  - "layout-shift entries are not supported" - the browser lacks the API`,
+	Args: cobra.NoArgs,
+	RunE: runPerfShifts,
+}
+
+func init() {
+	perfShiftsCmd.Flags().Bool("follow", false, "Keep printing new shifts until interrupted")
+	perfShiftsCmd.Flags().Duration("timeout", 0, "With --follow, stop after this duration (0 = until interrupted)")
+	perfCmd.AddCommand(perfShiftsCmd)
+	rootCmd.AddCommand(perfCmd)
+}
+
+func runPerfShifts(cmd *cobra.Command, args []string) error {
+	t := startTimer("perf shifts")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	follow, _ := cmd.Flags().GetBool("follow")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout > 0 && !follow {
+		return outputCodedError(ipc.CodeInvalidArgs, "--timeout requires --follow")
+	}
+	debugParam("follow=%v timeout=%v", follow, timeout)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	// A fresh ID per run makes the daemon replace any observer left behind by
+	// an earlier run that exited without stopping.
+	watchID := fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
+
+	poll := func(stop bool) (ipc.PerfShiftsData, error) {
+		params, err := json.Marshal(ipc.PerfParams{Action: "shifts", WatchID: watchID, Stop: stop})
+		if err != nil {
+			return ipc.PerfShiftsData{}, err
+		}
+
+		debugRequest("perf", fmt.Sprintf("shifts stop=%v", stop))
+		ipcStart := time.Now()
+
+		resp, err := exec.Execute(ipc.Request{Cmd: "perf", Params: params})
+
+		debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+		if err != nil {
+			return ipc.PerfShiftsData{}, err
+		}
+		if !resp.OK {
+			return ipc.PerfShiftsData{}, fmt.Errorf("%s", resp.Error)
+		}
+
+		var data ipc.PerfShiftsData
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			return ipc.PerfShiftsData{}, err
+		}
+		return data, nil
+	}
+
+	// Always disconnect the observer, whatever ends the run
+	defer func() { _, _ = poll(true) }()
+
+	if !follow {
+		data, err := poll(false)
+		if err != nil {
+			return outputError(err.Error())
+		}
+		if JSONOutput {
+			result := map[string]any{
+				"ok":     true,
+				"total":  data.Total,
+				"shifts": data.Shifts,
+			}
+			if data.CPUThrottle > 0 {
+				result["cpuThrottle"] = data.CPUThrottle
+			}
+			return outputJSON(os.Stdout, result)
+		}
+		if err := format.LayoutShifts(os.Stdout, data.Shifts); err != nil {
+			return err
+		}
+		if err := format.LayoutShiftTotal(os.Stdout, data.Total, len(data.Shifts)); err != nil {
+			return err
+		}
+		if data.CPUThrottle > 0 {
+			_, err = fmt.Fprintf(os.Stdout, "CPU throttled %s\n", format.CPUFactor(data.CPUThrottle))
+		}
+		return err
+	}
+
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// JSON mode streams compact JSONL, one shift per line
+	enc := json.NewEncoder(os.Stdout)
+
+	started := false
+	ticker := time.NewTicker(perfShiftsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		data, err := poll(false)
+		if err != nil {
+			return outputError(err.Error())
+		}
+
+		if data.Installed {
+			if started {
+				watchDOMNotice("observer re-installed (page changed)")
+			}
+			started = true
+		}
+		if data.Dropped > 0 {
+			watchDOMNotice(fmt.Sprintf("%d shifts dropped (buffer full)", data.Dropped))
+		}
+
+		if JSONOutput {
+			for _, s := range data.Shifts {
+				if err := enc.Encode(s); err != nil {
+					return err
+				}
+			}
+		} else if err := format.LayoutShifts(os.Stdout, data.Shifts); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// perfShiftsExecutor answers "perf" shifts polls, returning shifts on the
+// first poll only, and records each request.
+func perfShiftsExecutor(t *testing.T, requests *[]ipc.PerfParams) *mockExecutor {
+	return &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "perf" {
+				t.Errorf("expected cmd=perf, got %s", req.Cmd)
+			}
+			var params ipc.PerfParams
+			_ = json.Unmarshal(req.Params, &params)
+			*requests = append(*requests, params)
+
+			data := ipc.PerfShiftsData{Shifts: []ipc.LayoutShift{}}
+			if !params.Stop && len(*requests) == 1 {
+				data.Installed = true
+				data.Total = 0.09
+				data.Shifts = []ipc.LayoutShift{
+					{Time: 1, StartTime: 800, Score: 0.09, Sources: []ipc.LayoutShiftSource{{Node: "img.hero"}}},
+					{Time: 2, StartTime: 3000, Score: 0.01, HadRecentInput: true},
+				}
+			}
+			raw, _ := json.Marshal(data)
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+}
+
+func TestRunPerfShifts_Once(t *testing.T) {
+	var requests []ipc.PerfParams
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: perfShiftsExecutor(t, &requests)})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runPerfShifts(perfShiftsCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"0.0900  at 800ms", "img.hero  0,0 0x0 -> 0,0 0x0", "(input)", "total 0.0900 (2 shifts)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if len(requests) != 2 || requests[0].Action != "shifts" || !requests[1].Stop || requests[0].WatchID != requests[1].WatchID {
+		t.Errorf("requests = %+v, want a poll then a stop", requests)
+	}
+}
+
+func TestRunPerfShifts_FollowStreamsJSONL(t *testing.T) {
+	enableJSONOutput(t)
+	resetFlags(t, perfShiftsCmd.Flags(), "follow", "timeout")
+	for name, value := range map[string]string{"follow": "true", "timeout": "50ms"} {
+		if err := perfShiftsCmd.Flags().Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var requests []ipc.PerfParams
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: perfShiftsExecutor(t, &requests)})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runPerfShifts(perfShiftsCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %q", out)
+	}
+	var s ipc.LayoutShift
+	if err := json.Unmarshal([]byte(lines[0]), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Score != 0.09 || len(s.Sources) != 1 || s.Sources[0].Node != "img.hero" {
+		t.Errorf("first shift = %+v", s)
+	}
+	if last := requests[len(requests)-1]; !last.Stop {
+		t.Error("expected the final request to stop the observer")
+	}
+}
+
+func TestRunPerfShifts_TimeoutNeedsFollow(t *testing.T) {
+	resetFlags(t, perfShiftsCmd.Flags(), "timeout")
+	if err := perfShiftsCmd.Flags().Set("timeout", "5s"); err != nil {
+		t.Fatal(err)
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})()
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runPerfShifts(perfShiftsCmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "--timeout requires --follow") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var pickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Pick an element in the browser and print its selector",
+	Long: `Puts the page into DevTools inspect mode and waits for you to click an element
+in the headful browser, then prints a CSS selector and XPath that match exactly
+that element. The reverse of highlight.
+
+While waiting, elements under the mouse are outlined with the same overlay used
+by highlight. The click selects the element without triggering page handlers.
+
+The generated CSS selector prefers stable hooks: a unique id, then a unique
+data-testid, data-test, data-cy, data-qa, name, or aria-label attribute, then
+the shortest unique ancestor path. Generated-looking class names are skipped.
+
+Only usable in a headful browser (webctl start without --headless).
+
+Flags:
+  --timeout <d>     How long to wait for a click (default 60s)
+
+Examples:
+  pick                                  # Wait up to 60s for a click
+  pick --timeout 5m                     # Wait longer
+  pick --json | jq -r .selector         # Selector only, for scripting
+
+Text output:
+  css:   form#login > button.btn
+  xpath: //*[@id="login"]/button
+
+JSON output:
+  {"ok": true, "tag": "button", "class": "btn", "selector": "...", "xpath": "..."}
+
+Error cases:
+  - "timeout waiting for element pick" - no element clicked within --timeout
+  - "a pick is already in progress" - another pick is waiting for a click
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.NoArgs,
+	RunE: runPick,
+}
+
+func init() {
+	pickCmd.Flags().Duration("timeout", 60*time.Second, "How long to wait for a click")
+	rootCmd.AddCommand(pickCmd)
+}
+
+func runPick(cmd *cobra.Command, args []string) error {
+	t := startTimer("pick")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	debugParam("timeout=%v", timeout)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.PickParams{
+		Timeout: int(timeout.Seconds()),
+	})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !JSONOutput {
+		outputHint("click an element in the browser to pick it")
+	}
+
+	debugRequest("pick", fmt.Sprintf("timeout=%v", timeout))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "pick",
+		Params: params,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.PickData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	// JSON mode: element metadata plus both selectors
+	if JSONOutput {
+		result := map[string]any{
+			"ok":       true,
+			"tag":      data.Tag,
+			"selector": data.Selector,
+			"xpath":    data.XPath,
+		}
+		if data.ID != "" {
+			result["id"] = data.ID
+		}
+		if data.Class != "" {
+			result["class"] = data.Class
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	// Text mode: one labelled line per selector form
+	fmt.Printf("css:   %s\n", data.Selector)
+	fmt.Printf("xpath: %s\n", data.XPath)
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunPick_JSON(t *testing.T) {
+	enableJSONOutput(t)
+	exec := expectExecutor(t, "pick", ipc.SuccessResponse(ipc.PickData{
+		ElementMeta: ipc.ElementMeta{Tag: "button", Class: "btn"},
+		Selector:    "#login > button.btn",
+		XPath:       `//*[@id="login"]/button`,
+	}))
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runPick(pickCmd, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if result["selector"] != "#login > button.btn" {
+		t.Errorf("unexpected selector: %v", result["selector"])
+	}
+	if result["xpath"] != `//*[@id="login"]/button` {
+		t.Errorf("unexpected xpath: %v", result["xpath"])
+	}
+	if _, ok := result["id"]; ok {
+		t.Error("expected empty id to be omitted")
+	}
+}
+
+func TestRunPick_Text(t *testing.T) {
+	exec := expectExecutor(t, "pick", ipc.SuccessResponse(ipc.PickData{
+		ElementMeta: ipc.ElementMeta{Tag: "a"},
+		Selector:    "nav > a:nth-of-type(2)",
+		XPath:       "/html/body/nav/a[2]",
+	}))
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		_ = captureStream(t, &os.Stderr, func() {
+			err = runPick(pickCmd, nil)
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "css:   nav > a:nth-of-type(2)\nxpath: /html/body/nav/a[2]\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestRunPick_Timeout(t *testing.T) {
+	enableJSONOutput(t)
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			return ipc.Response{OK: false, Error: "timeout waiting for element pick"}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runPick(pickCmd, nil)
+	})
+	if err == nil {
+		t.Error("expected error on timeout")
+	}
+	if !strings.Contains(out, "timeout waiting for element pick") {
+		t.Errorf("expected timeout error, got %q", out)
+	}
+}
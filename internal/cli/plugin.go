@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// pluginPrefix names external subcommands: "webctl foo" runs a webctl-foo
+// executable from PATH when foo is not a built-in command, as git does.
+const pluginPrefix = "webctl-"
+
+// Environment passed to plugins. The socket, auto-start, CI, and preset
+// variables are the ones webctl itself reads, so webctl commands a plugin
+// runs reach the same daemon with the same settings.
+const (
+	pluginBinEnv     = "WEBCTL_BIN"
+	pluginNameEnv    = "WEBCTL_PLUGIN"
+	pluginJSONEnv    = "WEBCTL_JSON"
+	pluginOutputEnv  = "WEBCTL_OUTPUT"
+	pluginDebugEnv   = "WEBCTL_DEBUG"
+	pluginNoColorEnv = "WEBCTL_NO_COLOR"
+)
+
+// lookPlugin finds a plugin executable on PATH. Replaceable for testing.
+var lookPlugin = exec.LookPath
+
+// PluginExitError carries a plugin's exit status, which webctl exits with
+// instead of printing an error of its own.
+type PluginExitError struct {
+	Code int
+}
+
+func (e PluginExitError) Error() string {
+	return fmt.Sprintf("plugin exited with status %d", e.Code)
+}
+
+// pluginCommand returns the plugin name and its executable when args invoke
+// one: the first non-flag argument is not a built-in command and names a
+// webctl-<name> executable on PATH. Global flags before the name are returned
+// in globals, and everything after it, verbatim, in rest.
+func pluginCommand(args []string) (name, path string, globals, rest []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return "", "", nil, nil
+		}
+		if strings.HasPrefix(arg, "-") {
+			if globalFlagTakesValue(arg) {
+				i++
+			}
+			continue
+		}
+		if isBuiltinCommand(arg) || strings.ContainsAny(arg, `/\`) {
+			return "", "", nil, nil
+		}
+		path, err := lookPlugin(pluginPrefix + arg)
+		if err != nil {
+			return "", "", nil, nil
+		}
+		return arg, path, args[:i], args[i+1:]
+	}
+	return "", "", nil, nil
+}
+
+// globalFlagTakesValue reports whether arg is a root flag whose value is the
+// next argument ("--socket path" rather than "--socket=path").
+func globalFlagTakesValue(arg string) bool {
+	if strings.Contains(arg, "=") {
+		return false
+	}
+	f := rootCmd.PersistentFlags().Lookup(strings.TrimPrefix(arg, "--"))
+	if !strings.HasPrefix(arg, "--") {
+		f = rootCmd.PersistentFlags().ShorthandLookup(strings.TrimPrefix(arg, "-"))
+	}
+	return f != nil && f.NoOptDefVal == ""
+}
+
+// isBuiltinCommand reports whether name is a command or alias of webctl's
+// own, which plugins cannot replace.
+func isBuiltinCommand(name string) bool {
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return name == "help" || name == "completion" || strings.HasPrefix(name, "__")
+}
+
+// runPlugin parses the global flags given before the plugin name and runs
+// the plugin with the remaining arguments, its standard streams connected to
+// webctl's, and the global settings in its environment.
+func runPlugin(name, path string, globals, rest []string) error {
+	if err := rootCmd.PersistentFlags().Parse(globals); err != nil {
+		return err
+	}
+	if err := applySocket(); err != nil {
+		return err
+	}
+	debugf("PLUGIN", "running %s %s", path, strings.Join(rest, " "))
+
+	c := exec.Command(path, rest...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(), pluginEnv(name)...)
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			code = 1 // killed by a signal
+		}
+		return PluginExitError{Code: code}
+	}
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", name, err)
+	}
+	return nil
+}
+
+// pluginEnv returns the variables that pass the socket path and global flags
+// to a plugin.
+func pluginEnv(name string) []string {
+	env := []string{
+		pluginNameEnv + "=" + name,
+		ipc.SocketEnv + "=" + ipc.DefaultSocketPath(),
+	}
+	if exe, err := os.Executable(); err == nil {
+		env = append(env, pluginBinEnv+"="+exe)
+	}
+	set := func(key string, on bool) {
+		if on {
+			env = append(env, key+"=1")
+		}
+	}
+	set(pluginJSONEnv, JSONOutput || Output == "json")
+	set(pluginDebugEnv, Debug)
+	set(pluginNoColorEnv, NoColor)
+	set(autoStartEnv, AutoStart)
+	set(ciEnv, rootCmd.PersistentFlags().Changed("ci"))
+	if Output != "" {
+		env = append(env, pluginOutputEnv+"="+Output)
+	}
+	if Preset != "" {
+		env = append(env, presetEnv+"="+Preset)
+	}
+	return env
+}
+
+// discoverPlugins returns the names of the webctl-<name> executables on
+// PATH, sorted, skipping any a built-in command shadows.
+func discoverPlugins() []string {
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := strings.CutPrefix(e.Name(), pluginPrefix)
+			if !ok || name == "" || e.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			} else if info, err := e.Info(); err != nil || info.Mode()&0o111 == 0 {
+				continue
+			}
+			if !isBuiltinCommand(name) {
+				seen[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pluginsHelpBlock lists the plugins on PATH for the root help.
+func pluginsHelpBlock() string {
+	names := discoverPlugins()
+	if len(names) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nPlugins (webctl-<name> on PATH):")
+	for _, name := range names {
+		b.WriteString("\n  " + name)
+	}
+	return b.String()
+}
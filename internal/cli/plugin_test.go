@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPluginCommand(t *testing.T) {
+	old := lookPlugin
+	defer func() { lookPlugin = old }()
+	lookPlugin = func(file string) (string, error) {
+		if file == "webctl-sso" {
+			return "/opt/bin/webctl-sso", nil
+		}
+		return "", exec.ErrNotFound
+	}
+
+	tests := []struct {
+		args          []string
+		name          string
+		globals, rest []string
+	}{
+		{[]string{"sso", "--realm", "corp"}, "sso", []string{}, []string{"--realm", "corp"}},
+		{[]string{"--json", "--socket", "/tmp/s.sock", "sso", "login"}, "sso", []string{"--json", "--socket", "/tmp/s.sock"}, []string{"login"}},
+		{[]string{"--socket=/tmp/s.sock", "sso"}, "sso", []string{"--socket=/tmp/s.sock"}, []string{}},
+		{[]string{"navigate", "sso"}, "", nil, nil},
+		{[]string{"missing"}, "", nil, nil},
+		{[]string{"--", "sso"}, "", nil, nil},
+		{[]string{"--socket", "sso"}, "", nil, nil},
+	}
+	for _, tt := range tests {
+		name, path, globals, rest := pluginCommand(tt.args)
+		if name != tt.name || !slices.Equal(globals, tt.globals) || !slices.Equal(rest, tt.rest) {
+			t.Errorf("pluginCommand(%q) = %q, %q, %q; want %q, %q, %q", tt.args, name, globals, rest, tt.name, tt.globals, tt.rest)
+		}
+		if name != "" && path != "/opt/bin/webctl-sso" {
+			t.Errorf("pluginCommand(%q) path = %q", tt.args, path)
+		}
+	}
+}
+
+func TestPluginEnv(t *testing.T) {
+	t.Setenv("WEBCTL_SOCKET", "/tmp/plugin.sock")
+	defer func(j bool, o, p string) { JSONOutput, Output, Preset = j, o, p }(JSONOutput, Output, Preset)
+	JSONOutput, Output, Preset = true, "", "work"
+
+	env := pluginEnv("sso")
+	for _, want := range []string{"WEBCTL_PLUGIN=sso", "WEBCTL_SOCKET=/tmp/plugin.sock", "WEBCTL_JSON=1", "WEBCTL_PRESET=work"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("env %q is missing %s", env, want)
+		}
+	}
+	for _, v := range env {
+		if strings.HasPrefix(v, "WEBCTL_DEBUG=") || strings.HasPrefix(v, "WEBCTL_OUTPUT=") {
+			t.Errorf("unset global passed as %s", v)
+		}
+	}
+}
+
+// writePlugin puts an executable shell script named webctl-<name> in dir.
+func writePlugin(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, pluginPrefix+name), []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	writePlugin(t, dir, "probe", `echo "$WEBCTL_PLUGIN $*" > "`+out+`"; exit 3`)
+
+	err := runPlugin("probe", filepath.Join(dir, "webctl-probe"), nil, []string{"a", "b"})
+	var exitErr PluginExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("runPlugin error = %v, want exit status 3", err)
+	}
+	got, _ := os.ReadFile(out)
+	if string(got) != "probe a b\n" {
+		t.Errorf("plugin saw %q, want \"probe a b\"", got)
+	}
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits")
+	}
+	dir := t.TempDir()
+	writePlugin(t, dir, "sso", "true")
+	writePlugin(t, dir, "navigate", "true")
+	if err := os.WriteFile(filepath.Join(dir, "webctl-notes"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	if got := discoverPlugins(); !slices.Equal(got, []string{"sso"}) {
+		t.Errorf("discoverPlugins() = %q, want [sso]", got)
+	}
+	if !strings.Contains(pluginsHelpBlock(), "\n  sso") {
+		t.Errorf("help block %q does not list sso", pluginsHelpBlock())
+	}
+}
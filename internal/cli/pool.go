@@ -0,0 +1,380 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/executor"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var poolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Load many URLs in parallel in a pool of background tabs",
+	Long: `Manages a pool of background tabs that the daemon loads URLs in, so a list
+of pages can be scraped or checked concurrently instead of one at a time.
+Without a subcommand, shows the pool's size and busy tabs.
+
+Create the pool once, then map a command over a list of URLs: each URL is
+loaded in a free pool tab and the command runs on the page once it has
+loaded. As many URLs load at once as the pool has tabs. The active tab is
+not touched.
+
+The pool lasts until closed or the daemon stops.
+
+Subcommands:
+  create      Open the pool's tabs
+  map         Run a command on every URL in a list
+  status      Show the pool's size and busy tabs
+  close       Close the pool's tabs
+
+Examples:
+  pool create --size 4
+  pool map --urls urls.txt --cmd "text h1"
+  pool map --urls urls.txt --cmd title --json
+  pool close
+
+Response:
+  Pool: 4 tabs, 1 busy`,
+	Args: cobra.NoArgs,
+	RunE: runPoolStatus,
+}
+
+var poolCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Open the pool's tabs",
+	Long: `Opens the pool's background tabs. Each tab is a full renderer, so size the
+pool to the machine; at most 16. Close an existing pool first.
+
+Flags:
+  --size <n>    Tabs in the pool (default 4)
+
+Response:
+  Pool: 4 tabs, 0 busy
+
+Error cases:
+  - "a pool of 4 tabs already exists; close it first with: webctl pool close"`,
+	Args: cobra.NoArgs,
+	RunE: runPoolCreate,
+}
+
+var poolMapCmd = &cobra.Command{
+	Use:   "map",
+	Short: "Run a command on every URL in a list",
+	Long: `Loads every URL in a file in the pool's tabs, as many at once as the pool
+has tabs, and runs a command on each page once it has loaded. Results are
+printed in the order of the file when all are done.
+
+The file has one URL per line; blank lines and lines starting with # are
+skipped. Use --urls - to read standard input. URLs without a scheme get
+https://, as with navigate.
+
+Commands:
+  text [selector]     innerText of the first match (default body)
+  html [selector]     outerHTML of the first match (default html)
+  title               The page title
+  count <selector>    The number of matching elements
+  eval <js>           The value of an expression; a promise is awaited
+
+A URL that fails to load, or whose command fails, is reported and the rest
+carry on. The exit status is non-zero if any failed.
+
+Flags:
+  --urls <file>       File of URLs, or - for standard input (required)
+  --cmd <command>     Command to run on each page (default "text")
+  --timeout <dur>     Time each URL may take to load and run (default 30s)
+
+Examples:
+  pool map --urls urls.txt
+  pool map --urls urls.txt --cmd "text main h1"
+  pool map --urls urls.txt --cmd "count a[href]"
+  cat urls.txt | webctl pool map --urls - --cmd "eval document.links.length" --json
+
+Response:
+  == https://example.com/  OK  1.2s
+  Example Domain
+  == https://example.org/  FAIL  30.0s
+  navigation timeout
+  2 URLs, 1 failed
+
+Error cases:
+  - "no pool; create one with: webctl pool create"
+  - "invalid command ..." - --cmd is not one of the commands above`,
+	Args: cobra.NoArgs,
+	RunE: runPoolMap,
+}
+
+var poolStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the pool's size and busy tabs",
+	Args:  cobra.NoArgs,
+	RunE:  runPoolStatus,
+}
+
+var poolCloseCmd = &cobra.Command{
+	Use:   "close",
+	Short: "Close the pool's tabs",
+	Long: `Closes the pool's tabs, including any still loading a URL for a map, whose
+results are then failures.`,
+	Args: cobra.NoArgs,
+	RunE: runPoolClose,
+}
+
+func init() {
+	poolCreateCmd.Flags().Int("size", 4, "Tabs in the pool (at most 16)")
+	poolMapCmd.Flags().String("urls", "", "File of URLs, one per line, or - for standard input")
+	poolMapCmd.Flags().String("cmd", "text", "Command: text [selector], html [selector], title, count <selector>, eval <js>")
+	poolMapCmd.Flags().Duration("timeout", 30*time.Second, "Time each URL may take to load and run")
+	_ = poolMapCmd.MarkFlagRequired("urls")
+
+	poolCmd.AddCommand(poolCreateCmd, poolMapCmd, poolStatusCmd, poolCloseCmd)
+	rootCmd.AddCommand(poolCmd)
+}
+
+func runPoolStatus(cmd *cobra.Command, args []string) error {
+	t := startTimer("pool status")
+	defer t.log()
+
+	return poolAction(ipc.PoolParams{Action: "status"})
+}
+
+func runPoolCreate(cmd *cobra.Command, args []string) error {
+	t := startTimer("pool create")
+	defer t.log()
+
+	size, _ := cmd.Flags().GetInt("size")
+	debugParam("size=%d", size)
+	return poolAction(ipc.PoolParams{Action: "create", Size: size})
+}
+
+func runPoolClose(cmd *cobra.Command, args []string) error {
+	t := startTimer("pool close")
+	defer t.log()
+
+	return poolAction(ipc.PoolParams{Action: "close"})
+}
+
+// poolAction sends a pool action other than map and prints the pool's status.
+func poolAction(params ipc.PoolParams) error {
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	resp, err := poolRequest(exec, params)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.PoolData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":   true,
+			"size": data.Size,
+			"busy": data.Busy,
+		})
+	}
+	if params.Action == "close" {
+		_, err := fmt.Fprintf(os.Stdout, "Closed %d pool tabs\n", data.Size)
+		return err
+	}
+	if data.Size == 0 {
+		_, err := fmt.Fprintln(os.Stdout, "No pool")
+		return err
+	}
+	_, err = fmt.Fprintf(os.Stdout, "Pool: %d tabs, %d busy\n", data.Size, data.Busy)
+	return err
+}
+
+func runPoolMap(cmd *cobra.Command, args []string) error {
+	t := startTimer("pool map")
+	defer t.log()
+
+	urlsPath, _ := cmd.Flags().GetString("urls")
+	line, _ := cmd.Flags().GetString("cmd")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	command, arg, err := ipc.ParsePoolCommand(line)
+	if err != nil {
+		return outputCodedError(ipc.CodeInvalidArgs, err.Error())
+	}
+	if timeout <= 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--timeout must be positive")
+	}
+	urls, err := readURLList(urlsPath)
+	if err != nil {
+		return outputError(fmt.Sprintf("failed to read URLs: %v", err))
+	}
+	if len(urls) == 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("no URLs in %s", urlsPath))
+	}
+	debugParam("urls=%d command=%q arg=%q timeout=%s", len(urls), command, arg, timeout)
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	// One URL per pool tab at a time; the daemon queues any beyond that
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	resp, err := poolRequest(exec, ipc.PoolParams{Action: "status"})
+	_ = exec.Close()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+	var status ipc.PoolData
+	if err := json.Unmarshal(resp.Data, &status); err != nil {
+		return outputError(err.Error())
+	}
+	if status.Size == 0 {
+		return outputCodedError(ipc.CodeNotFound, "no pool; create one with: webctl pool create")
+	}
+
+	results := poolMap(urls, min(status.Size, len(urls)), ipc.PoolParams{
+		Action:  "map",
+		Command: command,
+		Arg:     arg,
+		Timeout: int(timeout.Milliseconds()),
+	})
+
+	failed := 0
+	for _, r := range results {
+		if !r.OK {
+			failed++
+		}
+	}
+
+	if JSONOutput {
+		if err := outputJSON(os.Stdout, map[string]any{
+			"ok":      failed == 0,
+			"results": results,
+			"failed":  failed,
+		}); err != nil {
+			return err
+		}
+	} else if err := format.PoolResults(os.Stdout, results, format.NewOutputOptions(JSONOutput, NoColor)); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return printedError{err: fmt.Errorf("%d of %d URLs failed", failed, len(urls))}
+	}
+	return nil
+}
+
+// poolMap runs params on every URL with the given number of workers, each
+// with its own connection to the daemon, and returns the results in the
+// order of urls.
+func poolMap(urls []string, workers int, params ipc.PoolParams) []ipc.PoolMapResult {
+	results := make([]ipc.PoolMapResult, len(urls))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exec, err := execFactory.NewExecutor()
+			if err == nil {
+				defer func() { _ = exec.Close() }()
+			}
+			for i := range jobs {
+				p := params
+				p.URL = urls[i]
+				results[i] = poolMapOne(exec, err, p)
+			}
+		}()
+	}
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// poolMapOne runs one URL of a map, turning a failed request into a failed
+// result.
+func poolMapOne(exec executor.Executor, execErr error, params ipc.PoolParams) ipc.PoolMapResult {
+	res := ipc.PoolMapResult{URL: params.URL}
+	if execErr != nil {
+		res.Error = execErr.Error()
+		return res
+	}
+	resp, err := poolRequest(exec, params)
+	switch {
+	case err != nil:
+		res.Error = err.Error()
+	case !resp.OK:
+		res.Error = resp.Error
+	default:
+		if err := json.Unmarshal(resp.Data, &res); err != nil {
+			res.Error = fmt.Sprintf("failed to parse result: %v", err)
+		}
+	}
+	return res
+}
+
+// poolRequest sends a pool action to the daemon.
+func poolRequest(exec executor.Executor, params ipc.PoolParams) (ipc.Response, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return ipc.Response{}, err
+	}
+
+	debugRequest("pool", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "pool", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+	return resp, err
+}
+
+// readURLList reads URLs one per line from path, or standard input for "-",
+// skipping blank lines and # comments.
+func readURLList(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	var urls []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, normalizeURL(line))
+	}
+	return urls, sc.Err()
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunPoolMap(t *testing.T) {
+	resetFlags(t, poolMapCmd.Flags(), "urls", "cmd", "timeout")
+	path := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(path, []byte("# sites\na.test\n\nhttps://b.test/\nc.test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := poolMapCmd.Flags().Set("urls", path); err != nil {
+		t.Fatal(err)
+	}
+	if err := poolMapCmd.Flags().Set("cmd", "text h1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var mapped []string
+	// Each worker opens its own executor, so the factory hands out a new one
+	// per call rather than a shared mock
+	execute := func(req ipc.Request) (ipc.Response, error) {
+		var params ipc.PoolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			t.Error(err)
+			return ipc.ErrorResponse(err.Error()), nil
+		}
+		if params.Action == "status" {
+			return ipc.SuccessResponse(ipc.PoolData{Size: 2}), nil
+		}
+		if params.Action != "map" || params.Command != "text" || params.Arg != "h1" || params.Timeout != 30000 {
+			t.Errorf("params = %+v", params)
+		}
+		mu.Lock()
+		mapped = append(mapped, params.URL)
+		mu.Unlock()
+		if params.URL == "https://b.test/" {
+			return ipc.SuccessResponse(ipc.PoolMapResult{URL: params.URL, Error: `no element matches "h1"`}), nil
+		}
+		value, _ := json.Marshal("Title of " + params.URL)
+		return ipc.SuccessResponse(ipc.PoolMapResult{URL: params.URL, OK: true, Value: value, DurationMs: 1200}), nil
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executeFunc: execute})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runPoolMap(poolMapCmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 URLs failed") {
+		t.Errorf("err = %v, want 1 of 3 failed", err)
+	}
+	if len(mapped) != 3 {
+		t.Errorf("mapped = %v", mapped)
+	}
+
+	// Results keep the order of the file, whatever order they finished in
+	want := "== https://a.test  OK  1.2s\nTitle of https://a.test\n" +
+		"== https://b.test/  FAIL  0.0s\nno element matches \"h1\"\n" +
+		"== https://c.test  OK  1.2s\nTitle of https://c.test\n" +
+		"3 URLs, 1 failed\n"
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestRunPoolMap_NoPool(t *testing.T) {
+	resetFlags(t, poolMapCmd.Flags(), "urls", "cmd", "timeout")
+	path := filepath.Join(t.TempDir(), "urls.txt")
+	if err := os.WriteFile(path, []byte("a.test\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := poolMapCmd.Flags().Set("urls", path); err != nil {
+		t.Fatal(err)
+	}
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.SuccessResponse(ipc.PoolData{}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runPoolMap(poolMapCmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "no pool") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRunPoolMap_InvalidCommand(t *testing.T) {
+	resetFlags(t, poolMapCmd.Flags(), "urls", "cmd", "timeout")
+	if err := poolMapCmd.Flags().Set("cmd", "count"); err != nil {
+		t.Fatal(err)
+	}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})()
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runPoolMap(poolMapCmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "count requires an argument") {
+		t.Errorf("err = %v", err)
+	}
+}
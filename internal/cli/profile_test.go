@@ -28,6 +28,11 @@ func TestStartupErrorHint(t *testing.T) {
 			err:     fmt.Errorf("%w: 9222", browser.ErrPortInUse),
 			wantHas: "stop --force",
 		},
+		{
+			name:    "browser on the port suggests adopting it",
+			err:     fmt.Errorf("failed to start browser: %w: 9222 (Chrome/120.0)", browser.ErrBrowserOnPort),
+			wantHas: "--adopt",
+		},
 		{
 			name:      "unrelated error gets no hint",
 			err:       errors.New("some other failure"),
@@ -146,3 +151,72 @@ func TestResolveProfile_MutuallyExclusive(t *testing.T) {
 		})
 	}
 }
+
+func TestStartLaunchFlags(t *testing.T) {
+	set := func(lang, size, pos string, incognito bool, flags ...string) {
+		startLang, startWindowSize, startWindowPos, startIncognito, startChromeFlags = lang, size, pos, incognito, flags
+	}
+	t.Cleanup(func() {
+		set("", "", "", false)
+		startExtensions = nil
+	})
+
+	set("en-GB", "1280x800", "10, -20", true, "--disable-gpu", "--enable-features=A,B")
+	got, err := startLaunchFlags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Lang != "en-GB" || got.WindowSize != "1280,800" || got.WindowPosition != "10,-20" || !got.Incognito {
+		t.Errorf("unexpected flags: %+v", got)
+	}
+	if len(got.ChromeFlags) != 2 || got.ChromeFlags[1] != "--enable-features=A,B" {
+		t.Errorf("chrome flags = %q, want both kept as given", got.ChromeFlags)
+	}
+
+	ext := t.TempDir()
+	if err := os.WriteFile(filepath.Join(ext, "manifest.json"), []byte(`{"name": "Dev Tool"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	startExtensions = []string{ext}
+	if got, err := startLaunchFlags(); err != nil || len(got.Extensions) != 1 || got.Extensions[0] != ext {
+		t.Errorf("--load-extension %s: got %q, %v", ext, got.Extensions, err)
+	}
+	startExtensions = []string{t.TempDir()}
+	if _, err := startLaunchFlags(); err == nil || !strings.Contains(err.Error(), "--load-extension") {
+		t.Errorf("--load-extension without a manifest: expected an error, got %v", err)
+	}
+	startExtensions = nil
+
+	startDevTools = true
+	if got, err := startLaunchFlags(); err != nil || !got.DevTools {
+		t.Errorf("--devtools: got %+v, %v", got, err)
+	}
+	startHeadless = true
+	if _, err := startLaunchFlags(); err == nil || !strings.Contains(err.Error(), "--devtools needs a visible browser") {
+		t.Errorf("--devtools --headless: expected an error, got %v", err)
+	}
+	startDevTools, startHeadless = false, false
+
+	invalid := []struct {
+		name    string
+		size    string
+		pos     string
+		flags   []string
+		wantHas string
+	}{
+		{"size without height", "1280", "", nil, "--window-size"},
+		{"zero size", "0x800", "", nil, "--window-size"},
+		{"position not a pair", "", "10", nil, "--window-position"},
+		{"flag without dashes", "", "", []string{"disable-gpu"}, "starts with --"},
+		{"reserved flag", "", "", []string{"--remote-debugging-port=9333"}, "use --port"},
+		{"reserved devtools flag", "", "", []string{"--auto-open-devtools-for-tabs"}, "use --devtools"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			set("", tc.size, tc.pos, false, tc.flags...)
+			if _, err := startLaunchFlags(); err == nil || !strings.Contains(err.Error(), tc.wantHas) {
+				t.Errorf("expected error containing %q, got %v", tc.wantHas, err)
+			}
+		})
+	}
+}
@@ -53,6 +53,11 @@ Timeout:
   --timeout duration    Maximum time to wait (default 60s)
                         Accepts Go duration format: 10s, 1m, 500ms
 
+Failure artifacts:
+  --artifacts dir       On failure, save a screenshot and the page HTML to
+                        dir as <time>-ready.png and <time>-ready.html, so a
+                        CI failure shows what the page looked like
+
 Examples:
   # Page load mode - wait for the DOM to be ready
   ready
@@ -73,6 +78,9 @@ Examples:
   ready --eval "window.appReady === true"
   ready --eval "document.querySelector('.error') === null"
 
+  # Keep evidence when the wait fails in CI
+  ready "#dashboard" --timeout 30s --artifacts ./artifacts
+
 Common patterns:
   # Navigate and wait for page load
   navigate example.com
@@ -124,6 +132,7 @@ func init() {
 	readyCmd.Flags().Duration("timeout", 60*time.Second, "Maximum time to wait")
 	readyCmd.Flags().Bool("network-idle", false, "Wait for network to be idle (500ms of no activity)")
 	readyCmd.Flags().String("eval", "", "JavaScript expression to evaluate")
+	readyCmd.Flags().String("artifacts", "", "Directory to save a screenshot and HTML to on failure")
 	rootCmd.AddCommand(readyCmd)
 }
 
@@ -132,13 +141,14 @@ func runReady(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Read flags from command
 	timeout, _ := cmd.Flags().GetDuration("timeout")
 	networkIdle, _ := cmd.Flags().GetBool("network-idle")
 	evalExpr, _ := cmd.Flags().GetString("eval")
+	artifacts, _ := cmd.Flags().GetString("artifacts")
 
 	// Get selector from args if provided
 	var selector string
@@ -146,7 +156,7 @@ func runReady(cmd *cobra.Command, args []string) error {
 		selector = args[0]
 	}
 
-	debugParam("timeout=%v selector=%q networkIdle=%v eval=%q", timeout, selector, networkIdle, evalExpr)
+	debugParam("timeout=%v selector=%q networkIdle=%v eval=%q artifacts=%q", timeout, selector, networkIdle, evalExpr, artifacts)
 
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
@@ -179,7 +189,10 @@ func runReady(cmd *cobra.Command, args []string) error {
 	}
 
 	if !resp.OK {
-		return outputError(resp.Error)
+		if artifacts != "" {
+			return outputArtifactsError(resp, saveFailureArtifacts(exec, artifacts, "ready"))
+		}
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: output JSON
@@ -0,0 +1,254 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/flow"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var recordFlowCmd = &cobra.Command{
+	Use:   "record-flow",
+	Short: "Record browser interactions as a flow or shell script",
+	Long: `Records what you do in the browser - navigations, clicks, typing, and
+Enter presses - and saves it as a flow file for 'webctl flow run' or a shell
+script of webctl commands. Use it with a visible browser (webctl start, or
+webctl head).
+
+Subcommands:
+  start          Start recording the active tab
+  stop <path>    Stop recording and save the steps to path`,
+}
+
+var recordFlowStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start recording interactions in the active tab",
+	Long: `Starts recording the active tab. Its current URL is the first step; after
+that, addresses typed into the address bar, clicks, text entered into fields,
+and Enter in a field are recorded, across page loads, until
+'webctl record-flow stop'.
+
+Links and form submissions are replayed by the recorded click or key, so the
+navigations they cause are not recorded separately. Password fields are
+recorded as ${PASSWORD} rather than their value.
+
+Examples:
+  webctl record-flow start
+  webctl navigate example.com && webctl record-flow start
+
+Error cases:
+  - "already recording" - stop the current recording first`,
+	Args: cobra.NoArgs,
+	RunE: runRecordFlowStart,
+}
+
+var recordFlowStopCmd = &cobra.Command{
+	Use:   "stop <path>",
+	Short: "Stop recording and save the steps to a file",
+	Long: `Stops recording and saves the recorded steps to path, as a flow file or, for
+a .sh path or --format shell, an executable shell script of webctl commands.
+
+Recordings are a starting point: add waits and assertions to the flow, and
+set PASSWORD in the environment to replay a recorded password field.
+
+Flags:
+  --format <flow|shell>   Output format (default: shell for .sh, else flow)
+  --name <name>           Flow name (default: the file name)
+
+Examples:
+  webctl record-flow stop login.yaml
+  webctl record-flow stop login.sh
+  webctl record-flow stop login.txt --format shell
+
+Error cases:
+  - "not recording" - start a recording first`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRecordFlowStop,
+}
+
+func init() {
+	recordFlowStopCmd.Flags().String("format", "", "Output format: flow or shell (default: shell for .sh, else flow)")
+	recordFlowStopCmd.Flags().String("name", "", "Flow name (default: the file name)")
+	recordFlowCmd.AddCommand(recordFlowStartCmd, recordFlowStopCmd)
+	rootCmd.AddCommand(recordFlowCmd)
+}
+
+// recordSecretVar stands in for the value of a recorded password field.
+const recordSecretVar = "PASSWORD"
+
+func runRecordFlowStart(cmd *cobra.Command, args []string) error {
+	t := startTimer("record-flow start")
+	defer t.log()
+
+	data, err := recordRequest("start")
+	if err != nil {
+		return err
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":        true,
+			"sessionId": data.SessionID,
+		})
+	}
+	fmt.Fprintln(os.Stdout, "Recording. Stop with: webctl record-flow stop <path>")
+	return nil
+}
+
+func runRecordFlowStop(cmd *cobra.Command, args []string) error {
+	t := startTimer("record-flow stop")
+	defer t.log()
+
+	path := args[0]
+	formatName, _ := cmd.Flags().GetString("format")
+	if formatName == "" {
+		formatName = "flow"
+		if strings.EqualFold(filepath.Ext(path), ".sh") {
+			formatName = "shell"
+		}
+	}
+	if formatName != "flow" && formatName != "shell" {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid format %q: must be flow or shell", formatName))
+	}
+	// Check the destination before stopping, so a bad path keeps the recording
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		return outputError(fmt.Sprintf("directory does not exist: %s", filepath.Dir(path)))
+	}
+	name, _ := cmd.Flags().GetString("name")
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	debugParam("path=%q format=%s name=%q", path, formatName, name)
+
+	data, err := recordRequest("stop")
+	if err != nil {
+		return err
+	}
+	if len(data.Steps) == 0 {
+		return outputError("nothing was recorded")
+	}
+
+	var out []byte
+	mode := os.FileMode(0o644)
+	if formatName == "shell" {
+		out = []byte(recordedShell(data.Steps))
+		mode = 0o755
+	} else if out, err = recordedFlow(name, data.Steps).Marshal(); err != nil {
+		return outputError(err.Error())
+	}
+	if err := os.WriteFile(path, out, mode); err != nil {
+		return outputError(fmt.Sprintf("failed to write %s: %v", path, err))
+	}
+	debugFile("wrote", path, len(out))
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":    true,
+			"path":  path,
+			"steps": len(data.Steps),
+		})
+	}
+	return format.FilePath(os.Stdout, path)
+}
+
+// recordRequest sends a record action to the daemon.
+func recordRequest(action string) (ipc.RecordData, error) {
+	var data ipc.RecordData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.RecordParams{Action: action})
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("record", "action="+action)
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "record", Params: params})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
+
+// recordedFlow converts recorded steps to a flow. A password field is filled
+// from ${PASSWORD}, which the environment or --var supplies at run time.
+func recordedFlow(name string, steps []ipc.RecordedStep) *flow.Flow {
+	f := &flow.Flow{Name: name}
+	for _, s := range steps {
+		switch s.Action {
+		case "navigate":
+			f.Steps = append(f.Steps, flow.Step{Navigate: s.URL})
+		case "click":
+			f.Steps = append(f.Steps, flow.Step{Click: s.Selector})
+		case "fill":
+			text := s.Text
+			if s.Secret {
+				text = "${" + recordSecretVar + "}"
+			}
+			f.Steps = append(f.Steps, flow.Step{Fill: &flow.Fill{Selector: s.Selector, Text: text}})
+		case "key":
+			f.Steps = append(f.Steps, flow.Step{Key: s.Key})
+		}
+	}
+	return f
+}
+
+// recordedShell converts recorded steps to a shell script of webctl commands.
+func recordedShell(steps []ipc.RecordedStep) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n# Recorded by webctl record-flow.\n")
+	for _, s := range steps {
+		if s.Secret {
+			b.WriteString("# Set " + recordSecretVar + " to the password before running.\n")
+			break
+		}
+	}
+	b.WriteString("set -e\n\n")
+	for _, s := range steps {
+		switch s.Action {
+		case "navigate":
+			fmt.Fprintf(&b, "webctl navigate %s --wait\n", shellQuote(s.URL))
+		case "click":
+			fmt.Fprintf(&b, "webctl click %s\n", shellQuote(s.Selector))
+		case "fill":
+			text := shellQuote(s.Text)
+			if s.Secret {
+				text = `"$` + recordSecretVar + `"`
+			}
+			fmt.Fprintf(&b, "webctl type --clear -- %s %s\n", shellQuote(s.Selector), text)
+		case "key":
+			fmt.Fprintf(&b, "webctl key %s\n", shellQuote(s.Key))
+		}
+	}
+	return b.String()
+}
+
+// shellQuote quotes s as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
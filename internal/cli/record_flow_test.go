@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/flow"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var recordedSteps = []ipc.RecordedStep{
+	{Action: "navigate", URL: "https://example.com/login"},
+	{Action: "fill", Selector: "#email", Text: "o'neil@example.com"},
+	{Action: "fill", Selector: "#password", Secret: true},
+	{Action: "key", Key: "Enter"},
+	{Action: "click", Selector: "a.profile"},
+}
+
+func TestRecordedFlow_RoundTrips(t *testing.T) {
+	data, err := recordedFlow("login", recordedSteps).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := flow.Parse(data)
+	if err != nil {
+		t.Fatalf("recorded flow does not parse: %v\n%s", err, data)
+	}
+	if f.Name != "login" || len(f.Steps) != 5 {
+		t.Fatalf("flow = %+v", f)
+	}
+	want := []string{"navigate", "fill", "fill", "key", "click"}
+	for i, s := range f.Steps {
+		if s.Action() != want[i] {
+			t.Errorf("step %d action = %q, want %q", i+1, s.Action(), want[i])
+		}
+	}
+	if f.Steps[2].Fill.Text != "${PASSWORD}" {
+		t.Errorf("secret fill text = %q, want ${PASSWORD}", f.Steps[2].Fill.Text)
+	}
+}
+
+func TestRecordedShell(t *testing.T) {
+	got := recordedShell(recordedSteps)
+	for _, want := range []string{
+		"#!/bin/sh\n",
+		"# Set PASSWORD",
+		"set -e\n",
+		"webctl navigate 'https://example.com/login' --wait\n",
+		`webctl type --clear -- '#email' 'o'\''neil@example.com'` + "\n",
+		`webctl type --clear -- '#password' "$PASSWORD"` + "\n",
+		"webctl key 'Enter'\n",
+		"webctl click 'a.profile'\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("script missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunRecordFlowStop_WritesShell(t *testing.T) {
+	var action string
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		action = string(req.Params)
+		return ipc.SuccessResponse(ipc.RecordData{Steps: recordedSteps[:1]}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	path := filepath.Join(t.TempDir(), "replay.sh")
+	cmd := &cobra.Command{}
+	cmd.Flags().String("format", "", "")
+	cmd.Flags().String("name", "", "")
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		err = runRecordFlowStop(cmd, []string{path})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(action, `"stop"`) {
+		t.Errorf("request params = %s, want stop", action)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&0o111 == 0 {
+		t.Error("shell script is not executable")
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "webctl navigate") {
+		t.Errorf("script = %s", data)
+	}
+}
+
+func TestRunRecordFlowStop_BadFormatKeepsRecording(t *testing.T) {
+	called := false
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		called = true
+		return ipc.SuccessResponse(ipc.RecordData{}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("format", "", "")
+	cmd.Flags().String("name", "", "")
+	_ = cmd.Flags().Set("format", "python")
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runRecordFlowStop(cmd, []string{filepath.Join(t.TempDir(), "x.py")})
+	})
+	if err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+	if called {
+		t.Error("recording was stopped despite the invalid format")
+	}
+}
@@ -13,14 +13,19 @@ import (
 var reloadCmd = &cobra.Command{
 	Use:   "reload",
 	Short: "Reload current page",
-	Long:  "Reloads the current page in the active browser session (hard reload, ignores cache). Returns immediately unless --wait is specified.",
-	Args:  cobra.NoArgs,
-	RunE:  runReload,
+	Long: `Reloads the current page in the active browser session (hard reload, ignores cache). Returns immediately unless --wait is specified.
+
+--preserve-scroll saves the scroll position before reloading and scrolls back
+to it once the page has loaded, retrying for a few seconds while late content
+makes the page long enough. It implies --wait.`,
+	Args: cobra.NoArgs,
+	RunE: runReload,
 }
 
 func init() {
 	reloadCmd.Flags().Bool("wait", false, "Wait for page load completion")
 	reloadCmd.Flags().Int("timeout", 60, "Timeout in seconds (used with --wait)")
+	reloadCmd.Flags().Bool("preserve-scroll", false, "Restore the scroll position after the reload (implies --wait)")
 	rootCmd.AddCommand(reloadCmd)
 }
 
@@ -29,14 +34,15 @@ func runReload(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Read flags
 	wait, _ := cmd.Flags().GetBool("wait")
 	timeout, _ := cmd.Flags().GetInt("timeout")
+	preserveScroll, _ := cmd.Flags().GetBool("preserve-scroll")
 
-	debugParam("wait=%v timeout=%d ignoreCache=true", wait, timeout)
+	debugParam("wait=%v timeout=%d preserveScroll=%v ignoreCache=true", wait, timeout, preserveScroll)
 
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
@@ -46,15 +52,16 @@ func runReload(cmd *cobra.Command, args []string) error {
 
 	// Always do hard reload (ignore cache)
 	params, err := json.Marshal(ipc.ReloadParams{
-		IgnoreCache: true,
-		Wait:        wait,
-		Timeout:     timeout,
+		IgnoreCache:    true,
+		Wait:           wait,
+		Timeout:        timeout,
+		PreserveScroll: preserveScroll,
 	})
 	if err != nil {
 		return outputError(err.Error())
 	}
 
-	debugRequest("reload", fmt.Sprintf("wait=%v timeout=%d ignoreCache=true", wait, timeout))
+	debugRequest("reload", fmt.Sprintf("wait=%v timeout=%d preserveScroll=%v ignoreCache=true", wait, timeout, preserveScroll))
 	ipcStart := time.Now()
 
 	resp, err := exec.Execute(ipc.Request{
@@ -69,7 +76,7 @@ func runReload(cmd *cobra.Command, args []string) error {
 	}
 
 	if !resp.OK {
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: include URL and title
@@ -84,6 +91,9 @@ func runReload(cmd *cobra.Command, args []string) error {
 			"url":   data.URL,
 			"title": data.Title,
 		}
+		if data.Scroll != nil {
+			result["scroll"] = data.Scroll
+		}
 		return outputJSON(os.Stdout, result)
 	}
 
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/grantcarthew/webctl/internal/jsonpatch"
+	"github.com/spf13/cobra"
+)
+
+var rewriteCmd = &cobra.Command{
+	Use:   "rewrite",
+	Short: "Edit response bodies matching a URL before the page sees them",
+	Long: `Intercepts responses whose URL matches a pattern and edits their bodies
+before the page sees them: with a JSON patch, or by replacing text. Use it to
+flip a server feature flag or fake an entitlement client-side, against the
+real backend. Without a subcommand, lists rewrites.
+
+Rewrites apply to every tab, including tabs opened later, and last until they
+are removed or the daemon stops. Every rewrite matching a response applies,
+in the order they were added.
+
+Subcommands:
+  add --url <pattern>   Add a rewrite
+  list                  List rewrites with how often each applied
+  remove <id>           Remove a rewrite
+  clear                 Remove every rewrite
+
+Examples:
+  rewrite add --url /api/flags --json-patch flags.json
+  rewrite add --url /api/me --replace '"plan":"free"="plan":"pro"'
+  rewrite
+  rewrite remove w1`,
+	Args: cobra.NoArgs,
+	RunE: runRewriteList,
+}
+
+var rewriteAddCmd = &cobra.Command{
+	Use:   "add --url <pattern>",
+	Short: "Add a rewrite",
+	Long: `Adds a rewrite for responses whose URL matches --url. A pattern may use * for
+any run of characters and ? for one character, and then matches the whole
+URL; a pattern without either matches anywhere in the URL.
+
+--json-patch reads an RFC 6902 JSON Patch: a list of add, remove, replace,
+move, copy, and test operations addressed by JSON Pointer. The patched body
+is written back as compact JSON. A response the patch cannot apply to (not
+JSON, a missing path, a failed test) is passed on unchanged and counted as
+failed in 'rewrite list'.
+
+--replace FROM=TO replaces every occurrence of FROM in the body with TO,
+after the patch. It splits at the first =, so FROM cannot contain one.
+Repeat it for several replacements, applied in order.
+
+Redirects and failed requests are never rewritten.
+
+Flags:
+  --url <pattern>        URL pattern to match (required)
+  --json-patch <file>    JSON Patch file to apply
+  --replace <from=to>    Replace text in the body (repeatable)
+
+Patch file:
+  [{"op": "replace", "path": "/features/newCheckout", "value": true},
+   {"op": "add", "path": "/user/entitlements/-", "value": "pro"}]
+
+Examples:
+  rewrite add --url /api/flags --json-patch flags.json
+  rewrite add --url "*/api/user*" --replace '"admin":false="admin":true'
+  rewrite add --url /config.js --replace "debug: false=debug: true"
+
+Response:
+  Rewriting */api/flags* as w1: JSON patch (2 ops)
+
+Error cases:
+  - "--url is required" - no pattern given
+  - "give --json-patch or --replace" - nothing to edit
+  - "invalid JSON patch" - the file is not a valid patch`,
+	Args: cobra.NoArgs,
+	RunE: runRewriteAdd,
+}
+
+var rewriteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List rewrites",
+	Long: `Lists rewrites in the order they were added, with their edits, how many
+responses each rewrote, and how many it passed on unchanged because its patch
+failed, with the latest reason.
+
+Response:
+  w1  */api/flags*  JSON patch (2 ops)  5 rewritten, 1 failed
+        op 0 (replace /beta): path not found
+  w2  */api/me*  replace "free" → "pro"  3 rewritten, 0 failed`,
+	Args: cobra.NoArgs,
+	RunE: runRewriteList,
+}
+
+var rewriteRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a rewrite",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRewriteRemove,
+}
+
+var rewriteClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every rewrite",
+	Args:  cobra.NoArgs,
+	RunE:  runRewriteClear,
+}
+
+func init() {
+	rewriteAddCmd.Flags().String("url", "", "URL pattern to match (* any run, ? one character)")
+	rewriteAddCmd.Flags().String("json-patch", "", "JSON Patch (RFC 6902) file to apply")
+	rewriteAddCmd.Flags().StringArray("replace", nil, "Replace text in the body: FROM=TO (repeatable)")
+
+	rewriteCmd.AddCommand(rewriteAddCmd, rewriteListCmd, rewriteRemoveCmd, rewriteClearCmd)
+	rootCmd.AddCommand(rewriteCmd)
+}
+
+func runRewriteAdd(cmd *cobra.Command, args []string) error {
+	t := startTimer("rewrite add")
+	defer t.log()
+
+	url, _ := cmd.Flags().GetString("url")
+	patchFile, _ := cmd.Flags().GetString("json-patch")
+	replaces, _ := cmd.Flags().GetStringArray("replace")
+
+	if url == "" {
+		return outputCodedError(ipc.CodeInvalidArgs, "--url is required")
+	}
+	if patchFile == "" && len(replaces) == 0 {
+		return outputError("give --json-patch or --replace")
+	}
+
+	params := ipc.RewriteParams{Action: "add", URL: url}
+	if patchFile != "" {
+		data, err := os.ReadFile(patchFile)
+		if err != nil {
+			return outputError(err.Error())
+		}
+		if _, err := jsonpatch.Parse(data); err != nil {
+			return outputError(fmt.Sprintf("%s: %v", patchFile, err))
+		}
+		params.JSONPatch = data
+	}
+	for _, r := range replaces {
+		from, to, ok := strings.Cut(r, "=")
+		if !ok || from == "" {
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid --replace %q (use FROM=TO)", r))
+		}
+		params.Replace = append(params.Replace, ipc.RewriteReplace{From: from, To: to})
+	}
+
+	data, err := rewriteRequest(params)
+	if err != nil {
+		return err
+	}
+	if len(data.Rewrites) == 0 {
+		return outputError("daemon did not return the rewrite")
+	}
+	r := data.Rewrites[0]
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":      true,
+			"rewrite": r,
+		})
+	}
+	fmt.Fprintf(os.Stdout, "Rewriting %s as %s: %s\n", r.URL, r.ID, format.RewriteEdits(r))
+	return nil
+}
+
+func runRewriteList(cmd *cobra.Command, args []string) error {
+	t := startTimer("rewrite list")
+	defer t.log()
+
+	data, err := rewriteRequest(ipc.RewriteParams{Action: "list"})
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":       true,
+			"rewrites": data.Rewrites,
+		})
+	}
+	return format.Rewrites(os.Stdout, data.Rewrites)
+}
+
+func runRewriteRemove(cmd *cobra.Command, args []string) error {
+	t := startTimer("rewrite remove")
+	defer t.log()
+
+	if _, err := rewriteRequest(ipc.RewriteParams{Action: "remove", ID: args[0]}); err != nil {
+		return err
+	}
+	return outputSuccess(nil)
+}
+
+func runRewriteClear(cmd *cobra.Command, args []string) error {
+	t := startTimer("rewrite clear")
+	defer t.log()
+
+	if _, err := rewriteRequest(ipc.RewriteParams{Action: "clear"}); err != nil {
+		return err
+	}
+	return outputSuccess(nil)
+}
+
+// rewriteRequest sends a rewrite action to the daemon.
+func rewriteRequest(params ipc.RewriteParams) (ipc.RewriteData, error) {
+	var data ipc.RewriteData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("rewrite", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "rewrite", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
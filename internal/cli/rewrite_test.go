@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func newRewriteAddTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("url", "", "")
+	cmd.Flags().String("json-patch", "", "")
+	cmd.Flags().StringArray("replace", nil, "")
+	return cmd
+}
+
+func TestRunRewriteAdd(t *testing.T) {
+	var got ipc.RewriteParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &got)
+		return ipc.SuccessResponse(ipc.RewriteData{Rewrites: []ipc.RewriteInfo{{
+			ID: "w1", URL: "*" + got.URL + "*", PatchOps: 1, Replace: got.Replace,
+		}}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	patch := filepath.Join(t.TempDir(), "flags.json")
+	if err := os.WriteFile(patch, []byte(`[{"op":"replace","path":"/beta","value":true}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := newRewriteAddTestCmd()
+	_ = cmd.Flags().Set("url", "/api/flags")
+	_ = cmd.Flags().Set("json-patch", patch)
+	_ = cmd.Flags().Set("replace", "free=pro")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runRewriteAdd(cmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Action != "add" || got.URL != "/api/flags" || !strings.Contains(string(got.JSONPatch), `"/beta"`) ||
+		len(got.Replace) != 1 || got.Replace[0] != (ipc.RewriteReplace{From: "free", To: "pro"}) {
+		t.Errorf("params = %+v", got)
+	}
+	if out != "Rewriting */api/flags* as w1: JSON patch (1 op), replace \"free\" → \"pro\"\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunRewriteAdd_Validation(t *testing.T) {
+	dir := t.TempDir()
+	badPatch := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(badPatch, []byte(`[{"op":"set","path":"/a"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  string
+	}{
+		{"no url", map[string]string{"replace": "a=b"}, "--url is required"},
+		{"no edit", map[string]string{"url": "/api"}, "give --json-patch or --replace"},
+		{"bad replace", map[string]string{"url": "/api", "replace": "nothing"}, "use FROM=TO"},
+		{"bad patch", map[string]string{"url": "/api", "json-patch": badPatch}, `unknown op "set"`},
+		{"missing file", map[string]string{"url": "/api", "json-patch": filepath.Join(dir, "none.json")}, "no such file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+				called = true
+				return ipc.SuccessResponse(nil), nil
+			}}
+			defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+			cmd := newRewriteAddTestCmd()
+			for k, v := range tt.flags {
+				_ = cmd.Flags().Set(k, v)
+			}
+			var err error
+			captureStream(t, &os.Stderr, func() {
+				err = runRewriteAdd(cmd, nil)
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+			if called {
+				t.Error("daemon was called for an invalid rewrite")
+			}
+		})
+	}
+}
+
+func TestRunRewriteList(t *testing.T) {
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.SuccessResponse(ipc.RewriteData{Rewrites: []ipc.RewriteInfo{
+			{ID: "w1", URL: "*/api/flags*", PatchOps: 2, Hits: 5, Errors: 1, LastError: "op 0 (replace /beta): path not found"},
+		}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runRewriteList(rewriteListCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "w1  */api/flags*  JSON patch (2 ops)  5 rewritten, 1 failed\n" +
+		"      op 0 (replace /beta): path not found\n"
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+}
@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"golang.org/x/term"
@@ -20,9 +23,11 @@ import (
 var ErrNoMatches = errors.New("no matches found")
 
 // printedError wraps an error that has already been printed to stderr.
-// Used to prevent double-printing in main.go.
+// Used to prevent double-printing in main.go. Code is the error class (an
+// ipc.Code* constant) that picks the exit code; empty exits 1.
 type printedError struct {
-	err error
+	err  error
+	code string
 }
 
 func (e printedError) Error() string {
@@ -39,6 +44,75 @@ func IsPrintedError(err error) bool {
 	return errors.As(err, &pe)
 }
 
+// exitCodes maps error codes to process exit codes.
+var exitCodes = map[string]int{
+	ipc.CodeFailed:          1,
+	ipc.CodeInvalidArgs:     2,
+	ipc.CodeDaemonDown:      3,
+	ipc.CodeNoSession:       4,
+	ipc.CodeElementNotFound: 5,
+	ipc.CodeTimeout:         6,
+	ipc.CodeNotFound:        7,
+}
+
+// usageError marks an error in the command line or config rather than in the
+// command's work. Flag parsing, argument validation, and prepareCommand return
+// one so the error exits with the invalid-args code.
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+// usageErrors are fragments of cobra's usage messages, matched only for errors
+// that reach main without a usageError, such as an unknown command.
+var usageErrors = []string{
+	"arg(s)",
+	"unknown flag",
+	"invalid argument \"",
+	"unknown shorthand flag",
+	"flag needs an argument",
+	"required flag",
+	"cannot be used together",
+	"none of the others can be",
+	"is only supported by",
+	"config: ",
+}
+
+// ErrorCode returns the error class of an error returned by Execute. Printed
+// and usage errors carry their own; the rest are classified by message.
+func ErrorCode(err error) string {
+	var pe printedError
+	if errors.As(err, &pe) {
+		return pe.code
+	}
+	var ue usageError
+	if errors.As(err, &ue) {
+		return ipc.CodeInvalidArgs
+	}
+	msg := err.Error()
+	for _, fragment := range usageErrors {
+		if strings.Contains(msg, fragment) {
+			return ipc.CodeInvalidArgs
+		}
+	}
+	return ipc.ErrorCode(msg)
+}
+
+// ExitCode returns the process exit code for an error code.
+func ExitCode(code string) int {
+	if n, ok := exitCodes[code]; ok {
+		return n
+	}
+	return 1
+}
+
 // ErrNoElements indicates a selector matched no elements (informational, not an error).
 var ErrNoElements = errors.New("no elements found")
 
@@ -72,6 +146,22 @@ var JSONOutput bool
 // NoColor disables color output.
 var NoColor bool
 
+// LogLevel is the minimum daemon log level (debug, info, warn, error).
+var LogLevel string
+
+// LogFormat is the daemon's stderr log format (text or json).
+var LogFormat string
+
+// Output selects the output format (see format.OutputFormats). --json is
+// shorthand for --output json.
+var Output string
+
+// Preset selects a named config preset (see 'webctl config').
+var Preset string
+
+// Socket overrides the daemon socket path (see ipc.SocketEnv).
+var Socket string
+
 // rootHelpTemplate appends the AI agent help topics block after the standard
 // usage output so the topic list lives at the bottom of `webctl --help`.
 // The {{if not .HasParent}} guard scopes the topics block to the root command:
@@ -80,7 +170,7 @@ var NoColor bool
 const rootHelpTemplate = `{{with (or .Long .Short)}}{{. | trimTrailingWhitespaces}}
 
 {{end}}{{if or .Runnable .HasSubCommands}}{{.UsageString}}{{end}}{{if not .HasParent}}
-{{agentHelpTopics}}
+{{agentHelpTopics}}{{plugins}}
 {{end}}`
 
 var rootCmd = &cobra.Command{
@@ -96,6 +186,12 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&Debug, "debug", false, "Enable verbose debug output")
 	rootCmd.PersistentFlags().BoolVar(&JSONOutput, "json", false, "Output in JSON format (default is text)")
 	rootCmd.PersistentFlags().BoolVar(&NoColor, "no-color", false, "Disable color output")
+	rootCmd.PersistentFlags().StringVar(&Output, "output", "", "Output format: "+strings.Join(format.OutputFormats, ", ")+" (csv and table for console, network, cookies)")
+	rootCmd.PersistentFlags().StringVar(&LogLevel, "log-level", "", "Daemon log level: debug, info, warn, error (default info, or debug with --debug)")
+	rootCmd.PersistentFlags().StringVar(&LogFormat, "log-format", "", "Daemon stderr log format: text or json (default text)")
+	rootCmd.PersistentFlags().StringVar(&Preset, "preset", "", "Apply a named config preset (or set "+presetEnv+")")
+	rootCmd.PersistentFlags().StringVar(&Socket, "socket", "", "Daemon socket path, for running separate daemons (or set "+ipc.SocketEnv+")")
+	rootCmd.PersistentPreRunE = prepareCommand
 	rootCmd.SetVersionTemplate(`webctl version {{.Version}}
 Repository: https://github.com/grantcarthew/webctl
 Report issues: https://github.com/grantcarthew/webctl/issues/new
@@ -110,9 +206,103 @@ Report issues: https://github.com/grantcarthew/webctl/issues/new
 	}
 
 	cobra.AddTemplateFunc("agentHelpTopics", agentHelpTopicsBlock)
+	cobra.AddTemplateFunc("plugins", pluginsHelpBlock)
 	rootCmd.SetHelpTemplate(rootHelpTemplate)
 }
 
+// prepareCommand applies the root persistent flags, the config file, and CI
+// mode before any command runs. Its errors are usage errors.
+func prepareCommand(cmd *cobra.Command, args []string) error {
+	_, autoStartSuppressed = cmd.Annotations[noAutoStartAnnotation]
+	if err := applySocket(); err != nil {
+		return usageError{err}
+	}
+	if err := applyCI(cmd); err != nil {
+		return usageError{err}
+	}
+	if err := applyConfig(cmd); err != nil {
+		return usageError{err}
+	}
+	if err := resolveOutput(cmd); err != nil {
+		return usageError{err}
+	}
+	return nil
+}
+
+// markUsageErrors makes cobra's flag and argument errors usage errors. Cobra
+// checks required flags and flag groups after Args, so the wrapped Args runs
+// those checks first to claim their errors too.
+func markUsageErrors(cmd *cobra.Command) {
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+	if cmd == rootCmd {
+		cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+			return usageError{err}
+		})
+		return
+	}
+	validate := cmd.Args
+	cmd.Args = func(cmd *cobra.Command, args []string) error {
+		if validate != nil {
+			if err := validate(cmd, args); err != nil {
+				return usageError{err}
+			}
+		}
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return usageError{err}
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return usageError{err}
+		}
+		return nil
+	}
+}
+
+// commandEnv holds the values environment variables had before the current
+// command changed them with setCommandEnv; nil means unset.
+var commandEnv = map[string]*string{}
+
+// setCommandEnv sets an environment variable for the rest of this command, so
+// it reaches the daemon and auto-started daemons. ExecuteArgs restores the
+// previous value so one REPL command does not leak into the next.
+func setCommandEnv(key, value string) error {
+	if _, saved := commandEnv[key]; !saved {
+		if prev, ok := os.LookupEnv(key); ok {
+			commandEnv[key] = &prev
+		} else {
+			commandEnv[key] = nil
+		}
+	}
+	return os.Setenv(key, value)
+}
+
+// restoreCommandEnv undoes every setCommandEnv since the last restore.
+func restoreCommandEnv() {
+	for key, prev := range commandEnv {
+		if prev == nil {
+			_ = os.Unsetenv(key)
+		} else {
+			_ = os.Setenv(key, *prev)
+		}
+	}
+	clear(commandEnv)
+}
+
+// applySocket makes --socket the socket path for this command. The
+// environment carries it to the daemon and to auto-started daemons.
+func applySocket() error {
+	if Socket == "" {
+		return nil
+	}
+	path, err := filepath.Abs(Socket)
+	if err != nil {
+		return fmt.Errorf("invalid --socket %q: %w", Socket, err)
+	}
+	debugf("PARAM", "socket=%s", path)
+	return setCommandEnv(ipc.SocketEnv, path)
+}
+
 // debugf logs a debug message if debug mode is enabled.
 // Format: [DEBUG] [HH:MM:SS.mmm] [CATEGORY] message
 func debugf(category, format string, args ...any) {
@@ -189,37 +379,90 @@ func (t *timer) log() {
 // commandGroups assigns each top-level command to a help-rendering group.
 // Commands not listed here fall under cobra's "Additional Commands" section.
 var commandGroups = map[string]string{
-	"start":      "lifecycle",
-	"status":     "lifecycle",
-	"stop":       "lifecycle",
-	"navigate":   "navigation",
-	"reload":     "navigation",
-	"back":       "navigation",
-	"forward":    "navigation",
-	"tab":        "tabs",
-	"html":       "observation",
-	"markdown":   "observation",
-	"css":        "observation",
-	"console":    "observation",
-	"network":    "observation",
-	"cookies":    "observation",
-	"screenshot": "observation",
-	"eval":       "observation",
-	"click":      "interaction",
-	"type":       "interaction",
-	"select":     "interaction",
-	"scroll":     "interaction",
-	"focus":      "interaction",
-	"key":        "interaction",
-	"ready":      "sync",
-	"clear":      "buffers",
-	"serve":      "server",
+	"start":           "lifecycle",
+	"status":          "lifecycle",
+	"stop":            "lifecycle",
+	"logs":            "lifecycle",
+	"events":          "lifecycle",
+	"doctor":          "lifecycle",
+	"config":          "lifecycle",
+	"schema":          "lifecycle",
+	"head":            "lifecycle",
+	"headless":        "lifecycle",
+	"devtools":        "lifecycle",
+	"navigate":        "navigation",
+	"reload":          "navigation",
+	"back":            "navigation",
+	"forward":         "navigation",
+	"history":         "navigation",
+	"open":            "navigation",
+	"bookmark":        "navigation",
+	"cache-disable":   "navigation",
+	"cache-clear":     "navigation",
+	"throttle":        "navigation",
+	"emulate":         "navigation",
+	"tab":             "tabs",
+	"context":         "tabs",
+	"html":            "observation",
+	"markdown":        "observation",
+	"css":             "observation",
+	"console":         "observation",
+	"network":         "observation",
+	"buffer":          "lifecycle",
+	"cookies":         "observation",
+	"screenshot":      "observation",
+	"eval":            "observation",
+	"highlight":       "observation",
+	"pick":            "observation",
+	"selector":        "observation",
+	"box":             "observation",
+	"attr":            "observation",
+	"count":           "observation",
+	"elements":        "observation",
+	"styles":          "observation",
+	"watch-dom":       "observation",
+	"perf":            "observation",
+	"heap":            "observation",
+	"bench":           "observation",
+	"guard":           "observation",
+	"assert":          "observation",
+	"frames":          "observation",
+	"dom":             "observation",
+	"describe":        "observation",
+	"meta":            "observation",
+	"source":          "observation",
+	"logpoint":        "observation",
+	"exceptions":      "observation",
+	"extensions":      "observation",
+	"monitor":         "observation",
+	"pool":            "observation",
+	"click":           "interaction",
+	"type":            "interaction",
+	"select":          "interaction",
+	"check":           "interaction",
+	"uncheck":         "interaction",
+	"scroll":          "interaction",
+	"zoom":            "interaction",
+	"dismiss-banners": "interaction",
+	"mouse":           "interaction",
+	"focus":           "interaction",
+	"key":             "interaction",
+	"flow":            "interaction",
+	"login":           "interaction",
+	"record-flow":     "interaction",
+	"ready":           "sync",
+	"wait-request":    "sync",
+	"clear":           "buffers",
+	"serve":           "server",
+	"mock":            "server",
+	"watch":           "server",
+	"rewrite":         "server",
 }
 
 var groupsOnce sync.Once
 
-// setupCommandGroups registers help groups on rootCmd and assigns each
-// subcommand its GroupID. Runs lazily so it executes after every command's
+// setupCommandGroups registers help groups on rootCmd, assigns each
+// subcommand its GroupID, and marks usage errors. Runs lazily so it executes after every command's
 // init() has registered the command itself.
 func setupCommandGroups() {
 	groupsOnce.Do(func() {
@@ -238,6 +481,7 @@ func setupCommandGroups() {
 				cmd.GroupID = id
 			}
 		}
+		markUsageErrors(rootCmd)
 	})
 }
 
@@ -247,6 +491,9 @@ func Execute() error {
 	setupCommandGroups()
 	// Try abbreviation expansion for CLI commands
 	args := os.Args[1:]
+	if name, path, globals, rest := pluginCommand(args); name != "" {
+		return runPlugin(name, path, globals, rest)
+	}
 	if len(args) > 0 {
 		if expanded := tryExpandCommand(args[0]); expanded != "" {
 			args[0] = expanded
@@ -341,9 +588,26 @@ func ExecuteArgs(args []string) (recognized bool, err error) {
 	JSONOutput = false
 	NoColor = false
 
+	// Undo the environment and mode changes made by prepareCommand
+	restoreCommandEnv()
+	ciMode = false
+	autoStartSuppressed = false
+
 	return true, err
 }
 
+// CommandNames returns the names of the commands ExecuteArgs runs, for REPL
+// tab completion.
+func CommandNames() []string {
+	var names []string
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.IsAvailableCommand() {
+			names = append(names, cmd.Name())
+		}
+	}
+	return names
+}
+
 // isWriterTTY reports whether w is an *os.File backed by a terminal.
 // Non-file writers (bytes.Buffer, pipes wrapped in io.Writer) report false.
 func isWriterTTY(w io.Writer) bool {
@@ -354,11 +618,50 @@ func isWriterTTY(w io.Writer) bool {
 	return term.IsTerminal(int(f.Fd()))
 }
 
+// recordsAnnotation marks list commands that render --output csv and table.
+const recordsAnnotation = "webctl:records"
+
+// resolveOutput validates --output for cmd and folds it into JSONOutput: the
+// JSON-shaped formats (json, jsonl, yaml) take every command's JSON path.
+func resolveOutput(cmd *cobra.Command) error {
+	switch Output {
+	case "", format.OutputText:
+		return nil
+	case format.OutputJSON, format.OutputJSONL, format.OutputYAML:
+	case format.OutputCSV, format.OutputTable:
+		if _, ok := cmd.Annotations[recordsAnnotation]; !ok {
+			return fmt.Errorf("--output %s is only supported by console, network, and cookies", Output)
+		}
+	default:
+		return fmt.Errorf("invalid --output %q (use %s)", Output, strings.Join(format.OutputFormats, ", "))
+	}
+	if JSONOutput && Output != format.OutputJSON && cmd.Flags().Changed("json") {
+		return fmt.Errorf("--json and --output %s cannot be used together", Output)
+	}
+	JSONOutput = true
+	return nil
+}
+
+// recordOutput reports whether list output should be rendered as records
+// (jsonl, yaml, csv, or table) rather than the JSON envelope or text.
+func recordOutput() bool {
+	switch Output {
+	case format.OutputJSONL, format.OutputYAML, format.OutputCSV, format.OutputTable:
+		return true
+	}
+	return false
+}
+
 // outputJSON writes a JSON response to the given writer.
-// Pretty prints if the writer itself is a TTY, compact otherwise.
+// Pretty prints if the writer itself is a TTY, compact otherwise. With
+// --output yaml the same structure is written as YAML, and with --output jsonl
+// it is always compact.
 func outputJSON(w io.Writer, data any) error {
+	if Output == format.OutputYAML {
+		return format.YAML(w, data)
+	}
 	enc := json.NewEncoder(w)
-	if isWriterTTY(w) {
+	if isWriterTTY(w) && Output != format.OutputJSONL {
 		enc.SetIndent("", "  ")
 	}
 	return enc.Encode(data)
@@ -395,13 +698,32 @@ func outputSuccess(data any) error {
 }
 
 // outputError writes an error response to stderr and returns a printedError.
-// Uses text format by default, JSON if --json flag is set.
+// Uses text format by default, JSON if --json flag is set. The error code is
+// classified from the message; callers that know the class use
+// outputCodedError.
 // The returned error is wrapped in printedError to prevent double-printing.
 func outputError(msg string) error {
+	return outputCodedError(ipc.ErrorCode(msg), msg)
+}
+
+// outputResponseError reports a failed daemon response, keeping the daemon's
+// error code.
+func outputResponseError(resp ipc.Response) error {
+	code := resp.Code
+	if code == "" {
+		code = ipc.ErrorCode(resp.Error)
+	}
+	return outputCodedError(code, resp.Error)
+}
+
+// outputCodedError writes an error with an explicit code; JSON output carries
+// the code alongside the message.
+func outputCodedError(code, msg string) error {
 	if JSONOutput {
 		resp := map[string]any{
 			"ok":    false,
 			"error": msg,
+			"code":  code,
 		}
 		_ = outputJSON(os.Stderr, resp)
 	} else {
@@ -413,23 +735,32 @@ func outputError(msg string) error {
 			_, _ = fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
 		}
 	}
-	return printedError{err: fmt.Errorf("%s", msg)}
+	return printedError{err: fmt.Errorf("%s", msg), code: code}
 }
 
 // outputNotice writes a notice message to stderr without "Error:" prefix.
 // Used for informational messages that still result in non-zero exit code.
 // The returned error is wrapped in printedError to prevent double-printing.
 func outputNotice(msg string) error {
+	return outputCodedNotice("", msg)
+}
+
+// outputCodedNotice writes a notice that reports a classified failure, such as
+// a selector matching nothing, so JSON output and the exit code carry its code.
+func outputCodedNotice(code, msg string) error {
 	if JSONOutput {
 		resp := map[string]any{
 			"ok":      false,
 			"message": msg,
 		}
+		if code != "" {
+			resp["code"] = code
+		}
 		_ = outputJSON(os.Stderr, resp)
 	} else {
 		fmt.Fprintln(os.Stderr, msg)
 	}
-	return printedError{err: errors.New(msg)}
+	return printedError{err: errors.New(msg), code: code}
 }
 
 // outputHint writes a hint message to stderr in text mode only.
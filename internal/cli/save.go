@@ -40,7 +40,7 @@ func runSave(cmd *cobra.Command, args []string, spec saveSpec) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	content, err := spec.produce(cmd)
@@ -79,7 +79,7 @@ func saveSentinelNotice(err error) (error, bool) {
 	case errors.Is(err, ErrNoMatches):
 		return outputNotice("No matches found"), true
 	case errors.Is(err, ErrNoElements):
-		return outputNotice("No elements found"), true
+		return outputCodedNotice(ipc.CodeElementNotFound, "No elements found"), true
 	case errors.Is(err, ErrNoRules):
 		return outputNotice("No rules found"), true
 	}
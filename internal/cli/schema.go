@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/grantcarthew/webctl/internal/jsondiff"
+	"github.com/grantcarthew/webctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [command]",
+	Short: "Print JSON Schema for --json output and IPC messages",
+	Long: `Prints JSON Schema (draft 2020-12) generated from webctl's own types, so
+other tools can validate and generate code against its output.
+
+With a command, prints the schema of that command's --json output. Subcommands
+are given as separate words. Commands that stream JSON Lines (logs,
+watch-dom) describe a single line.
+
+Without arguments, prints one document covering every command under
+"commands", the error envelope under "error", and every IPC request, response,
+and data structure under "$defs".
+
+Examples:
+  schema                                # Everything
+  schema navigate                       # navigate --json
+  schema css computed                   # Subcommand output
+  schema console | jq '.properties.entries'
+
+Error cases:
+  - "no schema for \"help\"" - command has no JSON output`,
+	RunE: runSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// schemaField is shorthand for a required schema field.
+func schemaField(name string, value any) schema.Field {
+	return schema.Field{Name: name, Value: value}
+}
+
+// optionalField is shorthand for an optional schema field.
+func optionalField(name string, value any) schema.Field {
+	return schema.Field{Name: name, Value: value, Optional: true}
+}
+
+var (
+	pageFields        = []schema.Field{schemaField("url", ""), schemaField("title", "")}
+	pathFields        = []schema.Field{schemaField("path", "")}
+	contextListFields = []schema.Field{schemaField("contexts", []ipc.ContextInfo{})}
+	eventsFields      = []schema.Field{schemaField("events", []ipc.DaemonEvent{}), schemaField("dropped", 0)}
+	bufferFields      = []schema.Field{schemaField("buffers", []ipc.BufferInfo{}), schemaField("captureBodies", false)}
+	// browserModeFields are the fields of 'head' and 'headless' output.
+	browserModeFields = []schema.Field{schemaField("headless", false), schemaField("changed", false), schemaField("tabs", 0), schemaField("cookies", 0)}
+	// mouseFields are the pointer state every 'mouse' subcommand outputs.
+	mouseFields = []schema.Field{schemaField("x", 0), schemaField("y", 0), schemaField("buttons", []string{})}
+	// poolFields are the pool status every 'pool' action but map outputs.
+	poolFields = []schema.Field{schemaField("size", 0), schemaField("busy", 0)}
+)
+
+// commandSchemas lists the fields each command's --json output carries
+// alongside "ok". Keep in step with the command's JSON branch.
+var commandSchemas = map[string][]schema.Field{
+	"attr get":          {schemaField("value", "")},
+	"attr list":         {schemaField("elements", []ipc.ElementWithAttributes{})},
+	"attr set":          {schemaField("count", 0)},
+	"back":              pageFields,
+	"buffer":            bufferFields,
+	"buffer set":        bufferFields,
+	"buffer status":     bufferFields,
+	"box":               {schemaField("elements", []ipc.ElementBox{}), schemaField("scrollX", 0.0), schemaField("scrollY", 0.0), schemaField("viewportWidth", 0.0), schemaField("viewportHeight", 0.0)},
+	"clear":             {schemaField("data", []schema.Field{schemaField("message", ""), optionalField("session", "")})},
+	"click":             {optionalField("warning", "")},
+	"config":            {schemaField("files", map[string]string{}), schemaField("values", map[string]string{})},
+	"config get":        {schemaField("key", ""), schemaField("value", "")},
+	"config list":       {schemaField("files", map[string]string{}), schemaField("values", map[string]string{})},
+	"config set":        pathFields,
+	"config unset":      pathFields,
+	"console":           {schemaField("entries", []ipc.ConsoleEntry{}), schemaField("count", 0)},
+	"console save":      pathFields,
+	"cookies":           {schemaField("cookies", []ipc.Cookie{}), schemaField("count", 0)},
+	"cookies delete":    nil,
+	"cookies diff":      {schemaField("identical", false), schemaField("changes", []cookieChange{})},
+	"cookies save":      pathFields,
+	"cookies set":       nil,
+	"context":           contextListFields,
+	"context close":     contextListFields,
+	"context list":      contextListFields,
+	"context new":       {schemaField("id", ""), optionalField("incognito", false), schemaField("tabs", []ipc.PageSession{})},
+	"count":             {schemaField("count", 0), optionalField("message", ""), optionalField("artifacts", []string{})},
+	"bench compare":     {schemaField("runs", 0), schemaField("warm", false), schemaField("a", benchSide{}), schemaField("b", benchSide{}), schemaField("delta", map[string]float64{}), optionalField("cpuThrottle", 0.0)},
+	"bookmark":          {schemaField("bookmarks", map[string]string{})},
+	"bookmark add":      pathFields,
+	"bookmark list":     {schemaField("bookmarks", map[string]string{})},
+	"bookmark remove":   pathFields,
+	"css":               {schemaField("css", "")},
+	"css computed":      {schemaField("elements", []ipc.ElementWithStyles{})},
+	"css dump":          {optionalField("styleSheet", ipc.CSSStyleSheet{}), optionalField("paths", []string{})},
+	"css get":           {schemaField("value", "")},
+	"css inline":        {schemaField("elements", []ipc.ElementWithStyles{})},
+	"css list":          {schemaField("styleSheets", []ipc.CSSStyleSheet{})},
+	"css matched":       {schemaField("matched", []ipc.CSSMatchedRule{})},
+	"css save":          pathFields,
+	"css unused":        {schemaField("unused", []ipc.CSSUnusedSheet{})},
+	"describe":          {schemaField("url", ""), schemaField("title", ""), schemaField("landmarks", []ipc.DescribeLandmark{}), schemaField("headings", []ipc.DescribeHeading{}), schemaField("controls", []ipc.DescribeControl{}), schemaField("moreControls", 0), schemaField("forms", []ipc.DescribeForm{})},
+	"dismiss-banners":   {schemaField("dismissed", []ipc.BannerHit{}), schemaField("auto", false)},
+	"doctor":            {schemaField("checks", []doctorCheck{})},
+	"dom snapshot":      {schemaField("url", ""), schemaField("title", ""), schemaField("nodes", []ipc.DOMNode{})},
+	"elements":          {schemaField("elements", []ipc.InteractiveElement{})},
+	"eval":              {optionalField("value", nil)},
+	"events":            eventsFields,
+	"events show":       eventsFields,
+	"extensions":        {schemaField("extensions", []ipc.ExtensionInfo{})},
+	"extensions list":   {schemaField("extensions", []ipc.ExtensionInfo{})},
+	"flow run":          {schemaField("flow", ""), schemaField("passed", 0), schemaField("failed", 0), schemaField("skipped", 0), schemaField("durationMs", 0), schemaField("artifacts", ""), schemaField("steps", []flowStepResult{})},
+	"focus":             nil,
+	"forward":           pageFields,
+	"frames":            {schemaField("frames", []ipc.FrameInfo{})},
+	"head":              browserModeFields,
+	"headless":          browserModeFields,
+	"guard":             {schemaField("ok", false), schemaField("durationMs", 0), schemaField("failOn", []string{}), schemaField("violations", []guardViolation{})},
+	"heap leakcheck":    {schemaField("action", ""), schemaField("samples", []ipc.HeapSample{}), schemaField("growth", 0), schemaField("monotonic", false), schemaField("constructors", []ipc.HeapConstructorGrowth{})},
+	"heap snapshot":     pathFields,
+	"highlight":         {schemaField("count", 0)},
+	"history":           {schemaField("currentIndex", 0), schemaField("entries", []ipc.HistoryEntry{})},
+	"history go":        pageFields,
+	"html":              {optionalField("elements", []ipc.ElementWithHTML{}), optionalField("html", "")},
+	"html diff":         {schemaField("identical", false), schemaField("diff", "")},
+	"html path":         {schemaField("elements", []ipc.ElementWithHTML{})},
+	"html save":         pathFields,
+	"key":               nil,
+	"login":             {optionalField("login", ""), optionalField("durationMs", 0), optionalField("steps", []flowStepResult{}), optionalField("logins", map[string]string{})},
+	"markdown":          {schemaField("markdown", "")},
+	"markdown save":     pathFields,
+	"meta":              {schemaField("url", ""), schemaField("title", ""), optionalField("description", ""), optionalField("canonical", ""), optionalField("robots", ""), schemaField("hreflang", []ipc.MetaAlternate{}), schemaField("openGraph", []ipc.MetaTag{}), schemaField("twitter", []ipc.MetaTag{}), schemaField("jsonLd", []any{}), optionalField("jsonLdErrors", []string{}), schemaField("microdata", []ipc.MetaItem{})},
+	"monitor":           {schemaField("monitor", ipc.MonitorInfo{})},
+	"monitor list":      {schemaField("monitors", []ipc.MonitorInfo{})},
+	"monitor results":   {schemaField("results", []ipc.MonitorResult{})},
+	"monitor stop":      nil,
+	"pool":              poolFields,
+	"pool close":        poolFields,
+	"pool create":       poolFields,
+	"pool map":          {schemaField("results", []ipc.PoolMapResult{}), schemaField("failed", 0)},
+	"pool status":       poolFields,
+	"exceptions":        {schemaField("mode", ""), schemaField("exceptions", []ipc.PausedException{}), schemaField("dropped", 0)},
+	"exceptions pause":  {schemaField("mode", "")},
+	"exceptions show":   {schemaField("mode", ""), schemaField("exceptions", []ipc.PausedException{}), schemaField("dropped", 0)},
+	"logpoint":          {schemaField("logpoints", []ipc.LogpointInfo{})},
+	"logpoint add":      {schemaField("logpoint", ipc.LogpointInfo{})},
+	"logpoint list":     {schemaField("logpoints", []ipc.LogpointInfo{})},
+	"logpoint remove":   nil,
+	"logpoint clear":    nil,
+	"mock":              {schemaField("mocks", []ipc.MockInfo{})},
+	"mock add":          {schemaField("mock", ipc.MockInfo{})},
+	"mock list":         {schemaField("mocks", []ipc.MockInfo{})},
+	"mock remove":       nil,
+	"mock clear":        nil,
+	"rewrite":           {schemaField("rewrites", []ipc.RewriteInfo{})},
+	"rewrite add":       {schemaField("rewrite", ipc.RewriteInfo{})},
+	"rewrite list":      {schemaField("rewrites", []ipc.RewriteInfo{})},
+	"rewrite remove":    nil,
+	"rewrite clear":     nil,
+	"mouse down":        mouseFields,
+	"mouse move":        mouseFields,
+	"mouse position":    mouseFields,
+	"mouse up":          mouseFields,
+	"mouse wheel":       mouseFields,
+	"navigate":          pageFields,
+	"network":           {schemaField("entries", []ipc.NetworkEntry{}), schemaField("count", 0)},
+	"network save":      pathFields,
+	"assert header":     {schemaField("header", ""), schemaField("regex", ""), schemaField("checked", 0), schemaField("failed", 0), schemaField("results", []headerCheck{})},
+	"network abort":     {optionalField("rule", ipc.AbortRule{}), optionalField("aborted", ipc.HeldRequest{}), optionalField("rules", []ipc.AbortRule{}), optionalField("held", []ipc.HeldRequest{})},
+	"network diff":      {schemaField("identical", false), schemaField("mode", ""), optionalField("changes", []jsondiff.Change{}), optionalField("diff", "")},
+	"network dupes":     {schemaField("groups", []networkDupeGroup{}), schemaField("wastedBytes", 0)},
+	"network tls":       {schemaField("seq", 0), schemaField("requestId", ""), schemaField("url", ""), schemaField("securityState", ""), schemaField("security", ipc.NetworkSecurityDetails{}), schemaField("warnings", []string{})},
+	"open":              pageFields,
+	"perf shifts":       {schemaField("total", 0.0), schemaField("shifts", []ipc.LayoutShift{}), optionalField("cpuThrottle", 0.0)},
+	"pick":              {schemaField("tag", ""), optionalField("id", ""), optionalField("class", ""), schemaField("selector", ""), schemaField("xpath", "")},
+	"ready":             nil,
+	"wait-request":      {schemaField("entry", ipc.NetworkEntry{})},
+	"record-flow start": {schemaField("sessionId", "")},
+	"record-flow stop":  {schemaField("path", ""), schemaField("steps", 0)},
+	"reload":            {schemaField("url", ""), schemaField("title", ""), optionalField("scroll", ipc.ScrollPosition{})},
+	"screenshot":        pathFields,
+	"screenshot save":   pathFields,
+	"screenshot diff":   {schemaField("changedPixels", 0), schemaField("totalPixels", 0), schemaField("percent", 0.0), schemaField("threshold", 0.0), schemaField("baselineSize", imageSize{}), schemaField("currentSize", imageSize{}), optionalField("changedArea", imageArea{}), schemaField("diff", "")},
+	"scroll":            {optionalField("scrolls", 0), optionalField("height", 0)},
+	"check":             {optionalField("checked", false), optionalField("changed", false)},
+	"uncheck":           {optionalField("checked", false), optionalField("changed", false)},
+	"select":            {optionalField("selected", []ipc.SelectedOption{})},
+	"selector":          {schemaField("tag", ""), optionalField("id", ""), optionalField("class", ""), optionalField("fromMatches", 0), schemaField("candidates", []ipc.SelectorCandidate{}), schemaField("xpath", "")},
+	"serve":             {schemaField("mode", ""), schemaField("url", ""), schemaField("port", 0)},
+	"source":            {optionalField("scripts", []ipc.ScriptSource{}), optionalField("script", ipc.ScriptSource{}), optionalField("paths", []string{})},
+	"start":             {schemaField("data", []schema.Field{schemaField("message", ""), schemaField("port", 0)})},
+	"status":            {schemaField("data", ipc.StatusData{})},
+	"stop":              {schemaField("data", []schema.Field{schemaField("message", ""), optionalField("actions", []string{})})},
+	"styles diff":       {schemaField("a", ""), schemaField("b", ""), schemaField("diff", []ipc.CSSPropertyDiff{})},
+	"tab":               {schemaField("activeSession", ""), schemaField("sessions", []tabListSession{})},
+	"tab close":         {schemaField("activeSession", "")},
+	"tab new":           {schemaField("id", ""), schemaField("url", ""), schemaField("title", "")},
+	"tab switch":        {schemaField("activeSession", "")},
+	"type":              nil,
+	"zoom":              {schemaField("factor", 0.0)},
+	"cache-disable":     {schemaField("disabled", false)},
+	"cache-clear":       {schemaField("cleared", []string{}), optionalField("origin", "")},
+	"throttle cpu":      {schemaField("cpu", 0.0)},
+	"emulate idle":      {optionalField("idle", ipc.IdleState{})},
+	"emulate battery":   {optionalField("battery", ipc.BatteryState{})},
+	"devtools":          {schemaField("targetId", ""), schemaField("url", "")},
+}
+
+// streamSchemas describe one line of the commands whose --json output is JSON
+// Lines rather than a single envelope.
+var streamSchemas = map[string]any{
+	"events follow": ipc.DaemonEvent{},
+	"logs": schema.Schema{
+		"type": "object",
+		"properties": schema.Schema{
+			"time":  schema.Schema{"type": "string", "format": "date-time"},
+			"level": schema.Schema{"type": "string"},
+			"msg":   schema.Schema{"type": "string"},
+		},
+		"required": []string{"time", "level", "msg"},
+	},
+	"watch-dom": ipc.DOMMutation{},
+	"watch":     watchCycle{},
+}
+
+// errorFields are the fields of the envelope written to stderr when a command
+// fails or reports a notice.
+var errorFields = []schema.Field{
+	schemaField("ok", false),
+	optionalField("error", ""),
+	optionalField("message", ""),
+	optionalField("code", ""),
+	optionalField("matches", nil),
+	optionalField("sessions", []ipc.PageSession{}),
+}
+
+// ipcSchemaTypes are the IPC messages and data structures exchanged with the
+// daemon, included in the full schema document.
+var ipcSchemaTypes = []any{
+	ipc.Request{}, ipc.Response{},
+	ipc.StatusParams{}, ipc.StatusData{}, ipc.ConsoleData{}, ipc.NetworkData{},
+	ipc.TabParams{}, ipc.TabData{}, ipc.NewTabData{},
+	ipc.ScreenshotParams{}, ipc.ScreenshotData{},
+	ipc.HTMLParams{}, ipc.HTMLData{},
+	ipc.NavigateParams{}, ipc.NavigateData{}, ipc.ReloadParams{},
+	ipc.HistoryParams{}, ipc.HistoryData{},
+	ipc.ReadyParams{}, ipc.ClickParams{}, ipc.FocusParams{},
+	ipc.HighlightParams{}, ipc.HighlightData{},
+	ipc.PickParams{}, ipc.PickData{},
+	ipc.SelectorParams{}, ipc.SelectorData{},
+	ipc.BoxParams{}, ipc.BoxData{},
+	ipc.AttrParams{}, ipc.AttrData{},
+	ipc.CountParams{}, ipc.CountData{},
+	ipc.WatchDOMParams{}, ipc.WatchDOMData{}, ipc.FramesData{},
+	ipc.DOMParams{}, ipc.DOMSnapshotData{}, ipc.DescribeParams{}, ipc.DescribeData{},
+	ipc.ElementsParams{}, ipc.ElementsData{}, ipc.MetaData{},
+	ipc.ClearParams{}, ipc.ClearData{}, ipc.EventsParams{}, ipc.EventsData{},
+	ipc.TypeParams{}, ipc.KeyParams{}, ipc.SelectParams{}, ipc.CheckParams{}, ipc.CheckData{}, ipc.SelectData{}, ipc.SelectedOption{}, ipc.ScrollParams{}, ipc.ScrollData{}, ipc.ScrollPosition{}, ipc.ZoomParams{}, ipc.ZoomData{}, ipc.DismissBannersParams{}, ipc.DismissBannersData{}, ipc.MouseParams{}, ipc.MouseData{},
+	ipc.EvalParams{}, ipc.EvalData{},
+	ipc.CookiesParams{}, ipc.CookiesData{},
+	ipc.CSSParams{}, ipc.CSSData{},
+	ipc.SourceParams{}, ipc.SourceData{},
+	ipc.ServeParams{}, ipc.ServeData{},
+	ipc.RecordParams{}, ipc.RecordData{},
+	ipc.MonitorParams{}, ipc.MonitorData{},
+	ipc.PoolParams{}, ipc.PoolData{}, ipc.PoolMapResult{},
+	ipc.MockParams{}, ipc.MockData{},
+	ipc.AbortParams{}, ipc.AbortData{},
+	ipc.CacheParams{}, ipc.CacheData{},
+	ipc.ThrottleParams{}, ipc.ThrottleData{},
+	ipc.EmulateParams{}, ipc.EmulateData{},
+	ipc.DevToolsData{},
+	ipc.LogpointParams{}, ipc.LogpointData{},
+	ipc.ExceptionsParams{}, ipc.ExceptionsData{},
+	ipc.PerfParams{}, ipc.PerfShiftsData{},
+	ipc.HeapParams{}, ipc.HeapLeakcheckData{}, ipc.HeapSnapshotData{},
+	ipc.BenchParams{}, ipc.BenchData{},
+	ipc.RewriteParams{}, ipc.RewriteData{},
+	ipc.WaitRequestParams{}, ipc.WaitRequestData{},
+}
+
+// commandSchema returns the schema of a command's --json output, or false if
+// the command has none.
+func commandSchema(g *schema.Generator, path string) (schema.Schema, bool) {
+	if fields, ok := commandSchemas[path]; ok {
+		return g.Object(append([]schema.Field{schemaField("ok", true)}, fields...)...), true
+	}
+	if line, ok := streamSchemas[path]; ok {
+		return g.Of(line), true
+	}
+	return nil, false
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	t := startTimer("schema")
+	defer t.log()
+
+	g := schema.NewGenerator()
+
+	if len(args) > 0 {
+		path := strings.Join(args, " ")
+		s, ok := commandSchema(g, path)
+		if !ok {
+			return outputError(fmt.Sprintf("no schema for %q; run 'webctl schema' for every command", path))
+		}
+		debugParam("command=%s", path)
+		return outputJSON(os.Stdout, g.Document("webctl "+path+" --json", s))
+	}
+
+	commands := schema.Schema{}
+	for path := range commandSchemas {
+		commands[path], _ = commandSchema(g, path)
+	}
+	for path := range streamSchemas {
+		commands[path], _ = commandSchema(g, path)
+	}
+	for _, v := range ipcSchemaTypes {
+		g.Of(v)
+	}
+	return outputJSON(os.Stdout, g.Document("webctl", schema.Schema{
+		"commands": commands,
+		"error":    g.Object(errorFields...),
+	}))
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+// TestCommandSchemas_Coverage fails when a command is added without describing
+// its --json output.
+func TestCommandSchemas_Coverage(t *testing.T) {
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		path := configCommandPath(c)
+		generated := strings.HasPrefix(path, "help") || strings.HasPrefix(path, "completion")
+		if c.Runnable() && c != rootCmd && c != schemaCmd && !generated {
+			if _, ok := commandSchema(schema.NewGenerator(), path); !ok {
+				t.Errorf("no schema for %q", path)
+			}
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+
+	for path := range commandSchemas {
+		if cmd, _, err := rootCmd.Find(strings.Fields(path)); err != nil || configCommandPath(cmd) != path {
+			t.Errorf("schema for unknown command %q", path)
+		}
+	}
+}
+
+func TestRunSchema_Command(t *testing.T) {
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runSchema(schemaCmd, []string{"console"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	entries := doc["properties"].(map[string]any)["entries"].(map[string]any)
+	if entries["items"].(map[string]any)["$ref"] != "#/$defs/ConsoleEntry" {
+		t.Errorf("entries should reference ConsoleEntry: %v", entries)
+	}
+	if _, ok := doc["$defs"].(map[string]any)["ConsoleFrame"]; !ok {
+		t.Error("expected nested ConsoleFrame definition")
+	}
+}
+
+func TestRunSchema_All(t *testing.T) {
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runSchema(schemaCmd, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Commands map[string]any `json:"commands"`
+		Error    map[string]any `json:"error"`
+		Defs     map[string]any `json:"$defs"`
+	}
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.Commands["watch-dom"]; !ok || doc.Error == nil {
+		t.Error("expected stream commands and the error envelope")
+	}
+	for _, name := range []string{"Request", "Response", "NavigateParams", "CSSData"} {
+		if _, ok := doc.Defs[name]; !ok {
+			t.Errorf("missing IPC definition %s", name)
+		}
+	}
+}
+
+func TestRunSchema_Unknown(t *testing.T) {
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runSchema(schemaCmd, []string{"help"})
+	})
+	if err == nil || !strings.Contains(err.Error(), `no schema for "help"`) {
+		t.Errorf("expected unknown command error, got %v", err)
+	}
+}
@@ -2,15 +2,20 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	"image/png"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/grantcarthew/webctl/internal/cli/format"
 	"github.com/grantcarthew/webctl/internal/executor"
+	"github.com/grantcarthew/webctl/internal/imagediff"
 	"github.com/grantcarthew/webctl/internal/ipc"
 	"github.com/spf13/cobra"
 )
@@ -28,9 +33,12 @@ Default behavior (no subcommand):
 
 Subcommands:
   save [path]       Save screenshot to file (temp dir if no path given)
+  diff <baseline>   Compare the page against a baseline PNG
 
 Flags:
   --full-page       Capture entire scrollable page instead of viewport only
+  --annotate <sel>  Outline and label the elements a selector matches; a
+                    comma-separated list or repeated flag outlines several
 
 File location:
   Default: /tmp/webctl-screenshots/YY-MM-DD-HHMMSS-{title}.png
@@ -60,6 +68,9 @@ Common patterns:
   click "#toggle-dark-mode"
   screenshot save ./after.png
 
+  # Point a bug report at the exact elements
+  screenshot save ./bug.png --annotate "#checkout,.price"
+
   # Document visual state
   screenshot save ./docs/homepage-full.png --full-page
 
@@ -71,6 +82,9 @@ Common patterns:
   # CI/CD test artifacts
   screenshot save ./test-results/homepage-${BUILD_ID}.png --full-page
 
+  # Visual regression check (exit 1 on change)
+  screenshot diff ./golden/homepage.png --full-page
+
   # Multi-tab capture
   tab switch "Admin Panel"
   screenshot save ./admin.png
@@ -82,6 +96,7 @@ Response:
 
 Error cases:
   - "failed to capture screenshot" - CDP capture failed
+  - "selector '...' matched no elements" - an --annotate selector found nothing
   - "failed to write screenshot: permission denied" - cannot write to path
   - "no active session" - no browser page open
   - "daemon not running" - start daemon first with: webctl start
@@ -110,10 +125,52 @@ Examples:
 	RunE: runScreenshotSave,
 }
 
+var screenshotDiffCmd = &cobra.Command{
+	Use:   "diff <baseline.png>",
+	Short: "Compare the page against a baseline screenshot",
+	Long: `Captures the current page and compares it pixel by pixel against a baseline
+PNG, typically one saved earlier with screenshot save. Writes a diff image
+showing the page faded to grey with changed pixels in red.
+
+Pixels are compared by perceived colour, so the slight shifts of
+anti-aliasing do not count as changes. Where the sizes differ, the area only
+one image covers counts as changed.
+
+Like html diff, the exit code is 0 when the changed share of pixels is at or
+below --threshold and 1 above it, for visual regression checks in CI.
+
+Flags:
+  --threshold <percent>   Changed pixels allowed, as a percentage (default 0.1)
+  --out <path>            Diff image path (default: temp dir, -diff.png)
+  --full-page             Capture the entire page, as the baseline was
+
+Examples:
+  screenshot save ./golden/home.png --full-page     # Once: the baseline
+  screenshot diff ./golden/home.png --full-page     # Later: compare
+  screenshot diff ./golden/home.png --threshold 0 --out ./diff.png
+
+Response:
+  1523 of 921600 pixels differ (0.17%), above the 0.1% threshold
+  Changed area: 640x120 at 0,96
+  Diff: /tmp/webctl-screenshots/24-12-24-143052-example-domain-diff.png
+
+JSON response:
+  {"ok": false, "changedPixels": 1523, "totalPixels": 921600, "percent": 0.17, ...}
+
+Error cases:
+  - "failed to read baseline" - file missing or not a PNG`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScreenshotDiff,
+}
+
 func init() {
 	screenshotCmd.PersistentFlags().Bool("full-page", false, "Capture entire scrollable page instead of viewport")
+	screenshotCmd.PersistentFlags().StringArray("annotate", nil, "Outline and label elements matching selectors (comma-separated, repeatable)")
+
+	screenshotDiffCmd.Flags().Float64("threshold", 0.1, "Percentage of pixels allowed to differ")
+	screenshotDiffCmd.Flags().String("out", "", "Diff image path (default: temp dir)")
 
-	screenshotCmd.AddCommand(screenshotSaveCmd)
+	screenshotCmd.AddCommand(screenshotSaveCmd, screenshotDiffCmd)
 	rootCmd.AddCommand(screenshotCmd)
 }
 
@@ -121,7 +178,7 @@ func init() {
 func runScreenshotDefault(cmd *cobra.Command, args []string) error {
 	// Validate that no arguments were provided (catches unknown subcommands)
 	if len(args) > 0 {
-		return outputError(fmt.Sprintf("unknown command %q for \"webctl screenshot\"", args[0]))
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("unknown command %q for \"webctl screenshot\"", args[0]))
 	}
 
 	return captureAndSaveScreenshot(cmd, "")
@@ -143,7 +200,7 @@ func captureAndSaveScreenshot(cmd *cobra.Command, path string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Read flags from command, falling back to parent for persistent flags
@@ -152,7 +209,12 @@ func captureAndSaveScreenshot(cmd *cobra.Command, path string) error {
 		fullPage, _ = cmd.Parent().PersistentFlags().GetBool("full-page")
 	}
 
-	debugParam("fullPage=%v path=%q", fullPage, path)
+	annotate, err := screenshotAnnotations(cmd)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugParam("fullPage=%v path=%q annotate=%q", fullPage, path, annotate)
 
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
@@ -163,35 +225,12 @@ func captureAndSaveScreenshot(cmd *cobra.Command, path string) error {
 	// Send screenshot request with fullPage parameter
 	params, err := json.Marshal(ipc.ScreenshotParams{
 		FullPage: fullPage,
+		Annotate: annotate,
 	})
 	if err != nil {
 		return outputError(err.Error())
 	}
 
-	debugRequest("screenshot", fmt.Sprintf("fullPage=%v", fullPage))
-	ipcStart := time.Now()
-
-	resp, err := exec.Execute(ipc.Request{
-		Cmd:    "screenshot",
-		Params: params,
-	})
-
-	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
-
-	if err != nil {
-		return outputError(err.Error())
-	}
-
-	if !resp.OK {
-		return outputError(resp.Error)
-	}
-
-	// Parse screenshot data
-	var data ipc.ScreenshotData
-	if err := json.Unmarshal(resp.Data, &data); err != nil {
-		return outputError(err.Error())
-	}
-
 	// Determine output path
 	var outputPath string
 	if path == "" {
@@ -221,18 +260,17 @@ func captureAndSaveScreenshot(cmd *cobra.Command, path string) error {
 		}
 	}
 
-	// Ensure parent directory exists
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return outputError(fmt.Sprintf("failed to create directory: %v", err))
+	resp, err := writeScreenshot(exec, params, outputPath)
+	if err != nil {
+		return outputError(err.Error())
 	}
-
-	// Write PNG data to file
-	if err := os.WriteFile(outputPath, data.Data, 0644); err != nil {
-		return outputError(fmt.Sprintf("failed to write screenshot: %v", err))
+	if !resp.OK {
+		return outputResponseError(resp)
 	}
 
-	debugFile("wrote", outputPath, len(data.Data))
+	if info, err := os.Stat(outputPath); err == nil {
+		debugFile("wrote", outputPath, int(info.Size()))
+	}
 
 	// JSON mode: return JSON with file path
 	if JSONOutput {
@@ -327,3 +365,287 @@ func normalizeTitle(title string) string {
 
 	return title
 }
+
+// writeScreenshot captures a screenshot with params and writes it to
+// outputPath, creating its directory. A failed capture is returned in the
+// response, with nothing written.
+func writeScreenshot(exec executor.Executor, params json.RawMessage, outputPath string) (ipc.Response, error) {
+	// Ensure parent directory exists
+	dir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ipc.Response{}, fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	// Stream the PNG into a temporary file beside the output, so a failed
+	// capture never leaves a truncated screenshot behind
+	f, err := os.CreateTemp(dir, ".webctl-screenshot-*.png")
+	if err != nil {
+		return ipc.Response{}, fmt.Errorf("failed to write screenshot: %v", err)
+	}
+	tmpPath := f.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+	defer func() { _ = f.Close() }()
+
+	debugRequest("screenshot", string(params))
+	ipcStart := time.Now()
+
+	resp, err := executeStream(exec, ipc.Request{
+		Cmd:    "screenshot",
+		Params: params,
+	}, f)
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return ipc.Response{}, err
+	}
+
+	if !resp.OK {
+		return resp, nil
+	}
+
+	// A daemon that does not stream screenshots sends the PNG in the data
+	if len(resp.Data) > 0 {
+		var data ipc.ScreenshotData
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			return ipc.Response{}, err
+		}
+		if _, err := f.Write(data.Data); err != nil {
+			return ipc.Response{}, fmt.Errorf("failed to write screenshot: %v", err)
+		}
+	}
+	if err := f.Chmod(0644); err != nil {
+		return ipc.Response{}, fmt.Errorf("failed to write screenshot: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return ipc.Response{}, fmt.Errorf("failed to write screenshot: %v", err)
+	}
+	if err := os.Rename(tmpPath, outputPath); err != nil {
+		return ipc.Response{}, fmt.Errorf("failed to write screenshot: %v", err)
+	}
+
+	return resp, nil
+}
+
+// screenshotAnnotations returns the --annotate selectors, splitting each value
+// at the commas that separate selectors.
+func screenshotAnnotations(cmd *cobra.Command) ([]string, error) {
+	values, _ := cmd.Flags().GetStringArray("annotate")
+	if len(values) == 0 && cmd.Parent() != nil {
+		values, _ = cmd.Parent().PersistentFlags().GetStringArray("annotate")
+	}
+	var selectors []string
+	for _, v := range values {
+		for _, sel := range splitSelectorList(v) {
+			if sel == "" {
+				return nil, fmt.Errorf("--annotate has an empty selector: %q", v)
+			}
+			selectors = append(selectors, sel)
+		}
+	}
+	return selectors, nil
+}
+
+// splitSelectorList splits a comma-separated list of CSS selectors, ignoring
+// commas inside brackets, parentheses, and quotes, as in a[title="a,b"] or
+// :is(h1, h2).
+func splitSelectorList(s string) []string {
+	var parts []string
+	depth := 0
+	var quote rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[':
+			depth++
+		case r == ')' || r == ']':
+			depth--
+		case r == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+// imageSize is the size of an image in screenshot diff output.
+type imageSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// imageArea is a rectangle of an image in screenshot diff output.
+type imageArea struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// runScreenshotDiff handles diff subcommand: compare the page with a baseline
+func runScreenshotDiff(cmd *cobra.Command, args []string) error {
+	t := startTimer("screenshot diff")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	if threshold < 0 || threshold > 100 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--threshold must be a percentage from 0 to 100")
+	}
+	if annotate, _ := screenshotAnnotations(cmd); len(annotate) > 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--annotate cannot be used with diff")
+	}
+	outPath, _ := cmd.Flags().GetString("out")
+	fullPage, _ := cmd.Flags().GetBool("full-page")
+	if !fullPage && cmd.Parent() != nil {
+		fullPage, _ = cmd.Parent().PersistentFlags().GetBool("full-page")
+	}
+	debugParam("baseline=%q threshold=%v out=%q fullPage=%v", args[0], threshold, outPath, fullPage)
+
+	baseline, err := readPNG(args[0])
+	if err != nil {
+		return outputError(fmt.Sprintf("failed to read baseline: %v", err))
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	current, resp, err := captureScreenshotImage(exec, fullPage)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	if outPath == "" {
+		if outPath, err = generateScreenshotPath(exec); err != nil {
+			return outputError(err.Error())
+		}
+		outPath = strings.TrimSuffix(outPath, ".png") + "-diff.png"
+	}
+
+	res := imagediff.Compare(baseline, current, imagediff.DefaultSensitivity)
+	if err := writePNG(outPath, res.Diff); err != nil {
+		return outputError(fmt.Sprintf("failed to write diff: %v", err))
+	}
+
+	within := res.Percent() <= threshold
+	bw, bh := baseline.Bounds().Dx(), baseline.Bounds().Dy()
+	cw, ch := current.Bounds().Dx(), current.Bounds().Dy()
+
+	if JSONOutput {
+		result := map[string]any{
+			"ok":            within,
+			"changedPixels": res.Changed,
+			"totalPixels":   res.Total(),
+			"percent":       res.Percent(),
+			"threshold":     threshold,
+			"baselineSize":  imageSize{Width: bw, Height: bh},
+			"currentSize":   imageSize{Width: cw, Height: ch},
+			"diff":          outPath,
+		}
+		if res.Changed > 0 {
+			result["changedArea"] = imageArea{
+				X: res.Bounds.Min.X, Y: res.Bounds.Min.Y,
+				Width: res.Bounds.Dx(), Height: res.Bounds.Dy(),
+			}
+		}
+		if err := outputJSON(os.Stdout, result); err != nil {
+			return err
+		}
+	} else {
+		relation := "within"
+		if !within {
+			relation = "above"
+		}
+		fmt.Fprintf(os.Stdout, "%d of %d pixels differ (%s%%), %s the %s%% threshold\n",
+			res.Changed, res.Total(), formatPercent(res.Percent()), relation, formatPercent(threshold))
+		if bw != cw || bh != ch {
+			fmt.Fprintf(os.Stdout, "Size differs: baseline %dx%d, current %dx%d\n", bw, bh, cw, ch)
+		}
+		if res.Changed > 0 {
+			fmt.Fprintf(os.Stdout, "Changed area: %dx%d at %d,%d\n",
+				res.Bounds.Dx(), res.Bounds.Dy(), res.Bounds.Min.X, res.Bounds.Min.Y)
+		}
+		fmt.Fprintf(os.Stdout, "Diff: %s\n", outPath)
+	}
+
+	if !within {
+		return printedError{err: errors.New("screenshot differs from baseline")}
+	}
+	return nil
+}
+
+// captureScreenshotImage captures the page and decodes it. A failed response
+// is returned with a nil image.
+func captureScreenshotImage(exec executor.Executor, fullPage bool) (image.Image, ipc.Response, error) {
+	params, err := json.Marshal(ipc.ScreenshotParams{FullPage: fullPage})
+	if err != nil {
+		return nil, ipc.Response{}, err
+	}
+	dir, err := os.MkdirTemp("", "webctl-screenshot-")
+	if err != nil {
+		return nil, ipc.Response{}, err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "current.png")
+	resp, err := writeScreenshot(exec, params, path)
+	if err != nil || !resp.OK {
+		return nil, resp, err
+	}
+	img, err := readPNG(path)
+	if err != nil {
+		return nil, resp, fmt.Errorf("failed to decode screenshot: %v", err)
+	}
+	return img, resp, nil
+}
+
+// readPNG decodes the PNG file at path.
+func readPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return png.Decode(f)
+}
+
+// writePNG encodes img to path, creating its directory.
+func writePNG(path string, img image.Image) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// formatPercent formats a percentage with up to two decimals.
+func formatPercent(p float64) string {
+	s := strconv.FormatFloat(p, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "0" && p > 0 {
+		return "<0.01"
+	}
+	return s
+}
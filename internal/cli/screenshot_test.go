@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// testPNG encodes a white w x h image with the given pixels set black.
+func testPNG(t *testing.T, w, h int, black ...image.Point) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{255, 255, 255, 255})
+		}
+	}
+	for _, p := range black {
+		img.SetNRGBA(p.X, p.Y, color.NRGBA{0, 0, 0, 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// screenshotDiffRun runs screenshot diff of a page that renders current
+// against a baseline, with the given threshold, and returns the JSON output.
+func screenshotDiffRun(t *testing.T, baseline, current []byte, threshold string) (map[string]any, string, error) {
+	t.Helper()
+	enableJSONOutput(t)
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		if req.Cmd != "screenshot" {
+			t.Errorf("unexpected command: %s", req.Cmd)
+		}
+		return ipc.SuccessResponse(ipc.ScreenshotData{Data: current}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "baseline.png")
+	if err := os.WriteFile(basePath, baseline, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(dir, "out", "diff.png")
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Float64("threshold", 0.1, "")
+	cmd.Flags().String("out", "", "")
+	cmd.Flags().Bool("full-page", false, "")
+	_ = cmd.Flags().Set("threshold", threshold)
+	_ = cmd.Flags().Set("out", outPath)
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runScreenshotDiff(cmd, []string{basePath})
+	})
+	var result map[string]any
+	if jerr := json.Unmarshal([]byte(out), &result); jerr != nil {
+		t.Fatalf("invalid JSON: %v\n%s", jerr, out)
+	}
+	return result, outPath, err
+}
+
+func TestRunScreenshotDiff_WithinThreshold(t *testing.T) {
+	result, outPath, err := screenshotDiffRun(t, testPNG(t, 10, 10), testPNG(t, 10, 10, image.Pt(1, 1)), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result["ok"] != true || result["changedPixels"] != 1.0 || result["totalPixels"] != 100.0 {
+		t.Errorf("result = %v", result)
+	}
+	img, err := readPNG(outPath)
+	if err != nil {
+		t.Fatalf("diff image not written: %v", err)
+	}
+	if r, g, b, _ := img.At(1, 1).RGBA(); r>>8 != 255 || g != 0 || b != 0 {
+		t.Errorf("changed pixel not red in diff image")
+	}
+}
+
+func TestRunScreenshotDiff_AboveThreshold(t *testing.T) {
+	result, _, err := screenshotDiffRun(t, testPNG(t, 10, 10), testPNG(t, 10, 10, image.Pt(1, 1), image.Pt(4, 2)), "1")
+	if err == nil || !IsPrintedError(err) {
+		t.Fatalf("error = %v, want a printed failure", err)
+	}
+	if result["ok"] != false || result["percent"] != 2.0 {
+		t.Errorf("result = %v", result)
+	}
+	area, _ := result["changedArea"].(map[string]any)
+	if area["x"] != 1.0 || area["y"] != 1.0 || area["width"] != 4.0 || area["height"] != 2.0 {
+		t.Errorf("changedArea = %v", area)
+	}
+}
+
+func TestRunScreenshotDiff_SizeMismatch(t *testing.T) {
+	result, _, err := screenshotDiffRun(t, testPNG(t, 10, 10), testPNG(t, 10, 20), "0.1")
+	if err == nil {
+		t.Fatal("expected a size change to fail")
+	}
+	size, _ := result["currentSize"].(map[string]any)
+	if size["height"] != 20.0 || result["changedPixels"] != 100.0 {
+		t.Errorf("result = %v", result)
+	}
+}
+
+func TestRunScreenshotDiff_BadBaseline(t *testing.T) {
+	enableJSONOutput(t)
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: &mockExecutor{}})()
+
+	path := filepath.Join(t.TempDir(), "baseline.png")
+	if err := os.WriteFile(path, []byte("not a png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := &cobra.Command{}
+	cmd.Flags().Float64("threshold", 0.1, "")
+	cmd.Flags().String("out", "", "")
+	cmd.Flags().Bool("full-page", false, "")
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runScreenshotDiff(cmd, []string{path})
+	})
+	if err == nil {
+		t.Fatal("expected an error for an invalid baseline")
+	}
+}
+
+func TestFormatPercent(t *testing.T) {
+	tests := map[float64]string{0: "0", 0.1: "0.1", 2: "2", 0.166: "0.17", 0.001: "<0.01", 100: "100"}
+	for in, want := range tests {
+		if got := formatPercent(in); got != want {
+			t.Errorf("formatPercent(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSplitSelectorList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"#a", []string{"#a"}},
+		{"#a, .b ,c", []string{"#a", ".b", "c"}},
+		{`a[title="x,y"],b`, []string{`a[title="x,y"]`, "b"}},
+		{":is(h1, h2), p", []string{":is(h1, h2)", "p"}},
+		{"a,", []string{"a", ""}},
+	}
+	for _, tt := range tests {
+		got := splitSelectorList(tt.in)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("splitSelectorList(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRunScreenshotSave_Annotate(t *testing.T) {
+	enableJSONOutput(t)
+	var params ipc.ScreenshotParams
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		_ = json.Unmarshal(req.Params, &params)
+		return ipc.SuccessResponse(ipc.ScreenshotData{Data: testPNG(t, 2, 2)}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("full-page", false, "")
+	cmd.Flags().StringArray("annotate", nil, "")
+	_ = cmd.Flags().Set("annotate", "#checkout, .price")
+	_ = cmd.Flags().Set("annotate", "nav a")
+
+	var err error
+	captureStream(t, &os.Stdout, func() {
+		err = captureAndSaveScreenshot(cmd, filepath.Join(t.TempDir(), "bug.png"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"#checkout", ".price", "nav a"}; strings.Join(params.Annotate, "|") != strings.Join(want, "|") {
+		t.Errorf("annotate = %q, want %q", params.Annotate, want)
+	}
+}
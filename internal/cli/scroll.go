@@ -13,14 +13,16 @@ import (
 )
 
 var scrollCmd = &cobra.Command{
-	Use:   "scroll <selector> | --to x,y | --by x,y",
+	Use:   "scroll <selector> | --to x,y | --by x,y | --top | --bottom | --page-down | --page-up",
 	Short: "Scroll to element or position",
 	Long: `Scrolls to an element, absolute position, or by an offset.
 
-Three scroll modes:
+Scroll modes:
   1. Element mode: scroll an element into the center of the viewport
   2. Absolute mode: scroll to an exact position on the page
   3. Relative mode: scroll by an offset from current position
+  4. Edge mode: scroll to the top (--top) or bottom (--bottom) of the page
+  5. Page mode: scroll down (--page-down) or up (--page-up) one viewport height
 
 Coordinates are specified as x,y where:
   x = horizontal position (0 = left edge)
@@ -56,15 +58,39 @@ Relative offset examples (--by):
   scroll --by -200,0                  # Scroll left 200px
   scroll --by 100,100                 # Scroll diagonally
 
+Edge and page examples:
+  scroll --top                        # Top of the page
+  scroll --bottom                     # Bottom of the page
+  scroll --page-down                  # Down one viewport height
+  scroll --page-up --smooth           # Up one viewport height, animated
+
+Smooth scrolling (--smooth):
+  Scrolls with the page's smooth animation instead of jumping, for pages that
+  react to scroll events along the way, and returns once the scroll settles.
+  Works with every mode.
+
+Infinite scroll (--until-idle):
+  Scrolls to the bottom, waits for the page to grow, and repeats until the
+  page height holds still for --idle (default 1s), so content that loads as
+  the end comes into view is all on the page before a capture. Implies
+  --bottom. Fails if the page is still growing at --timeout (default 30s).
+
+  scroll --until-idle && webctl screenshot save feed.png --full-page
+  scroll --until-idle --idle 3s --timeout 2m
+
+  Response:
+    Scrolled 6 times until idle (height 14820px)
+
 Common patterns:
-  scroll --to 0,0                     # Return to top of page
+  scroll --top                        # Return to top of page
   scroll "#main-content"              # Skip to main content
-  scroll --by 0,window.innerHeight    # Page down (use eval for dynamic)
+  scroll --until-idle                 # Load an infinite feed before capture
 
 Error cases:
   - "element not found" - selector doesn't match any element
   - "invalid --to coordinates" - coordinates not in x,y format
-  - "provide a selector, --to x,y, or --by x,y" - no mode specified`,
+  - "provide a selector, --to x,y, --by x,y, --top, --bottom, --page-down, or --page-up" - no mode specified
+  - "timeout: page height still growing ..." - --until-idle reached --timeout`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runScroll,
 }
@@ -72,6 +98,14 @@ Error cases:
 func init() {
 	scrollCmd.Flags().String("to", "", "Scroll to absolute position (x,y)")
 	scrollCmd.Flags().String("by", "", "Scroll by offset (x,y)")
+	scrollCmd.Flags().Bool("top", false, "Scroll to the top of the page")
+	scrollCmd.Flags().Bool("bottom", false, "Scroll to the bottom of the page")
+	scrollCmd.Flags().Bool("page-down", false, "Scroll down one viewport height")
+	scrollCmd.Flags().Bool("page-up", false, "Scroll up one viewport height")
+	scrollCmd.Flags().Bool("smooth", false, "Scroll with smooth animation and wait for it to settle")
+	scrollCmd.Flags().Bool("until-idle", false, "Scroll to the bottom until the page height stops growing (implies --bottom)")
+	scrollCmd.Flags().Duration("idle", time.Second, "With --until-idle, how long the height must hold still")
+	scrollCmd.Flags().Duration("timeout", 30*time.Second, "With --until-idle, how long to keep scrolling")
 	rootCmd.AddCommand(scrollCmd)
 }
 
@@ -80,12 +114,33 @@ func runScroll(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Read flags from command
 	toCoords, _ := cmd.Flags().GetString("to")
 	byCoords, _ := cmd.Flags().GetString("by")
+	top, _ := cmd.Flags().GetBool("top")
+	bottom, _ := cmd.Flags().GetBool("bottom")
+	pageDown, _ := cmd.Flags().GetBool("page-down")
+	pageUp, _ := cmd.Flags().GetBool("page-up")
+	smooth, _ := cmd.Flags().GetBool("smooth")
+	untilIdle, _ := cmd.Flags().GetBool("until-idle")
+	idle, _ := cmd.Flags().GetDuration("idle")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	modes := 0
+	for _, set := range []bool{len(args) == 1, toCoords != "", byCoords != "", top, bottom || untilIdle, pageDown, pageUp} {
+		if set {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return outputError("provide only one of a selector, --to, --by, --top, --bottom, --page-down, or --page-up")
+	}
+	if untilIdle && (idle <= 0 || timeout <= 0) {
+		return outputCodedError(ipc.CodeInvalidArgs, "--idle and --timeout must be positive")
+	}
 
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
@@ -93,34 +148,57 @@ func runScroll(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = exec.Close() }()
 
-	var params ipc.ScrollParams
+	params := ipc.ScrollParams{Smooth: smooth}
 	var paramStr string
 
 	// Determine scroll mode
-	if toCoords != "" {
+	switch {
+	case toCoords != "":
 		x, y, err := parseCoords(toCoords)
 		if err != nil {
-			return outputError(fmt.Sprintf("invalid --to coordinates: %v", err))
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid --to coordinates: %v", err))
 		}
 		params.Mode = "to"
 		params.ToX = x
 		params.ToY = y
 		paramStr = fmt.Sprintf("mode=to x=%d y=%d", x, y)
-	} else if byCoords != "" {
+	case byCoords != "":
 		x, y, err := parseCoords(byCoords)
 		if err != nil {
-			return outputError(fmt.Sprintf("invalid --by coordinates: %v", err))
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid --by coordinates: %v", err))
 		}
 		params.Mode = "by"
 		params.ByX = x
 		params.ByY = y
 		paramStr = fmt.Sprintf("mode=by x=%d y=%d", x, y)
-	} else if len(args) == 1 {
+	case top:
+		params.Mode = "top"
+		paramStr = "mode=top"
+	case bottom || untilIdle:
+		params.Mode = "bottom"
+		paramStr = "mode=bottom"
+		if untilIdle {
+			params.UntilIdle = true
+			params.Idle = int(idle.Milliseconds())
+			params.Timeout = int(timeout.Milliseconds())
+			paramStr += fmt.Sprintf(" until-idle idle=%v timeout=%v", idle, timeout)
+		}
+	case pageDown, pageUp:
+		params.Mode = "page"
+		params.Pages = 1
+		if pageUp {
+			params.Pages = -1
+		}
+		paramStr = fmt.Sprintf("mode=page pages=%d", params.Pages)
+	case len(args) == 1:
 		params.Mode = "element"
 		params.Selector = args[0]
 		paramStr = fmt.Sprintf("mode=element selector=%q", args[0])
-	} else {
-		return outputError("provide a selector, --to x,y, or --by x,y")
+	default:
+		return outputError("provide a selector, --to x,y, --by x,y, --top, --bottom, --page-down, or --page-up")
+	}
+	if smooth {
+		paramStr += " smooth"
 	}
 
 	debugParam("%s", paramStr)
@@ -146,9 +224,25 @@ func runScroll(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoElementsError(resp.Error) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputResponseError(resp)
+	}
+
+	if params.UntilIdle {
+		var data ipc.ScrollData
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			return outputError(err.Error())
+		}
+		if JSONOutput {
+			return outputJSON(os.Stdout, map[string]any{
+				"ok":      true,
+				"scrolls": data.Scrolls,
+				"height":  data.Height,
+			})
 		}
-		return outputError(resp.Error)
+		fmt.Fprintf(os.Stdout, "Scrolled %d times until idle (height %dpx)\n", data.Scrolls, data.Height)
+		return nil
 	}
 
 	// JSON mode: output JSON
@@ -12,12 +12,20 @@ import (
 
 // Named selectCmd_ to avoid collision with Go's select keyword
 var selectCmd_ = &cobra.Command{
-	Use:   "select <selector> <value>",
+	Use:   "select <selector> [value...] [--label <text>]",
 	Short: "Select a dropdown option",
 	Long: `Selects an option in a native HTML <select> dropdown element.
 
 The selector identifies the <select> element using CSS selector syntax.
-The value must match the option's value attribute (not the display text).
+A value must match the option's value attribute (not the display text). To
+match the display text instead, for options with generated values, use
+--label; surrounding and repeated whitespace is ignored.
+
+The matched options replace the current selection. Give several values or
+labels, in any mix, to select several options of a <select multiple>. The
+selected options are printed, so the result can be checked:
+
+  Australia (AU)
 
 Only works with native HTML <select> elements. For custom JavaScript dropdowns
 (like React Select, Material UI, etc.), use click and type commands instead.
@@ -42,7 +50,7 @@ Given this HTML (country selector):
   </select>
 
 Use: select "#country" "AU"
-Note: Use "AU" (the value attribute), not "Australia" (the display text)
+Or:  select "#country" --label "Australia"
 
 Given this HTML (size selector):
   <select class="product-size" name="size">
@@ -73,6 +81,15 @@ Use:
   select "form#order select[name=shipping]" "express"
   select "form#order select[name=payment]" "credit"
 
+Given this HTML (multiple select with generated values):
+  <select id="toppings" multiple>
+    <option value="t-1f3a">Cheese</option>
+    <option value="t-9c2e">Olives</option>
+    <option value="t-44d0">Basil</option>
+  </select>
+
+Use: select "#toppings" --label Cheese --label Basil
+
 Common form automation pattern:
   type "#email" "user@example.com"
   type "#name" "John Smith"
@@ -90,12 +107,15 @@ For custom dropdowns (React, Vue, Material UI):
 
 Error cases:
   - "element not found" - selector doesn't match any element
-  - "element is not a select" - matched element is not a <select>`,
-	Args: cobra.ExactArgs(2),
+  - "element is not a select" - matched element is not a <select>
+  - "no option with label ..." - no option has that value or label
+  - "is not a multiple select" - several options for a single <select>`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runSelect,
 }
 
 func init() {
+	selectCmd_.Flags().StringArray("label", nil, "Select the option with this visible text (repeatable)")
 	rootCmd.AddCommand(selectCmd_)
 }
 
@@ -104,12 +124,16 @@ func runSelect(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	selector := args[0]
-	value := args[1]
-	debugParam("selector=%q value=%q", selector, value)
+	values := args[1:]
+	labels, _ := cmd.Flags().GetStringArray("label")
+	if len(values) == 0 && len(labels) == 0 {
+		return outputError("provide a value or --label")
+	}
+	debugParam("selector=%q values=%q labels=%q", selector, values, labels)
 
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
@@ -117,15 +141,18 @@ func runSelect(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = exec.Close() }()
 
-	params, err := json.Marshal(ipc.SelectParams{
-		Selector: selector,
-		Value:    value,
-	})
+	selectParams := ipc.SelectParams{Selector: selector, Labels: labels}
+	if len(values) == 1 {
+		selectParams.Value = values[0]
+	} else {
+		selectParams.Values = values
+	}
+	params, err := json.Marshal(selectParams)
 	if err != nil {
 		return outputError(err.Error())
 	}
 
-	debugRequest("select", fmt.Sprintf("selector=%q value=%q", selector, value))
+	debugRequest("select", fmt.Sprintf("selector=%q values=%q labels=%q", selector, values, labels))
 	ipcStart := time.Now()
 
 	resp, err := exec.Execute(ipc.Request{
@@ -141,19 +168,38 @@ func runSelect(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoElementsError(resp.Error) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputResponseError(resp)
+	}
+
+	var data ipc.SelectData
+	if len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			return outputError(err.Error())
 		}
-		return outputError(resp.Error)
 	}
 
 	// JSON mode: output JSON
 	if JSONOutput {
+		selected := data.Selected
+		if selected == nil {
+			selected = []ipc.SelectedOption{}
+		}
 		result := map[string]any{
-			"ok": true,
+			"ok":       true,
+			"selected": selected,
 		}
 		return outputJSON(os.Stdout, result)
 	}
 
-	// Text mode: just output OK
-	return outputSuccess(nil)
+	// Text mode: the selected options, or OK from a daemon that does not
+	// report them
+	if len(data.Selected) == 0 {
+		return outputSuccess(nil)
+	}
+	for _, o := range data.Selected {
+		fmt.Fprintf(os.Stdout, "%s (%s)\n", o.Label, o.Value)
+	}
+	return nil
 }
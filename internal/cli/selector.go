@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var selectorCmd = &cobra.Command{
+	Use:   "selector <x,y> | --from <selector>",
+	Short: "Generate robust selectors for an element",
+	Long: `Generates selectors for one element and ranks them by robustness, checking
+each against the page. The element is the topmost one at the viewport point
+x,y (CSS pixels, as reported by 'box'), or the first match of --from. Use
+--from to replace a fragile selector, such as a long nth-child path copied
+from DevTools, with one that survives layout changes.
+
+Selectors are tried in this order:
+  id           #save (ids that look generated are skipped)
+  test-id      [data-testid="save"], data-test, data-cy, data-qa
+  name         input[name="email"]
+  aria-label   button[aria-label="Close"]
+  attribute    placeholder, alt, title, for, href, or type
+  class        button.primary (generated-looking classes are skipped)
+  anchored     #checkout button.primary (nearest ancestor with a stable hook)
+  path         form#checkout > button:nth-of-type(2) (the fallback)
+Selectors that match the element and others too are listed after every
+unique one, with their match count.
+
+Flags:
+  --from <selector>   Existing selector whose first match to describe
+
+Examples:
+  selector 640,360
+  selector --from "#root > div > div:nth-child(3) > button"
+  selector --from ".btn" --json | jq -r '.candidates[0].selector'
+
+Text output:
+  1. #save                 id
+  2. [data-testid="save"]  test-id
+  3. button.primary        class, 3 matches
+  xpath: //*[@id="save"]
+
+JSON output:
+  {"ok": true, "tag": "button", "id": "save", "candidates": [{"selector": "#save",
+   "strategy": "id", "matches": 1, "unique": true}], "xpath": "..."}
+
+Error cases:
+  - "give a point x,y or --from, not both" - both or neither given
+  - "no element at x,y" - the point is outside the page
+  - "selector '.x' matched no elements" - --from found nothing`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSelector,
+}
+
+func init() {
+	selectorCmd.Flags().String("from", "", "Existing selector whose first match to describe")
+	rootCmd.AddCommand(selectorCmd)
+}
+
+func runSelector(cmd *cobra.Command, args []string) error {
+	t := startTimer("selector")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	from, _ := cmd.Flags().GetString("from")
+	if (len(args) == 1) == (from != "") {
+		return outputError("give a point x,y or --from, not both")
+	}
+
+	params := ipc.SelectorParams{From: from}
+	if len(args) == 1 {
+		x, y, err := parseCoords(args[0])
+		if err != nil || x < 0 || y < 0 {
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid point %q (use x,y, e.g. 640,360)", args[0]))
+		}
+		params.X, params.Y = float64(x), float64(y)
+	}
+	debugParam("from=%q x=%g y=%g", params.From, params.X, params.Y)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("selector", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "selector", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.SelectorData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		result := map[string]any{
+			"ok":         true,
+			"tag":        data.Tag,
+			"candidates": data.Candidates,
+			"xpath":      data.XPath,
+		}
+		if data.ID != "" {
+			result["id"] = data.ID
+		}
+		if data.Class != "" {
+			result["class"] = data.Class
+		}
+		if data.FromMatches > 0 {
+			result["fromMatches"] = data.FromMatches
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	if data.FromMatches > 1 {
+		outputHint(fmt.Sprintf("--from matched %d elements; selectors are for the first", data.FromMatches))
+	}
+	return format.SelectorCandidates(os.Stdout, data)
+}
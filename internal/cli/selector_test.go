@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func TestRunSelector(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		from string
+		want ipc.SelectorParams
+	}{
+		{"point", []string{"640, 360"}, "", ipc.SelectorParams{X: 640, Y: 360}},
+		{"from", nil, "div > button", ipc.SelectorParams{From: "div > button"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ipc.SelectorParams
+			exec := &mockExecutor{
+				executeFunc: func(req ipc.Request) (ipc.Response, error) {
+					if req.Cmd != "selector" {
+						t.Errorf("expected cmd=selector, got %s", req.Cmd)
+					}
+					if err := json.Unmarshal(req.Params, &got); err != nil {
+						t.Fatalf("failed to parse params: %v", err)
+					}
+					raw, _ := json.Marshal(ipc.SelectorData{
+						ElementMeta: ipc.ElementMeta{Tag: "button"},
+						Candidates:  []ipc.SelectorCandidate{{Selector: "#save", Strategy: "id", Matches: 1, Unique: true}},
+						XPath:       `//*[@id="save"]`,
+					})
+					return ipc.Response{OK: true, Data: raw}, nil
+				},
+			}
+			restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+			defer restore()
+
+			cmd := newSelectorTestCmd()
+			if tt.from != "" {
+				_ = cmd.Flags().Set("from", tt.from)
+			}
+
+			var err error
+			out := captureStream(t, &os.Stdout, func() {
+				err = runSelector(cmd, tt.args)
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("params = %+v, want %+v", got, tt.want)
+			}
+			if !strings.HasPrefix(out, "1. #save  id\n") {
+				t.Errorf("unexpected output:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestRunSelector_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		from string
+	}{
+		{"neither", nil, ""},
+		{"both", []string{"1,2"}, ".btn"},
+		{"bad point", []string{"10"}, ""},
+		{"negative point", []string{"-1,5"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			exec := &mockExecutor{
+				executeFunc: func(req ipc.Request) (ipc.Response, error) {
+					called = true
+					return ipc.Response{OK: true}, nil
+				},
+			}
+			restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+			defer restore()
+
+			cmd := newSelectorTestCmd()
+			if tt.from != "" {
+				_ = cmd.Flags().Set("from", tt.from)
+			}
+
+			var err error
+			captureStream(t, &os.Stderr, func() {
+				err = runSelector(cmd, tt.args)
+			})
+			if err == nil {
+				t.Error("expected an error")
+			}
+			if called {
+				t.Error("daemon should not be called")
+			}
+		})
+	}
+}
+
+func newSelectorTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "selector", RunE: runSelector}
+	cmd.Flags().String("from", "", "")
+	return cmd
+}
@@ -15,8 +15,9 @@ import (
 )
 
 var serveCmd = &cobra.Command{
-	Use:   "serve [directory] [--proxy url]",
-	Short: "Start development server with hot reload",
+	Use:     "serve [directory] [--proxy url]",
+	Aliases: []string{"serve-dir"},
+	Short:   "Start development server with hot reload",
 	Long: `Start a development web server with automatic hot reload capabilities.
 
 Auto-Start Behavior:
@@ -30,6 +31,7 @@ Static Mode (serve directory):
   webctl serve <directory>         # Serve static files from directory
   webctl serve ./dist              # Serve ./dist directory
   webctl serve .                   # Serve current directory (explicit)
+  webctl serve ./dist --spa        # Single-page app: unknown routes get index.html
 
 Proxy Mode (proxy to backend):
   webctl serve --proxy <url>       # Proxy requests to backend server
@@ -42,6 +44,8 @@ Features:
 - Auto-detect available port or use --port flag
 - Network binding options (localhost vs 0.0.0.0)
 - Automatic browser navigation to served URL
+- SPA fallback (--spa): extensionless paths with no matching file serve the
+  root index.html, so client-side routes survive a reload
 - Full access to webctl debugging commands (console, network, etc.)
 
 Examples:
@@ -50,6 +54,7 @@ Static mode (default):
   serve ./public                   # Serve ./public directory
   serve . --port 3000              # Serve current dir on port 3000
   serve ./dist --host 0.0.0.0      # Accessible from network
+  serve-dir ./build --spa          # Same command, for a React/Vue build
 
 Proxy mode:
   serve --proxy localhost:8080     # Proxy to localhost:8080
@@ -68,14 +73,16 @@ Integration with webctl commands:
   console                          # Monitor console logs
   network --status 4xx             # Monitor network errors
   html --select "#app"             # Inspect rendered HTML`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runServe,
+	Args:        cobra.MaximumNArgs(1),
+	RunE:        runServe,
+	Annotations: map[string]string{noAutoStartAnnotation: ""},
 }
 
 var (
 	serveProxy  string
 	servePort   int
 	serveHost   string
+	serveSPA    bool
 	serveWatch  []string
 	serveIgnore []string
 )
@@ -84,6 +91,7 @@ func init() {
 	serveCmd.Flags().StringVar(&serveProxy, "proxy", "", "Backend URL to proxy (enables proxy mode)")
 	serveCmd.Flags().IntVar(&servePort, "port", 0, "Server port (0 = auto-detect)")
 	serveCmd.Flags().StringVar(&serveHost, "host", "localhost", "Bind host (localhost or 0.0.0.0)")
+	serveCmd.Flags().BoolVar(&serveSPA, "spa", false, "Serve index.html for unknown routes (static mode)")
 	serveCmd.Flags().StringSliceVar(&serveWatch, "watch", nil, "Additional paths to watch (comma-separated)")
 	serveCmd.Flags().StringSliceVar(&serveIgnore, "ignore", nil, "Glob patterns to ignore (comma-separated)")
 
@@ -92,11 +100,18 @@ func init() {
 
 // runServeWithDaemon starts the daemon and server together when daemon is not running
 func runServeWithDaemon(mode, directory, proxyURL string) error {
+	logLevel, logHandler, err := daemonLogging()
+	if err != nil {
+		return outputError(err.Error())
+	}
+
 	// Create daemon config
 	cfg := daemon.DefaultConfig()
 	cfg.Headless = false // Default to headed mode for serve
 	cfg.Port = 0         // Auto-detect available CDP port
-	cfg.Debug = Debug
+	cfg.LogPath = logFilePath("")
+	cfg.LogLevel = logLevel
+	cfg.LogHandler = logHandler
 
 	// Declare d first so the closure can capture it
 	var d *daemon.Daemon
@@ -108,6 +123,7 @@ func runServeWithDaemon(mode, directory, proxyURL string) error {
 		defer ResetExecutorFactory()
 		return ExecuteArgs(args)
 	}
+	cfg.CommandNames = CommandNames()
 
 	// Signal IPC readiness so command issuance waits for a serving daemon rather
 	// than a fixed sleep. Nil-safe by contract, so this caller opts in explicitly.
@@ -154,6 +170,7 @@ func runServeWithDaemon(mode, directory, proxyURL string) error {
 		Action:      "start",
 		Mode:        mode,
 		Directory:   directory,
+		SPA:         serveSPA,
 		ProxyURL:    proxyURL,
 		Port:        servePort,
 		Host:        serveHost,
@@ -174,7 +191,7 @@ func runServeWithDaemon(mode, directory, proxyURL string) error {
 	}
 
 	if !resp.OK {
-		outErr := outputError(resp.Error)
+		outErr := outputResponseError(resp)
 		if strings.Contains(resp.Error, "already running") {
 			outputHint("use 'webctl stop' to stop the server, or 'webctl stop --force' to force cleanup")
 		}
@@ -226,6 +243,9 @@ func runServe(cmd *cobra.Command, args []string) error {
 		if len(args) > 0 {
 			return outputError("cannot specify both directory and --proxy flag")
 		}
+		if serveSPA {
+			return outputCodedError(ipc.CodeInvalidArgs, "--spa applies to static mode, not --proxy")
+		}
 	} else {
 		// Static mode - defaults to current directory
 		mode = "static"
@@ -267,6 +287,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 		Action:      "start",
 		Mode:        mode,
 		Directory:   directory,
+		SPA:         serveSPA,
 		ProxyURL:    proxyURL,
 		Port:        servePort,
 		Host:        serveHost,
@@ -293,7 +314,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}
 
 	if !resp.OK {
-		outErr := outputError(resp.Error)
+		outErr := outputResponseError(resp)
 		if strings.Contains(resp.Error, "already running") {
 			outputHint("use 'webctl stop' to stop the server, or 'webctl stop --force' to force cleanup")
 		}
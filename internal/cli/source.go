@@ -0,0 +1,295 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var sourceCmd = &cobra.Command{
+	Use:   "source [url-pattern|scriptId] [path]",
+	Short: "Fetch the source of scripts loaded by the page",
+	Long: `Fetches the source of the scripts the page has loaded, exactly as the browser
+is running them, so it can be grepped and diffed locally. Inline <script>
+elements are listed under the document URL.
+
+Without arguments, lists the loaded scripts: scriptId, size, and URL, marked
+(map) when the script declares a source map.
+
+A script is picked by its scriptId from the list, or by a URL pattern: a
+substring of the URL, or a glob when it contains * or ?. A pattern may match
+several scripts.
+
+With --source-maps, each script's source map is loaded (inline data: maps are
+decoded, others fetched by the page with its cookies) and the original files
+it embeds are saved under sources/ in the output directory. A map without
+sourcesContent lists file names only; those files are reported, not saved.
+
+Output:
+  No path               One script: source to stdout
+                        Several, or --source-maps: save to /tmp/webctl-source/<timestamp>/
+  path/                 Save into this directory as <scriptId>-<name>.js
+  path                  One script: save to exact file; otherwise used as a directory
+
+Flags:
+  --source-maps   Also save the original files from each script's source map
+
+Examples:
+  source
+  source app.js | grep -n fetchUser
+  source 'https://cdn.example.com/*.js' ./scripts/
+  source main --source-maps ./src-dump/
+  source --json | jq '.scripts[] | select(.size > 500000) | .url'
+
+Text output (list):
+  41  312.0KB  https://example.com/static/app.js (map)
+  57  1.2KB    https://example.com/
+
+Error cases:
+  - "no script matches '<pattern>'" - check the URL with 'webctl source'
+  - "'<pattern>' matches N scripts" - give a path to save them all, or narrow the pattern`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runSource,
+}
+
+func init() {
+	sourceCmd.Flags().Bool("source-maps", false, "Also save the original files from each script's source map")
+	rootCmd.AddCommand(sourceCmd)
+}
+
+func runSource(cmd *cobra.Command, args []string) error {
+	t := startTimer("source")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	sourceMaps, _ := cmd.Flags().GetBool("source-maps")
+
+	if len(args) == 0 {
+		scripts, err := executeSource(ipc.SourceParams{Action: "list"})
+		if err != nil {
+			return outputError(err.Error())
+		}
+		if JSONOutput {
+			if scripts == nil {
+				scripts = []ipc.ScriptSource{}
+			}
+			return outputJSON(os.Stdout, map[string]any{
+				"ok":      true,
+				"scripts": scripts,
+			})
+		}
+		if len(scripts) == 0 {
+			return outputNotice("No scripts found")
+		}
+		return format.Scripts(os.Stdout, scripts)
+	}
+
+	query := args[0]
+	outPath := ""
+	if len(args) == 2 {
+		outPath = args[1]
+	}
+	debugParam("query=%q path=%q sourceMaps=%v", query, outPath, sourceMaps)
+
+	scripts, err := executeSource(ipc.SourceParams{Action: "get", Query: query, SourceMaps: sourceMaps})
+	if err != nil {
+		return outputError(err.Error())
+	}
+	for _, s := range scripts {
+		if s.SourceMapError != "" {
+			outputHint(fmt.Sprintf("%s: %s", s.URL, s.SourceMapError))
+		}
+	}
+
+	// One script without a path: source to stdout
+	if outPath == "" && !sourceMaps {
+		if len(scripts) > 1 {
+			return outputError(fmt.Sprintf("'%s' matches %d scripts; give a path to save them all, or narrow the pattern", query, len(scripts)))
+		}
+		if JSONOutput {
+			return outputJSON(os.Stdout, map[string]any{
+				"ok":     true,
+				"script": scripts[0],
+			})
+		}
+		fmt.Print(scripts[0].Source)
+		if !strings.HasSuffix(scripts[0].Source, "\n") {
+			fmt.Println()
+		}
+		return nil
+	}
+
+	paths, err := writeScriptSources(scripts, outPath, sourceMaps)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":      true,
+			"paths":   paths,
+			"scripts": scriptSummaries(scripts),
+		})
+	}
+	for _, p := range paths {
+		if err := format.FilePath(os.Stdout, p); err != nil {
+			return err
+		}
+	}
+	for _, s := range scripts {
+		for _, o := range s.Originals {
+			if o.Missing {
+				fmt.Fprintf(os.Stderr, "not in source map: %s\n", o.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// executeSource sends a "source" request and returns the scripts.
+func executeSource(params ipc.SourceParams) ([]ipc.ScriptSource, error) {
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	debugRequest("source", fmt.Sprintf("action=%s query=%q", params.Action, params.Query))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "source",
+		Params: raw,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return nil, err
+	}
+	if !resp.OK {
+		return nil, fmt.Errorf("%s", resp.Error)
+	}
+
+	var data ipc.SourceData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, err
+	}
+	return data.Scripts, nil
+}
+
+// writeScriptSources saves scripts and, with sourceMaps, their original files,
+// returning the paths written. A single script goes to outPath itself unless
+// it names a directory; anything more is saved into outPath as a directory,
+// or into a timestamped directory under /tmp/webctl-source when it is empty.
+func writeScriptSources(scripts []ipc.ScriptSource, outPath string, sourceMaps bool) ([]string, error) {
+	isDir := strings.HasSuffix(outPath, string(os.PathSeparator)) || strings.HasSuffix(outPath, "/")
+	if len(scripts) == 1 && !sourceMaps && outPath != "" && !isDir {
+		if err := writeSaveFile(outPath, scripts[0].Source); err != nil {
+			return nil, err
+		}
+		return []string{outPath}, nil
+	}
+
+	dir := outPath
+	if dir == "" {
+		now := time.Now()
+		dir = filepath.Join("/tmp/webctl-source", fmt.Sprintf("%s-%03d", now.Format("06-01-02-150405"), now.Nanosecond()/int(time.Millisecond)))
+	}
+
+	var paths []string
+	for _, s := range scripts {
+		p := filepath.Join(dir, scriptFilename(s))
+		if err := writeSaveFile(p, s.Source); err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+
+		for i, o := range s.Originals {
+			if o.Missing {
+				continue
+			}
+			p := filepath.Join(dir, "sources", originalSourcePath(o.Path, i))
+			if err := writeSaveFile(p, o.Content); err != nil {
+				return nil, err
+			}
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
+
+// scriptSummaries strips the source text from scripts for the JSON output of
+// a save, which reports the paths written instead.
+func scriptSummaries(scripts []ipc.ScriptSource) []ipc.ScriptSource {
+	summaries := make([]ipc.ScriptSource, len(scripts))
+	for i, s := range scripts {
+		s.Source = ""
+		originals := make([]ipc.OriginalSource, len(s.Originals))
+		for j, o := range s.Originals {
+			originals[j] = ipc.OriginalSource{Path: o.Path, Missing: o.Missing}
+		}
+		if len(originals) > 0 {
+			s.Originals = originals
+		}
+		summaries[i] = s
+	}
+	return summaries
+}
+
+// scriptFilename generates the filename for a saved script:
+// <scriptId>-<name>.js, where name comes from the last URL path segment.
+// The scriptId keeps inline scripts, which share the document URL, apart.
+func scriptFilename(s ipc.ScriptSource) string {
+	base := s.URL
+	if u, err := url.Parse(s.URL); err == nil {
+		base = path.Base(u.Path)
+		if base == "/" || base == "." {
+			base = u.Host
+		}
+	}
+	return fmt.Sprintf("%s-%s.js", s.ID, normalizeTitle(strings.TrimSuffix(base, ".js")))
+}
+
+// originalSourcePath maps a source map entry such as webpack:///./src/app.ts
+// to a relative file path (webpack/src/app.ts). The scheme becomes the first
+// directory and "." and ".." segments are dropped, so nothing is written
+// outside the output directory. Entries with no usable name fall back to
+// source-<i>.
+func originalSourcePath(source string, i int) string {
+	if scheme, rest, ok := strings.Cut(source, "://"); ok {
+		source = scheme + "/" + rest
+	}
+	if j := strings.IndexAny(source, "?#"); j >= 0 {
+		source = source[:j]
+	}
+
+	var parts []string
+	for _, part := range strings.Split(source, "/") {
+		if part == "" || part == "." || part == ".." {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("source-%d", i+1)
+	}
+	return filepath.Join(parts...)
+}
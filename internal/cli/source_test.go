@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func newSourceTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "source", RunE: runSource}
+	cmd.Flags().Bool("source-maps", false, "")
+	return cmd
+}
+
+func TestScriptFilename(t *testing.T) {
+	tests := []struct {
+		script ipc.ScriptSource
+		want   string
+	}{
+		{ipc.ScriptSource{ID: "41", URL: "https://example.com/static/App.Bundle.js?v=3"}, "41-app-bundle.js"},
+		{ipc.ScriptSource{ID: "57", URL: "https://example.com/"}, "57-example-com.js"},
+	}
+	for _, tt := range tests {
+		if got := scriptFilename(tt.script); got != tt.want {
+			t.Errorf("scriptFilename(%+v) = %q, want %q", tt.script, got, tt.want)
+		}
+	}
+}
+
+func TestOriginalSourcePath(t *testing.T) {
+	tests := []struct{ source, want string }{
+		{"webpack:///./src/app.ts", filepath.Join("webpack", "src", "app.ts")},
+		{"../../node_modules/lib/index.js", filepath.Join("node_modules", "lib", "index.js")},
+		{"src/main.ts?abc#x", filepath.Join("src", "main.ts")},
+		{"./", "source-4"},
+	}
+	for i, tt := range tests {
+		if got := originalSourcePath(tt.source, i); got != tt.want {
+			t.Errorf("originalSourcePath(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestRunSource_Stdout(t *testing.T) {
+	exec := expectParamsExecutor(t, "source", ipc.SourceParams{Action: "get", Query: "app.js"}, ipc.SuccessResponse(ipc.SourceData{Scripts: []ipc.ScriptSource{
+		{ID: "41", URL: "https://example.com/app.js", Source: "console.log(1)"},
+	}}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runSource(newSourceTestCmd(), []string{"app.js"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "console.log(1)\n" {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestRunSource_AmbiguousWithoutPath(t *testing.T) {
+	exec := expectParamsExecutor(t, "source", ipc.SourceParams{Action: "get", Query: "example.com"}, ipc.SuccessResponse(ipc.SourceData{Scripts: []ipc.ScriptSource{
+		{ID: "41", URL: "https://example.com/app.js"},
+		{ID: "42", URL: "https://example.com/vendor.js"},
+	}}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	stderr := captureStream(t, &os.Stderr, func() {
+		err = runSource(newSourceTestCmd(), []string{"example.com"})
+	})
+	if err == nil || !strings.Contains(stderr, "matches 2 scripts") {
+		t.Errorf("err = %v, stderr = %q; want a 'matches 2 scripts' error", err, stderr)
+	}
+}
+
+func TestRunSource_SourceMaps(t *testing.T) {
+	enableJSONOutput(t)
+	exec := expectParamsExecutor(t, "source", ipc.SourceParams{Action: "get", Query: "app.js", SourceMaps: true}, ipc.SuccessResponse(ipc.SourceData{Scripts: []ipc.ScriptSource{
+		{ID: "41", URL: "https://example.com/app.js", Source: "minified", SourceMapURL: "app.js.map", Originals: []ipc.OriginalSource{
+			{Path: "webpack:///./src/app.ts", Content: "const a: number = 1"},
+			{Path: "webpack:///./src/gone.ts", Missing: true},
+		}},
+	}}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	dir := t.TempDir()
+	cmd := newSourceTestCmd()
+	_ = cmd.Flags().Set("source-maps", "true")
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runSource(cmd, []string{"app.js", dir})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		Paths   []string           `json:"paths"`
+		Scripts []ipc.ScriptSource `json:"scripts"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	want := []string{
+		filepath.Join(dir, "41-app.js"),
+		filepath.Join(dir, "sources", "webpack", "src", "app.ts"),
+	}
+	if strings.Join(result.Paths, ",") != strings.Join(want, ",") {
+		t.Errorf("paths = %v, want %v", result.Paths, want)
+	}
+	if got, _ := os.ReadFile(want[1]); string(got) != "const a: number = 1" {
+		t.Errorf("original content = %q", got)
+	}
+	if len(result.Scripts) != 1 || result.Scripts[0].Source != "" || !result.Scripts[0].Originals[1].Missing {
+		t.Errorf("scripts = %+v, want summaries without source", result.Scripts)
+	}
+}
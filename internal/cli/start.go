@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/grantcarthew/webctl/internal/browser"
 	"github.com/grantcarthew/webctl/internal/daemon"
+	"github.com/grantcarthew/webctl/internal/ipc"
 	"github.com/spf13/cobra"
 )
 
@@ -23,8 +26,86 @@ Profile modes (mutually exclusive; default is the persistent profile):
   --user-data-dir DIR  Use DIR as the profile. webctl never deletes it.
   --system-profile     Use your real Chrome profile. Requires that no other
                        Chrome instance is running on the default profile, or the
-                       launch forwards to it and webctl cannot attach.`,
-	RunE: runStart,
+                       launch forwards to it and webctl cannot attach.
+
+Reuse:
+  The daemon records its browser's process, CDP endpoint, and profile in a
+  state file next to its PID file. If the daemon crashes or is killed, the
+  browser keeps running; 'webctl start --reuse' takes it over, tabs and all,
+  instead of launching a second Chrome. When there is no browser left to
+  reuse, --reuse launches one as usual.
+
+Adopt:
+  If a Chrome started with --remote-debugging-port is already listening on
+  --port, start reports it instead of launching a duplicate. 'webctl start
+  --adopt' attaches to that Chrome, keeping its tabs and logins. An adopted
+  browser is never closed, killed, or relaunched by webctl: 'webctl stop'
+  only disconnects from it.
+
+Browser:
+  --lang LOCALE              UI and Accept-Language locale, e.g. en-GB
+  --window-size WxH          Initial window size in screen pixels, e.g. 1280x800
+  --window-position X,Y      Initial window position in screen pixels
+  --incognito                Open the first window in incognito mode
+  --load-extension DIR       Load the unpacked extension in DIR (repeatable);
+                             list them with 'webctl extensions list'
+  --chrome-flag FLAG         Pass FLAG to Chrome as is (repeatable), e.g.
+                             --chrome-flag=--disable-gpu
+  --devtools                 Open DevTools for every tab; not with --headless,
+                             and only while headed after 'webctl headless'
+  These, with the profile, are shown by 'webctl status', and are kept when
+  the browser is relaunched. They do not apply to --adopt, and --reuse keeps
+  the flags the reused browser was launched with.
+
+CI and containers:
+  --ci, or WEBCTL_CI=1, or CI=true as set by GitHub Actions and most other CI
+  services, starts a headless browser on a temporary profile with the flags
+  containers need (--no-sandbox, --disable-gpu), fails if Chrome has not
+  started within 15 seconds, and turns color off. Temp files go to
+  $RUNNER_TEMP when TMPDIR is unset. Explicit flags still win, and --ci=false
+  or WEBCTL_CI=0 turns CI mode off under CI=true.
+
+Logging:
+  The daemon logs to $XDG_STATE_HOME/webctl/daemon.log (falls back to
+  ~/.local/state/webctl/daemon.log), or --log-file / $WEBCTL_LOG_FILE, as JSON
+  lines at --log-level (default info; --debug implies debug). Read it with
+  'webctl logs'. With --debug, --log-level, or --log-format, records are also
+  written to stderr, as text or, with --log-format json, JSON lines.
+
+Buffers:
+  --buffer-size N keeps the last N console and network entries. Network
+  response bodies are fetched and stored with their entries;
+  --capture-bodies RULES limits that to the bodies matching a comma-separated
+  list of mime:<type> and url:<glob> rules, where * matches anything and a
+  leading ! excludes, e.g. 'mime:application/json,url:*/api/*,!mime:image/*'.
+  --max-capture-size BYTES skips larger bodies, and lets Chrome keep bodies
+  up to that size, beyond its usual 10 MB, so they are stored whole. With
+  --persist-buffers they are also kept in $XDG_STATE_HOME/webctl/buffers, up
+  to 32 MB each, and a later start with --persist-buffers restores them, so a
+  daemon restart does not lose them. Restored entries are shown with every
+  tab; 'webctl clear' removes them.
+
+Metrics:
+  --metrics ADDR serves Prometheus metrics at http://ADDR/metrics: attached
+  sessions, buffer sizes, CDP command latency and errors, IPC requests and
+  errors by command, and browser restarts. ":9090" listens on all interfaces;
+  use "localhost:9090" to keep it local.
+
+Emulation (applied to every tab, including ones opened later):
+  --viewport WxH       Viewport size in CSS pixels, e.g. 390x844
+  --user-agent UA      User agent string
+  --throttle PROFILE   Network profile: fast-4g, slow-4g, 3g, or offline
+  --stealth            Hide the signs of an automated browser from bot
+                       detection: navigator.webdriver, a headless user agent
+                       and client hints, and missing plugins and languages
+
+Configuration:
+  --headless, --buffer-size, --viewport, --user-agent, and --throttle default
+  to the config keys of the same names (see 'webctl config'), and any flag can
+  be given a default with defaults.start.<flag>. --preset <name> applies a
+  named bundle of these, e.g. 'webctl start --preset mobile-ci'.`,
+	RunE:        runStart,
+	Annotations: map[string]string{noAutoStartAnnotation: ""},
 }
 
 var (
@@ -33,6 +114,25 @@ var (
 	startTempProfile   bool
 	startUserDataDir   string
 	startSystemProfile bool
+	startLogFile       string
+	startMetrics       string
+	startBufferSize    int
+	startPersist       bool
+	startCapture       string
+	startMaxCapture    int64
+	startViewport      string
+	startUserAgent     string
+	startThrottle      string
+	startStealth       bool
+	startReuse         bool
+	startAdopt         bool
+	startLang          string
+	startWindowSize    string
+	startWindowPos     string
+	startIncognito     bool
+	startChromeFlags   []string
+	startExtensions    []string
+	startDevTools      bool
 )
 
 func init() {
@@ -41,6 +141,25 @@ func init() {
 	startCmd.Flags().BoolVar(&startTempProfile, "temp-profile", false, "Use a throwaway profile, deleted on stop")
 	startCmd.Flags().StringVar(&startUserDataDir, "user-data-dir", "", "Use an explicit profile directory, never deleted by webctl")
 	startCmd.Flags().BoolVar(&startSystemProfile, "system-profile", false, "Use the real Chrome profile (no other Chrome may run on it)")
+	startCmd.Flags().BoolVar(&startReuse, "reuse", false, "Take over the browser left running by a crashed daemon instead of launching one")
+	startCmd.Flags().BoolVar(&startAdopt, "adopt", false, "Attach to a Chrome already serving CDP on --port instead of launching one")
+	startCmd.Flags().StringVar(&startLang, "lang", "", "Browser UI and Accept-Language locale (e.g. en-GB)")
+	startCmd.Flags().StringVar(&startWindowSize, "window-size", "", "Initial browser window size (WIDTHxHEIGHT, e.g. 1280x800)")
+	startCmd.Flags().StringVar(&startWindowPos, "window-position", "", "Initial browser window position (X,Y)")
+	startCmd.Flags().BoolVar(&startIncognito, "incognito", false, "Open the first browser window in incognito mode")
+	startCmd.Flags().StringArrayVar(&startExtensions, "load-extension", nil, "Load the unpacked extension in this directory (repeatable)")
+	startCmd.Flags().StringArrayVar(&startChromeFlags, "chrome-flag", nil, "Extra Chrome command-line flag (repeatable)")
+	startCmd.Flags().BoolVar(&startDevTools, "devtools", false, "Open DevTools for every tab (needs a visible browser)")
+	startCmd.Flags().StringVar(&startLogFile, "log-file", "", "Daemon log file (default $"+logFileEnv+" or the XDG state path)")
+	startCmd.Flags().StringVar(&startMetrics, "metrics", "", "Expose Prometheus metrics on this address (e.g. :9090)")
+	startCmd.Flags().IntVar(&startBufferSize, "buffer-size", daemon.DefaultBufferSize, "Console and network buffer capacity (entries)")
+	startCmd.Flags().StringVar(&startCapture, "capture-bodies", "", "Only capture network bodies matching these rules (e.g. mime:application/json,url:*/api/*,!mime:image/*)")
+	startCmd.Flags().Int64Var(&startMaxCapture, "max-capture-size", 0, "Skip network bodies larger than this many bytes (0 for no limit)")
+	startCmd.Flags().BoolVar(&startPersist, "persist-buffers", false, "Keep the console and network buffers on disk and restore them on start")
+	startCmd.Flags().StringVar(&startViewport, "viewport", "", "Emulate a viewport size in every tab (WIDTHxHEIGHT, e.g. 390x844)")
+	startCmd.Flags().StringVar(&startUserAgent, "user-agent", "", "Override the user agent in every tab")
+	startCmd.Flags().StringVar(&startThrottle, "throttle", "", "Throttle the network in every tab: "+strings.Join(daemon.ThrottleProfileNames(), ", "))
+	startCmd.Flags().BoolVar(&startStealth, "stealth", false, "Hide automation fingerprints (navigator.webdriver, headless user agent) from bot detection")
 	rootCmd.AddCommand(startCmd)
 }
 
@@ -52,12 +171,91 @@ func startupErrorHint(err error) string {
 	switch {
 	case errors.Is(err, browser.ErrSystemProfileInUse):
 		return "close the running Chrome on the default profile, or start with the persistent default profile or --temp-profile"
+	case errors.Is(err, browser.ErrBrowserOnPort):
+		return "use 'webctl start --adopt' to attach to it and keep its tabs, or 'webctl start --reuse' if a crashed daemon left it running"
 	case errors.Is(err, browser.ErrPortInUse):
 		return "use 'webctl stop --force' to kill orphaned processes"
 	}
 	return ""
 }
 
+// startEmulation builds the daemon's emulation settings from --viewport,
+// --user-agent, --throttle, and --stealth.
+func startEmulation() (daemon.Emulation, error) {
+	e := daemon.Emulation{UserAgent: startUserAgent, Throttle: startThrottle, Stealth: startStealth}
+	if startViewport != "" {
+		w, h, err := daemon.ParseViewport(startViewport)
+		if err != nil {
+			return e, err
+		}
+		e.Width, e.Height = w, h
+	}
+	debugParam("viewport=%dx%d user-agent=%q throttle=%q stealth=%v", e.Width, e.Height, e.UserAgent, e.Throttle, e.Stealth)
+	return e, e.Validate()
+}
+
+// reservedChromeFlags are Chrome flags that webctl sets itself, mapped to the
+// start flag to use instead.
+var reservedChromeFlags = map[string]string{
+	"--remote-debugging-port":       "--port",
+	"--user-data-dir":               "--user-data-dir",
+	"--headless":                    "--headless",
+	"--lang":                        "--lang",
+	"--window-size":                 "--window-size",
+	"--window-position":             "--window-position",
+	"--incognito":                   "--incognito",
+	"--load-extension":              "--load-extension",
+	"--auto-open-devtools-for-tabs": "--devtools",
+}
+
+// startLaunchFlags builds the browser customizations from --lang,
+// --window-size, --window-position, --incognito, --load-extension, --devtools,
+// and --chrome-flag.
+func startLaunchFlags() (ipc.LaunchFlags, error) {
+	f := ipc.LaunchFlags{Lang: startLang, Incognito: startIncognito, DevTools: startDevTools}
+	if startDevTools && startHeadless {
+		return f, fmt.Errorf("--devtools needs a visible browser; drop --headless")
+	}
+	if startWindowSize != "" {
+		w, h, ok := strings.Cut(strings.ToLower(startWindowSize), "x")
+		width, werr := strconv.Atoi(w)
+		height, herr := strconv.Atoi(h)
+		if !ok || werr != nil || herr != nil || width <= 0 || height <= 0 {
+			return f, fmt.Errorf("invalid --window-size %q (use WIDTHxHEIGHT, e.g. 1280x800)", startWindowSize)
+		}
+		f.WindowSize = fmt.Sprintf("%d,%d", width, height)
+	}
+	if startWindowPos != "" {
+		x, y, ok := strings.Cut(startWindowPos, ",")
+		_, xerr := strconv.Atoi(strings.TrimSpace(x))
+		_, yerr := strconv.Atoi(strings.TrimSpace(y))
+		if !ok || xerr != nil || yerr != nil {
+			return f, fmt.Errorf("invalid --window-position %q (use X,Y, e.g. 0,0)", startWindowPos)
+		}
+		f.WindowPosition = strings.TrimSpace(x) + "," + strings.TrimSpace(y)
+	}
+	for _, dir := range startExtensions {
+		ext, err := browser.ReadExtension(dir)
+		if err != nil {
+			return f, fmt.Errorf("invalid --load-extension %s: %w", dir, err)
+		}
+		f.Extensions = append(f.Extensions, ext.Path)
+	}
+	for _, flag := range startChromeFlags {
+		if !strings.HasPrefix(flag, "-") {
+			return f, fmt.Errorf("invalid --chrome-flag %q (a Chrome flag starts with --)", flag)
+		}
+		name, _, _ := strings.Cut(flag, "=")
+		if use, ok := reservedChromeFlags[name]; ok {
+			return f, fmt.Errorf("--chrome-flag %s is set by webctl; use %s instead", name, use)
+		}
+		f.ChromeFlags = append(f.ChromeFlags, flag)
+	}
+	debugParam("lang=%q window-size=%q window-position=%q incognito=%v devtools=%v extensions=%q chrome-flags=%q",
+		f.Lang, f.WindowSize, f.WindowPosition, f.Incognito, f.DevTools, f.Extensions, f.ChromeFlags)
+	return f, nil
+}
+
 func runStart(cmd *cobra.Command, args []string) error {
 	t := startTimer("start")
 	defer t.log()
@@ -69,7 +267,11 @@ func runStart(cmd *cobra.Command, args []string) error {
 		return printedError{err: fmt.Errorf("daemon is already running")}
 	}
 
-	debugParam("headless=%v port=%d", startHeadless, startPort)
+	debugParam("headless=%v port=%d reuse=%v adopt=%v", startHeadless, startPort, startReuse, startAdopt)
+
+	if startAdopt && startReuse {
+		return outputCodedError(ipc.CodeInvalidArgs, "--adopt and --reuse are mutually exclusive")
+	}
 
 	userDataDir, err := resolveProfile(startTempProfile, startUserDataDir, cmd.Flags().Changed("user-data-dir"), startSystemProfile)
 	if err != nil {
@@ -77,11 +279,48 @@ func runStart(cmd *cobra.Command, args []string) error {
 	}
 	debugParam("profile=%q", userDataDir)
 
+	logLevel, logHandler, err := daemonLogging()
+	if err != nil {
+		return outputError(err.Error())
+	}
+
 	cfg := daemon.DefaultConfig()
+	cfg.Version = Version
 	cfg.Headless = startHeadless
 	cfg.Port = startPort
 	cfg.UserDataDir = userDataDir
-	cfg.Debug = Debug
+	cfg.Reuse = startReuse
+	cfg.Adopt = startAdopt
+	cfg.LogPath = logFilePath(startLogFile)
+	cfg.LogLevel = logLevel
+	cfg.LogHandler = logHandler
+	cfg.MetricsAddr = startMetrics
+	if startBufferSize <= 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid --buffer-size %d (must be positive)", startBufferSize))
+	}
+	cfg.BufferSize = startBufferSize
+	if startPersist {
+		cfg.PersistDir = daemon.DefaultPersistDir()
+	}
+	cfg.BodyCapture.Rules, err = daemon.ParseCaptureRules(startCapture)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if startMaxCapture < 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid --max-capture-size %d (must be 0 or more)", startMaxCapture))
+	}
+	cfg.BodyCapture.MaxSize = startMaxCapture
+	debugParam("capture=%q", cfg.BodyCapture)
+	cfg.Emulation, err = startEmulation()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	cfg.Launch, err = startLaunchFlags()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	cfg.Launch.CI = ciMode
+	debugParam("log=%q level=%s metrics=%q", cfg.LogPath, cfg.LogLevel, cfg.MetricsAddr)
 
 	// Declare d first so the closure can capture it.
 	// The closure is only called when REPL executes commands, by which time d is set.
@@ -94,6 +333,7 @@ func runStart(cmd *cobra.Command, args []string) error {
 		defer ResetExecutorFactory()
 		return ExecuteArgs(args)
 	}
+	cfg.CommandNames = CommandNames()
 
 	// Report success only once the daemon is serving IPC, so a start that fails
 	// before readiness emits its error without a preceding success line. Run
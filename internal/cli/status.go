@@ -15,11 +15,27 @@ import (
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show daemon status",
-	Long:  "Returns the current daemon status including whether it's running, the current URL, and page title.",
-	RunE:  runStatus,
+	Long: `Returns the current daemon status including whether it's running, the current URL, and page title.
+
+With --verbose, also reports diagnostics for debugging a stuck or surprising
+session: daemon uptime and memory, the emulation applied to every tab,
+attached workers, and for each tab its console and network buffer sizes,
+in-flight requests, last navigation time, whether a navigation is pending,
+and a per-origin request summary.
+
+Flags:
+  --verbose   Include daemon and per-tab diagnostics
+
+Examples:
+  status
+  status --verbose
+  status --verbose --json | jq '.data.detail.sessions[] | {id, inFlight}'`,
+	RunE:        runStatus,
+	Annotations: map[string]string{noAutoStartAnnotation: ""},
 }
 
 func init() {
+	statusCmd.Flags().Bool("verbose", false, "Include daemon and per-tab diagnostics")
 	rootCmd.AddCommand(statusCmd)
 }
 
@@ -35,6 +51,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		if JSONOutput {
 			return outputSuccess(map[string]any{
 				"running": false,
+				"socket":  ipc.DefaultSocketPath(),
 			})
 		}
 		return format.Status(os.Stdout, status, format.NewOutputOptions(JSONOutput, NoColor))
@@ -46,10 +63,16 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = exec.Close() }()
 
-	debugRequest("status", "")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	params, err := json.Marshal(ipc.StatusParams{Verbose: verbose})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("status", string(params))
 	ipcStart := time.Now()
 
-	resp, err := exec.Execute(ipc.Request{Cmd: "status"})
+	resp, err := exec.Execute(ipc.Request{Cmd: "status", Params: params})
 
 	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
 
@@ -58,7 +81,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	if !resp.OK {
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// Parse status data
@@ -24,8 +24,9 @@ the daemon is unresponsive or processes are orphaned.
 Force cleanup sequence:
   1. Attempt graceful shutdown via IPC
   2. Kill daemon process from PID file
-  3. Kill browser process on CDP port
-  4. Remove stale socket and PID files`,
+  3. Kill browser process from the state file, or on the CDP port
+     (a browser attached with 'start --adopt' is left running)
+  4. Remove stale socket, PID, and state files`,
 	RunE: runStop,
 }
 
@@ -61,7 +62,7 @@ func runStop(cmd *cobra.Command, args []string) error {
 
 	// If not forcing, report graceful shutdown failure
 	if !stopForce {
-		return outputError("daemon not running or not responding")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running or not responding")
 	}
 
 	// Force mode: clean up everything
@@ -116,8 +117,18 @@ func forceCleanup() error {
 		debugf("STOP", "no PID file or error: %v", err)
 	}
 
-	// 2. Kill browser process on CDP port
-	if browserPID := findBrowserOnPort(stopPort); browserPID > 0 {
+	// 2. Kill the browser the state file records, falling back to the one
+	// listening on the CDP port
+	statePath := ipc.DefaultStatePath()
+	if state, err := ipc.ReadState(statePath); err == nil && state.Adopted {
+		// An adopted browser belongs to the user; never kill it
+		debugf("STOP", "leaving adopted browser on port %d running", state.Port)
+	} else if err == nil && state.BrowserPID > 0 && isBrowserProcess(state.BrowserPID) {
+		if killProcess(state.BrowserPID) {
+			cleaned = append(cleaned, fmt.Sprintf("killed browser (PID %d) on port %d", state.BrowserPID, state.Port))
+			debugf("STOP", "killed browser PID %d from state file", state.BrowserPID)
+		}
+	} else if browserPID := findBrowserOnPort(stopPort); browserPID > 0 {
 		if killProcess(browserPID) {
 			cleaned = append(cleaned, fmt.Sprintf("killed browser (PID %d) on port %d", browserPID, stopPort))
 			debugf("STOP", "killed browser PID %d on port %d", browserPID, stopPort)
@@ -145,6 +156,14 @@ func forceCleanup() error {
 		errors = append(errors, fmt.Sprintf("failed to remove PID file: %v", err))
 	}
 
+	// 5. Remove stale state file
+	if err := os.Remove(statePath); err == nil {
+		cleaned = append(cleaned, "removed state file")
+		debugf("STOP", "removed state file: %s", statePath)
+	} else if !os.IsNotExist(err) {
+		errors = append(errors, fmt.Sprintf("failed to remove state file: %v", err))
+	}
+
 	// Report results
 	if len(errors) > 0 {
 		return outputError(strings.Join(errors, "; "))
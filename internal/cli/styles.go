@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var stylesCmd = &cobra.Command{
+	Use:   "styles",
+	Short: "Compare computed styles",
+	Long: `Compares computed styles between elements or between states of one element.
+
+For reading styles, see the css command (css computed, css get).
+
+Subcommands:
+  diff <selA> <selB>        Properties that differ between two elements
+  diff <sel> --before       Snapshot an element's computed styles
+  diff <sel> --after        Properties that changed since the snapshot
+
+Examples:
+  styles diff "#primary" ".card .btn"
+  styles diff "#menu" --before && webctl click "#toggle" && webctl styles diff "#menu" --after`,
+}
+
+var stylesDiffCmd = &cobra.Command{
+	Use:   "diff <selectorA> [selectorB]",
+	Short: "Show computed properties that differ",
+	Long: `Prints only the computed CSS properties that differ, answering "why does this
+render differently here".
+
+Two-element mode compares the first match of each selector. Snapshot mode
+compares one element with itself around an action: --before stores its
+computed styles in the daemon, and a later --after with the same selector
+prints what changed. A snapshot is kept until the next --before for that
+selector, so several --after checks can follow one --before.
+
+Flags:
+  --before          Snapshot the element's computed styles
+  --after           Compare against the --before snapshot
+
+Examples:
+  styles diff "#primary" ".card .btn"
+  styles diff "nav a:nth-child(1)" "nav a:nth-child(2)"
+
+  styles diff ".menu" --before
+  webctl click "#menu-toggle"
+  styles diff ".menu" --after
+
+Text output:
+  --- #primary
+  +++ .card .btn
+  color: rgb(0, 0, 0) -> rgb(255, 0, 0)
+  padding-top: 8px -> 12px
+
+JSON output:
+  {"ok": true, "a": "#primary", "b": ".card .btn",
+   "diff": [{"property": "color", "a": "rgb(0, 0, 0)", "b": "rgb(255, 0, 0)"}]}
+
+Error cases:
+  - "selector '.missing' matched no elements" - nothing matches selector
+  - "no --before snapshot for selector '.menu'" - run --before first
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runStylesDiff,
+}
+
+func init() {
+	stylesDiffCmd.Flags().Bool("before", false, "Snapshot the element's computed styles")
+	stylesDiffCmd.Flags().Bool("after", false, "Compare against the --before snapshot")
+	stylesCmd.AddCommand(stylesDiffCmd)
+	rootCmd.AddCommand(stylesCmd)
+}
+
+func runStylesDiff(cmd *cobra.Command, args []string) error {
+	t := startTimer("styles diff")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	before, _ := cmd.Flags().GetBool("before")
+	after, _ := cmd.Flags().GetBool("after")
+
+	params := ipc.CSSParams{
+		Action:   "diff",
+		Selector: args[0],
+	}
+	labelA, labelB := args[0], ""
+	switch {
+	case before && after:
+		return outputCodedError(ipc.CodeInvalidArgs, "--before and --after cannot be used together")
+	case before || after:
+		if len(args) != 1 {
+			return outputCodedError(ipc.CodeInvalidArgs, "--before/--after take a single selector")
+		}
+		if before {
+			params.Snapshot = "before"
+		} else {
+			params.Snapshot = "after"
+			labelA, labelB = args[0]+" (before)", args[0]+" (after)"
+		}
+	default:
+		if len(args) != 2 {
+			return outputCodedError(ipc.CodeInvalidArgs, "two selectors are required (or one with --before/--after)")
+		}
+		params.Other = args[1]
+		labelB = args[1]
+	}
+	debugParam("selector=%q other=%q snapshot=%q", params.Selector, params.Other, params.Snapshot)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("css", fmt.Sprintf("action=diff selector=%q other=%q snapshot=%q", params.Selector, params.Other, params.Snapshot))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{
+		Cmd:    "css",
+		Params: raw,
+	})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	if !resp.OK {
+		if isNoElementsError(resp.Error) {
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
+		}
+		return outputResponseError(resp)
+	}
+
+	// --before only stores a snapshot
+	if before {
+		return outputSuccess(nil)
+	}
+
+	var data ipc.CSSData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+
+	// JSON mode: both labels plus the differing properties
+	if JSONOutput {
+		diff := data.Diff
+		if diff == nil {
+			diff = []ipc.CSSPropertyDiff{}
+		}
+		result := map[string]any{
+			"ok":   true,
+			"a":    labelA,
+			"b":    labelB,
+			"diff": diff,
+		}
+		return outputJSON(os.Stdout, result)
+	}
+
+	if len(data.Diff) == 0 {
+		fmt.Println("No differences")
+		return nil
+	}
+
+	// Text mode: unified-diff style header then one line per property
+	return format.StyleDiff(os.Stdout, labelA, labelB, data.Diff)
+}
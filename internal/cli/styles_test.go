@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// setStylesFlag sets a styles diff flag and restores it after the test.
+func setStylesFlag(t *testing.T, name string) {
+	t.Helper()
+	if err := stylesDiffCmd.Flags().Set(name, "true"); err != nil {
+		t.Fatalf("set %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		f := stylesDiffCmd.Flags().Lookup(name)
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+func TestRunStylesDiff_TwoSelectors(t *testing.T) {
+	exec := expectParamsExecutor(t, "css",
+		ipc.CSSParams{Action: "diff", Selector: "#a", Other: "#b"},
+		ipc.SuccessResponse(ipc.CSSData{Diff: []ipc.CSSPropertyDiff{{Property: "color", A: "red", B: "blue"}}}))
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runStylesDiff(stylesDiffCmd, []string{"#a", "#b"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "--- #a\n+++ #b\ncolor: red -> blue\n"
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestRunStylesDiff_AfterNoDifferences(t *testing.T) {
+	enableJSONOutput(t)
+	setStylesFlag(t, "after")
+	exec := expectParamsExecutor(t, "css",
+		ipc.CSSParams{Action: "diff", Selector: ".menu", Snapshot: "after"},
+		ipc.SuccessResponse(ipc.CSSData{}))
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runStylesDiff(stylesDiffCmd, []string{".menu"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result struct {
+		OK   bool                  `json:"ok"`
+		Diff []ipc.CSSPropertyDiff `json:"diff"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !result.OK || result.Diff == nil || len(result.Diff) != 0 {
+		t.Errorf("expected ok with empty diff array, got %s", out)
+	}
+}
+
+func TestRunStylesDiff_RequiresSecondSelector(t *testing.T) {
+	enableJSONOutput(t)
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			t.Error("daemon should not be called without a second selector")
+			return ipc.Response{}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stderr, func() {
+		err = runStylesDiff(stylesDiffCmd, []string{"#a"})
+	})
+	if err == nil {
+		t.Error("expected error with a single selector and no snapshot flag")
+	}
+	if !strings.Contains(out, "two selectors are required") {
+		t.Errorf("unexpected error output: %q", out)
+	}
+}
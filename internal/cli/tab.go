@@ -90,7 +90,7 @@ func runTabList(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -115,7 +115,7 @@ func runTabList(cmd *cobra.Command, args []string) error {
 		return outputError(err.Error())
 	}
 	if !resp.OK {
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	var data ipc.TabData
@@ -134,7 +134,7 @@ func runTabSwitch(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -179,7 +179,7 @@ func runTabNew(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -209,7 +209,7 @@ func runTabNew(cmd *cobra.Command, args []string) error {
 		return outputError(err.Error())
 	}
 	if !resp.OK {
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	var data ipc.NewTabData
@@ -231,7 +231,7 @@ func runTabClose(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	exec, err := execFactory.NewExecutor()
@@ -286,11 +286,16 @@ func outputTabError(resp ipc.Response) error {
 	if len(resp.Data) > 0 {
 		_ = json.Unmarshal(resp.Data, &errData)
 	}
+	code := resp.Code
+	if code == "" {
+		code = ipc.ErrorCode(resp.Error)
+	}
 
 	if JSONOutput {
 		out := map[string]any{
 			"ok":    false,
 			"error": resp.Error,
+			"code":  code,
 		}
 		if len(errData.Matches) > 0 {
 			out["matches"] = errData.Matches
@@ -299,24 +304,35 @@ func outputTabError(resp ipc.Response) error {
 			out["sessions"] = errData.Sessions
 		}
 		_ = outputJSON(os.Stderr, out)
-		return printedError{err: fmt.Errorf("%s", resp.Error)}
+		return printedError{err: fmt.Errorf("%s", resp.Error), code: code}
 	}
 
 	_ = format.TabError(os.Stderr, resp.Error, errData.Sessions, errData.Matches, format.NewOutputOptions(JSONOutput, NoColor))
-	return printedError{err: fmt.Errorf("%s", resp.Error)}
+	return printedError{err: fmt.Errorf("%s", resp.Error), code: code}
+}
+
+// tabListSession is one tab in the "tab --json" list.
+type tabListSession struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Active bool   `json:"active"`
+	// Context is the 'context new' browser context the tab belongs to.
+	Context string `json:"context,omitempty"`
 }
 
 // outputTabListJSON emits the tab list as JSON with full session IDs and titles,
 // so JSON consumers can round-trip ids back into `tab switch` and friends. The
 // text formatter (format.Tab) keeps its own truncation for display.
 func outputTabListJSON(data ipc.TabData) error {
-	sessions := make([]map[string]any, len(data.Sessions))
+	sessions := make([]tabListSession, len(data.Sessions))
 	for i, s := range data.Sessions {
-		sessions[i] = map[string]any{
-			"id":     s.ID,
-			"title":  s.Title,
-			"url":    s.URL,
-			"active": s.ID == data.ActiveSession,
+		sessions[i] = tabListSession{
+			ID:      s.ID,
+			Title:   s.Title,
+			URL:     s.URL,
+			Active:  s.ID == data.ActiveSession,
+			Context: s.Context,
 		}
 	}
 	return outputJSON(os.Stdout, map[string]any{
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var throttleCmd = &cobra.Command{
+	Use:   "throttle",
+	Short: "Slow the browser down to reproduce low-end devices",
+	Long: `Slows the browser down in every tab, to reproduce how a page behaves on a
+low-end device. Network throttling is set when the daemon starts, with
+'start --throttle'.
+
+Subcommands:
+  cpu [factor|off]    Slow the CPU by a factor, or show the current factor`,
+}
+
+var throttleCPUCmd = &cobra.Command{
+	Use:   "cpu [factor|off]",
+	Short: "Slow the CPU by a factor, or show the current factor",
+	Long: `Slows the CPU of every tab by factor, as DevTools' CPU throttling does: at 4,
+scripts, style, and layout take four times as long. Tabs opened later follow
+the setting, which lasts until changed or the daemon stops. off, or a factor
+of 1, turns throttling off. Without an argument, prints the current factor.
+
+Combine with 'start --throttle slow-4g' to reproduce a low-end phone. The
+factor in effect is reported by perf shifts, bench compare, and
+status --verbose.
+
+Examples:
+  throttle cpu 4
+  throttle cpu 6x && bench compare app.test staging.app.test
+  throttle cpu off
+  throttle cpu
+
+Response:
+  CPU throttled 4x
+
+Error cases:
+  - "invalid factor ..." - not a number of 1 or more, or off
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runThrottleCPU,
+}
+
+func init() {
+	throttleCmd.AddCommand(throttleCPUCmd)
+	rootCmd.AddCommand(throttleCmd)
+}
+
+func runThrottleCPU(cmd *cobra.Command, args []string) error {
+	t := startTimer("throttle cpu")
+	defer t.log()
+
+	params := ipc.ThrottleParams{Action: "status"}
+	if len(args) == 1 {
+		rate, err := parseCPUFactor(args[0])
+		if err != nil {
+			return outputCodedError(ipc.CodeInvalidArgs, err.Error())
+		}
+		params = ipc.ThrottleParams{Action: "cpu", Rate: rate}
+	}
+	debugParam("action=%s rate=%g", params.Action, params.Rate)
+
+	data, err := throttleRequest(params)
+	if err != nil {
+		return err
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":  true,
+			"cpu": data.CPU,
+		})
+	}
+	if data.CPU <= 1 {
+		_, err = fmt.Fprintln(os.Stdout, "CPU not throttled")
+	} else {
+		_, err = fmt.Fprintf(os.Stdout, "CPU throttled %s\n", format.CPUFactor(data.CPU))
+	}
+	return err
+}
+
+// parseCPUFactor parses a CPU slowdown factor: a number of 1 or more with an
+// optional x suffix, or off for 1.
+func parseCPUFactor(s string) (float64, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if v == "off" {
+		return 1, nil
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSuffix(v, "x"), 64)
+	if err != nil || rate < 1 || rate > 100 {
+		return 0, fmt.Errorf("invalid factor %q (use a number from 1 to 100, or off)", s)
+	}
+	return rate, nil
+}
+
+// throttleRequest sends a throttle action to the daemon.
+func throttleRequest(params ipc.ThrottleParams) (ipc.ThrottleData, error) {
+	var data ipc.ThrottleData
+	if !execFactory.IsDaemonRunning() {
+		return data, outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+
+	debugRequest("throttle", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "throttle", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return data, outputError(err.Error())
+	}
+	if !resp.OK {
+		return data, outputResponseError(resp)
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return data, outputError(err.Error())
+	}
+	return data, nil
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestParseCPUFactor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"4", 4},
+		{"2.5x", 2.5},
+		{"6X", 6},
+		{"off", 1},
+		{"1", 1},
+	}
+	for _, tt := range tests {
+		got, err := parseCPUFactor(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseCPUFactor(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"0.5", "fast", "", "1000"} {
+		if _, err := parseCPUFactor(bad); err == nil {
+			t.Errorf("parseCPUFactor(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestRunThrottleCPU(t *testing.T) {
+	var got ipc.ThrottleParams
+	rate := 1.0
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		if req.Cmd != "throttle" {
+			t.Errorf("expected cmd=throttle, got %s", req.Cmd)
+		}
+		got = ipc.ThrottleParams{}
+		_ = json.Unmarshal(req.Params, &got)
+		if got.Action == "cpu" {
+			rate = got.Rate
+		}
+		return ipc.SuccessResponse(ipc.ThrottleData{CPU: rate}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	tests := []struct {
+		args   []string
+		action string
+		want   string
+	}{
+		{[]string{"4x"}, "cpu", "CPU throttled 4x\n"},
+		{nil, "status", "CPU throttled 4x\n"},
+		{[]string{"off"}, "cpu", "CPU not throttled\n"},
+	}
+	for _, tt := range tests {
+		var err error
+		out := captureStream(t, &os.Stdout, func() {
+			err = runThrottleCPU(throttleCPUCmd, tt.args)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Action != tt.action || out != tt.want {
+			t.Errorf("%v: action = %s, output = %q", tt.args, got.Action, out)
+		}
+	}
+
+	var err error
+	_ = captureStream(t, &os.Stderr, func() {
+		err = runThrottleCPU(throttleCPUCmd, []string{"slow"})
+	})
+	if err == nil || ErrorCode(err) != ipc.CodeInvalidArgs || !strings.Contains(err.Error(), "invalid factor") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// timeWindow keeps entries whose timestamp, in Unix milliseconds, falls in
+// [after, before]. A zero bound is open.
+type timeWindow struct {
+	after, before int64
+}
+
+// active reports whether the window excludes anything.
+func (w timeWindow) active() bool {
+	return w.after != 0 || w.before != 0
+}
+
+// contains reports whether ms falls inside the window.
+func (w timeWindow) contains(ms int64) bool {
+	if w.after != 0 && ms < w.after {
+		return false
+	}
+	if w.before != 0 && ms > w.before {
+		return false
+	}
+	return true
+}
+
+// String describes the window for debug output.
+func (w timeWindow) String() string {
+	bound := func(ms int64) string {
+		if ms == 0 {
+			return "*"
+		}
+		return time.UnixMilli(ms).Local().Format("2006-01-02T15:04:05.000")
+	}
+	return fmt.Sprintf("[%s, %s]", bound(w.after), bound(w.before))
+}
+
+// timeWindowFromFlags reads --since, --after, and --before from cmd or its
+// parent's persistent flags.
+func timeWindowFromFlags(cmd *cobra.Command) (timeWindow, error) {
+	since, _ := cmd.Flags().GetDuration("since")
+	if since == 0 && cmd.Parent() != nil {
+		since, _ = cmd.Parent().PersistentFlags().GetDuration("since")
+	}
+
+	after, _ := cmd.Flags().GetString("after")
+	if after == "" && cmd.Parent() != nil {
+		after, _ = cmd.Parent().PersistentFlags().GetString("after")
+	}
+
+	before, _ := cmd.Flags().GetString("before")
+	if before == "" && cmd.Parent() != nil {
+		before, _ = cmd.Parent().PersistentFlags().GetString("before")
+	}
+
+	return parseTimeWindow(since, after, before, time.Now())
+}
+
+// parseTimeWindow builds the window for --since, --after, and --before
+// relative to now. --since and --after both set the lower bound, so they are
+// mutually exclusive.
+func parseTimeWindow(since time.Duration, after, before string, now time.Time) (timeWindow, error) {
+	var w timeWindow
+	if since != 0 && after != "" {
+		return w, fmt.Errorf("--since and --after are mutually exclusive")
+	}
+	if since < 0 {
+		return w, fmt.Errorf("invalid --since %s: must be positive", since)
+	}
+	if since > 0 {
+		w.after = now.Add(-since).UnixMilli()
+	}
+	if after != "" {
+		t, err := parseTimestamp(after, now)
+		if err != nil {
+			return w, fmt.Errorf("invalid --after: %v", err)
+		}
+		w.after = t.UnixMilli()
+	}
+	if before != "" {
+		t, err := parseTimestamp(before, now)
+		if err != nil {
+			return w, fmt.Errorf("invalid --before: %v", err)
+		}
+		w.before = t.UnixMilli()
+	}
+	if w.after != 0 && w.before != 0 && w.after > w.before {
+		return w, fmt.Errorf("time window is empty: start is after end")
+	}
+	return w, nil
+}
+
+// timestampLayouts are the absolute forms parseTimestamp accepts. Layouts
+// without a zone are local time.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// clockLayouts are the time-of-day forms parseTimestamp accepts, taken as
+// today in local time: the same clock the console list prints.
+var clockLayouts = []string{
+	"15:04:05.999999999",
+	"15:04",
+}
+
+// parseTimestamp parses an --after or --before value: an RFC 3339 or local
+// date and time, a local time of day (15:04:05, 15:04), or Unix milliseconds
+// as found in the JSON "timestamp" and "requestTime" fields.
+func parseTimestamp(s string, now time.Time) (time.Time, error) {
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range clockLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			y, m, d := now.Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), now.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time (use 15:04:05, 2006-01-02T15:04:05Z07:00, or Unix milliseconds)", s)
+}
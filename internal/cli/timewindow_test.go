@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	loc := time.FixedZone("AEST", 10*3600)
+	now := time.Date(2025, 6, 1, 14, 45, 0, 0, loc)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"14:30:05", time.Date(2025, 6, 1, 14, 30, 5, 0, loc)},
+		{"14:30:05.250", time.Date(2025, 6, 1, 14, 30, 5, 250e6, loc)},
+		{"09:15", time.Date(2025, 6, 1, 9, 15, 0, 0, loc)},
+		{"2025-05-31T23:00:00", time.Date(2025, 5, 31, 23, 0, 0, 0, loc)},
+		{"2025-05-31 23:00", time.Date(2025, 5, 31, 23, 0, 0, 0, loc)},
+		{"2025-06-01T04:30:05Z", time.Date(2025, 6, 1, 4, 30, 5, 0, time.UTC)},
+		{"1748752205000", time.UnixMilli(1748752205000)},
+	}
+	for _, tt := range tests {
+		got, err := parseTimestamp(tt.in, now)
+		if err != nil {
+			t.Errorf("parseTimestamp(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTimestamp(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := parseTimestamp("yesterday", now); err == nil {
+		t.Error("parseTimestamp(\"yesterday\") should fail")
+	}
+}
+
+func TestParseTimeWindow(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+
+	w, err := parseTimeWindow(5*time.Second, "", "", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.after != 995_000 || w.before != 0 {
+		t.Errorf("--since 5s = %+v, want after 995000", w)
+	}
+
+	w, err = parseTimeWindow(0, "990000", "999000", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.after != 990_000 || w.before != 999_000 {
+		t.Errorf("--after/--before = %+v", w)
+	}
+
+	w, err = parseTimeWindow(0, "", "", now)
+	if err != nil || w.active() {
+		t.Errorf("no flags = %+v, %v; want inactive", w, err)
+	}
+
+	for name, tc := range map[string]struct {
+		since         time.Duration
+		after, before string
+		want          string
+	}{
+		"since and after": {time.Minute, "990000", "", "mutually exclusive"},
+		"negative since":  {-time.Minute, "", "", "must be positive"},
+		"bad after":       {0, "soon", "", "invalid --after"},
+		"bad before":      {0, "", "later", "invalid --before"},
+		"inverted":        {0, "999000", "990000", "start is after end"},
+	} {
+		if _, err := parseTimeWindow(tc.since, tc.after, tc.before, now); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: error = %v, want %q", name, err, tc.want)
+		}
+	}
+}
+
+func TestTimeWindow_Contains(t *testing.T) {
+	w := timeWindow{after: 100, before: 200}
+	for ms, want := range map[int64]bool{99: false, 100: true, 150: true, 200: true, 201: false} {
+		if got := w.contains(ms); got != want {
+			t.Errorf("contains(%d) = %v, want %v", ms, got, want)
+		}
+	}
+	if !(timeWindow{after: 100}).contains(1 << 40) {
+		t.Error("open upper bound should contain later times")
+	}
+}
+
+func TestFilterByTimeWindow(t *testing.T) {
+	w := timeWindow{after: 2000, before: 3000}
+
+	console := filterConsoleByTime([]ipc.ConsoleEntry{
+		{Seq: 1, Timestamp: 1000},
+		{Seq: 2, Timestamp: 2000},
+		{Seq: 3, Timestamp: 3000},
+		{Seq: 4, Timestamp: 4000},
+	}, w)
+	if len(console) != 2 || console[0].Seq != 2 || console[1].Seq != 3 {
+		t.Errorf("console window kept %+v, want seqs 2 and 3", console)
+	}
+
+	network := filterNetworkEntries([]ipc.NetworkEntry{
+		{Seq: 1, RequestTime: 1500},
+		{Seq: 2, RequestTime: 2500},
+		{Seq: 3, RequestTime: 3500},
+	}, nil, nil, networkFilterOptions{window: w})
+	if len(network) != 1 || network[0].Seq != 2 {
+		t.Errorf("network window kept %+v, want seq 2", network)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/grantcarthew/webctl/internal/ipc"
@@ -21,6 +22,9 @@ With two arguments: focuses the element matching the selector, then types.
 Flags:
   --key <key>     Send a key after typing (e.g., Enter, Tab)
   --clear         Clear existing content before typing (select all + delete)
+  --delay <d>     Type key by key, pausing d (or a random time in a range
+                  such as 50ms-120ms) between keys
+  --ime           Compose the text as an input method would, then commit it
 
 The --clear flag is OS-aware:
   - macOS: Uses Cmd+A (Meta+A) to select all
@@ -43,6 +47,23 @@ With --key flag (send key after typing):
 With --clear flag (replace existing content):
   type "#email" "new@email.com" --clear # Clear first, then type
 
+With --delay (realistic typing):
+  By default the text is inserted at once, like a paste. With --delay each
+  character is a separate key press, with a pause before the next, for
+  autocomplete and validation widgets that only react to key events.
+
+  type "#city" "Melbourne" --delay 80ms          # 80ms between keys
+  type "#city" "Melbourne" --delay 50ms-120ms    # Random 50-120ms
+
+With --ime (input method composition):
+  The text is composed one character at a time with Input.imeSetComposition,
+  then committed, so the page sees compositionstart, compositionupdate, and
+  compositionend as with CJK input or an emoji picker. Use it for editors and
+  fields that handle composition events. --delay paces the updates.
+
+  type "#chat" "こんにちは" --ime
+  type "#message" "你好" --ime --delay 100ms --key Enter
+
 Combined flags:
   type "#search" "new query" --clear --key Enter
 
@@ -82,6 +103,8 @@ Error cases:
 func init() {
 	typeCmd.Flags().String("key", "", "Key to send after typing (e.g., Enter)")
 	typeCmd.Flags().Bool("clear", false, "Clear existing content before typing")
+	typeCmd.Flags().String("delay", "", "Type key by key with this delay, or a random one in a range (e.g., 50ms-120ms)")
+	typeCmd.Flags().Bool("ime", false, "Compose the text as an input method would, then commit it")
 	rootCmd.AddCommand(typeCmd)
 }
 
@@ -90,12 +113,18 @@ func runType(cmd *cobra.Command, args []string) error {
 	defer t.log()
 
 	if !execFactory.IsDaemonRunning() {
-		return outputError("daemon not running. Start with: webctl start")
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
 	}
 
 	// Read flags from command
 	key, _ := cmd.Flags().GetString("key")
 	clear, _ := cmd.Flags().GetBool("clear")
+	delay, _ := cmd.Flags().GetString("delay")
+	ime, _ := cmd.Flags().GetBool("ime")
+	delayMin, delayMax, err := parseTypeDelay(delay)
+	if err != nil {
+		return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid --delay: %v", err))
+	}
 
 	var selector, text string
 	if len(args) == 1 {
@@ -106,7 +135,7 @@ func runType(cmd *cobra.Command, args []string) error {
 	}
 
 	// Note: don't log text content for security reasons
-	debugParam("selector=%q key=%q clear=%v textLen=%d", selector, key, clear, len(text))
+	debugParam("selector=%q key=%q clear=%v delay=%v-%v ime=%v textLen=%d", selector, key, clear, delayMin, delayMax, ime, len(text))
 
 	exec, err := execFactory.NewExecutor()
 	if err != nil {
@@ -119,6 +148,9 @@ func runType(cmd *cobra.Command, args []string) error {
 		Text:     text,
 		Key:      key,
 		Clear:    clear,
+		DelayMin: int(delayMin.Milliseconds()),
+		DelayMax: int(delayMax.Milliseconds()),
+		IME:      ime,
 	})
 	if err != nil {
 		return outputError(err.Error())
@@ -140,9 +172,9 @@ func runType(cmd *cobra.Command, args []string) error {
 
 	if !resp.OK {
 		if isNoElementsError(resp.Error) {
-			return outputNotice("No elements found")
+			return outputCodedNotice(ipc.CodeElementNotFound, "No elements found")
 		}
-		return outputError(resp.Error)
+		return outputResponseError(resp)
 	}
 
 	// JSON mode: output JSON
@@ -156,3 +188,35 @@ func runType(cmd *cobra.Command, args []string) error {
 	// Text mode: just output OK
 	return outputSuccess(nil)
 }
+
+// maxTypeDelay caps --delay, which applies between every pair of keys.
+const maxTypeDelay = 10 * time.Second
+
+// parseTypeDelay parses --delay: a duration, or a range of two joined by a
+// dash. Empty means no delay.
+func parseTypeDelay(s string) (lo, hi time.Duration, err error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+	loStr, hiStr, isRange := strings.Cut(s, "-")
+	if lo, err = time.ParseDuration(strings.TrimSpace(loStr)); err != nil {
+		return 0, 0, err
+	}
+	hi = lo
+	if isRange {
+		if hi, err = time.ParseDuration(strings.TrimSpace(hiStr)); err != nil {
+			return 0, 0, err
+		}
+	}
+	switch {
+	case lo < 0:
+		return 0, 0, fmt.Errorf("%s is negative", s)
+	case hi < lo:
+		return 0, 0, fmt.Errorf("%s ends before it starts", s)
+	case hi > maxTypeDelay:
+		return 0, 0, fmt.Errorf("%s is over %s", s, maxTypeDelay)
+	case hi > 0 && hi < time.Millisecond:
+		return 0, 0, fmt.Errorf("%s is under 1ms", s)
+	}
+	return lo, hi, nil
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var waitRequestCmd = &cobra.Command{
+	Use:   "wait-request --url <pattern>",
+	Short: "Wait for a matching network request to complete",
+	Long: `Blocks until a request in the active tab whose URL matches --url completes,
+then prints it. Use it to synchronize a script on an API call instead of
+sleeping: trigger the call, then wait for it.
+
+--url is a regular expression, as for 'network --url', matched anywhere in the
+URL. A request completes when its response arrives or it fails. Requests that
+had completed before wait-request started do not count; requests still in
+flight do, so start the wait right after the action that sends the request.
+
+With --status, only a response with a matching status counts, and failed
+requests are ignored. Without it, the first matching request counts whatever
+its outcome, and a failed one makes wait-request exit 1 with the reason.
+
+Flags:
+  --url <pattern>       URL regex to match (required)
+  --status <pattern>    Status to wait for: 200, 2xx, or 200-299
+  --timeout <d>         Maximum time to wait (default 60s)
+
+Examples:
+  click "#save" && wait-request --url /api/save --status 2xx
+  wait-request --url "/graphql$" --timeout 10s
+  wait-request --url /api/orders --json | jq .entry.responseBody
+
+Response (the entry's seq, for 'network <seq>'):
+  42 POST https://app.test/api/save 201 87ms fetch 1.2KB
+
+JSON response:
+  {"ok": true, "entry": {"seq": 42, "url": "...", "status": 201, ...}}
+
+Error cases:
+  - "--url is required" - no pattern given
+  - "timeout waiting for request matching: <pattern>" - no match in time
+  - "request failed: <reason>" - the matching request failed`,
+	Args: cobra.NoArgs,
+	RunE: runWaitRequest,
+}
+
+func init() {
+	waitRequestCmd.Flags().String("url", "", "URL regex to match")
+	waitRequestCmd.Flags().String("status", "", "Status to wait for (200, 2xx, or 200-299)")
+	waitRequestCmd.Flags().Duration("timeout", 60*time.Second, "Maximum time to wait")
+	rootCmd.AddCommand(waitRequestCmd)
+}
+
+func runWaitRequest(cmd *cobra.Command, args []string) error {
+	t := startTimer("wait-request")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	url, _ := cmd.Flags().GetString("url")
+	status, _ := cmd.Flags().GetString("status")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	if url == "" {
+		return outputCodedError(ipc.CodeInvalidArgs, "--url is required")
+	}
+	if timeout <= 0 {
+		return outputCodedError(ipc.CodeInvalidArgs, "--timeout must be positive")
+	}
+	params := ipc.WaitRequestParams{URL: url, Timeout: int(timeout.Seconds())}
+	if params.Timeout == 0 {
+		params.Timeout = 1
+	}
+	if status != "" {
+		matchers, err := parseStatusPatterns([]string{status})
+		if err != nil || len(matchers) != 1 {
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid --status %q (use 200, 2xx, or 200-299)", status))
+		}
+		m := matchers[0]
+		if m.isRange || m.isWildcard {
+			params.StatusMin, params.StatusMax = m.rangeStart, m.rangeEnd
+		} else {
+			params.StatusMin, params.StatusMax = m.exact, m.exact
+		}
+	}
+
+	debugParam("url=%q status=%q timeout=%v", url, status, timeout)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("wait-request", string(raw))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "wait-request", Params: raw})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.WaitRequestData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+	if data.Entry.Failed {
+		return outputError(fmt.Sprintf("request failed: %s %s: %s", data.Entry.Method, data.Entry.URL, data.Entry.Error))
+	}
+
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":    true,
+			"entry": data.Entry,
+		})
+	}
+
+	opts := format.NewOutputOptions(JSONOutput, NoColor)
+	opts.Detail = format.DetailSummary
+	return format.Network(os.Stdout, []ipc.NetworkEntry{data.Entry}, opts)
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func newWaitRequestTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("url", "", "")
+	cmd.Flags().String("status", "", "")
+	cmd.Flags().Duration("timeout", 60*time.Second, "")
+	return cmd
+}
+
+func TestRunWaitRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		wantMin int
+		wantMax int
+	}{
+		{"any status", "", 0, 0},
+		{"class", "2xx", 200, 299},
+		{"exact", "201", 201, 201},
+		{"range", "200-204", 200, 204},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ipc.WaitRequestParams
+			exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+				if req.Cmd != "wait-request" {
+					t.Errorf("cmd = %q", req.Cmd)
+				}
+				_ = json.Unmarshal(req.Params, &got)
+				return ipc.SuccessResponse(ipc.WaitRequestData{Entry: ipc.NetworkEntry{
+					Seq: 42, Method: "POST", URL: "https://app.test/api/save", Status: 201, Duration: 0.087,
+				}}), nil
+			}}
+			defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+			cmd := newWaitRequestTestCmd()
+			_ = cmd.Flags().Set("url", "/api/save")
+			_ = cmd.Flags().Set("timeout", "10s")
+			if tt.status != "" {
+				_ = cmd.Flags().Set("status", tt.status)
+			}
+
+			var err error
+			out := captureStream(t, &os.Stdout, func() {
+				err = runWaitRequest(cmd, nil)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := ipc.WaitRequestParams{URL: "/api/save", StatusMin: tt.wantMin, StatusMax: tt.wantMax, Timeout: 10}
+			if got != want {
+				t.Errorf("params = %+v, want %+v", got, want)
+			}
+			if out != "42 POST https://app.test/api/save 201 87ms\n" {
+				t.Errorf("output = %q", out)
+			}
+		})
+	}
+}
+
+func TestRunWaitRequest_Failed(t *testing.T) {
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		return ipc.SuccessResponse(ipc.WaitRequestData{Entry: ipc.NetworkEntry{
+			Seq: 7, Method: "GET", URL: "https://app.test/api/load", Failed: true, Error: "net::ERR_CONNECTION_REFUSED",
+		}}), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	cmd := newWaitRequestTestCmd()
+	_ = cmd.Flags().Set("url", "/api/load")
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runWaitRequest(cmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "request failed: GET https://app.test/api/load: net::ERR_CONNECTION_REFUSED") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRunWaitRequest_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  string
+	}{
+		{"no url", map[string]string{}, "--url is required"},
+		{"bad status", map[string]string{"url": "/api", "status": "ok"}, "invalid --status"},
+		{"zero timeout", map[string]string{"url": "/api", "timeout": "0s"}, "--timeout must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+				called = true
+				return ipc.SuccessResponse(nil), nil
+			}}
+			defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+			cmd := newWaitRequestTestCmd()
+			for k, v := range tt.flags {
+				_ = cmd.Flags().Set(k, v)
+			}
+			var err error
+			captureStream(t, &os.Stderr, func() {
+				err = runWaitRequest(cmd, nil)
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+			if called {
+				t.Error("daemon was called for invalid flags")
+			}
+		})
+	}
+}
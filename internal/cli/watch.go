@@ -0,0 +1,337 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// watchPollInterval is how often watch checks the matched files.
+	watchPollInterval = 500 * time.Millisecond
+	// watchSettle is how long watch keeps collecting console errors after
+	// the reloaded page has loaded, to catch errors from deferred scripts.
+	watchSettle = time.Second
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <glob>...",
+	Short: "Hard-reload the page when files change and print new console errors",
+	Long: `Watches files matching the globs, and when any is added, changed, or removed:
+runs --cmd (if given), hard-reloads the active tab, waits for it to load, and
+prints the console errors logged since the reload. Runs until interrupted
+(Ctrl-C).
+
+A live reload for any setup, without a dev server: the page can be served by
+anything. Use 'webctl serve' instead when webctl should serve the files too.
+
+Globs use * and ? within a path segment and [...] classes; ** is not
+supported, so give one glob per directory level ("src/*.js" "src/*/*.js").
+Quote them so the shell does not expand them: files created later are then
+picked up. Changes within 500ms are batched into one reload.
+
+When --cmd exits non-zero its output is shown and the page is not reloaded.
+The command runs with sh -c in the current directory.
+
+Flags:
+  --cmd <command>   Build command to run before each reload
+
+Examples:
+  watch "src/*.js" "src/*.css"
+  watch "src/*.ts" --cmd "npm run build"
+  watch "*.go" "templates/*.html" --cmd "make build" --json
+
+Text output:
+  Watching 14 files; Ctrl-C to stop
+  14:03:21 changed src/app.js
+  14:03:22 reloaded: 1 new console error
+    Uncaught TypeError: x is undefined (http://localhost:3000/app.js:42)
+
+JSON output (one object per change):
+  {"time":1700000000123,"changed":["src/app.js"],"build":"ok","reloaded":true,"errors":[...]}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start
+  - "no files match" - no glob matched an existing file`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.Flags().String("cmd", "", "Build command to run before each reload (run with sh -c)")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchCycle is the result of one change: the files that triggered it, the
+// build outcome, and the console errors logged after the reload.
+type watchCycle struct {
+	Time    int64    `json:"time"` // Unix milliseconds
+	Changed []string `json:"changed"`
+	// Build is "ok" or "failed" when --cmd is set; BuildError holds the exit
+	// status and output of a failed build.
+	Build      string             `json:"build,omitempty"`
+	BuildError string             `json:"buildError,omitempty"`
+	Reloaded   bool               `json:"reloaded"`
+	Errors     []ipc.ConsoleEntry `json:"errors"`
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	t := startTimer("watch")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	build, _ := cmd.Flags().GetString("cmd")
+	for _, g := range args {
+		if _, err := filepath.Match(g, ""); err != nil {
+			return outputCodedError(ipc.CodeInvalidArgs, fmt.Sprintf("invalid glob %q: %v", g, err))
+		}
+	}
+	debugParam("globs=%v cmd=%q", args, build)
+
+	files, err := watchSnapshot(args)
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if len(files) == 0 {
+		return outputError(fmt.Sprintf("no files match %s", strings.Join(args, " ")))
+	}
+
+	// Errors logged before watch started are not the new code's.
+	consoleAfter, err := watchConsoleSeq()
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	watchNotice(fmt.Sprintf("Watching %d files; Ctrl-C to stop", len(files)))
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := watchSnapshot(args)
+		if err != nil {
+			return outputError(err.Error())
+		}
+		changed := watchChanged(files, current)
+		files = current
+		if len(changed) == 0 {
+			continue
+		}
+
+		cycle := watchCycle{Time: time.Now().UnixMilli(), Changed: changed, Errors: []ipc.ConsoleEntry{}}
+		if !JSONOutput {
+			fmt.Fprintf(os.Stdout, "%s changed %s\n", time.Now().Format("15:04:05"), watchChangedList(changed))
+		}
+
+		if build != "" {
+			out, err := exec.CommandContext(ctx, "sh", "-c", build).CombinedOutput()
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				cycle.Build = "failed"
+				cycle.BuildError = strings.TrimSpace(fmt.Sprintf("%v\n%s", err, out))
+			} else {
+				cycle.Build = "ok"
+			}
+		}
+
+		if cycle.Build != "failed" {
+			if err := watchReload(); err != nil {
+				return outputError(err.Error())
+			}
+			cycle.Reloaded = true
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(watchSettle):
+			}
+
+			data, err := fetchConsoleData()
+			if err != nil {
+				return outputError(err.Error())
+			}
+			cycle.Errors, consoleAfter = watchConsoleErrors(data.Entries, consoleAfter)
+		}
+
+		if err := outputWatchCycle(cycle); err != nil {
+			return err
+		}
+		// Re-read the files so a build writing into a watched file does not
+		// trigger another cycle.
+		if files, err = watchSnapshot(args); err != nil {
+			return outputError(err.Error())
+		}
+	}
+}
+
+// watchSnapshot expands the globs and returns each matched file's
+// modification time. Directories are skipped.
+func watchSnapshot(globs []string) (map[string]time.Time, error) {
+	files := map[string]time.Time{}
+	for _, g := range globs {
+		matches, err := filepath.Glob(g)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", g, err)
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || info.IsDir() {
+				continue
+			}
+			files[m] = info.ModTime()
+		}
+	}
+	return files, nil
+}
+
+// watchChanged returns the files added, modified, or removed between two
+// snapshots, sorted.
+func watchChanged(before, after map[string]time.Time) []string {
+	var changed []string
+	for f, mod := range after {
+		if old, ok := before[f]; !ok || !old.Equal(mod) {
+			changed = append(changed, f)
+		}
+	}
+	for f := range before {
+		if _, ok := after[f]; !ok {
+			changed = append(changed, f)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// watchChangedList names up to three changed files, e.g.
+// "a.js, b.js, c.js (+2 more)".
+func watchChangedList(changed []string) string {
+	const shown = 3
+	if len(changed) <= shown {
+		return strings.Join(changed, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(changed[:shown], ", "), len(changed)-shown)
+}
+
+// watchConsoleErrors returns the console errors newer than seq after, and
+// the highest seq seen.
+func watchConsoleErrors(entries []ipc.ConsoleEntry, after uint64) ([]ipc.ConsoleEntry, uint64) {
+	errs := []ipc.ConsoleEntry{}
+	highest := after
+	for _, e := range entries {
+		if e.Seq <= after {
+			continue
+		}
+		highest = max(highest, e.Seq)
+		if e.Type == ipc.ConsoleTypeError {
+			errs = append(errs, e)
+		}
+	}
+	return errs, highest
+}
+
+// watchConsoleSeq returns the newest console entry's seq.
+func watchConsoleSeq() (uint64, error) {
+	data, err := fetchConsoleData()
+	if err != nil {
+		return 0, err
+	}
+	_, seq := watchConsoleErrors(data.Entries, 0)
+	return seq, nil
+}
+
+// watchReload hard-reloads the active tab and waits for it to load.
+func watchReload() error {
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.ReloadParams{IgnoreCache: true, Wait: true, Timeout: 60})
+	if err != nil {
+		return err
+	}
+
+	debugRequest("reload", "wait=true timeout=60 ignoreCache=true")
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "reload", Params: params})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return err
+	}
+	if !resp.OK {
+		return fmt.Errorf("%s", resp.Error)
+	}
+	return nil
+}
+
+// outputWatchCycle prints one change's outcome: a JSON line, or the build
+// failure or reload summary with each new console error.
+func outputWatchCycle(c watchCycle) error {
+	if JSONOutput {
+		return json.NewEncoder(os.Stdout).Encode(c)
+	}
+
+	now := time.Now().Format("15:04:05")
+	if c.Build == "failed" {
+		fmt.Fprintf(os.Stdout, "%s build failed, not reloading:\n", now)
+		for _, line := range strings.Split(c.BuildError, "\n") {
+			fmt.Fprintf(os.Stdout, "  %s\n", line)
+		}
+		return nil
+	}
+
+	switch len(c.Errors) {
+	case 0:
+		fmt.Fprintf(os.Stdout, "%s reloaded: no console errors\n", now)
+	case 1:
+		fmt.Fprintf(os.Stdout, "%s reloaded: 1 new console error\n", now)
+	default:
+		fmt.Fprintf(os.Stdout, "%s reloaded: %d new console errors\n", now, len(c.Errors))
+	}
+	for _, e := range c.Errors {
+		text := e.Text
+		if e.URL != "" {
+			text += fmt.Sprintf(" (%s:%d)", e.URL, e.Line)
+		}
+		fmt.Fprintf(os.Stdout, "  %s\n", text)
+	}
+	return nil
+}
+
+// watchNotice writes a status line to stderr in text mode, keeping stdout to
+// change reports only.
+func watchNotice(msg string) {
+	if JSONOutput {
+		return
+	}
+	fmt.Fprintln(os.Stderr, msg)
+}
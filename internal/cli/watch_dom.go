@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cli/format"
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+// watchDOMPollInterval is how often the CLI drains mutations from the page.
+const watchDOMPollInterval = 250 * time.Millisecond
+
+var watchDOMCmd = &cobra.Command{
+	Use:   "watch-dom [selector]",
+	Short: "Stream DOM mutations as they happen",
+	Long: `Installs a MutationObserver in the page and prints added and removed nodes,
+attribute changes, and text changes until interrupted (Ctrl-C) or --timeout.
+
+With a selector only that element's subtree is observed; without one the whole
+document is. If the selector does not match yet, watch-dom waits for it to
+appear. The observer lives in the page, so after a navigation it is
+re-installed automatically on the next poll.
+
+Mutations are buffered in the page between polls (every 250ms). If more than
+1000 accumulate in that window the oldest are dropped and a notice is printed.
+
+Flags:
+  --timeout <d>     Stop watching after this long (default: until Ctrl-C)
+
+Examples:
+  watch-dom                             # Whole document
+  watch-dom "#results"                  # One subtree
+  watch-dom ".toast" --timeout 10s      # Wait for a toast to appear
+  watch-dom --json | jq -c 'select(.type == "attribute")'
+
+Text output:
+  14:03:21.118 + li.item  in ul#results
+  14:03:21.118 - div.spinner  from main
+  14:03:21.402 ~ button#save [disabled] "" -> (none)
+  14:03:22.010 ~ span.count text "3" -> "4"
+
+JSON output (one object per line):
+  {"time":1700000000123,"type":"added","target":"ul#results","node":"li.item"}
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWatchDOM,
+}
+
+func init() {
+	watchDOMCmd.Flags().Duration("timeout", 0, "Stop watching after this duration (0 = until interrupted)")
+	rootCmd.AddCommand(watchDOMCmd)
+}
+
+func runWatchDOM(cmd *cobra.Command, args []string) error {
+	t := startTimer("watch-dom")
+	defer t.log()
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	selector := ""
+	if len(args) > 0 {
+		selector = args[0]
+	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	debugParam("selector=%q timeout=%v", selector, timeout)
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// A fresh ID per run makes the daemon replace any observer left behind by
+	// an earlier watch-dom that exited without stopping.
+	watchID := fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano())
+
+	poll := func(stop bool) (ipc.WatchDOMData, error) {
+		params, err := json.Marshal(ipc.WatchDOMParams{
+			Selector: selector,
+			WatchID:  watchID,
+			Stop:     stop,
+		})
+		if err != nil {
+			return ipc.WatchDOMData{}, err
+		}
+
+		debugRequest("watch-dom", fmt.Sprintf("selector=%q stop=%v", selector, stop))
+		ipcStart := time.Now()
+
+		resp, err := exec.Execute(ipc.Request{
+			Cmd:    "watch-dom",
+			Params: params,
+		})
+
+		debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+		if err != nil {
+			return ipc.WatchDOMData{}, err
+		}
+		if !resp.OK {
+			return ipc.WatchDOMData{}, fmt.Errorf("%s", resp.Error)
+		}
+
+		var data ipc.WatchDOMData
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			return ipc.WatchDOMData{}, err
+		}
+		return data, nil
+	}
+
+	// Always disconnect the observer, whatever ends the watch
+	defer func() { _, _ = poll(true) }()
+
+	// JSON mode streams compact JSONL, one mutation per line
+	enc := json.NewEncoder(os.Stdout)
+
+	status := ""
+	started := false
+	ticker := time.NewTicker(watchDOMPollInterval)
+	defer ticker.Stop()
+
+	for {
+		data, err := poll(false)
+		if err != nil {
+			return outputError(err.Error())
+		}
+
+		if data.Status != status {
+			switch {
+			case data.Status == "waiting" && selector != "":
+				watchDOMNotice(fmt.Sprintf("waiting for %s", selector))
+			case data.Status == "watching" && status == "waiting":
+				watchDOMNotice("watching")
+			}
+			status = data.Status
+		}
+		if data.Installed {
+			if started {
+				watchDOMNotice("observer re-installed (page changed)")
+			}
+			started = true
+		}
+		if data.Dropped > 0 {
+			watchDOMNotice(fmt.Sprintf("%d mutations dropped (buffer full)", data.Dropped))
+		}
+
+		if JSONOutput {
+			for _, m := range data.Mutations {
+				if err := enc.Encode(m); err != nil {
+					return err
+				}
+			}
+		} else if err := format.DOMMutations(os.Stdout, data.Mutations); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchDOMNotice writes a status line to stderr in text mode, keeping stdout
+// to mutations only.
+func watchDOMNotice(msg string) {
+	if JSONOutput {
+		return
+	}
+	fmt.Fprintln(os.Stderr, msg)
+}
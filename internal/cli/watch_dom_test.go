@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestRunWatchDOM_StreamsJSONLAndStops(t *testing.T) {
+	enableJSONOutput(t)
+	if err := watchDOMCmd.Flags().Set("timeout", "50ms"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		f := watchDOMCmd.Flags().Lookup("timeout")
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+
+	var requests []ipc.WatchDOMParams
+	exec := &mockExecutor{
+		executeFunc: func(req ipc.Request) (ipc.Response, error) {
+			if req.Cmd != "watch-dom" {
+				t.Errorf("expected cmd=watch-dom, got %s", req.Cmd)
+			}
+			var params ipc.WatchDOMParams
+			_ = json.Unmarshal(req.Params, &params)
+			requests = append(requests, params)
+
+			data := ipc.WatchDOMData{Status: "watching", Mutations: []ipc.DOMMutation{}}
+			if params.Stop {
+				data.Status = "stopped"
+			} else if len(requests) == 1 {
+				data.Installed = true
+				data.Mutations = []ipc.DOMMutation{
+					{Time: 1, Type: "added", Target: "ul", Node: "li"},
+					{Time: 2, Type: "removed", Target: "ul", Node: "p"},
+				}
+			}
+			raw, _ := json.Marshal(data)
+			return ipc.Response{OK: true, Data: raw}, nil
+		},
+	}
+	restore := setMockFactory(&mockFactory{daemonRunning: true, executor: exec})
+	defer restore()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runWatchDOM(watchDOMCmd, []string{"#list"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %q", out)
+	}
+	var m ipc.DOMMutation
+	if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+		t.Fatalf("failed to parse line: %v", err)
+	}
+	if m.Type != "added" || m.Node != "li" {
+		t.Errorf("unexpected first mutation: %+v", m)
+	}
+
+	if len(requests) < 2 {
+		t.Fatalf("expected at least a poll and a stop, got %d requests", len(requests))
+	}
+	last := requests[len(requests)-1]
+	if !last.Stop {
+		t.Error("expected final request to stop the observer")
+	}
+	for _, p := range requests {
+		if p.Selector != "#list" || p.WatchID == "" || p.WatchID != requests[0].WatchID {
+			t.Errorf("unexpected params: %+v", p)
+		}
+	}
+}
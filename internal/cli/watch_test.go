@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+func newWatchTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("cmd", "", "")
+	return cmd
+}
+
+func TestWatchSnapshotAndChanged(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.js")
+	b := filepath.Join(dir, "b.js")
+	for _, f := range []string{a, b, filepath.Join(dir, "c.css")} {
+		if err := os.WriteFile(f, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "dir.js"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	globs := []string{filepath.Join(dir, "*.js")}
+	before, err := watchSnapshot(globs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 2 {
+		t.Fatalf("snapshot = %v, want a.js and b.js only", before)
+	}
+
+	// Modify a, remove b, add d.
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(a, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	d := filepath.Join(dir, "d.js")
+	if err := os.WriteFile(d, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := watchSnapshot(globs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := watchChanged(before, after), []string{a, b, d}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changed = %v, want %v", got, want)
+	}
+	if got := watchChanged(after, after); len(got) != 0 {
+		t.Errorf("changed with no edits = %v", got)
+	}
+}
+
+func TestWatchChangedList(t *testing.T) {
+	if got := watchChangedList([]string{"a", "b"}); got != "a, b" {
+		t.Errorf("got %q", got)
+	}
+	if got := watchChangedList([]string{"a", "b", "c", "d", "e"}); got != "a, b, c (+2 more)" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestWatchConsoleErrors(t *testing.T) {
+	entries := []ipc.ConsoleEntry{
+		{Seq: 3, Type: "error", Text: "old"},
+		{Seq: 4, Type: "log", Text: "hello"},
+		{Seq: 5, Type: "error", Text: "new"},
+		{Seq: 6, Type: "warning", Text: "careful"},
+	}
+	errs, seq := watchConsoleErrors(entries, 3)
+	if len(errs) != 1 || errs[0].Text != "new" {
+		t.Errorf("errors = %+v, want only the new error", errs)
+	}
+	if seq != 6 {
+		t.Errorf("seq = %d, want 6", seq)
+	}
+}
+
+func TestRunWatch_NoMatch(t *testing.T) {
+	called := false
+	exec := &mockExecutor{executeFunc: func(req ipc.Request) (ipc.Response, error) {
+		called = true
+		return ipc.SuccessResponse(nil), nil
+	}}
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	captureStream(t, &os.Stderr, func() {
+		err = runWatch(newWatchTestCmd(), []string{filepath.Join(t.TempDir(), "*.js")})
+	})
+	if err == nil || !strings.Contains(err.Error(), "no files match") {
+		t.Errorf("err = %v, want no files match", err)
+	}
+	if called {
+		t.Error("daemon was called with nothing to watch")
+	}
+}
+
+func TestOutputWatchCycle(t *testing.T) {
+	out := captureStream(t, &os.Stdout, func() {
+		_ = outputWatchCycle(watchCycle{
+			Changed:  []string{"app.js"},
+			Reloaded: true,
+			Errors:   []ipc.ConsoleEntry{{Type: "error", Text: "Uncaught TypeError: x is undefined", URL: "http://localhost/app.js", Line: 42}},
+		})
+	})
+	if !strings.Contains(out, "reloaded: 1 new console error\n  Uncaught TypeError: x is undefined (http://localhost/app.js:42)\n") {
+		t.Errorf("output = %q", out)
+	}
+
+	out = captureStream(t, &os.Stdout, func() {
+		_ = outputWatchCycle(watchCycle{Changed: []string{"app.js"}, Build: "failed", BuildError: "exit status 2\nsyntax error"})
+	})
+	if !strings.Contains(out, "build failed, not reloading:\n  exit status 2\n  syntax error\n") {
+		t.Errorf("output = %q", out)
+	}
+}
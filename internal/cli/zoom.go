@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/spf13/cobra"
+)
+
+var zoomCmd = &cobra.Command{
+	Use:   "zoom [factor]",
+	Short: "Zoom the page, keeping the zoom across reloads",
+	Long: `Zooms the active tab's page by a factor, as the browser's page zoom does:
+the page is laid out and drawn larger or smaller. The zoom applies at once
+and again on every load in the tab, so it survives reloads and navigations,
+until changed or reset.
+
+The factor is a number (1.5) or a percentage (150%), from 0.25 to 5.
+"reset" sets it back to 1. Without a factor, prints the current zoom.
+
+The zoom is CSS zoom on the root element: media queries still see the
+window's real size.
+
+Examples:
+  zoom 1.5
+  zoom 80%
+  zoom reset
+  zoom
+
+Response:
+  Zoom 150%
+
+Error cases:
+  - "daemon not running" - start daemon first with: webctl start
+  - "zoom 10 out of range (0.25 to 5)" - the factor is outside the range`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runZoom,
+}
+
+func init() {
+	rootCmd.AddCommand(zoomCmd)
+}
+
+func runZoom(cmd *cobra.Command, args []string) error {
+	t := startTimer("zoom")
+	defer t.log()
+
+	var factor float64
+	if len(args) == 1 {
+		f, err := parseZoomFactor(args[0])
+		if err != nil {
+			return outputCodedError(ipc.CodeInvalidArgs, err.Error())
+		}
+		factor = f
+	}
+	debugParam("factor=%g", factor)
+
+	if !execFactory.IsDaemonRunning() {
+		return outputCodedError(ipc.CodeDaemonDown, "daemon not running. Start with: webctl start")
+	}
+
+	exec, err := execFactory.NewExecutor()
+	if err != nil {
+		return outputError(err.Error())
+	}
+	defer func() { _ = exec.Close() }()
+
+	params, err := json.Marshal(ipc.ZoomParams{Factor: factor})
+	if err != nil {
+		return outputError(err.Error())
+	}
+
+	debugRequest("zoom", string(params))
+	ipcStart := time.Now()
+
+	resp, err := exec.Execute(ipc.Request{Cmd: "zoom", Params: params})
+
+	debugResponse(err == nil && resp.OK, len(resp.Data), time.Since(ipcStart))
+
+	if err != nil {
+		return outputError(err.Error())
+	}
+	if !resp.OK {
+		return outputResponseError(resp)
+	}
+
+	var data ipc.ZoomData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return outputError(err.Error())
+	}
+	if JSONOutput {
+		return outputJSON(os.Stdout, map[string]any{
+			"ok":     true,
+			"factor": data.Factor,
+		})
+	}
+	_, err = fmt.Fprintf(os.Stdout, "Zoom %g%%\n", math.Round(data.Factor*10000)/100)
+	return err
+}
+
+// parseZoomFactor parses a zoom factor: a number, a percentage, or "reset".
+func parseZoomFactor(s string) (float64, error) {
+	if strings.EqualFold(s, "reset") {
+		return 1, nil
+	}
+	pct := strings.HasSuffix(s, "%")
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil || f <= 0 {
+		return 0, fmt.Errorf("invalid zoom %q (use a factor like 1.5, a percentage like 150%%, or reset)", s)
+	}
+	if pct {
+		f /= 100
+	}
+	return f, nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestParseZoomFactor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"1.5", 1.5, true},
+		{"150%", 1.5, true},
+		{"80%", 0.8, true},
+		{"reset", 1, true},
+		{"RESET", 1, true},
+		{"0", 0, false},
+		{"-2", 0, false},
+		{"big", 0, false},
+		{"%", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseZoomFactor(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseZoomFactor(%q) = %v, %v; want %v, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRunZoom(t *testing.T) {
+	exec := expectParamsExecutor(t, "zoom", ipc.ZoomParams{Factor: 1.25}, ipc.SuccessResponse(ipc.ZoomData{Factor: 1.25}))
+	defer setMockFactory(&mockFactory{daemonRunning: true, executor: exec})()
+
+	var err error
+	out := captureStream(t, &os.Stdout, func() {
+		err = runZoom(zoomCmd, []string{"125%"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "Zoom 125%\n" {
+		t.Errorf("output = %q, want %q", out, "Zoom 125%\n")
+	}
+}
@@ -0,0 +1,584 @@
+// Package config loads webctl's YAML configuration: the user file at
+// $XDG_CONFIG_HOME/webctl/config.yaml and an optional project-local
+// .webctl.yaml, merged with the project file taking precedence, plus named
+// presets that bundle settings selected together, named URL bookmarks, and
+// login sequences.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// ProjectFile is the name of the project-local config file, looked up in the
+// working directory and its parents.
+const ProjectFile = ".webctl.yaml"
+
+// Config is the contents of one config file, or several merged.
+type Config struct {
+	// Headless is the default for 'webctl start --headless'.
+	Headless *bool `yaml:"headless,omitempty"`
+	// Browser is the Chrome or Chromium binary, used when WEBCTL_CHROME is unset.
+	Browser string `yaml:"browser,omitempty"`
+	// Output is the default output format: text, json, jsonl, yaml, csv, or
+	// table.
+	Output string `yaml:"output,omitempty"`
+	// BufferSize is the daemon's console and network buffer capacity.
+	BufferSize int `yaml:"buffer-size,omitempty"`
+	// Timeout is the default for commands with a --timeout flag.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Viewport is the default for 'webctl start --viewport' (WIDTHxHEIGHT).
+	Viewport string `yaml:"viewport,omitempty"`
+	// UserAgent is the default for 'webctl start --user-agent'.
+	UserAgent string `yaml:"user-agent,omitempty"`
+	// Throttle is the default for 'webctl start --throttle'.
+	Throttle string `yaml:"throttle,omitempty"`
+	// Defaults holds default flag values keyed by command path (e.g.
+	// "navigate", "history go") and then flag name.
+	Defaults map[string]map[string]string `yaml:"defaults,omitempty"`
+	// Preset names the preset applied when --preset and WEBCTL_PRESET are unset.
+	Preset string `yaml:"preset,omitempty"`
+	// Presets are named bundles of the keys above, overlaid on the rest of the
+	// config when selected.
+	Presets map[string]Config `yaml:"presets,omitempty"`
+	// Bookmarks are named URLs for 'webctl open <name>'.
+	Bookmarks map[string]string `yaml:"bookmarks,omitempty"`
+	// Logins are named sign-in sequences for 'webctl login <name>'.
+	Logins map[string]Login `yaml:"logins,omitempty"`
+}
+
+// Login is a sign-in sequence: open URL, fill each field, submit, and wait
+// for the signed-in page.
+type Login struct {
+	URL    string       `yaml:"url"`
+	Fields []LoginField `yaml:"fields,omitempty"`
+	// Submit is the button clicked to sign in; without it Enter is pressed
+	// in the last field.
+	Submit string `yaml:"submit,omitempty"`
+	// Wait is a selector that matches once signed in; without it the login
+	// waits for the network to go idle.
+	Wait string `yaml:"wait,omitempty"`
+	// Timeout bounds each step (default 30s).
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// LoginField is a form field and where its text comes from: the value
+// itself, an environment variable, or the output of a command such as a
+// password manager's CLI. At most one source is set; none fills it empty.
+type LoginField struct {
+	Selector string `yaml:"selector"`
+	Value    string `yaml:"value,omitempty"`
+	Env      string `yaml:"env,omitempty"`
+	Command  string `yaml:"command,omitempty"`
+}
+
+// Output formats accepted for the output key.
+const (
+	OutputText  = "text"
+	OutputJSON  = "json"
+	OutputJSONL = "jsonl"
+	OutputYAML  = "yaml"
+	OutputCSV   = "csv"
+	OutputTable = "table"
+)
+
+// outputs lists the accepted output values in display order.
+var outputs = []string{OutputText, OutputJSON, OutputJSONL, OutputYAML, OutputCSV, OutputTable}
+
+// Keys lists the top-level keys in display order. Per-command defaults are
+// addressed as defaults.<command>.<flag>.
+var Keys = []string{"headless", "browser", "output", "buffer-size", "timeout", "viewport", "user-agent", "throttle", "preset"}
+
+const (
+	defaultsPrefix  = "defaults."
+	presetsPrefix   = "presets."
+	bookmarksPrefix = "bookmarks."
+)
+
+// UserPath returns $XDG_CONFIG_HOME/webctl/config.yaml, falling back to
+// ~/.config/webctl/config.yaml.
+func UserPath() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "webctl", "config.yaml")
+}
+
+// FindProject returns the nearest ProjectFile in dir or its parents, or "" if
+// there is none.
+func FindProject(dir string) string {
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadFile reads and validates one config file. A missing file yields an
+// empty config and no error.
+func LoadFile(path string) (Config, error) {
+	var c Config
+	if path == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := c.validate(); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes c to path, creating the parent directory if needed.
+func (c Config) Save(path string) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Merge returns c overlaid with every value set in over.
+func (c Config) Merge(over Config) Config {
+	if over.Headless != nil {
+		c.Headless = over.Headless
+	}
+	if over.Browser != "" {
+		c.Browser = over.Browser
+	}
+	if over.Output != "" {
+		c.Output = over.Output
+	}
+	if over.BufferSize != 0 {
+		c.BufferSize = over.BufferSize
+	}
+	if over.Timeout != 0 {
+		c.Timeout = over.Timeout
+	}
+	if over.Viewport != "" {
+		c.Viewport = over.Viewport
+	}
+	if over.UserAgent != "" {
+		c.UserAgent = over.UserAgent
+	}
+	if over.Throttle != "" {
+		c.Throttle = over.Throttle
+	}
+	if over.Preset != "" {
+		c.Preset = over.Preset
+	}
+	if len(over.Presets) > 0 {
+		merged := make(map[string]Config, len(c.Presets)+len(over.Presets))
+		for name, p := range c.Presets {
+			merged[name] = p
+		}
+		for name, p := range over.Presets {
+			merged[name] = merged[name].Merge(p)
+		}
+		c.Presets = merged
+	}
+	if len(over.Bookmarks) > 0 {
+		merged := make(map[string]string, len(c.Bookmarks)+len(over.Bookmarks))
+		for _, b := range []map[string]string{c.Bookmarks, over.Bookmarks} {
+			for name, url := range b {
+				merged[name] = url
+			}
+		}
+		c.Bookmarks = merged
+	}
+	if len(over.Logins) > 0 {
+		merged := make(map[string]Login, len(c.Logins)+len(over.Logins))
+		for _, l := range []map[string]Login{c.Logins, over.Logins} {
+			for name, login := range l {
+				merged[name] = login
+			}
+		}
+		c.Logins = merged
+	}
+	if len(over.Defaults) > 0 {
+		merged := make(map[string]map[string]string, len(c.Defaults)+len(over.Defaults))
+		for _, d := range []map[string]map[string]string{c.Defaults, over.Defaults} {
+			for cmd, flags := range d {
+				if merged[cmd] == nil {
+					merged[cmd] = make(map[string]string, len(flags))
+				}
+				for flag, v := range flags {
+					merged[cmd][flag] = v
+				}
+			}
+		}
+		c.Defaults = merged
+	}
+	return c
+}
+
+// WithPreset returns c overlaid with the named preset. An empty name returns c
+// unchanged.
+func (c Config) WithPreset(name string) (Config, error) {
+	if name == "" {
+		return c, nil
+	}
+	p, ok := c.Presets[name]
+	if !ok {
+		return c, fmt.Errorf("unknown preset %q (%s)", name, c.presetList())
+	}
+	return c.Merge(p), nil
+}
+
+// PresetNames returns the defined preset names, sorted.
+func (c Config) PresetNames() []string {
+	names := make([]string, 0, len(c.Presets))
+	for name := range c.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoginNames returns the defined login names, sorted.
+func (c Config) LoginNames() []string {
+	names := make([]string, 0, len(c.Logins))
+	for name := range c.Logins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (l Login) validate() error {
+	if l.URL == "" {
+		return errors.New("url is required")
+	}
+	if l.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	for i, f := range l.Fields {
+		if f.Selector == "" {
+			return fmt.Errorf("field %d: selector is required", i+1)
+		}
+		sources := 0
+		for _, set := range []bool{f.Value != "", f.Env != "", f.Command != ""} {
+			if set {
+				sources++
+			}
+		}
+		if sources > 1 {
+			return fmt.Errorf("field %s: use one of value, env, command", f.Selector)
+		}
+	}
+	return nil
+}
+
+// BookmarkNames returns the defined bookmark names, sorted.
+func (c Config) BookmarkNames() []string {
+	names := make([]string, 0, len(c.Bookmarks))
+	for name := range c.Bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c Config) presetList() string {
+	if len(c.Presets) == 0 {
+		return "no presets are defined"
+	}
+	return "available: " + strings.Join(c.PresetNames(), ", ")
+}
+
+func (c Config) validate() error {
+	for name, p := range c.Presets {
+		if name == "" || strings.Contains(name, ".") {
+			return fmt.Errorf("invalid preset name %q (must be non-empty, without dots)", name)
+		}
+		if p.Preset != "" || len(p.Presets) > 0 {
+			return fmt.Errorf("preset %q: presets cannot select or define other presets", name)
+		}
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("preset %q: %w", name, err)
+		}
+	}
+	for name, url := range c.Bookmarks {
+		if err := ValidateBookmarkName(name); err != nil {
+			return err
+		}
+		if url == "" {
+			return fmt.Errorf("bookmark %q has no URL", name)
+		}
+	}
+	for name, l := range c.Logins {
+		if err := l.validate(); err != nil {
+			return fmt.Errorf("login %q: %w", name, err)
+		}
+	}
+	if c.Output != "" && !slices.Contains(outputs, c.Output) {
+		return fmt.Errorf("invalid output %q (use %s)", c.Output, strings.Join(outputs, ", "))
+	}
+	if c.BufferSize < 0 {
+		return fmt.Errorf("invalid buffer-size %d (must be positive)", c.BufferSize)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("invalid timeout %v (must be positive)", c.Timeout)
+	}
+	return nil
+}
+
+// Get returns the value of key and whether it is set.
+func (c Config) Get(key string) (string, bool, error) {
+	switch key {
+	case "headless":
+		if c.Headless == nil {
+			return "", false, nil
+		}
+		return strconv.FormatBool(*c.Headless), true, nil
+	case "browser":
+		return c.Browser, c.Browser != "", nil
+	case "output":
+		return c.Output, c.Output != "", nil
+	case "buffer-size":
+		return strconv.Itoa(c.BufferSize), c.BufferSize != 0, nil
+	case "timeout":
+		return c.Timeout.String(), c.Timeout != 0, nil
+	case "viewport":
+		return c.Viewport, c.Viewport != "", nil
+	case "user-agent":
+		return c.UserAgent, c.UserAgent != "", nil
+	case "throttle":
+		return c.Throttle, c.Throttle != "", nil
+	case "preset":
+		return c.Preset, c.Preset != "", nil
+	}
+	if name, rest, ok := splitPresetKey(key); ok {
+		p, ok := c.Presets[name]
+		if !ok {
+			return "", false, nil
+		}
+		return p.Get(rest)
+	}
+	if name, ok := strings.CutPrefix(key, bookmarksPrefix); ok {
+		url, ok := c.Bookmarks[name]
+		return url, ok, nil
+	}
+	cmd, flag, err := SplitDefaultsKey(key)
+	if err != nil {
+		return "", false, err
+	}
+	v, ok := c.Defaults[cmd][flag]
+	return v, ok, nil
+}
+
+// Set parses value and assigns it to key.
+func (c *Config) Set(key, value string) error {
+	switch key {
+	case "headless":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid headless %q (use true or false)", value)
+		}
+		c.Headless = &b
+	case "browser":
+		c.Browser = value
+	case "output":
+		c.Output = value
+	case "buffer-size":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid buffer-size %q (must be an integer)", value)
+		}
+		c.BufferSize = n
+	case "timeout":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q (use a duration such as 30s)", value)
+		}
+		c.Timeout = d
+	case "viewport":
+		c.Viewport = value
+	case "user-agent":
+		c.UserAgent = value
+	case "throttle":
+		c.Throttle = value
+	case "preset":
+		c.Preset = value
+	default:
+		if name, rest, ok := splitPresetKey(key); ok {
+			p := c.Presets[name]
+			if err := p.Set(rest, value); err != nil {
+				return fmt.Errorf("preset %q: %w", name, err)
+			}
+			if c.Presets == nil {
+				c.Presets = make(map[string]Config)
+			}
+			c.Presets[name] = p
+			break
+		}
+		if name, ok := strings.CutPrefix(key, bookmarksPrefix); ok {
+			if c.Bookmarks == nil {
+				c.Bookmarks = make(map[string]string)
+			}
+			c.Bookmarks[name] = value
+			break
+		}
+		cmd, flag, err := SplitDefaultsKey(key)
+		if err != nil {
+			return err
+		}
+		if c.Defaults == nil {
+			c.Defaults = make(map[string]map[string]string)
+		}
+		if c.Defaults[cmd] == nil {
+			c.Defaults[cmd] = make(map[string]string)
+		}
+		c.Defaults[cmd][flag] = value
+	}
+	return c.validate()
+}
+
+// Unset clears key.
+func (c *Config) Unset(key string) error {
+	switch key {
+	case "headless":
+		c.Headless = nil
+	case "browser":
+		c.Browser = ""
+	case "output":
+		c.Output = ""
+	case "buffer-size":
+		c.BufferSize = 0
+	case "timeout":
+		c.Timeout = 0
+	case "viewport":
+		c.Viewport = ""
+	case "user-agent":
+		c.UserAgent = ""
+	case "throttle":
+		c.Throttle = ""
+	case "preset":
+		c.Preset = ""
+	default:
+		if name, rest, ok := splitPresetKey(key); ok {
+			p, ok := c.Presets[name]
+			if !ok {
+				return nil
+			}
+			if err := p.Unset(rest); err != nil {
+				return err
+			}
+			c.Presets[name] = p
+			if reflect.ValueOf(p).IsZero() {
+				delete(c.Presets, name)
+			}
+			return nil
+		}
+		if name, ok := strings.CutPrefix(key, bookmarksPrefix); ok {
+			delete(c.Bookmarks, name)
+			if len(c.Bookmarks) == 0 {
+				c.Bookmarks = nil
+			}
+			return nil
+		}
+		cmd, flag, err := SplitDefaultsKey(key)
+		if err != nil {
+			return err
+		}
+		delete(c.Defaults[cmd], flag)
+		if len(c.Defaults[cmd]) == 0 {
+			delete(c.Defaults, cmd)
+		}
+	}
+	return nil
+}
+
+// List returns every set key in display order, per-command defaults last and
+// sorted.
+func (c Config) List() []string {
+	var keys []string
+	for _, k := range Keys {
+		if _, ok, _ := c.Get(k); ok {
+			keys = append(keys, k)
+		}
+	}
+	var defaults []string
+	for cmd, flags := range c.Defaults {
+		for flag := range flags {
+			defaults = append(defaults, defaultsPrefix+cmd+"."+flag)
+		}
+	}
+	sort.Strings(defaults)
+	keys = append(keys, defaults...)
+	for _, name := range c.PresetNames() {
+		for _, k := range c.Presets[name].List() {
+			keys = append(keys, presetsPrefix+name+"."+k)
+		}
+	}
+	for _, name := range c.BookmarkNames() {
+		keys = append(keys, bookmarksPrefix+name)
+	}
+	return keys
+}
+
+// ValidateBookmarkName reports whether name can name a bookmark: a single
+// word such as "admin" or "staging-dash", usable as a config key.
+func ValidateBookmarkName(name string) error {
+	if name == "" || strings.ContainsAny(name, ". \t/:") {
+		return fmt.Errorf("invalid bookmark name %q (use a word like admin or staging-dash, without dots, slashes, colons, or spaces)", name)
+	}
+	return nil
+}
+
+// splitPresetKey splits presets.<name>.<key> into the preset name and the key
+// within it.
+func splitPresetKey(key string) (name, rest string, ok bool) {
+	rest, ok = strings.CutPrefix(key, presetsPrefix)
+	if !ok {
+		return "", "", false
+	}
+	name, rest, ok = strings.Cut(rest, ".")
+	return name, rest, ok && name != "" && rest != ""
+}
+
+// SplitDefaultsKey splits defaults.<command>.<flag> into its command path and
+// flag name. The command path may contain spaces for subcommands.
+func SplitDefaultsKey(key string) (cmd, flag string, err error) {
+	rest, ok := strings.CutPrefix(key, defaultsPrefix)
+	i := strings.LastIndex(rest, ".")
+	if !ok || i <= 0 || i == len(rest)-1 {
+		return "", "", fmt.Errorf("unknown key %q (use %s, defaults.<command>.<flag>, presets.<name>.<key>, or bookmarks.<name>)", key, strings.Join(Keys, ", "))
+	}
+	return rest[:i], strings.TrimPrefix(rest[i+1:], "--"), nil
+}
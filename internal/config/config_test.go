@@ -0,0 +1,315 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadFile_Missing(t *testing.T) {
+	c, err := LoadFile(filepath.Join(t.TempDir(), "absent.yaml"))
+	if err != nil {
+		t.Fatalf("missing file should not be an error: %v", err)
+	}
+	if !reflect.DeepEqual(c, Config{}) {
+		t.Errorf("expected empty config, got %+v", c)
+	}
+}
+
+func TestLoadFile_Parses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `headless: true
+timeout: 45s
+buffer-size: 500
+defaults:
+  navigate:
+    wait: true
+  history go:
+    timeout: 10
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Headless == nil || !*c.Headless || c.Timeout != 45*time.Second || c.BufferSize != 500 {
+		t.Errorf("unexpected config: %+v", c)
+	}
+	if c.Defaults["navigate"]["wait"] != "true" || c.Defaults["history go"]["timeout"] != "10" {
+		t.Errorf("unexpected defaults: %+v", c.Defaults)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("output: xml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("expected error naming the file, got %v", err)
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+	var c Config
+	for k, v := range map[string]string{
+		"headless":               "false",
+		"timeout":                "1m30s",
+		"output":                 "json",
+		"defaults.navigate.wait": "true",
+	} {
+		if err := c.Set(k, v); err != nil {
+			t.Fatalf("Set(%s): %v", k, err)
+		}
+	}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, c)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	var user, project Config
+	_ = user.Set("headless", "true")
+	_ = user.Set("timeout", "30s")
+	_ = user.Set("defaults.navigate.wait", "true")
+	_ = user.Set("defaults.navigate.timeout", "10")
+	_ = project.Set("timeout", "5s")
+	_ = project.Set("defaults.navigate.timeout", "20")
+
+	m := user.Merge(project)
+	if m.Headless == nil || !*m.Headless || m.Timeout != 5*time.Second {
+		t.Errorf("unexpected merge: %+v", m)
+	}
+	want := map[string]string{"wait": "true", "timeout": "20"}
+	if !reflect.DeepEqual(m.Defaults["navigate"], want) {
+		t.Errorf("defaults: got %v, want %v", m.Defaults["navigate"], want)
+	}
+	if user.Defaults["navigate"]["timeout"] != "10" {
+		t.Error("Merge must not modify the receiver's defaults")
+	}
+}
+
+func TestGetSetUnset(t *testing.T) {
+	var c Config
+	if err := c.Set("defaults.history go.wait", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok, err := c.Get("defaults.history go.wait"); err != nil || !ok || v != "true" {
+		t.Errorf("Get: %q %v %v", v, ok, err)
+	}
+	if got := c.List(); !reflect.DeepEqual(got, []string{"defaults.history go.wait"}) {
+		t.Errorf("List: %v", got)
+	}
+	if err := c.Unset("defaults.history go.wait"); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Defaults) != 0 {
+		t.Errorf("expected empty defaults after unset, got %v", c.Defaults)
+	}
+
+	for _, tc := range []struct{ key, value string }{
+		{"headless", "maybe"},
+		{"timeout", "soon"},
+		{"buffer-size", "-1"},
+		{"colour", "red"},
+		{"defaults.navigate", "true"},
+	} {
+		if err := c.Set(tc.key, tc.value); err == nil {
+			t.Errorf("Set(%q, %q): expected error", tc.key, tc.value)
+		}
+	}
+}
+
+func TestFindProject(t *testing.T) {
+	root := t.TempDir()
+	deep := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(deep, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindProject(deep); got != "" && strings.HasPrefix(got, root) {
+		t.Errorf("expected no project file under %s, got %q", root, got)
+	}
+
+	want := filepath.Join(root, "a", ProjectFile)
+	if err := os.WriteFile(want, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindProject(deep); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestUserPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if got := UserPath(); got != "/xdg/webctl/config.yaml" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestPresets(t *testing.T) {
+	var c Config
+	_ = c.Set("timeout", "30s")
+	_ = c.Set("headless", "false")
+	for k, v := range map[string]string{
+		"presets.mobile-ci.viewport":               "390x844",
+		"presets.mobile-ci.headless":               "true",
+		"presets.mobile-ci.throttle":               "slow-4g",
+		"presets.mobile-ci.defaults.navigate.wait": "true",
+		"presets.local-dev.timeout":                "5s",
+	} {
+		if err := c.Set(k, v); err != nil {
+			t.Fatalf("Set(%s): %v", k, err)
+		}
+	}
+
+	if v, ok, _ := c.Get("presets.mobile-ci.viewport"); !ok || v != "390x844" {
+		t.Errorf("Get preset key: %q %v", v, ok)
+	}
+	wantKeys := []string{
+		"headless", "timeout",
+		"presets.local-dev.timeout",
+		"presets.mobile-ci.headless", "presets.mobile-ci.viewport", "presets.mobile-ci.throttle",
+		"presets.mobile-ci.defaults.navigate.wait",
+	}
+	if got := c.List(); !reflect.DeepEqual(got, wantKeys) {
+		t.Errorf("List:\n got %v\nwant %v", got, wantKeys)
+	}
+
+	m, err := c.WithPreset("mobile-ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !*m.Headless || m.Viewport != "390x844" || m.Timeout != 30*time.Second || m.Defaults["navigate"]["wait"] != "true" {
+		t.Errorf("unexpected preset merge: %+v", m)
+	}
+
+	if _, err := c.WithPreset("nope"); err == nil || !strings.Contains(err.Error(), "local-dev, mobile-ci") {
+		t.Errorf("expected unknown preset error listing presets, got %v", err)
+	}
+
+	if err := c.Unset("presets.local-dev.timeout"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Presets["local-dev"]; ok {
+		t.Error("empty preset should be removed")
+	}
+}
+
+func TestPresets_Invalid(t *testing.T) {
+	var c Config
+	if err := c.Set("presets.ci.preset", "other"); err == nil {
+		t.Error("a preset must not select another preset")
+	}
+	if err := c.Set("presets.ci.output", "xml"); err == nil || !strings.Contains(err.Error(), `preset "ci"`) {
+		t.Errorf("expected preset validation error, got %v", err)
+	}
+}
+
+func TestMerge_Presets(t *testing.T) {
+	var user, project Config
+	_ = user.Set("presets.ci.headless", "true")
+	_ = user.Set("presets.ci.timeout", "30s")
+	_ = project.Set("presets.ci.timeout", "90s")
+
+	m := user.Merge(project)
+	if p := m.Presets["ci"]; p.Headless == nil || !*p.Headless || p.Timeout != 90*time.Second {
+		t.Errorf("presets should merge key by key, got %+v", p)
+	}
+}
+
+func TestBookmarks(t *testing.T) {
+	var user, project Config
+	_ = user.Set("bookmarks.admin", "http://localhost:3000/admin")
+	_ = user.Set("bookmarks.dash", "https://staging.example.com/dash")
+	_ = project.Set("bookmarks.admin", "http://localhost:4000/admin")
+
+	m := user.Merge(project)
+	want := map[string]string{"admin": "http://localhost:4000/admin", "dash": "https://staging.example.com/dash"}
+	if !reflect.DeepEqual(m.Bookmarks, want) {
+		t.Errorf("bookmarks: got %v, want %v", m.Bookmarks, want)
+	}
+	if got := m.List(); !reflect.DeepEqual(got, []string{"bookmarks.admin", "bookmarks.dash"}) {
+		t.Errorf("List: %v", got)
+	}
+	if v, ok, err := m.Get("bookmarks.dash"); err != nil || !ok || v != want["dash"] {
+		t.Errorf("Get: %q %v %v", v, ok, err)
+	}
+
+	if err := user.Unset("bookmarks.admin"); err != nil {
+		t.Fatal(err)
+	}
+	_ = user.Unset("bookmarks.dash")
+	if user.Bookmarks != nil {
+		t.Errorf("expected no bookmarks after unset, got %v", user.Bookmarks)
+	}
+
+	for _, tc := range []struct{ key, value string }{
+		{"bookmarks.", "https://example.com"},
+		{"bookmarks.a/b", "https://example.com"},
+		{"bookmarks.admin", ""},
+	} {
+		var c Config
+		if err := c.Set(tc.key, tc.value); err == nil {
+			t.Errorf("Set(%q, %q): expected error", tc.key, tc.value)
+		}
+	}
+}
+
+func TestLogins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `logins:
+  staging:
+    url: https://staging.example.com/login
+    fields:
+      - selector: "#email"
+        value: admin@example.com
+      - selector: "#password"
+        command: pass show staging
+    submit: button[type=submit]
+    wait: "#dashboard"
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := c.Logins["staging"]
+	if l.URL != "https://staging.example.com/login" || len(l.Fields) != 2 || l.Fields[1].Command != "pass show staging" || l.Submit != "button[type=submit]" {
+		t.Errorf("unexpected login: %+v", l)
+	}
+
+	project := Config{Logins: map[string]Login{"local": {URL: "http://localhost:3000/login"}}}
+	if got := c.Merge(project).LoginNames(); !reflect.DeepEqual(got, []string{"local", "staging"}) {
+		t.Errorf("LoginNames after merge: %v", got)
+	}
+
+	for _, bad := range []string{
+		"logins:\n  x:\n    fields: [{selector: '#a'}]\n",
+		"logins:\n  x:\n    url: https://a.test/\n    fields: [{value: a}]\n",
+		"logins:\n  x:\n    url: https://a.test/\n    fields: [{selector: '#a', value: a, env: A}]\n",
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), `login "x"`) {
+			t.Errorf("LoadFile(%q) error = %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package daemon
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// heldRequest is a request paused by an abort rule or a mock's delay. A
+// value on decide ends the hold early: true aborts the request, false lets
+// it proceed.
+type heldRequest struct {
+	info      ipc.HeldRequest
+	sessionID string
+	fetchID   string
+	mock      bool // held by a mock's delay rather than an abort rule
+	decide    chan bool
+}
+
+// handleAbort adds or lists abort rules, aborts a held request, or clears
+// the rules.
+func (d *Daemon) handleAbort(req ipc.Request) ipc.Response {
+	var params ipc.AbortParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid abort parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "add":
+		return d.addAbort(params)
+	case "request":
+		h, ok := d.takeHeld(params.RequestID)
+		if !ok {
+			return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no held request %q; only requests held by an abort rule or a mock's delay can be aborted by ID", params.RequestID))
+		}
+		h.decide <- true
+		d.log.Info("held request aborted", "requestId", params.RequestID, "url", h.info.URL)
+		return ipc.SuccessResponse(ipc.AbortData{Rules: []ipc.AbortRule{}, Held: []ipc.HeldRequest{h.info}})
+	case "list", "":
+		rules, held := d.abortList()
+		return ipc.SuccessResponse(ipc.AbortData{Rules: rules, Held: held})
+	case "clear":
+		rules, _ := d.abortList()
+		d.abortsMu.Lock()
+		d.aborts = nil
+		d.abortsMu.Unlock()
+		released := d.releaseHeld("", true)
+		d.log.Info("abort rules cleared", "count", len(rules), "released", len(released))
+		return d.syncInterception(ipc.AbortData{Rules: rules, Held: released})
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown abort action: %s", params.Action))
+	}
+}
+
+// addAbort validates an abort rule and starts intercepting its URL in every
+// tab.
+func (d *Daemon) addAbort(params ipc.AbortParams) ipc.Response {
+	switch {
+	case params.URL == "":
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "url is required")
+	case params.Delay < 0:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "delay must not be negative")
+	case params.Hold && params.Delay > 0:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "use a delay or hold, not both")
+	case params.Times < 0:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "times must not be negative")
+	}
+
+	rule := &ipc.AbortRule{
+		URL:     interceptPattern(params.URL),
+		Delay:   params.Delay,
+		Hold:    params.Hold,
+		Times:   params.Times,
+		Created: time.Now().UnixMilli(),
+	}
+	d.abortsMu.Lock()
+	d.abortSeq++
+	rule.ID = "a" + strconv.Itoa(d.abortSeq)
+	d.aborts = append(d.aborts, rule)
+	info := *rule
+	d.abortsMu.Unlock()
+
+	d.log.Info("abort rule added", "id", info.ID, "url", info.URL, "delayMs", info.Delay, "hold", info.Hold, "times", info.Times)
+	return d.syncInterception(ipc.AbortData{Rules: []ipc.AbortRule{info}, Held: []ipc.HeldRequest{}})
+}
+
+// abortList returns every abort rule in the order they were added and every
+// held request, oldest first.
+func (d *Daemon) abortList() ([]ipc.AbortRule, []ipc.HeldRequest) {
+	d.abortsMu.Lock()
+	defer d.abortsMu.Unlock()
+	rules := make([]ipc.AbortRule, 0, len(d.aborts))
+	for _, r := range d.aborts {
+		rules = append(rules, *r)
+	}
+	held := make([]ipc.HeldRequest, 0, len(d.held))
+	for _, h := range d.held {
+		held = append(held, h.info)
+	}
+	slices.SortFunc(held, func(a, b ipc.HeldRequest) int { return cmp.Compare(a.Since, b.Since) })
+	return rules, held
+}
+
+// matchAbort counts a request against the first abort rule matching url. A
+// rule that has aborted its number of requests is removed, and spent is
+// true so the caller can stop intercepting its URL.
+func (d *Daemon) matchAbort(url string) (rule ipc.AbortRule, spent, ok bool) {
+	d.abortsMu.Lock()
+	defer d.abortsMu.Unlock()
+	for i, r := range d.aborts {
+		if !interceptURLMatch(r.URL, url) {
+			continue
+		}
+		r.Hits++
+		if r.Times > 0 && r.Hits >= r.Times {
+			d.aborts = append(d.aborts[:i:i], d.aborts[i+1:]...)
+			spent = true
+		}
+		return *r, spent, true
+	}
+	return ipc.AbortRule{}, false, false
+}
+
+// abortRequest fails, or after a hold maybe continues, a request paused at
+// the request stage by an abort rule. It runs off the read loop.
+func (d *Daemon) abortRequest(sessionID, fetchID, networkID, url string, rule ipc.AbortRule, spent bool) {
+	go func() {
+		abort := true
+		if rule.Hold || rule.Delay > 0 {
+			h := d.holdRequest(sessionID, fetchID, networkID, url, rule.ID, false)
+			if decision, decided := d.awaitHeld(h, time.Duration(rule.Delay)*time.Millisecond); decided {
+				abort = decision
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		method, body := "Fetch.continueRequest", map[string]any{"requestId": fetchID}
+		if abort {
+			method, body["errorReason"] = "Fetch.failRequest", "Aborted"
+		}
+		if _, err := d.cdp.SendToSession(ctx, sessionID, method, body); err != nil {
+			d.log.Debug(method+" failed", "requestId", fetchID, "error", err)
+		} else {
+			d.log.Debug("abort rule applied", "id", rule.ID, "url", url, "method", method)
+		}
+
+		// A spent rule's URL is no longer intercepted
+		if spent {
+			if resp := d.syncInterception(nil); !resp.OK {
+				d.log.Debug("failed to stop intercepting", "id", rule.ID, "error", resp.Error)
+			}
+		}
+	}()
+}
+
+// holdRequest records a paused request so 'network abort <requestId>' can
+// end its hold.
+func (d *Daemon) holdRequest(sessionID, fetchID, networkID, url, by string, mock bool) *heldRequest {
+	h := &heldRequest{
+		info: ipc.HeldRequest{
+			RequestID: networkID,
+			URL:       url,
+			By:        by,
+			Since:     time.Now().UnixMilli(),
+		},
+		sessionID: sessionID,
+		fetchID:   fetchID,
+		mock:      mock,
+		decide:    make(chan bool, 1),
+	}
+	d.abortsMu.Lock()
+	if d.held == nil {
+		d.held = make(map[string]*heldRequest)
+	}
+	d.held[networkID] = h
+	d.abortsMu.Unlock()
+	return h
+}
+
+// awaitHeld waits out a held request's delay, or with no delay until a
+// decision reaches it. decided reports whether one did, and abort what it
+// was.
+func (d *Daemon) awaitHeld(h *heldRequest, delay time.Duration) (abort, decided bool) {
+	var timeout <-chan time.Time
+	if delay > 0 {
+		timeout = time.After(delay)
+	}
+	select {
+	case <-timeout:
+		if d.unhold(h) {
+			return false, false
+		}
+		// A decision was taken as the delay ran out and is on its way
+		return <-h.decide, true
+	case abort = <-h.decide:
+		return abort, true
+	}
+}
+
+// unhold removes a held request whose delay ran out, and reports false when
+// a decision took it first.
+func (d *Daemon) unhold(h *heldRequest) bool {
+	d.abortsMu.Lock()
+	defer d.abortsMu.Unlock()
+	if d.held[h.info.RequestID] != h {
+		return false
+	}
+	delete(d.held, h.info.RequestID)
+	return true
+}
+
+// takeHeld removes a held request so only one decision reaches it.
+func (d *Daemon) takeHeld(networkID string) (*heldRequest, bool) {
+	d.abortsMu.Lock()
+	defer d.abortsMu.Unlock()
+	h, ok := d.held[networkID]
+	if ok {
+		delete(d.held, networkID)
+	}
+	return h, ok
+}
+
+// releaseHeld lets held requests in a session, or in every session when
+// sessionID is empty, proceed. With rulesOnly, requests held by a mock's
+// delay are left to it.
+func (d *Daemon) releaseHeld(sessionID string, rulesOnly bool) []ipc.HeldRequest {
+	d.abortsMu.Lock()
+	defer d.abortsMu.Unlock()
+	released := []ipc.HeldRequest{}
+	for id, h := range d.held {
+		if (sessionID != "" && h.sessionID != sessionID) || (rulesOnly && h.mock) {
+			continue
+		}
+		delete(d.held, id)
+		h.decide <- false
+		released = append(released, h.info)
+	}
+	return released
+}
@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func abortCall(t *testing.T, d *Daemon, params ipc.AbortParams) (ipc.Response, ipc.AbortData) {
+	t.Helper()
+	raw, _ := json.Marshal(params)
+	resp := d.handleAbort(ipc.Request{Cmd: "abort", Params: raw})
+	var data ipc.AbortData
+	if resp.OK {
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp, data
+}
+
+func TestHandleAbort_Rules(t *testing.T) {
+	d := New(DefaultConfig())
+
+	for _, params := range []ipc.AbortParams{
+		{Action: "add"},
+		{Action: "add", URL: "/poll", Delay: -1},
+		{Action: "add", URL: "/poll", Delay: 100, Hold: true},
+		{Action: "add", URL: "/poll", Times: -1},
+	} {
+		if resp, _ := abortCall(t, d, params); resp.OK || resp.Code != ipc.CodeInvalidArgs {
+			t.Errorf("%+v: response = %+v, want %s", params, resp, ipc.CodeInvalidArgs)
+		}
+	}
+
+	_, data := abortCall(t, d, ipc.AbortParams{Action: "add", URL: "/upload", Times: 2})
+	if len(data.Rules) != 1 || data.Rules[0].ID != "a1" || data.Rules[0].URL != "*/upload*" {
+		t.Fatalf("add = %+v", data)
+	}
+	abortCall(t, d, ipc.AbortParams{Action: "add", URL: "*/poll*", Hold: true, Times: 0})
+	if patterns := d.interceptPatterns(); len(patterns) != 2 || patterns[0]["requestStage"] != "Request" {
+		t.Errorf("patterns = %v", patterns)
+	}
+
+	// A rule is removed once it has aborted its requests
+	for i, spentWant := range []bool{false, true} {
+		rule, spent, ok := d.matchAbort("https://app.test/upload")
+		if !ok || rule.ID != "a1" || rule.Hits != i+1 || spent != spentWant {
+			t.Errorf("match %d = %+v spent=%v ok=%v", i+1, rule, spent, ok)
+		}
+	}
+	if rule, _, _ := d.matchAbort("https://app.test/upload"); rule.ID != "" {
+		t.Errorf("spent rule still matched: %+v", rule)
+	}
+	if _, _, ok := d.matchAbort("https://app.test/index.html"); ok {
+		t.Error("unmatched URL matched a rule")
+	}
+
+	_, data = abortCall(t, d, ipc.AbortParams{Action: "clear"})
+	if len(data.Rules) != 1 || data.Rules[0].ID != "a2" || d.interceptPatterns() != nil {
+		t.Errorf("clear = %+v, patterns = %v", data, d.interceptPatterns())
+	}
+}
+
+func TestHandleAbort_HeldRequest(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://app.test/", "")
+
+	abortCall(t, d, ipc.AbortParams{Action: "add", URL: "*/poll*", Hold: true, Times: 0})
+	for _, id := range []string{"1000.1", "1000.2"} {
+		d.handleRequestPaused(cdp.Event{
+			SessionID: "AAA1",
+			Params:    json.RawMessage(`{"requestId":"interception-` + id + `","networkId":"` + id + `","request":{"url":"https://app.test/poll"}}`),
+		})
+	}
+
+	var held []ipc.HeldRequest
+	deadline := time.Now().Add(2 * time.Second)
+	for len(held) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		_, data := abortCall(t, d, ipc.AbortParams{Action: "list"})
+		held = data.Held
+	}
+	if len(held) != 2 || held[0].By != "a1" {
+		t.Fatalf("held = %+v", held)
+	}
+
+	if resp, _ := abortCall(t, d, ipc.AbortParams{Action: "request", RequestID: "9.9"}); resp.OK || resp.Code != ipc.CodeNotFound {
+		t.Errorf("unknown request = %+v, want %s", resp, ipc.CodeNotFound)
+	}
+	_, data := abortCall(t, d, ipc.AbortParams{Action: "request", RequestID: "1000.1"})
+	if len(data.Held) != 1 || data.Held[0].URL != "https://app.test/poll" {
+		t.Errorf("abort = %+v", data)
+	}
+	// Clearing the rules lets the other request proceed
+	_, data = abortCall(t, d, ipc.AbortParams{Action: "clear"})
+	if len(data.Held) != 1 || data.Held[0].RequestID != "1000.2" {
+		t.Errorf("clear released %+v", data.Held)
+	}
+
+	settled := map[string]string{}
+	for time.Now().Before(deadline) && len(settled) < 2 {
+		time.Sleep(5 * time.Millisecond)
+		for _, req := range conn.getCapturedRequests() {
+			if !strings.HasPrefix(req.Method, "Fetch.") || req.Method == "Fetch.enable" || req.Method == "Fetch.disable" {
+				continue
+			}
+			params, _ := req.Params.(map[string]any)
+			id, _ := params["requestId"].(string)
+			settled[id] = req.Method
+			if reason, _ := params["errorReason"].(string); req.Method == "Fetch.failRequest" && reason != "Aborted" {
+				t.Errorf("errorReason = %q", reason)
+			}
+		}
+	}
+	if settled["interception-1000.1"] != "Fetch.failRequest" || settled["interception-1000.2"] != "Fetch.continueRequest" {
+		t.Errorf("settled = %v", settled)
+	}
+}
+
+func TestAwaitHeld_Delay(t *testing.T) {
+	d := New(DefaultConfig())
+
+	h := d.holdRequest("AAA1", "f1", "1.1", "https://app.test/poll", "k1", true)
+	if abort, decided := d.awaitHeld(h, time.Millisecond); abort || decided {
+		t.Errorf("awaitHeld = %v, %v, want the delay to run out", abort, decided)
+	}
+	if _, held := d.abortList(); len(held) != 0 {
+		t.Errorf("held after delay = %+v", held)
+	}
+
+	// Rules only: a mock's hold is left to the mock
+	h = d.holdRequest("AAA1", "f2", "1.2", "https://app.test/poll", "k1", true)
+	if released := d.releaseHeld("", true); len(released) != 0 {
+		t.Errorf("released %+v", released)
+	}
+	if released := d.releaseHeld("AAA1", false); len(released) != 1 {
+		t.Errorf("released %+v on detach", released)
+	}
+	if abort, decided := d.awaitHeld(h, time.Hour); abort || !decided {
+		t.Errorf("awaitHeld = %v, %v, want released", abort, decided)
+	}
+}
@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// annotationsID is the id of the element holding screenshot annotations.
+const annotationsID = "__webctl-annotations"
+
+// annotateJS draws a labelled box around every element matching each of the
+// selectors passed as its argument, in a layer above the page. It returns
+// {missing} naming the first selector that matched nothing, in which case
+// nothing is drawn. Boxes are placed in document coordinates so full-page
+// captures line up, and each selector gets its own colour. The label is the
+// selector, with the match number when it matched more than one element.
+const annotateJS = `function(selectors) {
+	const colors = ['#e6194b', '#3cb44b', '#4363d8', '#f58231', '#911eb4', '#008080', '#f032e6', '#9a6324'];
+	for (const sel of selectors) {
+		if (document.querySelectorAll(sel).length === 0) {
+			return {missing: sel};
+		}
+	}
+	document.getElementById('` + annotationsID + `')?.remove();
+	const layer = document.createElement('div');
+	layer.id = '` + annotationsID + `';
+	layer.style.cssText = 'position:absolute;left:0;top:0;width:0;height:0;overflow:visible;z-index:2147483647;pointer-events:none;';
+	selectors.forEach((sel, i) => {
+		const color = colors[i % colors.length];
+		const els = document.querySelectorAll(sel);
+		els.forEach((el, n) => {
+			const r = el.getBoundingClientRect();
+			const x = r.left + window.scrollX;
+			const y = r.top + window.scrollY;
+			const box = document.createElement('div');
+			box.style.cssText = 'position:absolute;box-sizing:border-box;border:2px solid ' + color + ';' +
+				'left:' + (x - 2) + 'px;top:' + (y - 2) + 'px;width:' + (r.width + 4) + 'px;height:' + (r.height + 4) + 'px;';
+			const label = document.createElement('div');
+			label.textContent = els.length > 1 ? sel + ' [' + (n + 1) + ']' : sel;
+			label.style.cssText = 'position:absolute;left:-2px;white-space:nowrap;padding:1px 4px;' +
+				'font:bold 12px/16px sans-serif;color:#fff;background:' + color + ';' +
+				(y >= 20 ? 'bottom:100%;' : 'top:100%;');
+			box.appendChild(label);
+			layer.appendChild(box);
+		});
+	});
+	document.documentElement.appendChild(layer);
+	return {missing: null};
+}`
+
+// annotate draws boxes around the elements the selectors match, for a
+// screenshot. A selector that matches nothing is an error, and nothing is
+// drawn.
+func (d *Daemon) annotate(sessionID string, selectors []string) error {
+	arg, err := json.Marshal(selectors)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Missing string `json:"missing"`
+	}
+	if _, err := d.evalElementQuery(sessionID, "("+annotateJS+")("+string(arg)+")", &result); err != nil {
+		return err
+	}
+	if result.Missing != "" {
+		return fmt.Errorf("selector '%s' matched no elements", result.Missing)
+	}
+	return nil
+}
+
+// removeAnnotations removes the boxes drawn by annotate.
+func (d *Daemon) removeAnnotations(sessionID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+		"expression": "document.getElementById('" + annotationsID + "')?.remove()",
+	}); err != nil {
+		d.log.Debug("failed to remove screenshot annotations", "error", err)
+	}
+}
@@ -0,0 +1,229 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// bannersAutoWatch is how long after DOMContentLoaded automatic dismissal
+// keeps watching for overlays that consent scripts inject late.
+const bannersAutoWatch = 10 * time.Second
+
+// bannersJS applies banner rules to the page: for each rule that applies to
+// the site, it clicks the first visible button and hides the overlay
+// elements, then undoes the scroll lock overlays put on the page. Format
+// args: rules JSON, auto flag, watch milliseconds.
+//
+// Run once, it returns what it did. As an automatic new-document script it
+// runs at DOMContentLoaded and again on DOM changes for the watch time.
+const bannersJS = `(() => {
+	const rules = %s;
+	const auto = %t;
+	const watch = %d;
+
+	const host = location.hostname;
+	const applies = (r) => !r.hosts || !r.hosts.length ||
+		r.hosts.some((h) => host === h || host.endsWith('.' + h));
+	const query = (sel) => {
+		try {
+			return Array.from(document.querySelectorAll(sel));
+		} catch (e) {
+			return [];
+		}
+	};
+	const visible = (el) => {
+		const r = el.getBoundingClientRect();
+		const s = getComputedStyle(el);
+		return r.width > 0 && r.height > 0 && s.visibility !== 'hidden' && s.display !== 'none';
+	};
+
+	const run = () => {
+		const hits = [];
+		for (const r of rules) {
+			if (!applies(r)) {
+				continue;
+			}
+			for (const sel of r.click || []) {
+				const el = query(sel).find(visible);
+				if (el) {
+					el.click();
+					hits.push({rule: r.name, action: 'clicked', selector: sel});
+					break;
+				}
+			}
+			for (const sel of r.hide || []) {
+				for (const el of query(sel)) {
+					if (el.hasAttribute('data-webctl-hidden')) {
+						continue;
+					}
+					el.style.setProperty('display', 'none', 'important');
+					el.setAttribute('data-webctl-hidden', '');
+					hits.push({rule: r.name, action: 'hidden', selector: sel});
+				}
+			}
+		}
+		if (hits.length) {
+			for (const el of [document.documentElement, document.body]) {
+				if (el && getComputedStyle(el).overflowY === 'hidden') {
+					el.style.setProperty('overflow', 'auto', 'important');
+				}
+			}
+		}
+		return hits;
+	};
+
+	if (!auto) {
+		return run();
+	}
+	const start = () => {
+		run();
+		let pending = false;
+		const o = new MutationObserver(() => {
+			if (!pending) {
+				pending = true;
+				setTimeout(() => {
+					pending = false;
+					run();
+				}, 100);
+			}
+		});
+		o.observe(document.documentElement, {childList: true, subtree: true});
+		setTimeout(() => o.disconnect(), watch);
+	};
+	if (document.readyState === 'loading') {
+		document.addEventListener('DOMContentLoaded', start);
+	} else {
+		start();
+	}
+})()`
+
+// handleDismissBanners handles the "dismiss-banners" command: it applies the
+// rules to the active tab, or turns automatic dismissal on every load on or
+// off.
+func (d *Daemon) handleDismissBanners(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.DismissBannersParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid dismiss-banners parameters: %v", err))
+	}
+
+	if params.Auto != nil {
+		if err := d.setAutoBanners(*params.Auto, params.Rules); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to update automatic dismissal: %v", err))
+		}
+		if !*params.Auto {
+			return ipc.SuccessResponse(ipc.DismissBannersData{Dismissed: []ipc.BannerHit{}})
+		}
+	}
+	return d.dismissBanners(activeID, params.Rules)
+}
+
+// dismissBanners applies rules to the page in a session once.
+func (d *Daemon) dismissBanners(sessionID string, rules []ipc.BannerRule) ipc.Response {
+	js, err := bannersScript(rules, false)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+
+	data := ipc.DismissBannersData{Auto: d.autoBannerRules() != nil}
+	found, err := d.evalElementQuery(sessionID, js, &data.Dismissed)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to dismiss banners: %v", err))
+	}
+	if !found || data.Dismissed == nil {
+		data.Dismissed = []ipc.BannerHit{}
+	}
+	return ipc.SuccessResponse(data)
+}
+
+// bannersScript returns bannersJS for rules.
+func bannersScript(rules []ipc.BannerRule, auto bool) (string, error) {
+	if rules == nil {
+		rules = []ipc.BannerRule{}
+	}
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(bannersJS, raw, auto, bannersAutoWatch.Milliseconds()), nil
+}
+
+// autoBannerRules returns the rules applied on every load, or nil while
+// automatic dismissal is off.
+func (d *Daemon) autoBannerRules() []ipc.BannerRule {
+	d.bannersMu.Lock()
+	defer d.bannersMu.Unlock()
+	return d.bannerRules
+}
+
+// setAutoBanners turns automatic dismissal on, with rules, or off, in every
+// tab. Tabs opened later pick it up as they attach.
+func (d *Daemon) setAutoBanners(on bool, rules []ipc.BannerRule) error {
+	d.bannersMu.Lock()
+	if on {
+		if rules == nil {
+			rules = []ipc.BannerRule{}
+		}
+		d.bannerRules = rules
+	} else {
+		d.bannerRules = nil
+		rules = nil
+	}
+	d.bannersMu.Unlock()
+
+	for _, s := range d.sessions.All() {
+		if err := d.applyBannerScript(s.ID, rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyBannerScript replaces a session's automatic dismissal script with one
+// for rules, or removes it when rules is nil.
+func (d *Daemon) applyBannerScript(sessionID string, rules []ipc.BannerRule) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	scriptID := ""
+	if rules != nil {
+		js, err := bannersScript(rules, true)
+		if err != nil {
+			return err
+		}
+		result, err := d.sendToSession(ctx, sessionID, "Page.addScriptToEvaluateOnNewDocument", map[string]any{
+			"source": js,
+		})
+		if err != nil {
+			return err
+		}
+		var resp struct {
+			Identifier string `json:"identifier"`
+		}
+		if err := json.Unmarshal(result, &resp); err != nil {
+			return fmt.Errorf("failed to parse script identifier: %w", err)
+		}
+		scriptID = resp.Identifier
+	}
+	if previous := d.sessions.SwapBannerScript(sessionID, scriptID); previous != "" {
+		if _, err := d.sendToSession(ctx, sessionID, "Page.removeScriptToEvaluateOnNewDocument", map[string]any{
+			"identifier": previous,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestBannersScript(t *testing.T) {
+	js, err := bannersScript([]ipc.BannerRule{{Name: "cmp", Click: []string{"#accept"}}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`const rules = [{"name":"cmp","click":["#accept"]}];`, "const auto = true;", "const watch = 10000;"} {
+		if !strings.Contains(js, want) {
+			t.Errorf("script missing %q", want)
+		}
+	}
+
+	js, err = bannersScript(nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(js, "const rules = [];") {
+		t.Error("nil rules should be an empty list")
+	}
+}
+
+func TestSetAutoBanners(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+	d.sessions.Add("BBB2", "T2", "https://two.test/", "Two")
+
+	if err := d.setAutoBanners(true, []ipc.BannerRule{{Name: "cmp", Hide: []string{"#cmp"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if rules := d.autoBannerRules(); len(rules) != 1 {
+		t.Errorf("autoBannerRules() = %v", rules)
+	}
+	reqs := conn.getCapturedRequests()
+	if len(reqs) != 2 {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	for _, req := range reqs {
+		if req.Method != "Page.addScriptToEvaluateOnNewDocument" {
+			t.Errorf("method = %s", req.Method)
+		}
+	}
+
+	// Turning it off removes each tab's script
+	d.sessions.SwapBannerScript("AAA1", "3")
+	d.sessions.SwapBannerScript("BBB2", "4")
+	if err := d.setAutoBanners(false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if rules := d.autoBannerRules(); rules != nil {
+		t.Errorf("autoBannerRules() after off = %v", rules)
+	}
+	reqs = conn.getCapturedRequests()[2:]
+	if len(reqs) != 2 {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	removed := map[any]bool{}
+	for _, req := range reqs {
+		if req.Method != "Page.removeScriptToEvaluateOnNewDocument" {
+			t.Errorf("method = %s", req.Method)
+		}
+		params, _ := req.Params.(map[string]any)
+		removed[params["identifier"]] = true
+	}
+	if !removed["3"] || !removed["4"] {
+		t.Errorf("removed scripts = %v, want 3 and 4", removed)
+	}
+}
@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// benchMetricsJS reads the metrics of the page's last load from the
+// Performance APIs. Largest-contentful-paint and layout-shift entries are not
+// exposed by getEntriesByType, so a buffered observer collects them; its
+// takeRecords returns the buffered entries without waiting for a callback.
+//
+// CLS is the largest session window of shifts without recent input: shifts
+// less than 1s apart, spanning at most 5s.
+const benchMetricsJS = `(() => {
+	const nav = performance.getEntriesByType('navigation')[0];
+	if (!nav) {
+		throw new Error('no navigation timing for this page');
+	}
+	const take = (type) => {
+		if (typeof PerformanceObserver === 'undefined' ||
+			!(PerformanceObserver.supportedEntryTypes || []).includes(type)) {
+			return [];
+		}
+		const o = new PerformanceObserver(() => {});
+		o.observe({type, buffered: true});
+		const list = o.takeRecords();
+		o.disconnect();
+		return list;
+	};
+
+	const paint = performance.getEntriesByName('first-contentful-paint')[0];
+	const lcp = take('largest-contentful-paint').pop();
+
+	let cls = 0, session = 0, first = 0, last = 0;
+	for (const e of take('layout-shift')) {
+		if (e.hadRecentInput) {
+			continue;
+		}
+		if (session && e.startTime - last < 1000 && e.startTime - first < 5000) {
+			session += e.value;
+		} else {
+			session = e.value;
+			first = e.startTime;
+		}
+		last = e.startTime;
+		cls = Math.max(cls, session);
+	}
+
+	const resources = performance.getEntriesByType('resource');
+	return {
+		url: location.href,
+		metrics: {
+			ttfb: nav.responseStart,
+			fcp: paint ? paint.startTime : 0,
+			lcp: lcp ? lcp.startTime : 0,
+			domContentLoaded: nav.domContentLoadedEventEnd,
+			load: nav.loadEventEnd || nav.loadEventStart,
+			cls,
+			requests: resources.length + 1,
+			transferSize: resources.reduce((n, r) => n + (r.transferSize || 0), nav.transferSize || 0),
+			jsHeapUsed: performance.memory ? performance.memory.usedJSHeapSize : 0
+		}
+	};
+})()`
+
+// handleBench handles the "bench" command: it loads a URL in the active tab,
+// cold unless asked to keep the cache, and measures the load. The CLI runs it
+// repeatedly and aggregates the results.
+func (d *Daemon) handleBench(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.BenchParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid bench parameters: %v", err))
+	}
+	return d.bench(activeID, params)
+}
+
+// bench runs one measured load of params.URL in a session.
+func (d *Daemon) bench(sessionID string, params ipc.BenchParams) ipc.Response {
+	if params.URL == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "url is required")
+	}
+	if params.Settle < 0 {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "settle must not be negative")
+	}
+
+	if !params.KeepCache {
+		if err := d.ensureNetworkEnabled(sessionID); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err := d.sendToSession(ctx, sessionID, "Network.clearBrowserCache", nil)
+		cancel()
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to clear the cache: %v", err))
+		}
+	}
+
+	nav := d.navTracker.begin(sessionID)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	result, err := d.sendToSession(ctx, sessionID, "Page.navigate", map[string]any{
+		"url": params.URL,
+	})
+	if err != nil {
+		d.navTracker.abort(sessionID, nav)
+		return ipc.ErrorResponse(fmt.Sprintf("navigation failed: %v", err))
+	}
+	var navResp struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := json.Unmarshal(result, &navResp); err == nil && navResp.ErrorText != "" {
+		d.navTracker.abort(sessionID, nav)
+		return ipc.ErrorResponse(navResp.ErrorText)
+	}
+
+	timeout := cdp.DefaultTimeout
+	if params.Timeout > 0 {
+		timeout = time.Duration(params.Timeout) * time.Second
+	}
+	switch awaitMilestone(nav.Loaded(), nav.Cancelled(), timeout) {
+	case navCancelled:
+		return cancelledNavResponse(nav, sessionID)
+	case navTimedOut:
+		return ipc.CodedErrorResponse(ipc.CodeTimeout, "timeout waiting for page load")
+	}
+	time.Sleep(time.Duration(params.Settle) * time.Millisecond)
+
+	var data ipc.BenchData
+	found, err := d.evalElementQuery(sessionID, benchMetricsJS, &data)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to measure the load: %v", err))
+	}
+	if !found {
+		return ipc.ErrorResponse("failed to measure the load: no result from page")
+	}
+	data.CPUThrottle = d.cpuThrottled()
+	return ipc.SuccessResponse(data)
+}
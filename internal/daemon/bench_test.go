@@ -0,0 +1,30 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestBench_Validation(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+
+	for _, params := range []ipc.BenchParams{
+		{},
+		{URL: "https://one.test/", Settle: -1},
+	} {
+		resp := d.bench("AAA1", params)
+		if resp.OK || resp.Code != ipc.CodeInvalidArgs {
+			t.Errorf("%+v: response = %+v, want %s", params, resp, ipc.CodeInvalidArgs)
+		}
+	}
+	if reqs := conn.getCapturedRequests(); len(reqs) != 0 {
+		t.Errorf("invalid requests reached the browser: %v", reqs)
+	}
+}
@@ -9,6 +9,9 @@ type RingBuffer[T any] struct {
 	head  int // next write position
 	count int // number of items currently in buffer
 	cap   int // maximum capacity
+	// dropped counts the items overwritten when full, or cut by Resize,
+	// since construction or Clear.
+	dropped uint64
 	// seq is the last assigned sequence number. It increments before each
 	// stamp, so the first push after construction or Clear assigns 1 and 0
 	// remains reserved for entries that never passed through Push.
@@ -17,7 +20,9 @@ type RingBuffer[T any] struct {
 	// nil for element types without sequence identity. A function rather than a
 	// setter constraint so the buffer stays generic over T (RingBuffer[int]).
 	stamp func(*T, uint64)
-	mu    sync.RWMutex
+	// persist, if set, is told of every change, to keep a copy on disk.
+	persist persister[T]
+	mu      sync.RWMutex
 }
 
 // NewRingBuffer creates a new ring buffer with the specified capacity. stamp,
@@ -45,13 +50,26 @@ func (b *RingBuffer[T]) Push(item T) {
 		b.seq++
 		b.stamp(&b.items[b.head], b.seq)
 	}
+	if b.persist != nil {
+		b.persist.write(b.items[b.head])
+	}
 	b.head = (b.head + 1) % b.cap
 
 	if b.count < b.cap {
 		b.count++
+	} else {
+		b.dropped++
 	}
 }
 
+// SetPersister directs the buffer to tell p of every push, update, and
+// removal from now on, or stops with nil.
+func (b *RingBuffer[T]) SetPersister(p persister[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.persist = p
+}
+
 // All returns all items in the buffer, oldest first.
 // Allocates a new slice on each call. This is acceptable for the current
 // request-response IPC pattern where each query is a discrete operation.
@@ -88,9 +106,44 @@ func (b *RingBuffer[T]) Len() int {
 
 // Cap returns the buffer capacity.
 func (b *RingBuffer[T]) Cap() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
 	return b.cap
 }
 
+// Dropped returns the number of items lost to a full buffer or a Resize
+// since construction or Clear.
+func (b *RingBuffer[T]) Dropped() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.dropped
+}
+
+// Resize changes the buffer capacity, keeping the newest items that fit.
+func (b *RingBuffer[T]) Resize(capacity int) {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	start := 0
+	if b.count == b.cap {
+		start = b.head
+	}
+	keep := min(b.count, capacity)
+	items := make([]T, capacity)
+	for i := 0; i < keep; i++ {
+		items[i] = b.items[(start+b.count-keep+i)%b.cap]
+	}
+
+	b.dropped += uint64(b.count - keep)
+	b.items = items
+	b.cap = capacity
+	b.count = keep
+	b.head = keep % capacity
+}
+
 // Update iterates through buffer items from newest to oldest,
 // calling fn with a pointer to each item. Iteration stops when fn returns true.
 // This allows in-place modification of buffer entries.
@@ -106,6 +159,9 @@ func (b *RingBuffer[T]) Update(fn func(*T) bool) {
 	for i := 0; i < b.count; i++ {
 		idx := (b.head - 1 - i + b.cap) % b.cap
 		if fn(&b.items[idx]) {
+			if b.persist != nil {
+				b.persist.write(b.items[idx])
+			}
 			return
 		}
 	}
@@ -125,6 +181,10 @@ func (b *RingBuffer[T]) Clear() {
 	b.head = 0
 	b.count = 0
 	b.seq = 0
+	b.dropped = 0
+	if b.persist != nil {
+		b.persist.rewrite(nil)
+	}
 }
 
 // RemoveIf removes all items for which fn returns true.
@@ -157,9 +217,14 @@ func (b *RingBuffer[T]) RemoveIf(fn func(*T) bool) {
 		b.items[i] = zero
 	}
 
+	removed := b.count - len(keep)
+
 	// Re-add kept items
 	b.head = 0
 	b.count = len(keep)
 	copy(b.items, keep)
 	b.head = b.count % b.cap
+	if b.persist != nil && removed > 0 {
+		b.persist.rewrite(keep)
+	}
 }
@@ -401,3 +401,47 @@ func slicesEqual[T comparable](a, b []T) bool {
 	}
 	return true
 }
+
+func TestRingBuffer_Dropped(t *testing.T) {
+	buf := NewRingBuffer[int](3, nil)
+	for i := 1; i <= 5; i++ {
+		buf.Push(i)
+	}
+	if got := buf.Dropped(); got != 2 {
+		t.Errorf("Dropped() = %d, want 2", got)
+	}
+	buf.Clear()
+	if got := buf.Dropped(); got != 0 {
+		t.Errorf("Dropped() after Clear = %d, want 0", got)
+	}
+}
+
+func TestRingBuffer_Resize(t *testing.T) {
+	buf := NewRingBuffer[int](4, nil)
+	for i := 1; i <= 6; i++ {
+		buf.Push(i) // wraps: holds 3, 4, 5, 6
+	}
+
+	buf.Resize(6)
+	if items := buf.All(); !slicesEqual(items, []int{3, 4, 5, 6}) || buf.Cap() != 6 {
+		t.Errorf("grown: got %v cap %d, want [3 4 5 6] cap 6", items, buf.Cap())
+	}
+	buf.Push(7)
+	buf.Push(8)
+	buf.Push(9)
+	if items := buf.All(); !slicesEqual(items, []int{4, 5, 6, 7, 8, 9}) {
+		t.Errorf("after pushes: got %v", items)
+	}
+
+	buf.Resize(2)
+	if items := buf.All(); !slicesEqual(items, []int{8, 9}) || buf.Cap() != 2 {
+		t.Errorf("shrunk: got %v cap %d, want [8 9] cap 2", items, buf.Cap())
+	}
+	if got := buf.Dropped(); got != 7 {
+		t.Errorf("Dropped() = %d, want 7 (2 overwritten, 1 overwritten, 4 cut)", got)
+	}
+	buf.Push(10)
+	if items := buf.All(); !slicesEqual(items, []int{9, 10}) {
+		t.Errorf("after shrink push: got %v, want [9 10]", items)
+	}
+}
@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// cacheStorageTypes are the Storage.clearDataForOrigin types cleared by
+// 'cache-clear --storage': everything a site keeps but its cookies.
+var cacheStorageTypes = []string{
+	"local_storage",
+	"indexeddb",
+	"cache_storage",
+	"service_workers",
+	"file_systems",
+	"websql",
+	"shared_storage",
+	"storage_buckets",
+}
+
+// handleCache handles the "cache" command: it turns the HTTP cache off or on
+// in every tab, or clears it and optionally the active page origin's
+// cookies and storage.
+func (d *Daemon) handleCache(req ipc.Request) ipc.Response {
+	var params ipc.CacheParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid cache parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "status", "":
+		return ipc.SuccessResponse(ipc.CacheData{Disabled: d.cacheDisabled.Load()})
+	case "disable":
+		if ok, resp := d.requireBrowser(); !ok {
+			return resp
+		}
+		return d.setCacheDisabled(params.Disabled)
+	case "clear":
+		if ok, resp := d.requireBrowser(); !ok {
+			return resp
+		}
+		activeID := d.sessions.ActiveID()
+		if activeID == "" {
+			return d.noActiveSessionError()
+		}
+		return d.clearCache(activeID, params)
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown cache action: %s", params.Action))
+	}
+}
+
+// setCacheDisabled turns the HTTP cache off or on in every tab. Tabs opened
+// later follow the setting (see enableDomainsForSession).
+func (d *Daemon) setCacheDisabled(disabled bool) ipc.Response {
+	d.cacheDisabled.Store(disabled)
+	for _, s := range d.sessions.All() {
+		if err := d.ensureNetworkEnabled(s.ID); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err := d.sendToSession(ctx, s.ID, "Network.setCacheDisabled", map[string]any{"cacheDisabled": disabled})
+		cancel()
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to set the cache: %v", err))
+		}
+	}
+	d.log.Info("cache disabled", "disabled", disabled)
+	return ipc.SuccessResponse(ipc.CacheData{Disabled: disabled})
+}
+
+// clearCache clears the browser's HTTP cache and, as asked, the cookies and
+// storage of the origin of the page in a session.
+func (d *Daemon) clearCache(sessionID string, params ipc.CacheParams) ipc.Response {
+	data := ipc.CacheData{Disabled: d.cacheDisabled.Load()}
+
+	// Find the origin first, so nothing is cleared when it cannot be
+	var types []string
+	if params.Cookies {
+		types = append(types, "cookies")
+	}
+	if params.Storage {
+		types = append(types, cacheStorageTypes...)
+	}
+	if len(types) > 0 {
+		var pageURL string
+		if s := d.sessions.Get(sessionID); s != nil {
+			pageURL = s.URL
+		}
+		origin, err := pageOrigin(pageURL)
+		if err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, err.Error())
+		}
+		data.Origin = origin
+	}
+
+	if err := d.ensureNetworkEnabled(sessionID); err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := d.sendToSession(ctx, sessionID, "Network.clearBrowserCache", nil); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to clear the cache: %v", err))
+	}
+	data.Cleared = append(data.Cleared, "cache")
+
+	if len(types) > 0 {
+		if _, err := d.sendToSession(ctx, sessionID, "Storage.clearDataForOrigin", map[string]any{
+			"origin":       data.Origin,
+			"storageTypes": strings.Join(types, ","),
+		}); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to clear data for %s: %v", data.Origin, err))
+		}
+	}
+	if params.Cookies {
+		data.Cleared = append(data.Cleared, "cookies")
+	}
+	if params.Storage {
+		// sessionStorage belongs to the tab, not the origin's storage
+		if _, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+			"expression": "try { sessionStorage.clear() } catch (e) {}",
+		}); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to clear sessionStorage: %v", err))
+		}
+		data.Cleared = append(data.Cleared, "storage")
+	}
+
+	d.log.Info("cache cleared", "cleared", data.Cleared, "origin", data.Origin)
+	return ipc.SuccessResponse(data)
+}
+
+// pageOrigin returns the origin of a web page URL, which cookies and storage
+// are kept by.
+func pageOrigin(pageURL string) (string, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("the active tab has no web page to clear cookies or storage for (%s)", pageURL)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestSetCacheDisabled(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+	d.sessions.Add("BBB2", "T2", "https://two.test/", "Two")
+
+	if resp := d.setCacheDisabled(true); !resp.OK {
+		t.Fatalf("setCacheDisabled(true) = %+v", resp)
+	}
+	if !d.cacheDisabled.Load() {
+		t.Error("cacheDisabled not recorded")
+	}
+	var tabs []string
+	for _, req := range conn.getCapturedRequests() {
+		if req.Method != "Network.setCacheDisabled" {
+			continue
+		}
+		if params, _ := req.Params.(map[string]any); params["cacheDisabled"] != true {
+			t.Errorf("params = %v", req.Params)
+		}
+		tabs = append(tabs, req.SessionID)
+	}
+	if len(tabs) != 2 {
+		t.Errorf("cache disabled in %v, want both tabs", tabs)
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://app.test/account?tab=1", "App")
+	d.sessions.Add("BBB2", "T2", "about:blank", "")
+
+	resp := d.clearCache("AAA1", ipc.CacheParams{Action: "clear", Cookies: true, Storage: true})
+	if !resp.OK {
+		t.Fatalf("clearCache = %+v", resp)
+	}
+	var methods []string
+	for _, req := range conn.getCapturedRequests() {
+		methods = append(methods, req.Method)
+		if req.Method != "Storage.clearDataForOrigin" {
+			continue
+		}
+		params, _ := req.Params.(map[string]any)
+		types, _ := params["storageTypes"].(string)
+		if params["origin"] != "https://app.test" || !strings.HasPrefix(types, "cookies,local_storage,") {
+			t.Errorf("clearDataForOrigin params = %v", params)
+		}
+	}
+	if got := strings.Join(methods, " "); got != "Network.enable Network.clearBrowserCache Storage.clearDataForOrigin Runtime.evaluate" {
+		t.Errorf("methods = %s", got)
+	}
+
+	// Without a web page there is no origin, and nothing is cleared
+	before := len(conn.getCapturedRequests())
+	resp = d.clearCache("BBB2", ipc.CacheParams{Action: "clear", Cookies: true})
+	if resp.OK || resp.Code != ipc.CodeInvalidArgs || len(conn.getCapturedRequests()) != before {
+		t.Errorf("clear on about:blank = %+v", resp)
+	}
+}
@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// chromeResourceBufferSize is the most body bytes Chrome keeps per response
+// by default; a larger body cannot be fetched unless Network.enable raises
+// the limit.
+const chromeResourceBufferSize = 10 << 20
+
+// BodyCapture selects the network bodies the daemon fetches and stores.
+type BodyCapture struct {
+	// Rules pick the bodies to capture; with none, every body is captured.
+	Rules []CaptureRule
+	// MaxSize skips bodies larger than this many bytes, if positive. It also
+	// raises Chrome's per-response buffer to fit, so bodies up to MaxSize are
+	// never dropped by the browser.
+	MaxSize int64
+}
+
+// CaptureRule matches network bodies by MIME type or URL. Patterns are
+// globs where * matches any run of characters, including "/".
+type CaptureRule struct {
+	Field   string // "mime" or "url"
+	Pattern string
+	// Exclude skips matching bodies instead of capturing them.
+	Exclude bool
+}
+
+// String returns the rule as written for --capture-bodies.
+func (r CaptureRule) String() string {
+	s := r.Field + ":" + r.Pattern
+	if r.Exclude {
+		s = "!" + s
+	}
+	return s
+}
+
+// ParseCaptureRules parses a comma-separated --capture-bodies list such as
+// "mime:application/json,url:*/api/*,!mime:image/*".
+func ParseCaptureRules(s string) ([]CaptureRule, error) {
+	var rules []CaptureRule
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		var r CaptureRule
+		if rest, ok := strings.CutPrefix(item, "!"); ok {
+			r.Exclude, item = true, rest
+		}
+		field, pattern, ok := strings.Cut(item, ":")
+		if !ok || pattern == "" || (field != "mime" && field != "url") {
+			return nil, fmt.Errorf("invalid capture rule %q (use mime:<type> or url:<glob>, ! to exclude)", item)
+		}
+		r.Field, r.Pattern = field, pattern
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// Allows reports whether a body of the given MIME type, URL, and size should
+// be captured: it must match an including rule, if there are any, and no
+// excluding rule. A size of 0 is unknown and passes MaxSize.
+func (c BodyCapture) Allows(mimeType, url string, size int64) bool {
+	if c.MaxSize > 0 && size > c.MaxSize {
+		return false
+	}
+	included, hasInclude := false, false
+	for _, r := range c.Rules {
+		value := url
+		if r.Field == "mime" {
+			// Content-Type headers can carry parameters (; charset=utf-8)
+			value, _, _ = strings.Cut(mimeType, ";")
+			value = strings.TrimSpace(value)
+		}
+		match := globMatch(r.Pattern, value)
+		if r.Exclude {
+			if match {
+				return false
+			}
+			continue
+		}
+		hasInclude = true
+		included = included || match
+	}
+	return included || !hasInclude
+}
+
+// String summarises the policy for 'buffer status'.
+func (c BodyCapture) String() string {
+	rules := make([]string, len(c.Rules))
+	for i, r := range c.Rules {
+		rules[i] = r.String()
+	}
+	s := strings.Join(rules, ",")
+	if c.MaxSize > 0 {
+		if s != "" {
+			s += ", "
+		}
+		s += fmt.Sprintf("max %d bytes", c.MaxSize)
+	}
+	return s
+}
+
+// headerValue returns the value of the named header, matched
+// case-insensitively.
+func headerValue(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// globMatch reports whether s matches pattern, where * matches any run of
+// characters and everything else matches itself, case-insensitively.
+func globMatch(pattern, s string) bool {
+	pattern, s = strings.ToLower(pattern), strings.ToLower(s)
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return len(s) >= len(last) && strings.HasSuffix(s, last)
+}
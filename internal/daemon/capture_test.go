@@ -0,0 +1,107 @@
+package daemon
+
+import (
+	"testing"
+)
+
+func TestParseCaptureRules(t *testing.T) {
+	rules, err := ParseCaptureRules("mime:application/json, url:*/api/*,!mime:image/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CaptureRule{
+		{Field: "mime", Pattern: "application/json"},
+		{Field: "url", Pattern: "*/api/*"},
+		{Field: "mime", Pattern: "image/*", Exclude: true},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("got %+v, want %+v", rules, want)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+
+	if rules, err := ParseCaptureRules(""); err != nil || rules != nil {
+		t.Errorf("empty = %v, %v, want no rules", rules, err)
+	}
+	for _, bad := range []string{"json", "type:text/html", "mime:", "!url"} {
+		if _, err := ParseCaptureRules(bad); err == nil {
+			t.Errorf("ParseCaptureRules(%q) accepted an invalid rule", bad)
+		}
+	}
+}
+
+func TestBodyCapture_Allows(t *testing.T) {
+	rules, _ := ParseCaptureRules("mime:application/json,url:*/api/*,!mime:image/*")
+	policy := BodyCapture{Rules: rules, MaxSize: 1000}
+
+	tests := []struct {
+		name     string
+		capture  BodyCapture
+		mimeType string
+		url      string
+		size     int64
+		want     bool
+	}{
+		{"no policy", BodyCapture{}, "video/mp4", "https://x.test/a.mp4", 50 << 20, true},
+		{"mime match", policy, "application/json; charset=utf-8", "https://x.test/data", 10, true},
+		{"url match", policy, "text/plain", "https://x.test/api/users", 10, true},
+		{"no match", policy, "text/html", "https://x.test/", 10, false},
+		{"excluded", policy, "image/png", "https://x.test/api/avatar.png", 10, false},
+		{"too large", policy, "application/json", "https://x.test/data", 1001, false},
+		{"unknown size", policy, "application/json", "https://x.test/data", 0, true},
+		{"exclude only", BodyCapture{Rules: []CaptureRule{{Field: "mime", Pattern: "video/*", Exclude: true}}}, "text/html", "https://x.test/", 10, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.capture.Allows(tt.mimeType, tt.url, tt.size); got != tt.want {
+				t.Errorf("Allows(%q, %q, %d) = %v, want %v", tt.mimeType, tt.url, tt.size, got, tt.want)
+			}
+		})
+	}
+
+	if got := policy.String(); got != "mime:application/json,url:*/api/*,!mime:image/*, max 1000 bytes" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*/api/*", "https://example.com/api/v1/users", true},
+		{"*/api/*", "https://example.com/apis", false},
+		{"image/*", "IMAGE/PNG", true},
+		{"application/json", "application/json", true},
+		{"application/json", "application/jsonp", false},
+		{"*.js", "https://cdn.test/vendor.js", true},
+		{"a*b*c", "abxbc", true},
+		{"a*b*c", "ab", false},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkEnableParams_MaxCaptureSize(t *testing.T) {
+	d := New(DefaultConfig())
+	if _, ok := d.networkEnableParams()["maxResourceBufferSize"]; ok {
+		t.Error("default raises Chrome's resource buffer")
+	}
+
+	cfg := DefaultConfig()
+	cfg.BodyCapture.MaxSize = 50 << 20
+	d = New(cfg)
+	params := d.networkEnableParams()
+	if params["maxResourceBufferSize"] != int64(50<<20) {
+		t.Errorf("maxResourceBufferSize = %v, want %d", params["maxResourceBufferSize"], 50<<20)
+	}
+	if total, _ := params["maxTotalBufferSize"].(int64); total < 50<<20 {
+		t.Errorf("maxTotalBufferSize = %v, want at least the resource size", params["maxTotalBufferSize"])
+	}
+}
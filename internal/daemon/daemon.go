@@ -3,18 +3,23 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/grantcarthew/webctl/internal/browser"
 	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/daemonlog"
 	"github.com/grantcarthew/webctl/internal/ipc"
 	"github.com/grantcarthew/webctl/internal/server"
 	"golang.org/x/term"
@@ -40,13 +45,53 @@ type Config struct {
 	// browser.UserDataDirDefault sentinel means the user's system Chrome
 	// profile, and any other value is a concrete persistent directory.
 	UserDataDir string
-	SocketPath  string
-	PIDPath     string
-	BufferSize  int
-	Debug       bool
+	// Launch holds the remaining Chrome customizations: locale, window
+	// geometry, incognito, and extra command-line flags.
+	Launch ipc.LaunchFlags
+	// Version is the webctl version, reported to clients in the IPC
+	// handshake.
+	Version    string
+	SocketPath string
+	PIDPath    string
+	// StatePath is the state file recording the browser's process, CDP
+	// endpoint, and profile, for 'start --reuse' after a daemon crash.
+	StatePath string
+	// Reuse takes over the browser recorded in the state file, if it is still
+	// running, instead of launching a new one.
+	Reuse bool
+	// Adopt attaches to a browser someone else launched that is already
+	// serving CDP on Port. The daemon never closes or relaunches it.
+	Adopt      bool
+	BufferSize int
+	// PersistDir, if set, keeps the console and network buffers in this
+	// directory as JSON lines, and restores them when a daemon starts with
+	// the same directory and socket.
+	PersistDir string
+	// BodyCapture selects the network bodies that are fetched and stored.
+	BodyCapture BodyCapture
+	// LogPath is the daemon log file. Empty disables the log file.
+	LogPath string
+	// LogLevel is the minimum level recorded in the log file.
+	LogLevel slog.Level
+	// LogHandler, if non-nil, receives every daemon, CDP, and IPC log record it
+	// is enabled for, alongside the log file. The CLI uses it to mirror the log
+	// to stderr.
+	LogHandler slog.Handler
+	// MetricsAddr, if set, is the TCP address (e.g. ":9090") of an HTTP
+	// listener exposing Prometheus metrics at /metrics.
+	MetricsAddr string
+	// Emulation is the viewport, user agent, and network throttling applied to
+	// every page session.
+	Emulation Emulation
 	// CommandExecutor is called by REPL for CLI command execution with flags.
 	// If nil, REPL falls back to basic IPC-only execution.
 	CommandExecutor ipc.CommandExecutor
+	// CommandNames lists the CLI's commands for REPL tab completion. If nil,
+	// the REPL completes a built-in list.
+	CommandNames []string
+	// REPLHistoryFile keeps REPL line history across daemon runs. If empty,
+	// history lasts for the run only.
+	REPLHistoryFile string
 	// ReadyCallback, if non-nil, is invoked once from Run when the daemon
 	// reaches operational readiness (IPC serving). Mirrors CommandExecutor in
 	// being optional and nil-safe: a caller that leaves it unset is unaffected.
@@ -60,7 +105,11 @@ func DefaultConfig() Config {
 		Port:       9222,
 		SocketPath: ipc.DefaultSocketPath(),
 		PIDPath:    ipc.DefaultPIDPath(),
+		StatePath:  ipc.DefaultStatePath(),
 		BufferSize: DefaultBufferSize,
+		LogPath:    daemonlog.DefaultPath(),
+
+		REPLHistoryFile: DefaultREPLHistoryPath(),
 	}
 }
 
@@ -72,14 +121,23 @@ type Daemon struct {
 	sessions        *SessionManager
 	consoleBuf      *RingBuffer[ipc.ConsoleEntry]
 	networkBuf      *RingBuffer[ipc.NetworkEntry]
+	eventBuf        *RingBuffer[ipc.DaemonEvent]     // Daemon events, for 'webctl events'
+	exceptionBuf    *RingBuffer[ipc.PausedException] // Exceptions captured by 'webctl exceptions'
+	captureBodies   atomic.Bool                      // Fetch and store network bodies ('buffer set bodies')
+	cacheDisabled   atomic.Bool                      // HTTP cache off in every tab ('cache-disable on')
+	cpuThrottle     float64                          // CPU slowdown in every tab ('throttle cpu'); 1 or 0 is none
+	cpuThrottleMu   sync.Mutex                       // Protects cpuThrottle
 	server          *ipc.Server
 	devServer       *server.Server // Development web server (serve command)
 	devServerMu     sync.Mutex     // Protects devServer
 	shutdown        chan struct{}
 	shutdownOnce    sync.Once
-	browserLostMsg  string // Classified disconnect message, set when shutdown triggered by browser disconnection
-	browserLostMu   sync.Mutex
-	debug           bool
+	browserLost     chan error      // Browser disconnects and exits, for Run to relaunch it
+	browserMu       sync.RWMutex    // Held by requests, and exclusively while the browser is replaced
+	modeSwitches    chan modeSwitch // 'head' and 'headless' requests, for Run to relaunch the browser
+	log             *slog.Logger    // Daemon log; LogHandler only until Run opens the log file
+	metrics         *daemonMetrics
+	started         time.Time   // when New made the daemon, for uptime
 	terminalState   *term.State // Saved terminal state for restoration
 	terminalStateMu sync.Mutex
 	repl            *REPL // REPL instance for external command notifications
@@ -88,14 +146,99 @@ type Daemon struct {
 	navTracker *navTracker
 	// attaches deduplicates Target.attachToTarget calls by targetID.
 	attaches *attachSet
-}
-
-// debugf logs a debug message if debug mode is enabled (daemon-level or request-level).
-func (d *Daemon) debugf(reqDebug bool, format string, args ...any) {
-	if d.debug || reqDebug {
-		timestamp := time.Now().Format("15:04:05.000")
-		fmt.Fprintf(os.Stderr, "[DEBUG] [%s] "+format+"\n", append([]any{timestamp}, args...)...)
-	}
+	// tabLocks serialises the commands that drive each tab.
+	tabLocks *tabLocks
+
+	// highlightTimer removes the current element overlay when it fires.
+	highlightTimer *time.Timer
+	highlightMu    sync.Mutex
+
+	// pick is the pending "pick" request, if any, awaiting an inspect-mode click.
+	pick   *pickRequest
+	pickMu sync.Mutex
+
+	// styleSnapshots holds "css diff --before" computed styles, keyed by
+	// session ID and selector, for comparison by a later --after.
+	styleSnapshots   map[string]map[string]string
+	styleSnapshotsMu sync.Mutex
+
+	// styleSheets tracks CSS.styleSheetAdded headers per session while the CSS
+	// domain is enabled, in the order Chrome reported them.
+	styleSheets   map[string][]styleSheetHeader
+	styleSheetsMu sync.Mutex
+
+	// scripts collects Debugger.scriptParsed events per session while
+	// "source" has the Debugger domain enabled.
+	scripts   map[string][]scriptInfo
+	scriptsMu sync.Mutex
+
+	// heapSnapshots receives HeapProfiler.addHeapSnapshotChunk events per
+	// session while a heap snapshot is being taken.
+	heapSnapshots   map[string]io.Writer
+	heapSnapshotsMu sync.Mutex
+
+	// browserContexts holds the browser contexts made with 'context new', by
+	// ID. They die with the browser.
+	browserContexts   map[string]*browserContext
+	browserContextsMu sync.Mutex
+
+	// contextFrames maps execution context IDs to frame IDs per session, from
+	// Runtime.executionContextCreated, so console entries can carry a frame ID.
+	contextFrames   map[string]map[int]string
+	contextFramesMu sync.Mutex
+
+	// recording is the active 'record-flow' recording, if any.
+	recording *recording
+	recordMu  sync.Mutex
+
+	// monitors are the URLs checked on a schedule by 'webctl monitor', by ID.
+	monitors   map[string]*monitor
+	monitorSeq int
+	monitorsMu sync.Mutex
+
+	// pool is the 'webctl pool' of background tabs, if one was created.
+	pool   *tabPool
+	poolMu sync.Mutex
+
+	// mocks are the 'webctl mock' rules intercepting requests in every tab,
+	// in the order they were added; the first match applies.
+	mocks   []*mockRule
+	mockSeq int
+	mocksMu sync.Mutex
+
+	// aborts are the 'network abort' rules failing requests in every tab, in
+	// the order they were added; held are the requests paused by them or by
+	// a mock's delay, by network request ID.
+	aborts   []*ipc.AbortRule
+	abortSeq int
+	held     map[string]*heldRequest
+	abortsMu sync.Mutex
+
+	// logpoints are the 'webctl logpoint' breakpoints logging an expression
+	// in every tab, in the order they were added.
+	logpoints   []ipc.LogpointInfo
+	logpointSeq int
+	logpointsMu sync.Mutex
+
+	// exceptionMode is the 'webctl exceptions pause' mode in every tab: on,
+	// uncaught, or off (empty).
+	exceptionMode string
+	exceptionsMu  sync.Mutex
+
+	// bannerRules are the 'dismiss-banners --auto' rules applied on every
+	// load in every tab; nil while automatic dismissal is off.
+	bannerRules []ipc.BannerRule
+	bannersMu   sync.Mutex
+
+	// rewrites are the 'webctl rewrite' rules editing response bodies in
+	// every tab, in the order they were added; every match applies.
+	rewrites   []*rewriteRule
+	rewriteSeq int
+	rewritesMu sync.Mutex
+
+	// mice holds each tab's pointer position and held buttons for 'mouse'.
+	mice   map[string]mouseState
+	miceMu sync.Mutex
 }
 
 // browserConnected checks if the browser is currently running and connected.
@@ -108,25 +251,17 @@ func (d *Daemon) browserConnected() bool {
 }
 
 // requireBrowser checks if the browser is connected.
-// If not connected, it triggers daemon shutdown and returns an error response.
-// Handlers should return this response immediately if err is not nil.
+// If not connected, it asks Run to relaunch the browser and returns an error
+// response. Handlers should return this response immediately if err is not nil.
 func (d *Daemon) requireBrowser() (ok bool, resp ipc.Response) {
 	if d.browserConnected() {
 		return true, ipc.Response{}
 	}
 
-	// Browser is dead - clear state and trigger shutdown
-	d.debugf(false, "Browser not connected - clearing state and shutting down daemon")
-	d.sessions.Clear()
-	msg := classifyDisconnect(d.cdp.Err())
-	d.browserLostMu.Lock()
-	d.browserLostMsg = msg
-	d.browserLostMu.Unlock()
-	go d.shutdownOnce.Do(func() {
-		close(d.shutdown)
-	})
-
-	return false, ipc.ErrorResponse(msg + " - daemon shutting down")
+	d.log.Warn("browser not connected, restarting")
+	err := d.cdp.Err()
+	d.reportBrowserLost(err)
+	return false, ipc.ErrorResponse(classifyDisconnect(err) + " - restarting browser")
 }
 
 // isConnectionError checks if an error indicates a CDP connection failure.
@@ -141,20 +276,14 @@ func (d *Daemon) isConnectionError(err error) bool {
 }
 
 // sendToSession wraps cdp.SendToSession with connection error detection.
-// If a connection error is detected, it triggers daemon shutdown.
+// If a connection error is detected, it asks Run to relaunch the browser.
 func (d *Daemon) sendToSession(ctx context.Context, sessionID, method string, params any) (json.RawMessage, error) {
 	result, err := d.cdp.SendToSession(ctx, sessionID, method, params)
 	if err != nil && d.isConnectionError(err) {
-		d.debugf(false, "Connection error detected in %s: %v - shutting down daemon", method, err)
-		d.sessions.Clear()
-		msg := classifyDisconnect(d.cdp.Err())
-		d.browserLostMu.Lock()
-		d.browserLostMsg = msg
-		d.browserLostMu.Unlock()
-		go d.shutdownOnce.Do(func() {
-			close(d.shutdown)
-		})
-		return nil, fmt.Errorf("%s - daemon shutting down", msg)
+		d.log.Warn("CDP connection error, restarting browser", "method", method, "error", err)
+		cdpErr := d.cdp.Err()
+		d.reportBrowserLost(cdpErr)
+		return nil, fmt.Errorf("%s - restarting browser", classifyDisconnect(cdpErr))
 	}
 	return result, err
 }
@@ -165,16 +294,25 @@ func New(cfg Config) *Daemon {
 		cfg.BufferSize = DefaultBufferSize
 	}
 
-	return &Daemon{
-		config:     cfg,
-		sessions:   NewSessionManager(),
-		consoleBuf: NewRingBuffer(cfg.BufferSize, func(e *ipc.ConsoleEntry, s uint64) { e.Seq = s }),
-		networkBuf: NewRingBuffer(cfg.BufferSize, func(e *ipc.NetworkEntry, s uint64) { e.Seq = s }),
-		shutdown:   make(chan struct{}),
-		debug:      cfg.Debug,
-		navTracker: newNavTracker(),
-		attaches:   newAttachSet(),
+	d := &Daemon{
+		config:       cfg,
+		sessions:     NewSessionManager(),
+		consoleBuf:   NewRingBuffer(cfg.BufferSize, func(e *ipc.ConsoleEntry, s uint64) { e.Seq = s }),
+		networkBuf:   NewRingBuffer(cfg.BufferSize, func(e *ipc.NetworkEntry, s uint64) { e.Seq = s }),
+		eventBuf:     NewRingBuffer(eventBufferSize, func(e *ipc.DaemonEvent, s uint64) { e.Seq = s }),
+		exceptionBuf: NewRingBuffer(exceptionBufferSize, func(e *ipc.PausedException, s uint64) { e.Seq = s }),
+		shutdown:     make(chan struct{}),
+		browserLost:  make(chan error, 1),
+		modeSwitches: make(chan modeSwitch),
+		log:          slog.New(daemonlog.Tee(cfg.LogHandler)),
+		navTracker:   newNavTracker(),
+		attaches:     newAttachSet(),
+		tabLocks:     newTabLocks(),
+		started:      time.Now(),
 	}
+	d.captureBodies.Store(true)
+	d.metrics = d.newMetrics()
+	return d
 }
 
 // Handler returns the IPC request handler function.
@@ -199,6 +337,39 @@ func (d *Daemon) restoreTerminalState() {
 
 // Run starts the daemon and blocks until shutdown.
 func (d *Daemon) Run(ctx context.Context) error {
+	// Open the log file first so startup failures are recorded
+	if d.config.LogPath != "" {
+		closeLog, err := d.openLog()
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		defer closeLog()
+	}
+
+	d.log.Info("daemon starting", "pid", os.Getpid(), "headless", d.config.Headless, "port", d.config.Port)
+	err := d.run(ctx)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		d.log.Error("daemon failed", "error", err)
+	}
+	d.log.Info("daemon stopped")
+	return err
+}
+
+// openLog opens the rotating log file at LogPath and directs d.log to it as
+// well as LogHandler. The returned function closes the file; later writes to it
+// are dropped.
+func (d *Daemon) openLog() (func(), error) {
+	f, err := daemonlog.Open(d.config.LogPath, daemonlog.Options{})
+	if err != nil {
+		return nil, err
+	}
+	file := slog.NewJSONHandler(f, &slog.HandlerOptions{Level: d.config.LogLevel})
+	d.log = slog.New(daemonlog.Tee(file, d.config.LogHandler))
+	return func() { _ = f.Close() }, nil
+}
+
+// run launches the browser, serves IPC, and blocks until shutdown.
+func (d *Daemon) run(ctx context.Context) error {
 	// Restore terminal state on exit (all paths)
 	defer d.restoreTerminalState()
 
@@ -208,17 +379,16 @@ func (d *Daemon) Run(ctx context.Context) error {
 	}
 	defer d.removePIDFile()
 
-	// Start browser
-	b, err := browser.Start(browser.LaunchOptions{
-		Port:        d.config.Port,
-		Headless:    d.config.Headless,
-		UserDataDir: d.config.UserDataDir,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to start browser: %w", err)
-	}
-	d.browser = b
-	defer func() { _ = d.browser.Close() }()
+	// Close the browser and its connection on exit, whichever browser is
+	// current by then
+	defer func() {
+		if d.cdp != nil {
+			_ = d.cdp.Close()
+		}
+		if d.browser != nil {
+			_ = d.browser.Close()
+		}
+	}()
 
 	// Stop dev server on shutdown if running
 	defer func() {
@@ -228,50 +398,46 @@ func (d *Daemon) Run(ctx context.Context) error {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 			if err := d.devServer.Stop(ctx); err != nil {
-				d.debugf(false, "Failed to stop dev server: %v", err)
+				d.log.Warn("failed to stop development server", "error", err)
 			}
 		}
 	}()
 
-	// Update config with actual port used (may differ from requested if auto-selected)
-	d.config.Port = b.Port()
-
-	// Connect to browser-level CDP WebSocket (not page target)
-	// This allows us to use Target.setAutoAttach for session management
-	version, err := d.browser.Version(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get browser version: %w", err)
+	// Restore the buffers before the browser adds to them
+	if d.config.PersistDir != "" {
+		closeJournals, err := d.restoreBuffers()
+		if err != nil {
+			return err
+		}
+		defer closeJournals()
 	}
-	d.debugf(false, "Browser version info: %+v", version)
-	d.debugf(false, "Connecting to CDP WebSocket: %s", version.WebSocketURL)
 
-	cdpClient, err := cdp.Dial(ctx, version.WebSocketURL)
-	if err != nil {
-		return fmt.Errorf("failed to connect to CDP: %w", err)
+	// Start (or reuse) the browser, connect CDP, and attach to its targets
+	if err := d.launchBrowser(ctx); err != nil {
+		return err
 	}
-	d.cdp = cdpClient
-	defer func() { _ = d.cdp.Close() }()
-	d.debugf(false, "CDP client connected successfully")
-
-	// Subscribe to events before enabling domains
-	d.debugf(false, "Subscribing to CDP events")
-	d.subscribeEvents()
-	d.debugf(false, "Event subscriptions complete")
+	defer d.removeStateFile()
 
-	// Enable auto-attach for session tracking
-	d.debugf(false, "Enabling target discovery and attachment")
-	if err := d.enableAutoAttach(); err != nil {
-		return fmt.Errorf("failed to enable auto-attach: %w", err)
+	// Expose metrics before serving IPC so a scrape never sees a half-started daemon
+	if d.config.MetricsAddr != "" {
+		_, stopMetrics, err := d.serveMetrics(d.config.MetricsAddr)
+		if err != nil {
+			return err
+		}
+		defer stopMetrics()
 	}
-	d.debugf(false, "Target discovery and attachment enabled")
 
-	// Start heartbeat for proactive disconnect detection
-	disconnectCh := make(chan error, 1)
-	d.startHeartbeat(ctx, disconnectCh)
+	// Watch for browser exit and silent disconnection
+	stopWatch := d.watchBrowser(ctx)
+	defer func() { stopWatch() }()
 
 	// Start IPC server with wrapper handler for external command notifications
 	ipcHandler := func(req ipc.Request) ipc.Response {
 		resp := d.handleRequest(req)
+		d.metrics.observeIPC(req, resp)
+		if !resp.OK {
+			d.recordEvent("warn", "command_failed", resp.Error, "cmd", req.Cmd)
+		}
 		// Notify REPL of external command AFTER handling (so prompt reflects updated state)
 		if d.repl != nil {
 			summary := formatCommandSummary(req)
@@ -284,6 +450,8 @@ func (d *Daemon) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to start IPC server: %w", err)
 	}
 	d.server = server
+	d.server.SetLogger(d.log.With("component", "ipc"))
+	d.server.SetVersion(d.config.Version, ipc.CapabilityStream, ipc.CapabilityGzip)
 	defer func() { _ = d.server.Close() }()
 
 	// Set up signal handling
@@ -301,6 +469,7 @@ func (d *Daemon) Run(ctx context.Context) error {
 	// that would let it race raw-mode entry and render differently on a TTY than
 	// on non-TTY stdin. The bound port (resolved earlier via b.Port()) is passed
 	// so consumers report the actual port rather than the requested one.
+	d.log.Info("daemon ready", "port", d.config.Port, "socket", d.config.SocketPath)
 	if d.config.ReadyCallback != nil {
 		d.config.ReadyCallback(d.config.Port)
 	}
@@ -329,6 +498,8 @@ func (d *Daemon) Run(ctx context.Context) error {
 		repl.SetSessionProvider(func() (*ipc.PageSession, int) {
 			return d.sessions.Active(), d.sessions.Count()
 		})
+		repl.SetCommands(d.config.CommandNames)
+		repl.SetHistoryFile(d.config.REPLHistoryFile)
 		d.repl = repl // Store reference for external command notifications
 
 		go func() {
@@ -339,37 +510,71 @@ func (d *Daemon) Run(ctx context.Context) error {
 	// When stdin is not a TTY, replDone remains open - daemon waits for
 	// context cancellation, signal, shutdown command, or server error.
 
-	// Wait for shutdown
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-sigCh:
-		return nil
-	case <-d.shutdown:
-		d.browserLostMu.Lock()
-		msg := d.browserLostMsg
-		d.browserLostMu.Unlock()
-		if msg != "" {
-			fmt.Fprintf(os.Stderr, "\nError: %s - daemon shutting down\n", msg)
+	// Wait for shutdown, relaunching the browser whenever it crashes
+	for {
+		select {
+		case <-ctx.Done():
+			d.log.Info("daemon stopping", "reason", "context canceled")
+			return ctx.Err()
+		case sig := <-sigCh:
+			d.log.Info("daemon stopping", "reason", "signal", "signal", sig.String())
+			return nil
+		case <-d.shutdown:
+			d.log.Info("daemon stopping", "reason", "shutdown requested")
+			return nil
+		case err := <-d.browserLost:
+			msg := classifyDisconnect(err)
+			stopWatch()
+			d.recordEvent("warn", "browser_lost", msg, "error", err)
+			if !browserCrashed(err) || d.browser.Adopted() {
+				d.log.Error("daemon stopping", "reason", msg, "error", err)
+				fmt.Fprintf(os.Stderr, "\nError: %s - daemon shutting down\n", msg)
+				return nil
+			}
+			d.log.Warn("restarting browser", "reason", msg, "error", err)
+			if _, _, rerr := d.replaceBrowser(ctx, d.config.Headless); rerr != nil {
+				d.recordEvent("error", "browser_restart_failed", "browser could not be restarted", "error", rerr)
+				d.log.Error("daemon stopping", "reason", "browser restart failed", "error", rerr)
+				fmt.Fprintf(os.Stderr, "\nError: %s and could not be restarted - daemon shutting down\n", msg)
+				return nil
+			}
+			// Drop reports about the old browser that raced the restart
+			select {
+			case <-d.browserLost:
+			default:
+			}
+			stopWatch = d.watchBrowser(ctx)
+			d.metrics.browserRestarts.Inc()
+			d.log.Warn("browser restarted", "event", "browser_restarted", "reason", msg,
+				"pid", d.browser.PID(), "port", d.config.Port, "restarts", d.metrics.browserRestarts.Value())
+			d.recordEvent("warn", "browser_restarted", "browser restarted", "reason", msg,
+				"pid", d.browser.PID(), "restarts", d.metrics.browserRestarts.Value())
+			fmt.Fprintf(os.Stderr, "\nWarning: %s - browser restarted\n", msg)
+		case sw := <-d.modeSwitches:
+			stopWatch()
+			if !d.switchBrowserMode(ctx, sw) {
+				return nil
+			}
+			select {
+			case <-d.browserLost:
+			default:
+			}
+			stopWatch = d.watchBrowser(ctx)
+		case err := <-errCh:
+			d.log.Error("daemon stopping", "reason", "IPC server failed", "error", err)
+			return err
+		case <-replDone:
+			// REPL exited (EOF or error)
+			d.log.Info("daemon stopping", "reason", "REPL exited")
+			return nil
 		}
-		return nil
-	case err := <-disconnectCh:
-		msg := classifyDisconnect(err)
-		d.debugf(false, "browser disconnect: %v", err)
-		fmt.Fprintf(os.Stderr, "\nError: %s - daemon shutting down\n", msg)
-		return nil
-	case err := <-errCh:
-		return err
-	case <-replDone:
-		// REPL exited (EOF or error)
-		return nil
 	}
 }
 
 // enableAutoAttach enables Target.setDiscoverTargets for target discovery.
 // We use manual Target.attachToTarget with flatten:true for each discovered target.
 func (d *Daemon) enableAutoAttach() error {
-	d.debugf(false, "Calling Target.setDiscoverTargets...")
+	d.log.Debug("calling Target.setDiscoverTargets")
 	// Enable target discovery to receive targetCreated/targetInfoChanged/targetDestroyed events
 	_, err := d.cdp.Send("Target.setDiscoverTargets", map[string]any{
 		"discover": true,
@@ -377,19 +582,19 @@ func (d *Daemon) enableAutoAttach() error {
 	if err != nil {
 		return fmt.Errorf("failed to set discover targets: %w", err)
 	}
-	d.debugf(false, "Target.setDiscoverTargets succeeded")
+	d.log.Debug("Target.setDiscoverTargets succeeded")
 
 	// NOTE: We do NOT use Target.setAutoAttach here.
 	// Instead, we manually call Target.attachToTarget for each target in handleTargetCreated.
 	// Using flatten:true in attachToTarget (not setAutoAttach) avoids networkIdle blocking.
 
 	// Attach to any existing targets that were created before we enabled discovery
-	d.debugf(false, "Calling Target.getTargets to find existing targets...")
+	d.log.Debug("calling Target.getTargets to find existing targets")
 	result, err := d.cdp.Send("Target.getTargets", nil)
 	if err != nil {
 		return fmt.Errorf("failed to get existing targets: %w", err)
 	}
-	d.debugf(false, "Target.getTargets succeeded, parsing results...")
+	d.log.Debug("Target.getTargets succeeded")
 
 	var targetsResult struct {
 		TargetInfos []struct {
@@ -402,21 +607,21 @@ func (d *Daemon) enableAutoAttach() error {
 	if err := json.Unmarshal(result, &targetsResult); err != nil {
 		return fmt.Errorf("failed to parse targets: %w", err)
 	}
-	d.debugf(false, "Found %d total targets", len(targetsResult.TargetInfos))
+	d.log.Debug("found existing targets", "count", len(targetsResult.TargetInfos))
 
 	// Attach to existing page targets asynchronously
 	for _, targetInfo := range targetsResult.TargetInfos {
-		d.debugf(false, "  Target: type=%q, targetID=%q, url=%q", targetInfo.Type, targetInfo.TargetID, targetInfo.URL)
+		d.log.Debug("existing target", "type", targetInfo.Type, "targetId", targetInfo.TargetID, "url", targetInfo.URL)
 		if targetInfo.Type == "page" {
 			// Check if we've already attached (targetCreated might have fired before getTargets returned)
 			if !d.attaches.mark(targetInfo.TargetID) {
-				d.debugf(false, "  Already attached to targetID=%q, skipping", targetInfo.TargetID)
+				d.log.Debug("already attached to target, skipping", "targetId", targetInfo.TargetID)
 				continue
 			}
 
 			targetID := targetInfo.TargetID // capture for goroutine
 			go func() {
-				d.debugf(false, "  Attaching to existing page target: targetID=%q", targetID)
+				d.log.Debug("attaching to existing page target", "targetId", targetID)
 				_, err := d.cdp.Send("Target.attachToTarget", map[string]any{
 					"targetId": targetID,
 					"flatten":  true,
@@ -426,7 +631,7 @@ func (d *Daemon) enableAutoAttach() error {
 					// Clear the mark on failure so we can retry
 					d.attaches.clear(targetID)
 				} else {
-					d.debugf(false, "  Successfully attached to target %q", targetID)
+					d.log.Debug("attached to target", "targetId", targetID)
 				}
 			}()
 		}
@@ -453,12 +658,18 @@ func (d *Daemon) enableDomainsForSession(sessionID string) error {
 	// claim on failure so a later caller can retry rather than being permanently
 	// marked enabled.
 	if d.sessions.ClaimNetworkEnable(sessionID) {
-		if _, err := d.cdp.SendToSession(context.Background(), sessionID, "Network.enable", networkEnableParams()); err != nil {
+		if _, err := d.cdp.SendToSession(context.Background(), sessionID, "Network.enable", d.networkEnableParams()); err != nil {
 			d.sessions.ClearNetworkEnabled(sessionID)
 			return fmt.Errorf("failed to enable Network.enable: %w", err)
 		}
 	}
 
+	if d.cacheDisabled.Load() {
+		if _, err := d.cdp.SendToSession(context.Background(), sessionID, "Network.setCacheDisabled", map[string]any{"cacheDisabled": true}); err != nil {
+			return fmt.Errorf("failed to disable the cache: %w", err)
+		}
+	}
+
 	// Enable lifecycle events (required to receive Page.lifecycleEvent)
 	if _, err := d.cdp.SendToSession(context.Background(), sessionID, "Page.setLifecycleEventsEnabled", map[string]any{"enabled": true}); err != nil {
 		return fmt.Errorf("failed to enable lifecycle events: %w", err)
@@ -478,67 +689,211 @@ func (d *Daemon) enableDomainsForSession(sessionID string) error {
 		return fmt.Errorf("failed to set async call stack depth: %w", err)
 	}
 
+	if err := d.applyEmulation(sessionID); err != nil {
+		return err
+	}
+	if rate := d.cpuThrottled(); rate > 0 {
+		if _, err := d.cdp.SendToSession(context.Background(), sessionID, "Emulation.setCPUThrottlingRate", map[string]any{"rate": rate}); err != nil {
+			return fmt.Errorf("failed to throttle the CPU: %w", err)
+		}
+	}
+	if patterns := d.interceptPatterns(); len(patterns) > 0 {
+		if err := d.applyInterception(sessionID, patterns); err != nil {
+			return fmt.Errorf("failed to intercept requests: %w", err)
+		}
+	}
+	if d.debuggerNeeded() {
+		if _, err := d.applyDebugger(sessionID); err != nil {
+			return fmt.Errorf("failed to set up the debugger: %w", err)
+		}
+	}
+	if rules := d.autoBannerRules(); rules != nil {
+		if err := d.applyBannerScript(sessionID, rules); err != nil {
+			return fmt.Errorf("failed to dismiss banners: %w", err)
+		}
+	}
+
 	// NOTE: We don't use waitForDebuggerOnStart with manual Target.attachToTarget,
 	// so no need to call Runtime.runIfWaitingForDebugger
 
 	return nil
 }
 
-// handleRequest processes an IPC request and returns a response.
+// tabCommands are the commands that drive the active tab. They take its lock
+// (see tabLocks), so they run one at a time per tab.
+var tabCommands = map[string]bool{
+	"navigate":        true,
+	"reload":          true,
+	"back":            true,
+	"forward":         true,
+	"click":           true,
+	"focus":           true,
+	"type":            true,
+	"key":             true,
+	"select":          true,
+	"check":           true,
+	"scroll":          true,
+	"mouse":           true,
+	"eval":            true,
+	"dismiss-banners": true,
+}
+
+// handleRequest processes an IPC request and returns a response. Requests
+// run concurrently; see tabCommands for the ones that take turns.
 func (d *Daemon) handleRequest(req ipc.Request) ipc.Response {
 	switch req.Cmd {
+	case "browser-mode":
+		// Replacing the browser takes browserMu exclusively, so this must
+		// not hold it while waiting
+		return d.handleBrowserMode(req)
+
+	// Read-only commands never wait: not for a tab's commands, nor for the
+	// browser to be replaced, which can take as long as a launch
 	case "status":
-		return d.handleStatus()
-	case "console":
-		return d.handleConsole()
-	case "network":
-		return d.handleNetwork()
+		return d.handleStatus(req)
+	case "clear":
+		return d.handleClear(req)
+	case "buffer":
+		return d.handleBuffer(req)
+	case "events":
+		return d.handleEvents(req)
+	case "shutdown":
+		return d.handleShutdown()
+	case "console", "network":
+		if !d.browserMu.TryRLock() {
+			return ipc.ErrorResponse("browser is restarting - try again shortly")
+		}
+		defer d.browserMu.RUnlock()
+		if req.Cmd == "console" {
+			return d.handleConsole()
+		}
+		return d.handleNetwork(req)
+	}
+
+	// Hold off while the browser is being replaced
+	d.browserMu.RLock()
+	defer d.browserMu.RUnlock()
+
+	if tabCommands[req.Cmd] {
+		unlock := d.lockActiveTab()
+		defer unlock()
+	}
+
+	switch req.Cmd {
 	case "screenshot":
 		return d.handleScreenshot(req)
 	case "html":
 		return d.handleHTML(req)
 	case "tab":
 		return d.handleTab(req)
-	case "clear":
-		return d.handleClear(req.Target)
+	case "record":
+		return d.handleRecord(req)
+	case "monitor":
+		return d.handleMonitor(req)
+	case "pool":
+		return d.handlePool(req)
+	case "mock":
+		return d.handleMock(req)
+	case "abort":
+		return d.handleAbort(req)
+	case "rewrite":
+		return d.handleRewrite(req)
 	case "cdp":
 		return d.handleCDP(req)
 	case "navigate":
 		return d.handleNavigate(req)
 	case "reload":
 		return d.handleReload(req)
+	case "history":
+		return d.handleHistory(req)
 	case "back":
 		return d.handleBack(req)
 	case "forward":
 		return d.handleForward(req)
 	case "ready":
 		return d.handleReady(req)
+	case "wait-request":
+		return d.handleWaitRequest(req)
 	case "click":
 		return d.handleClick(req)
 	case "focus":
 		return d.handleFocus(req)
+	case "highlight":
+		return d.handleHighlight(req)
+	case "pick":
+		return d.handlePick(req)
+	case "box":
+		return d.handleBox(req)
+	case "attr":
+		return d.handleAttr(req)
+	case "count":
+		return d.handleCount(req)
+	case "elements":
+		return d.handleElements(req)
+	case "selector":
+		return d.handleSelector(req)
+	case "dom":
+		return d.handleDOM(req)
+	case "describe":
+		return d.handleDescribe(req)
+	case "meta":
+		return d.handleMeta()
+	case "frames":
+		return d.handleFrames()
+	case "watch-dom":
+		return d.handleWatchDOM(req)
+	case "perf":
+		return d.handlePerf(req)
+	case "heap":
+		return d.handleHeap(req)
+	case "bench":
+		return d.handleBench(req)
+	case "cache":
+		return d.handleCache(req)
+	case "throttle":
+		return d.handleThrottle(req)
+	case "emulate":
+		return d.handleEmulate(req)
+	case "devtools":
+		return d.handleDevTools(req)
+	case "zoom":
+		return d.handleZoom(req)
+	case "dismiss-banners":
+		return d.handleDismissBanners(req)
 	case "type":
 		return d.handleType(req)
 	case "key":
 		return d.handleKey(req)
 	case "select":
 		return d.handleSelect(req)
+	case "check":
+		return d.handleCheck(req)
 	case "scroll":
 		return d.handleScroll(req)
+	case "mouse":
+		return d.handleMouse(req)
 	case "eval":
 		return d.handleEval(req)
 	case "cookies":
 		return d.handleCookies(req)
+	case "extensions":
+		return d.handleExtensions(req)
+	case "context":
+		return d.handleContext(req)
 	case "find":
 		return d.handleFind(req)
 	case "css":
 		return d.handleCSS(req)
 	case "serve":
 		return d.handleServe(req)
-	case "shutdown":
-		return d.handleShutdown()
+	case "source":
+		return d.handleSource(req)
+	case "logpoint":
+		return d.handleLogpoint(req)
+	case "exceptions":
+		return d.handleExceptions(req)
 	default:
-		return ipc.ErrorResponse(fmt.Sprintf("unknown command: %s", req.Cmd))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown command: %s", req.Cmd))
 	}
 }
 
@@ -571,3 +926,32 @@ func (d *Daemon) writePIDFile() error {
 func (d *Daemon) removePIDFile() {
 	_ = os.Remove(d.config.PIDPath)
 }
+
+// writeStateFile records the daemon and its browser in the state file. Failure
+// only costs 'start --reuse' after a crash, so it is logged, not returned.
+func (d *Daemon) writeStateFile(endpoint string) {
+	if d.config.StatePath == "" {
+		return
+	}
+	err := ipc.WriteState(d.config.StatePath, ipc.State{
+		PID:         os.Getpid(),
+		BrowserPID:  d.browser.PID(),
+		Port:        d.browser.Port(),
+		Endpoint:    endpoint,
+		UserDataDir: d.browser.DataDir(),
+		TempProfile: d.browser.TempProfile(),
+		Adopted:     d.browser.Adopted(),
+		Headless:    d.config.Headless,
+		LaunchFlags: d.config.Launch,
+	})
+	if err != nil {
+		d.log.Warn("failed to write state file", "path", d.config.StatePath, "error", err)
+	}
+}
+
+// removeStateFile removes the state file.
+func (d *Daemon) removeStateFile() {
+	if d.config.StatePath != "" {
+		_ = os.Remove(d.config.StatePath)
+	}
+}
@@ -752,6 +752,17 @@ func TestDaemon_updateResponseEvent(t *testing.T) {
 			"fromPrefetchCache": false,
 			"connectionId":      float64(17),
 			"securityState":     "secure",
+			"securityDetails": map[string]any{
+				"protocol":         "TLS 1.3",
+				"keyExchange":      "",
+				"keyExchangeGroup": "X25519",
+				"cipher":           "AES_128_GCM",
+				"subjectName":      "example.com",
+				"sanList":          []string{"example.com", "www.example.com"},
+				"issuer":           "R11",
+				"validFrom":        1750000000.0,
+				"validTo":          1760000000.0,
+			},
 			"timing": map[string]any{
 				"dnsStart":          1.0,
 				"dnsEnd":            6.0,
@@ -814,6 +825,12 @@ func TestDaemon_updateResponseEvent(t *testing.T) {
 	if entry.SecurityState != "secure" {
 		t.Errorf("SecurityState = %q, want 'secure'", entry.SecurityState)
 	}
+	if sec := entry.Security; sec == nil {
+		t.Error("Security should be populated")
+	} else if sec.Protocol != "TLS 1.3" || sec.KeyExchange != "X25519" || sec.Issuer != "R11" ||
+		len(sec.SANs) != 2 || sec.ValidTo != 1760000000000 {
+		t.Errorf("Security = %+v", sec)
+	}
 	if entry.Timing == nil {
 		t.Fatal("Timing should be populated")
 	}
@@ -887,6 +904,41 @@ func TestDeriveNetworkTiming(t *testing.T) {
 	})
 }
 
+func TestDaemon_cacheAndPriorityEvents(t *testing.T) {
+	d := New(DefaultConfig())
+
+	params, _ := json.Marshal(map[string]any{
+		"requestId": "req-1",
+		"wallTime":  float64(time.Now().Unix()),
+		"request": map[string]any{
+			"url":             "https://example.com/app.js",
+			"method":          "GET",
+			"initialPriority": "Low",
+		},
+		"type": "Script",
+	})
+	entry, ok := d.parseRequestEvent(cdp.Event{Method: "Network.requestWillBeSent", Params: params})
+	if !ok {
+		t.Fatal("parseRequestEvent returned false")
+	}
+	if entry.Priority != "Low" {
+		t.Errorf("Priority = %q, want Low", entry.Priority)
+	}
+	d.networkBuf.Push(entry)
+	d.networkBuf.Push(ipc.NetworkEntry{RequestID: "req-2", URL: "https://example.com/other.js"})
+
+	d.handleResourceChangedPriority(cdp.Event{Params: json.RawMessage(`{"requestId":"req-1","newPriority":"High"}`)})
+	d.handleRequestServedFromCache(cdp.Event{Params: json.RawMessage(`{"requestId":"req-1"}`)})
+
+	entries := d.networkBuf.All()
+	if entries[0].Priority != "High" || !entries[0].FromMemoryCache {
+		t.Errorf("entry = %+v, want priority High served from memory cache", entries[0])
+	}
+	if entries[1].Priority != "" || entries[1].FromMemoryCache {
+		t.Errorf("unrelated entry changed: %+v", entries[1])
+	}
+}
+
 func TestDaemon_handleLoadingFailed(t *testing.T) {
 	t.Run("network error", func(t *testing.T) {
 		d := New(DefaultConfig())
@@ -1142,3 +1194,27 @@ func (m *sessionCapturingMockConn) getCapturedRequests() []cdp.Request {
 	copy(result, m.requests)
 	return result
 }
+
+func TestDaemon_handleRequest_errorCodes(t *testing.T) {
+	d := New(DefaultConfig())
+
+	tests := []struct {
+		req  ipc.Request
+		want string
+	}{
+		{ipc.Request{Cmd: "nope"}, ipc.CodeInvalidArgs},
+		{ipc.Request{Cmd: "clear", Target: "bogus"}, ipc.CodeInvalidArgs},
+		{ipc.Request{Cmd: "clear", Params: json.RawMessage(`{"session":1}`)}, ipc.CodeInvalidArgs},
+		{ipc.Request{Cmd: "clear", Target: "console", Params: json.RawMessage(`{"session":"zzz"}`)}, ipc.CodeNotFound},
+	}
+	for _, tt := range tests {
+		resp := d.handleRequest(tt.req)
+		if resp.OK {
+			t.Errorf("%s %s: unexpected success", tt.req.Cmd, tt.req.Target)
+			continue
+		}
+		if resp.Code != tt.want {
+			t.Errorf("%s %s: code = %q (%s), want %q", tt.req.Cmd, tt.req.Target, resp.Code, resp.Error, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+)
+
+// The Debugger domain is shared by 'webctl logpoint', which sets conditional
+// breakpoints, and 'webctl exceptions', which pauses on exceptions to capture
+// them. It is enabled in every tab while either needs it, and each change
+// resets the domain in every tab and sets everything again, as
+// syncInterception re-applies every Fetch pattern. 'webctl source' enables
+// the domain on its own and calls applyDebugger when done.
+
+// debuggerPausedParams is the subset of a Debugger.paused event used to
+// capture exceptions.
+type debuggerPausedParams struct {
+	Reason     string              `json:"reason"`
+	Data       json.RawMessage     `json:"data"`
+	CallFrames []debuggerCallFrame `json:"callFrames"`
+}
+
+// debuggerCallFrame is a paused call frame: where it is, and its scopes.
+type debuggerCallFrame struct {
+	FunctionName string `json:"functionName"`
+	URL          string `json:"url"`
+	Location     struct {
+		LineNumber   int `json:"lineNumber"`
+		ColumnNumber int `json:"columnNumber"`
+	} `json:"location"`
+	ScopeChain []struct {
+		Type   string `json:"type"`
+		Object struct {
+			ObjectID string `json:"objectId"`
+		} `json:"object"`
+	} `json:"scopeChain"`
+}
+
+// debuggerNeeded reports whether any logpoint or exception pause mode needs
+// the Debugger domain on.
+func (d *Daemon) debuggerNeeded() bool {
+	return len(d.logpointList()) > 0 || d.exceptionPauseState() != "none"
+}
+
+// syncDebugger sets up the Debugger domain in every tab for the current
+// logpoints and exception pause mode, and returns how many loaded script
+// locations each logpoint bound to, by logpoint ID.
+func (d *Daemon) syncDebugger() (map[string]int, error) {
+	locations := map[string]int{}
+	for _, s := range d.sessions.All() {
+		bound, err := d.applyDebugger(s.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update the debugger: %v", err)
+		}
+		for id, n := range bound {
+			locations[id] += n
+		}
+	}
+	return locations, nil
+}
+
+// applyDebugger resets the Debugger domain in a session and, when logpoints
+// or the exception pause mode need it, enables it, sets the pause mode, and
+// sets the logpoints, returning the script locations each bound to.
+// Disabling the domain drops every breakpoint, so "source" calls this when
+// done to restore what it cleared.
+func (d *Daemon) applyDebugger(sessionID string) (map[string]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := d.sendToSession(ctx, sessionID, "Debugger.disable", nil); err != nil {
+		return nil, err
+	}
+	if !d.debuggerNeeded() {
+		return nil, nil
+	}
+	if _, err := d.sendToSession(ctx, sessionID, "Debugger.enable", nil); err != nil {
+		return nil, err
+	}
+	if state := d.exceptionPauseState(); state != "none" {
+		if _, err := d.sendToSession(ctx, sessionID, "Debugger.setPauseOnExceptions", map[string]any{"state": state}); err != nil {
+			return nil, fmt.Errorf("failed to pause on exceptions: %v", err)
+		}
+	}
+
+	logpoints := d.logpointList()
+	bound := make(map[string]int, len(logpoints))
+	for _, lp := range logpoints {
+		params := map[string]any{
+			"urlRegex":   logpointURLRegex(lp.URL),
+			"lineNumber": lp.Line - 1,
+			"condition":  fmt.Sprintf(logpointCondition, lp.Expression),
+		}
+		if lp.Column > 0 {
+			params["columnNumber"] = lp.Column - 1
+		}
+		result, err := d.sendToSession(ctx, sessionID, "Debugger.setBreakpointByUrl", params)
+		if err != nil {
+			return nil, fmt.Errorf("logpoint %s: %v", lp.ID, err)
+		}
+		var resp struct {
+			Locations []json.RawMessage `json:"locations"`
+		}
+		if err := json.Unmarshal(result, &resp); err == nil {
+			bound[lp.ID] = len(resp.Locations)
+		}
+	}
+	return bound, nil
+}
+
+// handleDebuggerPaused captures an exception the page paused on, when
+// 'exceptions pause' asked for it, and resumes the page. Logpoint conditions
+// never pause, but with the domain enabled a debugger statement in the page
+// would freeze it, so every pause is resumed. Called on the CDP read loop;
+// must not block.
+func (d *Daemon) handleDebuggerPaused(evt cdp.Event) {
+	var params debuggerPausedParams
+	if err := json.Unmarshal(evt.Params, &params); err != nil {
+		return
+	}
+	client := d.cdp
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if isExceptionPause(params.Reason) && d.exceptionPauseState() != "none" {
+			d.capturePausedException(ctx, client, evt.SessionID, params)
+		}
+		if _, err := client.SendToSession(ctx, evt.SessionID, "Debugger.resume", nil); err != nil {
+			d.log.Debug("Debugger.resume failed", "session", evt.SessionID, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// handleDevTools handles the "devtools" command: it opens a DevTools window
+// for the active tab, which needs a visible browser.
+func (d *Daemon) handleDevTools(req ipc.Request) ipc.Response {
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+	if d.config.Headless {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "the browser is headless, so there is no window for DevTools; switch with: webctl head")
+	}
+	return d.openDevTools(activeID)
+}
+
+// openDevTools opens a DevTools window inspecting a session's page.
+// Target.openDevTools is new in Chrome 129; with an older browser the error
+// names the inspector URL to open by hand instead.
+func (d *Daemon) openDevTools(sessionID string) ipc.Response {
+	s := d.sessions.Get(sessionID)
+	targetID := d.sessions.TargetID(sessionID)
+	if s == nil || targetID == "" {
+		return d.noActiveSessionError()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := d.cdp.SendContext(ctx, "Target.openDevTools", map[string]any{"targetId": targetID}); err != nil {
+		msg := fmt.Sprintf("failed to open DevTools: %v", err)
+		if d.browser != nil {
+			port := d.browser.Port()
+			msg += fmt.Sprintf(" (open http://127.0.0.1:%d/devtools/inspector.html?ws=127.0.0.1:%d/devtools/page/%s instead)", port, port, targetID)
+		}
+		return ipc.ErrorResponse(msg)
+	}
+	d.log.Info("devtools opened", "target", targetID, "url", s.URL)
+	return ipc.SuccessResponse(ipc.DevToolsData{TargetID: targetID, URL: s.URL})
+}
@@ -0,0 +1,34 @@
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestOpenDevTools(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://app.test/", "App")
+
+	resp := d.openDevTools("AAA1")
+	if !resp.OK {
+		t.Fatalf("openDevTools = %+v", resp)
+	}
+	var data ipc.DevToolsData
+	if err := json.Unmarshal(resp.Data, &data); err != nil || data.TargetID != "T1" || data.URL != "https://app.test/" {
+		t.Errorf("data = %s, %v", resp.Data, err)
+	}
+	reqs := conn.getCapturedRequests()
+	if len(reqs) != 1 || reqs[0].Method != "Target.openDevTools" || reqs[0].SessionID != "" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	if params, _ := reqs[0].Params.(map[string]any); params["targetId"] != "T1" {
+		t.Errorf("params = %v", reqs[0].Params)
+	}
+}
@@ -0,0 +1,199 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// batteryJS replaces navigator.getBattery() with a battery in the given
+// state. Run again in a page that has it, it updates the state and fires the
+// change events a real battery would. Format arg: the state as JSON.
+const batteryJS = `(() => {
+  const next = %s;
+  const key = Symbol.for('webctl.battery');
+  if (window[key]) {
+    window[key].update(next);
+    return;
+  }
+
+  let state = next;
+  const battery = new EventTarget();
+  const handlers = {};
+  for (const type of ['chargingchange', 'chargingtimechange', 'dischargingtimechange', 'levelchange']) {
+    Object.defineProperty(battery, 'on' + type, {
+      get: () => handlers[type] || null,
+      set: (fn) => {
+        if (handlers[type]) battery.removeEventListener(type, handlers[type]);
+        handlers[type] = typeof fn === 'function' ? fn : null;
+        if (handlers[type]) battery.addEventListener(type, handlers[type]);
+      },
+    });
+  }
+  Object.defineProperties(battery, {
+    level: { get: () => state.level },
+    charging: { get: () => state.charging },
+    chargingTime: { get: () => (state.charging && state.level >= 1 ? 0 : Infinity) },
+    dischargingTime: { get: () => Infinity },
+  });
+  if (window.BatteryManager) {
+    Object.setPrototypeOf(battery, BatteryManager.prototype);
+  }
+
+  const original = Object.getOwnPropertyDescriptor(Navigator.prototype, 'getBattery');
+  Object.defineProperty(Navigator.prototype, 'getBattery', {
+    value: function getBattery() { return Promise.resolve(battery); },
+    configurable: true,
+    writable: true,
+  });
+  Object.defineProperty(window, key, {
+    configurable: true,
+    value: {
+      update: (s) => {
+        const prev = state;
+        state = s;
+        if (prev.charging !== s.charging) {
+          battery.dispatchEvent(new Event('chargingchange'));
+          battery.dispatchEvent(new Event('chargingtimechange'));
+        }
+        if (prev.level !== s.level) {
+          battery.dispatchEvent(new Event('levelchange'));
+        }
+      },
+      restore: () => {
+        if (original) {
+          Object.defineProperty(Navigator.prototype, 'getBattery', original);
+        } else {
+          delete Navigator.prototype.getBattery;
+        }
+        delete window[key];
+      },
+    },
+  });
+})()`
+
+// batteryRestoreJS puts back the page's own navigator.getBattery().
+const batteryRestoreJS = `(() => {
+  const b = window[Symbol.for('webctl.battery')];
+  if (b) b.restore();
+})()`
+
+// handleEmulate handles the "emulate" command: it overrides the idle state or
+// the battery the active tab's page sees, or clears the override.
+func (d *Daemon) handleEmulate(req ipc.Request) ipc.Response {
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.EmulateParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid emulate parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "idle":
+		return d.emulateIdle(activeID, params.Idle)
+	case "battery":
+		return d.emulateBattery(activeID, params.Battery)
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown emulate action: %s", params.Action))
+	}
+}
+
+// emulateIdle overrides the user and screen state the Idle Detection API
+// reports in a session, or clears the override for a nil state. So a page can
+// use the API without a prompt, idle detection is granted to its origin.
+func (d *Daemon) emulateIdle(sessionID string, idle *ipc.IdleState) ipc.Response {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if idle == nil {
+		if _, err := d.sendToSession(ctx, sessionID, "Emulation.clearIdleOverride", nil); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to clear the idle state: %v", err))
+		}
+		d.log.Info("idle override cleared")
+		return ipc.SuccessResponse(ipc.EmulateData{})
+	}
+
+	if s := d.sessions.Get(sessionID); s != nil {
+		if origin, err := pageOrigin(s.URL); err == nil {
+			grant := map[string]any{"permissions": []string{"idleDetection"}, "origin": origin}
+			if s.Context != "" {
+				grant["browserContextId"] = s.Context
+			}
+			if _, err := d.cdp.SendContext(ctx, "Browser.grantPermissions", grant); err != nil {
+				d.log.Debug("emulate idle: failed to grant idle detection", "origin", origin, "error", err)
+			}
+		}
+	}
+	if _, err := d.sendToSession(ctx, sessionID, "Emulation.setIdleOverride", map[string]any{
+		"isUserActive":     !idle.UserIdle,
+		"isScreenUnlocked": !idle.ScreenLocked,
+	}); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to set the idle state: %v", err))
+	}
+	d.log.Info("idle override set", "userIdle", idle.UserIdle, "screenLocked", idle.ScreenLocked)
+	return ipc.SuccessResponse(ipc.EmulateData{Idle: idle})
+}
+
+// emulateBattery installs a battery in a given state in the page now and on
+// every later load in the session, or restores the page's own for a nil
+// state.
+func (d *Daemon) emulateBattery(sessionID string, battery *ipc.BatteryState) ipc.Response {
+	if battery != nil && (battery.Level < 0 || battery.Level > 1) {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("battery level %g out of range (0 to 1)", battery.Level))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	js := batteryRestoreJS
+	scriptID := ""
+	if battery != nil {
+		state, err := json.Marshal(battery)
+		if err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		js = fmt.Sprintf(batteryJS, state)
+		result, err := d.sendToSession(ctx, sessionID, "Page.addScriptToEvaluateOnNewDocument", map[string]any{
+			"source": js,
+		})
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to emulate the battery: %v", err))
+		}
+		var resp struct {
+			Identifier string `json:"identifier"`
+		}
+		if err := json.Unmarshal(result, &resp); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to parse battery script: %v", err))
+		}
+		scriptID = resp.Identifier
+	}
+	if previous := d.sessions.SwapBatteryScript(sessionID, scriptID); previous != "" {
+		if _, err := d.sendToSession(ctx, sessionID, "Page.removeScriptToEvaluateOnNewDocument", map[string]any{
+			"identifier": previous,
+		}); err != nil {
+			d.log.Debug("emulate battery: failed to remove previous script", "error", err)
+		}
+	}
+
+	if _, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+		"expression": js,
+	}); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to emulate the battery: %v", err))
+	}
+	if battery == nil {
+		d.log.Info("battery override cleared")
+	} else {
+		d.log.Info("battery emulated", "level", battery.Level, "charging", battery.Charging)
+	}
+	return ipc.SuccessResponse(ipc.EmulateData{Battery: battery})
+}
@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestEmulateIdle(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://app.test/chat", "Chat")
+
+	if resp := d.emulateIdle("AAA1", &ipc.IdleState{UserIdle: true}); !resp.OK {
+		t.Fatalf("emulateIdle = %+v", resp)
+	}
+	var methods []string
+	for _, req := range conn.getCapturedRequests() {
+		methods = append(methods, req.Method)
+		params, _ := req.Params.(map[string]any)
+		switch req.Method {
+		case "Browser.grantPermissions":
+			if params["origin"] != "https://app.test" || req.SessionID != "" {
+				t.Errorf("grantPermissions params = %v, session %q", params, req.SessionID)
+			}
+		case "Emulation.setIdleOverride":
+			if params["isUserActive"] != false || params["isScreenUnlocked"] != true {
+				t.Errorf("setIdleOverride params = %v", params)
+			}
+		}
+	}
+	if got := strings.Join(methods, " "); got != "Browser.grantPermissions Emulation.setIdleOverride" {
+		t.Errorf("methods = %s", got)
+	}
+
+	if resp := d.emulateIdle("AAA1", nil); !resp.OK {
+		t.Fatalf("emulateIdle(nil) = %+v", resp)
+	}
+	reqs := conn.getCapturedRequests()
+	if last := reqs[len(reqs)-1]; last.Method != "Emulation.clearIdleOverride" {
+		t.Errorf("clear sent %s", last.Method)
+	}
+}
+
+func TestEmulateBattery(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://app.test/", "App")
+
+	if resp := d.emulateBattery("AAA1", &ipc.BatteryState{Level: 0.15}); !resp.OK {
+		t.Fatalf("emulateBattery = %+v", resp)
+	}
+	var methods []string
+	for _, req := range conn.getCapturedRequests() {
+		methods = append(methods, req.Method)
+		params, _ := req.Params.(map[string]any)
+		if req.Method == "Runtime.evaluate" && !strings.Contains(params["expression"].(string), `const next = {"level":0.15,"charging":false};`) {
+			t.Errorf("evaluate did not install the battery state:\n%v", params["expression"])
+		}
+	}
+	if got := strings.Join(methods, " "); got != "Page.addScriptToEvaluateOnNewDocument Runtime.evaluate" {
+		t.Errorf("methods = %s", got)
+	}
+
+	// Clearing removes the load script and restores the page's own battery
+	d.sessions.SwapBatteryScript("AAA1", "9")
+	before := len(conn.getCapturedRequests())
+	if resp := d.emulateBattery("AAA1", nil); !resp.OK {
+		t.Fatalf("emulateBattery(nil) = %+v", resp)
+	}
+	methods = nil
+	for _, req := range conn.getCapturedRequests()[before:] {
+		methods = append(methods, req.Method)
+		if params, _ := req.Params.(map[string]any); req.Method == "Page.removeScriptToEvaluateOnNewDocument" && params["identifier"] != "9" {
+			t.Errorf("removed script params = %v", params)
+		}
+	}
+	if got := strings.Join(methods, " "); got != "Page.removeScriptToEvaluateOnNewDocument Runtime.evaluate" {
+		t.Errorf("clear methods = %s", got)
+	}
+
+	if resp := d.emulateBattery("AAA1", &ipc.BatteryState{Level: 1.5}); resp.OK || resp.Code != ipc.CodeInvalidArgs {
+		t.Errorf("level 1.5 = %+v", resp)
+	}
+}
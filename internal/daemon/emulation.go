@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Emulation is the device and network emulation applied to every page session
+// as it is attached. The zero value emulates nothing.
+type Emulation struct {
+	// Width and Height override the viewport in CSS pixels. Zero leaves the
+	// window's own size.
+	Width, Height int
+	// UserAgent overrides the browser's user agent string.
+	UserAgent string
+	// Throttle is a network profile name from ThrottleProfiles.
+	Throttle string
+	// Stealth hides the signs of an automated browser that bot detection
+	// looks for (see applyStealth).
+	Stealth bool
+}
+
+// NetworkConditions are the Network.emulateNetworkConditions parameters for a
+// throttling profile. Throughput is in bytes per second.
+type NetworkConditions struct {
+	Offline            bool    `json:"offline"`
+	Latency            float64 `json:"latency"`
+	DownloadThroughput float64 `json:"downloadThroughput"`
+	UploadThroughput   float64 `json:"uploadThroughput"`
+}
+
+// ThrottleProfiles are the network throttling presets, matching Chrome
+// DevTools' presets of the same names.
+var ThrottleProfiles = map[string]NetworkConditions{
+	"fast-4g": {Latency: 165, DownloadThroughput: 9_000_000 / 8 * 0.9, UploadThroughput: 1_500_000 / 8 * 0.9},
+	"slow-4g": {Latency: 562.5, DownloadThroughput: 1_600_000 / 8 * 0.9, UploadThroughput: 750_000 / 8 * 0.9},
+	"3g":      {Latency: 2000, DownloadThroughput: 500_000 / 8 * 0.8, UploadThroughput: 500_000 / 8 * 0.8},
+	"offline": {Offline: true},
+}
+
+// ThrottleProfileNames returns the profile names, sorted.
+func ThrottleProfileNames() []string {
+	names := make([]string, 0, len(ThrottleProfiles))
+	for name := range ThrottleProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseViewport parses a "WIDTHxHEIGHT" viewport size.
+func ParseViewport(s string) (width, height int, err error) {
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	if ok {
+		width, err = strconv.Atoi(w)
+		if err == nil {
+			height, err = strconv.Atoi(h)
+		}
+	}
+	if !ok || err != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid viewport %q (use WIDTHxHEIGHT, e.g. 390x844)", s)
+	}
+	return width, height, nil
+}
+
+// Validate reports an unknown throttle profile or a half-specified viewport.
+func (e Emulation) Validate() error {
+	if e.Throttle != "" {
+		if _, ok := ThrottleProfiles[e.Throttle]; !ok {
+			return fmt.Errorf("unknown throttle profile %q (use %s)", e.Throttle, strings.Join(ThrottleProfileNames(), ", "))
+		}
+	}
+	if (e.Width > 0) != (e.Height > 0) {
+		return fmt.Errorf("viewport needs both width and height")
+	}
+	return nil
+}
+
+// applyEmulation sends the configured emulation overrides to a session.
+func (d *Daemon) applyEmulation(sessionID string) error {
+	e := d.config.Emulation
+	ctx := context.Background()
+
+	if e.Width > 0 && e.Height > 0 {
+		if _, err := d.cdp.SendToSession(ctx, sessionID, "Emulation.setDeviceMetricsOverride", map[string]any{
+			"width":             e.Width,
+			"height":            e.Height,
+			"deviceScaleFactor": 0,
+			"mobile":            false,
+		}); err != nil {
+			return fmt.Errorf("failed to set viewport: %w", err)
+		}
+	}
+	if e.UserAgent != "" {
+		if _, err := d.cdp.SendToSession(ctx, sessionID, "Emulation.setUserAgentOverride", map[string]any{
+			"userAgent": e.UserAgent,
+		}); err != nil {
+			return fmt.Errorf("failed to set user agent: %w", err)
+		}
+	}
+	if conditions, ok := ThrottleProfiles[e.Throttle]; ok {
+		if _, err := d.cdp.SendToSession(ctx, sessionID, "Network.emulateNetworkConditions", conditions); err != nil {
+			return fmt.Errorf("failed to throttle network: %w", err)
+		}
+	}
+	if e.Stealth {
+		return d.applyStealth(ctx, sessionID)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseViewport(t *testing.T) {
+	tests := []struct {
+		in     string
+		w, h   int
+		hasErr bool
+	}{
+		{"390x844", 390, 844, false},
+		{"1280X720", 1280, 720, false},
+		{"390", 0, 0, true},
+		{"0x844", 0, 0, true},
+		{"wide x tall", 0, 0, true},
+	}
+	for _, tt := range tests {
+		w, h, err := ParseViewport(tt.in)
+		if (err != nil) != tt.hasErr || w != tt.w || h != tt.h {
+			t.Errorf("ParseViewport(%q) = %d, %d, %v", tt.in, w, h, err)
+		}
+	}
+}
+
+func TestEmulationValidate(t *testing.T) {
+	if err := (Emulation{Throttle: "slow-4g", Width: 390, Height: 844}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (Emulation{Throttle: "dial-up"}).Validate(); err == nil {
+		t.Error("expected error for unknown throttle profile")
+	}
+	if err := (Emulation{Width: 390}).Validate(); err == nil {
+		t.Error("expected error for viewport without height")
+	}
+}
+
+func TestStealthUserAgent(t *testing.T) {
+	ua := "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/126.0.6478.126 Safari/537.36"
+	got := stealthUserAgent(ua, "HeadlessChrome/126.0.6478.126", "linux", "amd64")
+
+	want := "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.6478.126 Safari/537.36"
+	if got["userAgent"] != want {
+		t.Errorf("userAgent = %q, want %q", got["userAgent"], want)
+	}
+	if got["platform"] != "Linux x86_64" {
+		t.Errorf("platform = %q", got["platform"])
+	}
+	meta := got["userAgentMetadata"].(map[string]any)
+	if meta["platform"] != "Linux" || meta["architecture"] != "x86" || meta["fullVersion"] != "126.0.6478.126" {
+		t.Errorf("userAgentMetadata = %v", meta)
+	}
+	brands := meta["brands"].([]map[string]string)
+	for _, b := range brands {
+		if strings.Contains(b["brand"], "Headless") {
+			t.Errorf("brand %q gives away headless", b["brand"])
+		}
+		if b["brand"] == "Google Chrome" && b["version"] != "126" {
+			t.Errorf("Google Chrome brand version = %q, want 126", b["version"])
+		}
+	}
+
+	mac := stealthUserAgent(want, "Chrome/126.0.6478.126", "darwin", "arm64")
+	macMeta := mac["userAgentMetadata"].(map[string]any)
+	if mac["platform"] != "MacIntel" || macMeta["platform"] != "macOS" || macMeta["architecture"] != "arm" {
+		t.Errorf("darwin/arm64 = %v, %v", mac["platform"], macMeta)
+	}
+}
+
+func TestLaunchOptions_Stealth(t *testing.T) {
+	cfg := DefaultConfig()
+	flags := make([]string, 1, 4)
+	flags[0] = "--mute-audio"
+	cfg.Launch.ChromeFlags = flags
+	cfg.Emulation.Stealth = true
+	d := New(cfg)
+
+	opts := d.launchOptions(true)
+	if len(opts.ExtraFlags) != 2 || opts.ExtraFlags[0] != "--mute-audio" || opts.ExtraFlags[1] != stealthChromeFlag {
+		t.Errorf("ExtraFlags = %v, want --mute-audio then %s", opts.ExtraFlags, stealthChromeFlag)
+	}
+	if got := d.config.Launch.ChromeFlags; len(got) != 1 || flags[:2][1] != "" {
+		t.Errorf("launchOptions modified the configured flags: %v", got)
+	}
+
+	d.config.Emulation.Stealth = false
+	if opts := d.launchOptions(true); len(opts.ExtraFlags) != 1 {
+		t.Errorf("ExtraFlags without stealth = %v", opts.ExtraFlags)
+	}
+}
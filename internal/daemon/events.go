@@ -60,8 +60,12 @@ func (d *Daemon) subscribeEvents() {
 		if entry, ok := d.parseRequestEvent(evt); ok {
 			entry.SessionID = evt.SessionID
 			awaiting := entry.AwaitingRequestBody()
+			if awaiting && !d.shouldCapture(headerValue(entry.RequestHeaders, "Content-Type"), entry.URL, 0) {
+				entry.ClearAwaitingRequestBody()
+				awaiting = false
+			}
 			d.networkBuf.Push(entry)
-			d.debugf(false, "Network.requestWillBeSent: requestId=%s, url=%s, type=%s", entry.RequestID, entry.URL, entry.Type)
+			d.log.Debug("Network.requestWillBeSent", "requestId", entry.RequestID, "url", entry.URL, "type", entry.Type)
 			// Body advertised but omitted from the event (exceeds maxPostDataSize):
 			// fetch it off the read loop, like the response body in handleLoadingFinished.
 			if awaiting {
@@ -77,17 +81,25 @@ func (d *Daemon) subscribeEvents() {
 			Type      string `json:"type"`
 		}
 		if err := json.Unmarshal(evt.Params, &params); err == nil {
-			d.debugf(false, "Network.responseReceived: requestId=%s, type=%s", params.RequestID, params.Type)
+			d.log.Debug("Network.responseReceived", "requestId", params.RequestID, "type", params.Type)
 		}
 	})
 
+	d.cdp.Subscribe("Network.requestServedFromCache", func(evt cdp.Event) {
+		d.handleRequestServedFromCache(evt)
+	})
+
+	d.cdp.Subscribe("Network.resourceChangedPriority", func(evt cdp.Event) {
+		d.handleResourceChangedPriority(evt)
+	})
+
 	d.cdp.Subscribe("Network.loadingFinished", func(evt cdp.Event) {
 		d.handleLoadingFinished(evt)
 		var params struct {
 			RequestID string `json:"requestId"`
 		}
 		if err := json.Unmarshal(evt.Params, &params); err == nil {
-			d.debugf(false, "Network.loadingFinished: requestId=%s", params.RequestID)
+			d.log.Debug("Network.loadingFinished", "requestId", params.RequestID)
 		}
 	})
 
@@ -97,10 +109,68 @@ func (d *Daemon) subscribeEvents() {
 			RequestID string `json:"requestId"`
 		}
 		if err := json.Unmarshal(evt.Params, &params); err == nil {
-			d.debugf(false, "Network.loadingFailed: requestId=%s", params.RequestID)
+			d.log.Debug("Network.loadingFailed", "requestId", params.RequestID)
+		}
+	})
+
+	// Requests paused by 'webctl mock', 'network abort', and 'webctl rewrite' rules (Fetch enabled only while rules exist)
+	d.cdp.Subscribe("Fetch.requestPaused", func(evt cdp.Event) {
+		d.handleRequestPaused(evt)
+	})
+
+	// Overlay inspect mode: the user clicked an element during "pick".
+	d.cdp.Subscribe("Overlay.inspectNodeRequested", func(evt cdp.Event) {
+		var params struct {
+			BackendNodeID int `json:"backendNodeId"`
+		}
+		if err := json.Unmarshal(evt.Params, &params); err == nil {
+			d.handleInspectNodeRequested(evt.SessionID, params.BackendNodeID)
+		}
+	})
+
+	// Stylesheet tracking for "css list" and "css dump" (CSS domain enabled on demand)
+	d.cdp.Subscribe("CSS.styleSheetAdded", func(evt cdp.Event) {
+		var params struct {
+			Header styleSheetHeader `json:"header"`
+		}
+		if err := json.Unmarshal(evt.Params, &params); err == nil {
+			d.addStyleSheet(evt.SessionID, params.Header)
+		}
+	})
+
+	d.cdp.Subscribe("CSS.styleSheetRemoved", func(evt cdp.Event) {
+		var params struct {
+			StyleSheetID string `json:"styleSheetId"`
+		}
+		if err := json.Unmarshal(evt.Params, &params); err == nil {
+			d.removeStyleSheet(evt.SessionID, params.StyleSheetID)
 		}
 	})
 
+	// Script tracking for "source" (Debugger domain enabled on demand)
+	d.cdp.Subscribe("Debugger.scriptParsed", func(evt cdp.Event) {
+		var params scriptInfo
+		if err := json.Unmarshal(evt.Params, &params); err == nil {
+			d.addScript(evt.SessionID, params)
+		}
+	})
+
+	// Heap snapshot chunks for "heap", written to the snapshot being taken
+	d.cdp.Subscribe("HeapProfiler.addHeapSnapshotChunk", func(evt cdp.Event) {
+		var params struct {
+			Chunk string `json:"chunk"`
+		}
+		if err := json.Unmarshal(evt.Params, &params); err == nil {
+			d.addHeapSnapshotChunk(evt.SessionID, params.Chunk)
+		}
+	})
+
+	// Logpoints and 'exceptions pause' keep the Debugger domain on: capture
+	// exceptions and never leave the page paused
+	d.cdp.Subscribe("Debugger.paused", func(evt cdp.Event) {
+		d.handleDebuggerPaused(evt)
+	})
+
 	// Page navigation events for navigation commands
 	d.cdp.Subscribe("Page.frameNavigated", func(evt cdp.Event) {
 		d.handleFrameNavigated(evt)
@@ -116,11 +186,11 @@ func (d *Daemon) subscribeEvents() {
 
 	// Debug: Additional Page events
 	d.cdp.Subscribe("Page.frameStartedLoading", func(evt cdp.Event) {
-		d.debugf(false, "Page.frameStartedLoading: sessionID=%s", evt.SessionID)
+		d.log.Debug("Page.frameStartedLoading", "session", evt.SessionID)
 	})
 
 	d.cdp.Subscribe("Page.frameStoppedLoading", func(evt cdp.Event) {
-		d.debugf(false, "Page.frameStoppedLoading: sessionID=%s", evt.SessionID)
+		d.log.Debug("Page.frameStoppedLoading", "session", evt.SessionID)
 	})
 
 	d.cdp.Subscribe("Page.lifecycleEvent", func(evt cdp.Event) {
@@ -128,20 +198,25 @@ func (d *Daemon) subscribeEvents() {
 			Name string `json:"name"`
 		}
 		if err := json.Unmarshal(evt.Params, &params); err == nil {
-			d.debugf(false, "Page.lifecycleEvent: name=%s, sessionID=%s", params.Name, evt.SessionID)
+			d.log.Debug("Page.lifecycleEvent", "name", params.Name, "session", evt.SessionID)
 		}
 	})
 
-	// Debug: Runtime execution context events
+	// Runtime execution context events, tracked to tag console entries with
+	// the frame that produced them
 	d.cdp.Subscribe("Runtime.executionContextCreated", func(evt cdp.Event) {
 		var params struct {
 			Context struct {
-				ID   int    `json:"id"`
-				Name string `json:"name"`
+				ID      int    `json:"id"`
+				Name    string `json:"name"`
+				AuxData struct {
+					FrameID string `json:"frameId"`
+				} `json:"auxData"`
 			} `json:"context"`
 		}
 		if err := json.Unmarshal(evt.Params, &params); err == nil {
-			d.debugf(false, "Runtime.executionContextCreated: contextId=%d, name=%s", params.Context.ID, params.Context.Name)
+			d.setContextFrame(evt.SessionID, params.Context.ID, params.Context.AuxData.FrameID)
+			d.log.Debug("Runtime.executionContextCreated", "contextId", params.Context.ID, "name", params.Context.Name, "frameId", params.Context.AuxData.FrameID)
 		}
 	})
 
@@ -150,17 +225,30 @@ func (d *Daemon) subscribeEvents() {
 			ExecutionContextID int `json:"executionContextId"`
 		}
 		if err := json.Unmarshal(evt.Params, &params); err == nil {
-			d.debugf(false, "Runtime.executionContextDestroyed: contextId=%d", params.ExecutionContextID)
+			d.setContextFrame(evt.SessionID, params.ExecutionContextID, "")
+			d.log.Debug("Runtime.executionContextDestroyed", "contextId", params.ExecutionContextID)
+		}
+	})
+
+	// Interactions reported by the 'record-flow' recorder script
+	d.cdp.Subscribe("Runtime.bindingCalled", func(evt cdp.Event) {
+		var params struct {
+			Name    string `json:"name"`
+			Payload string `json:"payload"`
+		}
+		if err := json.Unmarshal(evt.Params, &params); err == nil {
+			d.handleRecordBinding(evt.SessionID, params.Name, params.Payload)
 		}
 	})
 
 	d.cdp.Subscribe("Runtime.executionContextsCleared", func(evt cdp.Event) {
-		d.debugf(false, "Runtime.executionContextsCleared")
+		d.clearContextFrames(evt.SessionID)
+		d.log.Debug("Runtime.executionContextsCleared")
 	})
 
 	// Debug: DOM events
 	d.cdp.Subscribe("DOM.documentUpdated", func(evt cdp.Event) {
-		d.debugf(false, "DOM.documentUpdated: sessionID=%s", evt.SessionID)
+		d.log.Debug("DOM.documentUpdated", "session", evt.SessionID)
 	})
 }
 
@@ -302,10 +390,11 @@ func setSummaryLocator(entry *ipc.ConsoleEntry, fallbackURL string, fallbackLine
 // Returns the entry and true on success, or zero value and false on parse error.
 func (d *Daemon) parseConsoleEvent(evt cdp.Event) (ipc.ConsoleEntry, bool) {
 	var params struct {
-		Type       string            `json:"type"`
-		Timestamp  float64           `json:"timestamp"`
-		Args       []cdpRemoteObject `json:"args"`
-		StackTrace *cdpStackTrace    `json:"stackTrace"`
+		Type               string            `json:"type"`
+		Timestamp          float64           `json:"timestamp"`
+		Args               []cdpRemoteObject `json:"args"`
+		StackTrace         *cdpStackTrace    `json:"stackTrace"`
+		ExecutionContextID int               `json:"executionContextId"`
 	}
 	if err := json.Unmarshal(evt.Params, &params); err != nil {
 		return ipc.ConsoleEntry{}, false
@@ -315,6 +404,7 @@ func (d *Daemon) parseConsoleEvent(evt cdp.Event) (ipc.ConsoleEntry, bool) {
 		Type:      params.Type,
 		Timestamp: int64(params.Timestamp),
 		Stack:     flattenStack(params.StackTrace),
+		FrameID:   d.contextFrame(evt.SessionID, params.ExecutionContextID),
 	}
 
 	if len(params.Args) > 0 {
@@ -336,12 +426,13 @@ func (d *Daemon) parseExceptionEvent(evt cdp.Event) (ipc.ConsoleEntry, bool) {
 	var params struct {
 		Timestamp        float64 `json:"timestamp"`
 		ExceptionDetails struct {
-			Text       string           `json:"text"`
-			URL        string           `json:"url"`
-			Line       int              `json:"lineNumber"`
-			Column     int              `json:"columnNumber"`
-			StackTrace *cdpStackTrace   `json:"stackTrace"`
-			Exception  *cdpRemoteObject `json:"exception"`
+			Text               string           `json:"text"`
+			URL                string           `json:"url"`
+			Line               int              `json:"lineNumber"`
+			Column             int              `json:"columnNumber"`
+			StackTrace         *cdpStackTrace   `json:"stackTrace"`
+			Exception          *cdpRemoteObject `json:"exception"`
+			ExecutionContextID int              `json:"executionContextId"`
 		} `json:"exceptionDetails"`
 	}
 	if err := json.Unmarshal(evt.Params, &params); err != nil {
@@ -354,6 +445,7 @@ func (d *Daemon) parseExceptionEvent(evt cdp.Event) (ipc.ConsoleEntry, bool) {
 		Text:      ed.Text,
 		Timestamp: int64(params.Timestamp),
 		Stack:     flattenStack(ed.StackTrace),
+		FrameID:   d.contextFrame(evt.SessionID, ed.ExecutionContextID),
 	}
 
 	if ed.Exception != nil {
@@ -431,9 +523,22 @@ func (d *Daemon) parseLogEvent(evt cdp.Event) (ipc.ConsoleEntry, bool) {
 const networkMaxPostDataSize = ipc.DefaultMaxBodySize
 
 // networkEnableParams builds the Network.enable parameters shared by every
-// enable site, so the inline post-data cap cannot drift between them.
-func networkEnableParams() map[string]any {
-	return map[string]any{"maxPostDataSize": networkMaxPostDataSize}
+// enable site, so the inline post-data cap cannot drift between them. A
+// --max-capture-size beyond Chrome's per-response buffer raises the buffer,
+// so bodies within it are kept for fetching rather than dropped.
+func (d *Daemon) networkEnableParams() map[string]any {
+	params := map[string]any{"maxPostDataSize": networkMaxPostDataSize}
+	if size := d.config.BodyCapture.MaxSize; size > chromeResourceBufferSize {
+		params["maxResourceBufferSize"] = size
+		params["maxTotalBufferSize"] = max(10*chromeResourceBufferSize, 4*size)
+	}
+	return params
+}
+
+// shouldCapture reports whether to fetch and store a network body, by the
+// 'buffer set bodies' switch and the --capture-bodies policy.
+func (d *Daemon) shouldCapture(mimeType, url string, size int64) bool {
+	return d.captureBodies.Load() && d.config.BodyCapture.Allows(mimeType, url, size)
 }
 
 // parseRequestEvent parses a Network.requestWillBeSent event.
@@ -443,13 +548,16 @@ func (d *Daemon) parseRequestEvent(evt cdp.Event) (ipc.NetworkEntry, bool) {
 		RequestID string  `json:"requestId"`
 		WallTime  float64 `json:"wallTime"` // Unix epoch in seconds
 		Request   struct {
-			URL         string            `json:"url"`
-			Method      string            `json:"method"`
-			Headers     map[string]string `json:"headers"`
-			PostData    string            `json:"postData"`
-			HasPostData bool              `json:"hasPostData"`
+			URL              string            `json:"url"`
+			Method           string            `json:"method"`
+			Headers          map[string]string `json:"headers"`
+			PostData         string            `json:"postData"`
+			HasPostData      bool              `json:"hasPostData"`
+			InitialPriority  string            `json:"initialPriority"`
+			MixedContentType string            `json:"mixedContentType"`
 		} `json:"request"`
 		Type      string `json:"type"`
+		FrameID   string `json:"frameId"`
 		Initiator struct {
 			Type       string `json:"type"`
 			URL        string `json:"url"`
@@ -474,6 +582,11 @@ func (d *Daemon) parseRequestEvent(evt cdp.Event) (ipc.NetworkEntry, bool) {
 		RequestTime:    int64(params.WallTime * 1000), // Convert seconds to milliseconds
 		RequestHeaders: params.Request.Headers,
 		RequestBody:    params.Request.PostData,
+		FrameID:        params.FrameID,
+		Priority:       params.Request.InitialPriority,
+	}
+	if params.Request.MixedContentType != "none" {
+		entry.MixedContentType = params.Request.MixedContentType
 	}
 
 	// Capture the initiator type plus a single source location. CDP carries the
@@ -539,7 +652,7 @@ func (d *Daemon) fetchRequestPostData(sessionID, requestID string) {
 			// session, transport error) also land here; in every case we degrade
 			// gracefully by clearing the marker, but log so the off-read-loop
 			// fetch is diagnosable under --debug.
-			d.debugf(false, "Network.getRequestPostData failed: requestId=%s, err=%v", requestID, err)
+			d.log.Debug("Network.getRequestPostData failed", "requestId", requestID, "error", err)
 			clearMarker()
 			return
 		}
@@ -548,7 +661,7 @@ func (d *Daemon) fetchRequestPostData(sessionID, requestID string) {
 			PostData string `json:"postData"`
 		}
 		if err := json.Unmarshal(result, &bodyResp); err != nil {
-			d.debugf(false, "Network.getRequestPostData: failed to parse response: requestId=%s, err=%v", requestID, err)
+			d.log.Warn("Network.getRequestPostData: failed to parse response", "requestId", requestID, "error", err)
 			clearMarker()
 			return
 		}
@@ -568,19 +681,20 @@ func (d *Daemon) updateResponseEvent(evt cdp.Event) {
 	var params struct {
 		RequestID string `json:"requestId"`
 		Response  struct {
-			Status            int                `json:"status"`
-			StatusText        string             `json:"statusText"`
-			MimeType          string             `json:"mimeType"`
-			Headers           map[string]string  `json:"headers"`
-			RemoteIPAddress   string             `json:"remoteIPAddress"`
-			RemotePort        int                `json:"remotePort"`
-			Protocol          string             `json:"protocol"`
-			FromDiskCache     bool               `json:"fromDiskCache"`
-			FromServiceWorker bool               `json:"fromServiceWorker"`
-			FromPrefetchCache bool               `json:"fromPrefetchCache"`
-			ConnectionID      float64            `json:"connectionId"`
-			SecurityState     string             `json:"securityState"`
-			Timing            *cdpResourceTiming `json:"timing"`
+			Status            int                 `json:"status"`
+			StatusText        string              `json:"statusText"`
+			MimeType          string              `json:"mimeType"`
+			Headers           map[string]string   `json:"headers"`
+			RemoteIPAddress   string              `json:"remoteIPAddress"`
+			RemotePort        int                 `json:"remotePort"`
+			Protocol          string              `json:"protocol"`
+			FromDiskCache     bool                `json:"fromDiskCache"`
+			FromServiceWorker bool                `json:"fromServiceWorker"`
+			FromPrefetchCache bool                `json:"fromPrefetchCache"`
+			ConnectionID      float64             `json:"connectionId"`
+			SecurityState     string              `json:"securityState"`
+			SecurityDetails   *cdpSecurityDetails `json:"securityDetails"`
+			Timing            *cdpResourceTiming  `json:"timing"`
 		} `json:"response"`
 	}
 	if err := json.Unmarshal(evt.Params, &params); err != nil {
@@ -588,6 +702,7 @@ func (d *Daemon) updateResponseEvent(evt cdp.Event) {
 	}
 
 	timing := deriveNetworkTiming(params.Response.Timing)
+	security := params.Response.SecurityDetails.details()
 
 	// Use current wall time for response timestamp since CDP's Network.responseReceived
 	// only provides monotonic timestamp, not wallTime. This is accurate because events
@@ -611,6 +726,7 @@ func (d *Daemon) updateResponseEvent(evt cdp.Event) {
 			entry.FromPrefetchCache = params.Response.FromPrefetchCache
 			entry.ConnectionID = params.Response.ConnectionID
 			entry.SecurityState = params.Response.SecurityState
+			entry.Security = security
 			entry.Timing = timing
 			entry.ResponseTime = responseTime
 			if entry.RequestTime > 0 {
@@ -622,6 +738,42 @@ func (d *Daemon) updateResponseEvent(evt cdp.Event) {
 	})
 }
 
+// cdpSecurityDetails mirrors the subset of CDP's Network.SecurityDetails the
+// daemon keeps. Validity times are seconds since the epoch.
+type cdpSecurityDetails struct {
+	Protocol         string   `json:"protocol"`
+	KeyExchange      string   `json:"keyExchange"`
+	KeyExchangeGroup string   `json:"keyExchangeGroup"`
+	Cipher           string   `json:"cipher"`
+	SubjectName      string   `json:"subjectName"`
+	SanList          []string `json:"sanList"`
+	Issuer           string   `json:"issuer"`
+	ValidFrom        float64  `json:"validFrom"`
+	ValidTo          float64  `json:"validTo"`
+}
+
+// details converts the CDP security details, or returns nil when the response
+// carried none. TLS 1.3 leaves keyExchange empty and names the group instead.
+func (s *cdpSecurityDetails) details() *ipc.NetworkSecurityDetails {
+	if s == nil {
+		return nil
+	}
+	keyExchange := s.KeyExchange
+	if keyExchange == "" {
+		keyExchange = s.KeyExchangeGroup
+	}
+	return &ipc.NetworkSecurityDetails{
+		Protocol:    s.Protocol,
+		KeyExchange: keyExchange,
+		Cipher:      s.Cipher,
+		SubjectName: s.SubjectName,
+		SANs:        s.SanList,
+		Issuer:      s.Issuer,
+		ValidFrom:   int64(s.ValidFrom * 1000),
+		ValidTo:     int64(s.ValidTo * 1000),
+	}
+}
+
 // cdpResourceTiming mirrors the subset of CDP's Network.ResourceTiming the
 // daemon consumes. Offsets are milliseconds relative to a requestTime baseline;
 // a negative value marks a phase boundary that did not occur.
@@ -700,6 +852,10 @@ func (d *Daemon) handleLoadingFinished(evt cdp.Event) {
 		return false
 	})
 
+	if !d.shouldCapture(mimeType, entryURL, params.EncodedDataLength) {
+		return
+	}
+
 	// Fetch the response body asynchronously to avoid blocking the read loop.
 	// CRITICAL: CDP calls block waiting for a response that comes through
 	// the same read loop. Synchronous CDP calls in event handlers cause deadlock.
@@ -783,6 +939,47 @@ func (d *Daemon) handleLoadingFailed(evt cdp.Event) {
 	})
 }
 
+// handleRequestServedFromCache handles the Network.requestServedFromCache
+// event, which fires before responseReceived when the memory cache answers a
+// request. responseReceived carries flags for the disk and prefetch caches and
+// service workers, but not this one.
+func (d *Daemon) handleRequestServedFromCache(evt cdp.Event) {
+	var params struct {
+		RequestID string `json:"requestId"`
+	}
+	if err := json.Unmarshal(evt.Params, &params); err != nil {
+		return
+	}
+
+	d.networkBuf.Update(func(entry *ipc.NetworkEntry) bool {
+		if entry.RequestID == params.RequestID {
+			entry.FromMemoryCache = true
+			return true
+		}
+		return false
+	})
+}
+
+// handleResourceChangedPriority handles the Network.resourceChangedPriority
+// event, recording the priority the browser moved a loading request to.
+func (d *Daemon) handleResourceChangedPriority(evt cdp.Event) {
+	var params struct {
+		RequestID   string `json:"requestId"`
+		NewPriority string `json:"newPriority"`
+	}
+	if err := json.Unmarshal(evt.Params, &params); err != nil || params.NewPriority == "" {
+		return
+	}
+
+	d.networkBuf.Update(func(entry *ipc.NetworkEntry) bool {
+		if entry.RequestID == params.RequestID {
+			entry.Priority = params.NewPriority
+			return true
+		}
+		return false
+	})
+}
+
 // handleTargetCreated handles Target.targetCreated event.
 // Manually attaches to page targets using Target.attachToTarget with flatten:true.
 func (d *Daemon) handleTargetCreated(evt cdp.Event) {
@@ -803,12 +1000,12 @@ func (d *Daemon) handleTargetCreated(evt cdp.Event) {
 		return
 	}
 
-	d.debugf(false, "Target.targetCreated: targetID=%q, type=%q, url=%q",
-		params.TargetInfo.TargetID, params.TargetInfo.Type, params.TargetInfo.URL)
+	d.log.Debug("Target.targetCreated",
+		"targetId", params.TargetInfo.TargetID, "type", params.TargetInfo.Type, "url", params.TargetInfo.URL)
 
 	// Check if we've already attached to this target (prevent double-attach)
 	if !d.attaches.mark(params.TargetInfo.TargetID) {
-		d.debugf(false, "Target.targetCreated: already attached to targetID=%q, skipping", params.TargetInfo.TargetID)
+		d.log.Debug("Target.targetCreated: already attached, skipping", "targetId", params.TargetInfo.TargetID)
 		return
 	}
 
@@ -830,7 +1027,7 @@ func (d *Daemon) handleTargetCreated(evt cdp.Event) {
 
 		// The result contains the sessionId, but we'll receive Target.attachedToTarget event anyway
 		// which will handle session setup via handleTargetAttached
-		d.debugf(false, "Target.attachToTarget result for targetID=%q: %s", params.TargetInfo.TargetID, string(result))
+		d.log.Debug("Target.attachToTarget succeeded", "targetId", params.TargetInfo.TargetID, "result", string(result))
 	}()
 }
 
@@ -840,10 +1037,11 @@ func (d *Daemon) handleTargetAttached(evt cdp.Event) {
 	var params struct {
 		SessionID  string `json:"sessionId"`
 		TargetInfo struct {
-			TargetID string `json:"targetId"`
-			Type     string `json:"type"`
-			Title    string `json:"title"`
-			URL      string `json:"url"`
+			TargetID         string `json:"targetId"`
+			Type             string `json:"type"`
+			Title            string `json:"title"`
+			URL              string `json:"url"`
+			BrowserContextID string `json:"browserContextId"`
 		} `json:"targetInfo"`
 	}
 	if err := json.Unmarshal(evt.Params, &params); err != nil {
@@ -855,8 +1053,10 @@ func (d *Daemon) handleTargetAttached(evt cdp.Event) {
 		return
 	}
 
-	d.debugf(false, "Target.attachedToTarget: sessionID=%q, targetID=%q, url=%q",
-		params.SessionID, params.TargetInfo.TargetID, params.TargetInfo.URL)
+	d.log.Debug("Target.attachedToTarget",
+		"session", params.SessionID, "targetId", params.TargetInfo.TargetID, "url", params.TargetInfo.URL)
+	d.recordEvent("info", "target_attached", "tab attached",
+		"session", params.SessionID, "targetId", params.TargetInfo.TargetID, "url", params.TargetInfo.URL)
 
 	// Add to session manager. Add signals any registered tab-new waiter for this
 	// targetID under its lock, closing the attach rendezvous.
@@ -866,6 +1066,9 @@ func (d *Daemon) handleTargetAttached(evt cdp.Event) {
 		params.TargetInfo.URL,
 		params.TargetInfo.Title,
 	)
+	if d.ownsBrowserContext(params.TargetInfo.BrowserContextID) {
+		d.sessions.SetBrowserContext(params.SessionID, params.TargetInfo.BrowserContextID)
+	}
 
 	// Refresh REPL prompt to show new session
 	if d.repl != nil {
@@ -879,7 +1082,7 @@ func (d *Daemon) handleTargetAttached(evt cdp.Event) {
 			// Log error but don't fail - session is still tracked
 			fmt.Fprintf(os.Stderr, "\nwarning: failed to enable domains for session: %v\n", err)
 		}
-		d.debugf(false, "enableDomainsForSession completed in %v for session %q", time.Since(startEnable), params.SessionID)
+		d.log.Debug("session domains enabled", "session", params.SessionID, "duration", time.Since(startEnable))
 	}()
 }
 
@@ -893,7 +1096,8 @@ func (d *Daemon) handleTargetDetached(evt cdp.Event) {
 		return
 	}
 
-	d.debugf(false, "Target.detachedFromTarget: sessionID=%q", params.SessionID)
+	d.log.Debug("Target.detachedFromTarget", "session", params.SessionID)
+	d.recordEvent("info", "target_detached", "tab detached", "session", params.SessionID)
 
 	// Cancel any in-flight navigation with the detach reason so a blocked ready or
 	// --wait consumer wakes with the session-closed outcome instead of timing out.
@@ -907,14 +1111,36 @@ func (d *Daemon) handleTargetDetached(evt cdp.Event) {
 	if targetID := d.sessions.TargetID(params.SessionID); targetID != "" {
 		d.attaches.clear(targetID)
 	}
+	var contextID string
+	if s := d.sessions.Get(params.SessionID); s != nil {
+		contextID = s.Context
+	}
 
 	// Remove from session manager. Remove signals any registered tab-close waiter
 	// for this sessionID under its lock, closing the detach rendezvous.
 	newActive, changed := d.sessions.Remove(params.SessionID)
-	d.debugf(false, "Session removed: newActiveID=%q, activeChanged=%v", newActive, changed)
+	d.log.Debug("session removed", "newActive", newActive, "activeChanged", changed)
 
 	// Purge entries for this session
 	d.purgeSessionEntries(params.SessionID)
+
+	// Drop tracked stylesheets, scripts, execution contexts, pointer state, and held requests for this session
+	d.styleSheetsMu.Lock()
+	delete(d.styleSheets, params.SessionID)
+	d.styleSheetsMu.Unlock()
+	d.scriptsMu.Lock()
+	delete(d.scripts, params.SessionID)
+	d.scriptsMu.Unlock()
+	d.clearContextFrames(params.SessionID)
+	d.miceMu.Lock()
+	delete(d.mice, params.SessionID)
+	d.miceMu.Unlock()
+	d.releaseHeld(params.SessionID, false)
+
+	// An incognito context goes with its last tab
+	if contextID != "" {
+		d.disposeIfEmpty(contextID)
+	}
 }
 
 // handleTargetInfoChanged handles Target.targetInfoChanged event.
@@ -937,8 +1163,8 @@ func (d *Daemon) handleTargetInfoChanged(evt cdp.Event) {
 		return
 	}
 
-	d.debugf(false, "Target.targetInfoChanged: targetID=%q, url=%q",
-		params.TargetInfo.TargetID, params.TargetInfo.URL)
+	d.log.Debug("Target.targetInfoChanged",
+		"targetId", params.TargetInfo.TargetID, "url", params.TargetInfo.URL)
 
 	// Update session by target ID
 	d.sessions.UpdateByTargetID(
@@ -961,6 +1187,9 @@ func (d *Daemon) purgeSessionEntries(sessionID string) {
 	d.networkBuf.RemoveIf(func(entry *ipc.NetworkEntry) bool {
 		return entry.SessionID == sessionID
 	})
+	d.exceptionBuf.RemoveIf(func(entry *ipc.PausedException) bool {
+		return entry.SessionID == sessionID
+	})
 }
 
 // handleFrameNavigated processes Page.frameNavigated events.
@@ -992,15 +1221,22 @@ func (d *Daemon) handleFrameNavigated(evt cdp.Event) {
 		return
 	}
 
+	// Console relative timestamps count from here
+	d.sessions.RecordNavigation(evt.SessionID, time.Now().UnixMilli())
+
 	if nav := d.navTracker.current(evt.SessionID); nav != nil {
 		nav.markFrameNavigated()
 	}
+
+	if d.recordingSession() == evt.SessionID {
+		d.checkRecordedNavigation(evt.SessionID, params.Frame.URL)
+	}
 }
 
 // handleLoadEventFired processes Page.loadEventFired events, marking the current
 // navigation Loaded (which also closes its DOM-ready milestone).
 func (d *Daemon) handleLoadEventFired(evt cdp.Event) {
-	d.debugf(false, "Page.loadEventFired: sessionID=%s", evt.SessionID)
+	d.log.Debug("Page.loadEventFired", "session", evt.SessionID)
 
 	if nav := d.navTracker.current(evt.SessionID); nav != nil {
 		nav.markLoaded()
@@ -1012,7 +1248,7 @@ func (d *Daemon) handleLoadEventFired(evt cdp.Event) {
 // ready default mode and DOM operations proceed once the DOM is ready without
 // waiting for all resources (images, scripts, ads) to finish loading.
 func (d *Daemon) handleDOMContentEventFired(evt cdp.Event) {
-	d.debugf(false, "Page.domContentEventFired: sessionID=%s", evt.SessionID)
+	d.log.Debug("Page.domContentEventFired", "session", evt.SessionID)
 
 	if nav := d.navTracker.current(evt.SessionID); nav != nil {
 		nav.markDOMReady()
@@ -0,0 +1,177 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// exceptionBufferSize is the capacity of the paused exception buffer. Each
+// entry carries a stack and a scope preview, and a page in an error loop
+// should not crowd out the rest, so it is fixed and small.
+const exceptionBufferSize = 200
+
+// exceptionMaxLocals caps the local variables previewed per exception.
+const exceptionMaxLocals = 50
+
+// exceptionPauseStates maps the 'exceptions pause' modes to the
+// Debugger.setPauseOnExceptions states.
+var exceptionPauseStates = map[string]string{
+	"on":       "all",
+	"uncaught": "uncaught",
+	"off":      "none",
+}
+
+// handleExceptions sets the exception pause mode or returns the captured
+// exceptions.
+func (d *Daemon) handleExceptions(req ipc.Request) ipc.Response {
+	var params ipc.ExceptionsParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid exceptions parameters: %v", err))
+		}
+	}
+
+	switch params.Action {
+	case "show", "":
+		return ipc.SuccessResponse(ipc.ExceptionsData{
+			Mode:       d.exceptionPauseMode(),
+			Exceptions: d.exceptionBuf.All(),
+			Dropped:    d.exceptionBuf.Dropped(),
+		})
+	case "pause":
+		if _, ok := exceptionPauseStates[params.Mode]; !ok {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid pause mode %q (use on, uncaught, or off)", params.Mode))
+		}
+		d.exceptionsMu.Lock()
+		d.exceptionMode = params.Mode
+		d.exceptionsMu.Unlock()
+		d.log.Info("exception pause mode set", "mode", params.Mode)
+		if _, err := d.syncDebugger(); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		return ipc.SuccessResponse(ipc.ExceptionsData{Mode: params.Mode})
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown exceptions action: %s", params.Action))
+	}
+}
+
+// exceptionPauseMode returns the 'exceptions pause' mode: on, uncaught, or
+// off.
+func (d *Daemon) exceptionPauseMode() string {
+	d.exceptionsMu.Lock()
+	defer d.exceptionsMu.Unlock()
+	if d.exceptionMode == "" {
+		return "off"
+	}
+	return d.exceptionMode
+}
+
+// exceptionPauseState returns the Debugger.setPauseOnExceptions state for
+// the pause mode.
+func (d *Daemon) exceptionPauseState() string {
+	return exceptionPauseStates[d.exceptionPauseMode()]
+}
+
+// isExceptionPause reports whether a Debugger.paused reason is a thrown
+// exception or a rejected promise.
+func isExceptionPause(reason string) bool {
+	return reason == "exception" || reason == "promiseRejection"
+}
+
+// capturePausedException records the exception a session paused on, with
+// the throwing frame's locals. Object IDs are only valid while paused, so the
+// caller resumes the page after this returns.
+func (d *Daemon) capturePausedException(ctx context.Context, client *cdp.Client, sessionID string, params debuggerPausedParams) {
+	e := parsePausedException(params)
+	e.SessionID = sessionID
+	e.Timestamp = time.Now().UnixMilli()
+
+	if len(params.CallFrames) > 0 {
+		for _, scope := range params.CallFrames[0].ScopeChain {
+			if scope.Type != "local" || scope.Object.ObjectID == "" {
+				continue
+			}
+			locals, err := scopeLocals(ctx, client, sessionID, scope.Object.ObjectID)
+			if err != nil {
+				d.log.Debug("failed to read exception locals", "session", sessionID, "error", err)
+			}
+			e.Locals = locals
+			break
+		}
+	}
+	d.exceptionBuf.Push(e)
+}
+
+// parsePausedException builds an exception entry from a Debugger.paused
+// event: the message and class from the exception value, and the stack from
+// the paused call frames.
+func parsePausedException(params debuggerPausedParams) ipc.PausedException {
+	var data struct {
+		cdpRemoteObject
+		Uncaught bool `json:"uncaught"`
+	}
+	_ = json.Unmarshal(params.Data, &data)
+
+	e := ipc.PausedException{
+		Reason:   params.Reason,
+		Uncaught: data.Uncaught,
+		Class:    data.ClassName,
+	}
+	// An Error's description is its stack; the first line is the message
+	if data.Description != "" {
+		e.Message, _, _ = strings.Cut(data.Description, "\n")
+	} else {
+		e.Message = renderArgText(remoteObjectToArg(data.cdpRemoteObject))
+	}
+	for _, cf := range params.CallFrames {
+		e.Stack = append(e.Stack, ipc.ConsoleFrame{
+			Function: cf.FunctionName,
+			URL:      cf.URL,
+			Line:     cf.Location.LineNumber,
+			Column:   cf.Location.ColumnNumber,
+		})
+	}
+	return e
+}
+
+// scopeLocals previews the variables of a paused scope object.
+func scopeLocals(ctx context.Context, client *cdp.Client, sessionID, objectID string) ([]ipc.ConsolePreviewProp, error) {
+	result, err := client.SendToSession(ctx, sessionID, "Runtime.getProperties", map[string]any{
+		"objectId":        objectID,
+		"ownProperties":   true,
+		"generatePreview": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Result []struct {
+			Name  string           `json:"name"`
+			Value *cdpRemoteObject `json:"value"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, err
+	}
+
+	var locals []ipc.ConsolePreviewProp
+	for _, p := range resp.Result {
+		if len(locals) == exceptionMaxLocals {
+			break
+		}
+		prop := ipc.ConsolePreviewProp{Name: p.Name, Type: "undefined"}
+		if p.Value != nil {
+			prop.Type = p.Value.Type
+			prop.Subtype = p.Value.Subtype
+			prop.Value = renderArgText(remoteObjectToArg(*p.Value))
+		}
+		locals = append(locals, prop)
+	}
+	return locals, nil
+}
@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func exceptionsRequest(t *testing.T, d *Daemon, params ipc.ExceptionsParams) (ipc.Response, ipc.ExceptionsData) {
+	t.Helper()
+	raw, _ := json.Marshal(params)
+	resp := d.handleExceptions(ipc.Request{Cmd: "exceptions", Params: raw})
+	var data ipc.ExceptionsData
+	if resp.OK {
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp, data
+}
+
+func TestHandleExceptions_Pause(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+
+	if _, data := exceptionsRequest(t, d, ipc.ExceptionsParams{}); data.Mode != "off" {
+		t.Errorf("default mode = %q, want off", data.Mode)
+	}
+	if resp, _ := exceptionsRequest(t, d, ipc.ExceptionsParams{Action: "pause", Mode: "sometimes"}); resp.OK || resp.Code != ipc.CodeInvalidArgs {
+		t.Errorf("invalid mode = %+v", resp)
+	}
+
+	resp, data := exceptionsRequest(t, d, ipc.ExceptionsParams{Action: "pause", Mode: "uncaught"})
+	if !resp.OK || data.Mode != "uncaught" {
+		t.Fatalf("pause = %+v %+v", resp, data)
+	}
+	var methods []string
+	var state any
+	for _, req := range conn.getCapturedRequests() {
+		methods = append(methods, req.Method)
+		if req.Method == "Debugger.setPauseOnExceptions" {
+			state = req.Params.(map[string]any)["state"]
+		}
+	}
+	if strings.Join(methods, ",") != "Debugger.disable,Debugger.enable,Debugger.setPauseOnExceptions" {
+		t.Errorf("methods = %v", methods)
+	}
+	if state != "uncaught" {
+		t.Errorf("pause state = %v, want uncaught", state)
+	}
+
+	_, _ = exceptionsRequest(t, d, ipc.ExceptionsParams{Action: "pause", Mode: "off"})
+	reqs := conn.getCapturedRequests()
+	if last := reqs[len(reqs)-1]; last.Method != "Debugger.disable" {
+		t.Errorf("last request after pause off = %s, want Debugger.disable", last.Method)
+	}
+}
+
+func TestParsePausedException(t *testing.T) {
+	raw := `{
+		"reason": "exception",
+		"data": {"type": "object", "subtype": "error", "className": "TypeError",
+			"description": "TypeError: Cannot read properties of undefined (reading 'id')\n    at loadUser (app.js:120:15)",
+			"uncaught": true},
+		"callFrames": [
+			{"functionName": "loadUser", "url": "https://example.com/app.js", "location": {"lineNumber": 119, "columnNumber": 14}},
+			{"functionName": "", "url": "https://example.com/app.js", "location": {"lineNumber": 139, "columnNumber": 2}}
+		]
+	}`
+	var params debuggerPausedParams
+	if err := json.Unmarshal([]byte(raw), &params); err != nil {
+		t.Fatal(err)
+	}
+
+	e := parsePausedException(params)
+	if e.Message != "TypeError: Cannot read properties of undefined (reading 'id')" {
+		t.Errorf("message = %q", e.Message)
+	}
+	if e.Class != "TypeError" || !e.Uncaught || e.Reason != "exception" {
+		t.Errorf("exception = %+v", e)
+	}
+	if len(e.Stack) != 2 || e.Stack[0].Function != "loadUser" || e.Stack[0].Line != 119 || e.Stack[1].Line != 139 {
+		t.Errorf("stack = %+v", e.Stack)
+	}
+}
+
+func TestParsePausedException_Primitive(t *testing.T) {
+	params := debuggerPausedParams{
+		Reason: "promiseRejection",
+		Data:   json.RawMessage(`{"type": "string", "value": "nope"}`),
+	}
+	if e := parsePausedException(params); e.Message == "" || !strings.Contains(e.Message, "nope") {
+		t.Errorf("message = %q, want the rejected value", e.Message)
+	}
+}
@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// handleBuffer handles the "buffer" command: it reports the fill level of the
+// console and network buffers, and resizes them or toggles body capture.
+// Neither needs the browser, so it is answered while the browser restarts.
+func (d *Daemon) handleBuffer(req ipc.Request) ipc.Response {
+	var params ipc.BufferParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid buffer parameters: %v", err))
+		}
+	}
+
+	switch params.Action {
+	case "", "status":
+	case "set":
+		if params.CaptureBodies != nil {
+			d.captureBodies.Store(*params.CaptureBodies)
+			d.log.Info("body capture changed", "enabled", *params.CaptureBodies)
+		}
+		if params.Buffer != "" {
+			if params.Size <= 0 {
+				return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid buffer size %d (must be positive)", params.Size))
+			}
+			switch params.Buffer {
+			case "console":
+				d.consoleBuf.Resize(params.Size)
+			case "network":
+				d.networkBuf.Resize(params.Size)
+			default:
+				return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown buffer: %s (use console or network)", params.Buffer))
+			}
+			d.log.Info("buffer resized", "buffer", params.Buffer, "size", params.Size)
+		}
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown buffer action: %s", params.Action))
+	}
+
+	return ipc.SuccessResponse(ipc.BufferData{
+		Buffers: []ipc.BufferInfo{
+			bufferInfo("console", d.consoleBuf),
+			bufferInfo("network", d.networkBuf),
+		},
+		CaptureBodies: d.captureBodies.Load(),
+		Capture:       d.config.BodyCapture.String(),
+	})
+}
+
+// bufferInfo describes a buffer for "buffer status".
+func bufferInfo[T any](name string, b *RingBuffer[T]) ipc.BufferInfo {
+	return ipc.BufferInfo{Name: name, Entries: b.Len(), Capacity: b.Cap(), Dropped: b.Dropped()}
+}
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestHandleBuffer(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.BufferSize = 2
+	d := New(cfg)
+	for range 3 {
+		d.consoleBuf.Push(ipc.ConsoleEntry{Text: "x"})
+	}
+
+	send := func(params ipc.BufferParams) (ipc.Response, ipc.BufferData) {
+		t.Helper()
+		raw, _ := json.Marshal(params)
+		resp := d.handleRequest(ipc.Request{Cmd: "buffer", Params: raw})
+		var data ipc.BufferData
+		if resp.OK {
+			if err := json.Unmarshal(resp.Data, &data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return resp, data
+	}
+
+	resp, data := send(ipc.BufferParams{Action: "status"})
+	if !resp.OK || !data.CaptureBodies || len(data.Buffers) != 2 {
+		t.Fatalf("status = %+v %+v", resp, data)
+	}
+	if got := data.Buffers[0]; got != (ipc.BufferInfo{Name: "console", Entries: 2, Capacity: 2, Dropped: 1}) {
+		t.Errorf("console = %+v", got)
+	}
+
+	off := false
+	resp, data = send(ipc.BufferParams{Action: "set", Buffer: "network", Size: 50, CaptureBodies: &off})
+	if !resp.OK || data.CaptureBodies || data.Buffers[1].Capacity != 50 {
+		t.Errorf("set = %+v %+v, want network capacity 50 and capture off", resp, data)
+	}
+	if d.captureBodies.Load() {
+		t.Error("body capture still on")
+	}
+
+	for _, bad := range []ipc.BufferParams{
+		{Action: "set", Buffer: "network", Size: 0},
+		{Action: "set", Buffer: "dom", Size: 10},
+	} {
+		if resp, _ := send(bad); resp.OK || resp.Code != ipc.CodeInvalidArgs {
+			t.Errorf("set %+v = %+v, want an invalid-args error", bad, resp)
+		}
+	}
+}
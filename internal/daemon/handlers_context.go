@@ -0,0 +1,201 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// browserContext is a browser context made with 'context new': a separate
+// cookie jar and storage, like an incognito window, that lives in memory.
+type browserContext struct {
+	id string
+	// incognito contexts are disposed when their last tab closes, as closing
+	// the last incognito window discards its session. Others stay until
+	// 'context close' so more tabs can be opened in them.
+	incognito bool
+	created   time.Time
+}
+
+// ownsBrowserContext reports whether id is a context made with 'context new'.
+func (d *Daemon) ownsBrowserContext(id string) bool {
+	if id == "" {
+		return false
+	}
+	d.browserContextsMu.Lock()
+	defer d.browserContextsMu.Unlock()
+	_, ok := d.browserContexts[id]
+	return ok
+}
+
+// handleContext handles the "context" command.
+func (d *Daemon) handleContext(req ipc.Request) ipc.Response {
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	var params ipc.ContextParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid context parameters: %v", err))
+		}
+	}
+
+	switch params.Action {
+	case "", "list":
+		return ipc.SuccessResponse(ipc.ContextData{Contexts: d.listBrowserContexts()})
+	case "new":
+		return d.handleContextNew(params.URL, params.Incognito)
+	case "close":
+		return d.handleContextClose(params.ID)
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown context action: %s", params.Action))
+	}
+}
+
+// handleContextNew creates a browser context and opens a tab in it, which
+// becomes the active tab.
+func (d *Daemon) handleContextNew(url string, incognito bool) ipc.Response {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := d.cdp.SendContext(ctx, "Target.createBrowserContext", nil)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to create browser context: %v", err))
+	}
+	var created struct {
+		BrowserContextID string `json:"browserContextId"`
+	}
+	if err := json.Unmarshal(result, &created); err != nil || created.BrowserContextID == "" {
+		return ipc.ErrorResponse("createBrowserContext returned no browserContextId")
+	}
+	id := created.BrowserContextID
+
+	// Register before opening the tab so its attach event is recognised
+	d.browserContextsMu.Lock()
+	if d.browserContexts == nil {
+		d.browserContexts = make(map[string]*browserContext)
+	}
+	d.browserContexts[id] = &browserContext{id: id, incognito: incognito, created: time.Now()}
+	d.browserContextsMu.Unlock()
+
+	session, err := d.openTab(url, id)
+	if err != nil {
+		_ = d.disposeBrowserContext(id)
+		return ipc.ErrorResponse(err.Error())
+	}
+	d.log.Info("browser context created", "context", id, "incognito", incognito)
+
+	return ipc.SuccessResponse(ipc.ContextInfo{
+		ID:        id,
+		Incognito: incognito,
+		Tabs:      []ipc.PageSession{*session},
+	})
+}
+
+// handleContextClose disposes the context matching the ID prefix, closing its
+// tabs.
+func (d *Daemon) handleContextClose(query string) ipc.Response {
+	if query == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "context ID is required")
+	}
+
+	var matches []string
+	d.browserContextsMu.Lock()
+	for id := range d.browserContexts {
+		if strings.HasPrefix(id, query) {
+			matches = append(matches, id)
+		}
+	}
+	d.browserContextsMu.Unlock()
+	switch len(matches) {
+	case 0:
+		return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no context matches: %s", query))
+	case 1:
+	default:
+		return ipc.ErrorResponse(fmt.Sprintf("ambiguous context ID %s matches %d contexts", query, len(matches)))
+	}
+	id := matches[0]
+
+	// Same guard as closing the last tab
+	if len(d.sessions.InBrowserContext(id)) >= d.sessions.Count() {
+		return ipc.ErrorResponse("cannot close the context holding the last tab; open a tab in the default context first, or use 'webctl stop'")
+	}
+
+	if err := d.disposeBrowserContext(id); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to close context: %v", err))
+	}
+	d.log.Info("browser context closed", "context", id)
+
+	if d.repl != nil {
+		d.repl.refreshPrompt()
+	}
+	return ipc.SuccessResponse(ipc.ContextData{Contexts: d.listBrowserContexts()})
+}
+
+// disposeBrowserContext forgets a context and disposes it in the browser,
+// which closes its tabs.
+func (d *Daemon) disposeBrowserContext(id string) error {
+	d.browserContextsMu.Lock()
+	delete(d.browserContexts, id)
+	d.browserContextsMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := d.cdp.SendContext(ctx, "Target.disposeBrowserContext", map[string]any{"browserContextId": id})
+	return err
+}
+
+// disposeIfEmpty disposes an incognito context once its last tab has closed.
+// It is called from the CDP event loop, so the dispose is sent from a new
+// goroutine.
+func (d *Daemon) disposeIfEmpty(id string) {
+	d.browserContextsMu.Lock()
+	c, ok := d.browserContexts[id]
+	d.browserContextsMu.Unlock()
+	if !ok || !c.incognito || len(d.sessions.InBrowserContext(id)) > 0 {
+		return
+	}
+	go func() {
+		if err := d.disposeBrowserContext(id); err != nil {
+			d.log.Warn("failed to dispose browser context", "context", id, "error", err)
+			return
+		}
+		d.log.Info("browser context closed", "context", id, "reason", "last tab closed")
+	}()
+}
+
+// listBrowserContexts returns the default context followed by the ones made
+// with 'context new', oldest first, each with its tabs.
+func (d *Daemon) listBrowserContexts() []ipc.ContextInfo {
+	d.browserContextsMu.Lock()
+	owned := make([]*browserContext, 0, len(d.browserContexts))
+	for _, c := range d.browserContexts {
+		owned = append(owned, c)
+	}
+	d.browserContextsMu.Unlock()
+	sort.Slice(owned, func(i, j int) bool { return owned[i].created.Before(owned[j].created) })
+
+	contexts := []ipc.ContextInfo{{Default: true, Tabs: orEmpty(d.sessions.InBrowserContext(""))}}
+	for _, c := range owned {
+		contexts = append(contexts, ipc.ContextInfo{
+			ID:        c.id,
+			Incognito: c.incognito,
+			Tabs:      orEmpty(d.sessions.InBrowserContext(c.id)),
+		})
+	}
+	return contexts
+}
+
+// orEmpty returns s, or an empty slice if s is nil, so it encodes as [].
+func orEmpty(s []ipc.PageSession) []ipc.PageSession {
+	if s == nil {
+		return []ipc.PageSession{}
+	}
+	return s
+}
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListBrowserContexts(t *testing.T) {
+	d := New(DefaultConfig())
+	d.sessions.Add("sess1", "target1", "http://example.com", "Example")
+	d.sessions.Add("sess2", "target2", "http://other.com", "Other")
+	d.sessions.SetBrowserContext("sess2", "ctxB")
+
+	now := time.Now()
+	d.browserContexts = map[string]*browserContext{
+		"ctxB": {id: "ctxB", incognito: true, created: now.Add(time.Second)},
+		"ctxA": {id: "ctxA", created: now},
+	}
+
+	got := d.listBrowserContexts()
+	if len(got) != 3 {
+		t.Fatalf("got %d contexts, want 3: %+v", len(got), got)
+	}
+	if !got[0].Default || len(got[0].Tabs) != 1 || got[0].Tabs[0].ID != "sess1" {
+		t.Errorf("default context: got %+v", got[0])
+	}
+	if got[1].ID != "ctxA" || got[1].Incognito || got[1].Tabs == nil || len(got[1].Tabs) != 0 {
+		t.Errorf("oldest context first with empty tabs: got %+v", got[1])
+	}
+	if got[2].ID != "ctxB" || !got[2].Incognito || len(got[2].Tabs) != 1 || got[2].Tabs[0].ID != "sess2" {
+		t.Errorf("incognito context: got %+v", got[2])
+	}
+}
+
+func TestOwnsBrowserContext(t *testing.T) {
+	d := New(DefaultConfig())
+	d.browserContexts = map[string]*browserContext{"ctxA": {id: "ctxA"}}
+
+	if !d.ownsBrowserContext("ctxA") {
+		t.Error("expected ctxA to be owned")
+	}
+	if d.ownsBrowserContext("") {
+		t.Error("the default context is never owned")
+	}
+	if d.ownsBrowserContext("other") {
+		t.Error("expected a context made elsewhere not to be owned")
+	}
+}
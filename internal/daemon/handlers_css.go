@@ -4,8 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf16"
 
+	"github.com/grantcarthew/webctl/internal/cssformat"
 	"github.com/grantcarthew/webctl/internal/ipc"
 )
 
@@ -23,7 +28,7 @@ func (d *Daemon) handleCSS(req ipc.Request) ipc.Response {
 
 	var params ipc.CSSParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid css parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid css parameters: %v", err))
 	}
 
 	switch params.Action {
@@ -37,8 +42,16 @@ func (d *Daemon) handleCSS(req ipc.Request) ipc.Response {
 		return d.handleCSSInline(activeID, params)
 	case "matched":
 		return d.handleCSSMatched(activeID, params)
+	case "diff":
+		return d.handleCSSDiff(activeID, params)
+	case "list":
+		return d.handleCSSList(activeID, params)
+	case "dump":
+		return d.handleCSSDump(activeID, params)
+	case "unused":
+		return d.handleCSSUnused(activeID, params)
 	default:
-		return ipc.ErrorResponse(fmt.Sprintf("unknown css action: %s", params.Action))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown css action: %s", params.Action))
 	}
 }
 
@@ -92,7 +105,7 @@ func (d *Daemon) handleCSSSave(sessionID string, _ ipc.CSSParams) ipc.Response {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse CSS response: %v", err))
 	}
 	if evalResp.ExceptionDetails != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
+		return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
 	}
 
 	return ipc.SuccessResponse(ipc.CSSData{
@@ -103,7 +116,7 @@ func (d *Daemon) handleCSSSave(sessionID string, _ ipc.CSSParams) ipc.Response {
 // handleCSSComputed gets computed styles for all matching elements.
 func (d *Daemon) handleCSSComputed(sessionID string, params ipc.CSSParams) ipc.Response {
 	if params.Selector == "" {
-		return ipc.ErrorResponse("selector is required for computed styles")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required for computed styles")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -169,12 +182,12 @@ func (d *Daemon) handleCSSComputed(sessionID string, params ipc.CSSParams) ipc.R
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse computed styles response: %v", err))
 	}
 	if evalResp.ExceptionDetails != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
+		return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
 	}
 
 	// null result means no element matched
 	if evalResp.Result.Type == "object" && evalResp.Result.Value == nil {
-		return ipc.ErrorResponse(fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
 	}
 
 	// For backward compatibility, also set Styles if there's only one element
@@ -192,10 +205,10 @@ func (d *Daemon) handleCSSComputed(sessionID string, params ipc.CSSParams) ipc.R
 // handleCSSGet gets a single CSS property value for a selector.
 func (d *Daemon) handleCSSGet(sessionID string, params ipc.CSSParams) ipc.Response {
 	if params.Selector == "" {
-		return ipc.ErrorResponse("selector is required")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required")
 	}
 	if params.Property == "" {
-		return ipc.ErrorResponse("property is required")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "property is required")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -249,17 +262,17 @@ func (d *Daemon) handleCSSGet(sessionID string, params ipc.CSSParams) ipc.Respon
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse property response: %v", err))
 	}
 	if evalResp.ExceptionDetails != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
+		return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
 	}
 
 	// null result means no element matched
 	if evalResp.Result.Value == nil {
-		return ipc.ErrorResponse(fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
 	}
 
 	// Check if property exists
 	if !evalResp.Result.Value.Exists {
-		return ipc.ErrorResponse("property not found")
+		return ipc.CodedErrorResponse(ipc.CodeNotFound, "property not found")
 	}
 
 	// Check if value is empty (property exists but no value)
@@ -275,7 +288,7 @@ func (d *Daemon) handleCSSGet(sessionID string, params ipc.CSSParams) ipc.Respon
 // handleCSSInline gets inline style attributes for matching elements.
 func (d *Daemon) handleCSSInline(sessionID string, params ipc.CSSParams) ipc.Response {
 	if params.Selector == "" {
-		return ipc.ErrorResponse("selector is required for inline styles")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required for inline styles")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -331,12 +344,12 @@ func (d *Daemon) handleCSSInline(sessionID string, params ipc.CSSParams) ipc.Res
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse inline styles response: %v", err))
 	}
 	if evalResp.ExceptionDetails != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
+		return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
 	}
 
 	// null result means no element matched
 	if evalResp.Result.Type == "object" && evalResp.Result.Value == nil {
-		return ipc.ErrorResponse(fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
 	}
 
 	// Build deprecated Inline field for backward compatibility
@@ -354,7 +367,7 @@ func (d *Daemon) handleCSSInline(sessionID string, params ipc.CSSParams) ipc.Res
 // handleCSSMatched gets matched CSS rules for an element using CDP CSS.getMatchedStylesForNode.
 func (d *Daemon) handleCSSMatched(sessionID string, params ipc.CSSParams) ipc.Response {
 	if params.Selector == "" {
-		return ipc.ErrorResponse("selector is required for matched styles")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required for matched styles")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -401,7 +414,7 @@ func (d *Daemon) handleCSSMatched(sessionID string, params ipc.CSSParams) ipc.Re
 	}
 
 	if queryResp.NodeID == 0 {
-		return ipc.ErrorResponse(fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
 	}
 
 	// Get matched styles for the node
@@ -532,3 +545,381 @@ func (d *Daemon) handleCSSMatched(sessionID string, params ipc.CSSParams) ipc.Re
 		Matched: rules,
 	})
 }
+
+// handleCSSDiff compares the computed styles of two elements, or of one element
+// before and after an action, and returns only the properties that differ.
+//
+// Modes:
+//   - Selector + Other: first match of each selector (A and B)
+//   - Snapshot "before": stores the first match's computed styles
+//   - Snapshot "after": compares the stored snapshot (A) with now (B)
+func (d *Daemon) handleCSSDiff(sessionID string, params ipc.CSSParams) ipc.Response {
+	if params.Selector == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required for diff")
+	}
+
+	key := sessionID + "\x00" + params.Selector
+
+	switch params.Snapshot {
+	case "before":
+		styles, err := d.firstComputedStyles(sessionID, params.Selector)
+		if err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		d.styleSnapshotsMu.Lock()
+		if d.styleSnapshots == nil {
+			d.styleSnapshots = make(map[string]map[string]string)
+		}
+		d.styleSnapshots[key] = styles
+		d.styleSnapshotsMu.Unlock()
+		return ipc.SuccessResponse(nil)
+
+	case "after":
+		d.styleSnapshotsMu.Lock()
+		before, ok := d.styleSnapshots[key]
+		d.styleSnapshotsMu.Unlock()
+		if !ok {
+			return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no --before snapshot for selector '%s'", params.Selector))
+		}
+		after, err := d.firstComputedStyles(sessionID, params.Selector)
+		if err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		return ipc.SuccessResponse(ipc.CSSData{Diff: diffStyles(before, after)})
+
+	case "":
+		if params.Other == "" {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "second selector is required for diff")
+		}
+		a, err := d.firstComputedStyles(sessionID, params.Selector)
+		if err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		b, err := d.firstComputedStyles(sessionID, params.Other)
+		if err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		return ipc.SuccessResponse(ipc.CSSData{Diff: diffStyles(a, b)})
+
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown diff snapshot: %s", params.Snapshot))
+	}
+}
+
+// firstComputedStyles returns all computed properties of the first element
+// matching the selector.
+func (d *Daemon) firstComputedStyles(sessionID, selector string) (map[string]string, error) {
+	js := fmt.Sprintf(`(() => {
+	const el = document.querySelector(%q);
+	if (!el) {
+		return null;
+	}
+	const computed = window.getComputedStyle(el);
+	const styles = {};
+	for (let i = 0; i < computed.length; i++) {
+		const prop = computed[i];
+		styles[prop] = computed.getPropertyValue(prop);
+	}
+	return styles;
+})()`, selector)
+
+	var styles map[string]string
+	found, err := d.evalElementQuery(sessionID, js, &styles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get computed styles: %v", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("selector '%s' matched no elements", selector)
+	}
+	return styles, nil
+}
+
+// diffStyles returns the properties whose values differ between a and b,
+// sorted by property name. A property missing on one side has an empty value.
+func diffStyles(a, b map[string]string) []ipc.CSSPropertyDiff {
+	props := make(map[string]struct{}, len(a))
+	for prop := range a {
+		props[prop] = struct{}{}
+	}
+	for prop := range b {
+		props[prop] = struct{}{}
+	}
+
+	diffs := []ipc.CSSPropertyDiff{}
+	for prop := range props {
+		if a[prop] != b[prop] {
+			diffs = append(diffs, ipc.CSSPropertyDiff{Property: prop, A: a[prop], B: b[prop]})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Property < diffs[j].Property
+	})
+	return diffs
+}
+
+// styleSheetHeader is the subset of the CDP CSSStyleSheetHeader used by
+// "css list" and "css dump".
+type styleSheetHeader struct {
+	StyleSheetID string `json:"styleSheetId"`
+	SourceURL    string `json:"sourceURL"`
+	Origin       string `json:"origin"`
+	IsInline     bool   `json:"isInline"`
+}
+
+// addStyleSheet records a CSS.styleSheetAdded header. Called on the CDP read
+// loop; must not block.
+func (d *Daemon) addStyleSheet(sessionID string, header styleSheetHeader) {
+	d.styleSheetsMu.Lock()
+	defer d.styleSheetsMu.Unlock()
+	if d.styleSheets == nil {
+		d.styleSheets = make(map[string][]styleSheetHeader)
+	}
+	d.styleSheets[sessionID] = append(d.styleSheets[sessionID], header)
+}
+
+// removeStyleSheet forgets a stylesheet on CSS.styleSheetRemoved.
+func (d *Daemon) removeStyleSheet(sessionID, styleSheetID string) {
+	d.styleSheetsMu.Lock()
+	defer d.styleSheetsMu.Unlock()
+	sheets := d.styleSheets[sessionID]
+	for i, h := range sheets {
+		if h.StyleSheetID == styleSheetID {
+			d.styleSheets[sessionID] = append(sheets[:i:i], sheets[i+1:]...)
+			return
+		}
+	}
+}
+
+// collectStyleSheets returns the page's stylesheets, excluding user-agent and
+// DevTools inspector sheets. It re-enables the CSS domain so Chrome re-reports
+// every current stylesheet via CSS.styleSheetAdded; those events are handled
+// on the read loop before the CSS.enable response is delivered.
+func (d *Daemon) collectStyleSheets(ctx context.Context, sessionID string) ([]ipc.CSSStyleSheet, error) {
+	d.styleSheetsMu.Lock()
+	delete(d.styleSheets, sessionID)
+	d.styleSheetsMu.Unlock()
+
+	if _, err := d.sendToSession(ctx, sessionID, "CSS.disable", nil); err != nil {
+		return nil, fmt.Errorf("failed to reset CSS domain: %v", err)
+	}
+	if _, err := d.sendToSession(ctx, sessionID, "CSS.enable", nil); err != nil {
+		return nil, fmt.Errorf("failed to enable CSS domain: %v", err)
+	}
+
+	d.styleSheetsMu.Lock()
+	headers := append([]styleSheetHeader(nil), d.styleSheets[sessionID]...)
+	d.styleSheetsMu.Unlock()
+
+	var sheets []ipc.CSSStyleSheet
+	for _, h := range headers {
+		if h.Origin == "user-agent" || h.Origin == "inspector" {
+			continue
+		}
+		sheets = append(sheets, ipc.CSSStyleSheet{
+			Index:  len(sheets) + 1,
+			ID:     h.StyleSheetID,
+			URL:    h.SourceURL,
+			Origin: h.Origin,
+			Inline: h.IsInline,
+		})
+	}
+	return sheets, nil
+}
+
+// fillStyleSheetText fetches a stylesheet's source and sets its size and rule
+// count. The text itself is kept only when keepText is set.
+func (d *Daemon) fillStyleSheetText(ctx context.Context, sessionID string, sheet *ipc.CSSStyleSheet, keepText bool) error {
+	result, err := d.sendToSession(ctx, sessionID, "CSS.getStyleSheetText", map[string]any{
+		"styleSheetId": sheet.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get stylesheet %d text: %v", sheet.Index, err)
+	}
+
+	var textResp struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(result, &textResp); err != nil {
+		return fmt.Errorf("failed to parse stylesheet text: %v", err)
+	}
+
+	sheet.Size = len(textResp.Text)
+	sheet.Rules = len(cssformat.ParseRules(textResp.Text))
+	if keepText {
+		sheet.Text = textResp.Text
+	}
+	return nil
+}
+
+// handleCSSList lists all stylesheets with URL, size, and rule count.
+func (d *Daemon) handleCSSList(sessionID string, _ ipc.CSSParams) ipc.Response {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	sheets, err := d.collectStyleSheets(ctx, sessionID)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+	for i := range sheets {
+		if err := d.fillStyleSheetText(ctx, sessionID, &sheets[i], false); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+	}
+
+	return ipc.SuccessResponse(ipc.CSSData{StyleSheets: sheets})
+}
+
+// handleCSSDump returns the source text of one stylesheet, or of all
+// stylesheets when params.Sheet is empty.
+func (d *Daemon) handleCSSDump(sessionID string, params ipc.CSSParams) ipc.Response {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	sheets, err := d.collectStyleSheets(ctx, sessionID)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+	if len(sheets) == 0 {
+		return ipc.ErrorResponse("page has no stylesheets")
+	}
+
+	if params.Sheet != "" {
+		sheet, err := matchStyleSheet(sheets, params.Sheet)
+		if err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		sheets = []ipc.CSSStyleSheet{sheet}
+	}
+
+	for i := range sheets {
+		if err := d.fillStyleSheetText(ctx, sessionID, &sheets[i], true); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+	}
+
+	return ipc.SuccessResponse(ipc.CSSData{StyleSheets: sheets})
+}
+
+// matchStyleSheet resolves a stylesheet query against the list: a 1-based
+// index, an exact stylesheet ID, or a substring of exactly one URL.
+func matchStyleSheet(sheets []ipc.CSSStyleSheet, query string) (ipc.CSSStyleSheet, error) {
+	if n, err := strconv.Atoi(query); err == nil {
+		if n < 1 || n > len(sheets) {
+			return ipc.CSSStyleSheet{}, fmt.Errorf("stylesheet index %d out of range (1-%d)", n, len(sheets))
+		}
+		return sheets[n-1], nil
+	}
+
+	for _, s := range sheets {
+		if s.ID == query {
+			return s, nil
+		}
+	}
+
+	var matches []ipc.CSSStyleSheet
+	for _, s := range sheets {
+		if s.URL != "" && strings.Contains(s.URL, query) {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return ipc.CSSStyleSheet{}, fmt.Errorf("no stylesheet matches '%s'", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return ipc.CSSStyleSheet{}, fmt.Errorf("'%s' matches %d stylesheets, use the index from css list", query, len(matches))
+	}
+}
+
+// ruleUsage is a CDP CSS.RuleUsage entry. Offsets are UTF-16 code units into
+// the stylesheet text and cover the whole rule, selector included.
+type ruleUsage struct {
+	StyleSheetID string  `json:"styleSheetId"`
+	StartOffset  float64 `json:"startOffset"`
+	EndOffset    float64 `json:"endOffset"`
+	Used         bool    `json:"used"`
+}
+
+// handleCSSUnused reports style rules that do not match anything in the
+// current page state. Starting rule usage tracking forces a style recalc, so
+// stopping it straight away yields which rules apply right now; rules for
+// states not currently present (hover, other routes, closed menus) show as
+// unused.
+func (d *Daemon) handleCSSUnused(sessionID string, _ ipc.CSSParams) ipc.Response {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	sheets, err := d.collectStyleSheets(ctx, sessionID)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+
+	if _, err := d.sendToSession(ctx, sessionID, "CSS.startRuleUsageTracking", nil); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to start rule usage tracking: %v", err))
+	}
+	result, err := d.sendToSession(ctx, sessionID, "CSS.stopRuleUsageTracking", nil)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to stop rule usage tracking: %v", err))
+	}
+
+	var usageResp struct {
+		RuleUsage []ruleUsage `json:"ruleUsage"`
+	}
+	if err := json.Unmarshal(result, &usageResp); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to parse rule usage: %v", err))
+	}
+
+	bySheet := make(map[string][]ruleUsage)
+	for _, u := range usageResp.RuleUsage {
+		bySheet[u.StyleSheetID] = append(bySheet[u.StyleSheetID], u)
+	}
+
+	report := []ipc.CSSUnusedSheet{}
+	for i := range sheets {
+		if err := d.fillStyleSheetText(ctx, sessionID, &sheets[i], true); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		entry := unusedRules(sheets[i].Text, bySheet[sheets[i].ID])
+		sheets[i].Text = ""
+		entry.CSSStyleSheet = sheets[i]
+		report = append(report, entry)
+	}
+
+	return ipc.SuccessResponse(ipc.CSSData{Unused: report})
+}
+
+// unusedRules builds the unused rule report for one stylesheet from its source
+// text and rule usage entries, in source order.
+func unusedRules(text string, usage []ruleUsage) ipc.CSSUnusedSheet {
+	sort.Slice(usage, func(i, j int) bool {
+		return usage[i].StartOffset < usage[j].StartOffset
+	})
+
+	units := utf16.Encode([]rune(text))
+	entry := ipc.CSSUnusedSheet{
+		StyleRules: len(usage),
+		Selectors:  []ipc.CSSUnusedRule{},
+	}
+	for _, u := range usage {
+		if u.Used {
+			continue
+		}
+		start, end := int(u.StartOffset), int(u.EndOffset)
+		if start < 0 || end > len(units) || start >= end {
+			continue
+		}
+		rule := string(utf16.Decode(units[start:end]))
+		selector := rule
+		if i := strings.IndexByte(rule, '{'); i >= 0 {
+			selector = rule[:i]
+		}
+		entry.UnusedRules++
+		entry.UnusedBytes += len(rule)
+		entry.Selectors = append(entry.Selectors, ipc.CSSUnusedRule{
+			Selector: strings.Join(strings.Fields(selector), " "),
+			Bytes:    len(rule),
+		})
+	}
+	return entry
+}
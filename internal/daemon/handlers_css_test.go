@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestDiffStyles(t *testing.T) {
+	a := map[string]string{
+		"color":       "rgb(0, 0, 0)",
+		"display":     "block",
+		"padding-top": "8px",
+		"outline":     "none",
+	}
+	b := map[string]string{
+		"color":       "rgb(255, 0, 0)",
+		"display":     "block",
+		"padding-top": "12px",
+		"gap":         "4px",
+	}
+
+	got := diffStyles(a, b)
+	want := []ipc.CSSPropertyDiff{
+		{Property: "color", A: "rgb(0, 0, 0)", B: "rgb(255, 0, 0)"},
+		{Property: "gap", A: "", B: "4px"},
+		{Property: "outline", A: "none", B: ""},
+		{Property: "padding-top", A: "8px", B: "12px"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffStyles() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestDiffStyles_Identical(t *testing.T) {
+	styles := map[string]string{"color": "red", "display": "flex"}
+	if got := diffStyles(styles, styles); len(got) != 0 {
+		t.Errorf("expected no differences, got %+v", got)
+	}
+}
+
+func TestMatchStyleSheet(t *testing.T) {
+	sheets := []ipc.CSSStyleSheet{
+		{Index: 1, ID: "1001.0", URL: "https://example.com/css/app.css"},
+		{Index: 2, ID: "1001.1", Inline: true},
+		{Index: 3, ID: "1001.2", URL: "https://example.com/css/vendor.css"},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantID  string
+		wantErr string
+	}{
+		{name: "index", query: "2", wantID: "1001.1"},
+		{name: "index out of range", query: "4", wantErr: "out of range"},
+		{name: "stylesheet id", query: "1001.2", wantID: "1001.2"},
+		{name: "unique url substring", query: "app.css", wantID: "1001.0"},
+		{name: "ambiguous url substring", query: "example.com", wantErr: "matches 2 stylesheets"},
+		{name: "no match", query: "print.css", wantErr: "no stylesheet matches"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchStyleSheet(sheets, tt.query)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("ID = %q, want %q", got.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestStyleSheetTracking(t *testing.T) {
+	d := New(DefaultConfig())
+	d.addStyleSheet("sess1", styleSheetHeader{StyleSheetID: "a"})
+	d.addStyleSheet("sess1", styleSheetHeader{StyleSheetID: "b"})
+	d.addStyleSheet("sess1", styleSheetHeader{StyleSheetID: "c"})
+	d.addStyleSheet("sess2", styleSheetHeader{StyleSheetID: "x"})
+
+	d.removeStyleSheet("sess1", "b")
+	d.removeStyleSheet("sess1", "missing")
+
+	var ids []string
+	for _, h := range d.styleSheets["sess1"] {
+		ids = append(ids, h.StyleSheetID)
+	}
+	if !reflect.DeepEqual(ids, []string{"a", "c"}) {
+		t.Errorf("sess1 stylesheets = %v, want [a c]", ids)
+	}
+	if len(d.styleSheets["sess2"]) != 1 {
+		t.Errorf("sess2 should be unaffected, got %v", d.styleSheets["sess2"])
+	}
+}
+
+func TestUnusedRules(t *testing.T) {
+	// "é" is one UTF-16 unit but two UTF-8 bytes; offsets are UTF-16.
+	text := ".used { color: red; }\n.old,\n  .older { content: \"é\"; }\n@media (min-width: 1px) { .wide { gap: 0; } }"
+	usage := []ruleUsage{
+		{StyleSheetID: "s", StartOffset: 81, EndOffset: 98, Used: false},
+		{StyleSheetID: "s", StartOffset: 0, EndOffset: 21, Used: true},
+		{StyleSheetID: "s", StartOffset: 22, EndOffset: 54, Used: false},
+	}
+
+	got := unusedRules(text, usage)
+	if got.StyleRules != 3 || got.UnusedRules != 2 {
+		t.Fatalf("StyleRules=%d UnusedRules=%d, want 3 and 2", got.StyleRules, got.UnusedRules)
+	}
+	want := []ipc.CSSUnusedRule{
+		{Selector: ".old, .older", Bytes: 33},
+		{Selector: ".wide", Bytes: 17},
+	}
+	if !reflect.DeepEqual(got.Selectors, want) {
+		t.Errorf("Selectors = %+v, want %+v", got.Selectors, want)
+	}
+	if got.UnusedBytes != 50 {
+		t.Errorf("UnusedBytes = %d, want 50", got.UnusedBytes)
+	}
+}
@@ -0,0 +1,175 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// describeDefaultLimit is how many controls describe lists when the request
+// does not set a limit.
+const describeDefaultLimit = 50
+
+// describePageJS is a function taking the control limit that summarizes the
+// page for the "describe" command. Only rendered, visible elements are
+// included, every element gets a unique selector from describeElementJS, and
+// names come from elementNameJS.
+const describePageJS = `function(limit) {
+	const describe = ` + describeElementJS + `;
+	const selector = el => describe.call(el).selector;
+` + elementNameJS + `
+
+	const landmarkRoles = {HEADER: 'banner', NAV: 'navigation', MAIN: 'main', ASIDE: 'complementary', FOOTER: 'contentinfo'};
+	const landmarkSel = 'header, nav, main, aside, footer, section[aria-label], section[aria-labelledby], ' +
+		'[role=banner], [role=navigation], [role=main], [role=complementary], [role=contentinfo], [role=search], [role=region]';
+
+	function landmarkRole(el) {
+		const role = el.getAttribute('role');
+		if (role) {
+			return role;
+		}
+		if (el.tagName === 'SECTION') {
+			return 'region';
+		}
+		// header and footer are page landmarks only outside sectioning content.
+		if ((el.tagName === 'HEADER' || el.tagName === 'FOOTER') && el.parentElement &&
+			el.parentElement.closest('article, aside, main, nav, section')) {
+			return '';
+		}
+		return landmarkRoles[el.tagName] || '';
+	}
+
+	const landmarks = [];
+	const landmarkEls = [];
+	for (const el of document.querySelectorAll(landmarkSel)) {
+		const role = landmarkRole(el);
+		if (!role || !visible(el)) {
+			continue;
+		}
+		let depth = 0;
+		for (const other of landmarkEls) {
+			if (other.contains(el)) {
+				depth++;
+			}
+		}
+		landmarkEls.push(el);
+		landmarks.push({role: role, label: clean(labelText(el), 60), selector: selector(el), depth: depth});
+	}
+
+	const headings = [];
+	for (const el of document.querySelectorAll('h1, h2, h3, h4, h5, h6, [role=heading]')) {
+		if (!visible(el)) {
+			continue;
+		}
+		const level = /^H[1-6]$/.test(el.tagName) ? Number(el.tagName[1]) : Number(el.getAttribute('aria-level') || 2);
+		headings.push({level: level, text: clean(el.innerText || el.textContent, 100), selector: selector(el)});
+	}
+
+	const controls = [];
+	let moreControls = 0;
+	const controlSel = 'a[href], button, input[type=submit], input[type=button], input[type=reset], input[type=image], summary, ' +
+		'[role=button], [role=link], [role=tab], [role=menuitem], [role=checkbox], [role=switch], [role=option], [onclick]';
+	for (const el of document.querySelectorAll(controlSel)) {
+		if (!visible(el)) {
+			continue;
+		}
+		if (controls.length >= limit) {
+			moreControls++;
+			continue;
+		}
+		let role = el.getAttribute('role');
+		if (!role) {
+			role = el.tagName === 'A' ? 'link' : 'button';
+		}
+		const c = {role: role, name: accessibleName(el), selector: selector(el)};
+		if (el.tagName === 'A') {
+			c.href = el.getAttribute('href');
+		}
+		if (el.disabled || el.getAttribute('aria-disabled') === 'true') {
+			c.disabled = true;
+		}
+		controls.push(c);
+	}
+
+	const forms = [];
+	const formsByEl = new Map();
+	const fieldSel = 'input:not([type=hidden]):not([type=submit]):not([type=button]):not([type=reset]):not([type=image]), select, textarea';
+	for (const el of document.querySelectorAll(fieldSel)) {
+		if (!visible(el)) {
+			continue;
+		}
+		const formEl = el.form || null;
+		let form = formsByEl.get(formEl);
+		if (!form) {
+			form = {selector: formEl ? selector(formEl) : '', fields: []};
+			if (formEl) {
+				form.name = clean(labelText(formEl) || formEl.getAttribute('name') || '', 60);
+				form.action = formEl.getAttribute('action') || '';
+			}
+			formsByEl.set(formEl, form);
+			forms.push(form);
+		}
+		const type = el.tagName === 'INPUT' ? (el.type || 'text') : el.tagName.toLowerCase();
+		const f = {type: type, name: clean(labelText(el), 80) || el.getAttribute('name') || '', selector: selector(el)};
+		if (type === 'checkbox' || type === 'radio') {
+			f.checked = el.checked;
+		} else if (type !== 'password' && el.value) {
+			f.value = clean(el.value, 80);
+		}
+		if (el.required) {
+			f.required = true;
+		}
+		if (el.disabled) {
+			f.disabled = true;
+		}
+		if (type === 'select') {
+			f.options = Array.from(el.options).slice(0, 20).map(o => clean(o.label || o.text, 40));
+		}
+		form.fields.push(f);
+	}
+
+	return {
+		url: location.href,
+		title: document.title,
+		landmarks: landmarks,
+		headings: headings,
+		controls: controls,
+		moreControls: moreControls,
+		forms: forms
+	};
+}`
+
+// handleDescribe summarizes the active page for use as agent context: its
+// landmarks, headings, controls, and form fields, each with a selector.
+func (d *Daemon) handleDescribe(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.DescribeParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid describe parameters: %v", err))
+		}
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = describeDefaultLimit
+	}
+
+	js := "(" + describePageJS + ")(" + strconv.Itoa(limit) + ")"
+
+	var data ipc.DescribeData
+	if _, err := d.evalElementQuery(activeID, js, &data); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to describe page: %v", err))
+	}
+	return ipc.SuccessResponse(data)
+}
@@ -0,0 +1,177 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// cdpDOMSnapshot mirrors the parts of a DOMSnapshot.captureSnapshot result
+// that "dom snapshot" uses. Strings are indexes into Strings, -1 for none.
+type cdpDOMSnapshot struct {
+	Documents []struct {
+		DocumentURL int `json:"documentURL"`
+		Title       int `json:"title"`
+		Nodes       struct {
+			ParentIndex []int   `json:"parentIndex"`
+			NodeType    []int   `json:"nodeType"`
+			NodeName    []int   `json:"nodeName"`
+			NodeValue   []int   `json:"nodeValue"`
+			Attributes  [][]int `json:"attributes"`
+		} `json:"nodes"`
+		Layout struct {
+			NodeIndex []int       `json:"nodeIndex"`
+			Styles    [][]int     `json:"styles"`
+			Bounds    [][]float64 `json:"bounds"`
+		} `json:"layout"`
+	} `json:"documents"`
+	Strings []string `json:"strings"`
+}
+
+// DOM node types kept in a snapshot; comments, doctypes, and the like are
+// dropped.
+const (
+	domElementNode  = 1
+	domTextNode     = 3
+	domDocumentNode = 9
+)
+
+// handleDOM handles the "dom" command.
+func (d *Daemon) handleDOM(req ipc.Request) ipc.Response {
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.DOMParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid dom parameters: %v", err))
+	}
+	if params.Action != "snapshot" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown dom action: %s", params.Action))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	styles := params.ComputedStyles
+	if styles == nil {
+		styles = []string{}
+	}
+	result, err := d.sendToSession(ctx, activeID, "DOMSnapshot.captureSnapshot", map[string]any{
+		"computedStyles": styles,
+	})
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to capture DOM snapshot: %v", err))
+	}
+
+	var snap cdpDOMSnapshot
+	if err := json.Unmarshal(result, &snap); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to parse DOM snapshot: %v", err))
+	}
+	data, err := flattenDOMSnapshot(snap, styles)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+	return ipc.SuccessResponse(data)
+}
+
+// flattenDOMSnapshot turns the main document of a snapshot's column-oriented
+// tables into one record per node, in document order. Whitespace-only text
+// and the source text of scripts and style sheets are dropped. Iframe
+// documents are not included.
+func flattenDOMSnapshot(snap cdpDOMSnapshot, styles []string) (ipc.DOMSnapshotData, error) {
+	if len(snap.Documents) == 0 {
+		return ipc.DOMSnapshotData{}, fmt.Errorf("DOM snapshot has no document")
+	}
+	doc := snap.Documents[0]
+	str := func(i int) string {
+		if i < 0 || i >= len(snap.Strings) {
+			return ""
+		}
+		return snap.Strings[i]
+	}
+	at := func(col []int, i int) int {
+		if i < len(col) {
+			return col[i]
+		}
+		return -1
+	}
+
+	// A node may have several layout objects; the first is its box.
+	layout := map[int]int{}
+	for i, n := range doc.Layout.NodeIndex {
+		if _, ok := layout[n]; !ok {
+			layout[n] = i
+		}
+	}
+
+	data := ipc.DOMSnapshotData{URL: str(doc.DocumentURL), Title: str(doc.Title), Nodes: []ipc.DOMNode{}}
+	depth := map[int]int{}
+	names := map[int]string{}
+	kept := map[int]bool{}
+	nodes := doc.Nodes
+	for i := range nodes.NodeType {
+		parent := at(nodes.ParentIndex, i)
+		if parent >= 0 && !kept[parent] {
+			continue // inside a dropped node
+		}
+
+		node := ipc.DOMNode{Index: i, Parent: parent, Name: str(at(nodes.NodeName, i))}
+		switch nodes.NodeType[i] {
+		case domDocumentNode:
+			node.Type = "document"
+		case domElementNode:
+			node.Type = "element"
+			node.Name = strings.ToLower(node.Name)
+		case domTextNode:
+			node.Type = "text"
+			node.Value = str(at(nodes.NodeValue, i))
+			if strings.TrimSpace(node.Value) == "" || names[parent] == "script" || names[parent] == "style" {
+				continue
+			}
+		default:
+			continue
+		}
+		if parent >= 0 {
+			node.Depth = depth[parent] + 1
+		}
+
+		if i < len(nodes.Attributes) && len(nodes.Attributes[i]) > 0 {
+			node.Attributes = map[string]string{}
+			attrs := nodes.Attributes[i]
+			for j := 0; j+1 < len(attrs); j += 2 {
+				node.Attributes[str(attrs[j])] = str(attrs[j+1])
+			}
+		}
+
+		if l, ok := layout[i]; ok {
+			if l < len(doc.Layout.Bounds) && len(doc.Layout.Bounds[l]) == 4 {
+				b := doc.Layout.Bounds[l]
+				node.Box = &ipc.DOMBox{X: b[0], Y: b[1], Width: b[2], Height: b[3]}
+			}
+			if l < len(doc.Layout.Styles) && len(styles) > 0 {
+				node.Styles = map[string]string{}
+				for j, v := range doc.Layout.Styles[l] {
+					if j < len(styles) {
+						node.Styles[styles[j]] = str(v)
+					}
+				}
+			}
+		}
+
+		kept[i] = true
+		depth[i] = node.Depth
+		names[i] = node.Name
+		data.Nodes = append(data.Nodes, node)
+	}
+	return data, nil
+}
@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestFlattenDOMSnapshot(t *testing.T) {
+	raw := `{
+		"strings": ["https://app.test/", "Home", "#document", "HTML", "HEAD", "SCRIPT", "var x = 1", "#text",
+			"BODY", "DIV", "id", "app", "Hello", "  \n", "block", "16px", "#comment", "note", "inline"],
+		"documents": [{
+			"documentURL": 0,
+			"title": 1,
+			"nodes": {
+				"parentIndex": [-1, 0, 1, 2, 3, 1, 5, 5, 7, 5],
+				"nodeType":    [9, 1, 1, 1, 3, 1, 3, 1, 3, 8],
+				"nodeName":    [2, 3, 4, 5, 7, 8, 7, 9, 7, 16],
+				"nodeValue":   [-1, -1, -1, -1, 6, -1, 13, -1, 12, 17],
+				"attributes":  [[], [], [], [], [], [], [], [10, 11], [], []]
+			},
+			"layout": {
+				"nodeIndex": [1, 5, 7, 8, 8],
+				"bounds": [[0, 0, 800, 600], [8, 8, 784, 584], [8, 8, 784, 20], [8, 8, 40, 20], [50, 8, 10, 20]],
+				"styles": [[14, 15], [14, 15], [14, 15], [18, 15], [18, 15]]
+			}
+		}]
+	}`
+	var snap cdpDOMSnapshot
+	if err := json.Unmarshal([]byte(raw), &snap); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := flattenDOMSnapshot(snap, []string{"display", "font-size"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.URL != "https://app.test/" || data.Title != "Home" {
+		t.Errorf("url, title = %q, %q", data.URL, data.Title)
+	}
+
+	// The script source, whitespace text, and comment are dropped.
+	wantIndexes := []int{0, 1, 2, 3, 5, 7, 8}
+	wantDepths := []int{0, 1, 2, 3, 2, 3, 4}
+	wantNames := []string{"#document", "html", "head", "script", "body", "div", "#text"}
+	if len(data.Nodes) != len(wantIndexes) {
+		t.Fatalf("got %d nodes, want %d: %+v", len(data.Nodes), len(wantIndexes), data.Nodes)
+	}
+	for i, n := range data.Nodes {
+		if n.Index != wantIndexes[i] || n.Depth != wantDepths[i] || n.Name != wantNames[i] {
+			t.Errorf("node %d = %d %s@%d, want %d %s@%d", i, n.Index, n.Name, n.Depth, wantIndexes[i], wantNames[i], wantDepths[i])
+		}
+	}
+
+	div := data.Nodes[5]
+	if div.Type != "element" || div.Parent != 5 || div.Attributes["id"] != "app" {
+		t.Errorf("div = %+v", div)
+	}
+	if div.Box == nil || *div.Box != (ipc.DOMBox{X: 8, Y: 8, Width: 784, Height: 20}) {
+		t.Errorf("div box = %+v", div.Box)
+	}
+	if want := map[string]string{"display": "block", "font-size": "16px"}; !reflect.DeepEqual(div.Styles, want) {
+		t.Errorf("div styles = %v, want %v", div.Styles, want)
+	}
+
+	text := data.Nodes[6]
+	if text.Type != "text" || text.Value != "Hello" {
+		t.Errorf("text = %+v", text)
+	}
+	if text.Box == nil || text.Box.Width != 40 {
+		t.Errorf("text box = %+v, want the first of its layout boxes", text.Box)
+	}
+	if data.Nodes[2].Box != nil {
+		t.Errorf("head has a box: %+v", data.Nodes[2].Box)
+	}
+}
+
+func TestFlattenDOMSnapshot_NoDocument(t *testing.T) {
+	if _, err := flattenDOMSnapshot(cdpDOMSnapshot{}, nil); err == nil {
+		t.Error("expected an error for a snapshot without documents")
+	}
+}
@@ -0,0 +1,456 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// elementMetaJS defines getElementMeta(el), which extracts the element
+// identification metadata (tag, id, first class) shared by element commands.
+const elementMetaJS = `function getElementMeta(el) {
+		const id = (el.id || '').trim();
+		const classes = (el.getAttribute('class') || '')
+			.split(/\s+/)
+			.map(c => c.trim())
+			.filter(c => c.length > 0);
+		return {
+			tag: el.tagName.toLowerCase(),
+			id: id || null,
+			class: classes.length > 0 ? classes[0] : null
+		};
+	}`
+
+// evalElementQuery evaluates a page expression that returns a JSON value and
+// decodes it into out. A null result reports found=false, which callers map
+// to the "matched no elements" error.
+func (d *Daemon) evalElementQuery(sessionID, js string, out any) (found bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+		"expression":    js,
+		"returnByValue": true,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var evalResp struct {
+		Result struct {
+			Type    string          `json:"type"`
+			Subtype string          `json:"subtype"`
+			Value   json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text      string `json:"text"`
+			Exception *struct {
+				Description string `json:"description"`
+			} `json:"exception"`
+		} `json:"exceptionDetails"`
+	}
+	if err := json.Unmarshal(result, &evalResp); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if evalResp.ExceptionDetails != nil {
+		msg := evalResp.ExceptionDetails.Text
+		if evalResp.ExceptionDetails.Exception != nil && evalResp.ExceptionDetails.Exception.Description != "" {
+			msg = evalResp.ExceptionDetails.Exception.Description
+		}
+		return false, fmt.Errorf("JavaScript error: %s", msg)
+	}
+	if evalResp.Result.Subtype == "null" || len(evalResp.Result.Value) == 0 || string(evalResp.Result.Value) == "null" {
+		return false, nil
+	}
+	if err := json.Unmarshal(evalResp.Result.Value, out); err != nil {
+		return false, fmt.Errorf("failed to parse result: %w", err)
+	}
+	return true, nil
+}
+
+// handleBox returns the bounding box, visibility, and scroll state of every
+// element matching the selector.
+func (d *Daemon) handleBox(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.BoxParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid box parameters: %v", err))
+	}
+
+	if params.Selector == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required")
+	}
+
+	js := fmt.Sprintf(`(() => {
+	%s
+
+	const elements = document.querySelectorAll(%q);
+	if (elements.length === 0) {
+		return null;
+	}
+	const vw = window.innerWidth;
+	const vh = window.innerHeight;
+	return {
+		scrollX: window.scrollX,
+		scrollY: window.scrollY,
+		viewportWidth: vw,
+		viewportHeight: vh,
+		elements: Array.from(elements).map((el) => {
+			const r = el.getBoundingClientRect();
+			const style = window.getComputedStyle(el);
+			const rendered = typeof el.checkVisibility === 'function'
+				? el.checkVisibility({opacityProperty: true, visibilityProperty: true})
+				: style.display !== 'none' && style.visibility !== 'hidden' && style.opacity !== '0';
+			return {
+				...getElementMeta(el),
+				x: r.x,
+				y: r.y,
+				width: r.width,
+				height: r.height,
+				pageX: r.x + window.scrollX,
+				pageY: r.y + window.scrollY,
+				visible: rendered && r.width > 0 && r.height > 0,
+				inViewport: r.bottom > 0 && r.right > 0 && r.top < vh && r.left < vw,
+				fullyInViewport: r.top >= 0 && r.left >= 0 && r.bottom <= vh && r.right <= vw,
+				scrollTop: el.scrollTop,
+				scrollLeft: el.scrollLeft
+			};
+		})
+	};
+})()`, elementMetaJS, params.Selector)
+
+	var data ipc.BoxData
+	found, err := d.evalElementQuery(activeID, js, &data)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to get element box: %v", err))
+	}
+	if !found {
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+	}
+
+	return ipc.SuccessResponse(data)
+}
+
+// handleAttr reads, sets, or lists element attributes.
+// get reads from the first matching element; set and list apply to all matches.
+func (d *Daemon) handleAttr(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.AttrParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid attr parameters: %v", err))
+	}
+
+	if params.Selector == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required")
+	}
+
+	switch params.Action {
+	case "get":
+		return d.handleAttrGet(activeID, params)
+	case "set":
+		return d.handleAttrSet(activeID, params)
+	case "list":
+		return d.handleAttrList(activeID, params)
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown attr action: %s", params.Action))
+	}
+}
+
+// handleAttrGet returns a single attribute value from the first matching element.
+func (d *Daemon) handleAttrGet(sessionID string, params ipc.AttrParams) ipc.Response {
+	if params.Name == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "attribute name is required")
+	}
+
+	js := fmt.Sprintf(`(() => {
+	const el = document.querySelector(%q);
+	if (!el) {
+		return null;
+	}
+	return {exists: el.hasAttribute(%q), value: el.getAttribute(%q) || ''};
+})()`, params.Selector, params.Name, params.Name)
+
+	var result struct {
+		Exists bool   `json:"exists"`
+		Value  string `json:"value"`
+	}
+	found, err := d.evalElementQuery(sessionID, js, &result)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to get attribute: %v", err))
+	}
+	if !found {
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+	}
+	if !result.Exists {
+		return ipc.CodedErrorResponse(ipc.CodeNotFound, "attribute not found")
+	}
+
+	return ipc.SuccessResponse(ipc.AttrData{Value: result.Value})
+}
+
+// handleAttrSet sets an attribute on every matching element.
+func (d *Daemon) handleAttrSet(sessionID string, params ipc.AttrParams) ipc.Response {
+	if params.Name == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "attribute name is required")
+	}
+
+	js := fmt.Sprintf(`(() => {
+	const elements = document.querySelectorAll(%q);
+	if (elements.length === 0) {
+		return null;
+	}
+	elements.forEach(el => el.setAttribute(%q, %q));
+	return elements.length;
+})()`, params.Selector, params.Name, params.Value)
+
+	var count int
+	found, err := d.evalElementQuery(sessionID, js, &count)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to set attribute: %v", err))
+	}
+	if !found {
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+	}
+
+	return ipc.SuccessResponse(ipc.AttrData{Count: count})
+}
+
+// handleAttrList returns all attributes of every matching element.
+func (d *Daemon) handleAttrList(sessionID string, params ipc.AttrParams) ipc.Response {
+	js := fmt.Sprintf(`(() => {
+	%s
+
+	const elements = document.querySelectorAll(%q);
+	if (elements.length === 0) {
+		return null;
+	}
+	return Array.from(elements).map((el) => {
+		const attributes = {};
+		for (const attr of el.attributes) {
+			attributes[attr.name] = attr.value;
+		}
+		return {...getElementMeta(el), attributes};
+	});
+})()`, elementMetaJS, params.Selector)
+
+	var elements []ipc.ElementWithAttributes
+	found, err := d.evalElementQuery(sessionID, js, &elements)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to list attributes: %v", err))
+	}
+	if !found {
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+	}
+
+	return ipc.SuccessResponse(ipc.AttrData{Elements: elements})
+}
+
+// handleCount returns the number of elements matching the selector.
+// Zero matches is a valid result, not an error.
+func (d *Daemon) handleCount(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.CountParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid count parameters: %v", err))
+	}
+
+	if params.Selector == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required")
+	}
+
+	js := fmt.Sprintf(`document.querySelectorAll(%q).length`, params.Selector)
+
+	var count int
+	if _, err := d.evalElementQuery(activeID, js, &count); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to count elements: %v", err))
+	}
+
+	return ipc.SuccessResponse(ipc.CountData{Count: count})
+}
+
+// elementsJS is a function taking a list of element types (empty for all)
+// that inventories the page's clickable and focusable elements for the
+// "elements" command. An explicit ARIA role decides the type before the tag
+// does, so <div role="button"> is a button and <a role="tab"> a tab.
+const elementsJS = `function(types) {
+	const describe = ` + describeElementJS + `;
+` + elementNameJS + `
+
+	const roleTypes = {
+		link: 'link', button: 'button', checkbox: 'checkbox', switch: 'checkbox', radio: 'radio',
+		combobox: 'select', listbox: 'select', textbox: 'input', searchbox: 'input', tab: 'tab',
+		menuitem: 'menuitem', menuitemcheckbox: 'menuitem', menuitemradio: 'menuitem', option: 'menuitem'
+	};
+	const tagTypes = [
+		['link', 'a[href], area[href]'],
+		['button', 'button, input[type=submit], input[type=button], input[type=reset], input[type=image], summary'],
+		['checkbox', 'input[type=checkbox]'],
+		['radio', 'input[type=radio]'],
+		['select', 'select'],
+		['input', 'input, textarea, [contenteditable=""], [contenteditable=true]']
+	];
+	const candidates = tagTypes.map(t => t[1]).join(', ') +
+		', [role], [onclick], [tabindex]:not([tabindex="-1"])';
+
+	function elementType(el) {
+		const role = el.getAttribute('role');
+		if (role && roleTypes[role]) {
+			return roleTypes[role];
+		}
+		for (const [type, sel] of tagTypes) {
+			if (el.matches(sel)) {
+				return type;
+			}
+		}
+		if (el.hasAttribute('onclick') || el.matches('[tabindex]:not([tabindex="-1"])')) {
+			return 'other';
+		}
+		return '';
+	}
+
+	const elements = [];
+	for (const el of document.querySelectorAll(candidates)) {
+		if (el.matches('input[type=hidden]')) {
+			continue;
+		}
+		const type = elementType(el);
+		if (!type || (types.length > 0 && !types.includes(type))) {
+			continue;
+		}
+		const r = el.getBoundingClientRect();
+		const shown = visible(el);
+		const item = {
+			type: type,
+			tag: el.tagName.toLowerCase(),
+			name: accessibleName(el),
+			selector: describe.call(el).selector,
+			disabled: !!el.disabled || el.getAttribute('aria-disabled') === 'true',
+			visible: shown,
+			inViewport: shown && r.bottom > 0 && r.right > 0 && r.top < innerHeight && r.left < innerWidth
+		};
+		if (type === 'link' && el.getAttribute('href')) {
+			item.href = el.getAttribute('href');
+		}
+		elements.push(item);
+	}
+	return {elements: elements};
+}`
+
+// handleElements lists the page's clickable and focusable elements, each
+// with a unique selector, its name, and its disabled and visibility state.
+func (d *Daemon) handleElements(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.ElementsParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid elements parameters: %v", err))
+		}
+	}
+	types, err := json.Marshal(append([]string{}, params.Types...))
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+
+	js := "(" + elementsJS + ")(" + string(types) + ")"
+
+	var data ipc.ElementsData
+	if _, err := d.evalElementQuery(activeID, js, &data); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to list elements: %v", err))
+	}
+	if data.Elements == nil {
+		data.Elements = []ipc.InteractiveElement{}
+	}
+	return ipc.SuccessResponse(data)
+}
+
+// handleSelector generates ranked selectors for one element: the first match
+// of params.From, or the topmost element at params.X, params.Y.
+func (d *Daemon) handleSelector(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.SelectorParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid selector parameters: %v", err))
+	}
+
+	var js, notFound string
+	if params.From != "" {
+		from, err := json.Marshal(params.From)
+		if err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		js = `(function() {
+			const found = document.querySelectorAll(` + string(from) + `);
+			if (found.length === 0) {
+				return null;
+			}
+			const result = (` + selectorCandidatesJS + `).call(found[0]);
+			result.fromMatches = found.length;
+			return result;
+		})()`
+		notFound = fmt.Sprintf("selector '%s' matched no elements", params.From)
+	} else {
+		js = fmt.Sprintf(`(function() {
+			const el = document.elementFromPoint(%g, %g);
+			return el ? (%s).call(el) : null;
+		})()`, params.X, params.Y, selectorCandidatesJS)
+		notFound = fmt.Sprintf("no element at %g,%g", params.X, params.Y)
+	}
+
+	var data ipc.SelectorData
+	found, err := d.evalElementQuery(activeID, js, &data)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to generate selectors: %v", err))
+	}
+	if !found {
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, notFound)
+	}
+	return ipc.SuccessResponse(data)
+}
@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// eventBufferSize is the capacity of the daemon event buffer. Events are rare
+// next to console and network entries, so it is fixed rather than configured.
+const eventBufferSize = 1000
+
+// recordEvent adds an operational event to the event buffer for 'webctl
+// events'. level is "info", "warn", or "error"; attrs are key-value pairs, as
+// for slog.
+func (d *Daemon) recordEvent(level, typ, msg string, attrs ...any) {
+	e := ipc.DaemonEvent{
+		Timestamp: time.Now().UnixMilli(),
+		Level:     level,
+		Type:      typ,
+		Message:   msg,
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		key, ok := attrs[i].(string)
+		if !ok {
+			continue
+		}
+		if e.Attrs == nil {
+			e.Attrs = make(map[string]any)
+		}
+		if err, ok := attrs[i+1].(error); ok {
+			e.Attrs[key] = err.Error()
+		} else {
+			e.Attrs[key] = attrs[i+1]
+		}
+	}
+	d.eventBuf.Push(e)
+}
+
+// handleEvents returns the buffered daemon events, oldest first, after
+// params.After if set. It does not need the browser, so it is answered while
+// the browser restarts.
+func (d *Daemon) handleEvents(req ipc.Request) ipc.Response {
+	var params ipc.EventsParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid events parameters: %v", err))
+		}
+	}
+
+	events := []ipc.DaemonEvent{}
+	for _, e := range d.eventBuf.All() {
+		if e.Seq > params.After {
+			events = append(events, e)
+		}
+	}
+	return ipc.SuccessResponse(ipc.EventsData{Events: events, Dropped: d.eventBuf.Dropped()})
+}
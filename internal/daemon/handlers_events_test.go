@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestHandleEvents(t *testing.T) {
+	d := New(DefaultConfig())
+	d.recordEvent("info", "target_attached", "tab attached", "session", "S1", "url", "https://example.com")
+	d.recordEvent("warn", "browser_lost", "browser crashed", "error", errors.New("exit status 1"))
+	d.recordEvent("info", "target_detached", "tab detached")
+
+	send := func(params ipc.EventsParams) ipc.EventsData {
+		t.Helper()
+		raw, _ := json.Marshal(params)
+		resp := d.handleRequest(ipc.Request{Cmd: "events", Params: raw})
+		if !resp.OK {
+			t.Fatalf("events failed: %s", resp.Error)
+		}
+		var data ipc.EventsData
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	data := send(ipc.EventsParams{})
+	if len(data.Events) != 3 {
+		t.Fatalf("got %d events, want 3", len(data.Events))
+	}
+	first := data.Events[0]
+	if first.Seq != 1 || first.Type != "target_attached" || first.Attrs["url"] != "https://example.com" || first.Timestamp == 0 {
+		t.Errorf("first = %+v", first)
+	}
+	if got := data.Events[1].Attrs["error"]; got != "exit status 1" {
+		t.Errorf("error attr = %v, want the error's message", got)
+	}
+	if data.Events[2].Attrs != nil {
+		t.Errorf("attrs = %v, want none", data.Events[2].Attrs)
+	}
+
+	data = send(ipc.EventsParams{After: 2})
+	if len(data.Events) != 1 || data.Events[0].Seq != 3 {
+		t.Errorf("after 2 = %+v, want only seq 3", data.Events)
+	}
+	data = send(ipc.EventsParams{After: 3})
+	if data.Events == nil || len(data.Events) != 0 {
+		t.Errorf("after 3 = %#v, want an empty list", data.Events)
+	}
+}
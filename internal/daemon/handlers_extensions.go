@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/browser"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// extensionTarget is the part of a CDP TargetInfo that identifies an
+// extension's service worker, background page, or other page.
+type extensionTarget struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// handleExtensions handles the "extensions" command.
+func (d *Daemon) handleExtensions(req ipc.Request) ipc.Response {
+	var params ipc.ExtensionsParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid extensions parameters: %v", err))
+		}
+	}
+	if params.Action != "" && params.Action != "list" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown extensions action: %s", params.Action))
+	}
+
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := d.cdp.SendContext(ctx, "Target.getTargets", nil)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to get targets: %v", err))
+	}
+	var targets struct {
+		TargetInfos []extensionTarget `json:"targetInfos"`
+	}
+	if err := json.Unmarshal(result, &targets); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to parse targets: %v", err))
+	}
+
+	return ipc.SuccessResponse(ipc.ExtensionsData{
+		Extensions: listExtensions(d.config.Launch.Extensions, targets.TargetInfos),
+	})
+}
+
+// listExtensions lists the extensions loaded from the given directories, in
+// order, followed by any others that have a target open, such as ones
+// installed in the profile. Extensions with a target are marked active.
+func listExtensions(dirs []string, targets []extensionTarget) []ipc.ExtensionInfo {
+	active := make(map[string]string) // extension ID -> a target title
+	var order []string
+	for _, t := range targets {
+		u, err := url.Parse(t.URL)
+		if err != nil || u.Scheme != "chrome-extension" || u.Host == "" {
+			continue
+		}
+		if _, seen := active[u.Host]; !seen {
+			order = append(order, u.Host)
+			active[u.Host] = ""
+		}
+		// A page's title is a better name than a service worker's URL
+		if t.Type != "service_worker" && t.Title != "" && t.Title != t.URL {
+			active[u.Host] = t.Title
+		}
+	}
+
+	extensions := []ipc.ExtensionInfo{}
+	listed := make(map[string]bool)
+	for _, dir := range dirs {
+		ext, err := browser.ReadExtension(dir)
+		if err != nil {
+			extensions = append(extensions, ipc.ExtensionInfo{Path: dir, Error: err.Error()})
+			continue
+		}
+		_, ok := active[ext.ID]
+		extensions = append(extensions, ipc.ExtensionInfo{
+			ID:      ext.ID,
+			Name:    ext.Name,
+			Version: ext.Version,
+			Path:    ext.Path,
+			Active:  ok,
+		})
+		listed[ext.ID] = true
+	}
+	for _, id := range order {
+		if !listed[id] {
+			extensions = append(extensions, ipc.ExtensionInfo{ID: id, Name: active[id], Active: true})
+		}
+	}
+	return extensions
+}
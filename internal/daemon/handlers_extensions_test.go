@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/browser"
+)
+
+func TestListExtensions(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `{"manifest_version": 3, "name": "Dev Tool", "version": "1.2.0"}`
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ext, err := browser.ReadExtension(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(t.TempDir(), "gone")
+
+	targets := []extensionTarget{
+		{Type: "page", Title: "Example", URL: "https://example.com/"},
+		{Type: "service_worker", Title: "chrome-extension://" + ext.ID + "/sw.js", URL: "chrome-extension://" + ext.ID + "/sw.js"},
+		{Type: "service_worker", Title: "chrome-extension://abcdefghijklmnopabcdefghijklmnop/bg.js", URL: "chrome-extension://abcdefghijklmnopabcdefghijklmnop/bg.js"},
+		{Type: "page", Title: "Installed Options", URL: "chrome-extension://abcdefghijklmnopabcdefghijklmnop/options.html"},
+	}
+
+	got := listExtensions([]string{dir, missing}, targets)
+	if len(got) != 3 {
+		t.Fatalf("got %d extensions, want 3: %+v", len(got), got)
+	}
+	if got[0].ID != ext.ID || got[0].Name != "Dev Tool" || got[0].Version != "1.2.0" || !got[0].Active {
+		t.Errorf("loaded extension: got %+v", got[0])
+	}
+	if got[1].Path != missing || got[1].Error == "" || got[1].Active {
+		t.Errorf("unreadable extension: got %+v", got[1])
+	}
+	if got[2].ID != "abcdefghijklmnopabcdefghijklmnop" || got[2].Name != "Installed Options" || !got[2].Active {
+		t.Errorf("profile extension: got %+v", got[2])
+	}
+
+	if got := listExtensions(nil, nil); got == nil || len(got) != 0 {
+		t.Errorf("no extensions: got %#v, want an empty list", got)
+	}
+}
@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// cdpFrameTree mirrors a CDP Page.FrameTree node.
+type cdpFrameTree struct {
+	Frame struct {
+		ID             string `json:"id"`
+		ParentID       string `json:"parentId"`
+		URL            string `json:"url"`
+		URLFragment    string `json:"urlFragment"`
+		Name           string `json:"name"`
+		SecurityOrigin string `json:"securityOrigin"`
+	} `json:"frame"`
+	ChildFrames []cdpFrameTree `json:"childFrames"`
+}
+
+// handleFrames returns the active page's frame hierarchy.
+func (d *Daemon) handleFrames() ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := d.sendToSession(ctx, activeID, "Page.getFrameTree", nil)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to get frame tree: %v", err))
+	}
+
+	var resp struct {
+		FrameTree cdpFrameTree `json:"frameTree"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to parse frame tree: %v", err))
+	}
+
+	return ipc.SuccessResponse(ipc.FramesData{Frames: flattenFrameTree(resp.FrameTree, 0, nil)})
+}
+
+// flattenFrameTree appends the tree's frames depth-first, parents first.
+func flattenFrameTree(tree cdpFrameTree, depth int, frames []ipc.FrameInfo) []ipc.FrameInfo {
+	frames = append(frames, ipc.FrameInfo{
+		ID:             tree.Frame.ID,
+		ParentID:       tree.Frame.ParentID,
+		URL:            tree.Frame.URL + tree.Frame.URLFragment,
+		Name:           tree.Frame.Name,
+		SecurityOrigin: tree.Frame.SecurityOrigin,
+		Depth:          depth,
+	})
+	for _, child := range tree.ChildFrames {
+		frames = flattenFrameTree(child, depth+1, frames)
+	}
+	return frames
+}
+
+// setContextFrame records the frame an execution context belongs to. An empty
+// frameID forgets the context.
+func (d *Daemon) setContextFrame(sessionID string, contextID int, frameID string) {
+	d.contextFramesMu.Lock()
+	defer d.contextFramesMu.Unlock()
+	if frameID == "" {
+		delete(d.contextFrames[sessionID], contextID)
+		return
+	}
+	if d.contextFrames == nil {
+		d.contextFrames = make(map[string]map[int]string)
+	}
+	if d.contextFrames[sessionID] == nil {
+		d.contextFrames[sessionID] = make(map[int]string)
+	}
+	d.contextFrames[sessionID][contextID] = frameID
+}
+
+// contextFrame returns the frame ID for an execution context, or "" if the
+// context is unknown.
+func (d *Daemon) contextFrame(sessionID string, contextID int) string {
+	d.contextFramesMu.Lock()
+	defer d.contextFramesMu.Unlock()
+	return d.contextFrames[sessionID][contextID]
+}
+
+// clearContextFrames forgets all execution contexts for a session.
+func (d *Daemon) clearContextFrames(sessionID string) {
+	d.contextFramesMu.Lock()
+	delete(d.contextFrames, sessionID)
+	d.contextFramesMu.Unlock()
+}
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+)
+
+func TestFlattenFrameTree(t *testing.T) {
+	raw := `{
+		"frame": {"id": "main", "url": "https://shop.example.com/checkout", "urlFragment": "#pay", "securityOrigin": "https://shop.example.com"},
+		"childFrames": [
+			{
+				"frame": {"id": "stripe", "parentId": "main", "url": "https://js.stripe.com/v3/", "name": "__privateStripeFrame1"},
+				"childFrames": [{"frame": {"id": "inner", "parentId": "stripe", "url": "about:blank"}}]
+			},
+			{"frame": {"id": "ads", "parentId": "main", "url": "https://ads.example.net/"}}
+		]
+	}`
+	var tree cdpFrameTree
+	if err := json.Unmarshal([]byte(raw), &tree); err != nil {
+		t.Fatal(err)
+	}
+
+	frames := flattenFrameTree(tree, 0, nil)
+
+	wantIDs := []string{"main", "stripe", "inner", "ads"}
+	wantDepths := []int{0, 1, 2, 1}
+	if len(frames) != len(wantIDs) {
+		t.Fatalf("got %d frames, want %d", len(frames), len(wantIDs))
+	}
+	for i, f := range frames {
+		if f.ID != wantIDs[i] || f.Depth != wantDepths[i] {
+			t.Errorf("frame %d = %s@%d, want %s@%d", i, f.ID, f.Depth, wantIDs[i], wantDepths[i])
+		}
+	}
+	if frames[0].URL != "https://shop.example.com/checkout#pay" {
+		t.Errorf("main URL = %q, want fragment appended", frames[0].URL)
+	}
+	if frames[1].Name != "__privateStripeFrame1" || frames[1].ParentID != "main" {
+		t.Errorf("unexpected child frame: %+v", frames[1])
+	}
+}
+
+func TestDaemon_consoleEntriesTaggedWithFrame(t *testing.T) {
+	d := New(DefaultConfig())
+	d.setContextFrame("s1", 7, "frame-A")
+
+	consoleEvt := cdp.Event{
+		SessionID: "s1",
+		Params:    json.RawMessage(`{"type":"log","timestamp":1,"executionContextId":7,"args":[{"type":"string","value":"hi"}]}`),
+	}
+	entry, ok := d.parseConsoleEvent(consoleEvt)
+	if !ok || entry.FrameID != "frame-A" {
+		t.Errorf("console entry FrameID = %q, want frame-A", entry.FrameID)
+	}
+
+	exceptionEvt := cdp.Event{
+		SessionID: "s1",
+		Params:    json.RawMessage(`{"timestamp":1,"exceptionDetails":{"text":"Uncaught","executionContextId":7}}`),
+	}
+	entry, ok = d.parseExceptionEvent(exceptionEvt)
+	if !ok || entry.FrameID != "frame-A" {
+		t.Errorf("exception entry FrameID = %q, want frame-A", entry.FrameID)
+	}
+
+	// Destroyed and cleared contexts no longer resolve
+	d.setContextFrame("s1", 7, "")
+	if got := d.contextFrame("s1", 7); got != "" {
+		t.Errorf("destroyed context resolved to %q", got)
+	}
+	d.setContextFrame("s1", 8, "frame-B")
+	d.clearContextFrames("s1")
+	if got := d.contextFrame("s1", 8); got != "" {
+		t.Errorf("cleared context resolved to %q", got)
+	}
+}
+
+func TestDaemon_parseRequestEvent_frameID(t *testing.T) {
+	d := New(DefaultConfig())
+	entry, ok := d.parseRequestEvent(cdp.Event{
+		Params: json.RawMessage(`{"requestId":"1","frameId":"frame-A","request":{"url":"https://example.com/","method":"GET"}}`),
+	})
+	if !ok || entry.FrameID != "frame-A" {
+		t.Errorf("network entry FrameID = %q, want frame-A", entry.FrameID)
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"runtime"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/grantcarthew/webctl/internal/ipc"
 )
@@ -26,11 +30,11 @@ func (d *Daemon) handleClick(req ipc.Request) ipc.Response {
 
 	var params ipc.ClickParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid click parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid click parameters: %v", err))
 	}
 
 	if params.Selector == "" {
-		return ipc.ErrorResponse("selector is required")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -79,7 +83,7 @@ func (d *Daemon) handleClick(req ipc.Request) ipc.Response {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse element position: %v", err))
 	}
 	if evalResp.Result.Type == "undefined" || evalResp.Result.Value.Error == "not_found" {
-		return ipc.ErrorResponse(fmt.Sprintf("element not found: %s", params.Selector))
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("element not found: %s", params.Selector))
 	}
 
 	x := evalResp.Result.Value.X
@@ -135,11 +139,11 @@ func (d *Daemon) handleFocus(req ipc.Request) ipc.Response {
 
 	var params ipc.FocusParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid focus parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid focus parameters: %v", err))
 	}
 
 	if params.Selector == "" {
-		return ipc.ErrorResponse("selector is required")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -170,7 +174,7 @@ func (d *Daemon) handleFocus(req ipc.Request) ipc.Response {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse focus result: %v", err))
 	}
 	if !evalResp.Result.Value {
-		return ipc.ErrorResponse(fmt.Sprintf("element not found: %s", params.Selector))
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("element not found: %s", params.Selector))
 	}
 
 	return ipc.SuccessResponse(nil)
@@ -190,10 +194,16 @@ func (d *Daemon) handleType(req ipc.Request) ipc.Response {
 
 	var params ipc.TypeParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid type parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid type parameters: %v", err))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if params.DelayMin < 0 || params.DelayMax < params.DelayMin {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid delay: %d-%dms", params.DelayMin, params.DelayMax))
+	}
+
+	// Typing key by key takes as long as its pauses on top of the usual limit
+	timeout := 30*time.Second + time.Duration(utf8.RuneCountInString(params.Text)*params.DelayMax)*time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	// If selector provided, focus the element first
@@ -240,8 +250,16 @@ func (d *Daemon) handleType(req ipc.Request) ipc.Response {
 		}
 	}
 
-	// Insert text
-	if params.Text != "" {
+	// Insert text, compose it, or press a key per character with delays
+	if params.Text != "" && params.IME {
+		if err := d.typeIME(ctx, activeID, params.Text, params.DelayMin, params.DelayMax); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to compose text: %v", err))
+		}
+	} else if params.Text != "" && params.DelayMax > 0 {
+		if err := d.typeKeys(ctx, activeID, params.Text, params.DelayMin, params.DelayMax); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to type text: %v", err))
+		}
+	} else if params.Text != "" {
 		_, err := d.sendToSession(ctx, activeID, "Input.insertText", map[string]any{
 			"text": params.Text,
 		})
@@ -266,6 +284,111 @@ func (d *Daemon) handleType(req ipc.Request) ipc.Response {
 	return ipc.SuccessResponse(nil)
 }
 
+// typeIME enters text as an input method would: the composition grows one
+// character at a time, pausing a random minMs to maxMs between updates, and
+// is then committed. The page sees compositionstart, a compositionupdate per
+// character, and compositionend, as with CJK input or an emoji picker.
+func (d *Daemon) typeIME(ctx context.Context, sessionID, text string, minMs, maxMs int) error {
+	runes := []rune(text)
+	for i := range runes {
+		if i > 0 && maxMs > 0 {
+			if err := typingPause(ctx, minMs, maxMs); err != nil {
+				return err
+			}
+		}
+
+		// Selection offsets count UTF-16 code units, as in the page
+		composed := string(runes[:i+1])
+		end := len(utf16.Encode(runes[:i+1]))
+		if _, err := d.sendToSession(ctx, sessionID, "Input.imeSetComposition", map[string]any{
+			"text":           composed,
+			"selectionStart": end,
+			"selectionEnd":   end,
+		}); err != nil {
+			return err
+		}
+	}
+	_, err := d.sendToSession(ctx, sessionID, "Input.insertText", map[string]any{"text": text})
+	return err
+}
+
+// typeKeys types text one character at a time, each as a key down and up
+// with the character as its text, pausing a random minMs to maxMs between
+// characters. Widgets that only react to real key events (autocomplete,
+// per-keystroke validation) see it as typing rather than a paste.
+func (d *Daemon) typeKeys(ctx context.Context, sessionID, text string, minMs, maxMs int) error {
+	for i, r := range []rune(text) {
+		if i > 0 {
+			if err := typingPause(ctx, minMs, maxMs); err != nil {
+				return err
+			}
+		}
+
+		if err := d.typeChar(ctx, sessionID, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typingPause waits a random minMs to maxMs, or until ctx is done.
+func typingPause(ctx context.Context, minMs, maxMs int) error {
+	delay := time.Duration(minMs) * time.Millisecond
+	if maxMs > minMs {
+		delay += time.Duration(rand.IntN(maxMs-minMs+1)) * time.Millisecond
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// typeChar types one character as a key down, carrying the character as its
+// text, and a key up.
+func (d *Daemon) typeChar(ctx context.Context, sessionID string, r rune) error {
+	info := charKeyInfo(r)
+	down := map[string]any{
+		"type":                  "keyDown",
+		"key":                   info.key,
+		"code":                  info.code,
+		"windowsVirtualKeyCode": info.keyCode,
+		"text":                  info.text,
+		"unmodifiedText":        info.text,
+	}
+	if _, err := d.sendToSession(ctx, sessionID, "Input.dispatchKeyEvent", down); err != nil {
+		return err
+	}
+	up := map[string]any{
+		"type":                  "keyUp",
+		"key":                   info.key,
+		"code":                  info.code,
+		"windowsVirtualKeyCode": info.keyCode,
+	}
+	_, err := d.sendToSession(ctx, sessionID, "Input.dispatchKeyEvent", up)
+	return err
+}
+
+// charKeyInfo returns CDP key parameters for typing a character. Letters,
+// digits, space, and newline get the physical key a US keyboard would use;
+// other characters only carry their text, which is all the page needs to
+// insert them.
+func charKeyInfo(r rune) keyInfo {
+	s := string(r)
+	switch {
+	case r == '\n' || r == '\r':
+		return getKeyInfo("Enter")
+	case r == ' ':
+		return keyInfo{key: " ", code: "Space", keyCode: 32, text: " "}
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		return keyInfo{key: s, code: "Key" + strings.ToUpper(s), keyCode: int(unicode.ToUpper(r)), text: s}
+	case r >= '0' && r <= '9':
+		return keyInfo{key: s, code: "Digit" + s, keyCode: int(r), text: s}
+	}
+	return keyInfo{key: s, text: s}
+}
+
 // handleKey sends a keyboard key event.
 func (d *Daemon) handleKey(req ipc.Request) ipc.Response {
 	// Check if browser is connected (fail-fast if not)
@@ -280,36 +403,78 @@ func (d *Daemon) handleKey(req ipc.Request) ipc.Response {
 
 	var params ipc.KeyParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid key parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid key parameters: %v", err))
 	}
 
-	if params.Key == "" {
-		return ipc.ErrorResponse("key is required")
+	chords := params.Sequence
+	if len(chords) == 0 {
+		if params.Key == "" {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "key is required")
+		}
+		chords = []ipc.KeyChord{{Key: params.Key, Ctrl: params.Ctrl, Alt: params.Alt, Shift: params.Shift, Meta: params.Meta}}
+	}
+	for _, c := range chords {
+		if c.Key == "" {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "key is required for every step of a sequence")
+		}
+	}
+	repeat := params.Repeat
+	if repeat == 0 {
+		repeat = 1
+	}
+	if repeat < 0 || params.Delay < 0 {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "invalid key parameters: repeat and delay must not be negative")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	presses := repeat * len(chords)
+	timeout := 30*time.Second + time.Duration(presses*params.Delay)*time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	for i := 0; i < presses; i++ {
+		if i > 0 && params.Delay > 0 {
+			select {
+			case <-ctx.Done():
+				return ipc.ErrorResponse(fmt.Sprintf("failed to send key: %v", ctx.Err()))
+			case <-time.After(time.Duration(params.Delay) * time.Millisecond):
+			}
+		}
+		if err := d.pressKey(ctx, activeID, chords[i%len(chords)]); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to send key: %v", err))
+		}
+	}
+
+	return ipc.SuccessResponse(nil)
+}
+
+// pressKey presses and releases a key with its modifiers held. A single
+// character with no modifiers is typed, as the keyboard would.
+func (d *Daemon) pressKey(ctx context.Context, sessionID string, chord ipc.KeyChord) error {
+	if !chord.Ctrl && !chord.Alt && !chord.Shift && !chord.Meta && utf8.RuneCountInString(chord.Key) == 1 {
+		r, _ := utf8.DecodeRuneInString(chord.Key)
+		return d.typeChar(ctx, sessionID, r)
+	}
+
 	// Calculate modifiers bitmap: Alt=1, Ctrl=2, Meta=4, Shift=8
 	modifiers := 0
-	if params.Alt {
+	if chord.Alt {
 		modifiers |= 1
 	}
-	if params.Ctrl {
+	if chord.Ctrl {
 		modifiers |= 2
 	}
-	if params.Meta {
+	if chord.Meta {
 		modifiers |= 4
 	}
-	if params.Shift {
+	if chord.Shift {
 		modifiers |= 8
 	}
 
 	// Map key names to CDP key info
-	keyInfo := getKeyInfo(params.Key)
+	keyInfo := getKeyInfo(chord.Key)
 
 	// keyDown
-	_, err := d.sendToSession(ctx, activeID, "Input.dispatchKeyEvent", map[string]any{
+	_, err := d.sendToSession(ctx, sessionID, "Input.dispatchKeyEvent", map[string]any{
 		"type":                  "keyDown",
 		"key":                   keyInfo.key,
 		"code":                  keyInfo.code,
@@ -317,13 +482,13 @@ func (d *Daemon) handleKey(req ipc.Request) ipc.Response {
 		"modifiers":             modifiers,
 	})
 	if err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("failed to send key: %v", err))
+		return err
 	}
 
 	// For Enter key, send a char event to trigger keypress DOM event
 	// Some web apps listen for keypress instead of keydown
 	if keyInfo.text != "" {
-		_, err = d.sendToSession(ctx, activeID, "Input.dispatchKeyEvent", map[string]any{
+		_, err = d.sendToSession(ctx, sessionID, "Input.dispatchKeyEvent", map[string]any{
 			"type":                  "char",
 			"key":                   keyInfo.key,
 			"code":                  keyInfo.code,
@@ -332,23 +497,19 @@ func (d *Daemon) handleKey(req ipc.Request) ipc.Response {
 			"modifiers":             modifiers,
 		})
 		if err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("failed to send key: %v", err))
+			return err
 		}
 	}
 
 	// keyUp
-	_, err = d.sendToSession(ctx, activeID, "Input.dispatchKeyEvent", map[string]any{
+	_, err = d.sendToSession(ctx, sessionID, "Input.dispatchKeyEvent", map[string]any{
 		"type":                  "keyUp",
 		"key":                   keyInfo.key,
 		"code":                  keyInfo.code,
 		"windowsVirtualKeyCode": keyInfo.keyCode,
 		"modifiers":             modifiers,
 	})
-	if err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("failed to send key: %v", err))
-	}
-
-	return ipc.SuccessResponse(nil)
+	return err
 }
 
 // keyInfo holds CDP key event parameters.
@@ -419,28 +580,54 @@ func (d *Daemon) handleSelect(req ipc.Request) ipc.Response {
 
 	var params ipc.SelectParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid select parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid select parameters: %v", err))
 	}
 
 	if params.Selector == "" {
-		return ipc.ErrorResponse("selector is required")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required")
 	}
-	if params.Value == "" {
-		return ipc.ErrorResponse("value is required")
+	values := params.Values
+	if params.Value != "" {
+		values = append([]string{params.Value}, values...)
 	}
+	if len(values) == 0 && len(params.Labels) == 0 {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "value is required")
+	}
+	valuesJSON, _ := json.Marshal(values)
+	labelsJSON, _ := json.Marshal(params.Labels)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Select using JavaScript
+	// Select using JavaScript. The matched options replace the selection, and
+	// input and change fire as they do when the user picks.
 	js := fmt.Sprintf(`(() => {
 		const el = document.querySelector(%q);
-		if (!el) return 'not_found';
-		if (el.tagName !== 'SELECT') return 'not_select';
-		el.value = %q;
+		if (!el) return {status: 'not_found'};
+		if (el.tagName !== 'SELECT') return {status: 'not_select'};
+		const values = %s, labels = %s;
+		const norm = s => s.trim().replace(/\s+/g, ' ');
+		const options = Array.from(el.options);
+		const picked = new Set();
+		for (const v of values) {
+			const o = options.find(o => o.value === v);
+			if (!o) return {status: 'no_option', missing: 'value ' + JSON.stringify(v)};
+			picked.add(o);
+		}
+		for (const l of labels) {
+			const o = options.find(o => norm(o.label) === norm(l));
+			if (!o) return {status: 'no_option', missing: 'label ' + JSON.stringify(l)};
+			picked.add(o);
+		}
+		if (picked.size > 1 && !el.multiple) return {status: 'not_multiple', count: picked.size};
+		for (const o of options) o.selected = picked.has(o);
+		el.dispatchEvent(new Event('input', {bubbles: true}));
 		el.dispatchEvent(new Event('change', {bubbles: true}));
-		return 'ok';
-	})()`, params.Selector, params.Value)
+		return {
+			status: 'ok',
+			selected: Array.from(el.selectedOptions).map(o => ({index: o.index, value: o.value, label: norm(o.label)})),
+		};
+	})()`, params.Selector, valuesJSON, labelsJSON)
 
 	result, err := d.sendToSession(ctx, activeID, "Runtime.evaluate", map[string]any{
 		"expression":    js,
@@ -452,25 +639,119 @@ func (d *Daemon) handleSelect(req ipc.Request) ipc.Response {
 
 	var evalResp struct {
 		Result struct {
-			Value string `json:"value"`
+			Value struct {
+				Status   string               `json:"status"`
+				Missing  string               `json:"missing"`
+				Count    int                  `json:"count"`
+				Selected []ipc.SelectedOption `json:"selected"`
+			} `json:"value"`
 		} `json:"result"`
 	}
 	if err := json.Unmarshal(result, &evalResp); err != nil {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse select result: %v", err))
 	}
 
-	switch evalResp.Result.Value {
+	v := evalResp.Result.Value
+	switch v.Status {
 	case "not_found":
-		return ipc.ErrorResponse(fmt.Sprintf("element not found: %s", params.Selector))
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("element not found: %s", params.Selector))
 	case "not_select":
 		return ipc.ErrorResponse(fmt.Sprintf("element is not a select: %s", params.Selector))
+	case "no_option":
+		return ipc.ErrorResponse(fmt.Sprintf("no option with %s in %s", v.Missing, params.Selector))
+	case "not_multiple":
+		return ipc.ErrorResponse(fmt.Sprintf("%s is not a multiple select: %d options requested", params.Selector, v.Count))
 	case "ok":
-		return ipc.SuccessResponse(nil)
+		return ipc.SuccessResponse(ipc.SelectData{Selected: v.Selected})
 	default:
 		return ipc.ErrorResponse("unexpected select result")
 	}
 }
 
+// handleCheck sets a checkbox or radio button to a state. Unlike click, it
+// leaves an element already in that state alone.
+func (d *Daemon) handleCheck(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.CheckParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid check parameters: %v", err))
+	}
+
+	if params.Selector == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// The state is changed with click(), so the page sees the click, input,
+	// and change events a user's click fires, and a handler that cancels the
+	// click shows up as an unchanged state. A <label> stands for its control.
+	js := fmt.Sprintf(`(() => {
+		let el = document.querySelector(%q);
+		if (!el) return {status: 'not_found'};
+		if (el.tagName === 'LABEL' && el.control) el = el.control;
+		const type = el.tagName === 'INPUT' ? el.type : el.tagName.toLowerCase();
+		if (type !== 'checkbox' && type !== 'radio') return {status: 'wrong_type', type};
+		const want = %t;
+		if (el.checked === want) return {status: 'ok', checked: el.checked, changed: false};
+		if (type === 'radio' && !want) return {status: 'radio_uncheck'};
+		if (el.disabled) return {status: 'disabled'};
+		el.click();
+		if (el.checked !== want) return {status: 'unchanged'};
+		return {status: 'ok', checked: el.checked, changed: true};
+	})()`, params.Selector, params.Checked)
+
+	result, err := d.sendToSession(ctx, activeID, "Runtime.evaluate", map[string]any{
+		"expression":    js,
+		"returnByValue": true,
+	})
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to set checked state: %v", err))
+	}
+
+	var evalResp struct {
+		Result struct {
+			Value struct {
+				Status  string `json:"status"`
+				Type    string `json:"type"`
+				Checked bool   `json:"checked"`
+				Changed bool   `json:"changed"`
+			} `json:"value"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(result, &evalResp); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to parse check result: %v", err))
+	}
+
+	v := evalResp.Result.Value
+	switch v.Status {
+	case "not_found":
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("element not found: %s", params.Selector))
+	case "wrong_type":
+		return ipc.ErrorResponse(fmt.Sprintf("element is not a checkbox or radio button: %s is %s", params.Selector, v.Type))
+	case "radio_uncheck":
+		return ipc.ErrorResponse(fmt.Sprintf("cannot uncheck radio button %s: check another option in its group", params.Selector))
+	case "disabled":
+		return ipc.ErrorResponse(fmt.Sprintf("element is disabled: %s", params.Selector))
+	case "unchanged":
+		return ipc.ErrorResponse(fmt.Sprintf("state of %s did not change: a click handler may have prevented it", params.Selector))
+	case "ok":
+		return ipc.SuccessResponse(ipc.CheckData{Checked: v.Checked, Changed: v.Changed})
+	default:
+		return ipc.ErrorResponse("unexpected check result")
+	}
+}
+
 // handleScroll scrolls to an element or position.
 func (d *Daemon) handleScroll(req ipc.Request) ipc.Response {
 	// Check if browser is connected (fail-fast if not)
@@ -485,41 +766,63 @@ func (d *Daemon) handleScroll(req ipc.Request) ipc.Response {
 
 	var params ipc.ScrollParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid scroll parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid scroll parameters: %v", err))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	behavior := "instant"
+	if params.Smooth {
+		behavior = "smooth"
+	}
 
-	var js string
+	var scroll string
 	switch params.Mode {
 	case "element":
 		if params.Selector == "" {
-			return ipc.ErrorResponse("selector is required for element scroll")
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required for element scroll")
 		}
-		js = fmt.Sprintf(`(() => {
-			const el = document.querySelector(%q);
+		scroll = fmt.Sprintf(`const el = document.querySelector(%q);
 			if (!el) return false;
-			el.scrollIntoView({block: 'center', behavior: 'instant'});
-			return true;
-		})()`, params.Selector)
+			el.scrollIntoView({block: 'center', behavior: %q});`, params.Selector, behavior)
 	case "to":
-		js = fmt.Sprintf(`(() => {
-			window.scrollTo({left: %d, top: %d, behavior: 'instant'});
-			return true;
-		})()`, params.ToX, params.ToY)
+		scroll = fmt.Sprintf(`window.scrollTo({left: %d, top: %d, behavior: %q});`, params.ToX, params.ToY, behavior)
 	case "by":
-		js = fmt.Sprintf(`(() => {
-			window.scrollBy({left: %d, top: %d, behavior: 'instant'});
-			return true;
-		})()`, params.ByX, params.ByY)
+		scroll = fmt.Sprintf(`window.scrollBy({left: %d, top: %d, behavior: %q});`, params.ByX, params.ByY, behavior)
+	case "top":
+		scroll = fmt.Sprintf(`window.scrollTo({top: 0, behavior: %q});`, behavior)
+	case "bottom":
+		if params.UntilIdle {
+			return d.scrollUntilIdle(activeID, params, behavior)
+		}
+		scroll = fmt.Sprintf(`window.scrollTo({top: document.scrollingElement.scrollHeight, behavior: %q});`, behavior)
+	case "page":
+		if params.Pages == 0 {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "pages is required for page scroll")
+		}
+		scroll = fmt.Sprintf(`window.scrollBy({top: %d * window.innerHeight, behavior: %q});`, params.Pages, behavior)
 	default:
-		return ipc.ErrorResponse("invalid scroll mode: must be 'element', 'to', or 'by'")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "invalid scroll mode: must be 'element', 'to', 'by', 'top', 'bottom', or 'page'")
+	}
+	if params.UntilIdle {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "invalid scroll: until-idle requires bottom mode")
 	}
 
+	settle := ""
+	if params.Smooth {
+		settle = scrollSettleJS
+	}
+	js := fmt.Sprintf(`(async () => {
+		%s
+		%s
+		return true;
+	})()`, scroll, settle)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
 	result, err := d.sendToSession(ctx, activeID, "Runtime.evaluate", map[string]any{
 		"expression":    js,
 		"returnByValue": true,
+		"awaitPromise":  true,
 	})
 	if err != nil {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to scroll: %v", err))
@@ -534,8 +837,104 @@ func (d *Daemon) handleScroll(req ipc.Request) ipc.Response {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse scroll result: %v", err))
 	}
 	if !evalResp.Result.Value {
-		return ipc.ErrorResponse(fmt.Sprintf("element not found: %s", params.Selector))
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("element not found: %s", params.Selector))
 	}
 
 	return ipc.SuccessResponse(nil)
 }
+
+// scrollSettleJS waits, inside an async function, until the window's scroll
+// position has held still for 150ms, or 5s have passed, so a smooth scroll
+// has finished before the command returns.
+const scrollSettleJS = `{
+		let last = '', still = 0;
+		for (let i = 0; i < 100 && still < 3; i++) {
+			await new Promise(r => setTimeout(r, 50));
+			const pos = window.scrollX + ',' + window.scrollY;
+			still = pos === last ? still + 1 : 0;
+			last = pos;
+		}
+	}`
+
+// Defaults for an until-idle scroll.
+const (
+	scrollDefaultIdle    = time.Second
+	scrollDefaultTimeout = 30 * time.Second
+)
+
+// scrollUntilIdle scrolls to the bottom of the page repeatedly, for
+// infinite-scroll pages that load more content as the end comes into view,
+// until the page height stops growing for the idle window.
+func (d *Daemon) scrollUntilIdle(sessionID string, params ipc.ScrollParams, behavior string) ipc.Response {
+	idle := time.Duration(params.Idle) * time.Millisecond
+	if idle <= 0 {
+		idle = scrollDefaultIdle
+	}
+	timeout := time.Duration(params.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = scrollDefaultTimeout
+	}
+	settle := ""
+	if params.Smooth {
+		settle = scrollSettleJS
+	}
+
+	js := fmt.Sprintf(`(async () => {
+		const idle = %d, deadline = Date.now() + %d;
+		const height = () => document.scrollingElement.scrollHeight;
+		let scrolls = 0;
+		for (;;) {
+			const before = height();
+			window.scrollTo({top: before, behavior: %q});
+			scrolls++;
+			%s
+			const waited = Date.now();
+			while (height() === before && Date.now() - waited < idle && Date.now() < deadline) {
+				await new Promise(r => setTimeout(r, 100));
+			}
+			if (height() === before) {
+				return {scrolls, height: before, idle: Date.now() - waited >= idle};
+			}
+			if (Date.now() >= deadline) {
+				return {scrolls, height: height(), idle: false};
+			}
+		}
+	})()`, idle.Milliseconds(), timeout.Milliseconds(), behavior, settle)
+
+	// The page keeps the deadline; the extra time covers the last idle wait
+	// and the round trip.
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+idle+5*time.Second)
+	defer cancel()
+
+	result, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+		"expression":    js,
+		"returnByValue": true,
+		"awaitPromise":  true,
+	})
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to scroll: %v", err))
+	}
+
+	var evalResp struct {
+		Result struct {
+			Value struct {
+				ipc.ScrollData
+				Idle bool `json:"idle"`
+			} `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+	if err := json.Unmarshal(result, &evalResp); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to parse scroll result: %v", err))
+	}
+	if evalResp.ExceptionDetails != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to scroll: %s", evalResp.ExceptionDetails.Text))
+	}
+	v := evalResp.Result.Value
+	if !v.Idle {
+		return ipc.CodedErrorResponse(ipc.CodeTimeout, fmt.Sprintf("timeout: page height still growing after %d scrolls (%dpx)", v.Scrolls, v.Height))
+	}
+	return ipc.SuccessResponse(v.ScrollData)
+}
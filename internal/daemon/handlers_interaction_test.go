@@ -1,6 +1,13 @@
 package daemon
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+)
 
 func TestGetKeyInfo(t *testing.T) {
 	tests := []struct {
@@ -72,3 +79,57 @@ func TestGetKeyInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestCharKeyInfo(t *testing.T) {
+	tests := []struct {
+		char        rune
+		wantKey     string
+		wantCode    string
+		wantKeyCode int
+		wantText    string
+	}{
+		{'a', "a", "KeyA", 65, "a"},
+		{'Q', "Q", "KeyQ", 81, "Q"},
+		{'7', "7", "Digit7", 55, "7"},
+		{' ', " ", "Space", 32, " "},
+		{'\n', "Enter", "Enter", 13, "\r"},
+		{'@', "@", "", 0, "@"},
+		{'é', "é", "", 0, "é"},
+	}
+	for _, tt := range tests {
+		got := charKeyInfo(tt.char)
+		if got.key != tt.wantKey || got.code != tt.wantCode || got.keyCode != tt.wantKeyCode || got.text != tt.wantText {
+			t.Errorf("charKeyInfo(%q) = %+v", tt.char, got)
+		}
+	}
+}
+
+func TestTypeIME(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://app.test/", "App")
+
+	if err := d.typeIME(context.Background(), "AAA1", "日😀", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	var steps []string
+	for _, req := range conn.getCapturedRequests() {
+		params, _ := req.Params.(map[string]any)
+		switch req.Method {
+		case "Input.imeSetComposition":
+			steps = append(steps, fmt.Sprintf("compose %s %v-%v", params["text"], params["selectionStart"], params["selectionEnd"]))
+		case "Input.insertText":
+			steps = append(steps, fmt.Sprintf("commit %s", params["text"]))
+		default:
+			t.Errorf("unexpected %s", req.Method)
+		}
+	}
+	// The emoji is two UTF-16 code units, so the caret moves by two
+	want := "compose 日 1-1, compose 日😀 3-3, commit 日😀"
+	if got := strings.Join(steps, ", "); got != want {
+		t.Errorf("steps = %s, want %s", got, want)
+	}
+}
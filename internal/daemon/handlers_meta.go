@@ -0,0 +1,135 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// metaJS collects the page's metadata for the "meta" command. OpenGraph
+// covers the og: namespace and the object types it defines (article:,
+// product:, ...); both it and Twitter cards are read from property or name,
+// since sites use either. Microdata values follow the HTML spec's per-element
+// rules (content, src, href, datetime, value, or text).
+const metaJS = `(function() {
+	const abs = href => {
+		try {
+			return new URL(href, document.baseURI).href;
+		} catch (e) {
+			return href;
+		}
+	};
+	const metaContent = name => {
+		const el = document.querySelector('meta[name="' + name + '" i]');
+		return el ? (el.getAttribute('content') || '') : '';
+	};
+
+	const ogPrefix = /^(og|article|book|profile|product|music|video|fb):/i;
+	const openGraph = [];
+	const twitter = [];
+	for (const el of document.querySelectorAll('meta[property], meta[name]')) {
+		const property = el.getAttribute('property') || el.getAttribute('name');
+		const tag = {property: property, content: el.getAttribute('content') || ''};
+		if (ogPrefix.test(property)) {
+			openGraph.push(tag);
+		} else if (/^twitter:/i.test(property)) {
+			twitter.push(tag);
+		}
+	}
+
+	const canonical = document.querySelector('link[rel~="canonical" i]');
+	const hreflang = Array.from(document.querySelectorAll('link[rel~="alternate" i][hreflang]'))
+		.map(el => ({lang: el.getAttribute('hreflang'), url: abs(el.getAttribute('href') || '')}));
+
+	const jsonLd = [];
+	const jsonLdErrors = [];
+	document.querySelectorAll('script[type="application/ld+json" i]').forEach((el, i) => {
+		try {
+			jsonLd.push(JSON.parse(el.textContent));
+		} catch (e) {
+			jsonLdErrors.push('block ' + (i + 1) + ': ' + e.message);
+		}
+	});
+
+	function itemValue(el) {
+		if (el.hasAttribute('itemscope')) {
+			return item(el);
+		}
+		switch (el.tagName) {
+		case 'META':
+			return el.getAttribute('content') || '';
+		case 'AUDIO': case 'EMBED': case 'IFRAME': case 'IMG': case 'SOURCE': case 'TRACK': case 'VIDEO':
+			return abs(el.getAttribute('src') || '');
+		case 'A': case 'AREA': case 'LINK':
+			return abs(el.getAttribute('href') || '');
+		case 'OBJECT':
+			return abs(el.getAttribute('data') || '');
+		case 'DATA': case 'METER':
+			return el.getAttribute('value') || '';
+		case 'TIME':
+			return el.getAttribute('datetime') || el.textContent.trim();
+		}
+		return el.textContent.replace(/\s+/g, ' ').trim();
+	}
+
+	// item walks the scope's descendants, stopping at nested scopes, whose
+	// properties belong to the nested item.
+	function item(scope) {
+		const result = {properties: {}};
+		const type = (scope.getAttribute('itemtype') || '').split(/\s+/).filter(Boolean);
+		if (type.length > 0) {
+			result.type = type;
+		}
+		if (scope.getAttribute('itemid')) {
+			result.id = scope.getAttribute('itemid');
+		}
+		const pending = Array.from(scope.children);
+		while (pending.length > 0) {
+			const el = pending.shift();
+			for (const name of (el.getAttribute('itemprop') || '').split(/\s+/).filter(Boolean)) {
+				(result.properties[name] = result.properties[name] || []).push(itemValue(el));
+			}
+			if (!el.hasAttribute('itemscope')) {
+				pending.push(...el.children);
+			}
+		}
+		return result;
+	}
+
+	const microdata = Array.from(document.querySelectorAll('[itemscope]:not([itemprop])')).map(item);
+
+	return {
+		url: location.href,
+		title: document.title,
+		description: metaContent('description'),
+		canonical: canonical ? abs(canonical.getAttribute('href') || '') : '',
+		robots: metaContent('robots'),
+		hreflang: hreflang,
+		openGraph: openGraph,
+		twitter: twitter,
+		jsonLd: jsonLd,
+		jsonLdErrors: jsonLdErrors,
+		microdata: microdata
+	};
+})()`
+
+// handleMeta returns the active page's SEO and social metadata: description,
+// canonical and hreflang links, OpenGraph and Twitter cards, JSON-LD, and
+// microdata.
+func (d *Daemon) handleMeta() ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var data ipc.MetaData
+	if _, err := d.evalElementQuery(activeID, metaJS, &data); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to read metadata: %v", err))
+	}
+	return ipc.SuccessResponse(data)
+}
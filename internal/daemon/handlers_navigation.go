@@ -14,7 +14,7 @@ import (
 // Returns immediately after sending Page.navigate without waiting for frameNavigated.
 // This avoids Chrome's internal blocking that occurs when waiting for navigation events.
 func (d *Daemon) handleNavigate(req ipc.Request) ipc.Response {
-	d.debugf(false, "handleNavigate called")
+	d.log.Debug("navigate: handling request")
 
 	// Check if browser is connected (fail-fast if not)
 	if ok, resp := d.requireBrowser(); !ok {
@@ -28,18 +28,31 @@ func (d *Daemon) handleNavigate(req ipc.Request) ipc.Response {
 
 	var params ipc.NavigateParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid navigate parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid navigate parameters: %v", err))
 	}
 
 	if params.URL == "" {
-		return ipc.ErrorResponse("url is required")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "url is required")
+	}
+
+	untilMode, untilArg, err := ipc.ParseWaitUntil(params.Until)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+
+	// Network idle counts in-flight requests from the network buffer, so the
+	// Network domain must be capturing before the navigation starts.
+	if params.Wait && untilMode == ipc.WaitUntilNetworkIdle {
+		if err := d.ensureNetworkEnabled(activeID); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
 	}
 
 	// Begin a navigation unconditionally, independent of --wait, so a later ready
 	// default-mode call can detect this navigation as in-flight. begin atomically
 	// cancels and replaces any prior navigation for the session.
 	nav := d.navTracker.begin(activeID)
-	d.debugf(false, "navigate: began navigation for session %s", activeID)
+	d.log.Debug("navigate: began navigation", "session", activeID)
 
 	// Send navigate command
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -64,19 +77,16 @@ func (d *Daemon) handleNavigate(req ipc.Request) ipc.Response {
 		return ipc.ErrorResponse(navResp.ErrorText)
 	}
 
-	// If wait requested, wait for full page load (Loaded milestone).
+	// If wait requested, wait for the chosen strategy (default: Loaded milestone).
 	if params.Wait {
 		timeout := cdp.DefaultTimeout
 		if params.Timeout > 0 {
 			timeout = time.Duration(params.Timeout) * time.Second
 		}
-		d.debugf(false, "navigate: waiting for page load (timeout=%v)", timeout)
+		d.log.Debug("navigate: waiting", "until", untilMode, "arg", untilArg, "timeout", timeout)
 
-		switch awaitMilestone(nav.Loaded(), nav.Cancelled(), timeout) {
-		case navCancelled:
-			return cancelledNavResponse(nav, activeID)
-		case navTimedOut:
-			return ipc.ErrorResponse("timeout waiting for page load")
+		if resp := d.awaitNavigateUntil(nav, activeID, untilMode, untilArg, timeout); !resp.OK {
+			return resp
 		}
 
 		// Get title after page load
@@ -94,13 +104,49 @@ func (d *Daemon) handleNavigate(req ipc.Request) ipc.Response {
 
 	// Return immediately - don't wait for frameNavigated.
 	// Chrome's Page.navigate response includes the URL we navigated to.
-	d.debugf(false, "navigate: returning immediately, frameId=%s", navResp.FrameID)
+	d.log.Debug("navigate: returning immediately", "frameId", navResp.FrameID)
 	return ipc.SuccessResponse(ipc.NavigateData{
 		URL:   params.URL,
 		Title: "", // Title not available until page loads
 	})
 }
 
+// awaitNavigateUntil blocks until navigation nav satisfies the wait strategy.
+// load and domcontentloaded await the navigation's milestones. networkidle waits
+// for DOM-ready first so the previous page's traffic is not mistaken for the new
+// page settling; selector and js wait for the new document to commit so they
+// cannot match the page being navigated away from. The timeout bounds the whole
+// wait. Returns a success response with no data when the strategy is met.
+func (d *Daemon) awaitNavigateUntil(nav *Navigation, sessionID, mode, arg string, timeout time.Duration) ipc.Response {
+	deadline := time.Now().Add(timeout)
+
+	milestone, label := nav.Loaded(), "page load"
+	switch mode {
+	case ipc.WaitUntilDOMContentLoaded, ipc.WaitUntilNetworkIdle:
+		milestone, label = nav.DOMReady(), "DOMContentLoaded"
+	case ipc.WaitUntilSelector, ipc.WaitUntilJS:
+		milestone, label = nav.FrameNavigated(), "navigation to commit"
+	}
+
+	switch awaitMilestone(milestone, nav.Cancelled(), timeout) {
+	case navCancelled:
+		return cancelledNavResponse(nav, sessionID)
+	case navTimedOut:
+		return ipc.CodedErrorResponse(ipc.CodeTimeout, "timeout waiting for "+label)
+	}
+
+	remaining := time.Until(deadline)
+	switch mode {
+	case ipc.WaitUntilNetworkIdle:
+		return d.handleReadyNetworkIdle(sessionID, remaining)
+	case ipc.WaitUntilSelector:
+		return d.handleReadySelector(sessionID, arg, remaining)
+	case ipc.WaitUntilJS:
+		return d.handleReadyEval(sessionID, arg, remaining)
+	}
+	return ipc.SuccessResponse(nil)
+}
+
 // handleReload reloads the current page.
 // Returns immediately after sending Page.reload command.
 func (d *Daemon) handleReload(req ipc.Request) ipc.Response {
@@ -117,14 +163,23 @@ func (d *Daemon) handleReload(req ipc.Request) ipc.Response {
 	var params ipc.ReloadParams
 	if len(req.Params) > 0 {
 		if err := json.Unmarshal(req.Params, &params); err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("invalid reload parameters: %v", err))
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid reload parameters: %v", err))
+		}
+	}
+
+	var saved ipc.ScrollPosition
+	if params.PreserveScroll {
+		pos, err := d.evalScroll(activeID, readScrollJS)
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to read scroll position: %v", err))
 		}
+		saved = pos
 	}
 
 	// Begin a navigation unconditionally so a later ready can detect the reload as
 	// in-flight, independent of --wait.
 	nav := d.navTracker.begin(activeID)
-	d.debugf(false, "reload: began navigation for session %s", activeID)
+	d.log.Debug("reload: began navigation", "session", activeID)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -138,19 +193,20 @@ func (d *Daemon) handleReload(req ipc.Request) ipc.Response {
 		return ipc.ErrorResponse(fmt.Sprintf("reload failed: %v", err))
 	}
 
-	// If wait requested, wait for full page load (Loaded milestone).
-	if params.Wait {
+	// If wait requested, wait for full page load (Loaded milestone). Scroll
+	// can only be restored once the page has loaded.
+	if params.Wait || params.PreserveScroll {
 		timeout := cdp.DefaultTimeout
 		if params.Timeout > 0 {
 			timeout = time.Duration(params.Timeout) * time.Second
 		}
-		d.debugf(false, "reload: waiting for page load (timeout=%v)", timeout)
+		d.log.Debug("reload: waiting for page load", "timeout", timeout)
 
 		switch awaitMilestone(nav.Loaded(), nav.Cancelled(), timeout) {
 		case navCancelled:
 			return cancelledNavResponse(nav, activeID)
 		case navTimedOut:
-			return ipc.ErrorResponse("timeout waiting for page load")
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, "timeout waiting for page load")
 		}
 
 		// Get URL and title after page load
@@ -163,10 +219,18 @@ func (d *Daemon) handleReload(req ipc.Request) ipc.Response {
 		if session != nil {
 			url = session.URL
 		}
-		return ipc.SuccessResponse(ipc.NavigateData{
+		data := ipc.NavigateData{
 			URL:   url,
 			Title: title,
-		})
+		}
+		if params.PreserveScroll {
+			pos, err := d.evalScroll(activeID, fmt.Sprintf(restoreScrollJS, saved.X, saved.Y))
+			if err != nil {
+				return ipc.ErrorResponse(fmt.Sprintf("failed to restore scroll position: %v", err))
+			}
+			data.Scroll = &pos
+		}
+		return ipc.SuccessResponse(data)
 	}
 
 	// Get current URL from session for response
@@ -178,22 +242,74 @@ func (d *Daemon) handleReload(req ipc.Request) ipc.Response {
 
 	// Return immediately - don't wait for frameNavigated
 	// Session URL stays the same for reload, so no need to update
-	d.debugf(false, "reload: returning immediately")
+	d.log.Debug("reload: returning immediately")
 	return ipc.SuccessResponse(ipc.NavigateData{
 		URL:   currentURL,
 		Title: "", // Title not available until frameNavigated
 	})
 }
 
+// readScrollJS reads the window's scroll position.
+const readScrollJS = `({x: window.scrollX, y: window.scrollY})`
+
+// restoreScrollJS scrolls the window back to a saved position. Content that
+// loads after the load event can leave the page too short to reach it at
+// first, so it retries every 100ms for up to 3s. Format args: x, y.
+const restoreScrollJS = `(async () => {
+	const x = %g, y = %g;
+	for (let i = 0; i < 30; i++) {
+		window.scrollTo(x, y);
+		if (Math.abs(window.scrollX - x) < 1 && Math.abs(window.scrollY - y) < 1) {
+			break;
+		}
+		await new Promise(r => setTimeout(r, 100));
+	}
+	return {x: window.scrollX, y: window.scrollY};
+})()`
+
+// evalScroll evaluates js, which returns a scroll position or a promise of
+// one, in a session.
+func (d *Daemon) evalScroll(sessionID, js string) (ipc.ScrollPosition, error) {
+	var pos ipc.ScrollPosition
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+		"expression":    js,
+		"returnByValue": true,
+		"awaitPromise":  true,
+	})
+	if err != nil {
+		return pos, err
+	}
+
+	var evalResp struct {
+		Result struct {
+			Value ipc.ScrollPosition `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+	if err := json.Unmarshal(result, &evalResp); err != nil {
+		return pos, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if evalResp.ExceptionDetails != nil {
+		return pos, fmt.Errorf("JavaScript error: %s", evalResp.ExceptionDetails.Text)
+	}
+	return evalResp.Result.Value, nil
+}
+
 // handleBack navigates to the previous history entry.
 func (d *Daemon) handleBack(req ipc.Request) ipc.Response {
 	var params ipc.HistoryParams
 	if len(req.Params) > 0 {
 		if err := json.Unmarshal(req.Params, &params); err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("invalid back parameters: %v", err))
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid back parameters: %v", err))
 		}
 	}
-	return d.navigateHistory(-1, params, req.Debug)
+	return d.navigateHistory(-1, params)
 }
 
 // handleForward navigates to the next history entry.
@@ -201,15 +317,54 @@ func (d *Daemon) handleForward(req ipc.Request) ipc.Response {
 	var params ipc.HistoryParams
 	if len(req.Params) > 0 {
 		if err := json.Unmarshal(req.Params, &params); err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("invalid forward parameters: %v", err))
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid forward parameters: %v", err))
 		}
 	}
-	return d.navigateHistory(1, params, req.Debug)
+	return d.navigateHistory(1, params)
 }
 
 // navigateHistory navigates forward or backward in history.
 // Returns immediately after sending navigation command unless wait=true.
-func (d *Daemon) navigateHistory(delta int, params ipc.HistoryParams, debug bool) ipc.Response {
+func (d *Daemon) navigateHistory(delta int, params ipc.HistoryParams) ipc.Response {
+	return d.navigateHistoryTo(func(current, count int) (int, string) {
+		target := current + delta
+		if target < 0 {
+			return 0, "no previous page in history"
+		}
+		if target >= count {
+			return 0, "no next page in history"
+		}
+		return target, ""
+	}, params)
+}
+
+// cdpNavigationHistory mirrors the Page.getNavigationHistory result.
+type cdpNavigationHistory struct {
+	CurrentIndex int `json:"currentIndex"`
+	Entries      []struct {
+		ID    int    `json:"id"`
+		URL   string `json:"url"`
+		Title string `json:"title"`
+	} `json:"entries"`
+}
+
+// getNavigationHistory fetches the session's back/forward history.
+func (d *Daemon) getNavigationHistory(ctx context.Context, sessionID string) (cdpNavigationHistory, error) {
+	var history cdpNavigationHistory
+	result, err := d.sendToSession(ctx, sessionID, "Page.getNavigationHistory", nil)
+	if err != nil {
+		return history, fmt.Errorf("failed to get history: %v", err)
+	}
+	if err := json.Unmarshal(result, &history); err != nil {
+		return history, fmt.Errorf("failed to parse history: %v", err)
+	}
+	return history, nil
+}
+
+// navigateHistoryTo navigates to the history entry chosen by resolve, which maps
+// the current index and entry count to a target index or an error message.
+// Returns immediately after sending navigation command unless wait=true.
+func (d *Daemon) navigateHistoryTo(resolve func(current, count int) (int, string), params ipc.HistoryParams) ipc.Response {
 	// Check if browser is connected (fail-fast if not)
 	if ok, resp := d.requireBrowser(); !ok {
 		return resp
@@ -224,34 +379,28 @@ func (d *Daemon) navigateHistory(delta int, params ipc.HistoryParams, debug bool
 	defer cancel()
 
 	// Get navigation history
-	result, err := d.sendToSession(ctx, activeID, "Page.getNavigationHistory", nil)
+	history, err := d.getNavigationHistory(ctx, activeID)
 	if err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("failed to get history: %v", err))
+		return ipc.ErrorResponse(err.Error())
 	}
 
-	var history struct {
-		CurrentIndex int `json:"currentIndex"`
-		Entries      []struct {
-			ID  int    `json:"id"`
-			URL string `json:"url"`
-		} `json:"entries"`
-	}
-	if err := json.Unmarshal(result, &history); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("failed to parse history: %v", err))
+	targetIndex, errMsg := resolve(history.CurrentIndex, len(history.Entries))
+	if errMsg != "" {
+		return ipc.ErrorResponse(errMsg)
 	}
 
-	targetIndex := history.CurrentIndex + delta
-	if targetIndex < 0 {
-		return ipc.ErrorResponse("no previous page in history")
-	}
-	if targetIndex >= len(history.Entries) {
-		return ipc.ErrorResponse("no next page in history")
+	// Already there: Chrome would not navigate, so a --wait would never finish.
+	if targetIndex == history.CurrentIndex {
+		return ipc.SuccessResponse(ipc.NavigateData{
+			URL:   history.Entries[targetIndex].URL,
+			Title: history.Entries[targetIndex].Title,
+		})
 	}
 
 	// Begin a navigation unconditionally so a later ready can detect the history
 	// navigation as in-flight, independent of --wait.
 	nav := d.navTracker.begin(activeID)
-	d.debugf(debug, "navigateHistory: began navigation for session %s", activeID)
+	d.log.Debug("navigateHistory: began navigation", "session", activeID)
 
 	// Navigate to history entry
 	_, err = d.sendToSession(ctx, activeID, "Page.navigateToHistoryEntry", map[string]any{
@@ -270,14 +419,14 @@ func (d *Daemon) navigateHistory(delta int, params ipc.HistoryParams, debug bool
 		if params.Timeout > 0 {
 			timeout = time.Duration(params.Timeout) * time.Second
 		}
-		d.debugf(debug, "navigateHistory: waiting for frame navigation (timeout=%v)", timeout)
+		d.log.Debug("navigateHistory: waiting for frame navigation", "timeout", timeout)
 
 		targetURL := history.Entries[targetIndex].URL
 		switch awaitMilestone(nav.FrameNavigated(), nav.Cancelled(), timeout) {
 		case navCancelled:
 			return cancelledNavResponse(nav, activeID)
 		case navTimedOut:
-			return ipc.ErrorResponse(fmt.Sprintf("timeout waiting for navigation to %s", targetURL))
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, fmt.Sprintf("timeout waiting for navigation to %s", targetURL))
 		}
 
 		// FrameNavigated has closed; report the requested history-entry URL to stay
@@ -299,13 +448,64 @@ func (d *Daemon) navigateHistory(delta int, params ipc.HistoryParams, debug bool
 	// Update session URL immediately so REPL prompt reflects the change
 	d.sessions.Update(activeID, targetURL, "")
 
-	d.debugf(debug, "navigateHistory: returning immediately, target URL=%s", targetURL)
+	d.log.Debug("navigateHistory: returning immediately", "url", targetURL)
 	return ipc.SuccessResponse(ipc.NavigateData{
 		URL:   targetURL, // We know the target URL from history
 		Title: "",        // Title not available until frameNavigated
 	})
 }
 
+// handleHistory lists the session's navigation history or jumps to an entry.
+func (d *Daemon) handleHistory(req ipc.Request) ipc.Response {
+	var params ipc.HistoryParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid history parameters: %v", err))
+		}
+	}
+
+	if params.Action == "go" {
+		return d.navigateHistoryTo(func(current, count int) (int, string) {
+			if params.Index < 0 || params.Index >= count {
+				return 0, fmt.Sprintf("history index %d out of range (0-%d)", params.Index, count-1)
+			}
+			return params.Index, ""
+		}, params)
+	}
+
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	history, err := d.getNavigationHistory(ctx, activeID)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+
+	data := ipc.HistoryData{
+		CurrentIndex: history.CurrentIndex,
+		Entries:      make([]ipc.HistoryEntry, len(history.Entries)),
+	}
+	for i, e := range history.Entries {
+		data.Entries[i] = ipc.HistoryEntry{
+			Index:   i,
+			URL:     e.URL,
+			Title:   e.Title,
+			Current: i == history.CurrentIndex,
+		}
+	}
+	return ipc.SuccessResponse(data)
+}
+
 // handleReady waits for the page or application to be ready.
 // Supports multiple modes: page load, selector, network idle, and eval.
 func (d *Daemon) handleReady(req ipc.Request) ipc.Response {
@@ -322,7 +522,7 @@ func (d *Daemon) handleReady(req ipc.Request) ipc.Response {
 	var params ipc.ReadyParams
 	if len(req.Params) > 0 {
 		if err := json.Unmarshal(req.Params, &params); err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("invalid ready parameters: %v", err))
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid ready parameters: %v", err))
 		}
 	}
 
@@ -393,7 +593,7 @@ func (d *Daemon) handleReadySelector(sessionID, selector string, timeout time.Du
 	for {
 		select {
 		case <-ctx.Done():
-			return ipc.ErrorResponse(fmt.Sprintf("timeout waiting for: %s", selector))
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, fmt.Sprintf("timeout waiting for: %s", selector))
 		case <-ticker.C:
 			// Try to find the element
 			found, err := d.querySelector(ctx, sessionID, selector)
@@ -427,7 +627,7 @@ func (d *Daemon) handleReadyNetworkIdle(sessionID string, timeout time.Duration)
 	for {
 		select {
 		case <-ctx.Done():
-			return ipc.ErrorResponse("timeout waiting for network idle")
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, "timeout waiting for network idle")
 		case <-ticker.C:
 			pending := d.getPendingRequestCount(sessionID)
 			if pending == 0 {
@@ -454,7 +654,7 @@ func (d *Daemon) handleReadyEval(sessionID, expression string, timeout time.Dura
 	for {
 		select {
 		case <-ctx.Done():
-			return ipc.ErrorResponse(fmt.Sprintf("timeout waiting for: %s", expression))
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, fmt.Sprintf("timeout waiting for: %s", expression))
 		case <-ticker.C:
 			result, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
 				"expression":    expression,
@@ -531,7 +731,7 @@ func (d *Daemon) ensureNetworkEnabled(sessionID string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if _, err := d.sendToSession(ctx, sessionID, "Network.enable", networkEnableParams()); err != nil {
+	if _, err := d.sendToSession(ctx, sessionID, "Network.enable", d.networkEnableParams()); err != nil {
 		d.sessions.ClearNetworkEnabled(sessionID)
 		return fmt.Errorf("failed to enable Network domain: %v", err)
 	}
@@ -627,7 +827,7 @@ func (d *Daemon) waitForDOMReady(sessionID string, timeout time.Duration) error
 	nav := d.navTracker.current(sessionID)
 	if nav == nil {
 		// No navigation in flight; ready has nothing to wait for.
-		d.debugf(false, "waitForDOMReady: no navigation in flight for session %s", sessionID)
+		d.log.Debug("waitForDOMReady: no navigation in flight", "session", sessionID)
 		return nil
 	}
 	timer := time.NewTimer(timeout)
@@ -651,7 +851,7 @@ func (d *Daemon) waitForDOMReady(sessionID string, timeout time.Duration) error
 			case cancelAborted:
 				// The navigation failed to start, so the page is in whatever state it
 				// already held; there is nothing to wait for.
-				d.debugf(false, "waitForDOMReady: navigation aborted for session %s", sessionID)
+				d.log.Debug("waitForDOMReady: navigation aborted", "session", sessionID)
 				return nil
 			}
 			// Superseded: re-bind to the newer navigation and keep waiting. A nil
@@ -659,7 +859,7 @@ func (d *Daemon) waitForDOMReady(sessionID string, timeout time.Duration) error
 			// detach, or aborted because a start failed), so there is nothing left to
 			// wait for; report success, the same outcome as the no-navigation entry
 			// guard. A direct detach is still reported via the cancelDetached arm.
-			d.debugf(false, "waitForDOMReady: navigation superseded, re-binding for session %s", sessionID)
+			d.log.Debug("waitForDOMReady: navigation superseded, re-binding", "session", sessionID)
 			nav = d.navTracker.current(sessionID)
 			if nav == nil {
 				return nil
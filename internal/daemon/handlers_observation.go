@@ -2,10 +2,13 @@ package daemon
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,8 +16,28 @@ import (
 	"github.com/grantcarthew/webctl/internal/ipc"
 )
 
-// handleStatus returns the daemon status.
-func (d *Daemon) handleStatus() ipc.Response {
+// handleStatus returns the daemon status, with diagnostic detail when
+// params.Verbose is set.
+func (d *Daemon) handleStatus(req ipc.Request) ipc.Response {
+	var params ipc.StatusParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid status parameters: %v", err))
+		}
+	}
+
+	// Report a browser being replaced rather than wait for it
+	if !d.browserMu.TryRLock() {
+		return ipc.SuccessResponse(ipc.StatusData{
+			Running:         true,
+			PID:             os.Getpid(),
+			Socket:          d.config.SocketPath,
+			Restarting:      true,
+			BrowserRestarts: d.metrics.browserRestarts.Value(),
+		})
+	}
+	defer d.browserMu.RUnlock()
+
 	sessions := d.sessions.All()
 
 	// Look up HTTP status for each session from network buffer
@@ -23,7 +46,23 @@ func (d *Daemon) handleStatus() ipc.Response {
 	status := ipc.StatusData{
 		Running:  true,
 		PID:      os.Getpid(),
+		Socket:   d.config.SocketPath,
 		Sessions: sessions,
+
+		BrowserRestarts: d.metrics.browserRestarts.Value(),
+		Adopted:         d.browser != nil && d.browser.Adopted(),
+	}
+	if d.browser != nil && !d.browser.Adopted() {
+		status.Browser = &ipc.BrowserInfo{
+			Headless:    d.config.Headless,
+			Port:        d.config.Port,
+			UserDataDir: d.config.UserDataDir,
+			TempProfile: d.browser.TempProfile(),
+			LaunchFlags: d.config.Launch,
+		}
+		if dir := d.browser.DataDir(); dir != "" {
+			status.Browser.UserDataDir = dir
+		}
 	}
 
 	// Get active session info (find it in the already-enriched sessions list)
@@ -34,9 +73,139 @@ func (d *Daemon) handleStatus() ipc.Response {
 		}
 	}
 
+	if params.Verbose {
+		status.Detail = d.statusDetail(sessions)
+	}
+
 	return ipc.SuccessResponse(status)
 }
 
+// statusDetail gathers the diagnostic state for 'status --verbose'. A failure
+// to list workers leaves the list empty rather than failing the status.
+func (d *Daemon) statusDetail(sessions []ipc.PageSession) *ipc.StatusDetail {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	detail := &ipc.StatusDetail{
+		StartedAt:  d.started.UnixMilli(),
+		UptimeMs:   time.Since(d.started).Milliseconds(),
+		HeapBytes:  mem.HeapAlloc,
+		SysBytes:   mem.Sys,
+		Goroutines: runtime.NumGoroutine(),
+		Workers:    []ipc.WorkerInfo{},
+		Sessions:   sessionDetails(sessions, d.consoleBuf.All(), d.networkBuf.All()),
+	}
+
+	if e, cpu := d.config.Emulation, d.cpuThrottled(); e != (Emulation{}) || cpu > 0 {
+		detail.Emulation = &ipc.EmulationInfo{UserAgent: e.UserAgent, Throttle: e.Throttle, Stealth: e.Stealth, CPUThrottle: cpu}
+		if e.Width > 0 && e.Height > 0 {
+			detail.Emulation.Viewport = fmt.Sprintf("%dx%d", e.Width, e.Height)
+		}
+	}
+
+	for i := range detail.Sessions {
+		s := &detail.Sessions[i]
+		s.NetworkEnabled = d.sessions.NetworkEnabled(s.ID)
+		if navs := d.sessions.Navigations(s.ID); len(navs) > 0 {
+			s.LastNavigation = navs[len(navs)-1]
+		}
+		if nav := d.navTracker.current(s.ID); nav != nil {
+			s.NavigationPending = nav.pending()
+		}
+	}
+
+	if d.cdp != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if result, err := d.cdp.SendContext(ctx, "Target.getTargets", nil); err != nil {
+			d.log.Debug("status: failed to list workers", "error", err)
+		} else {
+			var targets struct {
+				TargetInfos []struct {
+					Type     string `json:"type"`
+					URL      string `json:"url"`
+					Attached bool   `json:"attached"`
+				} `json:"targetInfos"`
+			}
+			if err := json.Unmarshal(result, &targets); err == nil {
+				for _, t := range targets.TargetInfos {
+					if t.Type == "worker" || t.Type == "shared_worker" || t.Type == "service_worker" {
+						detail.Workers = append(detail.Workers, ipc.WorkerInfo{Type: t.Type, URL: t.URL, Attached: t.Attached})
+					}
+				}
+			}
+		}
+	}
+	return detail
+}
+
+// sessionDetails counts each session's buffered console and network entries
+// and summarizes its requests by origin, most requests first.
+func sessionDetails(sessions []ipc.PageSession, console []ipc.ConsoleEntry, network []ipc.NetworkEntry) []ipc.SessionDetail {
+	details := make([]ipc.SessionDetail, len(sessions))
+	index := make(map[string]int, len(sessions))
+	origins := make([]map[string]*ipc.OriginSummary, len(sessions))
+	for i, s := range sessions {
+		details[i] = ipc.SessionDetail{ID: s.ID, Origins: []ipc.OriginSummary{}}
+		index[s.ID] = i
+		origins[i] = map[string]*ipc.OriginSummary{}
+	}
+
+	for _, e := range console {
+		if i, ok := index[e.SessionID]; ok {
+			details[i].ConsoleEntries++
+		}
+	}
+	for _, e := range network {
+		i, ok := index[e.SessionID]
+		if !ok {
+			continue
+		}
+		details[i].NetworkEntries++
+		if e.ResponseTime == 0 && !e.Failed {
+			details[i].InFlight++
+		}
+		origin := requestOrigin(e.URL)
+		o := origins[i][origin]
+		if o == nil {
+			o = &ipc.OriginSummary{Origin: origin}
+			origins[i][origin] = o
+		}
+		o.Requests++
+		if e.Failed || e.Status >= 400 {
+			o.Errors++
+		}
+		o.Bytes += e.Size
+	}
+
+	for i := range details {
+		for _, o := range origins[i] {
+			details[i].Origins = append(details[i].Origins, *o)
+		}
+		sort.Slice(details[i].Origins, func(a, b int) bool {
+			oa, ob := details[i].Origins[a], details[i].Origins[b]
+			if oa.Requests != ob.Requests {
+				return oa.Requests > ob.Requests
+			}
+			return oa.Origin < ob.Origin
+		})
+	}
+	return details
+}
+
+// requestOrigin returns a URL's scheme and host, or its scheme alone for
+// URLs without a host such as data: and blob:.
+func requestOrigin(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return raw
+	}
+	if u.Host == "" {
+		return u.Scheme + ":"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
 // enrichSessionsWithHTTPStatus looks up the HTTP status code for each session
 // from the network buffer. Finds the most recent Document-type request matching
 // each session's URL.
@@ -77,20 +246,22 @@ func (d *Daemon) handleConsole() ipc.Response {
 	allEntries := d.consoleBuf.All()
 	var filtered []ipc.ConsoleEntry
 	for _, e := range allEntries {
-		if e.SessionID == activeID {
+		if e.SessionID == activeID || e.SessionID == restoredSession {
 			filtered = append(filtered, e)
 		}
 	}
 
 	return ipc.SuccessResponse(ipc.ConsoleData{
-		Entries: filtered,
-		Count:   len(filtered),
+		Entries:     filtered,
+		Count:       len(filtered),
+		Navigations: d.sessions.Navigations(activeID),
 	})
 }
 
 // handleNetwork returns buffered network entries filtered to active session.
 // Enables Network domain lazily on first call to avoid blocking Runtime.evaluate.
-func (d *Daemon) handleNetwork() ipc.Response {
+// With req.Stream set the entries are streamed as the NetworkData JSON.
+func (d *Daemon) handleNetwork(req ipc.Request) ipc.Response {
 	// Check if browser is connected (fail-fast if not)
 	if ok, resp := d.requireBrowser(); !ok {
 		return resp
@@ -107,22 +278,27 @@ func (d *Daemon) handleNetwork() ipc.Response {
 	if d.sessions.ClaimNetworkEnable(activeID) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		if _, err := d.sendToSession(ctx, activeID, "Network.enable", networkEnableParams()); err != nil {
+		if _, err := d.sendToSession(ctx, activeID, "Network.enable", d.networkEnableParams()); err != nil {
 			d.sessions.ClearNetworkEnabled(activeID)
-			d.debugf(false, "warning: failed to enable Network domain: %v", err)
+			d.log.Warn("failed to enable Network domain", "session", activeID, "error", err)
 		} else {
-			d.debugf(false, "Network domain enabled lazily for session %s", activeID)
+			d.log.Debug("Network domain enabled lazily", "session", activeID)
 		}
 	}
 
 	allEntries := d.networkBuf.All()
 	var filtered []ipc.NetworkEntry
 	for _, e := range allEntries {
-		if e.SessionID == activeID {
+		if e.SessionID == activeID || e.SessionID == restoredSession {
 			filtered = append(filtered, e)
 		}
 	}
 
+	if req.Stream {
+		return ipc.StreamResponse(func(w io.Writer) error {
+			return streamNetworkData(w, filtered)
+		})
+	}
 	return ipc.SuccessResponse(ipc.NetworkData{
 		Entries: filtered,
 		Count:   len(filtered),
@@ -145,8 +321,16 @@ func (d *Daemon) handleScreenshot(req ipc.Request) ipc.Response {
 	var params ipc.ScreenshotParams
 	if len(req.Params) > 0 {
 		if err := json.Unmarshal(req.Params, &params); err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("invalid screenshot parameters: %v", err))
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid screenshot parameters: %v", err))
+		}
+	}
+
+	// Draw the requested element boxes into the page for the capture
+	if len(params.Annotate) > 0 {
+		if err := d.annotate(activeID, params.Annotate); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to annotate screenshot: %v", err))
 		}
+		defer d.removeAnnotations(activeID)
 	}
 
 	// Build CDP request parameters
@@ -168,16 +352,20 @@ func (d *Daemon) handleScreenshot(req ipc.Request) ipc.Response {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to capture screenshot: %v", err))
 	}
 
-	// Parse CDP response
-	var cdpResp struct {
-		Data string `json:"data"` // base64-encoded PNG
-	}
-	if err := json.Unmarshal(result, &cdpResp); err != nil {
+	// Decode the base64 PNG straight from the CDP result
+	png, err := base64Field(result, "data")
+	if err != nil {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse screenshot response: %v", err))
 	}
 
-	// Decode base64 data
-	pngData, err := base64.StdEncoding.DecodeString(cdpResp.Data)
+	if req.Stream {
+		return ipc.StreamResponse(func(w io.Writer) error {
+			_, err := io.Copy(w, png)
+			return err
+		})
+	}
+
+	pngData, err := io.ReadAll(png)
 	if err != nil {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to decode screenshot data: %v", err))
 	}
@@ -190,8 +378,9 @@ func (d *Daemon) handleScreenshot(req ipc.Request) ipc.Response {
 // handleHTML extracts HTML from the current page or specified selector.
 // Gets window ObjectID first, then uses Runtime.callFunctionOn.
 // This avoids the networkIdle blocking that occurs with direct Runtime.evaluate.
+// With req.Stream set, a full page is streamed as the HTMLData JSON.
 func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
-	d.debugf(false, "handleHTML called")
+	d.log.Debug("html: handling request")
 
 	// Check if browser is connected (fail-fast if not)
 	if ok, resp := d.requireBrowser(); !ok {
@@ -207,10 +396,17 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 	var params ipc.HTMLParams
 	if len(req.Params) > 0 {
 		if err := json.Unmarshal(req.Params, &params); err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("invalid html parameters: %v", err))
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid html parameters: %v", err))
 		}
 	}
 
+	if params.Complete {
+		return d.handleHTMLComplete(activeID, req.Stream)
+	}
+	if params.Path && params.Selector == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "html path requires a selector")
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -223,11 +419,11 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 
 		// Step 1: Get window ObjectID using Runtime.evaluate.
 		// Chrome handles "window" specially - it's always available.
-		d.debugf(false, "html: calling Runtime.evaluate for window")
+		d.log.Debug("html: calling Runtime.evaluate for window")
 		windowResult, err := d.sendToSession(ctx, activeID, "Runtime.evaluate", map[string]any{
 			"expression": "window",
 		})
-		d.debugf(false, "html: Runtime.evaluate(window) completed in %v", time.Since(start))
+		d.log.Debug("html: Runtime.evaluate(window) completed", "duration", time.Since(start))
 		if err != nil {
 			return ipc.ErrorResponse(fmt.Sprintf("failed to get window: %v", err))
 		}
@@ -244,7 +440,7 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 			return ipc.ErrorResponse(fmt.Sprintf("failed to parse window response: %v", err))
 		}
 		if windowResp.ExceptionDetails != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("JavaScript error getting window: %s", windowResp.ExceptionDetails.Text))
+			return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("JavaScript error getting window: %s", windowResp.ExceptionDetails.Text))
 		}
 		if windowResp.Result.ObjectID == "" {
 			return ipc.ErrorResponse("window objectId is empty")
@@ -252,14 +448,14 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 
 		// Step 2: Use Runtime.callFunctionOn to get document.documentElement.
 		// By targeting the window object directly, we avoid context creation delays.
-		d.debugf(false, "html: calling Runtime.callFunctionOn for document.documentElement")
+		d.log.Debug("html: calling Runtime.callFunctionOn for document.documentElement")
 		callStart := time.Now()
 		callResult, err := d.sendToSession(ctx, activeID, "Runtime.callFunctionOn", map[string]any{
 			"objectId":            windowResp.Result.ObjectID,
 			"functionDeclaration": "function() { return document.documentElement; }",
 			"returnByValue":       false,
 		})
-		d.debugf(false, "html: Runtime.callFunctionOn completed in %v", time.Since(callStart))
+		d.log.Debug("html: Runtime.callFunctionOn completed", "duration", time.Since(callStart))
 		if err != nil {
 			return ipc.ErrorResponse(fmt.Sprintf("failed to get documentElement: %v", err))
 		}
@@ -276,19 +472,19 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 			return ipc.ErrorResponse(fmt.Sprintf("failed to parse callFunctionOn response: %v", err))
 		}
 		if callResp.ExceptionDetails != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("JavaScript error: %s", callResp.ExceptionDetails.Text))
+			return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("JavaScript error: %s", callResp.ExceptionDetails.Text))
 		}
 		if callResp.Result.ObjectID == "" {
 			return ipc.ErrorResponse("documentElement objectId is empty")
 		}
 
 		// Step 3: Get outer HTML using DOM.getOuterHTML with the ObjectID.
-		d.debugf(false, "html: calling DOM.getOuterHTML with objectId=%s", callResp.Result.ObjectID)
+		d.log.Debug("html: calling DOM.getOuterHTML", "objectId", callResp.Result.ObjectID)
 		htmlStart := time.Now()
 		htmlResult, err := d.sendToSession(ctx, activeID, "DOM.getOuterHTML", map[string]any{
 			"objectId": callResp.Result.ObjectID,
 		})
-		d.debugf(false, "html: DOM.getOuterHTML completed in %v", time.Since(htmlStart))
+		d.log.Debug("html: DOM.getOuterHTML completed", "duration", time.Since(htmlStart))
 		if err != nil {
 			return ipc.ErrorResponse(fmt.Sprintf("failed to get outer HTML: %v", err))
 		}
@@ -300,8 +496,13 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 			return ipc.ErrorResponse(fmt.Sprintf("failed to parse HTML response: %v", err))
 		}
 
-		d.debugf(false, "html: total time: %v", time.Since(start))
+		d.log.Debug("html: completed", "duration", time.Since(start))
 
+		if req.Stream {
+			return ipc.StreamResponse(func(w io.Writer) error {
+				return streamJSONObject(w, "html", htmlResp.OuterHTML)
+			})
+		}
 		return ipc.SuccessResponse(ipc.HTMLData{
 			HTML: htmlResp.OuterHTML,
 		})
@@ -326,6 +527,25 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 			};
 		}
 
+		// Wrap an element in its ancestors, collapsing their other children
+		// into a count so only the structure leading to the match remains.
+		function pathHTML(el) {
+			const openTag = (node) => {
+				const attrs = Array.from(node.attributes).map((a) =>
+					' ' + a.name + '="' + a.value.replace(/&/g, '&amp;').replace(/"/g, '&quot;') + '"');
+				return '<' + node.localName + attrs.join('') + '>';
+			};
+			const collapsed = (n) => n > 0 ? '<!-- ' + n + (n === 1 ? ' sibling' : ' siblings') + ' -->' : '';
+			let html = el.outerHTML;
+			for (let node = el; node.parentElement; node = node.parentElement) {
+				const parent = node.parentElement;
+				const i = Array.prototype.indexOf.call(parent.children, node);
+				html = openTag(parent) + collapsed(i) + html +
+					collapsed(parent.children.length - 1 - i) + '</' + parent.localName + '>';
+			}
+			return html;
+		}
+
 		return new Promise((resolve, reject) => {
 			const queryElements = () => {
 				const elements = document.querySelectorAll(%q);
@@ -335,7 +555,7 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 				}
 				resolve(Array.from(elements).map((el) => ({
 					...getElementMeta(el),
-					html: el.outerHTML
+					html: %t ? pathHTML(el) : el.outerHTML
 				})));
 			};
 
@@ -361,7 +581,7 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 				}
 			}
 		});
-	})()`, params.Selector)
+	})()`, params.Selector, params.Path)
 
 	result, err := d.sendToSession(ctx, activeID, "Runtime.evaluate", map[string]any{
 		"expression":    js,
@@ -386,11 +606,11 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 		return ipc.ErrorResponse(fmt.Sprintf("failed to parse query response: %v", err))
 	}
 	if evalResp.ExceptionDetails != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
+		return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
 	}
 	// null result means no elements matched
 	if evalResp.Result.Type == "object" && evalResp.Result.Value == nil {
-		return ipc.ErrorResponse(fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
 	}
 
 	// Build legacy HTML field with -- separators for backward compatibility
@@ -409,6 +629,37 @@ func (d *Daemon) handleHTML(req ipc.Request) ipc.Response {
 	})
 }
 
+// handleHTMLComplete captures the page as a single MHTML document with
+// stylesheets, images, and frames inlined, so it renders offline. With stream
+// set the snapshot, often many megabytes, is streamed as the HTMLData JSON.
+func (d *Daemon) handleHTMLComplete(sessionID string, stream bool) ipc.Response {
+	// Snapshots of large pages serialise every resource; allow more than the
+	// usual 30s.
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	result, err := d.sendToSession(ctx, sessionID, "Page.captureSnapshot", map[string]any{
+		"format": "mhtml",
+	})
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to capture snapshot: %v", err))
+	}
+
+	var snapshot struct {
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(result, &snapshot); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to parse snapshot response: %v", err))
+	}
+
+	if stream {
+		return ipc.StreamResponse(func(w io.Writer) error {
+			return streamJSONObject(w, "mhtml", snapshot.Data)
+		})
+	}
+	return ipc.SuccessResponse(ipc.HTMLData{MHTML: snapshot.Data})
+}
+
 // handleEval evaluates JavaScript in the browser context.
 func (d *Daemon) handleEval(req ipc.Request) ipc.Response {
 	// Check if browser is connected (fail-fast if not)
@@ -423,11 +674,11 @@ func (d *Daemon) handleEval(req ipc.Request) ipc.Response {
 
 	var params ipc.EvalParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid eval parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid eval parameters: %v", err))
 	}
 
 	if params.Expression == "" {
-		return ipc.ErrorResponse("expression is required")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "expression is required")
 	}
 
 	timeout := cdp.DefaultTimeout
@@ -438,14 +689,18 @@ func (d *Daemon) handleEval(req ipc.Request) ipc.Response {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	result, err := d.sendToSession(ctx, activeID, "Runtime.evaluate", map[string]any{
+	evalParams := map[string]any{
 		"expression":    params.Expression,
 		"awaitPromise":  true,
 		"returnByValue": true,
-	})
+	}
+	if params.REPLMode {
+		evalParams["replMode"] = true
+	}
+	result, err := d.sendToSession(ctx, activeID, "Runtime.evaluate", evalParams)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return ipc.ErrorResponse(fmt.Sprintf("evaluation timed out after %s", timeout))
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, fmt.Sprintf("evaluation timed out after %s", timeout))
 		}
 		return ipc.ErrorResponse(fmt.Sprintf("failed to evaluate expression: %v", err))
 	}
@@ -473,7 +728,7 @@ func (d *Daemon) handleEval(req ipc.Request) ipc.Response {
 		if errMsg == "" {
 			errMsg = cdpResp.ExceptionDetails.Text
 		}
-		return ipc.ErrorResponse(errMsg)
+		return ipc.CodedErrorResponse(ipc.CodeFailed, errMsg)
 	}
 
 	// Return the result - omit value field if undefined
@@ -498,7 +753,7 @@ func (d *Daemon) handleCookies(req ipc.Request) ipc.Response {
 
 	var params ipc.CookiesParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid cookies parameters: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid cookies parameters: %v", err))
 	}
 
 	switch params.Action {
@@ -509,7 +764,7 @@ func (d *Daemon) handleCookies(req ipc.Request) ipc.Response {
 	case "delete":
 		return d.handleCookiesDelete(activeID, params)
 	default:
-		return ipc.ErrorResponse(fmt.Sprintf("unknown cookies action: %s", params.Action))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown cookies action: %s", params.Action))
 	}
 }
 
@@ -539,7 +794,7 @@ func (d *Daemon) handleCookiesList(sessionID string) ipc.Response {
 // handleCookiesSet sets a cookie in the active session.
 func (d *Daemon) handleCookiesSet(sessionID string, params ipc.CookiesParams) ipc.Response {
 	if params.Name == "" {
-		return ipc.ErrorResponse("cookie name is required")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "cookie name is required")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -548,7 +803,7 @@ func (d *Daemon) handleCookiesSet(sessionID string, params ipc.CookiesParams) ip
 	// Get current URL from session - CDP requires either url or domain
 	session := d.sessions.Get(sessionID)
 	if session == nil || session.URL == "" {
-		return ipc.ErrorResponse("no active page URL")
+		return ipc.CodedErrorResponse(ipc.CodeNoSession, "no active page URL")
 	}
 
 	// Build CDP params
@@ -606,7 +861,7 @@ func (d *Daemon) handleCookiesSet(sessionID string, params ipc.CookiesParams) ip
 // handleCookiesDelete deletes a cookie from the active session.
 func (d *Daemon) handleCookiesDelete(sessionID string, params ipc.CookiesParams) ipc.Response {
 	if params.Name == "" {
-		return ipc.ErrorResponse("cookie name is required")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "cookie name is required")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -640,7 +895,7 @@ func (d *Daemon) handleCookiesDelete(sessionID string, params ipc.CookiesParams)
 
 	// Multiple matches without domain specified - error
 	if len(matches) > 1 && params.Domain == "" {
-		resp := ipc.ErrorResponse(fmt.Sprintf("multiple cookies named '%s' found", params.Name))
+		resp := ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("multiple cookies named '%s' found", params.Name))
 		resp.Data, _ = json.Marshal(ipc.CookiesData{Matches: matches})
 		return resp
 	}
@@ -658,7 +913,7 @@ func (d *Daemon) handleCookiesDelete(sessionID string, params ipc.CookiesParams)
 			}
 		}
 		if targetCookie == nil {
-			return ipc.ErrorResponse(fmt.Sprintf("no cookie named '%s' found with domain '%s'", params.Name, params.Domain))
+			return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no cookie named '%s' found with domain '%s'", params.Name, params.Domain))
 		}
 	}
 
@@ -688,7 +943,7 @@ func (d *Daemon) handleFind(req ipc.Request) ipc.Response {
 // Commands are sent to the active session. Use Target.* methods for browser-level commands.
 func (d *Daemon) handleCDP(req ipc.Request) ipc.Response {
 	if req.Target == "" {
-		return ipc.ErrorResponse("cdp command requires target (CDP method name)")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "cdp command requires target (CDP method name)")
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -697,7 +952,7 @@ func (d *Daemon) handleCDP(req ipc.Request) ipc.Response {
 	var params any
 	if req.Params != nil {
 		if err := json.Unmarshal(req.Params, &params); err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("invalid params: %v", err))
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid params: %v", err))
 		}
 	}
 
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestSessionDetails(t *testing.T) {
+	sessions := []ipc.PageSession{{ID: "A"}, {ID: "B"}}
+	console := []ipc.ConsoleEntry{
+		{SessionID: "A"}, {SessionID: "A"}, {SessionID: "gone"},
+	}
+	network := []ipc.NetworkEntry{
+		{SessionID: "A", URL: "https://app.test/", Status: 200, ResponseTime: 1, Size: 1000},
+		{SessionID: "A", URL: "https://app.test/api/x", Status: 500, ResponseTime: 2, Size: 20},
+		{SessionID: "A", URL: "https://cdn.test/a.js", Status: 200, ResponseTime: 3, Size: 300},
+		{SessionID: "A", URL: "https://cdn.test/b.js", Failed: true, ResponseTime: 4},
+		{SessionID: "A", URL: "https://app.test/slow"}, // in flight
+		{SessionID: "A", URL: "data:image/png;base64,xx", ResponseTime: 5},
+		{SessionID: "gone", URL: "https://other.test/"},
+	}
+
+	got := sessionDetails(sessions, console, network)
+	if len(got) != 2 {
+		t.Fatalf("got %d details, want 2", len(got))
+	}
+
+	a := got[0]
+	if a.ID != "A" || a.ConsoleEntries != 2 || a.NetworkEntries != 6 || a.InFlight != 1 {
+		t.Errorf("A = %+v", a)
+	}
+	wantOrigins := []ipc.OriginSummary{
+		{Origin: "https://app.test", Requests: 3, Errors: 1, Bytes: 1020},
+		{Origin: "https://cdn.test", Requests: 2, Errors: 1, Bytes: 300},
+		{Origin: "data:", Requests: 1},
+	}
+	if !reflect.DeepEqual(a.Origins, wantOrigins) {
+		t.Errorf("A origins = %+v, want %+v", a.Origins, wantOrigins)
+	}
+
+	b := got[1]
+	if b.ID != "B" || b.ConsoleEntries != 0 || b.NetworkEntries != 0 || b.Origins == nil || len(b.Origins) != 0 {
+		t.Errorf("B = %+v, want empty counts and a non-nil origin list", b)
+	}
+}
@@ -0,0 +1,287 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// highlightConfig is the Overlay.HighlightConfig used for element overlays.
+// The colours match the Chrome DevTools element inspector so the boxes read
+// the same way: content blue, padding green, border yellow, margin orange.
+var highlightConfig = map[string]any{
+	"showInfo":     true,
+	"showStyles":   false,
+	"contentColor": map[string]any{"r": 111, "g": 168, "b": 220, "a": 0.66},
+	"paddingColor": map[string]any{"r": 147, "g": 196, "b": 125, "a": 0.55},
+	"borderColor":  map[string]any{"r": 255, "g": 229, "b": 153, "a": 0.66},
+	"marginColor":  map[string]any{"r": 246, "g": 178, "b": 107, "a": 0.66},
+}
+
+// querySelectorAllNodes resolves a CSS selector to DOM node IDs in the
+// session's main document. An empty result means nothing matched.
+func (d *Daemon) querySelectorAllNodes(ctx context.Context, sessionID, selector string) ([]int, error) {
+	docResult, err := d.sendToSession(ctx, sessionID, "DOM.getDocument", map[string]any{
+		"depth": 0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+
+	var docResp struct {
+		Root struct {
+			NodeID int `json:"nodeId"`
+		} `json:"root"`
+	}
+	if err := json.Unmarshal(docResult, &docResp); err != nil {
+		return nil, fmt.Errorf("failed to parse document response: %w", err)
+	}
+
+	queryResult, err := d.sendToSession(ctx, sessionID, "DOM.querySelectorAll", map[string]any{
+		"nodeId":   docResp.Root.NodeID,
+		"selector": selector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query selector: %w", err)
+	}
+
+	var queryResp struct {
+		NodeIDs []int `json:"nodeIds"`
+	}
+	if err := json.Unmarshal(queryResult, &queryResp); err != nil {
+		return nil, fmt.Errorf("failed to parse query response: %w", err)
+	}
+
+	return queryResp.NodeIDs, nil
+}
+
+// handleHighlight draws DevTools-style overlay boxes around every element
+// matching the selector. The overlay is removed after params.Duration; a new
+// highlight replaces the previous one and cancels its pending removal.
+func (d *Daemon) handleHighlight(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.HighlightParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid highlight parameters: %v", err))
+	}
+
+	if params.Selector == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "selector is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Overlay depends on the DOM domain, which is enabled per session at attach.
+	if _, err := d.sendToSession(ctx, activeID, "Overlay.enable", nil); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to enable Overlay domain: %v", err))
+	}
+
+	nodeIDs, err := d.querySelectorAllNodes(ctx, activeID, params.Selector)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+	if len(nodeIDs) == 0 {
+		return ipc.CodedErrorResponse(ipc.CodeElementNotFound, fmt.Sprintf("selector '%s' matched no elements", params.Selector))
+	}
+
+	// highlightNode draws a single node unless the selector parameter is given,
+	// in which case Chrome highlights every node matching it.
+	_, err = d.sendToSession(ctx, activeID, "Overlay.highlightNode", map[string]any{
+		"highlightConfig": highlightConfig,
+		"nodeId":          nodeIDs[0],
+		"selector":        params.Selector,
+	})
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to highlight element: %v", err))
+	}
+
+	d.scheduleHideHighlight(activeID, time.Duration(params.Duration)*time.Millisecond)
+
+	return ipc.SuccessResponse(ipc.HighlightData{Count: len(nodeIDs)})
+}
+
+// scheduleHideHighlight replaces any pending overlay removal with one that
+// fires after delay. A zero delay leaves the overlay in place until the next
+// highlight or navigation.
+func (d *Daemon) scheduleHideHighlight(sessionID string, delay time.Duration) {
+	d.highlightMu.Lock()
+	defer d.highlightMu.Unlock()
+
+	if d.highlightTimer != nil {
+		d.highlightTimer.Stop()
+		d.highlightTimer = nil
+	}
+	if delay <= 0 {
+		return
+	}
+
+	d.highlightTimer = time.AfterFunc(delay, func() {
+		// A browser being replaced takes its overlays with it
+		if !d.browserMu.TryRLock() {
+			return
+		}
+		defer d.browserMu.RUnlock()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := d.sendToSession(ctx, sessionID, "Overlay.hideHighlight", nil); err != nil {
+			d.log.Warn("failed to hide highlight", "error", err)
+		}
+	})
+}
+
+// pickRequest is the pending "pick" waiting for the user to click an element
+// while the page is in inspect mode. Only one pick may be pending at a time.
+type pickRequest struct {
+	sessionID string
+	nodeCh    chan int // receives the backendNodeId of the clicked element
+}
+
+// handleInspectNodeRequested forwards an Overlay.inspectNodeRequested event to
+// the pending pick for that session. It runs on the CDP read loop, so the send
+// never blocks: a click that arrives after the pick already has its node is
+// dropped.
+func (d *Daemon) handleInspectNodeRequested(sessionID string, backendNodeID int) {
+	d.pickMu.Lock()
+	defer d.pickMu.Unlock()
+
+	if d.pick == nil || d.pick.sessionID != sessionID {
+		return
+	}
+	select {
+	case d.pick.nodeCh <- backendNodeID:
+	default:
+	}
+}
+
+// handlePick puts the page into inspect mode, waits for the user to click an
+// element in the headful browser, and returns a CSS selector and XPath that
+// uniquely identify it.
+func (d *Daemon) handlePick(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.PickParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid pick parameters: %v", err))
+		}
+	}
+
+	timeout := 60 * time.Second
+	if params.Timeout > 0 {
+		timeout = time.Duration(params.Timeout) * time.Second
+	}
+
+	pick := &pickRequest{sessionID: activeID, nodeCh: make(chan int, 1)}
+	d.pickMu.Lock()
+	if d.pick != nil {
+		d.pickMu.Unlock()
+		return ipc.ErrorResponse("a pick is already in progress")
+	}
+	d.pick = pick
+	d.pickMu.Unlock()
+	defer func() {
+		d.pickMu.Lock()
+		d.pick = nil
+		d.pickMu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := d.sendToSession(ctx, activeID, "Overlay.enable", nil); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to enable Overlay domain: %v", err))
+	}
+
+	_, err := d.sendToSession(ctx, activeID, "Overlay.setInspectMode", map[string]any{
+		"mode":            "searchForNode",
+		"highlightConfig": highlightConfig,
+	})
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to enable inspect mode: %v", err))
+	}
+
+	// Always leave inspect mode, even on timeout, so the page is usable again.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := d.sendToSession(ctx, activeID, "Overlay.setInspectMode", map[string]any{"mode": "none"}); err != nil {
+			d.log.Warn("failed to leave inspect mode", "error", err)
+		}
+		_, _ = d.sendToSession(ctx, activeID, "Overlay.hideHighlight", nil)
+	}()
+
+	var backendNodeID int
+	select {
+	case backendNodeID = <-pick.nodeCh:
+	case <-time.After(timeout):
+		return ipc.CodedErrorResponse(ipc.CodeTimeout, "timeout waiting for element pick")
+	}
+
+	// The wait may have used most of the setup context; describe the element
+	// under a fresh deadline.
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resolveResult, err := d.sendToSession(ctx, activeID, "DOM.resolveNode", map[string]any{
+		"backendNodeId": backendNodeID,
+	})
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to resolve picked element: %v", err))
+	}
+
+	var resolveResp struct {
+		Object struct {
+			ObjectID string `json:"objectId"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal(resolveResult, &resolveResp); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to parse resolve response: %v", err))
+	}
+
+	result, err := d.sendToSession(ctx, activeID, "Runtime.callFunctionOn", map[string]any{
+		"functionDeclaration": describeElementJS,
+		"objectId":            resolveResp.Object.ObjectID,
+		"returnByValue":       true,
+	})
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to describe picked element: %v", err))
+	}
+
+	var evalResp struct {
+		Result struct {
+			Value ipc.PickData `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text string `json:"text"`
+		} `json:"exceptionDetails"`
+	}
+	if err := json.Unmarshal(result, &evalResp); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to parse picked element: %v", err))
+	}
+	if evalResp.ExceptionDetails != nil {
+		return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("JavaScript error: %s", evalResp.ExceptionDetails.Text))
+	}
+
+	return ipc.SuccessResponse(evalResp.Result.Value)
+}
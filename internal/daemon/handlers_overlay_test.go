@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+)
+
+func TestHandleInspectNodeRequested_RoutesToPendingPick(t *testing.T) {
+	d := New(DefaultConfig())
+	pick := &pickRequest{sessionID: "sess1", nodeCh: make(chan int, 1)}
+	d.pick = pick
+
+	d.handleInspectNodeRequested("sess1", 42)
+
+	select {
+	case got := <-pick.nodeCh:
+		if got != 42 {
+			t.Errorf("backendNodeId = %d, want 42", got)
+		}
+	default:
+		t.Fatal("expected picked node to be delivered")
+	}
+}
+
+func TestHandleInspectNodeRequested_IgnoresOtherSession(t *testing.T) {
+	d := New(DefaultConfig())
+	pick := &pickRequest{sessionID: "sess1", nodeCh: make(chan int, 1)}
+	d.pick = pick
+
+	d.handleInspectNodeRequested("sess2", 42)
+
+	select {
+	case got := <-pick.nodeCh:
+		t.Errorf("expected no delivery for another session, got %d", got)
+	default:
+	}
+}
+
+func TestHandleInspectNodeRequested_NoPendingPick(t *testing.T) {
+	d := New(DefaultConfig())
+	// Must not panic or block when no pick is waiting.
+	d.handleInspectNodeRequested("sess1", 42)
+}
+
+func TestHandleInspectNodeRequested_DoesNotBlockWhenFull(t *testing.T) {
+	d := New(DefaultConfig())
+	pick := &pickRequest{sessionID: "sess1", nodeCh: make(chan int, 1)}
+	d.pick = pick
+
+	d.handleInspectNodeRequested("sess1", 1)
+	d.handleInspectNodeRequested("sess1", 2) // dropped, first click wins
+
+	if got := <-pick.nodeCh; got != 1 {
+		t.Errorf("backendNodeId = %d, want first click 1", got)
+	}
+}
+
+// TestScheduleHideHighlight_WhileReplacingBrowser checks a pending overlay
+// removal neither touches the connection being replaced nor outlives the
+// old browser. Run with -race.
+func TestScheduleHideHighlight_WhileReplacingBrowser(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+
+	// Fires while replaceBrowser holds browserMu
+	d.browserMu.Lock()
+	d.scheduleHideHighlight("sess1", time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	d.cdp = client
+	d.browserMu.Unlock()
+
+	// Stopped by the replacement before it fires
+	d.scheduleHideHighlight("sess1", 20*time.Millisecond)
+	d.browserMu.Lock()
+	d.scheduleHideHighlight("", 0)
+	d.browserMu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+
+	if reqs := conn.getCapturedRequests(); len(reqs) != 0 {
+		t.Errorf("sent %d CDP requests, want none", len(reqs))
+	}
+}
@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// perfShiftsBufferSize caps the layout shifts held in the page between polls.
+const perfShiftsBufferSize = 1000
+
+// perfShiftsJS installs (or reuses) a layout-shift PerformanceObserver stored
+// on window.__webctlShiftWatch and drains its recorded shifts. Format args:
+// watch ID, stop flag, buffer size.
+//
+// The observer is buffered, so the first poll also returns the shifts the
+// page made before it was installed; takeRecords collects those without
+// waiting for the observer callback. As with watch-dom, a navigation discards
+// the observer and the next poll installs a new one.
+const perfShiftsJS = `(() => {
+	const id = %q;
+	const stop = %t;
+	const limit = %d;
+
+	let w = window.__webctlShiftWatch;
+	if (w && (stop || w.id !== id)) {
+		w.observer.disconnect();
+		delete window.__webctlShiftWatch;
+		w = null;
+	}
+	if (stop) {
+		return {total: 0, shifts: []};
+	}
+	if (typeof PerformanceObserver === 'undefined' ||
+		!(PerformanceObserver.supportedEntryTypes || []).includes('layout-shift')) {
+		throw new Error('layout-shift entries are not supported by this browser');
+	}
+
+	const describe = (n) => {
+		if (!n || n.nodeType !== Node.ELEMENT_NODE) {
+			return n ? n.nodeName.toLowerCase() : '';
+		}
+		let s = n.tagName.toLowerCase();
+		if (n.id) {
+			s += '#' + n.id;
+		}
+		const classes = (n.getAttribute('class') || '').split(/\s+/).filter(Boolean).slice(0, 2);
+		for (const c of classes) {
+			s += '.' + c;
+		}
+		return s;
+	};
+	const rect = (r) => ({x: r.x, y: r.y, width: r.width, height: r.height});
+
+	let installed = false;
+	if (!w) {
+		const state = {id, records: [], dropped: 0, total: 0};
+		state.record = (list) => {
+			for (const e of list) {
+				if (!e.hadRecentInput) {
+					state.total += e.value;
+				}
+				state.records.push({
+					time: Math.round(performance.timeOrigin + e.startTime),
+					startTime: e.startTime,
+					score: e.value,
+					hadRecentInput: e.hadRecentInput,
+					sources: (e.sources || []).map((s) => ({
+						node: describe(s.node),
+						previous: rect(s.previousRect),
+						current: rect(s.currentRect)
+					}))
+				});
+				if (state.records.length > limit) {
+					state.records.shift();
+					state.dropped++;
+				}
+			}
+		};
+		state.observer = new PerformanceObserver((list) => state.record(list.getEntries()));
+		state.observer.observe({type: 'layout-shift', buffered: true});
+		w = window.__webctlShiftWatch = state;
+		installed = true;
+	}
+
+	w.record(w.observer.takeRecords());
+	const shifts = w.records.splice(0);
+	const dropped = w.dropped;
+	w.dropped = 0;
+	return {installed, dropped, total: w.total, shifts};
+})()`
+
+// handlePerf handles the "perf" command. "shifts" installs a layout-shift
+// observer in the page on first use and returns the shifts recorded since the
+// previous poll.
+func (d *Daemon) handlePerf(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.PerfParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid perf parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "shifts":
+		return d.perfShifts(activeID, params)
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown perf action: %s", params.Action))
+	}
+}
+
+// perfShifts drains the layout shifts recorded in a session.
+func (d *Daemon) perfShifts(sessionID string, params ipc.PerfParams) ipc.Response {
+	if params.WatchID == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "watch ID is required")
+	}
+
+	js := fmt.Sprintf(perfShiftsJS, params.WatchID, params.Stop, perfShiftsBufferSize)
+
+	var data ipc.PerfShiftsData
+	found, err := d.evalElementQuery(sessionID, js, &data)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to watch layout shifts: %v", err))
+	}
+	if !found {
+		return ipc.ErrorResponse("failed to watch layout shifts: no result from page")
+	}
+	if data.Shifts == nil {
+		data.Shifts = []ipc.LayoutShift{}
+	}
+	data.CPUThrottle = d.cpuThrottled()
+
+	return ipc.SuccessResponse(data)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/grantcarthew/webctl/internal/ipc"
@@ -15,7 +16,7 @@ func (d *Daemon) handleServe(req ipc.Request) ipc.Response {
 	var params ipc.ServeParams
 	if len(req.Params) > 0 {
 		if err := json.Unmarshal(req.Params, &params); err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("invalid params: %v", err))
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid params: %v", err))
 		}
 	}
 
@@ -27,7 +28,7 @@ func (d *Daemon) handleServe(req ipc.Request) ipc.Response {
 	case "status":
 		return d.handleServeStatus()
 	default:
-		return ipc.ErrorResponse(fmt.Sprintf("unknown action: %s", params.Action))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown action: %s", params.Action))
 	}
 }
 
@@ -49,20 +50,21 @@ func (d *Daemon) handleServeStart(params ipc.ServeParams) ipc.Response {
 	case "proxy":
 		mode = server.ModeProxy
 	default:
-		return ipc.ErrorResponse("mode must be 'static' or 'proxy'")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "mode must be 'static' or 'proxy'")
 	}
 
 	// Create server config
 	cfg := server.Config{
 		Mode:        mode,
 		Directory:   params.Directory,
+		SPA:         params.SPA,
 		ProxyURL:    params.ProxyURL,
 		Port:        params.Port,
 		Host:        params.Host,
 		WatchPaths:  params.WatchPaths,
 		IgnorePaths: params.IgnorePaths,
 		OnReload:    d.handleServerReload,
-		Debug:       d.debug,
+		Debug:       d.log.Enabled(context.Background(), slog.LevelDebug),
 	}
 
 	// Create server
@@ -78,7 +80,7 @@ func (d *Daemon) handleServeStart(params ipc.ServeParams) ipc.Response {
 	}
 
 	d.devServer = srv
-	d.debugf(false, "Development server started: %s", srv.URL())
+	d.log.Info("development server started", "url", srv.URL())
 
 	// Navigate browser to server URL if browser is running
 	// Do this in a goroutine to not block the response
@@ -91,7 +93,7 @@ func (d *Daemon) handleServeStart(params ipc.ServeParams) ipc.Response {
 		for {
 			select {
 			case <-timeout:
-				d.debugf(false, "Timeout waiting for browser session - navigation skipped")
+				d.log.Warn("timed out waiting for browser session, navigation skipped")
 				return
 			case <-ticker.C:
 				if d.browserConnected() {
@@ -105,9 +107,9 @@ func (d *Daemon) handleServeStart(params ipc.ServeParams) ipc.Response {
 							"url": srv.URL(),
 						})
 						if err != nil {
-							d.debugf(false, "Failed to navigate to server URL: %v", err)
+							d.log.Warn("failed to navigate to server URL", "url", srv.URL(), "error", err)
 						} else {
-							d.debugf(false, "Navigated to server URL: %s", srv.URL())
+							d.log.Debug("navigated to server URL", "url", srv.URL())
 						}
 						return
 					}
@@ -141,7 +143,7 @@ func (d *Daemon) handleServeStop() ipc.Response {
 	}
 
 	d.devServer = nil
-	d.debugf(false, "Development server stopped")
+	d.log.Info("development server stopped")
 
 	return ipc.SuccessResponse(ipc.ServeData{
 		Running: false,
@@ -168,18 +170,18 @@ func (d *Daemon) handleServeStatus() ipc.Response {
 
 // handleServerReload is called when files change - triggers page reload via CDP.
 func (d *Daemon) handleServerReload() {
-	d.debugf(false, "File change detected - reloading page")
+	d.log.Debug("file change detected, reloading page")
 
 	// Check if browser is connected
 	if !d.browserConnected() {
-		d.debugf(false, "Browser not connected - skipping reload")
+		d.log.Debug("browser not connected, skipping reload")
 		return
 	}
 
 	// Get active session
 	session := d.sessions.Active()
 	if session == nil {
-		d.debugf(false, "No active session - skipping reload")
+		d.log.Debug("no active session, skipping reload")
 		return
 	}
 
@@ -192,9 +194,9 @@ func (d *Daemon) handleServerReload() {
 			"ignoreCache": false,
 		})
 		if err != nil {
-			d.debugf(false, "Failed to reload page: %v", err)
+			d.log.Warn("failed to reload page", "error", err)
 		} else {
-			d.debugf(false, "Page reloaded successfully")
+			d.log.Debug("page reloaded")
 		}
 	}()
 }
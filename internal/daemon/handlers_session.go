@@ -7,29 +7,81 @@ import (
 	"github.com/grantcarthew/webctl/internal/ipc"
 )
 
-// handleClear clears the specified buffer.
-func (d *Daemon) handleClear(target string) ipc.Response {
-	switch target {
+// handleClear clears the buffer named by req.Target: "console", "network",
+// "events", or every buffer when empty or "all". With a session query in the params it removes only that
+// tab's entries, so another tab's captured evidence survives.
+func (d *Daemon) handleClear(req ipc.Request) ipc.Response {
+	var params ipc.ClearParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid clear parameters: %v", err))
+		}
+	}
+
+	var console, network, events bool
+	switch req.Target {
 	case "console":
-		d.consoleBuf.Clear()
+		console = true
 	case "network":
-		d.networkBuf.Clear()
-		_ = clearBodiesDir()
+		network = true
+	case "events":
+		events = true
 	case "", "all":
-		d.consoleBuf.Clear()
-		d.networkBuf.Clear()
-		_ = clearBodiesDir()
+		console, network, events = true, true, true
 	default:
-		return ipc.ErrorResponse(fmt.Sprintf("unknown clear target: %s", target))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown clear target: %s (use console, network, events, or all)", req.Target))
+	}
+
+	if params.Session == "" {
+		if console {
+			d.consoleBuf.Clear()
+		}
+		if network {
+			d.networkBuf.Clear()
+			_ = clearBodiesDir()
+		}
+		if events {
+			d.eventBuf.Clear()
+		}
+		return ipc.SuccessResponse(nil)
+	}
+
+	matches := d.sessions.FindByQuery(params.Session)
+	if len(matches) == 0 {
+		return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no tab matches query: %s", params.Session))
+	}
+	if len(matches) > 1 {
+		return ambiguousTabError(params.Session, matches)
+	}
+	sessionID := matches[0].ID
+
+	if console {
+		d.consoleBuf.RemoveIf(func(e *ipc.ConsoleEntry) bool { return e.SessionID == sessionID })
+	}
+	if network {
+		var bodies []string
+		d.networkBuf.RemoveIf(func(e *ipc.NetworkEntry) bool {
+			if e.SessionID != sessionID {
+				return false
+			}
+			if e.ResponseBodyPath != "" {
+				bodies = append(bodies, e.ResponseBodyPath)
+			}
+			return true
+		})
+		removeBodyFiles(bodies)
+	}
+	if events {
+		d.eventBuf.RemoveIf(func(e *ipc.DaemonEvent) bool { return e.Attrs["session"] == sessionID })
 	}
-	return ipc.SuccessResponse(nil)
+	return ipc.SuccessResponse(ipc.ClearData{Session: sessionID})
 }
 
 // noActiveSessionError returns an error response with available sessions.
 func (d *Daemon) noActiveSessionError() ipc.Response {
 	sessions := d.sessions.All()
 	if len(sessions) == 0 {
-		return ipc.ErrorResponse("no active session - no pages available")
+		return ipc.CodedErrorResponse(ipc.CodeNoSession, "no active session - no pages available")
 	}
 
 	// Return error with session list so user can select
@@ -42,5 +94,5 @@ func (d *Daemon) noActiveSessionError() ipc.Response {
 	}
 
 	raw, _ := json.Marshal(data)
-	return ipc.Response{OK: false, Error: data.Error, Data: raw}
+	return ipc.Response{OK: false, Error: data.Error, Code: ipc.CodeNoSession, Data: raw}
 }
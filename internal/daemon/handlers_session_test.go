@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestHandleClear_Session(t *testing.T) {
+	d := New(DefaultConfig())
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+	d.sessions.Add("BBB2", "T2", "https://two.test/", "Two")
+	bodies := map[string]string{}
+	for _, id := range []string{"AAA1", "BBB2"} {
+		bodies[id] = filepath.Join(t.TempDir(), id+".json")
+		if err := os.WriteFile(bodies[id], []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		d.consoleBuf.Push(ipc.ConsoleEntry{SessionID: id, Text: "x"})
+		d.networkBuf.Push(ipc.NetworkEntry{SessionID: id, URL: "https://x.test/", ResponseBodyPath: bodies[id]})
+		d.recordEvent("info", "target_attached", "tab attached", "session", id)
+	}
+
+	clear := func(target, session string) ipc.Response {
+		t.Helper()
+		raw, _ := json.Marshal(ipc.ClearParams{Session: session})
+		return d.handleRequest(ipc.Request{Cmd: "clear", Target: target, Params: raw})
+	}
+
+	resp := clear("console", "two")
+	if !resp.OK {
+		t.Fatalf("clear console --session two: %s", resp.Error)
+	}
+	var data ipc.ClearData
+	if err := json.Unmarshal(resp.Data, &data); err != nil || data.Session != "BBB2" {
+		t.Errorf("data = %s, want session BBB2", resp.Data)
+	}
+	if entries := d.consoleBuf.All(); len(entries) != 1 || entries[0].SessionID != "AAA1" {
+		t.Errorf("console = %+v, want only AAA1's entry", entries)
+	}
+	if d.networkBuf.Len() != 2 || d.eventBuf.Len() != 2 {
+		t.Errorf("network %d, events %d: want both untouched", d.networkBuf.Len(), d.eventBuf.Len())
+	}
+
+	if resp := clear("all", "AAA"); !resp.OK {
+		t.Fatalf("clear all --session AAA: %s", resp.Error)
+	}
+	if d.consoleBuf.Len() != 0 || d.networkBuf.Len() != 1 || d.eventBuf.Len() != 1 {
+		t.Errorf("console %d, network %d, events %d: want 0, 1, 1",
+			d.consoleBuf.Len(), d.networkBuf.Len(), d.eventBuf.Len())
+	}
+	if _, err := os.Stat(bodies["AAA1"]); !os.IsNotExist(err) {
+		t.Errorf("AAA1's response body still on disk (err %v)", err)
+	}
+	if _, err := os.Stat(bodies["BBB2"]); err != nil {
+		t.Errorf("BBB2's response body removed: %v", err)
+	}
+
+	for _, bad := range []struct{ target, session string }{
+		{"console", "nope"},
+		{"timeline", ""},
+	} {
+		if resp := clear(bad.target, bad.session); resp.OK {
+			t.Errorf("clear %q --session %q succeeded, want an error", bad.target, bad.session)
+		}
+	}
+}
+
+func TestHandleClear_Targets(t *testing.T) {
+	d := New(DefaultConfig())
+	d.consoleBuf.Push(ipc.ConsoleEntry{Text: "x"})
+	d.recordEvent("info", "cdp_connected", "CDP connected")
+
+	if resp := d.handleRequest(ipc.Request{Cmd: "clear", Target: "events"}); !resp.OK {
+		t.Fatalf("clear events: %s", resp.Error)
+	}
+	if d.consoleBuf.Len() != 1 || d.eventBuf.Len() != 0 {
+		t.Errorf("console %d, events %d: want 1, 0", d.consoleBuf.Len(), d.eventBuf.Len())
+	}
+
+	d.recordEvent("info", "cdp_connected", "CDP connected")
+	if resp := d.handleRequest(ipc.Request{Cmd: "clear"}); !resp.OK {
+		t.Fatalf("clear: %s", resp.Error)
+	}
+	if d.consoleBuf.Len() != 0 || d.eventBuf.Len() != 0 {
+		t.Errorf("console %d, events %d: want both empty", d.consoleBuf.Len(), d.eventBuf.Len())
+	}
+}
@@ -0,0 +1,310 @@
+package daemon
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// scriptInfo is the subset of the CDP Debugger.scriptParsed event used by
+// "source".
+type scriptInfo struct {
+	ScriptID     string `json:"scriptId"`
+	URL          string `json:"url"`
+	Length       int    `json:"length"`
+	SourceMapURL string `json:"sourceMapURL"`
+}
+
+// sourceMap is the subset of a version 3 source map used to recover the
+// original files.
+type sourceMap struct {
+	SourceRoot     string    `json:"sourceRoot"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent"`
+}
+
+// fetchSourceMapJS fetches a source map from the page, so the request carries
+// the page's cookies and origin. %q is the absolute map URL.
+const fetchSourceMapJS = `fetch(%q, {credentials: 'include'}).then(r => {
+	if (!r.ok) {
+		throw new Error('HTTP ' + r.status);
+	}
+	return r.text();
+})`
+
+// handleSource lists the scripts loaded by the active page or returns the
+// source of those matching a query.
+func (d *Daemon) handleSource(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.SourceParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid source parameters: %v", err))
+		}
+	}
+	if params.Action != "list" && params.Action != "get" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown source action: %s", params.Action))
+	}
+	if params.Action == "get" && params.Query == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "script required (scriptId or URL pattern)")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	scripts, err := d.collectScripts(ctx, activeID)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+	// Debugger stays enabled until the sources are read: script IDs are only
+	// valid while it is. applyDebugger then turns it off, or back to the
+	// logpoints' setup when there are any.
+	defer func() {
+		_, _ = d.applyDebugger(activeID)
+	}()
+
+	if params.Action == "list" {
+		return ipc.SuccessResponse(ipc.SourceData{Scripts: scripts})
+	}
+
+	scripts, err = matchScripts(scripts, params.Query)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+
+	for i := range scripts {
+		s := &scripts[i]
+		result, err := d.sendToSession(ctx, activeID, "Debugger.getScriptSource", map[string]any{
+			"scriptId": s.ID,
+		})
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to get source of %s: %v", scriptLabel(*s), err))
+		}
+		var sourceResp struct {
+			ScriptSource string `json:"scriptSource"`
+		}
+		if err := json.Unmarshal(result, &sourceResp); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to parse script source: %v", err))
+		}
+		s.Source = sourceResp.ScriptSource
+		s.Size = len(s.Source)
+
+		if params.SourceMaps && s.SourceMapURL != "" {
+			originals, err := d.resolveSourceMap(ctx, activeID, *s)
+			if err != nil {
+				s.SourceMapError = err.Error()
+				continue
+			}
+			s.Originals = originals
+		}
+	}
+
+	return ipc.SuccessResponse(ipc.SourceData{Scripts: scripts})
+}
+
+// addScript records a Debugger.scriptParsed event while collectScripts is
+// collecting for the session; logpoints keep the domain on between
+// collections. Called on the CDP read loop; must not block.
+func (d *Daemon) addScript(sessionID string, script scriptInfo) {
+	d.scriptsMu.Lock()
+	defer d.scriptsMu.Unlock()
+	if infos, ok := d.scripts[sessionID]; ok {
+		d.scripts[sessionID] = append(infos, script)
+	}
+}
+
+// collectScripts returns the scripts the page has loaded from a URL, in the
+// order Chrome parsed them; eval'd and anonymous scripts are left out. Like
+// collectStyleSheets, it re-enables the Debugger domain so Chrome re-reports
+// every live script via Debugger.scriptParsed before the enable response.
+// Pauses are skipped so a debugger statement cannot freeze the page while the
+// domain is on. The caller must disable the domain when done.
+func (d *Daemon) collectScripts(ctx context.Context, sessionID string) ([]ipc.ScriptSource, error) {
+	d.scriptsMu.Lock()
+	if d.scripts == nil {
+		d.scripts = make(map[string][]scriptInfo)
+	}
+	d.scripts[sessionID] = []scriptInfo{}
+	d.scriptsMu.Unlock()
+
+	if _, err := d.sendToSession(ctx, sessionID, "Debugger.disable", nil); err != nil {
+		return nil, fmt.Errorf("failed to reset Debugger domain: %v", err)
+	}
+	if _, err := d.sendToSession(ctx, sessionID, "Debugger.enable", nil); err != nil {
+		return nil, fmt.Errorf("failed to enable Debugger domain: %v", err)
+	}
+	if _, err := d.sendToSession(ctx, sessionID, "Debugger.setSkipAllPauses", map[string]any{"skip": true}); err != nil {
+		return nil, fmt.Errorf("failed to skip pauses: %v", err)
+	}
+
+	d.scriptsMu.Lock()
+	infos := d.scripts[sessionID]
+	delete(d.scripts, sessionID)
+	d.scriptsMu.Unlock()
+
+	scripts := []ipc.ScriptSource{}
+	for _, s := range infos {
+		if s.URL == "" {
+			continue
+		}
+		scripts = append(scripts, ipc.ScriptSource{
+			ID:           s.ScriptID,
+			URL:          s.URL,
+			Size:         s.Length,
+			SourceMapURL: s.SourceMapURL,
+		})
+	}
+	return scripts, nil
+}
+
+// matchScripts resolves a query to scripts: an exact scriptId, or a URL
+// pattern matched as by "mock" and "rewrite" (a substring unless it has
+// wildcards). A pattern may match several scripts.
+func matchScripts(scripts []ipc.ScriptSource, query string) ([]ipc.ScriptSource, error) {
+	for _, s := range scripts {
+		if s.ID == query {
+			return []ipc.ScriptSource{s}, nil
+		}
+	}
+
+	pattern := interceptPattern(query)
+	var matches []ipc.ScriptSource
+	for _, s := range scripts {
+		if interceptURLMatch(pattern, s.URL) {
+			matches = append(matches, s)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no script matches '%s'", query)
+	}
+	return matches, nil
+}
+
+// resolveSourceMap loads a script's source map and returns the original files
+// it lists. Inline data: maps are decoded here; others are fetched by the page.
+func (d *Daemon) resolveSourceMap(ctx context.Context, sessionID string, s ipc.ScriptSource) ([]ipc.OriginalSource, error) {
+	var raw []byte
+	if strings.HasPrefix(s.SourceMapURL, "data:") {
+		var err error
+		raw, err = decodeDataURL(s.SourceMapURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inline source map: %v", err)
+		}
+	} else {
+		mapURL, err := resolveReference(s.URL, s.SourceMapURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source map URL %q: %v", s.SourceMapURL, err)
+		}
+		result, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+			"expression":    fmt.Sprintf(fetchSourceMapJS, mapURL),
+			"returnByValue": true,
+			"awaitPromise":  true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch source map: %v", err)
+		}
+		var evalResp struct {
+			Result struct {
+				Value string `json:"value"`
+			} `json:"result"`
+			ExceptionDetails *struct {
+				Text      string `json:"text"`
+				Exception *struct {
+					Description string `json:"description"`
+				} `json:"exception"`
+			} `json:"exceptionDetails"`
+		}
+		if err := json.Unmarshal(result, &evalResp); err != nil {
+			return nil, fmt.Errorf("failed to parse source map response: %v", err)
+		}
+		if e := evalResp.ExceptionDetails; e != nil {
+			msg := e.Text
+			if e.Exception != nil && e.Exception.Description != "" {
+				msg = e.Exception.Description
+			}
+			return nil, fmt.Errorf("failed to fetch source map %s: %s", mapURL, msg)
+		}
+		raw = []byte(evalResp.Result.Value)
+	}
+
+	return parseSourceMap(raw)
+}
+
+// parseSourceMap returns the original files listed in a source map, with the
+// sourceRoot applied to their paths.
+func parseSourceMap(raw []byte) ([]ipc.OriginalSource, error) {
+	var m sourceMap
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("invalid source map: %v", err)
+	}
+	if len(m.Sources) == 0 {
+		return nil, fmt.Errorf("source map lists no sources")
+	}
+
+	originals := make([]ipc.OriginalSource, len(m.Sources))
+	for i, src := range m.Sources {
+		p := src
+		if m.SourceRoot != "" && !strings.Contains(src, "://") {
+			p = strings.TrimSuffix(m.SourceRoot, "/") + "/" + strings.TrimPrefix(src, "/")
+		}
+		originals[i] = ipc.OriginalSource{Path: p, Missing: true}
+		if i < len(m.SourcesContent) && m.SourcesContent[i] != nil {
+			originals[i].Content = *m.SourcesContent[i]
+			originals[i].Missing = false
+		}
+	}
+	return originals, nil
+}
+
+// resolveReference resolves ref against base, as a browser resolves a
+// sourceMappingURL against the script URL.
+func resolveReference(base, ref string) (string, error) {
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// decodeDataURL returns the payload of a data: URL.
+func decodeDataURL(u string) ([]byte, error) {
+	meta, payload, ok := strings.Cut(strings.TrimPrefix(u, "data:"), ",")
+	if !ok {
+		return nil, fmt.Errorf("missing ','")
+	}
+	if strings.HasSuffix(meta, ";base64") {
+		return base64.StdEncoding.DecodeString(payload)
+	}
+	s, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
+
+// scriptLabel names a script in error messages.
+func scriptLabel(s ipc.ScriptSource) string {
+	if s.URL != "" {
+		return s.URL
+	}
+	return "script " + s.ID
+}
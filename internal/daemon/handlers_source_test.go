@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestMatchScripts(t *testing.T) {
+	scripts := []ipc.ScriptSource{
+		{ID: "12", URL: "https://example.com/static/app.js"},
+		{ID: "13", URL: "https://cdn.example.com/vendor.js"},
+		{ID: "14", URL: "https://example.com/"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"13", []string{"13"}},
+		{"app.js", []string{"12"}},
+		{"example.com", []string{"12", "13", "14"}},
+		{"https://cdn.*/*.js", []string{"13"}},
+	}
+	for _, tt := range tests {
+		got, err := matchScripts(scripts, tt.query)
+		if err != nil {
+			t.Errorf("matchScripts(%q) error: %v", tt.query, err)
+			continue
+		}
+		var ids []string
+		for _, s := range got {
+			ids = append(ids, s.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("matchScripts(%q) = %v, want %v", tt.query, ids, tt.want)
+		}
+	}
+
+	if _, err := matchScripts(scripts, "missing.js"); err == nil {
+		t.Error("expected an error for a pattern matching nothing")
+	}
+}
+
+func TestParseSourceMap(t *testing.T) {
+	raw := []byte(`{"version":3,"sourceRoot":"webpack:///","sources":["./src/app.ts","./src/util.ts","webpack://lib/x.js"],"sourcesContent":["export {}",null]}`)
+	got, err := parseSourceMap(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ipc.OriginalSource{
+		{Path: "webpack:///./src/app.ts", Content: "export {}"},
+		{Path: "webpack:///./src/util.ts", Missing: true},
+		{Path: "webpack://lib/x.js", Missing: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSourceMap() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	for _, bad := range []string{`not json`, `{"version":3,"sources":[]}`} {
+		if _, err := parseSourceMap([]byte(bad)); err == nil {
+			t.Errorf("parseSourceMap(%s) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestDecodeDataURL(t *testing.T) {
+	payload := `{"sources":["a.js"]}`
+	tests := []string{
+		"data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(payload)),
+		"data:application/json;charset=utf-8;base64," + base64.StdEncoding.EncodeToString([]byte(payload)),
+		"data:application/json,%7B%22sources%22%3A%5B%22a.js%22%5D%7D",
+	}
+	for _, u := range tests {
+		got, err := decodeDataURL(u)
+		if err != nil || string(got) != payload {
+			t.Errorf("decodeDataURL(%q) = %q, %v; want %q", u, got, err, payload)
+		}
+	}
+	if _, err := decodeDataURL("data:application/json"); err == nil {
+		t.Error("expected an error for a data URL without a payload")
+	}
+}
+
+func TestResolveReference(t *testing.T) {
+	tests := []struct{ base, ref, want string }{
+		{"https://example.com/static/app.js", "app.js.map", "https://example.com/static/app.js.map"},
+		{"https://example.com/static/app.js", "/maps/app.js.map", "https://example.com/maps/app.js.map"},
+		{"https://example.com/static/app.js", "https://maps.example.com/app.js.map", "https://maps.example.com/app.js.map"},
+	}
+	for _, tt := range tests {
+		got, err := resolveReference(tt.base, tt.ref)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveReference(%q, %q) = %q, %v; want %q", tt.base, tt.ref, got, err, tt.want)
+		}
+	}
+}
@@ -22,7 +22,7 @@ func (d *Daemon) handleTab(req ipc.Request) ipc.Response {
 	var params ipc.TabParams
 	if len(req.Params) > 0 {
 		if err := json.Unmarshal(req.Params, &params); err != nil {
-			return ipc.ErrorResponse(fmt.Sprintf("invalid tab parameters: %v", err))
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid tab parameters: %v", err))
 		}
 	}
 
@@ -36,7 +36,7 @@ func (d *Daemon) handleTab(req ipc.Request) ipc.Response {
 	case "close":
 		return d.handleTabClose(params.Query)
 	default:
-		return ipc.ErrorResponse(fmt.Sprintf("unknown tab action: %s", params.Action))
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown tab action: %s", params.Action))
 	}
 }
 
@@ -51,12 +51,12 @@ func (d *Daemon) handleTabList() ipc.Response {
 // handleTabSwitch sets the active session and foregrounds the tab.
 func (d *Daemon) handleTabSwitch(query string) ipc.Response {
 	if query == "" {
-		return ipc.ErrorResponse("query is required for tab switch")
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "query is required for tab switch")
 	}
 
 	matches := d.sessions.FindByQuery(query)
 	if len(matches) == 0 {
-		return ipc.ErrorResponse(fmt.Sprintf("no tab matches query: %s", query))
+		return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no tab matches query: %s", query))
 	}
 	if len(matches) > 1 {
 		return ambiguousTabError(query, matches)
@@ -90,8 +90,50 @@ func (d *Daemon) handleTabSwitch(query string) ipc.Response {
 	})
 }
 
-// handleTabNew creates a new tab and waits for it to be registered.
+// handleTabNew creates a new tab and waits for it to be registered. The tab
+// opens in the active tab's browser context, so a tab opened from a
+// 'context new' tab shares its cookies and storage.
 func (d *Daemon) handleTabNew(url string) ipc.Response {
+	var contextID string
+	if active := d.sessions.Active(); active != nil {
+		contextID = active.Context
+	}
+
+	session, err := d.openTab(url, contextID)
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+
+	return ipc.SuccessResponse(ipc.NewTabData{
+		ID:    session.ID,
+		URL:   session.URL,
+		Title: session.Title,
+	})
+}
+
+// openTab opens url in a new tab in the given browser context (the default
+// context if empty), waits for its session to attach, and makes it active.
+func (d *Daemon) openTab(url, browserContextID string) (*ipc.PageSession, error) {
+	session, err := d.createTab(url, browserContextID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make the new tab the active session. CDP foregrounds the new tab by default,
+	// so no explicit Target.activateTarget is required.
+	d.sessions.SetActive(session.ID)
+
+	if d.repl != nil {
+		d.repl.refreshPrompt()
+	}
+
+	return session, nil
+}
+
+// createTab opens url in a new tab in the given browser context (the default
+// context if empty) and waits for its session to attach. A background tab
+// opens without taking the foreground.
+func (d *Daemon) createTab(url, browserContextID string, background bool) (*ipc.PageSession, error) {
 	if url == "" {
 		url = "about:blank"
 	}
@@ -100,22 +142,29 @@ func (d *Daemon) handleTabNew(url string) ipc.Response {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	result, err := d.cdp.SendContext(ctx, "Target.createTarget", map[string]any{
+	params := map[string]any{
 		"url":       url,
 		"newWindow": false,
-	})
+	}
+	if browserContextID != "" {
+		params["browserContextId"] = browserContextID
+	}
+	if background {
+		params["background"] = true
+	}
+	result, err := d.cdp.SendContext(ctx, "Target.createTarget", params)
 	if err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("failed to create tab: %v", err))
+		return nil, fmt.Errorf("failed to create tab: %v", err)
 	}
 
 	var createResp struct {
 		TargetID string `json:"targetId"`
 	}
 	if err := json.Unmarshal(result, &createResp); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("failed to parse createTarget response: %v", err))
+		return nil, fmt.Errorf("failed to parse createTarget response: %v", err)
 	}
 	if createResp.TargetID == "" {
-		return ipc.ErrorResponse("createTarget returned empty targetId")
+		return nil, fmt.Errorf("createTarget returned empty targetId")
 	}
 
 	// Resolve the attach rendezvous through SessionManager. The check-current-state
@@ -128,27 +177,22 @@ func (d *Daemon) handleTabNew(url string) ipc.Response {
 		case <-wait:
 			session = d.sessions.GetByTargetID(createResp.TargetID)
 		case <-time.After(tabWaiterTimeout):
-			return ipc.ErrorResponse("timeout waiting for new tab to attach")
+			return nil, fmt.Errorf("timeout waiting for new tab to attach")
 		}
 	}
 
 	if session == nil {
-		return ipc.ErrorResponse("new tab attach event observed but session not found")
+		return nil, fmt.Errorf("new tab attach event observed but session not found")
 	}
 
-	// Make the new tab the active session. CDP foregrounds the new tab by default,
-	// so no explicit Target.activateTarget is required.
-	d.sessions.SetActive(session.ID)
-
-	if d.repl != nil {
-		d.repl.refreshPrompt()
+	// The attach event records the context too, but may not have yet when the
+	// waiter wakes
+	if browserContextID != "" {
+		d.sessions.SetBrowserContext(session.ID, browserContextID)
+		session.Context = browserContextID
 	}
 
-	return ipc.SuccessResponse(ipc.NewTabData{
-		ID:    session.ID,
-		URL:   session.URL,
-		Title: session.Title,
-	})
+	return session, nil
 }
 
 // handleTabClose closes the tab matching query, or the active tab if query is empty.
@@ -158,12 +202,12 @@ func (d *Daemon) handleTabClose(query string) ipc.Response {
 	if query == "" {
 		sessionID = d.sessions.ActiveID()
 		if sessionID == "" {
-			return ipc.ErrorResponse("no active tab")
+			return ipc.CodedErrorResponse(ipc.CodeNoSession, "no active tab")
 		}
 	} else {
 		matches := d.sessions.FindByQuery(query)
 		if len(matches) == 0 {
-			return ipc.ErrorResponse(fmt.Sprintf("no tab matches query: %s", query))
+			return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no tab matches query: %s", query))
 		}
 		if len(matches) > 1 {
 			return ambiguousTabError(query, matches)
@@ -198,7 +242,7 @@ func (d *Daemon) handleTabClose(query string) ipc.Response {
 		Success bool `json:"success"`
 	}
 	if err := json.Unmarshal(result, &closeResp); err != nil {
-		return ipc.ErrorResponse(fmt.Sprintf("invalid closeTarget response: %v", err))
+		return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("invalid closeTarget response: %v", err))
 	}
 	if !closeResp.Success {
 		return ipc.ErrorResponse("browser refused to close tab")
@@ -213,7 +257,7 @@ func (d *Daemon) handleTabClose(query string) ipc.Response {
 		select {
 		case <-wait:
 		case <-time.After(tabWaiterTimeout):
-			return ipc.ErrorResponse("timeout waiting for tab to close")
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, "timeout waiting for tab to close")
 		}
 	}
 
@@ -250,5 +294,5 @@ func ambiguousTabError(query string, matches []ipc.PageSession) ipc.Response {
 		Error:   msg,
 		Matches: matches,
 	})
-	return ipc.Response{OK: false, Error: msg, Data: raw}
+	return ipc.Response{OK: false, Error: msg, Code: ipc.CodeInvalidArgs, Data: raw}
 }
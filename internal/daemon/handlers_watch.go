@@ -0,0 +1,162 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// watchDOMBufferSize caps the mutations held in the page between polls.
+const watchDOMBufferSize = 1000
+
+// watchDOMJS installs (or reuses) a MutationObserver stored on
+// window.__webctlDomWatch and drains its buffered records. Format args: watch
+// ID, root selector, stop flag, buffer size.
+//
+// The observer lives in the page, so a navigation discards it; the next poll
+// finds it missing and installs a new one, reporting installed=true. If the
+// observed root is detached from the document the observer is dropped and
+// re-created once the selector matches again.
+const watchDOMJS = `(() => {
+	const id = %q;
+	const selector = %q;
+	const stop = %t;
+	const limit = %d;
+
+	let w = window.__webctlDomWatch;
+	if (w && (stop || w.id !== id || !w.root.isConnected)) {
+		w.observer.disconnect();
+		delete window.__webctlDomWatch;
+		w = null;
+	}
+	if (stop) {
+		return {status: 'stopped', mutations: []};
+	}
+
+	let installed = false;
+	if (!w) {
+		const root = selector ? document.querySelector(selector) : document.documentElement;
+		if (!root) {
+			return {status: 'waiting', mutations: []};
+		}
+
+		const describe = (n) => {
+			if (!n) {
+				return '';
+			}
+			if (n.nodeType === Node.TEXT_NODE) {
+				const text = n.data.trim().replace(/\s+/g, ' ');
+				return '#text "' + (text.length > 40 ? text.slice(0, 40) + '...' : text) + '"';
+			}
+			if (n.nodeType === Node.COMMENT_NODE) {
+				return '#comment';
+			}
+			if (n.nodeType !== Node.ELEMENT_NODE) {
+				return n.nodeName.toLowerCase();
+			}
+			let s = n.tagName.toLowerCase();
+			if (n.id) {
+				s += '#' + n.id;
+			}
+			const classes = (n.getAttribute('class') || '').split(/\s+/).filter(Boolean).slice(0, 2);
+			for (const c of classes) {
+				s += '.' + c;
+			}
+			return s;
+		};
+
+		const state = {id, root, records: [], dropped: 0};
+		const push = (rec) => {
+			state.records.push(rec);
+			if (state.records.length > limit) {
+				state.records.shift();
+				state.dropped++;
+			}
+		};
+		state.observer = new MutationObserver((list) => {
+			const time = Date.now();
+			for (const m of list) {
+				if (m.type === 'childList') {
+					for (const n of m.addedNodes) {
+						push({time, type: 'added', target: describe(m.target), node: describe(n)});
+					}
+					for (const n of m.removedNodes) {
+						push({time, type: 'removed', target: describe(m.target), node: describe(n)});
+					}
+				} else if (m.type === 'attributes') {
+					push({
+						time,
+						type: 'attribute',
+						target: describe(m.target),
+						attribute: m.attributeName,
+						oldValue: m.oldValue,
+						value: m.target.getAttribute(m.attributeName)
+					});
+				} else if (m.type === 'characterData') {
+					push({
+						time,
+						type: 'text',
+						target: describe(m.target.parentNode),
+						oldValue: m.oldValue,
+						value: m.target.data
+					});
+				}
+			}
+		});
+		state.observer.observe(root, {
+			subtree: true,
+			childList: true,
+			attributes: true,
+			attributeOldValue: true,
+			characterData: true,
+			characterDataOldValue: true
+		});
+		w = window.__webctlDomWatch = state;
+		installed = true;
+	}
+
+	const mutations = w.records.splice(0);
+	const dropped = w.dropped;
+	w.dropped = 0;
+	return {status: 'watching', installed, dropped, mutations};
+})()`
+
+// handleWatchDOM installs a DOM mutation observer in the page on first use and
+// returns the mutations recorded since the previous poll.
+func (d *Daemon) handleWatchDOM(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.WatchDOMParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid watch-dom parameters: %v", err))
+	}
+
+	if params.WatchID == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "watch ID is required")
+	}
+
+	js := fmt.Sprintf(watchDOMJS, params.WatchID, params.Selector, params.Stop, watchDOMBufferSize)
+
+	var data ipc.WatchDOMData
+	found, err := d.evalElementQuery(activeID, js, &data)
+	if err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to watch DOM: %v", err))
+	}
+	if !found {
+		return ipc.ErrorResponse("failed to watch DOM: no result from page")
+	}
+	if data.Mutations == nil {
+		data.Mutations = []ipc.DOMMutation{}
+	}
+
+	return ipc.SuccessResponse(data)
+}
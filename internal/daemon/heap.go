@@ -0,0 +1,536 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// Leakcheck defaults, used when the request leaves them zero.
+const (
+	heapDefaultCycles = 5
+	heapDefaultSettle = 500 // milliseconds
+	heapDefaultTop    = 10
+)
+
+// heapSnapshotTimeout bounds taking one heap snapshot. Large pages take tens
+// of seconds to serialise.
+const heapSnapshotTimeout = 2 * time.Minute
+
+// heapConstructorStat counts the live objects of one constructor in a heap
+// snapshot.
+type heapConstructorStat struct {
+	count int
+	size  int64
+}
+
+// handleHeap handles the "heap" command.
+func (d *Daemon) handleHeap(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.HeapParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid heap parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "leakcheck":
+		return d.heapLeakcheck(activeID, params)
+	case "snapshot":
+		return d.heapSnapshot(activeID, req.Stream)
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown heap action: %s", params.Action))
+	}
+}
+
+// heapSnapshot takes a heap snapshot. Streamed, it is spooled to a temporary
+// file and copied to the client once complete, so a snapshot of hundreds of
+// megabytes is never held in the daemon and a slow client never holds up the
+// CDP read loop; otherwise it is returned in the data.
+func (d *Daemon) heapSnapshot(sessionID string, stream bool) ipc.Response {
+	if stream {
+		f, err := d.heapSnapshotFile(sessionID)
+		if err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		return ipc.StreamResponse(func(w io.Writer) error {
+			defer removeHeapSnapshotFile(f)
+			_, err := io.Copy(w, f)
+			return err
+		})
+	}
+	var buf bytes.Buffer
+	if err := d.takeHeapSnapshot(sessionID, &buf); err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+	return ipc.SuccessResponse(ipc.HeapSnapshotData{Snapshot: buf.Bytes()})
+}
+
+// heapLeakcheck runs the action params describe once per cycle, sampling the
+// heap after a forced garbage collection each time, and compares heap
+// snapshots taken after the first and last cycles. The first cycle warms up
+// caches and lazy initialisation, so growth is measured from there.
+func (d *Daemon) heapLeakcheck(sessionID string, params ipc.HeapParams) ipc.Response {
+	cycles, settle, top := params.Cycles, params.Settle, params.Top
+	if cycles == 0 {
+		cycles = heapDefaultCycles
+	}
+	if settle == 0 {
+		settle = heapDefaultSettle
+	}
+	if top == 0 {
+		top = heapDefaultTop
+	}
+	switch {
+	case cycles < 2:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid cycles %d (need at least 2 to compare)", cycles))
+	case settle < 0:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid settle %dms (must not be negative)", settle))
+	case top < 0:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid top %d (must not be negative)", top))
+	}
+
+	action := "reload"
+	if params.Expression != "" {
+		action = params.Expression
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := d.sendToSession(ctx, sessionID, "HeapProfiler.enable", nil); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to enable heap profiler: %v", err))
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _ = d.sendToSession(ctx, sessionID, "HeapProfiler.disable", nil)
+	}()
+
+	data := ipc.HeapLeakcheckData{Action: action, Constructors: []ipc.HeapConstructorGrowth{}}
+	var first, last map[string]heapConstructorStat
+	for cycle := 1; cycle <= cycles; cycle++ {
+		if resp, ok := d.heapCycleAction(sessionID, params.Expression); !ok {
+			return resp
+		}
+		time.Sleep(time.Duration(settle) * time.Millisecond)
+
+		sample, err := d.heapSample(sessionID)
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("cycle %d: %v", cycle, err))
+		}
+		sample.Cycle = cycle
+		data.Samples = append(data.Samples, sample)
+		d.log.Debug("heap leakcheck sample", "cycle", cycle, "used", sample.UsedSize)
+
+		if cycle == 1 || cycle == cycles {
+			stats, err := d.heapSnapshotStats(sessionID)
+			if err != nil {
+				return ipc.ErrorResponse(fmt.Sprintf("cycle %d: %v", cycle, err))
+			}
+			if cycle == 1 {
+				first = stats
+			} else {
+				last = stats
+			}
+		}
+	}
+
+	data.Growth = data.Samples[len(data.Samples)-1].UsedSize - data.Samples[0].UsedSize
+	data.Monotonic = heapMonotonic(data.Samples)
+	data.Constructors = heapGrowth(first, last, top)
+	return ipc.SuccessResponse(data)
+}
+
+// heapCycleAction runs one leakcheck action: the expression, awaiting a
+// returned promise, or a reload that waits for the page to load.
+func (d *Daemon) heapCycleAction(sessionID, expression string) (ipc.Response, bool) {
+	if expression == "" {
+		nav := d.navTracker.begin(sessionID)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := d.sendToSession(ctx, sessionID, "Page.reload", nil); err != nil {
+			d.navTracker.abort(sessionID, nav)
+			return ipc.ErrorResponse(fmt.Sprintf("reload failed: %v", err)), false
+		}
+		switch awaitMilestone(nav.Loaded(), nav.Cancelled(), cdp.DefaultTimeout) {
+		case navCancelled:
+			return cancelledNavResponse(nav, sessionID), false
+		case navTimedOut:
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, "timeout waiting for page load"), false
+		}
+		return ipc.Response{}, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cdp.DefaultTimeout)
+	defer cancel()
+	result, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+		"expression":   expression,
+		"awaitPromise": true,
+	})
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, fmt.Sprintf("action timed out after %s", cdp.DefaultTimeout)), false
+		}
+		return ipc.ErrorResponse(fmt.Sprintf("failed to run action: %v", err)), false
+	}
+	var resp struct {
+		ExceptionDetails *struct {
+			Text      string `json:"text"`
+			Exception struct {
+				Description string `json:"description"`
+			} `json:"exception"`
+		} `json:"exceptionDetails"`
+	}
+	if err := json.Unmarshal(result, &resp); err == nil && resp.ExceptionDetails != nil {
+		msg := resp.ExceptionDetails.Exception.Description
+		if msg == "" {
+			msg = resp.ExceptionDetails.Text
+		}
+		return ipc.CodedErrorResponse(ipc.CodeFailed, fmt.Sprintf("action failed: %s", msg)), false
+	}
+	return ipc.Response{}, true
+}
+
+// heapSample forces a garbage collection and reads the JavaScript heap size.
+func (d *Daemon) heapSample(sessionID string) (ipc.HeapSample, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := d.sendToSession(ctx, sessionID, "HeapProfiler.collectGarbage", nil); err != nil {
+		return ipc.HeapSample{}, fmt.Errorf("failed to collect garbage: %v", err)
+	}
+	result, err := d.sendToSession(ctx, sessionID, "Runtime.getHeapUsage", nil)
+	if err != nil {
+		return ipc.HeapSample{}, fmt.Errorf("failed to read heap usage: %v", err)
+	}
+	var usage struct {
+		UsedSize  float64 `json:"usedSize"`
+		TotalSize float64 `json:"totalSize"`
+	}
+	if err := json.Unmarshal(result, &usage); err != nil {
+		return ipc.HeapSample{}, fmt.Errorf("failed to parse heap usage: %v", err)
+	}
+	return ipc.HeapSample{UsedSize: int64(usage.UsedSize), TotalSize: int64(usage.TotalSize)}, nil
+}
+
+// heapSnapshotStats takes a heap snapshot through a temporary file and counts
+// its objects by constructor.
+func (d *Daemon) heapSnapshotStats(sessionID string) (map[string]heapConstructorStat, error) {
+	f, err := d.heapSnapshotFile(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer removeHeapSnapshotFile(f)
+
+	stats, err := parseHeapSnapshot(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse heap snapshot: %v", err)
+	}
+	return stats, nil
+}
+
+// heapSnapshotFile takes a heap snapshot into a temporary file, rewound for
+// reading. The caller disposes of it with removeHeapSnapshotFile.
+func (d *Daemon) heapSnapshotFile(sessionID string) (*os.File, error) {
+	f, err := os.CreateTemp("", "webctl-heap-*.heapsnapshot")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create heap snapshot file: %v", err)
+	}
+	if err := d.takeHeapSnapshot(sessionID, f); err != nil {
+		removeHeapSnapshotFile(f)
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		removeHeapSnapshotFile(f)
+		return nil, fmt.Errorf("failed to read heap snapshot: %v", err)
+	}
+	return f, nil
+}
+
+// removeHeapSnapshotFile closes and deletes a file from heapSnapshotFile.
+func removeHeapSnapshotFile(f *os.File) {
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+}
+
+// takeHeapSnapshot takes a heap snapshot of a session, writing its JSON to w
+// as Chrome sends it. The chunks arrive as events ahead of the command's
+// response, so the snapshot is complete when this returns. w is written on
+// the CDP read loop, so it must be a local file or buffer, never a client
+// connection that could stall every tab's events.
+func (d *Daemon) takeHeapSnapshot(sessionID string, w io.Writer) error {
+	d.heapSnapshotsMu.Lock()
+	if _, busy := d.heapSnapshots[sessionID]; busy {
+		d.heapSnapshotsMu.Unlock()
+		return errors.New("a heap snapshot is already being taken")
+	}
+	if d.heapSnapshots == nil {
+		d.heapSnapshots = make(map[string]io.Writer)
+	}
+	cw := &heapChunkWriter{w: w}
+	d.heapSnapshots[sessionID] = cw
+	d.heapSnapshotsMu.Unlock()
+	defer func() {
+		d.heapSnapshotsMu.Lock()
+		delete(d.heapSnapshots, sessionID)
+		d.heapSnapshotsMu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), heapSnapshotTimeout)
+	defer cancel()
+	if _, err := d.sendToSession(ctx, sessionID, "HeapProfiler.takeHeapSnapshot", map[string]any{
+		"reportProgress": false,
+	}); err != nil {
+		return fmt.Errorf("failed to take heap snapshot: %v", err)
+	}
+	if cw.err != nil {
+		return fmt.Errorf("failed to write heap snapshot: %v", cw.err)
+	}
+	return nil
+}
+
+// addHeapSnapshotChunk writes a heap snapshot chunk to the snapshot being
+// taken in a session, if any. Called on the CDP read loop.
+func (d *Daemon) addHeapSnapshotChunk(sessionID, chunk string) {
+	d.heapSnapshotsMu.Lock()
+	w := d.heapSnapshots[sessionID]
+	d.heapSnapshotsMu.Unlock()
+	if w != nil {
+		_, _ = io.WriteString(w, chunk)
+	}
+}
+
+// heapChunkWriter keeps the first write error, so one failed chunk fails the
+// snapshot rather than leaving a gap in it.
+type heapChunkWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (c *heapChunkWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.err = err
+	return n, err
+}
+
+// heapMonotonic reports whether the used heap grew on every cycle after the
+// first.
+func heapMonotonic(samples []ipc.HeapSample) bool {
+	if len(samples) < 2 {
+		return false
+	}
+	for i := 1; i < len(samples); i++ {
+		if samples[i].UsedSize <= samples[i-1].UsedSize {
+			return false
+		}
+	}
+	return true
+}
+
+// heapGrowth returns the constructors with more live objects in last than
+// in first, largest size growth first, at most top of them.
+func heapGrowth(first, last map[string]heapConstructorStat, top int) []ipc.HeapConstructorGrowth {
+	growth := []ipc.HeapConstructorGrowth{}
+	for name, s := range last {
+		base := first[name]
+		if s.count <= base.count {
+			continue
+		}
+		growth = append(growth, ipc.HeapConstructorGrowth{
+			Name:       name,
+			Count:      s.count,
+			Size:       s.size,
+			CountDelta: s.count - base.count,
+			SizeDelta:  s.size - base.size,
+		})
+	}
+	sort.Slice(growth, func(i, j int) bool {
+		a, b := growth[i], growth[j]
+		if a.SizeDelta != b.SizeDelta {
+			return a.SizeDelta > b.SizeDelta
+		}
+		if a.CountDelta != b.CountDelta {
+			return a.CountDelta > b.CountDelta
+		}
+		return a.Name < b.Name
+	})
+	if len(growth) > top {
+		growth = growth[:top]
+	}
+	return growth
+}
+
+// parseHeapSnapshot counts the objects in a V8 heap snapshot by constructor,
+// the grouping of the DevTools summary view. The snapshot is read as a token
+// stream, so its node and edge arrays, often millions of numbers, are never
+// held in memory.
+func parseHeapSnapshot(r io.Reader) (map[string]heapConstructorStat, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var meta struct {
+		Meta struct {
+			NodeFields []string          `json:"node_fields"`
+			NodeTypes  []json.RawMessage `json:"node_types"`
+		} `json:"meta"`
+	}
+	type nodeKey struct{ typ, name int }
+	counts := map[nodeKey]heapConstructorStat{}
+	var types, strs []string
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tok {
+		case "snapshot":
+			if err := dec.Decode(&meta); err != nil {
+				return nil, err
+			}
+			if len(meta.Meta.NodeTypes) > 0 {
+				if err := json.Unmarshal(meta.Meta.NodeTypes[0], &types); err != nil {
+					return nil, fmt.Errorf("invalid node types: %v", err)
+				}
+			}
+		case "nodes":
+			fields := meta.Meta.NodeFields
+			typeField, nameField, sizeField := slices.Index(fields, "type"), slices.Index(fields, "name"), slices.Index(fields, "self_size")
+			if typeField < 0 || nameField < 0 || sizeField < 0 {
+				return nil, errors.New("missing node fields in the snapshot metadata")
+			}
+			if err := expectDelim(dec, '['); err != nil {
+				return nil, err
+			}
+			var key nodeKey
+			var size int64
+			for i := 0; dec.More(); i++ {
+				tok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				n, ok := tok.(float64)
+				if !ok {
+					return nil, fmt.Errorf("invalid node value %v", tok)
+				}
+				switch i % len(fields) {
+				case typeField:
+					key.typ = int(n)
+				case nameField:
+					key.name = int(n)
+				case sizeField:
+					size = int64(n)
+				}
+				if i%len(fields) == len(fields)-1 {
+					s := counts[key]
+					s.count++
+					s.size += size
+					counts[key] = s
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+		case "strings":
+			if err := dec.Decode(&strs); err != nil {
+				return nil, err
+			}
+		default:
+			if err := skipJSONValue(dec); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	stats := map[string]heapConstructorStat{}
+	for key, s := range counts {
+		typ := ""
+		if key.typ < len(types) {
+			typ = types[key.typ]
+		}
+		name := ""
+		if key.name < len(strs) {
+			name = strs[key.name]
+		}
+		c := heapConstructorName(typ, name)
+		total := stats[c]
+		total.count += s.count
+		total.size += s.size
+		stats[c] = total
+	}
+	return stats, nil
+}
+
+// heapConstructorName groups a heap node as the DevTools summary does:
+// objects by constructor name, everything else by kind.
+func heapConstructorName(typ, name string) string {
+	switch typ {
+	case "object", "native":
+		return name
+	case "string", "concatenated string", "sliced string":
+		return "(string)"
+	case "closure", "array", "regexp", "number", "symbol", "bigint":
+		return "(" + typ + ")"
+	case "code":
+		return "(compiled code)"
+	default:
+		return "(system)"
+	}
+}
+
+// expectDelim reads the next token and checks it is the delimiter want.
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// skipJSONValue reads past the next value, token by token, without holding
+// it in memory.
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
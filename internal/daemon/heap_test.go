@@ -0,0 +1,167 @@
+package daemon
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// testHeapSnapshot is a minimal V8 heap snapshot: two HTMLDivElement
+// objects, a closure, a string, and a hidden node. The edges and locations
+// are there to be skipped.
+const testHeapSnapshot = `{
+	"snapshot": {
+		"meta": {
+			"node_fields": ["type", "name", "id", "self_size", "edge_count"],
+			"node_types": [["hidden", "array", "string", "object", "code", "closure"], "string", "number", "number", "number"]
+		},
+		"node_count": 5
+	},
+	"nodes": [
+		3, 1, 1, 40, 0,
+		3, 1, 3, 40, 0,
+		5, 2, 5, 32, 1,
+		2, 3, 7, 24, 0,
+		0, 0, 9, 8, 0
+	],
+	"edges": [1, 2, 0],
+	"locations": [],
+	"strings": ["(GC roots)", "HTMLDivElement", "onClick", "hello"]
+}`
+
+func TestParseHeapSnapshot(t *testing.T) {
+	stats, err := parseHeapSnapshot(strings.NewReader(testHeapSnapshot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]heapConstructorStat{
+		"HTMLDivElement": {count: 2, size: 80},
+		"(closure)":      {count: 1, size: 32},
+		"(string)":       {count: 1, size: 24},
+		"(system)":       {count: 1, size: 8},
+	}
+	if len(stats) != len(want) {
+		t.Errorf("stats = %v, want %v", stats, want)
+	}
+	for name, w := range want {
+		if stats[name] != w {
+			t.Errorf("stats[%q] = %+v, want %+v", name, stats[name], w)
+		}
+	}
+
+	if _, err := parseHeapSnapshot(strings.NewReader(`{"nodes": [1, 2]}`)); err == nil {
+		t.Error("expected an error for nodes without metadata")
+	}
+}
+
+func TestHeapGrowth(t *testing.T) {
+	first := map[string]heapConstructorStat{
+		"Listener": {count: 10, size: 400},
+		"Array":    {count: 5, size: 100},
+		"Gone":     {count: 3, size: 30},
+	}
+	last := map[string]heapConstructorStat{
+		"Listener":                {count: 20, size: 800},
+		"Array":                   {count: 5, size: 200},
+		"Detached HTMLDivElement": {count: 50, size: 4000},
+	}
+	got := heapGrowth(first, last, 10)
+	if len(got) != 2 || got[0].Name != "Detached HTMLDivElement" || got[0].CountDelta != 50 || got[1].Name != "Listener" || got[1].SizeDelta != 400 {
+		t.Errorf("heapGrowth = %+v", got)
+	}
+	if got := heapGrowth(first, last, 1); len(got) != 1 {
+		t.Errorf("heapGrowth with top 1 = %+v", got)
+	}
+}
+
+func TestHeapMonotonic(t *testing.T) {
+	samples := func(sizes ...int64) []ipc.HeapSample {
+		var s []ipc.HeapSample
+		for i, n := range sizes {
+			s = append(s, ipc.HeapSample{Cycle: i + 1, UsedSize: n})
+		}
+		return s
+	}
+	if !heapMonotonic(samples(10, 11, 12)) {
+		t.Error("steady growth should be monotonic")
+	}
+	if heapMonotonic(samples(10, 12, 12)) || heapMonotonic(samples(10, 12, 11)) || heapMonotonic(samples(10)) {
+		t.Error("flat, falling, or single samples should not be monotonic")
+	}
+}
+
+func TestHeapLeakcheck_Validation(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+
+	for _, params := range []ipc.HeapParams{
+		{Action: "leakcheck", Cycles: 1},
+		{Action: "leakcheck", Settle: -5},
+		{Action: "leakcheck", Top: -1},
+	} {
+		resp := d.heapLeakcheck("AAA1", params)
+		if resp.OK || resp.Code != ipc.CodeInvalidArgs {
+			t.Errorf("%+v: response = %+v, want %s", params, resp, ipc.CodeInvalidArgs)
+		}
+	}
+	if reqs := conn.getCapturedRequests(); len(reqs) != 0 {
+		t.Errorf("invalid requests reached the browser: %v", reqs)
+	}
+}
+
+func TestAddHeapSnapshotChunk(t *testing.T) {
+	d := New(DefaultConfig())
+	var b strings.Builder
+	d.heapSnapshots = map[string]io.Writer{"AAA1": &b}
+
+	d.addHeapSnapshotChunk("AAA1", `{"snap`)
+	d.addHeapSnapshotChunk("BBB2", `ignored`)
+	d.addHeapSnapshotChunk("AAA1", `shot":{}}`)
+	if b.String() != `{"snapshot":{}}` {
+		t.Errorf("snapshot = %q", b.String())
+	}
+}
+
+func TestHeapSnapshot_StreamSpoolsBeforeBody(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+
+	resp := d.heapSnapshot("AAA1", true)
+	if !resp.OK || resp.Body == nil {
+		t.Fatalf("response = %+v, want a streamed body", resp)
+	}
+	// The snapshot is taken in the request, so the body only copies a file
+	reqs := conn.getCapturedRequests()
+	if len(reqs) != 1 || reqs[0].Method != "HeapProfiler.takeHeapSnapshot" {
+		t.Fatalf("requests before the body = %v", reqs)
+	}
+	if entries, _ := os.ReadDir(tmp); len(entries) != 1 {
+		t.Fatalf("spool files = %v, want 1", entries)
+	}
+
+	var out strings.Builder
+	if err := resp.Body(&out); err != nil {
+		t.Fatalf("body error = %v", err)
+	}
+	if len(conn.getCapturedRequests()) != 1 {
+		t.Error("the body sent CDP requests")
+	}
+	if entries, _ := os.ReadDir(tmp); len(entries) != 0 {
+		t.Errorf("spool files left after the body = %v", entries)
+	}
+}
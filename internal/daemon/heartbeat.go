@@ -18,6 +18,12 @@ func classifyDisconnect(err error) string {
 	if err == nil {
 		return "browser disconnected"
 	}
+	if errors.Is(err, errBrowserExited) {
+		if browserCrashed(err) {
+			return "browser process crashed"
+		}
+		return "browser closed normally"
+	}
 
 	code := websocket.CloseStatus(err)
 	switch code {
@@ -35,6 +41,7 @@ func classifyDisconnect(err error) string {
 // to detect silent browser disconnections. On failure, it sends the underlying
 // error to disconnectCh for classification by Run().
 func (d *Daemon) startHeartbeat(ctx context.Context, disconnectCh chan<- error) {
+	c := d.cdp
 	go func() {
 		ticker := time.NewTicker(heartbeatInterval)
 		defer ticker.Stop()
@@ -45,7 +52,7 @@ func (d *Daemon) startHeartbeat(ctx context.Context, disconnectCh chan<- error)
 				return
 			case <-ticker.C:
 				hbCtx, cancel := context.WithTimeout(ctx, heartbeatTimeout)
-				_, err := d.cdp.SendContext(hbCtx, "Browser.getVersion", nil)
+				_, err := c.SendContext(hbCtx, "Browser.getVersion", nil)
 				timedOut := hbCtx.Err() == context.DeadlineExceeded
 				cancel()
 
@@ -68,7 +75,7 @@ func (d *Daemon) startHeartbeat(ctx context.Context, disconnectCh chan<- error)
 				}
 
 				// Underlying websocket error from the CDP client.
-				cdpErr := d.cdp.Err()
+				cdpErr := c.Err()
 				if cdpErr == nil {
 					cdpErr = err
 				}
@@ -81,5 +88,5 @@ func (d *Daemon) startHeartbeat(ctx context.Context, disconnectCh chan<- error)
 		}
 	}()
 
-	d.debugf(false, "Heartbeat started (interval=%s, timeout=%s)", heartbeatInterval, heartbeatTimeout)
+	d.log.Debug("heartbeat started", "interval", heartbeatInterval, "timeout", heartbeatTimeout)
 }
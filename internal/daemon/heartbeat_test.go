@@ -3,6 +3,7 @@ package daemon
 import (
 	"context"
 	"fmt"
+	"os/exec"
 	"testing"
 
 	"github.com/coder/websocket"
@@ -54,6 +55,16 @@ func TestClassifyDisconnect(t *testing.T) {
 			err:  fmt.Errorf("something went wrong"),
 			want: "browser connection lost",
 		},
+		{
+			name: "clean process exit",
+			err:  errBrowserExited,
+			want: "browser closed normally",
+		},
+		{
+			name: "process crash",
+			err:  fmt.Errorf("%w: %w", errBrowserExited, &exec.ExitError{}),
+			want: "browser process crashed",
+		},
 	}
 
 	for _, tt := range tests {
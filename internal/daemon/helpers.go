@@ -147,6 +147,14 @@ func extensionFromMimeType(mimeType string) string {
 	return ""
 }
 
+// removeBodyFiles deletes saved response bodies, for entries dropped from
+// the network buffer one tab at a time.
+func removeBodyFiles(paths []string) {
+	for _, p := range paths {
+		_ = os.Remove(p)
+	}
+}
+
 // clearBodiesDir removes all files in the bodies directory.
 func clearBodiesDir() error {
 	bodiesDir := getBodiesDir()
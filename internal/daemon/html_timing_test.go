@@ -37,7 +37,7 @@ func TestHTMLTiming_NetworkIdleBlocking(t *testing.T) {
 		SocketPath: socketPath,
 		PIDPath:    pidPath,
 		BufferSize: 100,
-		Debug:      false, // Set to true to see CDP timing details
+		LogHandler: nil, // Set to a debug-level handler to see CDP timing details
 	}
 
 	d := New(cfg)
@@ -0,0 +1,147 @@
+package daemon
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// Request interception is shared by 'webctl mock' and 'network abort', which
+// act on requests before they are sent, and 'webctl rewrite', which edits
+// responses before the page sees them. The Fetch domain is enabled in every
+// tab while any mock, abort rule, or rewrite exists, with one pattern per
+// rule at the stage it needs.
+
+// interceptPattern turns a --url pattern into a Fetch URL pattern. A pattern
+// without wildcards matches anywhere in the URL, as --url does for the
+// network filter; Chrome's own patterns match the whole URL.
+func interceptPattern(pattern string) string {
+	if !strings.ContainsAny(pattern, "*?") {
+		return "*" + pattern + "*"
+	}
+	return pattern
+}
+
+// interceptPatterns returns the Fetch.enable patterns for the current abort
+// rules, mocks, and rewrites, or nil when there are none.
+func (d *Daemon) interceptPatterns() []map[string]string {
+	var patterns []map[string]string
+	d.abortsMu.Lock()
+	for _, r := range d.aborts {
+		patterns = append(patterns, map[string]string{"urlPattern": r.URL, "requestStage": "Request"})
+	}
+	d.abortsMu.Unlock()
+	d.mocksMu.Lock()
+	for _, m := range d.mocks {
+		patterns = append(patterns, map[string]string{"urlPattern": m.info.URL, "requestStage": "Request"})
+	}
+	d.mocksMu.Unlock()
+	d.rewritesMu.Lock()
+	for _, r := range d.rewrites {
+		patterns = append(patterns, map[string]string{"urlPattern": r.info.URL, "requestStage": "Response"})
+	}
+	d.rewritesMu.Unlock()
+	return patterns
+}
+
+// syncInterception updates request interception in every tab to the current
+// rules and, when that works, returns data as the response.
+func (d *Daemon) syncInterception(data any) ipc.Response {
+	patterns := d.interceptPatterns()
+	for _, s := range d.sessions.All() {
+		if err := d.applyInterception(s.ID, patterns); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to update request interception: %v", err))
+		}
+	}
+	return ipc.SuccessResponse(data)
+}
+
+// applyInterception intercepts the requests matching patterns in a session,
+// or stops intercepting when there are none.
+func (d *Daemon) applyInterception(sessionID string, patterns []map[string]string) error {
+	ctx := context.Background()
+	if len(patterns) == 0 {
+		_, err := d.sendToSession(ctx, sessionID, "Fetch.disable", nil)
+		return err
+	}
+	_, err := d.sendToSession(ctx, sessionID, "Fetch.enable", map[string]any{"patterns": patterns})
+	return err
+}
+
+// handleRequestPaused routes a paused request to the abort rules and then the
+// mocks, or to the rewrites when Chrome paused it at the response stage,
+// which it marks with the response status or error.
+func (d *Daemon) handleRequestPaused(evt cdp.Event) {
+	var params struct {
+		RequestID string `json:"requestId"`
+		NetworkID string `json:"networkId"`
+		Request   struct {
+			URL string `json:"url"`
+		} `json:"request"`
+		ResponseStatusCode  int                 `json:"responseStatusCode"`
+		ResponseErrorReason string              `json:"responseErrorReason"`
+		ResponseHeaders     []map[string]string `json:"responseHeaders"`
+	}
+	if err := json.Unmarshal(evt.Params, &params); err != nil {
+		return
+	}
+	if params.ResponseStatusCode == 0 && params.ResponseErrorReason == "" {
+		// Held requests are found by the ID the network buffer shows
+		networkID := cmp.Or(params.NetworkID, params.RequestID)
+		if rule, spent, ok := d.matchAbort(params.Request.URL); ok {
+			d.abortRequest(evt.SessionID, params.RequestID, networkID, params.Request.URL, rule, spent)
+			return
+		}
+		d.settleMockedRequest(evt.SessionID, params.RequestID, networkID, params.Request.URL)
+		return
+	}
+	d.rewriteResponse(evt.SessionID, params.RequestID, params.Request.URL, params.ResponseStatusCode, params.ResponseHeaders)
+}
+
+// interceptURLMatch reports whether url matches a Fetch URL pattern: *
+// matches any run of characters, ? one character, and a backslash escapes
+// the next.
+func interceptURLMatch(pattern, url string) bool {
+	// Unescape into literal flags so the matcher can backtrack over stars
+	// without re-reading escapes.
+	type token struct {
+		c       byte
+		literal bool
+	}
+	var tokens []token
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			i++
+			tokens = append(tokens, token{pattern[i], true})
+			continue
+		}
+		tokens = append(tokens, token{pattern[i], false})
+	}
+
+	p, u := 0, 0
+	star, mark := -1, 0
+	for u < len(url) {
+		switch {
+		case p < len(tokens) && !tokens[p].literal && tokens[p].c == '*':
+			star, mark = p, u
+			p++
+		case p < len(tokens) && ((!tokens[p].literal && tokens[p].c == '?') || tokens[p].c == url[u]):
+			p++
+			u++
+		case star >= 0:
+			p, mark = star+1, mark+1
+			u = mark
+		default:
+			return false
+		}
+	}
+	for p < len(tokens) && !tokens[p].literal && tokens[p].c == '*' {
+		p++
+	}
+	return p == len(tokens)
+}
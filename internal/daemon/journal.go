@@ -0,0 +1,250 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// DefaultJournalSize caps each buffer's journal on disk, in bytes.
+const DefaultJournalSize = 32 << 20
+
+// restoredSession is the session ID given to entries restored from a journal.
+// The tabs they came from are gone, so they are shown with every tab.
+const restoredSession = "restored"
+
+// DefaultPersistDir returns the directory for buffer journals
+// ($XDG_STATE_HOME/webctl/buffers).
+func DefaultPersistDir() string {
+	return filepath.Join(filepath.Dir(getBodiesDir()), "buffers")
+}
+
+// persister keeps a copy of a ring buffer's entries outside it. Its methods
+// are called under the buffer's write lock, so they see changes in order.
+type persister[T any] interface {
+	// write records an entry that was pushed or updated.
+	write(item T)
+	// rewrite replaces everything recorded with items.
+	rewrite(items []T)
+}
+
+// journal persists a ring buffer's entries as JSON lines, so they survive a
+// daemon restart. It only appends: an entry is written when pushed and again
+// each time it is updated, and loading keeps the last record for each
+// sequence number. Once the file passes half the size cap it is rotated to
+// path+".1", replacing the previous one, so the pair stays under the cap.
+type journal[T any] struct {
+	mu   sync.Mutex
+	path string
+	max  int64
+	f    *os.File
+	size int64
+	log  *slog.Logger
+}
+
+// openJournal loads the entries recorded at path, oldest first, and starts a
+// new journal there. seq returns an entry's sequence number, which tells the
+// records of one entry apart from another's.
+func openJournal[T any](path string, limit int64, seq func(*T) uint64, log *slog.Logger) (*journal[T], []T, error) {
+	var entries []T
+	index := make(map[uint64]int)
+	for _, p := range []string{path + ".1", path} {
+		if err := readJournal(p, func(item T) {
+			if i, ok := index[seq(&item)]; ok {
+				entries[i] = item
+				return
+			}
+			index[seq(&item)] = len(entries)
+			entries = append(entries, item)
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, nil, err
+	}
+	j := &journal[T]{path: path, max: limit, log: log}
+	// Entries are renumbered when pushed back into the buffer, so their old
+	// records must go
+	if err := j.truncate(); err != nil {
+		return nil, nil, err
+	}
+	return j, entries, nil
+}
+
+// readJournal calls fn with each record in the journal file at path. A
+// missing file has no records; a record cut short by a crash is skipped.
+func readJournal[T any](path string, fn func(T)) error {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			var item T
+			if json.Unmarshal(line, &item) == nil {
+				fn(item)
+			}
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+func (j *journal[T]) write(item T) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.append(item)
+}
+
+func (j *journal[T]) rewrite(items []T) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return
+	}
+	if err := j.truncate(); err != nil {
+		j.fail(err)
+		return
+	}
+	for _, item := range items {
+		j.append(item)
+	}
+}
+
+// append writes a record, rotating the file first if it would pass half the
+// size cap. The caller holds mu.
+func (j *journal[T]) append(item T) {
+	if j.f == nil {
+		return
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+	if j.size > 0 && j.size+int64(len(data)) > j.max/2 {
+		if err := j.rotate(); err != nil {
+			j.fail(err)
+			return
+		}
+	}
+	n, err := j.f.Write(data)
+	j.size += int64(n)
+	if err != nil {
+		j.fail(err)
+	}
+}
+
+// truncate empties the journal and removes its rotated file.
+func (j *journal[T]) truncate() error {
+	if err := os.Remove(j.path + ".1"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if j.f != nil {
+		_ = j.f.Close()
+	}
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		j.f = nil
+		return err
+	}
+	j.f, j.size = f, 0
+	return nil
+}
+
+// rotate moves the journal to path+".1" and starts an empty one.
+func (j *journal[T]) rotate() error {
+	_ = j.f.Close()
+	j.f = nil
+	if err := os.Rename(j.path, j.path+".1"); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	j.f, j.size = f, 0
+	return nil
+}
+
+// fail stops the journal after a write error, which is logged once. The
+// buffer carries on in memory.
+func (j *journal[T]) fail(err error) {
+	j.log.Warn("buffer journal failed, no longer persisting", "path", j.path, "error", err)
+	if j.f != nil {
+		_ = j.f.Close()
+		j.f = nil
+	}
+}
+
+// close closes the journal file.
+func (j *journal[T]) close() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f != nil {
+		_ = j.f.Close()
+		j.f = nil
+	}
+}
+
+// restoreBuffers loads the console and network journals in PersistDir into
+// the buffers and keeps journaling them there. The journals are named after
+// the socket, so daemons on different sockets keep their own.
+func (d *Daemon) restoreBuffers() (closeJournals func(), err error) {
+	base := strings.TrimSuffix(filepath.Base(d.config.SocketPath), filepath.Ext(d.config.SocketPath))
+	prefix := filepath.Join(d.config.PersistDir, base)
+
+	console, consoleEntries, err := openJournal(prefix+"-console.jsonl", DefaultJournalSize,
+		func(e *ipc.ConsoleEntry) uint64 { return e.Seq }, d.log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open console journal: %w", err)
+	}
+	network, networkEntries, err := openJournal(prefix+"-network.jsonl", DefaultJournalSize,
+		func(e *ipc.NetworkEntry) uint64 { return e.Seq }, d.log)
+	if err != nil {
+		console.close()
+		return nil, fmt.Errorf("failed to open network journal: %w", err)
+	}
+
+	// Entries beyond the buffer's capacity would only be overwritten
+	consoleEntries = consoleEntries[max(0, len(consoleEntries)-d.consoleBuf.Cap()):]
+	networkEntries = networkEntries[max(0, len(networkEntries)-d.networkBuf.Cap()):]
+
+	d.consoleBuf.SetPersister(console)
+	d.networkBuf.SetPersister(network)
+	for _, e := range consoleEntries {
+		e.SessionID = restoredSession
+		d.consoleBuf.Push(e)
+	}
+	for _, e := range networkEntries {
+		e.SessionID = restoredSession
+		d.networkBuf.Push(e)
+	}
+	d.log.Info("buffers restored", "dir", d.config.PersistDir, "console", len(consoleEntries), "network", len(networkEntries))
+
+	return func() {
+		d.consoleBuf.SetPersister(nil)
+		d.networkBuf.SetPersister(nil)
+		console.close()
+		network.close()
+	}, nil
+}
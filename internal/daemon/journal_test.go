@@ -0,0 +1,163 @@
+package daemon
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func consoleSeq(e *ipc.ConsoleEntry) uint64 { return e.Seq }
+
+func TestJournal_RestoresLastRecordPerEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "console.jsonl")
+	j, entries, err := openJournal(path, DefaultJournalSize, consoleSeq, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("new journal has %d entries", len(entries))
+	}
+
+	buf := NewRingBuffer(10, func(e *ipc.ConsoleEntry, s uint64) { e.Seq = s })
+	buf.SetPersister(j)
+	buf.Push(ipc.ConsoleEntry{Type: "log", Text: "one"})
+	buf.Push(ipc.ConsoleEntry{Type: "log", Text: "two"})
+	buf.Update(func(e *ipc.ConsoleEntry) bool {
+		if e.Text == "one" {
+			e.Text = "one, updated"
+			return true
+		}
+		return false
+	})
+	j.close()
+
+	// A record cut short by a crash is skipped
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"seq":3,"type":"log","te`)
+	_ = f.Close()
+
+	j, entries, err = openJournal(path, DefaultJournalSize, consoleSeq, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.close()
+	if len(entries) != 2 || entries[0].Text != "one, updated" || entries[1].Text != "two" {
+		t.Errorf("restored %+v, want the updated first entry and the second", entries)
+	}
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Errorf("journal not emptied on open: %q", data)
+	}
+}
+
+func TestJournal_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "console.jsonl")
+	const limit = 4 << 10
+	j, _, err := openJournal(path, limit, consoleSeq, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.close()
+
+	buf := NewRingBuffer(1000, func(e *ipc.ConsoleEntry, s uint64) { e.Seq = s })
+	buf.SetPersister(j)
+	for range 200 {
+		buf.Push(ipc.ConsoleEntry{Type: "log", Text: strings.Repeat("x", 50)})
+	}
+
+	var total int64
+	for _, p := range []string{path, path + ".1"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total += info.Size()
+	}
+	if total > limit {
+		t.Errorf("journal files total %d bytes, want at most %d", total, limit)
+	}
+}
+
+func TestJournal_ClearAndRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "console.jsonl")
+	j, _, err := openJournal(path, DefaultJournalSize, consoleSeq, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := NewRingBuffer(10, func(e *ipc.ConsoleEntry, s uint64) { e.Seq = s })
+	buf.SetPersister(j)
+	buf.Push(ipc.ConsoleEntry{SessionID: "A", Text: "closed tab"})
+	buf.Push(ipc.ConsoleEntry{SessionID: "B", Text: "open tab"})
+	buf.RemoveIf(func(e *ipc.ConsoleEntry) bool { return e.SessionID == "A" })
+	j.close()
+
+	j, entries, err := openJournal(path, DefaultJournalSize, consoleSeq, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Text != "open tab" {
+		t.Errorf("after RemoveIf, restored %+v, want only the open tab's entry", entries)
+	}
+
+	buf.SetPersister(j)
+	buf.Push(ipc.ConsoleEntry{Text: "cleared"})
+	buf.Clear()
+	j.close()
+	j, entries, err = openJournal(path, DefaultJournalSize, consoleSeq, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	j.close()
+	if len(entries) != 0 {
+		t.Errorf("after Clear, restored %+v, want none", entries)
+	}
+}
+
+func TestDaemon_RestoreBuffers(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.PersistDir = t.TempDir()
+	cfg.SocketPath = filepath.Join(t.TempDir(), "webctl.sock")
+	cfg.BufferSize = 2
+
+	d := New(cfg)
+	closeJournals, err := d.restoreBuffers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{"one", "two", "three"} {
+		d.consoleBuf.Push(ipc.ConsoleEntry{SessionID: "S1", Text: text})
+	}
+	d.networkBuf.Push(ipc.NetworkEntry{SessionID: "S1", URL: "https://example.com/"})
+	closeJournals()
+
+	if _, err := os.Stat(filepath.Join(cfg.PersistDir, "webctl-console.jsonl")); err != nil {
+		t.Errorf("console journal not named after the socket: %v", err)
+	}
+
+	d = New(cfg)
+	closeJournals, err = d.restoreBuffers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeJournals()
+
+	console := d.consoleBuf.All()
+	if len(console) != 2 || console[0].Text != "two" || console[1].Text != "three" {
+		t.Errorf("restored console %+v, want the last two entries", console)
+	}
+	for _, e := range console {
+		if e.SessionID != restoredSession {
+			t.Errorf("restored entry has session %q, want %q", e.SessionID, restoredSession)
+		}
+	}
+	if network := d.networkBuf.All(); len(network) != 1 || network[0].URL != "https://example.com/" || network[0].Seq != 1 {
+		t.Errorf("restored network %+v", network)
+	}
+}
@@ -0,0 +1,133 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// Logpoints are Debugger breakpoints whose condition logs an expression and
+// evaluates to false, so the page logs at that line without ever pausing.
+// See debugger.go for how they are set in each tab.
+
+// logpointCondition is the breakpoint condition for a logpoint: it logs the
+// expression and never pauses.
+const logpointCondition = "console.log(%s) && false"
+
+// handleLogpoint adds, lists, or removes logpoints.
+func (d *Daemon) handleLogpoint(req ipc.Request) ipc.Response {
+	var params ipc.LogpointParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid logpoint parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "add":
+		return d.addLogpoint(params)
+	case "list", "":
+		return ipc.SuccessResponse(ipc.LogpointData{Logpoints: d.logpointList()})
+	case "remove":
+		d.logpointsMu.Lock()
+		i := -1
+		for j, lp := range d.logpoints {
+			if lp.ID == params.ID {
+				i = j
+				break
+			}
+		}
+		if i < 0 {
+			d.logpointsMu.Unlock()
+			return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no logpoint with id %q", params.ID))
+		}
+		removed := d.logpoints[i]
+		d.logpoints = append(d.logpoints[:i:i], d.logpoints[i+1:]...)
+		d.logpointsMu.Unlock()
+		d.log.Info("logpoint removed", "id", params.ID)
+		if _, err := d.syncDebugger(); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		return ipc.SuccessResponse(ipc.LogpointData{Logpoints: []ipc.LogpointInfo{removed}})
+	case "clear":
+		removed := d.logpointList()
+		d.logpointsMu.Lock()
+		d.logpoints = nil
+		d.logpointsMu.Unlock()
+		d.log.Info("logpoints cleared", "count", len(removed))
+		if _, err := d.syncDebugger(); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+		return ipc.SuccessResponse(ipc.LogpointData{Logpoints: removed})
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown logpoint action: %s", params.Action))
+	}
+}
+
+// addLogpoint validates a logpoint and sets it in every tab.
+func (d *Daemon) addLogpoint(params ipc.LogpointParams) ipc.Response {
+	switch {
+	case params.URL == "":
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "url is required")
+	case params.Line < 1:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid line %d (lines start at 1)", params.Line))
+	case params.Column < 0:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid column %d (columns start at 1)", params.Column))
+	case strings.TrimSpace(params.Expression) == "":
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "expression is required")
+	}
+
+	d.logpointsMu.Lock()
+	d.logpointSeq++
+	info := ipc.LogpointInfo{
+		ID:         "l" + strconv.Itoa(d.logpointSeq),
+		URL:        params.URL,
+		Line:       params.Line,
+		Column:     params.Column,
+		Expression: params.Expression,
+		Created:    time.Now().UnixMilli(),
+	}
+	d.logpoints = append(d.logpoints, info)
+	d.logpointsMu.Unlock()
+
+	d.log.Info("logpoint added", "id", info.ID, "url", info.URL, "line", info.Line, "column", info.Column)
+	locations, err := d.syncDebugger()
+	if err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+	info.Locations = locations[info.ID]
+	return ipc.SuccessResponse(ipc.LogpointData{Logpoints: []ipc.LogpointInfo{info}})
+}
+
+// logpointList returns a copy of the logpoints in the order they were added.
+func (d *Daemon) logpointList() []ipc.LogpointInfo {
+	d.logpointsMu.Lock()
+	defer d.logpointsMu.Unlock()
+	return append([]ipc.LogpointInfo{}, d.logpoints...)
+}
+
+// logpointURLRegex turns a logpoint URL pattern into the regular expression
+// Debugger.setBreakpointByUrl matches script URLs with. As for "mock", a
+// pattern with * or ? matches the whole URL; one without matches anywhere.
+func logpointURLRegex(pattern string) string {
+	if !strings.ContainsAny(pattern, "*?") {
+		return regexp.QuoteMeta(pattern)
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
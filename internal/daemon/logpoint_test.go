@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func logpointRequest(t *testing.T, d *Daemon, params ipc.LogpointParams) (ipc.Response, ipc.LogpointData) {
+	t.Helper()
+	raw, _ := json.Marshal(params)
+	resp := d.handleLogpoint(ipc.Request{Cmd: "logpoint", Params: raw})
+	var data ipc.LogpointData
+	if resp.OK {
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp, data
+}
+
+func TestHandleLogpoint_AddValidation(t *testing.T) {
+	d := New(DefaultConfig())
+
+	tests := []struct {
+		name   string
+		params ipc.LogpointParams
+		want   string
+	}{
+		{"no url", ipc.LogpointParams{Action: "add", Line: 1, Expression: "x"}, "url is required"},
+		{"no line", ipc.LogpointParams{Action: "add", URL: "app.js", Expression: "x"}, "invalid line 0"},
+		{"no expression", ipc.LogpointParams{Action: "add", URL: "app.js", Line: 3, Expression: " "}, "expression is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := logpointRequest(t, d, tt.params)
+			if resp.OK || !strings.Contains(resp.Error, tt.want) || resp.Code != ipc.CodeInvalidArgs {
+				t.Errorf("response = %+v, want %s containing %q", resp, ipc.CodeInvalidArgs, tt.want)
+			}
+		})
+	}
+	if len(d.logpoints) != 0 {
+		t.Errorf("invalid logpoints were added: %d", len(d.logpoints))
+	}
+}
+
+func TestHandleLogpoint_Lifecycle(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+
+	resp, data := logpointRequest(t, d, ipc.LogpointParams{Action: "add", URL: "app.js", Line: 120, Column: 7, Expression: "user.id"})
+	if !resp.OK || len(data.Logpoints) != 1 || data.Logpoints[0].ID != "l1" {
+		t.Fatalf("add = %+v %+v", resp, data)
+	}
+
+	var methods []string
+	var bp map[string]any
+	for _, req := range conn.getCapturedRequests() {
+		methods = append(methods, req.Method)
+		if req.Method == "Debugger.setBreakpointByUrl" {
+			bp = req.Params.(map[string]any)
+		}
+	}
+	if strings.Join(methods, ",") != "Debugger.disable,Debugger.enable,Debugger.setBreakpointByUrl" {
+		t.Errorf("methods = %v", methods)
+	}
+	if bp["urlRegex"] != `app\.js` || bp["lineNumber"] != 119.0 || bp["columnNumber"] != 6.0 || bp["condition"] != "console.log(user.id) && false" {
+		t.Errorf("setBreakpointByUrl params = %v", bp)
+	}
+
+	_, _ = logpointRequest(t, d, ipc.LogpointParams{Action: "add", URL: "*/vendor.js", Line: 1, Expression: "a, b"})
+	_, data = logpointRequest(t, d, ipc.LogpointParams{Action: "list"})
+	if len(data.Logpoints) != 2 || data.Logpoints[1].ID != "l2" {
+		t.Fatalf("list = %+v", data.Logpoints)
+	}
+
+	if resp, _ := logpointRequest(t, d, ipc.LogpointParams{Action: "remove", ID: "l9"}); resp.OK || resp.Code != ipc.CodeNotFound {
+		t.Errorf("removing an unknown logpoint = %+v", resp)
+	}
+	if resp, _ := logpointRequest(t, d, ipc.LogpointParams{Action: "remove", ID: "l1"}); !resp.OK {
+		t.Fatalf("remove = %+v", resp)
+	}
+	_, data = logpointRequest(t, d, ipc.LogpointParams{Action: "clear"})
+	if len(data.Logpoints) != 1 || data.Logpoints[0].ID != "l2" || len(d.logpointList()) != 0 {
+		t.Errorf("clear = %+v", data.Logpoints)
+	}
+	reqs := conn.getCapturedRequests()
+	if last := reqs[len(reqs)-1]; last.Method != "Debugger.disable" {
+		t.Errorf("last request after clear = %s, want Debugger.disable", last.Method)
+	}
+}
+
+func TestLogpointURLRegex(t *testing.T) {
+	tests := []struct {
+		pattern string
+		url     string
+		match   bool
+	}{
+		{"app.js", "https://example.com/static/app.js?v=2", true},
+		{"app.js", "https://example.com/static/appxjs", false},
+		{"https://cdn.*/*.min.js", "https://cdn.example.com/lib/vendor.min.js", true},
+		{"https://cdn.*/*.min.js", "https://cdn.example.com/lib/vendor.min.js.map", false},
+		{"*/v?.js", "https://example.com/v2.js", true},
+	}
+	for _, tt := range tests {
+		re := regexp.MustCompile(logpointURLRegex(tt.pattern))
+		if got := re.MatchString(tt.url); got != tt.match {
+			t.Errorf("logpointURLRegex(%q) on %q = %v, want %v", tt.pattern, tt.url, got, tt.match)
+		}
+	}
+}
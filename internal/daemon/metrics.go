@@ -0,0 +1,102 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/grantcarthew/webctl/internal/metrics"
+)
+
+// daemonMetrics holds the counters and histograms the daemon updates as it
+// runs. Gauges are registered as functions reading daemon state at scrape time.
+type daemonMetrics struct {
+	registry        *metrics.Registry
+	cdpLatency      *metrics.Histogram
+	cdpErrors       *metrics.Counter
+	ipcRequests     *metrics.CounterVec
+	ipcErrors       *metrics.CounterVec
+	browserRestarts *metrics.Counter
+}
+
+// newMetrics registers the daemon's metrics. Gauges read d's sessions and
+// buffers, which must be set before the first scrape.
+func (d *Daemon) newMetrics() *daemonMetrics {
+	r := metrics.New()
+	m := &daemonMetrics{
+		registry: r,
+		cdpLatency: r.NewHistogram("webctl_cdp_command_duration_seconds",
+			"Round-trip time of CDP commands sent to the browser.", metrics.DefaultLatencyBuckets),
+		cdpErrors: r.NewCounter("webctl_cdp_command_errors_total",
+			"CDP commands that failed, timed out, or were cut off by a closed connection."),
+		ipcRequests: r.NewCounterVec("webctl_ipc_requests_total",
+			"IPC requests handled, by command.", "cmd"),
+		ipcErrors: r.NewCounterVec("webctl_ipc_request_errors_total",
+			"IPC requests that returned an error response, by command.", "cmd"),
+		browserRestarts: r.NewCounter("webctl_browser_restarts_total",
+			"Times the daemon relaunched the browser after it exited."),
+	}
+
+	r.NewGaugeFunc("webctl_daemon_start_time_seconds", "Unix time the daemon started.", func() float64 {
+		return float64(d.started.UnixNano()) / 1e9
+	})
+	r.NewGaugeFunc("webctl_sessions", "Attached page sessions.", func() float64 {
+		return float64(d.sessions.Count())
+	})
+	r.NewGaugeFunc("webctl_console_buffer_entries", "Entries in the console buffer.", func() float64 {
+		return float64(d.consoleBuf.Len())
+	})
+	r.NewGaugeFunc("webctl_network_buffer_entries", "Entries in the network buffer.", func() float64 {
+		return float64(d.networkBuf.Len())
+	})
+	r.NewGaugeFunc("webctl_buffer_capacity", "Capacity of each event buffer.", func() float64 {
+		return float64(d.consoleBuf.Cap())
+	})
+	return m
+}
+
+// observeCDP records a completed CDP command. It is the cdp.Client observer.
+func (m *daemonMetrics) observeCDP(_ string, d time.Duration, err error) {
+	m.cdpLatency.ObserveDuration(d)
+	if err != nil {
+		m.cdpErrors.Inc()
+	}
+}
+
+// observeIPC records a handled IPC request.
+func (m *daemonMetrics) observeIPC(req ipc.Request, resp ipc.Response) {
+	m.ipcRequests.Inc(req.Cmd)
+	if !resp.OK {
+		m.ipcErrors.Inc(req.Cmd)
+	}
+}
+
+// serveMetrics starts an HTTP listener on addr exposing the metrics at
+// /metrics. It returns the bound address and a function that shuts it down.
+func (d *Daemon) serveMetrics(addr string) (net.Addr, func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start metrics listener: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", d.metrics.registry.Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			d.log.Warn("metrics listener stopped", "error", err)
+		}
+	}()
+	d.log.Info("metrics listening", "addr", listener.Addr().String())
+
+	return listener.Addr(), func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,56 @@
+package daemon
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestMetrics_ServesDaemonState(t *testing.T) {
+	d := New(DefaultConfig())
+	d.consoleBuf.Push(ipc.ConsoleEntry{Type: "log", Text: "hi"})
+	d.metrics.observeIPC(ipc.Request{Cmd: "status"}, ipc.Response{OK: true})
+	d.metrics.observeIPC(ipc.Request{Cmd: "click"}, ipc.ErrorResponse("no match"))
+	d.metrics.observeCDP("Page.navigate", 20*time.Millisecond, nil)
+
+	addr, stop, err := d.serveMetrics("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://" + addr.String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	for _, want := range []string{
+		"webctl_sessions 0\n",
+		"webctl_console_buffer_entries 1\n",
+		"webctl_network_buffer_entries 0\n",
+		`webctl_ipc_requests_total{cmd="click"} 1`,
+		`webctl_ipc_requests_total{cmd="status"} 1`,
+		`webctl_ipc_request_errors_total{cmd="click"} 1`,
+		"webctl_cdp_command_duration_seconds_count 1\n",
+		"webctl_cdp_command_errors_total 0\n",
+		"webctl_browser_restarts_total 0\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics missing %q", want)
+		}
+	}
+}
+
+func TestMetrics_ListenError(t *testing.T) {
+	d := New(DefaultConfig())
+	if _, _, err := d.serveMetrics("256.0.0.1:bad"); err == nil {
+		t.Fatal("expected listen error")
+	}
+}
@@ -0,0 +1,216 @@
+package daemon
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// mockRand rolls each request against a mock's fail rate. Replaceable for
+// testing.
+var mockRand = rand.Float64
+
+// mockRule is a 'webctl mock' rule: requests whose URL matches are held,
+// failed, or answered with a stub through the Fetch domain.
+type mockRule struct {
+	info ipc.MockInfo
+	body []byte
+}
+
+// mockAction is what happens to one paused request.
+type mockAction struct {
+	delay  time.Duration
+	fail   bool
+	status int // failure status, or stub status when not failing
+	body   []byte
+	ctype  string
+}
+
+// handleMock adds, lists, or removes mocks.
+func (d *Daemon) handleMock(req ipc.Request) ipc.Response {
+	var params ipc.MockParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid mock parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "add":
+		return d.addMock(params)
+	case "list", "":
+		return ipc.SuccessResponse(ipc.MockData{Mocks: d.mockList()})
+	case "remove":
+		d.mocksMu.Lock()
+		i := d.mockIndex(params.ID)
+		if i < 0 {
+			d.mocksMu.Unlock()
+			return ipc.ErrorResponse(fmt.Sprintf("no mock with id %q", params.ID))
+		}
+		removed := d.mocks[i].info
+		d.mocks = append(d.mocks[:i:i], d.mocks[i+1:]...)
+		d.mocksMu.Unlock()
+		d.log.Info("mock removed", "id", params.ID)
+		return d.syncInterception(ipc.MockData{Mocks: []ipc.MockInfo{removed}})
+	case "clear":
+		removed := d.mockList()
+		d.mocksMu.Lock()
+		d.mocks = nil
+		d.mocksMu.Unlock()
+		d.log.Info("mocks cleared", "count", len(removed))
+		return d.syncInterception(ipc.MockData{Mocks: removed})
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown mock action: %s", params.Action))
+	}
+}
+
+// addMock validates a mock and starts intercepting its URL in every tab.
+func (d *Daemon) addMock(params ipc.MockParams) ipc.Response {
+	if params.URL == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "url is required")
+	}
+	if params.Delay < 0 {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "delay must not be negative")
+	}
+	if params.FailRate < 0 || params.FailRate > 1 {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("fail rate %v must be between 0 and 1", params.FailRate))
+	}
+	for _, status := range []int{params.Status, params.FailStatus} {
+		if status != 0 && (status < 100 || status > 599) {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid status %d (use 100-599)", status))
+		}
+	}
+	if params.Delay == 0 && params.FailRate == 0 && params.Status == 0 {
+		return ipc.ErrorResponse("mock does nothing: set a delay, a fail rate, or a stub status")
+	}
+
+	pattern := interceptPattern(params.URL)
+	rule := &mockRule{
+		info: ipc.MockInfo{
+			URL:        pattern,
+			Delay:      params.Delay,
+			FailRate:   params.FailRate,
+			FailStatus: params.FailStatus,
+			Status:     params.Status,
+			Created:    time.Now().UnixMilli(),
+		},
+	}
+	if params.Status != 0 {
+		rule.body = []byte(params.Body)
+		rule.info.BodyBytes = len(rule.body)
+		rule.info.ContentType = params.ContentType
+		if rule.info.ContentType == "" {
+			rule.info.ContentType = mockContentType(params.Body)
+		}
+	}
+
+	d.mocksMu.Lock()
+	d.mockSeq++
+	rule.info.ID = "k" + strconv.Itoa(d.mockSeq)
+	d.mocks = append(d.mocks, rule)
+	info := rule.info
+	d.mocksMu.Unlock()
+
+	d.log.Info("mock added", "id", info.ID, "url", pattern, "delayMs", info.Delay, "failRate", info.FailRate, "status", info.Status)
+	return d.syncInterception(ipc.MockData{Mocks: []ipc.MockInfo{info}})
+}
+
+// mockContentType guesses a stub body's content type: JSON when it parses,
+// otherwise plain text.
+func mockContentType(body string) string {
+	if json.Valid([]byte(body)) {
+		return "application/json"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// mockIndex returns the position of the mock with id, or -1. Callers hold
+// mocksMu.
+func (d *Daemon) mockIndex(id string) int {
+	for i, m := range d.mocks {
+		if m.info.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// mockList returns every mock in the order they were added.
+func (d *Daemon) mockList() []ipc.MockInfo {
+	d.mocksMu.Lock()
+	defer d.mocksMu.Unlock()
+	infos := make([]ipc.MockInfo, 0, len(d.mocks))
+	for _, m := range d.mocks {
+		infos = append(infos, m.info)
+	}
+	return infos
+}
+
+// settleMockedRequest holds, fails, stubs, or continues a request paused at
+// the request stage, by the first mock matching its URL. A held request can
+// be cut short by 'network abort <requestId>'. It runs off the read loop.
+func (d *Daemon) settleMockedRequest(sessionID, requestID, networkID, url string) {
+	action, id, ok := d.matchMock(url)
+	go func() {
+		aborted := false
+		if ok && action.delay > 0 {
+			h := d.holdRequest(sessionID, requestID, networkID, url, id, true)
+			aborted, _ = d.awaitHeld(h, action.delay)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		method, body := "Fetch.continueRequest", map[string]any{"requestId": requestID}
+		switch {
+		case aborted:
+			method, body["errorReason"] = "Fetch.failRequest", "Aborted"
+		case !ok:
+			// The mock was removed after Chrome paused the request.
+		case action.fail && action.status == 0:
+			method, body["errorReason"] = "Fetch.failRequest", "Failed"
+		case action.fail || action.status != 0:
+			method = "Fetch.fulfillRequest"
+			body["responseCode"] = action.status
+			body["responseHeaders"] = []map[string]string{{"name": "Content-Type", "value": action.ctype}}
+			body["body"] = base64.StdEncoding.EncodeToString(action.body)
+		}
+		if _, err := d.cdp.SendToSession(ctx, sessionID, method, body); err != nil {
+			d.log.Debug(method+" failed", "requestId", requestID, "error", err)
+			return
+		}
+		d.log.Debug("mock applied", "id", id, "url", url, "method", method)
+	}()
+}
+
+// matchMock counts a request against the first mock matching url and decides
+// what happens to it.
+func (d *Daemon) matchMock(url string) (mockAction, string, bool) {
+	d.mocksMu.Lock()
+	defer d.mocksMu.Unlock()
+	for _, m := range d.mocks {
+		if !interceptURLMatch(m.info.URL, url) {
+			continue
+		}
+		m.info.Hits++
+		action := mockAction{
+			delay:  time.Duration(m.info.Delay) * time.Millisecond,
+			status: m.info.Status,
+			body:   m.body,
+			ctype:  m.info.ContentType,
+		}
+		if m.info.FailRate > 0 && mockRand() < m.info.FailRate {
+			m.info.Failures++
+			action.fail = true
+			action.status = m.info.FailStatus
+			action.body = []byte(http.StatusText(m.info.FailStatus))
+			action.ctype = "text/plain; charset=utf-8"
+		}
+		return action, m.info.ID, true
+	}
+	return mockAction{}, "", false
+}
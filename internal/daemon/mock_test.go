@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func mockRequest(t *testing.T, d *Daemon, params ipc.MockParams) (ipc.Response, ipc.MockData) {
+	t.Helper()
+	raw, _ := json.Marshal(params)
+	resp := d.handleMock(ipc.Request{Cmd: "mock", Params: raw})
+	var data ipc.MockData
+	if resp.OK {
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp, data
+}
+
+func TestHandleMock_AddValidation(t *testing.T) {
+	d := New(DefaultConfig())
+
+	tests := []struct {
+		name   string
+		params ipc.MockParams
+		want   string
+	}{
+		{"no url", ipc.MockParams{Action: "add", Delay: 100}, "url is required"},
+		{"no effect", ipc.MockParams{Action: "add", URL: "/api"}, "mock does nothing"},
+		{"rate too high", ipc.MockParams{Action: "add", URL: "/api", FailRate: 1.5}, "between 0 and 1"},
+		{"bad status", ipc.MockParams{Action: "add", URL: "/api", Status: 42}, "invalid status 42"},
+		{"negative delay", ipc.MockParams{Action: "add", URL: "/api", Delay: -1}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := mockRequest(t, d, tt.params)
+			if resp.OK || !strings.Contains(resp.Error, tt.want) {
+				t.Errorf("response = %+v, want error containing %q", resp, tt.want)
+			}
+		})
+	}
+	if len(d.mocks) != 0 {
+		t.Errorf("invalid mocks were added: %d", len(d.mocks))
+	}
+}
+
+func TestHandleMock_Lifecycle(t *testing.T) {
+	d := New(DefaultConfig())
+
+	resp, data := mockRequest(t, d, ipc.MockParams{Action: "add", URL: "*/api/*", Delay: 2000, FailRate: 0.3})
+	if !resp.OK || len(data.Mocks) != 1 || data.Mocks[0].ID != "k1" || data.Mocks[0].URL != "*/api/*" {
+		t.Fatalf("add = %+v %+v", resp, data)
+	}
+	_, data = mockRequest(t, d, ipc.MockParams{Action: "add", URL: "/api/flags", Status: 200, Body: `{"beta":true}`})
+	stub := data.Mocks[0]
+	if stub.URL != "*/api/flags*" || stub.ContentType != "application/json" || stub.BodyBytes != 13 {
+		t.Errorf("stub = %+v", stub)
+	}
+
+	_, data = mockRequest(t, d, ipc.MockParams{Action: "list"})
+	if len(data.Mocks) != 2 || data.Mocks[0].ID != "k1" || data.Mocks[1].ID != "k2" {
+		t.Fatalf("list = %+v", data.Mocks)
+	}
+	if patterns := d.interceptPatterns(); len(patterns) != 2 || patterns[1]["urlPattern"] != "*/api/flags*" {
+		t.Errorf("patterns = %v", patterns)
+	}
+
+	if resp, _ := mockRequest(t, d, ipc.MockParams{Action: "remove", ID: "k9"}); resp.OK {
+		t.Error("removing an unknown mock succeeded")
+	}
+	if resp, _ := mockRequest(t, d, ipc.MockParams{Action: "remove", ID: "k1"}); !resp.OK {
+		t.Fatalf("remove = %+v", resp)
+	}
+	_, data = mockRequest(t, d, ipc.MockParams{Action: "clear"})
+	if len(data.Mocks) != 1 || data.Mocks[0].ID != "k2" || d.interceptPatterns() != nil {
+		t.Errorf("clear = %+v, patterns = %v", data.Mocks, d.interceptPatterns())
+	}
+}
+
+func TestMatchMock(t *testing.T) {
+	d := New(DefaultConfig())
+	roll := 0.5
+	old := mockRand
+	mockRand = func() float64 { return roll }
+	t.Cleanup(func() { mockRand = old })
+
+	mockRequest(t, d, ipc.MockParams{Action: "add", URL: "*/api/orders*", FailRate: 0.3, FailStatus: 503})
+	mockRequest(t, d, ipc.MockParams{Action: "add", URL: "*/api/*", Status: 200, Body: "ok", Delay: 250})
+
+	// The first mock added wins; a roll above the fail rate passes through.
+	action, id, ok := d.matchMock("https://app.test/api/orders/7")
+	if !ok || id != "k1" || action.fail || action.status != 0 {
+		t.Errorf("pass = %+v %s %v", action, id, ok)
+	}
+	roll = 0.1
+	action, _, _ = d.matchMock("https://app.test/api/orders/7")
+	if !action.fail || action.status != 503 || string(action.body) != "Service Unavailable" {
+		t.Errorf("fail = %+v", action)
+	}
+
+	action, id, _ = d.matchMock("https://app.test/api/user")
+	if id != "k2" || action.fail || action.status != 200 || string(action.body) != "ok" || action.delay.Milliseconds() != 250 {
+		t.Errorf("stub = %+v %s", action, id)
+	}
+
+	if _, _, ok := d.matchMock("https://app.test/index.html"); ok {
+		t.Error("unmatched URL matched a mock")
+	}
+
+	list := d.mockList()
+	if list[0].Hits != 2 || list[0].Failures != 1 || list[1].Hits != 1 {
+		t.Errorf("counts = %+v", list)
+	}
+}
+
+func TestInterceptURLMatch(t *testing.T) {
+	tests := []struct {
+		pattern, url string
+		want         bool
+	}{
+		{"*/api/*", "https://app.test/api/user", true},
+		{"*/api/*", "https://app.test/apix/user", false},
+		{"https://app.test/", "https://app.test/", true},
+		{"https://app.test/", "https://app.test/x", false},
+		{"*.png", "https://cdn.test/a/logo.png", true},
+		{"*.png", "https://cdn.test/a/logo.png?v=2", false},
+		{"*/v?/*", "https://app.test/v2/items", true},
+		{"*/v?/*", "https://app.test/v10/items", false},
+		{"*\\?q=*", "https://app.test/search?q=go", true},
+		{"*\\*", "https://app.test/a", false},
+		{"*a*b*c*", strings.Repeat("a", 200) + "b", false},
+		{"*", "", true},
+	}
+	for _, tt := range tests {
+		if got := interceptURLMatch(tt.pattern, tt.url); got != tt.want {
+			t.Errorf("interceptURLMatch(%q, %q) = %v, want %v", tt.pattern, tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+const (
+	// monitorMinEvery is the shortest interval between a monitor's checks.
+	monitorMinEvery = 5 * time.Second
+	// monitorDefaultTimeout bounds a check when the monitor sets no timeout.
+	monitorDefaultTimeout = 30 * time.Second
+	// monitorResultsKept is how many results each monitor keeps in memory;
+	// results.jsonl in its directory has them all.
+	monitorResultsKept = 500
+	// monitorAssertPoll is how often a failing assertion is retried until the
+	// check times out, so content rendered after load is seen.
+	monitorAssertPoll = 250 * time.Millisecond
+	// monitorWebhookTimeout bounds a webhook delivery.
+	monitorWebhookTimeout = 10 * time.Second
+)
+
+// monitorDir holds each monitor's results and screenshots, in a directory
+// named after its ID. Replaceable for testing.
+var monitorDir = "/tmp/webctl-monitors"
+
+// Webhook events, sent when a monitor starts failing and when it recovers.
+const (
+	monitorEventFailure  = "failure"
+	monitorEventRecovery = "recovery"
+)
+
+// monitor is a URL checked on a schedule by 'webctl monitor'.
+type monitor struct {
+	seq     int // creation order, for listing
+	info    ipc.MonitorInfo
+	results []ipc.MonitorResult
+	stop    chan struct{}
+}
+
+// monitorAssertJS evaluates one monitor assertion, given its kind and value,
+// returning {failure} with a message when it does not hold and null failure
+// when it does.
+const monitorAssertJS = `((kind, value) => {
+	const q = (s) => JSON.stringify(s);
+	try {
+		switch (kind) {
+		case 'selector':
+			return {failure: document.querySelector(value) ? null : 'no element matches ' + value};
+		case 'text':
+			return {failure: document.body && document.body.innerText.includes(value) ? null : 'page text does not contain ' + q(value)};
+		case 'title':
+			return {failure: document.title.includes(value) ? null : 'title ' + q(document.title) + ' does not contain ' + q(value)};
+		case 'url':
+			return {failure: location.href.includes(value) ? null : 'url ' + q(location.href) + ' does not contain ' + q(value)};
+		case 'js':
+			return {failure: (0, eval)(value) ? null : value + ' is not truthy'};
+		}
+		return {failure: 'unknown assertion ' + kind};
+	} catch (e) {
+		return {failure: String(e && e.message || e)};
+	}
+})`
+
+// handleMonitor adds, lists, reports on, or removes monitors.
+func (d *Daemon) handleMonitor(req ipc.Request) ipc.Response {
+	var params ipc.MonitorParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid monitor parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "add":
+		return d.addMonitor(params)
+	case "list", "":
+		return ipc.SuccessResponse(ipc.MonitorData{Monitors: d.monitorList()})
+	case "results":
+		d.monitorsMu.Lock()
+		defer d.monitorsMu.Unlock()
+		m, ok := d.monitors[params.ID]
+		if !ok {
+			return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no monitor with id %q", params.ID))
+		}
+		results := m.results
+		if params.Limit > 0 && len(results) > params.Limit {
+			results = results[len(results)-params.Limit:]
+		}
+		return ipc.SuccessResponse(ipc.MonitorData{
+			Monitors: []ipc.MonitorInfo{m.info},
+			Results:  append([]ipc.MonitorResult{}, results...),
+		})
+	case "remove":
+		d.monitorsMu.Lock()
+		defer d.monitorsMu.Unlock()
+		m, ok := d.monitors[params.ID]
+		if !ok {
+			return ipc.CodedErrorResponse(ipc.CodeNotFound, fmt.Sprintf("no monitor with id %q", params.ID))
+		}
+		close(m.stop)
+		delete(d.monitors, params.ID)
+		d.log.Info("monitor removed", "id", params.ID)
+		return ipc.SuccessResponse(ipc.MonitorData{Monitors: []ipc.MonitorInfo{m.info}})
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown monitor action: %s", params.Action))
+	}
+}
+
+// addMonitor validates a monitor's settings and starts checking it, with the
+// first check right away.
+func (d *Daemon) addMonitor(params ipc.MonitorParams) ipc.Response {
+	if params.URL == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "url is required")
+	}
+	every := time.Duration(params.Every) * time.Millisecond
+	if every < monitorMinEvery {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("--every must be at least %s", monitorMinEvery))
+	}
+	timeout := time.Duration(params.Timeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = monitorDefaultTimeout
+	}
+	for _, a := range params.Assert {
+		if _, _, err := ipc.ParseMonitorAssert(a); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+	}
+	if params.Webhook != "" {
+		if u, err := url.Parse(params.Webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid webhook URL: %s", params.Webhook))
+		}
+	}
+
+	d.monitorsMu.Lock()
+	defer d.monitorsMu.Unlock()
+	if d.monitors == nil {
+		d.monitors = make(map[string]*monitor)
+	}
+	d.monitorSeq++
+	id := "m" + strconv.Itoa(d.monitorSeq)
+	dir := filepath.Join(monitorDir, id+"-"+time.Now().Format("06-01-02-150405"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to create monitor directory: %v", err))
+	}
+
+	m := &monitor{
+		seq: d.monitorSeq,
+		info: ipc.MonitorInfo{
+			ID:         id,
+			URL:        params.URL,
+			Every:      every.Milliseconds(),
+			Timeout:    timeout.Milliseconds(),
+			Screenshot: params.Screenshot,
+			Assert:     params.Assert,
+			Webhook:    params.Webhook,
+			Dir:        dir,
+			Created:    time.Now().UnixMilli(),
+		},
+		stop: make(chan struct{}),
+	}
+	d.monitors[id] = m
+	d.log.Info("monitor added", "id", id, "url", params.URL, "every", every)
+
+	go d.runMonitor(m)
+	return ipc.SuccessResponse(ipc.MonitorData{Monitors: []ipc.MonitorInfo{m.info}})
+}
+
+// monitorList returns every monitor in the order they were added.
+func (d *Daemon) monitorList() []ipc.MonitorInfo {
+	d.monitorsMu.Lock()
+	defer d.monitorsMu.Unlock()
+	ms := make([]*monitor, 0, len(d.monitors))
+	for _, m := range d.monitors {
+		ms = append(ms, m)
+	}
+	sort.Slice(ms, func(i, j int) bool { return ms[i].seq < ms[j].seq })
+	infos := make([]ipc.MonitorInfo, 0, len(ms))
+	for _, m := range ms {
+		infos = append(infos, m.info)
+	}
+	return infos
+}
+
+// runMonitor checks m every interval until it is removed or the daemon stops.
+func (d *Daemon) runMonitor(m *monitor) {
+	ticker := time.NewTicker(time.Duration(m.info.Every) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		start := time.Now()
+		if shot, ran, err := d.monitorCheck(m.info, start); ran {
+			res := ipc.MonitorResult{
+				Time:       start.UnixMilli(),
+				OK:         err == nil,
+				DurationMs: time.Since(start).Milliseconds(),
+				Screenshot: shot,
+			}
+			if err != nil {
+				res.Error = err.Error()
+			}
+
+			select {
+			case <-m.stop:
+				return // removed during the check
+			default:
+			}
+			if event := d.recordMonitorResult(m, res); event != "" {
+				go d.sendMonitorWebhook(m.info, event, res)
+			}
+		}
+
+		select {
+		case <-m.stop:
+			return
+		case <-d.shutdown:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordMonitorResult adds a check's result to m and its results.jsonl, and
+// returns the webhook event it causes, if any: a failure after a pass (or as
+// the first check), or a pass after a failure.
+func (d *Daemon) recordMonitorResult(m *monitor, res ipc.MonitorResult) (event string) {
+	d.monitorsMu.Lock()
+	prev := m.info.Last
+	m.results = append(m.results, res)
+	if len(m.results) > monitorResultsKept {
+		m.results = append([]ipc.MonitorResult(nil), m.results[len(m.results)-monitorResultsKept:]...)
+	}
+	m.info.Checks++
+	if !res.OK {
+		m.info.Failures++
+	}
+	last := res
+	m.info.Last = &last
+	dir := m.info.Dir
+	d.monitorsMu.Unlock()
+
+	if line, err := json.Marshal(res); err == nil {
+		if f, err := os.OpenFile(filepath.Join(dir, "results.jsonl"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644); err == nil {
+			_, _ = f.Write(append(line, '\n'))
+			_ = f.Close()
+		}
+	}
+
+	switch {
+	case !res.OK && (prev == nil || prev.OK):
+		d.log.Warn("monitor failing", "id", m.info.ID, "error", res.Error)
+		return monitorEventFailure
+	case res.OK && prev != nil && !prev.OK:
+		d.log.Info("monitor recovered", "id", m.info.ID)
+		return monitorEventRecovery
+	}
+	return ""
+}
+
+// monitorCheck runs checkMonitor holding browserMu, so the browser cannot be
+// replaced mid-check. While a replacement is under way the check is skipped
+// (ran is false) and the next tick runs against the new browser.
+func (d *Daemon) monitorCheck(info ipc.MonitorInfo, start time.Time) (screenshot string, ran bool, err error) {
+	if !d.browserMu.TryRLock() {
+		d.log.Debug("monitor check skipped while the browser restarts", "id", info.ID)
+		return "", false, nil
+	}
+	defer d.browserMu.RUnlock()
+	screenshot, err = d.checkMonitor(info, start)
+	return screenshot, true, err
+}
+
+// checkMonitor loads the monitor's URL in a new background tab, checks its
+// assertions, and takes a screenshot if asked, closing the tab after. The
+// screenshot is taken whether or not the assertions hold, to show a failure.
+func (d *Daemon) checkMonitor(info ipc.MonitorInfo, start time.Time) (screenshot string, err error) {
+	if !d.browserConnected() {
+		return "", errors.New("browser not connected")
+	}
+	deadline := start.Add(time.Duration(info.Timeout) * time.Millisecond)
+
+	session, err := d.createTab("about:blank", "", true)
+	if err != nil {
+		return "", err
+	}
+	defer d.closeMonitorTab(session.ID)
+
+	checkErr := d.loadTab(session.ID, info.URL, deadline)
+	if checkErr == nil {
+		checkErr = d.monitorAssertions(session.ID, info.Assert, deadline)
+	}
+	if info.Screenshot {
+		path := filepath.Join(info.Dir, start.Format("06-01-02-150405")+".png")
+		if err := d.monitorScreenshot(session.ID, path); err != nil {
+			d.log.Warn("monitor screenshot failed", "id", info.ID, "error", err)
+		} else {
+			screenshot = path
+		}
+	}
+	return screenshot, checkErr
+}
+
+// loadTab navigates a background tab, such as a monitor check's or a pool
+// tab, to pageURL and waits for it to load.
+func (d *Daemon) loadTab(sessionID, pageURL string, deadline time.Time) error {
+	nav := d.navTracker.begin(sessionID)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	result, err := d.sendToSession(ctx, sessionID, "Page.navigate", map[string]any{"url": pageURL})
+	if err != nil {
+		d.navTracker.abort(sessionID, nav)
+		return fmt.Errorf("navigation failed: %v", err)
+	}
+	var navResp struct {
+		ErrorText string `json:"errorText"`
+	}
+	if err := json.Unmarshal(result, &navResp); err == nil && navResp.ErrorText != "" {
+		d.navTracker.abort(sessionID, nav)
+		return errors.New(navResp.ErrorText)
+	}
+	if resp := d.awaitNavigateUntil(nav, sessionID, ipc.WaitUntilLoad, "", time.Until(deadline)); !resp.OK {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// monitorAssertions checks each assertion in turn, retrying a failing one
+// until the deadline.
+func (d *Daemon) monitorAssertions(sessionID string, asserts []string, deadline time.Time) error {
+	for _, a := range asserts {
+		kind, value, err := ipc.ParseMonitorAssert(a)
+		if err != nil {
+			return err
+		}
+		args, err := json.Marshal([]string{kind, value})
+		if err != nil {
+			return err
+		}
+		js := monitorAssertJS + "(..." + string(args) + ")"
+		for {
+			var result struct {
+				Failure *string `json:"failure"`
+			}
+			if _, err := d.evalElementQuery(sessionID, js, &result); err != nil {
+				return fmt.Errorf("%s: %v", a, err)
+			}
+			if result.Failure == nil {
+				break
+			}
+			if time.Now().Add(monitorAssertPoll).After(deadline) {
+				return fmt.Errorf("%s: %s", a, *result.Failure)
+			}
+			time.Sleep(monitorAssertPoll)
+		}
+	}
+	return nil
+}
+
+// monitorScreenshot saves a screenshot of the check's tab at path.
+func (d *Daemon) monitorScreenshot(sessionID, path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	result, err := d.sendToSession(ctx, sessionID, "Page.captureScreenshot", map[string]any{"format": "png"})
+	if err != nil {
+		return err
+	}
+	var shot struct {
+		Data string `json:"data"`
+	}
+	if err := json.Unmarshal(result, &shot); err != nil {
+		return err
+	}
+	png, err := base64.StdEncoding.DecodeString(shot.Data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, png, 0644)
+}
+
+// closeMonitorTab closes a check's tab.
+func (d *Daemon) closeMonitorTab(sessionID string) {
+	targetID := d.sessions.TargetID(sessionID)
+	if targetID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := d.cdp.SendContext(ctx, "Target.closeTarget", map[string]any{"targetId": targetID}); err != nil {
+		d.log.Debug("failed to close monitor tab", "error", err)
+	}
+}
+
+// sendMonitorWebhook posts a failure or recovery event to the monitor's
+// webhook as JSON.
+func (d *Daemon) sendMonitorWebhook(info ipc.MonitorInfo, event string, res ipc.MonitorResult) {
+	if info.Webhook == "" {
+		return
+	}
+	body, err := json.Marshal(map[string]any{
+		"event":   event,
+		"monitor": info,
+		"result":  res,
+		"text":    monitorWebhookText(info, event, res),
+	})
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), monitorWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, info.Webhook, bytes.NewReader(body))
+	if err != nil {
+		d.log.Warn("monitor webhook failed", "id", info.ID, "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		d.log.Warn("monitor webhook failed", "id", info.ID, "error", err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		d.log.Warn("monitor webhook failed", "id", info.ID, "status", resp.StatusCode)
+	}
+}
+
+// monitorWebhookText summarises an event in a line, for chat webhooks that
+// display a "text" field.
+func monitorWebhookText(info ipc.MonitorInfo, event string, res ipc.MonitorResult) string {
+	if event == monitorEventRecovery {
+		return fmt.Sprintf("webctl monitor %s recovered: %s", info.ID, info.URL)
+	}
+	return fmt.Sprintf("webctl monitor %s failing: %s: %s", info.ID, info.URL, res.Error)
+}
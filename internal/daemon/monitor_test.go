@@ -0,0 +1,186 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func monitorRequest(t *testing.T, d *Daemon, params ipc.MonitorParams) (ipc.Response, ipc.MonitorData) {
+	t.Helper()
+	raw, _ := json.Marshal(params)
+	resp := d.handleMonitor(ipc.Request{Cmd: "monitor", Params: raw})
+	var data ipc.MonitorData
+	if resp.OK {
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp, data
+}
+
+func TestHandleMonitor_AddValidation(t *testing.T) {
+	monitorDir = t.TempDir()
+	d := New(DefaultConfig())
+	every := int64(time.Minute / time.Millisecond)
+
+	tests := []struct {
+		name   string
+		params ipc.MonitorParams
+		want   string
+	}{
+		{"no url", ipc.MonitorParams{Action: "add", Every: every}, "url is required"},
+		{"too often", ipc.MonitorParams{Action: "add", URL: "https://example.com", Every: 1000}, "--every must be at least"},
+		{"bad assert", ipc.MonitorParams{Action: "add", URL: "https://example.com", Every: every, Assert: []string{"xpath://a"}}, "invalid --assert"},
+		{"empty assert", ipc.MonitorParams{Action: "add", URL: "https://example.com", Every: every, Assert: []string{"selector:"}}, "requires a value"},
+		{"bad webhook", ipc.MonitorParams{Action: "add", URL: "https://example.com", Every: every, Webhook: "ftp://x"}, "invalid webhook URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := monitorRequest(t, d, tt.params)
+			if resp.OK || !strings.Contains(resp.Error, tt.want) {
+				t.Errorf("response = %+v, want error containing %q", resp, tt.want)
+			}
+		})
+	}
+	if len(d.monitors) != 0 {
+		t.Errorf("invalid monitors were added: %d", len(d.monitors))
+	}
+}
+
+func TestHandleMonitor_Lifecycle(t *testing.T) {
+	monitorDir = t.TempDir()
+	d := New(DefaultConfig())
+
+	resp, data := monitorRequest(t, d, ipc.MonitorParams{
+		Action: "add",
+		URL:    "https://example.com",
+		Every:  int64(time.Hour / time.Millisecond),
+		Assert: []string{"selector:#app"},
+	})
+	if !resp.OK {
+		t.Fatalf("add failed: %s", resp.Error)
+	}
+	id := data.Monitors[0].ID
+	if id != "m1" || data.Monitors[0].Timeout != monitorDefaultTimeout.Milliseconds() {
+		t.Errorf("added = %+v", data.Monitors[0])
+	}
+
+	// The first check runs at once and fails without a browser
+	var results ipc.MonitorData
+	for i := 0; i < 100; i++ {
+		_, results = monitorRequest(t, d, ipc.MonitorParams{Action: "results", ID: id})
+		if len(results.Results) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(results.Results) != 1 || results.Results[0].OK || results.Results[0].Error != "browser not connected" {
+		t.Fatalf("results = %+v", results.Results)
+	}
+
+	_, list := monitorRequest(t, d, ipc.MonitorParams{Action: "list"})
+	if len(list.Monitors) != 1 || list.Monitors[0].Checks != 1 || list.Monitors[0].Failures != 1 {
+		t.Errorf("list = %+v", list.Monitors)
+	}
+
+	if resp, _ := monitorRequest(t, d, ipc.MonitorParams{Action: "remove", ID: id}); !resp.OK {
+		t.Fatalf("remove failed: %s", resp.Error)
+	}
+	if resp, _ := monitorRequest(t, d, ipc.MonitorParams{Action: "remove", ID: id}); resp.OK {
+		t.Error("expected removing twice to fail")
+	}
+	if resp, _ := monitorRequest(t, d, ipc.MonitorParams{Action: "results", ID: id}); resp.OK {
+		t.Error("expected results of a removed monitor to fail")
+	}
+}
+
+func TestRecordMonitorResult_Events(t *testing.T) {
+	d := New(DefaultConfig())
+	m := &monitor{info: ipc.MonitorInfo{ID: "m1", Dir: t.TempDir()}}
+
+	steps := []struct {
+		ok   bool
+		want string
+	}{
+		{true, ""},
+		{false, monitorEventFailure},
+		{false, ""},
+		{true, monitorEventRecovery},
+		{true, ""},
+	}
+	for i, s := range steps {
+		if got := d.recordMonitorResult(m, ipc.MonitorResult{OK: s.ok}); got != s.want {
+			t.Errorf("check %d: event = %q, want %q", i+1, got, s.want)
+		}
+	}
+	if m.info.Checks != 5 || m.info.Failures != 2 || len(m.results) != 5 {
+		t.Errorf("info = %+v, results = %d", m.info, len(m.results))
+	}
+
+	first := &monitor{info: ipc.MonitorInfo{ID: "m2", Dir: t.TempDir()}}
+	if got := d.recordMonitorResult(first, ipc.MonitorResult{}); got != monitorEventFailure {
+		t.Errorf("failing first check: event = %q, want failure", got)
+	}
+}
+
+func TestSendMonitorWebhook(t *testing.T) {
+	bodies := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies <- r.Header.Get("Content-Type") + " " + string(b)
+	}))
+	defer srv.Close()
+
+	d := New(DefaultConfig())
+	info := ipc.MonitorInfo{ID: "m1", URL: "https://example.com", Webhook: srv.URL}
+	d.sendMonitorWebhook(info, monitorEventFailure, ipc.MonitorResult{Error: "no element matches #app"})
+
+	got := <-bodies
+	for _, want := range []string{"application/json", `"event":"failure"`, `"id":"m1"`, "failing: https://example.com: no element matches #app"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("webhook body missing %q: %s", want, got)
+		}
+	}
+}
+
+// TestMonitorCheck_WhileReplacingBrowser runs checks while the browser
+// connection is swapped as replaceBrowser does. Run with -race.
+func TestMonitorCheck_WhileReplacingBrowser(t *testing.T) {
+	d := New(DefaultConfig())
+	client := cdp.NewClient(newSessionCapturingMockConn())
+	defer func() { _ = client.Close() }()
+	info := ipc.MonitorInfo{ID: "m1", URL: "https://example.test/", Timeout: 1000}
+
+	d.browserMu.Lock()
+	if _, ran, _ := d.monitorCheck(info, time.Now()); ran {
+		t.Error("check ran while the browser was being replaced")
+	}
+	d.browserMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			_, _, _ = d.monitorCheck(info, time.Now())
+		}
+	}()
+	for range 100 {
+		d.browserMu.Lock()
+		d.cdp = client
+		d.browser = nil
+		d.browserMu.Unlock()
+	}
+	<-done
+
+	if _, ran, err := d.monitorCheck(info, time.Now()); !ran || err == nil {
+		t.Errorf("check ran=%v err=%v, want it to run and report no browser", ran, err)
+	}
+}
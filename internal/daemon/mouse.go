@@ -0,0 +1,177 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// mouseMaxSteps caps 'mouse move --steps', one CDP round trip each.
+const mouseMaxSteps = 1000
+
+// mouseButtons are the buttons 'mouse down' and 'mouse up' accept, in the
+// order of their bits in CDP's buttons mask.
+var mouseButtons = []string{"left", "right", "middle"}
+
+// mouseState is where the pointer is in a tab and which buttons it holds.
+// CDP mouse events carry absolute coordinates and the button state, so the
+// daemon remembers both between commands.
+type mouseState struct {
+	x, y    int
+	buttons int // CDP buttons mask: left 1, right 2, middle 4
+}
+
+// data reports the state as the response to a mouse command.
+func (m mouseState) data() ipc.MouseData {
+	held := []string{}
+	for i, name := range mouseButtons {
+		if m.buttons&(1<<i) != 0 {
+			held = append(held, name)
+		}
+	}
+	return ipc.MouseData{X: m.x, Y: m.y, Buttons: held}
+}
+
+// button names the first held button, which CDP expects on mouseMoved while
+// dragging.
+func (m mouseState) button() string {
+	for i, name := range mouseButtons {
+		if m.buttons&(1<<i) != 0 {
+			return name
+		}
+	}
+	return "none"
+}
+
+// mouseButtonBit returns the buttons mask bit for a button name.
+func mouseButtonBit(name string) (int, bool) {
+	for i, b := range mouseButtons {
+		if b == name {
+			return 1 << i, true
+		}
+	}
+	return 0, false
+}
+
+// handleMouse dispatches low-level mouse input to the active tab, for
+// gestures the selector-based commands cannot express.
+func (d *Daemon) handleMouse(req ipc.Request) ipc.Response {
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.MouseParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid mouse parameters: %v", err))
+	}
+
+	d.miceMu.Lock()
+	state := d.mice[activeID]
+	d.miceMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	dispatch := func(event map[string]any) error {
+		_, err := d.sendToSession(ctx, activeID, "Input.dispatchMouseEvent", event)
+		return err
+	}
+
+	switch params.Action {
+	case "position":
+		return ipc.SuccessResponse(state.data())
+
+	case "move":
+		steps := params.Steps
+		if steps == 0 {
+			steps = 1
+		}
+		if steps < 1 || steps > mouseMaxSteps {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid steps %d: must be 1 to %d", params.Steps, mouseMaxSteps))
+		}
+		fromX, fromY := state.x, state.y
+		for i := 1; i <= steps; i++ {
+			x := fromX + (params.X-fromX)*i/steps
+			y := fromY + (params.Y-fromY)*i/steps
+			err := dispatch(map[string]any{
+				"type":    "mouseMoved",
+				"x":       x,
+				"y":       y,
+				"button":  state.button(),
+				"buttons": state.buttons,
+			})
+			if err != nil {
+				return ipc.ErrorResponse(fmt.Sprintf("failed to move mouse: %v", err))
+			}
+			// Keep the position reached, so a failed move resumes from there
+			state.x, state.y = x, y
+			d.setMouse(activeID, state)
+		}
+
+	case "down", "up":
+		button := params.Button
+		if button == "" {
+			button = "left"
+		}
+		bit, ok := mouseButtonBit(button)
+		if !ok {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid button %q: must be left, middle, or right", button))
+		}
+		eventType, buttons := "mousePressed", state.buttons|bit
+		if params.Action == "up" {
+			eventType, buttons = "mouseReleased", state.buttons&^bit
+		}
+		err := dispatch(map[string]any{
+			"type":       eventType,
+			"x":          state.x,
+			"y":          state.y,
+			"button":     button,
+			"buttons":    buttons,
+			"clickCount": 1,
+		})
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to send mouse %s: %v", params.Action, err))
+		}
+		state.buttons = buttons
+		d.setMouse(activeID, state)
+
+	case "wheel":
+		if params.DeltaX == 0 && params.DeltaY == 0 {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "invalid wheel delta: dx and dy are both 0")
+		}
+		err := dispatch(map[string]any{
+			"type":    "mouseWheel",
+			"x":       state.x,
+			"y":       state.y,
+			"deltaX":  params.DeltaX,
+			"deltaY":  params.DeltaY,
+			"buttons": state.buttons,
+		})
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to send mouse wheel: %v", err))
+		}
+
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown mouse action: %s", params.Action))
+	}
+
+	return ipc.SuccessResponse(state.data())
+}
+
+// setMouse records a tab's pointer state.
+func (d *Daemon) setMouse(sessionID string, state mouseState) {
+	d.miceMu.Lock()
+	defer d.miceMu.Unlock()
+	if d.mice == nil {
+		d.mice = make(map[string]mouseState)
+	}
+	d.mice[sessionID] = state
+}
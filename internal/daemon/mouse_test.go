@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMouseState(t *testing.T) {
+	left, _ := mouseButtonBit("left")
+	middle, _ := mouseButtonBit("middle")
+	if _, ok := mouseButtonBit("back"); ok {
+		t.Error("expected an unknown button to be rejected")
+	}
+
+	state := mouseState{x: 10, y: 20}
+	if got := state.button(); got != "none" {
+		t.Errorf("button() with nothing held = %q, want none", got)
+	}
+	if data := state.data(); data.Buttons == nil || len(data.Buttons) != 0 {
+		t.Errorf("data().Buttons = %#v, want empty", data.Buttons)
+	}
+
+	state.buttons = left | middle
+	if got := state.button(); got != "left" {
+		t.Errorf("button() = %q, want left", got)
+	}
+	data := state.data()
+	if data.X != 10 || data.Y != 20 || strings.Join(data.Buttons, ",") != "left,middle" {
+		t.Errorf("data() = %+v", data)
+	}
+}
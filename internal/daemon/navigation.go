@@ -65,6 +65,14 @@ func (n *Navigation) FrameNavigated() <-chan struct{} { return n.frameNav }
 // superseded or its session detaches. CancelReason is readable once it closes.
 func (n *Navigation) Cancelled() <-chan struct{} { return n.cancelled }
 
+// pending reports whether the navigation is still waiting for its page to
+// load: neither loaded nor cancelled.
+func (n *Navigation) pending() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return !n.loadedClosed && !n.cancelledClosed
+}
+
 // markDOMReady closes the DOM-ready milestone. Idempotent.
 func (n *Navigation) markDOMReady() {
 	n.mu.Lock()
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
 )
 
 // isClosed reports whether a milestone channel has been closed without blocking.
@@ -72,6 +73,24 @@ func TestNavigation_FrameNavigatedCloses(t *testing.T) {
 	}
 }
 
+func TestNavigation_Pending(t *testing.T) {
+	n := newNavigation()
+	n.markDOMReady()
+	if !n.pending() {
+		t.Error("pending = false before load")
+	}
+	n.markLoaded()
+	if n.pending() {
+		t.Error("pending = true after load")
+	}
+
+	c := newNavigation()
+	c.cancel(cancelSuperseded)
+	if c.pending() {
+		t.Error("pending = true after cancel")
+	}
+}
+
 func TestAwaitMilestone_ReachedBeforeAwaitReturnsPromptly(t *testing.T) {
 	n := newNavigation()
 	n.markLoaded() // milestone reached before anyone awaits it
@@ -423,3 +442,43 @@ func TestReadyDefaultMode_ReturnsAtDOMReadyBeforeLoad(t *testing.T) {
 		t.Fatal("ready default mode did not return at DOM-ready (no loadEventFired was delivered)")
 	}
 }
+
+// --until domcontentloaded is satisfied by DOM-ready alone; the default load
+// strategy is not.
+func TestAwaitNavigateUntil_DOMContentLoaded(t *testing.T) {
+	d := New(DefaultConfig())
+	nav := d.navTracker.begin("s1")
+	nav.markDOMReady()
+
+	if resp := d.awaitNavigateUntil(nav, "s1", ipc.WaitUntilDOMContentLoaded, "", time.Second); !resp.OK {
+		t.Errorf("expected success after DOM-ready, got %q", resp.Error)
+	}
+
+	resp := d.awaitNavigateUntil(nav, "s1", ipc.WaitUntilLoad, "", 50*time.Millisecond)
+	if resp.OK || resp.Error != "timeout waiting for page load" {
+		t.Errorf("expected load timeout, got %+v", resp)
+	}
+}
+
+// Selector and js strategies must not poll before the new document commits,
+// so a navigation that never commits times out on the commit milestone.
+func TestAwaitNavigateUntil_SelectorWaitsForCommit(t *testing.T) {
+	d := New(DefaultConfig())
+	nav := d.navTracker.begin("s1")
+
+	resp := d.awaitNavigateUntil(nav, "s1", ipc.WaitUntilSelector, "#app", 50*time.Millisecond)
+	if resp.OK || resp.Error != "timeout waiting for navigation to commit" {
+		t.Errorf("expected commit timeout, got %+v", resp)
+	}
+}
+
+func TestAwaitNavigateUntil_Superseded(t *testing.T) {
+	d := New(DefaultConfig())
+	nav := d.navTracker.begin("s1")
+	d.navTracker.begin("s1")
+
+	resp := d.awaitNavigateUntil(nav, "s1", ipc.WaitUntilNetworkIdle, "", time.Second)
+	if resp.OK || resp.Error != errNavigationSuperseded {
+		t.Errorf("expected superseded error, got %+v", resp)
+	}
+}
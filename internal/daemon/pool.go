@@ -0,0 +1,264 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// MaxPoolSize caps the tabs in a pool; each is a full renderer.
+const MaxPoolSize = 16
+
+// poolMapTimeout is how long a "pool map" URL gets to load and run its
+// command when the request does not say.
+const poolMapTimeout = 30 * time.Second
+
+// tabPool is the set of background tabs 'webctl pool' loads URLs in.
+type tabPool struct {
+	tabs []string    // session IDs, in creation order
+	free chan string // session IDs not running a map
+}
+
+// handlePool handles the "pool" command: it creates, reports on, runs a
+// command in, or closes the tab pool.
+func (d *Daemon) handlePool(req ipc.Request) ipc.Response {
+	var params ipc.PoolParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid pool parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "status", "":
+		return ipc.SuccessResponse(d.poolStatus())
+	case "create":
+		if ok, resp := d.requireBrowser(); !ok {
+			return resp
+		}
+		return d.createPool(params.Size)
+	case "map":
+		if ok, resp := d.requireBrowser(); !ok {
+			return resp
+		}
+		return d.poolMap(params)
+	case "close":
+		return ipc.SuccessResponse(d.closePool())
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown pool action: %s", params.Action))
+	}
+}
+
+// poolStatus returns the pool's size and busy tabs; zero when there is none.
+func (d *Daemon) poolStatus() ipc.PoolData {
+	d.poolMu.Lock()
+	defer d.poolMu.Unlock()
+	if d.pool == nil {
+		return ipc.PoolData{}
+	}
+	return ipc.PoolData{Size: len(d.pool.tabs), Busy: len(d.pool.tabs) - len(d.pool.free)}
+}
+
+// createPool opens size background tabs as the pool. If any tab fails to
+// open, those already opened are closed again.
+func (d *Daemon) createPool(size int) ipc.Response {
+	if size < 1 || size > MaxPoolSize {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("pool size %d out of range (1 to %d)", size, MaxPoolSize))
+	}
+
+	d.poolMu.Lock()
+	defer d.poolMu.Unlock()
+	if d.pool != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("a pool of %d tabs already exists; close it first with: webctl pool close", len(d.pool.tabs)))
+	}
+
+	pool := &tabPool{free: make(chan string, size)}
+	for range size {
+		session, err := d.createTab("about:blank", "", true)
+		if err != nil {
+			for _, id := range pool.tabs {
+				d.closeMonitorTab(id)
+			}
+			return ipc.ErrorResponse(fmt.Sprintf("failed to create pool: %v", err))
+		}
+		pool.tabs = append(pool.tabs, session.ID)
+		pool.free <- session.ID
+	}
+	d.pool = pool
+	d.log.Info("pool created", "size", size)
+	return ipc.SuccessResponse(ipc.PoolData{Size: size})
+}
+
+// closePool closes every pool tab, including any running a map, and returns
+// the pool's status before it closed.
+func (d *Daemon) closePool() ipc.PoolData {
+	d.poolMu.Lock()
+	pool := d.pool
+	d.pool = nil
+	d.poolMu.Unlock()
+	if pool == nil {
+		return ipc.PoolData{}
+	}
+
+	for _, id := range pool.tabs {
+		d.closeMonitorTab(id)
+	}
+	d.log.Info("pool closed", "size", len(pool.tabs))
+	return ipc.PoolData{Size: len(pool.tabs), Busy: len(pool.tabs) - len(pool.free)}
+}
+
+// poolMap loads params.URL in a free pool tab, waiting for one if all are
+// busy, and runs the command on it. A failed load or command is reported in
+// the result, not as an error response, so the caller can carry on with
+// other URLs.
+func (d *Daemon) poolMap(params ipc.PoolParams) ipc.Response {
+	if params.URL == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "url is required")
+	}
+	expr, err := poolExpression(params.Command, params.Arg)
+	if err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, err.Error())
+	}
+	timeout := poolMapTimeout
+	if params.Timeout > 0 {
+		timeout = time.Duration(params.Timeout) * time.Millisecond
+	}
+
+	d.poolMu.Lock()
+	pool := d.pool
+	d.poolMu.Unlock()
+	if pool == nil {
+		return ipc.CodedErrorResponse(ipc.CodeNotFound, "no pool; create one with: webctl pool create")
+	}
+
+	var sessionID string
+	select {
+	case sessionID = <-pool.free:
+	case <-time.After(timeout):
+		return ipc.ErrorResponse(fmt.Sprintf("no pool tab free within %s", timeout))
+	}
+	defer d.releasePoolTab(pool, sessionID)
+
+	start := time.Now()
+	res := ipc.PoolMapResult{URL: params.URL}
+	deadline := start.Add(timeout)
+	err = d.loadTab(sessionID, params.URL, deadline)
+	if err == nil {
+		res.Value, err = d.poolEval(sessionID, expr, deadline)
+	}
+	if err == nil && string(res.Value) == "null" && (params.Command == ipc.PoolCommandText || params.Command == ipc.PoolCommandHTML) {
+		res.Value = nil
+		err = fmt.Errorf("no element matches %q", poolSelector(params.Command, params.Arg))
+	}
+	res.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		res.Error = err.Error()
+	} else {
+		res.OK = true
+	}
+	return ipc.SuccessResponse(res)
+}
+
+// releasePoolTab returns a tab to the pool after a map. A tab closed in the
+// meantime leaves the pool, and a tab of a pool since closed is dropped.
+func (d *Daemon) releasePoolTab(pool *tabPool, sessionID string) {
+	d.poolMu.Lock()
+	defer d.poolMu.Unlock()
+	if d.pool != pool {
+		return
+	}
+	if d.sessions.Get(sessionID) == nil {
+		for i, id := range pool.tabs {
+			if id == sessionID {
+				pool.tabs = append(pool.tabs[:i], pool.tabs[i+1:]...)
+				break
+			}
+		}
+		d.log.Debug("pool tab closed during map", "session", sessionID)
+		return
+	}
+	pool.free <- sessionID
+}
+
+// poolSelector returns the selector a text or html command reads, with the
+// default applied.
+func poolSelector(command, arg string) string {
+	if arg != "" {
+		return arg
+	}
+	if command == ipc.PoolCommandHTML {
+		return "html"
+	}
+	return "body"
+}
+
+// poolExpression returns the JavaScript that runs a pool map command.
+func poolExpression(command, arg string) (string, error) {
+	sel, err := json.Marshal(poolSelector(command, arg))
+	if err != nil {
+		return "", err
+	}
+	switch command {
+	case ipc.PoolCommandText:
+		return fmt.Sprintf(`(() => { const el = document.querySelector(%s); return el ? el.innerText : null; })()`, sel), nil
+	case ipc.PoolCommandHTML:
+		return fmt.Sprintf(`(() => { const el = document.querySelector(%s); return el ? el.outerHTML : null; })()`, sel), nil
+	case ipc.PoolCommandTitle:
+		return "document.title", nil
+	case ipc.PoolCommandCount:
+		if arg == "" {
+			return "", errors.New("count requires a selector")
+		}
+		return fmt.Sprintf(`document.querySelectorAll(%s).length`, sel), nil
+	case ipc.PoolCommandEval:
+		if arg == "" {
+			return "", errors.New("eval requires an expression")
+		}
+		return arg, nil
+	}
+	return "", fmt.Errorf("unknown pool command: %q", command)
+}
+
+// poolEval evaluates expr in a pool tab, awaiting a promise, and returns the
+// value as JSON; undefined is null.
+func (d *Daemon) poolEval(sessionID, expr string, deadline time.Time) (json.RawMessage, error) {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	result, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+		"expression":    expr,
+		"returnByValue": true,
+		"awaitPromise":  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var evalResp struct {
+		Result struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"result"`
+		ExceptionDetails *struct {
+			Text      string `json:"text"`
+			Exception *struct {
+				Description string `json:"description"`
+			} `json:"exception"`
+		} `json:"exceptionDetails"`
+	}
+	if err := json.Unmarshal(result, &evalResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if evalResp.ExceptionDetails != nil {
+		msg := evalResp.ExceptionDetails.Text
+		if evalResp.ExceptionDetails.Exception != nil && evalResp.ExceptionDetails.Exception.Description != "" {
+			msg = evalResp.ExceptionDetails.Exception.Description
+		}
+		return nil, fmt.Errorf("JavaScript error: %s", msg)
+	}
+	if len(evalResp.Result.Value) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	return evalResp.Result.Value, nil
+}
@@ -0,0 +1,97 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestPool_Validation(t *testing.T) {
+	d := New(DefaultConfig())
+
+	for _, size := range []int{0, -1, MaxPoolSize + 1} {
+		if resp := d.createPool(size); resp.OK || resp.Code != ipc.CodeInvalidArgs {
+			t.Errorf("createPool(%d) = %+v, want %s", size, resp, ipc.CodeInvalidArgs)
+		}
+	}
+
+	if resp := d.poolMap(ipc.PoolParams{Action: "map", URL: "https://one.test/", Command: "title"}); resp.OK || resp.Code != ipc.CodeNotFound {
+		t.Errorf("map without a pool = %+v, want %s", resp, ipc.CodeNotFound)
+	}
+	for _, params := range []ipc.PoolParams{
+		{Action: "map", Command: "title"},
+		{Action: "map", URL: "https://one.test/", Command: "links"},
+		{Action: "map", URL: "https://one.test/", Command: "count"},
+	} {
+		if resp := d.poolMap(params); resp.OK || resp.Code != ipc.CodeInvalidArgs {
+			t.Errorf("%+v: response = %+v, want %s", params, resp, ipc.CodeInvalidArgs)
+		}
+	}
+	if status := d.poolStatus(); status.Size != 0 {
+		t.Errorf("poolStatus() = %+v, want no pool", status)
+	}
+}
+
+func TestPool_ReleaseAndClose(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "about:blank", "")
+	d.sessions.Add("BBB2", "T2", "about:blank", "")
+
+	pool := &tabPool{tabs: []string{"AAA1", "BBB2"}, free: make(chan string, 2)}
+	d.pool = pool
+	if status := d.poolStatus(); status.Size != 2 || status.Busy != 2 {
+		t.Errorf("poolStatus() = %+v, want 2 busy", status)
+	}
+
+	// A tab closed during a map leaves the pool
+	d.sessions.Remove("BBB2")
+	d.releasePoolTab(pool, "AAA1")
+	d.releasePoolTab(pool, "BBB2")
+	if status := d.poolStatus(); status.Size != 1 || status.Busy != 0 {
+		t.Errorf("poolStatus() = %+v, want 1 free", status)
+	}
+
+	status := d.closePool()
+	if status.Size != 1 || d.pool != nil {
+		t.Errorf("closePool() = %+v, pool = %v", status, d.pool)
+	}
+	reqs := conn.getCapturedRequests()
+	if len(reqs) != 1 || reqs[0].Method != "Target.closeTarget" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+
+	// Tabs of a closed pool are not returned to it
+	d.releasePoolTab(pool, "AAA1")
+	if len(pool.free) != 1 {
+		t.Errorf("free tabs = %d after release to a closed pool", len(pool.free))
+	}
+}
+
+func TestPoolExpression(t *testing.T) {
+	tests := []struct {
+		command, arg, want string
+	}{
+		{"text", "", `document.querySelector("body")`},
+		{"text", "#main", `document.querySelector("#main")`},
+		{"html", "", `document.querySelector("html")`},
+		{"title", "", "document.title"},
+		{"count", "a[href]", `document.querySelectorAll("a[href]").length`},
+		{"eval", "location.href", "location.href"},
+	}
+	for _, tt := range tests {
+		js, err := poolExpression(tt.command, tt.arg)
+		if err != nil {
+			t.Errorf("poolExpression(%q, %q): %v", tt.command, tt.arg, err)
+			continue
+		}
+		if !strings.Contains(js, tt.want) {
+			t.Errorf("poolExpression(%q, %q) = %s, want %s", tt.command, tt.arg, js, tt.want)
+		}
+	}
+}
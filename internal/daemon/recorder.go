@@ -0,0 +1,296 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// recordBinding is the page binding the recorder script reports
+// interactions through (Runtime.addBinding).
+const recordBinding = "__webctlRecord"
+
+// maxRecordedSteps caps a recording, so one left running cannot grow without
+// bound.
+const maxRecordedSteps = 10000
+
+// recordJS installs listeners that report the user's clicks, committed input
+// values, and Enter presses to recordBinding, with a selector for each
+// element built by describeElementJS. It is added as a new-document script,
+// so it follows the tab across navigations, and is idempotent per document.
+//
+// Clicks are reported on the nearest interactive ancestor. Clicks that only
+// focus a text field are left out, since the fill that follows covers them.
+// An input's value is reported when it changes (on blur) or just before Enter
+// is reported, whichever comes first. Password values are never reported.
+const recordJS = `(() => {
+	if (window.__webctlRecording) {
+		return;
+	}
+	window.__webctlRecording = true;
+
+	const describe = ` + describeElementJS + `;
+	const send = (step) => {
+		try {
+			window.` + recordBinding + `(JSON.stringify(step));
+		} catch (e) {
+			// Binding removed: the recording has stopped.
+		}
+	};
+	const selector = (el) => describe.call(el).selector;
+	const textLike = (el) => el instanceof HTMLTextAreaElement ||
+		(el instanceof HTMLInputElement && !['checkbox', 'radio', 'file', 'submit', 'button', 'reset', 'image', 'range', 'color'].includes(el.type));
+	const recorded = new WeakMap();
+	const fill = (el) => {
+		if (!textLike(el) || recorded.get(el) === el.value) {
+			return;
+		}
+		recorded.set(el, el.value);
+		const secret = el.type === 'password';
+		send({action: 'fill', selector: selector(el), text: secret ? '' : el.value, secret});
+	};
+
+	document.addEventListener('click', (ev) => {
+		if (!ev.isTrusted || !(ev.target instanceof Element)) {
+			return;
+		}
+		const el = ev.target.closest('a, button, input, textarea, select, label, summary, [role=button], [role=link], [role=tab], [role=menuitem], [onclick]') || ev.target;
+		if (textLike(el) || el instanceof HTMLSelectElement) {
+			return;
+		}
+		send({action: 'click', selector: selector(el)});
+	}, true);
+
+	document.addEventListener('change', (ev) => {
+		if (ev.isTrusted && ev.target instanceof Element) {
+			fill(ev.target);
+		}
+	}, true);
+
+	document.addEventListener('keydown', (ev) => {
+		if (!ev.isTrusted || ev.key !== 'Enter' || !(ev.target instanceof Element) || !textLike(ev.target) || ev.target instanceof HTMLTextAreaElement) {
+			return;
+		}
+		fill(ev.target);
+		send({action: 'key', key: 'Enter'});
+	}, true);
+})()`
+
+// recording is an active 'record-flow' session.
+type recording struct {
+	sessionID string
+	scriptID  string // Page.addScriptToEvaluateOnNewDocument identifier
+	steps     []ipc.RecordedStep
+}
+
+// recordedTransitions are the navigation transition types a user starts by
+// entering a URL or choosing a bookmark, as opposed to following a link or
+// submitting a form, which the recorded click or key already replays.
+var recordedTransitions = map[string]bool{
+	"typed":             true,
+	"address_bar":       true,
+	"auto_bookmark":     true,
+	"generated":         true,
+	"keyword":           true,
+	"keyword_generated": true,
+}
+
+// handleRecord starts, stops, or reports on recording the user's interactions
+// in the active tab.
+func (d *Daemon) handleRecord(req ipc.Request) ipc.Response {
+	var params ipc.RecordParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid record parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "start":
+		return d.startRecording()
+	case "stop":
+		return d.stopRecording()
+	case "status", "":
+		d.recordMu.Lock()
+		defer d.recordMu.Unlock()
+		data := ipc.RecordData{Steps: []ipc.RecordedStep{}}
+		if d.recording != nil {
+			data.Recording = true
+			data.SessionID = d.recording.sessionID
+			data.Steps = append(data.Steps, d.recording.steps...)
+		}
+		return ipc.SuccessResponse(data)
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown record action: %s", params.Action))
+	}
+}
+
+// startRecording installs the recorder in the active tab, beginning the
+// recording with a navigation to its current URL.
+func (d *Daemon) startRecording() ipc.Response {
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+	active := d.sessions.Active()
+	if active == nil {
+		return d.noActiveSessionError()
+	}
+
+	d.recordMu.Lock()
+	defer d.recordMu.Unlock()
+	if d.recording != nil {
+		return ipc.ErrorResponse("already recording - stop it with: webctl record-flow stop <path>")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := d.sendToSession(ctx, active.ID, "Runtime.addBinding", map[string]any{"name": recordBinding}); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to start recording: %v", err))
+	}
+	result, err := d.sendToSession(ctx, active.ID, "Page.addScriptToEvaluateOnNewDocument", map[string]any{
+		"source":         recordJS,
+		"runImmediately": true,
+	})
+	if err != nil {
+		_, _ = d.sendToSession(ctx, active.ID, "Runtime.removeBinding", map[string]any{"name": recordBinding})
+		return ipc.ErrorResponse(fmt.Sprintf("failed to start recording: %v", err))
+	}
+	var script struct {
+		Identifier string `json:"identifier"`
+	}
+	_ = json.Unmarshal(result, &script)
+
+	// runImmediately needs a recent Chrome; the script guards against running
+	// twice, so also evaluate it in the current document
+	_, _ = d.sendToSession(ctx, active.ID, "Runtime.evaluate", map[string]any{"expression": recordJS})
+
+	d.recording = &recording{sessionID: active.ID, scriptID: script.Identifier}
+	if active.URL != "" && active.URL != "about:blank" {
+		d.recording.steps = append(d.recording.steps, ipc.RecordedStep{
+			Time:   time.Now().UnixMilli(),
+			Action: "navigate",
+			URL:    active.URL,
+		})
+	}
+	d.log.Info("recording started", "session", active.ID)
+	return ipc.SuccessResponse(ipc.RecordData{Recording: true, SessionID: active.ID, Steps: d.recording.steps})
+}
+
+// stopRecording removes the recorder and returns the recorded steps.
+func (d *Daemon) stopRecording() ipc.Response {
+	d.recordMu.Lock()
+	rec := d.recording
+	d.recording = nil
+	d.recordMu.Unlock()
+	if rec == nil {
+		return ipc.ErrorResponse("not recording - start with: webctl record-flow start")
+	}
+
+	// The tab may have closed; its steps are still returned
+	if d.sessions.Get(rec.sessionID) != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if rec.scriptID != "" {
+			_, _ = d.sendToSession(ctx, rec.sessionID, "Page.removeScriptToEvaluateOnNewDocument", map[string]any{"identifier": rec.scriptID})
+		}
+		_, _ = d.sendToSession(ctx, rec.sessionID, "Runtime.removeBinding", map[string]any{"name": recordBinding})
+	}
+
+	d.log.Info("recording stopped", "session", rec.sessionID, "steps", len(rec.steps))
+	steps := rec.steps
+	if steps == nil {
+		steps = []ipc.RecordedStep{}
+	}
+	return ipc.SuccessResponse(ipc.RecordData{SessionID: rec.sessionID, Steps: steps})
+}
+
+// handleRecordBinding processes a Runtime.bindingCalled event, adding the
+// interaction it reports to the recording of its tab.
+func (d *Daemon) handleRecordBinding(sessionID, name, payload string) {
+	if name != recordBinding {
+		return
+	}
+	var step ipc.RecordedStep
+	if err := json.Unmarshal([]byte(payload), &step); err != nil {
+		return
+	}
+	switch step.Action {
+	case "click", "fill":
+		if step.Selector == "" {
+			return
+		}
+	case "key":
+		if step.Key == "" {
+			return
+		}
+	default:
+		return
+	}
+	step.URL = ""
+	step.Time = time.Now().UnixMilli()
+	d.addRecordedStep(sessionID, step)
+}
+
+// recordNavigation adds a main-frame navigation of the recorded tab when the
+// user started it from the address bar or a bookmark.
+func (d *Daemon) recordNavigation(sessionID, url, transitionType string) {
+	if !recordedTransitions[transitionType] {
+		return
+	}
+	d.addRecordedStep(sessionID, ipc.RecordedStep{
+		Time:   time.Now().UnixMilli(),
+		Action: "navigate",
+		URL:    url,
+	})
+}
+
+// addRecordedStep appends a step to the recording if sessionID is the tab
+// being recorded. A navigation to the URL just navigated to is dropped.
+func (d *Daemon) addRecordedStep(sessionID string, step ipc.RecordedStep) {
+	d.recordMu.Lock()
+	defer d.recordMu.Unlock()
+	rec := d.recording
+	if rec == nil || rec.sessionID != sessionID || len(rec.steps) >= maxRecordedSteps {
+		return
+	}
+	if n := len(rec.steps); step.Action == "navigate" && n > 0 && rec.steps[n-1].Action == "navigate" && rec.steps[n-1].URL == step.URL {
+		return
+	}
+	rec.steps = append(rec.steps, step)
+}
+
+// recordingSession returns the tab being recorded, or "" when not recording.
+func (d *Daemon) recordingSession() string {
+	d.recordMu.Lock()
+	defer d.recordMu.Unlock()
+	if d.recording == nil {
+		return ""
+	}
+	return d.recording.sessionID
+}
+
+// checkRecordedNavigation looks up how the recorded tab's latest navigation
+// was started, off the CDP read loop, and records it if the user typed it.
+func (d *Daemon) checkRecordedNavigation(sessionID, url string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		result, err := d.sendToSession(ctx, sessionID, "Page.getNavigationHistory", nil)
+		if err != nil {
+			return
+		}
+		var history struct {
+			CurrentIndex int `json:"currentIndex"`
+			Entries      []struct {
+				URL            string `json:"url"`
+				TransitionType string `json:"transitionType"`
+			} `json:"entries"`
+		}
+		if err := json.Unmarshal(result, &history); err != nil || history.CurrentIndex < 0 || history.CurrentIndex >= len(history.Entries) {
+			return
+		}
+		d.recordNavigation(sessionID, url, history.Entries[history.CurrentIndex].TransitionType)
+	}()
+}
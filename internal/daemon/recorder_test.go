@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestHandleRecordBinding(t *testing.T) {
+	d := New(DefaultConfig())
+	d.recording = &recording{sessionID: "sess1"}
+
+	d.handleRecordBinding("sess1", recordBinding, `{"action":"click","selector":"#go"}`)
+	d.handleRecordBinding("sess1", recordBinding, `{"action":"fill","selector":"#pw","secret":true}`)
+	d.handleRecordBinding("sess1", recordBinding, `{"action":"key","key":"Enter"}`)
+	// Ignored: other session, other binding, malformed, unknown, incomplete
+	d.handleRecordBinding("sess2", recordBinding, `{"action":"click","selector":"#other"}`)
+	d.handleRecordBinding("sess1", "someBinding", `{"action":"click","selector":"#x"}`)
+	d.handleRecordBinding("sess1", recordBinding, `not json`)
+	d.handleRecordBinding("sess1", recordBinding, `{"action":"navigate","url":"https://evil.test"}`)
+	d.handleRecordBinding("sess1", recordBinding, `{"action":"click"}`)
+
+	steps := d.recording.steps
+	if len(steps) != 3 {
+		t.Fatalf("got %d steps, want 3: %+v", len(steps), steps)
+	}
+	if steps[0].Action != "click" || steps[0].Selector != "#go" {
+		t.Errorf("step 1 = %+v", steps[0])
+	}
+	if steps[1].Action != "fill" || !steps[1].Secret {
+		t.Errorf("step 2 = %+v", steps[1])
+	}
+	if steps[2].Action != "key" || steps[2].Key != "Enter" {
+		t.Errorf("step 3 = %+v", steps[2])
+	}
+	if steps[0].Time == 0 {
+		t.Error("expected step time to be set")
+	}
+}
+
+func TestHandleRecordBinding_NotRecording(t *testing.T) {
+	d := New(DefaultConfig())
+	// Must not panic when no recording is active.
+	d.handleRecordBinding("sess1", recordBinding, `{"action":"click","selector":"#go"}`)
+}
+
+func TestRecordNavigation(t *testing.T) {
+	d := New(DefaultConfig())
+	d.recording = &recording{sessionID: "sess1"}
+
+	d.recordNavigation("sess1", "https://example.com/", "typed")
+	d.recordNavigation("sess1", "https://example.com/", "reload")
+	d.recordNavigation("sess1", "https://example.com/", "address_bar") // duplicate
+	d.recordNavigation("sess1", "https://example.com/next", "link")
+	d.recordNavigation("sess1", "https://example.com/b", "auto_bookmark")
+
+	steps := d.recording.steps
+	if len(steps) != 2 {
+		t.Fatalf("got %d steps, want 2: %+v", len(steps), steps)
+	}
+	if steps[0].URL != "https://example.com/" || steps[1].URL != "https://example.com/b" {
+		t.Errorf("steps = %+v", steps)
+	}
+}
+
+func TestHandleRecord_StatusAndStop(t *testing.T) {
+	d := New(DefaultConfig())
+	req := func(action string) ipc.Response {
+		params, _ := json.Marshal(ipc.RecordParams{Action: action})
+		return d.handleRecord(ipc.Request{Cmd: "record", Params: params})
+	}
+
+	resp := req("status")
+	if !resp.OK {
+		t.Fatalf("status failed: %s", resp.Error)
+	}
+	var data ipc.RecordData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Recording {
+		t.Error("expected not recording")
+	}
+
+	if resp := req("stop"); resp.OK {
+		t.Error("expected stop without a recording to fail")
+	}
+
+	d.recording = &recording{sessionID: "gone", steps: []ipc.RecordedStep{{Action: "click", Selector: "#a"}}}
+	resp = req("stop")
+	if !resp.OK {
+		t.Fatalf("stop failed: %s", resp.Error)
+	}
+	data = ipc.RecordData{}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Steps) != 1 || data.Steps[0].Selector != "#a" {
+		t.Errorf("steps = %+v", data.Steps)
+	}
+	if d.recording != nil {
+		t.Error("expected recording to be cleared")
+	}
+
+	if resp := req("pause"); resp.OK {
+		t.Error("expected unknown action to fail")
+	}
+}
@@ -2,10 +2,15 @@ package daemon
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -26,6 +31,10 @@ type REPL struct {
 	sessionProv SessionProvider
 	readline    *readline.Instance
 	history     []string
+	historyFile string
+	commands    []string
+	jsMode      bool     // Lines are JavaScript for the page (see repl_jsmode.go)
+	jsBuf       []string // Lines of a JS input awaiting its closing brackets
 	shutdown    func()
 	closeOnce   sync.Once
 	closeErr    error
@@ -47,6 +56,27 @@ func (r *REPL) SetSessionProvider(sp SessionProvider) {
 	r.sessionProv = sp
 }
 
+// SetCommands sets the command names offered by tab completion. Without it
+// the REPL completes its built-in list.
+func (r *REPL) SetCommands(names []string) {
+	r.commands = names
+}
+
+// SetHistoryFile sets the file that keeps line history across REPL sessions.
+// An empty path keeps history in memory only.
+func (r *REPL) SetHistoryFile(path string) {
+	r.historyFile = path
+}
+
+// replHistoryLimit caps the lines kept in the history file.
+const replHistoryLimit = 1000
+
+// DefaultREPLHistoryPath returns the REPL history file in the webctl state
+// directory.
+func DefaultREPLHistoryPath() string {
+	return filepath.Join(filepath.Dir(getBodiesDir()), "repl_history")
+}
+
 // Close closes the readline instance if it exists.
 // Safe to call multiple times (idempotent).
 // Returns the error from the first close attempt on all subsequent calls.
@@ -68,9 +98,17 @@ func IsStdinTTY() bool {
 func (r *REPL) Run() error {
 	// Create readline instance with initial prompt
 	cfg := &readline.Config{
-		Prompt:          r.prompt(),
-		InterruptPrompt: "^C",
-		EOFPrompt:       "^D",
+		Prompt:            r.prompt(),
+		InterruptPrompt:   "^C",
+		EOFPrompt:         "^D",
+		AutoComplete:      &replCompleter{r: r},
+		HistoryLimit:      replHistoryLimit,
+		HistorySearchFold: true,
+	}
+	if r.historyFile != "" {
+		if err := os.MkdirAll(filepath.Dir(r.historyFile), 0700); err == nil {
+			cfg.HistoryFile = r.historyFile
+		}
 	}
 
 	rl, err := readline.NewEx(cfg)
@@ -102,6 +140,14 @@ func (r *REPL) Run() error {
 		r.readline.SetPrompt(r.prompt())
 
 		line, err := r.readline.Readline()
+		if r.jsMode && (err == readline.ErrInterrupt || err == io.EOF) {
+			// Ctrl-C drops the pending input, Ctrl-D leaves JS mode
+			if err == io.EOF || len(r.jsBuf) == 0 {
+				r.leaveJS()
+			}
+			r.jsBuf = nil
+			continue
+		}
 		if err != nil {
 			if err == readline.ErrInterrupt || err == io.EOF {
 				return nil
@@ -109,6 +155,11 @@ func (r *REPL) Run() error {
 			return err
 		}
 
+		if r.jsMode {
+			r.handleJSLine(line)
+			continue
+		}
+
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
@@ -145,6 +196,10 @@ func cleanURLForDisplay(url string) string {
 
 // prompt generates the REPL prompt with session context.
 func (r *REPL) prompt() string {
+	if r.jsMode {
+		return r.jsPromptText()
+	}
+
 	// Check if color should be enabled
 	useColor := shouldUseREPLColor()
 
@@ -217,12 +272,12 @@ func coloredPrompt(url string, count int) string {
 }
 
 // replCommands lists REPL-specific commands for abbreviation matching.
-var replCommands = []string{"exit", "quit", "help", "history", "stop"}
+var replCommands = []string{"exit", "quit", "help", "history", "stop", "js"}
 
 // webctlCommands lists webctl commands for abbreviation matching.
 var webctlCommands = []string{
 	"back", "clear", "click", "console", "cookies", "eval", "find", "focus",
-	"forward", "html", "key", "markdown", "navigate", "network", "ready", "reload",
+	"forward", "html", "key", "markdown", "navigate", "network", "open", "ready", "reload",
 	"screenshot", "scroll", "select", "status", "target", "type",
 }
 
@@ -246,6 +301,15 @@ func expandAbbreviation(prefix string, commands []string) (string, bool) {
 // handleSpecialCommand handles REPL-specific commands.
 // Returns (handled, error). Returns io.EOF for clean exit commands.
 func (r *REPL) handleSpecialCommand(line string) (bool, error) {
+	switch {
+	case strings.HasPrefix(line, "!"):
+		r.runShell(strings.TrimSpace(line[1:]))
+		return true, nil
+	case strings.HasPrefix(line, ":"):
+		r.switchTab(strings.TrimSpace(line[1:]))
+		return true, nil
+	}
+
 	parts := strings.Fields(line)
 	if len(parts) == 0 {
 		return false, nil
@@ -267,7 +331,16 @@ func (r *REPL) handleSpecialCommand(line string) (bool, error) {
 		r.printHelp()
 		return true, nil
 
+	case "js":
+		r.enterJS()
+		return true, nil
+
 	case "history":
+		// With arguments (history go 2, history --limit 5) this is the page
+		// navigation history command, not the REPL's command history.
+		if len(parts) > 1 {
+			return false, nil
+		}
 		r.printHistory()
 		return true, nil
 	}
@@ -275,6 +348,81 @@ func (r *REPL) handleSpecialCommand(line string) (bool, error) {
 	return false, nil
 }
 
+// runShell runs a shell command with the terminal, for "!cmd".
+func (r *REPL) runShell(command string) {
+	if command == "" {
+		outputError("usage: !<shell command>")
+		return
+	}
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		shell := os.Getenv("SHELL")
+		if shell == "" {
+			shell = "/bin/sh"
+		}
+		cmd = exec.Command(shell, "-c", command)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			outputError(err.Error())
+		}
+	}
+}
+
+// switchTab handles ":" (list tabs by number) and ":N" (make tab N active).
+// Tabs are numbered from 1 in the order they were opened.
+func (r *REPL) switchTab(arg string) {
+	params, err := json.Marshal(ipc.TabParams{Action: "list"})
+	if err != nil {
+		outputError(err.Error())
+		return
+	}
+	resp := r.handler(ipc.Request{Cmd: "tab", Params: params})
+	if !resp.OK {
+		outputError(resp.Error)
+		return
+	}
+	var data ipc.TabData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		outputError(err.Error())
+		return
+	}
+
+	if arg == "" {
+		for i, s := range data.Sessions {
+			marker := " "
+			if s.ID == data.ActiveSession {
+				marker = "*"
+			}
+			fmt.Printf("%s %d  %s - %s\n", marker, i+1, s.URL, strings.TrimSpace(s.Title))
+		}
+		return
+	}
+
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(data.Sessions) {
+		outputError(fmt.Sprintf("no tab %s: use 1 to %d (':' lists them)", arg, len(data.Sessions)))
+		return
+	}
+	id := data.Sessions[n-1].ID
+	if r.cmdExec != nil {
+		if _, err := r.cmdExec([]string{"tab", "switch", id}); err != nil && !strings.Contains(err.Error(), "daemon") {
+			outputError(err.Error())
+		}
+		return
+	}
+	params, err = json.Marshal(ipc.TabParams{Action: "switch", Query: id})
+	if err != nil {
+		outputError(err.Error())
+		return
+	}
+	r.outputResponse(r.handler(ipc.Request{Cmd: "tab", Params: params}))
+}
+
 // executeCommand parses and executes a webctl command.
 func (r *REPL) executeCommand(line string) {
 	args := parseArgs(line)
@@ -391,9 +539,12 @@ func (r *REPL) printHelp() {
 Commands (unique prefixes accepted: h=html, k=key, ba=back, na=navigate, ne=network, cli=click, foc=focus):
   Navigation:
     navigate <url>      Navigate to URL
+    open <name>         Navigate to a bookmark
     reload              Reload current page
     back                Go back in history
     forward             Go forward in history
+    history go <n>      Jump to page history entry n
+    history --limit <n> List the last n page history entries
 
   Interaction:
     click <selector>    Click element
@@ -421,11 +572,20 @@ Commands (unique prefixes accepted: h=html, k=key, ba=back, na=navigate, ne=netw
     target [query]      List sessions or switch to a session
     clear [target]      Clear event buffers (console, network, or all)
     ready               Wait for page load
+    bookmark add <name> [url]  Name a URL, or the current page's, for open
+    bookmark list       List bookmarks
 
 REPL (unique prefixes accepted: he=help, hi=history, e=exit, q=quit):
   help, ?     Show this help
-  history     Show command history
+  history     Show command history (with arguments: page history)
+  :           List tabs by number
+  :<n>        Switch to tab n
+  !<command>  Run a shell command
+  js          JavaScript mode: lines run in the page until .exit or Ctrl-D
   exit, quit  Stop daemon and exit
+
+Tab completes commands, and #id and .class selectors after commands that take
+one. Up and Ctrl-R recall lines from earlier sessions too.
 `
 	fmt.Println(help)
 }
@@ -485,6 +645,31 @@ func formatCommandSummary(req ipc.Request) string {
 		if json.Unmarshal(req.Params, &params) == nil && params.Selector != "" {
 			return "focus " + params.Selector
 		}
+	case "highlight":
+		var params ipc.HighlightParams
+		if json.Unmarshal(req.Params, &params) == nil && params.Selector != "" {
+			return "highlight " + params.Selector
+		}
+	case "box":
+		var params ipc.BoxParams
+		if json.Unmarshal(req.Params, &params) == nil && params.Selector != "" {
+			return "box " + params.Selector
+		}
+	case "attr":
+		var params ipc.AttrParams
+		if json.Unmarshal(req.Params, &params) == nil && params.Selector != "" {
+			return "attr " + params.Action + " " + params.Selector
+		}
+	case "count":
+		var params ipc.CountParams
+		if json.Unmarshal(req.Params, &params) == nil && params.Selector != "" {
+			return "count " + params.Selector
+		}
+	case "watch-dom":
+		var params ipc.WatchDOMParams
+		if json.Unmarshal(req.Params, &params) == nil && params.Selector != "" {
+			return "watch-dom " + params.Selector
+		}
 	case "type":
 		var params ipc.TypeParams
 		if json.Unmarshal(req.Params, &params) == nil && params.Selector != "" {
@@ -495,6 +680,14 @@ func formatCommandSummary(req ipc.Request) string {
 		if json.Unmarshal(req.Params, &params) == nil && params.Selector != "" {
 			return "select " + params.Selector
 		}
+	case "check":
+		var params ipc.CheckParams
+		if json.Unmarshal(req.Params, &params) == nil && params.Selector != "" {
+			if params.Checked {
+				return "check " + params.Selector
+			}
+			return "uncheck " + params.Selector
+		}
 	case "scroll":
 		var params ipc.ScrollParams
 		if json.Unmarshal(req.Params, &params) == nil {
@@ -517,6 +710,12 @@ func formatCommandSummary(req ipc.Request) string {
 			if params.Action == "get" && params.Selector != "" {
 				return "css get " + params.Selector
 			}
+			if params.Action == "diff" && params.Selector != "" {
+				return "styles diff " + params.Selector
+			}
+			if params.Action == "dump" && params.Sheet != "" {
+				return "css dump " + params.Sheet
+			}
 		}
 	case "cookies":
 		var params ipc.CookiesParams
@@ -0,0 +1,118 @@
+package daemon
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// selectorCommands are the commands whose arguments are CSS selectors, which
+// the REPL completes from the ids and classes on the page.
+var selectorCommands = map[string]bool{
+	"attr": true, "box": true, "check": true, "click": true, "count": true, "css": true,
+	"focus": true, "highlight": true, "html": true, "markdown": true,
+	"screenshot": true, "scroll": true, "select": true, "styles": true,
+	"type": true, "uncheck": true, "watch-dom": true,
+}
+
+// pageSelectorsJS lists up to 2000 "#id" and ".class" selectors on the page,
+// in document order, escaped so they can be used as typed.
+const pageSelectorsJS = `(() => {
+  const seen = new Set();
+  for (const el of document.querySelectorAll('[id], [class]')) {
+    if (el.id) seen.add('#' + CSS.escape(el.id));
+    for (const c of el.classList) seen.add('.' + CSS.escape(c));
+    if (seen.size >= 2000) break;
+  }
+  return [...seen];
+})()`
+
+// replCompleter completes the REPL line: command names in the first word, and
+// "#id" and ".class" selectors in the arguments of selector commands. JS mode
+// and shell escapes are not completed.
+type replCompleter struct {
+	r *REPL
+}
+
+// Do implements readline.AutoCompleter. It returns the remainder of each
+// candidate that starts with the word before the cursor, and that word's
+// length.
+func (c *replCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	head := string(line[:pos])
+	if c.r.jsMode || strings.HasPrefix(strings.TrimSpace(head), "!") {
+		return nil, 0
+	}
+
+	words := strings.Fields(head)
+	word := ""
+	if len(words) > 0 && !strings.HasSuffix(head, " ") && !strings.HasSuffix(head, "\t") {
+		word = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	if len(words) > 0 && strings.ToLower(words[0]) == "webctl" {
+		words = words[1:]
+	}
+
+	switch {
+	case len(words) == 0:
+		return completions(c.r.completionCommands(), word, " "), len([]rune(word))
+	case selectorCommands[strings.ToLower(words[0])] && (strings.HasPrefix(word, "#") || strings.HasPrefix(word, ".")):
+		return completions(c.r.pageSelectors(), word, ""), len([]rune(word))
+	}
+	return nil, 0
+}
+
+// completions returns the remainder of each candidate starting with prefix,
+// followed by suffix.
+func completions(candidates []string, prefix, suffix string) [][]rune {
+	var out [][]rune
+	for _, cand := range candidates {
+		if strings.HasPrefix(cand, prefix) {
+			out = append(out, []rune(cand[len(prefix):]+suffix))
+		}
+	}
+	return out
+}
+
+// completionCommands returns the sorted command names to complete: the CLI's
+// commands when known, else the built-in list, plus the REPL's own.
+func (r *REPL) completionCommands() []string {
+	names := r.commands
+	if len(names) == 0 {
+		names = webctlCommands
+	}
+	seen := make(map[string]bool)
+	var all []string
+	for _, list := range [][]string{names, replCommands} {
+		for _, name := range list {
+			if !seen[name] {
+				seen[name] = true
+				all = append(all, name)
+			}
+		}
+	}
+	sort.Strings(all)
+	return all
+}
+
+// pageSelectors returns the "#id" and ".class" selectors on the active page,
+// or nil if the page cannot be evaluated.
+func (r *REPL) pageSelectors() []string {
+	params, err := json.Marshal(ipc.EvalParams{Expression: pageSelectorsJS, Timeout: 2})
+	if err != nil {
+		return nil
+	}
+	resp := r.handler(ipc.Request{Cmd: "eval", Params: params})
+	if !resp.OK {
+		return nil
+	}
+	var data struct {
+		Value []string `json:"value"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil
+	}
+	return data.Value
+}
@@ -0,0 +1,137 @@
+package daemon
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// completed runs the completer on line with the cursor at its end and returns
+// each completed word in full.
+func completed(t *testing.T, c *replCompleter, line string) []string {
+	t.Helper()
+	suffixes, n := c.Do([]rune(line), len([]rune(line)))
+	prefix := string([]rune(line)[len([]rune(line))-n:])
+	var words []string
+	for _, s := range suffixes {
+		words = append(words, prefix+string(s))
+	}
+	sort.Strings(words)
+	return words
+}
+
+func TestReplCompleter(t *testing.T) {
+	evals := 0
+	r := NewREPL(func(req ipc.Request) ipc.Response {
+		if req.Cmd != "eval" {
+			t.Errorf("unexpected %q request", req.Cmd)
+			return ipc.ErrorResponse("unexpected")
+		}
+		evals++
+		return ipc.SuccessResponse(ipc.EvalData{
+			Value:    []string{"#login", "#logout", ".btn", ".btn-primary", ".card"},
+			HasValue: true,
+		})
+	}, nil, func() {})
+	r.SetCommands([]string{"click", "console", "cookies", "navigate"})
+	c := &replCompleter{r: r}
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"co", []string{"console ", "cookies "}},
+		{"webctl cl", []string{"click "}},
+		{"ex", []string{"exit "}},
+		{"click #log", []string{"#login", "#logout"}},
+		{"click .btn", []string{".btn", ".btn-primary"}},
+		{"click button", nil},
+		{"navigate #top", nil},
+		{"!ec", nil},
+	}
+	for _, tt := range tests {
+		got := completed(t, c, tt.line)
+		if len(got) != len(tt.want) {
+			t.Errorf("%q completes to %q, want %q", tt.line, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%q completes to %q, want %q", tt.line, got, tt.want)
+				break
+			}
+		}
+	}
+	if evals != 2 {
+		t.Errorf("page selectors fetched %d times, want 2", evals)
+	}
+}
+
+func TestReplCompleter_BuiltInCommands(t *testing.T) {
+	r := NewREPL(nil, nil, func() {})
+	got := completed(t, &replCompleter{r: r}, "scr")
+	if len(got) != 2 || got[0] != "screenshot " || got[1] != "scroll " {
+		t.Errorf("completions = %q, want screenshot and scroll", got)
+	}
+}
+
+func TestREPL_switchTab(t *testing.T) {
+	var switched string
+	r := NewREPL(func(req ipc.Request) ipc.Response {
+		var params ipc.TabParams
+		_ = json.Unmarshal(req.Params, &params)
+		if params.Action == "switch" {
+			switched = params.Query
+			return ipc.SuccessResponse(nil)
+		}
+		return ipc.SuccessResponse(ipc.TabData{
+			ActiveSession: "AAAA",
+			Sessions: []ipc.PageSession{
+				{ID: "AAAA", URL: "https://a.example"},
+				{ID: "BBBB", URL: "https://b.example"},
+			},
+		})
+	}, nil, func() {})
+
+	if handled, err := r.handleSpecialCommand(":2"); !handled || err != nil {
+		t.Fatalf("handleSpecialCommand(:2) = %v, %v", handled, err)
+	}
+	if switched != "BBBB" {
+		t.Errorf(":2 switched to %q, want BBBB", switched)
+	}
+
+	switched = ""
+	r.switchTab("3")
+	if switched != "" {
+		t.Errorf(":3 of two tabs switched to %q", switched)
+	}
+}
+
+func TestREPL_switchTab_UsesCommandExecutor(t *testing.T) {
+	var args []string
+	r := NewREPL(func(req ipc.Request) ipc.Response {
+		return ipc.SuccessResponse(ipc.TabData{Sessions: []ipc.PageSession{{ID: "AAAA"}}})
+	}, func(a []string) (bool, error) {
+		args = a
+		return true, nil
+	}, func() {})
+
+	r.switchTab("1")
+	if len(args) != 3 || args[0] != "tab" || args[1] != "switch" || args[2] != "AAAA" {
+		t.Errorf("command executor got %q, want tab switch AAAA", args)
+	}
+}
+
+func TestREPL_shellEscape(t *testing.T) {
+	t.Setenv("SHELL", "/bin/sh")
+	r := NewREPL(nil, func([]string) (bool, error) {
+		t.Error("a shell escape should not run a webctl command")
+		return true, nil
+	}, func() {})
+
+	if handled, err := r.handleSpecialCommand("!true"); !handled || err != nil {
+		t.Errorf("handleSpecialCommand(!true) = %v, %v", handled, err)
+	}
+}
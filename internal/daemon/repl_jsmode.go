@@ -0,0 +1,177 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// JS mode prompts: the first line of an input, and its continuation lines.
+const (
+	jsPrompt         = "js> "
+	jsContinuePrompt = "... "
+)
+
+// jsExit leaves JS mode, as in Node's REPL. Ctrl-D does too.
+const jsExit = ".exit"
+
+// enterJS switches the REPL to JS mode, where each input is evaluated in the
+// page as it is typed.
+func (r *REPL) enterJS() {
+	r.jsMode = true
+	r.jsBuf = nil
+	fmt.Println("JavaScript mode: input runs in the page; let and const persist. " + jsExit + " or Ctrl-D to leave.")
+}
+
+// leaveJS returns the REPL to webctl commands, dropping any pending input.
+func (r *REPL) leaveJS() {
+	r.jsMode = false
+	r.jsBuf = nil
+}
+
+// jsPromptText returns the JS mode prompt for the next line.
+func (r *REPL) jsPromptText() string {
+	if len(r.jsBuf) > 0 {
+		return jsContinuePrompt
+	}
+	return jsPrompt
+}
+
+// handleJSLine adds a line to the pending JS input and evaluates the input
+// once its brackets, strings, and comments are closed.
+func (r *REPL) handleJSLine(line string) {
+	if len(r.jsBuf) == 0 {
+		switch strings.TrimSpace(line) {
+		case "":
+			return
+		case jsExit:
+			r.leaveJS()
+			return
+		}
+	}
+
+	r.jsBuf = append(r.jsBuf, line)
+	src := strings.Join(r.jsBuf, "\n")
+	if jsIncomplete(src) {
+		return
+	}
+	r.jsBuf = nil
+	r.history = append(r.history, src)
+	r.evalJS(src, os.Stdout)
+}
+
+// evalJS evaluates src in the page in REPL mode, so top-level let, const, and
+// class declarations persist between inputs and may be redeclared, and writes
+// the result to w.
+func (r *REPL) evalJS(src string, w io.Writer) {
+	params, err := json.Marshal(ipc.EvalParams{Expression: src, REPLMode: true})
+	if err != nil {
+		outputError(err.Error())
+		return
+	}
+	resp := r.handler(ipc.Request{Cmd: "eval", Params: params})
+	if !resp.OK {
+		outputError(resp.Error)
+		return
+	}
+	var data ipc.EvalData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		outputError(err.Error())
+		return
+	}
+	printJSValue(w, data)
+}
+
+// printJSValue writes an eval result as 'webctl eval' does: strings bare,
+// objects and arrays as compact JSON.
+func printJSValue(w io.Writer, data ipc.EvalData) {
+	if !data.HasValue {
+		_, _ = fmt.Fprintln(w, "undefined")
+		return
+	}
+	switch v := data.Value.(type) {
+	case nil:
+		_, _ = fmt.Fprintln(w, "null")
+	case string:
+		_, _ = fmt.Fprintln(w, v)
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			outputError(err.Error())
+			return
+		}
+		_, _ = fmt.Fprintln(w, string(b))
+	default:
+		_, _ = fmt.Fprintf(w, "%v\n", v)
+	}
+}
+
+// jsIncomplete reports whether src ends inside an open bracket, template
+// literal, or block comment, so more lines are needed. Unbalanced closers and
+// unterminated quotes are left for the engine to report.
+func jsIncomplete(src string) bool {
+	// open holds the closers expected, innermost last. '`' means inside a
+	// template literal's text; a '}' pushed by "${" returns to it.
+	var open []rune
+	runes := []rune(src)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		if len(open) > 0 && open[len(open)-1] == '`' {
+			switch {
+			case c == '\\':
+				i++
+			case c == '`':
+				open = open[:len(open)-1]
+			case c == '$' && next == '{':
+				open = append(open, '}')
+				i++
+			}
+			continue
+		}
+
+		switch {
+		case c == '/' && next == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case c == '/' && next == '*':
+			j := i + 2
+			for j+1 < len(runes) && (runes[j] != '*' || runes[j+1] != '/') {
+				j++
+			}
+			if j+1 >= len(runes) {
+				return true
+			}
+			i = j + 1
+		case c == '\'' || c == '"':
+			for i++; i < len(runes) && runes[i] != c && runes[i] != '\n'; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+		case c == '`':
+			open = append(open, '`')
+		case c == '(':
+			open = append(open, ')')
+		case c == '[':
+			open = append(open, ']')
+		case c == '{':
+			open = append(open, '}')
+		case c == ')' || c == ']' || c == '}':
+			if len(open) == 0 || open[len(open)-1] != c {
+				return false
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	return len(open) > 0
+}
@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestJSIncomplete(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"1 + 1", false},
+		{"function f() {", true},
+		{"function f() {\n  return 1\n}", false},
+		{"[1, 2,", true},
+		{"foo(", true},
+		{"'{'", false},
+		{`"a \" {"`, false},
+		{"`multi", true},
+		{"`a ${b", true},
+		{"`a ${ {x: 1}.x } b`", false},
+		{"`a ${`inner`} b`", false},
+		{"x // {", false},
+		{"/* {", true},
+		{"/* { */ 1", false},
+		{"f())", false},
+		{"'unterminated", false},
+	}
+	for _, tt := range tests {
+		if got := jsIncomplete(tt.src); got != tt.want {
+			t.Errorf("jsIncomplete(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestREPL_JSMode(t *testing.T) {
+	var evaluated []ipc.EvalParams
+	r := NewREPL(func(req ipc.Request) ipc.Response {
+		var params ipc.EvalParams
+		_ = json.Unmarshal(req.Params, &params)
+		evaluated = append(evaluated, params)
+		return ipc.SuccessResponse(ipc.EvalData{Value: 3.0, HasValue: true})
+	}, nil, func() {})
+
+	if handled, err := r.handleSpecialCommand("js"); !handled || err != nil {
+		t.Fatalf("handleSpecialCommand(js) = %v, %v", handled, err)
+	}
+	if !r.jsMode || r.prompt() != jsPrompt {
+		t.Fatalf("expected JS mode with prompt %q, got %q", jsPrompt, r.prompt())
+	}
+
+	r.handleJSLine("const add = (a, b) => {")
+	if len(evaluated) != 0 || r.prompt() != jsContinuePrompt {
+		t.Fatalf("an open brace should wait for more input (prompt %q)", r.prompt())
+	}
+	r.handleJSLine("  return a + b")
+	r.handleJSLine("}")
+	if len(evaluated) != 1 {
+		t.Fatalf("expected one evaluation, got %d", len(evaluated))
+	}
+	if got := evaluated[0]; !got.REPLMode || got.Expression != "const add = (a, b) => {\n  return a + b\n}" {
+		t.Errorf("evaluated %+v, want the joined lines in REPL mode", got)
+	}
+
+	r.handleJSLine("")
+	if len(evaluated) != 1 {
+		t.Error("an empty line should not be evaluated")
+	}
+
+	r.handleJSLine(jsExit)
+	if r.jsMode {
+		t.Error(jsExit + " should leave JS mode")
+	}
+}
+
+func TestPrintJSValue(t *testing.T) {
+	tests := []struct {
+		data ipc.EvalData
+		want string
+	}{
+		{ipc.EvalData{}, "undefined\n"},
+		{ipc.EvalData{HasValue: true}, "null\n"},
+		{ipc.EvalData{Value: "hi", HasValue: true}, "hi\n"},
+		{ipc.EvalData{Value: []any{1.0, "a"}, HasValue: true}, "[1,\"a\"]\n"},
+		{ipc.EvalData{Value: true, HasValue: true}, "true\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		printJSValue(&buf, tt.data)
+		if buf.String() != tt.want {
+			t.Errorf("printJSValue(%+v) = %q, want %q", tt.data, buf.String(), tt.want)
+		}
+	}
+}
@@ -23,6 +23,8 @@ func TestREPL_handleSpecialCommand(t *testing.T) {
 		{"help", "help", true, false},
 		{"question mark", "?", true, false},
 		{"history", "history", true, false},
+		{"page history go", "history go 2", false, false},
+		{"page history limit", "history --limit 5", false, false},
 		{"regular command", "status", false, false},
 		{"clear command", "clear console", false, false},
 	}
@@ -248,6 +250,7 @@ func TestExpandAbbreviation(t *testing.T) {
 		{"h -> html", "h", webctlCommands, "html", true},
 		{"k -> key", "k", webctlCommands, "key", true},
 		{"m -> markdown", "m", webctlCommands, "markdown", true},
+		{"o -> open", "o", webctlCommands, "open", true},
 
 		// markdown prefixes; "md" is the cobra alias, not a name prefix, so the
 		// REPL leaves it unexpanded and cobra Find resolves the alias.
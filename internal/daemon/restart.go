@@ -0,0 +1,452 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/grantcarthew/webctl/internal/browser"
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+const (
+	// browserRestartAttempts is how many relaunches are tried after the
+	// browser is lost before the daemon gives up and shuts down.
+	browserRestartAttempts = 3
+	// browserRestartDelay is the pause between failed relaunch attempts.
+	browserRestartDelay = time.Second
+)
+
+// errBrowserExited is reported when the browser process exits. It wraps the
+// process's exit error when the exit was not clean.
+var errBrowserExited = errors.New("browser process exited")
+
+// browserCrashed reports whether a disconnect is worth relaunching the browser
+// for. A normal websocket closure or a clean process exit means the browser
+// was closed on purpose, and the daemon shuts down as it always has.
+func browserCrashed(err error) bool {
+	switch websocket.CloseStatus(err) {
+	case websocket.StatusNormalClosure, websocket.StatusGoingAway:
+		return false
+	}
+	if errors.Is(err, errBrowserExited) {
+		var exitErr *exec.ExitError
+		return errors.As(err, &exitErr)
+	}
+	return true
+}
+
+// reportBrowserLost asks Run to relaunch the browser. Reports made while one
+// is already pending are dropped.
+func (d *Daemon) reportBrowserLost(err error) {
+	select {
+	case d.browserLost <- err:
+	default:
+	}
+}
+
+// watchBrowser starts the heartbeat and a process-exit watcher for the current
+// browser, both reporting to browserLost. The returned function stops them,
+// and must be called before the browser is replaced.
+func (d *Daemon) watchBrowser(parent context.Context) context.CancelFunc {
+	ctx, cancel := context.WithCancel(parent)
+	d.startHeartbeat(ctx, d.browserLost)
+
+	b := d.browser
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-b.Exited():
+			if ctx.Err() != nil {
+				return
+			}
+			err := errBrowserExited
+			if exitErr := b.ExitErr(); exitErr != nil {
+				err = fmt.Errorf("%w: %w", errBrowserExited, exitErr)
+			}
+			d.log.Warn("browser process exited", "pid", b.PID(), "error", b.ExitErr())
+			d.reportBrowserLost(err)
+		}
+	}()
+	return cancel
+}
+
+// launchBrowser starts the daemon's initial browser and connects to it.
+// Config.Adopt attaches to a browser already on the port and Config.Reuse
+// takes over the one recorded in the state file.
+func (d *Daemon) launchBrowser(ctx context.Context) error {
+	var b *browser.Browser
+	switch {
+	case d.config.Adopt:
+		port := d.config.Port
+		if port == 0 {
+			port = browser.DefaultPort
+		}
+		var err error
+		if b, err = browser.Adopt(ctx, port); err != nil {
+			return err
+		}
+		d.log.Info("adopting browser", "port", port)
+	case d.config.Reuse:
+		var err error
+		if b, err = d.reuseBrowser(ctx); err != nil {
+			if !errors.Is(err, browser.ErrNotReusable) {
+				return err
+			}
+			d.log.Info("launching a new browser", "reason", err)
+		}
+	}
+	if b == nil {
+		var err error
+		b, err = browser.Start(d.launchOptions(d.config.Headless))
+		if err != nil {
+			return fmt.Errorf("failed to start browser: %w", err)
+		}
+	}
+	return d.connectBrowser(ctx, b)
+}
+
+// launchOptions returns the options the daemon launches its browser with.
+func (d *Daemon) launchOptions(headless bool) browser.LaunchOptions {
+	extra := d.config.Launch.ChromeFlags
+	if d.config.Emulation.Stealth {
+		extra = append(slices.Clip(extra), stealthChromeFlag)
+	}
+	return browser.LaunchOptions{
+		Port:           d.config.Port,
+		Headless:       headless,
+		UserDataDir:    d.config.UserDataDir,
+		Lang:           d.config.Launch.Lang,
+		WindowSize:     d.config.Launch.WindowSize,
+		WindowPosition: d.config.Launch.WindowPosition,
+		Incognito:      d.config.Launch.Incognito,
+		Extensions:     d.config.Launch.Extensions,
+		ExtraFlags:     extra,
+		CI:             d.config.Launch.CI,
+		DevTools:       d.config.Launch.DevTools,
+	}
+}
+
+// connectBrowser connects CDP to b and makes it the daemon's browser,
+// subscribing to events and attaching to its targets. Emulation is applied by
+// each session as it attaches, so a relaunched browser gets the same
+// viewport, user agent, and throttling as the first. b is closed on failure.
+func (d *Daemon) connectBrowser(ctx context.Context, b *browser.Browser) error {
+	// Connect to browser-level CDP WebSocket (not page target)
+	// This allows us to use Target.setAutoAttach for session management
+	version, err := b.Version(ctx)
+	if err != nil {
+		_ = b.Close()
+		return fmt.Errorf("failed to get browser version: %w", err)
+	}
+	d.log.Info("browser started", "browser", version.Browser, "protocol", version.ProtocolVer, "port", b.Port())
+	d.log.Debug("connecting to CDP", "url", version.WebSocketURL)
+
+	cdpClient, err := cdp.Dial(ctx, version.WebSocketURL)
+	if err != nil {
+		_ = b.Close()
+		return fmt.Errorf("failed to connect to CDP: %w", err)
+	}
+	cdpClient.SetLogger(d.log.With("component", "cdp"))
+	cdpClient.SetObserver(d.metrics.observeCDP)
+	d.log.Debug("CDP client connected")
+	d.recordEvent("info", "cdp_connected", "CDP connected", "browser", version.Browser, "port", b.Port())
+
+	d.browser = b
+	d.cdp = cdpClient
+	// Keep the port actually bound (auto-selection may have picked another) so
+	// a relaunch reuses it.
+	d.config.Port = b.Port()
+
+	// Subscribe to events before enabling domains
+	d.log.Debug("subscribing to CDP events")
+	d.subscribeEvents()
+	d.log.Debug("CDP event subscriptions complete")
+
+	// Enable auto-attach for session tracking
+	d.log.Debug("enabling target discovery and attachment")
+	if err := d.enableAutoAttach(); err != nil {
+		return fmt.Errorf("failed to enable auto-attach: %w", err)
+	}
+	d.log.Debug("target discovery and attachment enabled")
+
+	d.writeStateFile(version.WebSocketURL)
+	return nil
+}
+
+// reuseBrowser takes over the browser recorded in the state file by an earlier
+// daemon. It returns an error wrapping browser.ErrNotReusable when there is no
+// such browser left to reuse.
+func (d *Daemon) reuseBrowser(ctx context.Context) (*browser.Browser, error) {
+	state, err := ipc.ReadState(d.config.StatePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", browser.ErrNotReusable, err)
+	}
+	if state.PID != os.Getpid() && processRunning(state.PID) {
+		return nil, fmt.Errorf("daemon (PID %d) that owns the browser is still running", state.PID)
+	}
+	if state.Adopted {
+		// Not ours to own: adopt it again, if it is still there
+		b, err := browser.Adopt(ctx, state.Port)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", browser.ErrNotReusable, err)
+		}
+		d.log.Info("adopting browser", "port", state.Port)
+		return b, nil
+	}
+	b, err := browser.Reuse(ctx, state.BrowserPID, state.Port, state.UserDataDir, state.TempProfile)
+	if err != nil {
+		return nil, err
+	}
+	// The browser keeps the mode and flags it was launched with
+	d.config.Headless = state.Headless
+	d.config.Launch = state.LaunchFlags
+	d.log.Info("reusing browser", "pid", state.BrowserPID, "port", state.Port, "profile", state.UserDataDir)
+	return b, nil
+}
+
+// processRunning reports whether a process with the given PID exists.
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	return err == nil && proc.Signal(syscall.Signal(0)) == nil
+}
+
+// replaceBrowser closes the browser and launches a new one on the same port
+// and profile, headless or not, then reopens the tabs it had. Cookies are
+// read from the old browser first and set in the new one, so session cookies
+// that Chrome does not write to the profile survive too; after a crash there
+// is nothing to read and only what the profile kept remains. Requests are
+// held off while the browser and CDP client are swapped.
+func (d *Daemon) replaceBrowser(ctx context.Context, headless bool) (tabs, cookies int, err error) {
+	saved := d.browserCookies(ctx)
+
+	// Close the old connection first so requests blocked on it fail now rather
+	// than at their timeouts, releasing browserMu.
+	_ = d.cdp.Close()
+	var urls []string
+	for _, s := range d.sessions.All() {
+		urls = append(urls, s.URL)
+		d.navTracker.clear(s.ID)
+	}
+
+	d.browserMu.Lock()
+	defer d.browserMu.Unlock()
+
+	// The overlay goes with the old browser
+	d.scheduleHideHighlight("", 0)
+
+	// Carry the profile over, including a temporary one, rather than letting
+	// Close delete it.
+	opts := d.launchOptions(headless)
+	if dir := d.browser.DataDir(); dir != "" {
+		opts.UserDataDir = dir
+		opts.TempProfile = d.browser.TempProfile()
+		d.browser.KeepProfile()
+	}
+	_ = d.browser.Close()
+
+	d.sessions.Clear()
+	d.styleSheetsMu.Lock()
+	d.styleSheets = nil
+	d.styleSheetsMu.Unlock()
+	d.contextFramesMu.Lock()
+	d.contextFrames = nil
+	d.contextFramesMu.Unlock()
+	d.miceMu.Lock()
+	d.mice = nil
+	d.miceMu.Unlock()
+	d.releaseHeld("", false)
+	d.browserContextsMu.Lock()
+	d.browserContexts = nil
+	d.browserContextsMu.Unlock()
+
+	d.config.Headless = headless
+	for attempt := 1; ; attempt++ {
+		b, err := browser.Start(opts)
+		if err == nil {
+			err = d.connectBrowser(ctx, b)
+		} else {
+			err = fmt.Errorf("failed to start browser: %w", err)
+		}
+		if err == nil {
+			break
+		}
+		d.log.Warn("browser relaunch failed", "attempt", attempt, "error", err)
+		d.recordEvent("warn", "browser_relaunch_failed", "browser relaunch failed", "attempt", attempt, "error", err)
+		if attempt == browserRestartAttempts {
+			return 0, 0, err
+		}
+		select {
+		case <-ctx.Done():
+			return 0, 0, ctx.Err()
+		case <-time.After(browserRestartDelay):
+		}
+	}
+
+	cookies = d.restoreCookies(ctx, saved)
+	return d.reopenTabs(ctx, urls), cookies, nil
+}
+
+// browserCookies returns every cookie in the browser, or nil if it cannot be
+// reached.
+func (d *Daemon) browserCookies(ctx context.Context) []ipc.Cookie {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	result, err := d.cdp.SendContext(ctx, "Storage.getCookies", nil)
+	if err != nil {
+		return nil
+	}
+	var data struct {
+		Cookies []ipc.Cookie `json:"cookies"`
+	}
+	if err := json.Unmarshal(result, &data); err != nil {
+		return nil
+	}
+	return data.Cookies
+}
+
+// restoreCookies sets cookies in the browser, returning how many were set.
+func (d *Daemon) restoreCookies(ctx context.Context, cookies []ipc.Cookie) int {
+	if len(cookies) == 0 {
+		return 0
+	}
+	params := make([]map[string]any, len(cookies))
+	for i, c := range cookies {
+		p := map[string]any{
+			"name":     c.Name,
+			"value":    c.Value,
+			"domain":   c.Domain,
+			"path":     c.Path,
+			"secure":   c.Secure,
+			"httpOnly": c.HTTPOnly,
+		}
+		if c.SameSite != "" {
+			p["sameSite"] = c.SameSite
+		}
+		if !c.Session && c.Expires > 0 {
+			p["expires"] = c.Expires
+		}
+		params[i] = p
+	}
+	if _, err := d.cdp.SendContext(ctx, "Storage.setCookies", map[string]any{"cookies": params}); err != nil {
+		d.log.Warn("failed to restore cookies", "count", len(cookies), "error", err)
+		return 0
+	}
+	return len(cookies)
+}
+
+// reopenTabs opens the given URLs in the relaunched browser, replacing the
+// blank tab it starts with, and returns how many it opened. Failures are
+// logged; the browser stays usable.
+func (d *Daemon) reopenTabs(ctx context.Context, urls []string) int {
+	var initial []string
+	if result, err := d.cdp.SendContext(ctx, "Target.getTargets", nil); err == nil {
+		var targets struct {
+			TargetInfos []struct {
+				TargetID string `json:"targetId"`
+				Type     string `json:"type"`
+			} `json:"targetInfos"`
+		}
+		if json.Unmarshal(result, &targets) == nil {
+			for _, t := range targets.TargetInfos {
+				if t.Type == "page" {
+					initial = append(initial, t.TargetID)
+				}
+			}
+		}
+	}
+
+	opened := 0
+	for _, url := range urls {
+		if url == "" || url == "about:blank" {
+			continue
+		}
+		if _, err := d.cdp.SendContext(ctx, "Target.createTarget", map[string]any{"url": url}); err != nil {
+			d.log.Warn("failed to reopen tab", "url", url, "error", err)
+			continue
+		}
+		opened++
+	}
+	if opened == 0 {
+		return 0
+	}
+	for _, id := range initial {
+		_, _ = d.cdp.SendContext(ctx, "Target.closeTarget", map[string]any{"targetId": id})
+	}
+	return opened
+}
+
+// modeSwitch is a request, from 'head' or 'headless', to relaunch the browser
+// in the other mode. Run answers it on reply.
+type modeSwitch struct {
+	headless bool
+	reply    chan ipc.Response
+}
+
+// handleBrowserMode relaunches the browser headless or headed, keeping its
+// profile, cookies, and open tabs. The relaunch happens in Run, which owns the
+// browser; this waits for the result.
+func (d *Daemon) handleBrowserMode(req ipc.Request) ipc.Response {
+	var params ipc.BrowserModeParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid browser-mode parameters: %v", err))
+		}
+	}
+
+	d.browserMu.RLock()
+	adopted := d.browser != nil && d.browser.Adopted()
+	current := d.config.Headless
+	d.browserMu.RUnlock()
+	if adopted {
+		return ipc.ErrorResponse("cannot switch the mode of an adopted browser - it was not launched by webctl")
+	}
+	if params.Headless == current {
+		return ipc.SuccessResponse(ipc.BrowserModeData{Headless: current})
+	}
+
+	sw := modeSwitch{headless: params.Headless, reply: make(chan ipc.Response, 1)}
+	select {
+	case d.modeSwitches <- sw:
+	case <-d.shutdown:
+		return ipc.ErrorResponse("daemon is shutting down")
+	}
+	return <-sw.reply
+}
+
+// switchBrowserMode carries out a mode switch for Run, answering sw. If the
+// new browser cannot be launched, the old mode is restored; it returns false
+// when that fails too and the daemon has no browser left.
+func (d *Daemon) switchBrowserMode(ctx context.Context, sw modeSwitch) bool {
+	from := d.config.Headless
+	d.log.Info("switching browser mode", "headless", sw.headless)
+	tabs, cookies, err := d.replaceBrowser(ctx, sw.headless)
+	if err == nil {
+		d.log.Info("browser mode switched", "headless", sw.headless, "pid", d.browser.PID(), "tabs", tabs, "cookies", cookies)
+		d.recordEvent("info", "browser_mode_switched", "browser mode switched", "headless", sw.headless, "tabs", tabs)
+		sw.reply <- ipc.SuccessResponse(ipc.BrowserModeData{Headless: sw.headless, Changed: true, Tabs: tabs, Cookies: cookies})
+		return true
+	}
+
+	d.log.Error("browser mode switch failed", "headless", sw.headless, "error", err)
+	d.recordEvent("error", "browser_mode_switch_failed", "browser mode switch failed", "headless", sw.headless, "error", err)
+	if _, _, rerr := d.replaceBrowser(ctx, from); rerr != nil {
+		d.log.Error("daemon stopping", "reason", "browser relaunch failed", "error", rerr)
+		sw.reply <- ipc.ErrorResponse(fmt.Sprintf("failed to relaunch browser: %v - daemon shutting down", err))
+		fmt.Fprintf(os.Stderr, "\nError: browser could not be relaunched - daemon shutting down\n")
+		return false
+	}
+	sw.reply <- ipc.ErrorResponse(fmt.Sprintf("failed to relaunch browser: %v", err))
+	return true
+}
@@ -0,0 +1,132 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/coder/websocket"
+	"github.com/grantcarthew/webctl/internal/browser"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestBrowserCrashed(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"normal closure", websocket.CloseError{Code: websocket.StatusNormalClosure}, false},
+		{"going away", websocket.CloseError{Code: websocket.StatusGoingAway}, false},
+		{"clean process exit", errBrowserExited, false},
+		{"process crash", fmt.Errorf("%w: %w", errBrowserExited, &exec.ExitError{}), true},
+		{"connection lost", errors.New("unexpected EOF"), true},
+		{"heartbeat timeout", context.DeadlineExceeded, true},
+		{"no cause", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := browserCrashed(tt.err); got != tt.want {
+				t.Errorf("browserCrashed(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportBrowserLost_KeepsFirstReport(t *testing.T) {
+	d := New(DefaultConfig())
+	first := errors.New("first")
+
+	d.reportBrowserLost(first)
+	d.reportBrowserLost(errors.New("second")) // must not block
+
+	if got := <-d.browserLost; got != first {
+		t.Errorf("got %v, want the first report", got)
+	}
+	select {
+	case err := <-d.browserLost:
+		t.Errorf("unexpected second report: %v", err)
+	default:
+	}
+}
+
+func TestReuseBrowser_Refusals(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.StatePath = filepath.Join(t.TempDir(), "webctl.json")
+	d := New(cfg)
+
+	// No state file: nothing to reuse, so the caller launches instead.
+	if _, err := d.reuseBrowser(context.Background()); !errors.Is(err, browser.ErrNotReusable) {
+		t.Errorf("missing state: expected ErrNotReusable, got %v", err)
+	}
+
+	// The owning daemon is still alive: refuse rather than share the browser.
+	owner := exec.Command("sleep", "30")
+	if err := owner.Start(); err != nil {
+		t.Skip("no sleep binary:", err)
+	}
+	defer func() {
+		_ = owner.Process.Kill()
+		_ = owner.Wait()
+	}()
+	if err := ipc.WriteState(cfg.StatePath, ipc.State{PID: owner.Process.Pid, BrowserPID: owner.Process.Pid, Port: 9222}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := d.reuseBrowser(context.Background())
+	if err == nil || errors.Is(err, browser.ErrNotReusable) || !strings.Contains(err.Error(), "still running") {
+		t.Errorf("live owner: expected a hard error, got %v", err)
+	}
+}
+
+func TestHandleBrowserMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Headless = true
+	d := New(cfg)
+
+	params, _ := json.Marshal(ipc.BrowserModeParams{Headless: true})
+	resp := d.handleRequest(ipc.Request{Cmd: "browser-mode", Params: params})
+	if !resp.OK {
+		t.Fatalf("already headless: unexpected error %q", resp.Error)
+	}
+	var data ipc.BrowserModeData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Changed || !data.Headless {
+		t.Errorf("already headless: got %+v, want unchanged and headless", data)
+	}
+
+	// A switch is handed to Run; with the daemon shutting down there is no
+	// Run to take it.
+	close(d.shutdown)
+	params, _ = json.Marshal(ipc.BrowserModeParams{Headless: false})
+	resp = d.handleRequest(ipc.Request{Cmd: "browser-mode", Params: params})
+	if resp.OK || !strings.Contains(resp.Error, "shutting down") {
+		t.Errorf("shutting down: got %+v, want a shutdown error", resp)
+	}
+}
+
+func TestHandleBrowserMode_HandsSwitchToRun(t *testing.T) {
+	d := New(DefaultConfig())
+
+	go func() {
+		sw := <-d.modeSwitches
+		sw.reply <- ipc.SuccessResponse(ipc.BrowserModeData{Headless: sw.headless, Changed: true, Tabs: 2})
+	}()
+
+	params, _ := json.Marshal(ipc.BrowserModeParams{Headless: true})
+	resp := d.handleRequest(ipc.Request{Cmd: "browser-mode", Params: params})
+	var data ipc.BrowserModeData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.OK || !data.Changed || !data.Headless || data.Tabs != 2 {
+		t.Errorf("got %+v (%+v), want Run's reply", resp, data)
+	}
+}
@@ -0,0 +1,217 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+	"github.com/grantcarthew/webctl/internal/jsonpatch"
+)
+
+// rewriteRule is a 'webctl rewrite' rule: response bodies whose URL matches
+// are edited through the Fetch domain before the page sees them.
+type rewriteRule struct {
+	info  ipc.RewriteInfo
+	patch jsonpatch.Patch
+}
+
+// handleRewrite adds, lists, or removes rewrites.
+func (d *Daemon) handleRewrite(req ipc.Request) ipc.Response {
+	var params ipc.RewriteParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid rewrite parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "add":
+		return d.addRewrite(params)
+	case "list", "":
+		return ipc.SuccessResponse(ipc.RewriteData{Rewrites: d.rewriteList()})
+	case "remove":
+		d.rewritesMu.Lock()
+		i := -1
+		for j, r := range d.rewrites {
+			if r.info.ID == params.ID {
+				i = j
+			}
+		}
+		if i < 0 {
+			d.rewritesMu.Unlock()
+			return ipc.ErrorResponse(fmt.Sprintf("no rewrite with id %q", params.ID))
+		}
+		removed := d.rewrites[i].info
+		d.rewrites = append(d.rewrites[:i:i], d.rewrites[i+1:]...)
+		d.rewritesMu.Unlock()
+		d.log.Info("rewrite removed", "id", params.ID)
+		return d.syncInterception(ipc.RewriteData{Rewrites: []ipc.RewriteInfo{removed}})
+	case "clear":
+		removed := d.rewriteList()
+		d.rewritesMu.Lock()
+		d.rewrites = nil
+		d.rewritesMu.Unlock()
+		d.log.Info("rewrites cleared", "count", len(removed))
+		return d.syncInterception(ipc.RewriteData{Rewrites: removed})
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown rewrite action: %s", params.Action))
+	}
+}
+
+// addRewrite validates a rewrite and starts intercepting its URL's responses
+// in every tab.
+func (d *Daemon) addRewrite(params ipc.RewriteParams) ipc.Response {
+	if params.URL == "" {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "url is required")
+	}
+	if len(params.JSONPatch) == 0 && len(params.Replace) == 0 {
+		return ipc.ErrorResponse("rewrite does nothing: give a JSON patch or a replacement")
+	}
+	var patch jsonpatch.Patch
+	if len(params.JSONPatch) > 0 {
+		var err error
+		if patch, err = jsonpatch.Parse(params.JSONPatch); err != nil {
+			return ipc.ErrorResponse(err.Error())
+		}
+	}
+	for _, r := range params.Replace {
+		if r.From == "" {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, "replacement needs text to replace")
+		}
+	}
+
+	rule := &rewriteRule{
+		info: ipc.RewriteInfo{
+			URL:      interceptPattern(params.URL),
+			PatchOps: len(patch),
+			Replace:  params.Replace,
+			Created:  time.Now().UnixMilli(),
+		},
+		patch: patch,
+	}
+
+	d.rewritesMu.Lock()
+	d.rewriteSeq++
+	rule.info.ID = "w" + strconv.Itoa(d.rewriteSeq)
+	d.rewrites = append(d.rewrites, rule)
+	info := rule.info
+	d.rewritesMu.Unlock()
+
+	d.log.Info("rewrite added", "id", info.ID, "url", info.URL, "patchOps", info.PatchOps, "replacements", len(info.Replace))
+	return d.syncInterception(ipc.RewriteData{Rewrites: []ipc.RewriteInfo{info}})
+}
+
+// rewriteList returns every rewrite in the order they were added.
+func (d *Daemon) rewriteList() []ipc.RewriteInfo {
+	d.rewritesMu.Lock()
+	defer d.rewritesMu.Unlock()
+	infos := make([]ipc.RewriteInfo, 0, len(d.rewrites))
+	for _, r := range d.rewrites {
+		infos = append(infos, r.info)
+	}
+	return infos
+}
+
+// rewriteResponse edits a response paused at the response stage with every
+// rewrite matching its URL, and fulfills the request with the result. A
+// redirect, a failed response, or a body no rewrite changed continues as it
+// was. It runs off the read loop.
+func (d *Daemon) rewriteResponse(sessionID, requestID, url string, status int, headers []map[string]string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		cont := func() {
+			if _, err := d.cdp.SendToSession(ctx, sessionID, "Fetch.continueRequest", map[string]any{"requestId": requestID}); err != nil {
+				d.log.Debug("Fetch.continueRequest failed", "requestId", requestID, "error", err)
+			}
+		}
+		if status == 0 || (status >= 300 && status < 400) {
+			cont()
+			return
+		}
+
+		result, err := d.cdp.SendToSession(ctx, sessionID, "Fetch.getResponseBody", map[string]any{"requestId": requestID})
+		if err != nil {
+			d.log.Debug("Fetch.getResponseBody failed", "requestId", requestID, "error", err)
+			cont()
+			return
+		}
+		var resp struct {
+			Body          string `json:"body"`
+			Base64Encoded bool   `json:"base64Encoded"`
+		}
+		if err := json.Unmarshal(result, &resp); err != nil {
+			cont()
+			return
+		}
+		body := []byte(resp.Body)
+		if resp.Base64Encoded {
+			if body, err = base64.StdEncoding.DecodeString(resp.Body); err != nil {
+				cont()
+				return
+			}
+		}
+
+		edited := d.applyRewrites(url, body)
+		if bytes.Equal(edited, body) {
+			cont()
+			return
+		}
+
+		// The body from getResponseBody is decoded, so the original length
+		// and encoding no longer describe it.
+		var kept []map[string]string
+		for _, h := range headers {
+			switch strings.ToLower(h["name"]) {
+			case "content-length", "content-encoding":
+				continue
+			}
+			kept = append(kept, h)
+		}
+		if _, err := d.cdp.SendToSession(ctx, sessionID, "Fetch.fulfillRequest", map[string]any{
+			"requestId":       requestID,
+			"responseCode":    status,
+			"responseHeaders": kept,
+			"body":            base64.StdEncoding.EncodeToString(edited),
+		}); err != nil {
+			d.log.Debug("Fetch.fulfillRequest failed", "requestId", requestID, "error", err)
+			return
+		}
+		d.log.Debug("response rewritten", "url", url, "bytes", len(edited))
+	}()
+}
+
+// applyRewrites edits body with every rewrite matching url, in the order
+// they were added: each rewrite's JSON patch first, then its replacements. A
+// rewrite whose patch fails is skipped and its error recorded.
+func (d *Daemon) applyRewrites(url string, body []byte) []byte {
+	d.rewritesMu.Lock()
+	defer d.rewritesMu.Unlock()
+	for _, r := range d.rewrites {
+		if !interceptURLMatch(r.info.URL, url) {
+			continue
+		}
+		edited := body
+		if len(r.patch) > 0 {
+			patched, err := r.patch.Apply(edited)
+			if err != nil {
+				r.info.Errors++
+				r.info.LastError = err.Error()
+				d.log.Debug("rewrite failed", "id", r.info.ID, "url", url, "error", err)
+				continue
+			}
+			edited = patched
+		}
+		for _, rep := range r.info.Replace {
+			edited = bytes.ReplaceAll(edited, []byte(rep.From), []byte(rep.To))
+		}
+		r.info.Hits++
+		body = edited
+	}
+	return body
+}
@@ -0,0 +1,112 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func rewriteRequest(t *testing.T, d *Daemon, params ipc.RewriteParams) (ipc.Response, ipc.RewriteData) {
+	t.Helper()
+	raw, _ := json.Marshal(params)
+	resp := d.handleRewrite(ipc.Request{Cmd: "rewrite", Params: raw})
+	var data ipc.RewriteData
+	if resp.OK {
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp, data
+}
+
+func TestHandleRewrite_AddValidation(t *testing.T) {
+	d := New(DefaultConfig())
+
+	tests := []struct {
+		name   string
+		params ipc.RewriteParams
+		want   string
+	}{
+		{"no url", ipc.RewriteParams{Action: "add", Replace: []ipc.RewriteReplace{{From: "a", To: "b"}}}, "url is required"},
+		{"no edit", ipc.RewriteParams{Action: "add", URL: "/api"}, "rewrite does nothing"},
+		{"bad patch", ipc.RewriteParams{Action: "add", URL: "/api", JSONPatch: json.RawMessage(`[{"op":"set","path":"/a"}]`)}, `unknown op "set"`},
+		{"empty from", ipc.RewriteParams{Action: "add", URL: "/api", Replace: []ipc.RewriteReplace{{To: "b"}}}, "needs text to replace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := rewriteRequest(t, d, tt.params)
+			if resp.OK || !strings.Contains(resp.Error, tt.want) {
+				t.Errorf("response = %+v, want error containing %q", resp, tt.want)
+			}
+		})
+	}
+	if len(d.rewrites) != 0 {
+		t.Errorf("invalid rewrites were added: %d", len(d.rewrites))
+	}
+}
+
+func TestHandleRewrite_Lifecycle(t *testing.T) {
+	d := New(DefaultConfig())
+	mockRequest(t, d, ipc.MockParams{Action: "add", URL: "*/slow/*", Delay: 100})
+
+	resp, data := rewriteRequest(t, d, ipc.RewriteParams{
+		Action:    "add",
+		URL:       "/api/flags",
+		JSONPatch: json.RawMessage(`[{"op":"replace","path":"/beta","value":true}]`),
+	})
+	if !resp.OK || len(data.Rewrites) != 1 || data.Rewrites[0].ID != "w1" || data.Rewrites[0].URL != "*/api/flags*" || data.Rewrites[0].PatchOps != 1 {
+		t.Fatalf("add = %+v %+v", resp, data)
+	}
+
+	patterns := d.interceptPatterns()
+	if len(patterns) != 2 || patterns[0]["requestStage"] != "Request" || patterns[1]["requestStage"] != "Response" {
+		t.Errorf("patterns = %v", patterns)
+	}
+
+	if resp, _ := rewriteRequest(t, d, ipc.RewriteParams{Action: "remove", ID: "w9"}); resp.OK {
+		t.Error("removing an unknown rewrite succeeded")
+	}
+	if resp, _ := rewriteRequest(t, d, ipc.RewriteParams{Action: "remove", ID: "w1"}); !resp.OK {
+		t.Fatalf("remove = %+v", resp)
+	}
+	if patterns := d.interceptPatterns(); len(patterns) != 1 {
+		t.Errorf("patterns after remove = %v", patterns)
+	}
+}
+
+func TestApplyRewrites(t *testing.T) {
+	d := New(DefaultConfig())
+	rewriteRequest(t, d, ipc.RewriteParams{
+		Action:    "add",
+		URL:       "*/api/*",
+		JSONPatch: json.RawMessage(`[{"op":"replace","path":"/plan","value":"pro"}]`),
+	})
+	rewriteRequest(t, d, ipc.RewriteParams{
+		Action:  "add",
+		URL:     "/api/me",
+		Replace: []ipc.RewriteReplace{{From: `"pro"`, To: `"enterprise"`}, {From: "Ann", To: "Anne"}},
+	})
+
+	got := d.applyRewrites("https://app.test/api/me", []byte(`{"name": "Ann", "plan": "free"}`))
+	if string(got) != `{"name":"Anne","plan":"enterprise"}` {
+		t.Errorf("rewritten = %s", got)
+	}
+
+	// The patch cannot apply to HTML; that rewrite is skipped and recorded,
+	// and the others still apply.
+	got = d.applyRewrites("https://app.test/api/me", []byte(`<p>Ann</p>`))
+	if string(got) != `<p>Anne</p>` {
+		t.Errorf("rewritten = %s", got)
+	}
+
+	if got := d.applyRewrites("https://app.test/index.html", []byte("Ann")); string(got) != "Ann" {
+		t.Errorf("unmatched URL rewritten: %s", got)
+	}
+
+	list := d.rewriteList()
+	if list[0].Hits != 1 || list[0].Errors != 1 || !strings.Contains(list[0].LastError, "not JSON") || list[1].Hits != 2 {
+		t.Errorf("counts = %+v", list)
+	}
+}
@@ -0,0 +1,245 @@
+package daemon
+
+// describeElementJS is a Runtime.callFunctionOn function declaration that
+// describes the element it is called on (this). It returns the element
+// metadata used elsewhere (tag, id, first class) plus a CSS selector and an
+// XPath that each match exactly that element.
+//
+// The CSS selector prefers stable hooks over structure: a unique id, then a
+// unique test or accessibility attribute, then the shortest ancestor path
+// (tag, up to two classes, :nth-of-type when siblings share a tag) that is
+// unique in the document. Classes that look generated (long digit runs,
+// CSS-in-JS prefixes, CSS-module hash suffixes) are skipped. The XPath uses a
+// unique id when there is one and otherwise an absolute indexed path.
+const describeElementJS = `function() {
+	const el = this;
+	const stableAttrs = ['data-testid', 'data-test', 'data-cy', 'data-qa', 'name', 'aria-label'];
+
+	function unique(sel) {
+		try {
+			const found = document.querySelectorAll(sel);
+			return found.length === 1 && found[0] === el;
+		} catch (e) {
+			return false;
+		}
+	}
+
+	function stableClasses(node) {
+		return Array.from(node.classList)
+			.filter(c => !/\d{3,}/.test(c) && !/^(css|sc|jsx)-/.test(c) && !/__[a-zA-Z0-9]{5,}$/.test(c))
+			.slice(0, 2)
+			.map(c => '.' + CSS.escape(c));
+	}
+
+	function step(node) {
+		let part = node.tagName.toLowerCase() + stableClasses(node).join('');
+		const parent = node.parentElement;
+		if (parent) {
+			const sameTag = Array.from(parent.children).filter(c => c.tagName === node.tagName);
+			if (sameTag.length > 1) {
+				part += ':nth-of-type(' + (sameTag.indexOf(node) + 1) + ')';
+			}
+		}
+		return part;
+	}
+
+	function cssSelector() {
+		if (el.id && unique('#' + CSS.escape(el.id))) {
+			return '#' + CSS.escape(el.id);
+		}
+		const tag = el.tagName.toLowerCase();
+		for (const attr of stableAttrs) {
+			const v = el.getAttribute(attr);
+			if (v) {
+				const sel = tag + '[' + attr + '=' + JSON.stringify(v) + ']';
+				if (unique(sel)) {
+					return sel;
+				}
+			}
+		}
+		const parts = [];
+		let node = el;
+		while (node && node.nodeType === 1) {
+			if (node !== el && node.id && document.querySelectorAll('#' + CSS.escape(node.id)).length === 1) {
+				parts.unshift('#' + CSS.escape(node.id));
+			} else {
+				parts.unshift(step(node));
+			}
+			const sel = parts.join(' > ');
+			if (unique(sel)) {
+				return sel;
+			}
+			if (parts[0].startsWith('#')) {
+				break;
+			}
+			node = node.parentElement;
+		}
+		return parts.join(' > ');
+	}
+
+	function xpath() {
+		if (el.id && document.querySelectorAll('#' + CSS.escape(el.id)).length === 1) {
+			return '//*[@id=' + JSON.stringify(el.id) + ']';
+		}
+		const parts = [];
+		let node = el;
+		while (node && node.nodeType === 1) {
+			const tag = node.tagName.toLowerCase();
+			const parent = node.parentElement;
+			if (parent) {
+				const sameTag = Array.from(parent.children).filter(c => c.tagName === node.tagName);
+				parts.unshift(sameTag.length > 1 ? tag + '[' + (sameTag.indexOf(node) + 1) + ']' : tag);
+			} else {
+				parts.unshift(tag);
+			}
+			node = parent;
+		}
+		return '/' + parts.join('/');
+	}
+
+	const classes = (el.getAttribute('class') || '').split(/\s+/).filter(c => c.length > 0);
+	return {
+		tag: el.tagName.toLowerCase(),
+		id: (el.id || '').trim() || null,
+		class: classes.length > 0 ? classes[0] : null,
+		selector: cssSelector(),
+		xpath: xpath()
+	};
+}`
+
+// elementNameJS declares helpers for page scripts that list elements, to be
+// spliced into a function body: clean(s, max) collapses whitespace and
+// truncates, visible(el) reports whether el is rendered and not hidden,
+// labelText(el) returns el's explicit label, and accessibleName(el) follows
+// the accessible name computation loosely: aria-labelledby, aria-label, an
+// associated <label>, alt, title, placeholder, then the element's text.
+const elementNameJS = `	const clean = (s, max) => {
+		s = (s || '').replace(/\s+/g, ' ').trim();
+		return s.length > max ? s.slice(0, max - 1) + '…' : s;
+	};
+
+	function visible(el) {
+		const r = el.getBoundingClientRect();
+		if (r.width === 0 && r.height === 0) {
+			return false;
+		}
+		const style = getComputedStyle(el);
+		return style.visibility !== 'hidden' && style.display !== 'none';
+	}
+
+	function labelText(el) {
+		const ids = el.getAttribute('aria-labelledby');
+		if (ids) {
+			const text = ids.split(/\s+/).map(id => document.getElementById(id)).filter(Boolean).map(n => n.textContent).join(' ');
+			if (text.trim()) {
+				return text;
+			}
+		}
+		if (el.getAttribute('aria-label')) {
+			return el.getAttribute('aria-label');
+		}
+		if (el.labels && el.labels.length > 0) {
+			return Array.from(el.labels).map(l => l.textContent).join(' ');
+		}
+		return el.getAttribute('alt') || el.getAttribute('title') || el.getAttribute('placeholder') || '';
+	}
+
+	function accessibleName(el) {
+		let text = labelText(el);
+		if (!text) {
+			if (el.tagName === 'INPUT' && ['submit', 'button', 'reset'].includes(el.type)) {
+				text = el.value;
+			} else {
+				text = el.innerText || el.textContent;
+			}
+		}
+		if (!text) {
+			const img = el.querySelector('img[alt], svg title');
+			text = img ? (img.getAttribute('alt') || img.textContent) : '';
+		}
+		return clean(text, 80);
+	}`
+
+// selectorCandidatesJS is a Runtime function declaration that generates
+// selectors for the element it is called on (this), in order of robustness:
+// a stable id, test attributes, name, aria-label, other identifying
+// attributes, stable classes, classes anchored on the nearest ancestor with a
+// stable id, and finally the structural path from describeElementJS. Each
+// candidate is checked against the document; selectors that do not match the
+// element are dropped and ambiguous ones rank after every unique one.
+const selectorCandidatesJS = `function() {
+	const el = this;
+	const described = (` + describeElementJS + `).call(el);
+	const tag = el.tagName.toLowerCase();
+	const generated = s => /\d{3,}/.test(s) || /^(css|sc|jsx)-/.test(s) || /__[a-zA-Z0-9]{5,}$/.test(s) || /^:/.test(s);
+	const attr = (name, value) => '[' + name + '=' + JSON.stringify(value) + ']';
+	const stableID = node => node.id && !generated(node.id) && document.querySelectorAll('#' + CSS.escape(node.id)).length === 1;
+	const testAttrs = ['data-testid', 'data-test', 'data-cy', 'data-qa'];
+
+	const seen = new Set();
+	const candidates = [];
+	function add(selector, strategy) {
+		if (!selector || seen.has(selector)) {
+			return;
+		}
+		seen.add(selector);
+		let found;
+		try {
+			found = Array.from(document.querySelectorAll(selector));
+		} catch (e) {
+			return;
+		}
+		if (!found.includes(el)) {
+			return;
+		}
+		candidates.push({selector: selector, strategy: strategy, matches: found.length, unique: found.length === 1});
+	}
+
+	if (stableID(el)) {
+		add('#' + CSS.escape(el.id), 'id');
+	}
+	for (const a of testAttrs) {
+		const v = el.getAttribute(a);
+		if (v) {
+			add(attr(a, v), 'test-id');
+		}
+	}
+	if (el.getAttribute('name')) {
+		add(tag + attr('name', el.getAttribute('name')), 'name');
+	}
+	if (el.getAttribute('aria-label')) {
+		add(tag + attr('aria-label', el.getAttribute('aria-label')), 'aria-label');
+	}
+	for (const a of ['placeholder', 'alt', 'title', 'for', 'href', 'type']) {
+		const v = el.getAttribute(a);
+		if (v && v.length <= 100) {
+			add(tag + attr(a, v), 'attribute');
+		}
+	}
+
+	const classes = Array.from(el.classList).filter(c => !generated(c)).slice(0, 2).map(c => '.' + CSS.escape(c));
+	if (classes.length > 0) {
+		add(tag + classes.join(''), 'class');
+	}
+
+	for (let node = el.parentElement; node; node = node.parentElement) {
+		let anchor = '';
+		if (stableID(node)) {
+			anchor = '#' + CSS.escape(node.id);
+		} else {
+			const a = testAttrs.find(a => node.getAttribute(a));
+			if (a) {
+				anchor = attr(a, node.getAttribute(a));
+			}
+		}
+		if (anchor) {
+			add(anchor + ' ' + tag + classes.join(''), 'anchored');
+			break;
+		}
+	}
+
+	add(described.selector, 'path');
+
+	candidates.sort((a, b) => Number(b.unique) - Number(a.unique));
+	return {tag: described.tag, id: described.id, class: described.class, candidates: candidates, xpath: described.xpath};
+}`
@@ -13,12 +13,33 @@ type session struct {
 	TargetID  string
 	URL       string
 	Title     string
+	// BrowserContextID is the browser context the page was opened in, when it
+	// is one made with 'context new'; empty for the default context.
+	BrowserContextID string
 	// networkEnabled records that Network.enable succeeded for this session.
 	// It is a fact about the session, so it lives here rather than in a map on
 	// the daemon, and it gates the at-most-once Network.enable guarantee.
 	networkEnabled bool
+	// navigations holds the Unix-millisecond times of the page's main-frame
+	// navigations, oldest first, capped at maxNavigations.
+	navigations []int64
+	// zoom is the page zoom set with 'webctl zoom', zero for none, and
+	// zoomScript the identifier of the script that re-applies it on every
+	// load.
+	zoom       float64
+	zoomScript string
+	// bannerScript is the identifier of the script that dismisses banners on
+	// every load while 'dismiss-banners --auto' is on.
+	bannerScript string
+	// batteryScript is the identifier of the script that installs the battery
+	// set with 'emulate battery' on every load.
+	batteryScript string
 }
 
+// maxNavigations caps the navigation times kept per session. Console entries
+// older than the oldest kept navigation have no navigation to be relative to.
+const maxNavigations = 64
+
 // SessionManager tracks CDP page sessions and the tab attach/detach rendezvous.
 type SessionManager struct {
 	mu       sync.RWMutex
@@ -140,6 +161,31 @@ func (m *SessionManager) UpdateByTargetID(targetID, url, title string) {
 	}
 }
 
+// SetBrowserContext records the browser context a session's page belongs to.
+func (m *SessionManager) SetBrowserContext(sessionID, contextID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, exists := m.sessions[sessionID]; exists {
+		s.BrowserContextID = contextID
+	}
+}
+
+// InBrowserContext returns the sessions whose pages belong to the given
+// browser context, in attachment order.
+func (m *SessionManager) InBrowserContext(contextID string) []ipc.PageSession {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []ipc.PageSession
+	for _, id := range m.order {
+		if s := m.sessions[id]; s != nil && s.BrowserContextID == contextID {
+			result = append(result, *m.toPageSessionLocked(s))
+		}
+	}
+	return result
+}
+
 // SetActive sets the active session by ID.
 // Returns false if the session doesn't exist.
 func (m *SessionManager) SetActive(sessionID string) bool {
@@ -214,19 +260,16 @@ func (m *SessionManager) GetByTargetID(targetID string) *ipc.PageSession {
 	return nil
 }
 
-// All returns all sessions as IPC PageSession list.
+// All returns all sessions as IPC PageSession list, in attachment order.
 func (m *SessionManager) All() []ipc.PageSession {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	result := make([]ipc.PageSession, 0, len(m.sessions))
-	for _, s := range m.sessions {
-		result = append(result, ipc.PageSession{
-			ID:     s.SessionID,
-			Title:  s.Title,
-			URL:    s.URL,
-			Active: s.SessionID == m.activeID,
-		})
+	result := make([]ipc.PageSession, 0, len(m.order))
+	for _, id := range m.order {
+		if s, ok := m.sessions[id]; ok {
+			result = append(result, *m.toPageSessionLocked(s))
+		}
 	}
 	return result
 }
@@ -276,6 +319,85 @@ func (m *SessionManager) ClearNetworkEnabled(sessionID string) {
 	}
 }
 
+// SetZoom records the session's zoom factor and the script that applies it,
+// returning the script it replaces.
+func (m *SessionManager) SetZoom(sessionID string, factor float64, scriptID string) (previous string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return ""
+	}
+	previous = s.zoomScript
+	s.zoom, s.zoomScript = factor, scriptID
+	return previous
+}
+
+// Zoom returns the session's zoom factor, 1 when none is set.
+func (m *SessionManager) Zoom(sessionID string) float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if s, ok := m.sessions[sessionID]; ok && s.zoom > 0 {
+		return s.zoom
+	}
+	return 1
+}
+
+// SwapBannerScript records the session's banner dismissal script, returning
+// the one it replaces.
+func (m *SessionManager) SwapBannerScript(sessionID, scriptID string) (previous string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return ""
+	}
+	previous, s.bannerScript = s.bannerScript, scriptID
+	return previous
+}
+
+// SwapBatteryScript records the session's battery emulation script,
+// returning the one it replaces.
+func (m *SessionManager) SwapBatteryScript(sessionID, scriptID string) (previous string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return ""
+	}
+	previous, s.batteryScript = s.batteryScript, scriptID
+	return previous
+}
+
+// RecordNavigation notes a main-frame navigation of the session at ms.
+func (m *SessionManager) RecordNavigation(sessionID string, ms int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.sessions[sessionID]; ok {
+		s.navigations = append(s.navigations, ms)
+		if n := len(s.navigations); n > maxNavigations {
+			s.navigations = append([]int64(nil), s.navigations[n-maxNavigations:]...)
+		}
+	}
+}
+
+// Navigations returns a copy of the session's main-frame navigation times,
+// oldest first.
+func (m *SessionManager) Navigations(sessionID string) []int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if s, ok := m.sessions[sessionID]; ok && len(s.navigations) > 0 {
+		return append([]int64(nil), s.navigations...)
+	}
+	return nil
+}
+
 // NetworkEnabled reports whether Network.enable succeeded for the session.
 func (m *SessionManager) NetworkEnabled(sessionID string) bool {
 	m.mu.RLock()
@@ -343,10 +465,11 @@ func (m *SessionManager) stopWaitForDetach(sessionID string) {
 // toPageSessionLocked builds the IPC view of a session. Callers must hold m.mu.
 func (m *SessionManager) toPageSessionLocked(s *session) *ipc.PageSession {
 	return &ipc.PageSession{
-		ID:     s.SessionID,
-		Title:  s.Title,
-		URL:    s.URL,
-		Active: s.SessionID == m.activeID,
+		ID:      s.SessionID,
+		Title:   s.Title,
+		URL:     s.URL,
+		Active:  s.SessionID == m.activeID,
+		Context: s.BrowserContextID,
 	}
 }
 
@@ -366,12 +489,7 @@ func (m *SessionManager) FindByQuery(query string) []ipc.PageSession {
 	// First try exact session ID prefix match
 	for _, s := range m.sessions {
 		if len(s.SessionID) >= len(query) && s.SessionID[:len(query)] == query {
-			matches = append(matches, ipc.PageSession{
-				ID:     s.SessionID,
-				Title:  s.Title,
-				URL:    s.URL,
-				Active: s.SessionID == m.activeID,
-			})
+			matches = append(matches, *m.toPageSessionLocked(s))
 		}
 	}
 
@@ -383,12 +501,7 @@ func (m *SessionManager) FindByQuery(query string) []ipc.PageSession {
 	queryLower := strings.ToLower(query)
 	for _, s := range m.sessions {
 		if strings.Contains(strings.ToLower(s.Title), queryLower) {
-			matches = append(matches, ipc.PageSession{
-				ID:     s.SessionID,
-				Title:  s.Title,
-				URL:    s.URL,
-				Active: s.SessionID == m.activeID,
-			})
+			matches = append(matches, *m.toPageSessionLocked(s))
 		}
 	}
 
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -179,3 +180,72 @@ func TestSessionManager_All(t *testing.T) {
 		t.Errorf("expected 1 active session, got %d", activeCount)
 	}
 }
+
+func TestSessionManager_InBrowserContext(t *testing.T) {
+	sm := NewSessionManager()
+	sm.Add("sess1", "target1", "http://example.com", "Example")
+	sm.Add("sess2", "target2", "http://other.com", "Other")
+	sm.Add("sess3", "target3", "http://third.com", "Third")
+	sm.SetBrowserContext("sess2", "ctx1")
+	sm.SetBrowserContext("sess3", "ctx1")
+
+	def := sm.InBrowserContext("")
+	if len(def) != 1 || def[0].ID != "sess1" || def[0].Context != "" {
+		t.Errorf("default context = %+v, want only sess1", def)
+	}
+
+	ctx1 := sm.InBrowserContext("ctx1")
+	if len(ctx1) != 2 || ctx1[0].ID != "sess2" || ctx1[1].ID != "sess3" {
+		t.Errorf("ctx1 = %+v, want sess2, sess3", ctx1)
+	}
+	if ctx1[0].Context != "ctx1" {
+		t.Errorf("expected Context 'ctx1', got %q", ctx1[0].Context)
+	}
+
+	if got := sm.InBrowserContext("missing"); len(got) != 0 {
+		t.Errorf("expected no sessions, got %d", len(got))
+	}
+}
+
+func TestSessionManager_Navigations(t *testing.T) {
+	sm := NewSessionManager()
+	sm.Add("sess1", "target1", "http://example.com", "Example")
+
+	if got := sm.Navigations("sess1"); got != nil {
+		t.Errorf("expected no navigations, got %v", got)
+	}
+
+	for i := range maxNavigations + 3 {
+		sm.RecordNavigation("sess1", int64(i))
+	}
+	sm.RecordNavigation("missing", 1)
+
+	got := sm.Navigations("sess1")
+	if len(got) != maxNavigations {
+		t.Fatalf("expected %d navigations, got %d", maxNavigations, len(got))
+	}
+	if got[0] != 3 || got[len(got)-1] != maxNavigations+2 {
+		t.Errorf("expected the newest navigations kept, got %d..%d", got[0], got[len(got)-1])
+	}
+
+	got[0] = -1
+	if sm.Navigations("sess1")[0] != 3 {
+		t.Error("Navigations should return a copy")
+	}
+}
+
+func TestSessionManager_AllInAttachmentOrder(t *testing.T) {
+	sm := NewSessionManager()
+	for _, id := range []string{"c", "a", "d", "b"} {
+		sm.Add(id, "target-"+id, "", "")
+	}
+	sm.Remove("d")
+
+	var got []string
+	for _, s := range sm.All() {
+		got = append(got, s.ID)
+	}
+	if strings.Join(got, ",") != "c,a,b" {
+		t.Errorf("All() order = %v, want [c a b]", got)
+	}
+}
@@ -0,0 +1,157 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// stealthChromeFlag stops Chrome marking itself as automated, which sets
+// navigator.webdriver and shows the "controlled by automated software" bar.
+const stealthChromeFlag = "--disable-blink-features=AutomationControlled"
+
+// stealthJS runs in every document before its own scripts when stealth is on.
+// It hides the differences between an automated or headless Chrome and a
+// regular one that bot detection commonly checks. Each shim applies only
+// where the browser gives itself away, so a headed Chrome is mostly left as
+// is.
+const stealthJS = `(() => {
+  const define = (obj, prop, get) => {
+    try {
+      Object.defineProperty(obj, prop, { get, configurable: true, enumerable: true });
+    } catch (e) {}
+  };
+
+  // A regular Chrome reports false, not true or undefined
+  if (navigator.webdriver !== false) {
+    define(Navigator.prototype, 'webdriver', () => false);
+  }
+
+  // Headless Chrome can report no languages
+  if (!navigator.languages || navigator.languages.length === 0) {
+    const lang = navigator.language || 'en-US';
+    const languages = Object.freeze(lang.includes('-') ? [lang, lang.split('-')[0]] : [lang]);
+    define(Navigator.prototype, 'languages', () => languages);
+  }
+
+  // Headless Chrome can report no plugins; a regular one lists its PDF viewers
+  if (navigator.plugins && navigator.plugins.length === 0) {
+    const mime = { type: 'application/pdf', suffixes: 'pdf', description: 'Portable Document Format' };
+    const names = ['PDF Viewer', 'Chrome PDF Viewer', 'Chromium PDF Viewer', 'Microsoft Edge PDF Viewer', 'WebKit built-in PDF'];
+    const plugins = names.map((name) => {
+      const plugin = { name, filename: 'internal-pdf-viewer', description: 'Portable Document Format', length: 1, 0: mime };
+      plugin.item = (i) => plugin[i] || null;
+      plugin.namedItem = (type) => (type === mime.type ? mime : null);
+      return Object.setPrototypeOf(plugin, Plugin.prototype);
+    });
+    plugins.item = (i) => plugins[i] || null;
+    plugins.namedItem = (name) => plugins.find((p) => p.name === name) || null;
+    plugins.refresh = () => {};
+    Object.setPrototypeOf(plugins, PluginArray.prototype);
+    define(Navigator.prototype, 'plugins', () => plugins);
+    define(Navigator.prototype, 'pdfViewerEnabled', () => true);
+  }
+
+  // Pages in a regular Chrome have window.chrome
+  if (!window.chrome) {
+    define(window, 'chrome', () => ({ runtime: {} }));
+  }
+
+  // Headless Chrome denies notifications while reporting them as "default"
+  if (navigator.permissions && window.Notification) {
+    const query = navigator.permissions.query.bind(navigator.permissions);
+    navigator.permissions.query = (desc) =>
+      desc && desc.name === 'notifications'
+        ? Promise.resolve({ state: Notification.permission === 'default' ? 'prompt' : Notification.permission, onchange: null })
+        : query(desc);
+  }
+})();`
+
+// applyStealth installs stealthJS in a session and, unless a user agent is
+// emulated, replaces a headless user agent with the regular one and aligns the
+// User-Agent Client Hints with it.
+func (d *Daemon) applyStealth(ctx context.Context, sessionID string) error {
+	if _, err := d.cdp.SendToSession(ctx, sessionID, "Page.addScriptToEvaluateOnNewDocument", map[string]any{
+		"source":         stealthJS,
+		"runImmediately": true,
+	}); err != nil {
+		return fmt.Errorf("failed to add stealth script: %w", err)
+	}
+	if d.config.Emulation.UserAgent != "" {
+		return nil
+	}
+
+	result, err := d.cdp.SendContext(ctx, "Browser.getVersion", nil)
+	if err != nil {
+		return fmt.Errorf("failed to get browser version: %w", err)
+	}
+	var version struct {
+		Product   string `json:"product"`
+		UserAgent string `json:"userAgent"`
+	}
+	if err := json.Unmarshal(result, &version); err != nil {
+		return fmt.Errorf("failed to parse browser version: %w", err)
+	}
+	override := stealthUserAgent(version.UserAgent, version.Product, runtime.GOOS, runtime.GOARCH)
+	if d.config.Launch.Lang != "" {
+		override["acceptLanguage"] = d.config.Launch.Lang
+	}
+	if _, err := d.cdp.SendToSession(ctx, sessionID, "Emulation.setUserAgentOverride", override); err != nil {
+		return fmt.Errorf("failed to set user agent: %w", err)
+	}
+	return nil
+}
+
+// stealthUserAgent builds Emulation.setUserAgentOverride parameters from the
+// browser's user agent and product ("HeadlessChrome/126.0.6478.126"), so that
+// navigator.userAgent, navigator.platform, navigator.userAgentData, and the
+// Sec-CH-UA headers all describe a regular Chrome on this platform.
+func stealthUserAgent(userAgent, product, goos, goarch string) map[string]any {
+	userAgent = strings.Replace(userAgent, "HeadlessChrome/", "Chrome/", 1)
+	_, full, _ := strings.Cut(product, "/")
+	major, _, _ := strings.Cut(full, ".")
+
+	platform, navPlatform := "Linux", "Linux x86_64"
+	switch goos {
+	case "darwin":
+		platform, navPlatform = "macOS", "MacIntel"
+	case "windows":
+		platform, navPlatform = "Windows", "Win32"
+	}
+	arch := "x86"
+	if strings.HasPrefix(goarch, "arm") {
+		arch = "arm"
+		if goos == "linux" {
+			navPlatform = "Linux aarch64"
+		}
+	}
+
+	brands := []map[string]string{
+		{"brand": "Not)A;Brand", "version": "99"},
+		{"brand": "Google Chrome", "version": major},
+		{"brand": "Chromium", "version": major},
+	}
+	fullVersions := []map[string]string{
+		{"brand": "Not)A;Brand", "version": "99.0.0.0"},
+		{"brand": "Google Chrome", "version": full},
+		{"brand": "Chromium", "version": full},
+	}
+	return map[string]any{
+		"userAgent": userAgent,
+		"platform":  navPlatform,
+		"userAgentMetadata": map[string]any{
+			"brands":          brands,
+			"fullVersionList": fullVersions,
+			"fullVersion":     full,
+			"platform":        platform,
+			"platformVersion": "",
+			"architecture":    arch,
+			"bitness":         "64",
+			"model":           "",
+			"mobile":          false,
+			"wow64":           false,
+		},
+	}
+}
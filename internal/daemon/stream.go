@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// streamStringChunk is how much of a string streamJSONString escapes at a
+// time.
+const streamStringChunk = 64 << 10
+
+// streamJSONObject streams a JSON object with a single string field, such as
+// ipc.HTMLData{HTML: s}, escaping s a chunk at a time rather than building
+// the whole encoding first.
+func streamJSONObject(w io.Writer, field, s string) error {
+	key, err := json.Marshal(field)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "{%s:", key); err != nil {
+		return err
+	}
+	if err := streamJSONString(w, s); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "}")
+	return err
+}
+
+// streamJSONString writes s as a JSON string, escaping it in chunks that
+// end on rune boundaries.
+func streamJSONString(w io.Writer, s string) error {
+	if _, err := io.WriteString(w, `"`); err != nil {
+		return err
+	}
+	for len(s) > 0 {
+		n := min(len(s), streamStringChunk)
+		for n < len(s) && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		quoted, err := json.Marshal(s[:n])
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(quoted[1 : len(quoted)-1]); err != nil {
+			return err
+		}
+		s = s[n:]
+	}
+	_, err := io.WriteString(w, `"`)
+	return err
+}
+
+// streamNetworkData streams ipc.NetworkData one entry at a time, so a buffer
+// full of response bodies is never encoded in one piece.
+func streamNetworkData(w io.Writer, entries []ipc.NetworkEntry) error {
+	if _, err := io.WriteString(w, `{"entries":[`); err != nil {
+		return err
+	}
+	for i, e := range entries {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		raw, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, `],"count":%d}`, len(entries))
+	return err
+}
+
+// base64Field returns a reader that decodes the base64 string field name of
+// a CDP result straight from the result's bytes, without first copying the
+// payload into a string. Results whose field holds escapes are decoded the
+// slow way.
+func base64Field(result json.RawMessage, name string) (io.Reader, error) {
+	key := []byte(`"` + name + `":"`)
+	if i := bytes.Index(result, key); i >= 0 {
+		value := result[i+len(key):]
+		if end := bytes.IndexByte(value, '"'); end >= 0 && bytes.IndexByte(value[:end], '\\') < 0 {
+			return base64.NewDecoder(base64.StdEncoding, bytes.NewReader(value[:end])), nil
+		}
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(result, &fields); err != nil {
+		return nil, err
+	}
+	var value string
+	if err := json.Unmarshal(fields[name], &value); err != nil {
+		return nil, fmt.Errorf("invalid %s field: %v", name, err)
+	}
+	return base64.NewDecoder(base64.StdEncoding, bytes.NewReader([]byte(value))), nil
+}
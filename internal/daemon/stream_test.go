@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestStreamJSONObject(t *testing.T) {
+	// Multi-byte runes straddle the chunk boundaries
+	html := strings.Repeat("<p class=\"x\">héllo — 世界</p>\n", streamStringChunk/8)
+
+	var buf bytes.Buffer
+	if err := streamJSONObject(&buf, "html", html); err != nil {
+		t.Fatal(err)
+	}
+	var data ipc.HTMLData
+	if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+		t.Fatalf("streamed JSON does not parse: %v", err)
+	}
+	if data.HTML != html {
+		t.Errorf("HTML is %d bytes after the round trip, want %d intact", len(data.HTML), len(html))
+	}
+}
+
+func TestStreamNetworkData(t *testing.T) {
+	entries := []ipc.NetworkEntry{
+		{RequestID: "1", URL: "https://example.test/", Status: 200, ResponseBody: "<html></html>"},
+		{RequestID: "2", URL: "https://example.test/api", Status: 500, Failed: true},
+	}
+	for _, n := range []int{0, len(entries)} {
+		var buf bytes.Buffer
+		if err := streamNetworkData(&buf, entries[:n]); err != nil {
+			t.Fatal(err)
+		}
+		var data ipc.NetworkData
+		if err := json.Unmarshal(buf.Bytes(), &data); err != nil {
+			t.Fatalf("streamed JSON does not parse: %v (%s)", err, buf.Bytes())
+		}
+		if data.Count != n || len(data.Entries) != n {
+			t.Errorf("count %d with %d entries, want %d", data.Count, len(data.Entries), n)
+		}
+		if n > 0 && data.Entries[0].ResponseBody != "<html></html>" {
+			t.Errorf("entries[0] = %+v", data.Entries[0])
+		}
+	}
+}
+
+func TestBase64Field(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\nsome image bytes")
+	encoded := base64.StdEncoding.EncodeToString(png)
+
+	for name, result := range map[string]string{
+		"plain":   `{"data":"` + encoded + `"}`,
+		"escaped": `{"data":"` + strings.ReplaceAll(encoded, "/", `\/`) + `"}`,
+	} {
+		r, err := base64Field(json.RawMessage(result), "data")
+		if err != nil {
+			t.Fatalf("%s: base64Field() error = %v", name, err)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(got, png) {
+			t.Errorf("%s: decoded %q, %v; want %q", name, got, err, png)
+		}
+	}
+
+	if _, err := base64Field(json.RawMessage(`{"other":1}`), "data"); err == nil {
+		t.Error("base64Field() accepted a result without the field")
+	}
+}
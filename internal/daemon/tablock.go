@@ -0,0 +1,63 @@
+package daemon
+
+import "sync"
+
+// tabLocks serialises the commands that drive a tab, by session ID, so two
+// terminals clicking and typing into the same tab take turns while other tabs
+// and read-only commands carry on. A session's lock is dropped once no
+// command holds or waits for it.
+type tabLocks struct {
+	mu    sync.Mutex
+	locks map[string]*tabLock
+}
+
+type tabLock struct {
+	mu   sync.Mutex
+	refs int // holders and waiters
+}
+
+// newTabLocks creates an empty set of tab locks.
+func newTabLocks() *tabLocks {
+	return &tabLocks{locks: make(map[string]*tabLock)}
+}
+
+// lock blocks until the caller holds sessionID's lock, and returns the
+// function that releases it.
+func (t *tabLocks) lock(sessionID string) (unlock func()) {
+	t.mu.Lock()
+	l, ok := t.locks[sessionID]
+	if !ok {
+		l = &tabLock{}
+		t.locks[sessionID] = l
+	}
+	l.refs++
+	t.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		t.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(t.locks, sessionID)
+		}
+		t.mu.Unlock()
+	}
+}
+
+// lockActiveTab takes the lock of the active tab, or returns a no-op if there
+// is none, leaving the command to report that. The active tab can change
+// while waiting; the lock is then retaken for the new one.
+func (d *Daemon) lockActiveTab() (unlock func()) {
+	for {
+		id := d.sessions.ActiveID()
+		if id == "" {
+			return func() {}
+		}
+		unlock := d.tabLocks.lock(id)
+		if d.sessions.ActiveID() == id {
+			return unlock
+		}
+		unlock()
+	}
+}
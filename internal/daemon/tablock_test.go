@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestTabLocks(t *testing.T) {
+	locks := newTabLocks()
+
+	unlockA := locks.lock("A")
+
+	// Another tab is not held up
+	done := make(chan struct{})
+	go func() {
+		locks.lock("B")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lock(B) waited for A")
+	}
+
+	// The same tab waits its turn
+	acquired := make(chan func())
+	go func() { acquired <- locks.lock("A") }()
+	select {
+	case <-acquired:
+		t.Fatal("lock(A) was taken twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlockA()
+	select {
+	case unlock := <-acquired:
+		unlock()
+	case <-time.After(time.Second):
+		t.Fatal("lock(A) was not handed over")
+	}
+
+	locks.mu.Lock()
+	defer locks.mu.Unlock()
+	if len(locks.locks) != 0 {
+		t.Errorf("%d locks left after release, want 0", len(locks.locks))
+	}
+}
+
+func TestHandleRequest_ReadOnlyWhileReplacing(t *testing.T) {
+	d := New(DefaultConfig())
+
+	// As replaceBrowser holds it while relaunching
+	d.browserMu.Lock()
+	defer d.browserMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		resp := d.handleRequest(ipc.Request{Cmd: "status"})
+		var data ipc.StatusData
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			t.Errorf("status: %v", err)
+		}
+		if !resp.OK || !data.Running || !data.Restarting {
+			t.Errorf("status = %+v, want running and restarting", data)
+		}
+
+		resp = d.handleRequest(ipc.Request{Cmd: "console"})
+		if resp.OK || !strings.Contains(resp.Error, "restarting") {
+			t.Errorf("console = %+v, want a restarting error", resp)
+		}
+
+		if resp := d.handleRequest(ipc.Request{Cmd: "clear", Target: "all"}); !resp.OK {
+			t.Errorf("clear = %+v, want OK", resp)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("read-only commands waited for the browser")
+	}
+}
@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// handleThrottle handles the "throttle" command: it reports or sets the CPU
+// slowdown applied to every tab.
+func (d *Daemon) handleThrottle(req ipc.Request) ipc.Response {
+	var params ipc.ThrottleParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid throttle parameters: %v", err))
+	}
+
+	switch params.Action {
+	case "status", "":
+		return ipc.SuccessResponse(ipc.ThrottleData{CPU: d.cpuRate()})
+	case "cpu":
+		if params.Rate < 1 {
+			return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid CPU throttling rate %g (use 1 or more)", params.Rate))
+		}
+		if ok, resp := d.requireBrowser(); !ok {
+			return resp
+		}
+		return d.setCPUThrottle(params.Rate)
+	default:
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("unknown throttle action: %s", params.Action))
+	}
+}
+
+// setCPUThrottle slows the CPU of every tab by rate, or restores it at 1.
+// Tabs opened later follow the setting (see enableDomainsForSession).
+func (d *Daemon) setCPUThrottle(rate float64) ipc.Response {
+	d.cpuThrottleMu.Lock()
+	d.cpuThrottle = rate
+	d.cpuThrottleMu.Unlock()
+
+	for _, s := range d.sessions.All() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err := d.sendToSession(ctx, s.ID, "Emulation.setCPUThrottlingRate", map[string]any{"rate": rate})
+		cancel()
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to throttle the CPU: %v", err))
+		}
+	}
+	d.log.Info("cpu throttled", "rate", rate)
+	return ipc.SuccessResponse(ipc.ThrottleData{CPU: rate})
+}
+
+// cpuRate returns the CPU slowdown applied to every tab, 1 when none.
+func (d *Daemon) cpuRate() float64 {
+	d.cpuThrottleMu.Lock()
+	defer d.cpuThrottleMu.Unlock()
+	if d.cpuThrottle < 1 {
+		return 1
+	}
+	return d.cpuThrottle
+}
+
+// cpuThrottled returns the CPU slowdown for reports, 0 when none so it is
+// left out of them.
+func (d *Daemon) cpuThrottled() float64 {
+	if rate := d.cpuRate(); rate > 1 {
+		return rate
+	}
+	return 0
+}
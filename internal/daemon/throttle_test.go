@@ -0,0 +1,55 @@
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestSetCPUThrottle(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+	d.sessions.Add("BBB2", "T2", "https://two.test/", "Two")
+
+	if d.cpuRate() != 1 || d.cpuThrottled() != 0 {
+		t.Errorf("default rate = %v, throttled = %v", d.cpuRate(), d.cpuThrottled())
+	}
+	if resp := d.setCPUThrottle(4); !resp.OK {
+		t.Fatalf("setCPUThrottle(4) = %+v", resp)
+	}
+	if d.cpuThrottled() != 4 {
+		t.Errorf("cpuThrottled() = %v, want 4", d.cpuThrottled())
+	}
+	var tabs []string
+	for _, req := range conn.getCapturedRequests() {
+		if req.Method != "Emulation.setCPUThrottlingRate" {
+			continue
+		}
+		if params, _ := req.Params.(map[string]any); params["rate"] != float64(4) {
+			t.Errorf("params = %v", req.Params)
+		}
+		tabs = append(tabs, req.SessionID)
+	}
+	if len(tabs) != 2 {
+		t.Errorf("CPU throttled in %v, want both tabs", tabs)
+	}
+
+	if resp := d.setCPUThrottle(1); !resp.OK || d.cpuThrottled() != 0 {
+		t.Errorf("setCPUThrottle(1) = %+v, throttled = %v", resp, d.cpuThrottled())
+	}
+}
+
+func TestHandleThrottle_InvalidRate(t *testing.T) {
+	d := New(DefaultConfig())
+	raw, _ := json.Marshal(ipc.ThrottleParams{Action: "cpu", Rate: 0.5})
+	resp := d.handleThrottle(ipc.Request{Cmd: "throttle", Params: raw})
+	if resp.OK || resp.Code != ipc.CodeInvalidArgs {
+		t.Errorf("rate 0.5 = %+v", resp)
+	}
+}
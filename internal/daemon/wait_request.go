@@ -0,0 +1,97 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// handleWaitRequest blocks until a request in the active tab matching the URL
+// regex and status range completes, and returns its network entry. Requests
+// that had already completed when the wait began do not count; ones still in
+// flight do.
+func (d *Daemon) handleWaitRequest(req ipc.Request) ipc.Response {
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.WaitRequestParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid wait-request parameters: %v", err))
+	}
+	urlRegex, err := regexp.Compile(params.URL)
+	if err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid url pattern: %v", err))
+	}
+
+	timeout := cdp.DefaultTimeout
+	if params.Timeout > 0 {
+		timeout = time.Duration(params.Timeout) * time.Second
+	}
+
+	// Network entries only exist once the domain is enabled for the tab.
+	if err := d.ensureNetworkEnabled(activeID); err != nil {
+		return ipc.ErrorResponse(err.Error())
+	}
+
+	completed := map[uint64]bool{}
+	for _, e := range d.networkBuf.All() {
+		if requestCompleted(e) {
+			completed[e.Seq] = true
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ipc.CodedErrorResponse(ipc.CodeTimeout, fmt.Sprintf("timeout waiting for request matching: %s", params.URL))
+		case <-ticker.C:
+			if e, ok := matchWaitRequest(d.networkBuf.All(), activeID, urlRegex, params, completed); ok {
+				return ipc.SuccessResponse(ipc.WaitRequestData{Entry: e})
+			}
+		}
+	}
+}
+
+// requestCompleted reports whether a request has its response or has failed,
+// the same test network idle uses.
+func requestCompleted(e ipc.NetworkEntry) bool {
+	return e.ResponseTime > 0 || e.Failed
+}
+
+// matchWaitRequest returns the first entry of session that completed after
+// the wait began and matches the URL regex and status range. A failed request
+// has no status, so it only matches when no range is given.
+func matchWaitRequest(entries []ipc.NetworkEntry, sessionID string, urlRegex *regexp.Regexp, params ipc.WaitRequestParams, completed map[uint64]bool) (ipc.NetworkEntry, bool) {
+	for _, e := range entries {
+		if e.SessionID != sessionID || completed[e.Seq] || !requestCompleted(e) {
+			continue
+		}
+		if !urlRegex.MatchString(e.URL) {
+			continue
+		}
+		if params.StatusMin != 0 || params.StatusMax != 0 {
+			if e.Failed || e.Status < params.StatusMin || e.Status > params.StatusMax {
+				continue
+			}
+		}
+		return e, true
+	}
+	return ipc.NetworkEntry{}, false
+}
@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestMatchWaitRequest(t *testing.T) {
+	entries := []ipc.NetworkEntry{
+		{Seq: 1, SessionID: "s1", URL: "https://app.test/api/save", Status: 200, ResponseTime: 1},
+		{Seq: 2, SessionID: "s2", URL: "https://app.test/api/save", Status: 200, ResponseTime: 1},
+		{Seq: 3, SessionID: "s1", URL: "https://app.test/api/save"},
+		{Seq: 4, SessionID: "s1", URL: "https://app.test/api/save", Failed: true, Error: "net::ERR_FAILED", ResponseTime: 1},
+		{Seq: 5, SessionID: "s1", URL: "https://app.test/api/save", Status: 500, ResponseTime: 1},
+		{Seq: 6, SessionID: "s1", URL: "https://app.test/api/save", Status: 201, ResponseTime: 1},
+		{Seq: 7, SessionID: "s1", URL: "https://app.test/api/load", Status: 200, ResponseTime: 1},
+	}
+	completed := map[uint64]bool{1: true}
+	save := regexp.MustCompile("/api/save")
+
+	tests := []struct {
+		name    string
+		url     *regexp.Regexp
+		params  ipc.WaitRequestParams
+		wantSeq uint64
+		wantOK  bool
+	}{
+		{"any status takes the failure", save, ipc.WaitRequestParams{}, 4, true},
+		{"2xx skips failure and 500", save, ipc.WaitRequestParams{StatusMin: 200, StatusMax: 299}, 6, true},
+		{"exact status", save, ipc.WaitRequestParams{StatusMin: 500, StatusMax: 500}, 5, true},
+		{"other url", regexp.MustCompile("/api/load$"), ipc.WaitRequestParams{}, 7, true},
+		{"no match", save, ipc.WaitRequestParams{StatusMin: 404, StatusMax: 404}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := matchWaitRequest(entries, "s1", tt.url, tt.params, completed)
+			if ok != tt.wantOK || e.Seq != tt.wantSeq {
+				t.Errorf("got seq %d ok %v, want seq %d ok %v", e.Seq, ok, tt.wantSeq, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+// Zoom factors match the range of Chrome's own page zoom.
+const (
+	MinZoom = 0.25
+	MaxZoom = 5.0
+)
+
+// zoomJS sets CSS zoom on the root element, which lays the page out and
+// renders it as the browser's page zoom does. Installed as a new-document
+// script it runs before the root element exists, so it waits for it. Format
+// arg: zoom factor.
+const zoomJS = `(() => {
+	const zoom = %g;
+	const apply = () => {
+		document.documentElement.style.zoom = zoom === 1 ? '' : String(zoom);
+	};
+	if (document.documentElement) {
+		apply();
+		return;
+	}
+	new MutationObserver((_, o) => {
+		if (document.documentElement) {
+			o.disconnect();
+			apply();
+		}
+	}).observe(document, {childList: true});
+})()`
+
+// handleZoom handles the "zoom" command. A factor zooms the active tab now
+// and on every later load in it, until changed; without one, it reports the
+// current zoom.
+func (d *Daemon) handleZoom(req ipc.Request) ipc.Response {
+	// Check if browser is connected (fail-fast if not)
+	if ok, resp := d.requireBrowser(); !ok {
+		return resp
+	}
+
+	activeID := d.sessions.ActiveID()
+	if activeID == "" {
+		return d.noActiveSessionError()
+	}
+
+	var params ipc.ZoomParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("invalid zoom parameters: %v", err))
+	}
+	return d.zoom(activeID, params.Factor)
+}
+
+// zoom sets a session's zoom factor, or reports it for a zero factor.
+func (d *Daemon) zoom(sessionID string, factor float64) ipc.Response {
+	if factor == 0 {
+		return ipc.SuccessResponse(ipc.ZoomData{Factor: d.sessions.Zoom(sessionID)})
+	}
+	if factor < MinZoom || factor > MaxZoom {
+		return ipc.CodedErrorResponse(ipc.CodeInvalidArgs, fmt.Sprintf("zoom %g out of range (%g to %g)", factor, MinZoom, MaxZoom))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	js := fmt.Sprintf(zoomJS, factor)
+	scriptID := ""
+	if factor != 1 {
+		result, err := d.sendToSession(ctx, sessionID, "Page.addScriptToEvaluateOnNewDocument", map[string]any{
+			"source": js,
+		})
+		if err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to set zoom: %v", err))
+		}
+		var resp struct {
+			Identifier string `json:"identifier"`
+		}
+		if err := json.Unmarshal(result, &resp); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("failed to parse zoom script: %v", err))
+		}
+		scriptID = resp.Identifier
+	}
+	if previous := d.sessions.SetZoom(sessionID, factor, scriptID); previous != "" {
+		if _, err := d.sendToSession(ctx, sessionID, "Page.removeScriptToEvaluateOnNewDocument", map[string]any{
+			"identifier": previous,
+		}); err != nil {
+			d.log.Debug("zoom: failed to remove previous script", "error", err)
+		}
+	}
+
+	if _, err := d.sendToSession(ctx, sessionID, "Runtime.evaluate", map[string]any{
+		"expression": js,
+	}); err != nil {
+		return ipc.ErrorResponse(fmt.Sprintf("failed to set zoom: %v", err))
+	}
+	return ipc.SuccessResponse(ipc.ZoomData{Factor: factor})
+}
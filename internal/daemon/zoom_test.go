@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/webctl/internal/cdp"
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
+
+func TestZoom(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+
+	resp := d.zoom("AAA1", 1.5)
+	if !resp.OK {
+		t.Fatalf("zoom 1.5: %+v", resp)
+	}
+	reqs := conn.getCapturedRequests()
+	if len(reqs) != 2 || reqs[0].Method != "Page.addScriptToEvaluateOnNewDocument" || reqs[1].Method != "Runtime.evaluate" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	params, _ := reqs[1].Params.(map[string]any)
+	if expr, _ := params["expression"].(string); !strings.Contains(expr, "const zoom = 1.5;") {
+		t.Errorf("expression = %v", params["expression"])
+	}
+	if got := d.sessions.Zoom("AAA1"); got != 1.5 {
+		t.Errorf("Zoom() = %v, want 1.5", got)
+	}
+
+	// Resetting removes the script that re-applies the zoom and adds none
+	d.sessions.SetZoom("AAA1", 1.5, "7")
+	resp = d.zoom("AAA1", 1)
+	if !resp.OK {
+		t.Fatalf("zoom 1: %+v", resp)
+	}
+	reqs = conn.getCapturedRequests()[2:]
+	if len(reqs) != 2 || reqs[0].Method != "Page.removeScriptToEvaluateOnNewDocument" || reqs[1].Method != "Runtime.evaluate" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	if params, _ := reqs[0].Params.(map[string]any); params["identifier"] != "7" {
+		t.Errorf("removed script params = %v", reqs[0].Params)
+	}
+
+	resp = d.zoom("AAA1", 0)
+	var data ipc.ZoomData
+	if err := json.Unmarshal(resp.Data, &data); err != nil || data.Factor != 1 {
+		t.Errorf("zoom 0 = %s, %v", resp.Data, err)
+	}
+}
+
+func TestZoom_OutOfRange(t *testing.T) {
+	d := New(DefaultConfig())
+	conn := newSessionCapturingMockConn()
+	client := cdp.NewClient(conn)
+	defer func() { _ = client.Close() }()
+	d.cdp = client
+	d.sessions.Add("AAA1", "T1", "https://one.test/", "One")
+
+	for _, factor := range []float64{0.1, 6, -1} {
+		if resp := d.zoom("AAA1", factor); resp.OK || resp.Code != ipc.CodeInvalidArgs {
+			t.Errorf("zoom %v: response = %+v, want %s", factor, resp, ipc.CodeInvalidArgs)
+		}
+	}
+	if reqs := conn.getCapturedRequests(); len(reqs) != 0 {
+		t.Errorf("invalid requests reached the browser: %v", reqs)
+	}
+}
@@ -0,0 +1,67 @@
+package daemonlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// NewLogger returns a logger writing JSON lines to w, recording entries at
+// level and above.
+func NewLogger(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// Entry is one parsed log line.
+type Entry struct {
+	Time  time.Time
+	Level slog.Level
+	Msg   string
+	// Attrs holds every field other than time, level, and msg.
+	Attrs map[string]any
+}
+
+// ParseEntry parses a JSON log line written by a NewLogger logger.
+func ParseEntry(line []byte) (Entry, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return Entry{}, err
+	}
+
+	var e Entry
+	if s, ok := raw[slog.TimeKey].(string); ok {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return Entry{}, fmt.Errorf("invalid time: %w", err)
+		}
+		e.Time = t
+	}
+	if s, ok := raw[slog.LevelKey].(string); ok {
+		level, err := ParseLevel(s)
+		if err != nil {
+			return Entry{}, err
+		}
+		e.Level = level
+	}
+	e.Msg, _ = raw[slog.MessageKey].(string)
+
+	delete(raw, slog.TimeKey)
+	delete(raw, slog.LevelKey)
+	delete(raw, slog.MessageKey)
+	if len(raw) > 0 {
+		e.Attrs = raw
+	}
+	return e, nil
+}
+
+// ParseLevel parses a level name (debug, info, warn, error), case-insensitively.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.ToUpper(s))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (use debug, info, warn, or error)", s)
+	}
+	return level, nil
+}
@@ -0,0 +1,58 @@
+package daemonlog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestNewLogger_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, slog.LevelInfo)
+	logger.Debug("dropped")
+	logger.Warn("browser slow", "port", 9222, "url", "https://example.com")
+
+	e, err := ParseEntry(bytes.TrimSpace(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ParseEntry: %v (line %q)", err, buf.String())
+	}
+	if e.Level != slog.LevelWarn || e.Msg != "browser slow" {
+		t.Errorf("got level=%v msg=%q", e.Level, e.Msg)
+	}
+	if e.Time.IsZero() {
+		t.Error("expected time to be parsed")
+	}
+	if e.Attrs["port"] != float64(9222) || e.Attrs["url"] != "https://example.com" {
+		t.Errorf("unexpected attrs: %v", e.Attrs)
+	}
+}
+
+func TestParseEntry_Invalid(t *testing.T) {
+	for _, line := range []string{
+		`not json`,
+		`{"time":"yesterday","level":"INFO","msg":"x"}`,
+		`{"level":"LOUD","msg":"x"}`,
+	} {
+		if _, err := ParseEntry([]byte(line)); err == nil {
+			t.Errorf("ParseEntry(%s): expected error", line)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"Warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+	for in, want := range tests {
+		got, err := ParseLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
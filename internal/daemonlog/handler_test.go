@@ -0,0 +1,58 @@
+package daemonlog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewHandler_Formats(t *testing.T) {
+	var text, js bytes.Buffer
+	th, err := NewHandler(&text, "text", slog.LevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jh, err := NewHandler(&js, "JSON", slog.LevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(th).Info("ready", "port", 9222)
+	slog.New(jh).Info("ready", "port", 9222)
+
+	if !strings.Contains(text.String(), "msg=ready port=9222") {
+		t.Errorf("text output = %q", text.String())
+	}
+	if !strings.Contains(js.String(), `"msg":"ready","port":9222`) {
+		t.Errorf("json output = %q", js.String())
+	}
+
+	if _, err := NewHandler(&text, "xml", slog.LevelInfo); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestTee_RespectsEachLevel(t *testing.T) {
+	var debug, warn bytes.Buffer
+	logger := slog.New(Tee(
+		slog.NewTextHandler(&debug, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		nil,
+		slog.NewTextHandler(&warn, &slog.HandlerOptions{Level: slog.LevelWarn}),
+	)).With("component", "cdp")
+
+	logger.Debug("detail")
+	logger.Warn("problem")
+
+	if got := debug.String(); !strings.Contains(got, "detail") || !strings.Contains(got, "problem") {
+		t.Errorf("debug handler = %q, want both records", got)
+	}
+	if got := warn.String(); strings.Contains(got, "detail") || !strings.Contains(got, "msg=problem component=cdp") {
+		t.Errorf("warn handler = %q, want only the warning with attrs", got)
+	}
+}
+
+func TestTee_Empty(t *testing.T) {
+	if slog.New(Tee()).Enabled(t.Context(), slog.LevelError) {
+		t.Error("empty tee should not be enabled")
+	}
+}
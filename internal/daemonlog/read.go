@@ -0,0 +1,128 @@
+package daemonlog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// ReadAll calls fn for each line in the rotated files for path, oldest first,
+// and then in path itself. Missing files are skipped. It returns the number of
+// bytes read from path, for passing to Follow.
+func ReadAll(path string, fn func(line []byte) error) (int64, error) {
+	backups := Backups(path)
+	for i := len(backups) - 1; i >= 0; i-- {
+		if _, err := readFile(backups[i], fn); err != nil {
+			return 0, err
+		}
+	}
+	return readFile(path, fn)
+}
+
+// readFile calls fn for each complete line in name and returns the number of
+// bytes consumed. A missing file reads as empty.
+func readFile(name string, fn func(line []byte) error) (int64, error) {
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+	return readLines(bufio.NewReader(f), fn)
+}
+
+// readLines calls fn for each newline-terminated line in r, without the
+// newline. A trailing partial line is left unread; the returned count covers
+// only complete lines.
+func readLines(r *bufio.Reader, fn func(line []byte) error) (int64, error) {
+	var n int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n += int64(len(line))
+		if line = bytes.TrimRight(line, "\r\n"); len(line) > 0 {
+			if err := fn(line); err != nil {
+				return n, err
+			}
+		}
+	}
+}
+
+// Follow calls fn for each line appended to path after offset, polling every
+// interval until ctx is done. When the file is rotated or truncated it
+// continues from the start of the new file.
+func Follow(ctx context.Context, path string, offset int64, interval time.Duration, fn func(line []byte) error) error {
+	var (
+		f    *os.File
+		info os.FileInfo
+	)
+	defer func() {
+		if f != nil {
+			_ = f.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// drain reads whatever has been appended to f since offset.
+	drain := func() error {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		n, err := readLines(bufio.NewReader(f), fn)
+		offset += n
+		return err
+	}
+
+	for {
+		// (Re)open when the file appears, is replaced by rotation, or shrinks.
+		// Lines written to a rotated-out file before it was replaced are read
+		// first so none are lost.
+		if cur, err := os.Stat(path); err == nil {
+			if f != nil && (!os.SameFile(info, cur) || cur.Size() < offset) {
+				if !os.SameFile(info, cur) {
+					if err := drain(); err != nil {
+						return err
+					}
+				}
+				_ = f.Close()
+				f = nil
+				offset = 0
+			}
+			if f == nil {
+				if f, err = os.Open(path); err != nil {
+					return err
+				}
+				if info, err = f.Stat(); err != nil {
+					return err
+				}
+				if offset > info.Size() {
+					offset = 0
+				}
+			}
+		}
+
+		if f != nil {
+			if err := drain(); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package daemonlog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadAll_OldestFirstWithOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	files := map[string]string{
+		path + ".2": "one\n",
+		path + ".1": "two\n\n",
+		path:        "three\npartial",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	offset, err := ReadAll(path, func(line []byte) error {
+		got = append(got, string(line))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"one", "two", "three"}; !slices.Equal(got, want) {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+	if offset != int64(len("three\n")) {
+		t.Errorf("offset = %d, want %d", offset, len("three\n"))
+	}
+}
+
+func TestReadAll_MissingFile(t *testing.T) {
+	offset, err := ReadAll(filepath.Join(t.TempDir(), "daemon.log"), func([]byte) error {
+		t.Error("unexpected line")
+		return nil
+	})
+	if err != nil || offset != 0 {
+		t.Errorf("ReadAll = %d, %v; want 0, nil", offset, err)
+	}
+}
+
+func TestFollow_AppendsAndRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	r, err := Open(path, Options{MaxSize: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+	_, _ = r.Write([]byte("before\n"))
+
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, path, int64(len("before\n")), 5*time.Millisecond, func(line []byte) error {
+			mu.Lock()
+			got = append(got, string(line))
+			mu.Unlock()
+			return nil
+		})
+	}()
+
+	// The third write rotates the file; all three must still be seen once.
+	for _, s := range []string{"first\n", "second\n", "third\n"} {
+		_, _ = r.Write([]byte(s))
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	want := []string{"first", "second", "third"}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		ok := slices.Equal(got, want)
+		snapshot := slices.Clone(got)
+		mu.Unlock()
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("followed lines = %q, want %q", snapshot, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Follow: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+// Package daemonlog provides the daemon's logging: slog handlers, a size- and
+// age-rotated JSON-lines log file, and helpers for reading it back.
+package daemonlog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default rotation limits.
+const (
+	DefaultMaxSize    = 10 * 1024 * 1024
+	DefaultMaxAge     = 7 * 24 * time.Hour
+	DefaultMaxBackups = 3
+)
+
+// DefaultPath returns the XDG-compliant daemon log path:
+// $XDG_STATE_HOME/webctl/daemon.log, falling back to
+// ~/.local/state/webctl/daemon.log.
+func DefaultPath() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join(os.TempDir(), fmt.Sprintf("webctl-%d", os.Getuid()), "daemon.log")
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateHome, "webctl", "daemon.log")
+}
+
+// Options controls when a RotatingFile rotates and how many old files it keeps.
+// Zero values select the defaults; a negative MaxAge disables age rotation.
+type Options struct {
+	// MaxSize is the size in bytes at which the current file is rotated.
+	MaxSize int64
+	// MaxAge is how long a file is written before it is rotated, and how long
+	// rotated files are kept.
+	MaxAge time.Duration
+	// MaxBackups is how many rotated files (path.1, path.2, ...) are kept.
+	MaxBackups int
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxSize <= 0 {
+		o.MaxSize = DefaultMaxSize
+	}
+	if o.MaxAge == 0 {
+		o.MaxAge = DefaultMaxAge
+	}
+	if o.MaxBackups <= 0 {
+		o.MaxBackups = DefaultMaxBackups
+	}
+	return o
+}
+
+// RotatingFile is an append-only log file that rotates to path.1 (shifting
+// older files up to path.N) once it would exceed MaxSize or has been written
+// for longer than MaxAge. It is safe for concurrent use.
+type RotatingFile struct {
+	path string
+	opts Options
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	started time.Time
+
+	// now is the clock, replaceable for testing.
+	now func() time.Time
+}
+
+// Open opens (or creates) the log file at path for appending. An existing
+// file last written more than MaxAge ago is rotated out first.
+func Open(path string, opts Options) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, opts: opts.withDefaults(), now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && r.opts.MaxAge > 0 && r.now().Sub(info.ModTime()) > r.opts.MaxAge {
+		if err := r.shift(); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Path returns the path of the current log file.
+func (r *RotatingFile) Path() string {
+	return r.path
+}
+
+// Write appends p to the log, rotating first if p would push the file past
+// MaxSize or the file has aged past MaxAge. A single write is never split
+// across files.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	tooBig := r.size > 0 && r.size+int64(len(p)) > r.opts.MaxSize
+	tooOld := r.opts.MaxAge > 0 && r.now().Sub(r.started) > r.opts.MaxAge
+	if tooBig || tooOld {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// open opens r.path for appending and records its current size.
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	r.started = r.now()
+	return nil
+}
+
+// rotate closes the current file, shifts it to path.1, and starts a new one.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+	if err := r.shift(); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// shift renames path.N-1 -> path.N down to path -> path.1, dropping the
+// oldest, then removes any backups older than MaxAge.
+func (r *RotatingFile) shift() error {
+	_ = os.Remove(backupName(r.path, r.opts.MaxBackups))
+	for i := r.opts.MaxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backupName(r.path, i), backupName(r.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(r.path, backupName(r.path, 1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if r.opts.MaxAge > 0 {
+		for _, name := range Backups(r.path) {
+			if info, err := os.Stat(name); err == nil && r.now().Sub(info.ModTime()) > r.opts.MaxAge {
+				_ = os.Remove(name)
+			}
+		}
+	}
+	return nil
+}
+
+// backupName returns the name of the nth rotated file.
+func backupName(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
+
+// Backups returns the rotated files that exist for path, newest first.
+func Backups(path string) []string {
+	matches, _ := filepath.Glob(path + ".*")
+	type backup struct {
+		name string
+		n    int
+	}
+	var found []backup
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(m, path+"."))
+		if err != nil || n < 1 {
+			continue
+		}
+		found = append(found, backup{m, n})
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].n < found[j].n })
+
+	names := make([]string, len(found))
+	for i, b := range found {
+		names[i] = b.name
+	}
+	return names
+}
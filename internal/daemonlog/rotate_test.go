@@ -0,0 +1,112 @@
+package daemonlog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile_RotatesOnSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	r, err := Open(path, Options{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+
+	for _, s := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		if _, err := r.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]string{
+		path:        "dddddd\n",
+		path + ".1": "cccccc\n",
+		path + ".2": "bbbbbb\n",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), got, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups, found %s.3", path)
+	}
+}
+
+func TestRotatingFile_RotatesOnAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	r, err := Open(path, Options{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	_, _ = r.Write([]byte("old\n"))
+
+	// Written recently, but started over MaxAge ago: rotated, not pruned.
+	now = now.Add(2 * time.Hour)
+	recent := now.Add(-time.Minute)
+	if err := os.Chtimes(path, recent, recent); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = r.Write([]byte("new\n"))
+
+	if got, _ := os.ReadFile(path); string(got) != "new\n" {
+		t.Errorf("current = %q, want %q", got, "new\n")
+	}
+	if got, _ := os.ReadFile(path + ".1"); string(got) != "old\n" {
+		t.Errorf("backup = %q, want %q", got, "old\n")
+	}
+}
+
+func TestOpen_RotatesStaleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	if err := os.WriteFile(path, []byte("stale\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Open(path, Options{MaxAge: 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = r.Close()
+
+	// The stale file is shifted to .1 and then pruned as older than MaxAge.
+	if got, _ := os.ReadFile(path); len(got) != 0 {
+		t.Errorf("current = %q, want empty", got)
+	}
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("expected stale backup to be pruned")
+	}
+}
+
+func TestBackups_NewestFirst(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	for _, suffix := range []string{".10", ".2", ".1", ".bak"} {
+		if err := os.WriteFile(path+suffix, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	for _, name := range Backups(path) {
+		got = append(got, strings.TrimPrefix(name, path))
+	}
+	if want := ".1 .2 .10"; strings.Join(got, " ") != want {
+		t.Errorf("Backups = %v, want %s", got, want)
+	}
+}
@@ -1,6 +1,11 @@
 package executor
 
-import "github.com/grantcarthew/webctl/internal/ipc"
+import (
+	"fmt"
+	"io"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
 
 // DirectExecutor executes commands by calling the handler directly.
 // Used by the REPL to avoid IPC round-trip.
@@ -18,6 +23,20 @@ func (e *DirectExecutor) Execute(req ipc.Request) (ipc.Response, error) {
 	return e.handler(req), nil
 }
 
+// ExecuteStream calls the handler with Stream set and writes a streamed body
+// to w.
+func (e *DirectExecutor) ExecuteStream(req ipc.Request, w io.Writer) (ipc.Response, error) {
+	req.Stream = true
+	resp := e.handler(req)
+	if resp.Body != nil {
+		if err := resp.Body(w); err != nil {
+			return ipc.ErrorResponse(fmt.Sprintf("stream failed: %v", err)), nil
+		}
+		resp.Body = nil
+	}
+	return resp, nil
+}
+
 // Close is a no-op for direct executor.
 func (e *DirectExecutor) Close() error {
 	return nil
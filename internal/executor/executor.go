@@ -1,6 +1,10 @@
 package executor
 
-import "github.com/grantcarthew/webctl/internal/ipc"
+import (
+	"io"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
 
 // Executor executes commands and returns responses.
 // Implementations handle the transport mechanism (IPC, TCP, direct call).
@@ -8,3 +12,10 @@ type Executor interface {
 	Execute(req ipc.Request) (ipc.Response, error)
 	Close() error
 }
+
+// StreamExecutor is implemented by executors that can deliver a command's body
+// as a stream (see ipc.Request.Stream), writing it to w as it arrives instead
+// of holding it in the response.
+type StreamExecutor interface {
+	ExecuteStream(req ipc.Request, w io.Writer) (ipc.Response, error)
+}
@@ -1,7 +1,9 @@
 package executor
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"testing"
 
 	"github.com/grantcarthew/webctl/internal/ipc"
@@ -93,3 +95,27 @@ func TestDirectExecutor_HandlerReceivesParams(t *testing.T) {
 		t.Errorf("handler received params %s, want %s", receivedParams, paramsJSON)
 	}
 }
+
+func TestDirectExecutor_ExecuteStream(t *testing.T) {
+	exec := NewDirectExecutor(func(req ipc.Request) ipc.Response {
+		if !req.Stream {
+			t.Error("handler received a request without Stream set")
+		}
+		return ipc.StreamResponse(func(w io.Writer) error {
+			_, err := io.WriteString(w, "body")
+			return err
+		})
+	})
+
+	var buf bytes.Buffer
+	resp, err := exec.ExecuteStream(ipc.Request{Cmd: "screenshot"}, &buf)
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	if !resp.OK || resp.Body != nil {
+		t.Errorf("ExecuteStream() = %+v, want OK with the body consumed", resp)
+	}
+	if buf.String() != "body" {
+		t.Errorf("streamed body = %q, want %q", buf.String(), "body")
+	}
+}
@@ -1,6 +1,10 @@
 package executor
 
-import "github.com/grantcarthew/webctl/internal/ipc"
+import (
+	"io"
+
+	"github.com/grantcarthew/webctl/internal/ipc"
+)
 
 // IPCExecutor executes commands via Unix socket IPC.
 type IPCExecutor struct {
@@ -42,6 +46,13 @@ func (e *IPCExecutor) Execute(req ipc.Request) (ipc.Response, error) {
 	return e.client.Send(req)
 }
 
+// ExecuteStream sends a request asking for its body to be streamed, writing
+// the body to w.
+func (e *IPCExecutor) ExecuteStream(req ipc.Request, w io.Writer) (ipc.Response, error) {
+	req.Debug = e.debug
+	return e.client.SendStream(req, w)
+}
+
 // Close closes the IPC connection.
 func (e *IPCExecutor) Close() error {
 	return e.client.Close()
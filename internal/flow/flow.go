@@ -0,0 +1,376 @@
+// Package flow loads webctl flow files: YAML lists of named browser steps
+// (navigate, wait, click, fill, key, assert, screenshot) with variables and
+// per-step retries, run by 'webctl flow run'.
+package flow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultTimeout bounds each step when neither the step nor the flow sets a
+// timeout.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultRetryDelay is the pause between attempts of a step with retries.
+const DefaultRetryDelay = 500 * time.Millisecond
+
+// Flow is a parsed flow file.
+type Flow struct {
+	// Name identifies the flow in the report; the file name when unset.
+	Name string `yaml:"name,omitempty"`
+	// Vars are the flow's variables, referenced in steps as ${name}.
+	Vars map[string]string `yaml:"vars,omitempty"`
+	// Timeout is the default timeout of each step.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Steps run in order; the first failure skips the rest.
+	Steps []Step `yaml:"steps"`
+}
+
+// Step is one action with its options. Exactly one action field is set.
+type Step struct {
+	Name string `yaml:"name,omitempty"`
+
+	Navigate   string      `yaml:"navigate,omitempty"`
+	Wait       *Wait       `yaml:"wait,omitempty"`
+	Click      string      `yaml:"click,omitempty"`
+	Fill       *Fill       `yaml:"fill,omitempty"`
+	Key        string      `yaml:"key,omitempty"`
+	Assert     *Assert     `yaml:"assert,omitempty"`
+	Screenshot *Screenshot `yaml:"screenshot,omitempty"`
+
+	// Retries is how many more times a failed step is attempted.
+	Retries int `yaml:"retries,omitempty"`
+	// Delay is the pause between attempts (default DefaultRetryDelay).
+	Delay time.Duration `yaml:"delay,omitempty"`
+	// Timeout overrides the flow's timeout for this step.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// Wait waits for a selector, network idle, or a truthy expression, as
+// 'webctl ready' does. A plain string is a selector.
+type Wait struct {
+	Selector    string `yaml:"selector,omitempty"`
+	NetworkIdle bool   `yaml:"network-idle,omitempty"`
+	Eval        string `yaml:"eval,omitempty"`
+}
+
+// Fill replaces the value of an input with text. Secret text is left out of
+// --debug output.
+type Fill struct {
+	Selector string `yaml:"selector"`
+	Text     string `yaml:"text"`
+	Secret   bool   `yaml:"secret,omitempty"`
+}
+
+// Assert checks the page. Selector alone requires a matching element, and
+// with Text, that its text contains Text. URL and Title are substrings of
+// the page's. Eval must be truthy. A plain string is an Eval expression.
+type Assert struct {
+	Selector string `yaml:"selector,omitempty"`
+	Text     string `yaml:"text,omitempty"`
+	URL      string `yaml:"url,omitempty"`
+	Title    string `yaml:"title,omitempty"`
+	Eval     string `yaml:"eval,omitempty"`
+}
+
+// Screenshot saves a PNG in the step's artifact directory. A plain string is
+// the file name.
+type Screenshot struct {
+	Path     string `yaml:"path,omitempty"`
+	FullPage bool   `yaml:"full-page,omitempty"`
+}
+
+// UnmarshalYAML accepts a selector string for a wait.
+func (w *Wait) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		w.Selector = n.Value
+		return nil
+	}
+	type plain Wait
+	return decodeKnown(n, (*plain)(w))
+}
+
+// UnmarshalYAML accepts an expression string for an assert.
+func (a *Assert) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		a.Eval = n.Value
+		return nil
+	}
+	type plain Assert
+	return decodeKnown(n, (*plain)(a))
+}
+
+// UnmarshalYAML accepts a file name string for a screenshot.
+func (s *Screenshot) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		s.Path = n.Value
+		return nil
+	}
+	type plain Screenshot
+	return decodeKnown(n, (*plain)(s))
+}
+
+// decodeKnown decodes n into v, rejecting keys v does not have, which a
+// node's Decode does not do on its own.
+func decodeKnown(n *yaml.Node, v any) error {
+	data, err := yaml.Marshal(n)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	return dec.Decode(v)
+}
+
+// Action returns the name of the step's action.
+func (s Step) Action() string {
+	switch {
+	case s.Navigate != "":
+		return "navigate"
+	case s.Wait != nil:
+		return "wait"
+	case s.Click != "":
+		return "click"
+	case s.Fill != nil:
+		return "fill"
+	case s.Key != "":
+		return "key"
+	case s.Assert != nil:
+		return "assert"
+	case s.Screenshot != nil:
+		return "screenshot"
+	}
+	return ""
+}
+
+// Title is the step's name, or its action and main argument.
+func (s Step) Title() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	switch s.Action() {
+	case "navigate":
+		return "navigate " + s.Navigate
+	case "wait":
+		switch {
+		case s.Wait.Selector != "":
+			return "wait " + s.Wait.Selector
+		case s.Wait.NetworkIdle:
+			return "wait network-idle"
+		}
+		return "wait " + s.Wait.Eval
+	case "click":
+		return "click " + s.Click
+	case "fill":
+		return "fill " + s.Fill.Selector
+	case "key":
+		return "key " + s.Key
+	case "assert":
+		a := s.Assert
+		switch {
+		case a.Selector != "" && a.Text != "":
+			return fmt.Sprintf("assert %s contains %q", a.Selector, a.Text)
+		case a.Selector != "":
+			return "assert " + a.Selector
+		case a.URL != "":
+			return fmt.Sprintf("assert url contains %q", a.URL)
+		case a.Title != "":
+			return fmt.Sprintf("assert title contains %q", a.Title)
+		}
+		return "assert " + a.Eval
+	case "screenshot":
+		return "screenshot " + s.Screenshot.Path
+	}
+	return ""
+}
+
+// Load reads and parses a flow file. The flow's name defaults to the file's
+// base name without extension.
+func Load(path string) (*Flow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if f.Name == "" {
+		f.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return f, nil
+}
+
+// Parse parses and validates a flow. Unknown keys are errors, so a typo does
+// not silently drop a step's option.
+func Parse(data []byte) (*Flow, error) {
+	var f Flow
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("no steps")
+		}
+		return nil, err
+	}
+	if err := f.validate(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (f *Flow) validate() error {
+	if len(f.Steps) == 0 {
+		return errors.New("no steps")
+	}
+	if f.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	for i, s := range f.Steps {
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (s Step) validate() error {
+	actions := 0
+	for _, set := range []bool{s.Navigate != "", s.Wait != nil, s.Click != "", s.Fill != nil, s.Key != "", s.Assert != nil, s.Screenshot != nil} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return errors.New("needs exactly one of navigate, wait, click, fill, key, assert, screenshot")
+	}
+	if s.Retries < 0 || s.Delay < 0 || s.Timeout < 0 {
+		return errors.New("retries, delay, and timeout must not be negative")
+	}
+	switch {
+	case s.Wait != nil && !exactlyOne(s.Wait.Selector != "", s.Wait.NetworkIdle, s.Wait.Eval != ""):
+		return errors.New("wait needs one of selector, network-idle, eval")
+	case s.Fill != nil && s.Fill.Selector == "":
+		return errors.New("fill needs a selector")
+	case s.Assert != nil && s.Assert.Text != "" && s.Assert.Selector == "":
+		return errors.New("assert text needs a selector")
+	case s.Assert != nil && !exactlyOne(s.Assert.Selector != "", s.Assert.URL != "", s.Assert.Title != "", s.Assert.Eval != ""):
+		return errors.New("assert needs one of selector, url, title, eval")
+	case s.Screenshot != nil && s.Screenshot.Path == "":
+		return errors.New("screenshot needs a file name")
+	case s.Screenshot != nil && (filepath.IsAbs(s.Screenshot.Path) || strings.HasPrefix(filepath.Clean(s.Screenshot.Path), "..")):
+		return errors.New("screenshot file name must be relative to the artifact directory")
+	}
+	return nil
+}
+
+func exactlyOne(conds ...bool) bool {
+	n := 0
+	for _, c := range conds {
+		if c {
+			n++
+		}
+	}
+	return n == 1
+}
+
+// varPattern matches a ${name} reference.
+var varPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.-]*)\}`)
+
+// Expand returns the flow with every ${name} in its steps replaced. A name is
+// looked up in overrides, then the flow's vars, then the environment; a name
+// found in none is an error, reported for the first step that uses it.
+// Variables may refer to other variables.
+func (f *Flow) Expand(overrides map[string]string) (*Flow, error) {
+	lookup := func(name string) (string, bool) {
+		if v, ok := overrides[name]; ok {
+			return v, true
+		}
+		if v, ok := f.Vars[name]; ok {
+			return v, true
+		}
+		return os.LookupEnv(name)
+	}
+
+	var missing string
+	var expand func(s string, depth int) string
+	expand = func(s string, depth int) string {
+		return varPattern.ReplaceAllStringFunc(s, func(ref string) string {
+			name := varPattern.FindStringSubmatch(ref)[1]
+			v, ok := lookup(name)
+			if !ok {
+				if missing == "" {
+					missing = name
+				}
+				return ref
+			}
+			if depth < 8 {
+				v = expand(v, depth+1)
+			}
+			return v
+		})
+	}
+
+	out := *f
+	out.Steps = make([]Step, len(f.Steps))
+	for i, s := range f.Steps {
+		e := func(p *string) { *p = expand(*p, 0) }
+		e(&s.Name)
+		e(&s.Navigate)
+		e(&s.Click)
+		e(&s.Key)
+		if s.Wait != nil {
+			w := *s.Wait
+			e(&w.Selector)
+			e(&w.Eval)
+			s.Wait = &w
+		}
+		if s.Fill != nil {
+			fl := *s.Fill
+			e(&fl.Selector)
+			e(&fl.Text)
+			s.Fill = &fl
+		}
+		if s.Assert != nil {
+			a := *s.Assert
+			for _, p := range []*string{&a.Selector, &a.Text, &a.URL, &a.Title, &a.Eval} {
+				e(p)
+			}
+			s.Assert = &a
+		}
+		if s.Screenshot != nil {
+			sc := *s.Screenshot
+			e(&sc.Path)
+			s.Screenshot = &sc
+		}
+		if missing != "" {
+			return nil, fmt.Errorf("step %d: undefined variable ${%s}", i+1, missing)
+		}
+		out.Steps[i] = s
+	}
+	return &out, nil
+}
+
+// Marshal encodes the flow as a flow file.
+func (f *Flow) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
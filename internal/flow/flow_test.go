@@ -0,0 +1,142 @@
+package flow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `
+name: login
+vars:
+  base: http://localhost:3000
+  login: ${base}/login
+timeout: 10s
+steps:
+  - navigate: ${login}
+  - fill: {selector: "#email", text: "${user}@example.com"}
+  - key: Enter
+  - click: button[type=submit]
+    name: submit
+  - wait: .dashboard
+  - wait: {network-idle: true}
+  - assert: document.title.length > 0
+  - assert: {selector: h1, text: Welcome}
+    retries: 3
+    delay: 1s
+    timeout: 2s
+  - screenshot: home.png
+  - screenshot: {path: full.png, full-page: true}
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name != "login" || f.Timeout != 10*time.Second || len(f.Steps) != 10 {
+		t.Fatalf("parsed %+v", f)
+	}
+
+	want := []string{"navigate", "fill", "key", "click", "wait", "wait", "assert", "assert", "screenshot", "screenshot"}
+	for i, s := range f.Steps {
+		if s.Action() != want[i] {
+			t.Errorf("step %d action = %q, want %q", i+1, s.Action(), want[i])
+		}
+	}
+	if f.Steps[4].Wait.Selector != ".dashboard" || !f.Steps[5].Wait.NetworkIdle {
+		t.Errorf("wait steps = %+v, %+v", f.Steps[4].Wait, f.Steps[5].Wait)
+	}
+	if f.Steps[6].Assert.Eval != "document.title.length > 0" {
+		t.Errorf("string assert = %+v, want an eval", f.Steps[6].Assert)
+	}
+	if s := f.Steps[7]; s.Retries != 3 || s.Delay != time.Second || s.Timeout != 2*time.Second {
+		t.Errorf("step options = %+v", s)
+	}
+	if s := f.Steps[9].Screenshot; s.Path != "full.png" || !s.FullPage {
+		t.Errorf("screenshot = %+v", s)
+	}
+	if got := f.Steps[2].Title(); got != "key Enter" {
+		t.Errorf("key title = %q", got)
+	}
+	if got := f.Steps[3].Title(); got != "submit" {
+		t.Errorf("named step title = %q", got)
+	}
+	if got := f.Steps[7].Title(); got != `assert h1 contains "Welcome"` {
+		t.Errorf("assert title = %q", got)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := map[string]struct {
+		yaml string
+		want string
+	}{
+		"empty":            {"", "no steps"},
+		"no steps":         {"name: x\n", "no steps"},
+		"two actions":      {"steps:\n  - click: a\n    navigate: b\n", "step 1: needs exactly one"},
+		"no action":        {"steps:\n  - name: nothing\n", "step 1: needs exactly one"},
+		"unknown key":      {"steps:\n  - click: a\n    retry: 2\n", "retry"},
+		"unknown nested":   {"steps:\n  - assert: {selektor: a}\n", "selektor"},
+		"text without sel": {"steps:\n  - assert: {text: hi}\n", "assert text needs a selector"},
+		"two asserts":      {"steps:\n  - assert: {url: a, title: b}\n", "assert needs one of"},
+		"empty wait":       {"steps:\n  - wait: {}\n", "wait needs one of"},
+		"fill no selector": {"steps:\n  - fill: {text: hi}\n", "fill needs a selector"},
+		"escaping shot":    {"steps:\n  - screenshot: ../x.png\n", "relative to the artifact directory"},
+		"negative retries": {"steps:\n  - click: a\n    retries: -1\n", "must not be negative"},
+	}
+	for name, tt := range tests {
+		_, err := Parse([]byte(tt.yaml))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", name, err, tt.want)
+		}
+	}
+}
+
+func TestLoad_DefaultName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "smoke-test.yaml")
+	if err := os.WriteFile(path, []byte("steps:\n  - click: a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name != "smoke-test" {
+		t.Errorf("name = %q, want smoke-test", f.Name)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Setenv("user", "env-user")
+	f, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := f.Expand(map[string]string{"base": "https://staging.example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Steps[0].Navigate != "https://staging.example/login" {
+		t.Errorf("navigate = %q, want the override through a nested variable", got.Steps[0].Navigate)
+	}
+	if got.Steps[1].Fill.Text != "env-user@example.com" {
+		t.Errorf("fill text = %q, want the environment value", got.Steps[1].Fill.Text)
+	}
+	if f.Steps[0].Navigate != "${login}" || f.Steps[1].Fill.Text != "${user}@example.com" {
+		t.Error("Expand should not modify the original flow")
+	}
+}
+
+func TestExpand_Undefined(t *testing.T) {
+	f, err := Parse([]byte("steps:\n  - click: a\n  - navigate: ${webctl_flow_test_missing}/x\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Expand(nil); err == nil || err.Error() != "step 2: undefined variable ${webctl_flow_test_missing}" {
+		t.Errorf("error = %v", err)
+	}
+}
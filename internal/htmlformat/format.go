@@ -3,97 +3,175 @@ package htmlformat
 import (
 	"bytes"
 	"strings"
+	"unicode/utf8"
 
 	"golang.org/x/net/html"
 )
 
+// VoidStyle controls how void elements such as <br> and <img> are closed.
+type VoidStyle int
+
+const (
+	// VoidKeep leaves void tags exactly as they appear in the source.
+	VoidKeep VoidStyle = iota
+	// VoidHTML writes void tags without a closing slash: <br>.
+	VoidHTML
+	// VoidXHTML writes void tags self-closed with a space: <br />.
+	VoidXHTML
+)
+
+// DefaultCompactWidth is the line width used to decide whether text is short
+// enough for CompactText when MaxLineWidth is not set.
+const DefaultCompactWidth = 80
+
+// tabWidth is the number of columns a tab indent counts for when measuring
+// line width.
+const tabWidth = 4
+
+// Options configures Format output. The zero value is not usable directly;
+// start from DefaultOptions.
+type Options struct {
+	// Indent is the string written once per nesting level.
+	Indent string
+	// MaxLineWidth wraps text at word boundaries and puts one attribute per
+	// line on tags that would exceed it. Zero disables wrapping.
+	MaxLineWidth int
+	// CompactText keeps an element's text on the tag line (<p>Text</p>) when
+	// the element holds only text and the line fits. Empty elements are
+	// written as <div></div>.
+	CompactText bool
+	// VoidStyle selects how void elements are closed.
+	VoidStyle VoidStyle
+}
+
+// DefaultOptions returns the options used by Format: two-space indentation,
+// no wrapping, text on its own line, and void tags left as written.
+func DefaultOptions() Options {
+	return Options{Indent: "  "}
+}
+
 // Format formats HTML with proper indentation for readability.
 // Uses 2-space indentation and preserves content in pre/textarea tags.
 func Format(input string) (string, error) {
-	tokenizer := html.NewTokenizer(strings.NewReader(input))
-	buf := &bytes.Buffer{}
-	indentLevel := 0
-	const indent = "  " // 2 spaces
+	return FormatWithOptions(input, DefaultOptions())
+}
 
-	var rawTagStack []string // Track nested raw tags (pre, textarea)
-	prevWasText := false
-	needIndent := true
+// token is a tokenized piece of the input kept with its raw source text.
+type token struct {
+	typ  html.TokenType
+	raw  string
+	name string
+}
 
+// FormatWithOptions formats HTML like Format with configurable indentation,
+// wrapping, text placement, and void element style.
+func FormatWithOptions(input string, opts Options) (string, error) {
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+	var tokens []token
 	for {
 		tokenType := tokenizer.Next()
 		if tokenType == html.ErrorToken {
 			break
 		}
+		tok := token{typ: tokenType, raw: string(tokenizer.Raw())}
+		if tokenType == html.StartTagToken || tokenType == html.EndTagToken || tokenType == html.SelfClosingTagToken {
+			tok.name = getTagName(tokenizer)
+		}
+		tokens = append(tokens, tok)
+	}
+
+	f := &formatter{opts: opts}
+	var rawTagStack []string // Track nested raw tags (pre, textarea)
+	needIndent := true
 
-		raw := string(tokenizer.Raw())
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		raw := tok.raw
 		inRawTag := len(rawTagStack) > 0
 
-		switch tokenType {
+		switch tok.typ {
 		case html.DoctypeToken:
 			if needIndent {
-				buf.WriteString(strings.Repeat(indent, indentLevel))
+				f.writeIndent()
 			}
-			buf.WriteString(raw)
-			buf.WriteByte('\n')
+			f.buf.WriteString(raw)
+			f.buf.WriteByte('\n')
 			needIndent = true
-			prevWasText = false
 
 		case html.CommentToken:
 			if needIndent && !inRawTag {
-				buf.WriteString(strings.Repeat(indent, indentLevel))
+				f.writeIndent()
 			}
-			buf.WriteString(raw)
+			f.buf.WriteString(raw)
 			if !inRawTag {
-				buf.WriteByte('\n')
+				f.buf.WriteByte('\n')
 				needIndent = true
 			}
-			prevWasText = false
 
 		case html.StartTagToken:
-			tagName := getTagName(tokenizer)
+			tagName := tok.name
 			isRawTag := isPreformatted(tagName)
 			isVoid := isVoidElement(tagName)
 
-			if needIndent && !inRawTag {
-				buf.WriteString(strings.Repeat(indent, indentLevel))
+			if inRawTag {
+				f.buf.WriteString(raw)
+				needIndent = true
+				if isRawTag {
+					rawTagStack = append(rawTagStack, tagName)
+				}
+				continue
 			}
-			buf.WriteString(raw)
-			if !inRawTag {
-				buf.WriteByte('\n')
+
+			if isVoid {
+				raw = f.voidTag(raw)
+			}
+			if !isVoid && !isRawTag && opts.CompactText {
+				if line, skip, ok := f.compactElement(tokens, i, raw); ok {
+					f.writeIndent()
+					f.buf.WriteString(line)
+					f.buf.WriteByte('\n')
+					needIndent = true
+					i += skip
+					continue
+				}
+			}
+
+			if needIndent {
+				f.writeIndent()
 			}
+			f.writeTag(raw)
+			f.buf.WriteByte('\n')
 			needIndent = true
-			prevWasText = false
 
 			if isRawTag {
 				rawTagStack = append(rawTagStack, tagName)
 			}
 			// Only increment indentation for non-void elements
 			// Void elements have no closing tag, so incrementing would cause drift
-			if !inRawTag && !isVoid {
-				indentLevel++
+			if !isVoid {
+				f.level++
 			}
 
 		case html.EndTagToken:
-			tagName := getTagName(tokenizer)
+			tagName := tok.name
 			wasInRawTag := len(rawTagStack) > 0 && rawTagStack[len(rawTagStack)-1] == tagName
 
 			// Decrement for normal tags OR when closing a raw tag
 			// (raw tags increment when opened, so must decrement when closed)
 			if !inRawTag || wasInRawTag {
-				indentLevel--
+				f.level--
 			}
 
 			if needIndent && !inRawTag {
-				buf.WriteString(strings.Repeat(indent, indentLevel))
+				f.writeIndent()
 			}
-			buf.WriteString(raw)
+			f.buf.WriteString(raw)
 			// Add newline for normal tags OR when closing a raw tag
 			// (so the next element starts on a new line)
 			if !inRawTag || wasInRawTag {
-				buf.WriteByte('\n')
+				f.buf.WriteByte('\n')
 			}
 			needIndent = true
-			prevWasText = false
 
 			// Pop raw tag from stack
 			if wasInRawTag {
@@ -101,44 +179,225 @@ func Format(input string) (string, error) {
 			}
 
 		case html.SelfClosingTagToken:
-			if needIndent && !inRawTag {
-				buf.WriteString(strings.Repeat(indent, indentLevel))
+			if inRawTag {
+				f.buf.WriteString(raw)
+				needIndent = true
+				continue
 			}
-			buf.WriteString(raw)
-			if !inRawTag {
-				buf.WriteByte('\n')
+			if isVoidElement(tok.name) {
+				raw = f.voidTag(raw)
 			}
+			if needIndent {
+				f.writeIndent()
+			}
+			f.writeTag(raw)
+			f.buf.WriteByte('\n')
 			needIndent = true
-			prevWasText = false
 
 		case html.TextToken:
-			text := raw
 			if inRawTag {
 				// Preserve whitespace in raw tags
-				buf.WriteString(text)
+				f.buf.WriteString(raw)
 				needIndent = false
-			} else {
+			} else if text := collapseSpaces(strings.TrimSpace(raw)); text != "" {
 				// Trim and collapse whitespace for normal text
-				trimmed := strings.TrimSpace(text)
-				// Also collapse multiple spaces within the text
-				trimmed = collapseSpaces(trimmed)
-				if trimmed != "" {
-					if prevWasText {
-						// Add space between consecutive text nodes
-						buf.WriteByte(' ')
-					} else if needIndent {
-						buf.WriteString(strings.Repeat(indent, indentLevel))
-					}
-					buf.WriteString(trimmed)
-					buf.WriteByte('\n')
-					needIndent = true
-					prevWasText = false
+				f.writeText(text)
+				needIndent = true
+			}
+		}
+	}
+
+	return f.buf.String(), nil
+}
+
+// formatter holds output state for FormatWithOptions.
+type formatter struct {
+	opts  Options
+	buf   bytes.Buffer
+	level int
+}
+
+// writeIndent writes the indentation for the current nesting level.
+func (f *formatter) writeIndent() {
+	f.buf.WriteString(strings.Repeat(f.opts.Indent, f.level))
+}
+
+// indentWidth returns the column width of the current indentation.
+func (f *formatter) indentWidth(level int) int {
+	return level * lineWidth(f.opts.Indent)
+}
+
+// writeTag writes a start or self-closing tag, putting one attribute per line
+// when the tag does not fit within MaxLineWidth.
+func (f *formatter) writeTag(raw string) {
+	if f.opts.MaxLineWidth <= 0 || f.indentWidth(f.level)+lineWidth(raw) <= f.opts.MaxLineWidth {
+		f.buf.WriteString(raw)
+		return
+	}
+	name, attrs, closer := splitTag(raw)
+	if len(attrs) == 0 {
+		f.buf.WriteString(raw)
+		return
+	}
+
+	f.buf.WriteString("<" + name)
+	attrIndent := strings.Repeat(f.opts.Indent, f.level+1)
+	for _, attr := range attrs {
+		f.buf.WriteByte('\n')
+		f.buf.WriteString(attrIndent)
+		f.buf.WriteString(attr)
+	}
+	f.buf.WriteByte('\n')
+	f.writeIndent()
+	f.buf.WriteString(strings.TrimSpace(closer))
+}
+
+// writeText writes collapsed text at the current indentation, wrapping at word
+// boundaries when MaxLineWidth is set. A word longer than the width gets a line
+// of its own rather than being split.
+func (f *formatter) writeText(text string) {
+	limit := f.opts.MaxLineWidth - f.indentWidth(f.level)
+	if f.opts.MaxLineWidth <= 0 || lineWidth(text) <= limit {
+		f.writeIndent()
+		f.buf.WriteString(text)
+		f.buf.WriteByte('\n')
+		return
+	}
+
+	line := ""
+	for _, word := range strings.Split(text, " ") {
+		if line != "" && lineWidth(line)+1+lineWidth(word) > limit {
+			f.writeIndent()
+			f.buf.WriteString(line)
+			f.buf.WriteByte('\n')
+			line = ""
+		}
+		if line == "" {
+			line = word
+		} else {
+			line += " " + word
+		}
+	}
+	if line != "" {
+		f.writeIndent()
+		f.buf.WriteString(line)
+		f.buf.WriteByte('\n')
+	}
+}
+
+// compactElement returns the single-line form of the element starting at
+// tokens[i] when it holds nothing but text (or is empty) and fits the line
+// width. skip is the number of following tokens the line consumes.
+func (f *formatter) compactElement(tokens []token, i int, startTag string) (line string, skip int, ok bool) {
+	name := tokens[i].name
+	text := ""
+	j := i + 1
+	if j < len(tokens) && tokens[j].typ == html.TextToken {
+		text = collapseSpaces(strings.TrimSpace(tokens[j].raw))
+		j++
+	}
+	if j >= len(tokens) || tokens[j].typ != html.EndTagToken || tokens[j].name != name {
+		return "", 0, false
+	}
+
+	line = startTag + text + tokens[j].raw
+	width := f.opts.MaxLineWidth
+	if width <= 0 {
+		width = DefaultCompactWidth
+	}
+	if f.indentWidth(f.level)+lineWidth(line) > width {
+		return "", 0, false
+	}
+	return line, j - i, true
+}
+
+// voidTag rewrites a void element tag in the configured VoidStyle.
+func (f *formatter) voidTag(raw string) string {
+	if f.opts.VoidStyle == VoidKeep {
+		return raw
+	}
+	name, attrs, _ := splitTag(raw)
+	tag := "<" + name
+	if len(attrs) > 0 {
+		tag += " " + strings.Join(attrs, " ")
+	}
+	if f.opts.VoidStyle == VoidXHTML {
+		return tag + " />"
+	}
+	return tag + ">"
+}
+
+// splitTag splits a raw start or self-closing tag into its name, its
+// attributes exactly as written (including quoting), and the closing
+// delimiter (">" or "/>"). Attribute source text is kept so case and quoting
+// survive reformatting.
+func splitTag(raw string) (name string, attrs []string, closer string) {
+	body := strings.TrimPrefix(raw, "<")
+	closer = ">"
+	body = strings.TrimSuffix(body, ">")
+	if trimmed := strings.TrimRight(body, " \t\n\r\f"); strings.HasSuffix(trimmed, "/") {
+		closer = "/>"
+		body = strings.TrimSuffix(trimmed, "/")
+	}
+
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
+
+	i := 0
+	for i < len(body) && !isSpace(body[i]) && body[i] != '/' {
+		i++
+	}
+	name = body[:i]
+
+	for i < len(body) {
+		for i < len(body) && (isSpace(body[i]) || body[i] == '/') {
+			i++
+		}
+		if i >= len(body) {
+			break
+		}
+		start := i
+		for i < len(body) && !isSpace(body[i]) && body[i] != '=' && body[i] != '/' {
+			i++
+		}
+		// Look past whitespace for "=" and the value
+		j := i
+		for j < len(body) && isSpace(body[j]) {
+			j++
+		}
+		if j < len(body) && body[j] == '=' {
+			attrName := body[start:i]
+			j++
+			for j < len(body) && isSpace(body[j]) {
+				j++
+			}
+			valueStart := j
+			if j < len(body) && (body[j] == '"' || body[j] == '\'') {
+				quote := body[j]
+				j++
+				for j < len(body) && body[j] != quote {
+					j++
+				}
+				if j < len(body) {
+					j++
+				}
+			} else {
+				for j < len(body) && !isSpace(body[j]) {
+					j++
 				}
 			}
+			attrs = append(attrs, attrName+"="+body[valueStart:j])
+			i = j
+			continue
 		}
+		attrs = append(attrs, body[start:i])
 	}
+	return name, attrs, closer
+}
 
-	return buf.String(), nil
+// lineWidth returns the display width of s in columns, counting runes and
+// expanding tabs to tabWidth.
+func lineWidth(s string) int {
+	return utf8.RuneCountInString(s) + strings.Count(s, "\t")*(tabWidth-1)
 }
 
 // getTagName extracts the tag name from the tokenizer.
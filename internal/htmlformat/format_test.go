@@ -988,3 +988,96 @@ body{margin:0;}</style>
 		}
 	}
 }
+
+func TestFormatWithOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     func(*Options)
+		expected string
+	}{
+		{
+			name:     "tab indent",
+			input:    `<div><p>Text</p></div>`,
+			opts:     func(o *Options) { o.Indent = "\t" },
+			expected: "<div>\n\t<p>\n\t\tText\n\t</p>\n</div>\n",
+		},
+		{
+			name:     "four space indent",
+			input:    `<ul><li>One</li></ul>`,
+			opts:     func(o *Options) { o.Indent = "    " },
+			expected: "<ul>\n    <li>\n        One\n    </li>\n</ul>\n",
+		},
+		{
+			name:     "compact text",
+			input:    `<div><p>Short  text</p><span></span><p>Has <b>bold</b></p></div>`,
+			opts:     func(o *Options) { o.CompactText = true },
+			expected: "<div>\n  <p>Short text</p>\n  <span></span>\n  <p>\n    Has\n    <b>bold</b>\n  </p>\n</div>\n",
+		},
+		{
+			name:     "compact text respects width",
+			input:    `<p>This sentence is too long to fit</p>`,
+			opts:     func(o *Options) { o.CompactText = true; o.MaxLineWidth = 20 },
+			expected: "<p>\n  This sentence is\n  too long to fit\n</p>\n",
+		},
+		{
+			name:     "wraps long text",
+			input:    `<p>one two three four five six</p>`,
+			opts:     func(o *Options) { o.MaxLineWidth = 12 },
+			expected: "<p>\n  one two\n  three four\n  five six\n</p>\n",
+		},
+		{
+			name:     "long word stays whole",
+			input:    `<p>a https://example.com/very/long b</p>`,
+			opts:     func(o *Options) { o.MaxLineWidth = 10 },
+			expected: "<p>\n  a\n  https://example.com/very/long\n  b\n</p>\n",
+		},
+		{
+			name:     "wraps attributes",
+			input:    `<svg viewBox="0 0 10 10" class='icon big' hidden><path d="M0 0"/></svg>`,
+			opts:     func(o *Options) { o.MaxLineWidth = 30 },
+			expected: "<svg\n  viewBox=\"0 0 10 10\"\n  class='icon big'\n  hidden\n>\n  <path d=\"M0 0\"/>\n</svg>\n",
+		},
+		{
+			name:     "void html style",
+			input:    `<div><br/><img src="a.png" alt="" /><input disabled></div>`,
+			opts:     func(o *Options) { o.VoidStyle = VoidHTML },
+			expected: "<div>\n  <br>\n  <img src=\"a.png\" alt=\"\">\n  <input disabled>\n</div>\n",
+		},
+		{
+			name:     "void xhtml style",
+			input:    `<div><br><img src="a.png"><hr/></div>`,
+			opts:     func(o *Options) { o.VoidStyle = VoidXHTML },
+			expected: "<div>\n  <br />\n  <img src=\"a.png\" />\n  <hr />\n</div>\n",
+		},
+		{
+			name:     "pre untouched",
+			input:    `<pre>  keep   <br/>  this</pre>`,
+			opts:     func(o *Options) { o.VoidStyle = VoidHTML; o.CompactText = true; o.MaxLineWidth = 5 },
+			expected: "<pre>\n  keep   <br/>  this</pre>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.opts(&opts)
+			result, err := FormatWithOptions(tt.input, opts)
+			if err != nil {
+				t.Fatalf("FormatWithOptions() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("FormatWithOptions():\ngot:\n%q\nwant:\n%q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatWithOptions_DefaultMatchesFormat(t *testing.T) {
+	input := `<!DOCTYPE html><html><body><div class="a"><img src="x.png"/><p>Text</p></div></body></html>`
+	want, _ := Format(input)
+	got, _ := FormatWithOptions(input, DefaultOptions())
+	if got != want {
+		t.Errorf("default options differ from Format:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+// Package imagediff compares images pixel by pixel, using a perceptual colour
+// distance, and renders the differences as an image.
+package imagediff
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// DefaultSensitivity is the colour distance, from 0 to 1, below which two
+// pixels count as the same. It absorbs the slight colour shifts of image
+// scaling and anti-aliasing without hiding real changes.
+const DefaultSensitivity = 0.1
+
+// maxYIQDelta is the largest possible squared YIQ distance, between black
+// and white.
+const maxYIQDelta = 35215
+
+// Result is the outcome of a comparison.
+type Result struct {
+	// Width and Height are the compared area: the larger of each dimension of
+	// the two images. Pixels only one image covers count as changed.
+	Width, Height int
+	// Changed is the number of pixels that differ.
+	Changed int
+	// Bounds encloses the changed pixels; empty when none changed.
+	Bounds image.Rectangle
+	// Diff shows the second image faded to grey with changed pixels in red.
+	Diff *image.NRGBA
+}
+
+// Total returns the number of pixels compared.
+func (r Result) Total() int {
+	return r.Width * r.Height
+}
+
+// Percent returns the share of compared pixels that changed, from 0 to 100.
+func (r Result) Percent() float64 {
+	if r.Total() == 0 {
+		return 0
+	}
+	return float64(r.Changed) * 100 / float64(r.Total())
+}
+
+// Compare compares a to b. Two pixels differ when their distance in YIQ
+// colour space, which weighs brightness over hue as the eye does, exceeds
+// sensitivity (0 to 1) of the largest possible distance. Transparent pixels
+// are compared as if over white.
+func Compare(a, b image.Image, sensitivity float64) Result {
+	na, nb := toNRGBA(a), toNRGBA(b)
+	w := max(na.Rect.Dx(), nb.Rect.Dx())
+	h := max(na.Rect.Dy(), nb.Rect.Dy())
+	limit := maxYIQDelta * sensitivity * sensitivity
+
+	res := Result{Width: w, Height: h, Diff: image.NewNRGBA(image.Rect(0, 0, w, h))}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			inA := x < na.Rect.Dx() && y < na.Rect.Dy()
+			inB := x < nb.Rect.Dx() && y < nb.Rect.Dy()
+			var ca, cb color.NRGBA
+			if inA {
+				ca = na.NRGBAAt(x, y)
+			}
+			if inB {
+				cb = nb.NRGBAAt(x, y)
+			}
+
+			if !inA || !inB || (ca != cb && yiqDelta(ca, cb) > limit) {
+				res.Changed++
+				res.Bounds = res.Bounds.Union(image.Rect(x, y, x+1, y+1))
+				res.Diff.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+				continue
+			}
+			res.Diff.SetNRGBA(x, y, faded(cb))
+		}
+	}
+	return res
+}
+
+// toNRGBA returns img as an NRGBA image with its origin at (0, 0).
+func toNRGBA(img image.Image) *image.NRGBA {
+	if n, ok := img.(*image.NRGBA); ok && n.Rect.Min == (image.Point{}) {
+		return n
+	}
+	b := img.Bounds()
+	n := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(n, n.Rect, img, b.Min, draw.Src)
+	return n
+}
+
+// yiqDelta returns the squared YIQ distance between two colours blended over
+// white, weighted as in Kotsarenko and Ramos, "Measuring perceived color
+// difference using YIQ NTSC transmission color space in mobile applications".
+func yiqDelta(a, b color.NRGBA) float64 {
+	r1, g1, b1 := overWhite(a)
+	r2, g2, b2 := overWhite(b)
+	y := yiqY(r1, g1, b1) - yiqY(r2, g2, b2)
+	i := yiqI(r1, g1, b1) - yiqI(r2, g2, b2)
+	q := yiqQ(r1, g1, b1) - yiqQ(r2, g2, b2)
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+func overWhite(c color.NRGBA) (r, g, b float64) {
+	a := float64(c.A) / 255
+	blend := func(v uint8) float64 { return 255 + (float64(v)-255)*a }
+	return blend(c.R), blend(c.G), blend(c.B)
+}
+
+func yiqY(r, g, b float64) float64 { return r*0.29889531 + g*0.58662247 + b*0.11448223 }
+func yiqI(r, g, b float64) float64 { return r*0.59597799 - g*0.27417610 - b*0.32180189 }
+func yiqQ(r, g, b float64) float64 { return r*0.21147017 - g*0.52261711 + b*0.31114694 }
+
+// faded returns c as a light grey of its brightness, so unchanged areas stay
+// recognisable behind the red changes.
+func faded(c color.NRGBA) color.NRGBA {
+	r, g, b := overWhite(c)
+	v := uint8(255 + (yiqY(r, g, b)-255)*0.1)
+	return color.NRGBA{R: v, G: v, B: v, A: 255}
+}
@@ -0,0 +1,79 @@
+package imagediff
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+var white = color.NRGBA{255, 255, 255, 255}
+
+func TestCompare_Identical(t *testing.T) {
+	res := Compare(solid(10, 10, white), solid(10, 10, white), DefaultSensitivity)
+	if res.Changed != 0 || !res.Bounds.Empty() || res.Total() != 100 || res.Percent() != 0 {
+		t.Errorf("result = %+v", res)
+	}
+}
+
+func TestCompare_ChangedPixels(t *testing.T) {
+	a := solid(10, 10, white)
+	b := solid(10, 10, white)
+	b.SetNRGBA(2, 3, color.NRGBA{0, 0, 0, 255})
+	b.SetNRGBA(5, 7, color.NRGBA{255, 0, 0, 255})
+
+	res := Compare(a, b, DefaultSensitivity)
+	if res.Changed != 2 {
+		t.Fatalf("changed = %d, want 2", res.Changed)
+	}
+	if want := image.Rect(2, 3, 6, 8); res.Bounds != want {
+		t.Errorf("bounds = %v, want %v", res.Bounds, want)
+	}
+	if res.Percent() != 2 {
+		t.Errorf("percent = %v, want 2", res.Percent())
+	}
+	if got := res.Diff.NRGBAAt(2, 3); got != (color.NRGBA{255, 0, 0, 255}) {
+		t.Errorf("changed pixel drawn as %v, want red", got)
+	}
+	if got := res.Diff.NRGBAAt(0, 0); got.R != got.G || got.R < 200 {
+		t.Errorf("unchanged pixel drawn as %v, want light grey", got)
+	}
+}
+
+func TestCompare_SensitivityIgnoresSlightShifts(t *testing.T) {
+	a := solid(4, 4, color.NRGBA{100, 100, 100, 255})
+	b := solid(4, 4, color.NRGBA{103, 101, 100, 255})
+	if res := Compare(a, b, DefaultSensitivity); res.Changed != 0 {
+		t.Errorf("slight shift changed %d pixels, want 0", res.Changed)
+	}
+	if res := Compare(a, b, 0); res.Changed != 16 {
+		t.Errorf("zero sensitivity changed %d pixels, want 16", res.Changed)
+	}
+}
+
+func TestCompare_SizeMismatch(t *testing.T) {
+	res := Compare(solid(10, 10, white), solid(10, 12, white), DefaultSensitivity)
+	if res.Width != 10 || res.Height != 12 {
+		t.Fatalf("compared %dx%d, want 10x12", res.Width, res.Height)
+	}
+	if res.Changed != 20 {
+		t.Errorf("changed = %d, want the 20 uncovered pixels", res.Changed)
+	}
+}
+
+func TestCompare_OffsetBounds(t *testing.T) {
+	a := solid(6, 6, white).SubImage(image.Rect(2, 2, 6, 6))
+	res := Compare(a, solid(4, 4, white), DefaultSensitivity)
+	if res.Changed != 0 || res.Width != 4 {
+		t.Errorf("result = %+v", res)
+	}
+}
@@ -5,18 +5,50 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
+	"slices"
 	"time"
 )
 
 // ErrDaemonNotRunning is returned when the daemon is not running.
 var ErrDaemonNotRunning = errors.New("daemon is not running")
 
+// errPeerCredUnsupported is returned by peerUID on platforms where the user
+// at the other end of a socket cannot be found.
+var errPeerCredUnsupported = errors.New("peer credentials not supported")
+
+// checkPeer verifies that the daemon at the other end of conn runs as the
+// current user, so commands are not sent to a socket another user planted.
+func checkPeer(conn net.Conn) error {
+	uid, err := peerUID(conn)
+	if errors.Is(err, errPeerCredUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot identify daemon: %w", err)
+	}
+	if uid != os.Geteuid() {
+		return fmt.Errorf("daemon socket is served by uid %d, not you", uid)
+	}
+	return nil
+}
+
+// ErrProtocolMismatch is returned when the daemon speaks a different IPC
+// protocol version than this client, which happens when webctl is upgraded
+// while a daemon is running.
+var ErrProtocolMismatch = errors.New("daemon protocol mismatch")
+
 // Client is a Unix socket IPC client.
 type Client struct {
 	conn   net.Conn
 	reader *bufio.Reader
+	hello  HelloData
+	// mismatch is set when the handshake found a daemon that speaks another
+	// protocol. Every command but "shutdown" fails with it, so the daemon
+	// can still be stopped and restarted.
+	mismatch error
 }
 
 // Dial connects to the daemon at the default socket path.
@@ -35,15 +67,103 @@ func DialPath(socketPath string) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
 	}
+	if err := checkPeer(conn); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("refusing %s: %w", socketPath, err)
+	}
 
-	return &Client{
+	c := &Client{
 		conn:   conn,
 		reader: bufio.NewReader(conn),
-	}, nil
+	}
+	if err := c.handshake(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// handshake exchanges protocol versions with the daemon and records a
+// mismatch.
+func (c *Client) handshake() error {
+	params, err := json.Marshal(HelloParams{Protocol: ProtocolVersion, Accept: encodings})
+	if err != nil {
+		return err
+	}
+	resp, err := c.roundTrip(Request{Cmd: HelloCmd, Params: params}, nil)
+	if err != nil {
+		return fmt.Errorf("daemon handshake failed: %w", err)
+	}
+	if !resp.OK {
+		// Daemons from before the handshake answer "unknown command"
+		c.mismatch = fmt.Errorf("%w: the daemon is older than this webctl and does not report its version; restart it with 'webctl stop' and 'webctl start'", ErrProtocolMismatch)
+		return nil
+	}
+	if err := json.Unmarshal(resp.Data, &c.hello); err != nil {
+		return fmt.Errorf("daemon handshake failed: %w", err)
+	}
+	c.mismatch = protocolMismatch(c.hello)
+	return nil
 }
 
-// Send sends a request to the daemon and returns the response.
+// protocolMismatch describes a daemon speaking another protocol version, or
+// returns nil if it speaks this one.
+func protocolMismatch(hello HelloData) error {
+	if hello.Protocol == ProtocolVersion {
+		return nil
+	}
+	age := "older"
+	if hello.Protocol > ProtocolVersion {
+		age = "newer"
+	}
+	version := ""
+	if hello.Version != "" {
+		version = " (webctl " + hello.Version + ")"
+	}
+	return fmt.Errorf("%w: the daemon%s is %s than this webctl (IPC protocol %d, this webctl speaks %d); restart it with 'webctl stop' and 'webctl start'",
+		ErrProtocolMismatch, version, age, hello.Protocol, ProtocolVersion)
+}
+
+// Hello returns the daemon's handshake answer: its protocol version, webctl
+// version, and capabilities.
+func (c *Client) Hello() HelloData {
+	return c.hello
+}
+
+// HasCapability reports whether the daemon listed name among its
+// capabilities in the handshake.
+func (c *Client) HasCapability(name string) bool {
+	return slices.Contains(c.hello.Capabilities, name)
+}
+
+// Send sends a request to the daemon and returns the response. If the daemon
+// speaks another protocol version, only "shutdown" is sent; anything else
+// fails with ErrProtocolMismatch.
 func (c *Client) Send(req Request) (Response, error) {
+	if c.mismatch != nil && req.Cmd != "shutdown" {
+		return Response{}, c.mismatch
+	}
+	return c.roundTrip(req, nil)
+}
+
+// SendStream sends a request asking for its body to be streamed, writing the
+// body to w as it arrives. The response reports whether the body is complete;
+// a command that does not stream, or a daemon without CapabilityStream,
+// answers with its body in the response data instead, leaving w untouched.
+func (c *Client) SendStream(req Request, w io.Writer) (Response, error) {
+	if c.mismatch != nil {
+		return Response{}, c.mismatch
+	}
+	if !c.HasCapability(CapabilityStream) {
+		return c.roundTrip(req, nil)
+	}
+	req.Stream = true
+	return c.roundTrip(req, w)
+}
+
+// roundTrip writes a request and reads its response, writing any streamed
+// body to w.
+func (c *Client) roundTrip(req Request, w io.Writer) (Response, error) {
 	data, err := json.Marshal(req)
 	if err != nil {
 		return Response{}, fmt.Errorf("failed to marshal request: %w", err)
@@ -54,17 +174,29 @@ func (c *Client) Send(req Request) (Response, error) {
 		return Response{}, fmt.Errorf("failed to send request: %w", err)
 	}
 
-	line, err := c.reader.ReadBytes('\n')
-	if err != nil {
-		return Response{}, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var resp Response
-	if err := json.Unmarshal(line, &resp); err != nil {
-		return Response{}, fmt.Errorf("failed to parse response: %w", err)
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			return Response{}, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		var frame struct {
+			Response
+			Chunk []byte `json:"chunk"`
+		}
+		if err := json.Unmarshal(line, &frame); err != nil {
+			return Response{}, fmt.Errorf("failed to parse response: %w", err)
+		}
+		if frame.Chunk == nil {
+			return decodeResponse(frame.Response)
+		}
+		if w == nil {
+			return Response{}, errors.New("unexpected streamed response")
+		}
+		if _, err := w.Write(frame.Chunk); err != nil {
+			return Response{}, fmt.Errorf("failed to write streamed body: %w", err)
+		}
 	}
-
-	return resp, nil
 }
 
 // SendCmd is a convenience method for sending a simple command.
@@ -93,6 +225,6 @@ func IsDaemonRunningAt(socketPath string) bool {
 	if err != nil {
 		return false
 	}
-	_ = conn.Close()
-	return true
+	defer func() { _ = conn.Close() }()
+	return checkPeer(conn) == nil
 }
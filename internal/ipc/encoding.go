@@ -0,0 +1,72 @@
+package ipc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// encodings are the response encodings this build supports, preferred first.
+var encodings = []string{EncodingGzip}
+
+// chooseEncoding returns the first of the client's accepted encodings that
+// the server supports, or "" for none.
+func chooseEncoding(accept []string) string {
+	for _, e := range accept {
+		if slices.Contains(encodings, e) {
+			return e
+		}
+	}
+	return ""
+}
+
+// encodeResponse compresses the data of resp with encoding if it is over
+// CompressThreshold and compression shrinks it, taking into account that
+// Encoded is sent as base64. Otherwise it returns resp unchanged.
+func encodeResponse(resp Response, encoding string) Response {
+	if encoding != EncodingGzip || len(resp.Data) <= CompressThreshold {
+		return resp
+	}
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return resp
+	}
+	if _, err := zw.Write(resp.Data); err != nil {
+		return resp
+	}
+	if err := zw.Close(); err != nil {
+		return resp
+	}
+	if buf.Len()*4/3 >= len(resp.Data) {
+		return resp
+	}
+	resp.Encoding = encoding
+	resp.Encoded = buf.Bytes()
+	resp.Data = nil
+	return resp
+}
+
+// decodeResponse restores the data of a response sent with encodeResponse.
+func decodeResponse(resp Response) (Response, error) {
+	if resp.Encoding == "" {
+		return resp, nil
+	}
+	if resp.Encoding != EncodingGzip {
+		return Response{}, fmt.Errorf("unsupported response encoding: %s", resp.Encoding)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(resp.Encoded))
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	resp.Data = data
+	resp.Encoding = ""
+	resp.Encoded = nil
+	return resp, nil
+}
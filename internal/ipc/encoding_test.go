@@ -0,0 +1,91 @@
+package ipc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChooseEncoding(t *testing.T) {
+	tests := []struct {
+		accept []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{"br"}, ""},
+		{[]string{"br", EncodingGzip}, EncodingGzip},
+	}
+	for _, tt := range tests {
+		if got := chooseEncoding(tt.accept); got != tt.want {
+			t.Errorf("chooseEncoding(%v) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeResponse(t *testing.T) {
+	large := SuccessResponse(map[string]string{"body": strings.Repeat("GET /api/items 200 ", CompressThreshold/8)})
+	small := SuccessResponse(map[string]string{"body": "ok"})
+
+	if got := encodeResponse(small, EncodingGzip); got.Encoding != "" {
+		t.Errorf("small response was encoded with %s", got.Encoding)
+	}
+	if got := encodeResponse(large, ""); got.Encoding != "" {
+		t.Errorf("response was encoded with %s on a connection without an encoding", got.Encoding)
+	}
+
+	encoded := encodeResponse(large, EncodingGzip)
+	if encoded.Encoding != EncodingGzip || encoded.Data != nil || len(encoded.Encoded) >= len(large.Data) {
+		t.Fatalf("encodeResponse() = encoding %q, %d data bytes, %d encoded bytes", encoded.Encoding, len(encoded.Data), len(encoded.Encoded))
+	}
+	decoded, err := decodeResponse(encoded)
+	if err != nil {
+		t.Fatalf("decodeResponse() error = %v", err)
+	}
+	if !bytes.Equal(decoded.Data, large.Data) || decoded.Encoding != "" || decoded.Encoded != nil {
+		t.Error("decodeResponse() did not restore the original data")
+	}
+
+	if _, err := decodeResponse(Response{OK: true, Encoding: "zz"}); err == nil {
+		t.Error("decodeResponse() accepted an unknown encoding")
+	}
+}
+
+func TestClient_CompressedResponse(t *testing.T) {
+	body := strings.Repeat("console.log('hello') ", CompressThreshold/4)
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	server, err := NewServer(socketPath, func(req Request) Response {
+		return SuccessResponse(map[string]string{"body": body})
+	})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.SetVersion("", CapabilityGzip)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = server.Serve(ctx) }()
+	defer func() { _ = server.Close() }()
+
+	client, err := DialPath(socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if got := client.Hello().Encoding; got != EncodingGzip {
+		t.Errorf("negotiated encoding = %q, want %q", got, EncodingGzip)
+	}
+	resp, err := client.SendCmd("console")
+	if err != nil || !resp.OK {
+		t.Fatalf("SendCmd() = %+v, %v", resp, err)
+	}
+	var data map[string]string
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatalf("response data: %v", err)
+	}
+	if data["body"] != body {
+		t.Errorf("response body is %d bytes, want %d intact", len(data["body"]), len(body))
+	}
+}
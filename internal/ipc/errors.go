@@ -0,0 +1,51 @@
+package ipc
+
+import "strings"
+
+// Error codes classify a failed response so scripts can branch on the kind of
+// failure instead of matching its message.
+const (
+	CodeFailed          = "E_FAILED"            // any failure not classified below
+	CodeInvalidArgs     = "E_INVALID_ARGS"      // bad flags, arguments, parameters, or config
+	CodeDaemonDown      = "E_DAEMON_DOWN"       // no daemon to talk to
+	CodeNoSession       = "E_NO_SESSION"        // no page is open or selected
+	CodeElementNotFound = "E_ELEMENT_NOT_FOUND" // a selector matched nothing
+	CodeTimeout         = "E_TIMEOUT"           // a wait or evaluation ran out of time
+	CodeNotFound        = "E_NOT_FOUND"         // a tab, cookie, or other named item is missing
+)
+
+// ErrorCode classifies an error message. Handlers that know their failure's
+// class use CodedErrorResponse instead; this covers the rest by the wording the
+// daemon and CLI use for each class.
+func ErrorCode(msg string) string {
+	m := strings.ToLower(msg)
+	switch {
+	case strings.Contains(m, "daemon not running"),
+		strings.Contains(m, "daemon is not running"),
+		strings.Contains(m, "failed to connect to daemon"):
+		return CodeDaemonDown
+	case strings.Contains(m, "no active session"),
+		strings.Contains(m, "no active tab"):
+		return CodeNoSession
+	case strings.Contains(m, "timeout"),
+		strings.Contains(m, "timed out"):
+		return CodeTimeout
+	case strings.Contains(m, "element not found"),
+		strings.Contains(m, "matched no elements"):
+		return CodeElementNotFound
+	case strings.HasPrefix(m, "no tab matches"),
+		strings.HasPrefix(m, "no cookie named"),
+		strings.Contains(m, "not in buffer"):
+		return CodeNotFound
+	case strings.HasPrefix(m, "invalid "),
+		strings.HasPrefix(m, "unknown "),
+		strings.Contains(m, " is required"):
+		return CodeInvalidArgs
+	}
+	return CodeFailed
+}
+
+// CodedErrorResponse creates an error response with an explicit code.
+func CodedErrorResponse(code, msg string) Response {
+	return Response{OK: false, Error: msg, Code: code}
+}
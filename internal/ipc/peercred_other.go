@@ -0,0 +1,11 @@
+//go:build !linux && !darwin
+
+package ipc
+
+import "net"
+
+// peerUID is not implemented on this platform; the socket's permissions are
+// the only guard.
+func peerUID(conn net.Conn) (int, error) {
+	return -1, errPeerCredUnsupported
+}
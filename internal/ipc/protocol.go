@@ -2,7 +2,9 @@ package ipc
 
 import (
 	"encoding/json"
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
 	"strings"
 )
 
@@ -15,12 +17,67 @@ const MultiElementSeparator = "--"
 // Used by the REPL to execute commands via Cobra.
 type CommandExecutor func(args []string) (recognized bool, err error)
 
+// ProtocolVersion is the version of the request and response formats. Bump it
+// whenever a change would be misread by a CLI or daemon built before it, so
+// the mismatch is reported instead of surfacing as missing or garbled fields.
+const ProtocolVersion = 1
+
+// HelloCmd is the handshake a client sends first on each connection. The
+// server answers it itself with HelloData.
+const HelloCmd = "hello"
+
+// HelloParams are the parameters of the handshake.
+type HelloParams struct {
+	Protocol int `json:"protocol"`
+	// Accept lists the encodings the client can decode, preferred first.
+	Accept []string `json:"accept,omitempty"`
+}
+
+// HelloData is the server's answer to the handshake.
+type HelloData struct {
+	Protocol int    `json:"protocol"`
+	Version  string `json:"version,omitempty"` // the daemon's webctl version
+	// Capabilities name optional features the daemon supports, which a
+	// client may use without a protocol bump.
+	Capabilities []string `json:"capabilities"`
+	// Encoding is the one of the client's Accept encodings the server uses
+	// for large response data on this connection, if any.
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Capabilities a daemon reports in HelloData. Clients check them with
+// Client.HasCapability before using the feature.
+const (
+	// CapabilityStream means the daemon streams command bodies as Chunk
+	// frames for requests with Stream set.
+	CapabilityStream = "stream"
+	// CapabilityGzip means the daemon compresses large response data when
+	// the client accepts EncodingGzip.
+	CapabilityGzip = "gzip"
+)
+
+// EncodingGzip compresses response data with gzip. It is the only encoding
+// so far; others can be added to a client's Accept list without a protocol
+// bump, as the server picks only from what it knows.
+const EncodingGzip = "gzip"
+
+// CompressThreshold is the response data size, in bytes, above which the
+// server compresses data with the connection's encoding. Smaller responses
+// are not worth the CPU time.
+const CompressThreshold = 64 << 10
+
 // Request represents a command sent from the CLI to the daemon.
 type Request struct {
 	Cmd    string          `json:"cmd"`
 	Target string          `json:"target,omitempty"`
 	Params json.RawMessage `json:"params,omitempty"`
 	Debug  bool            `json:"debug,omitempty"` // Enable debug output for this request
+	// Stream asks for the command's body to be streamed in Chunk frames
+	// instead of inlined in the response data. A screenshot streams its
+	// image and a heap snapshot its JSON; a full page of html and the
+	// network buffer stream the JSON their data would hold. Commands that
+	// cannot stream ignore it.
+	Stream bool `json:"stream,omitempty"`
 }
 
 // Response represents a response sent from the daemon to the CLI.
@@ -28,14 +85,134 @@ type Response struct {
 	OK    bool            `json:"ok"`
 	Data  json.RawMessage `json:"data,omitempty"`
 	Error string          `json:"error,omitempty"`
+	Code  string          `json:"code,omitempty"` // error class (E_*) when OK is false
+
+	// Encoding, if set, names the encoding of Encoded, which then holds the
+	// data in place of Data. The client decodes it back into Data.
+	Encoding string `json:"encoding,omitempty"`
+	Encoded  []byte `json:"encoded,omitempty"`
+
+	// Body, if set, writes a streamed body. The server sends what it writes
+	// as Chunk frames ahead of the response itself (see StreamResponse). It
+	// runs after the handler has returned, so it must only use what the
+	// handler captured for it.
+	Body func(w io.Writer) error `json:"-"`
+}
+
+// ChunkSize is the most body bytes carried by one Chunk frame.
+const ChunkSize = 256 << 10
+
+// Chunk is a frame of a streamed body. A streamed response is sent as its
+// Chunk frames, in order, followed by the Response, whose OK and Error say
+// whether the body is complete.
+type Chunk struct {
+	Chunk []byte `json:"chunk"`
 }
 
 // StatusData is the response data for the "status" command.
 type StatusData struct {
-	Running       bool          `json:"running"`
-	PID           int           `json:"pid,omitempty"`
+	Running bool `json:"running"`
+	PID     int  `json:"pid,omitempty"`
+	// Socket is the daemon's IPC socket path.
+	Socket        string        `json:"socket,omitempty"`
 	ActiveSession *PageSession  `json:"activeSession,omitempty"`
 	Sessions      []PageSession `json:"sessions,omitempty"`
+	// BrowserRestarts counts the times the daemon relaunched a crashed browser.
+	BrowserRestarts uint64 `json:"browserRestarts,omitempty"`
+	// Adopted is true when the daemon attached to a browser it did not launch
+	// ('start --adopt'); stopping the daemon leaves that browser running.
+	Adopted bool `json:"adopted,omitempty"`
+	// Restarting is true while the daemon replaces its browser, after a crash
+	// or a 'head'/'headless' switch; the browser and tabs are then unknown.
+	Restarting bool `json:"restarting,omitempty"`
+	// Browser is how the browser was launched. It is absent for an adopted
+	// browser, whose launch webctl knows nothing about.
+	Browser *BrowserInfo `json:"browser,omitempty"`
+	// Detail is the diagnostic state added by 'status --verbose'.
+	Detail *StatusDetail `json:"detail,omitempty"`
+}
+
+// StatusParams represents parameters for the "status" command.
+type StatusParams struct {
+	Verbose bool `json:"verbose,omitempty"` // include StatusData.Detail
+}
+
+// StatusDetail is the daemon and per-tab state reported by 'status --verbose'.
+type StatusDetail struct {
+	StartedAt  int64  `json:"startedAt"` // Unix milliseconds
+	UptimeMs   int64  `json:"uptimeMs"`
+	HeapBytes  uint64 `json:"heapBytes"` // Go heap in use
+	SysBytes   uint64 `json:"sysBytes"`  // memory obtained from the OS
+	Goroutines int    `json:"goroutines"`
+	// Emulation is the viewport, user agent, throttling, and stealth applied
+	// to every tab; absent when nothing is emulated.
+	Emulation *EmulationInfo  `json:"emulation,omitempty"`
+	Workers   []WorkerInfo    `json:"workers"`
+	Sessions  []SessionDetail `json:"sessions"`
+}
+
+// EmulationInfo is the emulation 'webctl start' and 'throttle cpu' apply to
+// each tab.
+type EmulationInfo struct {
+	Viewport  string `json:"viewport,omitempty"` // WIDTHxHEIGHT
+	UserAgent string `json:"userAgent,omitempty"`
+	Throttle  string `json:"throttle,omitempty"`
+	Stealth   bool   `json:"stealth,omitempty"`
+	// CPUThrottle is the CPU slowdown set by 'throttle cpu'.
+	CPUThrottle float64 `json:"cpuThrottle,omitempty"`
+}
+
+// WorkerInfo is a dedicated, shared, or service worker running in the browser.
+type WorkerInfo struct {
+	Type     string `json:"type"` // "worker", "shared_worker", or "service_worker"
+	URL      string `json:"url"`
+	Attached bool   `json:"attached"` // a DevTools client is attached to it
+}
+
+// SessionDetail is one tab's buffers and navigation state.
+type SessionDetail struct {
+	ID             string `json:"id"`
+	ConsoleEntries int    `json:"consoleEntries"`
+	NetworkEntries int    `json:"networkEntries"`
+	InFlight       int    `json:"inFlight"` // buffered requests with no response yet
+	NetworkEnabled bool   `json:"networkEnabled"`
+	// LastNavigation is when the main frame last navigated, in Unix
+	// milliseconds; 0 if it has not since the daemon attached.
+	LastNavigation int64 `json:"lastNavigation,omitempty"`
+	// NavigationPending is true while a navigate, reload, back, or forward
+	// is waiting for the page to load.
+	NavigationPending bool `json:"navigationPending,omitempty"`
+	// Origins summarizes the buffered requests by origin, most requests first.
+	Origins []OriginSummary `json:"origins"`
+}
+
+// OriginSummary counts one origin's buffered requests for a tab.
+type OriginSummary struct {
+	Origin   string `json:"origin"`
+	Requests int    `json:"requests"`
+	Errors   int    `json:"errors"` // failed requests and 4xx/5xx responses
+	Bytes    int64  `json:"bytes"`  // response sizes
+}
+
+// BrowserInfo describes the launch of the daemon's browser, for "status".
+type BrowserInfo struct {
+	Headless    bool   `json:"headless"`
+	Port        int    `json:"port"`
+	UserDataDir string `json:"userDataDir,omitempty"`
+	TempProfile bool   `json:"tempProfile,omitempty"`
+	LaunchFlags
+}
+
+// LaunchFlags are the browser customizations given to 'webctl start'.
+type LaunchFlags struct {
+	Lang           string   `json:"lang,omitempty"`           // UI and Accept-Language locale, e.g. en-GB
+	WindowSize     string   `json:"windowSize,omitempty"`     // "WIDTH,HEIGHT" in screen pixels
+	WindowPosition string   `json:"windowPosition,omitempty"` // "X,Y" in screen pixels
+	Incognito      bool     `json:"incognito,omitempty"`
+	Extensions     []string `json:"extensions,omitempty"`  // unpacked extension directories
+	ChromeFlags    []string `json:"chromeFlags,omitempty"` // passed to Chrome verbatim
+	CI             bool     `json:"ci,omitempty"`          // container-safe flags and a shorter startup timeout
+	DevTools       bool     `json:"devtools,omitempty"`    // open DevTools for every tab when headed
 }
 
 // ConsoleFrame is a single call frame from a captured stack trace. It mirrors
@@ -120,6 +297,9 @@ type ConsoleEntry struct {
 	NetworkRequestID string `json:"networkRequestId,omitempty"`
 	// WorkerID identifies the worker that produced a Log-domain entry, if any.
 	WorkerID string `json:"workerId,omitempty"`
+	// FrameID identifies the frame whose execution context produced a console
+	// call or exception, when known. Matches the IDs printed by "frames".
+	FrameID string `json:"frameId,omitempty"`
 }
 
 // Console type constants matching CDP Runtime.consoleAPICalled types.
@@ -202,15 +382,30 @@ type NetworkEntry struct {
 	FromServiceWorker bool `json:"fromServiceWorker,omitempty"`
 	// FromPrefetchCache reports the response was served from the prefetch cache.
 	FromPrefetchCache bool `json:"fromPrefetchCache,omitempty"`
+	// FromMemoryCache reports the response was served from the memory cache,
+	// without a network request.
+	FromMemoryCache bool `json:"fromMemoryCache,omitempty"`
+	// Priority is the browser's loading priority for the request (VeryLow,
+	// Low, Medium, High, VeryHigh), updated if it changes while loading.
+	Priority string `json:"priority,omitempty"`
 	// ConnectionID identifies the physical connection that served the response,
 	// so requests sharing a connection can be correlated.
 	ConnectionID float64 `json:"connectionId,omitempty"`
 	// SecurityState is the transport security posture (secure, insecure, neutral, unknown).
 	SecurityState string `json:"securityState,omitempty"`
+	// Security is the TLS connection and certificate that served the response,
+	// absent for plain HTTP and responses that never reached the network.
+	Security *NetworkSecurityDetails `json:"security,omitempty"`
+	// MixedContentType is set when an HTTPS page requested this resource over
+	// HTTP: "blockable" or "optionally-blockable".
+	MixedContentType string `json:"mixedContentType,omitempty"`
 	// Timing is the per-phase latency breakdown derived from the CDP ResourceTiming.
 	Timing *NetworkTiming `json:"timing,omitempty"`
 	// Initiator records what caused the request: its type and a single source location.
 	Initiator *NetworkInitiator `json:"initiator,omitempty"`
+	// FrameID identifies the frame that issued the request. Matches the IDs
+	// printed by "frames".
+	FrameID string `json:"frameId,omitempty"`
 
 	// awaitingRequestBody marks an entry whose request body was advertised
 	// (hasPostData) but omitted from requestWillBeSent, so the daemon is
@@ -248,6 +443,25 @@ func (e *NetworkEntry) ClearAwaitingRequestBody() {
 	e.awaitingRequestBody = false
 }
 
+// NetworkSecurityDetails describes the TLS connection behind a response, from
+// the CDP Network.SecurityDetails.
+type NetworkSecurityDetails struct {
+	// Protocol is the TLS version, for example "TLS 1.3".
+	Protocol string `json:"protocol"`
+	// KeyExchange is the key exchange, or for TLS 1.3 the key exchange group.
+	KeyExchange string `json:"keyExchange,omitempty"`
+	Cipher      string `json:"cipher"`
+	// SubjectName is the certificate subject; SANs are its subject alternative
+	// names.
+	SubjectName string   `json:"subjectName"`
+	SANs        []string `json:"sans,omitempty"`
+	Issuer      string   `json:"issuer"`
+	// ValidFrom and ValidTo bound the certificate's validity, in Unix
+	// milliseconds.
+	ValidFrom int64 `json:"validFrom"`
+	ValidTo   int64 `json:"validTo"`
+}
+
 // NetworkTiming is a per-phase latency breakdown of a network request, in
 // milliseconds. The daemon derives each phase from the CDP ResourceTiming
 // offsets (which are relative to a requestTime baseline) so callers read
@@ -286,6 +500,9 @@ type NetworkInitiator struct {
 type ConsoleData struct {
 	Entries []ConsoleEntry `json:"entries"`
 	Count   int            `json:"count"`
+	// Navigations holds the Unix-millisecond times of the active tab's recent
+	// main-frame navigations, oldest first, for relative timestamps.
+	Navigations []int64 `json:"navigations,omitempty"`
 }
 
 // NetworkData is the response data for the "network" command.
@@ -301,6 +518,9 @@ type PageSession struct {
 	URL    string `json:"url"`
 	Active bool   `json:"active,omitempty"`
 	Status int    `json:"status,omitempty"` // HTTP status of last document load
+	// Context is the browser context the tab belongs to, when it is one made
+	// with 'context new'; empty for the default context.
+	Context string `json:"context,omitempty"`
 }
 
 // TabParams represents parameters for the "tab" command.
@@ -323,9 +543,37 @@ type NewTabData struct {
 	Title string `json:"title,omitempty"`
 }
 
+// ContextParams represents parameters for the "context" command.
+type ContextParams struct {
+	Action    string `json:"action"`              // "list", "new", or "close"
+	ID        string `json:"id,omitempty"`        // context ID or prefix, for "close"
+	URL       string `json:"url,omitempty"`       // first tab's URL, for "new"
+	Incognito bool   `json:"incognito,omitempty"` // dispose with the last tab, for "new"
+}
+
+// ContextData is the response data for "context list" and "context close".
+type ContextData struct {
+	Contexts []ContextInfo `json:"contexts"`
+}
+
+// ContextInfo describes a browser context and its tabs. It is also the
+// response data for "context new".
+type ContextInfo struct {
+	ID string `json:"id,omitempty"` // empty for the default context
+	// Default is true for the browser's own context, which shares the
+	// profile's cookies and storage.
+	Default bool `json:"default,omitempty"`
+	// Incognito is true for a context disposed when its last tab closes.
+	Incognito bool          `json:"incognito,omitempty"`
+	Tabs      []PageSession `json:"tabs"`
+}
+
 // ScreenshotParams represents parameters for the "screenshot" command.
 type ScreenshotParams struct {
 	FullPage bool `json:"fullPage"`
+	// Annotate lists selectors whose elements are outlined and labelled in
+	// the capture.
+	Annotate []string `json:"annotate,omitempty"`
 }
 
 // ScreenshotData is the response data for the "screenshot" command.
@@ -336,6 +584,10 @@ type ScreenshotData struct {
 // HTMLParams represents parameters for the "html" command.
 type HTMLParams struct {
 	Selector string `json:"selector,omitempty"`
+	Complete bool   `json:"complete,omitempty"` // capture an MHTML snapshot with inlined resources
+	// Path wraps each match in its ancestors' opening and closing tags, with
+	// their other children collapsed into comments.
+	Path bool `json:"path,omitempty"`
 }
 
 // ElementWithHTML combines element metadata with HTML
@@ -348,19 +600,58 @@ type ElementWithHTML struct {
 type HTMLData struct {
 	HTML      string            `json:"html,omitempty"`      // single result or legacy
 	HTMLMulti []ElementWithHTML `json:"htmlMulti,omitempty"` // multi-element with metadata
+	MHTML     string            `json:"mhtml,omitempty"`     // complete page snapshot (Complete param)
 }
 
 // NavigateParams represents parameters for the "navigate" command.
 type NavigateParams struct {
 	URL     string `json:"url"`
-	Wait    bool   `json:"wait"`    // wait for page load completion
-	Timeout int    `json:"timeout"` // timeout in seconds (when wait=true)
+	Wait    bool   `json:"wait"`            // wait for page load completion
+	Timeout int    `json:"timeout"`         // timeout in seconds (when wait=true)
+	Until   string `json:"until,omitempty"` // wait strategy (when wait=true), see ParseWaitUntil
+}
+
+// Wait strategies for NavigateParams.Until.
+const (
+	WaitUntilLoad             = "load"
+	WaitUntilDOMContentLoaded = "domcontentloaded"
+	WaitUntilNetworkIdle      = "networkidle"
+	WaitUntilSelector         = "selector"
+	WaitUntilJS               = "js"
+)
+
+// ParseWaitUntil splits a wait strategy into its mode and argument. Accepted
+// forms are "load", "domcontentloaded", "networkidle", "selector:<css>", and
+// "js:<expr>"; an empty string means "load".
+func ParseWaitUntil(until string) (mode, arg string, err error) {
+	switch strings.ToLower(until) {
+	case "", WaitUntilLoad:
+		return WaitUntilLoad, "", nil
+	case WaitUntilDOMContentLoaded:
+		return WaitUntilDOMContentLoaded, "", nil
+	case WaitUntilNetworkIdle:
+		return WaitUntilNetworkIdle, "", nil
+	}
+
+	for _, mode := range []string{WaitUntilSelector, WaitUntilJS} {
+		if rest, ok := strings.CutPrefix(until, mode+":"); ok {
+			if strings.TrimSpace(rest) == "" {
+				return "", "", fmt.Errorf("--until %s: requires a value", mode)
+			}
+			return mode, rest, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("invalid --until %q: use load, domcontentloaded, networkidle, selector:<css>, or js:<expr>", until)
 }
 
 // NavigateData is the response data for the "navigate" command.
 type NavigateData struct {
 	URL   string `json:"url"`
 	Title string `json:"title"`
+	// Scroll is where the page was scrolled back to after a reload with
+	// PreserveScroll: short of the saved position if the page got shorter.
+	Scroll *ScrollPosition `json:"scroll,omitempty"`
 }
 
 // ReloadParams represents parameters for the "reload" command.
@@ -368,12 +659,233 @@ type ReloadParams struct {
 	IgnoreCache bool `json:"ignoreCache"`
 	Wait        bool `json:"wait"`    // wait for page load completion
 	Timeout     int  `json:"timeout"` // timeout in seconds (when wait=true)
+	// PreserveScroll saves the window's scroll position before reloading and
+	// restores it once the page has loaded. Implies Wait.
+	PreserveScroll bool `json:"preserveScroll,omitempty"`
+}
+
+// ScrollPosition is the window's scroll offset in CSS pixels.
+type ScrollPosition struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// ZoomParams represents parameters for the "zoom" command.
+type ZoomParams struct {
+	// Factor is the zoom to set, 1 for none; zero reports the current zoom
+	// without changing it.
+	Factor float64 `json:"factor,omitempty"`
+}
+
+// ZoomData is the response data for the "zoom" command.
+type ZoomData struct {
+	Factor float64 `json:"factor"`
+}
+
+// CacheParams represents parameters for the "cache" command.
+type CacheParams struct {
+	Action string `json:"action"` // "status", "disable", or "clear"
+	// Disabled, for "disable", turns the HTTP cache off in every tab, or back
+	// on when false.
+	Disabled bool `json:"disabled,omitempty"`
+	// Cookies and Storage, for "clear", also clear the active page origin's
+	// cookies and its site storage.
+	Cookies bool `json:"cookies,omitempty"`
+	Storage bool `json:"storage,omitempty"`
+}
+
+// CacheData is the response data for the "cache" command.
+type CacheData struct {
+	Disabled bool `json:"disabled"`
+	// Cleared lists what "clear" cleared: cache, cookies, storage.
+	Cleared []string `json:"cleared,omitempty"`
+	// Origin is the origin whose cookies or storage were cleared.
+	Origin string `json:"origin,omitempty"`
+}
+
+// EmulateParams represents parameters for the "emulate" command, which
+// overrides device state in the active tab.
+type EmulateParams struct {
+	Action string `json:"action"` // "idle" or "battery"
+	// Idle, for "idle", is the state reported to the Idle Detection API; nil
+	// clears the override.
+	Idle *IdleState `json:"idle,omitempty"`
+	// Battery, for "battery", is the state navigator.getBattery() reports; nil
+	// clears the override.
+	Battery *BatteryState `json:"battery,omitempty"`
+}
+
+// IdleState is an emulated user and screen idle state.
+type IdleState struct {
+	UserIdle     bool `json:"userIdle"`
+	ScreenLocked bool `json:"screenLocked"`
+}
+
+// BatteryState is an emulated battery.
+type BatteryState struct {
+	Level    float64 `json:"level"` // 0 to 1
+	Charging bool    `json:"charging"`
+}
+
+// EmulateData is the response data for the "emulate" command: the override
+// now in effect, absent when it was cleared.
+type EmulateData struct {
+	Idle    *IdleState    `json:"idle,omitempty"`
+	Battery *BatteryState `json:"battery,omitempty"`
+}
+
+// DevToolsData is the response data for the "devtools" command.
+type DevToolsData struct {
+	TargetID string `json:"targetId"` // the tab DevTools is inspecting
+	URL      string `json:"url"`      // the tab's page
+}
+
+// ThrottleParams represents parameters for the "throttle" command.
+type ThrottleParams struct {
+	Action string `json:"action"` // "status" or "cpu"
+	// Rate, for "cpu", is the CPU slowdown factor for every tab: 4 runs
+	// scripts and layout four times slower. 1 turns throttling off.
+	Rate float64 `json:"rate,omitempty"`
+}
+
+// ThrottleData is the response data for the "throttle" command.
+type ThrottleData struct {
+	CPU float64 `json:"cpu"` // CPU slowdown factor; 1 when not throttled
+}
+
+// BufferParams represents parameters for the "buffer" command.
+type BufferParams struct {
+	Action string `json:"action"` // "status" or "set"
+	// Buffer and Size resize a buffer ("console" or "network") for "set".
+	Buffer string `json:"buffer,omitempty"`
+	Size   int    `json:"size,omitempty"`
+	// CaptureBodies turns network body capture on or off for "set".
+	CaptureBodies *bool `json:"captureBodies,omitempty"`
+}
+
+// BufferData is the response data for the "buffer" command.
+type BufferData struct {
+	Buffers []BufferInfo `json:"buffers"`
+	// CaptureBodies reports whether network response and request bodies are
+	// fetched and stored.
+	CaptureBodies bool `json:"captureBodies"`
+	// Capture is the body capture policy from 'start --capture-bodies' and
+	// --max-capture-size, if any.
+	Capture string `json:"capture,omitempty"`
+}
+
+// BufferInfo describes the fill level of an event buffer.
+type BufferInfo struct {
+	Name     string `json:"name"` // "console" or "network"
+	Entries  int    `json:"entries"`
+	Capacity int    `json:"capacity"`
+	// Dropped counts the entries lost to a full buffer or a shrink since the
+	// daemon started or the buffer was cleared.
+	Dropped uint64 `json:"dropped"`
+}
+
+// ClearParams represents parameters for the "clear" command. The buffer to
+// clear is the request's Target.
+type ClearParams struct {
+	// Session, if set, is a tab query (session ID prefix or title); only that
+	// tab's entries are removed.
+	Session string `json:"session,omitempty"`
+}
+
+// ClearData is the response data for a "clear" scoped to one tab.
+type ClearData struct {
+	Session string `json:"session"` // the matched tab's session ID
+}
+
+// EventsParams represents parameters for the "events" command.
+type EventsParams struct {
+	// After returns only events with a higher Seq, for 'events follow'.
+	After uint64 `json:"after,omitempty"`
+}
+
+// EventsData is the response data for the "events" command.
+type EventsData struct {
+	Events []DaemonEvent `json:"events"`
+	// Dropped counts the events lost to the full buffer since the daemon
+	// started.
+	Dropped uint64 `json:"dropped"`
+}
+
+// DaemonEvent is an operational event in the daemon's own buffer: a target
+// attached or detached, the browser lost or restarted, the CDP connection
+// made, or a command that failed. Page console output is not included.
+type DaemonEvent struct {
+	Seq       uint64 `json:"seq"`
+	Timestamp int64  `json:"timestamp"` // Unix milliseconds
+	Level     string `json:"level"`     // "info", "warn", or "error"
+	// Type is a stable snake_case name, such as "target_attached" or
+	// "browser_restarted", for filtering.
+	Type    string         `json:"type"`
+	Message string         `json:"message"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+// ExtensionsParams represents parameters for the "extensions" command.
+type ExtensionsParams struct {
+	Action string `json:"action"` // "list"
+}
+
+// ExtensionsData is the response data for "extensions list".
+type ExtensionsData struct {
+	Extensions []ExtensionInfo `json:"extensions"`
+}
+
+// ExtensionInfo describes an extension in the browser: one loaded with
+// 'start --load-extension', or one found running in the profile.
+type ExtensionInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Path    string `json:"path,omitempty"` // set for extensions loaded with --load-extension
+	// Active is true when the extension has a service worker, background
+	// page, or other page open. An idle extension's service worker is
+	// stopped by Chrome until an event wakes it.
+	Active bool   `json:"active"`
+	Error  string `json:"error,omitempty"` // why its manifest could not be read
+}
+
+// BrowserModeParams represents parameters for the "browser-mode" command,
+// used by 'webctl head' and 'webctl headless'.
+type BrowserModeParams struct {
+	Headless bool `json:"headless"`
+}
+
+// BrowserModeData is the response data for the "browser-mode" command.
+type BrowserModeData struct {
+	Headless bool `json:"headless"`
+	// Changed is false when the browser was already in the requested mode
+	// and was left alone.
+	Changed bool `json:"changed"`
+	Tabs    int  `json:"tabs"`    // tabs reopened in the relaunched browser
+	Cookies int  `json:"cookies"` // cookies carried over
 }
 
 // HistoryParams represents parameters for the "back" and "forward" commands.
+// The "history" command also uses Action ("list" or "go") and Index.
 type HistoryParams struct {
-	Wait    bool `json:"wait"`    // wait for page load completion
-	Timeout int  `json:"timeout"` // timeout in seconds (when wait=true)
+	Wait    bool   `json:"wait"`             // wait for page load completion
+	Timeout int    `json:"timeout"`          // timeout in seconds (when wait=true)
+	Action  string `json:"action,omitempty"` // history: "list" (default) or "go"
+	Index   int    `json:"index,omitempty"`  // history go: target entry index
+}
+
+// HistoryEntry is one entry in the session's navigation history.
+type HistoryEntry struct {
+	Index   int    `json:"index"`
+	URL     string `json:"url"`
+	Title   string `json:"title,omitempty"`
+	Current bool   `json:"current,omitempty"`
+}
+
+// HistoryData is the response data for the "history" command (list action).
+type HistoryData struct {
+	CurrentIndex int            `json:"currentIndex"`
+	Entries      []HistoryEntry `json:"entries"`
 }
 
 // ReadyParams represents parameters for the "ready" command.
@@ -384,6 +896,21 @@ type ReadyParams struct {
 	Eval        string `json:"eval"`        // JavaScript expression to evaluate (optional)
 }
 
+// WaitRequestParams represents parameters for the "wait-request" command.
+type WaitRequestParams struct {
+	URL string `json:"url"` // regex the request URL must match
+	// StatusMin and StatusMax bound the response status, inclusive. Both zero
+	// accepts any status, and a failed request.
+	StatusMin int `json:"statusMin,omitempty"`
+	StatusMax int `json:"statusMax,omitempty"`
+	Timeout   int `json:"timeout"` // timeout in seconds
+}
+
+// WaitRequestData is the response data for the "wait-request" command.
+type WaitRequestData struct {
+	Entry NetworkEntry `json:"entry"`
+}
+
 // ClickParams represents parameters for the "click" command.
 type ClickParams struct {
 	Selector string `json:"selector"`
@@ -394,16 +921,638 @@ type FocusParams struct {
 	Selector string `json:"selector"`
 }
 
+// HighlightParams represents parameters for the "highlight" command.
+type HighlightParams struct {
+	Selector string `json:"selector"`
+	Duration int    `json:"duration"` // milliseconds to keep the overlay (0 = until replaced)
+}
+
+// HighlightData is the response data for the "highlight" command.
+type HighlightData struct {
+	Count int `json:"count"` // number of elements highlighted
+}
+
+// PickParams represents parameters for the "pick" command.
+type PickParams struct {
+	Timeout int `json:"timeout"` // seconds to wait for the user to click an element
+}
+
+// PickData is the response data for the "pick" command.
+type PickData struct {
+	ElementMeta
+	Selector string `json:"selector"` // CSS selector matching only the picked element
+	XPath    string `json:"xpath"`    // XPath matching only the picked element
+}
+
+// SelectorParams represents parameters for the "selector" command. The
+// element is the first match of From, or when From is empty the topmost
+// element at X, Y (viewport CSS pixels).
+type SelectorParams struct {
+	From string  `json:"from,omitempty"`
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+}
+
+// SelectorCandidate is one generated selector for an element.
+type SelectorCandidate struct {
+	Selector string `json:"selector"`
+	// Strategy is how the selector was built: "id", "test-id", "name",
+	// "aria-label", "attribute", "class", "anchored", or "path".
+	Strategy string `json:"strategy"`
+	Matches  int    `json:"matches"` // elements the selector matches, including this one
+	Unique   bool   `json:"unique"`  // the selector matches only this element
+}
+
+// SelectorData is the response data for the "selector" command. Candidates
+// are ranked most robust first, unique selectors before ambiguous ones.
+type SelectorData struct {
+	ElementMeta
+	FromMatches int                 `json:"fromMatches,omitempty"` // elements From matched
+	Candidates  []SelectorCandidate `json:"candidates"`
+	XPath       string              `json:"xpath"`
+}
+
+// BoxParams represents parameters for the "box" command.
+type BoxParams struct {
+	Selector string `json:"selector"`
+}
+
+// ElementBox is the geometry and visibility of one element. X, Y, Width, and
+// Height come from getBoundingClientRect and are viewport-relative CSS pixels;
+// PageX and PageY add the window scroll offset to give document coordinates.
+type ElementBox struct {
+	ElementMeta
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+	PageX  float64 `json:"pageX"`
+	PageY  float64 `json:"pageY"`
+	// Visible reports the element is rendered: it has a non-zero box and is not
+	// hidden by display, visibility, or zero opacity.
+	Visible bool `json:"visible"`
+	// InViewport reports that any part of the box intersects the viewport.
+	InViewport bool `json:"inViewport"`
+	// FullyInViewport reports that the whole box lies within the viewport.
+	FullyInViewport bool `json:"fullyInViewport"`
+	// ScrollTop and ScrollLeft are the element's own scroll offsets, non-zero
+	// only for scrolled containers.
+	ScrollTop  float64 `json:"scrollTop"`
+	ScrollLeft float64 `json:"scrollLeft"`
+}
+
+// BoxData is the response data for the "box" command.
+type BoxData struct {
+	Elements       []ElementBox `json:"elements"`
+	ScrollX        float64      `json:"scrollX"`
+	ScrollY        float64      `json:"scrollY"`
+	ViewportWidth  float64      `json:"viewportWidth"`
+	ViewportHeight float64      `json:"viewportHeight"`
+}
+
+// AttrParams represents parameters for the "attr" command.
+type AttrParams struct {
+	Action   string `json:"action"` // "get", "set", or "list"
+	Selector string `json:"selector"`
+	Name     string `json:"name,omitempty"`  // attribute name for get/set
+	Value    string `json:"value,omitempty"` // attribute value for set
+}
+
+// ElementWithAttributes combines element metadata with its attributes.
+type ElementWithAttributes struct {
+	ElementMeta
+	Attributes map[string]string `json:"attributes"`
+}
+
+// AttrData is the response data for the "attr" command.
+type AttrData struct {
+	Value    string                  `json:"value,omitempty"`    // For get action
+	Elements []ElementWithAttributes `json:"elements,omitempty"` // For list action
+	Count    int                     `json:"count,omitempty"`    // Elements changed by set
+}
+
+// CountParams represents parameters for the "count" command.
+type CountParams struct {
+	Selector string `json:"selector"`
+}
+
+// CountData is the response data for the "count" command.
+type CountData struct {
+	Count int `json:"count"`
+}
+
+// ElementsParams represents parameters for the "elements" command.
+type ElementsParams struct {
+	// Types limits the listing to these element types; empty lists every type.
+	Types []string `json:"types,omitempty"`
+}
+
+// InteractiveElement is one clickable or focusable element on the page.
+type InteractiveElement struct {
+	// Type is "link", "button", "input", "checkbox", "radio", "select", "tab",
+	// "menuitem", or "other" (onclick handlers and tabindex).
+	Type     string `json:"type"`
+	Tag      string `json:"tag"`
+	Name     string `json:"name"` // accessible name
+	Selector string `json:"selector"`
+	Href     string `json:"href,omitempty"`
+	Disabled bool   `json:"disabled"`
+	// Visible is false for elements not rendered or hidden by CSS.
+	Visible    bool `json:"visible"`
+	InViewport bool `json:"inViewport"`
+}
+
+// ElementsData is the response data for the "elements" command. Elements are
+// in document order.
+type ElementsData struct {
+	Elements []InteractiveElement `json:"elements"`
+}
+
+// WatchDOMParams represents parameters for the "watch-dom" command.
+// The CLI polls with the same WatchID; each poll drains mutations recorded
+// since the previous one.
+type WatchDOMParams struct {
+	Selector string `json:"selector,omitempty"` // root element to observe (default: whole document)
+	WatchID  string `json:"watchId"`            // identifies the observer across polls
+	Stop     bool   `json:"stop,omitempty"`     // disconnect the observer
+}
+
+// DOMMutation is a single recorded DOM change.
+type DOMMutation struct {
+	Time      int64   `json:"time"`                // Unix milliseconds
+	Type      string  `json:"type"`                // "added", "removed", "attribute", or "text"
+	Target    string  `json:"target"`              // element that changed (or parent, for added/removed/text)
+	Node      string  `json:"node,omitempty"`      // added or removed node
+	Attribute string  `json:"attribute,omitempty"` // attribute name for "attribute"
+	OldValue  *string `json:"oldValue,omitempty"`  // previous attribute/text value; nil if absent
+	Value     *string `json:"value,omitempty"`     // new attribute/text value; nil if removed
+}
+
+// WatchDOMData is the response data for the "watch-dom" command.
+type WatchDOMData struct {
+	Status    string        `json:"status"`              // "watching", "waiting" (root not found), or "stopped"
+	Installed bool          `json:"installed,omitempty"` // observer was (re)installed by this poll
+	Dropped   int           `json:"dropped,omitempty"`   // mutations discarded because the buffer was full
+	Mutations []DOMMutation `json:"mutations"`
+}
+
+// PerfParams represents parameters for the "perf" command. For "shifts" the
+// CLI polls with the same WatchID; each poll drains the layout shifts
+// recorded since the previous one.
+type PerfParams struct {
+	Action  string `json:"action"`         // "shifts"
+	WatchID string `json:"watchId"`        // identifies the observer across polls
+	Stop    bool   `json:"stop,omitempty"` // disconnect the observer
+}
+
+// LayoutShiftRect is an element's box before or after a layout shift, in
+// viewport-relative CSS pixels.
+type LayoutShiftRect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// LayoutShiftSource is an element that moved in a layout shift.
+type LayoutShiftSource struct {
+	Node     string          `json:"node"` // element description, e.g. "img.hero"; empty if since removed
+	Previous LayoutShiftRect `json:"previous"`
+	Current  LayoutShiftRect `json:"current"`
+}
+
+// LayoutShift is one layout-shift performance entry.
+type LayoutShift struct {
+	Time      int64   `json:"time"`      // Unix milliseconds
+	StartTime float64 `json:"startTime"` // milliseconds since the page started loading
+	Score     float64 `json:"score"`
+	// HadRecentInput marks shifts within 500ms of user input, which do not
+	// count towards CLS.
+	HadRecentInput bool                `json:"hadRecentInput,omitempty"`
+	Sources        []LayoutShiftSource `json:"sources,omitempty"`
+}
+
+// PerfShiftsData is the response data for "perf" with action "shifts".
+type PerfShiftsData struct {
+	Installed bool `json:"installed,omitempty"` // observer was (re)installed by this poll
+	Dropped   int  `json:"dropped,omitempty"`   // shifts discarded because the buffer was full
+	// Total is the sum of scores without recent input since the observer was
+	// installed: the page's layout shift total, not its windowed CLS.
+	Total  float64       `json:"total"`
+	Shifts []LayoutShift `json:"shifts"`
+	// CPUThrottle is the CPU slowdown in effect ('throttle cpu'); absent when
+	// the CPU is not throttled.
+	CPUThrottle float64 `json:"cpuThrottle,omitempty"`
+}
+
+// HeapParams represents parameters for the "heap" command.
+type HeapParams struct {
+	Action string `json:"action"` // "leakcheck" or "snapshot"
+	// Cycles is how many times leakcheck runs the action and samples the heap.
+	Cycles int `json:"cycles,omitempty"`
+	// Expression is the JavaScript leakcheck evaluates each cycle, awaiting a
+	// returned promise. Empty reloads the page instead.
+	Expression string `json:"expression,omitempty"`
+	// Settle is how long to wait after each action before sampling, in
+	// milliseconds, so timers and requests it starts can finish.
+	Settle int `json:"settle,omitempty"`
+	// Top caps the constructors reported.
+	Top int `json:"top,omitempty"`
+}
+
+// HeapSnapshotData is the response data for "heap" with action "snapshot",
+// from daemons that cannot stream it. Streamed, the body is the snapshot
+// itself.
+type HeapSnapshotData struct {
+	Snapshot json.RawMessage `json:"snapshot"` // V8 .heapsnapshot JSON
+}
+
+// HeapSample is the JavaScript heap after one leakcheck cycle, sampled after
+// a forced garbage collection.
+type HeapSample struct {
+	Cycle     int   `json:"cycle"`
+	UsedSize  int64 `json:"usedSize"` // bytes
+	TotalSize int64 `json:"totalSize"`
+}
+
+// HeapConstructorGrowth is how much one constructor's live objects grew
+// between the heap snapshots after the first and the last cycle.
+type HeapConstructorGrowth struct {
+	Name       string `json:"name"`
+	Count      int    `json:"count"`      // objects in the last snapshot
+	Size       int64  `json:"size"`       // their shallow size in bytes
+	CountDelta int    `json:"countDelta"` // objects added since the first snapshot
+	SizeDelta  int64  `json:"sizeDelta"`
+}
+
+// HeapLeakcheckData is the response data for "heap" with action "leakcheck".
+type HeapLeakcheckData struct {
+	Action  string       `json:"action"` // "reload" or the expression
+	Samples []HeapSample `json:"samples"`
+	// Growth is the used heap added from the first sample to the last.
+	Growth int64 `json:"growth"`
+	// Monotonic reports that the used heap grew on every cycle after the
+	// first, the signature of a leak rather than noise.
+	Monotonic    bool                    `json:"monotonic"`
+	Constructors []HeapConstructorGrowth `json:"constructors"`
+}
+
+// BannerRule is a 'webctl dismiss-banners' rule for one consent or
+// newsletter overlay. Rules are also read from YAML, whose keys are the
+// lowercased field names.
+type BannerRule struct {
+	Name string `json:"name"`
+	// Hosts limits the rule to these sites and their subdomains; empty
+	// applies it everywhere.
+	Hosts []string `json:"hosts,omitempty"`
+	// Click lists the buttons that accept or close the overlay; the first
+	// visible one is clicked.
+	Click []string `json:"click,omitempty"`
+	// Hide lists the elements to hide, for overlays no button dismisses.
+	Hide []string `json:"hide,omitempty"`
+}
+
+// DismissBannersParams represents parameters for the "dismiss-banners"
+// command.
+type DismissBannersParams struct {
+	Rules []BannerRule `json:"rules"`
+	// Auto, when set, turns automatic dismissal on every page load in every
+	// tab on or off. Turning it on also dismisses banners in the active tab.
+	Auto *bool `json:"auto,omitempty"`
+}
+
+// BannerHit is an overlay element a rule acted on.
+type BannerHit struct {
+	Rule     string `json:"rule"`
+	Action   string `json:"action"` // "clicked" or "hidden"
+	Selector string `json:"selector"`
+}
+
+// DismissBannersData is the response data for the "dismiss-banners" command.
+type DismissBannersData struct {
+	Dismissed []BannerHit `json:"dismissed"`
+	Auto      bool        `json:"auto"` // automatic dismissal is on
+}
+
+// PoolParams represents parameters for the "pool" command, which manages a
+// set of background tabs that load URLs in parallel.
+type PoolParams struct {
+	Action string `json:"action"`         // "create", "status", "map", or "close"
+	Size   int    `json:"size,omitempty"` // tabs to create
+	// URL is the page "map" loads in a free pool tab before running Command
+	// on it with Arg.
+	URL     string `json:"url,omitempty"`
+	Command string `json:"command,omitempty"` // "text", "html", "title", "count", or "eval"
+	Arg     string `json:"arg,omitempty"`     // selector, or expression for "eval"
+	Timeout int    `json:"timeout,omitempty"` // milliseconds for one URL
+}
+
+// Pool map commands: what "pool map" reads from each page.
+const (
+	PoolCommandText  = "text"  // innerText of a selector (default body)
+	PoolCommandHTML  = "html"  // outerHTML of a selector (default html)
+	PoolCommandTitle = "title" // the page title
+	PoolCommandCount = "count" // how many elements match a selector
+	PoolCommandEval  = "eval"  // the value of an expression, awaited
+)
+
+// ParsePoolCommand splits a pool map command line such as "text body" into
+// the command and its argument.
+func ParsePoolCommand(line string) (command, arg string, err error) {
+	command, arg, _ = strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+	switch command {
+	case PoolCommandText, PoolCommandHTML:
+		return command, arg, nil
+	case PoolCommandTitle:
+		if arg != "" {
+			return "", "", fmt.Errorf("%s takes no argument", command)
+		}
+		return command, "", nil
+	case PoolCommandCount, PoolCommandEval:
+		if arg == "" {
+			return "", "", fmt.Errorf("%s requires an argument", command)
+		}
+		return command, arg, nil
+	}
+	return "", "", fmt.Errorf("invalid command %q: use text [selector], html [selector], title, count <selector>, or eval <js>", line)
+}
+
+// PoolData is the response data for "pool" actions other than "map".
+type PoolData struct {
+	Size int `json:"size"` // tabs in the pool; zero when there is none
+	Busy int `json:"busy"` // tabs running a map
+}
+
+// PoolMapResult is the result of running a command on one URL in a pool tab.
+type PoolMapResult struct {
+	URL        string          `json:"url"`
+	OK         bool            `json:"ok"`
+	Value      json.RawMessage `json:"value,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	DurationMs int64           `json:"durationMs"`
+}
+
+// BenchParams represents parameters for the "bench" command: one measured
+// load of URL in the active tab.
+type BenchParams struct {
+	URL string `json:"url"`
+	// KeepCache skips clearing the browser's HTTP cache before the load, to
+	// measure a warm load.
+	KeepCache bool `json:"keepCache,omitempty"`
+	// Settle is how long to wait after the load event before measuring, in
+	// milliseconds, so late paints and layout shifts are counted.
+	Settle  int `json:"settle,omitempty"`
+	Timeout int `json:"timeout,omitempty"` // seconds to wait for the load event
+}
+
+// BenchMetrics are the measurements of one page load. Times are
+// milliseconds since the navigation started; zero when the page never
+// reached the milestone.
+type BenchMetrics struct {
+	TTFB             float64 `json:"ttfb"`
+	FCP              float64 `json:"fcp"`
+	LCP              float64 `json:"lcp"`
+	DOMContentLoaded float64 `json:"domContentLoaded"`
+	Load             float64 `json:"load"`
+	CLS              float64 `json:"cls"`      // largest session window of layout shifts
+	Requests         int     `json:"requests"` // the document and every resource it loaded
+	// TransferSize is the bytes fetched over the network as the page sees
+	// them: cached and cross-origin resources without Timing-Allow-Origin
+	// count as zero.
+	TransferSize int64 `json:"transferSize"`
+	JSHeapUsed   int64 `json:"jsHeapUsed"`
+}
+
+// BenchData is the response data for the "bench" command.
+type BenchData struct {
+	URL     string       `json:"url"` // URL after redirects
+	Metrics BenchMetrics `json:"metrics"`
+	// CPUThrottle is the CPU slowdown the load ran under ('throttle cpu');
+	// absent when the CPU is not throttled.
+	CPUThrottle float64 `json:"cpuThrottle,omitempty"`
+}
+
+// RecordParams represents parameters for the "record" command.
+type RecordParams struct {
+	Action string `json:"action"` // "start", "stop", or "status"
+}
+
+// RecordedStep is one user interaction captured while recording.
+type RecordedStep struct {
+	Time     int64  `json:"time"`               // Unix milliseconds
+	Action   string `json:"action"`             // "navigate", "click", "fill", or "key"
+	URL      string `json:"url,omitempty"`      // navigate
+	Selector string `json:"selector,omitempty"` // click, fill
+	Text     string `json:"text,omitempty"`     // fill; empty for a secret
+	Secret   bool   `json:"secret,omitempty"`   // fill of a password field, whose value is not recorded
+	Key      string `json:"key,omitempty"`      // key
+}
+
+// RecordData is the response data for the "record" command. Stop returns the
+// steps recorded and ends the recording.
+type RecordData struct {
+	Recording bool           `json:"recording"`
+	SessionID string         `json:"sessionId,omitempty"` // tab being recorded
+	Steps     []RecordedStep `json:"steps"`
+}
+
+// FrameInfo describes one frame in the page's frame tree.
+type FrameInfo struct {
+	ID             string `json:"id"`
+	ParentID       string `json:"parentId,omitempty"`
+	URL            string `json:"url"`
+	Name           string `json:"name,omitempty"`
+	SecurityOrigin string `json:"securityOrigin,omitempty"`
+	Depth          int    `json:"depth"` // 0 for the main frame
+}
+
+// FramesData is the response data for the "frames" command.
+// Frames are listed depth-first, each parent before its children.
+type FramesData struct {
+	Frames []FrameInfo `json:"frames"`
+}
+
+// DOMParams represents parameters for the "dom" command.
+type DOMParams struct {
+	Action string `json:"action"` // "snapshot"
+	// ComputedStyles names the CSS properties to capture for each rendered
+	// node (snapshot action).
+	ComputedStyles []string `json:"computedStyles,omitempty"`
+}
+
+// DOMBox is a node's layout box in CSS pixels, relative to the document.
+type DOMBox struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// DOMNode is one node of a DOM snapshot.
+type DOMNode struct {
+	Index  int    `json:"index"`  // position in the snapshot
+	Parent int    `json:"parent"` // parent's index, -1 for the document
+	Depth  int    `json:"depth"`  // 0 for the document
+	Type   string `json:"type"`   // "document", "element", or "text"
+	Name   string `json:"name"`   // lowercase tag name, "#document", or "#text"
+	Value  string `json:"value,omitempty"`
+	// Attributes holds an element's attributes.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Box is absent for nodes that are not rendered (display: none, <head>).
+	Box *DOMBox `json:"box,omitempty"`
+	// Styles holds the requested computed styles of a rendered node.
+	Styles map[string]string `json:"styles,omitempty"`
+}
+
+// DOMSnapshotData is the response data for the "dom snapshot" command.
+// Nodes are listed in document order, each parent before its children.
+type DOMSnapshotData struct {
+	URL   string    `json:"url"`
+	Title string    `json:"title,omitempty"`
+	Nodes []DOMNode `json:"nodes"`
+}
+
+// DescribeParams represents parameters for the "describe" command.
+type DescribeParams struct {
+	// Limit caps the controls listed; 0 means the daemon's default of 50.
+	Limit int `json:"limit,omitempty"`
+}
+
+// DescribeLandmark is a page region: header, nav, main, aside, footer, or an
+// element with a landmark role.
+type DescribeLandmark struct {
+	Role     string `json:"role"` // ARIA landmark role, e.g. "navigation"
+	Label    string `json:"label,omitempty"`
+	Selector string `json:"selector"`
+	Depth    int    `json:"depth"` // landmarks nested inside other landmarks
+}
+
+// DescribeHeading is a visible h1-h6 or role="heading" element.
+type DescribeHeading struct {
+	Level    int    `json:"level"`
+	Text     string `json:"text"`
+	Selector string `json:"selector"`
+}
+
+// DescribeControl is a visible link, button, or other clickable element.
+type DescribeControl struct {
+	Role     string `json:"role"` // "link", "button", "tab", "checkbox", ...
+	Name     string `json:"name"` // accessible name
+	Selector string `json:"selector"`
+	Href     string `json:"href,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// DescribeField is a visible form field.
+type DescribeField struct {
+	Type     string `json:"type"` // input type, "select", or "textarea"
+	Name     string `json:"name"` // label text, or the name attribute
+	Selector string `json:"selector"`
+	// Value is the current value; omitted for passwords.
+	Value    string   `json:"value,omitempty"`
+	Checked  bool     `json:"checked,omitempty"`
+	Required bool     `json:"required,omitempty"`
+	Disabled bool     `json:"disabled,omitempty"`
+	Options  []string `json:"options,omitempty"` // a select's option labels
+}
+
+// DescribeForm groups the fields of one form. Fields outside any <form> are
+// grouped under a form with an empty selector.
+type DescribeForm struct {
+	Selector string          `json:"selector"`
+	Name     string          `json:"name,omitempty"`
+	Action   string          `json:"action,omitempty"`
+	Fields   []DescribeField `json:"fields"`
+}
+
+// DescribeData is the response data for the "describe" command.
+type DescribeData struct {
+	URL       string             `json:"url"`
+	Title     string             `json:"title"`
+	Landmarks []DescribeLandmark `json:"landmarks"`
+	Headings  []DescribeHeading  `json:"headings"`
+	Controls  []DescribeControl  `json:"controls"`
+	// MoreControls counts the visible controls left out by the limit.
+	MoreControls int            `json:"moreControls,omitempty"`
+	Forms        []DescribeForm `json:"forms"`
+}
+
+// MetaTag is one <meta> property and its content.
+type MetaTag struct {
+	Property string `json:"property"`
+	Content  string `json:"content"`
+}
+
+// MetaAlternate is a <link rel="alternate" hreflang> translation link.
+type MetaAlternate struct {
+	Lang string `json:"lang"`
+	URL  string `json:"url"`
+}
+
+// MetaItem is a top-level microdata item. Property values are strings, or
+// nested items for properties that have their own itemscope.
+type MetaItem struct {
+	Type       []string         `json:"type,omitempty"`
+	ID         string           `json:"id,omitempty"`
+	Properties map[string][]any `json:"properties"`
+}
+
+// MetaData is the response data for the "meta" command. URLs are resolved
+// against the document.
+type MetaData struct {
+	URL         string          `json:"url"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Canonical   string          `json:"canonical,omitempty"`
+	Robots      string          `json:"robots,omitempty"`
+	Hreflang    []MetaAlternate `json:"hreflang"`
+	// OpenGraph holds og: (and article:, product:, ...) properties and
+	// Twitter the twitter: card names, in document order with repeats.
+	OpenGraph []MetaTag `json:"openGraph"`
+	Twitter   []MetaTag `json:"twitter"`
+	// JSONLD holds each application/ld+json block's parsed value; blocks that
+	// fail to parse are reported in JSONLDErrors instead.
+	JSONLD       []json.RawMessage `json:"jsonLd"`
+	JSONLDErrors []string          `json:"jsonLdErrors,omitempty"`
+	Microdata    []MetaItem        `json:"microdata"`
+}
+
 // TypeParams represents parameters for the "type" command.
 type TypeParams struct {
 	Selector string `json:"selector,omitempty"`
 	Text     string `json:"text"`
 	Key      string `json:"key,omitempty"`
 	Clear    bool   `json:"clear,omitempty"`
+	// DelayMin and DelayMax, in milliseconds, make the text go in as one key
+	// press per character with a random pause between them, instead of being
+	// inserted at once. Zero inserts at once.
+	DelayMin int `json:"delayMin,omitempty"`
+	DelayMax int `json:"delayMax,omitempty"`
+	// IME enters the text as an input method would: composed one character
+	// at a time, then committed, firing composition events. The delay, if
+	// any, is the pause between composition updates.
+	IME bool `json:"ime,omitempty"`
 }
 
 // KeyParams represents parameters for the "key" command.
 type KeyParams struct {
+	Key   string `json:"key,omitempty"`
+	Ctrl  bool   `json:"ctrl,omitempty"`
+	Alt   bool   `json:"alt,omitempty"`
+	Shift bool   `json:"shift,omitempty"`
+	Meta  bool   `json:"meta,omitempty"`
+	// Sequence, when set, is pressed in order instead of Key and its
+	// modifiers.
+	Sequence []KeyChord `json:"sequence,omitempty"`
+	// Repeat presses the key or sequence this many times. Default 1.
+	Repeat int `json:"repeat,omitempty"`
+	// Delay is the pause between presses in milliseconds.
+	Delay int `json:"delay,omitempty"`
+}
+
+// KeyChord is a key pressed with modifiers held, one step of a key sequence.
+type KeyChord struct {
 	Key   string `json:"key"`
 	Ctrl  bool   `json:"ctrl,omitempty"`
 	Alt   bool   `json:"alt,omitempty"`
@@ -411,10 +1560,40 @@ type KeyParams struct {
 	Meta  bool   `json:"meta,omitempty"`
 }
 
-// SelectParams represents parameters for the "select" command.
+// SelectParams represents parameters for the "select" command. The options
+// matching Value, Values, and Labels together become the selection.
 type SelectParams struct {
+	Selector string   `json:"selector"`
+	Value    string   `json:"value,omitempty"`
+	Values   []string `json:"values,omitempty"`
+	// Labels match options by their visible text, ignoring surrounding and
+	// repeated whitespace.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// SelectedOption is an option of a <select> that is selected.
+type SelectedOption struct {
+	Index int    `json:"index"`
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// SelectData is the response data for the "select" command.
+type SelectData struct {
+	Selected []SelectedOption `json:"selected"`
+}
+
+// CheckParams represents parameters for the "check" command, which sets a
+// checkbox or radio button to Checked.
+type CheckParams struct {
 	Selector string `json:"selector"`
-	Value    string `json:"value"`
+	Checked  bool   `json:"checked"`
+}
+
+// CheckData is the response data for the "check" command.
+type CheckData struct {
+	Checked bool `json:"checked"`
+	Changed bool `json:"changed"` // false when it was already in that state
 }
 
 // ScrollParams represents parameters for the "scroll" command.
@@ -424,13 +1603,57 @@ type ScrollParams struct {
 	ToY      int    `json:"toY,omitempty"`
 	ByX      int    `json:"byX,omitempty"`
 	ByY      int    `json:"byY,omitempty"`
-	Mode     string `json:"mode"` // "element", "to", or "by"
+	Mode     string `json:"mode"` // "element", "to", "by", "top", "bottom", or "page"
+	// Pages is the number of viewport heights "page" mode scrolls, negative
+	// to scroll up.
+	Pages int `json:"pages,omitempty"`
+	// Smooth scrolls with the page's smooth behavior and waits for the
+	// scroll to settle.
+	Smooth bool `json:"smooth,omitempty"`
+	// UntilIdle repeats a "bottom" scroll until the page height stops
+	// growing for Idle milliseconds, or fails after Timeout milliseconds.
+	UntilIdle bool `json:"untilIdle,omitempty"`
+	Idle      int  `json:"idle,omitempty"`
+	Timeout   int  `json:"timeout,omitempty"`
+}
+
+// ScrollData is the response data for an UntilIdle scroll.
+type ScrollData struct {
+	Scrolls int `json:"scrolls"` // bottom scrolls made
+	Height  int `json:"height"`  // final page height in CSS pixels
+}
+
+// MouseParams represents parameters for the "mouse" command.
+type MouseParams struct {
+	Action string `json:"action"` // "move", "down", "up", "wheel", or "position"
+	// X and Y are the viewport position "move" goes to, in CSS pixels.
+	X int `json:"x,omitempty"`
+	Y int `json:"y,omitempty"`
+	// Steps is the number of mouseMoved events "move" spreads the path over,
+	// for gestures that track the pointer along the way. Default 1.
+	Steps int `json:"steps,omitempty"`
+	// Button is "left", "middle", or "right" for "down" and "up". Default left.
+	Button string `json:"button,omitempty"`
+	// DeltaX and DeltaY are the "wheel" scroll amounts in CSS pixels.
+	DeltaX int `json:"deltaX,omitempty"`
+	DeltaY int `json:"deltaY,omitempty"`
+}
+
+// MouseData is the response data for the "mouse" command: the pointer's
+// position and held buttons after the action.
+type MouseData struct {
+	X       int      `json:"x"`
+	Y       int      `json:"y"`
+	Buttons []string `json:"buttons"`
 }
 
 // EvalParams represents parameters for the "eval" command.
 type EvalParams struct {
 	Expression string `json:"expression"`
 	Timeout    int    `json:"timeout,omitempty"` // timeout in seconds
+	// REPLMode evaluates as a console does: top-level let, const, and class
+	// declarations persist for later evaluations and may be redeclared.
+	REPLMode bool `json:"replMode,omitempty"`
 }
 
 // EvalData is the response data for the "eval" command.
@@ -480,9 +1703,12 @@ type CookiesData struct {
 
 // CSSParams represents parameters for the "css" command.
 type CSSParams struct {
-	Action   string `json:"action"`             // "save", "computed", "get", "inline", or "matched"
-	Selector string `json:"selector,omitempty"` // CSS selector for computed/get/inline/matched
+	Action   string `json:"action"`             // "save", "computed", "get", "inline", "matched", "diff", "list", "dump", or "unused"
+	Selector string `json:"selector,omitempty"` // CSS selector for computed/get/inline/matched/diff
 	Property string `json:"property,omitempty"` // CSS property for get action
+	Other    string `json:"other,omitempty"`    // Second selector for diff action
+	Snapshot string `json:"snapshot,omitempty"` // "before" or "after" for diff action snapshots
+	Sheet    string `json:"sheet,omitempty"`    // Stylesheet index, ID, or URL substring for dump (empty = all)
 }
 
 // ElementMeta contains element identification metadata extracted from DOM elements.
@@ -517,6 +1743,47 @@ type CSSData struct {
 	InlineMulti   []ElementWithStyles `json:"inlineMulti,omitempty"`   // For inline action (with metadata)
 	Inline        []string            `json:"inline,omitempty"`        // Deprecated: For inline action (style attributes only)
 	Matched       []CSSMatchedRule    `json:"matched,omitempty"`       // For matched action
+	Diff          []CSSPropertyDiff   `json:"diff,omitempty"`          // For diff action
+	StyleSheets   []CSSStyleSheet     `json:"styleSheets,omitempty"`   // For list/dump actions
+	Unused        []CSSUnusedSheet    `json:"unused,omitempty"`        // For unused action
+}
+
+// CSSUnusedSheet reports the style rules of one stylesheet that do not apply
+// to the current page state.
+type CSSUnusedSheet struct {
+	CSSStyleSheet
+	StyleRules  int             `json:"styleRules"`  // style rules tracked in this sheet
+	UnusedRules int             `json:"unusedRules"` // style rules never matched
+	UnusedBytes int             `json:"unusedBytes"` // source bytes of unused rules
+	Selectors   []CSSUnusedRule `json:"selectors"`
+}
+
+// CSSUnusedRule is a single unused style rule.
+type CSSUnusedRule struct {
+	Selector string `json:"selector"`
+	Bytes    int    `json:"bytes"`
+}
+
+// CSSStyleSheet describes a stylesheet attached to the page, as reported by the
+// CDP CSS domain. Index is 1-based in document order and is stable only until
+// stylesheets are added or removed.
+type CSSStyleSheet struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id"`             // CDP styleSheetId
+	URL    string `json:"url,omitempty"`  // empty for inline <style> and constructed sheets
+	Origin string `json:"origin"`         // "regular", "injected", "inspector"
+	Inline bool   `json:"inline"`         // true for <style> elements
+	Size   int    `json:"size"`           // bytes of source text
+	Rules  int    `json:"rules"`          // top-level rule count
+	Text   string `json:"text,omitempty"` // source text, dump action only
+}
+
+// CSSPropertyDiff is a computed property whose value differs between two
+// elements (A and B) or two states of one element (before and after).
+type CSSPropertyDiff struct {
+	Property string `json:"property"`
+	A        string `json:"a"`
+	B        string `json:"b"`
 }
 
 // CSSMatchedRule represents a CSS rule matched to an element.
@@ -526,11 +1793,44 @@ type CSSMatchedRule struct {
 	Source     string            `json:"source,omitempty"` // stylesheet URL or "inline"
 }
 
+// SourceParams represents parameters for the "source" command.
+type SourceParams struct {
+	Action     string `json:"action"`               // "list" or "get"
+	Query      string `json:"query,omitempty"`      // scriptId or URL pattern for get
+	SourceMaps bool   `json:"sourceMaps,omitempty"` // Resolve source maps to original files (get)
+}
+
+// SourceData represents the response data for the "source" command.
+type SourceData struct {
+	Scripts []ScriptSource `json:"scripts"`
+}
+
+// ScriptSource describes a script loaded by the page, as reported by the CDP
+// Debugger domain. Source and Originals are filled by the get action only.
+type ScriptSource struct {
+	ID             string           `json:"id"`                       // CDP scriptId
+	URL            string           `json:"url"`                      // script URL; the document URL for inline scripts
+	Size           int              `json:"size"`                     // bytes of source text
+	SourceMapURL   string           `json:"sourceMapURL,omitempty"`   // as declared by the script, unresolved
+	Source         string           `json:"source,omitempty"`         // source text, get action only
+	Originals      []OriginalSource `json:"originals,omitempty"`      // source map sources, with --source-maps
+	SourceMapError string           `json:"sourceMapError,omitempty"` // why the source map could not be resolved
+}
+
+// OriginalSource is one original file listed in a script's source map.
+// Missing is set when the map has no sourcesContent entry for it.
+type OriginalSource struct {
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+	Missing bool   `json:"missing,omitempty"`
+}
+
 // ServeParams represents parameters for the "serve" command.
 type ServeParams struct {
 	Action      string   `json:"action"`                // "start" or "stop"
 	Mode        string   `json:"mode,omitempty"`        // "static" or "proxy"
 	Directory   string   `json:"directory,omitempty"`   // Directory to serve (static mode)
+	SPA         bool     `json:"spa,omitempty"`         // Serve index.html for unknown routes (static mode)
 	ProxyURL    string   `json:"proxyURL,omitempty"`    // Backend URL to proxy (proxy mode)
 	Port        int      `json:"port,omitempty"`        // Server port (0 = auto-detect)
 	Host        string   `json:"host,omitempty"`        // Bind host ("localhost" or "0.0.0.0")
@@ -546,6 +1846,265 @@ type ServeData struct {
 	Port    int    `json:"port,omitempty"`
 }
 
+// MonitorParams represents parameters for the "monitor" command.
+type MonitorParams struct {
+	Action string `json:"action"` // "add", "list", "results", or "remove"
+	// ID names the monitor for "results" and "remove".
+	ID string `json:"id,omitempty"`
+
+	// URL, Every, and the rest configure a monitor for "add".
+	URL        string   `json:"url,omitempty"`
+	Every      int64    `json:"every,omitempty"`   // milliseconds between checks
+	Timeout    int64    `json:"timeout,omitempty"` // milliseconds each check may take
+	Screenshot bool     `json:"screenshot,omitempty"`
+	Assert     []string `json:"assert,omitempty"` // see ParseMonitorAssert
+	Webhook    string   `json:"webhook,omitempty"`
+
+	// Limit caps "results" to the most recent checks (0 = all kept).
+	Limit int `json:"limit,omitempty"`
+}
+
+// Monitor assertion kinds. A check passes when every assertion holds.
+const (
+	MonitorAssertSelector = "selector" // an element matches the CSS selector
+	MonitorAssertText     = "text"     // the page text contains the value
+	MonitorAssertTitle    = "title"    // the page title contains the value
+	MonitorAssertURL      = "url"      // the final URL contains the value
+	MonitorAssertJS       = "js"       // the expression is truthy
+)
+
+// ParseMonitorAssert splits a monitor assertion of the form "<kind>:<value>"
+// into its kind and value.
+func ParseMonitorAssert(assert string) (kind, value string, err error) {
+	kind, value, ok := strings.Cut(assert, ":")
+	switch kind {
+	case MonitorAssertSelector, MonitorAssertText, MonitorAssertTitle, MonitorAssertURL, MonitorAssertJS:
+		if !ok || strings.TrimSpace(value) == "" {
+			return "", "", fmt.Errorf("--assert %s: requires a value", kind)
+		}
+		return kind, value, nil
+	}
+	return "", "", fmt.Errorf("invalid --assert %q: use selector:<css>, text:<text>, title:<text>, url:<text>, or js:<expr>", assert)
+}
+
+// MonitorResult is the outcome of one monitor check.
+type MonitorResult struct {
+	Time       int64  `json:"time"` // Unix milliseconds the check started
+	OK         bool   `json:"ok"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`      // first failure
+	Screenshot string `json:"screenshot,omitempty"` // file path
+}
+
+// MonitorInfo describes a monitor and its latest check.
+type MonitorInfo struct {
+	ID         string         `json:"id"`
+	URL        string         `json:"url"`
+	Every      int64          `json:"every"`   // milliseconds
+	Timeout    int64          `json:"timeout"` // milliseconds
+	Screenshot bool           `json:"screenshot,omitempty"`
+	Assert     []string       `json:"assert,omitempty"`
+	Webhook    string         `json:"webhook,omitempty"`
+	Dir        string         `json:"dir"` // results.jsonl and screenshots
+	Created    int64          `json:"created"`
+	Checks     int            `json:"checks"`
+	Failures   int            `json:"failures"`
+	Last       *MonitorResult `json:"last,omitempty"`
+}
+
+// MonitorData is the response data for the "monitor" command: the monitor
+// added, every monitor for "list", or one monitor's results for "results".
+type MonitorData struct {
+	Monitors []MonitorInfo   `json:"monitors"`
+	Results  []MonitorResult `json:"results,omitempty"`
+}
+
+// MockParams represents parameters for the "mock" command.
+type MockParams struct {
+	Action string `json:"action"` // "add", "list", "remove", or "clear"
+	// ID names the mock for "remove".
+	ID string `json:"id,omitempty"`
+
+	// URL and the rest configure a mock for "add".
+	URL        string  `json:"url,omitempty"`        // glob: * any run, ? one character
+	Delay      int64   `json:"delay,omitempty"`      // milliseconds each request is held
+	FailRate   float64 `json:"failRate,omitempty"`   // fraction of requests failed, 0 to 1
+	FailStatus int     `json:"failStatus,omitempty"` // respond with this status to fail (0 = network error)
+
+	// Status, when set, answers matching requests with a stub response
+	// instead of sending them to the server.
+	Status      int    `json:"status,omitempty"`
+	Body        string `json:"body,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+// MockInfo describes a mock and how often it has applied.
+type MockInfo struct {
+	ID          string  `json:"id"`
+	URL         string  `json:"url"`
+	Delay       int64   `json:"delay,omitempty"` // milliseconds
+	FailRate    float64 `json:"failRate,omitempty"`
+	FailStatus  int     `json:"failStatus,omitempty"`
+	Status      int     `json:"status,omitempty"`
+	ContentType string  `json:"contentType,omitempty"`
+	BodyBytes   int     `json:"bodyBytes,omitempty"`
+	Created     int64   `json:"created"`
+	Hits        int     `json:"hits"`
+	Failures    int     `json:"failures"`
+}
+
+// MockData is the response data for the "mock" command: the mock added or
+// removed, or every mock for "list" and "clear".
+type MockData struct {
+	Mocks []MockInfo `json:"mocks"`
+}
+
+// AbortParams represents parameters for the "abort" command.
+type AbortParams struct {
+	Action string `json:"action"` // "add", "request", "list", or "clear"
+	// RequestID names the held request to abort for "request".
+	RequestID string `json:"requestId,omitempty"`
+
+	// URL and the rest configure a rule for "add".
+	URL   string `json:"url,omitempty"`   // glob: * any run, ? one character
+	Delay int64  `json:"delay,omitempty"` // milliseconds each request is held before it is aborted
+	Hold  bool   `json:"hold,omitempty"`  // hold each request until it is aborted by request ID
+	Times int    `json:"times,omitempty"` // requests to abort before the rule is spent (0 = every)
+}
+
+// AbortRule describes a rule aborting the requests that match its URL.
+type AbortRule struct {
+	ID      string `json:"id"`
+	URL     string `json:"url"`
+	Delay   int64  `json:"delay,omitempty"` // milliseconds
+	Hold    bool   `json:"hold,omitempty"`
+	Times   int    `json:"times,omitempty"`
+	Created int64  `json:"created"`
+	Hits    int    `json:"hits"`
+}
+
+// HeldRequest is a request paused by an abort rule or a mock's delay that
+// can be aborted by its request ID.
+type HeldRequest struct {
+	RequestID string `json:"requestId"` // the network buffer's request ID
+	URL       string `json:"url"`
+	By        string `json:"by"`    // ID of the abort rule or mock holding it
+	Since     int64  `json:"since"` // Unix ms the request was paused
+}
+
+// AbortData is the response data for the "abort" command: the rule added,
+// the request aborted, or every rule and held request for "list" and
+// "clear".
+type AbortData struct {
+	Rules []AbortRule   `json:"rules"`
+	Held  []HeldRequest `json:"held"`
+}
+
+// LogpointParams represents parameters for the "logpoint" command.
+type LogpointParams struct {
+	Action string `json:"action"` // "add", "list", "remove", or "clear"
+	// ID names the logpoint for "remove".
+	ID string `json:"id,omitempty"`
+
+	// URL and the rest place a logpoint for "add".
+	URL        string `json:"url,omitempty"`        // script URL; glob: * any run, ? one character
+	Line       int    `json:"line,omitempty"`       // 1-based line number
+	Column     int    `json:"column,omitempty"`     // 1-based column; 0 = first statement on the line
+	Expression string `json:"expression,omitempty"` // logged with console.log each time the line runs
+}
+
+// LogpointInfo describes a logpoint.
+type LogpointInfo struct {
+	ID         string `json:"id"`
+	URL        string `json:"url"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column,omitempty"`
+	Expression string `json:"expression"`
+	Created    int64  `json:"created"`
+	// Locations counts the loaded scripts the logpoint bound to across tabs
+	// when it was added; scripts loaded later bind as they load.
+	Locations int `json:"locations,omitempty"`
+}
+
+// LogpointData is the response data for the "logpoint" command: the logpoint
+// added or removed, or every logpoint for "list" and "clear".
+type LogpointData struct {
+	Logpoints []LogpointInfo `json:"logpoints"`
+}
+
+// ExceptionsParams represents parameters for the "exceptions" command.
+type ExceptionsParams struct {
+	Action string `json:"action"`         // "pause" or "show"
+	Mode   string `json:"mode,omitempty"` // pause: "on", "uncaught", or "off"
+}
+
+// ExceptionsData is the response data for the "exceptions" command: the pause
+// mode, and for "show" the captured exceptions, oldest first.
+type ExceptionsData struct {
+	Mode       string            `json:"mode"`
+	Exceptions []PausedException `json:"exceptions,omitempty"`
+	// Dropped counts the exceptions lost to the full buffer since the daemon
+	// started.
+	Dropped uint64 `json:"dropped,omitempty"`
+}
+
+// PausedException is the state captured when the page paused on an
+// exception: the exception, the call stack at the throw, and a preview of the
+// throwing frame's local variables. The page is resumed straight after.
+type PausedException struct {
+	Seq       uint64 `json:"seq"`
+	SessionID string `json:"sessionId,omitempty"`
+	Timestamp int64  `json:"timestamp"` // Unix milliseconds
+	// Reason is "exception", or "promiseRejection" for a rejected promise.
+	Reason   string `json:"reason"`
+	Uncaught bool   `json:"uncaught,omitempty"`
+	Message  string `json:"message"`
+	Class    string `json:"class,omitempty"` // constructor name, e.g. TypeError
+	// Stack is the call stack at the throw, top frame first, with 0-based
+	// lines and columns as in console entries.
+	Stack []ConsoleFrame `json:"stack,omitempty"`
+	// Locals previews the variables in the throwing frame's local scope.
+	Locals []ConsolePreviewProp `json:"locals,omitempty"`
+}
+
+// RewriteParams represents parameters for the "rewrite" command.
+type RewriteParams struct {
+	Action string `json:"action"` // "add", "list", "remove", or "clear"
+	// ID names the rewrite for "remove".
+	ID string `json:"id,omitempty"`
+
+	// URL and the edits configure a rewrite for "add". The JSON patch is
+	// applied first, then the replacements in order.
+	URL       string           `json:"url,omitempty"` // glob: * any run, ? one character
+	JSONPatch json.RawMessage  `json:"jsonPatch,omitempty"`
+	Replace   []RewriteReplace `json:"replace,omitempty"`
+}
+
+// RewriteReplace replaces every occurrence of From in a response body with To.
+type RewriteReplace struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RewriteInfo describes a rewrite and how often it has applied.
+type RewriteInfo struct {
+	ID       string           `json:"id"`
+	URL      string           `json:"url"`
+	PatchOps int              `json:"patchOps,omitempty"` // JSON patch operations
+	Replace  []RewriteReplace `json:"replace,omitempty"`
+	Created  int64            `json:"created"`
+	Hits     int              `json:"hits"`   // responses rewritten
+	Errors   int              `json:"errors"` // responses passed on unchanged because the patch failed
+	// LastError is the most recent reason a response was passed on unchanged.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// RewriteData is the response data for the "rewrite" command: the rewrite
+// added or removed, or every rewrite for "list" and "clear".
+type RewriteData struct {
+	Rewrites []RewriteInfo `json:"rewrites"`
+}
+
 // SuccessResponse creates a successful response with the given data.
 func SuccessResponse(data any) Response {
 	var raw json.RawMessage
@@ -553,14 +2112,22 @@ func SuccessResponse(data any) Response {
 		var err error
 		raw, err = json.Marshal(data)
 		if err != nil {
-			log.Printf("ipc: failed to marshal response data: %v", err)
+			slog.Error("ipc: failed to marshal response data", "error", err)
 			return ErrorResponse("internal error: failed to marshal response")
 		}
 	}
 	return Response{OK: true, Data: raw}
 }
 
-// ErrorResponse creates an error response with the given message.
+// StreamResponse creates a success response whose body is written by body and
+// streamed to the client rather than held in the response data. Handlers
+// return it only for requests with Stream set.
+func StreamResponse(body func(w io.Writer) error) Response {
+	return Response{OK: true, Body: body}
+}
+
+// ErrorResponse creates an error response with the given message, coded by
+// ErrorCode.
 func ErrorResponse(msg string) Response {
-	return Response{OK: false, Error: msg}
+	return Response{OK: false, Error: msg, Code: ErrorCode(msg)}
 }
@@ -273,3 +273,119 @@ func TestNetworkEntry_TelemetryOmitEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestParseWaitUntil(t *testing.T) {
+	tests := []struct {
+		until    string
+		wantMode string
+		wantArg  string
+		wantErr  bool
+	}{
+		{"", WaitUntilLoad, "", false},
+		{"load", WaitUntilLoad, "", false},
+		{"DOMContentLoaded", WaitUntilDOMContentLoaded, "", false},
+		{"networkidle", WaitUntilNetworkIdle, "", false},
+		{"selector:#app .ready", WaitUntilSelector, "#app .ready", false},
+		{"js:window.a === 1 && b:c", WaitUntilJS, "window.a === 1 && b:c", false},
+		{"selector:", "", "", true},
+		{"idle", "", "", true},
+	}
+	for _, tt := range tests {
+		mode, arg, err := ParseWaitUntil(tt.until)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWaitUntil(%q) error = %v, wantErr %v", tt.until, err, tt.wantErr)
+			continue
+		}
+		if mode != tt.wantMode || arg != tt.wantArg {
+			t.Errorf("ParseWaitUntil(%q) = %q, %q, want %q, %q", tt.until, mode, arg, tt.wantMode, tt.wantArg)
+		}
+	}
+}
+
+func TestParseMonitorAssert(t *testing.T) {
+	tests := []struct {
+		assert    string
+		wantKind  string
+		wantValue string
+		wantErr   bool
+	}{
+		{"selector:#app", MonitorAssertSelector, "#app", false},
+		{"text:Welcome back", MonitorAssertText, "Welcome back", false},
+		{"title:Dashboard", MonitorAssertTitle, "Dashboard", false},
+		{"url:https://example.com/", MonitorAssertURL, "https://example.com/", false},
+		{"js:window.ready === true", MonitorAssertJS, "window.ready === true", false},
+		{"selector:", "", "", true},
+		{"selector", "", "", true},
+		{"#app", "", "", true},
+	}
+	for _, tt := range tests {
+		kind, value, err := ParseMonitorAssert(tt.assert)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMonitorAssert(%q) error = %v, wantErr %v", tt.assert, err, tt.wantErr)
+			continue
+		}
+		if kind != tt.wantKind || value != tt.wantValue {
+			t.Errorf("ParseMonitorAssert(%q) = %q, %q, want %q, %q", tt.assert, kind, value, tt.wantKind, tt.wantValue)
+		}
+	}
+}
+
+func TestParsePoolCommand(t *testing.T) {
+	tests := []struct {
+		line    string
+		wantCmd string
+		wantArg string
+		wantErr bool
+	}{
+		{"text", PoolCommandText, "", false},
+		{"text  main h1", PoolCommandText, "main h1", false},
+		{"html #app", PoolCommandHTML, "#app", false},
+		{"title", PoolCommandTitle, "", false},
+		{"count a[href]", PoolCommandCount, "a[href]", false},
+		{"eval document.links.length", PoolCommandEval, "document.links.length", false},
+		{"title h1", "", "", true},
+		{"count", "", "", true},
+		{"eval", "", "", true},
+		{"links", "", "", true},
+		{"", "", "", true},
+	}
+	for _, tt := range tests {
+		cmd, arg, err := ParsePoolCommand(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePoolCommand(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if cmd != tt.wantCmd || arg != tt.wantArg {
+			t.Errorf("ParsePoolCommand(%q) = %q, %q, want %q, %q", tt.line, cmd, arg, tt.wantCmd, tt.wantArg)
+		}
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"daemon not running. Start with: webctl start", CodeDaemonDown},
+		{"failed to connect to daemon: connection refused", CodeDaemonDown},
+		{"no active session - no pages available", CodeNoSession},
+		{"timeout waiting for: #app", CodeTimeout},
+		{"evaluation timed out after 30s", CodeTimeout},
+		{"element not found: .missing", CodeElementNotFound},
+		{"selector '.missing' matched no elements", CodeElementNotFound},
+		{"no tab matches query: docs", CodeNotFound},
+		{"invalid click parameters: unexpected EOF", CodeInvalidArgs},
+		{"selector is required", CodeInvalidArgs},
+		{"unknown command: bogus", CodeInvalidArgs},
+		{"JavaScript error: x is not defined", CodeFailed},
+	}
+	for _, tt := range tests {
+		if got := ErrorCode(tt.msg); got != tt.want {
+			t.Errorf("ErrorCode(%q) = %s, want %s", tt.msg, got, tt.want)
+		}
+	}
+
+	if resp := ErrorResponse("selector is required"); resp.Code != CodeInvalidArgs {
+		t.Errorf("ErrorResponse should classify its message, got code %q", resp.Code)
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Handler processes IPC requests and returns responses.
@@ -25,6 +28,8 @@ type Server struct {
 	wg         sync.WaitGroup
 	closed     chan struct{}
 	closeOnce  sync.Once
+	log        *slog.Logger
+	hello      HelloData
 }
 
 // NewServer creates a new Unix socket server.
@@ -35,6 +40,9 @@ func NewServer(socketPath string, handler Handler) (*Server, error) {
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return nil, fmt.Errorf("failed to create socket directory: %w", err)
 	}
+	if err := checkSocketDir(dir); err != nil {
+		return nil, err
+	}
 
 	// Remove existing socket file if present
 	if err := os.Remove(socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
@@ -46,7 +54,9 @@ func NewServer(socketPath string, handler Handler) (*Server, error) {
 		return nil, fmt.Errorf("failed to create unix socket: %w", err)
 	}
 
-	// Set socket permissions to owner-only
+	// Set socket permissions to owner-only. Until then the socket has the
+	// umask's permissions, but connections from other users are refused by
+	// handleConn whatever the permissions.
 	if err := os.Chmod(socketPath, 0600); err != nil {
 		_ = listener.Close()
 		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
@@ -57,9 +67,25 @@ func NewServer(socketPath string, handler Handler) (*Server, error) {
 		listener:   listener,
 		handler:    handler,
 		closed:     make(chan struct{}),
+		log:        slog.Default(),
+		hello:      HelloData{Protocol: ProtocolVersion, Capabilities: []string{}},
 	}, nil
 }
 
+// SetLogger directs the server's log records (requests at debug level, read
+// and decode failures at warn) to l. Call before Serve; the default is
+// slog.Default().
+func (s *Server) SetLogger(l *slog.Logger) {
+	s.log = l
+}
+
+// SetVersion sets the webctl version and capabilities reported in the
+// handshake. Call before Serve.
+func (s *Server) SetVersion(version string, capabilities ...string) {
+	s.hello.Version = version
+	s.hello.Capabilities = append([]string{}, capabilities...)
+}
+
 // Serve starts accepting connections. Blocks until Close is called.
 func (s *Server) Serve(ctx context.Context) error {
 	go func() {
@@ -91,7 +117,18 @@ func (s *Server) handleConn(conn net.Conn) {
 	defer s.wg.Done()
 	defer func() { _ = conn.Close() }()
 
+	// Only the user running the daemon may drive its browser
+	if uid, err := peerUID(conn); err != nil && !errors.Is(err, errPeerCredUnsupported) {
+		s.log.Warn("ipc: cannot identify client, refusing connection", "error", err)
+		return
+	} else if err == nil && uid != os.Geteuid() {
+		s.log.Warn("ipc: refusing connection from another user", "uid", uid)
+		return
+	}
+
 	reader := bufio.NewReader(conn)
+	// encoding is negotiated by the handshake, per connection
+	encoding := ""
 
 	for {
 		// Read newline-delimited JSON
@@ -100,13 +137,14 @@ func (s *Server) handleConn(conn net.Conn) {
 			// EOF means client closed connection normally.
 			// net.ErrClosed occurs during server shutdown.
 			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
-				log.Printf("ipc: unexpected read error: %v", err)
+				s.log.Warn("ipc: unexpected read error", "error", err)
 			}
 			return
 		}
 
 		var req Request
 		if err := json.Unmarshal(line, &req); err != nil {
+			s.log.Warn("ipc: invalid request", "error", err)
 			resp := ErrorResponse("invalid request format")
 			if err := s.writeResponse(conn, resp); err != nil {
 				return
@@ -114,8 +152,46 @@ func (s *Server) handleConn(conn net.Conn) {
 			continue
 		}
 
+		if req.Cmd == HelloCmd {
+			var params HelloParams
+			_ = json.Unmarshal(req.Params, &params)
+			if params.Protocol != ProtocolVersion {
+				s.log.Warn("ipc: client speaks a different protocol", "client", params.Protocol, "daemon", ProtocolVersion)
+			}
+			hello := s.hello
+			if slices.Contains(hello.Capabilities, CapabilityGzip) {
+				hello.Encoding = chooseEncoding(params.Accept)
+			}
+			encoding = hello.Encoding
+			if err := s.writeResponse(conn, SuccessResponse(hello)); err != nil {
+				return
+			}
+			continue
+		}
+
+		start := time.Now()
 		resp := s.handler(req)
-		if err := s.writeResponse(conn, resp); err != nil {
+		if resp.Body != nil {
+			cw := &chunkWriter{conn: conn}
+			err := resp.Body(cw)
+			if err == nil {
+				err = cw.flush()
+			}
+			if cw.err != nil {
+				s.log.Debug("ipc: failed to write stream", "cmd", req.Cmd, "error", cw.err)
+				return
+			}
+			if err != nil {
+				resp = ErrorResponse(fmt.Sprintf("stream failed after %d bytes: %v", cw.sent, err))
+			}
+		}
+		if resp.OK {
+			s.log.Debug("ipc request", "cmd", req.Cmd, "duration", time.Since(start))
+		} else {
+			s.log.Debug("ipc request failed", "cmd", req.Cmd, "duration", time.Since(start), "error", resp.Error)
+		}
+		if err := s.writeResponse(conn, encodeResponse(resp, encoding)); err != nil {
+			s.log.Debug("ipc: failed to write response", "cmd", req.Cmd, "error", err)
 			return
 		}
 	}
@@ -132,6 +208,51 @@ func (s *Server) writeResponse(conn net.Conn, resp Response) error {
 	return err
 }
 
+// chunkWriter sends what is written to it as Chunk frames of up to ChunkSize
+// bytes.
+type chunkWriter struct {
+	conn net.Conn
+	buf  []byte
+	sent int64
+	err  error // the first error writing to conn
+}
+
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		take := min(len(p), ChunkSize-len(c.buf))
+		c.buf = append(c.buf, p[:take]...)
+		p = p[take:]
+		if len(c.buf) == ChunkSize {
+			if err := c.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// flush sends the buffered bytes as a frame.
+func (c *chunkWriter) flush() error {
+	if len(c.buf) == 0 || c.err != nil {
+		return c.err
+	}
+	data, err := json.Marshal(Chunk{Chunk: c.buf})
+	if err != nil {
+		return err
+	}
+	if _, err := c.conn.Write(append(data, '\n')); err != nil {
+		c.err = err
+		return err
+	}
+	c.sent += int64(len(c.buf))
+	c.buf = c.buf[:0]
+	return nil
+}
+
 // SocketPath returns the path to the Unix socket.
 func (s *Server) SocketPath() string {
 	return s.socketPath
@@ -151,8 +272,17 @@ func (s *Server) Close() error {
 	return err
 }
 
-// DefaultSocketPath returns the XDG-compliant socket path.
+// SocketEnv is the environment variable that overrides the socket path. The
+// PID and state files move with it, so daemons on different sockets do not
+// share them.
+const SocketEnv = "WEBCTL_SOCKET"
+
+// DefaultSocketPath returns $WEBCTL_SOCKET, or the XDG-compliant socket path.
 func DefaultSocketPath() string {
+	if path := os.Getenv(SocketEnv); path != "" {
+		return path
+	}
+
 	// Try XDG_RUNTIME_DIR first
 	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
 		return filepath.Join(runtimeDir, "webctl", "webctl.sock")
@@ -162,13 +292,13 @@ func DefaultSocketPath() string {
 	return filepath.Join(fmt.Sprintf("/tmp/webctl-%d", os.Getuid()), "webctl.sock")
 }
 
-// DefaultPIDPath returns the XDG-compliant PID file path.
+// DefaultPIDPath returns the PID file path, next to the socket.
 func DefaultPIDPath() string {
-	// Try XDG_RUNTIME_DIR first
-	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
-		return filepath.Join(runtimeDir, "webctl", "webctl.pid")
-	}
+	return siblingPath(DefaultSocketPath(), ".pid")
+}
 
-	// Fallback to /tmp/webctl-<uid>/
-	return filepath.Join(fmt.Sprintf("/tmp/webctl-%d", os.Getuid()), "webctl.pid")
+// siblingPath returns the path beside the socket with the socket's name and
+// the given extension, e.g. webctl.sock -> webctl.pid.
+func siblingPath(socketPath, ext string) string {
+	return strings.TrimSuffix(socketPath, filepath.Ext(socketPath)) + ext
 }
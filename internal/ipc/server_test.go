@@ -1,9 +1,16 @@
 package ipc
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -194,3 +201,327 @@ func TestClient_DaemonNotRunning(t *testing.T) {
 		t.Errorf("expected ErrDaemonNotRunning, got %v", err)
 	}
 }
+
+func TestServer_SetLogger_RecordsRequests(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	server, err := NewServer(socketPath, func(req Request) Response {
+		if req.Cmd == "fail" {
+			return ErrorResponse("boom")
+		}
+		return SuccessResponse(nil)
+	})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	var out bytes.Buffer
+	server.SetLogger(slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = server.Serve(ctx) }()
+
+	client, err := DialPath(socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	_, _ = client.SendCmd("status")
+	_, _ = client.SendCmd("fail")
+	_ = client.Close()
+
+	// Close waits for connection handlers, so the buffer is quiescent after.
+	_ = server.Close()
+
+	got := out.String()
+	for _, want := range []string{`msg="ipc request" cmd=status`, `msg="ipc request failed" cmd=fail`, "error=boom"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestDefaultPaths_SocketOverride(t *testing.T) {
+	t.Setenv(SocketEnv, "/run/user/1000/work/browser.sock")
+
+	if got := DefaultSocketPath(); got != "/run/user/1000/work/browser.sock" {
+		t.Errorf("DefaultSocketPath() = %q", got)
+	}
+	if got := DefaultPIDPath(); got != "/run/user/1000/work/browser.pid" {
+		t.Errorf("DefaultPIDPath() = %q", got)
+	}
+	if got := DefaultStatePath(); got != "/run/user/1000/work/browser.json" {
+		t.Errorf("DefaultStatePath() = %q", got)
+	}
+}
+
+func TestNewServer_OwnerOnly(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "private", "test.sock")
+
+	server, err := NewServer(socketPath, func(req Request) Response { return SuccessResponse(nil) })
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer func() { _ = server.Close() }()
+
+	info, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode = %04o, want 0600", perm)
+	}
+	dirInfo, err := os.Stat(filepath.Dir(socketPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := dirInfo.Mode().Perm(); perm != 0700 {
+		t.Errorf("socket directory mode = %04o, want 0700", perm)
+	}
+}
+
+func TestPeerUID(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+		close(accepted)
+	}()
+
+	client, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	server := <-accepted
+	if server == nil {
+		t.Fatal("accept failed")
+	}
+	defer func() { _ = server.Close() }()
+
+	for name, conn := range map[string]net.Conn{"server side": server, "client side": client} {
+		uid, err := peerUID(conn)
+		if errors.Is(err, errPeerCredUnsupported) {
+			t.Skip("peer credentials not supported on this platform")
+		}
+		if err != nil {
+			t.Fatalf("%s: peerUID() error = %v", name, err)
+		}
+		if uid != os.Geteuid() {
+			t.Errorf("%s: peerUID() = %d, want %d", name, uid, os.Geteuid())
+		}
+	}
+	if err := checkPeer(client); err != nil {
+		t.Errorf("checkPeer() = %v, want nil for own daemon", err)
+	}
+}
+
+func TestClient_Handshake(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	server, err := NewServer(socketPath, func(req Request) Response { return SuccessResponse(nil) })
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.SetVersion("1.2.3", "example")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = server.Serve(ctx) }()
+	defer func() { _ = server.Close() }()
+
+	client, err := DialPath(socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	hello := client.Hello()
+	if hello.Protocol != ProtocolVersion || hello.Version != "1.2.3" || len(hello.Capabilities) != 1 || hello.Capabilities[0] != "example" {
+		t.Errorf("Hello() = %+v", hello)
+	}
+	if hello.Encoding != "" {
+		t.Errorf("encoding %q negotiated without the gzip capability", hello.Encoding)
+	}
+	if _, err := client.SendCmd("status"); err != nil {
+		t.Errorf("SendCmd() after handshake: %v", err)
+	}
+}
+
+// fakeDaemon serves one connection, answering the handshake with hello (or
+// an "unknown command" error when hello is nil) and every other request with
+// success.
+func fakeDaemon(t *testing.T, hello *HelloData) string {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	t.Cleanup(func() {
+		_ = listener.Close()
+		<-done
+	})
+	go func() {
+		defer close(done)
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		dec := json.NewDecoder(conn)
+		enc := json.NewEncoder(conn)
+		for {
+			var req Request
+			if err := dec.Decode(&req); err != nil {
+				return
+			}
+			resp := SuccessResponse(map[string]any{"cmd": req.Cmd, "stream": req.Stream})
+			if req.Cmd == HelloCmd {
+				if hello == nil {
+					resp = ErrorResponse("unknown command: hello")
+				} else {
+					resp = SuccessResponse(hello)
+				}
+			}
+			_ = enc.Encode(resp)
+		}
+	}()
+	return socketPath
+}
+
+func TestClient_ProtocolMismatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		hello *HelloData
+		want  string
+	}{
+		{"newer daemon", &HelloData{Protocol: ProtocolVersion + 1, Version: "9.0.0"}, "daemon (webctl 9.0.0) is newer"},
+		{"older daemon", &HelloData{Protocol: ProtocolVersion - 1}, "daemon is older"},
+		{"daemon without handshake", nil, "daemon is older"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := DialPath(fakeDaemon(t, tt.hello))
+			if err != nil {
+				t.Fatalf("DialPath() error = %v", err)
+			}
+			defer func() { _ = client.Close() }()
+
+			_, err = client.SendCmd("status")
+			if !errors.Is(err, ErrProtocolMismatch) {
+				t.Fatalf("SendCmd(status) error = %v, want ErrProtocolMismatch", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), "webctl stop") {
+				t.Errorf("error = %q, want it to contain %q and the restart advice", err, tt.want)
+			}
+
+			// The daemon can still be stopped
+			resp, err := client.SendCmd("shutdown")
+			if err != nil || !resp.OK {
+				t.Errorf("SendCmd(shutdown) = %+v, %v", resp, err)
+			}
+		})
+	}
+}
+
+func TestClient_SendStreamWithoutCapability(t *testing.T) {
+	client, err := DialPath(fakeDaemon(t, &HelloData{Protocol: ProtocolVersion, Capabilities: []string{}}))
+	if err != nil {
+		t.Fatalf("DialPath() error = %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	if client.HasCapability(CapabilityStream) {
+		t.Fatal("HasCapability(stream) = true for a daemon that did not report it")
+	}
+	var buf bytes.Buffer
+	resp, err := client.SendStream(Request{Cmd: "screenshot"}, &buf)
+	if err != nil || !resp.OK {
+		t.Fatalf("SendStream() = %+v, %v", resp, err)
+	}
+	var data struct {
+		Stream bool `json:"stream"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil || data.Stream {
+		t.Errorf("request sent with stream set (data %s), want it inline", resp.Data)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %d bytes to w, want none", buf.Len())
+	}
+}
+
+func TestClient_SendStream(t *testing.T) {
+	body := bytes.Repeat([]byte("0123456789abcdef"), ChunkSize/8+3) // a little over two chunks
+	socketPath := filepath.Join(t.TempDir(), "test.sock")
+	server, err := NewServer(socketPath, func(req Request) Response {
+		switch {
+		case req.Cmd == "fail":
+			return StreamResponse(func(w io.Writer) error {
+				if _, err := w.Write(body[:10]); err != nil {
+					return err
+				}
+				return errors.New("boom")
+			})
+		case req.Stream, req.Cmd == "rogue":
+			return StreamResponse(func(w io.Writer) error {
+				_, err := w.Write(body)
+				return err
+			})
+		default:
+			return SuccessResponse(map[string]int{"size": len(body)})
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	server.SetVersion("", CapabilityStream)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = server.Serve(ctx) }()
+	defer func() { _ = server.Close() }()
+
+	client, err := DialPath(socketPath)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var buf bytes.Buffer
+	resp, err := client.SendStream(Request{Cmd: "big"}, &buf)
+	if err != nil || !resp.OK {
+		t.Fatalf("SendStream() = %+v, %v", resp, err)
+	}
+	if len(resp.Data) != 0 {
+		t.Errorf("streamed response has data %s", resp.Data)
+	}
+	if !bytes.Equal(buf.Bytes(), body) {
+		t.Errorf("streamed body is %d bytes, want %d intact", buf.Len(), len(body))
+	}
+
+	// Without Stream the body comes back inline, on the same connection
+	resp, err = client.Send(Request{Cmd: "big"})
+	if err != nil || !resp.OK || len(resp.Data) == 0 {
+		t.Errorf("Send() = %+v, %v", resp, err)
+	}
+
+	buf.Reset()
+	resp, err = client.SendStream(Request{Cmd: "fail"}, &buf)
+	if err != nil {
+		t.Fatalf("SendStream(fail) error = %v", err)
+	}
+	if resp.OK || !strings.Contains(resp.Error, "stream failed after 0 bytes: boom") {
+		t.Errorf("SendStream(fail) = %+v, want a stream failure", resp)
+	}
+
+	// A streamed response to Send is a protocol error
+	if _, err := client.Send(Request{Cmd: "rogue"}); err == nil || !strings.Contains(err.Error(), "unexpected streamed response") {
+		t.Errorf("Send(rogue) error = %v, want unexpected streamed response", err)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package ipc
+
+import "os"
+
+// checkSocketDir only checks that the socket directory exists; ownership is
+// not available on this platform.
+func checkSocketDir(dir string) error {
+	_, err := os.Stat(dir)
+	return err
+}
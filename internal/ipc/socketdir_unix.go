@@ -0,0 +1,31 @@
+//go:build unix
+
+package ipc
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkSocketDir refuses a socket directory owned by another user, who could
+// replace the socket with one of their own. A shared directory such as /tmp
+// is allowed when root owns it and its sticky bit is set, as then only the
+// socket's owner can remove or rename it.
+func checkSocketDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || socketDirTrusted(int(st.Uid), info.Mode(), os.Geteuid()) {
+		return nil
+	}
+	return fmt.Errorf("socket directory %s is owned by uid %d, not you, and is not a root-owned sticky directory", dir, st.Uid)
+}
+
+// socketDirTrusted reports whether a directory with the given owner and mode
+// is safe to serve a socket from for the user euid.
+func socketDirTrusted(owner int, mode os.FileMode, euid int) bool {
+	return owner == euid || (owner == 0 && mode&os.ModeSticky != 0)
+}
@@ -0,0 +1,66 @@
+//go:build unix
+
+package ipc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestSocketDirTrusted(t *testing.T) {
+	tests := []struct {
+		name  string
+		owner int
+		mode  os.FileMode
+		want  bool
+	}{
+		{"own directory", 1000, os.ModeDir | 0700, true},
+		{"own sticky directory", 1000, os.ModeDir | os.ModeSticky | 0777, true},
+		{"root sticky shared", 0, os.ModeDir | os.ModeSticky | 0777, true},
+		{"root without sticky", 0, os.ModeDir | 0777, false},
+		{"other user sticky", 1001, os.ModeDir | os.ModeSticky | 0777, false},
+		{"other user", 1001, os.ModeDir | 0755, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := socketDirTrusted(tt.owner, tt.mode, 1000); got != tt.want {
+				t.Errorf("socketDirTrusted(%d, %v, 1000) = %v, want %v", tt.owner, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewServer_StickySharedDir(t *testing.T) {
+	// Use the system temp directory when it is the usual root-owned sticky
+	// /tmp, so the check is exercised for a directory owned by someone else;
+	// otherwise make a sticky shared directory of our own.
+	dir := os.TempDir()
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); !ok || st.Uid != 0 || info.Mode()&os.ModeSticky == 0 {
+		dir = t.TempDir()
+		if err := os.Chmod(dir, os.ModeSticky|0777); err != nil {
+			t.Fatal(err)
+		}
+	}
+	socketPath := filepath.Join(dir, fmt.Sprintf("webctl-test-%d.sock", os.Getpid()))
+
+	server, err := NewServer(socketPath, func(req Request) Response { return SuccessResponse(nil) })
+	if err != nil {
+		t.Fatalf("NewServer(%s) error = %v", socketPath, err)
+	}
+	defer func() { _ = server.Close() }()
+
+	socketInfo, err := os.Stat(socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := socketInfo.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode = %04o, want 0600", perm)
+	}
+}
@@ -0,0 +1,59 @@
+package ipc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// State is what a running daemon records about itself and the browser it
+// drives. It outlives a daemon that crashes, so a later 'start --reuse' can
+// find the browser that was left running.
+type State struct {
+	PID        int `json:"pid"`
+	BrowserPID int `json:"browserPid"`
+	Port       int `json:"port"`
+	// Endpoint is the browser-level CDP WebSocket URL.
+	Endpoint    string `json:"endpoint"`
+	UserDataDir string `json:"userDataDir,omitempty"`
+	// TempProfile is true when UserDataDir is a throwaway profile that is
+	// deleted when the browser is closed.
+	TempProfile bool `json:"tempProfile,omitempty"`
+	// Adopted is true when the browser was launched by someone else and
+	// attached to with 'start --adopt'; webctl never kills it.
+	Adopted  bool `json:"adopted,omitempty"`
+	Headless bool `json:"headless,omitempty"`
+	LaunchFlags
+}
+
+// DefaultStatePath returns the daemon state file path, next to the socket
+// and PID file.
+func DefaultStatePath() string {
+	return siblingPath(DefaultSocketPath(), ".json")
+}
+
+// ReadState reads the state file at path.
+func ReadState(path string) (State, error) {
+	var s State
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// WriteState writes s to the state file at path, creating its directory.
+func WriteState(path string, s State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
@@ -0,0 +1,61 @@
+package ipc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "webctl.json")
+	want := State{
+		PID:         100,
+		BrowserPID:  200,
+		Port:        9222,
+		Endpoint:    "ws://127.0.0.1:9222/devtools/browser/abc",
+		UserDataDir: "/tmp/webctl-profile",
+		TempProfile: true,
+		LaunchFlags: LaunchFlags{Lang: "en-GB", ChromeFlags: []string{"--disable-gpu"}},
+	}
+
+	if err := WriteState(path, want); err != nil {
+		t.Fatalf("WriteState: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	got, err := ReadState(path)
+	if err != nil {
+		t.Fatalf("ReadState: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadState_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ReadState(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadState(bad); err == nil {
+		t.Error("expected error for malformed state file")
+	}
+}
+
+func TestDefaultStatePath_NextToPIDFile(t *testing.T) {
+	if filepath.Dir(DefaultStatePath()) != filepath.Dir(DefaultPIDPath()) {
+		t.Errorf("state file %s should sit beside PID file %s", DefaultStatePath(), DefaultPIDPath())
+	}
+}
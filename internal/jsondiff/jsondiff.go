@@ -0,0 +1,134 @@
+// Package jsondiff compares two JSON documents structurally, reporting the
+// values added, removed, and changed by their path.
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Op is the kind of a change.
+type Op string
+
+const (
+	// Added marks a value present only in the second document.
+	Added Op = "added"
+	// Removed marks a value present only in the first document.
+	Removed Op = "removed"
+	// Changed marks a value that differs between the documents.
+	Changed Op = "changed"
+)
+
+// Change is one difference between the documents. Path is a JSONPath-style
+// address such as $.user.roles[2]. From is unset for Added and To for
+// Removed; both hold compact JSON.
+type Change struct {
+	Op   Op     `json:"op"`
+	Path string `json:"path"`
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// Parse decodes a JSON document, keeping numbers exact so 1.0 and 1 compare
+// as written.
+func Parse(data string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// Compare returns the changes turning a into b, in document order with object
+// keys sorted. Arrays are compared index by index, so an insertion reports
+// every later element as changed. Returns nil when the documents are equal.
+func Compare(a, b any) []Change {
+	var changes []Change
+	compare("$", a, b, &changes)
+	return changes
+}
+
+func compare(path string, a, b any, changes *[]Change) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			x, inA := av[k]
+			y, inB := bv[k]
+			p := path + key(k)
+			switch {
+			case !inB:
+				*changes = append(*changes, Change{Op: Removed, Path: p, From: encode(x)})
+			case !inA:
+				*changes = append(*changes, Change{Op: Added, Path: p, To: encode(y)})
+			default:
+				compare(p, x, y, changes)
+			}
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		for i := range max(len(av), len(bv)) {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(bv):
+				*changes = append(*changes, Change{Op: Removed, Path: p, From: encode(av[i])})
+			case i >= len(av):
+				*changes = append(*changes, Change{Op: Added, Path: p, To: encode(bv[i])})
+			default:
+				compare(p, av[i], bv[i], changes)
+			}
+		}
+		return
+	}
+
+	if from, to := encode(a), encode(b); from != to {
+		*changes = append(*changes, Change{Op: Changed, Path: path, From: from, To: to})
+	}
+}
+
+// identifier matches object keys that need no quoting in a path.
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// key renders an object key as a path segment.
+func key(k string) string {
+	if identifier.MatchString(k) {
+		return "." + k
+	}
+	q, _ := json.Marshal(k)
+	return "[" + string(q) + "]"
+}
+
+// encode renders a value as compact JSON without HTML escaping.
+func encode(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,52 @@
+package jsondiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) any {
+	t.Helper()
+	v, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", s, err)
+	}
+	return v
+}
+
+func TestCompare(t *testing.T) {
+	a := mustParse(t, `{"user":{"name":"Ann","roles":["read","write"],"age":30},"meta":{"cached":true},"a b":1}`)
+	b := mustParse(t, `{"user":{"name":"Anne","roles":["read","write","admin"],"age":30.0},"meta":{},"a b":1}`)
+
+	want := []Change{
+		{Op: Removed, Path: "$.meta.cached", From: "true"},
+		{Op: Changed, Path: "$.user.age", From: "30", To: "30.0"},
+		{Op: Changed, Path: "$.user.name", From: `"Ann"`, To: `"Anne"`},
+		{Op: Added, Path: "$.user.roles[2]", To: `"admin"`},
+	}
+	if got := Compare(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCompare_TypeChangeAndEqual(t *testing.T) {
+	a := mustParse(t, `{"items":[1,2],"tag":"<b>"}`)
+	b := mustParse(t, `{"items":{"count":2},"tag":"<b>"}`)
+	want := []Change{{Op: Changed, Path: "$.items", From: "[1,2]", To: `{"count":2}`}}
+	if got := Compare(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare = %+v, want %+v", got, want)
+	}
+
+	if got := Compare(a, mustParse(t, `{"tag":"<b>","items":[1,2]}`)); got != nil {
+		t.Errorf("equal documents gave %+v", got)
+	}
+}
+
+func TestParse_RejectsTrailingData(t *testing.T) {
+	if _, err := Parse(`{"a":1} {"b":2}`); err == nil {
+		t.Error("expected trailing data to fail")
+	}
+	if _, err := Parse(`<html>`); err == nil {
+		t.Error("expected HTML to fail")
+	}
+}
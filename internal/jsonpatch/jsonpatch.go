@@ -0,0 +1,274 @@
+// Package jsonpatch applies RFC 6902 JSON Patch documents: a list of add,
+// remove, replace, move, copy, and test operations addressed by RFC 6901
+// JSON Pointers.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Operation is one JSON Patch operation. Value is nil when the operation has
+// no "value" member, which is distinct from a null value.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch is a JSON Patch document, applied in order.
+type Patch []Operation
+
+// errNotFound reports a pointer that addresses nothing.
+var errNotFound = errors.New("path not found")
+
+// Parse decodes and validates a JSON Patch document, so a malformed patch is
+// reported before it is applied to anything.
+func Parse(data []byte) (Patch, error) {
+	var p Patch
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+	for i, op := range p {
+		if err := op.validate(); err != nil {
+			return nil, fmt.Errorf("invalid JSON patch op %d: %w", i, err)
+		}
+	}
+	return p, nil
+}
+
+func (op Operation) validate() error {
+	if _, err := parsePointer(op.Path); err != nil {
+		return err
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return fmt.Errorf("%s %s needs a value", op.Op, op.Path)
+		}
+	case "move", "copy":
+		if _, err := parsePointer(op.From); err != nil {
+			return fmt.Errorf("%s from: %w", op.Op, err)
+		}
+		if op.Op == "move" && strings.HasPrefix(op.Path, op.From+"/") {
+			return fmt.Errorf("cannot move %s into itself", op.From)
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("unknown op %q (use add, remove, replace, move, copy, or test)", op.Op)
+	}
+	return nil
+}
+
+// Apply applies the patch to a JSON document and returns the result as
+// compact JSON. Object keys come out sorted. A failing operation, including
+// a test that does not hold, fails the whole patch.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	v, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("document is not JSON: %w", err)
+	}
+	for i, op := range p {
+		if v, err = op.apply(v); err != nil {
+			return nil, fmt.Errorf("op %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (op Operation) apply(doc any) (any, error) {
+	path, _ := parsePointer(op.Path)
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return update(doc, path, func(any) (any, error) { return value, nil })
+		}
+		got, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(got, value) {
+			return nil, errors.New("test failed")
+		}
+		return doc, nil
+	case "remove":
+		return remove(doc, path)
+	default: // move, copy
+		from, _ := parsePointer(op.From)
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, fmt.Errorf("from %s: %w", op.From, err)
+		}
+		if op.Op == "move" {
+			if doc, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		} else if value, err = clone(value); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	}
+}
+
+// decode parses JSON keeping numbers exact, so values a patch does not touch
+// are written back as they were.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+func clone(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// parsePointer splits an RFC 6901 pointer into its unescaped reference
+// tokens. The empty pointer addresses the whole document.
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("invalid pointer %q (must start with /)", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// index parses an array index token, which must be a plain decimal within
+// n (inclusive of n itself when end is allowed, for appending).
+func index(token string, n int, end bool) (int, error) {
+	if end && token == "-" {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > n || (!end && i == n) {
+		return 0, errNotFound
+	}
+	return i, nil
+}
+
+func get(doc any, path []string) (any, error) {
+	var v any
+	_, err := update(doc, path, func(old any) (any, error) {
+		v = old
+		return old, nil
+	})
+	return v, err
+}
+
+// update replaces the value at path with fn's result and returns the
+// document, which is new when path is empty or ends in a grown array.
+func update(node any, path []string, fn func(any) (any, error)) (any, error) {
+	if len(path) == 0 {
+		return fn(node)
+	}
+	token, rest := path[0], path[1:]
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[token]
+		if !ok {
+			return nil, errNotFound
+		}
+		child, err := update(child, rest, fn)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = child
+		return n, nil
+	case []any:
+		i, err := index(token, len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(n[i], rest, fn)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = child
+		return n, nil
+	}
+	return nil, errNotFound
+}
+
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	key := path[len(path)-1]
+	return update(doc, path[:len(path)-1], func(parent any) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p[key] = value
+			return p, nil
+		case []any:
+			i, err := index(key, len(p), true)
+			if err != nil {
+				return nil, err
+			}
+			return append(p[:i], append([]any{value}, p[i:]...)...), nil
+		}
+		return nil, errNotFound
+	})
+}
+
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	key := path[len(path)-1]
+	return update(doc, path[:len(path)-1], func(parent any) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			if _, ok := p[key]; !ok {
+				return nil, errNotFound
+			}
+			delete(p, key)
+			return p, nil
+		case []any:
+			i, err := index(key, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(p[:i], p[i+1:]...), nil
+		}
+		return nil, errNotFound
+	})
+}
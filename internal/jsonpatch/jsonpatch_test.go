@@ -0,0 +1,82 @@
+package jsonpatch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, want string
+	}{
+		{"add member", `{"a":1}`, `[{"op":"add","path":"/b","value":[1,2]}]`, `{"a":1,"b":[1,2]}`},
+		{"add array insert", `{"xs":["a","c"]}`, `[{"op":"add","path":"/xs/1","value":"b"}]`, `{"xs":["a","b","c"]}`},
+		{"add array end", `{"xs":["a"]}`, `[{"op":"add","path":"/xs/-","value":"z"}]`, `{"xs":["a","z"]}`},
+		{"add null", `{}`, `[{"op":"add","path":"/n","value":null}]`, `{"n":null}`},
+		{"remove", `{"a":1,"b":{"c":2}}`, `[{"op":"remove","path":"/b/c"}]`, `{"a":1,"b":{}}`},
+		{"remove element", `[1,2,3]`, `[{"op":"remove","path":"/1"}]`, `[1,3]`},
+		{"replace", `{"flags":{"beta":false}}`, `[{"op":"replace","path":"/flags/beta","value":true}]`, `{"flags":{"beta":true}}`},
+		{"replace root", `{"a":1}`, `[{"op":"replace","path":"","value":[0]}]`, `[0]`},
+		{"move", `{"a":{"x":1},"b":{}}`, `[{"op":"move","from":"/a/x","path":"/b/y"}]`, `{"a":{},"b":{"y":1}}`},
+		{"copy is independent", `{"a":{"x":1}}`, `[{"op":"copy","from":"/a","path":"/b"},{"op":"replace","path":"/b/x","value":2}]`, `{"a":{"x":1},"b":{"x":2}}`},
+		{"test passes", `{"plan":"free"}`, `[{"op":"test","path":"/plan","value":"free"},{"op":"replace","path":"/plan","value":"pro"}]`, `{"plan":"pro"}`},
+		{"escaped keys", `{"a/b":{"m~n":1}}`, `[{"op":"replace","path":"/a~1b/m~0n","value":2}]`, `{"a/b":{"m~n":2}}`},
+		{"numbers and html kept", `{"n":1.50,"s":"<b>&"}`, `[]`, `{"n":1.50,"s":"<b>&"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse([]byte(tt.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Apply([]byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Apply = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApply_Errors(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, want string
+	}{
+		{"missing member", `{"a":1}`, `[{"op":"replace","path":"/b","value":1}]`, "op 0 (replace /b): path not found"},
+		{"missing parent", `{}`, `[{"op":"add","path":"/a/b","value":1}]`, "path not found"},
+		{"index past end", `[1]`, `[{"op":"add","path":"/2","value":1}]`, "path not found"},
+		{"leading zero", `[1,2]`, `[{"op":"remove","path":"/01"}]`, "invalid array index"},
+		{"test fails", `{"plan":"pro"}`, `[{"op":"test","path":"/plan","value":"free"}]`, "test failed"},
+		{"not json", `<html>`, `[]`, "document is not JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Parse([]byte(tt.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := p.Apply([]byte(tt.doc)); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		patch, want string
+	}{
+		{`{"op":"add"}`, "invalid JSON patch"},
+		{`[{"op":"upsert","path":"/a"}]`, `unknown op "upsert"`},
+		{`[{"op":"add","path":"/a"}]`, "needs a value"},
+		{`[{"op":"remove","path":"a"}]`, "must start with /"},
+		{`[{"op":"move","from":"/a","path":"/a/b"}]`, "into itself"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse([]byte(tt.patch)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%s) err = %v, want %q", tt.patch, err, tt.want)
+		}
+	}
+}
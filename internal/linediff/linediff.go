@@ -0,0 +1,214 @@
+// Package linediff computes line-based diffs and renders them in unified
+// diff format.
+package linediff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of a single line edit.
+type Op int
+
+const (
+	// Equal marks a line present in both inputs.
+	Equal Op = iota
+	// Delete marks a line present only in the first input.
+	Delete
+	// Insert marks a line present only in the second input.
+	Insert
+)
+
+// Edit is one line of an edit script.
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// Lines returns the shortest edit script turning a into b, using Myers'
+// algorithm. Common prefix and suffix lines are trimmed first, which keeps the
+// search small for the usual case of mostly identical documents.
+func Lines(a, b []string) []Edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, Edit{Equal, line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, Edit{Equal, line})
+	}
+	return edits
+}
+
+// myers runs the greedy O((N+M)D) forward search, keeping only the 2d+1
+// frontier values of each step so memory stays O(D^2), then backtracks.
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+		snapshot := make([]int, 2*d+1)
+		copy(snapshot, v[offset-d:offset+d+1])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Backtrack from (n, m) through the saved frontiers, collecting edits in
+	// reverse.
+	var reversed []Edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		frontier := trace[d]
+		at := func(k int) int { return frontier[k+d] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := 0
+		if d > 0 {
+			prevX = at(prevK)
+		}
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Edit{Equal, a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, Edit{Insert, b[prevY]})
+			} else {
+				reversed = append(reversed, Edit{Delete, a[prevX]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	edits := make([]Edit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
+
+// Unified renders the diff between a and b in unified format with the given
+// number of context lines. Returns an empty string when the inputs are equal.
+func Unified(fromName, toName string, a, b []string, context int) string {
+	edits := Lines(a, b)
+
+	changed := false
+	for _, e := range edits {
+		if e.Op != Equal {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Line numbers (0-based) in a and b at each edit index.
+	aLine := make([]int, len(edits)+1)
+	bLine := make([]int, len(edits)+1)
+	for i, e := range edits {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if e.Op != Insert {
+			aLine[i+1]++
+		}
+		if e.Op != Delete {
+			bLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			i++
+			continue
+		}
+
+		// Extend the hunk while changes are within 2*context of each other.
+		start := max(i-context, 0)
+		end := i
+		for j := i; j < len(edits); j++ {
+			if edits[j].Op != Equal {
+				end = j + 1
+			} else if j-end >= 2*context {
+				break
+			}
+		}
+		end = min(end+context, len(edits))
+
+		aCount := aLine[end] - aLine[start]
+		bCount := bLine[end] - bLine[start]
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(aLine[start], aCount), hunkRange(bLine[start], bCount))
+		for _, e := range edits[start:end] {
+			switch e.Op {
+			case Equal:
+				buf.WriteString(" ")
+			case Delete:
+				buf.WriteString("-")
+			case Insert:
+				buf.WriteString("+")
+			}
+			buf.WriteString(e.Line)
+			buf.WriteString("\n")
+		}
+		i = end
+	}
+
+	return buf.String()
+}
+
+// hunkRange formats a unified diff range. An empty range points at the line
+// before it, as GNU diff does.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,113 @@
+package linediff
+
+import (
+	"strings"
+	"testing"
+)
+
+// apply rebuilds both sides from an edit script.
+func apply(edits []Edit) (a, b []string) {
+	for _, e := range edits {
+		if e.Op != Insert {
+			a = append(a, e.Line)
+		}
+		if e.Op != Delete {
+			b = append(b, e.Line)
+		}
+	}
+	return a, b
+}
+
+func TestLines_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      string
+		wantEdits int // number of non-equal edits (shortest script)
+	}{
+		{name: "identical", a: "a b c", b: "a b c", wantEdits: 0},
+		{name: "both empty", a: "", b: "", wantEdits: 0},
+		{name: "insert into empty", a: "", b: "x y", wantEdits: 2},
+		{name: "delete all", a: "x y", b: "", wantEdits: 2},
+		{name: "single change", a: "a b c", b: "a x c", wantEdits: 2},
+		{name: "classic myers", a: "a b c a b b a", b: "c b a b a c", wantEdits: 5},
+		{name: "append", a: "a b", b: "a b c", wantEdits: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+			edits := Lines(a, b)
+
+			gotA, gotB := apply(edits)
+			if strings.Join(gotA, " ") != tt.a || strings.Join(gotB, " ") != tt.b {
+				t.Fatalf("edit script does not rebuild inputs: a=%v b=%v", gotA, gotB)
+			}
+
+			changes := 0
+			for _, e := range edits {
+				if e.Op != Equal {
+					changes++
+				}
+			}
+			if changes != tt.wantEdits {
+				t.Errorf("changes = %d, want %d", changes, tt.wantEdits)
+			}
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := strings.Split("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15", "\n")
+	b := strings.Split("1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n16", "\n")
+
+	got := Unified("a.html", "b.html", a, b, 3)
+	want := `--- a.html
++++ b.html
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -11,5 +11,5 @@
+ 11
+ 12
+ 13
+-14
+ 15
++16
+`
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnified_MergesNearbyHunks(t *testing.T) {
+	a := strings.Split("1\n2\n3\n4\n5\n6\n7\n8", "\n")
+	b := strings.Split("1\nX\n3\n4\n5\n6\nY\n8", "\n")
+
+	got := Unified("a", "b", a, b, 2)
+	if strings.Count(got, "@@ -") != 1 {
+		t.Errorf("expected changes 4 lines apart to share one hunk with context 2, got:\n%s", got)
+	}
+	if !strings.Contains(got, "@@ -1,8 +1,8 @@") {
+		t.Errorf("unexpected hunk header:\n%s", got)
+	}
+}
+
+func TestUnified_EmptyRanges(t *testing.T) {
+	got := Unified("a", "b", nil, []string{"x"}, 3)
+	want := "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n"
+	if got != want {
+		t.Errorf("Unified() = %q, want %q", got, want)
+	}
+}
+
+func TestUnified_Identical(t *testing.T) {
+	lines := []string{"a", "b"}
+	if got := Unified("a", "b", lines, lines, 3); got != "" {
+		t.Errorf("expected empty diff, got %q", got)
+	}
+}
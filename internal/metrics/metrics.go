@@ -0,0 +1,236 @@
+// Package metrics is a minimal Prometheus-compatible metrics registry: counters,
+// labelled counters, gauges read on scrape, and histograms, exposed in the
+// Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Registry holds metrics in registration order and renders them for scraping.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+// metric is one registered metric family.
+type metric interface {
+	write(w io.Writer)
+}
+
+// New returns an empty registry.
+func New() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// Render writes every metric in the Prometheus text exposition format.
+func (r *Registry) Render(w io.Writer) {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+// Handler returns an http.Handler serving the registry at any path.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.Render(w)
+	})
+}
+
+// writeHeader writes the HELP and TYPE lines for a metric family.
+func writeHeader(w io.Writer, name, help, typ string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// formatFloat renders a sample value the way Prometheus expects.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Counter is a monotonically increasing count.
+type Counter struct {
+	name, help string
+	v          atomic.Uint64
+}
+
+// NewCounter registers and returns a counter.
+func (r *Registry) NewCounter(name, help string) *Counter {
+	c := &Counter{name: name, help: help}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() {
+	c.v.Add(1)
+}
+
+// Value returns the current count.
+func (c *Counter) Value() uint64 {
+	return c.v.Load()
+}
+
+func (c *Counter) write(w io.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	_, _ = fmt.Fprintf(w, "%s %d\n", c.name, c.v.Load())
+}
+
+// CounterVec is a family of counters distinguished by one label.
+type CounterVec struct {
+	name, help, label string
+
+	mu     sync.Mutex
+	values map[string]uint64
+}
+
+// NewCounterVec registers and returns a counter family keyed by label.
+func (r *Registry) NewCounterVec(name, help, label string) *CounterVec {
+	c := &CounterVec{name: name, help: help, label: label, values: make(map[string]uint64)}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the counter for value.
+func (c *CounterVec) Inc(value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[value]++
+}
+
+// Value returns the current count for value.
+func (c *CounterVec) Value(value string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[value]
+}
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	counts := make([]uint64, len(keys))
+	for i, k := range keys {
+		counts[i] = c.values[k]
+	}
+	c.mu.Unlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+	for i, k := range keys {
+		_, _ = fmt.Fprintf(w, "%s{%s=%s} %d\n", c.name, c.label, quoteLabel(k), counts[i])
+	}
+}
+
+// quoteLabel quotes a label value with Prometheus escaping.
+func quoteLabel(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\n", `\n`)
+	v = strings.ReplaceAll(v, `"`, `\"`)
+	return `"` + v + `"`
+}
+
+// gaugeFunc is a gauge whose value is read from fn on each scrape.
+type gaugeFunc struct {
+	name, help string
+	fn         func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is fn's result at scrape time.
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(&gaugeFunc{name: name, help: help, fn: fn})
+}
+
+func (g *gaugeFunc) write(w io.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	_, _ = fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.fn()))
+}
+
+// DefaultLatencyBuckets are upper bounds in seconds suited to local CDP and
+// IPC round trips.
+var DefaultLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	name, help string
+	buckets    []float64
+
+	mu     sync.Mutex
+	counts []uint64 // Per bucket, non-cumulative; the last is +Inf
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram registers and returns a histogram with the given ascending
+// bucket upper bounds.
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := &Histogram{name: name, help: help, buckets: buckets, counts: make([]uint64, len(buckets)+1)}
+	r.register(h)
+	return h
+}
+
+// Observe records one value.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.buckets, v)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[i]++
+	h.sum += v
+	h.count++
+}
+
+// ObserveDuration records d in seconds.
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(d.Seconds())
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	counts := append([]uint64(nil), h.counts...)
+	sum, count := h.sum, h.count
+	h.mu.Unlock()
+
+	writeHeader(w, h.name, h.help, "histogram")
+	var cumulative uint64
+	for i, le := range h.buckets {
+		cumulative += counts[i]
+		_, _ = fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", h.name, formatFloat(le), cumulative)
+	}
+	_, _ = fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", h.name, count)
+	_, _ = fmt.Fprintf(w, "%s_sum %s\n", h.name, formatFloat(sum))
+	_, _ = fmt.Fprintf(w, "%s_count %d\n", h.name, count)
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Render(t *testing.T) {
+	r := New()
+	c := r.NewCounter("test_events_total", "Events seen.")
+	v := r.NewCounterVec("test_requests_total", "Requests by command.", "cmd")
+	r.NewGaugeFunc("test_depth", "Queue depth.", func() float64 { return 2.5 })
+	h := r.NewHistogram("test_latency_seconds", "Latency.", []float64{0.1, 1})
+
+	c.Inc()
+	c.Inc()
+	v.Inc("status")
+	v.Inc(`say "hi"`)
+	v.Inc("status")
+	h.Observe(0.05)
+	h.Observe(0.1)
+	h.ObserveDuration(3 * time.Second)
+
+	var sb strings.Builder
+	r.Render(&sb)
+
+	want := `# HELP test_events_total Events seen.
+# TYPE test_events_total counter
+test_events_total 2
+# HELP test_requests_total Requests by command.
+# TYPE test_requests_total counter
+test_requests_total{cmd="say \"hi\""} 1
+test_requests_total{cmd="status"} 2
+# HELP test_depth Queue depth.
+# TYPE test_depth gauge
+test_depth 2.5
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 2
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 3.15
+test_latency_seconds_count 3
+`
+	if got := sb.String(); got != want {
+		t.Errorf("Render mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := New()
+	r.NewCounter("test_total", "Test.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "test_total 1\n") {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,192 @@
+// Package schema generates JSON Schema (draft 2020-12) from Go types, following
+// encoding/json's rules for field names, omitempty, and embedded structs.
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Dialect is the JSON Schema dialect of generated documents.
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema object.
+type Schema = map[string]any
+
+// Field is one property of an object schema built with Object. Value is a
+// value of the property's Go type, a Schema, or a []Field for a nested object;
+// nil allows any JSON value.
+type Field struct {
+	Name     string
+	Value    any
+	Optional bool
+}
+
+// property is a resolved object property.
+type property struct {
+	name     string
+	schema   Schema
+	optional bool
+}
+
+var (
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+	timeType       = reflect.TypeFor[time.Time]()
+)
+
+// Generator builds schemas, collecting named struct types as shared
+// definitions referenced with $ref.
+type Generator struct {
+	defs map[string]Schema
+}
+
+// NewGenerator returns a Generator with no definitions.
+func NewGenerator() *Generator {
+	return &Generator{defs: map[string]Schema{}}
+}
+
+// Of returns the schema for v's type. A Schema is returned as is and a
+// []Field becomes an object.
+func (g *Generator) Of(v any) Schema {
+	switch v := v.(type) {
+	case nil:
+		return Schema{}
+	case Schema:
+		return v
+	case []Field:
+		return g.Object(v...)
+	}
+	return g.Type(reflect.TypeOf(v))
+}
+
+// Type returns the schema for t. Named struct types are added to the
+// definitions and referenced.
+func (g *Generator) Type(t reflect.Type) Schema {
+	switch {
+	case t == rawMessageType:
+		return Schema{}
+	case t == timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Pointer:
+		return Schema{"anyOf": []Schema{g.Type(t.Elem()), {"type": "null"}}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes byte slices as base64 strings.
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": "array", "items": g.Type(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.Type(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structObject(t)
+		}
+		// Definitions take the type's name, capitalised so unexported types
+		// read like the rest.
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // reserve the name so recursive types terminate
+			g.defs[name] = g.structObject(t)
+		}
+		return Schema{"$ref": "#/$defs/" + name}
+	}
+	// Interfaces and anything else encoding/json can produce.
+	return Schema{}
+}
+
+// Object returns a closed object schema with the given properties.
+func (g *Generator) Object(fields ...Field) Schema {
+	props := make([]property, len(fields))
+	for i, f := range fields {
+		props[i] = property{f.Name, g.Of(f.Value), f.Optional}
+	}
+	return object(props)
+}
+
+// structObject builds the schema for a struct's JSON fields.
+func (g *Generator) structObject(t reflect.Type) Schema {
+	var props []property
+	g.collect(t, &props)
+	return object(props)
+}
+
+// collect appends t's JSON fields, promoting the fields of embedded structs as
+// encoding/json does.
+func (g *Generator) collect(t reflect.Type, props *[]property) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.collect(ft, props)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		opts = "," + opts + ","
+		*props = append(*props, property{
+			name:     name,
+			schema:   g.Type(sf.Type),
+			optional: strings.Contains(opts, ",omitempty,") || strings.Contains(opts, ",omitzero,"),
+		})
+	}
+}
+
+// object returns a closed object schema. Properties without omitempty are
+// required, since encoding/json always writes them.
+func object(props []property) Schema {
+	properties := Schema{}
+	required := []string{}
+	for _, p := range props {
+		properties[p.name] = p.schema
+		if !p.optional {
+			required = append(required, p.name)
+		}
+	}
+	return Schema{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// Document returns s as a root schema carrying the dialect, a title, and every
+// definition generated so far.
+func (g *Generator) Document(title string, s Schema) Schema {
+	doc := Schema{"$schema": Dialect, "title": title}
+	for k, v := range s {
+		doc[k] = v
+	}
+	if len(g.defs) > 0 {
+		doc["$defs"] = g.defs
+	}
+	return doc
+}
@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type sample struct {
+	inner
+	Count   int               `json:"count"`
+	Ratio   float64           `json:"ratio,omitempty"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Next    *inner            `json:"next,omitempty"`
+	Raw     json.RawMessage   `json:"raw,omitempty"`
+	Any     any               `json:"any"`
+	Skipped string            `json:"-"`
+	private string
+}
+
+func TestType_Struct(t *testing.T) {
+	g := NewGenerator()
+	ref := g.Of(sample{})
+	if !reflect.DeepEqual(ref, Schema{"$ref": "#/$defs/Sample"}) {
+		t.Fatalf("expected a reference to the capitalised type name, got %v", ref)
+	}
+
+	def := g.defs["Sample"]
+	props := def["properties"].(Schema)
+	for _, name := range []string{"name", "count", "ratio", "tags", "labels", "next", "raw", "any"} {
+		if _, ok := props[name]; !ok {
+			t.Errorf("missing property %q", name)
+		}
+	}
+	if len(props) != 8 {
+		t.Errorf("unexpected properties: %v", props)
+	}
+	wantRequired := []string{"name", "count", "tags", "any"}
+	if !reflect.DeepEqual(def["required"], wantRequired) {
+		t.Errorf("required: got %v, want %v", def["required"], wantRequired)
+	}
+	if !reflect.DeepEqual(props["tags"], Schema{"type": "array", "items": Schema{"type": "string"}}) {
+		t.Errorf("tags: %v", props["tags"])
+	}
+	if !reflect.DeepEqual(props["raw"], Schema{}) || !reflect.DeepEqual(props["any"], Schema{}) {
+		t.Error("raw JSON and interfaces should accept any value")
+	}
+	next := props["next"].(Schema)["anyOf"].([]Schema)
+	if next[0]["$ref"] != "#/$defs/Inner" || next[1]["type"] != "null" {
+		t.Errorf("pointer should be nullable reference, got %v", next)
+	}
+}
+
+func TestObject_Document(t *testing.T) {
+	g := NewGenerator()
+	s := g.Object(
+		Field{Name: "ok", Value: true},
+		Field{Name: "items", Value: []inner{}},
+		Field{Name: "data", Value: []Field{{Name: "message", Value: ""}}, Optional: true},
+	)
+	doc := g.Document("example", s)
+
+	if doc["$schema"] != Dialect || doc["title"] != "example" || doc["additionalProperties"] != false {
+		t.Errorf("unexpected document header: %v", doc)
+	}
+	if !reflect.DeepEqual(doc["required"], []string{"ok", "items"}) {
+		t.Errorf("required: %v", doc["required"])
+	}
+	data := doc["properties"].(Schema)["data"].(Schema)
+	if !reflect.DeepEqual(data["required"], []string{"message"}) {
+		t.Errorf("nested object: %v", data)
+	}
+	if _, ok := doc["$defs"].(map[string]Schema)["Inner"]; !ok {
+		t.Error("expected Inner in $defs")
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -22,6 +22,7 @@ const (
 type Config struct {
 	Mode        Mode     // Server mode: static or proxy
 	Directory   string   // Directory to serve (static mode)
+	SPA         bool     // Serve index.html for unknown routes (static mode)
 	ProxyURL    string   // Backend URL to proxy (proxy mode)
 	Port        int      // Server port (0 = auto-detect)
 	Host        string   // Bind host ("localhost" or "0.0.0.0")
@@ -123,7 +124,7 @@ func (s *Server) Start(ctx context.Context) error {
 	var handler http.Handler
 	switch s.config.Mode {
 	case ModeStatic:
-		handler = newStaticHandler(s.config.Directory, s.debugLog)
+		handler = newStaticHandler(s.config.Directory, s.config.SPA, s.debugLog)
 	case ModeProxy:
 		handler, err = newProxyHandler(s.config.ProxyURL, s.debugLog)
 		if err != nil {
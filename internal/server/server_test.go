@@ -240,6 +240,72 @@ func TestIndexFileFallback(t *testing.T) {
 	}
 }
 
+func TestSPAFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"index.html": "<html><body>app</body></html>",
+		"app.js":     "console.log('app')",
+	}
+	for filename, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, filename), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		spa          bool
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"route with spa", true, "/users/42", http.StatusOK, files["index.html"]},
+		{"existing file with spa", true, "/app.js", http.StatusOK, files["app.js"]},
+		{"missing asset with spa", true, "/missing.css", http.StatusNotFound, ""},
+		{"route without spa", false, "/users/42", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, err := New(Config{
+				Mode:      ModeStatic,
+				Directory: tmpDir,
+				SPA:       tt.spa,
+				Port:      0,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			ctx := context.Background()
+			if err := srv.Start(ctx); err != nil {
+				t.Fatalf("Failed to start server: %v", err)
+			}
+			defer func() { _ = srv.Stop(ctx) }()
+
+			resp, err := http.Get(srv.URL() + tt.path)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, resp.StatusCode)
+			}
+			if tt.expectedBody == "" {
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if string(body) != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, string(body))
+			}
+		})
+	}
+}
+
 func TestPortAutoDetection(t *testing.T) {
 	tests := []struct {
 		name string
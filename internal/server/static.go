@@ -9,8 +9,10 @@ import (
 	"time"
 )
 
-// newStaticHandler creates an HTTP handler for serving static files.
-func newStaticHandler(directory string, debugLog func(format string, args ...any)) http.Handler {
+// newStaticHandler creates an HTTP handler for serving static files. With spa
+// set, unknown extensionless paths serve the root index file, so client-side
+// routes survive a reload.
+func newStaticHandler(directory string, spa bool, debugLog func(format string, args ...any)) http.Handler {
 	// Resolve absolute path
 	absDir, err := filepath.Abs(directory)
 	if err != nil {
@@ -25,6 +27,7 @@ func newStaticHandler(directory string, debugLog func(format string, args ...any
 
 	handler := &staticHandler{
 		root:     absDir,
+		spa:      spa,
 		debugLog: debugLog,
 	}
 
@@ -34,9 +37,24 @@ func newStaticHandler(directory string, debugLog func(format string, args ...any
 // staticHandler serves static files from a directory.
 type staticHandler struct {
 	root     string
+	spa      bool // serve the root index for unknown routes
 	debugLog func(format string, args ...any)
 }
 
+// indexFiles are the files tried, in order, for a directory request.
+var indexFiles = []string{"index.html", "index.htm", "default.html", "home.html"}
+
+// findIndex returns the first index file present in dir.
+func findIndex(dir string) (string, bool) {
+	for _, indexFile := range indexFiles {
+		indexPath := filepath.Join(dir, indexFile)
+		if _, err := os.Stat(indexPath); err == nil {
+			return indexPath, true
+		}
+	}
+	return "", false
+}
+
 // ServeHTTP implements http.Handler for static file serving.
 func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
@@ -68,6 +86,15 @@ func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Check if file exists
 	fileInfo, err := os.Stat(fullPath)
+	if err != nil && os.IsNotExist(err) && h.spa && filepath.Ext(path) == "" {
+		// SPA fallback: a route like /users/42 is the app's, not a file.
+		// Paths with an extension still 404 so missing assets show up.
+		if indexPath, ok := findIndex(h.root); ok {
+			h.debugLog("SPA fallback: %s -> %s", r.URL.Path, filepath.Base(indexPath))
+			fullPath = indexPath
+			fileInfo, err = os.Stat(fullPath)
+		}
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			h.debugLog("404 Not Found: %s", r.URL.Path)
@@ -81,26 +108,15 @@ func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// If path is a directory, try common index files
 	if fileInfo.IsDir() {
-		// Try common index files in order
-		indexFiles := []string{"index.html", "index.htm", "default.html", "home.html"}
-		found := false
-
-		for _, indexFile := range indexFiles {
-			indexPath := filepath.Join(fullPath, indexFile)
-			if _, err := os.Stat(indexPath); err == nil {
-				fullPath = indexPath
-				found = true
-				h.debugLog("Serving directory index: %s", indexFile)
-				break
-			}
-		}
-
+		indexPath, found := findIndex(fullPath)
 		if !found {
 			// Directory listing disabled - return 404
 			h.debugLog("404 Not Found: directory without index file (tried: %v): %s", indexFiles, r.URL.Path)
 			http.Error(w, "Not Found", http.StatusNotFound)
 			return
 		}
+		fullPath = indexPath
+		h.debugLog("Serving directory index: %s", filepath.Base(indexPath))
 	}
 
 	// Disable caching for development